@@ -36,6 +36,10 @@ type AgentTool struct {
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Namespace of a custom tool. Defaults to the agent namespace. Tools of other namespaces must be
+	// granted to agents of the agent namespace by a ReferenceGrant
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Optional
 	Functions []ToolFunction `json:"functions,omitempty"`
 	// +kubebuilder:validation:Optional
 	// ToolPartial allows overriding the tool's name and description as exposed to the agent,
@@ -64,6 +68,60 @@ type ExecutionEngineRef struct {
 	// Namespace of the ExecutionEngine resource. Defaults to the agent's namespace if not specified
 	Namespace string `json:"namespace,omitempty"`
 }
+
+// Tool choices of model calls. Any other tool choice is the name of the tool the model must call
+const (
+	ToolChoiceAuto     = "auto"
+	ToolChoiceNone     = "none"
+	ToolChoiceRequired = "required"
+)
+
+// DefaultMaxToolIterations is used when an agent does not set maxToolIterations
+const DefaultMaxToolIterations = 25
+
+// ModelParameters are the sampling parameters of model calls. Unset parameters keep the defaults
+// of the model. Fractional values are strings, like the temperature of Bedrock models
+type ModelParameters struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^\d+(\.\d+)?$
+	// Sampling temperature, between 0 and 2. Bedrock models accept at most 1
+	Temperature *string `json:"temperature,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.\d+)?|1(\.0+)?)$
+	// Nucleus sampling probability mass, between 0 and 1
+	TopP *string `json:"topP,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of tokens generated by a model call
+	MaxTokens *int64 `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^-?\d+(\.\d+)?$
+	// Penalty of tokens by their frequency so far, between -2 and 2. Not supported by Bedrock models
+	FrequencyPenalty *string `json:"frequencyPenalty,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^-?\d+(\.\d+)?$
+	// Penalty of tokens that appeared so far, between -2 and 2. Not supported by Bedrock models
+	PresencePenalty *string `json:"presencePenalty,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MaxItems=4
+	// Sequences at which the model stops generating
+	Stop []string `json:"stop,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Seed for best effort deterministic sampling. Not supported by Bedrock models
+	Seed *int64 `json:"seed,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=low;medium;high
+	// Reasoning effort of reasoning models such as the OpenAI o-series. Not supported by Bedrock
+	// models
+	ReasoningEffort string `json:"reasoningEffort,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[a-zA-Z0-9_-]{1,64}$
+	// Whether the model calls tools: auto, none, required, or the name of a tool to call. Required
+	// and a tool name only apply to the first model call of an agent execution, so the agent can
+	// answer once it has the tool results
+	ToolChoice string `json:"toolChoice,omitempty"`
+}
+
 type AgentSpec struct {
 	Prompt      string `json:"prompt,omitempty"`
 	Description string `json:"description,omitempty"`
@@ -79,11 +137,81 @@ type AgentSpec struct {
 	// +kubebuilder:validation:Optional
 	// JSON schema for structured output format
 	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Sampling parameters of the model calls of this agent. Queries can override them
+	ModelParameters *ModelParameters `json:"modelParameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of rounds of tool calls in one execution of the agent. The execution fails
+	// when the model still calls tools after them. Defaults to 25
+	MaxToolIterations *int32 `json:"maxToolIterations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Data policy applied to content this agent handles, in addition to the query data policy
+	DataPolicy DataPolicy `json:"dataPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Guardrails that check this agent's input and output
+	Guardrails []GuardrailRef `json:"guardrails,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Knowledge bases the agent searches with a retrieval tool registered for each of them
+	KnowledgeBases []KnowledgeBaseRef `json:"knowledgeBases,omitempty"`
+	// +kubebuilder:validation:Optional
+	// What the agent reads from and writes to the conversation when it runs in a team. Defaults to shared
+	MemoryPolicy MemoryPolicy `json:"memoryPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Post-processors applied in order to this agent's final response
+	PostProcessors []PostProcessor `json:"postProcessors,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Protocol version and capabilities the A2A server of this agent must declare. The agent is not
+	// available while its server lacks them
+	A2ARequirements *A2ARequirements `json:"a2aRequirements,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of previous AgentRevisions kept besides the current one. Older revisions are deleted.
+	// Defaults to 10
+	RevisionHistoryLimit *int32 `json:"revisionHistoryLimit,omitempty"`
+}
+
+// A2ACapability is an optional A2A protocol feature declared in an agent card
+// +kubebuilder:validation:Enum=streaming;pushNotifications
+type A2ACapability string
+
+const (
+	A2ACapabilityStreaming         A2ACapability = "streaming"
+	A2ACapabilityPushNotifications A2ACapability = "pushNotifications"
+)
+
+// A2ARequirements are checked against the agent card discovered by the A2AServer before an A2A
+// agent is executed
+type A2ARequirements struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+){0,2}$
+	// Minimum A2A protocol version of the agent card, e.g. 0.3.0
+	MinProtocolVersion string `json:"minProtocolVersion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Capabilities the agent card must declare
+	Capabilities []A2ACapability `json:"capabilities,omitempty"`
 }
 
+// MemoryPolicy controls the conversation an agent sees and adds to in team execution
+// +kubebuilder:validation:Enum=shared;isolated;readOnly
+type MemoryPolicy string
+
+const (
+	// MemoryPolicyShared reads and writes the team conversation
+	MemoryPolicyShared MemoryPolicy = "shared"
+	// MemoryPolicyIsolated reads and writes the agent's own message stream, keyed by agent name, and
+	// only adds its final response to the team conversation
+	MemoryPolicyIsolated MemoryPolicy = "isolated"
+	// MemoryPolicyReadOnly reads the team conversation without adding to it
+	MemoryPolicyReadOnly MemoryPolicy = "readOnly"
+)
+
 type AgentStatus struct {
 	// Conditions represent the latest available observations of an agent's state
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
+	// Revision number of the AgentRevision matching the current spec
+	// +kubebuilder:validation:Optional
+	CurrentRevision int64 `json:"currentRevision,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -91,6 +219,7 @@ type AgentStatus struct {
 // +kubebuilder:storageversion
 // +kubebuilder:printcolumn:name="Model",type="string",JSONPath=".spec.modelRef.name"
 // +kubebuilder:printcolumn:name="Available",type="string",JSONPath=`.status.conditions[?(@.type=="Available")].status`
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".status.currentRevision"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
 type Agent struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -0,0 +1,49 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// AgentRevisionSpec is an immutable snapshot of an Agent spec.
+type AgentRevisionSpec struct {
+	// Name of the Agent this revision belongs to
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	AgentName string `json:"agentName"`
+	// Revision number, incremented on every agent spec change
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	Revision int64 `json:"revision"`
+	// Agent spec at this revision
+	// +kubebuilder:validation:Required
+	Template AgentSpec `json:"template"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Agent",type="string",JSONPath=".spec.agentName"
+// +kubebuilder:printcolumn:name="Revision",type="integer",JSONPath=".spec.revision"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp"
+
+// AgentRevision is the Schema for the agentrevisions API.
+type AgentRevision struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="AgentRevision spec is immutable"
+	Spec AgentRevisionSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// AgentRevisionList contains a list of AgentRevision.
+type AgentRevisionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []AgentRevision `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&AgentRevision{}, &AgentRevisionList{})
+}
@@ -24,6 +24,21 @@ type ValueFromSource struct {
 	ServiceRef *ServiceReference `json:"serviceRef,omitempty"`
 	// +kubebuilder:validation:Optional
 	QueryParameterRef *QueryParameterReference `json:"queryParameterRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Field of the query being executed, one of metadata.name, metadata.namespace, metadata.uid,
+	// metadata.labels['<key>'], metadata.annotations['<key>'] or spec.sessionId
+	QueryFieldRef *FieldSelector `json:"queryFieldRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Field of the resource declaring the parameter, one of metadata.name, metadata.namespace,
+	// metadata.labels['<key>'] or metadata.annotations['<key>']
+	ResourceFieldRef *FieldSelector `json:"resourceFieldRef,omitempty"`
+}
+
+// FieldSelector selects a field of a resource, like the fieldRef of the Kubernetes downward API
+type FieldSelector struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	FieldPath string `json:"fieldPath"`
 }
 
 type QueryParameterReference struct {
@@ -125,3 +140,14 @@ type ResourceSelector struct {
 	// +kubebuilder:validation:Optional
 	NamespaceSelector *metav1.LabelSelector `json:"namespaceSelector,omitempty"`
 }
+
+// DataPolicy controls how content handled by a query or agent is stored and traced
+// +kubebuilder:validation:Enum=none;redactPII
+type DataPolicy string
+
+const (
+	// DataPolicyNone stores and traces content unchanged
+	DataPolicyNone DataPolicy = "none"
+	// DataPolicyRedactPII redacts personal data before content is written to memory or telemetry
+	DataPolicyRedactPII DataPolicy = "redactPII"
+)
@@ -14,6 +14,11 @@ const (
 	EvaluationCompleted EvaluationConditionType = "Completed"
 )
 
+// LastEvaluationPassed is the condition the evaluation controller sets on evaluated queries and the
+// agents that answered them. It is True when the last completed evaluation passed, its message has
+// the score, and its last transition time is when that evaluation completed.
+const LastEvaluationPassed = "LastEvaluationPassed"
+
 // EvaluationEvaluatorRef references an evaluator resource for evaluation with parameters
 type EvaluationEvaluatorRef struct {
 	// +kubebuilder:validation:Required
@@ -144,9 +149,13 @@ type BatchEvaluationConfig struct {
 	Evaluations []EvaluationRef `json:"evaluations,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=10
-	// Maximum number of concurrent child evaluations
+	// Deprecated: not enforced, use maxConcurrency
 	Concurrency int32 `json:"concurrency,omitempty"`
 	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Maximum number of child evaluations running at a time, unlimited if not set
+	MaxConcurrency int32 `json:"maxConcurrency,omitempty"`
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:default=false
 	// Whether to continue on child evaluation failures
 	ContinueOnFailure bool `json:"continueOnFailure,omitempty"`
@@ -161,6 +170,51 @@ type EventEvaluationConfig struct {
 	Rules []ExpressionRule `json:"rules,omitempty"`
 }
 
+// Aggregations of the metric results of an evaluation
+const (
+	EvaluationAggregationAll      = "all"
+	EvaluationAggregationAny      = "any"
+	EvaluationAggregationWeighted = "weighted"
+)
+
+// EvaluationMetricThreshold is the pass threshold of a metric reported by the evaluator
+type EvaluationMetricThreshold struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Name of the metric in the evaluator response (e.g., "relevance", "toxicity")
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.[0-9]+)?|1(\.0+)?)$
+	// Minimum score of the metric, or maximum score when lowerIsBetter is set
+	Threshold string `json:"threshold,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Whether lower scores are better, e.g. for toxicity
+	LowerIsBetter bool `json:"lowerIsBetter,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// +kubebuilder:default="1"
+	// Weight of the metric in the weighted aggregation
+	Weight string `json:"weight,omitempty"`
+}
+
+// EvaluationPassCriteria decides whether an evaluation passed from the metrics reported by the evaluator
+// +kubebuilder:validation:XValidation:rule="self.aggregation != 'weighted' || has(self.threshold)",message="threshold is required for the weighted aggregation"
+// +kubebuilder:validation:XValidation:rule="self.aggregation == 'weighted' || self.metrics.all(m, has(m.threshold))",message="metrics require a threshold unless the aggregation is weighted"
+type EvaluationPassCriteria struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Metrics []EvaluationMetricThreshold `json:"metrics"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=all;any;weighted
+	// +kubebuilder:default=all
+	// Whether all metrics, any metric, or the weighted average of the metric scores must meet the threshold
+	Aggregation string `json:"aggregation,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.[0-9]+)?|1(\.0+)?)$
+	// Minimum weighted average score for the weighted aggregation
+	Threshold string `json:"threshold,omitempty"`
+}
+
 // EvaluationSpec defines the desired state of Evaluation
 type EvaluationSpec struct {
 	// +kubebuilder:validation:Optional
@@ -177,15 +231,38 @@ type EvaluationSpec struct {
 	// +kubebuilder:default="5m"
 	// Timeout for query execution (e.g., "30s", "5m", "1h")
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +kubebuilder:default=10
+	// Number of completed runs kept in status.history, 0 disables the history
+	HistoryLimit *int32 `json:"historyLimit,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Pass criteria on the metrics reported by the evaluator, which replace the pass decision of the evaluator
+	PassCriteria *EvaluationPassCriteria `json:"passCriteria,omitempty"`
+}
+
+// EvaluationRunRecord is the result of a completed run of an evaluation
+type EvaluationRunRecord struct {
+	// +kubebuilder:validation:Optional
+	Score string `json:"score,omitempty"`
+	// +kubebuilder:validation:Optional
+	Passed bool `json:"passed"`
+	// +kubebuilder:validation:Optional
+	Metrics map[string]string `json:"metrics,omitempty"`
+	// +kubebuilder:validation:Optional
+	TokenUsage *TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Required
+	CompletedAt metav1.Time `json:"completedAt"`
 }
 
 // BatchEvaluationProgress tracks progress of batch evaluations
 type BatchEvaluationProgress struct {
 	// +kubebuilder:validation:Optional
-	// Total number of child evaluations created
+	// Total number of child evaluations in the batch
 	Total int32 `json:"total,omitempty"`
 	// +kubebuilder:validation:Optional
-	// Number of child evaluations completed
+	// Number of child evaluations completed, including failed ones
 	Completed int32 `json:"completed,omitempty"`
 	// +kubebuilder:validation:Optional
 	// Number of child evaluations that failed
@@ -194,8 +271,14 @@ type BatchEvaluationProgress struct {
 	// Number of child evaluations currently running
 	Running int32 `json:"running,omitempty"`
 	// +kubebuilder:validation:Optional
-	// List of child evaluation names and their status
+	// Number of child evaluations not created yet because of maxConcurrency
+	Pending int32 `json:"pending,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Child evaluations currently running and their status
 	ChildEvaluations []ChildEvaluationStatus `json:"childEvaluations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Estimated completion time, extrapolated from the children completed so far
+	EstimatedCompletionTime *metav1.Time `json:"estimatedCompletionTime,omitempty"`
 }
 
 // ChildEvaluationStatus represents the status of a child evaluation
@@ -226,6 +309,9 @@ type EvaluationStatus struct {
 	// +kubebuilder:validation:Optional
 	Passed bool `json:"passed"`
 	// +kubebuilder:validation:Optional
+	// Scores of the metrics reported by the evaluator, averaged over the children for batch evaluations
+	Metrics map[string]string `json:"metrics,omitempty"`
+	// +kubebuilder:validation:Optional
 	TokenUsage *TokenUsage `json:"tokenUsage,omitempty"`
 	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
@@ -233,8 +319,17 @@ type EvaluationStatus struct {
 	// Batch evaluation progress (only set for batch type evaluations)
 	BatchProgress *BatchEvaluationProgress `json:"batchProgress,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Results of the most recent completed runs, oldest first, kept across re-runs up to spec.historyLimit
+	History []EvaluationRunRecord `json:"history,omitempty"`
+	// +kubebuilder:validation:Optional
 	// Conditions represent the latest available observations of an evaluation's state
 	Conditions []metav1.Condition `json:"conditions,omitempty" patchStrategy:"merge" patchMergeKey:"type"`
+	// +kubebuilder:validation:Optional
+	// ID of the trace of the last evaluator call, set when the trace is exported
+	TraceID string `json:"traceID,omitempty"`
+	// +kubebuilder:validation:Optional
+	// URL of the trace in the trace UI, set when a trace URL template is configured
+	TraceURL string `json:"traceURL,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -29,6 +29,77 @@ type EvaluatorSpec struct {
 	// Parameters to pass to evaluation requests
 	// +kubebuilder:validation:Optional
 	Parameters []Parameter `json:"parameters,omitempty"`
+
+	// Protocol the evaluator is called with. HTTP evaluators receive the request as JSON posted to
+	// the address, gRPC evaluators serve ark.evaluator.v1.EvaluatorService at the host:port address
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http;grpc
+	// +kubebuilder:default=http
+	Protocol string `json:"protocol,omitempty"`
+
+	// Headers sent with every request, as metadata of gRPC calls
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+
+	// TLS of the connection to the evaluator. Without it, gRPC evaluators are called in plaintext
+	// and HTTPS evaluators are verified with the system roots
+	// +kubebuilder:validation:Optional
+	TLS *EvaluatorTLS `json:"tls,omitempty"`
+
+	// Auth sends credentials with every request, as the Authorization header or gRPC metadata
+	// +kubebuilder:validation:Optional
+	Auth *EvaluatorAuth `json:"auth,omitempty"`
+}
+
+// Protocols of evaluators
+const (
+	EvaluatorProtocolHTTP = "http"
+	EvaluatorProtocolGRPC = "grpc"
+)
+
+// EvaluatorAuth configures the credentials evaluators are called with
+// +kubebuilder:validation:XValidation:rule="has(self.bearerToken) != has(self.basic)",message="exactly one of bearerToken or basic must be set"
+type EvaluatorAuth struct {
+	// Token sent as a bearer token, usually from a Secret
+	// +kubebuilder:validation:Optional
+	BearerToken *ValueSource `json:"bearerToken,omitempty"`
+
+	// Username and password sent with basic authentication
+	// +kubebuilder:validation:Optional
+	Basic *EvaluatorBasicAuth `json:"basic,omitempty"`
+}
+
+// EvaluatorBasicAuth is the username and password of basic authentication
+type EvaluatorBasicAuth struct {
+	// +kubebuilder:validation:Required
+	Username ValueSource `json:"username"`
+
+	// +kubebuilder:validation:Required
+	Password ValueSource `json:"password"`
+}
+
+// EvaluatorTLS configures TLS of the connection to an evaluator, including client certificates
+// for evaluators that require mutual TLS
+type EvaluatorTLS struct {
+	// PEM encoded CA certificates that verify the evaluator. Defaults to the system roots
+	// +kubebuilder:validation:Optional
+	CACert *ValueSource `json:"caCert,omitempty"`
+
+	// PEM encoded client certificate, for evaluators that require mutual TLS
+	// +kubebuilder:validation:Optional
+	ClientCert *ValueSource `json:"clientCert,omitempty"`
+
+	// PEM encoded private key of the client certificate
+	// +kubebuilder:validation:Optional
+	ClientKey *ValueSource `json:"clientKey,omitempty"`
+
+	// Name the certificate of the evaluator is verified against, instead of the host of the address
+	// +kubebuilder:validation:Optional
+	ServerName string `json:"serverName,omitempty"`
+
+	// Skip verifying the certificate of the evaluator. Only for development
+	// +kubebuilder:validation:Optional
+	InsecureSkipVerify bool `json:"insecureSkipVerify,omitempty"`
 }
 
 type EvaluatorStatus struct {
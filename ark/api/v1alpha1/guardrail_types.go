@@ -0,0 +1,104 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GuardrailStage is the point of execution where a guardrail checks content
+// +kubebuilder:validation:Enum=input;output
+type GuardrailStage string
+
+const (
+	// GuardrailStageInput checks user input before it is sent to a target
+	GuardrailStageInput GuardrailStage = "input"
+	// GuardrailStageOutput checks assistant output before it is returned
+	GuardrailStageOutput GuardrailStage = "output"
+)
+
+// GuardrailAction is taken when content violates a guardrail
+// +kubebuilder:validation:Enum=block;annotate
+type GuardrailAction string
+
+const (
+	// GuardrailActionBlock fails the execution
+	GuardrailActionBlock GuardrailAction = "block"
+	// GuardrailActionAnnotate records the violation and continues
+	GuardrailActionAnnotate GuardrailAction = "annotate"
+)
+
+// GuardrailRef references a Guardrail in the same namespace
+type GuardrailRef struct {
+	// +kubebuilder:validation:Required
+	Name string `json:"name"`
+}
+
+// GuardrailSpec defines the desired state of Guardrail.
+type GuardrailSpec struct {
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default={input,output}
+	// Stages at which content is checked
+	Stages []GuardrailStage `json:"stages,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=block
+	Action GuardrailAction `json:"action,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Regular expressions that content must not match
+	Patterns []string `json:"patterns,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Keywords that content must not contain, matched case-insensitively
+	Keywords []string `json:"keywords,omitempty"`
+	// +kubebuilder:validation:Optional
+	// CEL expressions over the string variables content and stage. An expression evaluating to true is a violation
+	Expressions []string `json:"expressions,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Model asked whether content violates the policy
+	ModerationModelRef *AgentModelRef `json:"moderationModelRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Content policy given to the moderation model
+	Policy string `json:"policy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Message returned when content is blocked
+	Message string `json:"message,omitempty"`
+}
+
+// AppliesTo returns true if the guardrail checks content at stage
+func (s *GuardrailSpec) AppliesTo(stage GuardrailStage) bool {
+	if len(s.Stages) == 0 {
+		return true
+	}
+	for _, st := range s.Stages {
+		if st == stage {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Action",type="string",JSONPath=".spec.action",description="Action taken on violations"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the guardrail"
+
+// Guardrail is the Schema for the guardrails API.
+type Guardrail struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec GuardrailSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// GuardrailList contains a list of Guardrail.
+type GuardrailList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Guardrail `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Guardrail{}, &GuardrailList{})
+}
@@ -0,0 +1,186 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// KnowledgeBaseSpec defines the documents of a knowledge base and how they are indexed. The
+// documents are split into chunks, embedded with an embedding model and stored in the vector index
+// of a memory service.
+type KnowledgeBaseSpec struct {
+	// Description of the knowledge base, used as the description of its retrieval tool
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+
+	// Sources of the documents
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	Sources []KnowledgeBaseSource `json:"sources"`
+
+	// EmbeddingModelRef is the Model of type embedding that embeds the chunks and the searches
+	// +kubebuilder:validation:Required
+	EmbeddingModelRef AgentModelRef `json:"embeddingModelRef"`
+
+	// Memory whose memory service stores the chunks. The service must support vector search
+	// +kubebuilder:validation:Required
+	Memory MemoryRef `json:"memory"`
+
+	// Chunking configures how documents are split into chunks
+	// +kubebuilder:validation:Optional
+	Chunking *KnowledgeBaseChunking `json:"chunking,omitempty"`
+
+	// TopK is the number of chunks a search returns
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +kubebuilder:default=4
+	TopK int `json:"topK,omitempty"`
+
+	// RefreshInterval indexes the sources again periodically. Without it the sources are indexed
+	// when the spec changes
+	// +kubebuilder:validation:Optional
+	RefreshInterval *metav1.Duration `json:"refreshInterval,omitempty"`
+}
+
+// KnowledgeBaseSource is a source of documents. Exactly one of configMap, url and s3 is set.
+// +kubebuilder:validation:XValidation:rule="(has(self.configMap) ? 1 : 0) + (has(self.url) ? 1 : 0) + (has(self.s3) ? 1 : 0) == 1",message="exactly one of configMap, url or s3 must be set"
+type KnowledgeBaseSource struct {
+	// ConfigMap whose keys are documents
+	// +kubebuilder:validation:Optional
+	ConfigMap *KnowledgeBaseConfigMapSource `json:"configMap,omitempty"`
+
+	// URL of a document, fetched with GET
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^https?://
+	URL string `json:"url,omitempty"`
+
+	// S3 objects that are documents
+	// +kubebuilder:validation:Optional
+	S3 *KnowledgeBaseS3Source `json:"s3,omitempty"`
+}
+
+// KnowledgeBaseConfigMapSource selects the documents of a ConfigMap in the namespace of the
+// knowledge base
+type KnowledgeBaseConfigMapSource struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Keys that are documents. Defaults to all keys
+	// +kubebuilder:validation:Optional
+	Keys []string `json:"keys,omitempty"`
+}
+
+// KnowledgeBaseS3Source selects the objects of an S3 bucket. Without an access key the
+// credentials of the controller pod are used, e.g. from IRSA or EKS Pod Identity.
+// +kubebuilder:validation:XValidation:rule="has(self.accessKeyId) == has(self.secretAccessKey)",message="accessKeyId and secretAccessKey must be set together"
+type KnowledgeBaseS3Source struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Bucket string `json:"bucket"`
+
+	// Prefix of the keys of the objects
+	// +kubebuilder:validation:Optional
+	Prefix string `json:"prefix,omitempty"`
+
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Region string `json:"region"`
+
+	// Endpoint of an S3 compatible store, which is addressed with path style requests
+	// +kubebuilder:validation:Optional
+	Endpoint string `json:"endpoint,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	AccessKeyID *ValueSource `json:"accessKeyId,omitempty"`
+
+	// +kubebuilder:validation:Optional
+	SecretAccessKey *ValueSource `json:"secretAccessKey,omitempty"`
+}
+
+// KnowledgeBaseChunking configures the size of the chunks documents are split into, in characters
+// +kubebuilder:validation:XValidation:rule="self.overlap < self.size",message="overlap must be smaller than size"
+type KnowledgeBaseChunking struct {
+	// Size is the maximum number of characters of a chunk
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=100
+	// +kubebuilder:validation:Maximum=8000
+	// +kubebuilder:default=1000
+	Size int `json:"size,omitempty"`
+
+	// Overlap is the number of characters consecutive chunks share
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=200
+	Overlap int `json:"overlap,omitempty"`
+}
+
+// KnowledgeBaseRef references a knowledge base in the namespace of the agent
+type KnowledgeBaseRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// KnowledgeBaseStatus defines the observed state of KnowledgeBase
+type KnowledgeBaseStatus struct {
+	// Phase of the indexing of the knowledge base
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=pending;running;ready;error
+	Phase string `json:"phase,omitempty"`
+
+	// Message provides additional information about the phase
+	// +kubebuilder:validation:Optional
+	Message string `json:"message,omitempty"`
+
+	// Collection of the memory service that holds the chunks of the last completed indexing
+	// +kubebuilder:validation:Optional
+	Collection string `json:"collection,omitempty"`
+
+	// Documents indexed by the last completed indexing
+	// +kubebuilder:validation:Optional
+	Documents int `json:"documents,omitempty"`
+
+	// Chunks indexed by the last completed indexing
+	// +kubebuilder:validation:Optional
+	Chunks int `json:"chunks,omitempty"`
+
+	// LastIndexedTime is when the last indexing completed
+	// +kubebuilder:validation:Optional
+	LastIndexedTime *metav1.Time `json:"lastIndexedTime,omitempty"`
+
+	// ObservedGeneration is the generation of the spec the last indexing used
+	// +kubebuilder:validation:Optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type="string",JSONPath=".status.phase",description="Phase of the indexing"
+// +kubebuilder:printcolumn:name="Documents",type="integer",JSONPath=".status.documents",description="Indexed documents"
+// +kubebuilder:printcolumn:name="Chunks",type="integer",JSONPath=".status.chunks",description="Indexed chunks"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the knowledge base"
+
+// KnowledgeBase is the Schema for the knowledgebases API
+type KnowledgeBase struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   KnowledgeBaseSpec   `json:"spec,omitempty"`
+	Status KnowledgeBaseStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// KnowledgeBaseList contains a list of KnowledgeBase
+type KnowledgeBaseList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []KnowledgeBase `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&KnowledgeBase{}, &KnowledgeBaseList{})
+}
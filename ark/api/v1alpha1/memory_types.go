@@ -3,13 +3,118 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // MemorySpec defines the desired state of Memory.
+// +kubebuilder:validation:XValidation:rule="(has(self.provision) && self.provision) || (has(self.address) && (has(self.address.value) || has(self.address.valueFrom)))",message="address is required unless provision is true"
 type MemorySpec struct {
+	// Address of the memory service. Ignored when the service is provisioned.
+	// +kubebuilder:validation:Optional
+	Address ValueSource `json:"address,omitempty"`
+
+	// Provision deploys the postgres-memory service for this memory and wires it to its database
+	// +kubebuilder:validation:Optional
+	Provision bool `json:"provision,omitempty"`
+
+	// Postgres configures the provisioned postgres-memory service
+	// +kubebuilder:validation:Optional
+	Postgres *MemoryPostgres `json:"postgres,omitempty"`
+
+	// Compaction summarizes the older messages of long sessions
+	// +kubebuilder:validation:Optional
+	Compaction *MemoryCompaction `json:"compaction,omitempty"`
+}
+
+// MemoryCompaction replaces the older messages of a session with a summary written by a model
+// when the session grows past a limit, so long conversations fit in the model context.
+// +kubebuilder:validation:XValidation:rule="has(self.maxMessages) || has(self.maxTokens)",message="at least one of maxMessages or maxTokens must be set"
+type MemoryCompaction struct {
+	// MaxMessages compacts a session with more messages than this
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxMessages int `json:"maxMessages,omitempty"`
+
+	// MaxTokens compacts a session whose messages are estimated to exceed this many tokens
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens int `json:"maxTokens,omitempty"`
+
+	// KeepRecent is the number of most recent messages kept as they are
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=10
+	KeepRecent int `json:"keepRecent,omitempty"`
+
+	// ModelRef is the model that writes the summary. Defaults to the namespace default model.
+	// +kubebuilder:validation:Optional
+	ModelRef *AgentModelRef `json:"modelRef,omitempty"`
+}
+
+// MemoryPostgres configures the provisioned postgres-memory service and its database.
+// +kubebuilder:validation:XValidation:rule="has(self.credentialsSecret) != has(self.cluster)",message="exactly one of credentialsSecret or cluster must be set"
+type MemoryPostgres struct {
+	// Image of the postgres-memory service
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="ghcr.io/mckinsey/agents-at-scale-ark/postgres-memory:latest"
+	Image string `json:"image,omitempty"`
+
+	// Replicas of the postgres-memory service
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// Resources of the postgres-memory container
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// CredentialsSecret names a Secret with the connection settings of an existing database, in
+	// the host, port, dbname, user and password keys
+	// +kubebuilder:validation:Optional
+	CredentialsSecret string `json:"credentialsSecret,omitempty"`
+
+	// Cluster provisions a CloudNativePG cluster as the database. The CloudNativePG operator must
+	// be installed.
+	// +kubebuilder:validation:Optional
+	Cluster *MemoryPostgresCluster `json:"cluster,omitempty"`
+
+	// Encryption encrypts the content of stored messages, beyond the encryption of the disks
+	// +kubebuilder:validation:Optional
+	Encryption *MemoryEncryption `json:"encryption,omitempty"`
+}
+
+// MemoryEncryption configures envelope encryption of message content: each message is encrypted
+// with AES-256-GCM under a data key, and data keys are wrapped with a key from a Secret.
+type MemoryEncryption struct {
+	// KeysSecret names a Secret whose keys are key IDs and whose values are base64 encoded 256-bit
+	// keys. Keys that are no longer active decrypt the data keys wrapped with them, so they stay in
+	// the Secret until the service has rewrapped those data keys.
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	KeysSecret string `json:"keysSecret"`
+
+	// ActiveKey is the ID of the key in the KeysSecret that wraps new data keys. Changing it rotates
+	// the key.
 	// +kubebuilder:validation:Required
-	Address ValueSource `json:"address"`
+	// +kubebuilder:validation:MinLength=1
+	ActiveKey string `json:"activeKey"`
+}
+
+// MemoryPostgresCluster configures the CloudNativePG cluster provisioned for a memory.
+type MemoryPostgresCluster struct {
+	// Instances of the database cluster
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=1
+	Instances int32 `json:"instances,omitempty"`
+
+	// StorageSize of each database instance
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1Gi"
+	StorageSize resource.Quantity `json:"storageSize,omitempty"`
 }
 
 // MemoryStatus defines the observed state of Memory.
@@ -26,6 +131,10 @@ type MemoryStatus struct {
 	// Message provides additional information about the current status
 	// +kubebuilder:validation:Optional
 	Message string `json:"message,omitempty"`
+
+	// Conditions represent the health of the provisioned service and its database
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -52,6 +161,24 @@ type MemoryList struct {
 	Items           []Memory `json:"items"`
 }
 
+// ProvisionedName is the name of the resources provisioned for the memory
+func (m *Memory) ProvisionedName() string {
+	return m.Name + "-memory"
+}
+
+// AddressSource returns the address of the memory service, which is the provisioned Service when
+// the memory is provisioned
+func (m *Memory) AddressSource() ValueSource {
+	if !m.Spec.Provision {
+		return m.Spec.Address
+	}
+	return ValueSource{
+		ValueFrom: &ValueFromSource{
+			ServiceRef: &ServiceReference{Name: m.ProvisionedName(), Port: "http"},
+		},
+	}
+}
+
 func init() {
 	SchemeBuilder.Register(&Memory{}, &MemoryList{})
 }
@@ -14,14 +14,29 @@ type ModelConfig struct {
 	Azure *AzureModelConfig `json:"azure,omitempty"`
 	// +kubebuilder:validation:Optional
 	Bedrock *BedrockModelConfig `json:"bedrock,omitempty"`
+	// +kubebuilder:validation:Optional
+	Mock *MockModelConfig `json:"mock,omitempty"`
+}
+
+// MockModelConfig contains the script of a mock model, which answers requests with scripted
+// responses instead of calling a provider
+type MockModelConfig struct {
+	// +kubebuilder:validation:Required
+	// YAML script of the responses, usually read from a ConfigMap
+	Script ValueSource `json:"script"`
 }
 
 // AzureModelConfig contains Azure OpenAI specific parameters
 type AzureModelConfig struct {
 	// +kubebuilder:validation:Required
 	BaseURL ValueSource `json:"baseUrl"`
-	// +kubebuilder:validation:Required
-	APIKey ValueSource `json:"apiKey"`
+	// +kubebuilder:validation:Optional
+	// API key of the Azure OpenAI resource. Exactly one of apiKey and managedIdentity is set
+	APIKey *ValueSource `json:"apiKey,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Authenticates with Microsoft Entra ID tokens of the workload identity or managed identity of
+	// the controller instead of an API key
+	ManagedIdentity *AzureManagedIdentity `json:"managedIdentity,omitempty"`
 	// +kubebuilder:validation:Optional
 	APIVersion *ValueSource `json:"apiVersion,omitempty"`
 	// +kubebuilder:validation:Optional
@@ -30,6 +45,23 @@ type AzureModelConfig struct {
 	Properties map[string]ValueSource `json:"properties,omitempty"`
 }
 
+// AzureManagedIdentity selects the Microsoft Entra ID identity tokens are acquired for. With
+// workload identity the federated token of the pod is exchanged for the token, otherwise the
+// managed identity of the node is used.
+type AzureManagedIdentity struct {
+	// +kubebuilder:validation:Optional
+	// Client ID of the workload identity application or user-assigned managed identity. Defaults
+	// to AZURE_CLIENT_ID of the controller, or to the system-assigned managed identity
+	ClientID string `json:"clientId,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tenant of the workload identity application. Defaults to AZURE_TENANT_ID of the controller
+	TenantID string `json:"tenantId,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="https://cognitiveservices.azure.com/.default"
+	// Scope of the tokens
+	Scope string `json:"scope,omitempty"`
+}
+
 // OpenAIModelConfig contains OpenAI specific parameters
 type OpenAIModelConfig struct {
 	// +kubebuilder:validation:Required
@@ -57,6 +89,17 @@ type BedrockModelConfig struct {
 	// +kubebuilder:validation:Optional
 	ModelArn *ValueSource `json:"modelArn,omitempty"`
 	// +kubebuilder:validation:Optional
+	// ARN or ID of an inference profile the model is invoked through, e.g. a cross-region system
+	// profile like us.anthropic.claude-3-5-sonnet-20240620-v1:0. Takes precedence over modelArn
+	InferenceProfileArn *ValueSource `json:"inferenceProfileArn,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Regions tried in order when the region is throttled or unavailable
+	FailoverRegions []string `json:"failoverRegions,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ARN of a role assumed with the credentials of the pod, e.g. from IRSA or EKS Pod Identity.
+	// Without accessKeyId and secretAccessKey the pod credentials are used directly
+	RoleArn *ValueSource `json:"roleArn,omitempty"`
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Minimum=1
 	// +kubebuilder:validation:Maximum=100000
 	MaxTokens *int `json:"maxTokens,omitempty"`
@@ -71,13 +114,82 @@ type ModelSpec struct {
 	// +kubebuilder:validation:Required
 	Model ValueSource `json:"model"`
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=openai;azure;bedrock
+	// +kubebuilder:validation:Enum=openai;azure;bedrock;mock;embedding
+	// Provider of a chat completion model, or embedding for a model that produces embeddings
+	// with the openai or azure configuration
 	Type string `json:"type,omitempty"`
 	// +kubebuilder:validation:Required
 	Config ModelConfig `json:"config"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:default="1m"
 	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Capabilities supported by the model. When empty, all capabilities are assumed.
+	Capabilities []ModelCapability `json:"capabilities,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Context window of the model. Requests that do not fit are shortened by removing the oldest messages.
+	ContextWindow *ModelContextWindow `json:"contextWindow,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Price of the tokens of the model, used to report the cost of queries and sessions
+	Pricing *ModelPricing `json:"pricing,omitempty"`
+}
+
+// ModelPricing is the price of one million tokens, in a currency of your choice
+type ModelPricing struct {
+	// Price of one million prompt tokens
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	PromptTokens string `json:"promptTokens"`
+	// Price of one million completion tokens
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	CompletionTokens string `json:"completionTokens"`
+}
+
+// ModelContextWindow describes how many tokens a model accepts, so conversations are shortened
+// before they are sent instead of being rejected by the provider.
+// +kubebuilder:validation:XValidation:rule="self.maxTokens > self.reserveOutputTokens",message="maxTokens must be greater than reserveOutputTokens"
+type ModelContextWindow struct {
+	// Maximum number of tokens of a request and its response
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxTokens int `json:"maxTokens"`
+	// Tokens kept free for the response
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:default=4096
+	ReserveOutputTokens int `json:"reserveOutputTokens,omitempty"`
+	// How the oldest messages are removed: truncate drops them, summarize replaces them with a
+	// summary written by the model and falls back to truncate if summarization fails
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=truncate;summarize
+	// +kubebuilder:default="truncate"
+	Strategy string `json:"strategy,omitempty"`
+}
+
+// ModelCapability is an input a model can accept besides text
+// +kubebuilder:validation:Enum=vision;files
+type ModelCapability string
+
+const (
+	// ModelCapabilityVision indicates the model accepts images in user messages
+	ModelCapabilityVision ModelCapability = "vision"
+	// ModelCapabilityFiles indicates the model accepts files in user messages
+	ModelCapabilityFiles ModelCapability = "files"
+)
+
+// HasCapability reports whether the model supports capability.
+// Models that do not declare capabilities are assumed to support all of them.
+func (s *ModelSpec) HasCapability(capability ModelCapability) bool {
+	if len(s.Capabilities) == 0 {
+		return true
+	}
+	for _, c := range s.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
 }
 
 type ModelStatus struct {
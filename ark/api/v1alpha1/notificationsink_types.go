@@ -0,0 +1,101 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Notification sink types
+const (
+	NotificationSinkWebhook     = "webhook"
+	NotificationSinkSlack       = "slack"
+	NotificationSinkTeams       = "teams"
+	NotificationSinkCloudEvents = "cloudevents"
+)
+
+// NotificationEvent is a query lifecycle event sent to notification sinks
+// +kubebuilder:validation:Enum=QueryCompleted;QueryFailed;EvaluationBelowThreshold;BudgetExceeded
+type NotificationEvent string
+
+const (
+	// NotificationQueryCompleted is sent when a query completes successfully
+	NotificationQueryCompleted NotificationEvent = "QueryCompleted"
+	// NotificationQueryFailed is sent when a query ends in error
+	NotificationQueryFailed NotificationEvent = "QueryFailed"
+	// NotificationEvaluationBelowThreshold is sent when an evaluation completes without passing
+	NotificationEvaluationBelowThreshold NotificationEvent = "EvaluationBelowThreshold"
+	// NotificationBudgetExceeded is sent when a session first exceeds the session budget of the sink
+	NotificationBudgetExceeded NotificationEvent = "BudgetExceeded"
+)
+
+// SessionBudget is the token usage and cost a session may reach before BudgetExceeded is sent
+// +kubebuilder:validation:XValidation:rule="has(self.maxTokens) || has(self.maxCost)",message="maxTokens or maxCost is required"
+type SessionBudget struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Total tokens of the queries of a session
+	MaxTokens int64 `json:"maxTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// Cost of the queries of a session, in the currency of the model pricing
+	MaxCost string `json:"maxCost,omitempty"`
+}
+
+type NotificationSinkSpec struct {
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=webhook;slack;teams;cloudevents
+	// +kubebuilder:default=webhook
+	// Format of the notifications: webhook posts notifications as JSON, slack and teams post messages to
+	// incoming webhooks, cloudevents posts structured CloudEvents
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Required
+	// URL notifications are posted to. Chat webhook URLs contain a token, so prefer valueFrom.secretKeyRef
+	URL ValueSource `json:"url"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Events sent to the sink, all events when empty
+	Events []NotificationEvent `json:"events,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Budget of each session in the namespace, required for BudgetExceeded
+	SessionBudget *SessionBudget `json:"sessionBudget,omitempty"`
+}
+
+// Subscribes reports whether the sink receives event
+func (s *NotificationSinkSpec) Subscribes(event NotificationEvent) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// NotificationSink receives the query lifecycle notifications of its namespace.
+type NotificationSink struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec NotificationSinkSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// NotificationSinkList contains a list of NotificationSink.
+type NotificationSinkList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []NotificationSink `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&NotificationSink{}, &NotificationSinkList{})
+}
@@ -0,0 +1,126 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Pipeline step phases, in addition to the query phases pending, running, done and error
+const (
+	PipelineStepWaiting = "waiting"
+	PipelineStepSkipped = "skipped"
+)
+
+// PipelineApproval pauses a pipeline until a user approves or rejects the step by annotating the
+// pipeline with ark.mckinsey.com/approve or ark.mckinsey.com/reject set to the step name
+type PipelineApproval struct {
+	// +kubebuilder:validation:Optional
+	// Message shown to approvers, resolved with the step outputs like query inputs
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time to wait for a decision before the step fails. Waits indefinitely when unset
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// PipelineStep is a query or an approval gate. Steps run in order, each after the previous step
+// completed or was skipped.
+// +kubebuilder:validation:XValidation:rule="has(self.query) != has(self.approval)",message="exactly one of query or approval is required"
+type PipelineStep struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MaxLength=63
+	// +kubebuilder:validation:Pattern=^[a-z][a-z0-9_]*$
+	// Name of the step. The output of the step is passed to later steps as a parameter of this name
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// CEL expression over steps.<name>.phase and steps.<name>.output of the previous steps. The step
+	// is skipped when it evaluates to false
+	When string `json:"when,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Query created for the step
+	Query *QuerySpec `json:"query,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Approval the step waits for
+	Approval *PipelineApproval `json:"approval,omitempty"`
+}
+
+type PipelineSpec struct {
+	// +kubebuilder:validation:Optional
+	// Parameters added to the query of every step
+	Parameters []Parameter `json:"parameters,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// +listType=map
+	// +listMapKey=name
+	Steps []PipelineStep `json:"steps"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	// Session ID of the step queries that do not set one
+	SessionId string `json:"sessionId,omitempty"`
+}
+
+type PipelineStepStatus struct {
+	Name string `json:"name"`
+	// +kubebuilder:validation:Enum=pending;running;waiting;done;error;skipped
+	Phase string `json:"phase"`
+	// +kubebuilder:validation:Optional
+	// Query created for the step
+	Query string `json:"query,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Response content of the step query, or the approval decision
+	Output string `json:"output,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Approval message or failure reason
+	Message string `json:"message,omitempty"`
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+type PipelineStatus struct {
+	// +kubebuilder:default="pending"
+	// +kubebuilder:validation:Enum=pending;running;waiting;done;error
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Step that runs or waits for approval
+	CurrentStep string `json:"currentStep,omitempty"`
+	// +kubebuilder:validation:Optional
+	Steps []PipelineStepStatus `json:"steps,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Token usage of the step queries
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Step",type=string,JSONPath=`.status.currentStep`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Pipeline runs a sequence of queries and approval gates, passing the output of each step to the
+// steps after it.
+type Pipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   PipelineSpec   `json:"spec,omitempty"`
+	Status PipelineStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// PipelineList contains a list of Pipeline.
+type PipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pipeline `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Pipeline{}, &PipelineList{})
+}
@@ -0,0 +1,66 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PostProcessorFailurePolicy is applied when a post-processor fails
+// +kubebuilder:validation:Enum=fail;ignore
+type PostProcessorFailurePolicy string
+
+const (
+	// PostProcessorFailurePolicyFail fails the execution
+	PostProcessorFailurePolicyFail PostProcessorFailurePolicy = "fail"
+	// PostProcessorFailurePolicyIgnore keeps the response unchanged and continues
+	PostProcessorFailurePolicyIgnore PostProcessorFailurePolicy = "ignore"
+)
+
+// PostProcessorToolRef references a Tool in the same namespace
+type PostProcessorToolRef struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// PostProcessorWebhook is an HTTP endpoint the response is posted to
+type PostProcessorWebhook struct {
+	// +kubebuilder:validation:Required
+	URL ValueSource `json:"url"`
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+}
+
+// PostProcessor transforms the final response, e.g. to convert markdown to HTML, translate it or map
+// it to a schema
+// +kubebuilder:validation:XValidation:rule="has(self.tool) != has(self.webhook)",message="exactly one of tool or webhook is required"
+type PostProcessor struct {
+	// +kubebuilder:validation:Optional
+	// Name of the post-processor in traces and events. Defaults to the tool name or webhook
+	Name string `json:"name,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tool called with the response, whose result replaces the response
+	Tool *PostProcessorToolRef `json:"tool,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Webhook the response is posted to as JSON, answering with the transformed response
+	Webhook *PostProcessorWebhook `json:"webhook,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default=fail
+	FailurePolicy PostProcessorFailurePolicy `json:"failurePolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Timeout of the post-processor. Defaults to 30s
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+}
+
+// DisplayName returns the name of the post-processor in traces and events
+func (p *PostProcessor) DisplayName() string {
+	switch {
+	case p.Name != "":
+		return p.Name
+	case p.Tool != nil:
+		return p.Tool.Name
+	default:
+		return "webhook"
+	}
+}
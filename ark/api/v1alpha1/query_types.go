@@ -3,10 +3,15 @@
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"time"
 
 	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -17,6 +22,10 @@ type QueryConditionType string
 const (
 	// QueryCompleted indicates that the query has finished (regardless of outcome)
 	QueryCompleted QueryConditionType = "Completed"
+	// QuerySLOViolated indicates whether the completed query exceeded an objective of its SLO
+	QuerySLOViolated QueryConditionType = "SLOViolated"
+	// QueryCheckpointed is false when the last checkpoint of the execution could not be saved
+	QueryCheckpointed QueryConditionType = "Checkpointed"
 )
 
 const (
@@ -26,6 +35,18 @@ const (
 	QueryTypeMessages = "messages"
 )
 
+const (
+	// DefaultQueryTTL is used when neither the query nor the namespace defaults set a TTL
+	DefaultQueryTTL = 720 * time.Hour
+	// DefaultQueryTimeout is used when neither the query nor the namespace defaults set a timeout
+	DefaultQueryTimeout = 5 * time.Minute
+	// DefaultQueryInputMaxBytes is the size limit of an input read from inputFrom when maxBytes is unset
+	DefaultQueryInputMaxBytes = 1 << 20
+	// DefaultQueryIdempotencyWindow is how long an idempotency key is reserved by the query that used
+	// it, when the namespace defaults do not set a window
+	DefaultQueryIdempotencyWindow = 24 * time.Hour
+)
+
 type QueryTarget struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:Enum=agent;team;model;tool
@@ -33,6 +54,70 @@ type QueryTarget struct {
 	// +kubebuilder:validation:Required
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// Namespace of the target. Defaults to the query namespace
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Optional
+	// RemoteCluster in the query namespace the target runs in. The target namespace is then a
+	// namespace of the remote cluster
+	Cluster string `json:"cluster,omitempty"`
+}
+
+// TargetKind is a resource kind a query selector can match
+// +kubebuilder:validation:Enum=Agent;Team;Model;Tool
+type TargetKind string
+
+const (
+	TargetKindAgent TargetKind = "Agent"
+	TargetKindTeam  TargetKind = "Team"
+	TargetKindModel TargetKind = "Model"
+	TargetKindTool  TargetKind = "Tool"
+)
+
+// Kind returns the resource kind of the target type
+func (t QueryTarget) Kind() TargetKind {
+	switch t.Type {
+	case "agent":
+		return TargetKindAgent
+	case "team":
+		return TargetKindTeam
+	case "model":
+		return TargetKindModel
+	case "tool":
+		return TargetKindTool
+	}
+	return TargetKind(t.Type)
+}
+
+// TargetSelector selects query targets by label
+type TargetSelector struct {
+	// Embed the standard Kubernetes label selector
+	metav1.LabelSelector `json:",inline"`
+	// +kubebuilder:validation:Optional
+	// Kinds of resources to select. Empty selects agents, teams, models and tools
+	Kinds []TargetKind `json:"kinds,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Namespaces to select from. Empty selects from the query namespace
+	Namespaces []string `json:"namespaces,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Targets to skip even when they match. A target without a namespace is excluded in every selected namespace
+	Exclude []QueryTarget `json:"exclude,omitempty"`
+}
+
+// SelectsKind returns true when the selector matches resources of the given kind
+func (s *TargetSelector) SelectsKind(kind TargetKind) bool {
+	return len(s.Kinds) == 0 || slices.Contains(s.Kinds, kind)
+}
+
+// Excludes returns true when target, with its namespace set, is in the exclude list
+func (s *TargetSelector) Excludes(target QueryTarget) bool {
+	for _, excluded := range s.Exclude {
+		if excluded.Type == target.Type && excluded.Name == target.Name &&
+			(excluded.Namespace == "" || excluded.Namespace == target.Namespace) {
+			return true
+		}
+	}
+	return false
 }
 
 type MemoryRef struct {
@@ -43,40 +128,203 @@ type MemoryRef struct {
 	Namespace string `json:"namespace,omitempty"`
 }
 
+// Input part types
+const (
+	QueryInputPartText  = "text"
+	QueryInputPartImage = "image"
+	QueryInputPartFile  = "file"
+)
+
+// QueryInputPart is a part of the user message of a query, such as an image or a file
+// +kubebuilder:validation:XValidation:rule="self.type != 'text' || has(self.text)",message="text parts require text"
+// +kubebuilder:validation:XValidation:rule="self.type != 'image' || has(self.imageURL) != has(self.valueFrom)",message="image parts require exactly one of imageURL or valueFrom"
+// +kubebuilder:validation:XValidation:rule="self.type != 'file' || has(self.valueFrom)",message="file parts require valueFrom"
+type QueryInputPart struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=text;image;file
+	Type string `json:"type"`
+	// +kubebuilder:validation:Optional
+	// Text of a text part, resolved with the query parameters like the input
+	Text string `json:"text,omitempty"`
+	// +kubebuilder:validation:Optional
+	// URL of an image part, either an http(s) URL or a base64 data URL
+	ImageURL string `json:"imageURL,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Source of the content of an image or file part
+	ValueFrom *QueryInputPartSource `json:"valueFrom,omitempty"`
+	// +kubebuilder:validation:Optional
+	// MIME type of content read from valueFrom, e.g. image/png. Detected from the content when unset
+	MimeType string `json:"mimeType,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Name of the file sent with a file part. Defaults to the ConfigMap key
+	Filename string `json:"filename,omitempty"`
+}
+
+// QueryInputPartSource reads the content of an input part from a ConfigMap key. Binary content
+// is read from binaryData and text content from data.
+type QueryInputPartSource struct {
+	// +kubebuilder:validation:Required
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef"`
+}
+
+// QueryInputSource reads the input of a query from a ConfigMap key, a Secret key or an HTTP(S) URL
+// +kubebuilder:validation:XValidation:rule="(has(self.configMapKeyRef) ? 1 : 0) + (has(self.secretKeyRef) ? 1 : 0) + (has(self.url) ? 1 : 0) == 1",message="exactly one of configMapKeyRef, secretKeyRef or url is required"
+type QueryInputSource struct {
+	// +kubebuilder:validation:Optional
+	ConfigMapKeyRef *corev1.ConfigMapKeySelector `json:"configMapKeyRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	SecretKeyRef *corev1.SecretKeySelector `json:"secretKeyRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=`^https?://`
+	// URL the input is fetched from with a GET request when the query runs
+	URL string `json:"url,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=8388608
+	// Size limit of the input in bytes. Defaults to 1MiB
+	MaxBytes int64 `json:"maxBytes,omitempty"`
+}
+
+// QueryImpersonation is the identity a query runs as, so the RBAC of the original caller applies
+// when the query is submitted on their behalf
+type QueryImpersonation struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	User string `json:"user"`
+	// +kubebuilder:validation:Optional
+	Groups []string `json:"groups,omitempty"`
+	// +kubebuilder:validation:Optional
+	UID string `json:"uid,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="!has(self.parts) || !has(self.type) || self.type == 'user'",message="parts can only be used with type user"
+// +kubebuilder:validation:XValidation:rule="!has(self.serviceAccount) || !has(self.impersonate)",message="serviceAccount and impersonate cannot both be set"
+// +kubebuilder:validation:XValidation:rule="has(self.input) != has(self.inputFrom)",message="exactly one of input or inputFrom is required"
+// +kubebuilder:validation:XValidation:rule="!has(self.record) || !self.record || !has(self.replay)",message="record and replay cannot both be set"
 type QuerySpec struct {
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:Enum=user;messages
 	// +kubebuilder:default=user
 	Type string `json:"type,omitempty"`
-	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +kubebuilder:validation:Schemaless
 	// Input can be a string (type=user) or []openai.ChatCompletionMessageParamUnion (type=messages)
-	Input runtime.RawExtension `json:"input"`
+	Input runtime.RawExtension `json:"input,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Source the input is read from when the query runs instead of input. The content is a string
+	// (type=user) or a JSON array of messages (type=messages)
+	InputFrom *QueryInputSource `json:"inputFrom,omitempty"`
 	// +kubebuilder:validation:Optional
 	// Parameters for template processing in the input field
 	Parameters []Parameter `json:"parameters,omitempty"`
 	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	// Types, defaults and enums of the parameters. When set, parameters must be declared here.
+	// Queries created from a template carry the parameters of the template.
+	ParameterSchema []QueryTemplateParameter `json:"parameterSchema,omitempty"`
+	// +kubebuilder:validation:Optional
 	Targets []QueryTarget `json:"targets,omitempty"`
 	// +kubebuilder:validation:Optional
-	Selector *metav1.LabelSelector `json:"selector,omitempty"`
+	Selector *TargetSelector `json:"selector,omitempty"`
 	// +kubebuilder:validation:Optional
 	Memory *MemoryRef `json:"memory,omitempty"`
 	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
 	ServiceAccount string `json:"serviceAccount,omitempty"`
 	// +kubebuilder:validation:Optional
+	// User the query runs as instead of a service account. Setting it requires permission to
+	// impersonate the user, groups and uid, unless they are the caller's own
+	Impersonate *QueryImpersonation `json:"impersonate,omitempty"`
+	// +kubebuilder:validation:Optional
 	// +kubebuilder:validation:MinLength=1
 	SessionId string `json:"sessionId,omitempty"`
 	// +kubebuilder:validation:Optional
-	// +kubebuilder:default="720h"
+	// +kubebuilder:validation:MaxLength=256
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="idempotencyKey is immutable"
+	// Key of the submission, for submitters that may send it more than once. Creating a query with
+	// the key of a query created within the idempotency window of the namespace is rejected with
+	// the name of that query
+	IdempotencyKey string `json:"idempotencyKey,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time after creation after which the query is deleted once it completed, unless ttlAfterCompletion
+	// is set. Defaults to the namespace default or 720h, 0 keeps the query
 	TTL *metav1.Duration `json:"ttl,omitempty"`
-	// +kubebuilder:default="5m"
-	// Timeout for query execution (e.g., "30s", "5m", "1h")
+	// +kubebuilder:validation:Optional
+	// Time to keep the query after it completed, replacing ttl. Defaults to the namespace default,
+	// 0 keeps the query
+	TTLAfterCompletion *metav1.Duration `json:"ttlAfterCompletion,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Keep the query when it ends in error, regardless of ttl and ttlAfterCompletion
+	RetainOnError bool `json:"retainOnError,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Deadline for query execution (e.g., "30s", "5m", "1h"). Defaults to the namespace default or 5m
 	Timeout *metav1.Duration `json:"timeout,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Objectives the query is checked against once it completed. Defaults to the namespace default
+	SLO *QuerySLO `json:"slo,omitempty"`
+	// +kubebuilder:validation:Optional
 	// When true, indicates intent to cancel the query
 	Cancel bool `json:"cancel,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Data policy for messages stored in memory and content attached to traces. Defaults to the namespace default
+	DataPolicy DataPolicy `json:"dataPolicy,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Guardrails that check the input and output of every target
+	Guardrails []GuardrailRef `json:"guardrails,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Parts such as images and files appended to the user message built from the input (type=user)
+	Parts []QueryInputPart `json:"parts,omitempty"`
+	// +kubebuilder:validation:Optional
+	// System prompt added for every target. Agents get it after their own prompt, models as the
+	// first message. Template parameters are resolved like in the input
+	SystemPrompt string `json:"systemPrompt,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Messages added for every target after the conversation history, before the input
+	ContextMessages []QueryContextMessage `json:"contextMessages,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Sampling parameters of every model call of the query. Set parameters override the ones of
+	// agents
+	ModelParameters *ModelParameters `json:"modelParameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Resolve the targets, input, memory and tools of the query and report the execution plan in
+	// the status without calling any model or tool
+	DryRun bool `json:"dryRun,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Record every model call of the query, so it can be replayed without calling the models
+	Record bool `json:"record,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Answer the model calls of the query from a recording instead of calling the models
+	Replay *QueryReplay `json:"replay,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Post-processors applied in order to the final response of every target before it is written
+	// to the status and memory
+	PostProcessors []PostProcessor `json:"postProcessors,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Protocol version and capabilities the A2A servers of A2A agent targets must declare. The query
+	// fails before execution when a server lacks them
+	A2ARequirements *A2ARequirements `json:"a2aRequirements,omitempty"`
+}
+
+// QueryContextMessage is a message a query adds to the conversation of its targets
+type QueryContextMessage struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=system;user;assistant
+	Role string `json:"role"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Content of the message. Template parameters are resolved like in the input
+	Content string `json:"content"`
+}
+
+// QueryReplay references the recording whose model responses replay a query
+type QueryReplay struct {
+	// +kubebuilder:validation:Enum=configmap;http
+	// Store holding the recording
+	Store string `json:"store"`
+	// Name of the recording, the ConfigMap name for the configmap store
+	Name string `json:"name"`
 }
 
 // Response defines a response from a query target.
@@ -85,6 +333,53 @@ type Response struct {
 	Content string      `json:"content,omitempty"`
 	Raw     string      `json:"raw,omitempty"`
 	Phase   string      `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Artifact holding the full content and raw messages when the response is too large for the
+	// status. Content is then truncated and raw is left empty
+	Artifact *ResponseArtifact `json:"artifact,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Content was truncated and raw messages dropped because the response was too large for the
+	// status and could not be stored as an artifact
+	Truncated bool `json:"truncated,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tool calls whose results the response was derived from
+	Provenance []ResponseProvenance `json:"provenance,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Members of a team target as resolved for this execution, including the agents selected by label
+	Members []TeamMember `json:"members,omitempty"`
+}
+
+// ResponseProvenance records a tool call whose result was used for a response
+type ResponseProvenance struct {
+	// ID of the tool call in the raw messages
+	ToolCallID string `json:"toolCallId"`
+	// Name of the tool
+	Tool string `json:"tool"`
+	// Hex encoded SHA-256 of the tool call arguments
+	ArgumentsHash string `json:"argumentsHash"`
+	// +kubebuilder:validation:Optional
+	// Beginning of the tool result
+	Snippet string `json:"snippet,omitempty"`
+}
+
+// Artifact stores
+const (
+	ArtifactStoreConfigMap = "configmap"
+	ArtifactStoreHTTP      = "http"
+)
+
+// ResponseArtifact references the full content of a response stored outside the query status
+type ResponseArtifact struct {
+	// +kubebuilder:validation:Enum=configmap;http
+	// Store holding the artifact
+	Store string `json:"store"`
+	// Name of the artifact, the ConfigMap name for the configmap store
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	// URL the artifact can be fetched from, for the http store
+	URL string `json:"url,omitempty"`
+	// Size of the content and raw messages in bytes
+	Size int64 `json:"size"`
 }
 
 // +kubebuilder:object:root=true
@@ -106,6 +401,116 @@ type TokenUsage struct {
 	PromptTokens     int64 `json:"promptTokens,omitempty"`
 	CompletionTokens int64 `json:"completionTokens,omitempty"`
 	TotalTokens      int64 `json:"totalTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Completion tokens reasoning models spent on reasoning, included in completionTokens
+	ReasoningTokens int64 `json:"reasoningTokens,omitempty"`
+}
+
+// TokenUsageDetail is the token usage of the model calls of a query made by one model for one
+// target, team and agent
+type TokenUsageDetail struct {
+	// +kubebuilder:validation:Optional
+	// Query target of the calls, e.g. team/research
+	Target string `json:"target,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Team whose member made the calls
+	Team string `json:"team,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Agent or team member that made the calls
+	Agent string `json:"agent,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Model called
+	Model string `json:"model,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of calls
+	Calls int32 `json:"calls,omitempty"`
+	// +kubebuilder:validation:Optional
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+}
+
+// ResolvedTargets counts the targets a query ran against by kind
+type ResolvedTargets struct {
+	Agents int `json:"agents,omitempty"`
+	Teams  int `json:"teams,omitempty"`
+	Models int `json:"models,omitempty"`
+	Tools  int `json:"tools,omitempty"`
+}
+
+// QueryPlan is the execution plan reported by a dry run query
+type QueryPlan struct {
+	// +kubebuilder:validation:Optional
+	// Targets the query would run, explicit targets first and then the selected ones
+	Targets []QueryPlanTarget `json:"targets,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of messages of the session loaded from memory
+	HistoryMessages int `json:"historyMessages,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Estimated prompt tokens of the first model call of every target
+	EstimatedPromptTokens int64 `json:"estimatedPromptTokens,omitempty"`
+}
+
+// QueryPlanTarget is the plan of a single target of a dry run query
+type QueryPlanTarget struct {
+	Target QueryTarget `json:"target"`
+	// +kubebuilder:validation:Optional
+	// Model the target would call
+	Model string `json:"model,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Tools offered to the model, with their schemas resolved
+	Tools []string `json:"tools,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Members of a team target
+	Members []string `json:"members,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Estimated prompt tokens of the first model call of the target, from the size of its
+	// messages and tool schemas. For a team, the sum over its agent members
+	EstimatedPromptTokens int64 `json:"estimatedPromptTokens,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Why the target could not be resolved
+	Error string `json:"error,omitempty"`
+}
+
+// QueryTargetProgress is the execution progress of a single target of a query
+type QueryTargetProgress struct {
+	Target QueryTarget `json:"target"`
+	// +kubebuilder:validation:Enum=running;done;error
+	Phase string `json:"phase"`
+	// +kubebuilder:validation:Optional
+	// Response of the target once it completed
+	Response *Response `json:"response,omitempty"`
+}
+
+// QuerySLO are the duration, cost and token usage a completed query should stay within. A query
+// that exceeds one is not failed: it gets the SLOViolated condition and a warning event
+// +kubebuilder:validation:XValidation:rule="has(self.maxDuration) || has(self.maxCost) || has(self.maxTokens)",message="at least one of maxDuration, maxCost or maxTokens must be set"
+type QuerySLO struct {
+	// +kubebuilder:validation:Optional
+	// Duration of the query execution
+	MaxDuration *metav1.Duration `json:"maxDuration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+(\.[0-9]+)?$
+	// Cost of the model calls of the query, in the currency of the model pricing
+	MaxCost string `json:"maxCost,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=1
+	// Total tokens of the model calls of the query
+	MaxTokens int64 `json:"maxTokens,omitempty"`
+}
+
+// QueryCheckpoint is the persisted progress of a running query. When an execution is interrupted,
+// for example by a controller restart, it resumes from the checkpoint and targets that completed
+// are not run again.
+type QueryCheckpoint struct {
+	// +kubebuilder:validation:Optional
+	Targets []QueryTargetProgress `json:"targets,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Token usage of the execution so far, including interrupted executions
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of times the execution resumed from the checkpoint
+	Resumes int32 `json:"resumes,omitempty"`
+	// Time the checkpoint was last written
+	UpdatedAt metav1.Time `json:"updatedAt"`
 }
 
 type QueryStatus struct {
@@ -118,7 +523,31 @@ type QueryStatus struct {
 	Responses  []Response         `json:"responses,omitempty"`
 	TokenUsage TokenUsage         `json:"tokenUsage,omitempty"`
 	// +kubebuilder:validation:Optional
+	// Token usage of the execution by target, team, agent and model
+	TokenUsageDetails []TokenUsageDetail `json:"tokenUsageDetails,omitempty"`
+	// +kubebuilder:validation:Optional
 	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ResolvedTargets counts the explicit and selected targets by kind
+	ResolvedTargets *ResolvedTargets `json:"resolvedTargets,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Checkpoint of the running execution, cleared when the query completes
+	Checkpoint *QueryCheckpoint `json:"checkpoint,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost of the model calls of the query, from the pricing of the models. Empty when no model has pricing
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Execution plan of a dry run query
+	Plan *QueryPlan `json:"plan,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Recording of the model calls of a query with record set
+	Recording *ResponseArtifact `json:"recording,omitempty"`
+	// +kubebuilder:validation:Optional
+	// ID of the trace of the last execution, set when the trace is exported
+	TraceID string `json:"traceID,omitempty"`
+	// +kubebuilder:validation:Optional
+	// URL of the trace in the trace UI, set when a trace URL template is configured
+	TraceURL string `json:"traceURL,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -183,6 +612,46 @@ func (q *QuerySpec) SetInputMessages(messages []openai.ChatCompletionMessagePara
 	return nil
 }
 
+// HasMediaParts returns true when the input parts include images or files
+func (q *QuerySpec) HasMediaParts() bool {
+	for _, part := range q.Parts {
+		if part.Type == QueryInputPartImage || part.Type == QueryInputPartFile {
+			return true
+		}
+	}
+	return false
+}
+
+// IdempotencyKeyLabelValue returns the value of the idempotency key label of queries with the key.
+// Keys are hashed, as they can be longer than label values and contain any character
+func IdempotencyKeyLabelValue(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])[:63]
+}
+
+// IdempotentQueryName returns the name submitters give queries with the idempotency key, so the
+// API server rejects a second create of the key even when the webhook has not seen the first one
+func IdempotentQueryName(namespace, key string) string {
+	sum := sha256.Sum256([]byte(namespace + "/" + key))
+	return "query-" + hex.EncodeToString(sum[:])[:32]
+}
+
+// GetTTL returns the query TTL, falling back to DefaultQueryTTL when unset
+func (q *QuerySpec) GetTTL() time.Duration {
+	if q.TTL == nil {
+		return DefaultQueryTTL
+	}
+	return q.TTL.Duration
+}
+
+// GetTimeout returns the query timeout, falling back to DefaultQueryTimeout when unset or not positive
+func (q *QuerySpec) GetTimeout() time.Duration {
+	if q.Timeout == nil || q.Timeout.Duration <= 0 {
+		return DefaultQueryTimeout
+	}
+	return q.Timeout.Duration
+}
+
 // GetInputAsGeneric returns the input as either string or []openai.ChatCompletionMessageParamUnion based on type
 func (q *QuerySpec) GetInputAsGeneric() (interface{}, error) {
 	switch q.Type {
@@ -0,0 +1,77 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// QueryAuditLabel marks queries that are never garbage collected when set to "true"
+const QueryAuditLabel = "audit"
+
+// IsAuditQuery returns true for queries retained by the audit label
+func IsAuditQuery(query *Query) bool {
+	return query.Labels[QueryAuditLabel] == "true"
+}
+
+type QueryRetentionPolicySpec struct {
+	// +kubebuilder:validation:Optional
+	// Namespaces the policy applies to. Empty applies it to every namespace
+	Namespaces []string `json:"namespaces,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	// Number of the most recently completed queries kept per namespace. Older done and canceled
+	// queries are deleted. Unset keeps every completed query
+	KeepLastCompleted *int32 `json:"keepLastCompleted,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time errored queries are kept after they failed. Unset keeps errored queries
+	ErrorRetention *metav1.Duration `json:"errorRetention,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="10m"
+	// Interval between garbage collection runs
+	Interval *metav1.Duration `json:"interval,omitempty"`
+}
+
+type QueryRetentionPolicyStatus struct {
+	// +kubebuilder:validation:Optional
+	// Time of the last garbage collection run
+	LastRunTime *metav1.Time `json:"lastRunTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of queries deleted by the last run
+	LastDeleted int32 `json:"lastDeleted,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of queries deleted by the policy since it was created
+	TotalDeleted int64 `json:"totalDeleted,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:scope=Cluster
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Keep",type=integer,JSONPath=`.spec.keepLastCompleted`
+// +kubebuilder:printcolumn:name="Error-Retention",type=string,JSONPath=`.spec.errorRetention`
+// +kubebuilder:printcolumn:name="Last-Run",type=date,JSONPath=`.status.lastRunTime`
+// +kubebuilder:printcolumn:name="Deleted",type=integer,JSONPath=`.status.totalDeleted`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// QueryRetentionPolicy deletes finished queries of the cluster by count and age. Queries labelled
+// audit=true and running queries are never deleted. The ttl of each query still applies.
+type QueryRetentionPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   QueryRetentionPolicySpec   `json:"spec,omitempty"`
+	Status QueryRetentionPolicyStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QueryRetentionPolicyList contains a list of QueryRetentionPolicy.
+type QueryRetentionPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QueryRetentionPolicy `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&QueryRetentionPolicy{}, &QueryRetentionPolicyList{})
+}
@@ -0,0 +1,212 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Types of declared parameters. Parameter values are strings, which must parse as the type.
+const (
+	ParameterTypeString  = "string"
+	ParameterTypeInteger = "integer"
+	ParameterTypeNumber  = "number"
+	ParameterTypeBoolean = "boolean"
+)
+
+// QueryTemplateParameter declares a parameter of an input template
+type QueryTemplateParameter struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=^[a-zA-Z_][a-zA-Z0-9_]*$
+	// Name of the parameter, used as template variable
+	Name string `json:"name"`
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=string;integer;number;boolean
+	// +kubebuilder:default=string
+	// Type the values of the parameter must have. Templates see the value as this type.
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Queries can only be created from the template with a value for the parameter
+	Required bool `json:"required,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Value used when the parameter is not given
+	Default string `json:"default,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Values the parameter is restricted to
+	Enum []string `json:"enum,omitempty"`
+}
+
+type QueryTemplateSpec struct {
+	// +kubebuilder:validation:Optional
+	Description string `json:"description,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=user;messages
+	// +kubebuilder:default=user
+	Type string `json:"type,omitempty"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +kubebuilder:validation:Schemaless
+	// Input template of the queries, a string (type=user) or messages (type=messages)
+	Input runtime.RawExtension `json:"input"`
+	// +kubebuilder:validation:Optional
+	// +listType=map
+	// +listMapKey=name
+	// Parameters of the input template
+	Parameters []QueryTemplateParameter `json:"parameters,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Targets of the queries unless overridden
+	Targets []QueryTarget `json:"targets,omitempty"`
+	// +kubebuilder:validation:Optional
+	Selector *TargetSelector `json:"selector,omitempty"`
+	// +kubebuilder:validation:Optional
+	Memory *MemoryRef `json:"memory,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:MinLength=1
+	ServiceAccount string `json:"serviceAccount,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Deadline of the queries. Defaults to the namespace default or 5m
+	Timeout *metav1.Duration `json:"timeout,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Objectives of the queries. Defaults to the namespace default
+	SLO *QuerySLO `json:"slo,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Evaluators that evaluate every query created from the template once it is done
+	Evaluators []EvaluationEvaluatorRef `json:"evaluators,omitempty"`
+}
+
+// ValidateValue checks a value against the type and enum of the parameter
+func (p *QueryTemplateParameter) ValidateValue(value string) error {
+	if _, err := p.TypedValue(value); err != nil {
+		return err
+	}
+	if len(p.Enum) > 0 && !slices.Contains(p.Enum, value) {
+		return fmt.Errorf("parameter %s must be one of %v", p.Name, p.Enum)
+	}
+	return nil
+}
+
+// TypedValue converts a value to the type of the parameter: int64 for integer, float64 for number
+// and bool for boolean parameters
+func (p *QueryTemplateParameter) TypedValue(value string) (any, error) {
+	var typed any
+	var err error
+	switch p.Type {
+	case ParameterTypeInteger:
+		typed, err = strconv.ParseInt(value, 10, 64)
+	case ParameterTypeNumber:
+		typed, err = strconv.ParseFloat(value, 64)
+	case ParameterTypeBoolean:
+		typed, err = strconv.ParseBool(value)
+	case "", ParameterTypeString:
+		return value, nil
+	default:
+		return nil, fmt.Errorf("parameter %s has unsupported type %s", p.Name, p.Type)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parameter %s must be of type %s, got %q", p.Name, p.Type, value)
+	}
+	return typed, nil
+}
+
+// ApplyParameterSchema returns the parameters with the defaults of the schema filled in, in the
+// order of the schema. Optional parameters without a value or default are left out. Parameters must be declared, required parameters must be given, and values
+// must match their type and enum. Values from references are only known once they are resolved,
+// so they are not validated here.
+func ApplyParameterSchema(schema []QueryTemplateParameter, values []Parameter) ([]Parameter, error) {
+	given := make(map[string]Parameter, len(values))
+	for _, value := range values {
+		if !slices.ContainsFunc(schema, func(p QueryTemplateParameter) bool { return p.Name == value.Name }) {
+			return nil, fmt.Errorf("unknown parameter %s", value.Name)
+		}
+		given[value.Name] = value
+	}
+
+	params := make([]Parameter, 0, len(schema))
+	for _, declared := range schema {
+		param, ok := given[declared.Name]
+		if !ok {
+			if declared.Required {
+				return nil, fmt.Errorf("parameter %s is required", declared.Name)
+			}
+			if declared.Default == "" {
+				continue
+			}
+			param = Parameter{Name: declared.Name, Value: declared.Default}
+		}
+		if param.ValueFrom == nil {
+			if err := declared.ValidateValue(param.Value); err != nil {
+				return nil, err
+			}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// ResolveParameters returns the parameters of a query created from the template with the given
+// values. Defaults fill in missing values, and values must be declared and match their type and enum.
+func (s *QueryTemplateSpec) ResolveParameters(values []Parameter) ([]Parameter, error) {
+	return ApplyParameterSchema(s.Parameters, values)
+}
+
+// NewQuerySpec returns the spec of a query created from the template with the given parameter values
+func (s *QueryTemplateSpec) NewQuerySpec(values []Parameter) (QuerySpec, error) {
+	params, err := s.ResolveParameters(values)
+	if err != nil {
+		return QuerySpec{}, err
+	}
+	spec := QuerySpec{
+		Type:            s.Type,
+		Input:           *s.Input.DeepCopy(),
+		Parameters:      params,
+		ParameterSchema: slices.Clone(s.Parameters),
+		Targets:         slices.Clone(s.Targets),
+		ServiceAccount:  s.ServiceAccount,
+	}
+	if s.Selector != nil {
+		spec.Selector = s.Selector.DeepCopy()
+	}
+	if s.Memory != nil {
+		spec.Memory = s.Memory.DeepCopy()
+	}
+	if s.Timeout != nil {
+		spec.Timeout = s.Timeout.DeepCopy()
+	}
+	if s.SLO != nil {
+		spec.SLO = s.SLO.DeepCopy()
+	}
+	return spec, nil
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Description",type=string,JSONPath=`.spec.description`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// QueryTemplate is a reusable query definition. Queries are created from it with values for its
+// parameters, and keep its input, targets and defaults.
+type QueryTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec QueryTemplateSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// QueryTemplateList contains a list of QueryTemplate.
+type QueryTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []QueryTemplate `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&QueryTemplate{}, &QueryTemplateList{})
+}
@@ -0,0 +1,90 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("QueryTemplateSpec", func() {
+	spec := QueryTemplateSpec{
+		Input: runtime.RawExtension{Raw: []byte(`"Summarize {{.ticket}} for {{.audience}}"`)},
+		Parameters: []QueryTemplateParameter{
+			{Name: "ticket", Required: true},
+			{Name: "audience", Default: "engineers", Enum: []string{"engineers", "executives"}},
+		},
+		Targets: []QueryTarget{{Type: "agent", Name: "summarizer"}},
+		Timeout: &metav1.Duration{Duration: time.Minute},
+		SLO:     &QuerySLO{MaxTokens: 20000},
+	}
+
+	It("should fill in defaults", func() {
+		params, err := spec.ResolveParameters([]Parameter{{Name: "ticket", Value: "ARK-1"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]Parameter{
+			{Name: "ticket", Value: "ARK-1"},
+			{Name: "audience", Value: "engineers"},
+		}))
+	})
+
+	It("should reject missing, unknown and invalid parameters", func() {
+		_, err := spec.ResolveParameters(nil)
+		Expect(err).To(MatchError("parameter ticket is required"))
+
+		_, err = spec.ResolveParameters([]Parameter{{Name: "ticket", Value: "ARK-1"}, {Name: "tone", Value: "formal"}})
+		Expect(err).To(MatchError("unknown parameter tone"))
+
+		_, err = spec.ResolveParameters([]Parameter{{Name: "ticket", Value: "ARK-1"}, {Name: "audience", Value: "lawyers"}})
+		Expect(err).To(MatchError("parameter audience must be one of [engineers executives]"))
+	})
+
+	It("should validate values against the type of the parameter", func() {
+		typed := QueryTemplateSpec{Parameters: []QueryTemplateParameter{
+			{Name: "days", Type: ParameterTypeInteger, Default: "3"},
+			{Name: "verbose", Type: ParameterTypeBoolean},
+		}}
+		params, err := typed.ResolveParameters([]Parameter{{Name: "verbose", Value: "true"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]Parameter{{Name: "days", Value: "3"}, {Name: "verbose", Value: "true"}}))
+
+		_, err = typed.ResolveParameters([]Parameter{{Name: "days", Value: "three"}})
+		Expect(err).To(MatchError(`parameter days must be of type integer, got "three"`))
+
+		value, err := typed.Parameters[0].TypedValue("7")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(value).To(Equal(int64(7)))
+	})
+
+	It("should leave out optional parameters without value or default", func() {
+		params, err := ApplyParameterSchema([]QueryTemplateParameter{{Name: "tone"}}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(BeEmpty())
+	})
+
+	It("should not validate values from references", func() {
+		ref := Parameter{Name: "days", ValueFrom: &ValueFromSource{QueryFieldRef: &FieldSelector{FieldPath: "metadata.name"}}}
+		params, err := ApplyParameterSchema([]QueryTemplateParameter{{Name: "days", Type: ParameterTypeInteger}}, []Parameter{ref})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(params).To(Equal([]Parameter{ref}))
+	})
+
+	It("should create query specs that do not share the template", func() {
+		querySpec, err := spec.NewQuerySpec([]Parameter{{Name: "ticket", Value: "ARK-1"}, {Name: "audience", Value: "executives"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(querySpec.Targets).To(Equal(spec.Targets))
+		Expect(querySpec.Timeout.Duration).To(Equal(time.Minute))
+		Expect(querySpec.Parameters).To(ContainElement(Parameter{Name: "audience", Value: "executives"}))
+		Expect(querySpec.ParameterSchema).To(Equal(spec.Parameters))
+
+		querySpec.Targets[0].Name = "changed"
+		Expect(spec.Targets[0].Name).To(Equal("summarizer"))
+		querySpec.SLO.MaxTokens = 1
+		Expect(spec.SLO.MaxTokens).To(Equal(int64(20000)))
+	})
+})
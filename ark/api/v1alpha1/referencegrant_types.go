@@ -0,0 +1,95 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Kinds of resources that reference resources in other namespaces
+const (
+	ReferenceFromQuery = "Query"
+	ReferenceFromAgent = "Agent"
+)
+
+// ReferenceGrantFrom is a namespace whose resources of a kind may reference the granted resources
+type ReferenceGrantFrom struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Query;Agent
+	// Kind of the referencing resources
+	Kind string `json:"kind"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	// Namespace of the referencing resources
+	Namespace string `json:"namespace"`
+}
+
+// ReferenceGrantTo is a resource, or all resources of a kind, of the grant namespace that may be referenced
+type ReferenceGrantTo struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Enum=Agent;Team;Model;Tool
+	// Kind of the referenced resources
+	Kind string `json:"kind"`
+	// +kubebuilder:validation:Optional
+	// Name of the referenced resource. Empty grants every resource of the kind
+	Name string `json:"name,omitempty"`
+}
+
+// ReferenceGrantSpec allows resources in other namespaces to reference resources in the namespace
+// of the grant
+type ReferenceGrantSpec struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Namespaces and kinds of the resources that may reference the granted resources
+	From []ReferenceGrantFrom `json:"from"`
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinItems=1
+	// Resources of the grant namespace that may be referenced
+	To []ReferenceGrantTo `json:"to"`
+}
+
+// Allows returns true if the grant lets a resource of fromKind in fromNamespace reference the
+// resource toKind/toName in the namespace of the grant
+func (s *ReferenceGrantSpec) Allows(fromKind, fromNamespace string, toKind TargetKind, toName string) bool {
+	fromAllowed := false
+	for _, from := range s.From {
+		if from.Kind == fromKind && from.Namespace == fromNamespace {
+			fromAllowed = true
+			break
+		}
+	}
+	if !fromAllowed {
+		return false
+	}
+	for _, to := range s.To {
+		if to.Kind == string(toKind) && (to.Name == "" || to.Name == toName) {
+			return true
+		}
+	}
+	return false
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age of the grant"
+
+// ReferenceGrant is the Schema for the referencegrants API. It is created in the namespace of the
+// referenced resources by their owners.
+type ReferenceGrant struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec ReferenceGrantSpec `json:"spec,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ReferenceGrantList contains a list of ReferenceGrant.
+type ReferenceGrantList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ReferenceGrant `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ReferenceGrant{}, &ReferenceGrantList{})
+}
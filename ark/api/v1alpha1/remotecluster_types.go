@@ -0,0 +1,83 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RemoteClusterEndpoint is the API server of a remote cluster and the token used to call it
+type RemoteClusterEndpoint struct {
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern=`^https://`
+	// URL of the Kubernetes API server of the remote cluster
+	URL string `json:"url"`
+	// +kubebuilder:validation:Required
+	// Secret key holding the bearer token, e.g. of a service account of the remote cluster
+	TokenSecretRef corev1.SecretKeySelector `json:"tokenSecretRef"`
+	// +kubebuilder:validation:Optional
+	// Secret key holding the PEM encoded CA certificate of the API server. The system roots are
+	// used when unset
+	CASecretRef *corev1.SecretKeySelector `json:"caSecretRef,omitempty"`
+}
+
+// +kubebuilder:validation:XValidation:rule="has(self.kubeconfigSecretRef) != has(self.endpoint)",message="exactly one of kubeconfigSecretRef or endpoint is required"
+type RemoteClusterSpec struct {
+	// +kubebuilder:validation:Optional
+	// Secret key holding a kubeconfig of the remote cluster. Its current context is used. Only
+	// inline server, CA and credential data is accepted: exec plugins, auth providers, file
+	// references and insecure-skip-tls-verify are rejected
+	KubeconfigSecretRef *corev1.SecretKeySelector `json:"kubeconfigSecretRef,omitempty"`
+	// +kubebuilder:validation:Optional
+	// API server and token of the remote cluster
+	Endpoint *RemoteClusterEndpoint `json:"endpoint,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Namespace of the remote cluster queries are created in. Defaults to the namespace of the query
+	Namespace string `json:"namespace,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Other namespaces of the remote cluster query targets may name. Targets are restricted to
+	// the namespace of the remote cluster when unset
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1m"
+	// Interval between connectivity checks of the remote cluster
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
+}
+
+type RemoteClusterStatus struct {
+	// +kubebuilder:validation:Optional
+	// Host of the remote API server
+	Host string `json:"host,omitempty"`
+	// Conditions represent the latest available observations of the remote cluster
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Host",type=string,JSONPath=`.status.host`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="RemoteClusterAvailable")].status`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// RemoteCluster registers another ARK cluster. Query targets that name the cluster are executed by
+// a query created in the remote cluster, whose responses and token usage are merged into the query.
+type RemoteCluster struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RemoteClusterSpec   `json:"spec,omitempty"`
+	Status RemoteClusterStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// RemoteClusterList contains a list of RemoteCluster.
+type RemoteClusterList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []RemoteCluster `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&RemoteCluster{}, &RemoteClusterList{})
+}
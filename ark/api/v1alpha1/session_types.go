@@ -0,0 +1,86 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SessionSpec identifies the session that a Session aggregates.
+type SessionSpec struct {
+	// Session ID shared by the queries of the session
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	SessionId string `json:"sessionId"`
+}
+
+// SessionStatus holds the totals of the queries of a session and of their evaluations.
+type SessionStatus struct {
+	// +kubebuilder:validation:Optional
+	// Number of queries in the session
+	Queries int32 `json:"queries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of queries that ended in error
+	FailedQueries int32 `json:"failedQueries,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Token usage of all queries
+	TokenUsage TokenUsage `json:"tokenUsage,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Cost of all queries, empty when no query has a cost
+	Cost string `json:"cost,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time spent executing the queries
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Creation time of the first query
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Creation time of the latest query
+	LastQueryTime *metav1.Time `json:"lastQueryTime,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of completed evaluations of the queries
+	Evaluations int32 `json:"evaluations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Number of completed evaluations that passed
+	PassedEvaluations int32 `json:"passedEvaluations,omitempty"`
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^(0(\.[0-9]+)?|1(\.0+)?)$
+	// Average score of the completed evaluations
+	AverageScore string `json:"averageScore,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Memories the queries used
+	Memories []MemoryRef `json:"memories,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Session",type=string,JSONPath=`.spec.sessionId`
+// +kubebuilder:printcolumn:name="Queries",type=integer,JSONPath=`.status.queries`
+// +kubebuilder:printcolumn:name="Tokens",type=integer,JSONPath=`.status.tokenUsage.totalTokens`
+// +kubebuilder:printcolumn:name="Cost",type=string,JSONPath=`.status.cost`
+// +kubebuilder:printcolumn:name="Score",type=string,JSONPath=`.status.averageScore`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// Session aggregates the queries that share a session ID. Sessions are maintained by the
+// controller and deleted when their last query is deleted.
+type Session struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	// +kubebuilder:validation:XValidation:rule="self == oldSelf",message="Session spec is immutable"
+	Spec   SessionSpec   `json:"spec,omitempty"`
+	Status SessionStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// SessionList contains a list of Session.
+type SessionList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Session `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Session{}, &SessionList{})
+}
@@ -9,6 +9,20 @@ import (
 type TeamMember struct {
 	Name string `json:"name"`
 	Type string `json:"type"`
+	// +kubebuilder:validation:Optional
+	// Role of the member in the team, such as researcher, critic or writer. Shown to the selector
+	// in place of the member description
+	Role string `json:"role,omitempty"`
+}
+
+// TeamMemberSelector adds the agents matching a label selector to the team when it runs, so
+// agents created later join the team without changes to it
+type TeamMemberSelector struct {
+	// +kubebuilder:validation:Optional
+	// Role of the selected agents in the team
+	Role string `json:"role,omitempty"`
+	// Labels of the agents in the team namespace that join the team
+	Selector metav1.LabelSelector `json:"selector"`
 }
 
 type TeamSelectorSpec struct {
@@ -25,13 +39,47 @@ type TeamGraphSpec struct {
 	Edges []TeamGraphEdge `json:"edges"`
 }
 
+// TeamTerminationSpec ends a team execution when a member message matches one of its conditions
+type TeamTerminationSpec struct {
+	// +kubebuilder:validation:Optional
+	// Keywords that end the team when a member message contains one, ignoring case
+	Keywords []string `json:"keywords,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Regular expressions that end the team when a member message matches one
+	Patterns []string `json:"patterns,omitempty"`
+	// +kubebuilder:validation:Optional
+	// CEL expression over the variables content and member (strings) and turn (int). The team ends
+	// when it evaluates to true
+	Expression string `json:"expression,omitempty"`
+}
+
+// TeamModeratorSpec configures a model that decides after each turn whether the team has converged
+type TeamModeratorSpec struct {
+	// Model asked whether the team has converged
+	ModelRef AgentModelRef `json:"modelRef"`
+	// +kubebuilder:validation:Optional
+	// Describes when the team has converged. Defaults to the user request being answered
+	Prompt string `json:"prompt,omitempty"`
+}
+
 type TeamSpec struct {
-	Members     []TeamMember      `json:"members"`
-	Strategy    string            `json:"strategy"`
-	Description string            `json:"description,omitempty"`
-	MaxTurns    *int              `json:"maxTurns,omitempty"`
-	Selector    *TeamSelectorSpec `json:"selector,omitempty"`
-	Graph       *TeamGraphSpec    `json:"graph,omitempty"`
+	// +kubebuilder:validation:Optional
+	Members []TeamMember `json:"members,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Agents that join the team by label, resolved each time the team runs and ordered by name
+	// after the members
+	MemberSelectors []TeamMemberSelector `json:"memberSelectors,omitempty"`
+	Strategy        string               `json:"strategy"`
+	Description     string               `json:"description,omitempty"`
+	MaxTurns        *int                 `json:"maxTurns,omitempty"`
+	Selector        *TeamSelectorSpec    `json:"selector,omitempty"`
+	Graph           *TeamGraphSpec       `json:"graph,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Conditions on member messages that end the team
+	Termination *TeamTerminationSpec `json:"termination,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Model that ends the team once it has converged
+	Moderator *TeamModeratorSpec `json:"moderator,omitempty"`
 }
 
 type TeamStatus struct{}
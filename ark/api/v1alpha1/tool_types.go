@@ -3,6 +3,7 @@
 package v1alpha1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 )
@@ -72,14 +73,106 @@ type ToolAnnotations struct {
 	Title string `json:"title,omitempty"`
 }
 
+// ToolSecurity restricts what a tool is allowed to do at execution time
+type ToolSecurity struct {
+	// Hosts the tool may contact. Supports wildcard prefixes such as "*.example.com".
+	// Empty means no host restriction.
+	// +kubebuilder:validation:Optional
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+	// Allowed HTTP methods for http tools. Empty means no method restriction.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:items:Enum=GET;POST;PUT;DELETE;PATCH
+	AllowedMethods []string `json:"allowedMethods,omitempty"`
+	// Maximum size of the tool response in bytes. Zero means no limit.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	MaxResponseBytes int64 `json:"maxResponseBytes,omitempty"`
+	// Maximum execution time for a single tool call (e.g., "30s", "2m")
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+[smh]?$
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// ToolOutputPolicy controls how large tool results are reduced before being added to the conversation
+type ToolOutputPolicy struct {
+	// Maximum size of the tool result in bytes passed to the model
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Minimum=1
+	MaxBytes int64 `json:"maxBytes"`
+	// Which part of the output to keep when truncating
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=head;tail
+	// +kubebuilder:default="head"
+	Truncate string `json:"truncate,omitempty"`
+	// Model used to summarize output exceeding maxBytes. Falls back to truncation if summarization fails.
+	// +kubebuilder:validation:Optional
+	SummarizeWithModelRef *AgentModelRef `json:"summarizeWithModelRef,omitempty"`
+	// Store the original full output in query memory for audit
+	// +kubebuilder:validation:Optional
+	StoreFullOutput bool `json:"storeFullOutput,omitempty"`
+}
+
+// JobToolSpec runs every call of a tool as a Kubernetes Job in the tool namespace, for tools that
+// run longer than an HTTP request can wait
+type JobToolSpec struct {
+	// Image of the container handling the call
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
+	// Entrypoint of the container. Defaults to the entrypoint of the image
+	// +kubebuilder:validation:Optional
+	Command []string `json:"command,omitempty"`
+	// Arguments of the entrypoint
+	// +kubebuilder:validation:Optional
+	Args []string `json:"args,omitempty"`
+	// Service account the job pods run as. Containers writing their result to the call ConfigMap
+	// need update permission on ConfigMaps
+	// +kubebuilder:validation:Optional
+	ServiceAccountName string `json:"serviceAccountName,omitempty"`
+	// Resources of the container
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Maximum time of a call including scheduling of its pod (e.g., "10m"). Defaults to 10m
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+[smh]?$
+	Timeout string `json:"timeout,omitempty"`
+	// Retries of a failed pod before the call fails
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Minimum=0
+	BackoffLimit *int32 `json:"backoffLimit,omitempty"`
+}
+
+// CodeInterpreterSpec runs code written by the model in an ephemeral pod without network access
+type CodeInterpreterSpec struct {
+	// Language of the code
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=python;javascript
+	// +kubebuilder:default="python"
+	Language string `json:"language,omitempty"`
+	// Image running the code. Defaults to a slim Python or Node.js image
+	// +kubebuilder:validation:Optional
+	Image string `json:"image,omitempty"`
+	// Resources of the sandbox container. Defaults to limits of 1 CPU and 512Mi memory
+	// +kubebuilder:validation:Optional
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+	// Maximum run time of the code (e.g., "60s"). Defaults to 60s
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Pattern=^[0-9]+[smh]?$
+	Timeout string `json:"timeout,omitempty"`
+}
+
 type ToolSpec struct {
 	// +kubebuilder:validation:Required
-	// +kubebuilder:validation:Enum=http;mcp;agent;builtin
+	// +kubebuilder:validation:Enum=http;mcp;agent;builtin;job;code-interpreter
 	Type string `json:"type"`
 	// Tool description
 	Description string `json:"description,omitempty"`
 	// Input schema for the tool
 	InputSchema *runtime.RawExtension `json:"inputSchema,omitempty"`
+	// JSON schema the tool response must match. Responses that do not match are returned to the
+	// model as a tool error instead of the response.
+	// +kubebuilder:validation:Optional
+	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
 	// Optional additional tool information
 	Annotations *ToolAnnotations `json:"annotations,omitempty"`
 	// HTTP-specific configuration for HTTP-based tools
@@ -95,6 +188,24 @@ type ToolSpec struct {
 	// This field is required only if Type = "builtin".
 	// +kubebuilder:validation:Optional
 	Builtin *BuiltinToolRef `json:"builtin,omitempty"`
+	// Job-specific configuration for tools running as Kubernetes Jobs.
+	// This field is required only if Type = "job".
+	// +kubebuilder:validation:Optional
+	Job *JobToolSpec `json:"job,omitempty"`
+	// Sandbox configuration for code interpreter tools.
+	// This field is optional if Type = "code-interpreter".
+	// +kubebuilder:validation:Optional
+	CodeInterpreter *CodeInterpreterSpec `json:"codeInterpreter,omitempty"`
+	// Security policy enforced when the tool is executed
+	// +kubebuilder:validation:Optional
+	Security *ToolSecurity `json:"security,omitempty"`
+	// Post-processing applied to large tool results
+	// +kubebuilder:validation:Optional
+	Output *ToolOutputPolicy `json:"output,omitempty"`
+	// Pause every call of the tool until a user approves its arguments with a ToolApproval. Calls
+	// wait until the query times out
+	// +kubebuilder:validation:Optional
+	RequiresApproval bool `json:"requiresApproval,omitempty"`
 }
 
 type HTTPSpec struct {
@@ -117,10 +228,12 @@ type HTTPSpec struct {
 
 // Tool type constants
 const (
-	ToolTypeHTTP    = "http"
-	ToolTypeMCP     = "mcp"
-	ToolTypeAgent   = "agent"
-	ToolTypeBuiltin = "builtin"
+	ToolTypeHTTP            = "http"
+	ToolTypeMCP             = "mcp"
+	ToolTypeAgent           = "agent"
+	ToolTypeBuiltin         = "builtin"
+	ToolTypeJob             = "job"
+	ToolTypeCodeInterpreter = "code-interpreter"
 )
 
 // Tool state constants
@@ -161,6 +274,11 @@ func (in *ToolSpec) DeepCopyInto(out *ToolSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.OutputSchema != nil {
+		in, out := &in.OutputSchema, &out.OutputSchema
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Annotations != nil {
 		in, out := &in.Annotations, &out.Annotations
 		*out = new(ToolAnnotations)
@@ -176,6 +294,26 @@ func (in *ToolSpec) DeepCopyInto(out *ToolSpec) {
 		*out = new(MCPToolRef)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Job != nil {
+		in, out := &in.Job, &out.Job
+		*out = new(JobToolSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.CodeInterpreter != nil {
+		in, out := &in.CodeInterpreter, &out.CodeInterpreter
+		*out = new(CodeInterpreterSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Security != nil {
+		in, out := &in.Security, &out.Security
+		*out = new(ToolSecurity)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Output != nil {
+		in, out := &in.Output, &out.Output
+		*out = new(ToolOutputPolicy)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 func (in *MCPServerRef) DeepCopyInto(out *MCPServerRef) {
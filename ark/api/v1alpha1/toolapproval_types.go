@@ -0,0 +1,74 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Tool approval phases
+const (
+	ToolApprovalPending  = "pending"
+	ToolApprovalApproved = "approved"
+	ToolApprovalRejected = "rejected"
+	ToolApprovalExpired  = "expired"
+)
+
+// ToolApprovalSpec is a tool call waiting for approval
+type ToolApprovalSpec struct {
+	// +kubebuilder:validation:Required
+	// Query whose execution made the call
+	Query string `json:"query"`
+	// +kubebuilder:validation:Required
+	// Tool called
+	Tool string `json:"tool"`
+	// +kubebuilder:validation:Optional
+	// ID the model gave the tool call
+	ToolCallID string `json:"toolCallId,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Arguments of the call as JSON
+	Arguments string `json:"arguments,omitempty"`
+}
+
+type ToolApprovalStatus struct {
+	// +kubebuilder:default="pending"
+	// +kubebuilder:validation:Enum=pending;approved;rejected;expired
+	Phase string `json:"phase,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Reason given when the call was rejected, or why it expired
+	Reason string `json:"reason,omitempty"`
+	// +kubebuilder:validation:Optional
+	// Time the call was approved, rejected or expired
+	DecisionTime *metav1.Time `json:"decisionTime,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Tool",type=string,JSONPath=`.spec.tool`
+// +kubebuilder:printcolumn:name="Query",type=string,JSONPath=`.spec.query`
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
+
+// ToolApproval is created for each call of a tool that requires approval. The call runs once the
+// ToolApproval is annotated with ark.mckinsey.com/approve, and is rejected when it is annotated with
+// ark.mckinsey.com/reject, whose value is the reason.
+type ToolApproval struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ToolApprovalSpec   `json:"spec,omitempty"`
+	Status ToolApprovalStatus `json:"status,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+
+// ToolApprovalList contains a list of ToolApproval.
+type ToolApprovalList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ToolApproval `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&ToolApproval{}, &ToolApprovalList{})
+}
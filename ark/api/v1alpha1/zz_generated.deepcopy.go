@@ -12,6 +12,26 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *A2ARequirements) DeepCopyInto(out *A2ARequirements) {
+	*out = *in
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]A2ACapability, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new A2ARequirements.
+func (in *A2ARequirements) DeepCopy() *A2ARequirements {
+	if in == nil {
+		return nil
+	}
+	out := new(A2ARequirements)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Agent) DeepCopyInto(out *Agent) {
 	*out = *in
@@ -86,6 +106,80 @@ func (in *AgentModelRef) DeepCopy() *AgentModelRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRevision) DeepCopyInto(out *AgentRevision) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRevision.
+func (in *AgentRevision) DeepCopy() *AgentRevision {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRevision)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentRevision) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRevisionList) DeepCopyInto(out *AgentRevisionList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]AgentRevision, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRevisionList.
+func (in *AgentRevisionList) DeepCopy() *AgentRevisionList {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRevisionList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *AgentRevisionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AgentRevisionSpec) DeepCopyInto(out *AgentRevisionSpec) {
+	*out = *in
+	in.Template.DeepCopyInto(&out.Template)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentRevisionSpec.
+func (in *AgentRevisionSpec) DeepCopy() *AgentRevisionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(AgentRevisionSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 	*out = *in
@@ -118,6 +212,43 @@ func (in *AgentSpec) DeepCopyInto(out *AgentSpec) {
 		*out = new(runtime.RawExtension)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ModelParameters != nil {
+		in, out := &in.ModelParameters, &out.ModelParameters
+		*out = new(ModelParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MaxToolIterations != nil {
+		in, out := &in.MaxToolIterations, &out.MaxToolIterations
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]GuardrailRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.KnowledgeBases != nil {
+		in, out := &in.KnowledgeBases, &out.KnowledgeBases
+		*out = make([]KnowledgeBaseRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.PostProcessors != nil {
+		in, out := &in.PostProcessors, &out.PostProcessors
+		*out = make([]PostProcessor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.A2ARequirements != nil {
+		in, out := &in.A2ARequirements, &out.A2ARequirements
+		*out = new(A2ARequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RevisionHistoryLimit != nil {
+		in, out := &in.RevisionHistoryLimit, &out.RevisionHistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AgentSpec.
@@ -192,11 +323,35 @@ func (in *AgentToolRef) DeepCopy() *AgentToolRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AzureManagedIdentity) DeepCopyInto(out *AzureManagedIdentity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AzureManagedIdentity.
+func (in *AzureManagedIdentity) DeepCopy() *AzureManagedIdentity {
+	if in == nil {
+		return nil
+	}
+	out := new(AzureManagedIdentity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *AzureModelConfig) DeepCopyInto(out *AzureModelConfig) {
 	*out = *in
 	in.BaseURL.DeepCopyInto(&out.BaseURL)
-	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.APIKey != nil {
+		in, out := &in.APIKey, &out.APIKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ManagedIdentity != nil {
+		in, out := &in.ManagedIdentity, &out.ManagedIdentity
+		*out = new(AzureManagedIdentity)
+		**out = **in
+	}
 	if in.APIVersion != nil {
 		in, out := &in.APIVersion, &out.APIVersion
 		*out = new(ValueSource)
@@ -315,6 +470,10 @@ func (in *BatchEvaluationProgress) DeepCopyInto(out *BatchEvaluationProgress) {
 		*out = make([]ChildEvaluationStatus, len(*in))
 		copy(*out, *in)
 	}
+	if in.EstimatedCompletionTime != nil {
+		in, out := &in.EstimatedCompletionTime, &out.EstimatedCompletionTime
+		*out = (*in).DeepCopy()
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BatchEvaluationProgress.
@@ -384,6 +543,21 @@ func (in *BedrockModelConfig) DeepCopyInto(out *BedrockModelConfig) {
 		*out = new(ValueSource)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InferenceProfileArn != nil {
+		in, out := &in.InferenceProfileArn, &out.InferenceProfileArn
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.FailoverRegions != nil {
+		in, out := &in.FailoverRegions, &out.FailoverRegions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.RoleArn != nil {
+		in, out := &in.RoleArn, &out.RoleArn
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MaxTokens != nil {
 		in, out := &in.MaxTokens, &out.MaxTokens
 		*out = new(int)
@@ -413,6 +587,21 @@ func (in *BedrockModelConfig) DeepCopy() *BedrockModelConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BuiltinToolRef) DeepCopyInto(out *BuiltinToolRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BuiltinToolRef.
+func (in *BuiltinToolRef) DeepCopy() *BuiltinToolRef {
+	if in == nil {
+		return nil
+	}
+	out := new(BuiltinToolRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ChildEvaluationStatus) DeepCopyInto(out *ChildEvaluationStatus) {
 	*out = *in
@@ -428,6 +617,26 @@ func (in *ChildEvaluationStatus) DeepCopy() *ChildEvaluationStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CodeInterpreterSpec) DeepCopyInto(out *CodeInterpreterSpec) {
+	*out = *in
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CodeInterpreterSpec.
+func (in *CodeInterpreterSpec) DeepCopy() *CodeInterpreterSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CodeInterpreterSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DirectEvaluationConfig) DeepCopyInto(out *DirectEvaluationConfig) {
 	*out = *in
@@ -564,6 +773,41 @@ func (in *EvaluationList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluationMetricThreshold) DeepCopyInto(out *EvaluationMetricThreshold) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationMetricThreshold.
+func (in *EvaluationMetricThreshold) DeepCopy() *EvaluationMetricThreshold {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluationMetricThreshold)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluationPassCriteria) DeepCopyInto(out *EvaluationPassCriteria) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make([]EvaluationMetricThreshold, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationPassCriteria.
+func (in *EvaluationPassCriteria) DeepCopy() *EvaluationPassCriteria {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluationPassCriteria)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationRef) DeepCopyInto(out *EvaluationRef) {
 	*out = *in
@@ -579,6 +823,34 @@ func (in *EvaluationRef) DeepCopy() *EvaluationRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluationRunRecord) DeepCopyInto(out *EvaluationRunRecord) {
+	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.TokenUsage != nil {
+		in, out := &in.TokenUsage, &out.TokenUsage
+		*out = new(TokenUsage)
+		**out = **in
+	}
+	in.CompletedAt.DeepCopyInto(&out.CompletedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationRunRecord.
+func (in *EvaluationRunRecord) DeepCopy() *EvaluationRunRecord {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluationRunRecord)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationSpec) DeepCopyInto(out *EvaluationSpec) {
 	*out = *in
@@ -594,6 +866,16 @@ func (in *EvaluationSpec) DeepCopyInto(out *EvaluationSpec) {
 		*out = new(v1.Duration)
 		**out = **in
 	}
+	if in.HistoryLimit != nil {
+		in, out := &in.HistoryLimit, &out.HistoryLimit
+		*out = new(int32)
+		**out = **in
+	}
+	if in.PassCriteria != nil {
+		in, out := &in.PassCriteria, &out.PassCriteria
+		*out = new(EvaluationPassCriteria)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluationSpec.
@@ -609,6 +891,13 @@ func (in *EvaluationSpec) DeepCopy() *EvaluationSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluationStatus) DeepCopyInto(out *EvaluationStatus) {
 	*out = *in
+	if in.Metrics != nil {
+		in, out := &in.Metrics, &out.Metrics
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.TokenUsage != nil {
 		in, out := &in.TokenUsage, &out.TokenUsage
 		*out = new(TokenUsage)
@@ -624,6 +913,13 @@ func (in *EvaluationStatus) DeepCopyInto(out *EvaluationStatus) {
 		*out = new(BatchEvaluationProgress)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.History != nil {
+		in, out := &in.History, &out.History
+		*out = make([]EvaluationRunRecord, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make([]v1.Condition, len(*in))
@@ -670,6 +966,48 @@ func (in *Evaluator) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluatorAuth) DeepCopyInto(out *EvaluatorAuth) {
+	*out = *in
+	if in.BearerToken != nil {
+		in, out := &in.BearerToken, &out.BearerToken
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Basic != nil {
+		in, out := &in.Basic, &out.Basic
+		*out = new(EvaluatorBasicAuth)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluatorAuth.
+func (in *EvaluatorAuth) DeepCopy() *EvaluatorAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluatorAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluatorBasicAuth) DeepCopyInto(out *EvaluatorBasicAuth) {
+	*out = *in
+	in.Username.DeepCopyInto(&out.Username)
+	in.Password.DeepCopyInto(&out.Password)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluatorBasicAuth.
+func (in *EvaluatorBasicAuth) DeepCopy() *EvaluatorBasicAuth {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluatorBasicAuth)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EvaluatorList) DeepCopyInto(out *EvaluatorList) {
 	*out = *in
@@ -718,6 +1056,23 @@ func (in *EvaluatorSpec) DeepCopyInto(out *EvaluatorSpec) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(EvaluatorTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Auth != nil {
+		in, out := &in.Auth, &out.Auth
+		*out = new(EvaluatorAuth)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluatorSpec.
@@ -745,6 +1100,36 @@ func (in *EvaluatorStatus) DeepCopy() *EvaluatorStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EvaluatorTLS) DeepCopyInto(out *EvaluatorTLS) {
+	*out = *in
+	if in.CACert != nil {
+		in, out := &in.CACert, &out.CACert
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientCert != nil {
+		in, out := &in.ClientCert, &out.ClientCert
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientKey != nil {
+		in, out := &in.ClientKey, &out.ClientKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EvaluatorTLS.
+func (in *EvaluatorTLS) DeepCopy() *EvaluatorTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(EvaluatorTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *EventEvaluationConfig) DeepCopyInto(out *EventEvaluationConfig) {
 	*out = *in
@@ -795,8 +1180,23 @@ func (in *ExpressionRule) DeepCopy() *ExpressionRule {
 	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSpec.
-func (in *HTTPSpec) DeepCopy() *HTTPSpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FieldSelector) DeepCopyInto(out *FieldSelector) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FieldSelector.
+func (in *FieldSelector) DeepCopy() *FieldSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(FieldSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new HTTPSpec.
+func (in *HTTPSpec) DeepCopy() *HTTPSpec {
 	if in == nil {
 		return nil
 	}
@@ -805,6 +1205,119 @@ func (in *HTTPSpec) DeepCopy() *HTTPSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Guardrail) DeepCopyInto(out *Guardrail) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Guardrail.
+func (in *Guardrail) DeepCopy() *Guardrail {
+	if in == nil {
+		return nil
+	}
+	out := new(Guardrail)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Guardrail) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailList) DeepCopyInto(out *GuardrailList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Guardrail, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailList.
+func (in *GuardrailList) DeepCopy() *GuardrailList {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GuardrailList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailRef) DeepCopyInto(out *GuardrailRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailRef.
+func (in *GuardrailRef) DeepCopy() *GuardrailRef {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GuardrailSpec) DeepCopyInto(out *GuardrailSpec) {
+	*out = *in
+	if in.Stages != nil {
+		in, out := &in.Stages, &out.Stages
+		*out = make([]GuardrailStage, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Keywords != nil {
+		in, out := &in.Keywords, &out.Keywords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Expressions != nil {
+		in, out := &in.Expressions, &out.Expressions
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModerationModelRef != nil {
+		in, out := &in.ModerationModelRef, &out.ModerationModelRef
+		*out = new(AgentModelRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GuardrailSpec.
+func (in *GuardrailSpec) DeepCopy() *GuardrailSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(GuardrailSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Header) DeepCopyInto(out *Header) {
 	*out = *in
@@ -867,26 +1380,60 @@ func (in *HeaderValueSource) DeepCopy() *HeaderValueSource {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServer) DeepCopyInto(out *MCPServer) {
+func (in *JobToolSpec) DeepCopyInto(out *JobToolSpec) {
 	*out = *in
+	if in.Command != nil {
+		in, out := &in.Command, &out.Command
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Args != nil {
+		in, out := &in.Args, &out.Args
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.BackoffLimit != nil {
+		in, out := &in.BackoffLimit, &out.BackoffLimit
+		*out = new(int32)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new JobToolSpec.
+func (in *JobToolSpec) DeepCopy() *JobToolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(JobToolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBase) DeepCopyInto(out *KnowledgeBase) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
-func (in *MCPServer) DeepCopy() *MCPServer {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBase.
+func (in *KnowledgeBase) DeepCopy() *KnowledgeBase {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServer)
+	out := new(KnowledgeBase)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServer) DeepCopyObject() runtime.Object {
+func (in *KnowledgeBase) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -894,109 +1441,192 @@ func (in *MCPServer) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
+func (in *KnowledgeBaseChunking) DeepCopyInto(out *KnowledgeBaseChunking) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseChunking.
+func (in *KnowledgeBaseChunking) DeepCopy() *KnowledgeBaseChunking {
+	if in == nil {
+		return nil
+	}
+	out := new(KnowledgeBaseChunking)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBaseConfigMapSource) DeepCopyInto(out *KnowledgeBaseConfigMapSource) {
+	*out = *in
+	if in.Keys != nil {
+		in, out := &in.Keys, &out.Keys
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseConfigMapSource.
+func (in *KnowledgeBaseConfigMapSource) DeepCopy() *KnowledgeBaseConfigMapSource {
+	if in == nil {
+		return nil
+	}
+	out := new(KnowledgeBaseConfigMapSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBaseList) DeepCopyInto(out *KnowledgeBaseList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]MCPServer, len(*in))
+		*out = make([]KnowledgeBase, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
-func (in *MCPServerList) DeepCopy() *MCPServerList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseList.
+func (in *KnowledgeBaseList) DeepCopy() *KnowledgeBaseList {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerList)
+	out := new(KnowledgeBaseList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MCPServerList) DeepCopyObject() runtime.Object {
+func (in *KnowledgeBaseList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerRef.
-func (in *MCPServerRef) DeepCopy() *MCPServerRef {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBaseRef) DeepCopyInto(out *KnowledgeBaseRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseRef.
+func (in *KnowledgeBaseRef) DeepCopy() *KnowledgeBaseRef {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerRef)
+	out := new(KnowledgeBaseRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
+func (in *KnowledgeBaseS3Source) DeepCopyInto(out *KnowledgeBaseS3Source) {
 	*out = *in
-	in.Address.DeepCopyInto(&out.Address)
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make([]Header, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
+	if in.AccessKeyID != nil {
+		in, out := &in.AccessKeyID, &out.AccessKeyID
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
 	}
-	if in.PollInterval != nil {
-		in, out := &in.PollInterval, &out.PollInterval
-		*out = new(v1.Duration)
-		**out = **in
+	if in.SecretAccessKey != nil {
+		in, out := &in.SecretAccessKey, &out.SecretAccessKey
+		*out = new(ValueSource)
+		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
-func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseS3Source.
+func (in *KnowledgeBaseS3Source) DeepCopy() *KnowledgeBaseS3Source {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerSpec)
+	out := new(KnowledgeBaseS3Source)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+func (in *KnowledgeBaseSource) DeepCopyInto(out *KnowledgeBaseSource) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	if in.ConfigMap != nil {
+		in, out := &in.ConfigMap, &out.ConfigMap
+		*out = new(KnowledgeBaseConfigMapSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.S3 != nil {
+		in, out := &in.S3, &out.S3
+		*out = new(KnowledgeBaseS3Source)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseSource.
+func (in *KnowledgeBaseSource) DeepCopy() *KnowledgeBaseSource {
+	if in == nil {
+		return nil
+	}
+	out := new(KnowledgeBaseSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBaseSpec) DeepCopyInto(out *KnowledgeBaseSpec) {
+	*out = *in
+	if in.Sources != nil {
+		in, out := &in.Sources, &out.Sources
+		*out = make([]KnowledgeBaseSource, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	out.EmbeddingModelRef = in.EmbeddingModelRef
+	out.Memory = in.Memory
+	if in.Chunking != nil {
+		in, out := &in.Chunking, &out.Chunking
+		*out = new(KnowledgeBaseChunking)
+		**out = **in
+	}
+	if in.RefreshInterval != nil {
+		in, out := &in.RefreshInterval, &out.RefreshInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
-func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseSpec.
+func (in *KnowledgeBaseSpec) DeepCopy() *KnowledgeBaseSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPServerStatus)
+	out := new(KnowledgeBaseSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPToolRef.
-func (in *MCPToolRef) DeepCopy() *MCPToolRef {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *KnowledgeBaseStatus) DeepCopyInto(out *KnowledgeBaseStatus) {
+	*out = *in
+	if in.LastIndexedTime != nil {
+		in, out := &in.LastIndexedTime, &out.LastIndexedTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KnowledgeBaseStatus.
+func (in *KnowledgeBaseStatus) DeepCopy() *KnowledgeBaseStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(MCPToolRef)
+	out := new(KnowledgeBaseStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Memory) DeepCopyInto(out *Memory) {
+func (in *MCPServer) DeepCopyInto(out *MCPServer) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1004,18 +1634,18 @@ func (in *Memory) DeepCopyInto(out *Memory) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
-func (in *Memory) DeepCopy() *Memory {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServer.
+func (in *MCPServer) DeepCopy() *MCPServer {
 	if in == nil {
 		return nil
 	}
-	out := new(Memory)
+	out := new(MCPServer)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Memory) DeepCopyObject() runtime.Object {
+func (in *MCPServer) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1023,90 +1653,1882 @@ func (in *Memory) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryList) DeepCopyInto(out *MemoryList) {
+func (in *MCPServerList) DeepCopyInto(out *MCPServerList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Memory, len(*in))
+		*out = make([]MCPServer, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryList.
-func (in *MemoryList) DeepCopy() *MemoryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerList.
+func (in *MCPServerList) DeepCopy() *MCPServerList {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryList)
+	out := new(MCPServerList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *MemoryList) DeepCopyObject() runtime.Object {
+func (in *MCPServerList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
 	return nil
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryRef) DeepCopyInto(out *MemoryRef) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRef.
-func (in *MemoryRef) DeepCopy() *MemoryRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerRef.
+func (in *MCPServerRef) DeepCopy() *MCPServerRef {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryRef)
+	out := new(MCPServerRef)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
+func (in *MCPServerSpec) DeepCopyInto(out *MCPServerSpec) {
 	*out = *in
 	in.Address.DeepCopyInto(&out.Address)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemorySpec.
-func (in *MemorySpec) DeepCopy() *MemorySpec {
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerSpec.
+func (in *MCPServerSpec) DeepCopy() *MCPServerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MCPServerStatus) DeepCopyInto(out *MCPServerStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPServerStatus.
+func (in *MCPServerStatus) DeepCopy() *MCPServerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPServerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MCPToolRef.
+func (in *MCPToolRef) DeepCopy() *MCPToolRef {
+	if in == nil {
+		return nil
+	}
+	out := new(MCPToolRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Memory) DeepCopyInto(out *Memory) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Memory.
+func (in *Memory) DeepCopy() *Memory {
+	if in == nil {
+		return nil
+	}
+	out := new(Memory)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Memory) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryCompaction) DeepCopyInto(out *MemoryCompaction) {
+	*out = *in
+	if in.ModelRef != nil {
+		in, out := &in.ModelRef, &out.ModelRef
+		*out = new(AgentModelRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryCompaction.
+func (in *MemoryCompaction) DeepCopy() *MemoryCompaction {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryCompaction)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryEncryption) DeepCopyInto(out *MemoryEncryption) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryEncryption.
+func (in *MemoryEncryption) DeepCopy() *MemoryEncryption {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryEncryption)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryList) DeepCopyInto(out *MemoryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Memory, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryList.
+func (in *MemoryList) DeepCopy() *MemoryList {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *MemoryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPostgres) DeepCopyInto(out *MemoryPostgres) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(corev1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Cluster != nil {
+		in, out := &in.Cluster, &out.Cluster
+		*out = new(MemoryPostgresCluster)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Encryption != nil {
+		in, out := &in.Encryption, &out.Encryption
+		*out = new(MemoryEncryption)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPostgres.
+func (in *MemoryPostgres) DeepCopy() *MemoryPostgres {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPostgres)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryPostgresCluster) DeepCopyInto(out *MemoryPostgresCluster) {
+	*out = *in
+	out.StorageSize = in.StorageSize.DeepCopy()
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryPostgresCluster.
+func (in *MemoryPostgresCluster) DeepCopy() *MemoryPostgresCluster {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryPostgresCluster)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryRef) DeepCopyInto(out *MemoryRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryRef.
+func (in *MemoryRef) DeepCopy() *MemoryRef {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemorySpec) DeepCopyInto(out *MemorySpec) {
+	*out = *in
+	in.Address.DeepCopyInto(&out.Address)
+	if in.Postgres != nil {
+		in, out := &in.Postgres, &out.Postgres
+		*out = new(MemoryPostgres)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Compaction != nil {
+		in, out := &in.Compaction, &out.Compaction
+		*out = new(MemoryCompaction)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemorySpec.
+func (in *MemorySpec) DeepCopy() *MemorySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MemorySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemoryStatus) DeepCopyInto(out *MemoryStatus) {
+	*out = *in
+	if in.LastResolvedAddress != nil {
+		in, out := &in.LastResolvedAddress, &out.LastResolvedAddress
+		*out = new(string)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryStatus.
+func (in *MemoryStatus) DeepCopy() *MemoryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MemoryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MockModelConfig) DeepCopyInto(out *MockModelConfig) {
+	*out = *in
+	in.Script.DeepCopyInto(&out.Script)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MockModelConfig.
+func (in *MockModelConfig) DeepCopy() *MockModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MockModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Model) DeepCopyInto(out *Model) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
+func (in *Model) DeepCopy() *Model {
+	if in == nil {
+		return nil
+	}
+	out := new(Model)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Model) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
+	*out = *in
+	if in.OpenAI != nil {
+		in, out := &in.OpenAI, &out.OpenAI
+		*out = new(OpenAIModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Azure != nil {
+		in, out := &in.Azure, &out.Azure
+		*out = new(AzureModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Bedrock != nil {
+		in, out := &in.Bedrock, &out.Bedrock
+		*out = new(BedrockModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Mock != nil {
+		in, out := &in.Mock, &out.Mock
+		*out = new(MockModelConfig)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
+func (in *ModelConfig) DeepCopy() *ModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelContextWindow) DeepCopyInto(out *ModelContextWindow) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelContextWindow.
+func (in *ModelContextWindow) DeepCopy() *ModelContextWindow {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelContextWindow)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelList) DeepCopyInto(out *ModelList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Model, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelList.
+func (in *ModelList) DeepCopy() *ModelList {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ModelList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelParameters) DeepCopyInto(out *ModelParameters) {
+	*out = *in
+	if in.Temperature != nil {
+		in, out := &in.Temperature, &out.Temperature
+		*out = new(string)
+		**out = **in
+	}
+	if in.TopP != nil {
+		in, out := &in.TopP, &out.TopP
+		*out = new(string)
+		**out = **in
+	}
+	if in.MaxTokens != nil {
+		in, out := &in.MaxTokens, &out.MaxTokens
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FrequencyPenalty != nil {
+		in, out := &in.FrequencyPenalty, &out.FrequencyPenalty
+		*out = new(string)
+		**out = **in
+	}
+	if in.PresencePenalty != nil {
+		in, out := &in.PresencePenalty, &out.PresencePenalty
+		*out = new(string)
+		**out = **in
+	}
+	if in.Stop != nil {
+		in, out := &in.Stop, &out.Stop
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Seed != nil {
+		in, out := &in.Seed, &out.Seed
+		*out = new(int64)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelParameters.
+func (in *ModelParameters) DeepCopy() *ModelParameters {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelParameters)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelPricing) DeepCopyInto(out *ModelPricing) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelPricing.
+func (in *ModelPricing) DeepCopy() *ModelPricing {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelPricing)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+	*out = *in
+	in.Model.DeepCopyInto(&out.Model)
+	in.Config.DeepCopyInto(&out.Config)
+	if in.PollInterval != nil {
+		in, out := &in.PollInterval, &out.PollInterval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Capabilities != nil {
+		in, out := &in.Capabilities, &out.Capabilities
+		*out = make([]ModelCapability, len(*in))
+		copy(*out, *in)
+	}
+	if in.ContextWindow != nil {
+		in, out := &in.ContextWindow, &out.ContextWindow
+		*out = new(ModelContextWindow)
+		**out = **in
+	}
+	if in.Pricing != nil {
+		in, out := &in.Pricing, &out.Pricing
+		*out = new(ModelPricing)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
+func (in *ModelSpec) DeepCopy() *ModelSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
+func (in *ModelStatus) DeepCopy() *ModelStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ModelStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSink) DeepCopyInto(out *NotificationSink) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSink.
+func (in *NotificationSink) DeepCopy() *NotificationSink {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSink)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationSink) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSinkList) DeepCopyInto(out *NotificationSinkList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]NotificationSink, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSinkList.
+func (in *NotificationSinkList) DeepCopy() *NotificationSinkList {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSinkList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *NotificationSinkList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *NotificationSinkSpec) DeepCopyInto(out *NotificationSinkSpec) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Events != nil {
+		in, out := &in.Events, &out.Events
+		*out = make([]NotificationEvent, len(*in))
+		copy(*out, *in)
+	}
+	if in.SessionBudget != nil {
+		in, out := &in.SessionBudget, &out.SessionBudget
+		*out = new(SessionBudget)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new NotificationSinkSpec.
+func (in *NotificationSinkSpec) DeepCopy() *NotificationSinkSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(NotificationSinkSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *OpenAIModelConfig) DeepCopyInto(out *OpenAIModelConfig) {
+	*out = *in
+	in.BaseURL.DeepCopyInto(&out.BaseURL)
+	in.APIKey.DeepCopyInto(&out.APIKey)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Properties != nil {
+		in, out := &in.Properties, &out.Properties
+		*out = make(map[string]ValueSource, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAIModelConfig.
+func (in *OpenAIModelConfig) DeepCopy() *OpenAIModelConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(OpenAIModelConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Parameter) DeepCopyInto(out *Parameter) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(ValueFromSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
+func (in *Parameter) DeepCopy() *Parameter {
+	if in == nil {
+		return nil
+	}
+	out := new(Parameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Pipeline) DeepCopyInto(out *Pipeline) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Pipeline.
+func (in *Pipeline) DeepCopy() *Pipeline {
+	if in == nil {
+		return nil
+	}
+	out := new(Pipeline)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Pipeline) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineApproval) DeepCopyInto(out *PipelineApproval) {
+	*out = *in
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineApproval.
+func (in *PipelineApproval) DeepCopy() *PipelineApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineList) DeepCopyInto(out *PipelineList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Pipeline, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineList.
+func (in *PipelineList) DeepCopy() *PipelineList {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *PipelineList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineSpec) DeepCopyInto(out *PipelineSpec) {
+	*out = *in
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineSpec.
+func (in *PipelineSpec) DeepCopy() *PipelineSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStatus) DeepCopyInto(out *PipelineStatus) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]PipelineStepStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.TokenUsage = in.TokenUsage
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStatus.
+func (in *PipelineStatus) DeepCopy() *PipelineStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStep) DeepCopyInto(out *PipelineStep) {
+	*out = *in
+	if in.Query != nil {
+		in, out := &in.Query, &out.Query
+		*out = new(QuerySpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Approval != nil {
+		in, out := &in.Approval, &out.Approval
+		*out = new(PipelineApproval)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStep.
+func (in *PipelineStep) DeepCopy() *PipelineStep {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineStepStatus) DeepCopyInto(out *PipelineStepStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineStepStatus.
+func (in *PipelineStepStatus) DeepCopy() *PipelineStepStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineStepStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostProcessor) DeepCopyInto(out *PostProcessor) {
+	*out = *in
+	if in.Tool != nil {
+		in, out := &in.Tool, &out.Tool
+		*out = new(PostProcessorToolRef)
+		**out = **in
+	}
+	if in.Webhook != nil {
+		in, out := &in.Webhook, &out.Webhook
+		*out = new(PostProcessorWebhook)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostProcessor.
+func (in *PostProcessor) DeepCopy() *PostProcessor {
+	if in == nil {
+		return nil
+	}
+	out := new(PostProcessor)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostProcessorToolRef) DeepCopyInto(out *PostProcessorToolRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostProcessorToolRef.
+func (in *PostProcessorToolRef) DeepCopy() *PostProcessorToolRef {
+	if in == nil {
+		return nil
+	}
+	out := new(PostProcessorToolRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PostProcessorWebhook) DeepCopyInto(out *PostProcessorWebhook) {
+	*out = *in
+	in.URL.DeepCopyInto(&out.URL)
+	if in.Headers != nil {
+		in, out := &in.Headers, &out.Headers
+		*out = make([]Header, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PostProcessorWebhook.
+func (in *PostProcessorWebhook) DeepCopy() *PostProcessorWebhook {
+	if in == nil {
+		return nil
+	}
+	out := new(PostProcessorWebhook)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Query) DeepCopyInto(out *Query) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Query.
+func (in *Query) DeepCopy() *Query {
+	if in == nil {
+		return nil
+	}
+	out := new(Query)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Query) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryBasedEvaluationConfig) DeepCopyInto(out *QueryBasedEvaluationConfig) {
+	*out = *in
+	if in.QueryRef != nil {
+		in, out := &in.QueryRef, &out.QueryRef
+		*out = new(QueryRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryBasedEvaluationConfig.
+func (in *QueryBasedEvaluationConfig) DeepCopy() *QueryBasedEvaluationConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryBasedEvaluationConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryCheckpoint) DeepCopyInto(out *QueryCheckpoint) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]QueryTargetProgress, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.TokenUsage = in.TokenUsage
+	in.UpdatedAt.DeepCopyInto(&out.UpdatedAt)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryCheckpoint.
+func (in *QueryCheckpoint) DeepCopy() *QueryCheckpoint {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryCheckpoint)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryContextMessage) DeepCopyInto(out *QueryContextMessage) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryContextMessage.
+func (in *QueryContextMessage) DeepCopy() *QueryContextMessage {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryContextMessage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryImpersonation) DeepCopyInto(out *QueryImpersonation) {
+	*out = *in
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryImpersonation.
+func (in *QueryImpersonation) DeepCopy() *QueryImpersonation {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryImpersonation)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryInputPart) DeepCopyInto(out *QueryInputPart) {
+	*out = *in
+	if in.ValueFrom != nil {
+		in, out := &in.ValueFrom, &out.ValueFrom
+		*out = new(QueryInputPartSource)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryInputPart.
+func (in *QueryInputPart) DeepCopy() *QueryInputPart {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryInputPart)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryInputPartSource) DeepCopyInto(out *QueryInputPartSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryInputPartSource.
+func (in *QueryInputPartSource) DeepCopy() *QueryInputPartSource {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryInputPartSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryInputSource) DeepCopyInto(out *QueryInputSource) {
+	*out = *in
+	if in.ConfigMapKeyRef != nil {
+		in, out := &in.ConfigMapKeyRef, &out.ConfigMapKeyRef
+		*out = new(corev1.ConfigMapKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SecretKeyRef != nil {
+		in, out := &in.SecretKeyRef, &out.SecretKeyRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryInputSource.
+func (in *QueryInputSource) DeepCopy() *QueryInputSource {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryInputSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryList) DeepCopyInto(out *QueryList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Query, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryList.
+func (in *QueryList) DeepCopy() *QueryList {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryParameterReference) DeepCopyInto(out *QueryParameterReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryParameterReference.
+func (in *QueryParameterReference) DeepCopy() *QueryParameterReference {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryParameterReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryPlan) DeepCopyInto(out *QueryPlan) {
+	*out = *in
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]QueryPlanTarget, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryPlan.
+func (in *QueryPlan) DeepCopy() *QueryPlan {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryPlan)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryPlanTarget) DeepCopyInto(out *QueryPlanTarget) {
+	*out = *in
+	out.Target = in.Target
+	if in.Tools != nil {
+		in, out := &in.Tools, &out.Tools
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryPlanTarget.
+func (in *QueryPlanTarget) DeepCopy() *QueryPlanTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryPlanTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRef) DeepCopyInto(out *QueryRef) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRef.
+func (in *QueryRef) DeepCopy() *QueryRef {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRef)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryReplay) DeepCopyInto(out *QueryReplay) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryReplay.
+func (in *QueryReplay) DeepCopy() *QueryReplay {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryReplay)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRetentionPolicy) DeepCopyInto(out *QueryRetentionPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRetentionPolicy.
+func (in *QueryRetentionPolicy) DeepCopy() *QueryRetentionPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRetentionPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryRetentionPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRetentionPolicyList) DeepCopyInto(out *QueryRetentionPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QueryRetentionPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRetentionPolicyList.
+func (in *QueryRetentionPolicyList) DeepCopy() *QueryRetentionPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRetentionPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryRetentionPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRetentionPolicySpec) DeepCopyInto(out *QueryRetentionPolicySpec) {
+	*out = *in
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.KeepLastCompleted != nil {
+		in, out := &in.KeepLastCompleted, &out.KeepLastCompleted
+		*out = new(int32)
+		**out = **in
+	}
+	if in.ErrorRetention != nil {
+		in, out := &in.ErrorRetention, &out.ErrorRetention
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRetentionPolicySpec.
+func (in *QueryRetentionPolicySpec) DeepCopy() *QueryRetentionPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRetentionPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryRetentionPolicyStatus) DeepCopyInto(out *QueryRetentionPolicyStatus) {
+	*out = *in
+	if in.LastRunTime != nil {
+		in, out := &in.LastRunTime, &out.LastRunTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRetentionPolicyStatus.
+func (in *QueryRetentionPolicyStatus) DeepCopy() *QueryRetentionPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryRetentionPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuerySelector) DeepCopyInto(out *QuerySelector) {
+	*out = *in
+	if in.MatchLabels != nil {
+		in, out := &in.MatchLabels, &out.MatchLabels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.MatchExpressions != nil {
+		in, out := &in.MatchExpressions, &out.MatchExpressions
+		*out = make([]v1.LabelSelectorRequirement, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySelector.
+func (in *QuerySelector) DeepCopy() *QuerySelector {
+	if in == nil {
+		return nil
+	}
+	out := new(QuerySelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuerySLO) DeepCopyInto(out *QuerySLO) {
+	*out = *in
+	if in.MaxDuration != nil {
+		in, out := &in.MaxDuration, &out.MaxDuration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySLO.
+func (in *QuerySLO) DeepCopy() *QuerySLO {
+	if in == nil {
+		return nil
+	}
+	out := new(QuerySLO)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
+	*out = *in
+	in.Input.DeepCopyInto(&out.Input)
+	if in.InputFrom != nil {
+		in, out := &in.InputFrom, &out.InputFrom
+		*out = new(QueryInputSource)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]Parameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ParameterSchema != nil {
+		in, out := &in.ParameterSchema, &out.ParameterSchema
+		*out = make([]QueryTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]QueryTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(TargetSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemoryRef)
+		**out = **in
+	}
+	if in.Impersonate != nil {
+		in, out := &in.Impersonate, &out.Impersonate
+		*out = new(QueryImpersonation)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TTL != nil {
+		in, out := &in.TTL, &out.TTL
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.TTLAfterCompletion != nil {
+		in, out := &in.TTLAfterCompletion, &out.TTLAfterCompletion
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(QuerySLO)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Guardrails != nil {
+		in, out := &in.Guardrails, &out.Guardrails
+		*out = make([]GuardrailRef, len(*in))
+		copy(*out, *in)
+	}
+	if in.Parts != nil {
+		in, out := &in.Parts, &out.Parts
+		*out = make([]QueryInputPart, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.ContextMessages != nil {
+		in, out := &in.ContextMessages, &out.ContextMessages
+		*out = make([]QueryContextMessage, len(*in))
+		copy(*out, *in)
+	}
+	if in.ModelParameters != nil {
+		in, out := &in.ModelParameters, &out.ModelParameters
+		*out = new(ModelParameters)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Replay != nil {
+		in, out := &in.Replay, &out.Replay
+		*out = new(QueryReplay)
+		**out = **in
+	}
+	if in.PostProcessors != nil {
+		in, out := &in.PostProcessors, &out.PostProcessors
+		*out = make([]PostProcessor, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.A2ARequirements != nil {
+		in, out := &in.A2ARequirements, &out.A2ARequirements
+		*out = new(A2ARequirements)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySpec.
+func (in *QuerySpec) DeepCopy() *QuerySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QuerySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Responses != nil {
+		in, out := &in.Responses, &out.Responses
+		*out = make([]Response, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	out.TokenUsage = in.TokenUsage
+	if in.TokenUsageDetails != nil {
+		in, out := &in.TokenUsageDetails, &out.TokenUsageDetails
+		*out = make([]TokenUsageDetail, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.ResolvedTargets != nil {
+		in, out := &in.ResolvedTargets, &out.ResolvedTargets
+		*out = new(ResolvedTargets)
+		**out = **in
+	}
+	if in.Checkpoint != nil {
+		in, out := &in.Checkpoint, &out.Checkpoint
+		*out = new(QueryCheckpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Plan != nil {
+		in, out := &in.Plan, &out.Plan
+		*out = new(QueryPlan)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Recording != nil {
+		in, out := &in.Recording, &out.Recording
+		*out = new(ResponseArtifact)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatus.
+func (in *QueryStatus) DeepCopy() *QueryStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTarget) DeepCopyInto(out *QueryTarget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTarget.
+func (in *QueryTarget) DeepCopy() *QueryTarget {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTarget)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTargetProgress) DeepCopyInto(out *QueryTargetProgress) {
+	*out = *in
+	in.Target.DeepCopyInto(&out.Target)
+	if in.Response != nil {
+		in, out := &in.Response, &out.Response
+		*out = new(Response)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTargetProgress.
+func (in *QueryTargetProgress) DeepCopy() *QueryTargetProgress {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTargetProgress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTemplate) DeepCopyInto(out *QueryTemplate) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTemplate.
+func (in *QueryTemplate) DeepCopy() *QueryTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryTemplate) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTemplateList) DeepCopyInto(out *QueryTemplateList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]QueryTemplate, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTemplateList.
+func (in *QueryTemplateList) DeepCopy() *QueryTemplateList {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTemplateList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *QueryTemplateList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTemplateParameter) DeepCopyInto(out *QueryTemplateParameter) {
+	*out = *in
+	if in.Enum != nil {
+		in, out := &in.Enum, &out.Enum
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTemplateParameter.
+func (in *QueryTemplateParameter) DeepCopy() *QueryTemplateParameter {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTemplateParameter)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *QueryTemplateSpec) DeepCopyInto(out *QueryTemplateSpec) {
+	*out = *in
+	in.Input.DeepCopyInto(&out.Input)
+	if in.Parameters != nil {
+		in, out := &in.Parameters, &out.Parameters
+		*out = make([]QueryTemplateParameter, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Targets != nil {
+		in, out := &in.Targets, &out.Targets
+		*out = make([]QueryTarget, len(*in))
+		copy(*out, *in)
+	}
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = new(TargetSelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		*out = new(MemoryRef)
+		**out = **in
+	}
+	if in.Timeout != nil {
+		in, out := &in.Timeout, &out.Timeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.SLO != nil {
+		in, out := &in.SLO, &out.SLO
+		*out = new(QuerySLO)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Evaluators != nil {
+		in, out := &in.Evaluators, &out.Evaluators
+		*out = make([]EvaluationEvaluatorRef, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTemplateSpec.
+func (in *QueryTemplateSpec) DeepCopy() *QueryTemplateSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(QueryTemplateSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferenceGrant) DeepCopyInto(out *ReferenceGrant) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceGrant.
+func (in *ReferenceGrant) DeepCopy() *ReferenceGrant {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferenceGrant)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReferenceGrant) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferenceGrantFrom) DeepCopyInto(out *ReferenceGrantFrom) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceGrantFrom.
+func (in *ReferenceGrantFrom) DeepCopy() *ReferenceGrantFrom {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferenceGrantFrom)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReferenceGrantList) DeepCopyInto(out *ReferenceGrantList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ReferenceGrant, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceGrantList.
+func (in *ReferenceGrantList) DeepCopy() *ReferenceGrantList {
 	if in == nil {
 		return nil
 	}
-	out := new(MemorySpec)
+	out := new(ReferenceGrantList)
 	in.DeepCopyInto(out)
 	return out
 }
 
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ReferenceGrantList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *MemoryStatus) DeepCopyInto(out *MemoryStatus) {
+func (in *ReferenceGrantSpec) DeepCopyInto(out *ReferenceGrantSpec) {
 	*out = *in
-	if in.LastResolvedAddress != nil {
-		in, out := &in.LastResolvedAddress, &out.LastResolvedAddress
-		*out = new(string)
-		**out = **in
+	if in.From != nil {
+		in, out := &in.From, &out.From
+		*out = make([]ReferenceGrantFrom, len(*in))
+		copy(*out, *in)
+	}
+	if in.To != nil {
+		in, out := &in.To, &out.To
+		*out = make([]ReferenceGrantTo, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemoryStatus.
-func (in *MemoryStatus) DeepCopy() *MemoryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceGrantSpec.
+func (in *ReferenceGrantSpec) DeepCopy() *ReferenceGrantSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(MemoryStatus)
+	out := new(ReferenceGrantSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Model) DeepCopyInto(out *Model) {
+func (in *ReferenceGrantTo) DeepCopyInto(out *ReferenceGrantTo) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReferenceGrantTo.
+func (in *ReferenceGrantTo) DeepCopy() *ReferenceGrantTo {
+	if in == nil {
+		return nil
+	}
+	out := new(ReferenceGrantTo)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RemoteCluster) DeepCopyInto(out *RemoteCluster) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
@@ -1114,18 +3536,18 @@ func (in *Model) DeepCopyInto(out *Model) {
 	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Model.
-func (in *Model) DeepCopy() *Model {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteCluster.
+func (in *RemoteCluster) DeepCopy() *RemoteCluster {
 	if in == nil {
 		return nil
 	}
-	out := new(Model)
+	out := new(RemoteCluster)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Model) DeepCopyObject() runtime.Object {
+func (in *RemoteCluster) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1133,61 +3555,52 @@ func (in *Model) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelConfig) DeepCopyInto(out *ModelConfig) {
+func (in *RemoteClusterEndpoint) DeepCopyInto(out *RemoteClusterEndpoint) {
 	*out = *in
-	if in.OpenAI != nil {
-		in, out := &in.OpenAI, &out.OpenAI
-		*out = new(OpenAIModelConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Azure != nil {
-		in, out := &in.Azure, &out.Azure
-		*out = new(AzureModelConfig)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Bedrock != nil {
-		in, out := &in.Bedrock, &out.Bedrock
-		*out = new(BedrockModelConfig)
+	in.TokenSecretRef.DeepCopyInto(&out.TokenSecretRef)
+	if in.CASecretRef != nil {
+		in, out := &in.CASecretRef, &out.CASecretRef
+		*out = new(corev1.SecretKeySelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelConfig.
-func (in *ModelConfig) DeepCopy() *ModelConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterEndpoint.
+func (in *RemoteClusterEndpoint) DeepCopy() *RemoteClusterEndpoint {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelConfig)
+	out := new(RemoteClusterEndpoint)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelList) DeepCopyInto(out *ModelList) {
+func (in *RemoteClusterList) DeepCopyInto(out *RemoteClusterList) {
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ListMeta.DeepCopyInto(&out.ListMeta)
 	if in.Items != nil {
 		in, out := &in.Items, &out.Items
-		*out = make([]Model, len(*in))
+		*out = make([]RemoteCluster, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelList.
-func (in *ModelList) DeepCopy() *ModelList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterList.
+func (in *RemoteClusterList) DeepCopy() *RemoteClusterList {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelList)
+	out := new(RemoteClusterList)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *ModelList) DeepCopyObject() runtime.Object {
+func (in *RemoteClusterList) DeepCopyObject() runtime.Object {
 	if c := in.DeepCopy(); c != nil {
 		return c
 	}
@@ -1195,10 +3608,23 @@ func (in *ModelList) DeepCopyObject() runtime.Object {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
+func (in *RemoteClusterSpec) DeepCopyInto(out *RemoteClusterSpec) {
 	*out = *in
-	in.Model.DeepCopyInto(&out.Model)
-	in.Config.DeepCopyInto(&out.Config)
+	if in.KubeconfigSecretRef != nil {
+		in, out := &in.KubeconfigSecretRef, &out.KubeconfigSecretRef
+		*out = new(corev1.SecretKeySelector)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Endpoint != nil {
+		in, out := &in.Endpoint, &out.Endpoint
+		*out = new(RemoteClusterEndpoint)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.AllowedNamespaces != nil {
+		in, out := &in.AllowedNamespaces, &out.AllowedNamespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
 	if in.PollInterval != nil {
 		in, out := &in.PollInterval, &out.PollInterval
 		*out = new(v1.Duration)
@@ -1206,18 +3632,18 @@ func (in *ModelSpec) DeepCopyInto(out *ModelSpec) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelSpec.
-func (in *ModelSpec) DeepCopy() *ModelSpec {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterSpec.
+func (in *RemoteClusterSpec) DeepCopy() *RemoteClusterSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelSpec)
+	out := new(RemoteClusterSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
+func (in *RemoteClusterStatus) DeepCopyInto(out *RemoteClusterStatus) {
 	*out = *in
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
@@ -1228,354 +3654,283 @@ func (in *ModelStatus) DeepCopyInto(out *ModelStatus) {
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ModelStatus.
-func (in *ModelStatus) DeepCopy() *ModelStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RemoteClusterStatus.
+func (in *RemoteClusterStatus) DeepCopy() *RemoteClusterStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(ModelStatus)
+	out := new(RemoteClusterStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *OpenAIModelConfig) DeepCopyInto(out *OpenAIModelConfig) {
+func (in *ResolvedTargets) DeepCopyInto(out *ResolvedTargets) {
 	*out = *in
-	in.BaseURL.DeepCopyInto(&out.BaseURL)
-	in.APIKey.DeepCopyInto(&out.APIKey)
-	if in.Headers != nil {
-		in, out := &in.Headers, &out.Headers
-		*out = make([]Header, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Properties != nil {
-		in, out := &in.Properties, &out.Properties
-		*out = make(map[string]ValueSource, len(*in))
-		for key, val := range *in {
-			(*out)[key] = *val.DeepCopy()
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new OpenAIModelConfig.
-func (in *OpenAIModelConfig) DeepCopy() *OpenAIModelConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResolvedTargets.
+func (in *ResolvedTargets) DeepCopy() *ResolvedTargets {
 	if in == nil {
 		return nil
 	}
-	out := new(OpenAIModelConfig)
+	out := new(ResolvedTargets)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Parameter) DeepCopyInto(out *Parameter) {
+func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
 	*out = *in
-	if in.ValueFrom != nil {
-		in, out := &in.ValueFrom, &out.ValueFrom
-		*out = new(ValueFromSource)
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.NamespaceSelector != nil {
+		in, out := &in.NamespaceSelector, &out.NamespaceSelector
+		*out = new(v1.LabelSelector)
 		(*in).DeepCopyInto(*out)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Parameter.
-func (in *Parameter) DeepCopy() *Parameter {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
+func (in *ResourceSelector) DeepCopy() *ResourceSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(Parameter)
+	out := new(ResourceSelector)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Query) DeepCopyInto(out *Query) {
+func (in *Response) DeepCopyInto(out *Response) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	in.Spec.DeepCopyInto(&out.Spec)
-	in.Status.DeepCopyInto(&out.Status)
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Query.
-func (in *Query) DeepCopy() *Query {
-	if in == nil {
-		return nil
+	out.Target = in.Target
+	if in.Artifact != nil {
+		in, out := &in.Artifact, &out.Artifact
+		*out = new(ResponseArtifact)
+		**out = **in
 	}
-	out := new(Query)
-	in.DeepCopyInto(out)
-	return out
-}
-
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *Query) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
+	if in.Provenance != nil {
+		in, out := &in.Provenance, &out.Provenance
+		*out = make([]ResponseProvenance, len(*in))
+		copy(*out, *in)
 	}
-	return nil
-}
-
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryBasedEvaluationConfig) DeepCopyInto(out *QueryBasedEvaluationConfig) {
-	*out = *in
-	if in.QueryRef != nil {
-		in, out := &in.QueryRef, &out.QueryRef
-		*out = new(QueryRef)
-		**out = **in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]TeamMember, len(*in))
+		copy(*out, *in)
 	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryBasedEvaluationConfig.
-func (in *QueryBasedEvaluationConfig) DeepCopy() *QueryBasedEvaluationConfig {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Response.
+func (in *Response) DeepCopy() *Response {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryBasedEvaluationConfig)
+	out := new(Response)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryList) DeepCopyInto(out *QueryList) {
+func (in *ResponseArtifact) DeepCopyInto(out *ResponseArtifact) {
 	*out = *in
-	out.TypeMeta = in.TypeMeta
-	in.ListMeta.DeepCopyInto(&out.ListMeta)
-	if in.Items != nil {
-		in, out := &in.Items, &out.Items
-		*out = make([]Query, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryList.
-func (in *QueryList) DeepCopy() *QueryList {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseArtifact.
+func (in *ResponseArtifact) DeepCopy() *ResponseArtifact {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryList)
+	out := new(ResponseArtifact)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
-func (in *QueryList) DeepCopyObject() runtime.Object {
-	if c := in.DeepCopy(); c != nil {
-		return c
-	}
-	return nil
-}
-
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryParameterReference) DeepCopyInto(out *QueryParameterReference) {
+func (in *ResponseProvenance) DeepCopyInto(out *ResponseProvenance) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryParameterReference.
-func (in *QueryParameterReference) DeepCopy() *QueryParameterReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResponseProvenance.
+func (in *ResponseProvenance) DeepCopy() *ResponseProvenance {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryParameterReference)
+	out := new(ResponseProvenance)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryRef) DeepCopyInto(out *QueryRef) {
+func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
 	*out = *in
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryRef.
-func (in *QueryRef) DeepCopy() *QueryRef {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
+func (in *ServiceReference) DeepCopy() *ServiceReference {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryRef)
+	out := new(ServiceReference)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QuerySelector) DeepCopyInto(out *QuerySelector) {
+func (in *Session) DeepCopyInto(out *Session) {
 	*out = *in
-	if in.MatchLabels != nil {
-		in, out := &in.MatchLabels, &out.MatchLabels
-		*out = make(map[string]string, len(*in))
-		for key, val := range *in {
-			(*out)[key] = val
-		}
-	}
-	if in.MatchExpressions != nil {
-		in, out := &in.MatchExpressions, &out.MatchExpressions
-		*out = make([]v1.LabelSelectorRequirement, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySelector.
-func (in *QuerySelector) DeepCopy() *QuerySelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Session.
+func (in *Session) DeepCopy() *Session {
 	if in == nil {
 		return nil
 	}
-	out := new(QuerySelector)
+	out := new(Session)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QuerySpec) DeepCopyInto(out *QuerySpec) {
-	*out = *in
-	in.Input.DeepCopyInto(&out.Input)
-	if in.Parameters != nil {
-		in, out := &in.Parameters, &out.Parameters
-		*out = make([]Parameter, len(*in))
-		for i := range *in {
-			(*in)[i].DeepCopyInto(&(*out)[i])
-		}
-	}
-	if in.Targets != nil {
-		in, out := &in.Targets, &out.Targets
-		*out = make([]QueryTarget, len(*in))
-		copy(*out, *in)
-	}
-	if in.Selector != nil {
-		in, out := &in.Selector, &out.Selector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
-	if in.Memory != nil {
-		in, out := &in.Memory, &out.Memory
-		*out = new(MemoryRef)
-		**out = **in
-	}
-	if in.TTL != nil {
-		in, out := &in.TTL, &out.TTL
-		*out = new(v1.Duration)
-		**out = **in
-	}
-	if in.Timeout != nil {
-		in, out := &in.Timeout, &out.Timeout
-		*out = new(v1.Duration)
-		**out = **in
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *Session) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
+	return nil
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QuerySpec.
-func (in *QuerySpec) DeepCopy() *QuerySpec {
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionBudget) DeepCopyInto(out *SessionBudget) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionBudget.
+func (in *SessionBudget) DeepCopy() *SessionBudget {
 	if in == nil {
 		return nil
 	}
-	out := new(QuerySpec)
+	out := new(SessionBudget)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryStatus) DeepCopyInto(out *QueryStatus) {
+func (in *SessionList) DeepCopyInto(out *SessionList) {
 	*out = *in
-	if in.Conditions != nil {
-		in, out := &in.Conditions, &out.Conditions
-		*out = make([]v1.Condition, len(*in))
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]Session, len(*in))
 		for i := range *in {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
-	if in.Responses != nil {
-		in, out := &in.Responses, &out.Responses
-		*out = make([]Response, len(*in))
-		copy(*out, *in)
-	}
-	out.TokenUsage = in.TokenUsage
-	if in.Duration != nil {
-		in, out := &in.Duration, &out.Duration
-		*out = new(v1.Duration)
-		**out = **in
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryStatus.
-func (in *QueryStatus) DeepCopy() *QueryStatus {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionList.
+func (in *SessionList) DeepCopy() *SessionList {
 	if in == nil {
 		return nil
 	}
-	out := new(QueryStatus)
+	out := new(SessionList)
 	in.DeepCopyInto(out)
 	return out
 }
 
-// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *QueryTarget) DeepCopyInto(out *QueryTarget) {
-	*out = *in
-}
-
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new QueryTarget.
-func (in *QueryTarget) DeepCopy() *QueryTarget {
-	if in == nil {
-		return nil
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *SessionList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
 	}
-	out := new(QueryTarget)
-	in.DeepCopyInto(out)
-	return out
+	return nil
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ResourceSelector) DeepCopyInto(out *ResourceSelector) {
+func (in *SessionSpec) DeepCopyInto(out *SessionSpec) {
 	*out = *in
-	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
-	if in.Namespaces != nil {
-		in, out := &in.Namespaces, &out.Namespaces
-		*out = make([]string, len(*in))
-		copy(*out, *in)
-	}
-	if in.NamespaceSelector != nil {
-		in, out := &in.NamespaceSelector, &out.NamespaceSelector
-		*out = new(v1.LabelSelector)
-		(*in).DeepCopyInto(*out)
-	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ResourceSelector.
-func (in *ResourceSelector) DeepCopy() *ResourceSelector {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionSpec.
+func (in *SessionSpec) DeepCopy() *SessionSpec {
 	if in == nil {
 		return nil
 	}
-	out := new(ResourceSelector)
+	out := new(SessionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *Response) DeepCopyInto(out *Response) {
+func (in *SessionStatus) DeepCopyInto(out *SessionStatus) {
 	*out = *in
-	out.Target = in.Target
+	out.TokenUsage = in.TokenUsage
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.LastQueryTime != nil {
+		in, out := &in.LastQueryTime, &out.LastQueryTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Memories != nil {
+		in, out := &in.Memories, &out.Memories
+		*out = make([]MemoryRef, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Response.
-func (in *Response) DeepCopy() *Response {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionStatus.
+func (in *SessionStatus) DeepCopy() *SessionStatus {
 	if in == nil {
 		return nil
 	}
-	out := new(Response)
+	out := new(SessionStatus)
 	in.DeepCopyInto(out)
 	return out
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *ServiceReference) DeepCopyInto(out *ServiceReference) {
+func (in *TargetSelector) DeepCopyInto(out *TargetSelector) {
 	*out = *in
+	in.LabelSelector.DeepCopyInto(&out.LabelSelector)
+	if in.Kinds != nil {
+		in, out := &in.Kinds, &out.Kinds
+		*out = make([]TargetKind, len(*in))
+		copy(*out, *in)
+	}
+	if in.Namespaces != nil {
+		in, out := &in.Namespaces, &out.Namespaces
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]QueryTarget, len(*in))
+		copy(*out, *in)
+	}
 }
 
-// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceReference.
-func (in *ServiceReference) DeepCopy() *ServiceReference {
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TargetSelector.
+func (in *TargetSelector) DeepCopy() *TargetSelector {
 	if in == nil {
 		return nil
 	}
-	out := new(ServiceReference)
+	out := new(TargetSelector)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -1689,6 +4044,38 @@ func (in *TeamMember) DeepCopy() *TeamMember {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamMemberSelector) DeepCopyInto(out *TeamMemberSelector) {
+	*out = *in
+	in.Selector.DeepCopyInto(&out.Selector)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamMemberSelector.
+func (in *TeamMemberSelector) DeepCopy() *TeamMemberSelector {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamMemberSelector)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamModeratorSpec) DeepCopyInto(out *TeamModeratorSpec) {
+	*out = *in
+	out.ModelRef = in.ModelRef
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamModeratorSpec.
+func (in *TeamModeratorSpec) DeepCopy() *TeamModeratorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamModeratorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamSelectorSpec) DeepCopyInto(out *TeamSelectorSpec) {
 	*out = *in
@@ -1712,6 +4099,13 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = make([]TeamMember, len(*in))
 		copy(*out, *in)
 	}
+	if in.MemberSelectors != nil {
+		in, out := &in.MemberSelectors, &out.MemberSelectors
+		*out = make([]TeamMemberSelector, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	if in.MaxTurns != nil {
 		in, out := &in.MaxTurns, &out.MaxTurns
 		*out = new(int)
@@ -1727,6 +4121,16 @@ func (in *TeamSpec) DeepCopyInto(out *TeamSpec) {
 		*out = new(TeamGraphSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Termination != nil {
+		in, out := &in.Termination, &out.Termination
+		*out = new(TeamTerminationSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Moderator != nil {
+		in, out := &in.Moderator, &out.Moderator
+		*out = new(TeamModeratorSpec)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamSpec.
@@ -1739,6 +4143,31 @@ func (in *TeamSpec) DeepCopy() *TeamSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TeamTerminationSpec) DeepCopyInto(out *TeamTerminationSpec) {
+	*out = *in
+	if in.Keywords != nil {
+		in, out := &in.Keywords, &out.Keywords
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TeamTerminationSpec.
+func (in *TeamTerminationSpec) DeepCopy() *TeamTerminationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(TeamTerminationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TeamStatus) DeepCopyInto(out *TeamStatus) {
 	*out = *in
@@ -1769,6 +4198,22 @@ func (in *TokenUsage) DeepCopy() *TokenUsage {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *TokenUsageDetail) DeepCopyInto(out *TokenUsageDetail) {
+	*out = *in
+	out.TokenUsage = in.TokenUsage
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new TokenUsageDetail.
+func (in *TokenUsageDetail) DeepCopy() *TokenUsageDetail {
+	if in == nil {
+		return nil
+	}
+	out := new(TokenUsageDetail)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Tool) DeepCopyInto(out *Tool) {
 	*out = *in
@@ -1806,6 +4251,99 @@ func (in *ToolAnnotations) DeepCopy() *ToolAnnotations {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApproval) DeepCopyInto(out *ToolApproval) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.Spec = in.Spec
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApproval.
+func (in *ToolApproval) DeepCopy() *ToolApproval {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApproval)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToolApproval) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApprovalList) DeepCopyInto(out *ToolApprovalList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]ToolApproval, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApprovalList.
+func (in *ToolApprovalList) DeepCopy() *ToolApprovalList {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApprovalList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *ToolApprovalList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApprovalSpec) DeepCopyInto(out *ToolApprovalSpec) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApprovalSpec.
+func (in *ToolApprovalSpec) DeepCopy() *ToolApprovalSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApprovalSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolApprovalStatus) DeepCopyInto(out *ToolApprovalStatus) {
+	*out = *in
+	if in.DecisionTime != nil {
+		in, out := &in.DecisionTime, &out.DecisionTime
+		*out = (*in).DeepCopy()
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolApprovalStatus.
+func (in *ToolApprovalStatus) DeepCopy() *ToolApprovalStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolApprovalStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolFunction) DeepCopyInto(out *ToolFunction) {
 	*out = *in
@@ -1853,6 +4391,26 @@ func (in *ToolList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolOutputPolicy) DeepCopyInto(out *ToolOutputPolicy) {
+	*out = *in
+	if in.SummarizeWithModelRef != nil {
+		in, out := &in.SummarizeWithModelRef, &out.SummarizeWithModelRef
+		*out = new(AgentModelRef)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolOutputPolicy.
+func (in *ToolOutputPolicy) DeepCopy() *ToolOutputPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolOutputPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ToolPartial) DeepCopyInto(out *ToolPartial) {
 	*out = *in
@@ -1873,6 +4431,31 @@ func (in *ToolPartial) DeepCopy() *ToolPartial {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ToolSecurity) DeepCopyInto(out *ToolSecurity) {
+	*out = *in
+	if in.AllowedHosts != nil {
+		in, out := &in.AllowedHosts, &out.AllowedHosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.AllowedMethods != nil {
+		in, out := &in.AllowedMethods, &out.AllowedMethods
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSecurity.
+func (in *ToolSecurity) DeepCopy() *ToolSecurity {
+	if in == nil {
+		return nil
+	}
+	out := new(ToolSecurity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ToolSpec.
 func (in *ToolSpec) DeepCopy() *ToolSpec {
 	if in == nil {
@@ -1921,6 +4504,16 @@ func (in *ValueFromSource) DeepCopyInto(out *ValueFromSource) {
 		*out = new(QueryParameterReference)
 		**out = **in
 	}
+	if in.QueryFieldRef != nil {
+		in, out := &in.QueryFieldRef, &out.QueryFieldRef
+		*out = new(FieldSelector)
+		**out = **in
+	}
+	if in.ResourceFieldRef != nil {
+		in, out := &in.ResourceFieldRef, &out.ResourceFieldRef
+		*out = new(FieldSelector)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValueFromSource.
@@ -6,6 +6,30 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// OAuth2ClientCredentials configures the OAuth2 client credentials flow
+type OAuth2ClientCredentials struct {
+	// Token endpoint of the authorization server
+	// +kubebuilder:validation:Required
+	// +kubebuilder:validation:Pattern="^https?://.*"
+	TokenURL string `json:"tokenURL"`
+	// Client ID used to request tokens
+	// +kubebuilder:validation:Required
+	ClientID ValueSource `json:"clientID"`
+	// Client secret used to request tokens
+	// +kubebuilder:validation:Required
+	ClientSecret ValueSource `json:"clientSecret"`
+	// Scopes requested for the access token
+	// +kubebuilder:validation:Optional
+	Scopes []string `json:"scopes,omitempty"`
+}
+
+// A2AServerAuth configures authentication for requests to the A2A server
+type A2AServerAuth struct {
+	// OAuth2 client credentials. Tokens are cached and refreshed automatically.
+	// +kubebuilder:validation:Optional
+	OAuth2 *OAuth2ClientCredentials `json:"oauth2,omitempty"`
+}
+
 type A2AServerSpec struct {
 	// Address specifies how to reach the A2A server
 	// +kubebuilder:validation:Required
@@ -15,6 +39,10 @@ type A2AServerSpec struct {
 	// +kubebuilder:validation:Optional
 	Headers []Header `json:"headers,omitempty"`
 
+	// Auth configures token-based authentication, added as an Authorization header
+	// +kubebuilder:validation:Optional
+	Auth *A2AServerAuth `json:"auth,omitempty"`
+
 	// Description of the A2A server
 	// +kubebuilder:validation:Optional
 	Description string `json:"description,omitempty"`
@@ -24,11 +52,35 @@ type A2AServerSpec struct {
 	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
 }
 
+// A2AServerCapabilities are the optional protocol features declared by the agent card
+type A2AServerCapabilities struct {
+	// Streaming reports whether the agent streams responses
+	// +kubebuilder:validation:Optional
+	Streaming bool `json:"streaming,omitempty"`
+
+	// PushNotifications reports whether the agent sends push notifications
+	// +kubebuilder:validation:Optional
+	PushNotifications bool `json:"pushNotifications,omitempty"`
+}
+
 type A2AServerStatus struct {
 	// LastResolvedAddress contains the last resolved address value
 	// +kubebuilder:validation:Optional
 	LastResolvedAddress string `json:"lastResolvedAddress,omitempty"`
 
+	// ProtocolVersion is the A2A protocol version declared by the discovered agent card. For servers
+	// exposing several agents, the first agent card is recorded
+	// +kubebuilder:validation:Optional
+	ProtocolVersion string `json:"protocolVersion,omitempty"`
+
+	// Capabilities declared by the discovered agent card
+	// +kubebuilder:validation:Optional
+	Capabilities *A2AServerCapabilities `json:"capabilities,omitempty"`
+
+	// AuthSchemes are the types of the security schemes declared by the discovered agent card
+	// +kubebuilder:validation:Optional
+	AuthSchemes []string `json:"authSchemes,omitempty"`
+
 	// Conditions represent the latest available observations of the A2A server's state
 	// +kubebuilder:validation:Optional
 	Conditions []metav1.Condition `json:"conditions,omitempty"`
@@ -39,6 +91,7 @@ type A2AServerStatus struct {
 // +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status",description="Ready status"
 // +kubebuilder:printcolumn:name="Discovering",type="string",JSONPath=".status.conditions[?(@.type=='Discovering')].status",description="Discovery status"
 // +kubebuilder:printcolumn:name="Address",type="string",JSONPath=".status.lastResolvedAddress",description="Last resolved address"
+// +kubebuilder:printcolumn:name="Protocol",type="string",JSONPath=".status.protocolVersion",description="A2A protocol version"
 // +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Age"
 type A2AServer struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -20,22 +20,73 @@ type ExecutionEngineSpec struct {
 	// +kubebuilder:validation:Required
 	Address ValueSource `json:"address"`
 
+	// Protocol used to call the execution engine. Only http is currently supported.
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:validation:Enum=http
+	// +kubebuilder:default=http
+	Protocol string `json:"protocol,omitempty"`
+
+	// Headers sent with every request to the execution engine
+	// +kubebuilder:validation:Optional
+	Headers []Header `json:"headers,omitempty"`
+
+	// Capabilities supported by the execution engine. When empty, all capabilities are assumed.
+	// +kubebuilder:validation:Optional
+	Capabilities []ExecutionEngineCapability `json:"capabilities,omitempty"`
+
 	// Description provides human-readable information about this execution engine
 	Description string `json:"description,omitempty"`
+
+	// PollInterval is how often the execution engine health is checked
+	// +kubebuilder:validation:Optional
+	// +kubebuilder:default="1m"
+	PollInterval *metav1.Duration `json:"pollInterval,omitempty"`
 }
 
+// ExecutionEngineCapability is a feature an execution engine can support
+// +kubebuilder:validation:Enum=tools;structuredOutput
+type ExecutionEngineCapability string
+
+const (
+	// ExecutionEngineCapabilityTools indicates the engine can call tools sent with the request
+	ExecutionEngineCapabilityTools ExecutionEngineCapability = "tools"
+	// ExecutionEngineCapabilityStructuredOutput indicates the engine honours the agent output schema
+	ExecutionEngineCapabilityStructuredOutput ExecutionEngineCapability = "structuredOutput"
+)
+
+// ExecutionEngineAvailable indicates whether the execution engine passed its last health check
+const ExecutionEngineAvailable = "Available"
+
 type ExecutionEngineStatus struct {
 	// +kubebuilder:validation:Optional
 	// LastResolvedAddress contains the actual resolved address value
 	LastResolvedAddress string `json:"lastResolvedAddress,omitempty"`
 	Phase               string `json:"phase,omitempty"`
 	Message             string `json:"message,omitempty"`
+	// Conditions represent the latest available observations of the execution engine's state
+	// +kubebuilder:validation:Optional
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// HasCapability reports whether the execution engine supports capability.
+// Engines that do not declare capabilities are assumed to support all of them.
+func (s *ExecutionEngineSpec) HasCapability(capability ExecutionEngineCapability) bool {
+	if len(s.Capabilities) == 0 {
+		return true
+	}
+	for _, c := range s.Capabilities {
+		if c == capability {
+			return true
+		}
+	}
+	return false
 }
 
 // +kubebuilder:object:root=true
 // +kubebuilder:subresource:status
 // +kubebuilder:printcolumn:name="Type",type=string,JSONPath=`.spec.type`
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.conditions[?(@.type=="Available")].status`
 // +kubebuilder:printcolumn:name="Address",type=string,JSONPath=`.status.lastResolvedAddress`
 // +kubebuilder:printcolumn:name="Age",type=date,JSONPath=`.metadata.creationTimestamp`
 
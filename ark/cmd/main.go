@@ -3,22 +3,29 @@
 package main
 
 import (
+	"context"
 	"crypto/tls"
 	"flag"
 	"fmt"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
 	// to ensure that exec-entrypoint and run can make use of them.
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 	"k8s.io/client-go/tools/record"
 
+	coordinationv1 "k8s.io/api/coordination/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/certwatcher"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/metrics/filters"
@@ -27,7 +34,12 @@ import (
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	arkconfig "mckinsey.com/ark/internal/config"
 	"mckinsey.com/ark/internal/controller"
+	"mckinsey.com/ark/internal/embeddings"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/judge"
+	"mckinsey.com/ark/internal/kubeauth"
 	telemetryconfig "mckinsey.com/ark/internal/telemetry/config"
 	webhookv1 "mckinsey.com/ark/internal/webhook/v1"
 	webhookv1prealpha1 "mckinsey.com/ark/internal/webhook/v1prealpha1"
@@ -60,6 +72,12 @@ type config struct {
 	probeAddr                                        string
 	secureMetrics                                    bool
 	enableHTTP2                                      bool
+	queryExecutor                                    bool
+	judgeEvaluator                                   bool
+	judgeAddr                                        string
+	embeddingsAddr                                   string
+	validateModelConnectivity                        bool
+	configFile                                       string
 }
 
 func main() {
@@ -73,6 +91,9 @@ func main() {
 
 	setupLog.Info("starting ark controller", "version", Version, "commit", GitCommit)
 
+	// The controller config is applied before any component reads its settings
+	configWatcher := loadControllerConfig(result.configFile)
+
 	// Initialize telemetry provider
 	telemetryProvider := telemetryconfig.NewProvider()
 	defer func() {
@@ -80,13 +101,53 @@ func main() {
 			setupLog.Error(err, "failed to shutdown telemetry provider")
 		}
 	}()
+	configWatcher.OnReload(func() error {
+		settings, err := telemetryconfig.TraceSettingsFromEnv()
+		if err != nil {
+			return err
+		}
+		telemetryProvider.SetTraceSettings(settings)
+		return nil
+	})
 
 	mgr, metricsCertWatcher, webhookCertWatcher := setupManager(result.config)
-	setupControllers(mgr, telemetryProvider)
-	setupWebhooks(mgr)
+	if result.queryExecutor {
+		setupQueryExecutor(mgr, telemetryProvider, configWatcher)
+	} else if result.judgeEvaluator {
+		setupJudgeEvaluator(mgr, telemetryProvider, result.judgeAddr)
+	} else {
+		setupControllers(mgr, telemetryProvider, configWatcher)
+		setupWebhooks(mgr, result.config)
+		setupEmbeddings(mgr, telemetryProvider, result.embeddingsAddr)
+	}
+	if configWatcher != nil {
+		if err := mgr.Add(configWatcher); err != nil {
+			setupLog.Error(err, "unable to add controller config watcher to manager")
+			os.Exit(1)
+		}
+	}
 	startManager(mgr, metricsCertWatcher, webhookCertWatcher)
 }
 
+// loadControllerConfig applies the controller config file, if any, and returns the watcher
+// reloading it. The watcher is nil without a config file.
+func loadControllerConfig(path string) *arkconfig.Watcher {
+	if path == "" {
+		return nil
+	}
+	controllerConfig, err := arkconfig.Load(path)
+	if err != nil {
+		setupLog.Error(err, "unable to load controller config", "path", path)
+		os.Exit(1)
+	}
+	if err := arkconfig.Apply(controllerConfig); err != nil {
+		setupLog.Error(err, "unable to apply controller config", "path", path)
+		os.Exit(1)
+	}
+	setupLog.Info("loaded controller config", "path", path)
+	return arkconfig.NewWatcher(path, controllerConfig)
+}
+
 func parseFlags() struct {
 	config
 	zapOpts     zap.Options
@@ -111,6 +172,19 @@ func parseFlags() struct {
 	flag.StringVar(&cfg.metricsCertKey, "metrics-cert-key", "tls.key", "The name of the metrics server key file.")
 	flag.BoolVar(&cfg.enableHTTP2, "enable-http2", false,
 		"If set, HTTP/2 will be enabled for the metrics and webhook servers")
+	flag.BoolVar(&cfg.queryExecutor, "query-executor", false,
+		"Run as a query executor, executing running queries claimed by this replica instead of "+
+			"reconciling resources. Executors do not use leader election.")
+	flag.BoolVar(&cfg.judgeEvaluator, "judge-evaluator", false,
+		"Run as the LLM-as-judge evaluator service instead of reconciling resources.")
+	flag.StringVar(&cfg.judgeAddr, "judge-bind-address", ":8000", "The address the judge evaluator binds to.")
+	flag.StringVar(&cfg.embeddingsAddr, "embeddings-bind-address", "0",
+		"The address the embeddings endpoint of embedding models binds to, or 0 to disable it.")
+	flag.BoolVar(&cfg.validateModelConnectivity, "validate-model-connectivity", false,
+		"Reject models whose endpoint does not answer a one token completion when they are created or updated.")
+	flag.StringVar(&cfg.configFile, "config", "",
+		"Path of a ControllerConfig file. Its settings override the environment and the reloadable "+
+			"ones are applied when the file changes.")
 	flag.BoolVar(&showVersion, "version", false, "Show version information and exit")
 
 	zapOpts := zap.Options{Development: true}
@@ -142,7 +216,21 @@ func setupManager(cfg config) (ctrl.Manager, *certwatcher.CertWatcher, *certwatc
 		}),
 	}
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), managerOptions)
+	if cfg.judgeEvaluator {
+		managerOptions.LeaderElection = false
+	}
+
+	if cfg.queryExecutor {
+		// Executors claim queries with leases, which are read from the API server rather than
+		// cached to avoid watching every lease in the cluster
+		managerOptions.LeaderElection = false
+		managerOptions.Client = client.Options{Cache: &client.CacheOptions{DisableFor: []client.Object{&coordinationv1.Lease{}}}}
+	}
+
+	restConfig := ctrl.GetConfigOrDie()
+	managerOptions.Cache = namespaceCacheOptions(restConfig)
+
+	mgr, err := ctrl.NewManager(restConfig, managerOptions)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -151,6 +239,32 @@ func setupManager(cfg config) (ctrl.Manager, *certwatcher.CertWatcher, *certwatc
 	return mgr, metricsCertWatcher, webhookCertWatcher
 }
 
+// namespaceCacheOptions restricts the cache of the manager to the namespaces the controllers
+// watch, set with ARK_WATCH_NAMESPACES, ARK_EXCLUDE_NAMESPACES and ARK_NAMESPACE_SELECTOR
+func namespaceCacheOptions(restConfig *rest.Config) cache.Options {
+	filter, err := arkconfig.NamespaceFilterFromEnv()
+	if err != nil {
+		setupLog.Error(err, "invalid namespace filter")
+		os.Exit(1)
+	}
+	if filter.IsZero() {
+		return cache.Options{}
+	}
+
+	reader, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		os.Exit(1)
+	}
+	options, err := filter.CacheOptions(context.Background(), reader)
+	if err != nil {
+		setupLog.Error(err, "unable to resolve watched namespaces")
+		os.Exit(1)
+	}
+	setupLog.Info("watching namespaces", "namespaces", slices.Sorted(maps.Keys(options.DefaultNamespaces)), "excluded", filter.Exclude)
+	return options
+}
+
 func setupTLS(enableHTTP2 bool) []func(*tls.Config) {
 	var tlsOpts []func(*tls.Config)
 
@@ -225,18 +339,125 @@ func setupMetricsServer(cfg config, baseTLSOpts []func(*tls.Config)) (metricsser
 	return metricsServerOptions, metricsCertWatcher
 }
 
-func setupControllers(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider) {
+func newQueryReconciler(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider, configWatcher *arkconfig.Watcher, recorderName string) *controller.QueryReconciler {
+	auditSink, err := genai.NewAuditSinkFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to configure query audit sink")
+		os.Exit(1)
+	}
+
+	responseArtifacts, err := genai.NewResponseArtifactsFromEnv(mgr.GetClient(), mgr.GetScheme())
+	if err != nil {
+		setupLog.Error(err, "unable to configure response artifact store")
+		os.Exit(1)
+	}
+
+	eventSettings, err := genai.EventSettingsFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to configure event settings")
+		os.Exit(1)
+	}
+
+	execution, err := controller.QueryExecutionModeFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to configure query execution")
+		os.Exit(1)
+	}
+
+	watchdog, err := controller.QueryWatchdogFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to configure query watchdog")
+		os.Exit(1)
+	}
+
+	reconciler := &controller.QueryReconciler{
+		Client:    mgr.GetClient(),
+		Scheme:    mgr.GetScheme(),
+		Recorder:  mgr.GetEventRecorderFor(recorderName),
+		Telemetry: telemetryProvider,
+		AuditSink: auditSink,
+		Artifacts: responseArtifacts,
+		Events:    eventSettings,
+		Execution: execution,
+		Watchdog:  watchdog,
+		Notifier:  genai.NewNotifier(mgr.GetClient(), mgr.GetEventRecorderFor("notification")),
+	}
+	configWatcher.OnReload(func() error {
+		settings, err := genai.EventSettingsFromEnv()
+		if err != nil {
+			return err
+		}
+		reconciler.SetEventSettings(settings)
+		return nil
+	})
+	return reconciler
+}
+
+// setupQueryExecutor runs only the query executor, see controller.QueryExecutor
+func setupQueryExecutor(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider, configWatcher *arkconfig.Watcher) {
+	executor, err := controller.NewQueryExecutorFromEnv(newQueryReconciler(mgr, telemetryProvider, configWatcher, "query-executor"))
+	if err != nil {
+		setupLog.Error(err, "unable to configure query executor")
+		os.Exit(1)
+	}
+	executor.APIReader = mgr.GetAPIReader()
+
+	setupLog.Info("running as query executor", "identity", executor.Identity, "maxQueries", executor.MaxQueries)
+	if err := executor.SetupWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create controller", "controller", "QueryExecutor")
+		os.Exit(1)
+	}
+}
+
+func setupJudgeEvaluator(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider, addr string) {
+	server := &judge.Server{
+		Judge: &judge.Judge{
+			Client:        mgr.GetClient(),
+			ModelRecorder: telemetryProvider.ModelRecorder(),
+			Authorizer:    &kubeauth.Authorizer{Client: mgr.GetClient()},
+		},
+		Addr: addr,
+	}
+
+	setupLog.Info("running as judge evaluator", "address", addr)
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add judge evaluator to manager")
+		os.Exit(1)
+	}
+}
+
+// setupEmbeddings serves the embeddings of embedding models unless addr is 0
+func setupEmbeddings(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider, addr string) {
+	if addr == "0" {
+		return
+	}
+	server := &embeddings.Server{
+		Client:        mgr.GetClient(),
+		ModelRecorder: telemetryProvider.ModelRecorder(),
+		Authorizer:    &kubeauth.Authorizer{Client: mgr.GetClient()},
+		Addr:          addr,
+	}
+	if err := mgr.Add(server); err != nil {
+		setupLog.Error(err, "unable to add embeddings endpoint to manager")
+		os.Exit(1)
+	}
+}
+
+func setupControllers(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provider, configWatcher *arkconfig.Watcher) {
+	evaluatorClient, err := genai.NewEvaluatorClientFromEnv()
+	if err != nil {
+		setupLog.Error(err, "unable to configure evaluator client")
+		os.Exit(1)
+	}
+	configWatcher.OnReload(evaluatorClient.ConfigureFromEnv)
+	notifier := genai.NewNotifier(mgr.GetClient(), mgr.GetEventRecorderFor("notification"))
+
 	controllers := []struct {
 		name       string
 		reconciler interface{ SetupWithManager(ctrl.Manager) error }
 	}{
 		{"Agent", &controller.AgentReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("agent-controller")}},
-		{"Query", &controller.QueryReconciler{
-			Client:    mgr.GetClient(),
-			Scheme:    mgr.GetScheme(),
-			Recorder:  mgr.GetEventRecorderFor("query-controller"),
-			Telemetry: telemetryProvider,
-		}},
+		{"Query", newQueryReconciler(mgr, telemetryProvider, configWatcher, "query-controller")},
 		{"Tool", &controller.ToolReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}},
 		{"Team", &controller.TeamReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}},
 		{"A2AServer", &controller.A2AServerReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("a2aserver-controller")}},
@@ -250,7 +471,24 @@ func setupControllers(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provi
 		{"Memory", &controller.MemoryReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("memory-controller")}},
 		{"ExecutionEngine", &controller.ExecutionEngineReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("executionengine-controller")}},
 		{"Evaluator", &controller.EvaluatorReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}},
-		{"Evaluation", &controller.EvaluationReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("evaluation-controller")}},
+		{"Session", &controller.SessionReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Notifier: notifier}},
+		{"Pipeline", &controller.PipelineReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("pipeline-controller")}},
+		{"RemoteCluster", &controller.RemoteClusterReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("remotecluster-controller")}},
+		{"QueryRetentionPolicy", &controller.QueryRetentionPolicyReconciler{Client: mgr.GetClient(), Scheme: mgr.GetScheme(), Recorder: mgr.GetEventRecorderFor("queryretentionpolicy-controller")}},
+		{"KnowledgeBase", &controller.KnowledgeBaseReconciler{
+			Client:    mgr.GetClient(),
+			Scheme:    mgr.GetScheme(),
+			Recorder:  mgr.GetEventRecorderFor("knowledgebase-controller"),
+			Telemetry: telemetryProvider,
+		}},
+		{"Evaluation", &controller.EvaluationReconciler{
+			Client:     mgr.GetClient(),
+			Scheme:     mgr.GetScheme(),
+			Recorder:   mgr.GetEventRecorderFor("evaluation-controller"),
+			Evaluators: evaluatorClient,
+			Notifier:   notifier,
+			Telemetry:  telemetryProvider,
+		}},
 	}
 
 	for _, reconciler := range controllers {
@@ -261,7 +499,7 @@ func setupControllers(mgr ctrl.Manager, telemetryProvider *telemetryconfig.Provi
 	}
 }
 
-func setupWebhooks(mgr ctrl.Manager) {
+func setupWebhooks(mgr ctrl.Manager, cfg config) {
 	if os.Getenv("ENABLE_WEBHOOKS") == "false" {
 		return
 	}
@@ -274,10 +512,13 @@ func setupWebhooks(mgr ctrl.Manager) {
 		{"Agent", webhookv1.SetupAgentWebhookWithManager},
 		{"Query", webhookv1.SetupQueryWebhookWithManager},
 		{"Tool", webhookv1.SetupToolWebhookWithManager},
-		{"Model", webhookv1.SetupModelWebhookWithManager},
+		{"Model", func(mgr ctrl.Manager) error {
+			return webhookv1.SetupModelWebhookWithManager(mgr, cfg.validateModelConnectivity)
+		}},
 		{"MCPServer", webhookv1.SetupMCPServerWebhookWithManager},
 		{"Evaluator", webhookv1.SetupEvaluatorWebhookWithManager},
 		{"Evaluation", webhookv1.SetupEvaluationWebhookWithManager},
+		{"Guardrail", webhookv1.SetupGuardrailWebhookWithManager},
 		{"A2AServer", webhookv1prealpha1.SetupA2AServerWebhookWithManager},
 		{"ExecutionEngine", webhookv1prealpha1.SetupExecutionEngineWebhookWithManager},
 	}
@@ -61,3 +61,96 @@ app.kubernetes.io/instance: {{ .Release.Name }}
     $hasValidating = true }}{{- end }}
 {{- end }}
 {{ $hasValidating }}}}{{- end }}
+
+
+{{- define "chart.namespaceSelector" -}}
+{{- $pairs := list }}
+{{- range $key, $value := .Values.namespaces.selector }}
+{{- $pairs = append $pairs (printf "%s=%s" $key $value) }}
+{{- end }}
+{{- join "," $pairs }}
+{{- end }}
+
+
+{{- define "chart.namespaceEnv" -}}
+{{- with .Values.namespaces.watch }}
+- name: ARK_WATCH_NAMESPACES
+  value: {{ join "," . | quote }}
+{{- end }}
+{{- with .Values.namespaces.exclude }}
+- name: ARK_EXCLUDE_NAMESPACES
+  value: {{ join "," . | quote }}
+{{- end }}
+{{- with include "chart.namespaceSelector" . }}
+- name: ARK_NAMESPACE_SELECTOR
+  value: {{ . | quote }}
+{{- end }}
+{{- end }}
+
+
+{{- define "chart.telemetryEnv" -}}
+{{- with .Values.telemetry }}
+{{- with .environment }}
+- name: ARK_TELEMETRY_ENVIRONMENT
+  value: {{ . | quote }}
+{{- end }}
+{{- if .langfuse.host }}
+- name: ARK_LANGFUSE_HOST
+  value: {{ .langfuse.host | quote }}
+- name: ARK_LANGFUSE_PUBLIC_KEY
+  valueFrom:
+    secretKeyRef:
+      name: {{ required "telemetry.langfuse.secretName is required with telemetry.langfuse.host" .langfuse.secretName }}
+      key: public-key
+- name: ARK_LANGFUSE_SECRET_KEY
+  valueFrom:
+    secretKeyRef:
+      name: {{ required "telemetry.langfuse.secretName is required with telemetry.langfuse.host" .langfuse.secretName }}
+      key: secret-key
+{{- end }}
+{{- if .phoenix.endpoint }}
+- name: ARK_PHOENIX_ENDPOINT
+  value: {{ .phoenix.endpoint | quote }}
+{{- with .phoenix.project }}
+- name: ARK_PHOENIX_PROJECT
+  value: {{ . | quote }}
+{{- end }}
+{{- with .phoenix.secretName }}
+- name: ARK_PHOENIX_API_KEY
+  valueFrom:
+    secretKeyRef:
+      name: {{ . }}
+      key: api-key
+      optional: true
+{{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
+
+
+{{- define "chart.webhookNamespaceSelector" -}}
+{{- with .Values.namespaces }}
+{{- if or .watch .exclude .selector }}
+namespaceSelector:
+  {{- with .selector }}
+  matchLabels:
+    {{- toYaml . | nindent 4 }}
+  {{- end }}
+  {{- if or .watch .exclude }}
+  matchExpressions:
+    {{- with .watch }}
+    - key: kubernetes.io/metadata.name
+      operator: In
+      values:
+        {{- toYaml . | nindent 8 }}
+    {{- end }}
+    {{- with .exclude }}
+    - key: kubernetes.io/metadata.name
+      operator: NotIn
+      values:
+        {{- toYaml . | nindent 8 }}
+    {{- end }}
+  {{- end }}
+{{- end }}
+{{- end }}
+{{- end }}
@@ -43,11 +43,93 @@ const (
 const (
 	Finalizer            = ARKPrefix + "finalizer"
 	TriggeredFrom        = ARKPrefix + "triggered-from"
+	Batch                = ARKPrefix + "batch"
+	Benchmark            = ARKPrefix + "benchmark"
 	LocalhostGatewayPort = ARKPrefix + "localhost-gateway-port"
 )
 
+// Failure record annotations
+const (
+	// FailureRecord names the ConfigMap holding the context of a failed Query
+	FailureRecord = ARKPrefix + "failure-record"
+	// ReplayOf is the Query a replayed Query was created from
+	ReplayOf = ARKPrefix + "replay-of"
+)
+
+// Audit annotations
+const (
+	// CreatedBy is the user that created a Query, set by the Query admission webhook
+	CreatedBy = ARKPrefix + "created-by"
+	// RequestedBy is the user a proxy such as fark server created a Query on behalf of
+	RequestedBy = ARKPrefix + "requested-by"
+)
+
 // Streaming annotations
 const (
 	StreamingEnabled = ARKPrefix + "streaming-enabled"
 	StreamingURL     = ARKPrefix + "streaming-url"
 )
+
+// Tool security annotations (set on Namespace)
+const (
+	ToolAllowedHosts     = ARKPrefix + "tool-allowed-hosts"
+	ToolAllowedMethods   = ARKPrefix + "tool-allowed-methods"
+	ToolMaxResponseBytes = ARKPrefix + "tool-max-response-bytes"
+)
+
+// Query input annotations (set on Namespace)
+const (
+	// QueryInputAllowedHosts lists the hosts inputFrom URLs of the queries of a namespace can be
+	// fetched from, with "*.domain" wildcards
+	QueryInputAllowedHosts = ARKPrefix + "query-input-allowed-hosts"
+)
+
+// Tool job labels
+const (
+	// Tool is the Tool a Job was created for
+	Tool = ARKPrefix + "tool"
+	// QueryUID is the UID of the Query a Job was created for
+	QueryUID = ARKPrefix + "query-uid"
+	// Sandbox marks the pods of tool jobs that are isolated from the network
+	Sandbox = ARKPrefix + "sandbox"
+)
+
+// Pipeline annotations
+const (
+	// Pipeline is the Pipeline a step Query was created by
+	Pipeline = ARKPrefix + "pipeline"
+	// PipelineStep is the step a Query was created for
+	PipelineStep = ARKPrefix + "pipeline-step"
+)
+
+// Approval annotations
+const (
+	// Approve approves the Pipeline step it names, or the ToolApproval it is set on
+	Approve = ARKPrefix + "approve"
+	// Reject rejects the Pipeline step it names, or the ToolApproval it is set on with the value as reason
+	Reject = ARKPrefix + "reject"
+)
+
+// Remote cluster annotations
+const (
+	// RemoteQuerySource is the namespace and name of the Query a remote cluster target was run for
+	RemoteQuerySource = ARKPrefix + "remote-query-source"
+)
+
+// Idempotency labels
+const (
+	// IdempotencyKey is the hash of the idempotency key of a Query, set by the Query admission webhook
+	IdempotencyKey = ARKPrefix + "idempotency-key"
+)
+
+// Query template annotations
+const (
+	// QueryTemplate is the QueryTemplate a Query was created from
+	QueryTemplate = ARKPrefix + "query-template"
+)
+
+// Telemetry annotations
+const (
+	// TelemetryEnvironment is the environment the spans of a Query are tagged with, such as prod or staging
+	TelemetryEnvironment = ARKPrefix + "telemetry-environment"
+)
@@ -0,0 +1,84 @@
+/* Copyright 2025. McKinsey & Company */
+
+package common
+
+import (
+	"fmt"
+	"regexp"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	FieldPathName        = "metadata.name"
+	FieldPathNamespace   = "metadata.namespace"
+	FieldPathUID         = "metadata.uid"
+	FieldPathSessionID   = "spec.sessionId"
+	fieldPathAnnotations = "annotations"
+)
+
+// Matches metadata.labels['<key>'] and metadata.annotations['<key>']
+var fieldPathKeyPattern = regexp.MustCompile(`^metadata\.(labels|annotations)\['([^']+)'\]$`)
+
+// ValidateResourceFieldPath checks the field path of a resourceFieldRef
+func ValidateResourceFieldPath(fieldPath string) error {
+	switch {
+	case fieldPath == FieldPathName, fieldPath == FieldPathNamespace, fieldPathKeyPattern.MatchString(fieldPath):
+		return nil
+	}
+	return fmt.Errorf("unsupported field path '%s': supported are %s, %s, metadata.labels['<key>'] and metadata.annotations['<key>']",
+		fieldPath, FieldPathName, FieldPathNamespace)
+}
+
+// ValidateQueryFieldPath checks the field path of a queryFieldRef
+func ValidateQueryFieldPath(fieldPath string) error {
+	if fieldPath == FieldPathUID || fieldPath == FieldPathSessionID || ValidateResourceFieldPath(fieldPath) == nil {
+		return nil
+	}
+	return fmt.Errorf("unsupported field path '%s': supported are %s, %s, %s, %s, metadata.labels['<key>'] and metadata.annotations['<key>']",
+		fieldPath, FieldPathName, FieldPathNamespace, FieldPathUID, FieldPathSessionID)
+}
+
+// ResolveResourceFieldPath returns a metadata field of a resource. Missing labels and annotations
+// resolve to an empty string, like in the Kubernetes downward API.
+func ResolveResourceFieldPath(obj metav1.Object, fieldPath string) (string, error) {
+	if err := ValidateResourceFieldPath(fieldPath); err != nil {
+		return "", err
+	}
+	return resolveMetadataFieldPath(obj, fieldPath), nil
+}
+
+// ResolveQueryFieldPath returns a field of a query. spec.sessionId defaults to the query UID, which
+// is the session of queries without one.
+func ResolveQueryFieldPath(query *arkv1alpha1.Query, fieldPath string) (string, error) {
+	if err := ValidateQueryFieldPath(fieldPath); err != nil {
+		return "", err
+	}
+
+	switch fieldPath {
+	case FieldPathUID:
+		return string(query.UID), nil
+	case FieldPathSessionID:
+		if query.Spec.SessionId != "" {
+			return query.Spec.SessionId, nil
+		}
+		return string(query.UID), nil
+	}
+	return resolveMetadataFieldPath(query, fieldPath), nil
+}
+
+func resolveMetadataFieldPath(obj metav1.Object, fieldPath string) string {
+	switch fieldPath {
+	case FieldPathName:
+		return obj.GetName()
+	case FieldPathNamespace:
+		return obj.GetNamespace()
+	}
+
+	match := fieldPathKeyPattern.FindStringSubmatch(fieldPath)
+	if match[1] == fieldPathAnnotations {
+		return obj.GetAnnotations()[match[2]]
+	}
+	return obj.GetLabels()[match[2]]
+}
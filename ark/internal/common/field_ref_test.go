@@ -0,0 +1,60 @@
+/* Copyright 2025. McKinsey & Company */
+
+package common
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestResolveQueryFieldPath(t *testing.T) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "weather",
+			Namespace:   "team-a",
+			UID:         "5c1d",
+			Labels:      map[string]string{"app": "chat"},
+			Annotations: map[string]string{"ark.mckinsey.com/user": "jo"},
+		},
+	}
+
+	tests := []struct {
+		fieldPath string
+		want      string
+		wantErr   bool
+	}{
+		{fieldPath: "metadata.name", want: "weather"},
+		{fieldPath: "metadata.namespace", want: "team-a"},
+		{fieldPath: "metadata.uid", want: "5c1d"},
+		{fieldPath: "metadata.labels['app']", want: "chat"},
+		{fieldPath: "metadata.annotations['ark.mckinsey.com/user']", want: "jo"},
+		{fieldPath: "metadata.labels['missing']", want: ""},
+		{fieldPath: "spec.sessionId", want: "5c1d"},
+		{fieldPath: "spec.input", wantErr: true},
+		{fieldPath: "metadata.labels[app]", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fieldPath, func(t *testing.T) {
+			got, err := ResolveQueryFieldPath(query, tt.fieldPath)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResolveQueryFieldPath() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ResolveQueryFieldPath() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	query.Spec.SessionId = "chat-7"
+	if got, _ := ResolveQueryFieldPath(query, "spec.sessionId"); got != "chat-7" {
+		t.Errorf("ResolveQueryFieldPath() = %q, want chat-7", got)
+	}
+
+	// Resources only expose their name, namespace, labels and annotations
+	if _, err := ResolveResourceFieldPath(query, "metadata.uid"); err == nil {
+		t.Error("ResolveResourceFieldPath() expected error for metadata.uid")
+	}
+}
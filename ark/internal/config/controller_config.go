@@ -0,0 +1,271 @@
+/* Copyright 2025. McKinsey & Company */
+
+// Package config loads the ControllerConfig file, which configures the controller declaratively
+// instead of through environment variables.
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// APIVersion is the version of the ControllerConfig file format
+	APIVersion = "config.ark.mckinsey.com/v1alpha1"
+	// Kind is the kind of the ControllerConfig file
+	Kind = "ControllerConfig"
+)
+
+// ControllerConfig configures the controller, the query executors and the judge evaluator. Each
+// setting overrides the environment variable it replaces, and unset settings fall back to it.
+type ControllerConfig struct {
+	metav1.TypeMeta `json:",inline"`
+
+	Webhooks   WebhooksConfig   `json:"webhooks,omitempty"`
+	Telemetry  TelemetryConfig  `json:"telemetry,omitempty"`
+	Events     EventsConfig     `json:"events,omitempty"`
+	Evaluators EvaluatorsConfig `json:"evaluators,omitempty"`
+	Queries    QueriesConfig    `json:"queries,omitempty"`
+	Executor   ExecutorConfig   `json:"executor,omitempty"`
+	Memory     MemoryConfig     `json:"memory,omitempty"`
+	Audit      AuditConfig      `json:"audit,omitempty"`
+	Artifacts  ArtifactsConfig  `json:"artifacts,omitempty"`
+	Namespaces NamespacesConfig `json:"namespaces,omitempty"`
+	// HTTPLogging logs the requests and responses of model, memory and tool calls
+	HTTPLogging *bool `json:"httpLogging,omitempty"`
+}
+
+type WebhooksConfig struct {
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+type TelemetryConfig struct {
+	ContentCapture   string   `json:"contentCapture,omitempty"`
+	ContentMaxLength *int     `json:"contentMaxLength,omitempty"`
+	SamplingRatio    *float64 `json:"samplingRatio,omitempty"`
+	TraceURLTemplate string   `json:"traceURLTemplate,omitempty"`
+	// Environment tags the spans, for example prod or staging
+	Environment string `json:"environment,omitempty"`
+	// Langfuse and Phoenix receive the traces next to the OTLP endpoint. Their keys are read from
+	// Secrets through the environment variables.
+	Langfuse LangfuseConfig `json:"langfuse,omitempty"`
+	Phoenix  PhoenixConfig  `json:"phoenix,omitempty"`
+}
+
+type LangfuseConfig struct {
+	Host string `json:"host,omitempty"`
+}
+
+type PhoenixConfig struct {
+	Endpoint string `json:"endpoint,omitempty"`
+	Project  string `json:"project,omitempty"`
+}
+
+type EventsConfig struct {
+	Verbosity           string `json:"verbosity,omitempty"`
+	AggregationInterval *int   `json:"aggregationInterval,omitempty"`
+}
+
+type EvaluatorsConfig struct {
+	MaxRetries       *int `json:"maxRetries,omitempty"`
+	FailureThreshold *int `json:"failureThreshold,omitempty"`
+	OpenSeconds      *int `json:"openSeconds,omitempty"`
+}
+
+type QueriesConfig struct {
+	// Execution is controller or executor
+	Execution         string `json:"execution,omitempty"`
+	StuckGraceSeconds *int   `json:"stuckGraceSeconds,omitempty"`
+	// StuckPolicy is restart or error
+	StuckPolicy string `json:"stuckPolicy,omitempty"`
+}
+
+type ExecutorConfig struct {
+	MaxQueries   *int `json:"maxQueries,omitempty"`
+	LeaseSeconds *int `json:"leaseSeconds,omitempty"`
+}
+
+type MemoryConfig struct {
+	HTTPTimeoutSeconds *int `json:"httpTimeoutSeconds,omitempty"`
+}
+
+type AuditConfig struct {
+	Sink     string `json:"sink,omitempty"`
+	FilePath string `json:"filePath,omitempty"`
+	URL      string `json:"url,omitempty"`
+}
+
+type ArtifactsConfig struct {
+	Store          string `json:"store,omitempty"`
+	ThresholdBytes *int64 `json:"thresholdBytes,omitempty"`
+	MaxStatusBytes *int64 `json:"maxStatusBytes,omitempty"`
+	URL            string `json:"url,omitempty"`
+}
+
+type NamespacesConfig struct {
+	Watch   []string `json:"watch,omitempty"`
+	Exclude []string `json:"exclude,omitempty"`
+	// Selector is a label selector of the namespaces to watch
+	Selector string `json:"selector,omitempty"`
+}
+
+// setting maps a field of the config to the environment variable it replaces. Reloadable
+// settings are read when they are used or are pushed to the components when the config is
+// reloaded, the others are only read at startup.
+type setting struct {
+	env        string
+	reloadable bool
+	value      func(*ControllerConfig) string
+}
+
+var settings = []setting{
+	{"ENABLE_WEBHOOKS", false, func(c *ControllerConfig) string { return formatBool(c.Webhooks.Enabled) }},
+	{"ENABLE_HTTP_LOGGING", true, func(c *ControllerConfig) string { return formatBool(c.HTTPLogging) }},
+	{"ARK_TELEMETRY_CONTENT_CAPTURE", true, func(c *ControllerConfig) string { return c.Telemetry.ContentCapture }},
+	{"ARK_TELEMETRY_CONTENT_MAX_LENGTH", true, func(c *ControllerConfig) string { return formatInt(c.Telemetry.ContentMaxLength) }},
+	{"ARK_TELEMETRY_SAMPLING_RATIO", true, func(c *ControllerConfig) string { return formatFloat(c.Telemetry.SamplingRatio) }},
+	{"ARK_TRACE_URL_TEMPLATE", true, func(c *ControllerConfig) string { return c.Telemetry.TraceURLTemplate }},
+	{"ARK_TELEMETRY_ENVIRONMENT", true, func(c *ControllerConfig) string { return c.Telemetry.Environment }},
+	{"ARK_LANGFUSE_HOST", false, func(c *ControllerConfig) string { return c.Telemetry.Langfuse.Host }},
+	{"ARK_PHOENIX_ENDPOINT", false, func(c *ControllerConfig) string { return c.Telemetry.Phoenix.Endpoint }},
+	{"ARK_PHOENIX_PROJECT", false, func(c *ControllerConfig) string { return c.Telemetry.Phoenix.Project }},
+	{"ARK_EVENT_VERBOSITY", true, func(c *ControllerConfig) string { return c.Events.Verbosity }},
+	{"ARK_EVENT_AGGREGATION_INTERVAL", true, func(c *ControllerConfig) string { return formatInt(c.Events.AggregationInterval) }},
+	{"ARK_EVALUATOR_MAX_RETRIES", true, func(c *ControllerConfig) string { return formatInt(c.Evaluators.MaxRetries) }},
+	{"ARK_EVALUATOR_FAILURE_THRESHOLD", true, func(c *ControllerConfig) string { return formatInt(c.Evaluators.FailureThreshold) }},
+	{"ARK_EVALUATOR_OPEN_SECONDS", true, func(c *ControllerConfig) string { return formatInt(c.Evaluators.OpenSeconds) }},
+	{"ARK_MEMORY_HTTP_TIMEOUT_SECONDS", true, func(c *ControllerConfig) string { return formatInt(c.Memory.HTTPTimeoutSeconds) }},
+	{"ARK_QUERY_EXECUTION", false, func(c *ControllerConfig) string { return c.Queries.Execution }},
+	{"ARK_QUERY_STUCK_GRACE_SECONDS", false, func(c *ControllerConfig) string { return formatInt(c.Queries.StuckGraceSeconds) }},
+	{"ARK_QUERY_STUCK_POLICY", false, func(c *ControllerConfig) string { return c.Queries.StuckPolicy }},
+	{"ARK_EXECUTOR_MAX_QUERIES", false, func(c *ControllerConfig) string { return formatInt(c.Executor.MaxQueries) }},
+	{"ARK_EXECUTOR_LEASE_SECONDS", false, func(c *ControllerConfig) string { return formatInt(c.Executor.LeaseSeconds) }},
+	{"ARK_AUDIT_SINK", false, func(c *ControllerConfig) string { return c.Audit.Sink }},
+	{"ARK_AUDIT_FILE_PATH", false, func(c *ControllerConfig) string { return c.Audit.FilePath }},
+	{"ARK_AUDIT_URL", false, func(c *ControllerConfig) string { return c.Audit.URL }},
+	{"ARK_ARTIFACT_STORE", false, func(c *ControllerConfig) string { return c.Artifacts.Store }},
+	{"ARK_ARTIFACT_THRESHOLD_BYTES", false, func(c *ControllerConfig) string { return formatInt64(c.Artifacts.ThresholdBytes) }},
+	{"ARK_ARTIFACT_MAX_STATUS_BYTES", false, func(c *ControllerConfig) string { return formatInt64(c.Artifacts.MaxStatusBytes) }},
+	{"ARK_ARTIFACT_URL", false, func(c *ControllerConfig) string { return c.Artifacts.URL }},
+	{WatchNamespacesEnv, false, func(c *ControllerConfig) string { return strings.Join(c.Namespaces.Watch, ",") }},
+	{ExcludeNamespacesEnv, false, func(c *ControllerConfig) string { return strings.Join(c.Namespaces.Exclude, ",") }},
+	{NamespaceSelectorEnv, false, func(c *ControllerConfig) string { return c.Namespaces.Selector }},
+}
+
+// Load reads the config file at path
+func Load(path string) (*ControllerConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read controller config: %w", err)
+	}
+	return Parse(data)
+}
+
+// Parse decodes a config, rejecting unknown fields and other versions of the format
+func Parse(data []byte) (*ControllerConfig, error) {
+	config := &ControllerConfig{}
+	if err := yaml.UnmarshalStrict(data, config); err != nil {
+		return nil, fmt.Errorf("invalid controller config: %w", err)
+	}
+	if config.APIVersion != APIVersion || config.Kind != Kind {
+		return nil, fmt.Errorf("unsupported controller config %s %s, expected apiVersion %s and kind %s", config.APIVersion, config.Kind, APIVersion, Kind)
+	}
+	return config, nil
+}
+
+// Env returns the environment variables of the settings set in the config
+func (c *ControllerConfig) Env() map[string]string {
+	env := map[string]string{}
+	for _, s := range settings {
+		if value := s.value(c); value != "" {
+			env[s.env] = value
+		}
+	}
+	return env
+}
+
+// RestartRequired returns the environment variables of the settings that differ between the
+// configs and are only read at startup
+func RestartRequired(previous, next *ControllerConfig) []string {
+	var changed []string
+	for _, s := range settings {
+		if !s.reloadable && s.value(previous) != s.value(next) {
+			changed = append(changed, s.env)
+		}
+	}
+	return changed
+}
+
+var (
+	containerEnvOnce sync.Once
+	// containerEnv is the environment of the container for the settings before a config was
+	// applied, nil for unset variables
+	containerEnv map[string]*string
+)
+
+// Apply exports the settings of the config as the environment variables read by the
+// components, overriding the environment of the container. Settings missing from the config
+// fall back to the environment of the container, also when they are removed on reload.
+func Apply(config *ControllerConfig) error {
+	containerEnvOnce.Do(func() {
+		containerEnv = map[string]*string{}
+		for _, s := range settings {
+			if value, ok := os.LookupEnv(s.env); ok {
+				containerEnv[s.env] = &value
+			} else {
+				containerEnv[s.env] = nil
+			}
+		}
+	})
+
+	for _, s := range settings {
+		value := s.value(config)
+		var err error
+		switch {
+		case value != "":
+			err = os.Setenv(s.env, value)
+		case containerEnv[s.env] != nil:
+			err = os.Setenv(s.env, *containerEnv[s.env])
+		default:
+			err = os.Unsetenv(s.env)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to apply %s: %w", s.env, err)
+		}
+	}
+	return nil
+}
+
+func formatBool(value *bool) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatBool(*value)
+}
+
+func formatInt(value *int) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.Itoa(*value)
+}
+
+func formatInt64(value *int64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatInt(*value, 10)
+}
+
+func formatFloat(value *float64) string {
+	if value == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*value, 'g', -1, 64)
+}
@@ -0,0 +1,118 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testConfig = `
+apiVersion: config.ark.mckinsey.com/v1alpha1
+kind: ControllerConfig
+webhooks:
+  enabled: false
+telemetry:
+  contentCapture: truncated
+  samplingRatio: 0.25
+  environment: staging
+  phoenix:
+    endpoint: http://phoenix:6006
+events:
+  verbosity: warnings
+queries:
+  stuckPolicy: error
+artifacts:
+  thresholdBytes: 1024
+  maxStatusBytes: 262144
+namespaces:
+  exclude: [kube-system, sandbox]
+`
+
+func TestParse(t *testing.T) {
+	config, err := Parse([]byte(testConfig))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"ENABLE_WEBHOOKS":               "false",
+		"ARK_TELEMETRY_CONTENT_CAPTURE": "truncated",
+		"ARK_TELEMETRY_SAMPLING_RATIO":  "0.25",
+		"ARK_TELEMETRY_ENVIRONMENT":     "staging",
+		"ARK_PHOENIX_ENDPOINT":          "http://phoenix:6006",
+		"ARK_EVENT_VERBOSITY":           "warnings",
+		"ARK_QUERY_STUCK_POLICY":        "error",
+		"ARK_ARTIFACT_THRESHOLD_BYTES":  "1024",
+		"ARK_ARTIFACT_MAX_STATUS_BYTES": "262144",
+		"ARK_EXCLUDE_NAMESPACES":        "kube-system,sandbox",
+	}, config.Env())
+
+	_, err = Parse([]byte("apiVersion: config.ark.mckinsey.com/v1alpha1\nkind: ControllerConfig\nevents:\n  verbose: true\n"))
+	assert.ErrorContains(t, err, "unknown field")
+
+	_, err = Parse([]byte("apiVersion: config.ark.mckinsey.com/v2\nkind: ControllerConfig\n"))
+	assert.ErrorContains(t, err, "unsupported controller config")
+}
+
+func TestRestartRequired(t *testing.T) {
+	previous, err := Parse([]byte(testConfig))
+	require.NoError(t, err)
+	next, err := Parse([]byte(testConfig))
+	require.NoError(t, err)
+
+	next.Events.Verbosity = "none"
+	assert.Empty(t, RestartRequired(previous, next))
+
+	next.Queries.StuckPolicy = "restart"
+	next.Audit.Sink = "file"
+	assert.Equal(t, []string{"ARK_QUERY_STUCK_POLICY", "ARK_AUDIT_SINK"}, RestartRequired(previous, next))
+}
+
+func TestApplyAndReload(t *testing.T) {
+	t.Setenv("ARK_EVENT_VERBOSITY", "aggregated")
+	t.Setenv("ARK_TELEMETRY_CONTENT_CAPTURE", "")
+	require.NoError(t, os.Unsetenv("ARK_TELEMETRY_CONTENT_CAPTURE"))
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(testConfig), 0o600))
+	config, err := Load(path)
+	require.NoError(t, err)
+	require.NoError(t, Apply(config))
+	assert.Equal(t, "warnings", os.Getenv("ARK_EVENT_VERBOSITY"))
+	assert.Equal(t, "truncated", os.Getenv("ARK_TELEMETRY_CONTENT_CAPTURE"))
+
+	var reloads int
+	watcher := NewWatcher(path, config)
+	watcher.OnReload(func() error {
+		reloads++
+		if os.Getenv("ARK_EVENT_VERBOSITY") == "invalid" {
+			return errors.New("invalid event verbosity")
+		}
+		return nil
+	})
+
+	// An unchanged file is not applied again
+	require.NoError(t, watcher.Reload())
+	assert.Equal(t, 0, reloads)
+
+	// Removed settings fall back to the environment of the container
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: config.ark.mckinsey.com/v1alpha1\nkind: ControllerConfig\n"), 0o600))
+	require.NoError(t, watcher.Reload())
+	assert.Equal(t, 1, reloads)
+	assert.Equal(t, "aggregated", os.Getenv("ARK_EVENT_VERBOSITY"))
+	_, set := os.LookupEnv("ARK_TELEMETRY_CONTENT_CAPTURE")
+	assert.False(t, set)
+
+	// A config rejected by a component is rolled back
+	require.NoError(t, os.WriteFile(path, []byte("apiVersion: config.ark.mckinsey.com/v1alpha1\nkind: ControllerConfig\nevents:\n  verbosity: invalid\n"), 0o600))
+	assert.ErrorContains(t, watcher.Reload(), "invalid event verbosity")
+	assert.Equal(t, "aggregated", os.Getenv("ARK_EVENT_VERBOSITY"))
+
+	// An unreadable config keeps the current config
+	require.NoError(t, os.WriteFile(path, []byte("kind: ["), 0o600))
+	assert.Error(t, watcher.Reload())
+	assert.Equal(t, "aggregated", os.Getenv("ARK_EVENT_VERBOSITY"))
+}
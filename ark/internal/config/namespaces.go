@@ -0,0 +1,110 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// WatchNamespacesEnv is a comma separated list of the namespaces the controllers watch
+	WatchNamespacesEnv = "ARK_WATCH_NAMESPACES"
+	// ExcludeNamespacesEnv is a comma separated list of namespaces the controllers ignore
+	ExcludeNamespacesEnv = "ARK_EXCLUDE_NAMESPACES"
+	// NamespaceSelectorEnv is a label selector of the namespaces the controllers watch
+	NamespaceSelectorEnv = "ARK_NAMESPACE_SELECTOR"
+)
+
+// NamespaceFilter restricts the namespaces the controllers watch, so ARK can be rolled out to
+// some namespaces of a cluster and only caches the resources of those namespaces
+type NamespaceFilter struct {
+	Watch    []string
+	Exclude  []string
+	Selector labels.Selector
+}
+
+// NamespaceFilterFromEnv reads the namespace filter from the environment
+func NamespaceFilterFromEnv() (NamespaceFilter, error) {
+	filter := NamespaceFilter{
+		Watch:   splitNamespaces(os.Getenv(WatchNamespacesEnv)),
+		Exclude: splitNamespaces(os.Getenv(ExcludeNamespacesEnv)),
+	}
+	if value := strings.TrimSpace(os.Getenv(NamespaceSelectorEnv)); value != "" {
+		selector, err := labels.Parse(value)
+		if err != nil {
+			return filter, fmt.Errorf("invalid %s: %w", NamespaceSelectorEnv, err)
+		}
+		filter.Selector = selector
+	}
+	return filter, nil
+}
+
+// IsZero returns whether the filter watches all namespaces
+func (f NamespaceFilter) IsZero() bool {
+	return len(f.Watch) == 0 && len(f.Exclude) == 0 && f.Selector == nil
+}
+
+// CacheOptions returns the options of a cache holding only the resources of the watched
+// namespaces. Namespaces matching the selector are listed once, so labelling a namespace takes
+// effect when the controller restarts. Cluster scoped resources are not filtered.
+func (f NamespaceFilter) CacheOptions(ctx context.Context, reader client.Reader) (cache.Options, error) {
+	namespaces := f.Watch
+	if f.Selector != nil {
+		var list corev1.NamespaceList
+		if err := reader.List(ctx, &list, client.MatchingLabelsSelector{Selector: f.Selector}); err != nil {
+			return cache.Options{}, fmt.Errorf("failed to list namespaces matching %s: %w", f.Selector, err)
+		}
+		var matching []string
+		for _, namespace := range list.Items {
+			if len(f.Watch) == 0 || slices.Contains(f.Watch, namespace.Name) {
+				matching = append(matching, namespace.Name)
+			}
+		}
+		if len(matching) == 0 {
+			return cache.Options{}, fmt.Errorf("no namespace to watch matches %s", f.Selector)
+		}
+		namespaces = matching
+	}
+
+	if len(namespaces) == 0 {
+		if len(f.Exclude) == 0 {
+			return cache.Options{}, nil
+		}
+		selectors := make([]fields.Selector, 0, len(f.Exclude))
+		for _, namespace := range f.Exclude {
+			selectors = append(selectors, fields.OneTermNotEqualSelector("metadata.namespace", namespace))
+		}
+		return cache.Options{DefaultFieldSelector: fields.AndSelectors(selectors...)}, nil
+	}
+
+	defaultNamespaces := map[string]cache.Config{}
+	for _, namespace := range namespaces {
+		if !slices.Contains(f.Exclude, namespace) {
+			defaultNamespaces[namespace] = cache.Config{}
+		}
+	}
+	if len(defaultNamespaces) == 0 {
+		return cache.Options{}, fmt.Errorf("all watched namespaces are excluded")
+	}
+	return cache.Options{DefaultNamespaces: defaultNamespaces}, nil
+}
+
+func splitNamespaces(value string) []string {
+	var namespaces []string
+	for _, namespace := range strings.Split(value, ",") {
+		if namespace = strings.TrimSpace(namespace); namespace != "" {
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
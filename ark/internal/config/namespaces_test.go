@@ -0,0 +1,83 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestNamespaceFilterFromEnv(t *testing.T) {
+	filter, err := NamespaceFilterFromEnv()
+	require.NoError(t, err)
+	assert.True(t, filter.IsZero())
+
+	t.Setenv(WatchNamespacesEnv, "team-a, team-b,")
+	t.Setenv(ExcludeNamespacesEnv, "team-b")
+	t.Setenv(NamespaceSelectorEnv, "ark.mckinsey.com/enabled=true")
+	filter, err = NamespaceFilterFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a", "team-b"}, filter.Watch)
+	assert.Equal(t, []string{"team-b"}, filter.Exclude)
+	assert.Equal(t, "ark.mckinsey.com/enabled=true", filter.Selector.String())
+
+	t.Setenv(NamespaceSelectorEnv, "enabled in (")
+	_, err = NamespaceFilterFromEnv()
+	assert.ErrorContains(t, err, "invalid ARK_NAMESPACE_SELECTOR")
+}
+
+func TestNamespaceFilterCacheOptions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	namespace := func(name string, labels map[string]string) *corev1.Namespace {
+		return &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels}}
+	}
+	enabled := map[string]string{"ark": "enabled"}
+	reader := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		namespace("team-a", enabled),
+		namespace("team-b", enabled),
+		namespace("team-c", nil),
+	).Build()
+	ctx := context.Background()
+
+	options, err := NamespaceFilter{}.CacheOptions(ctx, reader)
+	require.NoError(t, err)
+	assert.Equal(t, cache.Options{}, options)
+
+	options, err = NamespaceFilter{Watch: []string{"team-a", "team-c"}, Exclude: []string{"team-c"}}.CacheOptions(ctx, reader)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cache.Config{"team-a": {}}, options.DefaultNamespaces)
+
+	// Exclusions alone filter the watches of all namespaces
+	options, err = NamespaceFilter{Exclude: []string{"kube-system", "sandbox"}}.CacheOptions(ctx, reader)
+	require.NoError(t, err)
+	assert.Empty(t, options.DefaultNamespaces)
+	assert.Equal(t, "metadata.namespace!=kube-system,metadata.namespace!=sandbox", options.DefaultFieldSelector.String())
+
+	selected := NamespaceFilter{Selector: labels.SelectorFromSet(labels.Set{"ark": "enabled"})}
+	options, err = selected.CacheOptions(ctx, reader)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cache.Config{"team-a": {}, "team-b": {}}, options.DefaultNamespaces)
+
+	selected.Watch = []string{"team-b", "team-c"}
+	options, err = selected.CacheOptions(ctx, reader)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]cache.Config{"team-b": {}}, options.DefaultNamespaces)
+
+	// An empty set of namespaces would watch all of them, so it is rejected
+	selected.Watch = []string{"team-c"}
+	_, err = selected.CacheOptions(ctx, reader)
+	assert.ErrorContains(t, err, "no namespace to watch matches ark=enabled")
+
+	_, err = NamespaceFilter{Watch: []string{"team-a"}, Exclude: []string{"team-a"}}.CacheOptions(ctx, reader)
+	assert.ErrorContains(t, err, "all watched namespaces are excluded")
+}
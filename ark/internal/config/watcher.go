@@ -0,0 +1,105 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// DefaultReloadInterval is how often the config file is checked for changes. Mounted ConfigMaps
+// are updated by the kubelet within about a minute, so polling keeps up without watching files.
+const DefaultReloadInterval = 10 * time.Second
+
+var log = logf.Log.WithName("controller-config")
+
+// Watcher reloads the config file when it changes. The new config is applied and the reload
+// functions push the reloadable settings to the components that read them at startup. If a
+// reload function rejects the new config, the previous config is applied again.
+type Watcher struct {
+	Path     string
+	Interval time.Duration
+
+	mu       sync.Mutex
+	current  *ControllerConfig
+	onReload []func() error
+}
+
+// NewWatcher creates a watcher of the config file at path, which was loaded as current
+func NewWatcher(path string, current *ControllerConfig) *Watcher {
+	return &Watcher{Path: path, Interval: DefaultReloadInterval, current: current}
+}
+
+// OnReload registers a function reading the reloadable settings again. It does nothing on a nil
+// watcher, so components can register without a config file.
+func (w *Watcher) OnReload(reload func() error) {
+	if w == nil {
+		return
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.onReload = append(w.onReload, reload)
+}
+
+// NeedLeaderElection is false, so every replica reloads its config
+func (w *Watcher) NeedLeaderElection() bool {
+	return false
+}
+
+// Start checks the config file until ctx is done
+func (w *Watcher) Start(ctx context.Context) error {
+	ticker := time.NewTicker(w.Interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := w.Reload(); err != nil {
+				log.Error(err, "failed to reload controller config, keeping the current config", "path", w.Path)
+			}
+		}
+	}
+}
+
+// Reload loads the config file and applies it if it changed
+func (w *Watcher) Reload() error {
+	next, err := Load(w.Path)
+	if err != nil {
+		return err
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if reflect.DeepEqual(next, w.current) {
+		return nil
+	}
+	if err := w.apply(next); err != nil {
+		return errors.Join(err, w.apply(w.current))
+	}
+
+	if restart := RestartRequired(w.current, next); len(restart) > 0 {
+		log.Info("controller config changed settings that take effect after a restart", "settings", restart)
+	}
+	log.Info("reloaded controller config", "path", w.Path)
+	w.current = next
+	return nil
+}
+
+func (w *Watcher) apply(config *ControllerConfig) error {
+	if err := Apply(config); err != nil {
+		return err
+	}
+	for _, reload := range w.onReload {
+		if err := reload(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -25,6 +25,7 @@ import (
 	"mckinsey.com/ark/internal/common"
 	"mckinsey.com/ark/internal/genai"
 	"mckinsey.com/ark/internal/labels"
+	"mckinsey.com/ark/internal/telemetry/metrics"
 )
 
 const (
@@ -105,10 +106,11 @@ func (r *A2AServerReconciler) processServer(ctx context.Context, a2aServer arkv1
 
 	// Use the already resolved address from status
 	resolvedAddress := a2aServer.Status.LastResolvedAddress
-	agentCard, err := genai.DiscoverA2AAgentsWithRecorder(ctx, r.Client, resolvedAddress, a2aServer.Spec.Headers, a2aServer.Namespace, r.Recorder, &a2aServer)
+	agentCards, err := genai.DiscoverA2AAgentsWithRecorder(ctx, r.Client, resolvedAddress, a2aServer.Spec.Headers, a2aServer.Spec.Auth, a2aServer.Namespace, r.Recorder, &a2aServer)
 	if err != nil {
 		log.Error(err, "A2A agent discovery failed", "server", a2aServer.Name, "address", resolvedAddress)
 		r.Recorder.Event(&a2aServer, corev1.EventTypeWarning, "AgentDiscoveryFailed", fmt.Sprintf("Failed to discover agents from A2A server %s: %v", resolvedAddress, err))
+		metrics.IncA2ADiscoveryFailure(a2aServer.Name, a2aServer.Namespace)
 		// Don't delete agents - just mark A2AServer as not ready
 		// The agent controller will detect this and set agent phase to Pending
 		r.setCondition(&a2aServer, A2AServerReady, metav1.ConditionFalse, "DiscoveryFailed", fmt.Sprintf("Server not ready due to discovery failure: %v", err))
@@ -118,10 +120,13 @@ func (r *A2AServerReconciler) processServer(ctx context.Context, a2aServer arkv1
 		return ctrl.Result{RequeueAfter: a2aServer.Spec.PollInterval.Duration}, nil
 	}
 
+	// Agents and queries requiring a protocol version or capabilities are checked against the card
+	cardChanged := genai.RecordA2AAgentCard(&a2aServer.Status, agentCards[0])
+
 	// Set connected condition after successful discovery
-	if err := r.createAgentWithSkills(ctx, &a2aServer, agentCard); err != nil {
-		log.Error(err, "A2A agent creation failed", "server", a2aServer.Name, "agent", agentCard.Name)
-		r.Recorder.Event(&a2aServer, corev1.EventTypeWarning, "AgentCreationFailed", fmt.Sprintf("Failed to create agent %s: %v", agentCard.Name, err))
+	if err := r.createAgentsWithSkills(ctx, &a2aServer, agentCards); err != nil {
+		log.Error(err, "A2A agent creation failed", "server", a2aServer.Name)
+		r.Recorder.Event(&a2aServer, corev1.EventTypeWarning, "AgentCreationFailed", fmt.Sprintf("Failed to create agents: %v", err))
 		r.setCondition(&a2aServer, A2AServerReady, metav1.ConditionFalse, "AgentCreationFailed", fmt.Sprintf("Failed to create agent: %v", err))
 		if err := r.updateStatusWithConditions(ctx, &a2aServer); err != nil {
 			return ctrl.Result{}, err
@@ -129,7 +134,7 @@ func (r *A2AServerReconciler) processServer(ctx context.Context, a2aServer arkv1
 		return ctrl.Result{RequeueAfter: a2aServer.Spec.PollInterval.Duration}, nil
 	}
 
-	return r.finalizeA2AServerProcessing(ctx, a2aServer)
+	return r.finalizeA2AServerProcessing(ctx, a2aServer, cardChanged)
 }
 
 // setCondition sets a condition on the A2AServer
@@ -155,7 +160,7 @@ func (r *A2AServerReconciler) updateStatusWithConditions(ctx context.Context, a2
 	return err
 }
 
-func (r *A2AServerReconciler) createAgentWithSkills(ctx context.Context, a2aServer *arkv1prealpha1.A2AServer, agentCard *genai.A2AAgentCard) error {
+func (r *A2AServerReconciler) createAgentsWithSkills(ctx context.Context, a2aServer *arkv1prealpha1.A2AServer, agentCards []*genai.A2AAgentCard) error {
 	log := logf.FromContext(ctx)
 
 	// Get existing agents for mark-and-sweep
@@ -170,15 +175,26 @@ func (r *A2AServerReconciler) createAgentWithSkills(ctx context.Context, a2aServ
 		agentMap[agent.Name] = false
 	}
 
-	// Create/update current agent and mark as keep
-	agentName := r.sanitizeAgentName(agentCard.Name)
-	agent := r.buildAgentWithSkills(a2aServer, agentCard, agentName)
-	agentMap[agentName] = true
+	// Create/update current agents and mark as keep
+	usedNames := make(map[string]bool)
+	for _, agentCard := range agentCards {
+		agentName, err := r.resolveAgentName(ctx, a2aServer, agentCard.Name, usedNames)
+		if err != nil {
+			return err
+		}
+		usedNames[agentName] = true
+		agentMap[agentName] = true
+
+		agent := r.buildAgentWithSkills(a2aServer, agentCard, agentName, genai.A2AAgentAddress(a2aServer.Status.LastResolvedAddress, agentCard, len(agentCards)))
+		created, err := r.createOrUpdateAgent(ctx, agent, agentName, a2aServer.Name)
+		if err != nil {
+			log.Error(err, "Failed to create agent", "agent", agentName, "a2aServer", a2aServer.Name, "namespace", a2aServer.Namespace)
+			return err
+		}
 
-	created, err := r.createOrUpdateAgent(ctx, agent, agentName, a2aServer.Name)
-	if err != nil {
-		log.Error(err, "Failed to create agent", "agent", agentName, "a2aServer", a2aServer.Name, "namespace", a2aServer.Namespace)
-		return err
+		if created {
+			r.Recorder.Event(a2aServer, corev1.EventTypeNormal, "AgentCreated", fmt.Sprintf("Agent created: %s with %d skills", agentName, len(agentCard.Skills)))
+		}
 	}
 
 	// Delete unmarked agents
@@ -199,20 +215,65 @@ func (r *A2AServerReconciler) createAgentWithSkills(ctx context.Context, a2aServ
 		}
 	}
 
-	if created {
-		r.Recorder.Event(a2aServer, corev1.EventTypeNormal, "AgentCreated", fmt.Sprintf("Agent created: %s with %d skills", agentName, len(agentCard.Skills)))
+	return nil
+}
+
+// resolveAgentName returns a unique agent name for a discovered agent card. Names colliding with another
+// discovered card or with an agent not managed by this A2AServer are prefixed with the server name.
+func (r *A2AServerReconciler) resolveAgentName(ctx context.Context, a2aServer *arkv1prealpha1.A2AServer, cardName string, usedNames map[string]bool) (string, error) {
+	candidates := []string{
+		r.sanitizeAgentName(cardName),
+		r.sanitizeAgentName(a2aServer.Name + "-" + cardName),
 	}
 
-	return nil
+	for _, candidate := range candidates {
+		available, err := r.isAgentNameAvailable(ctx, a2aServer, candidate, usedNames)
+		if err != nil {
+			return "", err
+		}
+		if available {
+			return candidate, nil
+		}
+	}
+
+	prefixed := candidates[len(candidates)-1]
+	for i := 2; ; i++ {
+		candidate := fmt.Sprintf("%s-%d", prefixed, i)
+		available, err := r.isAgentNameAvailable(ctx, a2aServer, candidate, usedNames)
+		if err != nil {
+			return "", err
+		}
+		if available {
+			return candidate, nil
+		}
+	}
+}
+
+// isAgentNameAvailable checks that a name is unused in this discovery and not taken by an agent from another source
+func (r *A2AServerReconciler) isAgentNameAvailable(ctx context.Context, a2aServer *arkv1prealpha1.A2AServer, name string, usedNames map[string]bool) (bool, error) {
+	if usedNames[name] {
+		return false, nil
+	}
+
+	existingAgent := &arkv1alpha1.Agent{}
+	err := r.Get(ctx, client.ObjectKey{Name: name, Namespace: a2aServer.Namespace}, existingAgent)
+	if errors.IsNotFound(err) {
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get agent %s: %w", name, err)
+	}
+
+	return existingAgent.Labels[labels.A2AServerLabel] == a2aServer.Name, nil
 }
 
-func (r *A2AServerReconciler) buildAgentWithSkills(a2aServer *arkv1prealpha1.A2AServer, agentCard *genai.A2AAgentCard, agentName string) *arkv1alpha1.Agent {
+func (r *A2AServerReconciler) buildAgentWithSkills(a2aServer *arkv1prealpha1.A2AServer, agentCard *genai.A2AAgentCard, agentName, address string) *arkv1alpha1.Agent {
 	// Build skills annotation JSON
 	skillsJSON, _ := json.Marshal(agentCard.Skills)
 
 	agentAnnotations := map[string]string{
 		annotations.A2AServerName:    a2aServer.Name,
-		annotations.A2AServerAddress: a2aServer.Status.LastResolvedAddress,
+		annotations.A2AServerAddress: address,
 		annotations.A2AServerSkills:  string(skillsJSON),
 	}
 
@@ -242,7 +303,8 @@ func (r *A2AServerReconciler) buildAgentWithSkills(a2aServer *arkv1prealpha1.A2A
 		},
 	}
 
-	_ = controllerutil.SetOwnerReference(a2aServer, agent, r.Scheme)
+	// The webhook exempts agents controlled by an A2AServer from the model default and prompt policy
+	_ = controllerutil.SetControllerReference(a2aServer, agent, r.Scheme)
 	return agent
 }
 
@@ -265,10 +327,16 @@ func (r *A2AServerReconciler) createOrUpdateAgent(ctx context.Context, agent *ar
 		return false, fmt.Errorf("failed to get agent %s: %w", agentName, err)
 	}
 
-	// Only update if skills annotation has changed
-	if existingAgent.Annotations[annotations.A2AServerSkills] != agent.Annotations[annotations.A2AServerSkills] {
+	// Only update if skills or address annotations have changed, or if the agent was discovered
+	// before agents were controlled by their A2AServer
+	if existingAgent.Annotations[annotations.A2AServerSkills] != agent.Annotations[annotations.A2AServerSkills] ||
+		existingAgent.Annotations[annotations.A2AServerAddress] != agent.Annotations[annotations.A2AServerAddress] ||
+		metav1.GetControllerOf(existingAgent) == nil {
+		// Requirements are set by users on the discovered agent, not derived from the agent card
+		agent.Spec.A2ARequirements = existingAgent.Spec.A2ARequirements
 		existingAgent.Spec = agent.Spec
 		existingAgent.Annotations = agent.Annotations
+		existingAgent.OwnerReferences = agent.OwnerReferences
 		if err := r.Update(ctx, existingAgent); err != nil {
 			log.Error(err, "Failed to update A2A agent", "agent", agentName, "a2aServer", a2aServerName)
 			return false, fmt.Errorf("failed to update agent %s: %w", agentName, err)
@@ -279,9 +347,9 @@ func (r *A2AServerReconciler) createOrUpdateAgent(ctx context.Context, agent *ar
 	return false, nil // Agent was updated or unchanged
 }
 
-func (r *A2AServerReconciler) finalizeA2AServerProcessing(ctx context.Context, a2aServer arkv1prealpha1.A2AServer) (ctrl.Result, error) {
+func (r *A2AServerReconciler) finalizeA2AServerProcessing(ctx context.Context, a2aServer arkv1prealpha1.A2AServer, cardChanged bool) (ctrl.Result, error) {
 	readyCondition := meta.FindStatusCondition(a2aServer.Status.Conditions, A2AServerReady)
-	if readyCondition != nil && readyCondition.Status == metav1.ConditionTrue && readyCondition.Reason == "AgentDiscovered" {
+	if !cardChanged && readyCondition != nil && readyCondition.Status == metav1.ConditionTrue && readyCondition.Reason == "AgentDiscovered" {
 		logf.FromContext(ctx).Info("A2AServer already in final state, skipping processing", "server", a2aServer.Name)
 		return ctrl.Result{RequeueAfter: a2aServer.Spec.PollInterval.Duration}, nil
 	}
@@ -5,8 +5,10 @@ package controller
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -15,17 +17,24 @@ import (
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/labels"
 )
 
 const (
 	// Condition types
 	AgentAvailable = "Available"
+
+	// defaultRevisionHistoryLimit is the number of previous revisions kept when an agent sets no limit
+	defaultRevisionHistoryLimit = 10
 )
 
 type AgentReconciler struct {
@@ -37,6 +46,7 @@ type AgentReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agentrevisions,verbs=get;list;watch;create;delete
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=tools,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=a2aservers,verbs=get;list;watch
@@ -65,6 +75,11 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, nil
 	}
 
+	// Record a new revision whenever the agent spec changes
+	if err := r.reconcileRevision(ctx, &agent); err != nil {
+		return ctrl.Result{}, err
+	}
+
 	// Check current condition
 	currentCondition := meta.FindStatusCondition(agent.Status.Conditions, AgentAvailable)
 
@@ -92,12 +107,138 @@ func (r *AgentReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 	return ctrl.Result{}, nil
 }
 
+// reconcileRevision creates an AgentRevision when the spec differs from the latest revision, updates
+// status.currentRevision and prunes revisions beyond the revision history limit
+func (r *AgentReconciler) reconcileRevision(ctx context.Context, agent *arkv1alpha1.Agent) error {
+	revisions, err := r.listRevisions(ctx, agent)
+	if err != nil {
+		return err
+	}
+
+	template := revisionTemplate(agent)
+	revision := int64(1)
+	if len(revisions) > 0 {
+		latest := revisions[0]
+		if equality.Semantic.DeepEqual(latest.Spec.Template, template) {
+			if err := r.setCurrentRevision(ctx, agent, latest.Spec.Revision); err != nil {
+				return err
+			}
+			return r.pruneRevisions(ctx, agent, revisions)
+		}
+		revision = latest.Spec.Revision + 1
+	}
+
+	agentRevision := &arkv1alpha1.AgentRevision{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", agent.Name, revision),
+			Namespace: agent.Namespace,
+			Labels: map[string]string{
+				labels.AgentLabel: agent.Name,
+			},
+		},
+		Spec: arkv1alpha1.AgentRevisionSpec{
+			AgentName: agent.Name,
+			Revision:  revision,
+			Template:  template,
+		},
+	}
+	if err := controllerutil.SetControllerReference(agent, agentRevision, r.Scheme); err != nil {
+		return fmt.Errorf("failed to set owner reference on agent revision: %w", err)
+	}
+
+	if err := r.Create(ctx, agentRevision); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return fmt.Errorf("failed to create agent revision %s: %w", agentRevision.Name, err)
+		}
+		// The name may be taken by a revision of a deleted agent or by an object created by someone else
+		existing := &arkv1alpha1.AgentRevision{}
+		if err := r.Get(ctx, client.ObjectKeyFromObject(agentRevision), existing); err != nil {
+			return fmt.Errorf("failed to get agent revision %s: %w", agentRevision.Name, err)
+		}
+		if !metav1.IsControlledBy(existing, agent) {
+			return fmt.Errorf("agent revision %s already exists and is not owned by agent %s", agentRevision.Name, agent.Name)
+		}
+	} else {
+		logf.FromContext(ctx).Info("agent revision created", "agent", agent.Name, "revision", revision)
+		r.Recorder.Event(agent, corev1.EventTypeNormal, "RevisionCreated", fmt.Sprintf("Created agent revision %d", revision))
+	}
+
+	if err := r.setCurrentRevision(ctx, agent, revision); err != nil {
+		return err
+	}
+	return r.pruneRevisions(ctx, agent, append([]arkv1alpha1.AgentRevision{*agentRevision}, revisions...))
+}
+
+// revisionTemplate returns the agent spec recorded in revisions. The revision history limit is not
+// part of it, so changing the limit neither records a revision nor is undone by a rollback.
+func revisionTemplate(agent *arkv1alpha1.Agent) arkv1alpha1.AgentSpec {
+	template := *agent.Spec.DeepCopy()
+	template.RevisionHistoryLimit = nil
+	return template
+}
+
+// listRevisions returns the AgentRevisions controlled by the agent, latest first. Revisions with the
+// agent label that the agent does not own are ignored.
+func (r *AgentReconciler) listRevisions(ctx context.Context, agent *arkv1alpha1.Agent) ([]arkv1alpha1.AgentRevision, error) {
+	var list arkv1alpha1.AgentRevisionList
+	if err := r.List(ctx, &list, client.InNamespace(agent.Namespace), client.MatchingLabels{labels.AgentLabel: agent.Name}); err != nil {
+		return nil, fmt.Errorf("failed to list agent revisions: %w", err)
+	}
+
+	revisions := make([]arkv1alpha1.AgentRevision, 0, len(list.Items))
+	for _, revision := range list.Items {
+		if metav1.IsControlledBy(&revision, agent) {
+			revisions = append(revisions, revision)
+		}
+	}
+	sort.Slice(revisions, func(i, j int) bool {
+		return revisions[i].Spec.Revision > revisions[j].Spec.Revision
+	})
+	return revisions, nil
+}
+
+// pruneRevisions deletes the revisions, latest first, beyond the current one and the revision history limit
+func (r *AgentReconciler) pruneRevisions(ctx context.Context, agent *arkv1alpha1.Agent, revisions []arkv1alpha1.AgentRevision) error {
+	limit := defaultRevisionHistoryLimit
+	if agent.Spec.RevisionHistoryLimit != nil {
+		limit = int(*agent.Spec.RevisionHistoryLimit)
+	}
+
+	kept := 0
+	for i := range revisions {
+		revision := &revisions[i]
+		if revision.Spec.Revision == agent.Status.CurrentRevision {
+			continue
+		}
+		if kept < limit {
+			kept++
+			continue
+		}
+		if err := r.Delete(ctx, revision); err != nil && !errors.IsNotFound(err) {
+			return fmt.Errorf("failed to delete agent revision %s: %w", revision.Name, err)
+		}
+		logf.FromContext(ctx).V(1).Info("agent revision pruned", "agent", agent.Name, "revision", revision.Spec.Revision)
+	}
+	return nil
+}
+
+func (r *AgentReconciler) setCurrentRevision(ctx context.Context, agent *arkv1alpha1.Agent, revision int64) error {
+	if agent.Status.CurrentRevision == revision {
+		return nil
+	}
+	agent.Status.CurrentRevision = revision
+	return r.updateStatus(ctx, agent)
+}
+
 // checkDependencies validates all agent dependencies and returns availability status
 func (r *AgentReconciler) checkDependencies(ctx context.Context, agent *arkv1alpha1.Agent) (available bool, reason, message string) {
 	// Check A2AServer dependency (if agent is owned by an A2AServer)
 	if ok, msg := r.checkA2AServerDependency(ctx, agent); !ok {
 		return false, "A2AServerNotReady", msg
 	}
+	if ok, msg := r.checkA2ARequirements(ctx, agent); !ok {
+		return false, "A2ARequirementsNotMet", msg
+	}
 
 	// Check the status of the agent's model. Some agents (such as A2A agents) have a 'nil' model, and their status is not associated with model availability.
 	if agent.Spec.ModelRef != nil {
@@ -124,6 +265,12 @@ func (r *AgentReconciler) checkModelDependency(ctx context.Context, agent *arkv1
 		modelNamespace = agent.Spec.ModelRef.Namespace
 	}
 
+	if err := genai.CheckReferenceGrant(ctx, r.Client, arkv1alpha1.ReferenceFromAgent, agent.Namespace, arkv1alpha1.TargetKindModel, modelName, modelNamespace); err != nil {
+		msg := fmt.Sprintf("Model '%s' is not available: %v", modelName, err)
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "ReferenceNotGranted", msg)
+		return false, msg
+	}
+
 	var model arkv1alpha1.Model
 	modelKey := types.NamespacedName{Name: modelName, Namespace: modelNamespace}
 	if err := r.Get(ctx, modelKey, &model); err != nil {
@@ -150,11 +297,21 @@ func (r *AgentReconciler) checkModelDependency(ctx context.Context, agent *arkv1
 func (r *AgentReconciler) checkToolDependencies(ctx context.Context, agent *arkv1alpha1.Agent) (bool, string) {
 	for _, toolSpec := range agent.Spec.Tools {
 		if toolSpec.Type == "custom" && toolSpec.Name != "" {
+			toolNamespace := agent.Namespace
+			if toolSpec.Namespace != "" {
+				toolNamespace = toolSpec.Namespace
+			}
+			if err := genai.CheckReferenceGrant(ctx, r.Client, arkv1alpha1.ReferenceFromAgent, agent.Namespace, arkv1alpha1.TargetKindTool, toolSpec.Name, toolNamespace); err != nil {
+				msg := fmt.Sprintf("Tool '%s' is not available: %v", toolSpec.Name, err)
+				r.Recorder.Event(agent, corev1.EventTypeWarning, "ReferenceNotGranted", msg)
+				return false, msg
+			}
+
 			var tool arkv1alpha1.Tool
-			toolKey := types.NamespacedName{Name: toolSpec.Name, Namespace: agent.Namespace}
+			toolKey := types.NamespacedName{Name: toolSpec.Name, Namespace: toolNamespace}
 			if err := r.Get(ctx, toolKey, &tool); err != nil {
 				if errors.IsNotFound(err) {
-					msg := fmt.Sprintf("Tool '%s' not found in namespace '%s'", toolSpec.Name, agent.Namespace)
+					msg := fmt.Sprintf("Tool '%s' not found in namespace '%s'", toolSpec.Name, toolNamespace)
 					r.Recorder.Event(agent, corev1.EventTypeWarning, "ToolNotFound", msg)
 					return false, msg
 				}
@@ -203,6 +360,26 @@ func (r *AgentReconciler) validateA2AServerDependency(ctx context.Context, agent
 	return true, ""
 }
 
+// checkA2ARequirements validates that the A2AServer of an A2A agent declares the required protocol
+// version and capabilities
+func (r *AgentReconciler) checkA2ARequirements(ctx context.Context, agent *arkv1alpha1.Agent) (bool, string) {
+	serverName, ok := agent.Annotations[annotations.A2AServerName]
+	if agent.Spec.A2ARequirements == nil || !ok {
+		return true, ""
+	}
+
+	var a2aServer arkv1prealpha1.A2AServer
+	if err := r.Get(ctx, types.NamespacedName{Name: serverName, Namespace: agent.Namespace}, &a2aServer); err != nil {
+		return false, fmt.Sprintf("Error checking A2AServer: %v", err)
+	}
+	if err := genai.CheckA2ARequirements(agent.Spec.A2ARequirements, &a2aServer.Status); err != nil {
+		msg := fmt.Sprintf("A2AServer '%s': %v", serverName, err)
+		r.Recorder.Event(agent, corev1.EventTypeWarning, "A2ARequirementsNotMet", msg)
+		return false, msg
+	}
+	return true, ""
+}
+
 // isA2AServerReady checks if an A2AServer has Ready condition true
 func (r *AgentReconciler) isA2AServerReady(a2aServer *arkv1prealpha1.A2AServer) bool {
 	for _, condition := range a2aServer.Status.Conditions {
@@ -240,6 +417,7 @@ func (r *AgentReconciler) updateStatus(ctx context.Context, agent *arkv1alpha1.A
 func (r *AgentReconciler) SetupWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewControllerManagedBy(mgr).
 		For(&arkv1alpha1.Agent{}).
+		Owns(&arkv1alpha1.AgentRevision{}).
 		// Watch for Tool events and reconcile dependent agents
 		Watches(
 			&arkv1alpha1.Tool{},
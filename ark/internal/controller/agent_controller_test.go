@@ -4,17 +4,24 @@ package controller
 
 import (
 	"context"
+	"fmt"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/labels"
 )
 
 var _ = Describe("Agent Controller", func() {
@@ -74,6 +81,30 @@ var _ = Describe("Agent Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 
+		It("should record an agent revision for the current spec", func() {
+			controllerReconciler := &AgentReconciler{
+				Client:   k8sClient,
+				Scheme:   k8sClient.Scheme(),
+				Recorder: record.NewFakeRecorder(10),
+			}
+
+			By("Reconciling until conditions are initialized and a revision is recorded")
+			for range 2 {
+				_, err := controllerReconciler.Reconcile(ctx, reconcile.Request{
+					NamespacedName: typeNamespacedName,
+				})
+				Expect(err).NotTo(HaveOccurred())
+			}
+
+			reconciled := &arkv1alpha1.Agent{}
+			Expect(k8sClient.Get(ctx, typeNamespacedName, reconciled)).To(Succeed())
+			Expect(reconciled.Status.CurrentRevision).To(Equal(int64(1)))
+
+			revision := &arkv1alpha1.AgentRevision{}
+			Expect(k8sClient.Get(ctx, types.NamespacedName{Name: resourceName + "-1", Namespace: "default"}, revision)).To(Succeed())
+			Expect(revision.Spec.Template).To(Equal(reconciled.Spec))
+		})
+
 		It("should handle agents without explicit model reference", func() {
 			const defaultModelResourceName = "test-default-model-resource"
 			defaultModelTypeNamespacedName := types.NamespacedName{
@@ -150,3 +181,70 @@ var _ = Describe("Agent Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Agent Revision History", func() {
+	var (
+		ctx        context.Context
+		scheme     *runtime.Scheme
+		agent      *arkv1alpha1.Agent
+		reconciler *AgentReconciler
+	)
+
+	newRevision := func(revision int64, owned bool) *arkv1alpha1.AgentRevision {
+		agentRevision := &arkv1alpha1.AgentRevision{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      fmt.Sprintf("weather-%d", revision),
+				Namespace: "default",
+				Labels:    map[string]string{labels.AgentLabel: "weather"},
+			},
+			Spec: arkv1alpha1.AgentRevisionSpec{AgentName: "weather", Revision: revision, Template: arkv1alpha1.AgentSpec{Prompt: "old"}},
+		}
+		if owned {
+			Expect(controllerutil.SetControllerReference(agent, agentRevision, scheme)).To(Succeed())
+		}
+		return agentRevision
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		scheme = runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+		agent = &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "agent-uid"},
+			Spec:       arkv1alpha1.AgentSpec{Prompt: "current", RevisionHistoryLimit: ptr.To(int32(1))},
+		}
+	})
+
+	build := func(objects ...client.Object) {
+		fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(append(objects, agent)...).WithStatusSubresource(agent).Build()
+		reconciler = &AgentReconciler{Client: fakeClient, Scheme: scheme, Recorder: record.NewFakeRecorder(10)}
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(agent), agent)).To(Succeed())
+	}
+
+	It("should ignore revisions the agent does not own and prune beyond the limit", func() {
+		build(newRevision(1, true), newRevision(2, true), newRevision(7, false))
+
+		Expect(reconciler.reconcileRevision(ctx, agent)).To(Succeed())
+		Expect(agent.Status.CurrentRevision).To(Equal(int64(3)))
+
+		var revisions arkv1alpha1.AgentRevisionList
+		Expect(reconciler.List(ctx, &revisions)).To(Succeed())
+		var names []string
+		for _, revision := range revisions.Items {
+			names = append(names, revision.Name)
+		}
+		Expect(names).To(ConsistOf("weather-2", "weather-3", "weather-7"))
+
+		recorded := &arkv1alpha1.AgentRevision{}
+		Expect(reconciler.Get(ctx, types.NamespacedName{Name: "weather-3", Namespace: "default"}, recorded)).To(Succeed())
+		Expect(recorded.Spec.Template.Prompt).To(Equal("current"))
+		Expect(recorded.Spec.Template.RevisionHistoryLimit).To(BeNil())
+	})
+
+	It("should not adopt a revision name taken by another object", func() {
+		build(newRevision(1, true), newRevision(2, false))
+
+		Expect(reconciler.reconcileRevision(ctx, agent)).To(MatchError(ContainSubstring("not owned by agent weather")))
+		Expect(agent.Status.CurrentRevision).To(BeZero())
+	})
+})
@@ -5,9 +5,11 @@ package controller
 import (
 	"context"
 	"fmt"
+	"maps"
 	"strconv"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -21,19 +23,27 @@ import (
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+	"mckinsey.com/ark/internal/telemetry/noop"
 )
 
 const (
 	paramModelNamespace = "model.namespace"
 	paramModelName      = "model.name"
+
+	defaultEvaluationHistoryLimit = 10
 )
 
 // EvaluationReconciler reconciles an Evaluation object
 type EvaluationReconciler struct {
 	client.Client
-	Scheme   *runtime.Scheme
-	Recorder record.EventRecorder
-	resolver *common.ValueSourceResolver
+	Scheme     *runtime.Scheme
+	Recorder   record.EventRecorder
+	Evaluators *genai.EvaluatorClient
+	Notifier   *genai.Notifier
+	Telemetry  telemetry.Provider
+	resolver   *common.ValueSourceResolver
 }
 
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluations,verbs=get;list;watch;create;update;patch;delete
@@ -41,6 +51,10 @@ type EvaluationReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluations/finalizers,verbs=update
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluators,verbs=get;list;watch
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=notificationsinks,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
@@ -94,10 +108,49 @@ func (r *EvaluationReconciler) getResolver() *common.ValueSourceResolver {
 	return r.resolver
 }
 
+func (r *EvaluationReconciler) getEvaluators() *genai.EvaluatorClient {
+	if r.Evaluators == nil {
+		r.Evaluators = genai.NewEvaluatorClient()
+	}
+	return r.Evaluators
+}
+
+func (r *EvaluationReconciler) getTelemetry() telemetry.Provider {
+	if r.Telemetry == nil {
+		r.Telemetry = noop.NewProvider()
+	}
+	return r.Telemetry
+}
+
+// handleEvaluatorError fails the evaluation with message, unless the evaluator circuit is open, in
+// which case the evaluation stays running and is requeued for when the evaluator accepts calls again
+func (r *EvaluationReconciler) handleEvaluatorError(ctx context.Context, evaluation arkv1alpha1.Evaluation, message string, err error) (ctrl.Result, error) {
+	if retryAfter, open := genai.EvaluatorRetryAfter(err); open {
+		logf.FromContext(ctx).Info("Evaluator unavailable, requeueing evaluation", "evaluation", evaluation.Name, "retryAfter", retryAfter)
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+	if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("%s: %v", message, err)); err != nil {
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, nil
+}
+
 func (r *EvaluationReconciler) processEvaluation(ctx context.Context, evaluation arkv1alpha1.Evaluation) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 	log.Info("Processing evaluation", "evaluation", evaluation.Name, "type", evaluation.Spec.Type)
 
+	// The evaluator calls of this reconcile share a trace, which the status links to when it is updated
+	ctx, span := r.getTelemetry().Tracer().Start(ctx, "evaluation."+evaluation.Name,
+		telemetry.WithAttributes(
+			telemetry.String(telemetry.AttrEvaluationName, evaluation.Name),
+			telemetry.String(telemetry.AttrEvaluationNamespace, evaluation.Namespace),
+			telemetry.String(telemetry.AttrEvaluationType, evaluation.Spec.Type),
+			telemetry.String(telemetry.AttrEvaluatorName, evaluation.Spec.Evaluator.Name),
+		),
+	)
+	defer span.End()
+	evaluation.Status.TraceID, evaluation.Status.TraceURL = telemetry.TraceLink(span)
+
 	// Validate evaluator reference
 	if err := r.validateEvaluatorRef(ctx, evaluation); err != nil {
 		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Evaluator validation failed: %v", err)); err != nil {
@@ -330,6 +383,49 @@ func (r *EvaluationReconciler) mergeParameters(evaluatorParams, evaluationParams
 	return merged
 }
 
+// resolveFieldRefParameters resolves queryFieldRef parameters from the query of the evaluation and
+// resourceFieldRef parameters from the resource declaring them. Parameters that cannot be resolved are
+// left out, like other parameters with failed valueFrom sources.
+func (r *EvaluationReconciler) resolveFieldRefParameters(ctx context.Context, evaluation arkv1alpha1.Evaluation, resource metav1.Object, params []arkv1alpha1.Parameter) []arkv1alpha1.Parameter {
+	log := logf.FromContext(ctx)
+
+	var query *arkv1alpha1.Query
+	resolved := make([]arkv1alpha1.Parameter, 0, len(params))
+	for _, param := range params {
+		if param.ValueFrom == nil || (param.ValueFrom.QueryFieldRef == nil && param.ValueFrom.ResourceFieldRef == nil) {
+			resolved = append(resolved, param)
+			continue
+		}
+
+		var value string
+		var err error
+		if param.ValueFrom.ResourceFieldRef != nil {
+			value, err = common.ResolveResourceFieldPath(resource, param.ValueFrom.ResourceFieldRef.FieldPath)
+		} else {
+			if query == nil {
+				query, err = r.fetchEvaluationQuery(ctx, evaluation)
+			}
+			if err == nil {
+				value, err = common.ResolveQueryFieldPath(query, param.ValueFrom.QueryFieldRef.FieldPath)
+			}
+		}
+		if err != nil {
+			log.Error(err, "Failed to resolve parameter field reference", "parameter", param.Name)
+			continue
+		}
+		resolved = append(resolved, arkv1alpha1.Parameter{Name: param.Name, Value: value})
+	}
+	return resolved
+}
+
+// fetchEvaluationQuery returns the query of a query evaluation
+func (r *EvaluationReconciler) fetchEvaluationQuery(ctx context.Context, evaluation arkv1alpha1.Evaluation) (*arkv1alpha1.Query, error) {
+	if evaluation.Spec.Config.QueryBasedEvaluationConfig == nil || evaluation.Spec.Config.QueryRef == nil {
+		return nil, fmt.Errorf("queryFieldRef requires an evaluation with queryRef")
+	}
+	return r.fetchQuery(ctx, evaluation)
+}
+
 // resolveFinalParameters resolves and merges parameters from evaluator and evaluation
 func (r *EvaluationReconciler) resolveFinalParameters(ctx context.Context, evaluation arkv1alpha1.Evaluation) []arkv1alpha1.Parameter {
 	log := logf.FromContext(ctx)
@@ -342,6 +438,8 @@ func (r *EvaluationReconciler) resolveFinalParameters(ctx context.Context, evalu
 
 	log.Info("Resolving evaluator parameters", "evaluation", evaluation.Name, "evaluatorName", evaluation.Spec.Evaluator.Name, "evaluatorNamespace", evaluatorNamespace)
 
+	evaluationParams := r.resolveFieldRefParameters(ctx, evaluation, &evaluation, evaluation.Spec.Evaluator.Parameters)
+
 	var evaluator arkv1alpha1.Evaluator
 	evaluatorKey := client.ObjectKey{
 		Name:      evaluation.Spec.Evaluator.Name,
@@ -350,15 +448,15 @@ func (r *EvaluationReconciler) resolveFinalParameters(ctx context.Context, evalu
 
 	if err := r.Get(ctx, evaluatorKey, &evaluator); err != nil {
 		log.Error(err, "Failed to get evaluator, falling back to evaluation parameters only", "evaluatorKey", evaluatorKey)
-		return evaluation.Spec.Evaluator.Parameters // Fall back to evaluation parameters only
+		return evaluationParams // Fall back to evaluation parameters only
 	}
 
 	log.Info("Evaluator retrieved successfully", "evaluation", evaluation.Name, "evaluatorParamsCount", len(evaluator.Spec.Parameters), "evaluatorParams", evaluator.Spec.Parameters)
 
 	// If evaluator has no parameters, just return evaluation parameters
 	if len(evaluator.Spec.Parameters) == 0 {
-		log.Info("Evaluator has no parameters, returning evaluation parameters", "evaluation", evaluation.Name, "evaluationParams", evaluation.Spec.Evaluator.Parameters)
-		return evaluation.Spec.Evaluator.Parameters
+		log.Info("Evaluator has no parameters, returning evaluation parameters", "evaluation", evaluation.Name, "evaluationParams", evaluationParams)
+		return evaluationParams
 	}
 
 	// Convert evaluator parameters to the standard Parameter format
@@ -372,6 +470,7 @@ func (r *EvaluationReconciler) resolveFinalParameters(ctx context.Context, evalu
 		})
 	}
 
+	evaluatorParams = r.resolveFieldRefParameters(ctx, evaluation, &evaluator, evaluatorParams)
 	log.Info("Converted evaluator parameters", "evaluation", evaluation.Name, "convertedParams", evaluatorParams)
 
 	// Log specific model parameters for debugging
@@ -382,7 +481,7 @@ func (r *EvaluationReconciler) resolveFinalParameters(ctx context.Context, evalu
 	}
 
 	// Merge evaluator parameters with evaluation parameters (evaluation takes precedence)
-	merged := r.mergeParameters(evaluatorParams, evaluation.Spec.Evaluator.Parameters)
+	merged := r.mergeParameters(evaluatorParams, evaluationParams)
 	log.Info("Merged parameters", "evaluation", evaluation.Name, "merged", merged)
 
 	// Log specific model parameters after merging
@@ -445,13 +544,10 @@ func (r *EvaluationReconciler) processDirectEvaluation(ctx context.Context, eval
 	log.Info("Using timeout for direct evaluation", "evaluation", evaluation.Name, "timeout", timeout)
 
 	// Call unified endpoint
-	response, err := genai.CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
+	response, err := r.getEvaluators().CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
 	if err != nil {
 		log.Error(err, "Failed to call unified evaluator", "evaluation", evaluation.Name)
-		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Evaluator call failed: %v", err)); err != nil {
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil
+		return r.handleEvaluatorError(ctx, evaluation, "Evaluator call failed", err)
 	}
 
 	// Complete evaluation with all results in one operation
@@ -499,12 +595,7 @@ func (r *EvaluationReconciler) processBatchEvaluation(ctx context.Context, evalu
 		return ctrl.Result{}, nil
 	}
 
-	if !childrenCreated {
-		// Still creating children, requeue
-		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
-	}
-
-	// Check child evaluation status
+	// Check child evaluation status and publish the batch progress
 	allCompleted, err := r.checkChildEvaluationStatus(ctx, evaluation)
 	if err != nil {
 		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Failed to check child evaluations: %v", err)); err != nil {
@@ -513,6 +604,11 @@ func (r *EvaluationReconciler) processBatchEvaluation(ctx context.Context, evalu
 		return ctrl.Result{}, nil
 	}
 
+	if !childrenCreated {
+		// Still creating children, requeue
+		return ctrl.Result{RequeueAfter: time.Second * 5}, nil
+	}
+
 	if !allCompleted {
 		// Still waiting for children to complete, requeue
 		return ctrl.Result{RequeueAfter: time.Second * 10}, nil
@@ -653,13 +749,10 @@ func (r *EvaluationReconciler) processQueryEvaluation(ctx context.Context, evalu
 	log.Info("Using timeout for query evaluation", "evaluation", evaluation.Name, "timeout", timeout)
 
 	// Call unified evaluator endpoint
-	response, err := genai.CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
+	response, err := r.getEvaluators().CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
 	if err != nil {
 		log.Error(err, "Failed to call unified direct evaluator for query evaluation", "evaluation", evaluation.Name)
-		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Query evaluation failed: %v", err)); err != nil {
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil
+		return r.handleEvaluatorError(ctx, evaluation, "Query evaluation failed", err)
 	}
 
 	// Log the response metadata for debugging
@@ -685,6 +778,30 @@ func (r *EvaluationReconciler) setConditionCompleted(evaluation *arkv1alpha1.Eva
 	})
 }
 
+// recordEvaluationRun appends the result in the status of a completed evaluation to its history,
+// dropping the oldest runs beyond spec.historyLimit
+func recordEvaluationRun(evaluation *arkv1alpha1.Evaluation, completedAt metav1.Time) {
+	limit := defaultEvaluationHistoryLimit
+	if evaluation.Spec.HistoryLimit != nil {
+		limit = int(*evaluation.Spec.HistoryLimit)
+	}
+
+	history := append(evaluation.Status.History, arkv1alpha1.EvaluationRunRecord{
+		Score:       evaluation.Status.Score,
+		Passed:      evaluation.Status.Passed,
+		Metrics:     maps.Clone(evaluation.Status.Metrics),
+		TokenUsage:  evaluation.Status.TokenUsage.DeepCopy(),
+		CompletedAt: completedAt,
+	})
+	if len(history) > limit {
+		history = history[len(history)-limit:]
+	}
+	if len(history) == 0 {
+		history = nil
+	}
+	evaluation.Status.History = history
+}
+
 func (r *EvaluationReconciler) updateStatus(ctx context.Context, evaluation arkv1alpha1.Evaluation, phase, message string) error {
 	log := logf.FromContext(ctx)
 
@@ -705,6 +822,7 @@ func (r *EvaluationReconciler) updateStatus(ctx context.Context, evaluation arkv
 		// Update status fields atomically
 		latest.Status.Phase = phase
 		latest.Status.Message = message
+		setEvaluationTrace(latest, evaluation)
 
 		switch phase {
 		case statusRunning:
@@ -728,16 +846,35 @@ func (r *EvaluationReconciler) updateStatus(ctx context.Context, evaluation arkv
 	})
 }
 
+// setEvaluationTrace links the status of an evaluation to the trace of the reconcile updating it
+func setEvaluationTrace(latest *arkv1alpha1.Evaluation, evaluation arkv1alpha1.Evaluation) {
+	if evaluation.Status.TraceID != "" {
+		latest.Status.TraceID = evaluation.Status.TraceID
+		latest.Status.TraceURL = evaluation.Status.TraceURL
+	}
+}
+
 func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, evaluation arkv1alpha1.Evaluation, response *genai.EvaluationResponse, message string) error {
 	log := logf.FromContext(ctx)
 
+	if criteria := evaluation.Spec.PassCriteria; criteria != nil {
+		failed, err := genai.ApplyPassCriteria(criteria, response)
+		if err != nil {
+			return r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Failed to apply pass criteria: %v", err))
+		}
+		if !response.Passed && len(failed) > 0 {
+			message = fmt.Sprintf("%s, %s", message, genai.FormatFailedMetrics(failed))
+		}
+	}
+
 	evalKey := client.ObjectKey{
 		Name:      evaluation.Name,
 		Namespace: evaluation.Namespace,
 	}
 
+	completedAt := metav1.Now()
 	// Use retry logic for atomic updates
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 		// Fetch the latest version
 		latest := &arkv1alpha1.Evaluation{}
 		if err := r.Get(ctx, evalKey, latest); err != nil {
@@ -771,9 +908,12 @@ func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, eva
 		// Update all status fields atomically
 		latest.Status.Score = response.Score
 		latest.Status.Passed = response.Passed
+		latest.Status.Metrics = response.Metrics
 		latest.Status.TokenUsage = response.TokenUsage
 		latest.Status.Phase = statusDone
 		latest.Status.Message = message
+		setEvaluationTrace(latest, evaluation)
+		recordEvaluationRun(latest, completedAt)
 
 		r.setConditionCompleted(latest, metav1.ConditionTrue, "EvaluationCompleted", message)
 
@@ -784,8 +924,38 @@ func (r *EvaluationReconciler) updateEvaluationComplete(ctx context.Context, eva
 		}
 
 		log.Info("Completed Evaluation atomically", "evaluation", evaluation.Name, "score", response.Score, "passed", response.Passed, "phase", statusDone)
+		metrics.RecordEvaluationResult(evaluation.Spec.Evaluator.Name, evaluation.Namespace, response.Passed)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	r.publishEvaluationResult(ctx, &evaluation, response, completedAt)
+	if !response.Passed {
+		r.notifyEvaluationBelowThreshold(ctx, &evaluation, response)
+	}
+	return nil
+}
+
+// notifyEvaluationBelowThreshold sends EvaluationBelowThreshold for an evaluation that did not pass
+func (r *EvaluationReconciler) notifyEvaluationBelowThreshold(ctx context.Context, evaluation *arkv1alpha1.Evaluation, response *genai.EvaluationResponse) {
+	details := map[string]string{
+		"evaluator": evaluation.Spec.Evaluator.Name,
+		"score":     response.Score,
+	}
+	if config := evaluation.Spec.Config.QueryBasedEvaluationConfig; config != nil && config.QueryRef != nil {
+		details["query"] = config.QueryRef.Name
+	}
+	r.Notifier.Notify(ctx, genai.Notification{
+		Event:     arkv1alpha1.NotificationEvaluationBelowThreshold,
+		Kind:      "Evaluation",
+		Name:      evaluation.Name,
+		Namespace: evaluation.Namespace,
+		Message:   fmt.Sprintf("Evaluation did not pass with score %s", response.Score),
+		Time:      time.Now().UTC(),
+		Details:   details,
+	})
 }
 
 func (r *EvaluationReconciler) ensureChildEvaluations(ctx context.Context, parentEvaluation arkv1alpha1.Evaluation) (bool, error) {
@@ -805,12 +975,9 @@ func (r *EvaluationReconciler) ensureChildEvaluations(ctx context.Context, paren
 	}
 
 	// Create missing child evaluations from batch config
-	for i, evaluationRef := range parentEvaluation.Spec.Config.Evaluations {
-		childName := fmt.Sprintf("%s-child-%d", parentEvaluation.Name, i)
-
-		if existingChildren[childName] {
-			continue // Child already exists
-		}
+	for _, i := range childEvaluationsToCreate(parentEvaluation, childEvaluations.Items) {
+		evaluationRef := parentEvaluation.Spec.Config.Evaluations[i]
+		childName := childEvaluationName(parentEvaluation, i)
 
 		// Note: This is a simplified implementation - in a full implementation,
 		// we would fetch the referenced evaluation and copy its spec
@@ -855,6 +1022,37 @@ func (r *EvaluationReconciler) ensureChildEvaluations(ctx context.Context, paren
 	return len(existingChildren) == len(parentEvaluation.Spec.Config.Evaluations), nil
 }
 
+func childEvaluationName(parentEvaluation arkv1alpha1.Evaluation, index int) string {
+	return fmt.Sprintf("%s-child-%d", parentEvaluation.Name, index)
+}
+
+// childEvaluationsToCreate returns the indexes of the batch evaluations without a child, in order,
+// limited so that at most maxConcurrency children run at a time
+func childEvaluationsToCreate(parentEvaluation arkv1alpha1.Evaluation, children []arkv1alpha1.Evaluation) []int {
+	existing := make(map[string]bool, len(children))
+	running := 0
+	for _, child := range children {
+		existing[child.Name] = true
+		if !isEvaluationFinished(child) {
+			running++
+		}
+	}
+
+	maxConcurrency := int(parentEvaluation.Spec.Config.MaxConcurrency)
+	var indexes []int
+	for i := range parentEvaluation.Spec.Config.Evaluations {
+		if existing[childEvaluationName(parentEvaluation, i)] {
+			continue
+		}
+		if maxConcurrency > 0 && running >= maxConcurrency {
+			break
+		}
+		indexes = append(indexes, i)
+		running++
+	}
+	return indexes
+}
+
 func (r *EvaluationReconciler) checkChildEvaluationStatus(ctx context.Context, parentEvaluation arkv1alpha1.Evaluation) (bool, error) {
 	log := logf.FromContext(ctx)
 
@@ -865,25 +1063,90 @@ func (r *EvaluationReconciler) checkChildEvaluationStatus(ctx context.Context, p
 		return false, fmt.Errorf("failed to list child evaluations: %w", err)
 	}
 
-	completedCount := 0
-	for _, child := range childEvaluations.Items {
-		if child.Status.Phase == statusDone || child.Status.Phase == statusError {
-			completedCount++
-		}
-	}
+	total := len(parentEvaluation.Spec.Config.Evaluations)
+	progress := batchProgress(childEvaluations.Items, total, time.Now())
 
 	// Update parent status to reflect child progress
-	if err := r.Status().Update(ctx, &parentEvaluation); err != nil {
+	if err := r.updateBatchProgress(ctx, parentEvaluation, progress); err != nil {
 		log.Error(err, "Failed to update parent evaluation status", "evaluation", parentEvaluation.Name)
 		return false, err
 	}
 
-	allCompleted := completedCount == len(childEvaluations.Items)
-	log.Info("Child evaluation status check", "parent", parentEvaluation.Name, "completed", completedCount, "total", len(childEvaluations.Items), "allCompleted", allCompleted)
+	allCompleted := int(progress.Completed) == total
+	log.Info("Child evaluation status check", "parent", parentEvaluation.Name, "completed", progress.Completed, "running", progress.Running, "total", total, "allCompleted", allCompleted)
 
 	return allCompleted, nil
 }
 
+func (r *EvaluationReconciler) updateBatchProgress(ctx context.Context, evaluation arkv1alpha1.Evaluation, progress *arkv1alpha1.BatchEvaluationProgress) error {
+	evalKey := client.ObjectKey{
+		Name:      evaluation.Name,
+		Namespace: evaluation.Namespace,
+	}
+
+	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		latest := &arkv1alpha1.Evaluation{}
+		if err := r.Get(ctx, evalKey, latest); err != nil {
+			return err
+		}
+		if sameBatchProgress(latest.Status.BatchProgress, progress) {
+			// Updating only the estimate would trigger another reconcile on every poll
+			return nil
+		}
+		latest.Status.BatchProgress = progress
+		latest.Status.Message = fmt.Sprintf("%d/%d child evaluations completed", progress.Completed, progress.Total)
+		return r.Status().Update(ctx, latest)
+	})
+}
+
+// batchProgress summarizes the children of a batch of total evaluations. The completion time is
+// estimated from the rate at which children completed since the first one was created.
+func batchProgress(children []arkv1alpha1.Evaluation, total int, now time.Time) *arkv1alpha1.BatchEvaluationProgress {
+	progress := &arkv1alpha1.BatchEvaluationProgress{Total: int32(total)}
+
+	var started time.Time
+	for _, child := range children {
+		if started.IsZero() || child.CreationTimestamp.Time.Before(started) {
+			started = child.CreationTimestamp.Time
+		}
+		switch {
+		case child.Status.Phase == statusError:
+			progress.Completed++
+			progress.Failed++
+		case isEvaluationFinished(child):
+			progress.Completed++
+		default:
+			progress.Running++
+			progress.ChildEvaluations = append(progress.ChildEvaluations, arkv1alpha1.ChildEvaluationStatus{
+				Name:  child.Name,
+				Phase: child.Status.Phase,
+			})
+		}
+	}
+	progress.Pending = max(progress.Total-int32(len(children)), 0)
+
+	if progress.Completed > 0 && progress.Completed < progress.Total {
+		elapsed := now.Sub(started)
+		remaining := elapsed * time.Duration(progress.Total-progress.Completed) / time.Duration(progress.Completed)
+		estimate := metav1.NewTime(now.Add(remaining).Truncate(time.Second))
+		progress.EstimatedCompletionTime = &estimate
+	}
+	return progress
+}
+
+func sameBatchProgress(current, progress *arkv1alpha1.BatchEvaluationProgress) bool {
+	if current == nil {
+		return false
+	}
+	currentCounts, progressCounts := *current, *progress
+	currentCounts.EstimatedCompletionTime, progressCounts.EstimatedCompletionTime = nil, nil
+	return equality.Semantic.DeepEqual(currentCounts, progressCounts)
+}
+
+func isEvaluationFinished(evaluation arkv1alpha1.Evaluation) bool {
+	return evaluation.Status.Phase == statusDone || evaluation.Status.Phase == statusError || evaluation.Status.Phase == statusCanceled
+}
+
 func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parentEvaluation arkv1alpha1.Evaluation) error {
 	log := logf.FromContext(ctx)
 
@@ -909,8 +1172,12 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 		TotalTokens:      0,
 	}
 
+	childMetrics := make([]map[string]string, 0, len(childEvaluations.Items))
+
 	// Aggregate results from all children
 	for _, child := range childEvaluations.Items {
+		childMetrics = append(childMetrics, child.Status.Metrics)
+
 		// Count passed/failed
 		if child.Status.Passed {
 			passedTests++
@@ -928,9 +1195,7 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 
 		// Aggregate token usage
 		if child.Status.TokenUsage != nil {
-			aggregatedTokenUsage.PromptTokens += child.Status.TokenUsage.PromptTokens
-			aggregatedTokenUsage.CompletionTokens += child.Status.TokenUsage.CompletionTokens
-			aggregatedTokenUsage.TotalTokens += child.Status.TokenUsage.TotalTokens
+			addTokenUsage(&aggregatedTokenUsage, *child.Status.TokenUsage)
 		}
 	}
 
@@ -948,15 +1213,27 @@ func (r *EvaluationReconciler) aggregateChildResults(ctx context.Context, parent
 	message := fmt.Sprintf("Batch evaluation completed: %d/%d children passed",
 		passedTests, totalTests)
 
-	parentEvaluation.Status.Score = averageScore
-	parentEvaluation.Status.Passed = parentPassed
-	parentEvaluation.Status.Phase = statusDone
-	parentEvaluation.Status.Message = message
-	parentEvaluation.Status.TokenUsage = &aggregatedTokenUsage
+	progress := batchProgress(childEvaluations.Items, totalTests, time.Now())
+
+	// The batch progress was just published, so update the latest version of the parent
+	if err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, client.ObjectKeyFromObject(&parentEvaluation), &parentEvaluation); err != nil {
+			return err
+		}
 
-	r.setConditionCompleted(&parentEvaluation, metav1.ConditionTrue, "EvaluationCompleted", message)
+		parentEvaluation.Status.Score = averageScore
+		parentEvaluation.Status.Passed = parentPassed
+		parentEvaluation.Status.Metrics = genai.AverageMetrics(childMetrics)
+		parentEvaluation.Status.Phase = statusDone
+		parentEvaluation.Status.Message = message
+		parentEvaluation.Status.TokenUsage = &aggregatedTokenUsage
+		parentEvaluation.Status.BatchProgress = progress
+		recordEvaluationRun(&parentEvaluation, metav1.Now())
 
-	if err := r.Status().Update(ctx, &parentEvaluation); err != nil {
+		r.setConditionCompleted(&parentEvaluation, metav1.ConditionTrue, "EvaluationCompleted", message)
+
+		return r.Status().Update(ctx, &parentEvaluation)
+	}); err != nil {
 		log.Error(err, "Failed to update parent evaluation with batch results", "evaluation", parentEvaluation.Name)
 		return err
 	}
@@ -996,13 +1273,10 @@ func (r *EvaluationReconciler) processBaselineEvaluation(ctx context.Context, ev
 	log.Info("Using timeout for baseline evaluation", "evaluation", evaluation.Name, "timeout", timeout)
 
 	// Call unified evaluator endpoint
-	response, err := genai.CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
+	response, err := r.getEvaluators().CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, request, evaluation.Namespace, timeout)
 	if err != nil {
 		log.Error(err, "Failed to call unified evaluator for baseline evaluation", "evaluation", evaluation.Name)
-		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Baseline evaluation failed: %v", err)); err != nil {
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil
+		return r.handleEvaluatorError(ctx, evaluation, "Baseline evaluation failed", err)
 	}
 
 	// Complete evaluation with all results including metadata annotations using atomic update
@@ -1081,13 +1355,10 @@ func (r *EvaluationReconciler) processEventEvaluation(ctx context.Context, evalu
 	log.Info("Using timeout for event evaluation", "evaluation", evaluation.Name, "timeout", timeout)
 
 	// Call the evaluator service
-	response, err := genai.CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, unifiedRequest, evaluation.Namespace, timeout)
+	response, err := r.getEvaluators().CallUnifiedEvaluator(ctx, r.Client, evaluation.Spec.Evaluator, unifiedRequest, evaluation.Namespace, timeout)
 	if err != nil {
 		log.Error(err, "Failed to call evaluator for event evaluation")
-		if err := r.updateStatus(ctx, evaluation, statusError, fmt.Sprintf("Evaluation failed: %v", err)); err != nil {
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil
+		return r.handleEvaluatorError(ctx, evaluation, "Evaluation failed", err)
 	}
 
 	// Prepare status message
@@ -8,6 +8,8 @@ import (
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
@@ -138,6 +140,29 @@ var _ = Describe("EvaluationController Parameter Logic", func() {
 			Expect(paramMap["model.namespace"]).To(Equal("test-namespace"))
 		})
 	})
+
+	Describe("resolveFieldRefParameters", func() {
+		It("should resolve resource field references and drop unresolvable ones", func() {
+			evaluator := &arkv1alpha1.Evaluator{
+				ObjectMeta: metav1.ObjectMeta{Name: "judge", Labels: map[string]string{"tier": "gold"}},
+			}
+			params := []arkv1alpha1.Parameter{
+				{Name: "tokens", Value: "1000"},
+				{Name: "evaluator", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.name"}}},
+				{Name: "tier", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.labels['tier']"}}},
+				// Direct evaluations have no query
+				{Name: "query", ValueFrom: &arkv1alpha1.ValueFromSource{QueryFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.name"}}},
+			}
+
+			resolved := reconciler.resolveFieldRefParameters(context.Background(), arkv1alpha1.Evaluation{}, evaluator, params)
+
+			Expect(resolved).To(Equal([]arkv1alpha1.Parameter{
+				{Name: "tokens", Value: "1000"},
+				{Name: "evaluator", Value: "judge"},
+				{Name: "tier", Value: "gold"},
+			}))
+		})
+	})
 })
 
 // Helper function to find parameter by name
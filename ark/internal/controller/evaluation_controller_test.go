@@ -4,6 +4,7 @@ package controller
 
 import (
 	"context"
+	"time"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -1116,4 +1117,88 @@ var _ = Describe("Evaluation Controller", func() {
 			Expect(k8sClient.Delete(ctx, evaluator)).Should(Succeed())
 		})
 	})
+
+	Context("When running a batch evaluation", func() {
+		It("Should run at most maxConcurrency children at a time", func() {
+			parent := arkv1alpha1.Evaluation{
+				ObjectMeta: metav1.ObjectMeta{Name: "batch"},
+				Spec: arkv1alpha1.EvaluationSpec{
+					Type: "batch",
+					Config: arkv1alpha1.EvaluationConfig{
+						BatchEvaluationConfig: &arkv1alpha1.BatchEvaluationConfig{
+							Evaluations:    []arkv1alpha1.EvaluationRef{{Name: "a"}, {Name: "b"}, {Name: "c"}, {Name: "d"}},
+							MaxConcurrency: 2,
+						},
+					},
+				},
+			}
+			child := func(index int, phase string) arkv1alpha1.Evaluation {
+				return arkv1alpha1.Evaluation{
+					ObjectMeta: metav1.ObjectMeta{Name: childEvaluationName(parent, index)},
+					Status:     arkv1alpha1.EvaluationStatus{Phase: phase},
+				}
+			}
+
+			Expect(childEvaluationsToCreate(parent, nil)).To(Equal([]int{0, 1}))
+			Expect(childEvaluationsToCreate(parent, []arkv1alpha1.Evaluation{child(0, statusRunning), child(1, "")})).To(BeEmpty())
+			Expect(childEvaluationsToCreate(parent, []arkv1alpha1.Evaluation{child(0, statusDone), child(1, statusRunning)})).To(Equal([]int{2}))
+			Expect(childEvaluationsToCreate(parent, []arkv1alpha1.Evaluation{child(0, statusDone), child(1, statusError)})).To(Equal([]int{2, 3}))
+
+			parent.Spec.Config.MaxConcurrency = 0
+			Expect(childEvaluationsToCreate(parent, []arkv1alpha1.Evaluation{child(1, statusRunning)})).To(Equal([]int{0, 2, 3}))
+		})
+
+		It("Should estimate completion from the children completed so far", func() {
+			start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+			child := func(name, phase string) arkv1alpha1.Evaluation {
+				return arkv1alpha1.Evaluation{
+					ObjectMeta: metav1.ObjectMeta{Name: name, CreationTimestamp: metav1.NewTime(start)},
+					Status:     arkv1alpha1.EvaluationStatus{Phase: phase},
+				}
+			}
+			children := []arkv1alpha1.Evaluation{child("c0", statusDone), child("c1", statusError), child("c2", statusRunning)}
+
+			progress := batchProgress(children, 4, start.Add(10*time.Minute))
+			Expect(progress.Completed).To(Equal(int32(2)))
+			Expect(progress.Failed).To(Equal(int32(1)))
+			Expect(progress.Running).To(Equal(int32(1)))
+			Expect(progress.Pending).To(Equal(int32(1)))
+			Expect(progress.ChildEvaluations).To(ConsistOf(arkv1alpha1.ChildEvaluationStatus{Name: "c2", Phase: statusRunning}))
+			Expect(progress.EstimatedCompletionTime.Time).To(Equal(start.Add(20 * time.Minute)))
+
+			later := batchProgress(children, 4, start.Add(11*time.Minute))
+			Expect(sameBatchProgress(progress, later)).To(BeTrue())
+			Expect(batchProgress(children, 4, start).EstimatedCompletionTime).NotTo(BeNil())
+			Expect(batchProgress(children[2:], 4, start).EstimatedCompletionTime).To(BeNil())
+		})
+	})
+
+	Context("When an evaluation completes", func() {
+		It("Should keep the most recent runs up to the history limit", func() {
+			start := time.Date(2025, 1, 1, 10, 0, 0, 0, time.UTC)
+			limit := int32(2)
+			evaluation := arkv1alpha1.Evaluation{Spec: arkv1alpha1.EvaluationSpec{HistoryLimit: &limit}}
+			for i, score := range []string{"0.5", "0.6", "0.7"} {
+				evaluation.Status.Score = score
+				evaluation.Status.Passed = i > 0
+				evaluation.Status.TokenUsage = &arkv1alpha1.TokenUsage{TotalTokens: int64(i)}
+				recordEvaluationRun(&evaluation, metav1.NewTime(start.Add(time.Duration(i)*time.Hour)))
+			}
+
+			Expect(evaluation.Status.History).To(HaveLen(2))
+			Expect(evaluation.Status.History[0].Score).To(Equal("0.6"))
+			Expect(evaluation.Status.History[1].Score).To(Equal("0.7"))
+			Expect(evaluation.Status.History[1].Passed).To(BeTrue())
+			Expect(evaluation.Status.History[1].TokenUsage.TotalTokens).To(Equal(int64(2)))
+			Expect(evaluation.Status.History[1].CompletedAt.Time).To(Equal(start.Add(2 * time.Hour)))
+
+			limit = 0
+			recordEvaluationRun(&evaluation, metav1.NewTime(start))
+			Expect(evaluation.Status.History).To(BeNil())
+
+			evaluation.Spec.HistoryLimit = nil
+			recordEvaluationRun(&evaluation, metav1.NewTime(start))
+			Expect(evaluation.Status.History).To(HaveLen(1))
+		})
+	})
 })
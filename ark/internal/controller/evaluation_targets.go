@@ -0,0 +1,117 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+)
+
+// publishEvaluationResult sets the LastEvaluationPassed condition on the query of a completed query
+// evaluation and on the agents that answered it, and exports the result of the agents as metrics.
+// Failures are logged, the evaluation itself has completed.
+func (r *EvaluationReconciler) publishEvaluationResult(ctx context.Context, evaluation *arkv1alpha1.Evaluation, response *genai.EvaluationResponse, completedAt metav1.Time) {
+	config := evaluation.Spec.Config.QueryBasedEvaluationConfig
+	if config == nil || config.QueryRef == nil {
+		return
+	}
+	log := logf.FromContext(ctx)
+	condition := lastEvaluationCondition(evaluation, response, completedAt)
+
+	queryKey := client.ObjectKey{Name: config.QueryRef.Name, Namespace: config.QueryRef.Namespace}
+	if queryKey.Namespace == "" {
+		queryKey.Namespace = evaluation.Namespace
+	}
+	var query arkv1alpha1.Query
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if err := r.Get(ctx, queryKey, &query); err != nil {
+			return err
+		}
+		setLastEvaluationCondition(&query.Status.Conditions, condition)
+		return r.Status().Update(ctx, &query)
+	})
+	if err != nil {
+		log.Error(err, "failed to set evaluation condition on query", "evaluation", evaluation.Name, "query", queryKey.Name)
+		if query.Name == "" {
+			return
+		}
+	}
+
+	score, scoreErr := strconv.ParseFloat(response.Score, 64)
+	for _, agentKey := range evaluatedAgents(&query, config.QueryRef.ResponseTarget) {
+		metrics.SetAgentEvaluationPassed(agentKey.Name, evaluation.Spec.Evaluator.Name, agentKey.Namespace, response.Passed)
+		if scoreErr == nil {
+			metrics.SetAgentEvaluationScore(agentKey.Name, evaluation.Spec.Evaluator.Name, agentKey.Namespace, score)
+		}
+
+		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+			var agent arkv1alpha1.Agent
+			if err := r.Get(ctx, agentKey, &agent); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			setLastEvaluationCondition(&agent.Status.Conditions, condition)
+			return r.Status().Update(ctx, &agent)
+		})
+		if err != nil {
+			log.Error(err, "failed to set evaluation condition on agent", "evaluation", evaluation.Name, "agent", agentKey.Name)
+		}
+	}
+}
+
+func lastEvaluationCondition(evaluation *arkv1alpha1.Evaluation, response *genai.EvaluationResponse, completedAt metav1.Time) metav1.Condition {
+	condition := metav1.Condition{
+		Type:               arkv1alpha1.LastEvaluationPassed,
+		Status:             metav1.ConditionTrue,
+		Reason:             "EvaluationPassed",
+		LastTransitionTime: completedAt,
+	}
+	if !response.Passed {
+		condition.Status = metav1.ConditionFalse
+		condition.Reason = "EvaluationFailed"
+	}
+	condition.Message = fmt.Sprintf("Evaluation %s by evaluator %s scored %s", evaluation.Name, evaluation.Spec.Evaluator.Name, response.Score)
+	if response.Score == "" {
+		condition.Message = fmt.Sprintf("Evaluation %s by evaluator %s reported no score", evaluation.Name, evaluation.Spec.Evaluator.Name)
+	}
+	return condition
+}
+
+// setLastEvaluationCondition replaces the condition, so its transition time is the completion time
+// of the last evaluation even when the result did not change
+func setLastEvaluationCondition(conditions *[]metav1.Condition, condition metav1.Condition) {
+	meta.RemoveStatusCondition(conditions, condition.Type)
+	meta.SetStatusCondition(conditions, condition)
+}
+
+// evaluatedAgents returns the agents whose responses to the query were evaluated, which are those
+// matching responseTarget when set
+func evaluatedAgents(query *arkv1alpha1.Query, responseTarget string) []client.ObjectKey {
+	var agents []client.ObjectKey
+	seen := map[client.ObjectKey]bool{}
+	for _, response := range query.Status.Responses {
+		target := response.Target
+		if target.Type != "agent" || target.Cluster != "" || (responseTarget != "" && target.Name != responseTarget) {
+			continue
+		}
+		key := client.ObjectKey{Name: target.Name, Namespace: target.Namespace}
+		if key.Namespace == "" {
+			key.Namespace = query.Namespace
+		}
+		if !seen[key] {
+			seen[key] = true
+			agents = append(agents, key)
+		}
+	}
+	return agents
+}
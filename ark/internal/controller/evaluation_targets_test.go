@@ -0,0 +1,117 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+)
+
+var _ = Describe("Evaluation Result Publishing", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		reconciler *EvaluationReconciler
+		evaluation *arkv1alpha1.Evaluation
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "eval-publish"},
+			Status: arkv1alpha1.QueryStatus{Responses: []arkv1alpha1.Response{
+				{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}},
+				{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "summarizer"}},
+				{Target: arkv1alpha1.QueryTarget{Type: "team", Name: "planners"}},
+			}},
+		}
+		weather := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "eval-publish"},
+			Status: arkv1alpha1.AgentStatus{Conditions: []metav1.Condition{
+				{Type: AgentAvailable, Status: metav1.ConditionTrue, Reason: "Available", LastTransitionTime: metav1.Now()},
+			}},
+		}
+		summarizer := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "summarizer", Namespace: "eval-publish"}}
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(query, weather, summarizer).
+			WithStatusSubresource(query, weather, summarizer).Build()
+		reconciler = &EvaluationReconciler{Client: fakeClient}
+
+		evaluation = &arkv1alpha1.Evaluation{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast-quality", Namespace: "eval-publish"},
+			Spec: arkv1alpha1.EvaluationSpec{
+				Type:      "query",
+				Evaluator: arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"},
+				Config: arkv1alpha1.EvaluationConfig{QueryBasedEvaluationConfig: &arkv1alpha1.QueryBasedEvaluationConfig{
+					QueryRef: &arkv1alpha1.QueryRef{Name: "forecast", ResponseTarget: "weather"},
+				}},
+			},
+		}
+	})
+
+	lastEvaluation := func(obj client.Object, conditions func() []metav1.Condition) *metav1.Condition {
+		Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(obj), obj)).To(Succeed())
+		return meta.FindStatusCondition(conditions(), arkv1alpha1.LastEvaluationPassed)
+	}
+
+	It("should set the condition on the query and the evaluated agent and export the score", func() {
+		completedAt := metav1.NewTime(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+		reconciler.publishEvaluationResult(ctx, evaluation, &genai.EvaluationResponse{Score: "0.35", Passed: false}, completedAt)
+
+		query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "eval-publish"}}
+		condition := lastEvaluation(query, func() []metav1.Condition { return query.Status.Conditions })
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("EvaluationFailed"))
+		Expect(condition.Message).To(Equal("Evaluation forecast-quality by evaluator judge scored 0.35"))
+		Expect(condition.LastTransitionTime.Equal(&completedAt)).To(BeTrue())
+
+		weather := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "eval-publish"}}
+		Expect(lastEvaluation(weather, func() []metav1.Condition { return weather.Status.Conditions }).Status).To(Equal(metav1.ConditionFalse))
+		Expect(meta.FindStatusCondition(weather.Status.Conditions, AgentAvailable)).NotTo(BeNil())
+
+		summarizer := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "summarizer", Namespace: "eval-publish"}}
+		Expect(lastEvaluation(summarizer, func() []metav1.Condition { return summarizer.Status.Conditions })).To(BeNil())
+
+		Expect(testutil.ToFloat64(metrics.AgentEvaluationScore.WithLabelValues("weather", "judge", "eval-publish"))).To(Equal(0.35))
+		Expect(testutil.ToFloat64(metrics.AgentEvaluationPassed.WithLabelValues("weather", "judge", "eval-publish"))).To(Equal(float64(0)))
+	})
+
+	It("should move the transition time to the latest evaluation when the result is unchanged", func() {
+		evaluation.Spec.Config.QueryRef.ResponseTarget = ""
+		first := metav1.NewTime(time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC))
+		second := metav1.NewTime(first.Add(time.Hour))
+		reconciler.publishEvaluationResult(ctx, evaluation, &genai.EvaluationResponse{Score: "0.9", Passed: true}, first)
+		reconciler.publishEvaluationResult(ctx, evaluation, &genai.EvaluationResponse{Score: "0.95", Passed: true}, second)
+
+		summarizer := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "summarizer", Namespace: "eval-publish"}}
+		condition := lastEvaluation(summarizer, func() []metav1.Condition { return summarizer.Status.Conditions })
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Message).To(HaveSuffix("scored 0.95"))
+		Expect(condition.LastTransitionTime.Equal(&second)).To(BeTrue())
+		Expect(testutil.ToFloat64(metrics.AgentEvaluationPassed.WithLabelValues("summarizer", "judge", "eval-publish"))).To(Equal(float64(1)))
+	})
+
+	It("should not publish evaluations without a query", func() {
+		evaluation.Spec.Config = arkv1alpha1.EvaluationConfig{DirectEvaluationConfig: &arkv1alpha1.DirectEvaluationConfig{Input: "hi", Output: "hello"}}
+		reconciler.publishEvaluationResult(ctx, evaluation, &genai.EvaluationResponse{Score: "1", Passed: true}, metav1.Now())
+
+		query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "eval-publish"}}
+		Expect(lastEvaluation(query, func() []metav1.Condition { return query.Status.Conditions })).To(BeNil())
+	})
+})
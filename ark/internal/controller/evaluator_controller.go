@@ -4,7 +4,10 @@ package controller
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
@@ -12,6 +15,7 @@ import (
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/retry"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -23,8 +27,12 @@ import (
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/annotations"
 	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/genai"
 )
 
+// evaluatorFieldOwner is the field manager of the evaluations applied by evaluators
+const evaluatorFieldOwner = "ark-evaluator"
+
 // EvaluatorReconciler reconciles an Evaluator object
 type EvaluatorReconciler struct {
 	client.Client
@@ -60,7 +68,7 @@ func (r *EvaluatorReconciler) Reconcile(ctx context.Context, req ctrl.Request) (
 	switch evaluator.Status.Phase {
 	case statusReady:
 		// For ready evaluators with selectors, process selector logic
-		if evaluator.Spec.Selector != nil {
+		if r.selectsQueries(ctx, &evaluator) {
 			if err := r.processEvaluatorWithSelector(ctx, &evaluator); err != nil {
 				log.Error(err, "failed to process evaluator selector in ready state", "evaluator", evaluator.Name)
 				return ctrl.Result{}, err
@@ -112,8 +120,22 @@ func (r *EvaluatorReconciler) processEvaluator(ctx context.Context, evaluator *a
 		return ctrl.Result{}, nil
 	}
 
+	// Check the auth and TLS references, so evaluations do not fail on them later
+	if err := genai.ValidateEvaluatorCredentials(ctx, r.Client, evaluator); err != nil {
+		log.Error(err, "failed to resolve Evaluator credentials", "evaluator", evaluator.Name)
+		r.Recorder.Event(evaluator, corev1.EventTypeWarning, "InvalidCredentials", err.Error())
+		if err := r.updateStatusAtomic(ctx, client.ObjectKeyFromObject(evaluator), func(e *arkv1alpha1.Evaluator) {
+			e.Status.Phase = statusError
+			e.Status.Message = fmt.Sprintf("Failed to resolve credentials: %v", err)
+			e.Status.LastResolvedAddress = resolvedAddress
+		}); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{}, nil
+	}
+
 	// If evaluator has selector, process matching queries
-	if evaluator.Spec.Selector != nil {
+	if r.selectsQueries(ctx, evaluator) {
 		if err := r.processEvaluatorWithSelector(ctx, evaluator); err != nil {
 			log.Error(err, "failed to process evaluator with selector", "evaluator", evaluator.Name)
 			// Atomic update for error state
@@ -271,9 +293,16 @@ func (r *EvaluatorReconciler) findEvaluatorsForQuery(ctx context.Context, obj cl
 		return nil
 	}
 
+	defaults, err := genai.GetNamespaceDefaults(ctx, r.Client, query.Namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to get namespace default evaluators", "namespace", query.Namespace)
+		defaults = &genai.NamespaceDefaults{}
+	}
+
 	var requests []reconcile.Request
 	for _, evaluator := range evaluators.Items {
-		if r.queryMatchesEvaluator(query, &evaluator) {
+		isDefault := evaluator.Spec.Selector == nil && defaults.IsDefaultEvaluator(evaluator.Name)
+		if isDefault || r.queryMatchesEvaluator(query, &evaluator) {
 			requests = append(requests, reconcile.Request{
 				NamespacedName: types.NamespacedName{
 					Name:      evaluator.Name,
@@ -312,6 +341,20 @@ func (r *EvaluatorReconciler) queryMatchesEvaluator(query *arkv1alpha1.Query, ev
 	return selectorObj.Matches(labels.Set(query.Labels))
 }
 
+// selectsQueries reports whether the evaluator evaluates queries automatically, either with its own
+// selector or, without one, as a default evaluator of its namespace
+func (r *EvaluatorReconciler) selectsQueries(ctx context.Context, evaluator *arkv1alpha1.Evaluator) bool {
+	if evaluator.Spec.Selector != nil {
+		return true
+	}
+	defaults, err := genai.GetNamespaceDefaults(ctx, r.Client, evaluator.Namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to get namespace default evaluators", "evaluator", evaluator.Name)
+		return false
+	}
+	return defaults.IsDefaultEvaluator(evaluator.Name)
+}
+
 // processEvaluatorWithSelector handles selector-based evaluation logic
 func (r *EvaluatorReconciler) processEvaluatorWithSelector(ctx context.Context, evaluator *arkv1alpha1.Evaluator) error {
 	log := logf.FromContext(ctx)
@@ -338,9 +381,17 @@ func (r *EvaluatorReconciler) processEvaluatorWithSelector(ctx context.Context,
 	return nil
 }
 
-// findMatchingQueries finds queries that match the evaluator's selector
+// findMatchingQueries finds queries that match the evaluator's selector. A default evaluator without
+// a selector matches every query in its namespace.
 func (r *EvaluatorReconciler) findMatchingQueries(ctx context.Context, evaluator *arkv1alpha1.Evaluator) ([]arkv1alpha1.Query, error) {
 	selector := evaluator.Spec.Selector
+	if selector == nil {
+		var queries arkv1alpha1.QueryList
+		if err := r.List(ctx, &queries, client.InNamespace(evaluator.Namespace)); err != nil {
+			return nil, err
+		}
+		return queries.Items, nil
+	}
 
 	// Build label selector
 	labelSelector := &metav1.LabelSelector{
@@ -366,12 +417,14 @@ func (r *EvaluatorReconciler) findMatchingQueries(ctx context.Context, evaluator
 	return queries.Items, nil
 }
 
-// createEvaluationForQuery creates an evaluation for a specific query
+// createEvaluationForQuery creates the evaluation of a query by the evaluator, or retriggers it when
+// the query changed. The evaluation has a deterministic name and is created with server-side apply,
+// so reconciles racing after a controller restart converge on one evaluation instead of conflicting.
 func (r *EvaluatorReconciler) createEvaluationForQuery(ctx context.Context, evaluator *arkv1alpha1.Evaluator, query *arkv1alpha1.Query) error {
 	log := logf.FromContext(ctx)
 
 	// Check if evaluation already exists
-	evaluationName := fmt.Sprintf("%s-%s-eval", evaluator.Name, query.Name)
+	evaluationName := autoEvaluationName(evaluator.Name, query.Name)
 
 	var existingEval arkv1alpha1.Evaluation
 	evalKey := client.ObjectKey{Name: evaluationName, Namespace: evaluator.Namespace}
@@ -383,6 +436,8 @@ func (r *EvaluatorReconciler) createEvaluationForQuery(ctx context.Context, eval
 		}
 		log.Info("Evaluation already exists and is up to date", "evaluation", evaluationName)
 		return nil // Already evaluated
+	} else if !errors.IsNotFound(err) {
+		return fmt.Errorf("failed to get evaluation %s: %w", evaluationName, err)
 	}
 
 	// Resolve parameters
@@ -393,6 +448,10 @@ func (r *EvaluatorReconciler) createEvaluationForQuery(ctx context.Context, eval
 
 	// Create new evaluation
 	evaluation := &arkv1alpha1.Evaluation{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: arkv1alpha1.GroupVersion.String(),
+			Kind:       "Evaluation",
+		},
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      evaluationName,
 			Namespace: evaluator.Namespace,
@@ -425,8 +484,22 @@ func (r *EvaluatorReconciler) createEvaluationForQuery(ctx context.Context, eval
 		},
 	}
 
+	// Applying instead of creating makes concurrent creations of the same evaluation succeed. Without
+	// forced ownership, an apply that would change fields set by another manager fails with a conflict.
 	log.Info("Creating evaluation for query", "evaluation", evaluationName, "query", query.Name)
-	return r.Create(ctx, evaluation)
+	return r.Patch(ctx, evaluation, client.Apply, client.FieldOwner(evaluatorFieldOwner))
+}
+
+// autoEvaluationName returns the deterministic name of the evaluation of a query by an evaluator.
+// Names longer than the limit of object names are shortened and made unique with a hash.
+func autoEvaluationName(evaluatorName, queryName string) string {
+	name := fmt.Sprintf("%s-%s-eval", evaluatorName, queryName)
+	if len(name) <= validation.DNS1123SubdomainMaxLength {
+		return name
+	}
+	sum := sha256.Sum256([]byte(evaluatorName + "/" + queryName))
+	suffix := "-" + hex.EncodeToString(sum[:8]) + "-eval"
+	return strings.TrimRight(name[:validation.DNS1123SubdomainMaxLength-len(suffix)], "-.") + suffix
 }
 
 // shouldRetriggerEvaluation checks if evaluation should be retriggered based on query changes
@@ -446,50 +519,83 @@ func (r *EvaluatorReconciler) shouldRetriggerEvaluation(evaluation *arkv1alpha1.
 	return currentPhase == "done" && currentPhase != lastPhase
 }
 
-// updateEvaluationForQuery updates an existing evaluation to retrigger evaluation
+// updateEvaluationForQuery retriggers an evaluation. Updates are conditional on the resourceVersion
+// the retrigger was decided on: after a conflict the evaluation is read again and only retriggered
+// when it is still out of date, so concurrent reconciles retrigger it once.
 func (r *EvaluatorReconciler) updateEvaluationForQuery(ctx context.Context, evaluation *arkv1alpha1.Evaluation, evaluator *arkv1alpha1.Evaluator, query *arkv1alpha1.Query) error {
 	log := logf.FromContext(ctx)
+	evalKey := client.ObjectKeyFromObject(evaluation)
 
-	return retry.RetryOnConflict(retry.DefaultRetry, func() error {
-		// Get fresh copy
-		var currentEval arkv1alpha1.Evaluation
-		evalKey := client.ObjectKeyFromObject(evaluation)
-		if err := r.Get(ctx, evalKey, &currentEval); err != nil {
-			return err
+	parameters, err := r.resolveEvaluatorParameters(ctx, evaluator.Spec.Parameters, evaluator.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve parameters: %w", err)
+	}
+
+	currentEval := evaluation.DeepCopy()
+	retriggered := false
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if currentEval == nil {
+			currentEval = &arkv1alpha1.Evaluation{}
+			if err := r.Get(ctx, evalKey, currentEval); err != nil {
+				return err
+			}
+		}
+		if !r.shouldRetriggerEvaluation(currentEval, query) {
+			return nil
 		}
 
-		// Update annotations
 		if currentEval.Annotations == nil {
 			currentEval.Annotations = make(map[string]string)
 		}
 		currentEval.Annotations[annotations.QueryGeneration] = fmt.Sprintf("%d", query.Generation)
 		currentEval.Annotations[annotations.QueryPhase] = query.Status.Phase
-
-		// Resolve and update parameters
-		parameters, err := r.resolveEvaluatorParameters(ctx, evaluator.Spec.Parameters, evaluator.Namespace)
-		if err != nil {
-			return fmt.Errorf("failed to resolve parameters: %w", err)
-		}
 		currentEval.Spec.Evaluator.Parameters = parameters
 
-		// Update main object first
-		if err := r.Update(ctx, &currentEval); err != nil {
+		if err := r.Update(ctx, currentEval); err != nil {
+			currentEval = nil
 			return err
 		}
+		retriggered = true
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if !retriggered {
+		log.Info("Evaluation was already retriggered", "evaluation", evalKey.Name, "query", query.Name)
+		return nil
+	}
+
+	// Reset the status to run the evaluation again, keeping the results of earlier runs. After a
+	// conflict the status is only reset while the evaluation has not started running again.
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		if currentEval == nil {
+			currentEval = &arkv1alpha1.Evaluation{}
+			if err := r.Get(ctx, evalKey, currentEval); err != nil {
+				return err
+			}
+			if !isEvaluationFinished(*currentEval) {
+				return nil
+			}
+		}
 
-		// Then atomically reset status to trigger re-evaluation
 		currentEval.Status = arkv1alpha1.EvaluationStatus{
 			Phase:   "",
 			Message: "",
 			Score:   "",
 			Passed:  false,
+			History: currentEval.Status.History,
 		}
-
-		if err := r.Status().Update(ctx, &currentEval); err != nil {
+		if err := r.Status().Update(ctx, currentEval); err != nil {
+			currentEval = nil
 			return err
 		}
-
-		log.Info("Updated evaluation for retriggering", "evaluation", currentEval.Name, "query", query.Name)
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+
+	log.Info("Updated evaluation for retriggering", "evaluation", evalKey.Name, "query", query.Name)
+	return nil
 }
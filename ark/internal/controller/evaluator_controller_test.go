@@ -4,16 +4,29 @@ package controller
 
 import (
 	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/serializer"
 	"k8s.io/apimachinery/pkg/types"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
 )
 
 var _ = Describe("Evaluator Controller", func() {
@@ -67,4 +80,176 @@ var _ = Describe("Evaluator Controller", func() {
 			// Example: If you expect a certain status condition after reconciliation, verify it here.
 		})
 	})
+
+	Context("When the credentials of an evaluator do not resolve", func() {
+		It("Should set the evaluator to error", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			evaluator := &arkv1alpha1.Evaluator{
+				ObjectMeta: metav1.ObjectMeta{Name: "judge", Namespace: "default"},
+				Spec: arkv1alpha1.EvaluatorSpec{
+					Address: arkv1alpha1.ValueSource{Value: "https://judge.internal/evaluate"},
+					Auth: &arkv1alpha1.EvaluatorAuth{BearerToken: &arkv1alpha1.ValueSource{ValueFrom: &arkv1alpha1.ValueFromSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "judge-token"}, Key: "token"},
+					}}},
+				},
+				Status: arkv1alpha1.EvaluatorStatus{Phase: statusRunning},
+			}
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(evaluator).WithStatusSubresource(evaluator).Build()
+			recorder := record.NewFakeRecorder(10)
+			reconciler := &EvaluatorReconciler{Client: fakeClient, Scheme: scheme, Recorder: recorder}
+
+			_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Name: "judge", Namespace: "default"}})
+			Expect(err).NotTo(HaveOccurred())
+
+			updated := &arkv1alpha1.Evaluator{}
+			Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "judge", Namespace: "default"}, updated)).To(Succeed())
+			Expect(updated.Status.Phase).To(Equal(statusError))
+			Expect(updated.Status.Message).To(ContainSubstring("Failed to resolve credentials"))
+			Expect(recorder.Events).To(Receive(ContainSubstring("InvalidCredentials")))
+		})
+	})
+
+	Context("When evaluators are namespace defaults", func() {
+		It("Should evaluate every query in the namespace without a selector", func() {
+			ctx := context.Background()
+			scheme := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+			Expect(corev1.AddToScheme(scheme)).To(Succeed())
+			fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+				&corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "team-a"},
+					Data:       map[string]string{"defaultEvaluators": "quality"},
+				},
+				&arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "team-a"}},
+				&arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q2", Namespace: "team-a", Labels: map[string]string{"evaluate": "true"}}},
+				&arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q3", Namespace: "team-b"}},
+			).Build()
+			reconciler := &EvaluatorReconciler{Client: fakeClient, Scheme: scheme}
+
+			quality := &arkv1alpha1.Evaluator{ObjectMeta: metav1.ObjectMeta{Name: "quality", Namespace: "team-a"}}
+			Expect(reconciler.selectsQueries(ctx, quality)).To(BeTrue())
+			queries, err := reconciler.findMatchingQueries(ctx, quality)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queries).To(HaveLen(2))
+
+			Expect(reconciler.selectsQueries(ctx, &arkv1alpha1.Evaluator{ObjectMeta: metav1.ObjectMeta{Name: "latency", Namespace: "team-a"}})).To(BeFalse())
+			Expect(reconciler.selectsQueries(ctx, &arkv1alpha1.Evaluator{ObjectMeta: metav1.ObjectMeta{Name: "quality", Namespace: "team-b"}})).To(BeFalse())
+
+			quality.Spec.Selector = &arkv1alpha1.ResourceSelector{
+				ResourceType:  "Query",
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"evaluate": "true"}},
+			}
+			queries, err = reconciler.findMatchingQueries(ctx, quality)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(queries).To(HaveLen(1))
+			Expect(queries[0].Name).To(Equal("q2"))
+		})
+	})
+
+	Context("When evaluator reconciles race", func() {
+		var (
+			ctx           context.Context
+			fakeClient    client.Client
+			reconciler    *EvaluatorReconciler
+			evaluator     *arkv1alpha1.Evaluator
+			query         *arkv1alpha1.Query
+			statusUpdates atomic.Int32
+		)
+
+		BeforeEach(func() {
+			ctx = context.Background()
+			scheme := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(scheme)).To(Succeed())
+			statusUpdates.Store(0)
+			// The field managed tracker of the fake client cannot handle the inline config of
+			// evaluations, so a plain tracker stores them and server-side applies of the same
+			// evaluation by the same field owner are emulated: the first creates it, the others
+			// leave it unchanged.
+			tracker := clienttesting.NewObjectTracker(scheme, serializer.NewCodecFactory(scheme).UniversalDecoder())
+			fakeClient = fake.NewClientBuilder().WithScheme(scheme).WithObjectTracker(tracker).
+				WithStatusSubresource(&arkv1alpha1.Evaluation{}).
+				WithInterceptorFuncs(interceptor.Funcs{
+					Patch: func(ctx context.Context, c client.WithWatch, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+						if patch.Type() != types.ApplyPatchType {
+							return c.Patch(ctx, obj, patch, opts...)
+						}
+						return client.IgnoreAlreadyExists(c.Create(ctx, obj))
+					},
+					SubResourceUpdate: func(ctx context.Context, c client.Client, subResourceName string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+						statusUpdates.Add(1)
+						return c.SubResource(subResourceName).Update(ctx, obj, opts...)
+					},
+				}).Build()
+			reconciler = &EvaluatorReconciler{Client: fakeClient, Scheme: scheme}
+			evaluator = &arkv1alpha1.Evaluator{ObjectMeta: metav1.ObjectMeta{Name: "quality", Namespace: "default"}}
+			query = &arkv1alpha1.Query{
+				ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", Generation: 1},
+				Status:     arkv1alpha1.QueryStatus{Phase: statusDone},
+			}
+		})
+
+		// evaluateConcurrently runs createEvaluationForQuery as concurrent reconciles do after a restart
+		evaluateConcurrently := func() {
+			var wg sync.WaitGroup
+			errs := make(chan error, 10)
+			for range 10 {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					defer GinkgoRecover()
+					errs <- reconciler.createEvaluationForQuery(ctx, evaluator, query.DeepCopy())
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				Expect(err).NotTo(HaveOccurred())
+			}
+		}
+
+		It("Should create one evaluation without conflicts", func() {
+			evaluateConcurrently()
+
+			var evaluations arkv1alpha1.EvaluationList
+			Expect(fakeClient.List(ctx, &evaluations)).To(Succeed())
+			Expect(evaluations.Items).To(HaveLen(1))
+			evaluation := evaluations.Items[0]
+			Expect(evaluation.Name).To(Equal("quality-weather-eval"))
+			Expect(evaluation.Annotations).To(HaveKeyWithValue(annotations.QueryGeneration, "1"))
+			Expect(evaluation.Spec.Config.QueryRef.Name).To(Equal("weather"))
+			Expect(statusUpdates.Load()).To(BeZero())
+		})
+
+		It("Should retrigger a finished evaluation once", func() {
+			Expect(reconciler.createEvaluationForQuery(ctx, evaluator, query)).To(Succeed())
+			var evaluation arkv1alpha1.Evaluation
+			key := types.NamespacedName{Name: "quality-weather-eval", Namespace: "default"}
+			Expect(fakeClient.Get(ctx, key, &evaluation)).To(Succeed())
+			evaluation.Status = arkv1alpha1.EvaluationStatus{Phase: statusDone, Score: "0.8", Passed: true}
+			Expect(fakeClient.Status().Update(ctx, &evaluation)).To(Succeed())
+			statusUpdates.Store(0)
+
+			query.Generation = 2
+			evaluateConcurrently()
+
+			Expect(statusUpdates.Load()).To(Equal(int32(1)))
+			Expect(fakeClient.Get(ctx, key, &evaluation)).To(Succeed())
+			Expect(evaluation.Annotations).To(HaveKeyWithValue(annotations.QueryGeneration, "2"))
+			Expect(evaluation.Status.Phase).To(BeEmpty())
+			Expect(evaluation.Status.Score).To(BeEmpty())
+		})
+
+		It("Should shorten long evaluation names deterministically", func() {
+			evaluatorName := strings.Repeat("e", 200)
+			queryName := strings.Repeat("q", 200)
+			name := autoEvaluationName(evaluatorName, queryName)
+			Expect(len(name)).To(BeNumerically("<=", 253))
+			Expect(name).To(HaveSuffix("-eval"))
+			Expect(autoEvaluationName(evaluatorName, queryName)).To(Equal(name))
+			Expect(autoEvaluationName(evaluatorName, queryName+"x")).NotTo(Equal(name))
+		})
+	})
 })
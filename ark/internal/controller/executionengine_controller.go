@@ -5,9 +5,12 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -16,6 +19,7 @@ import (
 
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
 	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/genai"
 )
 
 // ExecutionEngineReconciler reconciles an ExecutionEngine object
@@ -47,17 +51,7 @@ func (r *ExecutionEngineReconciler) Reconcile(ctx context.Context, req ctrl.Requ
 		return ctrl.Result{}, err
 	}
 
-	switch executionEngine.Status.Phase {
-	case statusReady, statusError:
-		return ctrl.Result{}, nil
-	case statusRunning:
-		return r.processExecutionEngine(ctx, executionEngine)
-	default:
-		if err := r.updateStatus(ctx, executionEngine, statusRunning, "Resolving execution engine address"); err != nil {
-			return ctrl.Result{}, err
-		}
-		return ctrl.Result{}, nil
-	}
+	return r.processExecutionEngine(ctx, executionEngine)
 }
 
 func (r *ExecutionEngineReconciler) getResolver() *common.ValueSourceResolverV1PreAlpha1 {
@@ -75,22 +69,60 @@ func (r *ExecutionEngineReconciler) processExecutionEngine(ctx context.Context,
 	resolvedAddress, err := resolver.ResolveValueSource(ctx, executionEngine.Spec.Address, executionEngine.Namespace)
 	if err != nil {
 		log.Error(err, "failed to resolve ExecutionEngine address", "executionEngine", executionEngine.Name)
-		r.Recorder.Event(&executionEngine, corev1.EventTypeWarning, "AddressResolutionFailed", fmt.Sprintf("Failed to resolve address: %v", err))
-		if err := r.updateStatus(ctx, executionEngine, statusError, fmt.Sprintf("Failed to resolve address: %v", err)); err != nil {
+		message := fmt.Sprintf("Failed to resolve address: %v", err)
+		if r.setAvailableCondition(&executionEngine, metav1.ConditionFalse, "AddressResolutionFailed", message) {
+			r.Recorder.Event(&executionEngine, corev1.EventTypeWarning, "AddressResolutionFailed", message)
+		}
+		if err := r.updateStatus(ctx, executionEngine, statusError, message); err != nil {
 			return ctrl.Result{}, err
 		}
-		return ctrl.Result{}, nil
+		return ctrl.Result{RequeueAfter: r.pollInterval(executionEngine)}, nil
 	}
 
 	executionEngine.Status.LastResolvedAddress = resolvedAddress
 
-	if err := r.updateStatus(ctx, executionEngine, statusReady, "ExecutionEngine address resolved successfully"); err != nil {
+	if err := genai.ProbeExecutionEngine(ctx, r.Client, resolvedAddress, executionEngine.Spec.Headers, executionEngine.Namespace); err != nil {
+		log.Info("ExecutionEngine health check failed", "executionEngine", executionEngine.Name, "address", resolvedAddress, "error", err.Error())
+		message := fmt.Sprintf("Health check failed: %v", err)
+		if r.setAvailableCondition(&executionEngine, metav1.ConditionFalse, "HealthCheckFailed", message) {
+			r.Recorder.Event(&executionEngine, corev1.EventTypeWarning, "HealthCheckFailed", message)
+		}
+		if err := r.updateStatus(ctx, executionEngine, statusError, message); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: r.pollInterval(executionEngine)}, nil
+	}
+
+	message := fmt.Sprintf("Execution engine is available at %s", resolvedAddress)
+	if r.setAvailableCondition(&executionEngine, metav1.ConditionTrue, "HealthCheckSucceeded", message) {
+		r.Recorder.Event(&executionEngine, corev1.EventTypeNormal, "HealthCheckSucceeded", message)
+	}
+	if err := r.updateStatus(ctx, executionEngine, statusReady, message); err != nil {
 		return ctrl.Result{}, err
 	}
 
-	r.Recorder.Event(&executionEngine, corev1.EventTypeNormal, "AddressResolved", fmt.Sprintf("Address resolved: %s", resolvedAddress))
 	log.Info("ExecutionEngine processed successfully", "executionEngine", executionEngine.Name, "resolvedAddress", resolvedAddress)
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.pollInterval(executionEngine)}, nil
+}
+
+// setAvailableCondition sets the Available condition and reports whether its status changed
+func (r *ExecutionEngineReconciler) setAvailableCondition(executionEngine *arkv1prealpha1.ExecutionEngine, status metav1.ConditionStatus, reason, message string) bool {
+	previous := meta.FindStatusCondition(executionEngine.Status.Conditions, arkv1prealpha1.ExecutionEngineAvailable)
+	meta.SetStatusCondition(&executionEngine.Status.Conditions, metav1.Condition{
+		Type:               arkv1prealpha1.ExecutionEngineAvailable,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: executionEngine.Generation,
+	})
+	return previous == nil || previous.Status != status
+}
+
+func (r *ExecutionEngineReconciler) pollInterval(executionEngine arkv1prealpha1.ExecutionEngine) time.Duration {
+	if executionEngine.Spec.PollInterval == nil || executionEngine.Spec.PollInterval.Duration <= 0 {
+		return time.Minute
+	}
+	return executionEngine.Spec.PollInterval.Duration
 }
 
 func (r *ExecutionEngineReconciler) updateStatus(ctx context.Context, executionEngine arkv1prealpha1.ExecutionEngine, status, message string) error {
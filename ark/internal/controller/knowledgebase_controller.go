@@ -0,0 +1,193 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// KnowledgeBaseReconciler indexes knowledge bases: it chunks the documents of their sources, embeds
+// the chunks with their embedding model and stores them in a collection of their memory. Sources are
+// indexed again when the spec changes and every refresh interval.
+type KnowledgeBaseReconciler struct {
+	client.Client
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Telemetry telemetry.Provider
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=knowledgebases,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=knowledgebases/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=knowledgebases/finalizers,verbs=update
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=memories,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *KnowledgeBaseReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var kb arkv1alpha1.KnowledgeBase
+	if err := r.Get(ctx, req.NamespacedName, &kb); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to fetch knowledge base", "knowledgeBase", req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	if !kb.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.finalize(ctx, &kb)
+	}
+	if !controllerutil.ContainsFinalizer(&kb, finalizer) {
+		controllerutil.AddFinalizer(&kb, finalizer)
+		if err := r.Update(ctx, &kb); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	now := time.Now()
+	if wait, due := knowledgeBaseRefreshDue(&kb, now); !due {
+		return ctrl.Result{RequeueAfter: wait}, nil
+	}
+
+	kb.Status.Phase = statusRunning
+	kb.Status.Message = "Indexing the sources"
+	if err := r.Status().Update(ctx, &kb); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	documents, chunks, collection, err := r.index(ctx, &kb, now)
+	if err != nil {
+		log.Error(err, "failed to index knowledge base", "knowledgeBase", kb.Name)
+		r.Recorder.Event(&kb, corev1.EventTypeWarning, "IndexingFailed", err.Error())
+		kb.Status.Phase = statusError
+		kb.Status.Message = err.Error()
+		if updateErr := r.Status().Update(ctx, &kb); updateErr != nil {
+			log.Error(updateErr, "failed to update knowledge base status")
+		}
+		return ctrl.Result{}, err
+	}
+
+	previous := kb.Status.Collection
+	indexedAt := metav1.NewTime(now)
+	kb.Status.Phase = statusReady
+	kb.Status.Message = fmt.Sprintf("Indexed %d chunks of %d documents", chunks, documents)
+	kb.Status.Collection = collection
+	kb.Status.Documents = documents
+	kb.Status.Chunks = chunks
+	kb.Status.LastIndexedTime = &indexedAt
+	kb.Status.ObservedGeneration = kb.Generation
+	if err := r.Status().Update(ctx, &kb); err != nil {
+		log.Error(err, "failed to update knowledge base status")
+		r.deleteCollection(ctx, &kb, collection)
+		return ctrl.Result{}, err
+	}
+	r.Recorder.Event(&kb, corev1.EventTypeNormal, "Indexed", kb.Status.Message)
+
+	if previous != "" && previous != collection {
+		r.deleteCollection(ctx, &kb, previous)
+	}
+
+	wait, _ := knowledgeBaseRefreshDue(&kb, now)
+	return ctrl.Result{RequeueAfter: wait}, nil
+}
+
+// index writes the chunks of the sources of a knowledge base to a new collection, and returns the
+// number of documents and chunks and the collection
+func (r *KnowledgeBaseReconciler) index(ctx context.Context, kb *arkv1alpha1.KnowledgeBase, now time.Time) (int, int, string, error) {
+	model, err := genai.LoadModel(ctx, r.Client, &kb.Spec.EmbeddingModelRef, kb.Namespace, r.Telemetry.ModelRecorder())
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to load embedding model: %w", err)
+	}
+	if model.Type != genai.ModelTypeEmbedding {
+		return 0, 0, "", fmt.Errorf("model %s is of type %s, knowledge bases require a model of type %s", kb.Spec.EmbeddingModelRef.Name, model.Type, genai.ModelTypeEmbedding)
+	}
+
+	documents, err := genai.LoadKnowledgeBaseDocuments(ctx, r.Client, kb)
+	if err != nil {
+		return 0, 0, "", fmt.Errorf("failed to load documents: %w", err)
+	}
+
+	store, err := genai.NewKnowledgeBaseStore(ctx, r.Client, kb.Spec.Memory, kb.Namespace)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	collection := genai.KnowledgeBaseCollection(kb, now)
+	size, overlap := genai.KnowledgeBaseChunkSize(kb)
+	chunks, err := genai.IndexKnowledgeBaseDocuments(ctx, model, store, collection, documents, size, overlap)
+	if err != nil {
+		if deleteErr := store.DeleteCollection(ctx, collection); deleteErr != nil {
+			logf.FromContext(ctx).Error(deleteErr, "failed to delete incomplete collection", "collection", collection)
+		}
+		return 0, 0, "", err
+	}
+	return len(documents), chunks, collection, nil
+}
+
+// knowledgeBaseRefreshDue returns whether a knowledge base is indexed now: when its spec changed
+// since the last indexing, the last indexing failed, or its refresh interval passed. Otherwise it
+// returns how long until the next refresh, or zero without a refresh interval
+func knowledgeBaseRefreshDue(kb *arkv1alpha1.KnowledgeBase, now time.Time) (time.Duration, bool) {
+	if kb.Status.ObservedGeneration != kb.Generation || kb.Status.LastIndexedTime == nil || kb.Status.Phase == statusError {
+		return 0, true
+	}
+	if kb.Spec.RefreshInterval == nil || kb.Spec.RefreshInterval.Duration <= 0 {
+		return 0, false
+	}
+	wait := kb.Status.LastIndexedTime.Add(kb.Spec.RefreshInterval.Duration).Sub(now)
+	if wait <= 0 {
+		return 0, true
+	}
+	return wait, false
+}
+
+// finalize deletes the collection of a knowledge base being deleted and removes its finalizer
+func (r *KnowledgeBaseReconciler) finalize(ctx context.Context, kb *arkv1alpha1.KnowledgeBase) error {
+	if !controllerutil.ContainsFinalizer(kb, finalizer) {
+		return nil
+	}
+	if kb.Status.Collection != "" {
+		r.deleteCollection(ctx, kb, kb.Status.Collection)
+	}
+	controllerutil.RemoveFinalizer(kb, finalizer)
+	return r.Update(ctx, kb)
+}
+
+// deleteCollection deletes a collection of a knowledge base. Failures are logged, since a collection
+// left behind only takes space in the memory
+func (r *KnowledgeBaseReconciler) deleteCollection(ctx context.Context, kb *arkv1alpha1.KnowledgeBase, collection string) {
+	store, err := genai.NewKnowledgeBaseStore(ctx, r.Client, kb.Spec.Memory, kb.Namespace)
+	if err == nil {
+		err = store.DeleteCollection(ctx, collection)
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to delete knowledge base collection", "knowledgeBase", kb.Name, "collection", collection)
+	}
+}
+
+// SetupWithManager reconciles knowledge bases when their spec changes. Status updates are ignored,
+// so failed indexings are retried with backoff and refreshes run at their interval
+func (r *KnowledgeBaseReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.KnowledgeBase{}, builder.WithPredicates(predicate.GenerationChangedPredicate{})).
+		Named("knowledgebase").
+		Complete(r)
+}
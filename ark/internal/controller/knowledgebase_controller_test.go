@@ -0,0 +1,61 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("KnowledgeBase Refresh", func() {
+	var (
+		kb  *arkv1alpha1.KnowledgeBase
+		now time.Time
+	)
+
+	BeforeEach(func() {
+		now = time.Now()
+		indexedAt := metav1.NewTime(now.Add(-time.Hour))
+		kb = &arkv1alpha1.KnowledgeBase{
+			ObjectMeta: metav1.ObjectMeta{Name: "product-docs", Namespace: "default", Generation: 2},
+			Status: arkv1alpha1.KnowledgeBaseStatus{
+				Phase:              statusReady,
+				LastIndexedTime:    &indexedAt,
+				ObservedGeneration: 2,
+			},
+		}
+	})
+
+	It("should not index an indexed knowledge base without a refresh interval", func() {
+		wait, due := knowledgeBaseRefreshDue(kb, now)
+		Expect(due).To(BeFalse())
+		Expect(wait).To(BeZero())
+	})
+
+	It("should index when the spec changed or the last indexing failed", func() {
+		kb.Generation = 3
+		_, due := knowledgeBaseRefreshDue(kb, now)
+		Expect(due).To(BeTrue())
+
+		kb.Generation = 2
+		kb.Status.Phase = statusError
+		_, due = knowledgeBaseRefreshDue(kb, now)
+		Expect(due).To(BeTrue())
+	})
+
+	It("should index when the refresh interval passed", func() {
+		kb.Spec.RefreshInterval = &metav1.Duration{Duration: 3 * time.Hour}
+		wait, due := knowledgeBaseRefreshDue(kb, now)
+		Expect(due).To(BeFalse())
+		Expect(wait).To(Equal(2 * time.Hour))
+
+		kb.Spec.RefreshInterval = &metav1.Duration{Duration: 30 * time.Minute}
+		_, due = knowledgeBaseRefreshDue(kb, now)
+		Expect(due).To(BeTrue())
+	})
+})
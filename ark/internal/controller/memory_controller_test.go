@@ -4,11 +4,20 @@ package controller
 
 import (
 	"context"
+	"encoding/base64"
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -72,3 +81,123 @@ var _ = Describe("Memory Controller", func() {
 		})
 	})
 })
+
+var _ = Describe("Memory Provisioning", func() {
+	var (
+		ctx        context.Context
+		reconciler *MemoryReconciler
+		fakeClient client.Client
+		key        types.NamespacedName
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		key = types.NamespacedName{Name: "sessions", Namespace: "default"}
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(&arkv1alpha1.Memory{}).WithObjects(
+			&arkv1alpha1.Memory{
+				ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+				Spec: arkv1alpha1.MemorySpec{
+					Provision: true,
+					Postgres:  &arkv1alpha1.MemoryPostgres{CredentialsSecret: "sessions-db"},
+				},
+			},
+		).Build()
+		reconciler = &MemoryReconciler{Client: fakeClient, Scheme: s, Recorder: record.NewFakeRecorder(10)}
+	})
+
+	It("should wait for the credentials secret before deploying the service", func() {
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var memory arkv1alpha1.Memory
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(statusRunning))
+		Expect(meta.IsStatusConditionFalse(memory.Status.Conditions, MemoryDatabaseReady)).To(BeTrue())
+
+		var deployment appsv1.Deployment
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: "sessions-memory", Namespace: "default"}, &deployment)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+	})
+
+	It("should deploy the service wired to the credentials secret and become ready when available", func() {
+		Expect(fakeClient.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sessions-db", Namespace: "default"}})).To(Succeed())
+
+		_, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+
+		var deployment appsv1.Deployment
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "sessions-memory", Namespace: "default"}, &deployment)).To(Succeed())
+		Expect(deployment.OwnerReferences).To(HaveLen(1))
+		env := deployment.Spec.Template.Spec.Containers[0].Env
+		Expect(env).To(ContainElement(HaveField("Name", "PGPASSWORD")))
+		for _, e := range env {
+			Expect(e.ValueFrom.SecretKeyRef.Name).To(Equal("sessions-db"))
+		}
+
+		var service corev1.Service
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "sessions-memory", Namespace: "default"}, &service)).To(Succeed())
+
+		var memory arkv1alpha1.Memory
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(statusRunning))
+		Expect(*memory.Status.LastResolvedAddress).To(Equal("http://sessions-memory.default.svc.cluster.local:8080"))
+
+		deployment.Status.AvailableReplicas = 1
+		Expect(fakeClient.Status().Update(ctx, &deployment)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(statusReady))
+		Expect(meta.IsStatusConditionTrue(memory.Status.Conditions, MemoryAvailable)).To(BeTrue())
+	})
+
+	It("should mount the encryption keys once the active key is valid", func() {
+		Expect(fakeClient.Create(ctx, &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "sessions-db", Namespace: "default"}})).To(Succeed())
+		keys := &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "sessions-keys", Namespace: "default"},
+			Data:       map[string][]byte{"2025-01": []byte(base64.StdEncoding.EncodeToString(make([]byte, 32)))},
+		}
+		Expect(fakeClient.Create(ctx, keys)).To(Succeed())
+
+		var memory arkv1alpha1.Memory
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		memory.Spec.Postgres.Encryption = &arkv1alpha1.MemoryEncryption{KeysSecret: "sessions-keys", ActiveKey: "2025-02"}
+		Expect(fakeClient.Update(ctx, &memory)).To(Succeed())
+
+		result, err := reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(memoryProvisionPollInterval))
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		Expect(memory.Status.Phase).To(Equal(statusError))
+		Expect(memory.Status.Message).To(ContainSubstring("active key 2025-02 not found in secret sessions-keys"))
+
+		var deployment appsv1.Deployment
+		err = fakeClient.Get(ctx, types.NamespacedName{Name: "sessions-memory", Namespace: "default"}, &deployment)
+		Expect(errors.IsNotFound(err)).To(BeTrue())
+
+		// Rotating to a new key adds it to the Secret next to the keys it replaces
+		keys.Data["2025-02"] = []byte(base64.StdEncoding.EncodeToString(make([]byte, 32)))
+		Expect(fakeClient.Update(ctx, keys)).To(Succeed())
+
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "sessions-memory", Namespace: "default"}, &deployment)).To(Succeed())
+		container := deployment.Spec.Template.Spec.Containers[0]
+		Expect(container.Env).To(ContainElement(corev1.EnvVar{Name: "MEMORY_ENCRYPTION_ACTIVE_KEY", Value: "2025-02"}))
+		Expect(container.VolumeMounts).To(ContainElement(HaveField("MountPath", memoryEncryptionKeysPath)))
+		Expect(deployment.Spec.Template.Spec.Volumes[0].Secret.SecretName).To(Equal("sessions-keys"))
+
+		keys.Data["broken"] = []byte("not a key")
+		Expect(fakeClient.Update(ctx, keys)).To(Succeed())
+		_, err = reconciler.Reconcile(ctx, reconcile.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fakeClient.Get(ctx, key, &memory)).To(Succeed())
+		Expect(memory.Status.Message).To(ContainSubstring("key broken of secret sessions-keys is not a base64 encoded 256-bit key"))
+	})
+})
@@ -0,0 +1,351 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/labels"
+)
+
+const (
+	// Condition types of a provisioned memory
+	MemoryDatabaseReady = "DatabaseReady"
+	MemoryAvailable     = "Available"
+
+	memoryServicePort  = 8080
+	memoryDatabaseName = "memory"
+	// The keys of the encryption keys Secret are mounted as files named after their key IDs
+	memoryEncryptionKeysPath = "/etc/postgres-memory/encryption-keys"
+	memoryEncryptionKeySize  = 32
+	// The CloudNativePG cluster is not watched, so provisioning is polled until it is ready
+	memoryProvisionPollInterval = 15 * time.Second
+)
+
+var (
+	cnpgClusterGVK = schema.GroupVersionKind{Group: "postgresql.cnpg.io", Version: "v1", Kind: "Cluster"}
+
+	defaultMemoryImage       = "ghcr.io/mckinsey/agents-at-scale-ark/postgres-memory:latest"
+	defaultMemoryStorageSize = resource.MustParse("1Gi")
+)
+
+// reconcileProvisioned provisions the postgres-memory service of a memory: its database, a
+// Deployment wired to the database credentials and a Service. Health of the database and the
+// Deployment is reflected in the memory conditions and phase.
+func (r *MemoryReconciler) reconcileProvisioned(ctx context.Context, memory *arkv1alpha1.Memory) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+	postgres := memoryPostgres(memory)
+
+	credentialsSecret, databaseReady, databaseMessage, err := r.reconcileMemoryDatabase(ctx, memory, postgres)
+	if err != nil {
+		log.Error(err, "failed to provision memory database", "memory", memory.Name)
+		r.setMemoryCondition(memory, MemoryDatabaseReady, metav1.ConditionFalse, "ProvisioningFailed", err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, *memory, statusError, fmt.Sprintf("Failed to provision database: %v", err))
+	}
+	if databaseReady {
+		r.setMemoryCondition(memory, MemoryDatabaseReady, metav1.ConditionTrue, "DatabaseReady", databaseMessage)
+	} else {
+		r.setMemoryCondition(memory, MemoryDatabaseReady, metav1.ConditionFalse, "DatabaseNotReady", databaseMessage)
+	}
+
+	// The service reads its connection settings from the credentials Secret, so it is deployed
+	// once the Secret exists
+	if credentialsSecret == "" {
+		r.setMemoryCondition(memory, MemoryAvailable, metav1.ConditionFalse, "WaitingForDatabase", "Waiting for the database credentials")
+		if err := r.updateStatus(ctx, *memory, statusRunning, databaseMessage); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: memoryProvisionPollInterval}, nil
+	}
+
+	if err := r.validateMemoryEncryption(ctx, memory, postgres.Encryption); err != nil {
+		log.Error(err, "invalid memory encryption", "memory", memory.Name)
+		r.setMemoryCondition(memory, MemoryAvailable, metav1.ConditionFalse, "InvalidEncryption", err.Error())
+		if err := r.updateStatus(ctx, *memory, statusError, fmt.Sprintf("Invalid encryption: %v", err)); err != nil {
+			return ctrl.Result{}, err
+		}
+		// The keys Secret is not watched, so it is checked again until it is fixed
+		return ctrl.Result{RequeueAfter: memoryProvisionPollInterval}, nil
+	}
+
+	deployment, err := r.reconcileMemoryDeployment(ctx, memory, postgres, credentialsSecret)
+	if err == nil {
+		err = r.reconcileMemoryService(ctx, memory)
+	}
+	if err != nil {
+		log.Error(err, "failed to provision memory service", "memory", memory.Name)
+		r.setMemoryCondition(memory, MemoryAvailable, metav1.ConditionFalse, "ProvisioningFailed", err.Error())
+		return ctrl.Result{}, r.updateStatus(ctx, *memory, statusError, fmt.Sprintf("Failed to provision service: %v", err))
+	}
+
+	available := deployment.Status.AvailableReplicas > 0
+	if available {
+		r.setMemoryCondition(memory, MemoryAvailable, metav1.ConditionTrue, "DeploymentAvailable",
+			fmt.Sprintf("%d of %d replicas available", deployment.Status.AvailableReplicas, *deployment.Spec.Replicas))
+	} else {
+		r.setMemoryCondition(memory, MemoryAvailable, metav1.ConditionFalse, "DeploymentUnavailable", "No replicas are available")
+	}
+
+	address, err := r.getResolver().ResolveValueSource(ctx, memory.AddressSource(), memory.Namespace)
+	if err != nil {
+		log.Error(err, "failed to resolve provisioned memory address", "memory", memory.Name)
+		return ctrl.Result{}, r.updateStatus(ctx, *memory, statusError, fmt.Sprintf("Failed to resolve address: %v", err))
+	}
+	memory.Status.LastResolvedAddress = &address
+
+	if !databaseReady || !available {
+		if err := r.updateStatus(ctx, *memory, statusRunning, "Provisioning memory service"); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{RequeueAfter: memoryProvisionPollInterval}, nil
+	}
+
+	if memory.Status.Phase != statusReady {
+		r.Recorder.Event(memory, corev1.EventTypeNormal, "Provisioned", fmt.Sprintf("Memory service available at %s", address))
+	}
+	return ctrl.Result{}, r.updateStatus(ctx, *memory, statusReady, "Memory service provisioned and available")
+}
+
+// reconcileMemoryDatabase provisions the CloudNativePG cluster of the memory, if it has one, and
+// returns the name of the credentials Secret once it exists
+func (r *MemoryReconciler) reconcileMemoryDatabase(ctx context.Context, memory *arkv1alpha1.Memory, postgres *arkv1alpha1.MemoryPostgres) (string, bool, string, error) {
+	if postgres.Cluster == nil {
+		exists, err := r.secretExists(ctx, postgres.CredentialsSecret, memory.Namespace)
+		if err != nil || !exists {
+			return "", false, fmt.Sprintf("Secret %s not found", postgres.CredentialsSecret), err
+		}
+		return postgres.CredentialsSecret, true, fmt.Sprintf("Using the database in Secret %s", postgres.CredentialsSecret), nil
+	}
+
+	cluster := &unstructured.Unstructured{}
+	cluster.SetGroupVersionKind(cnpgClusterGVK)
+	cluster.SetName(memory.ProvisionedName())
+	cluster.SetNamespace(memory.Namespace)
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, cluster, func() error {
+		instances := postgres.Cluster.Instances
+		if instances == 0 {
+			instances = 1
+		}
+		storageSize := postgres.Cluster.StorageSize
+		if storageSize.IsZero() {
+			storageSize = defaultMemoryStorageSize
+		}
+		if err := unstructured.SetNestedField(cluster.Object, int64(instances), "spec", "instances"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedField(cluster.Object, storageSize.String(), "spec", "storage", "size"); err != nil {
+			return err
+		}
+		if err := unstructured.SetNestedStringMap(cluster.Object, map[string]string{
+			"database": memoryDatabaseName,
+			"owner":    memoryDatabaseName,
+		}, "spec", "bootstrap", "initdb"); err != nil {
+			return err
+		}
+		return controllerutil.SetControllerReference(memory, cluster, r.Scheme)
+	}); err != nil {
+		if meta.IsNoMatchError(err) {
+			return "", false, "", fmt.Errorf("the CloudNativePG operator is not installed")
+		}
+		return "", false, "", err
+	}
+
+	ready := false
+	conditions, _, _ := unstructured.NestedSlice(cluster.Object, "status", "conditions")
+	for _, condition := range conditions {
+		fields, ok := condition.(map[string]any)
+		if ok && fields["type"] == "Ready" && fields["status"] == string(metav1.ConditionTrue) {
+			ready = true
+		}
+	}
+	message := fmt.Sprintf("CloudNativePG cluster %s is ready", cluster.GetName())
+	if !ready {
+		message = fmt.Sprintf("Waiting for CloudNativePG cluster %s", cluster.GetName())
+	}
+
+	// CloudNativePG writes the application credentials to the <cluster>-app Secret
+	secretName := cluster.GetName() + "-app"
+	exists, err := r.secretExists(ctx, secretName, memory.Namespace)
+	if err != nil || !exists {
+		return "", false, message, err
+	}
+	return secretName, ready, message, nil
+}
+
+func (r *MemoryReconciler) reconcileMemoryDeployment(ctx context.Context, memory *arkv1alpha1.Memory, postgres *arkv1alpha1.MemoryPostgres, credentialsSecret string) (*appsv1.Deployment, error) {
+	deployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: memory.ProvisionedName(), Namespace: memory.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, deployment, func() error {
+		podLabels := memoryPodLabels(memory)
+		replicas := int32(1)
+		if postgres.Replicas != nil {
+			replicas = *postgres.Replicas
+		}
+		image := postgres.Image
+		if image == "" {
+			image = defaultMemoryImage
+		}
+
+		deployment.Labels = podLabels
+		deployment.Spec.Replicas = &replicas
+		deployment.Spec.Selector = &metav1.LabelSelector{MatchLabels: podLabels}
+		deployment.Spec.Template.Labels = podLabels
+
+		container := corev1.Container{
+			Name:  "postgres-memory",
+			Image: image,
+			Ports: []corev1.ContainerPort{{Name: "http", ContainerPort: memoryServicePort, Protocol: corev1.ProtocolTCP}},
+			Env:   memoryDatabaseEnv(credentialsSecret),
+			ReadinessProbe: &corev1.Probe{
+				ProbeHandler: corev1.ProbeHandler{
+					HTTPGet: &corev1.HTTPGetAction{Path: "/health", Port: intstr.FromString("http")},
+				},
+			},
+		}
+		if postgres.Resources != nil {
+			container.Resources = *postgres.Resources
+		}
+		deployment.Spec.Template.Spec.Volumes = nil
+		if encryption := postgres.Encryption; encryption != nil {
+			container.Env = append(container.Env, memoryEncryptionEnv(encryption)...)
+			container.VolumeMounts = []corev1.VolumeMount{{Name: "encryption-keys", MountPath: memoryEncryptionKeysPath, ReadOnly: true}}
+			deployment.Spec.Template.Spec.Volumes = []corev1.Volume{{
+				Name:         "encryption-keys",
+				VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: encryption.KeysSecret}},
+			}}
+		}
+		deployment.Spec.Template.Spec.Containers = []corev1.Container{container}
+		return controllerutil.SetControllerReference(memory, deployment, r.Scheme)
+	})
+	return deployment, err
+}
+
+func (r *MemoryReconciler) reconcileMemoryService(ctx context.Context, memory *arkv1alpha1.Memory) error {
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: memory.ProvisionedName(), Namespace: memory.Namespace},
+	}
+	_, err := controllerutil.CreateOrUpdate(ctx, r.Client, service, func() error {
+		service.Labels = memoryPodLabels(memory)
+		service.Spec.Selector = memoryPodLabels(memory)
+		service.Spec.Ports = []corev1.ServicePort{{
+			Name:       "http",
+			Port:       memoryServicePort,
+			TargetPort: intstr.FromString("http"),
+			Protocol:   corev1.ProtocolTCP,
+		}}
+		return controllerutil.SetControllerReference(memory, service, r.Scheme)
+	})
+	return err
+}
+
+// validateMemoryEncryption checks that the keys Secret of the encryption has the active key and
+// that its keys are 256-bit, so the service does not start with keys it cannot use
+func (r *MemoryReconciler) validateMemoryEncryption(ctx context.Context, memory *arkv1alpha1.Memory, encryption *arkv1alpha1.MemoryEncryption) error {
+	if encryption == nil {
+		return nil
+	}
+
+	var secret corev1.Secret
+	if err := r.Get(ctx, types.NamespacedName{Name: encryption.KeysSecret, Namespace: memory.Namespace}, &secret); err != nil {
+		return fmt.Errorf("failed to get keys secret %s: %w", encryption.KeysSecret, err)
+	}
+	if _, ok := secret.Data[encryption.ActiveKey]; !ok {
+		return fmt.Errorf("active key %s not found in secret %s", encryption.ActiveKey, encryption.KeysSecret)
+	}
+	for id, value := range secret.Data {
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(value)))
+		if err != nil || len(key) != memoryEncryptionKeySize {
+			return fmt.Errorf("key %s of secret %s is not a base64 encoded %d-bit key", id, encryption.KeysSecret, memoryEncryptionKeySize*8)
+		}
+	}
+	return nil
+}
+
+func (r *MemoryReconciler) secretExists(ctx context.Context, name, namespace string) (bool, error) {
+	var secret corev1.Secret
+	err := r.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &secret)
+	if errors.IsNotFound(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *MemoryReconciler) setMemoryCondition(memory *arkv1alpha1.Memory, conditionType string, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&memory.Status.Conditions, metav1.Condition{
+		Type:               conditionType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: memory.Generation,
+	})
+}
+
+// memoryPostgres returns the postgres settings of a memory. A memory provisioned without them
+// gets a CloudNativePG cluster with default settings.
+func memoryPostgres(memory *arkv1alpha1.Memory) *arkv1alpha1.MemoryPostgres {
+	if memory.Spec.Postgres == nil {
+		return &arkv1alpha1.MemoryPostgres{Cluster: &arkv1alpha1.MemoryPostgresCluster{}}
+	}
+	return memory.Spec.Postgres
+}
+
+func memoryPodLabels(memory *arkv1alpha1.Memory) map[string]string {
+	return map[string]string{
+		"app.kubernetes.io/name":       "postgres-memory",
+		"app.kubernetes.io/managed-by": "ark",
+		labels.MemoryLabel:             memory.Name,
+	}
+}
+
+// memoryDatabaseEnv maps the credentials Secret to the standard PostgreSQL environment variables
+func memoryDatabaseEnv(credentialsSecret string) []corev1.EnvVar {
+	keys := []struct{ env, key string }{
+		{"PGHOST", "host"},
+		{"PGPORT", "port"},
+		{"PGDATABASE", "dbname"},
+		{"PGUSER", "user"},
+		{"PGPASSWORD", "password"},
+	}
+	env := make([]corev1.EnvVar, 0, len(keys))
+	for _, k := range keys {
+		env = append(env, corev1.EnvVar{
+			Name: k.env,
+			ValueFrom: &corev1.EnvVarSource{
+				SecretKeyRef: &corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: credentialsSecret},
+					Key:                  k.key,
+				},
+			},
+		})
+	}
+	return env
+}
+
+// memoryEncryptionEnv tells the service how to encrypt message content: the directory of the keys
+// and the active key ID
+func memoryEncryptionEnv(encryption *arkv1alpha1.MemoryEncryption) []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "MEMORY_ENCRYPTION_KEYS_DIR", Value: memoryEncryptionKeysPath},
+		{Name: "MEMORY_ENCRYPTION_ACTIVE_KEY", Value: encryption.ActiveKey},
+	}
+}
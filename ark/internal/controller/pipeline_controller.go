@@ -0,0 +1,368 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/common"
+)
+
+// PipelineReconciler runs the steps of a Pipeline in order, creating a Query for each query step and
+// waiting for a decision on each approval step
+type PipelineReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=pipelines,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=pipelines/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *PipelineReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	var pipeline arkv1alpha1.Pipeline
+	if err := r.Get(ctx, req.NamespacedName, &pipeline); err != nil {
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+	if pipeline.Status.Phase == statusDone || pipeline.Status.Phase == statusError {
+		return ctrl.Result{}, nil
+	}
+
+	status := pipeline.Status.DeepCopy()
+	result, err := r.advance(ctx, &pipeline, status)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if equality.Semantic.DeepEqual(pipeline.Status, *status) {
+		return result, nil
+	}
+	pipeline.Status = *status
+	if err := r.Status().Update(ctx, &pipeline); err != nil {
+		return ctrl.Result{}, err
+	}
+	return result, nil
+}
+
+// advance moves the pipeline forward as far as the step results allow, updating status
+func (r *PipelineReconciler) advance(ctx context.Context, pipeline *arkv1alpha1.Pipeline, status *arkv1alpha1.PipelineStatus) (ctrl.Result, error) {
+	now := metav1.Now()
+	if status.StartTime == nil {
+		status.StartTime = &now
+		status.Phase = statusRunning
+		status.Steps = make([]arkv1alpha1.PipelineStepStatus, 0, len(pipeline.Spec.Steps))
+		for _, step := range pipeline.Spec.Steps {
+			status.Steps = append(status.Steps, arkv1alpha1.PipelineStepStatus{Name: step.Name, Phase: statusPending})
+		}
+	}
+
+	for i := range status.Steps {
+		stepStatus := &status.Steps[i]
+		if stepStatus.Phase == statusDone || stepStatus.Phase == arkv1alpha1.PipelineStepSkipped {
+			continue
+		}
+		status.CurrentStep = stepStatus.Name
+		step := pipelineStep(pipeline, stepStatus.Name)
+		if step == nil {
+			r.failStep(pipeline, status, stepStatus, "step was removed from the pipeline spec")
+			return ctrl.Result{}, nil
+		}
+		previous := status.Steps[:i]
+
+		if stepStatus.Phase == statusPending {
+			run, err := evaluateStepCondition(step.When, previous)
+			if err != nil {
+				r.failStep(pipeline, status, stepStatus, fmt.Sprintf("invalid when expression: %v", err))
+				return ctrl.Result{}, nil
+			}
+			if !run {
+				stepStatus.Phase = arkv1alpha1.PipelineStepSkipped
+				stepStatus.CompletionTime = &now
+				continue
+			}
+			return r.startStep(ctx, pipeline, status, step, stepStatus, previous)
+		}
+
+		var done bool
+		var result ctrl.Result
+		var err error
+		switch stepStatus.Phase {
+		case statusRunning:
+			done, err = r.checkQueryStep(ctx, pipeline, status, stepStatus)
+		case arkv1alpha1.PipelineStepWaiting:
+			done, result = r.checkApprovalStep(pipeline, status, step, stepStatus)
+		}
+		if err != nil || !done {
+			return result, err
+		}
+	}
+
+	status.Phase = statusDone
+	status.CurrentStep = ""
+	status.CompletionTime = &now
+	r.Recorder.Event(pipeline, corev1.EventTypeNormal, "PipelineCompleted", "Pipeline completed successfully")
+	return ctrl.Result{}, nil
+}
+
+func (r *PipelineReconciler) startStep(ctx context.Context, pipeline *arkv1alpha1.Pipeline, status *arkv1alpha1.PipelineStatus, step *arkv1alpha1.PipelineStep, stepStatus *arkv1alpha1.PipelineStepStatus, previous []arkv1alpha1.PipelineStepStatus) (ctrl.Result, error) {
+	now := metav1.Now()
+
+	if step.Approval != nil {
+		message, err := common.ResolveTemplate(step.Approval.Message, stepOutputs(previous))
+		if err != nil {
+			r.failStep(pipeline, status, stepStatus, fmt.Sprintf("failed to resolve approval message: %v", err))
+			return ctrl.Result{}, nil
+		}
+		stepStatus.Phase = arkv1alpha1.PipelineStepWaiting
+		stepStatus.Message = message
+		stepStatus.StartTime = &now
+		status.Phase = arkv1alpha1.PipelineStepWaiting
+		r.Recorder.Event(pipeline, corev1.EventTypeNormal, "ApprovalRequired", fmt.Sprintf("Step %s is waiting for approval", step.Name))
+		return approvalRequeue(step, stepStatus), nil
+	}
+
+	query, err := pipelineStepQuery(pipeline, step, previous)
+	if err != nil {
+		r.failStep(pipeline, status, stepStatus, err.Error())
+		return ctrl.Result{}, nil
+	}
+	if err := controllerutil.SetControllerReference(pipeline, query, r.Scheme); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.Create(ctx, query); err != nil {
+		if !errors.IsAlreadyExists(err) {
+			return ctrl.Result{}, err
+		}
+		// The query was created by an earlier reconcile whose status update failed
+		var existing arkv1alpha1.Query
+		if err := r.Get(ctx, client.ObjectKeyFromObject(query), &existing); err != nil {
+			return ctrl.Result{}, err
+		}
+		if !metav1.IsControlledBy(&existing, pipeline) {
+			r.failStep(pipeline, status, stepStatus, fmt.Sprintf("query %s already exists", query.Name))
+			return ctrl.Result{}, nil
+		}
+	}
+
+	logf.FromContext(ctx).Info("started pipeline step", "pipeline", pipeline.Name, "step", step.Name, "query", query.Name)
+	stepStatus.Phase = statusRunning
+	stepStatus.Query = query.Name
+	stepStatus.StartTime = &now
+	status.Phase = statusRunning
+	r.Recorder.Event(pipeline, corev1.EventTypeNormal, "StepStarted", fmt.Sprintf("Step %s created query %s", step.Name, query.Name))
+	return ctrl.Result{}, nil
+}
+
+// checkQueryStep completes a query step once its query completed, and reports whether it did
+func (r *PipelineReconciler) checkQueryStep(ctx context.Context, pipeline *arkv1alpha1.Pipeline, status *arkv1alpha1.PipelineStatus, stepStatus *arkv1alpha1.PipelineStepStatus) (bool, error) {
+	var query arkv1alpha1.Query
+	if err := r.Get(ctx, types.NamespacedName{Name: stepStatus.Query, Namespace: pipeline.Namespace}, &query); err != nil {
+		if errors.IsNotFound(err) {
+			r.failStep(pipeline, status, stepStatus, fmt.Sprintf("query %s was deleted", stepStatus.Query))
+			return false, nil
+		}
+		return false, err
+	}
+
+	switch query.Status.Phase {
+	case statusDone:
+		now := metav1.Now()
+		addTokenUsage(&status.TokenUsage, query.Status.TokenUsage)
+		stepStatus.Phase = statusDone
+		stepStatus.Output = queryOutput(&query)
+		stepStatus.CompletionTime = &now
+		return true, nil
+	case statusError, statusCanceled:
+		addTokenUsage(&status.TokenUsage, query.Status.TokenUsage)
+		message := fmt.Sprintf("query %s ended with phase %s", query.Name, query.Status.Phase)
+		if condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryCompleted)); condition != nil && condition.Message != "" {
+			message = fmt.Sprintf("query %s failed: %s", query.Name, condition.Message)
+		}
+		r.failStep(pipeline, status, stepStatus, message)
+		return false, nil
+	default:
+		return false, nil
+	}
+}
+
+// checkApprovalStep completes an approval step once it is approved, and reports whether it was
+func (r *PipelineReconciler) checkApprovalStep(pipeline *arkv1alpha1.Pipeline, status *arkv1alpha1.PipelineStatus, step *arkv1alpha1.PipelineStep, stepStatus *arkv1alpha1.PipelineStepStatus) (bool, ctrl.Result) {
+	now := metav1.Now()
+	switch {
+	case pipeline.Annotations[annotations.Approve] == step.Name:
+		stepStatus.Phase = statusDone
+		stepStatus.Output = "approved"
+		stepStatus.CompletionTime = &now
+		status.Phase = statusRunning
+		r.Recorder.Event(pipeline, corev1.EventTypeNormal, "StepApproved", fmt.Sprintf("Step %s was approved", step.Name))
+		return true, ctrl.Result{}
+	case pipeline.Annotations[annotations.Reject] == step.Name:
+		stepStatus.Output = "rejected"
+		r.failStep(pipeline, status, stepStatus, "step was rejected")
+		return false, ctrl.Result{}
+	}
+
+	result := approvalRequeue(step, stepStatus)
+	if step.Approval.Timeout != nil && result.RequeueAfter <= 0 {
+		r.failStep(pipeline, status, stepStatus, fmt.Sprintf("no decision within %s", step.Approval.Timeout.Duration))
+		return false, ctrl.Result{}
+	}
+	return false, result
+}
+
+// approvalRequeue requeues a waiting approval step when its timeout expires
+func approvalRequeue(step *arkv1alpha1.PipelineStep, stepStatus *arkv1alpha1.PipelineStepStatus) ctrl.Result {
+	if step.Approval.Timeout == nil || stepStatus.StartTime == nil {
+		return ctrl.Result{}
+	}
+	remaining := time.Until(stepStatus.StartTime.Add(step.Approval.Timeout.Duration))
+	if remaining <= 0 {
+		return ctrl.Result{}
+	}
+	return ctrl.Result{RequeueAfter: remaining}
+}
+
+func (r *PipelineReconciler) failStep(pipeline *arkv1alpha1.Pipeline, status *arkv1alpha1.PipelineStatus, stepStatus *arkv1alpha1.PipelineStepStatus, message string) {
+	now := metav1.Now()
+	stepStatus.Phase = statusError
+	stepStatus.Message = message
+	stepStatus.CompletionTime = &now
+	status.Phase = statusError
+	status.CompletionTime = &now
+	r.Recorder.Event(pipeline, corev1.EventTypeWarning, "StepFailed", fmt.Sprintf("Step %s failed: %s", stepStatus.Name, message))
+}
+
+func pipelineStep(pipeline *arkv1alpha1.Pipeline, name string) *arkv1alpha1.PipelineStep {
+	for i := range pipeline.Spec.Steps {
+		if pipeline.Spec.Steps[i].Name == name {
+			return &pipeline.Spec.Steps[i]
+		}
+	}
+	return nil
+}
+
+// pipelineStepQuery builds the query of a step. The pipeline parameters and the outputs of the
+// completed steps are added to the parameters of the query, which take precedence.
+func pipelineStepQuery(pipeline *arkv1alpha1.Pipeline, step *arkv1alpha1.PipelineStep, previous []arkv1alpha1.PipelineStepStatus) (*arkv1alpha1.Query, error) {
+	spec := step.Query.DeepCopy()
+
+	parameters := make([]arkv1alpha1.Parameter, 0, len(pipeline.Spec.Parameters)+len(previous)+len(spec.Parameters))
+	parameters = append(parameters, pipeline.Spec.Parameters...)
+	for _, stepStatus := range previous {
+		if stepStatus.Phase == statusDone && stepStatus.Output != "" {
+			parameters = append(parameters, arkv1alpha1.Parameter{Name: stepStatus.Name, Value: stepStatus.Output})
+		}
+	}
+	spec.Parameters = append(parameters, spec.Parameters...)
+	if spec.SessionId == "" {
+		spec.SessionId = pipeline.Spec.SessionId
+	}
+	// Step queries are created once per pipeline by their name, a key would reject the steps of later pipelines
+	spec.IdempotencyKey = ""
+
+	name := pipeline.Name + "-" + strings.ReplaceAll(step.Name, "_", "-")
+	if len(name) > 253 {
+		return nil, fmt.Errorf("query name %s is longer than 253 characters", name)
+	}
+	return &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: pipeline.Namespace,
+			Labels: map[string]string{
+				annotations.Pipeline:     pipeline.Name,
+				annotations.PipelineStep: step.Name,
+			},
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// evaluateStepCondition evaluates the when expression of a step over the previous steps
+func evaluateStepCondition(expression string, previous []arkv1alpha1.PipelineStepStatus) (bool, error) {
+	if expression == "" {
+		return true, nil
+	}
+	env, err := cel.NewEnv(cel.Variable("steps", cel.MapType(cel.StringType, cel.MapType(cel.StringType, cel.StringType))))
+	if err != nil {
+		return false, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return false, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return false, fmt.Errorf("expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+	program, err := env.Program(ast)
+	if err != nil {
+		return false, err
+	}
+
+	steps := make(map[string]map[string]string, len(previous))
+	for _, stepStatus := range previous {
+		steps[stepStatus.Name] = map[string]string{"phase": stepStatus.Phase, "output": stepStatus.Output}
+	}
+	value, _, err := program.Eval(map[string]any{"steps": steps})
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.Value().(bool)
+	return ok && result, nil
+}
+
+// stepOutputs returns the outputs of the completed steps by step name, for templates
+func stepOutputs(steps []arkv1alpha1.PipelineStepStatus) map[string]any {
+	outputs := make(map[string]any, len(steps))
+	for _, stepStatus := range steps {
+		if stepStatus.Phase == statusDone {
+			outputs[stepStatus.Name] = stepStatus.Output
+		}
+	}
+	return outputs
+}
+
+// queryOutput returns the response content of a query, the responses of several targets separated
+// by blank lines
+func queryOutput(query *arkv1alpha1.Query) string {
+	contents := make([]string, 0, len(query.Status.Responses))
+	for _, response := range query.Status.Responses {
+		contents = append(contents, response.Content)
+	}
+	return strings.Join(contents, "\n\n")
+}
+
+func addTokenUsage(total *arkv1alpha1.TokenUsage, usage arkv1alpha1.TokenUsage) {
+	total.PromptTokens += usage.PromptTokens
+	total.CompletionTokens += usage.CompletionTokens
+	total.TotalTokens += usage.TotalTokens
+	total.ReasoningTokens += usage.ReasoningTokens
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *PipelineReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.Pipeline{}).
+		Owns(&arkv1alpha1.Query{}).
+		Named("pipeline").
+		Complete(r)
+}
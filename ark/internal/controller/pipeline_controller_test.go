@@ -0,0 +1,176 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+var _ = Describe("Pipeline", func() {
+	var (
+		ctx        context.Context
+		key        = types.NamespacedName{Name: "report", Namespace: "default"}
+		fakeClient client.Client
+		reconciler *PipelineReconciler
+	)
+
+	queryStep := func(name, input string) arkv1alpha1.PipelineStep {
+		raw, _ := json.Marshal(input)
+		return arkv1alpha1.PipelineStep{
+			Name: name,
+			Query: &arkv1alpha1.QuerySpec{
+				Input:   runtime.RawExtension{Raw: raw},
+				Targets: []arkv1alpha1.QueryTarget{{Type: "agent", Name: "writer"}},
+			},
+		}
+	}
+
+	setup := func(steps ...arkv1alpha1.PipelineStep) {
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		pipeline := &arkv1alpha1.Pipeline{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: arkv1alpha1.PipelineSpec{
+				Parameters: []arkv1alpha1.Parameter{{Name: "topic", Value: "tides"}},
+				SessionId:  "report-session",
+				Steps:      steps,
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(pipeline).WithStatusSubresource(pipeline, &arkv1alpha1.Query{}).Build()
+		reconciler = &PipelineReconciler{Client: fakeClient, Scheme: s, Recorder: record.NewFakeRecorder(20)}
+	}
+
+	reconcile := func() *arkv1alpha1.Pipeline {
+		_, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		pipeline := &arkv1alpha1.Pipeline{}
+		Expect(fakeClient.Get(ctx, key, pipeline)).To(Succeed())
+		return pipeline
+	}
+
+	completeQuery := func(name, phase, content string) {
+		query := &arkv1alpha1.Query{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: name, Namespace: key.Namespace}, query)).To(Succeed())
+		query.Status.Phase = phase
+		query.Status.Responses = []arkv1alpha1.Response{{Content: content}}
+		query.Status.TokenUsage = arkv1alpha1.TokenUsage{TotalTokens: 10}
+		Expect(fakeClient.Status().Update(ctx, query)).To(Succeed())
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+	})
+
+	It("should run query steps in order and pass outputs as parameters", func() {
+		setup(queryStep("research", "Research {{.topic}}"), queryStep("summarize", "Summarize {{.research}}"))
+
+		pipeline := reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(statusRunning))
+		Expect(pipeline.Status.CurrentStep).To(Equal("research"))
+		Expect(pipeline.Status.Steps[0].Query).To(Equal("report-research"))
+
+		query := &arkv1alpha1.Query{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "report-research", Namespace: key.Namespace}, query)).To(Succeed())
+		Expect(query.Labels[annotations.Pipeline]).To(Equal("report"))
+		Expect(query.Spec.SessionId).To(Equal("report-session"))
+		Expect(metav1.IsControlledBy(query, pipeline)).To(BeTrue())
+
+		completeQuery("report-research", statusDone, "tides follow the moon")
+		pipeline = reconcile()
+		Expect(pipeline.Status.Steps[0].Output).To(Equal("tides follow the moon"))
+		Expect(pipeline.Status.CurrentStep).To(Equal("summarize"))
+
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "report-summarize", Namespace: key.Namespace}, query)).To(Succeed())
+		Expect(query.Spec.Parameters).To(ContainElements(
+			arkv1alpha1.Parameter{Name: "topic", Value: "tides"},
+			arkv1alpha1.Parameter{Name: "research", Value: "tides follow the moon"},
+		))
+
+		completeQuery("report-summarize", statusDone, "moon")
+		pipeline = reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(statusDone))
+		Expect(pipeline.Status.TokenUsage.TotalTokens).To(Equal(int64(20)))
+		Expect(pipeline.Status.CompletionTime).NotTo(BeNil())
+	})
+
+	It("should skip steps whose condition is false", func() {
+		publish := queryStep("publish", "Publish")
+		publish.When = `steps.review.output.contains("APPROVED")`
+		setup(queryStep("review", "Review"), publish)
+
+		reconcile()
+		completeQuery("report-review", statusDone, "REJECTED")
+		pipeline := reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(statusDone))
+		Expect(pipeline.Status.Steps[1].Phase).To(Equal(arkv1alpha1.PipelineStepSkipped))
+	})
+
+	It("should fail when a step query fails", func() {
+		setup(queryStep("research", "Research"), queryStep("summarize", "Summarize"))
+
+		reconcile()
+		completeQuery("report-research", statusError, "")
+		pipeline := reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(statusError))
+		Expect(pipeline.Status.Steps[0].Phase).To(Equal(statusError))
+		Expect(pipeline.Status.Steps[1].Phase).To(Equal(statusPending))
+	})
+
+	It("should wait for approval steps", func() {
+		setup(queryStep("draft", "Draft"), arkv1alpha1.PipelineStep{
+			Name:     "review",
+			Approval: &arkv1alpha1.PipelineApproval{Message: "Publish {{.draft}}?"},
+		}, queryStep("publish", "Publish"))
+
+		reconcile()
+		completeQuery("report-draft", statusDone, "the draft")
+		pipeline := reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(arkv1alpha1.PipelineStepWaiting))
+		Expect(pipeline.Status.Steps[1].Message).To(Equal("Publish the draft?"))
+
+		pipeline = reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(arkv1alpha1.PipelineStepWaiting))
+
+		pipeline.Annotations = map[string]string{annotations.Approve: "review"}
+		Expect(fakeClient.Update(ctx, pipeline)).To(Succeed())
+		pipeline = reconcile()
+		Expect(pipeline.Status.Steps[1].Phase).To(Equal(statusDone))
+		Expect(pipeline.Status.CurrentStep).To(Equal("publish"))
+	})
+
+	It("should fail rejected approval steps", func() {
+		setup(arkv1alpha1.PipelineStep{Name: "review", Approval: &arkv1alpha1.PipelineApproval{}})
+
+		pipeline := reconcile()
+		pipeline.Annotations = map[string]string{annotations.Reject: "review"}
+		Expect(fakeClient.Update(ctx, pipeline)).To(Succeed())
+		pipeline = reconcile()
+		Expect(pipeline.Status.Phase).To(Equal(statusError))
+		Expect(pipeline.Status.Steps[0].Message).To(Equal("step was rejected"))
+	})
+
+	It("should evaluate step conditions", func() {
+		previous := []arkv1alpha1.PipelineStepStatus{{Name: "review", Phase: statusDone, Output: "APPROVED"}}
+
+		Expect(evaluateStepCondition("", previous)).To(BeTrue())
+		Expect(evaluateStepCondition(`steps.review.phase == "done"`, previous)).To(BeTrue())
+		Expect(evaluateStepCondition(`steps.review.output == "no"`, previous)).To(BeFalse())
+
+		_, err := evaluateStepCondition(`steps.review.output`, previous)
+		Expect(err).To(MatchError(ContainSubstring("must evaluate to a bool")))
+	})
+})
@@ -0,0 +1,69 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const a2aRequirementsNotMetReason = "A2ARequirementsNotMet"
+
+// checkA2ARequirements checks the A2A agent targets of the query against the requirements of the
+// query and of the agent, so that a query fails before execution rather than on the remote call.
+// Targets that cannot be resolved are left to the execution to report.
+func (r *QueryReconciler) checkA2ARequirements(ctx context.Context, query *arkv1alpha1.Query) error {
+	targets, err := r.resolveTargets(ctx, *query, r.Client)
+	if err != nil {
+		return nil
+	}
+
+	for _, target := range targets {
+		if target.Type != "agent" || target.Cluster != "" {
+			continue
+		}
+		var agent arkv1alpha1.Agent
+		if err := r.Get(ctx, targetKey(*query, target), &agent); err != nil {
+			continue
+		}
+		serverName, ok := agent.Annotations[annotations.A2AServerName]
+		if !ok || (query.Spec.A2ARequirements == nil && agent.Spec.A2ARequirements == nil) {
+			continue
+		}
+
+		var a2aServer arkv1prealpha1.A2AServer
+		if err := r.Get(ctx, client.ObjectKey{Name: serverName, Namespace: agent.Namespace}, &a2aServer); err != nil {
+			return fmt.Errorf("agent %s: unable to get A2AServer %s: %w", agent.Name, serverName, err)
+		}
+		for _, requirements := range []*arkv1alpha1.A2ARequirements{query.Spec.A2ARequirements, agent.Spec.A2ARequirements} {
+			if err := genai.CheckA2ARequirements(requirements, &a2aServer.Status); err != nil {
+				return fmt.Errorf("agent %s: %w", agent.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// failA2ARequirements fails a query whose A2A agent targets lack the required capabilities
+func (r *QueryReconciler) failA2ARequirements(ctx context.Context, query *arkv1alpha1.Query, err error) error {
+	if r.Recorder != nil {
+		r.Recorder.Event(query, corev1.EventTypeWarning, a2aRequirementsNotMetReason, err.Error())
+	}
+	query.Status.Phase = statusError
+	query.Status.Checkpoint = nil
+	r.setConditionCompleted(query, metav1.ConditionTrue, a2aRequirementsNotMetReason, err.Error())
+	if err := r.Status().Update(ctx, query); err != nil {
+		return err
+	}
+	r.notifyQueryEnd(ctx, query)
+	return nil
+}
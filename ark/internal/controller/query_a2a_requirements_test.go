@@ -0,0 +1,106 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+var _ = Describe("Query A2A Requirements", func() {
+	var (
+		ctx       context.Context
+		query     *arkv1alpha1.Query
+		agent     *arkv1alpha1.Agent
+		a2aServer *arkv1prealpha1.A2AServer
+		key       = types.NamespacedName{Name: "forecast", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec: arkv1alpha1.QuerySpec{
+				Targets: []arkv1alpha1.QueryTarget{{Type: "agent", Name: "weather"}},
+			},
+			Status: arkv1alpha1.QueryStatus{Phase: statusRunning},
+		}
+		agent = &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "weather",
+				Namespace:   key.Namespace,
+				Annotations: map[string]string{annotations.A2AServerName: "weather-server"},
+			},
+		}
+		a2aServer = &arkv1prealpha1.A2AServer{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather-server", Namespace: key.Namespace},
+			Status: arkv1prealpha1.A2AServerStatus{
+				ProtocolVersion: "0.2.5",
+				Capabilities:    &arkv1prealpha1.A2AServerCapabilities{Streaming: true},
+			},
+		}
+	})
+
+	newReconciler := func() (*QueryReconciler, func() *arkv1alpha1.Query) {
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(arkv1prealpha1.AddToScheme(s)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(query, agent, a2aServer).WithStatusSubresource(query).Build()
+		get := func() *arkv1alpha1.Query {
+			updated := &arkv1alpha1.Query{}
+			Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+			return updated
+		}
+		return &QueryReconciler{Client: fakeClient, Execution: QueryExecutionExecutor}, get
+	}
+
+	It("should fail queries before execution when the A2A server lacks a required capability", func() {
+		agent.Spec.A2ARequirements = &arkv1alpha1.A2ARequirements{
+			Capabilities: []arkv1alpha1.A2ACapability{arkv1alpha1.A2ACapabilityPushNotifications},
+		}
+		reconciler, get := newReconciler()
+
+		_, err := reconciler.handleRunningPhase(ctx, ctrl.Request{NamespacedName: key}, *query)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := get()
+		Expect(updated.Status.Phase).To(Equal(statusError))
+		condition := meta.FindStatusCondition(updated.Status.Conditions, string(arkv1alpha1.QueryCompleted))
+		Expect(condition.Reason).To(Equal(a2aRequirementsNotMetReason))
+		Expect(condition.Message).To(Equal("agent weather: A2A server does not meet requirements: missing capabilities: pushNotifications"))
+	})
+
+	It("should check the query requirements against A2A agent targets", func() {
+		query.Spec.A2ARequirements = &arkv1alpha1.A2ARequirements{MinProtocolVersion: "0.3.0"}
+		reconciler, _ := newReconciler()
+		Expect(reconciler.checkA2ARequirements(ctx, query)).To(MatchError(ContainSubstring("protocol version 0.2.5 is below 0.3.0")))
+
+		query.Spec.A2ARequirements = &arkv1alpha1.A2ARequirements{
+			MinProtocolVersion: "0.2",
+			Capabilities:       []arkv1alpha1.A2ACapability{arkv1alpha1.A2ACapabilityStreaming},
+		}
+		Expect(reconciler.checkA2ARequirements(ctx, query)).To(Succeed())
+	})
+
+	It("should ignore requirements for agents that are not A2A agents", func() {
+		agent.Annotations = nil
+		query.Spec.A2ARequirements = &arkv1alpha1.A2ARequirements{MinProtocolVersion: "9"}
+		reconciler, get := newReconciler()
+
+		_, err := reconciler.handleRunningPhase(ctx, ctrl.Request{NamespacedName: key}, *query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get().Status.Phase).To(Equal(statusRunning))
+	})
+})
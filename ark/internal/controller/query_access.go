@@ -0,0 +1,62 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// checkTargetAccess checks that the identity a query runs as may get the target and create events
+// in the query namespace. Missing permissions then fail the target with a forbidden error before it
+// runs, rather than with a client error midway through its execution. Queries running with the
+// controller's identity are not checked.
+func (r *QueryReconciler) checkTargetAccess(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, namespace string) error {
+	impersonation, ok := queryImpersonation(query)
+	if !ok {
+		return nil
+	}
+
+	checks := []authorizationv1.ResourceAttributes{
+		{Verb: "get", Group: arkv1alpha1.GroupVersion.Group, Resource: target.Type + "s", Namespace: namespace, Name: target.Name},
+		{Verb: "create", Resource: "events", Namespace: query.Namespace},
+	}
+	extra := make(map[string]authorizationv1.ExtraValue, len(impersonation.Extra))
+	for key, value := range impersonation.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	for _, check := range checks {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               impersonation.UserName,
+				UID:                impersonation.UID,
+				Groups:             impersonation.Groups,
+				Extra:              extra,
+				ResourceAttributes: &check,
+			},
+		}
+		if err := r.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to check permissions of %s: %w", impersonation.UserName, err)
+		}
+		if review.Status.Allowed {
+			continue
+		}
+
+		reason := fmt.Sprintf("User %q cannot %s resource %q", impersonation.UserName, check.Verb, check.Resource)
+		if check.Group != "" {
+			reason += fmt.Sprintf(" in API group %q", check.Group)
+		}
+		reason += fmt.Sprintf(" in the namespace %q", check.Namespace)
+		if review.Status.Reason != "" {
+			reason += ": " + review.Status.Reason
+		}
+		return apierrors.NewForbidden(schema.GroupResource{Group: check.Group, Resource: check.Resource}, check.Name, fmt.Errorf("%s", reason))
+	}
+	return nil
+}
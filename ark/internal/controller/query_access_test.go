@@ -0,0 +1,76 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query Target Access", func() {
+	var (
+		ctx        context.Context
+		query      arkv1alpha1.Query
+		reviews    []authorizationv1.SubjectAccessReviewSpec
+		reconciler *QueryReconciler
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		reviews = nil
+		query = arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "default"},
+			Spec:       arkv1alpha1.QuerySpec{ServiceAccount: "analyst"},
+		}
+
+		s := runtime.NewScheme()
+		Expect(authorizationv1.AddToScheme(s)).To(Succeed())
+		// The service account may get agents but not teams, and may create events
+		reviewClient := fake.NewClientBuilder().WithScheme(s).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				review := obj.(*authorizationv1.SubjectAccessReview)
+				reviews = append(reviews, review.Spec)
+				review.Status.Allowed = review.Spec.ResourceAttributes.Resource != "teams"
+				return nil
+			},
+		}).Build()
+		reconciler = &QueryReconciler{Client: reviewClient}
+	})
+
+	It("should check get on the target and create on events as the query identity", func() {
+		target := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+		Expect(reconciler.checkTargetAccess(ctx, query, target, "shared")).To(Succeed())
+
+		Expect(reviews).To(HaveLen(2))
+		Expect(reviews[0].User).To(Equal("system:serviceaccount:default:analyst"))
+		Expect(*reviews[0].ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+			Verb: "get", Group: "ark.mckinsey.com", Resource: "agents", Namespace: "shared", Name: "weather",
+		}))
+		Expect(*reviews[1].ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+			Verb: "create", Resource: "events", Namespace: "default",
+		}))
+	})
+
+	It("should fail targets the query identity may not get with a forbidden error", func() {
+		err := reconciler.checkTargetAccess(ctx, query, arkv1alpha1.QueryTarget{Type: "team", Name: "planners"}, "default")
+		Expect(apierrors.IsForbidden(err)).To(BeTrue())
+		Expect(err.Error()).To(Equal(`teams.ark.mckinsey.com "planners" is forbidden: User "system:serviceaccount:default:analyst" cannot get resource "teams" in API group "ark.mckinsey.com" in the namespace "default"`))
+	})
+
+	It("should not check queries running with the controller identity", func() {
+		query.Spec.ServiceAccount = ""
+		Expect(reconciler.checkTargetAccess(ctx, query, arkv1alpha1.QueryTarget{Type: "team", Name: "planners"}, "default")).To(Succeed())
+		Expect(reviews).To(BeEmpty())
+	})
+})
@@ -0,0 +1,192 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// maxCheckpointResponseBytes caps the size of a response in the checkpoint, so the checkpoints of
+// queries with many targets stay well within the object size limit
+const maxCheckpointResponseBytes = 4 * 1024
+
+// queryCheckpoint persists the progress of a query execution in status.checkpoint. An execution
+// interrupted by a controller restart resumes from it: targets that completed keep their response
+// and are not run again, targets that were running start over.
+type queryCheckpoint struct {
+	client client.Client
+	key    types.NamespacedName
+	tokens *genai.TokenUsageCollector
+	// artifacts stores the responses too large for the checkpoint, which keeps a preview of them
+	// when nil
+	artifacts *genai.ResponseArtifacts
+	query     *arkv1alpha1.Query
+	resumed   bool
+	// previousTokens is the token usage of the interrupted executions
+	previousTokens arkv1alpha1.TokenUsage
+
+	mu              sync.Mutex
+	state           arkv1alpha1.QueryCheckpoint
+	resourceVersion string
+	// saveErr is the error of the last save, nil once a save succeeds
+	saveErr error
+}
+
+func newQueryCheckpoint(k8sClient client.Client, query *arkv1alpha1.Query, tokens *genai.TokenUsageCollector, artifacts *genai.ResponseArtifacts) *queryCheckpoint {
+	checkpoint := &queryCheckpoint{
+		client:    k8sClient,
+		key:       types.NamespacedName{Name: query.Name, Namespace: query.Namespace},
+		tokens:    tokens,
+		artifacts: artifacts,
+		query:     query.DeepCopy(),
+	}
+	if query.Status.Checkpoint != nil {
+		checkpoint.resumed = true
+		checkpoint.state = *query.Status.Checkpoint.DeepCopy()
+		checkpoint.state.Resumes++
+		checkpoint.previousTokens = checkpoint.state.TokenUsage
+	}
+	return checkpoint
+}
+
+// completedResponse returns the response of a target that completed before the execution was
+// interrupted. The response is nil for targets delegated to an external execution engine.
+func (c *queryCheckpoint) completedResponse(target arkv1alpha1.QueryTarget) (*arkv1alpha1.Response, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, progress := range c.state.Targets {
+		if progress.Target == target && progress.Phase != statusRunning {
+			return progress.Response.DeepCopy(), true
+		}
+	}
+	return nil, false
+}
+
+// start marks the targets as running and saves the checkpoint
+func (c *queryCheckpoint) start(ctx context.Context, targets []arkv1alpha1.QueryTarget) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, target := range targets {
+		c.setProgress(arkv1alpha1.QueryTargetProgress{Target: target, Phase: statusRunning})
+	}
+	c.save(ctx)
+}
+
+// complete records the response of a target and saves the checkpoint. response is nil for targets
+// delegated to an external execution engine.
+func (c *queryCheckpoint) complete(ctx context.Context, target arkv1alpha1.QueryTarget, response *arkv1alpha1.Response) {
+	phase := statusDone
+	if response != nil && response.Phase == statusError {
+		phase = statusError
+	}
+	response = c.checkpointResponse(ctx, target, response)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.setProgress(arkv1alpha1.QueryTargetProgress{Target: target, Phase: phase, Response: response})
+	c.save(ctx)
+}
+
+// checkpointResponse returns the response as it is kept in the checkpoint. Responses larger than
+// maxCheckpointResponseBytes are stored as artifacts, or keep a preview when they cannot be stored.
+func (c *queryCheckpoint) checkpointResponse(ctx context.Context, target arkv1alpha1.QueryTarget, response *arkv1alpha1.Response) *arkv1alpha1.Response {
+	if response == nil || len(response.Content)+len(response.Raw) <= maxCheckpointResponseBytes {
+		return response
+	}
+	if c.artifacts != nil {
+		stored, err := c.artifacts.OffloadAbove(ctx, c.query, c.artifactName(target), *response, maxCheckpointResponseBytes)
+		if err == nil {
+			return &stored
+		}
+		logf.FromContext(ctx).Error(err, "failed to store checkpoint response, keeping a preview", "query", c.key.String())
+	}
+	preview := genai.PreviewResponse(*response, maxCheckpointResponseBytes)
+	return &preview
+}
+
+// artifactName returns the name of the artifact of the checkpointed response of target
+func (c *queryCheckpoint) artifactName(target arkv1alpha1.QueryTarget) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	index := len(c.state.Targets)
+	for i, progress := range c.state.Targets {
+		if progress.Target == target {
+			index = i
+			break
+		}
+	}
+	return fmt.Sprintf("%s-checkpoint-%d", c.key.Name, index)
+}
+
+// tokenUsage returns the token usage of the execution, including the interrupted executions
+func (c *queryCheckpoint) tokenUsage() arkv1alpha1.TokenUsage {
+	summary := c.tokens.GetTokenSummary()
+	return arkv1alpha1.TokenUsage{
+		PromptTokens:     c.previousTokens.PromptTokens + summary.PromptTokens,
+		CompletionTokens: c.previousTokens.CompletionTokens + summary.CompletionTokens,
+		TotalTokens:      c.previousTokens.TotalTokens + summary.TotalTokens,
+		ReasoningTokens:  c.previousTokens.ReasoningTokens + summary.ReasoningTokens,
+	}
+}
+
+// refresh sets the resource version of query to the one of the last saved checkpoint, so that the
+// final status update of the execution does not conflict with the checkpoints. When the last save
+// failed, the failure is recorded in the Checkpointed condition of query.
+func (c *queryCheckpoint) refresh(query *arkv1alpha1.Query) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.resourceVersion != "" {
+		query.ResourceVersion = c.resourceVersion
+	}
+	if c.saveErr != nil {
+		meta.SetStatusCondition(&query.Status.Conditions, metav1.Condition{
+			Type:               string(arkv1alpha1.QueryCheckpointed),
+			Status:             metav1.ConditionFalse,
+			Reason:             "CheckpointSaveFailed",
+			Message:            fmt.Sprintf("The progress of the query could not be saved: %v", c.saveErr),
+			ObservedGeneration: query.Generation,
+		})
+	}
+}
+
+func (c *queryCheckpoint) setProgress(progress arkv1alpha1.QueryTargetProgress) {
+	for i := range c.state.Targets {
+		if c.state.Targets[i].Target == progress.Target {
+			c.state.Targets[i] = progress
+			return
+		}
+	}
+	c.state.Targets = append(c.state.Targets, progress)
+}
+
+// save patches the checkpoint into the query status. Failures do not stop the execution: a lost
+// checkpoint means more targets run again if the execution is interrupted.
+func (c *queryCheckpoint) save(ctx context.Context) {
+	if ctx.Err() != nil {
+		return
+	}
+	c.state.TokenUsage = c.tokenUsage()
+	c.state.UpdatedAt = metav1.Now()
+
+	base := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: c.key.Name, Namespace: c.key.Namespace}}
+	query := base.DeepCopy()
+	query.Status.Checkpoint = c.state.DeepCopy()
+	if err := c.client.Status().Patch(ctx, query, client.MergeFrom(base)); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to save query checkpoint", "query", c.key.String())
+		c.saveErr = err
+		return
+	}
+	c.resourceVersion = query.ResourceVersion
+	c.saveErr = nil
+}
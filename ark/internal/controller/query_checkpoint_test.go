@@ -0,0 +1,144 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var _ = Describe("Query Checkpoint", func() {
+	var (
+		ctx        context.Context
+		fakeClient client.Client
+		weather    = arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"}
+		math       = arkv1alpha1.QueryTarget{Type: "agent", Name: "math"}
+		key        = types.NamespacedName{Name: "forecast", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(query).WithStatusSubresource(query).Build()
+	})
+
+	getQuery := func() *arkv1alpha1.Query {
+		query := &arkv1alpha1.Query{}
+		Expect(fakeClient.Get(ctx, key, query)).To(Succeed())
+		return query
+	}
+
+	It("should resume from the targets that completed", func() {
+		checkpoint := newQueryCheckpoint(fakeClient, getQuery(), genai.NewTokenUsageCollector(nil), nil)
+		Expect(checkpoint.resumed).To(BeFalse())
+
+		checkpoint.start(ctx, []arkv1alpha1.QueryTarget{weather, math})
+		checkpoint.complete(ctx, weather, &arkv1alpha1.Response{Target: weather, Content: "sunny", Phase: statusDone})
+
+		saved := getQuery()
+		Expect(saved.Status.Checkpoint).NotTo(BeNil())
+		Expect(saved.Status.Checkpoint.Targets).To(HaveLen(2))
+
+		saved.Status.Checkpoint.TokenUsage = arkv1alpha1.TokenUsage{TotalTokens: 40}
+		resumed := newQueryCheckpoint(fakeClient, saved, genai.NewTokenUsageCollector(nil), nil)
+		Expect(resumed.resumed).To(BeTrue())
+		Expect(resumed.state.Resumes).To(Equal(int32(1)))
+		Expect(resumed.tokenUsage().TotalTokens).To(Equal(int64(40)))
+
+		response, completed := resumed.completedResponse(weather)
+		Expect(completed).To(BeTrue())
+		Expect(response.Content).To(Equal("sunny"))
+		_, completed = resumed.completedResponse(math)
+		Expect(completed).To(BeFalse())
+	})
+
+	It("should not run completed targets again", func() {
+		query := getQuery()
+		query.Status.Checkpoint = &arkv1alpha1.QueryCheckpoint{Targets: []arkv1alpha1.QueryTargetProgress{
+			{Target: weather, Phase: statusDone, Response: &arkv1alpha1.Response{Target: weather, Content: "sunny", Phase: statusDone}},
+			{Target: math, Phase: statusError, Response: &arkv1alpha1.Response{Target: math, Content: "model unavailable", Phase: statusError}},
+		}}
+		checkpoint := newQueryCheckpoint(fakeClient, query, genai.NewTokenUsageCollector(nil), nil)
+
+		reconciler := &QueryReconciler{Client: fakeClient}
+		responses, failures := reconciler.executeTargetsInParallel(ctx, *query, []arkv1alpha1.QueryTarget{weather, math}, fakeClient, nil, nil, nil, checkpoint)
+		Expect(responses).To(HaveLen(2))
+		Expect(failures).To(HaveLen(1))
+		Expect(failures[0].Errors).To(ConsistOf("model unavailable"))
+	})
+
+	It("should keep a preview of large responses", func() {
+		checkpoint := newQueryCheckpoint(fakeClient, getQuery(), genai.NewTokenUsageCollector(nil), nil)
+		checkpoint.start(ctx, []arkv1alpha1.QueryTarget{weather})
+		checkpoint.complete(ctx, weather, &arkv1alpha1.Response{
+			Target: weather, Content: strings.Repeat("a", 2*maxCheckpointResponseBytes), Raw: "[]", Phase: statusDone,
+		})
+
+		response := getQuery().Status.Checkpoint.Targets[0].Response
+		Expect(len(response.Content)).To(BeNumerically("<", 2*maxCheckpointResponseBytes))
+		Expect(response.Raw).To(BeEmpty())
+		Expect(response.Phase).To(Equal(statusDone))
+	})
+
+	It("should store large responses as artifacts", func() {
+		s := fakeClient.Scheme()
+		Expect(corev1.AddToScheme(s)).To(Succeed())
+		artifacts := &genai.ResponseArtifacts{Store: &genai.ConfigMapArtifactStore{Client: fakeClient, Scheme: s}}
+		checkpoint := newQueryCheckpoint(fakeClient, getQuery(), genai.NewTokenUsageCollector(nil), artifacts)
+		checkpoint.start(ctx, []arkv1alpha1.QueryTarget{weather})
+		checkpoint.complete(ctx, weather, &arkv1alpha1.Response{
+			Target: weather, Content: strings.Repeat("a", 2*maxCheckpointResponseBytes), Phase: statusDone,
+		})
+
+		response := getQuery().Status.Checkpoint.Targets[0].Response
+		Expect(response.Artifact).NotTo(BeNil())
+		Expect(response.Artifact.Name).To(Equal("forecast-checkpoint-0"))
+	})
+
+	It("should record a failed save in the Checkpointed condition", func() {
+		failingClient := interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+			SubResourcePatch: func(context.Context, client.Client, string, client.Object, client.Patch, ...client.SubResourcePatchOption) error {
+				return errors.New("request entity too large")
+			},
+		})
+		checkpoint := newQueryCheckpoint(failingClient, getQuery(), genai.NewTokenUsageCollector(nil), nil)
+		checkpoint.start(ctx, []arkv1alpha1.QueryTarget{weather})
+
+		query := getQuery()
+		checkpoint.refresh(query)
+		condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryCheckpointed))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Message).To(ContainSubstring("request entity too large"))
+	})
+
+	It("should clear the checkpoint when the query completes", func() {
+		query := getQuery()
+		query.Status.Checkpoint = &arkv1alpha1.QueryCheckpoint{Resumes: 1}
+		reconciler := &QueryReconciler{Client: fakeClient}
+		Expect(reconciler.updateStatus(ctx, query, statusDone)).To(Succeed())
+		Expect(getQuery().Status.Checkpoint).To(BeNil())
+	})
+})
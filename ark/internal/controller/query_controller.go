@@ -5,8 +5,10 @@ package controller
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/openai/openai-go"
@@ -23,14 +25,21 @@ import (
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
 	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry"
 	telemetryconfig "mckinsey.com/ark/internal/telemetry/config"
+	"mckinsey.com/ark/internal/telemetry/metrics"
 )
 
 type targetResult struct {
 	messages []genai.Message
 	err      error
 	target   arkv1alpha1.QueryTarget
+	// response is nil for targets delegated to external execution engines
+	response *arkv1alpha1.Response
+	// members of a team target as resolved for the execution
+	members []arkv1alpha1.TeamMember
 }
 
 // QueryReconciler reconciles a Query object with telemetry abstraction.
@@ -43,10 +52,34 @@ type targetResult struct {
 // - Never import OTEL packages directly - use the abstraction layer
 type QueryReconciler struct {
 	client.Client
-	Scheme     *runtime.Scheme
-	Recorder   record.EventRecorder
-	Telemetry  *telemetryconfig.Provider
+	Scheme    *runtime.Scheme
+	Recorder  record.EventRecorder
+	Telemetry *telemetryconfig.Provider
+	AuditSink genai.AuditSink
+	Artifacts *genai.ResponseArtifacts
+	Events    genai.EventSettings
+	Notifier  *genai.Notifier
+	// Execution selects whether running queries are executed by this controller or by executor pods
+	Execution QueryExecutionMode
+	// Watchdog restarts or fails running queries whose execution was lost
+	Watchdog   QueryWatchdog
 	operations sync.Map
+	// reloadedEvents replaces Events once SetEventSettings is called
+	reloadedEvents atomic.Pointer[genai.EventSettings]
+}
+
+// SetEventSettings replaces the controller event settings, for example when the controller config
+// is reloaded. They apply to queries reconciled afterwards.
+func (r *QueryReconciler) SetEventSettings(settings genai.EventSettings) {
+	r.reloadedEvents.Store(&settings)
+}
+
+// eventSettings returns the controller event settings
+func (r *QueryReconciler) eventSettings() genai.EventSettings {
+	if settings := r.reloadedEvents.Load(); settings != nil {
+		return *settings
+	}
+	return r.Events
 }
 
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;create;update;patch;delete
@@ -55,8 +88,23 @@ type QueryReconciler struct {
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=agents,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=teams,verbs=get;list
 // +kubebuilder:rbac:groups=ark.mckinsey.com,resources=models,verbs=get;list
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=guardrails,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=notificationsinks,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=querytemplates,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=referencegrants,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluations,verbs=get;create
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=toolapprovals,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=toolapprovals/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;list;watch;patch
+// Impersonating users, groups and uids for spec.impersonate is only granted by the chart when
+// rbac.impersonation.users is set
 // +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=impersonate
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get;list;watch;create;update
+// +kubebuilder:rbac:groups=batch,resources=jobs,verbs=get;list;watch;create
+// +kubebuilder:rbac:groups="",resources=pods,verbs=get;list;watch
+// +kubebuilder:rbac:groups=networking.k8s.io,resources=networkpolicies,verbs=get;create;update
+// +kubebuilder:rbac:groups=coordination.k8s.io,resources=leases,verbs=get;create;update
 
 func (r *QueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
@@ -69,9 +117,8 @@ func (r *QueryReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl
 		return ctrl.Result{}, client.IgnoreNotFound(err)
 	}
 
-	expiry := obj.CreationTimestamp.Add(obj.Spec.TTL.Duration)
-	if time.Now().After(expiry) {
-		// TTL expired: delete the object
+	if expiry, expires := queryExpiry(&obj); expires && !time.Now().Before(expiry) {
+		// Retention expired: delete the object
 		if err := r.Delete(ctx, &obj); err != nil {
 			log.Error(err, "unable to delete object")
 			return ctrl.Result{}, err
@@ -115,30 +162,26 @@ func (r *QueryReconciler) handleFinalizer(ctx context.Context, obj *arkv1alpha1.
 }
 
 func (r *QueryReconciler) handleQueryExecution(ctx context.Context, req ctrl.Request, obj arkv1alpha1.Query) (ctrl.Result, error) {
-	expiry := obj.CreationTimestamp.Add(obj.Spec.TTL.Duration)
-
 	if obj.Spec.Cancel && obj.Status.Phase != statusCanceled {
 		r.cleanupExistingOperation(req.NamespacedName)
 		if err := r.updateStatus(ctx, &obj, statusCanceled); err != nil {
-			return ctrl.Result{
-				RequeueAfter: time.Until(expiry),
-			}, err
+			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
 
 	switch obj.Status.Phase {
 	case statusDone, statusError, statusCanceled:
-		return ctrl.Result{
-			RequeueAfter: time.Until(expiry),
-		}, nil
+		// Requeue finished queries to delete them when their retention expires
+		if expiry, expires := queryExpiry(&obj); expires {
+			return ctrl.Result{RequeueAfter: time.Until(expiry)}, nil
+		}
+		return ctrl.Result{}, nil
 	case statusRunning:
 		return r.handleRunningPhase(ctx, req, obj)
 	default:
 		if err := r.updateStatus(ctx, &obj, statusRunning); err != nil {
-			return ctrl.Result{
-				RequeueAfter: time.Until(expiry),
-			}, err
+			return ctrl.Result{}, err
 		}
 		return ctrl.Result{}, nil
 	}
@@ -147,29 +190,68 @@ func (r *QueryReconciler) handleQueryExecution(ctx context.Context, req ctrl.Req
 func (r *QueryReconciler) handleRunningPhase(ctx context.Context, req ctrl.Request, obj arkv1alpha1.Query) (ctrl.Result, error) {
 	log := logf.FromContext(ctx)
 
+	// Running queries are checked again after the grace period, so that the watchdog notices when
+	// their execution is lost
 	if _, exists := r.operations.Load(req.NamespacedName); exists {
 		log.Info("Exists")
+		return ctrl.Result{RequeueAfter: r.Watchdog.GracePeriod}, nil
+	}
+
+	// Dry runs call no model or tool, so the controller plans them even with executor pods
+	if obj.Spec.DryRun {
+		return ctrl.Result{}, r.dryRunQuery(ctx, &obj)
+	}
+
+	if err := r.checkA2ARequirements(ctx, &obj); err != nil {
+		return ctrl.Result{}, r.failA2ARequirements(ctx, &obj, err)
+	}
+
+	// Executor pods claim and run the query, see QueryExecutor
+	if r.Execution == QueryExecutionExecutor {
 		return ctrl.Result{}, nil
 	}
 
+	if r.Watchdog.stuck(&obj, time.Now()) {
+		if r.Watchdog.Policy == StuckQueryFail {
+			return ctrl.Result{}, r.failStuckQuery(ctx, &obj)
+		}
+		log.Info("restarting stuck query", "query", req.NamespacedName.String(), "lastProgress", lastQueryProgress(&obj))
+		if r.Recorder != nil {
+			r.Recorder.Event(&obj, corev1.EventTypeWarning, stuckQueryReason, "Query execution was lost, restarting from the last checkpoint")
+		}
+	}
+
+	r.startQuery(ctx, req.NamespacedName, obj, nil)
+	return ctrl.Result{RequeueAfter: r.Watchdog.GracePeriod}, nil
+}
+
+// startQuery executes the query in the background. done, if set, is called once the execution ends.
+func (r *QueryReconciler) startQuery(ctx context.Context, namespacedName types.NamespacedName, obj arkv1alpha1.Query, done func()) {
 	opCtx, cancel := context.WithCancel(ctx)
-	r.operations.Store(req.NamespacedName, cancel)
-	recorder := genai.NewQueryRecorder(&obj, r.Recorder)
-	tokenCollector := genai.NewTokenUsageCollector(recorder)
+	r.operations.Store(namespacedName, cancel)
+	recorder := genai.NewQueryRecorder(&obj, r.Recorder, genai.NamespaceEventSettings(ctx, r.Client, obj.Namespace, r.eventSettings()))
+	auditCollector := genai.NewAuditCollector(recorder)
+	tokenCollector := genai.NewTokenUsageCollector(auditCollector)
 
-	queryTracker := genai.NewOperationTracker(tokenCollector, opCtx, "QueryResolve", obj.Name, map[string]string{
+	queryTracker := genai.NewOperationTracker(tokenCollector, opCtx, genai.OperationQueryResolve, obj.Name, map[string]string{
 		"namespace": obj.Namespace,
 		"targets":   fmt.Sprintf("%d", len(obj.Spec.Targets)),
 	})
 
-	go r.executeQueryAsync(opCtx, obj, req.NamespacedName, queryTracker, tokenCollector)
-	return ctrl.Result{}, nil
+	go func() {
+		if done != nil {
+			defer done()
+		}
+		defer recorder.Flush(opCtx)
+		r.executeQueryAsync(opCtx, obj, namespacedName, queryTracker, tokenCollector, auditCollector)
+	}()
 }
 
-func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alpha1.Query, namespacedName types.NamespacedName, queryTracker *genai.OperationTracker, tokenCollector *genai.TokenUsageCollector) {
+func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alpha1.Query, namespacedName types.NamespacedName, queryTracker *genai.OperationTracker, tokenCollector *genai.TokenUsageCollector, auditCollector *genai.AuditCollector) {
 	log := logf.FromContext(opCtx)
 	cleanupCache := true
 	startTime := time.Now()
+	var executionErr error
 
 	defer func() {
 		if r := recover(); r != nil {
@@ -180,12 +262,47 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 		}
 	}()
 
+	defer func() {
+		r.writeAuditRecord(opCtx, &obj, auditCollector, tokenCollector, startTime, executionErr)
+		r.notifyQueryEnd(opCtx, &obj)
+	}()
+
 	// Start session-aware query tracing using new abstraction
 	sessionId := obj.Spec.SessionId
 	if sessionId == "" {
 		sessionId = string(obj.UID)
 	}
 
+	// Redaction must be enabled before the query span starts so that no content is recorded unredacted
+	if obj.Spec.DataPolicy == arkv1alpha1.DataPolicyRedactPII {
+		redactor, err := genai.NewPIIRedactor(opCtx, r.Client, obj.Namespace, r.Telemetry.ModelRecorder())
+		if err != nil {
+			executionErr = fmt.Errorf("failed to set up PII redaction: %w", err)
+			queryTracker.Fail(executionErr)
+			_ = r.updateStatus(opCtx, &obj, statusError)
+			return
+		}
+		opCtx = genai.WithPIIRedaction(opCtx, redactor)
+	}
+
+	// Namespace trace settings select the sampling ratio and content capture for the whole query
+	opCtx = r.Telemetry.WithNamespaceTraceSettings(opCtx, r.Client, obj.Namespace)
+	if environment := obj.Annotations[annotations.TelemetryEnvironment]; environment != "" {
+		environmentCtx, err := telemetry.WithEnvironment(opCtx, r.Telemetry.TraceSettings(), environment)
+		if err != nil {
+			log.Error(err, "ignoring telemetry environment annotation", "query", obj.Name)
+		} else {
+			opCtx = environmentCtx
+		}
+	}
+
+	// Tools check the namespace tool policy when they run, which the query identity may not be allowed to read
+	opCtx = genai.WithToolPolicyClient(opCtx, r.Client)
+
+	// Model calls with pricing add their cost, which is reported in the query status
+	costTracker := genai.NewCostTracker()
+	opCtx = genai.WithCostTracker(opCtx, costTracker)
+
 	// Create query execution span with session tracking.
 	// This span represents the entire query lifecycle and includes:
 	// - Session correlation for multi-query conversations
@@ -193,12 +310,34 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 	opCtx, span := r.Telemetry.QueryRecorder().StartQuery(opCtx, obj.Name, obj.Namespace, "execute")
 	r.Telemetry.QueryRecorder().RecordSessionID(span, sessionId)
 	defer span.End()
+	obj.Status.TraceID, obj.Status.TraceURL = telemetry.TraceLink(span)
+
+	checkpoint := newQueryCheckpoint(r.Client, &obj, tokenCollector, r.Artifacts)
+	if checkpoint.resumed {
+		log.Info("resuming query from checkpoint", "query", namespacedName.String(), "resumes", checkpoint.state.Resumes)
+	}
 
 	impersonatedClient, memory, err := r.setupQueryExecution(opCtx, obj, queryTracker, tokenCollector, sessionId)
 	if err != nil {
+		executionErr = err
+		r.Telemetry.QueryRecorder().RecordError(span, err)
+		r.recordFailure(opCtx, &obj, nil, []targetFailure{newTargetFailure(nil, nil, err)})
+		return
+	}
+
+	// Model calls are recorded, or answered from a recording, for the whole query
+	recording, err := r.modelCallRecording(opCtx, obj)
+	if err != nil {
+		executionErr = err
+		queryTracker.Fail(err)
 		r.Telemetry.QueryRecorder().RecordError(span, err)
+		_ = r.updateStatus(opCtx, &obj, statusError)
+		r.recordFailure(opCtx, &obj, nil, []targetFailure{newTargetFailure(nil, nil, err)})
 		return
 	}
+	if recording != nil {
+		opCtx = genai.WithModelCallRecording(opCtx, recording)
+	}
 
 	inputMessages, err := genai.GetQueryInputMessages(opCtx, obj, impersonatedClient)
 	if err == nil {
@@ -206,30 +345,38 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 		r.Telemetry.QueryRecorder().RecordRootInput(span, queryInput)
 	}
 
-	responses, eventStream, err := r.reconcileQueue(opCtx, obj, impersonatedClient, memory, tokenCollector)
+	responses, failures, eventStream, err := r.reconcileQueue(opCtx, obj, impersonatedClient, memory, tokenCollector, checkpoint)
 	if err != nil {
+		executionErr = err
 		queryTracker.Fail(err)
 		r.Telemetry.QueryRecorder().RecordError(span, err)
+		r.saveRecording(opCtx, &obj, recording)
 		_ = r.updateStatus(opCtx, &obj, statusError)
+		r.recordFailure(opCtx, &obj, inputMessages, []targetFailure{newTargetFailure(nil, nil, err)})
 		return
 	}
 
 	queryTracker.Complete("resolved")
-	obj.Status.Responses = responses
+	obj.Status.ResolvedTargets = countTargets(responses)
 
 	if len(responses) > 0 && responses[0].Phase == statusDone {
 		r.Telemetry.QueryRecorder().RecordRootOutput(span, responses[0].Content)
 	}
+	obj.Status.Responses = r.offloadLargeResponses(opCtx, &obj, responses)
 
 	tokenSummary := tokenCollector.GetTokenSummary()
-	obj.Status.TokenUsage = arkv1alpha1.TokenUsage{
-		PromptTokens:     tokenSummary.PromptTokens,
-		CompletionTokens: tokenSummary.CompletionTokens,
-		TotalTokens:      tokenSummary.TotalTokens,
-	}
+	obj.Status.TokenUsage = checkpoint.tokenUsage()
+	obj.Status.TokenUsageDetails = tokenCollector.GetTokenUsageDetails()
+	obj.Status.Cost = costTracker.Total()
+	r.saveRecording(opCtx, &obj, recording)
+	checkpoint.refresh(&obj)
 
 	// Record token usage in telemetry span
 	r.Telemetry.QueryRecorder().RecordTokenUsage(span, tokenSummary.PromptTokens, tokenSummary.CompletionTokens, tokenSummary.TotalTokens)
+	for _, detail := range obj.Status.TokenUsageDetails {
+		r.Telemetry.QueryRecorder().RecordTokenUsageDetail(span, detail.Target, detail.Team, detail.Agent, detail.Model,
+			detail.TokenUsage.PromptTokens, detail.TokenUsage.CompletionTokens, detail.TokenUsage.TotalTokens)
+	}
 
 	// Set overall query status based on whether any targets failed
 	queryStatus := r.determineQueryStatus(responses)
@@ -237,12 +384,65 @@ func (r *QueryReconciler) executeQueryAsync(opCtx context.Context, obj arkv1alph
 
 	duration := &metav1.Duration{Duration: time.Since(startTime)}
 	r.finalizeEventStream(opCtx, eventStream)
+	r.checkQuerySLO(&obj, duration.Duration)
 	_ = r.updateStatusWithDuration(opCtx, &obj, queryStatus, duration)
 
+	// The failure record annotation is patched after the final status update so it cannot conflict with it
+	if queryStatus == statusError {
+		r.recordFailure(opCtx, &obj, inputMessages, failures)
+	}
+	if queryStatus == statusDone {
+		r.evaluateTemplateQuery(opCtx, &obj)
+	}
+
 	// Mark span as successful
 	r.Telemetry.QueryRecorder().RecordSuccess(span)
 }
 
+// writeAuditRecord writes the audit record of a query execution to the configured audit sink.
+// Failures are logged rather than failing the query.
+func (r *QueryReconciler) writeAuditRecord(ctx context.Context, query *arkv1alpha1.Query, auditCollector *genai.AuditCollector, tokenCollector *genai.TokenUsageCollector, startTime time.Time, executionErr error) {
+	if r.AuditSink == nil {
+		return
+	}
+
+	targets := make([]string, 0, len(query.Spec.Targets))
+	if len(query.Status.Responses) > 0 {
+		for _, response := range query.Status.Responses {
+			targets = append(targets, fmt.Sprintf("%s/%s", response.Target.Type, response.Target.Name))
+		}
+	} else {
+		for _, target := range query.Spec.Targets {
+			targets = append(targets, fmt.Sprintf("%s/%s", target.Type, target.Name))
+		}
+	}
+
+	record := genai.AuditRecord{
+		Timestamp:      time.Now().UTC(),
+		Query:          query.Name,
+		Namespace:      query.Namespace,
+		QueryUID:       string(query.UID),
+		CreatedBy:      query.Annotations[annotations.CreatedBy],
+		ServiceAccount: query.Spec.ServiceAccount,
+		Targets:        targets,
+		ToolCalls:      auditCollector.ToolCalls(),
+		ModelCalls:     auditCollector.ModelCalls(),
+		TokenUsage:     tokenCollector.GetTokenSummary(),
+		Status:         query.Status.Phase,
+		StartTime:      startTime.UTC(),
+		Duration:       time.Since(startTime).String(),
+	}
+	if executionErr != nil {
+		record.Status = statusError
+		record.Error = executionErr.Error()
+	}
+
+	// Write the record even if the query was canceled or timed out
+	if err := r.AuditSink.Write(context.WithoutCancel(ctx), record); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to write query audit record", "query", query.Name, "namespace", query.Namespace)
+	}
+}
+
 // finalizeEventStream sends the completion message to the event stream and
 // closes its connection.
 func (r *QueryReconciler) finalizeEventStream(ctx context.Context, eventStream genai.EventStreamInterface) {
@@ -284,7 +484,7 @@ func (r *QueryReconciler) setupQueryExecution(opCtx context.Context, obj arkv1al
 		return nil, nil, err
 	}
 
-	return impersonatedClient, memory, nil
+	return impersonatedClient, genai.NewRedactingMemory(memory, genai.PIIRedactorFromContext(opCtx)), nil
 }
 
 func (r *QueryReconciler) resolveTargets(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client) ([]arkv1alpha1.QueryTarget, error) {
@@ -303,94 +503,115 @@ func (r *QueryReconciler) resolveTargets(ctx context.Context, query arkv1alpha1.
 	return allTargets, nil
 }
 
-func (r *QueryReconciler) resolveSelector(ctx context.Context, selector *metav1.LabelSelector, namespace string, impersonatedClient client.Client) ([]arkv1alpha1.QueryTarget, error) {
+// selectableKinds lists the resources a query selector can match, in resolution order
+var selectableKinds = []struct {
+	kind       arkv1alpha1.TargetKind
+	targetType string
+	newList    func() client.ObjectList
+}{
+	{arkv1alpha1.TargetKindAgent, "agent", func() client.ObjectList { return &arkv1alpha1.AgentList{} }},
+	{arkv1alpha1.TargetKindTeam, "team", func() client.ObjectList { return &arkv1alpha1.TeamList{} }},
+	{arkv1alpha1.TargetKindModel, "model", func() client.ObjectList { return &arkv1alpha1.ModelList{} }},
+	{arkv1alpha1.TargetKindTool, "tool", func() client.ObjectList { return &arkv1alpha1.ToolList{} }},
+}
+
+// resolveSelector lists the resources matching the selector in each selected namespace. Targets in
+// the query namespace keep an empty namespace so their responses look the same as explicit targets.
+func (r *QueryReconciler) resolveSelector(ctx context.Context, selector *arkv1alpha1.TargetSelector, namespace string, impersonatedClient client.Client) ([]arkv1alpha1.QueryTarget, error) {
 	targets := make([]arkv1alpha1.QueryTarget, 0, 10)
 
-	labelSelector, err := metav1.LabelSelectorAsSelector(selector)
+	labelSelector, err := metav1.LabelSelectorAsSelector(&selector.LabelSelector)
 	if err != nil {
 		return nil, fmt.Errorf("invalid label selector: %w", err)
 	}
 
-	// Search for agents
-	var agentList arkv1alpha1.AgentList
-	if err := impersonatedClient.List(ctx, &agentList, &client.ListOptions{
-		Namespace:     namespace,
-		LabelSelector: labelSelector,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to list agents: %w", err)
-	}
-
-	for _, agent := range agentList.Items {
-		targets = append(targets, arkv1alpha1.QueryTarget{
-			Type: "agent",
-			Name: agent.Name,
-		})
+	namespaces := selector.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{namespace}
 	}
 
-	// Search for teams
-	var teamList arkv1alpha1.TeamList
-	if err := impersonatedClient.List(ctx, &teamList, &client.ListOptions{
-		Namespace:     namespace,
-		LabelSelector: labelSelector,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to list teams: %w", err)
-	}
+	for _, ns := range namespaces {
+		for _, selectable := range selectableKinds {
+			if !selector.SelectsKind(selectable.kind) {
+				continue
+			}
 
-	for _, team := range teamList.Items {
-		targets = append(targets, arkv1alpha1.QueryTarget{
-			Type: "team",
-			Name: team.Name,
-		})
-	}
+			list := selectable.newList()
+			if err := impersonatedClient.List(ctx, list, &client.ListOptions{
+				Namespace:     ns,
+				LabelSelector: labelSelector,
+			}); err != nil {
+				return nil, fmt.Errorf("failed to list %ss in namespace %s: %w", selectable.targetType, ns, err)
+			}
 
-	// Search for models
-	var modelList arkv1alpha1.ModelList
-	if err := impersonatedClient.List(ctx, &modelList, &client.ListOptions{
-		Namespace:     namespace,
-		LabelSelector: labelSelector,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to list models: %w", err)
+			if err := meta.EachListItem(list, func(obj runtime.Object) error {
+				name := obj.(client.Object).GetName()
+				if selector.Excludes(arkv1alpha1.QueryTarget{Type: selectable.targetType, Name: name, Namespace: ns}) {
+					return nil
+				}
+				target := arkv1alpha1.QueryTarget{Type: selectable.targetType, Name: name}
+				if ns != namespace {
+					// Selectors only match resources of other namespaces that are granted to the query
+					err := genai.CheckReferenceGrant(ctx, r.Client, arkv1alpha1.ReferenceFromQuery, namespace, selectable.kind, name, ns)
+					if genai.IsReferenceNotGranted(err) {
+						return nil
+					}
+					if err != nil {
+						return err
+					}
+					target.Namespace = ns
+				}
+				targets = append(targets, target)
+				return nil
+			}); err != nil {
+				return nil, err
+			}
+		}
 	}
 
-	for _, model := range modelList.Items {
-		targets = append(targets, arkv1alpha1.QueryTarget{
-			Type: "model",
-			Name: model.Name,
-		})
-	}
+	return targets, nil
+}
 
-	// Search for tools
-	var toolList arkv1alpha1.ToolList
-	if err := impersonatedClient.List(ctx, &toolList, &client.ListOptions{
-		Namespace:     namespace,
-		LabelSelector: labelSelector,
-	}); err != nil {
-		return nil, fmt.Errorf("failed to list tools: %w", err)
+// countTargets counts the resolved targets by kind. Every target produces exactly one response.
+func countTargets(responses []arkv1alpha1.Response) *arkv1alpha1.ResolvedTargets {
+	counts := &arkv1alpha1.ResolvedTargets{}
+	for _, response := range responses {
+		switch response.Target.Type {
+		case "agent":
+			counts.Agents++
+		case "team":
+			counts.Teams++
+		case "model":
+			counts.Models++
+		case "tool":
+			counts.Tools++
+		}
 	}
+	return counts
+}
 
-	for _, tool := range toolList.Items {
-		targets = append(targets, arkv1alpha1.QueryTarget{
-			Type: "tool",
-			Name: tool.Name,
-		})
+// targetKey returns the name and namespace of a target, which defaults to the query namespace
+func targetKey(query arkv1alpha1.Query, target arkv1alpha1.QueryTarget) types.NamespacedName {
+	namespace := target.Namespace
+	if namespace == "" {
+		namespace = query.Namespace
 	}
-
-	return targets, nil
+	return types.NamespacedName{Name: target.Name, Namespace: namespace}
 }
 
-func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]arkv1alpha1.Response, genai.EventStreamInterface, error) {
+func (r *QueryReconciler) reconcileQueue(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector, checkpoint *queryCheckpoint) ([]arkv1alpha1.Response, []targetFailure, genai.EventStreamInterface, error) {
 	eventStream, err := r.createEventStreamIfNeeded(ctx, query)
 	if err != nil {
-		return nil, nil, err
+		return nil, nil, nil, err
 	}
 
 	targets, err := r.resolveTargets(ctx, query, impersonatedClient)
 	if err != nil {
-		return nil, nil, fmt.Errorf("failed to resolve targets: %w", err)
+		return nil, nil, nil, fmt.Errorf("failed to resolve targets: %w", err)
 	}
 
-	allResponses := r.executeTargetsInParallel(ctx, query, targets, impersonatedClient, memory, eventStream, tokenCollector)
-	return allResponses, eventStream, nil
+	allResponses, failures := r.executeTargetsInParallel(ctx, query, targets, impersonatedClient, memory, eventStream, tokenCollector, checkpoint)
+	return allResponses, failures, eventStream, nil
 }
 
 func (r *QueryReconciler) createEventStreamIfNeeded(ctx context.Context, query arkv1alpha1.Query) (genai.EventStreamInterface, error) {
@@ -417,16 +638,37 @@ func (r *QueryReconciler) createEventStreamIfNeeded(ctx context.Context, query a
 	return eventStream, nil
 }
 
-func (r *QueryReconciler) executeTargetsInParallel(ctx context.Context, query arkv1alpha1.Query, targets []arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) []arkv1alpha1.Response {
+// executeTargetsInParallel runs the targets that have not completed according to the checkpoint,
+// and saves the checkpoint as each target completes
+func (r *QueryReconciler) executeTargetsInParallel(ctx context.Context, query arkv1alpha1.Query, targets []arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector, checkpoint *queryCheckpoint) ([]arkv1alpha1.Response, []targetFailure) {
 	resultChan := make(chan targetResult, len(targets))
 	var wg sync.WaitGroup
 
+	var pending []arkv1alpha1.QueryTarget
 	for _, target := range targets {
+		response, completed := checkpoint.completedResponse(target)
+		if !completed {
+			pending = append(pending, target)
+			continue
+		}
+		result := targetResult{target: target, response: response}
+		if response != nil && response.Phase == statusError {
+			result.err = errors.New(response.Content)
+		}
+		resultChan <- result
+	}
+	checkpoint.start(ctx, pending)
+
+	for _, target := range pending {
 		wg.Add(1)
 		go func(target arkv1alpha1.QueryTarget) {
 			defer wg.Done()
-			responses, err := r.executeTarget(ctx, query, target, impersonatedClient, memory, eventStream, tokenCollector)
-			resultChan <- targetResult{responses, err, target}
+			targetCtx, members := withTeamMembership(ctx)
+			messages, err := r.executeTarget(targetCtx, query, target, impersonatedClient, memory, eventStream, tokenCollector)
+			result := targetResult{messages: messages, err: err, target: target, members: *members}
+			result.response = r.targetResponse(result)
+			checkpoint.complete(ctx, target, result.response)
+			resultChan <- result
 		}(target)
 	}
 
@@ -436,22 +678,39 @@ func (r *QueryReconciler) executeTargetsInParallel(ctx context.Context, query ar
 	return r.processTargetResults(resultChan)
 }
 
-func (r *QueryReconciler) processTargetResults(resultChan chan targetResult) []arkv1alpha1.Response {
+// processTargetResults returns the response of every target and the failure context of the failed ones
+func (r *QueryReconciler) processTargetResults(resultChan chan targetResult) ([]arkv1alpha1.Response, []targetFailure) {
 	var allResponses []arkv1alpha1.Response
+	var failures []targetFailure
 
 	for result := range resultChan {
-		switch {
-		case result.err != nil:
-			allResponses = append(allResponses, r.createErrorResponse(result.target, result.err))
-		case result.messages == nil:
-			// Skip targets that were delegated to external execution engines (messages == nil)
-		default:
-			response := r.createSuccessResponse(result.target, result.messages)
-			allResponses = append(allResponses, response)
+		if result.err != nil {
+			failures = append(failures, newTargetFailure(&result.target, result.messages, result.err))
+		}
+		if result.response != nil {
+			allResponses = append(allResponses, *result.response)
 		}
 	}
 
-	return allResponses
+	return allResponses, failures
+}
+
+// targetResponse returns the response for the result of a target, nil for targets that were
+// delegated to external execution engines
+func (r *QueryReconciler) targetResponse(result targetResult) *arkv1alpha1.Response {
+	var response arkv1alpha1.Response
+	switch {
+	case result.err != nil:
+		response = r.createErrorResponse(result.target, result.err)
+	case result.messages == nil:
+		return nil
+	default:
+		response = r.createSuccessResponse(result.target, result.messages)
+	}
+	if len(result.members) > 0 {
+		response.Members = result.members
+	}
+	return &response
 }
 
 func (r *QueryReconciler) createSuccessResponse(target arkv1alpha1.QueryTarget, messages []genai.Message) arkv1alpha1.Response {
@@ -462,30 +721,39 @@ func (r *QueryReconciler) createSuccessResponse(target arkv1alpha1.QueryTarget,
 	}
 
 	return arkv1alpha1.Response{
-		Target:  target,
-		Content: messageToText(messages[len(messages)-1]),
-		Raw:     rawJSON,
-		Phase:   statusDone,
+		Target:     target,
+		Content:    messageToText(messages[len(messages)-1]),
+		Raw:        rawJSON,
+		Phase:      statusDone,
+		Provenance: genai.ToolProvenance(messages),
 	}
 }
 
+// offloadLargeResponses stores responses larger than the artifact threshold, or too large together,
+// outside the query status. Responses that cannot be stored are truncated.
+func (r *QueryReconciler) offloadLargeResponses(ctx context.Context, query *arkv1alpha1.Query, responses []arkv1alpha1.Response) []arkv1alpha1.Response {
+	if r.Artifacts == nil {
+		return responses
+	}
+
+	offloaded, err := r.Artifacts.OffloadResponses(ctx, query, responses)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to store response artifacts, truncating the responses", "query", query.Name)
+	}
+	return offloaded
+}
+
 // messageToText extracts text content from a single OpenAI message format structure.
 // This function assumes the message follows OpenAI's ChatCompletionMessageParamUnion format.
 func messageToText(message genai.Message) string {
-	switch {
-	case message.OfAssistant != nil:
-		return message.OfAssistant.Content.OfString.Value
-	case message.OfTool != nil:
-		return message.OfTool.Content.OfString.Value
-	case message.OfUser != nil:
-		return message.OfUser.Content.OfString.Value
-	default:
+	if message.OfAssistant == nil && message.OfTool == nil && message.OfUser == nil && message.OfSystem == nil {
 		logf.Log.Error(fmt.Errorf("LLMResponseMalformed"),
 			"Unable to parse message content to text",
 			"messageContent", "unknown message structure",
 			"message", message)
 		return ""
 	}
+	return genai.MessageText(message)
 }
 
 // serializeMessages converts OpenAI union message types to their actual content for JSON serialization
@@ -554,6 +822,9 @@ func (r *QueryReconciler) updateStatusWithDuration(ctx context.Context, query *a
 	if duration != nil {
 		query.Status.Duration = duration
 	}
+	if status != statusRunning {
+		query.Status.Checkpoint = nil
+	}
 	err := r.Status().Update(ctx, query)
 	if err != nil {
 		logf.FromContext(ctx).Error(err, "failed to update query status", "status", status)
@@ -605,6 +876,13 @@ func (r *QueryReconciler) finalize(ctx context.Context, query *arkv1alpha1.Query
 func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
 	// Store query in context for access in deeper call stacks
 	ctx = context.WithValue(ctx, genai.QueryContextKey, &query)
+	// Grants are read by the controller, the query identity may not read grants of other namespaces
+	ctx = genai.WithReferenceGrantReader(ctx, r.Client)
+
+	ctx, memory, err := r.applyAgentDataPolicy(ctx, query, target, memory)
+	if err != nil {
+		return nil, err
+	}
 
 	// Create target-specific span for observability.
 	// This span tracks execution of a single target (agent/team/model/tool) and records:
@@ -613,6 +891,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 	// - Execution time and outcome
 	ctx, span := r.Telemetry.QueryRecorder().StartTarget(ctx, target.Type, target.Name)
 	defer span.End()
+	start := time.Now()
 
 	// Add query and session context for streaming metadata
 	queryID := string(query.UID)
@@ -621,15 +900,21 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 
 	// Add execution metadata for streaming
 	targetString := fmt.Sprintf("%s/%s", target.Type, target.Name)
+	if target.Cluster != "" {
+		targetString += "@" + target.Cluster
+	}
 	ctx = genai.WithExecutionMetadata(ctx, map[string]interface{}{
 		"target": targetString,
 	})
 
-	var err error
 	metadata := map[string]string{"targetType": target.Type, "targetName": target.Name}
 
 	// Get input messages for processing and telemetry
 	inputMessages, err := genai.GetQueryInputMessages(ctx, query, impersonatedClient)
+	var instructions *genai.QueryInstructions
+	if err == nil {
+		instructions, err = genai.ResolveQueryInstructions(ctx, impersonatedClient, &query)
+	}
 	if err != nil {
 		r.Telemetry.QueryRecorder().RecordError(span, err)
 		// Add trace correlation to event metadata for observability linkage
@@ -639,7 +924,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 			BaseEvent: genai.BaseEvent{Name: target.Name, Metadata: metadata},
 			Type:      target.Type,
 		}
-		tokenCollector.EmitEvent(ctx, corev1.EventTypeWarning, "QueryResolveError", event)
+		tokenCollector.EmitEvent(ctx, corev1.EventTypeWarning, genai.ReasonQueryResolveError, event)
 		return nil, err
 	}
 
@@ -647,25 +932,31 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 	userContent := genai.ExtractUserMessageContent(inputMessages)
 	r.Telemetry.QueryRecorder().RecordInput(span, userContent)
 
-	timeout := 5 * time.Minute
-	if query.Spec.Timeout != nil {
-		timeout = query.Spec.Timeout.Duration
-	}
-	execCtx, cancel := context.WithTimeout(ctx, timeout)
+	ctx = genai.WithQueryInstructions(ctx, instructions)
+	execCtx, cancel := context.WithTimeout(ctx, query.Spec.GetTimeout())
 	defer cancel()
 
+	// Query guardrails check the input and final output of every target
+	guardrails, err := genai.LoadGuardrails(execCtx, impersonatedClient, query.Spec.Guardrails, query.Namespace, r.Telemetry.ModelRecorder())
+	if err == nil {
+		err = genai.RunGuardrails(execCtx, guardrails, arkv1alpha1.GuardrailStageInput, userContent, tokenCollector)
+	}
+
+	if err == nil && target.Cluster == "" {
+		key := targetKey(query, target)
+		err = genai.CheckReferenceGrant(execCtx, r.Client, arkv1alpha1.ReferenceFromQuery, query.Namespace, target.Kind(), key.Name, key.Namespace)
+		if err == nil {
+			err = r.checkTargetAccess(execCtx, query, target, key.Namespace)
+		}
+	}
+
 	var responseMessages []genai.Message
-	switch target.Type {
-	case "agent":
-		responseMessages, err = r.executeAgent(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream, tokenCollector)
-	case "team":
-		responseMessages, err = r.executeTeam(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream, tokenCollector)
-	case "model":
-		responseMessages, err = r.executeModel(execCtx, query, inputMessages, target.Name, impersonatedClient, memory, eventStream, tokenCollector)
-	case "tool":
-		responseMessages, err = r.executeTool(execCtx, query, inputMessages, target.Name, impersonatedClient, tokenCollector)
-	default:
-		panic(fmt.Errorf("unknown query target type:%s", target.Type))
+	if err == nil {
+		responseMessages, err = r.dispatchTarget(execCtx, query, target, inputMessages, impersonatedClient, memory, eventStream, tokenCollector)
+	}
+	if err == nil && len(responseMessages) > 0 {
+		lastMessage := responseMessages[len(responseMessages)-1]
+		err = genai.RunGuardrails(execCtx, guardrails, arkv1alpha1.GuardrailStageOutput, messageToText(lastMessage), tokenCollector)
 	}
 
 	if err != nil {
@@ -677,7 +968,7 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 			BaseEvent: genai.BaseEvent{Name: target.Name, Metadata: metadata},
 			Type:      target.Type,
 		}
-		tokenCollector.EmitEvent(ctx, corev1.EventTypeWarning, "TargetExecutionError", event)
+		tokenCollector.EmitEvent(ctx, corev1.EventTypeWarning, genai.ReasonTargetExecutionError, event)
 	} else {
 		// Set the final response as output at trace level
 		if len(responseMessages) > 0 {
@@ -693,14 +984,72 @@ func (r *QueryReconciler) executeTarget(ctx context.Context, query arkv1alpha1.Q
 			BaseEvent: genai.BaseEvent{Name: target.Name, Metadata: metadata},
 			Type:      target.Type,
 		}
-		tokenCollector.EmitEvent(ctx, corev1.EventTypeNormal, "TargetExecutionComplete", event)
+		tokenCollector.EmitEvent(ctx, corev1.EventTypeNormal, genai.ReasonTargetExecutionComplete, event)
 	}
+	metrics.ObserveQueryTarget(target.Type, query.Namespace, time.Since(start), err)
 	return responseMessages, err
 }
 
-func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, agentName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+func (r *QueryReconciler) dispatchTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, inputMessages []genai.Message, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+	if target.Cluster != "" {
+		return r.executeRemoteTarget(ctx, query, target, inputMessages, impersonatedClient, memory, tokenCollector)
+	}
+	switch target.Type {
+	case "agent":
+		return r.executeAgent(ctx, query, inputMessages, targetKey(query, target), impersonatedClient, memory, eventStream, tokenCollector)
+	case "team":
+		return r.executeTeam(ctx, query, inputMessages, targetKey(query, target), impersonatedClient, memory, eventStream, tokenCollector)
+	case "model":
+		return r.executeModel(ctx, query, inputMessages, targetKey(query, target), impersonatedClient, memory, eventStream, tokenCollector)
+	case "tool":
+		return r.executeTool(ctx, query, inputMessages, targetKey(query, target), impersonatedClient, tokenCollector)
+	default:
+		panic(fmt.Errorf("unknown query target type:%s", target.Type))
+	}
+}
+
+// executeRemoteTarget runs the target by a query in its remote cluster and saves the exchange to
+// the memory of the local query
+func (r *QueryReconciler) executeRemoteTarget(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, inputMessages []genai.Message, impersonatedClient client.Client, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+	executor, err := genai.NewRemoteTargetExecutor(ctx, impersonatedClient, target.Cluster, query.Namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx = genai.WithExecutionMetadata(ctx, map[string]interface{}{
+		"cluster": target.Cluster,
+	})
+	responseMessages, err := executor.Execute(ctx, &query, target, inputMessages, tokenCollector)
+	if err != nil {
+		return nil, err
+	}
+	return r.saveTargetResponse(ctx, query, impersonatedClient, inputMessages, responseMessages, memory, tokenCollector)
+}
+
+// applyAgentDataPolicy enables PII redaction for an agent target that requires it when the query does not
+func (r *QueryReconciler) applyAgentDataPolicy(ctx context.Context, query arkv1alpha1.Query, target arkv1alpha1.QueryTarget, memory genai.MemoryInterface) (context.Context, genai.MemoryInterface, error) {
+	if target.Type != "agent" || target.Cluster != "" || genai.PIIRedactorFromContext(ctx) != nil {
+		return ctx, memory, nil
+	}
+
+	var agent arkv1alpha1.Agent
+	if err := r.Get(ctx, targetKey(query, target), &agent); err != nil {
+		// Missing agents are reported when the target executes
+		return ctx, memory, nil
+	}
+	if agent.Spec.DataPolicy != arkv1alpha1.DataPolicyRedactPII {
+		return ctx, memory, nil
+	}
+
+	redactor, err := genai.NewPIIRedactor(ctx, r.Client, query.Namespace, r.Telemetry.ModelRecorder())
+	if err != nil {
+		return ctx, memory, fmt.Errorf("failed to set up PII redaction for agent %s: %w", target.Name, err)
+	}
+	return genai.WithPIIRedaction(ctx, redactor), genai.NewRedactingMemory(memory, redactor), nil
+}
+
+func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, agentKey types.NamespacedName, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
 	var agentCRD arkv1alpha1.Agent
-	agentKey := types.NamespacedName{Name: agentName, Namespace: query.Namespace}
 
 	if err := impersonatedClient.Get(ctx, agentKey, &agentCRD); err != nil {
 		return nil, fmt.Errorf("unable to get %v, error:%w", agentKey, err)
@@ -709,7 +1058,7 @@ func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Qu
 	// Add agent to execution metadata
 	// This ensures that clients can see the specific agent being queried when streaming
 	ctx = genai.WithExecutionMetadata(ctx, map[string]interface{}{
-		"agent": agentName,
+		"agent": agentKey.Name,
 	})
 
 	// Regular agent execution
@@ -732,18 +1081,11 @@ func (r *QueryReconciler) executeAgent(ctx context.Context, query arkv1alpha1.Qu
 		return nil, err
 	}
 
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, responseMessages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
-	}
-
-	return responseMessages, nil
+	return r.saveTargetResponse(ctx, query, impersonatedClient, inputMessages, responseMessages, memory, tokenCollector)
 }
 
-func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, teamName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, teamKey types.NamespacedName, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
 	var teamCRD arkv1alpha1.Team
-	teamKey := types.NamespacedName{Name: teamName, Namespace: query.Namespace}
 
 	if err := impersonatedClient.Get(ctx, teamKey, &teamCRD); err != nil {
 		return nil, fmt.Errorf("unable to fetch team %v, error:%w", teamKey, err)
@@ -753,6 +1095,7 @@ func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Que
 	if err != nil {
 		return nil, fmt.Errorf("unable to make team %v, error:%w", teamKey, err)
 	}
+	recordTeamMembership(ctx, team.Membership)
 
 	historyMessages, err := r.loadInitialMessages(ctx, memory)
 	if err != nil {
@@ -764,41 +1107,38 @@ func (r *QueryReconciler) executeTeam(ctx context.Context, query arkv1alpha1.Que
 
 	responseMessages, err := team.Execute(ctx, currentMessage, contextMessages, memory, eventStream)
 	if err != nil {
-		return nil, err
+		// Members that completed before the failure are kept for the failure record
+		return responseMessages, err
 	}
 
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, responseMessages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
-	}
-
-	return responseMessages, nil
+	return r.saveTargetResponse(ctx, query, impersonatedClient, inputMessages, responseMessages, memory, tokenCollector)
 }
 
-func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, modelName string, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Query, inputMessages []genai.Message, modelKey types.NamespacedName, impersonatedClient client.Client, memory genai.MemoryInterface, eventStream genai.EventStreamInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
 	var modelCRD arkv1alpha1.Model
-	modelKey := types.NamespacedName{Name: modelName, Namespace: query.Namespace}
+	modelName := modelKey.Name
 
 	if err := impersonatedClient.Get(ctx, modelKey, &modelCRD); err != nil {
 		return nil, fmt.Errorf("unable to get %v, error:%w", modelKey, err)
 	}
 
-	model, err := genai.LoadModel(ctx, impersonatedClient, &arkv1alpha1.AgentModelRef{Name: modelName, Namespace: query.Namespace}, query.Namespace, r.Telemetry.ModelRecorder())
+	model, err := genai.LoadModel(ctx, impersonatedClient, &arkv1alpha1.AgentModelRef{Name: modelName, Namespace: modelKey.Namespace}, modelKey.Namespace, r.Telemetry.ModelRecorder())
 	if err != nil {
 		return nil, fmt.Errorf("unable to load model %v, error:%w", modelKey, err)
 	}
+	model.Parameters = query.Spec.ModelParameters
 
 	historyMessages, err := r.loadInitialMessages(ctx, memory)
 	if err != nil {
 		return nil, fmt.Errorf("unable to load initial messages: %w", err)
 	}
 
-	// Append all input messages to conversation history
-	allMessages := genai.PrepareModelMessages(inputMessages, historyMessages)
+	// Append all input messages to conversation history, after the query system prompt and context
+	instructions := genai.QueryInstructionsFromContext(ctx)
+	allMessages := append(instructions.SystemMessages(), genai.PrepareModelMessages(inputMessages, instructions.History(historyMessages))...)
 
 	// Create operation tracker for the model call
-	modelTracker := genai.NewOperationTracker(tokenCollector, ctx, "ModelCall", modelName, map[string]string{
+	modelTracker := genai.NewOperationTracker(tokenCollector, ctx, genai.OperationModelCall, modelName, map[string]string{
 		"model":     modelName,
 		"type":      "direct",
 		"streaming": fmt.Sprintf("%t", eventStream != nil),
@@ -822,12 +1162,7 @@ func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Qu
 		}
 
 		// Extract and track token usage
-		tokenUsage := genai.TokenUsage{
-			PromptTokens:     completion.Usage.PromptTokens,
-			CompletionTokens: completion.Usage.CompletionTokens,
-			TotalTokens:      completion.Usage.TotalTokens,
-		}
-		modelTracker.CompleteWithTokens(tokenUsage)
+		modelTracker.CompleteWithTokens(genai.TokenUsageFromCompletion(completion.Usage))
 
 		if len(completion.Choices) == 0 {
 			return nil, fmt.Errorf("model returned no completion choices")
@@ -838,17 +1173,10 @@ func (r *QueryReconciler) executeModel(ctx context.Context, query arkv1alpha1.Qu
 		responseMessages = []genai.Message{assistantMessage}
 	}
 
-	// Save all new messages (input + response) to memory
-	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, responseMessages)
-	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
-		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
-	}
-
-	return responseMessages, nil
+	return r.saveTargetResponse(ctx, query, impersonatedClient, inputMessages, responseMessages, memory, tokenCollector)
 }
 
-func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query, inputMessages []genai.Message, toolName string, impersonatedClient client.Client, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) { //nolint:unparam
-	// tokenCollector parameter is kept for consistency with other execute methods but not used since tools don't consume tokens
+func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query, inputMessages []genai.Message, toolKey types.NamespacedName, impersonatedClient client.Client, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) { //nolint:unparam
 	log := logf.FromContext(ctx)
 
 	query, err := genai.MakeQuery(&crd)
@@ -857,7 +1185,7 @@ func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query
 	}
 
 	var toolCRD arkv1alpha1.Tool
-	toolKey := types.NamespacedName{Name: toolName, Namespace: query.Namespace}
+	toolName := toolKey.Name
 
 	if err := impersonatedClient.Get(ctx, toolKey, &toolCRD); err != nil {
 		return nil, fmt.Errorf("unable to get tool %v, error:%w", toolKey, err)
@@ -906,22 +1234,63 @@ func (r *QueryReconciler) executeTool(ctx context.Context, crd arkv1alpha1.Query
 	toolDefinition := genai.CreateToolFromCRD(&toolCRD)
 	// Pass the tool registry's MCP pool to CreateToolExecutor
 	mcpPool, McpSettings := toolRegistry.GetMCPPool()
-	executor, err := genai.CreateToolExecutor(ctx, impersonatedClient, &toolCRD, query.Namespace, mcpPool, McpSettings, r.Telemetry)
+	executor, err := genai.CreateToolExecutor(ctx, impersonatedClient, &toolCRD, toolKey.Namespace, mcpPool, McpSettings, r.Telemetry)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create tool executor: %w", err)
 	}
 	toolRegistry.RegisterTool(toolDefinition, executor)
+	if err := toolRegistry.SetToolOutputPolicy(ctx, impersonatedClient, &toolCRD, toolDefinition.Name, toolKey.Namespace, r.Telemetry); err != nil {
+		return nil, err
+	}
+
+	toolTracker := genai.NewOperationTracker(tokenCollector, ctx, genai.OperationToolCall, toolName, map[string]string{
+		"toolId":     toolCall.ID,
+		"toolName":   toolName,
+		"parameters": toolCall.Function.Arguments,
+		"toolType":   toolRegistry.GetToolType(toolName),
+	})
 
 	// Execute the tool using the same ExecuteTool method agents use
+	toolStart := time.Now()
 	result, err := toolRegistry.ExecuteTool(ctx, toolCall, tokenCollector)
+	metrics.ObserveToolCall(toolName, query.Namespace, time.Since(toolStart), err)
 	if err != nil {
+		toolTracker.Fail(err)
 		return nil, fmt.Errorf("tool execution failed: %w", err)
 	}
+	toolTracker.Complete(result.Content)
 
 	// Create response message with tool result
 	assistantMessage := genai.NewAssistantMessage(result.Content)
 	responseMessages := []genai.Message{assistantMessage}
 
+	// Tool targets do not use memory
+	return r.saveTargetResponse(ctx, crd, impersonatedClient, inputMessages, responseMessages, nil, tokenCollector)
+}
+
+// saveTargetResponse applies the post-processors of the query to the final response of a target,
+// then saves the input and processed response to memory
+func (r *QueryReconciler) saveTargetResponse(ctx context.Context, query arkv1alpha1.Query, impersonatedClient client.Client, inputMessages, responseMessages []genai.Message, memory genai.MemoryInterface, tokenCollector *genai.TokenUsageCollector) ([]genai.Message, error) {
+	postProcessors := &genai.PostProcessors{
+		Specs:     query.Spec.PostProcessors,
+		Namespace: query.Namespace,
+		Client:    impersonatedClient,
+		Telemetry: r.Telemetry,
+		Recorder:  tokenCollector,
+	}
+	responseMessages, err := postProcessors.Run(ctx, responseMessages)
+	if err != nil {
+		return nil, err
+	}
+	if memory == nil {
+		return responseMessages, nil
+	}
+
+	// Save all new messages (input + response) to memory
+	newMessages := genai.PrepareNewMessagesForMemory(inputMessages, responseMessages)
+	if err := memory.AddMessages(ctx, query.Name, newMessages); err != nil {
+		return nil, fmt.Errorf("failed to save new messages to memory: %w", err)
+	}
 	return responseMessages, nil
 }
 
@@ -939,36 +1308,63 @@ func (r *QueryReconciler) loadInitialMessages(ctx context.Context, memory genai.
 		return nil, fmt.Errorf("failed to get messages from memory: %w", err)
 	}
 
+	// Compaction only shortens the history, so the query continues with the full history if it fails
+	if compactor, ok := memory.(genai.MemoryCompactor); ok {
+		compacted, err := compactor.CompactMessages(ctx, messages, r.Telemetry.ModelRecorder())
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "failed to compact memory, using the full history")
+			return messages, nil
+		}
+		messages = compacted
+	}
+
 	return messages, nil
 }
 
+// queryImpersonation returns the identity a query runs as, and false when it runs with the
+// controller's own identity
+func queryImpersonation(query arkv1alpha1.Query) (rest.ImpersonationConfig, bool) {
+	if impersonate := query.Spec.Impersonate; impersonate != nil {
+		return rest.ImpersonationConfig{
+			UserName: impersonate.User,
+			UID:      impersonate.UID,
+			Groups:   impersonate.Groups,
+		}, true
+	}
+	if query.Spec.ServiceAccount != "" {
+		return rest.ImpersonationConfig{
+			UserName: fmt.Sprintf("system:serviceaccount:%s:%s", query.Namespace, query.Spec.ServiceAccount),
+		}, true
+	}
+	return rest.ImpersonationConfig{}, false
+}
+
 func (r *QueryReconciler) getClientForQuery(query arkv1alpha1.Query) (client.Client, error) {
-	// If no service account specified, use controller's own identity.
+	// If no service account or user specified, use controller's own identity.
 	// This allows queries to run without impersonation when not needed,
 	// and supports local development where impersonation isn't available.
-	serviceAccount := query.Spec.ServiceAccount
-	if serviceAccount == "" {
+	impersonation, ok := queryImpersonation(query)
+	if !ok {
 		return r.Client, nil
 	}
 
-	// Impersonate the specified service account.
-	// Note: This requires rbac.impersonation.enabled=true in the Helm chart.
+	// Impersonate the specified service account or user.
+	// Note: This requires rbac.impersonation.enabled=true in the Helm chart, and
+	// rbac.impersonation.users=true for users.
 	// Future architecture will move this to per-namespace query executor pods.
 	cfg, err := rest.InClusterConfig()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get in-cluster config: %w", err)
 	}
 
-	cfg.Impersonate = rest.ImpersonationConfig{
-		UserName: fmt.Sprintf("system:serviceaccount:%s:%s", query.Namespace, serviceAccount),
-	}
+	cfg.Impersonate = impersonation
 
 	impersonatedClient, err := client.New(cfg, client.Options{
 		Scheme: r.Scheme,
 		Mapper: r.RESTMapper(),
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to create impersonated client for service account %s/%s: %w", query.Namespace, serviceAccount, err)
+		return nil, fmt.Errorf("failed to create impersonated client for %s: %w", impersonation.UserName, err)
 	}
 
 	return impersonatedClient, nil
@@ -995,12 +1391,7 @@ func (r *QueryReconciler) executeModelWithStreaming(ctx context.Context, model *
 	}
 
 	// Extract and track token usage
-	tokenUsage := genai.TokenUsage{
-		PromptTokens:     completion.Usage.PromptTokens,
-		CompletionTokens: completion.Usage.CompletionTokens,
-		TotalTokens:      completion.Usage.TotalTokens,
-	}
-	modelTracker.CompleteWithTokens(tokenUsage)
+	modelTracker.CompleteWithTokens(genai.TokenUsageFromCompletion(completion.Usage))
 
 	if len(completion.Choices) == 0 {
 		return nil, fmt.Errorf("model returned no completion choices")
@@ -9,8 +9,11 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -214,6 +217,27 @@ var _ = Describe("Query Controller", func() {
 	})
 })
 
+var _ = Describe("Query Controller Impersonation", func() {
+	It("should run queries as the impersonated user or service account", func() {
+		query := arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "team-a"}}
+		_, ok := queryImpersonation(query)
+		Expect(ok).To(BeFalse())
+
+		query.Spec.ServiceAccount = "query-runner"
+		impersonation, ok := queryImpersonation(query)
+		Expect(ok).To(BeTrue())
+		Expect(impersonation.UserName).To(Equal("system:serviceaccount:team-a:query-runner"))
+
+		query.Spec.ServiceAccount = ""
+		query.Spec.Impersonate = &arkv1alpha1.QueryImpersonation{User: "alice@example.com", UID: "42", Groups: []string{"analysts"}}
+		impersonation, ok = queryImpersonation(query)
+		Expect(ok).To(BeTrue())
+		Expect(impersonation.UserName).To(Equal("alice@example.com"))
+		Expect(impersonation.UID).To(Equal("42"))
+		Expect(impersonation.Groups).To(ConsistOf("analysts"))
+	})
+})
+
 var _ = Describe("Query Controller Message Serialization", func() {
 	Context("When serializing messages", func() {
 		It("should serialize all message types correctly", func() {
@@ -241,3 +265,96 @@ var _ = Describe("Query Controller Message Serialization", func() {
 		})
 	})
 })
+
+var _ = Describe("Query Controller Target Selection", func() {
+	var (
+		ctx        context.Context
+		reconciler *QueryReconciler
+		fakeClient client.Client
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		labels := map[string]string{"suite": "regression"}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "math", Namespace: "default", Labels: labels}},
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", Labels: labels}},
+			&arkv1alpha1.Team{ObjectMeta: metav1.ObjectMeta{Name: "research", Namespace: "default", Labels: labels}},
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "math", Namespace: "staging", Labels: labels}},
+			&arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "math", Namespace: "prod", Labels: labels}},
+			&arkv1alpha1.ReferenceGrant{
+				ObjectMeta: metav1.ObjectMeta{Name: "default-queries", Namespace: "staging"},
+				Spec: arkv1alpha1.ReferenceGrantSpec{
+					From: []arkv1alpha1.ReferenceGrantFrom{{Kind: arkv1alpha1.ReferenceFromQuery, Namespace: "default"}},
+					To:   []arkv1alpha1.ReferenceGrantTo{{Kind: string(arkv1alpha1.TargetKindAgent)}},
+				},
+			},
+		).Build()
+		reconciler = &QueryReconciler{Client: fakeClient}
+	})
+
+	selector := func() *arkv1alpha1.TargetSelector {
+		return &arkv1alpha1.TargetSelector{
+			LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"suite": "regression"}},
+		}
+	}
+
+	It("should select every kind in the query namespace by default", func() {
+		targets, err := reconciler.resolveSelector(ctx, selector(), "default", fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "math"},
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"},
+			arkv1alpha1.QueryTarget{Type: "team", Name: "research"},
+		))
+	})
+
+	It("should only select the requested kinds", func() {
+		sel := selector()
+		sel.Kinds = []arkv1alpha1.TargetKind{arkv1alpha1.TargetKindTeam}
+		targets, err := reconciler.resolveSelector(ctx, sel, "default", fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(arkv1alpha1.QueryTarget{Type: "team", Name: "research"}))
+	})
+
+	It("should select across namespaces and skip excluded targets", func() {
+		sel := selector()
+		sel.Kinds = []arkv1alpha1.TargetKind{arkv1alpha1.TargetKindAgent}
+		sel.Namespaces = []string{"default", "staging"}
+		sel.Exclude = []arkv1alpha1.QueryTarget{{Type: "agent", Name: "math", Namespace: "default"}}
+		targets, err := reconciler.resolveSelector(ctx, sel, "default", fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"},
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "math", Namespace: "staging"},
+		))
+	})
+
+	It("should skip targets of other namespaces without a reference grant", func() {
+		sel := selector()
+		sel.Kinds = []arkv1alpha1.TargetKind{arkv1alpha1.TargetKindAgent}
+		sel.Namespaces = []string{"staging", "prod"}
+		targets, err := reconciler.resolveSelector(ctx, sel, "default", fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(arkv1alpha1.QueryTarget{Type: "agent", Name: "math", Namespace: "staging"}))
+	})
+
+	It("should exclude a target in every namespace when the exclusion has no namespace", func() {
+		sel := selector()
+		sel.Namespaces = []string{"default", "staging"}
+		sel.Exclude = []arkv1alpha1.QueryTarget{{Type: "agent", Name: "math"}}
+		targets, err := reconciler.resolveSelector(ctx, sel, "default", fakeClient)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(targets).To(ConsistOf(
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "weather"},
+			arkv1alpha1.QueryTarget{Type: "team", Name: "research"},
+		))
+		Expect(countTargets([]arkv1alpha1.Response{{Target: targets[0]}, {Target: targets[1]}})).To(Equal(
+			&arkv1alpha1.ResolvedTargets{Agents: 1, Teams: 1},
+		))
+	})
+})
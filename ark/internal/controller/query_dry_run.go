@@ -0,0 +1,198 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// dryRunQuery resolves the query and saves its execution plan in the status without calling any
+// model or tool. The query is done when every target resolved and in error otherwise.
+func (r *QueryReconciler) dryRunQuery(ctx context.Context, query *arkv1alpha1.Query) error {
+	recorder := genai.NewQueryRecorder(query, r.Recorder, genai.NamespaceEventSettings(ctx, r.Client, query.Namespace, r.eventSettings()))
+	defer recorder.Flush(ctx)
+
+	planCtx, cancel := context.WithTimeout(ctx, query.Spec.GetTimeout())
+	defer cancel()
+	planCtx = genai.WithToolPolicyClient(planCtx, r.Client)
+
+	plan, err := r.planQuery(planCtx, *query, recorder)
+	query.Status.Plan = plan
+	query.Status.Phase = statusDone
+	reason, message := "QueryDryRunSucceeded", "Dry run resolved every target"
+	switch {
+	case err != nil:
+		query.Status.Phase = statusError
+		reason, message = "QueryDryRunFailed", err.Error()
+	default:
+		for _, target := range plan.Targets {
+			if target.Error != "" {
+				query.Status.Phase = statusError
+				reason, message = "QueryDryRunFailed", fmt.Sprintf("%s/%s: %s", target.Target.Type, target.Target.Name, target.Error)
+				break
+			}
+		}
+	}
+	r.setConditionCompleted(query, metav1.ConditionTrue, reason, message)
+
+	logf.FromContext(ctx).Info("dry run query", "query", query.Name, "namespace", query.Namespace, "phase", query.Status.Phase)
+	return r.Status().Update(ctx, query)
+}
+
+// planQuery resolves the input, memory and targets of the query. Targets that cannot be resolved
+// are reported in the plan, so a dry run shows every problem of a fan-out at once.
+func (r *QueryReconciler) planQuery(ctx context.Context, query arkv1alpha1.Query, recorder genai.EventEmitter) (*arkv1alpha1.QueryPlan, error) {
+	impersonatedClient, err := r.getClientForQuery(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create impersonated client: %w", err)
+	}
+
+	inputMessages, err := genai.GetQueryInputMessages(ctx, query, impersonatedClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve query input: %w", err)
+	}
+	instructions, err := genai.ResolveQueryInstructions(ctx, impersonatedClient, &query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve query input: %w", err)
+	}
+
+	sessionId := query.Spec.SessionId
+	if sessionId == "" {
+		sessionId = string(query.UID)
+	}
+	memory, err := genai.NewMemoryForQuery(ctx, impersonatedClient, query.Spec.Memory, query.Namespace, recorder, sessionId, query.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory client: %w", err)
+	}
+	defer func() { _ = memory.Close() }()
+
+	// The history is not compacted, as compaction calls a model
+	history, err := memory.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages from memory: %w", err)
+	}
+
+	targets, err := r.resolveTargets(ctx, query, impersonatedClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve targets: %w", err)
+	}
+
+	ctx = context.WithValue(ctx, genai.QueryContextKey, &query)
+	ctx = genai.WithReferenceGrantReader(ctx, r.Client)
+	ctx = genai.WithQueryInstructions(ctx, instructions)
+
+	plan := &arkv1alpha1.QueryPlan{HistoryMessages: len(history)}
+	for _, target := range targets {
+		planned := arkv1alpha1.QueryPlanTarget{Target: target}
+		if err := r.planTarget(ctx, query, &planned, inputMessages, history, impersonatedClient, recorder); err != nil {
+			planned.Error = err.Error()
+		}
+		plan.EstimatedPromptTokens += planned.EstimatedPromptTokens
+		plan.Targets = append(plan.Targets, planned)
+	}
+	return plan, nil
+}
+
+// planTarget resolves a target like executeTarget does and fills in its plan
+func (r *QueryReconciler) planTarget(ctx context.Context, query arkv1alpha1.Query, planned *arkv1alpha1.QueryPlanTarget, inputMessages, history []genai.Message, impersonatedClient client.Client, recorder genai.EventEmitter) error {
+	target := planned.Target
+	if target.Cluster != "" {
+		_, err := genai.NewRemoteTargetExecutor(ctx, impersonatedClient, target.Cluster, query.Namespace)
+		return err
+	}
+
+	key := targetKey(query, target)
+	if err := genai.CheckReferenceGrant(ctx, r.Client, arkv1alpha1.ReferenceFromQuery, query.Namespace, target.Kind(), key.Name, key.Namespace); err != nil {
+		return err
+	}
+	currentMessage, contextMessages := genai.PrepareExecutionMessages(inputMessages, history)
+
+	switch target.Type {
+	case "agent":
+		var agentCRD arkv1alpha1.Agent
+		if err := impersonatedClient.Get(ctx, key, &agentCRD); err != nil {
+			return fmt.Errorf("unable to get %v, error:%w", key, err)
+		}
+		agent, err := genai.MakeAgent(ctx, impersonatedClient, &agentCRD, recorder, r.Telemetry)
+		if err != nil {
+			return fmt.Errorf("unable to make agent %v, error:%w", key, err)
+		}
+		return r.planAgent(ctx, planned, agent, currentMessage, contextMessages)
+	case "team":
+		var teamCRD arkv1alpha1.Team
+		if err := impersonatedClient.Get(ctx, key, &teamCRD); err != nil {
+			return fmt.Errorf("unable to fetch team %v, error:%w", key, err)
+		}
+		team, err := genai.MakeTeam(ctx, impersonatedClient, &teamCRD, recorder, r.Telemetry)
+		if err != nil {
+			return fmt.Errorf("unable to make team %v, error:%w", key, err)
+		}
+		// Each agent member is called with the input at least once
+		for _, member := range team.Members {
+			planned.Members = append(planned.Members, fmt.Sprintf("%s/%s", member.GetType(), member.GetName()))
+			if agent, ok := member.(*genai.Agent); ok {
+				memberPlan := arkv1alpha1.QueryPlanTarget{}
+				if err := r.planAgent(ctx, &memberPlan, agent, currentMessage, contextMessages); err != nil {
+					return fmt.Errorf("team member %s: %w", agent.Name, err)
+				}
+				planned.EstimatedPromptTokens += memberPlan.EstimatedPromptTokens
+			}
+		}
+		return nil
+	case "model":
+		model, err := genai.LoadModel(ctx, impersonatedClient, &arkv1alpha1.AgentModelRef{Name: key.Name, Namespace: key.Namespace}, key.Namespace, r.Telemetry.ModelRecorder())
+		if err != nil {
+			return fmt.Errorf("unable to load model %v, error:%w", key, err)
+		}
+		planned.Model = model.Model
+		instructions := genai.QueryInstructionsFromContext(ctx)
+		messages := append(instructions.SystemMessages(), genai.PrepareModelMessages(inputMessages, instructions.History(history))...)
+		planned.EstimatedPromptTokens = genai.EstimatePromptTokens(messages, nil)
+		return nil
+	case "tool":
+		var toolCRD arkv1alpha1.Tool
+		if err := impersonatedClient.Get(ctx, key, &toolCRD); err != nil {
+			return fmt.Errorf("unable to get tool %v, error:%w", key, err)
+		}
+		planned.Tools = []string{genai.CreateToolFromCRD(&toolCRD).Name}
+		return nil
+	default:
+		return fmt.Errorf("unknown query target type: %s", target.Type)
+	}
+}
+
+// planAgent fills in the model, tools and first model call of the agent. The MCP connections
+// opened to list the tools are closed again.
+func (r *QueryReconciler) planAgent(ctx context.Context, planned *arkv1alpha1.QueryPlanTarget, agent *genai.Agent, currentMessage genai.Message, contextMessages []genai.Message) error {
+	if agent.Tools != nil {
+		defer func() {
+			if err := agent.Tools.Close(); err != nil {
+				logf.FromContext(ctx).Error(err, "failed to close MCP client connections of dry run", "agent", agent.FullName())
+			}
+		}()
+	}
+
+	// Agents of execution engines call their models outside of ARK
+	if agent.ExecutionEngine != nil || agent.Model == nil {
+		return nil
+	}
+
+	messages, tools, err := agent.PlanModelCall(ctx, currentMessage, contextMessages)
+	if err != nil {
+		return err
+	}
+	planned.Model = agent.Model.Model
+	for _, tool := range tools {
+		planned.Tools = append(planned.Tools, tool.Function.Name)
+	}
+	planned.EstimatedPromptTokens = genai.EstimatePromptTokens(messages, tools)
+	return nil
+}
@@ -0,0 +1,115 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	telemetryconfig "mckinsey.com/ark/internal/telemetry/config"
+)
+
+var _ = Describe("Query Controller Dry Run", func() {
+	var (
+		ctx        context.Context
+		reconciler *QueryReconciler
+		fakeClient client.Client
+	)
+
+	newQuery := func(targets ...arkv1alpha1.QueryTarget) *arkv1alpha1.Query {
+		input, _ := json.Marshal("What is 2+2?")
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "plan", Namespace: "default"},
+			Spec: arkv1alpha1.QuerySpec{
+				Input:   runtime.RawExtension{Raw: input},
+				Targets: targets,
+				DryRun:  true,
+			},
+		}
+		Expect(fakeClient.Create(ctx, query)).To(Succeed())
+		return query
+	}
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		model := &arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+			Spec: arkv1alpha1.ModelSpec{
+				Type:  "openai",
+				Model: arkv1alpha1.ValueSource{Value: "gpt-4"},
+				Config: arkv1alpha1.ModelConfig{
+					OpenAI: &arkv1alpha1.OpenAIModelConfig{
+						BaseURL: arkv1alpha1.ValueSource{Value: "http://127.0.0.1:1/v1"},
+						APIKey:  arkv1alpha1.ValueSource{Value: "test-key"},
+					},
+				},
+			},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithStatusSubresource(&arkv1alpha1.Query{}).WithObjects(
+			model,
+			&arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "math", Namespace: "default"},
+				Spec:       arkv1alpha1.AgentSpec{Prompt: "You are a careful mathematician.", ModelRef: &arkv1alpha1.AgentModelRef{Name: "default"}},
+			},
+			&arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "broken", Namespace: "default"},
+				Spec:       arkv1alpha1.AgentSpec{ModelRef: &arkv1alpha1.AgentModelRef{Name: "missing"}},
+			},
+		).Build()
+		reconciler = &QueryReconciler{
+			Client:    fakeClient,
+			Scheme:    s,
+			Recorder:  record.NewFakeRecorder(100),
+			Telemetry: telemetryconfig.NewProvider(),
+		}
+	})
+
+	It("should report the plan of every target without calling the model", func() {
+		query := newQuery(
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "math"},
+			arkv1alpha1.QueryTarget{Type: "model", Name: "default"},
+		)
+
+		Expect(reconciler.dryRunQuery(ctx, query)).To(Succeed())
+
+		Expect(query.Status.Phase).To(Equal(statusDone))
+		Expect(query.Status.Responses).To(BeEmpty())
+		plan := query.Status.Plan
+		Expect(plan).NotTo(BeNil())
+		Expect(plan.Targets).To(HaveLen(2))
+		Expect(plan.Targets[0].Model).To(Equal("gpt-4"))
+		Expect(plan.Targets[0].Error).To(BeEmpty())
+		Expect(plan.Targets[0].EstimatedPromptTokens).To(BeNumerically(">", plan.Targets[1].EstimatedPromptTokens))
+		Expect(plan.Targets[1].EstimatedPromptTokens).To(BeNumerically(">", 0))
+		Expect(plan.EstimatedPromptTokens).To(Equal(plan.Targets[0].EstimatedPromptTokens + plan.Targets[1].EstimatedPromptTokens))
+	})
+
+	It("should report targets that cannot be resolved and fail the query", func() {
+		query := newQuery(
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "math"},
+			arkv1alpha1.QueryTarget{Type: "agent", Name: "broken"},
+			arkv1alpha1.QueryTarget{Type: "tool", Name: "absent"},
+		)
+
+		Expect(reconciler.dryRunQuery(ctx, query)).To(Succeed())
+
+		Expect(query.Status.Phase).To(Equal(statusError))
+		Expect(query.Status.Plan.Targets[0].Error).To(BeEmpty())
+		Expect(query.Status.Plan.Targets[1].Error).To(ContainSubstring("missing"))
+		Expect(query.Status.Plan.Targets[2].Error).To(ContainSubstring("absent"))
+		Expect(query.Status.Conditions[0].Reason).To(Equal("QueryDryRunFailed"))
+	})
+})
@@ -0,0 +1,293 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	coordinationv1 "k8s.io/api/coordination/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// QueryExecutionMode selects where running queries are executed
+type QueryExecutionMode string
+
+const (
+	// QueryExecutionController executes queries in the controller that reconciles them
+	QueryExecutionController QueryExecutionMode = "controller"
+	// QueryExecutionExecutor leaves the execution of running queries to executor pods
+	QueryExecutionExecutor QueryExecutionMode = "executor"
+)
+
+const (
+	defaultExecutorMaxQueries    = 50
+	defaultExecutorLeaseDuration = 30 * time.Second
+	// executorRetryInterval is how long an executor waits before trying to claim a query again when
+	// it is at capacity or lost a race for the lease
+	executorRetryInterval = 5 * time.Second
+)
+
+// QueryExecutionModeFromEnv reads the query execution mode from ARK_QUERY_EXECUTION, which
+// defaults to controller.
+func QueryExecutionModeFromEnv() (QueryExecutionMode, error) {
+	switch mode := QueryExecutionMode(strings.TrimSpace(os.Getenv("ARK_QUERY_EXECUTION"))); mode {
+	case "":
+		return QueryExecutionController, nil
+	case QueryExecutionController, QueryExecutionExecutor:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid ARK_QUERY_EXECUTION '%s': must be controller or executor", mode)
+	}
+}
+
+// QueryExecutor executes running queries in a horizontally scalable executor deployment, so that
+// the leader controller only reconciles them. Executors do not use leader election: each query is
+// claimed by one executor with a Lease owned by the query, which the executor renews while the
+// query runs. If an executor stops renewing, for example because its pod was lost, another executor
+// takes the query over once the lease expires and runs it again. Leases are named after the query
+// UID, so a query recreated with the same name gets a new one. They are kept after the query
+// completes and are removed with the query.
+type QueryExecutor struct {
+	*QueryReconciler
+	// Identity is recorded as the holder of the leases of the queries this executor runs
+	Identity string
+	// MaxQueries is the number of queries this executor runs at a time
+	MaxQueries int
+	// LeaseDuration is how long a claim is valid without being renewed
+	LeaseDuration time.Duration
+	// APIReader reads the phase of queries from the API server before they are claimed, so that a
+	// stale cache cannot start a completed query again. The client is used when nil
+	APIReader client.Reader
+	running   atomic.Int32
+}
+
+// NewQueryExecutorFromEnv creates an executor running queries with reconciler. The executor is
+// identified by POD_NAME, or the hostname, and configured by ARK_EXECUTOR_MAX_QUERIES and
+// ARK_EXECUTOR_LEASE_SECONDS.
+func NewQueryExecutorFromEnv(reconciler *QueryReconciler) (*QueryExecutor, error) {
+	identity := os.Getenv("POD_NAME")
+	if identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, fmt.Errorf("failed to determine executor identity: %w", err)
+		}
+		identity = hostname
+	}
+
+	executor := &QueryExecutor{
+		QueryReconciler: reconciler,
+		Identity:        identity,
+		MaxQueries:      defaultExecutorMaxQueries,
+		LeaseDuration:   defaultExecutorLeaseDuration,
+	}
+	settings := []struct {
+		env     string
+		applyTo func(int)
+	}{
+		{"ARK_EXECUTOR_MAX_QUERIES", func(v int) { executor.MaxQueries = v }},
+		{"ARK_EXECUTOR_LEASE_SECONDS", func(v int) { executor.LeaseDuration = time.Duration(v) * time.Second }},
+	}
+	for _, setting := range settings {
+		value := strings.TrimSpace(os.Getenv(setting.env))
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 {
+			return nil, fmt.Errorf("invalid %s '%s': must be a positive integer", setting.env, value)
+		}
+		setting.applyTo(parsed)
+	}
+	return executor, nil
+}
+
+func (e *QueryExecutor) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	query, err := e.fetchQuery(ctx, req.NamespacedName)
+	if err != nil {
+		if errors.IsNotFound(err) {
+			e.stopQuery(req.NamespacedName)
+			return ctrl.Result{}, nil
+		}
+		log.Error(err, "unable to fetch Query")
+		return ctrl.Result{}, err
+	}
+
+	if !query.DeletionTimestamp.IsZero() || query.Spec.Cancel {
+		e.stopQuery(req.NamespacedName)
+		return ctrl.Result{}, nil
+	}
+	if query.Status.Phase != statusRunning || query.Spec.DryRun {
+		return ctrl.Result{}, nil
+	}
+	if _, exists := e.operations.Load(req.NamespacedName); exists {
+		return ctrl.Result{}, nil
+	}
+	if int(e.running.Load()) >= e.MaxQueries {
+		return ctrl.Result{RequeueAfter: executorRetryInterval}, nil
+	}
+
+	lease, retryAfter, err := e.claim(ctx, &query)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if lease == nil {
+		return ctrl.Result{RequeueAfter: retryAfter}, nil
+	}
+
+	log.Info("claimed query for execution", "query", req.NamespacedName, "executor", e.Identity)
+	e.running.Add(1)
+	renewCtx, stopRenewing := context.WithCancel(ctx)
+	e.startQuery(ctx, req.NamespacedName, query, func() {
+		stopRenewing()
+		e.running.Add(-1)
+	})
+	go e.renew(renewCtx, req.NamespacedName, lease)
+	return ctrl.Result{}, nil
+}
+
+// stopQuery cancels the execution of the query if this executor runs it
+func (e *QueryExecutor) stopQuery(namespacedName types.NamespacedName) {
+	if _, exists := e.operations.Load(namespacedName); exists {
+		e.cleanupExistingOperation(namespacedName)
+	}
+}
+
+// claim takes the execution lease of the query. When another executor holds a lease that has not
+// expired, claim returns no lease and the time after which the claim can be retried. Queries that
+// are no longer running on the API server are not claimed.
+func (e *QueryExecutor) claim(ctx context.Context, query *arkv1alpha1.Query) (*coordinationv1.Lease, time.Duration, error) {
+	var reader client.Reader = e.Client
+	if e.APIReader != nil {
+		reader = e.APIReader
+	}
+	current := &arkv1alpha1.Query{}
+	if err := reader.Get(ctx, client.ObjectKeyFromObject(query), current); err != nil {
+		return nil, 0, client.IgnoreNotFound(err)
+	}
+	if current.UID != query.UID || current.Status.Phase != statusRunning || !current.DeletionTimestamp.IsZero() {
+		return nil, 0, nil
+	}
+
+	now := metav1.NewMicroTime(time.Now())
+	key := executionLeaseKey(query)
+
+	lease := &coordinationv1.Lease{}
+	err := e.Get(ctx, key, lease)
+	if errors.IsNotFound(err) {
+		lease = &coordinationv1.Lease{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       e.leaseSpec(now),
+		}
+		if err := controllerutil.SetControllerReference(query, lease, e.Scheme); err != nil {
+			return nil, 0, fmt.Errorf("failed to set owner of execution lease: %w", err)
+		}
+		if err := e.Create(ctx, lease); err != nil {
+			if errors.IsAlreadyExists(err) {
+				return nil, executorRetryInterval, nil
+			}
+			return nil, 0, fmt.Errorf("failed to create execution lease: %w", err)
+		}
+		return lease, 0, nil
+	}
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to get execution lease: %w", err)
+	}
+
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity != e.Identity {
+		if remaining := leaseRemaining(lease, now.Time); remaining > 0 {
+			return nil, remaining, nil
+		}
+	}
+
+	// The update fails with a conflict if another executor took the expired lease over first
+	lease.Spec = e.leaseSpec(now)
+	if err := e.Update(ctx, lease); err != nil {
+		if errors.IsConflict(err) {
+			return nil, executorRetryInterval, nil
+		}
+		return nil, 0, fmt.Errorf("failed to take over execution lease: %w", err)
+	}
+	return lease, 0, nil
+}
+
+// renew keeps the lease of a running query until ctx is done. When the lease is lost, the query has
+// been taken over by another executor and the execution here is canceled.
+func (e *QueryExecutor) renew(ctx context.Context, namespacedName types.NamespacedName, lease *coordinationv1.Lease) {
+	log := logf.FromContext(ctx)
+
+	ticker := time.NewTicker(e.LeaseDuration / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		now := metav1.NewMicroTime(time.Now())
+		lease.Spec.RenewTime = &now
+		err := e.Update(ctx, lease)
+		switch {
+		case err == nil || ctx.Err() != nil:
+		case errors.IsConflict(err) || errors.IsNotFound(err):
+			log.Info("lost execution lease, canceling query", "query", namespacedName, "executor", e.Identity)
+			e.stopQuery(namespacedName)
+			return
+		default:
+			log.Error(err, "failed to renew execution lease", "query", namespacedName)
+		}
+	}
+}
+
+func (e *QueryExecutor) leaseSpec(now metav1.MicroTime) coordinationv1.LeaseSpec {
+	identity := e.Identity
+	durationSeconds := int32(e.LeaseDuration / time.Second)
+	return coordinationv1.LeaseSpec{
+		HolderIdentity:       &identity,
+		LeaseDurationSeconds: &durationSeconds,
+		AcquireTime:          &now,
+		RenewTime:            &now,
+	}
+}
+
+// executionLeaseKey is the key of the Lease claiming the execution of a query
+func executionLeaseKey(query *arkv1alpha1.Query) types.NamespacedName {
+	return types.NamespacedName{Name: "query-" + string(query.UID), Namespace: query.Namespace}
+}
+
+// leaseRemaining is how long the lease stays valid after now, zero or negative once it expired
+func leaseRemaining(lease *coordinationv1.Lease, now time.Time) time.Duration {
+	if lease.Spec.HolderIdentity == nil || *lease.Spec.HolderIdentity == "" ||
+		lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return 0
+	}
+	expiry := lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second)
+	return expiry.Sub(now)
+}
+
+// SetupWithManager runs the executor on every replica, without leader election
+func (e *QueryExecutor) SetupWithManager(mgr ctrl.Manager) error {
+	needLeaderElection := false
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.Query{}).
+		Named("query-executor").
+		WithOptions(controller.Options{NeedLeaderElection: &needLeaderElection}).
+		Complete(e)
+}
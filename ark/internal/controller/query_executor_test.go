@@ -0,0 +1,162 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query Executor", func() {
+	var (
+		ctx         context.Context
+		fakeClient  client.Client
+		query       *arkv1alpha1.Query
+		newExecutor func(identity string) *QueryExecutor
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(clientgoscheme.AddToScheme(s)).To(Succeed())
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "query-uid"},
+			Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(query).Build()
+		newExecutor = func(identity string) *QueryExecutor {
+			return &QueryExecutor{
+				QueryReconciler: &QueryReconciler{Client: fakeClient, Scheme: s},
+				Identity:        identity,
+				MaxQueries:      1,
+				LeaseDuration:   30 * time.Second,
+			}
+		}
+	})
+
+	getLease := func() *coordinationv1.Lease {
+		lease := &coordinationv1.Lease{}
+		Expect(fakeClient.Get(ctx, types.NamespacedName{Name: "query-query-uid", Namespace: "default"}, lease)).To(Succeed())
+		return lease
+	}
+
+	It("should let a single executor claim a query", func() {
+		lease, _, err := newExecutor("executor-a").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease).NotTo(BeNil())
+		Expect(*getLease().Spec.HolderIdentity).To(Equal("executor-a"))
+		Expect(getLease().OwnerReferences).To(HaveLen(1))
+
+		lease, retryAfter, err := newExecutor("executor-b").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease).To(BeNil())
+		Expect(retryAfter).To(BeNumerically("~", 30*time.Second, time.Second))
+
+		lease, _, err = newExecutor("executor-a").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease).NotTo(BeNil())
+	})
+
+	It("should take over a query whose lease expired", func() {
+		_, _, err := newExecutor("executor-a").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+
+		lease := getLease()
+		expired := metav1.NewMicroTime(time.Now().Add(-time.Minute))
+		lease.Spec.RenewTime = &expired
+		Expect(fakeClient.Update(ctx, lease)).To(Succeed())
+
+		claimed, _, err := newExecutor("executor-b").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(claimed).NotTo(BeNil())
+		Expect(*getLease().Spec.HolderIdentity).To(Equal("executor-b"))
+	})
+
+	It("should not claim a query that completed on the API server", func() {
+		completed := query.DeepCopy()
+		completed.Status.Phase = statusDone
+		s := fakeClient.Scheme()
+		executor := newExecutor("executor-a")
+		executor.APIReader = fake.NewClientBuilder().WithScheme(s).WithObjects(completed).Build()
+
+		lease, retryAfter, err := executor.claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease).To(BeNil())
+		Expect(retryAfter).To(BeZero())
+
+		leases := &coordinationv1.LeaseList{}
+		Expect(fakeClient.List(ctx, leases)).To(Succeed())
+		Expect(leases.Items).To(BeEmpty())
+	})
+
+	It("should claim a recreated query with a new lease", func() {
+		_, _, err := newExecutor("executor-a").claim(ctx, query)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeClient.Delete(ctx, query)).To(Succeed())
+		recreated := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather", Namespace: "default", UID: "recreated-uid"},
+			Status:     arkv1alpha1.QueryStatus{Phase: statusRunning},
+		}
+		Expect(fakeClient.Create(ctx, recreated)).To(Succeed())
+
+		lease, _, err := newExecutor("executor-b").claim(ctx, recreated)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(lease).NotTo(BeNil())
+		Expect(lease.Name).To(Equal("query-recreated-uid"))
+	})
+
+	It("should wait for capacity before claiming a query", func() {
+		executor := newExecutor("executor-a")
+		executor.running.Store(1)
+
+		result, err := executor.Reconcile(ctx, ctrl.Request{NamespacedName: types.NamespacedName{Name: "weather", Namespace: "default"}})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(executorRetryInterval))
+
+		leases := &coordinationv1.LeaseList{}
+		Expect(fakeClient.List(ctx, leases)).To(Succeed())
+		Expect(leases.Items).To(BeEmpty())
+	})
+
+	It("should leave running queries to executors in executor mode", func() {
+		reconciler := &QueryReconciler{Client: fakeClient, Execution: QueryExecutionExecutor}
+		req := ctrl.Request{NamespacedName: types.NamespacedName{Name: "weather", Namespace: "default"}}
+
+		_, err := reconciler.handleRunningPhase(ctx, req, *query)
+		Expect(err).NotTo(HaveOccurred())
+		_, exists := reconciler.operations.Load(req.NamespacedName)
+		Expect(exists).To(BeFalse())
+	})
+
+	It("should read the execution mode from the environment", func() {
+		mode, err := QueryExecutionModeFromEnv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mode).To(Equal(QueryExecutionController))
+
+		GinkgoT().Setenv("ARK_QUERY_EXECUTION", "executor")
+		mode, err = QueryExecutionModeFromEnv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(mode).To(Equal(QueryExecutionExecutor))
+
+		GinkgoT().Setenv("ARK_QUERY_EXECUTION", "jobs")
+		_, err = QueryExecutionModeFromEnv()
+		Expect(err).To(HaveOccurred())
+	})
+})
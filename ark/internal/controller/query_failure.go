@@ -0,0 +1,176 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// Keys of the failure record ConfigMap
+const (
+	failureRecordQueryKey    = "query.yaml"
+	failureRecordInputKey    = "input.json"
+	failureRecordFailuresKey = "failures.json"
+)
+
+// targetFailure is the context of one failure in a failure record. Target is unset when the
+// query failed before any target ran.
+type targetFailure struct {
+	Target *arkv1alpha1.QueryTarget `json:"target,omitempty"`
+	// Errors is the error chain, outermost first
+	Errors []string `json:"errors"`
+	// Messages are the messages the target produced before it failed
+	Messages json.RawMessage `json:"messages,omitempty"`
+
+	partialMessages []genai.Message
+}
+
+func newTargetFailure(target *arkv1alpha1.QueryTarget, partialMessages []genai.Message, err error) targetFailure {
+	return targetFailure{Target: target, Errors: errorChain(err), partialMessages: partialMessages}
+}
+
+// errorChain returns the message of err and of every error it wraps, outermost first
+func errorChain(err error) []string {
+	var chain []string
+	for ; err != nil; err = errors.Unwrap(err) {
+		chain = append(chain, err.Error())
+	}
+	return chain
+}
+
+func failureRecordName(queryName string) string {
+	return queryName + "-failure"
+}
+
+// recordFailure stores the context of a failed query in a ConfigMap owned by the query and
+// annotates the query with its name, so the failure can be inspected and replayed with fark.
+// Failures to record are logged rather than failing the query.
+func (r *QueryReconciler) recordFailure(ctx context.Context, query *arkv1alpha1.Query, inputMessages []genai.Message, failures []targetFailure) {
+	if ctx.Err() != nil {
+		return
+	}
+	log := logf.FromContext(ctx)
+
+	data, err := buildFailureRecord(ctx, query, inputMessages, failures)
+	if err != nil {
+		log.Error(err, "failed to build query failure record", "query", query.Name)
+		return
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: failureRecordName(query.Name), Namespace: query.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, r.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[annotations.Query] = query.Name
+		configMap.Data = data
+		return controllerutil.SetControllerReference(query, configMap, r.Scheme)
+	}); err != nil {
+		log.Error(err, "failed to write query failure record", "query", query.Name)
+		return
+	}
+
+	patch := client.MergeFrom(query.DeepCopy())
+	if query.Annotations == nil {
+		query.Annotations = map[string]string{}
+	}
+	query.Annotations[annotations.FailureRecord] = configMap.Name
+	if err := r.Patch(ctx, query, patch); err != nil {
+		log.Error(err, "failed to annotate query with failure record", "query", query.Name)
+	}
+}
+
+// buildFailureRecord returns the ConfigMap data of a failure record. The resolved input is left
+// out when a parameter is read from a Secret, and redacted when the query redacts personal data.
+func buildFailureRecord(ctx context.Context, query *arkv1alpha1.Query, inputMessages []genai.Message, failures []targetFailure) (map[string]string, error) {
+	manifest, err := yaml.Marshal(replayableQuery(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal query: %w", err)
+	}
+	data := map[string]string{failureRecordQueryKey: string(manifest)}
+
+	redactor := genai.PIIRedactorFromContext(ctx)
+	for i := range failures {
+		messages := failures[i].partialMessages
+		if redactor != nil {
+			for j, message := range failures[i].Errors {
+				failures[i].Errors[j] = redactor.Redact(ctx, message)
+			}
+			messages = redactor.RedactMessages(ctx, messages)
+		}
+		if len(messages) > 0 {
+			raw, err := serializeMessages(messages)
+			if err != nil {
+				return nil, err
+			}
+			failures[i].Messages = json.RawMessage(raw)
+		}
+	}
+
+	if len(inputMessages) > 0 && !usesSecretParameters(query) {
+		if redactor != nil {
+			inputMessages = redactor.RedactMessages(ctx, inputMessages)
+		}
+		input, err := serializeMessages(inputMessages)
+		if err != nil {
+			return nil, err
+		}
+		data[failureRecordInputKey] = input
+	}
+
+	failuresJSON, err := json.Marshal(failures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal failures: %w", err)
+	}
+	data[failureRecordFailuresKey] = string(failuresJSON)
+	return data, nil
+}
+
+// replayableQuery returns the query as it was submitted, without status or server-set metadata
+func replayableQuery(query *arkv1alpha1.Query) *arkv1alpha1.Query {
+	queryAnnotations := make(map[string]string, len(query.Annotations))
+	for key, value := range query.Annotations {
+		if key != annotations.FailureRecord && key != corev1.LastAppliedConfigAnnotation {
+			queryAnnotations[key] = value
+		}
+	}
+
+	return &arkv1alpha1.Query{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: arkv1alpha1.GroupVersion.String(),
+			Kind:       "Query",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        query.Name,
+			Namespace:   query.Namespace,
+			Labels:      query.Labels,
+			Annotations: queryAnnotations,
+		},
+		Spec: query.Spec,
+	}
+}
+
+func usesSecretParameters(query *arkv1alpha1.Query) bool {
+	for _, param := range query.Spec.Parameters {
+		if param.ValueFrom != nil && param.ValueFrom.SecretKeyRef != nil {
+			return true
+		}
+	}
+	return false
+}
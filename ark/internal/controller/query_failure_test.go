@@ -0,0 +1,76 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+)
+
+var _ = Describe("Query Failure Record", func() {
+	var query *arkv1alpha1.Query
+
+	BeforeEach(func() {
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "failing-query",
+				Namespace:   "default",
+				Annotations: map[string]string{annotations.FailureRecord: "failing-query-failure"},
+			},
+			Spec: arkv1alpha1.QuerySpec{
+				Targets: []arkv1alpha1.QueryTarget{{Type: "agent", Name: "math"}},
+			},
+		}
+		Expect(query.Spec.SetInputString("What is 2 + 2?")).To(Succeed())
+	})
+
+	It("should record the query, resolved input and error chain of each failed target", func() {
+		cause := errors.New("connection refused")
+		err := fmt.Errorf("model chat completion failed: %w", cause)
+		failures := []targetFailure{newTargetFailure(&query.Spec.Targets[0], []genai.Message{genai.NewAssistantMessage("partial")}, err)}
+
+		data, buildErr := buildFailureRecord(context.Background(), query, []genai.Message{genai.NewUserMessage("What is 2 + 2?")}, failures)
+		Expect(buildErr).NotTo(HaveOccurred())
+
+		var recorded arkv1alpha1.Query
+		Expect(yaml.Unmarshal([]byte(data[failureRecordQueryKey]), &recorded)).To(Succeed())
+		Expect(recorded.Name).To(Equal("failing-query"))
+		Expect(recorded.Spec.Targets).To(Equal(query.Spec.Targets))
+		Expect(recorded.Annotations).NotTo(HaveKey(annotations.FailureRecord))
+
+		Expect(data[failureRecordInputKey]).To(ContainSubstring("What is 2 + 2?"))
+
+		var recordedFailures []targetFailure
+		Expect(json.Unmarshal([]byte(data[failureRecordFailuresKey]), &recordedFailures)).To(Succeed())
+		Expect(recordedFailures).To(HaveLen(1))
+		Expect(recordedFailures[0].Target.Name).To(Equal("math"))
+		Expect(recordedFailures[0].Errors).To(Equal([]string{err.Error(), "connection refused"}))
+		Expect(string(recordedFailures[0].Messages)).To(ContainSubstring("partial"))
+	})
+
+	It("should leave out the resolved input when a parameter is read from a secret", func() {
+		query.Spec.Parameters = []arkv1alpha1.Parameter{{
+			Name: "token",
+			ValueFrom: &arkv1alpha1.ValueFromSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "api"}, Key: "token"},
+			},
+		}}
+
+		data, err := buildFailureRecord(context.Background(), query, []genai.Message{genai.NewUserMessage("secret value")}, nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(data).NotTo(HaveKey(failureRecordInputKey))
+		Expect(data).To(HaveKey(failureRecordQueryKey))
+	})
+})
@@ -0,0 +1,60 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// notifyQueryEnd sends QueryCompleted or QueryFailed for a query that reached a terminal phase
+func (r *QueryReconciler) notifyQueryEnd(ctx context.Context, query *arkv1alpha1.Query) {
+	if r.Notifier == nil {
+		return
+	}
+
+	notification := genai.Notification{
+		Kind:      "Query",
+		Name:      query.Name,
+		Namespace: query.Namespace,
+		Time:      time.Now().UTC(),
+		Details:   queryNotificationDetails(query),
+	}
+	switch query.Status.Phase {
+	case statusDone:
+		notification.Event = arkv1alpha1.NotificationQueryCompleted
+		notification.Message = "Query completed successfully"
+	case statusError:
+		notification.Event = arkv1alpha1.NotificationQueryFailed
+		notification.Message = "Query completed with error"
+		if condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryCompleted)); condition != nil && condition.Message != "" {
+			notification.Message = condition.Message
+		}
+	default:
+		return
+	}
+	r.Notifier.Notify(ctx, notification)
+}
+
+func queryNotificationDetails(query *arkv1alpha1.Query) map[string]string {
+	details := map[string]string{}
+	if query.Spec.SessionId != "" {
+		details["sessionId"] = query.Spec.SessionId
+	}
+	if query.Status.Duration != nil {
+		details["duration"] = query.Status.Duration.Duration.String()
+	}
+	if query.Status.Cost != "" {
+		details["cost"] = query.Status.Cost
+	}
+	if query.Status.TokenUsage.TotalTokens > 0 {
+		details["totalTokens"] = strconv.FormatInt(query.Status.TokenUsage.TotalTokens, 10)
+	}
+	return details
+}
@@ -0,0 +1,78 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+func recordingName(queryName string) string {
+	return queryName + "-recording"
+}
+
+// recordingStore returns the artifact store a recording is saved to, which is the configured
+// artifact store and otherwise ConfigMaps
+func (r *QueryReconciler) recordingStore() genai.ArtifactStore {
+	if r.Artifacts != nil {
+		return r.Artifacts.Store
+	}
+	return &genai.ConfigMapArtifactStore{Client: r.Client, Scheme: r.Scheme}
+}
+
+// replayStore returns the artifact store of a replayed recording. ConfigMaps can always be read,
+// the HTTP store only when the controller is configured with it.
+func (r *QueryReconciler) replayStore(kind string) (genai.ArtifactStore, error) {
+	if kind != arkv1alpha1.ArtifactStoreHTTP {
+		return &genai.ConfigMapArtifactStore{Client: r.Client, Scheme: r.Scheme}, nil
+	}
+	if r.Artifacts != nil {
+		if store, ok := r.Artifacts.Store.(*genai.HTTPArtifactStore); ok {
+			return store, nil
+		}
+	}
+	return nil, fmt.Errorf("the %s artifact store is not configured", arkv1alpha1.ArtifactStoreHTTP)
+}
+
+// modelCallRecording returns the recording that records or replays the model calls of the query,
+// or nil when the query neither records nor replays
+func (r *QueryReconciler) modelCallRecording(ctx context.Context, query arkv1alpha1.Query) (*genai.ModelCallRecording, error) {
+	if replay := query.Spec.Replay; replay != nil {
+		store, err := r.replayStore(replay.Store)
+		if err != nil {
+			return nil, fmt.Errorf("failed to replay recording %s: %w", replay.Name, err)
+		}
+		return genai.LoadModelCallRecording(ctx, store, query.Namespace, replay)
+	}
+	if query.Spec.Record {
+		return genai.NewModelCallRecording(), nil
+	}
+	return nil, nil
+}
+
+// saveRecording stores the model calls of a recorded query as an artifact and references it in
+// the status. Failures are logged rather than failing the query.
+func (r *QueryReconciler) saveRecording(ctx context.Context, query *arkv1alpha1.Query, recording *genai.ModelCallRecording) {
+	if recording == nil || recording.Replaying() {
+		return
+	}
+	log := logf.FromContext(ctx)
+
+	data, err := recording.Marshal()
+	if err != nil {
+		log.Error(err, "failed to marshal model call recording", "query", query.Name)
+		return
+	}
+
+	reference, err := r.recordingStore().Put(ctx, query, recordingName(query.Name), genai.Artifact{Content: string(data)})
+	if err != nil {
+		log.Error(err, "failed to store model call recording", "query", query.Name)
+		return
+	}
+	query.Status.Recording = reference
+}
@@ -0,0 +1,54 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// queryExpiry returns when a finished query is deleted: ttlAfterCompletion after it completed, or
+// ttl after it was created when ttlAfterCompletion is not set. Queries that have not finished,
+// queries with a zero retention, failed queries with retainOnError and queries labelled
+// audit=true are not deleted.
+func queryExpiry(query *arkv1alpha1.Query) (time.Time, bool) {
+	if arkv1alpha1.IsAuditQuery(query) {
+		return time.Time{}, false
+	}
+	switch query.Status.Phase {
+	case statusDone, statusCanceled:
+	case statusError:
+		if query.Spec.RetainOnError {
+			return time.Time{}, false
+		}
+	default:
+		return time.Time{}, false
+	}
+
+	if retention := query.Spec.TTLAfterCompletion; retention != nil {
+		if retention.Duration <= 0 {
+			return time.Time{}, false
+		}
+		return queryCompletedAt(query).Add(retention.Duration), true
+	}
+
+	ttl := query.Spec.GetTTL()
+	if ttl <= 0 {
+		return time.Time{}, false
+	}
+	return query.CreationTimestamp.Add(ttl), true
+}
+
+// queryCompletedAt is the time the completed condition of a finished query became true, or its
+// creation time for queries completed before the condition was recorded
+func queryCompletedAt(query *arkv1alpha1.Query) time.Time {
+	condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryCompleted))
+	if condition != nil && condition.Status == metav1.ConditionTrue {
+		return condition.LastTransitionTime.Time
+	}
+	return query.CreationTimestamp.Time
+}
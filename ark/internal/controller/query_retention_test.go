@@ -0,0 +1,164 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query Retention", func() {
+	var (
+		query       *arkv1alpha1.Query
+		createdAt   time.Time
+		completedAt time.Time
+	)
+
+	BeforeEach(func() {
+		createdAt = time.Now().Add(-2 * time.Hour)
+		completedAt = time.Now().Add(-time.Hour)
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "default", CreationTimestamp: metav1.NewTime(createdAt)},
+			Spec:       arkv1alpha1.QuerySpec{TTL: &metav1.Duration{Duration: 24 * time.Hour}},
+			Status: arkv1alpha1.QueryStatus{
+				Phase: statusDone,
+				Conditions: []metav1.Condition{{
+					Type:               string(arkv1alpha1.QueryCompleted),
+					Status:             metav1.ConditionTrue,
+					Reason:             "QuerySucceeded",
+					LastTransitionTime: metav1.NewTime(completedAt),
+				}},
+			},
+		}
+	})
+
+	It("should not delete queries that have not finished", func() {
+		query.Status.Phase = statusRunning
+		_, expires := queryExpiry(query)
+		Expect(expires).To(BeFalse())
+	})
+
+	It("should delete finished queries ttl after creation", func() {
+		expiry, expires := queryExpiry(query)
+		Expect(expires).To(BeTrue())
+		Expect(expiry).To(BeTemporally("~", createdAt.Add(24*time.Hour), time.Second))
+	})
+
+	It("should count ttlAfterCompletion from completion", func() {
+		query.Spec.TTLAfterCompletion = &metav1.Duration{Duration: 30 * time.Minute}
+		expiry, expires := queryExpiry(query)
+		Expect(expires).To(BeTrue())
+		Expect(expiry).To(BeTemporally("~", completedAt.Add(30*time.Minute), time.Second))
+	})
+
+	It("should keep queries with a zero retention", func() {
+		query.Spec.TTL = &metav1.Duration{}
+		_, expires := queryExpiry(query)
+		Expect(expires).To(BeFalse())
+
+		query.Spec.TTL = nil
+		query.Spec.TTLAfterCompletion = &metav1.Duration{}
+		_, expires = queryExpiry(query)
+		Expect(expires).To(BeFalse())
+	})
+
+	It("should keep failed queries with retainOnError", func() {
+		query.Status.Phase = statusError
+		_, expires := queryExpiry(query)
+		Expect(expires).To(BeTrue())
+
+		query.Spec.RetainOnError = true
+		_, expires = queryExpiry(query)
+		Expect(expires).To(BeFalse())
+	})
+
+	It("should keep queries labelled audit=true", func() {
+		query.Labels = map[string]string{arkv1alpha1.QueryAuditLabel: "true"}
+		_, expires := queryExpiry(query)
+		Expect(expires).To(BeFalse())
+	})
+
+	It("should not tie the execution deadline to retention", func() {
+		query.Spec.TTL = &metav1.Duration{Duration: time.Second}
+		Expect(query.Spec.GetTimeout()).To(Equal(arkv1alpha1.DefaultQueryTimeout))
+
+		query.Spec.Timeout = &metav1.Duration{}
+		Expect(query.Spec.GetTimeout()).To(Equal(arkv1alpha1.DefaultQueryTimeout))
+	})
+})
+
+var _ = Describe("Query Retention Policy", func() {
+	now := time.Now()
+
+	finished := func(name, namespace, phase string, completedAgo time.Duration) arkv1alpha1.Query {
+		return arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, CreationTimestamp: metav1.NewTime(now.Add(-completedAgo - time.Minute))},
+			Status: arkv1alpha1.QueryStatus{
+				Phase: phase,
+				Conditions: []metav1.Condition{{
+					Type:               string(arkv1alpha1.QueryCompleted),
+					Status:             metav1.ConditionTrue,
+					Reason:             "QuerySucceeded",
+					LastTransitionTime: metav1.NewTime(now.Add(-completedAgo)),
+				}},
+			},
+		}
+	}
+
+	names := func(queries []*arkv1alpha1.Query) []string {
+		result := make([]string, 0, len(queries))
+		for _, query := range queries {
+			result = append(result, query.Namespace+"/"+query.Name)
+		}
+		return result
+	}
+
+	It("should keep the last completed queries of each namespace", func() {
+		keep := int32(2)
+		queries := []arkv1alpha1.Query{
+			finished("a1", "team-a", statusDone, time.Hour),
+			finished("a2", "team-a", statusCanceled, 2*time.Hour),
+			finished("a3", "team-a", statusDone, 3*time.Hour),
+			finished("a4", "team-a", statusDone, 4*time.Hour),
+			finished("b1", "team-b", statusDone, 5*time.Hour),
+		}
+		expired := expiredByRetentionPolicy(&arkv1alpha1.QueryRetentionPolicySpec{KeepLastCompleted: &keep}, queries, now)
+		Expect(names(expired)).To(ConsistOf("team-a/a3", "team-a/a4"))
+	})
+
+	It("should delete errored queries after the error retention", func() {
+		queries := []arkv1alpha1.Query{
+			finished("old", "default", statusError, 3*24*time.Hour),
+			finished("recent", "default", statusError, time.Hour),
+			finished("retained", "default", statusError, 3*24*time.Hour),
+			finished("done", "default", statusDone, 3*24*time.Hour),
+		}
+		queries[2].Spec.RetainOnError = true
+		expired := expiredByRetentionPolicy(&arkv1alpha1.QueryRetentionPolicySpec{
+			ErrorRetention: &metav1.Duration{Duration: 48 * time.Hour},
+		}, queries, now)
+		Expect(names(expired)).To(ConsistOf("default/old"))
+	})
+
+	It("should never delete audit or running queries", func() {
+		keep := int32(0)
+		queries := []arkv1alpha1.Query{
+			finished("audited", "default", statusDone, time.Hour),
+			finished("audited-error", "default", statusError, time.Hour),
+			finished("running", "default", statusRunning, time.Hour),
+			finished("done", "default", statusDone, time.Hour),
+		}
+		queries[0].Labels = map[string]string{arkv1alpha1.QueryAuditLabel: "true"}
+		queries[1].Labels = map[string]string{arkv1alpha1.QueryAuditLabel: "true"}
+		expired := expiredByRetentionPolicy(&arkv1alpha1.QueryRetentionPolicySpec{
+			KeepLastCompleted: &keep,
+			ErrorRetention:    &metav1.Duration{},
+		}, queries, now)
+		Expect(names(expired)).To(ConsistOf("default/done"))
+	})
+})
@@ -0,0 +1,85 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+)
+
+// Objectives of a query SLO, as reported in the ark_query_slo_violations_total metric
+const (
+	sloObjectiveDuration = "duration"
+	sloObjectiveCost     = "cost"
+	sloObjectiveTokens   = "tokens"
+)
+
+// sloViolation is an objective of a query SLO the completed query exceeded
+type sloViolation struct {
+	objective string
+	message   string
+}
+
+// querySLOViolations returns the objectives of the SLO the completed query exceeded. The cost is
+// only checked when the models of the query have pricing.
+func querySLOViolations(slo *arkv1alpha1.QuerySLO, status *arkv1alpha1.QueryStatus, duration time.Duration) []sloViolation {
+	var violations []sloViolation
+	if slo.MaxDuration != nil && duration > slo.MaxDuration.Duration {
+		violations = append(violations, sloViolation{sloObjectiveDuration,
+			fmt.Sprintf("duration %s exceeds %s", duration.Round(time.Millisecond), slo.MaxDuration.Duration)})
+	}
+	if slo.MaxCost != "" && status.Cost != "" {
+		maxCost, maxErr := strconv.ParseFloat(slo.MaxCost, 64)
+		cost, costErr := strconv.ParseFloat(status.Cost, 64)
+		if maxErr == nil && costErr == nil && cost > maxCost {
+			violations = append(violations, sloViolation{sloObjectiveCost,
+				fmt.Sprintf("cost %s exceeds %s", status.Cost, slo.MaxCost)})
+		}
+	}
+	if slo.MaxTokens > 0 && status.TokenUsage.TotalTokens > slo.MaxTokens {
+		violations = append(violations, sloViolation{sloObjectiveTokens,
+			fmt.Sprintf("%d tokens exceed %d", status.TokenUsage.TotalTokens, slo.MaxTokens)})
+	}
+	return violations
+}
+
+// checkQuerySLO sets the SLOViolated condition of a completed query with an SLO. A violation is
+// recorded as a warning event and counted in the ark_query_slo_violations_total metric, by
+// objective, for alerting on runaway agents. The condition is saved with the final status.
+func (r *QueryReconciler) checkQuerySLO(query *arkv1alpha1.Query, duration time.Duration) {
+	slo := query.Spec.SLO
+	if slo == nil {
+		return
+	}
+
+	violations := querySLOViolations(slo, &query.Status, duration)
+	condition := metav1.Condition{
+		Type:               string(arkv1alpha1.QuerySLOViolated),
+		Status:             metav1.ConditionFalse,
+		Reason:             "WithinSLO",
+		Message:            "Query completed within its SLO",
+		ObservedGeneration: query.Generation,
+	}
+	if len(violations) > 0 {
+		messages := make([]string, 0, len(violations))
+		for _, violation := range violations {
+			messages = append(messages, violation.message)
+			metrics.IncQuerySLOViolation(violation.objective, query.Namespace, query.Labels[annotations.QueryTemplate])
+		}
+		condition.Status = metav1.ConditionTrue
+		condition.Reason = "SLOExceeded"
+		condition.Message = "Query exceeded its SLO: " + strings.Join(messages, ", ")
+		r.Recorder.Event(query, corev1.EventTypeWarning, "SLOViolated", condition.Message)
+	}
+	meta.SetStatusCondition(&query.Status.Conditions, condition)
+}
@@ -0,0 +1,82 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+)
+
+var _ = Describe("Query SLO", func() {
+	var (
+		query      *arkv1alpha1.Query
+		recorder   *record.FakeRecorder
+		reconciler *QueryReconciler
+	)
+
+	BeforeEach(func() {
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "summary",
+				Namespace: "slo-test",
+				Labels:    map[string]string{annotations.QueryTemplate: "summarize"},
+			},
+			Spec: arkv1alpha1.QuerySpec{SLO: &arkv1alpha1.QuerySLO{
+				MaxDuration: &metav1.Duration{Duration: time.Minute},
+				MaxCost:     "0.10",
+				MaxTokens:   10000,
+			}},
+			Status: arkv1alpha1.QueryStatus{
+				TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 4000},
+				Cost:       "0.020000",
+			},
+		}
+		recorder = record.NewFakeRecorder(10)
+		reconciler = &QueryReconciler{Recorder: recorder}
+	})
+
+	It("should mark a query within its SLO", func() {
+		reconciler.checkQuerySLO(query, 30*time.Second)
+
+		condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QuerySLOViolated))
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(recorder.Events).To(BeEmpty())
+	})
+
+	It("should report every exceeded objective in the condition, an event and the metric", func() {
+		query.Status.TokenUsage.TotalTokens = 25000
+		query.Status.Cost = "0.250000"
+		tokenViolations := testutil.ToFloat64(metrics.QuerySLOViolations.WithLabelValues("tokens", "slo-test", "summarize"))
+
+		reconciler.checkQuerySLO(query, 90*time.Second)
+
+		condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QuerySLOViolated))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+		Expect(condition.Reason).To(Equal("SLOExceeded"))
+		Expect(condition.Message).To(Equal("Query exceeded its SLO: duration 1m30s exceeds 1m0s, cost 0.250000 exceeds 0.10, 25000 tokens exceed 10000"))
+		Expect(recorder.Events).To(Receive(ContainSubstring("Warning SLOViolated")))
+		Expect(testutil.ToFloat64(metrics.QuerySLOViolations.WithLabelValues("tokens", "slo-test", "summarize"))).To(Equal(tokenViolations + 1))
+	})
+
+	It("should skip the cost when the models have no pricing", func() {
+		query.Status.Cost = ""
+		Expect(querySLOViolations(query.Spec.SLO, &query.Status, time.Second)).To(BeEmpty())
+	})
+
+	It("should not set a condition without an SLO", func() {
+		query.Spec.SLO = nil
+		reconciler.checkQuerySLO(query, time.Hour)
+		Expect(query.Status.Conditions).To(BeEmpty())
+	})
+})
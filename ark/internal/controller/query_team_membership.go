@@ -0,0 +1,25 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type teamMembershipKey struct{}
+
+// withTeamMembership returns a context in which the members of a team target are recorded as they
+// are resolved for the execution, and the members recorded in it
+func withTeamMembership(ctx context.Context) (context.Context, *[]arkv1alpha1.TeamMember) {
+	membership := &[]arkv1alpha1.TeamMember{}
+	return context.WithValue(ctx, teamMembershipKey{}, membership), membership
+}
+
+// recordTeamMembership records the resolved members of a team target in the context
+func recordTeamMembership(ctx context.Context, membership []arkv1alpha1.TeamMember) {
+	if recorded, ok := ctx.Value(teamMembershipKey{}).(*[]arkv1alpha1.TeamMember); ok {
+		*recorded = membership
+	}
+}
@@ -0,0 +1,37 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"errors"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query Team Membership", func() {
+	members := []arkv1alpha1.TeamMember{
+		{Name: "scout", Type: "agent", Role: "researcher"},
+		{Name: "reviewer", Type: "agent", Role: "critic"},
+	}
+
+	It("should record the resolved members of a team target in its response", func() {
+		ctx, recorded := withTeamMembership(context.Background())
+		recordTeamMembership(ctx, members)
+
+		reconciler := &QueryReconciler{}
+		result := targetResult{
+			target:  arkv1alpha1.QueryTarget{Type: "team", Name: "newsroom"},
+			err:     errors.New("reviewer failed"),
+			members: *recorded,
+		}
+		Expect(reconciler.targetResponse(result).Members).To(Equal(members))
+	})
+
+	It("should ignore members recorded outside a target execution", func() {
+		Expect(func() { recordTeamMembership(context.Background(), members) }).NotTo(Panic())
+	})
+})
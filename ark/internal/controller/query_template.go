@@ -0,0 +1,68 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+// evaluateTemplateQuery creates an evaluation of a done query for each evaluator of the
+// QueryTemplate it was created from. Failures are only logged, they do not fail the query.
+func (r *QueryReconciler) evaluateTemplateQuery(ctx context.Context, query *arkv1alpha1.Query) {
+	templateName := query.Labels[annotations.QueryTemplate]
+	if templateName == "" {
+		return
+	}
+	log := logf.FromContext(ctx)
+
+	var template arkv1alpha1.QueryTemplate
+	if err := r.Get(ctx, types.NamespacedName{Name: templateName, Namespace: query.Namespace}, &template); err != nil {
+		log.Error(err, "unable to get query template of query", "query", query.Name, "queryTemplate", templateName)
+		return
+	}
+
+	for _, evaluator := range template.Spec.Evaluators {
+		evaluation := templateQueryEvaluation(query, templateName, evaluator)
+		if err := r.Create(ctx, evaluation); err != nil && !apierrors.IsAlreadyExists(err) {
+			log.Error(err, "failed to create evaluation of query", "query", query.Name, "evaluator", evaluator.Name)
+		}
+	}
+}
+
+// templateQueryEvaluation returns the evaluation of the query by the evaluator. It is named like
+// the evaluations of evaluators with a selector, so a query selected by both is evaluated once.
+func templateQueryEvaluation(query *arkv1alpha1.Query, templateName string, evaluator arkv1alpha1.EvaluationEvaluatorRef) *arkv1alpha1.Evaluation {
+	return &arkv1alpha1.Evaluation{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      autoEvaluationName(evaluator.Name, query.Name),
+			Namespace: query.Namespace,
+			Labels: map[string]string{
+				annotations.Evaluator:     evaluator.Name,
+				annotations.Query:         query.Name,
+				annotations.QueryTemplate: templateName,
+			},
+			Annotations: map[string]string{
+				annotations.QueryGeneration: fmt.Sprintf("%d", query.Generation),
+				annotations.QueryPhase:      query.Status.Phase,
+			},
+		},
+		Spec: arkv1alpha1.EvaluationSpec{
+			Type: "query",
+			Config: arkv1alpha1.EvaluationConfig{
+				QueryBasedEvaluationConfig: &arkv1alpha1.QueryBasedEvaluationConfig{
+					QueryRef: &arkv1alpha1.QueryRef{Name: query.Name, Namespace: query.Namespace},
+				},
+			},
+			Evaluator: *evaluator.DeepCopy(),
+		},
+	}
+}
@@ -0,0 +1,91 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+var _ = Describe("Query Template Evaluation", func() {
+	var (
+		ctx         context.Context
+		reconciler  *QueryReconciler
+		evaluations map[string]*arkv1alpha1.Evaluation
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		template := &arkv1alpha1.QueryTemplate{
+			ObjectMeta: metav1.ObjectMeta{Name: "ticket-summary", Namespace: "default"},
+			Spec: arkv1alpha1.QueryTemplateSpec{
+				Evaluators: []arkv1alpha1.EvaluationEvaluatorRef{
+					{Name: "relevance", Parameters: []arkv1alpha1.Parameter{{Name: "scope", Value: "accuracy"}}},
+					{Name: "tone"},
+				},
+			},
+		}
+		// Evaluations are recorded by the interceptor, the fake client cannot store their inline config
+		evaluations = map[string]*arkv1alpha1.Evaluation{}
+		fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(template).WithInterceptorFuncs(interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				evaluation, ok := obj.(*arkv1alpha1.Evaluation)
+				if !ok {
+					return c.Create(ctx, obj, opts...)
+				}
+				if _, exists := evaluations[evaluation.Name]; exists {
+					return apierrors.NewAlreadyExists(arkv1alpha1.GroupVersion.WithResource("evaluations").GroupResource(), evaluation.Name)
+				}
+				evaluations[evaluation.Name] = evaluation
+				return nil
+			},
+		}).Build()
+		reconciler = &QueryReconciler{Client: fakeClient, Scheme: s}
+	})
+
+	It("Should create an evaluation for each evaluator of the template", func() {
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "ticket-summary-x7k2p",
+				Namespace: "default",
+				Labels:    map[string]string{annotations.QueryTemplate: "ticket-summary"},
+			},
+			Status: arkv1alpha1.QueryStatus{Phase: statusDone},
+		}
+
+		reconciler.evaluateTemplateQuery(ctx, query)
+		// Evaluating again, such as after a restart, keeps the existing evaluations
+		reconciler.evaluateTemplateQuery(ctx, query)
+
+		Expect(evaluations).To(HaveLen(2))
+		Expect(evaluations).To(HaveKey("tone-ticket-summary-x7k2p-eval"))
+		evaluation := evaluations["relevance-ticket-summary-x7k2p-eval"]
+		Expect(evaluation).NotTo(BeNil())
+		Expect(evaluation.Namespace).To(Equal("default"))
+		Expect(evaluation.Spec.Type).To(Equal("query"))
+		Expect(evaluation.Spec.Config.QueryRef.Name).To(Equal(query.Name))
+		Expect(evaluation.Spec.Evaluator.Parameters).To(Equal([]arkv1alpha1.Parameter{{Name: "scope", Value: "accuracy"}}))
+		Expect(evaluation.Labels).To(HaveKeyWithValue(annotations.QueryTemplate, "ticket-summary"))
+	})
+
+	It("Should not evaluate queries without a template", func() {
+		query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "plain", Namespace: "default"}}
+
+		reconciler.evaluateTemplateQuery(ctx, query)
+
+		Expect(evaluations).To(BeEmpty())
+	})
+})
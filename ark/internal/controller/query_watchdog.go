@@ -0,0 +1,102 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// StuckQueryPolicy is what the watchdog does with a query whose execution was lost
+type StuckQueryPolicy string
+
+const (
+	// StuckQueryRestart starts the execution again, resuming from the query checkpoint
+	StuckQueryRestart StuckQueryPolicy = "restart"
+	// StuckQueryFail moves the query to error with the QueryStuck reason
+	StuckQueryFail StuckQueryPolicy = "error"
+)
+
+const (
+	defaultStuckQueryGracePeriod = 10 * time.Minute
+	stuckQueryReason             = "QueryStuck"
+)
+
+// QueryWatchdog detects running queries that have no execution in the controller, for example
+// because the controller crashed or the final status update of the execution was lost. While a
+// query runs, it is checked every grace period. A query without an execution is stuck once it made
+// no progress for the grace period. A zero grace period disables the watchdog.
+type QueryWatchdog struct {
+	GracePeriod time.Duration
+	Policy      StuckQueryPolicy
+}
+
+// QueryWatchdogFromEnv reads the watchdog settings from ARK_QUERY_STUCK_GRACE_SECONDS, 0 to
+// disable the watchdog, and ARK_QUERY_STUCK_POLICY, restart or error.
+func QueryWatchdogFromEnv() (QueryWatchdog, error) {
+	watchdog := QueryWatchdog{GracePeriod: defaultStuckQueryGracePeriod, Policy: StuckQueryRestart}
+
+	if value := strings.TrimSpace(os.Getenv("ARK_QUERY_STUCK_GRACE_SECONDS")); value != "" {
+		seconds, err := strconv.Atoi(value)
+		if err != nil || seconds < 0 {
+			return QueryWatchdog{}, fmt.Errorf("invalid ARK_QUERY_STUCK_GRACE_SECONDS '%s': must be a non-negative integer", value)
+		}
+		watchdog.GracePeriod = time.Duration(seconds) * time.Second
+	}
+
+	switch policy := StuckQueryPolicy(strings.TrimSpace(os.Getenv("ARK_QUERY_STUCK_POLICY"))); policy {
+	case "":
+	case StuckQueryRestart, StuckQueryFail:
+		watchdog.Policy = policy
+	default:
+		return QueryWatchdog{}, fmt.Errorf("invalid ARK_QUERY_STUCK_POLICY '%s': must be restart or error", policy)
+	}
+	return watchdog, nil
+}
+
+// stuck reports whether a running query without an execution made no progress for the grace period
+func (w QueryWatchdog) stuck(query *arkv1alpha1.Query, now time.Time) bool {
+	if w.GracePeriod <= 0 {
+		return false
+	}
+	return now.Sub(lastQueryProgress(query)) > w.GracePeriod
+}
+
+// lastQueryProgress is the time the query last saved a checkpoint, or its completed condition last
+// changed if it has not saved one
+func lastQueryProgress(query *arkv1alpha1.Query) time.Time {
+	var last time.Time
+	if condition := meta.FindStatusCondition(query.Status.Conditions, string(arkv1alpha1.QueryCompleted)); condition != nil {
+		last = condition.LastTransitionTime.Time
+	}
+	if checkpoint := query.Status.Checkpoint; checkpoint != nil && checkpoint.UpdatedAt.After(last) {
+		last = checkpoint.UpdatedAt.Time
+	}
+	return last
+}
+
+// failStuckQuery moves a query whose execution was lost to error
+func (r *QueryReconciler) failStuckQuery(ctx context.Context, query *arkv1alpha1.Query) error {
+	message := fmt.Sprintf("Query execution was lost: no progress for %s", r.Watchdog.GracePeriod)
+	if r.Recorder != nil {
+		r.Recorder.Event(query, corev1.EventTypeWarning, stuckQueryReason, message)
+	}
+	query.Status.Phase = statusError
+	query.Status.Checkpoint = nil
+	r.setConditionCompleted(query, metav1.ConditionTrue, stuckQueryReason, message)
+	if err := r.Status().Update(ctx, query); err != nil {
+		return err
+	}
+	r.notifyQueryEnd(ctx, query)
+	return nil
+}
@@ -0,0 +1,96 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Query Watchdog", func() {
+	var (
+		ctx   context.Context
+		query *arkv1alpha1.Query
+		key   = types.NamespacedName{Name: "forecast", Namespace: "default"}
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Status: arkv1alpha1.QueryStatus{
+				Phase: statusRunning,
+				Conditions: []metav1.Condition{{
+					Type:               string(arkv1alpha1.QueryCompleted),
+					Status:             metav1.ConditionFalse,
+					Reason:             "QueryRunning",
+					LastTransitionTime: metav1.NewTime(time.Now().Add(-20 * time.Minute)),
+				}},
+			},
+		}
+	})
+
+	It("should detect queries without progress for the grace period", func() {
+		watchdog := QueryWatchdog{GracePeriod: 10 * time.Minute, Policy: StuckQueryRestart}
+		Expect(watchdog.stuck(query, time.Now())).To(BeTrue())
+
+		query.Status.Checkpoint = &arkv1alpha1.QueryCheckpoint{UpdatedAt: metav1.NewTime(time.Now().Add(-time.Minute))}
+		Expect(watchdog.stuck(query, time.Now())).To(BeFalse())
+
+		query.Status.Checkpoint = nil
+		Expect(QueryWatchdog{}.stuck(query, time.Now())).To(BeFalse())
+	})
+
+	It("should fail stuck queries with the error policy", func() {
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(s).WithObjects(query).WithStatusSubresource(query).Build()
+		reconciler := &QueryReconciler{Client: fakeClient, Watchdog: QueryWatchdog{GracePeriod: 10 * time.Minute, Policy: StuckQueryFail}}
+
+		_, err := reconciler.handleRunningPhase(ctx, ctrl.Request{NamespacedName: key}, *query)
+		Expect(err).NotTo(HaveOccurred())
+
+		updated := &arkv1alpha1.Query{}
+		Expect(fakeClient.Get(ctx, key, updated)).To(Succeed())
+		Expect(updated.Status.Phase).To(Equal(statusError))
+		condition := meta.FindStatusCondition(updated.Status.Conditions, string(arkv1alpha1.QueryCompleted))
+		Expect(condition.Reason).To(Equal(stuckQueryReason))
+		Expect(condition.Status).To(Equal(metav1.ConditionTrue))
+	})
+
+	It("should check running queries again after the grace period", func() {
+		reconciler := &QueryReconciler{Watchdog: QueryWatchdog{GracePeriod: 10 * time.Minute}}
+		reconciler.operations.Store(key, context.CancelFunc(func() {}))
+
+		result, err := reconciler.handleRunningPhase(ctx, ctrl.Request{NamespacedName: key}, *query)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(10 * time.Minute))
+	})
+
+	It("should read the watchdog settings from the environment", func() {
+		watchdog, err := QueryWatchdogFromEnv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watchdog).To(Equal(QueryWatchdog{GracePeriod: defaultStuckQueryGracePeriod, Policy: StuckQueryRestart}))
+
+		GinkgoT().Setenv("ARK_QUERY_STUCK_GRACE_SECONDS", "0")
+		GinkgoT().Setenv("ARK_QUERY_STUCK_POLICY", "error")
+		watchdog, err = QueryWatchdogFromEnv()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(watchdog).To(Equal(QueryWatchdog{Policy: StuckQueryFail}))
+
+		GinkgoT().Setenv("ARK_QUERY_STUCK_POLICY", "ignore")
+		_, err = QueryWatchdogFromEnv()
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,156 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const defaultQueryRetentionInterval = 10 * time.Minute
+
+// QueryRetentionPolicyReconciler garbage collects finished queries by the retention policies of
+// the cluster. Each policy runs every interval and deletes the queries it no longer retains.
+type QueryRetentionPolicyReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queryretentionpolicies,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queryretentionpolicies/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch;delete
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+
+func (r *QueryRetentionPolicyReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var policy arkv1alpha1.QueryRetentionPolicy
+	if err := r.Get(ctx, req.NamespacedName, &policy); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to fetch query retention policy", "queryRetentionPolicy", req.Name)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	interval := defaultQueryRetentionInterval
+	if policy.Spec.Interval != nil && policy.Spec.Interval.Duration > 0 {
+		interval = policy.Spec.Interval.Duration
+	}
+
+	queries, err := r.listQueries(ctx, policy.Spec.Namespaces)
+	if err != nil {
+		log.Error(err, "failed to list queries", "queryRetentionPolicy", policy.Name)
+		return ctrl.Result{}, err
+	}
+
+	var deleted int32
+	for _, query := range expiredByRetentionPolicy(&policy.Spec, queries, time.Now()) {
+		if err := r.Delete(ctx, query); client.IgnoreNotFound(err) != nil {
+			log.Error(err, "failed to delete query", "query", query.Name, "namespace", query.Namespace)
+			r.Recorder.Event(&policy, corev1.EventTypeWarning, "QueryDeleteFailed", fmt.Sprintf("Failed to delete query %s/%s: %v", query.Namespace, query.Name, err))
+			continue
+		}
+		log.Info("deleted query by retention policy", "query", query.Name, "namespace", query.Namespace, "queryRetentionPolicy", policy.Name)
+		deleted++
+	}
+	if deleted > 0 {
+		r.Recorder.Event(&policy, corev1.EventTypeNormal, "QueriesDeleted", fmt.Sprintf("Deleted %d queries", deleted))
+	}
+
+	now := metav1.Now()
+	policy.Status.LastRunTime = &now
+	policy.Status.LastDeleted = deleted
+	policy.Status.TotalDeleted += int64(deleted)
+	if err := r.Status().Update(ctx, &policy); err != nil {
+		log.Error(err, "failed to update query retention policy status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: interval}, nil
+}
+
+// listQueries lists the queries of the namespaces, or of every namespace when none are given
+func (r *QueryRetentionPolicyReconciler) listQueries(ctx context.Context, namespaces []string) ([]arkv1alpha1.Query, error) {
+	if len(namespaces) == 0 {
+		var list arkv1alpha1.QueryList
+		if err := r.List(ctx, &list); err != nil {
+			return nil, err
+		}
+		return list.Items, nil
+	}
+
+	var queries []arkv1alpha1.Query
+	for _, namespace := range namespaces {
+		var list arkv1alpha1.QueryList
+		if err := r.List(ctx, &list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		queries = append(queries, list.Items...)
+	}
+	return queries, nil
+}
+
+// expiredByRetentionPolicy returns the queries the policy deletes: done and canceled queries
+// beyond the most recent keepLastCompleted of their namespace, and errored queries without
+// retainOnError that failed more than errorRetention ago. Queries that have not finished and
+// queries labelled audit=true are always kept.
+func expiredByRetentionPolicy(spec *arkv1alpha1.QueryRetentionPolicySpec, queries []arkv1alpha1.Query, now time.Time) []*arkv1alpha1.Query {
+	var expired []*arkv1alpha1.Query
+	completed := map[string][]*arkv1alpha1.Query{}
+
+	for i := range queries {
+		query := &queries[i]
+		if arkv1alpha1.IsAuditQuery(query) || !query.DeletionTimestamp.IsZero() {
+			continue
+		}
+		switch query.Status.Phase {
+		case statusDone, statusCanceled:
+			completed[query.Namespace] = append(completed[query.Namespace], query)
+		case statusError:
+			if spec.ErrorRetention == nil || query.Spec.RetainOnError {
+				continue
+			}
+			if !now.Before(queryCompletedAt(query).Add(spec.ErrorRetention.Duration)) {
+				expired = append(expired, query)
+			}
+		}
+	}
+
+	if spec.KeepLastCompleted == nil {
+		return expired
+	}
+	keep := int(*spec.KeepLastCompleted)
+	for _, namespaceQueries := range completed {
+		if len(namespaceQueries) <= keep {
+			continue
+		}
+		sort.Slice(namespaceQueries, func(i, j int) bool {
+			a, b := queryCompletedAt(namespaceQueries[i]), queryCompletedAt(namespaceQueries[j])
+			if a.Equal(b) {
+				return namespaceQueries[i].Name < namespaceQueries[j].Name
+			}
+			return a.After(b)
+		})
+		expired = append(expired, namespaceQueries[keep:]...)
+	}
+	return expired
+}
+
+func (r *QueryRetentionPolicyReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.QueryRetentionPolicy{}).
+		Named("queryretentionpolicy").
+		Complete(r)
+}
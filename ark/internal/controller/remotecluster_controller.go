@@ -0,0 +1,91 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+const (
+	// Condition types
+	RemoteClusterAvailable = "RemoteClusterAvailable"
+
+	defaultRemoteClusterPollInterval = time.Minute
+)
+
+type RemoteClusterReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Recorder record.EventRecorder
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=remoteclusters,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=remoteclusters/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups="",resources=secrets,verbs=get;list;watch
+
+func (r *RemoteClusterReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var cluster arkv1alpha1.RemoteCluster
+	if err := r.Get(ctx, req.NamespacedName, &cluster); err != nil {
+		if client.IgnoreNotFound(err) != nil {
+			log.Error(err, "unable to fetch remote cluster", "remoteCluster", req.NamespacedName)
+		}
+		return ctrl.Result{}, client.IgnoreNotFound(err)
+	}
+
+	pollInterval := defaultRemoteClusterPollInterval
+	if cluster.Spec.PollInterval != nil && cluster.Spec.PollInterval.Duration > 0 {
+		pollInterval = cluster.Spec.PollInterval.Duration
+	}
+
+	host, err := genai.ProbeRemoteCluster(ctx, r.Client, &cluster)
+	cluster.Status.Host = host
+	if err != nil {
+		// Probe failures are expected while a cluster is unreachable, the condition reports them
+		log.Info("remote cluster probe failed", "remoteCluster", cluster.Name, "error", err)
+		r.setCondition(&cluster, metav1.ConditionFalse, "RemoteClusterProbeFailed", err.Error())
+		r.Recorder.Event(&cluster, corev1.EventTypeWarning, "RemoteClusterProbeFailed", err.Error())
+	} else {
+		r.setCondition(&cluster, metav1.ConditionTrue, "Available", fmt.Sprintf("Remote cluster %s is reachable", host))
+	}
+
+	if err := r.Status().Update(ctx, &cluster); err != nil {
+		log.Error(err, "failed to update remote cluster status")
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{RequeueAfter: pollInterval}, nil
+}
+
+// setCondition sets the availability condition on the RemoteCluster
+func (r *RemoteClusterReconciler) setCondition(cluster *arkv1alpha1.RemoteCluster, status metav1.ConditionStatus, reason, message string) {
+	meta.SetStatusCondition(&cluster.Status.Conditions, metav1.Condition{
+		Type:               RemoteClusterAvailable,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: cluster.Generation,
+	})
+}
+
+func (r *RemoteClusterReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.RemoteCluster{}).
+		Named("remotecluster").
+		Complete(r)
+}
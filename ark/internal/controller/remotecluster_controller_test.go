@@ -0,0 +1,69 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("RemoteCluster Controller", func() {
+	var (
+		ctx        context.Context
+		key        = types.NamespacedName{Name: "eu-west", Namespace: "default"}
+		fakeClient client.Client
+		reconciler *RemoteClusterReconciler
+	)
+
+	setup := func(spec arkv1alpha1.RemoteClusterSpec) {
+		ctx = context.Background()
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(corev1.AddToScheme(s)).To(Succeed())
+		cluster := &arkv1alpha1.RemoteCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: key.Name, Namespace: key.Namespace},
+			Spec:       spec,
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(cluster).WithStatusSubresource(cluster).Build()
+		reconciler = &RemoteClusterReconciler{Client: fakeClient, Scheme: s, Recorder: record.NewFakeRecorder(10)}
+	}
+
+	It("Should mark a remote cluster with missing credentials unavailable", func() {
+		setup(arkv1alpha1.RemoteClusterSpec{
+			Endpoint: &arkv1alpha1.RemoteClusterEndpoint{
+				URL: "https://eu-west.example.com",
+				TokenSecretRef: corev1.SecretKeySelector{
+					LocalObjectReference: corev1.LocalObjectReference{Name: "eu-west-token"},
+					Key:                  "token",
+				},
+			},
+			PollInterval: &metav1.Duration{Duration: 30 * time.Second},
+		})
+
+		result, err := reconciler.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.RequeueAfter).To(Equal(30 * time.Second))
+
+		cluster := &arkv1alpha1.RemoteCluster{}
+		Expect(fakeClient.Get(ctx, key, cluster)).To(Succeed())
+		condition := meta.FindStatusCondition(cluster.Status.Conditions, RemoteClusterAvailable)
+		Expect(condition).NotTo(BeNil())
+		Expect(condition.Status).To(Equal(metav1.ConditionFalse))
+		Expect(condition.Reason).To(Equal("RemoteClusterProbeFailed"))
+		Expect(condition.Message).To(ContainSubstring("eu-west-token"))
+	})
+})
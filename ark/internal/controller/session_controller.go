@@ -0,0 +1,237 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// SessionReconciler maintains a Session for every session ID used by queries, with the totals of
+// the queries of the session and of their evaluations
+type SessionReconciler struct {
+	client.Client
+	Scheme   *runtime.Scheme
+	Notifier *genai.Notifier
+}
+
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=sessions,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=sessions/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=queries,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=evaluations,verbs=get;list;watch
+// +kubebuilder:rbac:groups=ark.mckinsey.com,resources=notificationsinks,verbs=get;list;watch
+
+func (r *SessionReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	log := logf.FromContext(ctx)
+
+	var queries arkv1alpha1.QueryList
+	if err := r.List(ctx, &queries, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	sessionQueries := make([]arkv1alpha1.Query, 0)
+	for _, query := range queries.Items {
+		if query.Spec.SessionId != "" && sessionName(query.Spec.SessionId) == req.Name {
+			sessionQueries = append(sessionQueries, query)
+		}
+	}
+
+	var session arkv1alpha1.Session
+	err := r.Get(ctx, req.NamespacedName, &session)
+	if err != nil && !errors.IsNotFound(err) {
+		return ctrl.Result{}, err
+	}
+	exists := err == nil
+
+	if len(sessionQueries) == 0 {
+		if exists {
+			log.Info("deleting session without queries", "session", req.Name)
+			return ctrl.Result{}, client.IgnoreNotFound(r.Delete(ctx, &session))
+		}
+		return ctrl.Result{}, nil
+	}
+
+	var evaluations arkv1alpha1.EvaluationList
+	if err := r.List(ctx, &evaluations, client.InNamespace(req.Namespace)); err != nil {
+		return ctrl.Result{}, err
+	}
+	status := aggregateSession(sessionQueries, evaluations.Items)
+
+	if !exists {
+		session = arkv1alpha1.Session{
+			ObjectMeta: metav1.ObjectMeta{Name: req.Name, Namespace: req.Namespace},
+			Spec:       arkv1alpha1.SessionSpec{SessionId: sessionQueries[0].Spec.SessionId},
+		}
+		if err := r.Create(ctx, &session); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
+	if equality.Semantic.DeepEqual(session.Status, status) {
+		return ctrl.Result{}, nil
+	}
+	previous := session.Status
+	session.Status = status
+	if err := r.Status().Update(ctx, &session); err != nil {
+		return ctrl.Result{}, err
+	}
+	r.Notifier.NotifyBudget(ctx, &session, previous, status)
+	return ctrl.Result{}, nil
+}
+
+// aggregateSession returns the totals of the queries of a session and of the completed evaluations of
+// those queries
+func aggregateSession(queries []arkv1alpha1.Query, evaluations []arkv1alpha1.Evaluation) arkv1alpha1.SessionStatus {
+	var status arkv1alpha1.SessionStatus
+	var duration time.Duration
+	var cost float64
+	costed := false
+	queryNames := make(map[string]bool, len(queries))
+	memories := make(map[arkv1alpha1.MemoryRef]bool)
+
+	for _, query := range queries {
+		queryNames[query.Name] = true
+		status.Queries++
+		if query.Status.Phase == statusError {
+			status.FailedQueries++
+		}
+
+		addTokenUsage(&status.TokenUsage, query.Status.TokenUsage)
+		if query.Status.Duration != nil {
+			duration += query.Status.Duration.Duration
+		}
+		if value, err := strconv.ParseFloat(query.Status.Cost, 64); err == nil {
+			cost += value
+			costed = true
+		}
+
+		created := query.CreationTimestamp
+		if status.StartTime == nil || created.Before(status.StartTime) {
+			status.StartTime = created.DeepCopy()
+		}
+		if status.LastQueryTime == nil || status.LastQueryTime.Before(&created) {
+			status.LastQueryTime = created.DeepCopy()
+		}
+
+		if query.Spec.Memory != nil {
+			memory := *query.Spec.Memory
+			if memory.Namespace == "" {
+				memory.Namespace = query.Namespace
+			}
+			if !memories[memory] {
+				memories[memory] = true
+				status.Memories = append(status.Memories, memory)
+			}
+		}
+	}
+
+	if duration > 0 {
+		status.Duration = &metav1.Duration{Duration: duration}
+	}
+	if costed {
+		status.Cost = genai.FormatCost(cost)
+	}
+
+	var scores float64
+	for _, evaluation := range evaluations {
+		if !evaluatesSessionQuery(&evaluation, queryNames) || evaluation.Status.Phase != statusDone {
+			continue
+		}
+		score, err := strconv.ParseFloat(evaluation.Status.Score, 64)
+		if err != nil {
+			continue
+		}
+		status.Evaluations++
+		scores += score
+		if evaluation.Status.Passed {
+			status.PassedEvaluations++
+		}
+	}
+	if status.Evaluations > 0 {
+		status.AverageScore = strconv.FormatFloat(scores/float64(status.Evaluations), 'f', 2, 64)
+	}
+
+	return status
+}
+
+// evaluatesSessionQuery reports whether an evaluation references one of the queries of a session
+func evaluatesSessionQuery(evaluation *arkv1alpha1.Evaluation, queryNames map[string]bool) bool {
+	ref := localQueryRef(evaluation)
+	return ref != nil && queryNames[ref.Name]
+}
+
+// localQueryRef returns the query reference of an evaluation when it references a query in its own
+// namespace. Sessions only aggregate evaluations of their namespace.
+func localQueryRef(evaluation *arkv1alpha1.Evaluation) *arkv1alpha1.QueryRef {
+	config := evaluation.Spec.Config.QueryBasedEvaluationConfig
+	if config == nil || config.QueryRef == nil {
+		return nil
+	}
+	if config.QueryRef.Namespace != "" && config.QueryRef.Namespace != evaluation.Namespace {
+		return nil
+	}
+	return config.QueryRef
+}
+
+// sessionName returns the name of the Session of a session ID. IDs that are not valid resource
+// names are hashed.
+func sessionName(sessionId string) string {
+	if len(validation.IsDNS1123Subdomain(sessionId)) == 0 {
+		return sessionId
+	}
+	sum := sha256.Sum256([]byte(sessionId))
+	return "session-" + hex.EncodeToString(sum[:8])
+}
+
+func (r *SessionReconciler) findSessionForQuery(ctx context.Context, obj client.Object) []reconcile.Request {
+	query, ok := obj.(*arkv1alpha1.Query)
+	if !ok || query.Spec.SessionId == "" {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: sessionName(query.Spec.SessionId), Namespace: query.Namespace}}}
+}
+
+func (r *SessionReconciler) findSessionForEvaluation(ctx context.Context, obj client.Object) []reconcile.Request {
+	evaluation, ok := obj.(*arkv1alpha1.Evaluation)
+	if !ok {
+		return nil
+	}
+	ref := localQueryRef(evaluation)
+	if ref == nil {
+		return nil
+	}
+
+	var query arkv1alpha1.Query
+	key := types.NamespacedName{Name: ref.Name, Namespace: evaluation.Namespace}
+	if err := r.Get(ctx, key, &query); err != nil {
+		return nil
+	}
+	return r.findSessionForQuery(ctx, &query)
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (r *SessionReconciler) SetupWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		For(&arkv1alpha1.Session{}).
+		Watches(&arkv1alpha1.Query{}, handler.EnqueueRequestsFromMapFunc(r.findSessionForQuery)).
+		Watches(&arkv1alpha1.Evaluation{}, handler.EnqueueRequestsFromMapFunc(r.findSessionForEvaluation)).
+		Named("session").
+		Complete(r)
+}
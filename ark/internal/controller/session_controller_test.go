@@ -0,0 +1,112 @@
+/* Copyright 2025. McKinsey & Company */
+
+package controller
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Session aggregation", func() {
+	var (
+		start   time.Time
+		queries []arkv1alpha1.Query
+	)
+
+	BeforeEach(func() {
+		start = time.Now().Add(-time.Hour)
+		queries = []arkv1alpha1.Query{
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "first", Namespace: "default", CreationTimestamp: metav1.NewTime(start)},
+				Spec: arkv1alpha1.QuerySpec{
+					SessionId: "chat-1",
+					Memory:    &arkv1alpha1.MemoryRef{Name: "conversations"},
+				},
+				Status: arkv1alpha1.QueryStatus{
+					Phase:      statusDone,
+					TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+					Duration:   &metav1.Duration{Duration: 2 * time.Second},
+					Cost:       "0.001500",
+				},
+			},
+			{
+				ObjectMeta: metav1.ObjectMeta{Name: "second", Namespace: "default", CreationTimestamp: metav1.NewTime(start.Add(time.Minute))},
+				Spec: arkv1alpha1.QuerySpec{
+					SessionId: "chat-1",
+					Memory:    &arkv1alpha1.MemoryRef{Name: "conversations", Namespace: "default"},
+				},
+				Status: arkv1alpha1.QueryStatus{
+					Phase:      statusError,
+					TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 50, CompletionTokens: 10, TotalTokens: 60},
+					Duration:   &metav1.Duration{Duration: 3 * time.Second},
+					Cost:       "0.000500",
+				},
+			},
+		}
+	})
+
+	queryEvaluation := func(name, query, phase, score string, passed bool) arkv1alpha1.Evaluation {
+		return arkv1alpha1.Evaluation{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+			Spec: arkv1alpha1.EvaluationSpec{
+				Type: "query",
+				Config: arkv1alpha1.EvaluationConfig{
+					QueryBasedEvaluationConfig: &arkv1alpha1.QueryBasedEvaluationConfig{QueryRef: &arkv1alpha1.QueryRef{Name: query}},
+				},
+			},
+			Status: arkv1alpha1.EvaluationStatus{Phase: phase, Score: score, Passed: passed},
+		}
+	}
+
+	It("should total the queries of the session", func() {
+		status := aggregateSession(queries, nil)
+
+		Expect(status.Queries).To(Equal(int32(2)))
+		Expect(status.FailedQueries).To(Equal(int32(1)))
+		Expect(status.TokenUsage).To(Equal(arkv1alpha1.TokenUsage{PromptTokens: 150, CompletionTokens: 30, TotalTokens: 180}))
+		Expect(status.Duration.Duration).To(Equal(5 * time.Second))
+		Expect(status.Cost).To(Equal("0.002000"))
+		Expect(status.StartTime.Time).To(BeTemporally("==", metav1.NewTime(start).Time))
+		Expect(status.LastQueryTime.Time).To(BeTemporally("==", metav1.NewTime(start.Add(time.Minute)).Time))
+		Expect(status.Memories).To(Equal([]arkv1alpha1.MemoryRef{{Name: "conversations", Namespace: "default"}}))
+		Expect(status.Evaluations).To(BeZero())
+		Expect(status.AverageScore).To(BeEmpty())
+	})
+
+	It("should leave the cost empty when no query has a cost", func() {
+		queries[0].Status.Cost = ""
+		queries[1].Status.Cost = ""
+
+		Expect(aggregateSession(queries, nil).Cost).To(BeEmpty())
+	})
+
+	It("should average the completed evaluations of the session queries", func() {
+		evaluations := []arkv1alpha1.Evaluation{
+			queryEvaluation("first-eval", "first", statusDone, "0.9", true),
+			queryEvaluation("second-eval", "second", statusDone, "0.4", false),
+			queryEvaluation("running-eval", "second", statusRunning, "", false),
+			queryEvaluation("other-eval", "other", statusDone, "0.1", false),
+			{ObjectMeta: metav1.ObjectMeta{Name: "direct-eval", Namespace: "default"}, Status: arkv1alpha1.EvaluationStatus{Phase: statusDone, Score: "0.1"}},
+		}
+
+		status := aggregateSession(queries, evaluations)
+
+		Expect(status.Evaluations).To(Equal(int32(2)))
+		Expect(status.PassedEvaluations).To(Equal(int32(1)))
+		Expect(status.AverageScore).To(Equal("0.65"))
+	})
+
+	It("should name sessions after their ID when it is a valid name", func() {
+		Expect(sessionName("chat-1")).To(Equal("chat-1"))
+
+		hashed := sessionName("User Chat #1")
+		Expect(hashed).To(HavePrefix("session-"))
+		Expect(hashed).To(HaveLen(len("session-") + 16))
+		Expect(sessionName("User Chat #1")).To(Equal(hashed))
+	})
+})
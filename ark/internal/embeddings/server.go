@@ -0,0 +1,146 @@
+/* Copyright 2025. McKinsey & Company */
+
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/kubeauth"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	embedTimeout    = time.Minute
+	shutdownTimeout = 10 * time.Second
+	// maxInputs is the largest batch embedding providers accept in one request
+	maxInputs = 2048
+)
+
+// Request is the body of an embedding request
+type Request struct {
+	Input []string `json:"input"`
+}
+
+// Server serves the embeddings of Models of type embedding on
+// POST /namespaces/{namespace}/models/{model}/embeddings, for memory services and other in-cluster
+// consumers. It runs as a manager runnable on every replica. With an Authorizer, callers must send a
+// Kubernetes bearer token that may get the model.
+type Server struct {
+	Client        client.Client
+	ModelRecorder telemetry.ModelRecorder
+	Authorizer    *kubeauth.Authorizer
+	Addr          string
+}
+
+// Handler returns the HTTP handler of the embeddings endpoint
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	var embed http.Handler = http.HandlerFunc(s.embed)
+	if s.Authorizer != nil {
+		embed = s.Authorizer.Middleware(embed)
+	}
+	mux.Handle("POST /namespaces/{namespace}/models/{model}/embeddings", embed)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// Start serves until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("embeddings")
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return logf.IntoContext(ctx, log) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "failed to shut down embeddings endpoint")
+		}
+	}()
+
+	log.Info("serving embeddings", "address", s.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection is false so every replica serves embeddings
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+func (s *Server) embed(w http.ResponseWriter, r *http.Request) {
+	namespace, name := r.PathValue("namespace"), r.PathValue("model")
+	var request Request
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "invalid embedding request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if len(request.Input) == 0 || len(request.Input) > maxInputs {
+		http.Error(w, fmt.Sprintf("input must contain between 1 and %d texts", maxInputs), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), embedTimeout)
+	defer cancel()
+
+	if err := s.Authorizer.Authorize(ctx, authorizationv1.ResourceAttributes{
+		Verb: "get", Group: arkv1alpha1.GroupVersion.Group, Resource: "models", Namespace: namespace, Name: name,
+	}); err != nil {
+		kubeauth.WriteError(w, err)
+		return
+	}
+
+	var modelCRD arkv1alpha1.Model
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &modelCRD); err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	if modelCRD.Spec.Type != genai.ModelTypeEmbedding {
+		http.Error(w, "model "+name+" is not an embedding model", http.StatusBadRequest)
+		return
+	}
+
+	model, err := genai.MakeModel(ctx, s.Client, &modelCRD, s.ModelRecorder)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	embeddings, err := model.Embed(ctx, request.Input)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "embedding failed", "model", name, "namespace", namespace)
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+
+	w.Header().Set("Content-Type", genai.ContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(embeddings); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to write embedding response")
+	}
+}
@@ -0,0 +1,106 @@
+/* Copyright 2025. McKinsey & Company */
+
+package embeddings
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/kubeauth"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestServerEmbed(t *testing.T) {
+	provider := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "model": "text-embedding-3-small",
+			"data": [{"object": "embedding", "index": 0, "embedding": [0.5, 0.25]}],
+			"usage": {"prompt_tokens": 2, "total_tokens": 2}}`))
+	}))
+	defer provider.Close()
+
+	config := arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+		BaseURL: arkv1alpha1.ValueSource{Value: provider.URL},
+		APIKey:  arkv1alpha1.ValueSource{Value: "key"},
+	}}
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "embeddings", Namespace: "default"},
+			Spec:       arkv1alpha1.ModelSpec{Type: genai.ModelTypeEmbedding, Model: arkv1alpha1.ValueSource{Value: "text-embedding-3-small"}, Config: config},
+		},
+		&arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "gpt-4o", Namespace: "default"},
+			Spec:       arkv1alpha1.ModelSpec{Type: genai.ModelTypeOpenAI, Model: arkv1alpha1.ValueSource{Value: "gpt-4o"}, Config: config},
+		},
+	).Build()
+	server := &Server{Client: k8sClient, ModelRecorder: noop.NewModelRecorder()}
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, path, strings.NewReader(body)))
+		return recorder
+	}
+
+	recorder := post("/namespaces/default/models/embeddings/embeddings", `{"input": ["hello"]}`)
+	require.Equal(t, http.StatusOK, recorder.Code, recorder.Body.String())
+	var response genai.Embeddings
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Equal(t, [][]float64{{0.5, 0.25}}, response.Vectors)
+	assert.Equal(t, int64(2), response.Usage.PromptTokens)
+
+	assert.Equal(t, http.StatusBadRequest, post("/namespaces/default/models/embeddings/embeddings", `{"input": []}`).Code)
+	assert.Equal(t, http.StatusBadRequest, post("/namespaces/default/models/gpt-4o/embeddings", `{"input": ["hello"]}`).Code)
+	assert.Equal(t, http.StatusNotFound, post("/namespaces/default/models/missing/embeddings", `{"input": ["hello"]}`).Code)
+}
+
+func TestServerEmbedAuthorization(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				review.Status = authenticationv1.TokenReviewStatus{Authenticated: review.Spec.Token == "valid"}
+			case *authorizationv1.SubjectAccessReview:
+				review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a"
+			}
+			return nil
+		},
+	}).Build()
+	server := &Server{Client: k8sClient, Authorizer: &kubeauth.Authorizer{Client: k8sClient}}
+
+	post := func(path, token string) int {
+		request := httptest.NewRequest(http.MethodPost, path, strings.NewReader(`{"input": ["hello"]}`))
+		if token != "" {
+			request.Header.Set("Authorization", "Bearer "+token)
+		}
+		recorder := httptest.NewRecorder()
+		server.Handler().ServeHTTP(recorder, request)
+		return recorder.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, post("/namespaces/team-a/models/embeddings/embeddings", ""))
+	assert.Equal(t, http.StatusUnauthorized, post("/namespaces/team-a/models/embeddings/embeddings", "invalid"))
+	assert.Equal(t, http.StatusForbidden, post("/namespaces/team-b/models/embeddings/embeddings", "valid"))
+	assert.Equal(t, http.StatusNotFound, post("/namespaces/team-a/models/embeddings/embeddings", "valid"))
+}
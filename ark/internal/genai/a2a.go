@@ -3,10 +3,13 @@
 package genai
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -29,17 +32,18 @@ const (
 )
 
 // DiscoverA2AAgents discovers agents from an A2A server using simplified HTTP approach
-func DiscoverA2AAgents(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, namespace string) (*A2AAgentCard, error) {
-	return DiscoverA2AAgentsWithRecorder(ctx, k8sClient, address, headers, namespace, nil, nil)
+func DiscoverA2AAgents(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace string) ([]*A2AAgentCard, error) {
+	return DiscoverA2AAgentsWithRecorder(ctx, k8sClient, address, headers, auth, namespace, nil, nil)
 }
 
 // DiscoverA2AAgentsWithRecorder discovers agents with optional K8s event recording
 // Tries both A2A protocol versions: 0.3.x (agent-card.json) and 0.2.x (agent.json)
 // Note: protocol.AgentCardPath is version 0.2.x (agent.json) at time of writing
-func DiscoverA2AAgentsWithRecorder(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, namespace string, recorder record.EventRecorder, obj client.Object) (*A2AAgentCard, error) {
+// Servers may expose a single agent card, an array of cards, or a card listing sub-agents
+func DiscoverA2AAgentsWithRecorder(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace string, recorder record.EventRecorder, obj client.Object) ([]*A2AAgentCard, error) {
 	baseURL := strings.TrimSuffix(address, "/")
 
-	if err := validateA2AClient(address, headers, ctx, k8sClient, namespace, recorder, obj); err != nil {
+	if err := validateA2AClient(address, headers, auth, ctx, k8sClient, namespace, recorder, obj); err != nil {
 		return nil, err
 	}
 
@@ -53,18 +57,18 @@ func DiscoverA2AAgentsWithRecorder(ctx context.Context, k8sClient client.Client,
 
 	var lastErr error
 	for _, endpoint := range endpoints {
-		req, err := createA2ARequest(ctx, endpoint.url, headers, k8sClient, namespace, recorder, obj)
+		req, err := createA2ARequest(ctx, endpoint.url, headers, auth, k8sClient, namespace, recorder, obj)
 		if err != nil {
 			lastErr = err
 			continue
 		}
 
-		agentCard, err := executeA2ARequest(ctx, req, address, recorder, obj)
+		agentCards, err := executeA2ARequest(ctx, req, address, recorder, obj)
 		if err == nil {
 			if recorder != nil && obj != nil {
-				recorder.Event(obj, corev1.EventTypeNormal, "A2ADiscoverySuccess", fmt.Sprintf("Successfully discovered agent using %s at %s", endpoint.version, endpoint.url))
+				recorder.Event(obj, corev1.EventTypeNormal, "A2ADiscoverySuccess", fmt.Sprintf("Successfully discovered %d agent(s) using %s at %s", len(agentCards), endpoint.version, endpoint.url))
 			}
-			return agentCard, nil
+			return agentCards, nil
 		}
 
 		lastErr = err
@@ -76,17 +80,17 @@ func DiscoverA2AAgentsWithRecorder(ctx context.Context, k8sClient client.Client,
 }
 
 // ExecuteA2AAgent executes a task on an A2A agent using the official library client
-func ExecuteA2AAgent(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, namespace, input, agentName string) (string, error) {
-	return ExecuteA2AAgentWithRecorder(ctx, k8sClient, address, headers, namespace, input, agentName, nil, nil)
+func ExecuteA2AAgent(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace, input, agentName string) (string, error) {
+	return ExecuteA2AAgentWithRecorder(ctx, k8sClient, address, headers, auth, namespace, input, agentName, nil, nil)
 }
 
 // ExecuteA2AAgentWithRecorder executes a task on an A2A agent with optional K8s event recording
-func ExecuteA2AAgentWithRecorder(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, namespace, input, agentName string, recorder record.EventRecorder, obj client.Object) (string, error) {
+func ExecuteA2AAgentWithRecorder(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace, input, agentName string, recorder record.EventRecorder, obj client.Object) (string, error) {
 	rpcURL := strings.TrimSuffix(address, "/")
 	logf.FromContext(ctx).Info("calling A2A server", "url", rpcURL)
 
 	// Create and configure A2A client
-	a2aClient, err := createA2AClientForExecution(ctx, k8sClient, rpcURL, headers, namespace, agentName, recorder, obj)
+	a2aClient, err := createA2AClientForExecution(ctx, k8sClient, rpcURL, headers, auth, namespace, agentName, recorder, obj)
 	if err != nil {
 		return "", err
 	}
@@ -96,10 +100,10 @@ func ExecuteA2AAgentWithRecorder(ctx context.Context, k8sClient client.Client, a
 }
 
 // createA2AClientForExecution creates and configures A2A client for agent execution
-func createA2AClientForExecution(ctx context.Context, k8sClient client.Client, rpcURL string, headers []arkv1prealpha1.Header, namespace, agentName string, recorder record.EventRecorder, obj client.Object) (*a2aclient.A2AClient, error) {
+func createA2AClientForExecution(ctx context.Context, k8sClient client.Client, rpcURL string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace, agentName string, recorder record.EventRecorder, obj client.Object) (*a2aclient.A2AClient, error) {
 	var clientOptions []a2aclient.Option
-	if len(headers) > 0 {
-		resolvedHeaders, err := resolveA2AHeaders(ctx, k8sClient, headers, namespace)
+	if len(headers) > 0 || auth != nil {
+		resolvedHeaders, err := resolveA2ARequestHeaders(ctx, k8sClient, headers, auth, namespace)
 		if err != nil {
 			if recorder != nil && obj != nil {
 				recorder.Event(obj, corev1.EventTypeWarning, "A2AHeaderResolutionFailed", fmt.Sprintf("Failed to resolve headers for agent %s: %v", agentName, err))
@@ -146,7 +150,7 @@ func executeA2AAgentMessage(ctx context.Context, a2aClient *a2aclient.A2AClient,
 	result, err := a2aClient.SendMessage(ctx, params)
 	if err != nil {
 		if recorder != nil && obj != nil {
-			recorder.Event(obj, corev1.EventTypeWarning, "A2AExecutionFailed", fmt.Sprintf("A2A agent %s execution failed at %s: %v", agentName, rpcURL, err))
+			recorder.Event(obj, corev1.EventTypeWarning, ReasonA2AExecutionFailed, fmt.Sprintf("A2A agent %s execution failed at %s: %v", agentName, rpcURL, err))
 		}
 		return "", fmt.Errorf("A2A server call failed: %w", err)
 	}
@@ -160,7 +164,7 @@ func executeA2AAgentMessage(ctx context.Context, a2aClient *a2aclient.A2AClient,
 	}
 
 	if recorder != nil && obj != nil {
-		recorder.Event(obj, corev1.EventTypeNormal, "A2AExecutionSuccess", fmt.Sprintf("Successfully executed agent %s, response length: %d characters", agentName, len(response)))
+		recorder.Event(obj, corev1.EventTypeNormal, ReasonA2AExecutionSuccess, fmt.Sprintf("Successfully executed agent %s, response length: %d characters", agentName, len(response)))
 	}
 
 	return response, nil
@@ -256,12 +260,12 @@ func extractTextFromParts(parts []protocol.Part) string {
 }
 
 // validateA2AClient validates A2A client creation
-func validateA2AClient(address string, headers []arkv1prealpha1.Header, ctx context.Context, k8sClient client.Client, namespace string, recorder record.EventRecorder, obj client.Object) error {
+func validateA2AClient(address string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, ctx context.Context, k8sClient client.Client, namespace string, recorder record.EventRecorder, obj client.Object) error {
 	var clientOptions []a2aclient.Option
 	clientOptions = append(clientOptions, a2aclient.WithTimeout(30*time.Second))
 
-	if len(headers) > 0 {
-		resolvedHeaders, err := resolveA2AHeaders(ctx, k8sClient, headers, namespace)
+	if len(headers) > 0 || auth != nil {
+		resolvedHeaders, err := resolveA2ARequestHeaders(ctx, k8sClient, headers, auth, namespace)
 		if err != nil {
 			return err
 		}
@@ -281,7 +285,7 @@ func validateA2AClient(address string, headers []arkv1prealpha1.Header, ctx cont
 }
 
 // createA2ARequest creates and configures HTTP request for A2A discovery
-func createA2ARequest(ctx context.Context, agentCardURL string, headers []arkv1prealpha1.Header, k8sClient client.Client, namespace string, recorder record.EventRecorder, obj client.Object) (*http.Request, error) {
+func createA2ARequest(ctx context.Context, agentCardURL string, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, k8sClient client.Client, namespace string, recorder record.EventRecorder, obj client.Object) (*http.Request, error) {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, agentCardURL, nil)
 	if err != nil {
 		if recorder != nil && obj != nil {
@@ -291,8 +295,8 @@ func createA2ARequest(ctx context.Context, agentCardURL string, headers []arkv1p
 	}
 
 	// Add resolved headers if specified
-	if len(headers) > 0 {
-		resolvedHeaders, err := resolveA2AHeaders(ctx, k8sClient, headers, namespace)
+	if len(headers) > 0 || auth != nil {
+		resolvedHeaders, err := resolveA2ARequestHeaders(ctx, k8sClient, headers, auth, namespace)
 		if err != nil {
 			if recorder != nil && obj != nil {
 				recorder.Event(obj, corev1.EventTypeWarning, "A2AHeaderResolutionFailed", fmt.Sprintf("Failed to resolve A2A headers: %v", err))
@@ -315,7 +319,7 @@ func createA2ARequest(ctx context.Context, agentCardURL string, headers []arkv1p
 }
 
 // executeA2ARequest executes HTTP request and parses agent card response
-func executeA2ARequest(ctx context.Context, req *http.Request, address string, recorder record.EventRecorder, obj client.Object) (*A2AAgentCard, error) {
+func executeA2ARequest(ctx context.Context, req *http.Request, address string, recorder record.EventRecorder, obj client.Object) ([]*A2AAgentCard, error) {
 	httpClient := &http.Client{Timeout: 30 * time.Second}
 	resp, err := httpClient.Do(req)
 	if err != nil {
@@ -337,19 +341,123 @@ func executeA2ARequest(ctx context.Context, req *http.Request, address string, r
 		return nil, fmt.Errorf("A2A server returned status %d", resp.StatusCode)
 	}
 
-	var agentCard A2AAgentCard
-	if err := json.NewDecoder(resp.Body).Decode(&agentCard); err != nil {
-		if recorder != nil && obj != nil {
-			recorder.Event(obj, corev1.EventTypeWarning, "A2AParseError", fmt.Sprintf("Failed to parse agent card from %s: %v", address, err))
+	body, err := io.ReadAll(resp.Body)
+	if err == nil {
+		var agentCards []*A2AAgentCard
+		agentCards, err = parseA2AAgentCards(body)
+		if err == nil {
+			if recorder != nil && obj != nil {
+				for _, agentCard := range agentCards {
+					recorder.Event(obj, corev1.EventTypeNormal, "A2ADiscoverySuccess", fmt.Sprintf("Successfully discovered agent %s from %s", agentCard.Name, address))
+				}
+			}
+			return agentCards, nil
 		}
-		return nil, fmt.Errorf("failed to parse agent card: %w", err)
 	}
 
 	if recorder != nil && obj != nil {
-		recorder.Event(obj, corev1.EventTypeNormal, "A2ADiscoverySuccess", fmt.Sprintf("Successfully discovered agent %s from %s", agentCard.Name, address))
+		recorder.Event(obj, corev1.EventTypeWarning, "A2AParseError", fmt.Sprintf("Failed to parse agent card from %s: %v", address, err))
 	}
+	return nil, fmt.Errorf("failed to parse agent card: %w", err)
+}
 
-	return &agentCard, nil
+// a2aAgentCardWithSubAgents is an agent card that lists the sub-agents hosted by the same server
+type a2aAgentCardWithSubAgents struct {
+	Agents []*A2AAgentCard `json:"agents"`
+}
+
+// parseA2AAgentCards parses a single agent card, an array of agent cards, or a card listing sub-agents
+func parseA2AAgentCards(data []byte) ([]*A2AAgentCard, error) {
+	data = bytes.TrimSpace(data)
+
+	if len(data) > 0 && data[0] == '[' {
+		var parsed []*A2AAgentCard
+		if err := json.Unmarshal(data, &parsed); err != nil {
+			return nil, err
+		}
+		agentCards := nonNilAgentCards(parsed)
+		if len(agentCards) == 0 {
+			return nil, fmt.Errorf("agent card list is empty")
+		}
+		return agentCards, nil
+	}
+
+	var withSubAgents a2aAgentCardWithSubAgents
+	if err := json.Unmarshal(data, &withSubAgents); err == nil {
+		if agentCards := nonNilAgentCards(withSubAgents.Agents); len(agentCards) > 0 {
+			return agentCards, nil
+		}
+	}
+
+	var agentCard A2AAgentCard
+	if err := json.Unmarshal(data, &agentCard); err != nil {
+		return nil, err
+	}
+	return []*A2AAgentCard{&agentCard}, nil
+}
+
+// nonNilAgentCards drops the null entries of a list of agent cards
+func nonNilAgentCards(parsed []*A2AAgentCard) []*A2AAgentCard {
+	var agentCards []*A2AAgentCard
+	for _, agentCard := range parsed {
+		if agentCard != nil {
+			agentCards = append(agentCards, agentCard)
+		}
+	}
+	return agentCards
+}
+
+// A2AAgentAddress returns the address used to execute a discovered agent. When a server exposes
+// multiple agents, each agent is reached through the URL in its own card if that URL has the scheme,
+// host and port of the server address. Cards cannot direct calls, and the headers sent with them, to
+// another host.
+func A2AAgentAddress(serverAddress string, agentCard *A2AAgentCard, agentCount int) string {
+	if agentCount > 1 && agentCard.URL != "" && sameURLOrigin(agentCard.URL, serverAddress) {
+		return agentCard.URL
+	}
+	return serverAddress
+}
+
+// sameURLOrigin reports whether two URLs have the same scheme, host and port
+func sameURLOrigin(a, b string) bool {
+	urlA, err := url.Parse(a)
+	if err != nil {
+		return false
+	}
+	urlB, err := url.Parse(b)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(urlA.Scheme, urlB.Scheme) &&
+		strings.EqualFold(urlA.Hostname(), urlB.Hostname()) &&
+		urlPort(urlA) == urlPort(urlB)
+}
+
+func urlPort(u *url.URL) string {
+	if port := u.Port(); port != "" {
+		return port
+	}
+	if strings.EqualFold(u.Scheme, "https") {
+		return "443"
+	}
+	return "80"
+}
+
+// resolveA2ARequestHeaders resolves static headers and adds the Authorization header from auth, if configured
+func resolveA2ARequestHeaders(ctx context.Context, k8sClient client.Client, headers []arkv1prealpha1.Header, auth *arkv1prealpha1.A2AServerAuth, namespace string) (map[string]string, error) {
+	resolvedHeaders, err := resolveA2AHeaders(ctx, k8sClient, headers, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	authHeaders, err := resolveA2AAuthHeaders(ctx, k8sClient, auth, namespace)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range authHeaders {
+		resolvedHeaders[name] = value
+	}
+	return resolvedHeaders, nil
 }
 
 // resolveA2AHeaders resolves header values from ValueSources
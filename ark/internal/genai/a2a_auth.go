@@ -0,0 +1,142 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+// maxA2ATokens bounds the number of cached A2A tokens. The cache is cleared when it is full.
+const maxA2ATokens = 256
+
+// a2aTokens caches the OAuth2 token of each A2A server credential, so tokens are reused across
+// discovery and execution calls and only requested again when they expire. Entries are keyed by the
+// references of the credential and hold the version of the Secrets and ConfigMaps it was resolved
+// from, so a rotated credential replaces its entry.
+var a2aTokens = struct {
+	sync.Mutex
+	entries map[string]a2aToken
+}{entries: map[string]a2aToken{}}
+
+type a2aToken struct {
+	version string
+	token   *oauth2.Token
+}
+
+// resolveA2AAuthHeaders returns the Authorization header for the configured A2A server auth
+func resolveA2AAuthHeaders(ctx context.Context, k8sClient client.Client, auth *arkv1prealpha1.A2AServerAuth, namespace string) (map[string]string, error) {
+	if auth == nil || auth.OAuth2 == nil {
+		return nil, nil
+	}
+
+	token, err := getA2AToken(ctx, k8sClient, auth.OAuth2, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]string{"Authorization": token.Type() + " " + token.AccessToken}, nil
+}
+
+func getA2AToken(ctx context.Context, k8sClient client.Client, oauth2Config *arkv1prealpha1.OAuth2ClientCredentials, namespace string) (*oauth2.Token, error) {
+	resolver := common.NewValueSourceResolverV1PreAlpha1(k8sClient)
+
+	clientID, err := resolver.ResolveValueSource(ctx, oauth2Config.ClientID, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve OAuth2 client ID: %w", err)
+	}
+	clientSecret, err := resolver.ResolveValueSource(ctx, oauth2Config.ClientSecret, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve OAuth2 client secret: %w", err)
+	}
+
+	key := strings.Join([]string{
+		namespace, oauth2Config.TokenURL, strings.Join(oauth2Config.Scopes, " "),
+		valueSourceReference(oauth2Config.ClientID), valueSourceReference(oauth2Config.ClientSecret),
+	}, "\x00")
+	version, err := a2aCredentialVersion(ctx, k8sClient, namespace, oauth2Config.ClientID, oauth2Config.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	a2aTokens.Lock()
+	cached, ok := a2aTokens.entries[key]
+	a2aTokens.Unlock()
+	var current *oauth2.Token
+	if ok && cached.version == version {
+		current = cached.token
+	}
+
+	config := &clientcredentials.Config{
+		ClientID:     clientID,
+		ClientSecret: clientSecret,
+		TokenURL:     oauth2Config.TokenURL,
+		Scopes:       oauth2Config.Scopes,
+	}
+	token, err := oauth2.ReuseTokenSource(current, config.TokenSource(ctx)).Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain OAuth2 token from %s: %w", oauth2Config.TokenURL, err)
+	}
+
+	a2aTokens.Lock()
+	defer a2aTokens.Unlock()
+	if _, exists := a2aTokens.entries[key]; !exists && len(a2aTokens.entries) >= maxA2ATokens {
+		clear(a2aTokens.entries)
+	}
+	a2aTokens.entries[key] = a2aToken{version: version, token: token}
+	return token, nil
+}
+
+// valueSourceReference identifies where a value is read from, without the value itself
+func valueSourceReference(source arkv1prealpha1.ValueSource) string {
+	switch {
+	case source.ValueFrom == nil:
+		return "value"
+	case source.ValueFrom.SecretKeyRef != nil:
+		return "secret/" + source.ValueFrom.SecretKeyRef.Name + "/" + source.ValueFrom.SecretKeyRef.Key
+	case source.ValueFrom.ConfigMapKeyRef != nil:
+		return "configmap/" + source.ValueFrom.ConfigMapKeyRef.Name + "/" + source.ValueFrom.ConfigMapKeyRef.Key
+	case source.ValueFrom.ServiceRef != nil:
+		return "service/" + source.ValueFrom.ServiceRef.Namespace + "/" + source.ValueFrom.ServiceRef.Name
+	default:
+		return ""
+	}
+}
+
+// a2aCredentialVersion returns the UID and resource version of the Secrets and ConfigMaps the
+// credential values are read from, and a hash of the values set inline
+func a2aCredentialVersion(ctx context.Context, k8sClient client.Client, namespace string, sources ...arkv1prealpha1.ValueSource) (string, error) {
+	hash := sha256.New()
+	for _, source := range sources {
+		var object client.Object
+		var name string
+		switch {
+		case source.ValueFrom == nil:
+			hash.Write([]byte(source.Value))
+		case source.ValueFrom.SecretKeyRef != nil:
+			object, name = &corev1.Secret{}, source.ValueFrom.SecretKeyRef.Name
+		case source.ValueFrom.ConfigMapKeyRef != nil:
+			object, name = &corev1.ConfigMap{}, source.ValueFrom.ConfigMapKeyRef.Name
+		}
+		if object != nil {
+			if err := k8sClient.Get(ctx, client.ObjectKey{Name: name, Namespace: namespace}, object); err != nil {
+				return "", fmt.Errorf("failed to get OAuth2 credential %s: %w", name, err)
+			}
+			hash.Write([]byte(string(object.GetUID()) + "/" + object.GetResourceVersion()))
+		}
+		hash.Write([]byte{0})
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
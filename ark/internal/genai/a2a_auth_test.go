@@ -0,0 +1,101 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+)
+
+func TestResolveA2AAuthHeaders(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	auth := &arkv1prealpha1.A2AServerAuth{
+		OAuth2: &arkv1prealpha1.OAuth2ClientCredentials{
+			TokenURL:     tokenServer.URL,
+			ClientID:     arkv1prealpha1.ValueSource{Value: "client"},
+			ClientSecret: arkv1prealpha1.ValueSource{Value: "secret"},
+			Scopes:       []string{"a2a"},
+		},
+	}
+
+	headers, err := resolveA2AAuthHeaders(context.Background(), nil, auth, "default")
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer test-token", headers["Authorization"])
+
+	// Cached token is reused until it expires
+	_, err = resolveA2AAuthHeaders(context.Background(), nil, auth, "default")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), tokenRequests.Load())
+
+	// Changed credentials request a new token
+	auth.OAuth2.ClientSecret = arkv1prealpha1.ValueSource{Value: "rotated"}
+	_, err = resolveA2AAuthHeaders(context.Background(), nil, auth, "default")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), tokenRequests.Load())
+}
+
+func TestResolveA2AAuthHeadersRotatedSecret(t *testing.T) {
+	var tokenRequests atomic.Int32
+	tokenServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"test-token","token_type":"Bearer","expires_in":3600}`))
+	}))
+	defer tokenServer.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "a2a-credentials", Namespace: "default", UID: "secret-uid"},
+		Data:       map[string][]byte{"secret": []byte("secret")},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(secret).Build()
+	auth := &arkv1prealpha1.A2AServerAuth{
+		OAuth2: &arkv1prealpha1.OAuth2ClientCredentials{
+			TokenURL: tokenServer.URL,
+			ClientID: arkv1prealpha1.ValueSource{Value: "rotating-client"},
+			ClientSecret: arkv1prealpha1.ValueSource{ValueFrom: &arkv1prealpha1.ValueFromSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "a2a-credentials"}, Key: "secret"},
+			}},
+		},
+	}
+
+	ctx := context.Background()
+	_, err := resolveA2AAuthHeaders(ctx, k8sClient, auth, "default")
+	require.NoError(t, err)
+	_, err = resolveA2AAuthHeaders(ctx, k8sClient, auth, "default")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), tokenRequests.Load())
+
+	// A new version of the Secret requests a new token
+	secret.Data["secret"] = []byte("rotated")
+	require.NoError(t, k8sClient.Update(ctx, secret))
+	_, err = resolveA2AAuthHeaders(ctx, k8sClient, auth, "default")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), tokenRequests.Load())
+}
+
+func TestResolveA2AAuthHeadersWithoutAuth(t *testing.T) {
+	headers, err := resolveA2AAuthHeaders(context.Background(), nil, nil, "default")
+	require.NoError(t, err)
+	assert.Empty(t, headers)
+}
@@ -34,7 +34,7 @@ func (e *A2AExecutionEngine) Execute(ctx context.Context, agentName, namespace s
 	log := logf.FromContext(ctx)
 	log.Info("executing A2A agent", "agent", agentName)
 
-	a2aTracker := NewOperationTracker(e.recorder, ctx, "A2ACall", agentName, map[string]string{
+	a2aTracker := NewOperationTracker(e.recorder, ctx, OperationA2ACall, agentName, map[string]string{
 		"a2aServer":  annotations[arkann.A2AServerName],
 		"serverAddr": annotations[arkann.A2AServerAddress],
 		"queryId":    getQueryID(ctx),
@@ -68,10 +68,10 @@ func (e *A2AExecutionEngine) Execute(ctx context.Context, agentName, namespace s
 	}
 
 	// Execute A2A agent with event recording
-	response, err := ExecuteA2AAgentWithRecorder(ctx, e.client, a2aAddress, a2aServer.Spec.Headers, namespace, content, agentName, nil, &a2aServer)
+	response, err := ExecuteA2AAgentWithRecorder(ctx, e.client, a2aAddress, a2aServer.Spec.Headers, a2aServer.Spec.Auth, namespace, content, agentName, nil, &a2aServer)
 	if err != nil {
 		a2aTracker.Fail(err)
-		e.recorder.EmitEvent(ctx, "Warning", "A2AExecutionFailed", BaseEvent{
+		e.recorder.EmitEvent(ctx, "Warning", ReasonA2AExecutionFailed, BaseEvent{
 			Name: "A2AAgentExecutionFailed",
 			Metadata: map[string]string{
 				"agent":     agentName,
@@ -87,7 +87,7 @@ func (e *A2AExecutionEngine) Execute(ctx context.Context, agentName, namespace s
 	log.Info("A2A agent execution completed", "agent", agentName, "response_length", len(response))
 
 	// Emit success event
-	e.recorder.EmitEvent(ctx, "Normal", "A2AExecutionSuccess", BaseEvent{
+	e.recorder.EmitEvent(ctx, "Normal", ReasonA2AExecutionSuccess, BaseEvent{
 		Name: "A2AAgentExecutionCompleted",
 		Metadata: map[string]string{
 			"agent":          agentName,
@@ -0,0 +1,113 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+)
+
+// RecordA2AAgentCard records the protocol version, capabilities and auth schemes of a discovered
+// agent card in the A2AServer status. It returns whether the status changed.
+func RecordA2AAgentCard(status *arkv1prealpha1.A2AServerStatus, agentCard *A2AAgentCard) bool {
+	protocolVersion := ""
+	if agentCard.ProtocolVersion != nil {
+		protocolVersion = *agentCard.ProtocolVersion
+	}
+	capabilities := &arkv1prealpha1.A2AServerCapabilities{
+		Streaming:         agentCard.Capabilities.Streaming != nil && *agentCard.Capabilities.Streaming,
+		PushNotifications: agentCard.Capabilities.PushNotifications != nil && *agentCard.Capabilities.PushNotifications,
+	}
+	var authSchemes []string
+	for _, scheme := range agentCard.SecuritySchemes {
+		if scheme.Type != "" && !slices.Contains(authSchemes, string(scheme.Type)) {
+			authSchemes = append(authSchemes, string(scheme.Type))
+		}
+	}
+	sort.Strings(authSchemes)
+
+	changed := status.ProtocolVersion != protocolVersion ||
+		status.Capabilities == nil || *status.Capabilities != *capabilities ||
+		!slices.Equal(status.AuthSchemes, authSchemes)
+	status.ProtocolVersion = protocolVersion
+	status.Capabilities = capabilities
+	status.AuthSchemes = authSchemes
+	return changed
+}
+
+// CheckA2ARequirements checks the agent card recorded in the A2AServer status against the
+// requirements, returning an error describing everything the server lacks
+func CheckA2ARequirements(requirements *arkv1alpha1.A2ARequirements, status *arkv1prealpha1.A2AServerStatus) error {
+	if requirements == nil {
+		return nil
+	}
+
+	var unmet []string
+	if requirements.MinProtocolVersion != "" {
+		switch {
+		case status.ProtocolVersion == "":
+			unmet = append(unmet, fmt.Sprintf("protocol version %s required but the agent card declares none", requirements.MinProtocolVersion))
+		case compareProtocolVersions(status.ProtocolVersion, requirements.MinProtocolVersion) < 0:
+			unmet = append(unmet, fmt.Sprintf("protocol version %s is below %s", status.ProtocolVersion, requirements.MinProtocolVersion))
+		}
+	}
+
+	var missing []string
+	for _, capability := range requirements.Capabilities {
+		if !hasA2ACapability(status.Capabilities, capability) {
+			missing = append(missing, string(capability))
+		}
+	}
+	if len(missing) > 0 {
+		unmet = append(unmet, "missing capabilities: "+strings.Join(missing, ", "))
+	}
+
+	if len(unmet) > 0 {
+		return fmt.Errorf("A2A server does not meet requirements: %s", strings.Join(unmet, "; "))
+	}
+	return nil
+}
+
+func hasA2ACapability(capabilities *arkv1prealpha1.A2AServerCapabilities, capability arkv1alpha1.A2ACapability) bool {
+	if capabilities == nil {
+		return false
+	}
+	switch capability {
+	case arkv1alpha1.A2ACapabilityStreaming:
+		return capabilities.Streaming
+	case arkv1alpha1.A2ACapabilityPushNotifications:
+		return capabilities.PushNotifications
+	default:
+		return false
+	}
+}
+
+// compareProtocolVersions compares dotted versions numerically, treating missing or non-numeric
+// parts as 0, e.g. 0.3 equals 0.3.0
+func compareProtocolVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+	for i := 0; i < max(len(aParts), len(bParts)); i++ {
+		if diff := versionPart(aParts, i) - versionPart(bParts, i); diff != 0 {
+			if diff < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionPart(parts []string, i int) int {
+	if i >= len(parts) {
+		return 0
+	}
+	n, _ := strconv.Atoi(parts[i])
+	return n
+}
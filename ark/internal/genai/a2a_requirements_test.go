@@ -0,0 +1,83 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"trpc.group/trpc-go/trpc-a2a-go/server"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+)
+
+func TestRecordA2AAgentCard(t *testing.T) {
+	version := "0.3.0"
+	streaming := true
+	agentCard := &A2AAgentCard{
+		Name:            "weather",
+		ProtocolVersion: &version,
+		Capabilities:    server.AgentCapabilities{Streaming: &streaming},
+		SecuritySchemes: map[string]server.SecurityScheme{
+			"bearer":  {Type: server.SecuritySchemeTypeHTTP},
+			"partner": {Type: server.SecuritySchemeTypeOAuth2},
+			"service": {Type: server.SecuritySchemeTypeOAuth2},
+		},
+	}
+
+	var status arkv1prealpha1.A2AServerStatus
+	assert.True(t, RecordA2AAgentCard(&status, agentCard))
+	assert.Equal(t, "0.3.0", status.ProtocolVersion)
+	assert.Equal(t, &arkv1prealpha1.A2AServerCapabilities{Streaming: true}, status.Capabilities)
+	assert.Equal(t, []string{"http", "oauth2"}, status.AuthSchemes)
+	assert.False(t, RecordA2AAgentCard(&status, agentCard), "an unchanged card leaves the status unchanged")
+}
+
+func TestCheckA2ARequirements(t *testing.T) {
+	status := &arkv1prealpha1.A2AServerStatus{
+		ProtocolVersion: "0.2.5",
+		Capabilities:    &arkv1prealpha1.A2AServerCapabilities{Streaming: true},
+	}
+
+	tests := []struct {
+		name         string
+		requirements *arkv1alpha1.A2ARequirements
+		status       *arkv1prealpha1.A2AServerStatus
+		err          string
+	}{
+		{name: "no requirements", status: status},
+		{name: "met", requirements: &arkv1alpha1.A2ARequirements{MinProtocolVersion: "0.2", Capabilities: []arkv1alpha1.A2ACapability{arkv1alpha1.A2ACapabilityStreaming}}, status: status},
+		{
+			name: "version too old and capability missing",
+			requirements: &arkv1alpha1.A2ARequirements{
+				MinProtocolVersion: "0.3.0",
+				Capabilities:       []arkv1alpha1.A2ACapability{arkv1alpha1.A2ACapabilityStreaming, arkv1alpha1.A2ACapabilityPushNotifications},
+			},
+			status: status,
+			err:    "A2A server does not meet requirements: protocol version 0.2.5 is below 0.3.0; missing capabilities: pushNotifications",
+		},
+		{
+			name:         "nothing discovered yet",
+			requirements: &arkv1alpha1.A2ARequirements{MinProtocolVersion: "0.2.0", Capabilities: []arkv1alpha1.A2ACapability{arkv1alpha1.A2ACapabilityStreaming}},
+			status:       &arkv1prealpha1.A2AServerStatus{},
+			err:          "protocol version 0.2.0 required but the agent card declares none; missing capabilities: streaming",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckA2ARequirements(tt.requirements, tt.status)
+			if tt.err == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestCompareProtocolVersions(t *testing.T) {
+	assert.Equal(t, 0, compareProtocolVersions("0.3", "0.3.0"))
+	assert.Equal(t, -1, compareProtocolVersions("0.2.5", "0.3.0"))
+	assert.Equal(t, 1, compareProtocolVersions("0.10.0", "0.9.1"))
+}
@@ -249,3 +249,92 @@ func TestExtractTextFromParts(t *testing.T) {
 		})
 	}
 }
+
+func TestParseA2AAgentCards(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		expectedNames []string
+		expectError   bool
+	}{
+		{
+			name:          "single agent card",
+			data:          `{"name": "weather", "description": "Weather agent"}`,
+			expectedNames: []string{"weather"},
+		},
+		{
+			name:          "array of agent cards",
+			data:          `[{"name": "weather"}, {"name": "news"}]`,
+			expectedNames: []string{"weather", "news"},
+		},
+		{
+			name:          "card listing sub-agents",
+			data:          `{"name": "gateway", "agents": [{"name": "weather"}, {"name": "news"}]}`,
+			expectedNames: []string{"weather", "news"},
+		},
+		{
+			name:          "null entries",
+			data:          `[null, {"name": "weather"}]`,
+			expectedNames: []string{"weather"},
+		},
+		{
+			name:          "null sub-agents",
+			data:          `{"name": "gateway", "agents": [null, {"name": "news"}]}`,
+			expectedNames: []string{"news"},
+		},
+		{
+			name:        "empty array",
+			data:        `[]`,
+			expectError: true,
+		},
+		{
+			name:        "only null entries",
+			data:        `[null]`,
+			expectError: true,
+		},
+		{
+			name:        "invalid json",
+			data:        `{"name":`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			agentCards, err := parseA2AAgentCards([]byte(tt.data))
+			if tt.expectError {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			names := make([]string, 0, len(agentCards))
+			for _, agentCard := range agentCards {
+				names = append(names, agentCard.Name)
+			}
+			assert.Equal(t, tt.expectedNames, names)
+		})
+	}
+}
+
+func TestA2AAgentAddress(t *testing.T) {
+	const serverAddress = "http://gateway.default.svc:8080/a2a"
+	tests := []struct {
+		name       string
+		cardURL    string
+		agentCount int
+		expected   string
+	}{
+		{"single agent uses the server address", "http://gateway.default.svc:8080/a2a/weather", 1, serverAddress},
+		{"card on the server", "http://gateway.default.svc:8080/a2a/weather", 2, "http://gateway.default.svc:8080/a2a/weather"},
+		{"card without url", "", 2, serverAddress},
+		{"card on another host", "http://attacker.example.com:8080/a2a/weather", 2, serverAddress},
+		{"card on another port", "http://gateway.default.svc:9090/a2a/weather", 2, serverAddress},
+		{"card with another scheme", "https://gateway.default.svc:8080/a2a/weather", 2, serverAddress},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, A2AAgentAddress(serverAddress, &A2AAgentCard{URL: tt.cardURL}, tt.agentCount))
+		})
+	}
+}
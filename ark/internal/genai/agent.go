@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"time"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/packages/param"
@@ -15,6 +16,7 @@ import (
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
 	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/metrics"
 )
 
 type Agent struct {
@@ -28,9 +30,18 @@ type Agent struct {
 	Recorder        EventEmitter
 	AgentRecorder   telemetry.AgentRecorder
 	ExecutionEngine *arkv1alpha1.ExecutionEngineRef
+	Labels          map[string]string
 	Annotations     map[string]string
 	OutputSchema    *runtime.RawExtension
-	client          client.Client
+	ModelParameters *arkv1alpha1.ModelParameters
+	// MaxToolIterations is the number of rounds of tool calls after which an execution fails
+	MaxToolIterations int32
+	DataPolicy        arkv1alpha1.DataPolicy
+	Guardrails        []*Guardrail
+	PostProcessors    *PostProcessors
+	MemoryPolicy      arkv1alpha1.MemoryPolicy
+	client            client.Client
+	modelRecorder     telemetry.ModelRecorder
 }
 
 // FullName returns the namespace/name format for the agent
@@ -45,7 +56,7 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 		modelName = a.Model.Model
 	}
 
-	agentTracker := NewOperationTracker(a.Recorder, ctx, "AgentExecution", a.FullName(), map[string]string{
+	agentTracker := NewOperationTracker(a.Recorder, ctx, OperationAgentExecution, a.FullName(), map[string]string{
 		"model":     modelName,
 		"queryId":   getQueryID(ctx),
 		"sessionId": getSessionID(ctx),
@@ -54,9 +65,23 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 	})
 	defer agentTracker.Complete("")
 
+	// Redact this agent's traces when it requires it and the query does not already redact
+	if a.DataPolicy == arkv1alpha1.DataPolicyRedactPII && PIIRedactorFromContext(ctx) == nil {
+		redactor, err := NewPIIRedactor(ctx, a.client, a.Namespace, a.modelRecorder)
+		if err != nil {
+			return nil, fmt.Errorf("agent %s PII redaction setup failed: %w", a.FullName(), err)
+		}
+		ctx = WithPIIRedaction(ctx, redactor)
+	}
+
 	ctx, span := a.AgentRecorder.StartAgentExecution(ctx, a.Name, a.Namespace)
 	defer span.End()
 
+	if err := RunGuardrails(ctx, a.Guardrails, arkv1alpha1.GuardrailStageInput, MessageText(userInput), a.Recorder); err != nil {
+		a.AgentRecorder.RecordError(span, err)
+		return nil, err
+	}
+
 	var messages []Message
 	var err error
 
@@ -78,6 +103,13 @@ func (a *Agent) Execute(ctx context.Context, userInput Message, history []Messag
 		messages, err = a.executeLocally(ctx, userInput, history, memory, eventStream)
 	}
 
+	if err == nil && len(messages) > 0 {
+		err = RunGuardrails(ctx, a.Guardrails, arkv1alpha1.GuardrailStageOutput, MessageText(messages[len(messages)-1]), a.Recorder)
+	}
+	if err == nil && a.PostProcessors != nil {
+		messages, err = a.PostProcessors.Run(ctx, messages)
+	}
+
 	if err != nil {
 		a.AgentRecorder.RecordError(span, err)
 		return messages, err
@@ -99,11 +131,13 @@ func (a *Agent) executeWithExecutionEngine(ctx context.Context, userInput Messag
 	if err != nil {
 		return nil, fmt.Errorf("agent %s prompt resolution failed: %w", a.FullName(), err)
 	}
-	agentConfig.Prompt = resolvedPrompt
+	instructions := QueryInstructionsFromContext(ctx)
+	agentConfig.Prompt = instructions.Prompt(resolvedPrompt)
+	agentConfig.ModelParameters = MergeModelParameters(a.ModelParameters, queryModelParameters(ctx))
 
 	toolDefinitions := buildToolDefinitions(a.Tools)
 
-	return engineClient.Execute(ctx, a.ExecutionEngine, agentConfig, userInput, history, toolDefinitions, a.Recorder)
+	return engineClient.Execute(ctx, a.ExecutionEngine, agentConfig, userInput, instructions.History(history), toolDefinitions, a.Recorder)
 }
 
 func (a *Agent) executeWithA2AExecutionEngine(ctx context.Context, userInput Message, eventStream EventStreamInterface) ([]Message, error) {
@@ -117,15 +151,16 @@ func (a *Agent) prepareMessages(ctx context.Context, userInput Message, history
 		return nil, fmt.Errorf("agent %s prompt resolution failed: %w", a.FullName(), err)
 	}
 
-	systemMessage := NewSystemMessage(resolvedPrompt)
-	agentMessages := append([]Message{systemMessage}, history...)
+	instructions := QueryInstructionsFromContext(ctx)
+	systemMessage := NewSystemMessage(instructions.Prompt(resolvedPrompt))
+	agentMessages := append([]Message{systemMessage}, instructions.History(history)...)
 	agentMessages = append(agentMessages, userInput)
 	return agentMessages, nil
 }
 
 // executeModelCall executes a single model call with optional streaming support.
-func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, tools []openai.ChatCompletionToolParam, eventStream EventStreamInterface) (*openai.ChatCompletion, error) {
-	llmTracker := NewOperationTracker(a.Recorder, ctx, "LLMCall", a.Model.Model, map[string]string{
+func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, tools []openai.ChatCompletionToolParam, parameters *arkv1alpha1.ModelParameters, eventStream EventStreamInterface) (*openai.ChatCompletion, error) {
+	llmTracker := NewOperationTracker(a.Recorder, ctx, OperationLLMCall, a.Model.Model, map[string]string{
 		"agent": a.FullName(),
 		"model": a.Model.Model,
 	})
@@ -134,6 +169,7 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 	a.Model.OutputSchema = a.OutputSchema
 	// Truncate schema name to 64 chars for OpenAI API compatibility - name is purely an identifier
 	a.Model.SchemaName = fmt.Sprintf("%.64s", fmt.Sprintf("namespace-%s-agent-%s", a.Namespace, a.Name))
+	a.Model.Parameters = parameters
 
 	response, err := a.Model.ChatCompletion(ctx, agentMessages, eventStream, 1, tools)
 	if err != nil {
@@ -141,12 +177,7 @@ func (a *Agent) executeModelCall(ctx context.Context, agentMessages []Message, t
 		return nil, fmt.Errorf("agent %s execution failed: %w", a.FullName(), err)
 	}
 
-	tokenUsage := TokenUsage{
-		PromptTokens:     response.Usage.PromptTokens,
-		CompletionTokens: response.Usage.CompletionTokens,
-		TotalTokens:      response.Usage.TotalTokens,
-	}
-	llmTracker.CompleteWithTokens(tokenUsage)
+	llmTracker.CompleteWithTokens(TokenUsageFromCompletion(response.Usage))
 
 	if len(response.Choices) == 0 {
 		return nil, fmt.Errorf("agent %s received empty response", a.FullName())
@@ -171,7 +202,7 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 		params = map[string]interface{}{"_raw": toolCall.Function.Arguments}
 	}
 
-	toolTracker := NewOperationTracker(a.Recorder, ctx, "ToolCall", toolCall.Function.Name, map[string]string{
+	toolTracker := NewOperationTracker(a.Recorder, ctx, OperationToolCall, toolCall.Function.Name, map[string]string{
 		"toolId":     toolCall.ID,
 		"toolName":   toolCall.Function.Name,
 		"agentName":  a.FullName(),
@@ -182,7 +213,9 @@ func (a *Agent) executeToolCall(ctx context.Context, toolCall openai.ChatComplet
 		"toolType":   a.Tools.GetToolType(toolCall.Function.Name),
 	})
 
+	toolStart := time.Now()
 	result, err := a.Tools.ExecuteTool(ctx, ToolCall(toolCall), a.Recorder)
+	metrics.ObserveToolCall(toolCall.Function.Name, a.Namespace, time.Since(toolStart), err)
 	toolMessage := ToolMessage(result.Content, result.ID)
 
 	if err != nil {
@@ -226,19 +259,28 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 		tools = a.Tools.ToOpenAITools()
 	}
 
+	parameters := MergeModelParameters(a.ModelParameters, queryModelParameters(ctx))
+	if err := a.checkToolChoice(parameters, tools); err != nil {
+		return nil, err
+	}
+
 	agentMessages, err := a.prepareMessages(ctx, userInput, history)
 	if err != nil {
 		return nil, err
 	}
 
 	newMessages := []Message{}
+	maxToolIterations := a.MaxToolIterations
+	if maxToolIterations <= 0 {
+		maxToolIterations = arkv1alpha1.DefaultMaxToolIterations
+	}
 
-	for {
+	for iteration := int32(0); ; iteration++ {
 		if ctx.Err() != nil {
 			return newMessages, ctx.Err()
 		}
 
-		response, err := a.executeModelCall(ctx, agentMessages, tools, eventStream)
+		response, err := a.executeModelCall(ctx, agentMessages, tools, parameters, eventStream)
 		if err != nil {
 			return nil, err
 		}
@@ -252,15 +294,43 @@ func (a *Agent) executeLocally(ctx context.Context, userInput Message, history [
 		if len(choice.Message.ToolCalls) == 0 {
 			return newMessages, nil
 		}
+		if iteration >= maxToolIterations {
+			return newMessages, fmt.Errorf("agent %s exceeded the maximum of %d tool iterations: the model still called tools after them, raise maxToolIterations or check the agent for a loop", a.FullName(), maxToolIterations)
+		}
 
 		if err := a.executeToolCalls(ctx, choice.Message.ToolCalls, &agentMessages, &newMessages); err != nil {
 			logger := logf.FromContext(ctx)
 			logger.Error(err, "Tool execution failed", "agent", a.FullName())
 			return newMessages, err
 		}
+
+		// A forced tool choice applies to the first call only, otherwise the model could never answer
+		if parameters != nil && IsForcedToolChoice(parameters.ToolChoice) {
+			parameters = parameters.DeepCopy()
+			parameters.ToolChoice = arkv1alpha1.ToolChoiceAuto
+		}
 	}
 }
 
+// checkToolChoice returns an error when the tool choice forces a tool the agent does not have
+func (a *Agent) checkToolChoice(parameters *arkv1alpha1.ModelParameters, tools []openai.ChatCompletionToolParam) error {
+	if parameters == nil || !IsForcedToolChoice(parameters.ToolChoice) {
+		return nil
+	}
+	if len(tools) == 0 {
+		return fmt.Errorf("agent %s has tool choice %s but no tools", a.FullName(), parameters.ToolChoice)
+	}
+	if parameters.ToolChoice == arkv1alpha1.ToolChoiceRequired {
+		return nil
+	}
+	for _, tool := range tools {
+		if tool.Function.Name == parameters.ToolChoice {
+			return nil
+		}
+	}
+	return fmt.Errorf("tool choice %s is not a tool of agent %s", parameters.ToolChoice, a.FullName())
+}
+
 func (a *Agent) GetName() string {
 	return a.Name
 }
@@ -302,6 +372,11 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 
 	// A2A agents don't need models - they delegate to external A2A servers
 	if crd.Spec.ExecutionEngine == nil || crd.Spec.ExecutionEngine.Name != ExecutionEngineA2A {
+		if ref := crd.Spec.ModelRef; ref != nil {
+			if err := CheckReferenceGrant(ctx, k8sClient, arkv1alpha1.ReferenceFromAgent, crd.Namespace, arkv1alpha1.TargetKindModel, ref.Name, ref.Namespace); err != nil {
+				return nil, fmt.Errorf("failed to load model for agent %s/%s: %w", crd.Namespace, crd.Name, err)
+			}
+		}
 		var err error
 		resolvedModel, err = LoadModel(ctx, k8sClient, crd.Spec.ModelRef, crd.Namespace, telemetryProvider.ModelRecorder())
 		if err != nil {
@@ -331,20 +406,50 @@ func MakeAgent(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Ag
 	if err := tools.registerTools(ctx, k8sClient, crd, telemetryProvider); err != nil {
 		return nil, err
 	}
+	if err := tools.registerKnowledgeBases(ctx, k8sClient, crd, telemetryProvider); err != nil {
+		return nil, err
+	}
+
+	guardrails, err := LoadGuardrails(ctx, k8sClient, crd.Spec.Guardrails, crd.Namespace, telemetryProvider.ModelRecorder())
+	if err != nil {
+		return nil, fmt.Errorf("failed to load guardrails for agent %s/%s: %w", crd.Namespace, crd.Name, err)
+	}
+
+	postProcessors := &PostProcessors{
+		Specs:     crd.Spec.PostProcessors,
+		Namespace: crd.Namespace,
+		Agent:     crd.Name,
+		Client:    k8sClient,
+		Telemetry: telemetryProvider,
+		Recorder:  eventRecorder,
+	}
+
+	maxToolIterations := int32(arkv1alpha1.DefaultMaxToolIterations)
+	if crd.Spec.MaxToolIterations != nil {
+		maxToolIterations = *crd.Spec.MaxToolIterations
+	}
 
 	return &Agent{
-		Name:            crd.Name,
-		Namespace:       crd.Namespace,
-		Prompt:          crd.Spec.Prompt,
-		Description:     crd.Spec.Description,
-		Parameters:      crd.Spec.Parameters,
-		Model:           resolvedModel,
-		Tools:           tools,
-		Recorder:        eventRecorder,
-		AgentRecorder:   telemetryProvider.AgentRecorder(),
-		ExecutionEngine: crd.Spec.ExecutionEngine,
-		Annotations:     crd.Annotations,
-		OutputSchema:    crd.Spec.OutputSchema,
-		client:          k8sClient,
+		Name:              crd.Name,
+		Namespace:         crd.Namespace,
+		Prompt:            crd.Spec.Prompt,
+		Description:       crd.Spec.Description,
+		Parameters:        crd.Spec.Parameters,
+		Model:             resolvedModel,
+		Tools:             tools,
+		Recorder:          eventRecorder,
+		AgentRecorder:     telemetryProvider.AgentRecorder(),
+		ExecutionEngine:   crd.Spec.ExecutionEngine,
+		Labels:            crd.Labels,
+		Annotations:       crd.Annotations,
+		OutputSchema:      crd.Spec.OutputSchema,
+		ModelParameters:   crd.Spec.ModelParameters,
+		MaxToolIterations: maxToolIterations,
+		DataPolicy:        crd.Spec.DataPolicy,
+		Guardrails:        guardrails,
+		PostProcessors:    postProcessors,
+		MemoryPolicy:      crd.Spec.MemoryPolicy,
+		client:            k8sClient,
+		modelRecorder:     telemetryProvider.ModelRecorder(),
 	}, nil
 }
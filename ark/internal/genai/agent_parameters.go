@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
@@ -70,9 +71,18 @@ func (a *Agent) resolveValueFrom(ctx context.Context, valueFrom *arkv1alpha1.Val
 		return a.resolveQueryParameterRef(ctx, valueFrom.QueryParameterRef)
 	}
 
+	if valueFrom.QueryFieldRef != nil || valueFrom.ResourceFieldRef != nil {
+		return resolveQueryValueFrom(ctx, a.client, a.Namespace, a.objectMeta(), valueFrom)
+	}
+
 	return "", fmt.Errorf("no supported valueFrom source specified")
 }
 
+// objectMeta returns the metadata of the agent that resourceFieldRef parameters can refer to
+func (a *Agent) objectMeta() *metav1.ObjectMeta {
+	return &metav1.ObjectMeta{Name: a.Name, Namespace: a.Namespace, Labels: a.Labels, Annotations: a.Annotations}
+}
+
 func (a *Agent) resolveConfigMapRef(ctx context.Context, ref *corev1.ConfigMapKeySelector) (string, error) {
 	configMap := &corev1.ConfigMap{}
 	key := types.NamespacedName{Name: ref.Name, Namespace: a.Namespace}
@@ -127,11 +137,11 @@ func (a *Agent) resolveQueryParameterRef(ctx context.Context, ref *arkv1alpha1.Q
 		// Handle nested valueFrom resolution - query parameter may itself reference ConfigMap/Secret
 		// This enables chains like: Agent param -> Query param -> ConfigMap/Secret
 		if param.ValueFrom != nil {
-			value, err := resolveQueryValueFrom(ctx, a.client, a.Namespace, param.ValueFrom)
+			value, err := resolveQueryValueFrom(ctx, a.client, a.Namespace, query, param.ValueFrom)
 			if err != nil {
 				// This is a user configuration error - emit event for visibility
 				if a.Recorder != nil {
-					a.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "QueryParameterResolutionFailed", BaseEvent{
+					a.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonQueryParameterResolutionFailed, BaseEvent{
 						Name: a.GetName(),
 						Metadata: map[string]string{
 							"agentName":     a.GetName(),
@@ -152,7 +162,7 @@ func (a *Agent) resolveQueryParameterRef(ctx context.Context, ref *arkv1alpha1.Q
 
 	// Parameter not found - this is a user configuration error, emit event
 	if a.Recorder != nil {
-		a.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "QueryParameterNotFound", BaseEvent{
+		a.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonQueryParameterNotFound, BaseEvent{
 			Name: a.GetName(),
 			Metadata: map[string]string{
 				"agentName":     a.GetName(),
@@ -174,6 +174,37 @@ func TestAgentParameterResolution(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "query and resource field references",
+			agent: &Agent{
+				Name:      "test-agent",
+				Namespace: "default",
+				Labels:    map[string]string{"team": "travel"},
+				Prompt:    "{{.agent}} of {{.team}} answers {{.query}} in session {{.session}}",
+				Parameters: []arkv1alpha1.Parameter{
+					{Name: "agent", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.name"}}},
+					{Name: "team", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.labels['team']"}}},
+					{Name: "query", ValueFrom: &arkv1alpha1.ValueFromSource{QueryFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.name"}}},
+					{Name: "session", ValueFrom: &arkv1alpha1.ValueFromSource{QueryFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "spec.sessionId"}}},
+				},
+			},
+			query: &arkv1alpha1.Query{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-query", UID: "3f2a"},
+			},
+			wantPrompt: "test-agent of travel answers test-query in session 3f2a",
+		},
+		{
+			name: "unsupported field reference",
+			agent: &Agent{
+				Name:      "test-agent",
+				Namespace: "default",
+				Prompt:    "Hello {{.name}}",
+				Parameters: []arkv1alpha1.Parameter{
+					{Name: "name", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "spec.prompt"}}},
+				},
+			},
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,70 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// newLoopingAgent returns an agent whose mock model calls the noop tool on every request
+func newLoopingAgent(t *testing.T, maxToolIterations int32) *Agent {
+	model, err := MakeModel(context.Background(), fake.NewClientBuilder().Build(), &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "mock", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:  ModelTypeMock,
+			Model: arkv1alpha1.ValueSource{Value: "mock-gpt"},
+			Config: arkv1alpha1.ModelConfig{Mock: &arkv1alpha1.MockModelConfig{
+				Script: arkv1alpha1.ValueSource{Value: "latency: 1ms\nresponses:\n- toolCalls:\n  - name: noop\n    arguments: '{}'\n"},
+			}},
+		},
+	}, noop.NewModelRecorder())
+	require.NoError(t, err)
+
+	tools := NewToolRegistry(nil, noop.NewProvider().ToolRecorder())
+	tools.RegisterTool(GetNoopTool(), &NoopExecutor{})
+	return &Agent{
+		Name:              "looper",
+		Namespace:         "default",
+		Prompt:            "You are a test agent",
+		Model:             model,
+		Tools:             tools,
+		Recorder:          &mockRecorder{},
+		MaxToolIterations: maxToolIterations,
+	}
+}
+
+func TestAgentMaxToolIterations(t *testing.T) {
+	agent := newLoopingAgent(t, 2)
+
+	messages, err := agent.executeLocally(context.Background(), NewUserMessage("loop"), nil, nil, nil)
+	assert.ErrorContains(t, err, "agent default/looper exceeded the maximum of 2 tool iterations")
+	// Two rounds of an assistant message and its tool result, then the assistant message over the limit
+	assert.Len(t, messages, 5)
+}
+
+func TestAgentToolChoice(t *testing.T) {
+	agent := newLoopingAgent(t, 1)
+
+	agent.ModelParameters = &arkv1alpha1.ModelParameters{ToolChoice: "search"}
+	_, err := agent.executeLocally(context.Background(), NewUserMessage("loop"), nil, nil, nil)
+	assert.ErrorContains(t, err, "tool choice search is not a tool of agent default/looper")
+
+	agent.Tools = nil
+	agent.ModelParameters.ToolChoice = arkv1alpha1.ToolChoiceRequired
+	_, err = agent.executeLocally(context.Background(), NewUserMessage("loop"), nil, nil, nil)
+	assert.ErrorContains(t, err, "agent default/looper has tool choice required but no tools")
+
+	tools := []openai.ChatCompletionToolParam{{Function: openai.FunctionDefinitionParam{Name: "noop"}}}
+	assert.NoError(t, agent.checkToolChoice(&arkv1alpha1.ModelParameters{ToolChoice: "noop"}, tools))
+	assert.NoError(t, agent.checkToolChoice(&arkv1alpha1.ModelParameters{ToolChoice: arkv1alpha1.ToolChoiceNone}, nil))
+}
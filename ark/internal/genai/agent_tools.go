@@ -61,7 +61,14 @@ func (p *MCPClientPool) Close() error {
 
 func (r *ToolRegistry) registerTools(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent, telemetryProvider telemetry.Provider) error {
 	for _, agentTool := range agent.Spec.Tools {
-		if err := r.registerTool(ctx, k8sClient, agentTool, agent.Namespace, telemetryProvider); err != nil {
+		namespace := agent.Namespace
+		if agentTool.Namespace != "" {
+			namespace = agentTool.Namespace
+		}
+		if err := CheckReferenceGrant(ctx, k8sClient, arkv1alpha1.ReferenceFromAgent, agent.Namespace, arkv1alpha1.TargetKindTool, agentTool.Name, namespace); err != nil {
+			return err
+		}
+		if err := r.registerTool(ctx, k8sClient, agentTool, agent.Namespace, namespace, telemetryProvider); err != nil {
 			return err
 		}
 	}
@@ -69,6 +76,39 @@ func (r *ToolRegistry) registerTools(ctx context.Context, k8sClient client.Clien
 }
 
 func CreateToolExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, mcpPool *MCPClientPool, mcpSettings map[string]MCPSettings, telemetryProvider telemetry.Provider) (ToolExecutor, error) {
+	if err := ValidateToolSecurity(tool); err != nil {
+		return nil, err
+	}
+	if err := CheckNamespaceToolPolicy(ctx, tool, namespace); err != nil {
+		return nil, err
+	}
+
+	executor, err := createToolExecutorForType(ctx, k8sClient, tool, namespace, mcpPool, mcpSettings, telemetryProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	if tool.Spec.Security != nil {
+		executor = &SecureToolExecutor{
+			BaseExecutor: executor,
+			Security:     tool.Spec.Security,
+		}
+	}
+
+	// Approval wraps the security policy so the wait for a decision is not bounded by the tool timeout
+	if tool.Spec.RequiresApproval {
+		executor = &ApprovalToolExecutor{
+			BaseExecutor: executor,
+			K8sClient:    k8sClient,
+			ToolName:     tool.Name,
+			Namespace:    namespace,
+		}
+	}
+
+	return executor, nil
+}
+
+func createToolExecutorForType(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, mcpPool *MCPClientPool, mcpSettings map[string]MCPSettings, telemetryProvider telemetry.Provider) (ToolExecutor, error) {
 	switch tool.Spec.Type {
 	case ToolTypeHTTP:
 		return createHTTPExecutor(k8sClient, tool, namespace)
@@ -78,6 +118,10 @@ func CreateToolExecutor(ctx context.Context, k8sClient client.Client, tool *arkv
 		return createAgentExecutor(ctx, k8sClient, tool, namespace, telemetryProvider)
 	case ToolTypeBuiltin:
 		return createBuiltinExecutor(tool)
+	case ToolTypeJob:
+		return createJobExecutor(k8sClient, tool, namespace)
+	case ToolTypeCodeInterpreter:
+		return createCodeInterpreterExecutor(k8sClient, tool, namespace)
 	default:
 		return nil, fmt.Errorf("unsupported tool type %s for tool %s", tool.Spec.Type, tool.Name)
 	}
@@ -104,14 +148,10 @@ func createAgentExecutor(ctx context.Context, k8sClient client.Client, tool *ark
 }
 
 func createBuiltinExecutor(tool *arkv1alpha1.Tool) (ToolExecutor, error) {
-	switch tool.Name {
-	case BuiltinToolNoop:
-		return &NoopExecutor{}, nil
-	case BuiltinToolTerminate:
-		return &TerminateExecutor{}, nil
-	default:
-		return nil, fmt.Errorf("unsupported builtin tool %s", tool.Name)
+	if err := ValidateBuiltinTool(tool); err != nil {
+		return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
 	}
+	return builtinTools[BuiltinToolName(tool)].newExecutor(tool), nil
 }
 
 func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
@@ -125,6 +165,29 @@ func createHTTPExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespa
 	}, nil
 }
 
+func createJobExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
+	if tool.Spec.Job == nil {
+		return nil, fmt.Errorf("job spec is required for tool %s", tool.Name)
+	}
+	if _, err := ToolJobTimeout(*tool.Spec.Job); err != nil {
+		return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+	return &JobToolExecutor{
+		K8sClient: k8sClient,
+		ToolName:  tool.Name,
+		Namespace: namespace,
+		Spec:      *tool.Spec.Job,
+	}, nil
+}
+
+func createCodeInterpreterExecutor(k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string) (ToolExecutor, error) {
+	executor, err := NewCodeInterpreterExecutor(k8sClient, tool.Name, namespace, tool.Spec.CodeInterpreter)
+	if err != nil {
+		return nil, fmt.Errorf("tool %s: %w", tool.Name, err)
+	}
+	return executor, nil
+}
+
 func createMCPExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, mcpPool *MCPClientPool, mcpSettings map[string]MCPSettings) (ToolExecutor, error) {
 	if tool.Spec.MCP == nil {
 		return nil, fmt.Errorf("mcp spec is required for tool %s", tool.Name)
@@ -149,6 +212,10 @@ func createMCPExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1
 		return nil, fmt.Errorf("failed to build MCP server URL: %w", err)
 	}
 
+	if err := checkAllowedURL(tool.Spec.Security, mcpURL); err != nil {
+		return nil, fmt.Errorf("tool %s violates security policy: %w", tool.Name, err)
+	}
+
 	headers := make(map[string]string)
 	for _, header := range mcpServerCRD.Spec.Headers {
 		value, err := ResolveHeaderValue(ctx, k8sClient, header, namespace)
@@ -189,7 +256,7 @@ func createMCPExecutor(ctx context.Context, k8sClient client.Client, tool *arkv1
 	}, nil
 }
 
-func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, namespace string, telemetryProvider telemetry.Provider) error {
+func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client, agentTool arkv1alpha1.AgentTool, agentNamespace, namespace string, telemetryProvider telemetry.Provider) error {
 	tool := &arkv1alpha1.Tool{}
 	key := client.ObjectKey{Name: agentTool.Name, Namespace: namespace}
 
@@ -197,6 +264,13 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 		return fmt.Errorf("failed to get tool %s: %w", agentTool.Name, err)
 	}
 
+	// A tool of another namespace must also satisfy the policy of the agent namespace
+	if agentNamespace != namespace {
+		if err := CheckNamespaceToolPolicy(ctx, tool, agentNamespace); err != nil {
+			return err
+		}
+	}
+
 	toolDef := CreateToolFromCRD(tool)
 	executor, err := CreateToolExecutor(ctx, k8sClient, tool, namespace, r.mcpPool, r.mcpSettings, telemetryProvider)
 	if err != nil {
@@ -225,9 +299,50 @@ func (r *ToolRegistry) registerTool(ctx context.Context, k8sClient client.Client
 	}
 
 	r.RegisterTool(toolDef, executor)
+
+	if tool.Spec.OutputSchema != nil {
+		validator, err := NewToolOutputValidator(tool.Spec.OutputSchema.Raw)
+		if err != nil {
+			return fmt.Errorf("failed to configure output schema for tool %s: %w", agentTool.Name, err)
+		}
+		r.SetOutputValidator(toolDef.Name, validator)
+	}
+
+	return r.SetToolOutputPolicy(ctx, k8sClient, tool, toolDef.Name, namespace, telemetryProvider)
+}
+
+// SetToolOutputPolicy applies the output policy of tool, if any, to the results of the tool
+// registered as name
+func (r *ToolRegistry) SetToolOutputPolicy(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, name, namespace string, telemetryProvider telemetry.Provider) error {
+	if tool.Spec.Output == nil {
+		return nil
+	}
+	processor, err := newToolOutputProcessor(ctx, k8sClient, tool, namespace, telemetryProvider)
+	if err != nil {
+		return fmt.Errorf("failed to configure output policy for tool %s: %w", tool.Name, err)
+	}
+	r.SetOutputProcessor(name, processor)
 	return nil
 }
 
+func newToolOutputProcessor(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, namespace string, telemetryProvider telemetry.Provider) (*ToolOutputProcessor, error) {
+	processor := &ToolOutputProcessor{
+		Policy:    tool.Spec.Output,
+		K8sClient: k8sClient,
+		Namespace: namespace,
+	}
+
+	if tool.Spec.Output.SummarizeWithModelRef != nil {
+		model, err := LoadModel(ctx, k8sClient, tool.Spec.Output.SummarizeWithModelRef, namespace, telemetryProvider.ModelRecorder())
+		if err != nil {
+			return nil, fmt.Errorf("failed to load summary model: %w", err)
+		}
+		processor.SummaryModel = model
+	}
+
+	return processor, nil
+}
+
 // AgentToolExecutor executes agent tools by calling other agents via MCP
 type AgentToolExecutor struct {
 	AgentName         string
@@ -285,6 +400,9 @@ func (a *AgentToolExecutor) Execute(ctx context.Context, call ToolCall, recorder
 	userInput := NewSystemMessage(inputStr)
 	history := []Message{} // Provide history if applicable
 
+	// The agent acts on the input of the call, not on the instructions of the query
+	ctx = WithQueryInstructions(ctx, nil)
+
 	// Call the agent's Execute function
 	// Pass nil for memory and eventStream (agents-as-tools don't use memory or streaming)
 	// See ARKQB-137 for discussion on streaming support for agents as tools
@@ -0,0 +1,339 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const (
+	// DefaultArtifactThreshold is the response size in bytes above which the response is stored as an artifact
+	DefaultArtifactThreshold = 64 * 1024
+	// DefaultMaxStatusResponseBytes is the size in bytes the responses of a query may take in its status
+	// together, well below the etcd object size limit of 1.5MiB
+	DefaultMaxStatusResponseBytes = 512 * 1024
+
+	// ArtifactConfigMapKey is the key of the gzipped artifact in an artifact ConfigMap
+	ArtifactConfigMapKey = "artifact.json.gz"
+	// Leaves room for the ConfigMap metadata within the 1MiB object limit
+	maxConfigMapArtifactSize = 1000 * 1024
+
+	artifactHTTPTimeout = 30 * time.Second
+)
+
+// Artifact is the full content of a query response
+type Artifact struct {
+	Content string `json:"content"`
+	Raw     string `json:"raw"`
+}
+
+// ArtifactStore persists response artifacts
+type ArtifactStore interface {
+	// Put stores the artifact of a query response under name and returns its reference
+	Put(ctx context.Context, query *arkv1alpha1.Query, name string, artifact Artifact) (*arkv1alpha1.ResponseArtifact, error)
+	// Get reads the artifact stored under name in namespace
+	Get(ctx context.Context, namespace, name string) (Artifact, error)
+}
+
+// ResponseArtifacts moves responses larger than Threshold bytes out of the query status into Store.
+// MaxStatusBytes caps the size of all responses of a query in its status, 0 leaves it uncapped.
+type ResponseArtifacts struct {
+	Store          ArtifactStore
+	Threshold      int
+	MaxStatusBytes int
+}
+
+// NewResponseArtifactsFromEnv creates the artifact store configured by ARK_ARTIFACT_STORE, which defaults
+// to ConfigMaps owned by the query. Returns nil if ARK_ARTIFACT_THRESHOLD_BYTES is 0.
+func NewResponseArtifactsFromEnv(k8sClient client.Client, scheme *runtime.Scheme) (*ResponseArtifacts, error) {
+	threshold, err := artifactBytesFromEnv("ARK_ARTIFACT_THRESHOLD_BYTES", DefaultArtifactThreshold)
+	if err != nil {
+		return nil, err
+	}
+	if threshold == 0 {
+		return nil, nil
+	}
+	maxStatusBytes, err := artifactBytesFromEnv("ARK_ARTIFACT_MAX_STATUS_BYTES", DefaultMaxStatusResponseBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	switch store := strings.TrimSpace(os.Getenv("ARK_ARTIFACT_STORE")); store {
+	case "", arkv1alpha1.ArtifactStoreConfigMap:
+		return &ResponseArtifacts{
+			Store:          &ConfigMapArtifactStore{Client: k8sClient, Scheme: scheme},
+			Threshold:      threshold,
+			MaxStatusBytes: maxStatusBytes,
+		}, nil
+	case arkv1alpha1.ArtifactStoreHTTP:
+		baseURL := os.Getenv("ARK_ARTIFACT_URL")
+		if baseURL == "" {
+			return nil, fmt.Errorf("ARK_ARTIFACT_URL is required for the %s artifact store", arkv1alpha1.ArtifactStoreHTTP)
+		}
+		return &ResponseArtifacts{
+			Store:          &HTTPArtifactStore{URL: strings.TrimSuffix(baseURL, "/"), Client: &http.Client{Timeout: artifactHTTPTimeout}},
+			Threshold:      threshold,
+			MaxStatusBytes: maxStatusBytes,
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ARK_ARTIFACT_STORE '%s': must be %s or %s", store, arkv1alpha1.ArtifactStoreConfigMap, arkv1alpha1.ArtifactStoreHTTP)
+	}
+}
+
+func artifactBytesFromEnv(key string, defaultValue int) (int, error) {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue, nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil || parsed < 0 {
+		return 0, fmt.Errorf("invalid %s '%s': must be a non-negative number of bytes", key, value)
+	}
+	return parsed, nil
+}
+
+// Offload stores a response larger than the threshold as an artifact and returns the response with
+// truncated content and no raw messages. Smaller responses are returned unchanged.
+func (a *ResponseArtifacts) Offload(ctx context.Context, query *arkv1alpha1.Query, name string, response arkv1alpha1.Response) (arkv1alpha1.Response, error) {
+	return a.offload(ctx, query, name, response, a.Threshold)
+}
+
+// OffloadAbove stores a response larger than limit bytes as an artifact, as Offload does for
+// responses larger than the threshold
+func (a *ResponseArtifacts) OffloadAbove(ctx context.Context, query *arkv1alpha1.Query, name string, response arkv1alpha1.Response, limit int) (arkv1alpha1.Response, error) {
+	return a.offload(ctx, query, name, response, limit)
+}
+
+// PreviewResponse returns the response with its content truncated to limit bytes, without raw
+// messages and marked truncated, for responses too large for the status that are not stored as an
+// artifact
+func PreviewResponse(response arkv1alpha1.Response, limit int) arkv1alpha1.Response {
+	if len(response.Content) > limit {
+		response.Content = truncateToolOutput(response.Content, int64(limit), ToolOutputTruncateHead)
+	}
+	response.Raw = ""
+	response.Truncated = true
+	return response
+}
+
+// OffloadResponses offloads the responses of a query larger than the threshold. When the responses
+// would still take more than MaxStatusBytes of the status together, every response larger than an
+// equal share of MaxStatusBytes is offloaded and keeps a preview of that share, so queries fanning
+// out to many targets stay within the object size limit. Responses that cannot be stored keep a
+// preview of the same size, marked truncated, and their errors are returned together.
+func (a *ResponseArtifacts) OffloadResponses(ctx context.Context, query *arkv1alpha1.Query, responses []arkv1alpha1.Response) ([]arkv1alpha1.Response, error) {
+	limit := a.Threshold
+	if a.MaxStatusBytes > 0 && len(responses) > 0 && inlineResponsesSize(responses, limit) > a.MaxStatusBytes {
+		limit = max(min(limit, a.MaxStatusBytes/len(responses)), 1)
+	}
+
+	offloaded := make([]arkv1alpha1.Response, len(responses))
+	var errs []error
+	for i, response := range responses {
+		stored, err := a.offload(ctx, query, ResponseArtifactName(query.Name, i), response, limit)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("response of %s %s: %w", response.Target.Type, response.Target.Name, err))
+		}
+		offloaded[i] = stored
+	}
+	return offloaded, errors.Join(errs...)
+}
+
+// ResponseArtifactName returns the name of the artifact of the response at index of a query
+func ResponseArtifactName(queryName string, index int) string {
+	return fmt.Sprintf("%s-response-%d", queryName, index)
+}
+
+// inlineResponsesSize returns the most the responses take in the status when the responses larger
+// than limit are offloaded
+func inlineResponsesSize(responses []arkv1alpha1.Response, limit int) int {
+	size := 0
+	for _, response := range responses {
+		size += min(len(response.Content)+len(response.Raw), limit)
+	}
+	return size
+}
+
+func (a *ResponseArtifacts) offload(ctx context.Context, query *arkv1alpha1.Query, name string, response arkv1alpha1.Response, limit int) (arkv1alpha1.Response, error) {
+	if len(response.Content)+len(response.Raw) <= limit {
+		return response, nil
+	}
+
+	reference, err := a.Store.Put(ctx, query, name, Artifact{Content: response.Content, Raw: response.Raw})
+	if err != nil {
+		return PreviewResponse(response, limit), err
+	}
+
+	if len(response.Content) > limit {
+		response.Content = truncateToolOutput(response.Content, int64(limit), ToolOutputTruncateHead)
+	}
+	response.Raw = ""
+	response.Artifact = reference
+	return response, nil
+}
+
+// ConfigMapArtifactStore stores artifacts gzipped in ConfigMaps owned by the query, so they are
+// deleted with it
+type ConfigMapArtifactStore struct {
+	Client client.Client
+	Scheme *runtime.Scheme
+}
+
+func (s *ConfigMapArtifactStore) Put(ctx context.Context, query *arkv1alpha1.Query, name string, artifact Artifact) (*arkv1alpha1.ResponseArtifact, error) {
+	data, err := json.Marshal(artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	if _, err := writer.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress artifact: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress artifact: %w", err)
+	}
+	if compressed.Len() > maxConfigMapArtifactSize {
+		return nil, fmt.Errorf("artifact %s is %d bytes compressed, larger than a ConfigMap can hold", name, compressed.Len())
+	}
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: query.Namespace},
+	}
+	if _, err := controllerutil.CreateOrUpdate(ctx, s.Client, configMap, func() error {
+		if configMap.Labels == nil {
+			configMap.Labels = map[string]string{}
+		}
+		configMap.Labels[annotations.Query] = query.Name
+		configMap.BinaryData = map[string][]byte{ArtifactConfigMapKey: compressed.Bytes()}
+		return controllerutil.SetControllerReference(query, configMap, s.Scheme)
+	}); err != nil {
+		return nil, fmt.Errorf("failed to write artifact configMap %s: %w", name, err)
+	}
+
+	return &arkv1alpha1.ResponseArtifact{
+		Store: arkv1alpha1.ArtifactStoreConfigMap,
+		Name:  name,
+		Size:  int64(len(artifact.Content) + len(artifact.Raw)),
+	}, nil
+}
+
+// Get reads an artifact ConfigMap. Only ConfigMaps labeled with their query are read, so other
+// ConfigMaps of the namespace cannot be read as artifacts.
+func (s *ConfigMapArtifactStore) Get(ctx context.Context, namespace, name string) (Artifact, error) {
+	var configMap corev1.ConfigMap
+	if err := s.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &configMap); err != nil {
+		return Artifact{}, fmt.Errorf("failed to get artifact configMap %s: %w", name, err)
+	}
+	if _, ok := configMap.Labels[annotations.Query]; !ok {
+		return Artifact{}, fmt.Errorf("configMap %s is not an artifact", name)
+	}
+
+	reader, err := gzip.NewReader(bytes.NewReader(configMap.BinaryData[ArtifactConfigMapKey]))
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to decompress artifact %s: %w", name, err)
+	}
+	var artifact Artifact
+	if err := json.NewDecoder(reader).Decode(&artifact); err != nil {
+		return Artifact{}, fmt.Errorf("failed to decode artifact %s: %w", name, err)
+	}
+	return artifact, nil
+}
+
+// HTTPArtifactStore puts artifacts as JSON to URL/<namespace>/<name>, such as the ark-cluster-memory
+// /artifacts endpoint. Requests are authenticated with the controller service account token.
+type HTTPArtifactStore struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *HTTPArtifactStore) Put(ctx context.Context, query *arkv1alpha1.Query, name string, artifact Artifact) (*arkv1alpha1.ResponseArtifact, error) {
+	body, err := json.Marshal(artifact)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal artifact: %w", err)
+	}
+
+	artifactURL := fmt.Sprintf("%s/%s/%s", s.URL, url.PathEscape(query.Namespace), url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, artifactURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create artifact request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	if err := setServiceAccountAuthorization(req); err != nil {
+		return nil, err
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("artifact request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close artifact response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("artifact store returned status %d", resp.StatusCode)
+	}
+
+	return &arkv1alpha1.ResponseArtifact{
+		Store: arkv1alpha1.ArtifactStoreHTTP,
+		Name:  name,
+		URL:   artifactURL,
+		Size:  int64(len(artifact.Content) + len(artifact.Raw)),
+	}, nil
+}
+
+func (s *HTTPArtifactStore) Get(ctx context.Context, namespace, name string) (Artifact, error) {
+	artifactURL := fmt.Sprintf("%s/%s/%s", s.URL, url.PathEscape(namespace), url.PathEscape(name))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, artifactURL, nil)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("failed to create artifact request: %w", err)
+	}
+	if err := setServiceAccountAuthorization(req); err != nil {
+		return Artifact{}, err
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return Artifact{}, fmt.Errorf("artifact request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close artifact response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return Artifact{}, fmt.Errorf("artifact store returned status %d", resp.StatusCode)
+	}
+
+	var artifact Artifact
+	if err := json.NewDecoder(resp.Body).Decode(&artifact); err != nil {
+		return Artifact{}, fmt.Errorf("failed to decode artifact %s: %w", name, err)
+	}
+	return artifact, nil
+}
@@ -0,0 +1,196 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestResponseArtifactsOffload(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", UID: "query-uid"}}
+	artifacts := &ResponseArtifacts{Store: &ConfigMapArtifactStore{Client: k8sClient, Scheme: scheme}, Threshold: 32}
+
+	small := arkv1alpha1.Response{Content: "short", Raw: `[]`, Phase: "done"}
+	unchanged, err := artifacts.Offload(ctx, query, "report-response-0", small)
+	require.NoError(t, err)
+	assert.Equal(t, small, unchanged)
+
+	large := arkv1alpha1.Response{Content: strings.Repeat("a", 40), Raw: `[{"role":"assistant"}]`, Phase: "done"}
+	offloaded, err := artifacts.Offload(ctx, query, "report-response-1", large)
+	require.NoError(t, err)
+	require.NotNil(t, offloaded.Artifact)
+	assert.Equal(t, arkv1alpha1.ArtifactStoreConfigMap, offloaded.Artifact.Store)
+	assert.Equal(t, "report-response-1", offloaded.Artifact.Name)
+	assert.Equal(t, int64(len(large.Content)+len(large.Raw)), offloaded.Artifact.Size)
+	assert.Empty(t, offloaded.Raw)
+	assert.Equal(t, strings.Repeat("a", 11)+"\n[truncated 29 bytes]", offloaded.Content)
+
+	var configMap corev1.ConfigMap
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: "report-response-1", Namespace: "default"}, &configMap))
+	assert.Equal(t, "report", configMap.OwnerReferences[0].Name)
+
+	reader, err := gzip.NewReader(bytes.NewReader(configMap.BinaryData[ArtifactConfigMapKey]))
+	require.NoError(t, err)
+	data, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	var stored Artifact
+	require.NoError(t, json.Unmarshal(data, &stored))
+	assert.Equal(t, large.Content, stored.Content)
+	assert.Equal(t, large.Raw, stored.Raw)
+}
+
+func TestResponseArtifactsOffloadResponses(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "fanout", Namespace: "default", UID: "query-uid"}}
+	artifacts := &ResponseArtifacts{Store: &ConfigMapArtifactStore{Client: k8sClient, Scheme: scheme}, Threshold: 100, MaxStatusBytes: 200}
+
+	// Responses within the cap are only offloaded above the threshold
+	responses := []arkv1alpha1.Response{
+		{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "a"}, Content: strings.Repeat("a", 60)},
+		{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "b"}, Content: strings.Repeat("b", 120)},
+	}
+	offloaded, err := artifacts.OffloadResponses(ctx, query, responses)
+	require.NoError(t, err)
+	assert.Equal(t, responses[0], offloaded[0])
+	require.NotNil(t, offloaded[1].Artifact)
+	assert.Equal(t, "fanout-response-1", offloaded[1].Artifact.Name)
+
+	// Responses over the cap together are offloaded above an equal share of the cap
+	responses = nil
+	for i := range 5 {
+		responses = append(responses, arkv1alpha1.Response{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: fmt.Sprintf("agent-%d", i)}, Content: strings.Repeat("x", 30+i*10)})
+	}
+	offloaded, err = artifacts.OffloadResponses(ctx, query, responses)
+	require.NoError(t, err)
+	assert.Nil(t, offloaded[0].Artifact)
+	assert.Nil(t, offloaded[1].Artifact)
+	for _, response := range offloaded[2:] {
+		require.NotNil(t, response.Artifact)
+		assert.LessOrEqual(t, len(response.Content), 40)
+		assert.Contains(t, response.Content, "\n[truncated")
+	}
+
+	stored, err := artifacts.Store.Get(ctx, "default", "fanout-response-4")
+	require.NoError(t, err)
+	assert.Equal(t, responses[4].Content, stored.Content)
+}
+
+type failingArtifactStore struct{}
+
+func (failingArtifactStore) Put(context.Context, *arkv1alpha1.Query, string, Artifact) (*arkv1alpha1.ResponseArtifact, error) {
+	return nil, fmt.Errorf("artifact store unavailable")
+}
+
+func (failingArtifactStore) Get(context.Context, string, string) (Artifact, error) {
+	return Artifact{}, fmt.Errorf("artifact store unavailable")
+}
+
+func TestResponseArtifactsOffloadResponsesFailedStore(t *testing.T) {
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "fanout", Namespace: "default"}}
+	artifacts := &ResponseArtifacts{Store: failingArtifactStore{}, Threshold: 100, MaxStatusBytes: 100}
+
+	responses := []arkv1alpha1.Response{
+		{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "a"}, Content: strings.Repeat("a", 20)},
+		{Target: arkv1alpha1.QueryTarget{Type: "agent", Name: "b"}, Content: strings.Repeat("b", 120), Raw: `[]`},
+	}
+	offloaded, err := artifacts.OffloadResponses(context.Background(), query, responses)
+	assert.ErrorContains(t, err, "artifact store unavailable")
+	assert.Equal(t, responses[0], offloaded[0])
+	assert.Nil(t, offloaded[1].Artifact)
+	assert.True(t, offloaded[1].Truncated)
+	assert.Empty(t, offloaded[1].Raw)
+	assert.Equal(t, strings.Repeat("b", 28)+"\n[truncated 92 bytes]", offloaded[1].Content)
+}
+
+func TestHTTPArtifactStore(t *testing.T) {
+	setTestServiceAccountToken(t, "controller-token")
+	var received Artifact
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/artifacts/default/report-response-0", r.URL.Path)
+		assert.Equal(t, "Bearer controller-token", r.Header.Get("Authorization"))
+		if r.Method == http.MethodGet {
+			_ = json.NewEncoder(w).Encode(received)
+			return
+		}
+		assert.Equal(t, http.MethodPut, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	store := &HTTPArtifactStore{URL: server.URL + "/artifacts", Client: server.Client()}
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default"}}
+	reference, err := store.Put(context.Background(), query, "report-response-0", Artifact{Content: "full", Raw: "[]"})
+	require.NoError(t, err)
+	assert.Equal(t, "full", received.Content)
+	assert.Equal(t, server.URL+"/artifacts/default/report-response-0", reference.URL)
+	assert.Equal(t, int64(6), reference.Size)
+
+	artifact, err := store.Get(context.Background(), "default", "report-response-0")
+	require.NoError(t, err)
+	assert.Equal(t, "full", artifact.Content)
+}
+
+func TestNewResponseArtifactsFromEnv(t *testing.T) {
+	t.Setenv("ARK_ARTIFACT_THRESHOLD_BYTES", "")
+	t.Setenv("ARK_ARTIFACT_MAX_STATUS_BYTES", "")
+	t.Setenv("ARK_ARTIFACT_STORE", "")
+	artifacts, err := NewResponseArtifactsFromEnv(nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, DefaultArtifactThreshold, artifacts.Threshold)
+	assert.Equal(t, DefaultMaxStatusResponseBytes, artifacts.MaxStatusBytes)
+	assert.IsType(t, &ConfigMapArtifactStore{}, artifacts.Store)
+
+	t.Setenv("ARK_ARTIFACT_THRESHOLD_BYTES", "0")
+	artifacts, err = NewResponseArtifactsFromEnv(nil, nil)
+	require.NoError(t, err)
+	assert.Nil(t, artifacts)
+
+	t.Setenv("ARK_ARTIFACT_THRESHOLD_BYTES", "1kb")
+	_, err = NewResponseArtifactsFromEnv(nil, nil)
+	assert.ErrorContains(t, err, "invalid ARK_ARTIFACT_THRESHOLD_BYTES")
+
+	t.Setenv("ARK_ARTIFACT_THRESHOLD_BYTES", "1024")
+	t.Setenv("ARK_ARTIFACT_MAX_STATUS_BYTES", "-1")
+	_, err = NewResponseArtifactsFromEnv(nil, nil)
+	assert.ErrorContains(t, err, "invalid ARK_ARTIFACT_MAX_STATUS_BYTES")
+
+	t.Setenv("ARK_ARTIFACT_MAX_STATUS_BYTES", "0")
+	t.Setenv("ARK_ARTIFACT_STORE", "http")
+	_, err = NewResponseArtifactsFromEnv(nil, nil)
+	assert.ErrorContains(t, err, "ARK_ARTIFACT_URL")
+
+	t.Setenv("ARK_ARTIFACT_STORE", "s3")
+	_, err = NewResponseArtifactsFromEnv(nil, nil)
+	assert.ErrorContains(t, err, "unsupported ARK_ARTIFACT_STORE")
+}
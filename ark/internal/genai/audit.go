@@ -0,0 +1,223 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	AuditSinkFile = "file"
+	AuditSinkHTTP = "http"
+
+	auditHTTPTimeout = 10 * time.Second
+)
+
+// AuditRecord is the append-only record of a single query execution
+type AuditRecord struct {
+	Timestamp      time.Time        `json:"timestamp"`
+	Query          string           `json:"query"`
+	Namespace      string           `json:"namespace"`
+	QueryUID       string           `json:"queryUid"`
+	CreatedBy      string           `json:"createdBy,omitempty"`
+	ServiceAccount string           `json:"serviceAccount,omitempty"`
+	Targets        []string         `json:"targets"`
+	ToolCalls      []AuditToolCall  `json:"toolCalls"`
+	ModelCalls     []AuditModelCall `json:"modelCalls"`
+	TokenUsage     TokenUsage       `json:"tokenUsage"`
+	Status         string           `json:"status"`
+	Error          string           `json:"error,omitempty"`
+	StartTime      time.Time        `json:"startTime"`
+	Duration       string           `json:"duration"`
+}
+
+// AuditToolCall records a tool invocation. Arguments are hashed so records can be
+// correlated without storing potentially sensitive values.
+type AuditToolCall struct {
+	Name          string `json:"name"`
+	Agent         string `json:"agent,omitempty"`
+	ArgumentsHash string `json:"argumentsHash"`
+	Error         string `json:"error,omitempty"`
+}
+
+// AuditModelCall records a call to a model
+type AuditModelCall struct {
+	Model      string     `json:"model"`
+	Agent      string     `json:"agent,omitempty"`
+	TokenUsage TokenUsage `json:"tokenUsage"`
+	Error      string     `json:"error,omitempty"`
+}
+
+// AuditCollector records tool and model calls from operation events for the audit record
+type AuditCollector struct {
+	recorder   EventEmitter
+	mu         sync.Mutex
+	toolCalls  []AuditToolCall
+	modelCalls []AuditModelCall
+}
+
+func NewAuditCollector(recorder EventEmitter) *AuditCollector {
+	return &AuditCollector{
+		recorder:   recorder,
+		toolCalls:  make([]AuditToolCall, 0),
+		modelCalls: make([]AuditModelCall, 0),
+	}
+}
+
+func (c *AuditCollector) EmitEvent(ctx context.Context, eventType, reason string, data EventData) {
+	c.recorder.EmitEvent(ctx, eventType, reason, data)
+
+	opEvent, ok := data.(OperationEvent)
+	if !ok {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	switch reason {
+	case "ToolCallComplete", "ToolCallError":
+		c.toolCalls = append(c.toolCalls, AuditToolCall{
+			Name:          opEvent.Name,
+			Agent:         opEvent.Metadata["agentName"],
+			ArgumentsHash: HashAuditArguments(opEvent.Metadata["parameters"]),
+			Error:         opEvent.Error,
+		})
+	case "LLMCallComplete", "LLMCallError", "ModelCallComplete", "ModelCallError":
+		c.modelCalls = append(c.modelCalls, AuditModelCall{
+			Model:      opEvent.Name,
+			Agent:      opEvent.Metadata["agent"],
+			TokenUsage: opEvent.TokenUsage,
+			Error:      opEvent.Error,
+		})
+	}
+}
+
+// ToolCalls returns the tool calls recorded so far
+func (c *AuditCollector) ToolCalls() []AuditToolCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]AuditToolCall(nil), c.toolCalls...)
+}
+
+// ModelCalls returns the model calls recorded so far
+func (c *AuditCollector) ModelCalls() []AuditModelCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]AuditModelCall(nil), c.modelCalls...)
+}
+
+// HashAuditArguments returns the hex encoded SHA-256 of tool call arguments
+func HashAuditArguments(arguments string) string {
+	sum := sha256.Sum256([]byte(arguments))
+	return hex.EncodeToString(sum[:])
+}
+
+// AuditSink persists audit records
+type AuditSink interface {
+	Write(ctx context.Context, record AuditRecord) error
+}
+
+// NewAuditSinkFromEnv creates the audit sink configured by ARK_AUDIT_SINK.
+// Returns nil if auditing is not enabled.
+func NewAuditSinkFromEnv() (AuditSink, error) {
+	switch sink := strings.TrimSpace(os.Getenv("ARK_AUDIT_SINK")); sink {
+	case "":
+		return nil, nil
+	case AuditSinkFile:
+		path := os.Getenv("ARK_AUDIT_FILE_PATH")
+		if path == "" {
+			return nil, fmt.Errorf("ARK_AUDIT_FILE_PATH is required for the %s audit sink", AuditSinkFile)
+		}
+		return &FileAuditSink{Path: path}, nil
+	case AuditSinkHTTP:
+		url := os.Getenv("ARK_AUDIT_URL")
+		if url == "" {
+			return nil, fmt.Errorf("ARK_AUDIT_URL is required for the %s audit sink", AuditSinkHTTP)
+		}
+		return &HTTPAuditSink{URL: url, Client: &http.Client{Timeout: auditHTTPTimeout}}, nil
+	default:
+		return nil, fmt.Errorf("unsupported ARK_AUDIT_SINK '%s': must be %s or %s", sink, AuditSinkFile, AuditSinkHTTP)
+	}
+}
+
+// FileAuditSink appends audit records to a file as JSON lines
+type FileAuditSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+func (s *FileAuditSink) Write(ctx context.Context, record AuditRecord) error {
+	line, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return fmt.Errorf("failed to open audit file: %w", err)
+	}
+	defer func() {
+		if closeErr := file.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close audit file")
+		}
+	}()
+
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit record: %w", err)
+	}
+	return nil
+}
+
+// HTTPAuditSink posts audit records as JSON to a URL, such as the ark-cluster-memory /audit endpoint,
+// authenticated with the service account token
+type HTTPAuditSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func (s *HTTPAuditSink) Write(ctx context.Context, record AuditRecord) error {
+	body, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit record: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create audit request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if err := setServiceAccountAuthorization(req); err != nil {
+		return err
+	}
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("audit request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close audit response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("audit sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
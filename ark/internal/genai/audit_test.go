@@ -0,0 +1,114 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuditCollector(t *testing.T) {
+	ctx := context.Background()
+	mockRec := &mockRecorder{}
+	collector := NewAuditCollector(mockRec)
+
+	toolTracker := NewOperationTracker(collector, ctx, "ToolCall", "search", map[string]string{
+		"agentName":  "default/researcher",
+		"parameters": `{"q":"ark"}`,
+	})
+	toolTracker.Complete("result")
+
+	llmTracker := NewOperationTracker(collector, ctx, "LLMCall", "gpt-4o", map[string]string{"agent": "default/researcher"})
+	llmTracker.CompleteWithTokens(TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15})
+
+	// Events are passed through to the wrapped recorder
+	assert.Len(t, mockRec.events, 4)
+
+	toolCalls := collector.ToolCalls()
+	require.Len(t, toolCalls, 1)
+	assert.Equal(t, "search", toolCalls[0].Name)
+	assert.Equal(t, "default/researcher", toolCalls[0].Agent)
+	assert.Equal(t, HashAuditArguments(`{"q":"ark"}`), toolCalls[0].ArgumentsHash)
+
+	modelCalls := collector.ModelCalls()
+	require.Len(t, modelCalls, 1)
+	assert.Equal(t, "gpt-4o", modelCalls[0].Model)
+	assert.Equal(t, int64(15), modelCalls[0].TokenUsage.TotalTokens)
+}
+
+func TestFileAuditSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	sink := &FileAuditSink{Path: path}
+
+	require.NoError(t, sink.Write(context.Background(), AuditRecord{Query: "first", Status: "done"}))
+	require.NoError(t, sink.Write(context.Background(), AuditRecord{Query: "second", Status: "error"}))
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer func() { _ = file.Close() }()
+
+	var queries []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var record AuditRecord
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		queries = append(queries, record.Query)
+	}
+	assert.Equal(t, []string{"first", "second"}, queries)
+}
+
+// setTestServiceAccountToken makes requests to ARK services send token for the rest of the test
+func setTestServiceAccountToken(t *testing.T, token string) {
+	tokenPath := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenPath, []byte(token+"\n"), 0o600))
+	original := serviceAccountTokenPath
+	serviceAccountTokenPath = tokenPath
+	t.Cleanup(func() { serviceAccountTokenPath = original })
+}
+
+func TestHTTPAuditSink(t *testing.T) {
+	setTestServiceAccountToken(t, "controller-token")
+
+	var received AuditRecord
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		authorization = r.Header.Get("Authorization")
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	sink := &HTTPAuditSink{URL: server.URL, Client: server.Client()}
+	require.NoError(t, sink.Write(context.Background(), AuditRecord{Query: "q", CreatedBy: "alice"}))
+	assert.Equal(t, "alice", received.CreatedBy)
+	assert.Equal(t, "Bearer controller-token", authorization)
+
+	serviceAccountTokenPath = filepath.Join(t.TempDir(), "missing")
+	require.NoError(t, sink.Write(context.Background(), AuditRecord{Query: "q"}))
+	assert.Empty(t, authorization)
+}
+
+func TestNewAuditSinkFromEnv(t *testing.T) {
+	t.Setenv("ARK_AUDIT_SINK", "")
+	sink, err := NewAuditSinkFromEnv()
+	require.NoError(t, err)
+	assert.Nil(t, sink)
+
+	t.Setenv("ARK_AUDIT_SINK", AuditSinkFile)
+	_, err = NewAuditSinkFromEnv()
+	assert.ErrorContains(t, err, "ARK_AUDIT_FILE_PATH")
+
+	t.Setenv("ARK_AUDIT_SINK", "syslog")
+	_, err = NewAuditSinkFromEnv()
+	assert.ErrorContains(t, err, "unsupported ARK_AUDIT_SINK")
+}
@@ -0,0 +1,200 @@
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// AzureCognitiveServicesScope is the default scope of the Entra ID tokens for Azure OpenAI
+	AzureCognitiveServicesScope = "https://cognitiveservices.azure.com/.default"
+
+	defaultAzureAuthorityHost = "https://login.microsoftonline.com/"
+	defaultAzureIMDSEndpoint  = "http://169.254.169.254/metadata/identity/oauth2/token"
+	azureIMDSAPIVersion       = "2018-02-01"
+
+	// azureTokenRefreshMargin refreshes tokens this long before they expire
+	azureTokenRefreshMargin = 5 * time.Minute
+)
+
+// AzureTokenSource acquires Microsoft Entra ID tokens for Azure OpenAI and caches them until
+// shortly before they expire. With AZURE_FEDERATED_TOKEN_FILE set by workload identity, the
+// federated token of the pod is exchanged for the token. Otherwise the token is requested from
+// the instance metadata service of the node's managed identity.
+type AzureTokenSource struct {
+	ClientID string
+	TenantID string
+	Scope    string
+
+	authorityHost      string
+	imdsEndpoint       string
+	federatedTokenFile string
+	httpClient         *http.Client
+
+	mu        sync.Mutex
+	token     string
+	expiresAt time.Time
+}
+
+type azureTokenSourceKey struct {
+	clientID string
+	tenantID string
+	scope    string
+}
+
+// azureTokenSources shares the token sources of an identity across models and queries, so each
+// token is only requested once
+var azureTokenSources sync.Map
+
+// NewAzureTokenSource returns the token source of an identity. The client and tenant default to
+// AZURE_CLIENT_ID and AZURE_TENANT_ID, and the scope to AzureCognitiveServicesScope.
+func NewAzureTokenSource(clientID, tenantID, scope string) *AzureTokenSource {
+	if clientID == "" {
+		clientID = os.Getenv("AZURE_CLIENT_ID")
+	}
+	if tenantID == "" {
+		tenantID = os.Getenv("AZURE_TENANT_ID")
+	}
+	if scope == "" {
+		scope = AzureCognitiveServicesScope
+	}
+
+	key := azureTokenSourceKey{clientID: clientID, tenantID: tenantID, scope: scope}
+	source, _ := azureTokenSources.LoadOrStore(key, &AzureTokenSource{
+		ClientID:           clientID,
+		TenantID:           tenantID,
+		Scope:              scope,
+		authorityHost:      envOrDefault("AZURE_AUTHORITY_HOST", defaultAzureAuthorityHost),
+		imdsEndpoint:       defaultAzureIMDSEndpoint,
+		federatedTokenFile: os.Getenv("AZURE_FEDERATED_TOKEN_FILE"),
+		httpClient:         &http.Client{Timeout: 30 * time.Second},
+	})
+	return source.(*AzureTokenSource)
+}
+
+// Token returns a valid access token, requesting a new one when the cached token expires soon
+func (s *AzureTokenSource) Token(ctx context.Context) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.token != "" && time.Until(s.expiresAt) > azureTokenRefreshMargin {
+		return s.token, nil
+	}
+
+	var token azureToken
+	var err error
+	if s.federatedTokenFile != "" {
+		token, err = s.workloadIdentityToken(ctx)
+	} else {
+		token, err = s.managedIdentityToken(ctx)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	s.token = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn) * time.Second)
+	return s.token, nil
+}
+
+// workloadIdentityToken exchanges the federated token of the pod for an access token
+func (s *AzureTokenSource) workloadIdentityToken(ctx context.Context) (azureToken, error) {
+	if s.ClientID == "" || s.TenantID == "" {
+		return azureToken{}, fmt.Errorf("azure workload identity requires a client ID and a tenant ID")
+	}
+	assertion, err := os.ReadFile(s.federatedTokenFile)
+	if err != nil {
+		return azureToken{}, fmt.Errorf("failed to read azure federated token: %w", err)
+	}
+
+	form := url.Values{
+		"client_id":             {s.ClientID},
+		"scope":                 {s.Scope},
+		"grant_type":            {"client_credentials"},
+		"client_assertion_type": {"urn:ietf:params:oauth:client-assertion-type:jwt-bearer"},
+		"client_assertion":      {strings.TrimSpace(string(assertion))},
+	}
+	tokenURL := strings.TrimSuffix(s.authorityHost, "/") + "/" + url.PathEscape(s.TenantID) + "/oauth2/v2.0/token"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return azureToken{}, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	return s.requestToken(req)
+}
+
+// managedIdentityToken requests an access token of the managed identity of the node
+func (s *AzureTokenSource) managedIdentityToken(ctx context.Context) (azureToken, error) {
+	query := url.Values{
+		"api-version": {azureIMDSAPIVersion},
+		"resource":    {strings.TrimSuffix(s.Scope, "/.default")},
+	}
+	if s.ClientID != "" {
+		query.Set("client_id", s.ClientID)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.imdsEndpoint+"?"+query.Encode(), nil)
+	if err != nil {
+		return azureToken{}, err
+	}
+	req.Header.Set("Metadata", "true")
+	return s.requestToken(req)
+}
+
+func (s *AzureTokenSource) requestToken(req *http.Request) (azureToken, error) {
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return azureToken{}, fmt.Errorf("failed to request azure token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return azureToken{}, fmt.Errorf("failed to read azure token response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return azureToken{}, fmt.Errorf("azure token request failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	var token azureToken
+	if err := json.Unmarshal(body, &token); err != nil {
+		return azureToken{}, fmt.Errorf("failed to decode azure token response: %w", err)
+	}
+	if token.AccessToken == "" {
+		return azureToken{}, fmt.Errorf("azure token response has no access token")
+	}
+	return token, nil
+}
+
+// azureToken is the token response of Entra ID and of the instance metadata service, which
+// returns expires_in as a string
+type azureToken struct {
+	AccessToken string            `json:"access_token"`
+	ExpiresIn   azureTokenSeconds `json:"expires_in"`
+}
+
+type azureTokenSeconds int64
+
+func (s *azureTokenSeconds) UnmarshalJSON(data []byte) error {
+	seconds, err := strconv.ParseInt(strings.Trim(string(data), `"`), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid expires_in %s: %w", data, err)
+	}
+	*s = azureTokenSeconds(seconds)
+	return nil
+}
+
+func envOrDefault(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
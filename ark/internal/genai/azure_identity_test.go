@@ -0,0 +1,88 @@
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAzureTokenSourceManagedIdentity(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		assert.Equal(t, "true", r.Header.Get("Metadata"))
+		assert.Equal(t, "https://cognitiveservices.azure.com", r.URL.Query().Get("resource"))
+		assert.Equal(t, "user-assigned", r.URL.Query().Get("client_id"))
+		_, _ = w.Write([]byte(`{"access_token": "imds-token", "expires_in": "3599"}`))
+	}))
+	defer server.Close()
+
+	source := &AzureTokenSource{ClientID: "user-assigned", Scope: AzureCognitiveServicesScope, imdsEndpoint: server.URL, httpClient: server.Client()}
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "imds-token", token)
+
+	// The cached token is used until shortly before it expires
+	_, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, requests)
+
+	source.expiresAt = time.Now().Add(time.Minute)
+	_, err = source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, requests)
+}
+
+func TestAzureTokenSourceWorkloadIdentity(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "azure-identity-token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("federated-token\n"), 0o600))
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/tenant/oauth2/v2.0/token", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		assert.Equal(t, "client", r.PostForm.Get("client_id"))
+		assert.Equal(t, "federated-token", r.PostForm.Get("client_assertion"))
+		assert.Equal(t, AzureCognitiveServicesScope, r.PostForm.Get("scope"))
+		_, _ = w.Write([]byte(`{"access_token": "entra-token", "expires_in": 3599}`))
+	}))
+	defer server.Close()
+
+	source := &AzureTokenSource{
+		ClientID: "client", TenantID: "tenant", Scope: AzureCognitiveServicesScope,
+		authorityHost: server.URL + "/", federatedTokenFile: tokenFile, httpClient: server.Client(),
+	}
+	token, err := source.Token(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "entra-token", token)
+}
+
+func TestAzureTokenSourceError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"error": "invalid_client"}`, http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	source := &AzureTokenSource{Scope: AzureCognitiveServicesScope, imdsEndpoint: server.URL, httpClient: server.Client()}
+	_, err := source.Token(context.Background())
+	assert.ErrorContains(t, err, "status 401")
+}
+
+func TestAzureProviderAuthorize(t *testing.T) {
+	provider := &AzureProvider{TokenSource: &AzureTokenSource{token: "cached", expiresAt: time.Now().Add(time.Hour)}}
+	req := httptest.NewRequest(http.MethodPost, "https://example.openai.azure.com/openai/deployments/gpt-4o/chat/completions", nil)
+	req.Header.Set("api-key", "unused")
+
+	_, err := provider.authorize(req, func(r *http.Request) (*http.Response, error) {
+		assert.Equal(t, "Bearer cached", r.Header.Get("Authorization"))
+		assert.Empty(t, r.Header.Get("api-key"))
+		return &http.Response{StatusCode: http.StatusOK}, nil
+	})
+	require.NoError(t, err)
+}
@@ -0,0 +1,254 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const maxExpressionLength = 1024
+
+var calculatorFunctions = map[string]func(float64) float64{
+	"sqrt":  math.Sqrt,
+	"abs":   math.Abs,
+	"floor": math.Floor,
+	"ceil":  math.Ceil,
+	"round": math.Round,
+	"exp":   math.Exp,
+	"ln":    math.Log,
+	"log10": math.Log10,
+	"sin":   math.Sin,
+	"cos":   math.Cos,
+	"tan":   math.Tan,
+}
+
+var calculatorConstants = map[string]float64{
+	"pi": math.Pi,
+	"e":  math.E,
+}
+
+// evaluateExpression evaluates an arithmetic expression with the usual precedence: ^ binds tighter
+// than unary minus, which binds tighter than * / %, which bind tighter than + -
+func evaluateExpression(expression string) (float64, error) {
+	if strings.TrimSpace(expression) == "" {
+		return 0, fmt.Errorf("expression is empty")
+	}
+	if len(expression) > maxExpressionLength {
+		return 0, fmt.Errorf("expression is longer than %d characters", maxExpressionLength)
+	}
+
+	p := &expressionParser{input: expression}
+	value, err := p.parseSum()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected '%c' at position %d", p.input[p.pos], p.pos+1)
+	}
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return 0, fmt.Errorf("expression has no finite result")
+	}
+	return value, nil
+}
+
+// formatNumber prints integers without a fraction and other numbers with the shortest exact form
+func formatNumber(value float64) string {
+	if value == math.Trunc(value) && math.Abs(value) < 1e15 {
+		return strconv.FormatFloat(value, 'f', 0, 64)
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+type expressionParser struct {
+	input string
+	pos   int
+}
+
+func (p *expressionParser) skipSpaces() {
+	for p.pos < len(p.input) && unicode.IsSpace(rune(p.input[p.pos])) {
+		p.pos++
+	}
+}
+
+// next skips spaces and consumes op if it is the next character
+func (p *expressionParser) next(op byte) bool {
+	p.skipSpaces()
+	if p.pos < len(p.input) && p.input[p.pos] == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *expressionParser) parseSum() (float64, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch {
+		case p.next('+'):
+			right, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			left += right
+		case p.next('-'):
+			right, err := p.parseProduct()
+			if err != nil {
+				return 0, err
+			}
+			left -= right
+		default:
+			return left, nil
+		}
+	}
+}
+
+func (p *expressionParser) parseProduct() (float64, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		var op byte
+		switch {
+		case p.next('*'):
+			op = '*'
+		case p.next('/'):
+			op = '/'
+		case p.next('%'):
+			op = '%'
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return 0, err
+		}
+		switch op {
+		case '*':
+			left *= right
+		case '/':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left /= right
+		case '%':
+			if right == 0 {
+				return 0, fmt.Errorf("division by zero")
+			}
+			left = math.Mod(left, right)
+		}
+	}
+}
+
+func (p *expressionParser) parseUnary() (float64, error) {
+	switch {
+	case p.next('-'):
+		value, err := p.parseUnary()
+		return -value, err
+	case p.next('+'):
+		return p.parseUnary()
+	default:
+		return p.parsePower()
+	}
+}
+
+func (p *expressionParser) parsePower() (float64, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return 0, err
+	}
+	if !p.next('^') {
+		return base, nil
+	}
+	// Exponentiation is right associative: 2^3^2 is 2^(3^2)
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return 0, err
+	}
+	return math.Pow(base, exponent), nil
+}
+
+func (p *expressionParser) parsePrimary() (float64, error) {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0, fmt.Errorf("unexpected end of expression")
+	}
+
+	if p.next('(') {
+		value, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if !p.next(')') {
+			return 0, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+		return value, nil
+	}
+
+	start := p.pos
+	c := p.input[p.pos]
+	switch {
+	case c >= '0' && c <= '9' || c == '.':
+		return p.parseNumber()
+	case unicode.IsLetter(rune(c)):
+		for p.pos < len(p.input) && (unicode.IsLetter(rune(p.input[p.pos])) || unicode.IsDigit(rune(p.input[p.pos]))) {
+			p.pos++
+		}
+		name := strings.ToLower(p.input[start:p.pos])
+		if value, ok := calculatorConstants[name]; ok {
+			return value, nil
+		}
+		function, ok := calculatorFunctions[name]
+		if !ok {
+			return 0, fmt.Errorf("unknown name '%s' at position %d", name, start+1)
+		}
+		if !p.next('(') {
+			return 0, fmt.Errorf("missing '(' after %s", name)
+		}
+		argument, err := p.parseSum()
+		if err != nil {
+			return 0, err
+		}
+		if !p.next(')') {
+			return 0, fmt.Errorf("missing ')' at position %d", p.pos+1)
+		}
+		return function(argument), nil
+	default:
+		return 0, fmt.Errorf("unexpected '%c' at position %d", c, start+1)
+	}
+}
+
+func (p *expressionParser) parseNumber() (float64, error) {
+	start := p.pos
+	for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+		p.pos++
+	}
+	// Scientific notation such as 1.5e3
+	if p.pos+1 < len(p.input) && (p.input[p.pos] == 'e' || p.input[p.pos] == 'E') {
+		end := p.pos + 1
+		if p.input[end] == '+' || p.input[end] == '-' {
+			end++
+		}
+		if end < len(p.input) && p.input[end] >= '0' && p.input[end] <= '9' {
+			p.pos = end
+			for p.pos < len(p.input) && p.input[p.pos] >= '0' && p.input[p.pos] <= '9' {
+				p.pos++
+			}
+		}
+	}
+
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number '%s' at position %d", p.input[start:p.pos], start+1)
+	}
+	return value, nil
+}
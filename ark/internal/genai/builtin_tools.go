@@ -0,0 +1,319 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+	// The controller image has no time zone database for current-time
+	_ "time/tzdata"
+
+	"github.com/itchyny/gojq"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	// Responses of fetch-url tools without maxResponseBytes are cut at this size
+	defaultFetchURLMaxBytes = 1024 * 1024
+	defaultFetchURLTimeout  = 30 * time.Second
+	// json-transform filters that do not finish in time are stopped
+	defaultJSONTransformTimeout = 10 * time.Second
+)
+
+// builtinTool is a tool implemented in the controller, selected with spec.builtin.name
+type builtinTool struct {
+	definition  ToolDefinition
+	newExecutor func(tool *arkv1alpha1.Tool) ToolExecutor
+}
+
+var builtinTools = map[string]builtinTool{
+	BuiltinToolNoop: {
+		definition:  GetNoopTool(),
+		newExecutor: func(*arkv1alpha1.Tool) ToolExecutor { return &NoopExecutor{} },
+	},
+	BuiltinToolTerminate: {
+		definition:  GetTerminateTool(),
+		newExecutor: func(*arkv1alpha1.Tool) ToolExecutor { return &TerminateExecutor{} },
+	},
+	BuiltinToolFetchURL: {
+		definition: ToolDefinition{
+			Name:        BuiltinToolFetchURL,
+			Description: "Fetch the content of a web page or API endpoint with an HTTP GET request",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"url": map[string]any{
+						"type":        "string",
+						"description": "The http or https URL to fetch",
+					},
+				},
+				"required": []string{"url"},
+			},
+		},
+		newExecutor: func(tool *arkv1alpha1.Tool) ToolExecutor { return &FetchURLExecutor{Security: tool.Spec.Security} },
+	},
+	BuiltinToolCalculator: {
+		definition: ToolDefinition{
+			Name:        BuiltinToolCalculator,
+			Description: "Evaluate an arithmetic expression. Supports + - * / % ^, parentheses, the constants pi and e, and the functions sqrt, abs, floor, ceil, round, exp, ln, log10, sin, cos and tan",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"expression": map[string]any{
+						"type":        "string",
+						"description": "The expression to evaluate, for example (12.5 * 4) / sqrt(16)",
+					},
+				},
+				"required": []string{"expression"},
+			},
+		},
+		newExecutor: func(*arkv1alpha1.Tool) ToolExecutor { return &CalculatorExecutor{} },
+	},
+	BuiltinToolCurrentTime: {
+		definition: ToolDefinition{
+			Name:        BuiltinToolCurrentTime,
+			Description: "Get the current date and time",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"timezone": map[string]any{
+						"type":        "string",
+						"description": "IANA time zone such as Europe/London. Defaults to UTC",
+					},
+				},
+			},
+		},
+		newExecutor: func(*arkv1alpha1.Tool) ToolExecutor { return &CurrentTimeExecutor{Now: time.Now} },
+	},
+	BuiltinToolJSONTransform: {
+		definition: ToolDefinition{
+			Name:        BuiltinToolJSONTransform,
+			Description: "Transform JSON data with a jq filter, for example to select, filter, sort or aggregate fields",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"data": map[string]any{
+						"description": "The JSON data to transform, as a JSON value or a string containing JSON",
+					},
+					"filter": map[string]any{
+						"type":        "string",
+						"description": "The jq filter to apply, for example [.items[] | select(.price < 10) | .name]",
+					},
+				},
+				"required": []string{"data", "filter"},
+			},
+		},
+		newExecutor: func(*arkv1alpha1.Tool) ToolExecutor { return &JSONTransformExecutor{} },
+	},
+}
+
+// BuiltinToolNames returns the names of the built-in tools in alphabetical order
+func BuiltinToolNames() []string {
+	names := make([]string, 0, len(builtinTools))
+	for name := range builtinTools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// BuiltinToolName returns the built-in tool implementing a builtin Tool: spec.builtin.name, or the
+// Tool name for tools that do not set it
+func BuiltinToolName(tool *arkv1alpha1.Tool) string {
+	if tool.Spec.Builtin != nil && tool.Spec.Builtin.Name != "" {
+		return tool.Spec.Builtin.Name
+	}
+	return tool.Name
+}
+
+// ValidateBuiltinTool checks that a builtin Tool names a known built-in tool and sets what it requires
+func ValidateBuiltinTool(tool *arkv1alpha1.Tool) error {
+	name := BuiltinToolName(tool)
+	if _, ok := builtinTools[name]; !ok {
+		return fmt.Errorf("unsupported builtin tool '%s': supported builtin tools are: %v", name, BuiltinToolNames())
+	}
+	if name == BuiltinToolFetchURL && (tool.Spec.Security == nil || len(tool.Spec.Security.AllowedHosts) == 0) {
+		return fmt.Errorf("builtin tool '%s' requires security.allowedHosts", name)
+	}
+	return nil
+}
+
+func parseBuiltinToolArguments(call ToolCall, arguments any) (ToolResult, error) {
+	if call.Function.Arguments == "" {
+		return ToolResult{}, nil
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), arguments); err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to parse arguments: %w", err))
+	}
+	return ToolResult{}, nil
+}
+
+func builtinToolError(call ToolCall, err error) (ToolResult, error) {
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+}
+
+// FetchURLExecutor fetches a URL with GET. Only the tool's allowed hosts can be fetched, including
+// through redirects.
+type FetchURLExecutor struct {
+	Security *arkv1alpha1.ToolSecurity
+}
+
+func (f *FetchURLExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		URL string `json:"url"`
+	}
+	if result, err := parseBuiltinToolArguments(call, &arguments); err != nil {
+		return result, err
+	}
+
+	if err := f.checkURL(arguments.URL); err != nil {
+		return builtinToolError(call, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, arguments.URL, nil)
+	if err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to create request: %w", err))
+	}
+
+	httpClient := &http.Client{
+		Timeout: defaultFetchURLTimeout,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return f.checkURL(req.URL.String())
+		},
+	}
+
+	logf.FromContext(ctx).Info("fetching URL", "url", arguments.URL)
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to fetch URL: %w", err))
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		return builtinToolError(call, fmt.Errorf("HTTP error %d: %s (URL: %s)", resp.StatusCode, resp.Status, arguments.URL))
+	}
+
+	// Read one byte past the limit so a tool security policy can reject oversized responses
+	limit := int64(defaultFetchURLMaxBytes)
+	if f.Security != nil && f.Security.MaxResponseBytes > 0 {
+		limit = f.Security.MaxResponseBytes + 1
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, limit))
+	if err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to read response: %w", err))
+	}
+
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: string(body)}, nil
+}
+
+func (f *FetchURLExecutor) checkURL(rawURL string) error {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || (parsedURL.Scheme != "http" && parsedURL.Scheme != "https") || parsedURL.Host == "" {
+		return fmt.Errorf("invalid URL '%s': must be an absolute http or https URL", rawURL)
+	}
+	if f.Security == nil || len(f.Security.AllowedHosts) == 0 {
+		return fmt.Errorf("fetch-url requires security.allowedHosts")
+	}
+	return checkAllowedURL(f.Security, rawURL)
+}
+
+// CalculatorExecutor evaluates arithmetic expressions
+type CalculatorExecutor struct{}
+
+func (c *CalculatorExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		Expression string `json:"expression"`
+	}
+	if result, err := parseBuiltinToolArguments(call, &arguments); err != nil {
+		return result, err
+	}
+
+	value, err := evaluateExpression(arguments.Expression)
+	if err != nil {
+		return builtinToolError(call, err)
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: formatNumber(value)}, nil
+}
+
+// CurrentTimeExecutor returns the current time in a time zone
+type CurrentTimeExecutor struct {
+	Now func() time.Time
+}
+
+func (c *CurrentTimeExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		Timezone string `json:"timezone"`
+	}
+	if result, err := parseBuiltinToolArguments(call, &arguments); err != nil {
+		return result, err
+	}
+
+	location := time.UTC
+	if arguments.Timezone != "" {
+		var err error
+		if location, err = time.LoadLocation(arguments.Timezone); err != nil {
+			return builtinToolError(call, fmt.Errorf("unknown timezone '%s'", arguments.Timezone))
+		}
+	}
+
+	now := c.Now().In(location)
+	content, err := json.Marshal(map[string]any{
+		"time":     now.Format(time.RFC3339),
+		"timezone": location.String(),
+		"weekday":  now.Weekday().String(),
+		"unix":     now.Unix(),
+	})
+	if err != nil {
+		return builtinToolError(call, err)
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: string(content)}, nil
+}
+
+// JSONTransformExecutor applies a jq filter to JSON data
+type JSONTransformExecutor struct{}
+
+func (j *JSONTransformExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		Data   any    `json:"data"`
+		Filter string `json:"filter"`
+	}
+	if result, err := parseBuiltinToolArguments(call, &arguments); err != nil {
+		return result, err
+	}
+
+	query, err := gojq.Parse(arguments.Filter)
+	if err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to parse jq filter '%s': %w", arguments.Filter, err))
+	}
+
+	// Models often pass JSON documents as strings
+	data := arguments.Data
+	if text, ok := data.(string); ok {
+		var parsed any
+		if err := json.Unmarshal([]byte(text), &parsed); err == nil {
+			data = parsed
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, defaultJSONTransformTimeout)
+	defer cancel()
+
+	content, err := runJQQuery(ctx, query, data)
+	if err != nil {
+		return builtinToolError(call, err)
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: content}, nil
+}
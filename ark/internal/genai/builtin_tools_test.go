@@ -0,0 +1,131 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func builtinToolCall(name string, arguments any) ToolCall {
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = name
+	raw, _ := json.Marshal(arguments)
+	call.Function.Arguments = string(raw)
+	return call
+}
+
+func TestEvaluateExpression(t *testing.T) {
+	tests := []struct {
+		expression string
+		expected   string
+	}{
+		{"1 + 2 * 3", "7"},
+		{"(1 + 2) * 3", "9"},
+		{"-2^2", "-4"},
+		{"2^3^2", "512"},
+		{"10 % 4", "2"},
+		{"7 / 2", "3.5"},
+		{"sqrt(16) + abs(-2)", "6"},
+		{"round(pi * 100) / 100", "3.14"},
+		{"1.5e3", "1500"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			value, err := evaluateExpression(tt.expression)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, formatNumber(value))
+		})
+	}
+
+	for _, expression := range []string{"", "1 +", "1 / 0", "(1 + 2", "foo(1)", "2 $ 3", "sqrt(-1)"} {
+		_, err := evaluateExpression(expression)
+		assert.Error(t, err, expression)
+	}
+}
+
+func TestCurrentTimeExecutor(t *testing.T) {
+	executor := &CurrentTimeExecutor{Now: func() time.Time { return time.Date(2025, 3, 14, 15, 9, 26, 0, time.UTC) }}
+
+	result, err := executor.Execute(context.Background(), builtinToolCall(BuiltinToolCurrentTime, map[string]string{"timezone": "Asia/Tokyo"}), nil)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"time":"2025-03-15T00:09:26+09:00","timezone":"Asia/Tokyo","weekday":"Saturday","unix":1741964966}`, result.Content)
+
+	_, err = executor.Execute(context.Background(), builtinToolCall(BuiltinToolCurrentTime, map[string]string{"timezone": "Mars/Olympus"}), nil)
+	assert.Error(t, err)
+}
+
+func TestJSONTransformExecutor(t *testing.T) {
+	executor := &JSONTransformExecutor{}
+
+	result, err := executor.Execute(context.Background(), builtinToolCall(BuiltinToolJSONTransform, map[string]any{
+		"data":   `{"items":[{"name":"tea","price":3},{"name":"cake","price":12}]}`,
+		"filter": "[.items[] | select(.price < 10) | .name]",
+	}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `["tea"]`, result.Content)
+
+	_, err = executor.Execute(context.Background(), builtinToolCall(BuiltinToolJSONTransform, map[string]any{"data": 1, "filter": ".["}), nil)
+	assert.Error(t, err)
+}
+
+func TestFetchURLExecutor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://example.com/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("hello"))
+	}))
+	defer server.Close()
+
+	executor := &FetchURLExecutor{Security: &arkv1alpha1.ToolSecurity{AllowedHosts: []string{"127.0.0.1"}}}
+
+	result, err := executor.Execute(context.Background(), builtinToolCall(BuiltinToolFetchURL, map[string]string{"url": server.URL}), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", result.Content)
+
+	_, err = executor.Execute(context.Background(), builtinToolCall(BuiltinToolFetchURL, map[string]string{"url": "https://example.com/"}), nil)
+	assert.ErrorContains(t, err, "not in the tool's allowed hosts")
+
+	_, err = executor.Execute(context.Background(), builtinToolCall(BuiltinToolFetchURL, map[string]string{"url": server.URL + "/redirect"}), nil)
+	assert.ErrorContains(t, err, "not in the tool's allowed hosts")
+
+	_, err = executor.Execute(context.Background(), builtinToolCall(BuiltinToolFetchURL, map[string]string{"url": "file:///etc/passwd"}), nil)
+	assert.ErrorContains(t, err, "invalid URL")
+}
+
+func TestValidateBuiltinTool(t *testing.T) {
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "math"},
+		Spec: arkv1alpha1.ToolSpec{
+			Type:    ToolTypeBuiltin,
+			Builtin: &arkv1alpha1.BuiltinToolRef{Name: BuiltinToolCalculator},
+		},
+	}
+	assert.NoError(t, ValidateBuiltinTool(tool))
+	assert.Equal(t, builtinTools[BuiltinToolCalculator].definition.Description, CreateToolFromCRD(tool).Description)
+	assert.Contains(t, CreateToolFromCRD(tool).Parameters["properties"], "expression")
+
+	tool.Spec.Builtin.Name = "shell"
+	assert.ErrorContains(t, ValidateBuiltinTool(tool), "unsupported builtin tool 'shell'")
+
+	tool.Spec.Builtin.Name = BuiltinToolFetchURL
+	assert.ErrorContains(t, ValidateBuiltinTool(tool), "requires security.allowedHosts")
+
+	tool.Spec.Security = &arkv1alpha1.ToolSecurity{AllowedHosts: []string{"*.example.com"}}
+	assert.NoError(t, ValidateBuiltinTool(tool))
+
+	// Tools without spec.builtin are matched by name
+	assert.NoError(t, ValidateBuiltinTool(&arkv1alpha1.Tool{ObjectMeta: metav1.ObjectMeta{Name: BuiltinToolNoop}}))
+}
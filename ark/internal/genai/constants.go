@@ -7,9 +7,17 @@ const (
 
 // Model type constants
 const (
-	ModelTypeAzure   = "azure"
-	ModelTypeOpenAI  = "openai"
-	ModelTypeBedrock = "bedrock"
+	ModelTypeAzure     = "azure"
+	ModelTypeOpenAI    = "openai"
+	ModelTypeBedrock   = "bedrock"
+	ModelTypeMock      = "mock"
+	ModelTypeEmbedding = "embedding"
+)
+
+// Model context window strategy constants
+const (
+	ContextWindowStrategyTruncate  = "truncate"
+	ContextWindowStrategySummarize = "summarize"
 )
 
 // Agent tool type constants
@@ -28,14 +36,26 @@ const (
 
 // Tool type constants
 const (
-	ToolTypeHTTP    = "http"
-	ToolTypeMCP     = "mcp"
-	ToolTypeAgent   = "agent"
-	ToolTypeBuiltin = "builtin"
+	ToolTypeHTTP            = "http"
+	ToolTypeMCP             = "mcp"
+	ToolTypeAgent           = "agent"
+	ToolTypeBuiltin         = "builtin"
+	ToolTypeJob             = "job"
+	ToolTypeCodeInterpreter = "code-interpreter"
 )
 
 // Built-in tool name constants
 const (
-	BuiltinToolNoop      = "noop"
-	BuiltinToolTerminate = "terminate"
+	BuiltinToolNoop          = "noop"
+	BuiltinToolTerminate     = "terminate"
+	BuiltinToolFetchURL      = "fetch-url"
+	BuiltinToolCalculator    = "calculator"
+	BuiltinToolCurrentTime   = "current-time"
+	BuiltinToolJSONTransform = "json-transform"
+)
+
+// Tool output truncation constants
+const (
+	ToolOutputTruncateHead = "head"
+	ToolOutputTruncateTail = "tail"
 )
@@ -0,0 +1,121 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ApplyPassCriteria decides whether the evaluation passed from the metrics of the response,
+// replacing the decision of the evaluator. Metrics missing from the response fail. For the
+// weighted aggregation the score of the response becomes the weighted average of the metric
+// scores, where scores of metrics with lowerIsBetter count inverted. It returns the metrics that
+// did not meet their threshold.
+func ApplyPassCriteria(criteria *arkv1alpha1.EvaluationPassCriteria, response *EvaluationResponse) ([]string, error) {
+	var failed []string
+	missing := false
+	var weightedSum, totalWeight float64
+	for _, metric := range criteria.Metrics {
+		score, ok, err := metricScore(response.Metrics, metric.Name)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			failed = append(failed, metric.Name+" (missing)")
+			missing = true
+			continue
+		}
+
+		if metric.Threshold != "" {
+			threshold, err := strconv.ParseFloat(metric.Threshold, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid threshold %q of metric %s: %w", metric.Threshold, metric.Name, err)
+			}
+			if (metric.LowerIsBetter && score > threshold) || (!metric.LowerIsBetter && score < threshold) {
+				failed = append(failed, metric.Name)
+			}
+		}
+
+		weight := 1.0
+		if metric.Weight != "" {
+			if weight, err = strconv.ParseFloat(metric.Weight, 64); err != nil {
+				return nil, fmt.Errorf("invalid weight %q of metric %s: %w", metric.Weight, metric.Name, err)
+			}
+		}
+		if metric.LowerIsBetter {
+			score = 1 - score
+		}
+		weightedSum += weight * score
+		totalWeight += weight
+	}
+
+	switch criteria.Aggregation {
+	case arkv1alpha1.EvaluationAggregationAny:
+		response.Passed = len(failed) < len(criteria.Metrics)
+	case arkv1alpha1.EvaluationAggregationWeighted:
+		threshold, err := strconv.ParseFloat(criteria.Threshold, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid threshold %q of the weighted aggregation: %w", criteria.Threshold, err)
+		}
+		average := 0.0
+		if totalWeight > 0 {
+			average = weightedSum / totalWeight
+		}
+		response.Score = fmt.Sprintf("%.3f", average)
+		// A missing metric cannot be averaged, so it fails the evaluation
+		response.Passed = average >= threshold && !missing
+	default:
+		response.Passed = len(failed) == 0
+	}
+	return failed, nil
+}
+
+// AverageMetrics averages the scores of each metric over the given metric maps, skipping scores
+// that are not numbers
+func AverageMetrics(metrics []map[string]string) map[string]string {
+	sums := map[string]float64{}
+	counts := map[string]int{}
+	for _, scores := range metrics {
+		for name, value := range scores {
+			score, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				continue
+			}
+			sums[name] += score
+			counts[name]++
+		}
+	}
+	if len(sums) == 0 {
+		return nil
+	}
+
+	averages := make(map[string]string, len(sums))
+	for name, sum := range sums {
+		averages[name] = fmt.Sprintf("%.3f", sum/float64(counts[name]))
+	}
+	return averages
+}
+
+// FormatFailedMetrics describes the metrics that did not meet their threshold
+func FormatFailedMetrics(failed []string) string {
+	sorted := append([]string(nil), failed...)
+	sort.Strings(sorted)
+	return "metrics below threshold: " + strings.Join(sorted, ", ")
+}
+
+func metricScore(metrics map[string]string, name string) (float64, bool, error) {
+	value, ok := metrics[name]
+	if !ok {
+		return 0, false, nil
+	}
+	score, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid score %q of metric %s: %w", value, name, err)
+	}
+	return score, true, nil
+}
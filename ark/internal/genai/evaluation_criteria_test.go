@@ -0,0 +1,70 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestApplyPassCriteria(t *testing.T) {
+	metrics := []arkv1alpha1.EvaluationMetricThreshold{
+		{Name: "relevance", Threshold: "0.7", Weight: "2"},
+		{Name: "correctness", Threshold: "0.8"},
+		{Name: "toxicity", Threshold: "0.2", LowerIsBetter: true},
+	}
+	scores := map[string]string{"relevance": "0.9", "correctness": "0.6", "toxicity": "0.1"}
+
+	tests := []struct {
+		name        string
+		aggregation string
+		threshold   string
+		scores      map[string]string
+		passed      bool
+		score       string
+		failed      []string
+	}{
+		{name: "all fails on one metric", aggregation: arkv1alpha1.EvaluationAggregationAll, scores: scores, passed: false, score: "0.5", failed: []string{"correctness"}},
+		{name: "any passes on one metric", aggregation: arkv1alpha1.EvaluationAggregationAny, scores: scores, passed: true, score: "0.5", failed: []string{"correctness"}},
+		{name: "weighted averages inverted lower is better scores", aggregation: arkv1alpha1.EvaluationAggregationWeighted, threshold: "0.8", scores: scores, passed: true, score: "0.825", failed: []string{"correctness"}},
+		{name: "weighted fails below threshold", aggregation: arkv1alpha1.EvaluationAggregationWeighted, threshold: "0.9", scores: scores, passed: false, score: "0.825", failed: []string{"correctness"}},
+		{
+			name:        "missing metric fails weighted",
+			aggregation: arkv1alpha1.EvaluationAggregationWeighted,
+			threshold:   "0.5",
+			scores:      map[string]string{"relevance": "1", "correctness": "1"},
+			passed:      false,
+			score:       "1.000",
+			failed:      []string{"toxicity (missing)"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			criteria := &arkv1alpha1.EvaluationPassCriteria{Metrics: metrics, Aggregation: tt.aggregation, Threshold: tt.threshold}
+			response := &EvaluationResponse{Score: "0.5", Passed: !tt.passed, Metrics: tt.scores}
+
+			failed, err := ApplyPassCriteria(criteria, response)
+			require.NoError(t, err)
+			assert.Equal(t, tt.passed, response.Passed)
+			assert.Equal(t, tt.score, response.Score)
+			assert.Equal(t, tt.failed, failed)
+		})
+	}
+
+	_, err := ApplyPassCriteria(&arkv1alpha1.EvaluationPassCriteria{Metrics: metrics}, &EvaluationResponse{Metrics: map[string]string{"relevance": "high"}})
+	assert.ErrorContains(t, err, "invalid score")
+}
+
+func TestAverageMetrics(t *testing.T) {
+	averages := AverageMetrics([]map[string]string{
+		{"relevance": "0.8", "toxicity": "0.1"},
+		{"relevance": "0.6", "toxicity": "n/a"},
+		nil,
+	})
+	assert.Equal(t, map[string]string{"relevance": "0.700", "toxicity": "0.100"}, averages)
+	assert.Nil(t, AverageMetrics([]map[string]string{nil}))
+}
@@ -3,11 +3,9 @@
 package genai
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
+	"encoding/base64"
 	"fmt"
-	"net/http"
 	"time"
 
 	"k8s.io/apimachinery/pkg/types"
@@ -38,6 +36,8 @@ type EvaluationResponse struct {
 	Metadata   map[string]string       `json:"metadata,omitempty"`
 	Error      string                  `json:"error,omitempty"`
 	TokenUsage *arkv1alpha1.TokenUsage `json:"tokenUsage,omitempty"`
+	// Metrics are the scores of individual metrics, e.g. relevance or toxicity, as decimal strings
+	Metrics map[string]string `json:"metrics,omitempty"`
 }
 
 // Deprecated types - use UnifiedEvaluationRequest instead
@@ -85,44 +85,8 @@ func resolveEvaluatorAddress(ctx context.Context, k8sClient client.Client, evalu
 	return address, nil
 }
 
-func callEvaluatorHTTPEndpoint(ctx context.Context, address, endpoint string, request any, timeout time.Duration) (*http.Response, error) {
-	requestBody, err := json.Marshal(request)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	httpClient := &http.Client{Timeout: timeout}
-
-	// Build endpoint URL
-	evaluateURL := address
-	if endpoint != "" {
-		if evaluateURL[len(evaluateURL)-1] != '/' {
-			evaluateURL += "/"
-		}
-		evaluateURL += endpoint
-	}
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodPost, evaluateURL, bytes.NewBuffer(requestBody))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call evaluator: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		_ = resp.Body.Close()
-		return nil, fmt.Errorf("evaluator returned status %d", resp.StatusCode)
-	}
-
-	return resp, nil
-}
-
 // CallUnifiedEvaluator performs evaluation using the new unified endpoint
-func CallUnifiedEvaluator(ctx context.Context, k8sClient client.Client, evaluatorRef arkv1alpha1.EvaluationEvaluatorRef, request UnifiedEvaluationRequest, namespace string, timeout time.Duration) (*EvaluationResponse, error) {
+func (c *EvaluatorClient) CallUnifiedEvaluator(ctx context.Context, k8sClient client.Client, evaluatorRef arkv1alpha1.EvaluationEvaluatorRef, request UnifiedEvaluationRequest, namespace string, timeout time.Duration) (*EvaluationResponse, error) {
 	log := logf.FromContext(ctx)
 	log.Info("CallUnifiedEvaluator started", "evaluatorRef", evaluatorRef.Name, "namespace", namespace, "parameters", request.Parameters, "timeout", timeout)
 
@@ -142,12 +106,29 @@ func CallUnifiedEvaluator(ctx context.Context, k8sClient client.Client, evaluato
 		return nil, err
 	}
 
-	log.Info("Calling unified evaluator HTTP endpoint", "address", address, "requestType", request.Type, "parameters", request.Parameters, "timeout", timeout)
+	headers, err := resolveEvaluatorHeaders(ctx, k8sClient, evaluator)
+	if err != nil {
+		log.Error(err, "Failed to resolve evaluator headers")
+		return nil, err
+	}
 
-	// Call unified evaluator HTTP endpoint
-	response, err := callUnifiedEvaluatorHTTP(ctx, address, request, timeout)
+	tlsConfig, err := resolveEvaluatorTLS(ctx, k8sClient, evaluator)
 	if err != nil {
-		log.Error(err, "Unified evaluator HTTP call failed")
+		log.Error(err, "Failed to resolve evaluator TLS")
+		return nil, err
+	}
+
+	timeout = evaluationTimeout(request.Type, timeout)
+	log.Info("Calling unified evaluator", "address", address, "protocol", evaluator.Spec.Protocol, "requestType", request.Type, "parameters", request.Parameters, "timeout", timeout)
+
+	var response *EvaluationResponse
+	if evaluator.Spec.Protocol == arkv1alpha1.EvaluatorProtocolGRPC {
+		response, err = c.callUnifiedEvaluatorGRPC(ctx, address, tlsConfig, headers, request, timeout)
+	} else {
+		response, err = c.callUnifiedEvaluatorHTTP(ctx, address, tlsConfig, headers, request, timeout)
+	}
+	if err != nil {
+		log.Error(err, "Unified evaluator call failed")
 		return nil, err
 	}
 
@@ -155,28 +136,90 @@ func CallUnifiedEvaluator(ctx context.Context, k8sClient client.Client, evaluato
 	return response, nil
 }
 
-func callUnifiedEvaluatorHTTP(ctx context.Context, address string, request UnifiedEvaluationRequest, configuredTimeout time.Duration) (*EvaluationResponse, error) {
-	// Use configured timeout, with type-specific adjustments if needed
-	timeout := configuredTimeout
-	if request.Type == "baseline" && configuredTimeout < 120*time.Second {
-		// For baseline evaluations, ensure at least 2 minutes due to multiple LLM calls
-		timeout = 120 * time.Second
-		logf.Log.Info("Adjusted timeout for baseline evaluation", "configured", configuredTimeout, "adjusted", timeout)
+// evaluationTimeout returns the timeout of a call to the evaluator. Baseline evaluations make
+// multiple LLM calls, so they get at least 2 minutes.
+func evaluationTimeout(requestType string, configuredTimeout time.Duration) time.Duration {
+	if requestType == "baseline" && configuredTimeout < 120*time.Second {
+		logf.Log.Info("Adjusted timeout for baseline evaluation", "configured", configuredTimeout, "adjusted", 120*time.Second)
+		return 120 * time.Second
+	}
+	return configuredTimeout
+}
+
+// resolveEvaluatorHeaders resolves the headers sent to the evaluator, including the
+// Authorization header of its auth
+func resolveEvaluatorHeaders(ctx context.Context, k8sClient client.Client, evaluator *arkv1alpha1.Evaluator) (map[string]string, error) {
+	headers := make(map[string]string, len(evaluator.Spec.Headers)+1)
+	for _, header := range evaluator.Spec.Headers {
+		value, err := ResolveHeaderValue(ctx, k8sClient, header, evaluator.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve evaluator header %s: %w", header.Name, err)
+		}
+		headers[header.Name] = value
 	}
 
-	resp, err := callEvaluatorHTTPEndpoint(ctx, address, "", request, timeout)
+	authorization, err := resolveEvaluatorAuth(ctx, k8sClient, evaluator)
 	if err != nil {
 		return nil, err
 	}
-	defer func() {
-		if closeErr := resp.Body.Close(); closeErr != nil {
-			logf.Log.Error(closeErr, "failed to close response body")
+	if authorization != "" {
+		headers["Authorization"] = authorization
+	}
+	return headers, nil
+}
+
+// resolveEvaluatorAuth resolves the Authorization header of the evaluator, empty without auth
+func resolveEvaluatorAuth(ctx context.Context, k8sClient client.Client, evaluator *arkv1alpha1.Evaluator) (string, error) {
+	auth := evaluator.Spec.Auth
+	if auth == nil {
+		return "", nil
+	}
+
+	resolver := common.NewValueSourceResolver(k8sClient)
+	switch {
+	case auth.BearerToken != nil:
+		token, err := resolver.ResolveValueSource(ctx, *auth.BearerToken, evaluator.Namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve evaluator auth bearerToken: %w", err)
+		}
+		return "Bearer " + token, nil
+	case auth.Basic != nil:
+		username, err := resolver.ResolveValueSource(ctx, auth.Basic.Username, evaluator.Namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve evaluator auth basic username: %w", err)
 		}
-	}()
+		password, err := resolver.ResolveValueSource(ctx, auth.Basic.Password, evaluator.Namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve evaluator auth basic password: %w", err)
+		}
+		return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+password)), nil
+	}
+	return "", nil
+}
+
+// ValidateEvaluatorCredentials checks that the auth and TLS references of the evaluator resolve,
+// and that its certificates can be loaded
+func ValidateEvaluatorCredentials(ctx context.Context, k8sClient client.Client, evaluator *arkv1alpha1.Evaluator) error {
+	if _, err := resolveEvaluatorAuth(ctx, k8sClient, evaluator); err != nil {
+		return err
+	}
+	tlsConfig, err := resolveEvaluatorTLS(ctx, k8sClient, evaluator)
+	if err != nil || tlsConfig == nil {
+		return err
+	}
+	_, err = tlsConfig.config()
+	return err
+}
+
+func (c *EvaluatorClient) callUnifiedEvaluatorHTTP(ctx context.Context, address string, tlsConfig *evaluatorTLS, headers map[string]string, request UnifiedEvaluationRequest, timeout time.Duration) (*EvaluationResponse, error) {
+	httpClient, err := c.httpClient(address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
 
 	var response EvaluationResponse
-	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
-		return nil, fmt.Errorf("failed to decode unified evaluation response: %w", err)
+	if err := c.post(ctx, httpClient, address, headers, request, &response, timeout); err != nil {
+		return nil, err
 	}
 
 	if response.Error != "" {
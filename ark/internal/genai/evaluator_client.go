@@ -0,0 +1,325 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	DefaultEvaluatorMaxRetries       = 2
+	DefaultEvaluatorFailureThreshold = 5
+	DefaultEvaluatorOpenDuration     = 30 * time.Second
+
+	evaluatorRetryBaseDelay      = 500 * time.Millisecond
+	evaluatorRetryMaxDelay       = 10 * time.Second
+	evaluatorMaxIdleConnsPerHost = 32
+)
+
+// CircuitOpenError is returned without calling the evaluator while its circuit is open
+type CircuitOpenError struct {
+	Address    string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("evaluator %s is unavailable after repeated failures, retry in %s", e.Address, e.RetryAfter.Round(time.Second))
+}
+
+// EvaluatorRetryAfter reports whether err was returned because the circuit of the evaluator is
+// open, and when it will accept calls again
+func EvaluatorRetryAfter(err error) (time.Duration, bool) {
+	var circuitErr *CircuitOpenError
+	if errors.As(err, &circuitErr) {
+		return circuitErr.RetryAfter, true
+	}
+	return 0, false
+}
+
+// evaluatorUnavailableError marks failures that count towards opening the circuit: transport
+// errors, timeouts and 5xx responses
+type evaluatorUnavailableError struct {
+	err error
+}
+
+func (e *evaluatorUnavailableError) Error() string { return e.err.Error() }
+func (e *evaluatorUnavailableError) Unwrap() error { return e.err }
+
+type evaluatorCircuit struct {
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+// EvaluatorClient calls evaluators over shared connections. Unavailable evaluators are
+// retried with jittered backoff, and after FailureThreshold consecutive failed calls to an address
+// its circuit opens: calls fail fast for OpenDuration, then a single call probes the evaluator.
+type EvaluatorClient struct {
+	HTTPClient       *http.Client
+	MaxRetries       int
+	FailureThreshold int
+	OpenDuration     time.Duration
+
+	mu          sync.Mutex
+	circuits    map[string]*evaluatorCircuit
+	grpcConns   map[string]*evaluatorConn
+	httpClients map[string]*evaluatorHTTPClient
+}
+
+// evaluatorHTTPClient is the HTTP client of an HTTPS evaluator with its own TLS configuration, and
+// the key of that configuration
+type evaluatorHTTPClient struct {
+	tlsKey string
+	client *http.Client
+}
+
+// NewEvaluatorClient creates an evaluator client with the default retry and circuit settings
+func NewEvaluatorClient() *EvaluatorClient {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.MaxIdleConnsPerHost = evaluatorMaxIdleConnsPerHost
+	return &EvaluatorClient{
+		HTTPClient:       &http.Client{Transport: transport},
+		MaxRetries:       DefaultEvaluatorMaxRetries,
+		FailureThreshold: DefaultEvaluatorFailureThreshold,
+		OpenDuration:     DefaultEvaluatorOpenDuration,
+		circuits:         map[string]*evaluatorCircuit{},
+		grpcConns:        map[string]*evaluatorConn{},
+		httpClients:      map[string]*evaluatorHTTPClient{},
+	}
+}
+
+// NewEvaluatorClientFromEnv creates an evaluator client configured by ARK_EVALUATOR_MAX_RETRIES,
+// ARK_EVALUATOR_FAILURE_THRESHOLD and ARK_EVALUATOR_OPEN_SECONDS
+func NewEvaluatorClientFromEnv() (*EvaluatorClient, error) {
+	evaluatorClient := NewEvaluatorClient()
+	if err := evaluatorClient.ConfigureFromEnv(); err != nil {
+		return nil, err
+	}
+	return evaluatorClient, nil
+}
+
+// ConfigureFromEnv reads the retry and circuit settings from the environment again, for example
+// when the controller config is reloaded. Unset variables restore the defaults. The settings are
+// left unchanged when a variable is invalid.
+func (c *EvaluatorClient) ConfigureFromEnv() error {
+	maxRetries, failureThreshold, openDuration := DefaultEvaluatorMaxRetries, DefaultEvaluatorFailureThreshold, DefaultEvaluatorOpenDuration
+	settings := []struct {
+		env     string
+		min     int
+		applyTo func(int)
+	}{
+		{"ARK_EVALUATOR_MAX_RETRIES", 0, func(v int) { maxRetries = v }},
+		{"ARK_EVALUATOR_FAILURE_THRESHOLD", 1, func(v int) { failureThreshold = v }},
+		{"ARK_EVALUATOR_OPEN_SECONDS", 1, func(v int) { openDuration = time.Duration(v) * time.Second }},
+	}
+	for _, setting := range settings {
+		value := strings.TrimSpace(os.Getenv(setting.env))
+		if value == "" {
+			continue
+		}
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < setting.min {
+			return fmt.Errorf("invalid %s '%s': must be an integer of at least %d", setting.env, value, setting.min)
+		}
+		setting.applyTo(parsed)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.MaxRetries, c.FailureThreshold, c.OpenDuration = maxRetries, failureThreshold, openDuration
+	return nil
+}
+
+// httpClient returns the client of the evaluator at address. Evaluators without a TLS
+// configuration share HTTPClient, the others get a client that is replaced when their TLS
+// configuration changes.
+func (c *EvaluatorClient) httpClient(address string, tlsConfig *evaluatorTLS) (*http.Client, error) {
+	if tlsConfig == nil {
+		return c.HTTPClient, nil
+	}
+	key := tlsConfig.key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.httpClients == nil {
+		c.httpClients = map[string]*evaluatorHTTPClient{}
+	}
+	if existing, ok := c.httpClients[address]; ok {
+		if existing.tlsKey == key {
+			return existing.client, nil
+		}
+		existing.client.CloseIdleConnections()
+		delete(c.httpClients, address)
+	}
+
+	config, err := tlsConfig.config()
+	if err != nil {
+		return nil, err
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if base, ok := c.HTTPClient.Transport.(*http.Transport); ok {
+		transport = base.Clone()
+	}
+	transport.TLSClientConfig = config
+	httpClient := &http.Client{Transport: transport, Timeout: c.HTTPClient.Timeout}
+	c.httpClients[address] = &evaluatorHTTPClient{tlsKey: key, client: httpClient}
+	return httpClient, nil
+}
+
+// post sends request with headers to the evaluator at address and decodes its response into
+// response. Each attempt is limited to timeout.
+func (c *EvaluatorClient) post(ctx context.Context, httpClient *http.Client, address string, headers map[string]string, request, response any, timeout time.Duration) error {
+	requestBody, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	return c.call(ctx, address, func() error {
+		return c.attempt(ctx, httpClient, address, headers, requestBody, response, timeout)
+	})
+}
+
+// call runs attempt, retrying it while the evaluator at address is unavailable, and records the
+// outcome in the circuit of the address
+func (c *EvaluatorClient) call(ctx context.Context, address string, attempt func() error) error {
+	if err := c.allow(address); err != nil {
+		return err
+	}
+
+	c.mu.Lock()
+	maxRetries := c.MaxRetries
+	c.mu.Unlock()
+
+	log := logf.FromContext(ctx)
+	var err error
+	for i := 0; ; i++ {
+		err = attempt()
+		var unavailable *evaluatorUnavailableError
+		if err == nil || !errors.As(err, &unavailable) || i >= maxRetries || ctx.Err() != nil {
+			break
+		}
+
+		delay := evaluatorRetryDelay(i)
+		log.Info("retrying evaluator call", "address", address, "attempt", i+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			c.record(address, false)
+			return fmt.Errorf("evaluator call cancelled while retrying: %w", ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	var unavailable *evaluatorUnavailableError
+	c.record(address, err == nil || !errors.As(err, &unavailable))
+	return err
+}
+
+func (c *EvaluatorClient) attempt(ctx context.Context, httpClient *http.Client, address string, headers map[string]string, requestBody []byte, response any, timeout time.Duration) error {
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, address, bytes.NewReader(requestBody))
+	if err != nil {
+		return fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	// Evaluator spans join the trace of the evaluation
+	traceHeaders := map[string]string{}
+	telemetry.InjectOTELHeaders(ctx, traceHeaders)
+	for name, value := range traceHeaders {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return &evaluatorUnavailableError{fmt.Errorf("failed to call evaluator: %w", err)}
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close evaluator response body")
+		}
+	}()
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return &evaluatorUnavailableError{fmt.Errorf("evaluator returned status %d", resp.StatusCode)}
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("evaluator returned status %d", resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode evaluator response: %w", err)
+	}
+	return nil
+}
+
+// allow returns a CircuitOpenError while the circuit of address is open. Once the open duration
+// has passed, a single call is let through to probe the evaluator.
+func (c *EvaluatorClient) allow(address string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	circuit, exists := c.circuits[address]
+	if !exists || circuit.failures < c.FailureThreshold {
+		return nil
+	}
+
+	now := time.Now()
+	if now.Before(circuit.openUntil) {
+		return &CircuitOpenError{Address: address, RetryAfter: circuit.openUntil.Sub(now)}
+	}
+	if circuit.probing {
+		return &CircuitOpenError{Address: address, RetryAfter: c.OpenDuration}
+	}
+	circuit.probing = true
+	return nil
+}
+
+// record closes the circuit of address when the evaluator was available, otherwise counts the
+// failure and opens the circuit at the threshold
+func (c *EvaluatorClient) record(address string, available bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if available {
+		delete(c.circuits, address)
+		return
+	}
+
+	circuit, exists := c.circuits[address]
+	if !exists {
+		circuit = &evaluatorCircuit{}
+		c.circuits[address] = circuit
+	}
+	circuit.failures++
+	circuit.probing = false
+	if circuit.failures >= c.FailureThreshold {
+		circuit.openUntil = time.Now().Add(c.OpenDuration)
+	}
+}
+
+// evaluatorRetryDelay returns a random delay between half and all of an exponentially growing
+// bound, so retries of many evaluations do not hit a recovering evaluator at once
+func evaluatorRetryDelay(attempt int) time.Duration {
+	bound := min(evaluatorRetryBaseDelay<<attempt, evaluatorRetryMaxDelay)
+	return bound/2 + rand.N(bound/2+1)
+}
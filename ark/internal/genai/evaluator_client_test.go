@@ -0,0 +1,182 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newTestEvaluatorClient(maxRetries, failureThreshold int) *EvaluatorClient {
+	evaluatorClient := NewEvaluatorClient()
+	evaluatorClient.MaxRetries = maxRetries
+	evaluatorClient.FailureThreshold = failureThreshold
+	evaluatorClient.OpenDuration = time.Hour
+	return evaluatorClient
+}
+
+func TestEvaluatorClientRetriesServerErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"score":"0.9","passed":true}`))
+	}))
+	defer server.Close()
+
+	var response EvaluationResponse
+	err := newTestEvaluatorClient(2, 5).post(context.Background(), http.DefaultClient, server.URL, nil, UnifiedEvaluationRequest{Type: "direct"}, &response, time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), calls.Load())
+	assert.Equal(t, "0.9", response.Score)
+}
+
+func TestEvaluatorClientDoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	evaluatorClient := newTestEvaluatorClient(2, 1)
+	var response EvaluationResponse
+	err := evaluatorClient.post(context.Background(), evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second)
+	assert.ErrorContains(t, err, "status 400")
+	assert.Equal(t, int32(1), calls.Load())
+
+	// A client error means the evaluator is reachable, so the circuit stays closed
+	err = evaluatorClient.post(context.Background(), evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second)
+	_, open := EvaluatorRetryAfter(err)
+	assert.False(t, open)
+}
+
+func TestEvaluatorClientCircuitBreaker(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		_, _ = w.Write([]byte(`{"passed":true}`))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	evaluatorClient := newTestEvaluatorClient(0, 2)
+	var response EvaluationResponse
+
+	for range 2 {
+		err := evaluatorClient.post(ctx, evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second)
+		assert.ErrorContains(t, err, "status 503")
+	}
+
+	err := evaluatorClient.post(ctx, evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second)
+	retryAfter, open := EvaluatorRetryAfter(err)
+	require.True(t, open)
+	assert.Greater(t, retryAfter, 59*time.Minute)
+	assert.Equal(t, int32(2), calls.Load())
+
+	// Once the open duration has passed, a successful probe closes the circuit
+	evaluatorClient.circuits[server.URL].openUntil = time.Now()
+	healthy.Store(true)
+	require.NoError(t, evaluatorClient.post(ctx, evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second))
+	require.NoError(t, evaluatorClient.post(ctx, evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second))
+	assert.Equal(t, int32(4), calls.Load())
+}
+
+func TestEvaluatorClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	evaluatorClient := newTestEvaluatorClient(0, 1)
+	var response EvaluationResponse
+	err := evaluatorClient.post(context.Background(), evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, 50*time.Millisecond)
+	assert.ErrorContains(t, err, "failed to call evaluator")
+
+	_, open := EvaluatorRetryAfter(evaluatorClient.post(context.Background(), evaluatorClient.HTTPClient, server.URL, nil, UnifiedEvaluationRequest{}, &response, time.Second))
+	assert.True(t, open)
+}
+
+func TestNewEvaluatorClientFromEnv(t *testing.T) {
+	t.Setenv("ARK_EVALUATOR_MAX_RETRIES", "0")
+	t.Setenv("ARK_EVALUATOR_FAILURE_THRESHOLD", "")
+	t.Setenv("ARK_EVALUATOR_OPEN_SECONDS", "60")
+	evaluatorClient, err := NewEvaluatorClientFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, 0, evaluatorClient.MaxRetries)
+	assert.Equal(t, DefaultEvaluatorFailureThreshold, evaluatorClient.FailureThreshold)
+	assert.Equal(t, time.Minute, evaluatorClient.OpenDuration)
+
+	t.Setenv("ARK_EVALUATOR_FAILURE_THRESHOLD", "0")
+	_, err = NewEvaluatorClientFromEnv()
+	assert.ErrorContains(t, err, "invalid ARK_EVALUATOR_FAILURE_THRESHOLD")
+}
+
+func TestCallUnifiedEvaluatorHTTPSWithAuth(t *testing.T) {
+	var authorization string
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		_, _ = w.Write([]byte(`{"score":"0.7","passed":true}`))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	caCert := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+	evaluator := &arkv1alpha1.Evaluator{
+		ObjectMeta: metav1.ObjectMeta{Name: "judge", Namespace: "default"},
+		Spec: arkv1alpha1.EvaluatorSpec{
+			Address: arkv1alpha1.ValueSource{Value: server.URL},
+			TLS:     &arkv1alpha1.EvaluatorTLS{CACert: &arkv1alpha1.ValueSource{Value: caCert}},
+			Auth: &arkv1alpha1.EvaluatorAuth{BearerToken: &arkv1alpha1.ValueSource{ValueFrom: &arkv1alpha1.ValueFromSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "judge-token"}, Key: "token"},
+			}}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(evaluator, &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "judge-token", Namespace: "default"},
+		Data:       map[string][]byte{"token": []byte("s3cret")},
+	}).Build()
+
+	response, err := newTestEvaluatorClient(0, 5).CallUnifiedEvaluator(context.Background(), k8sClient, arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"}, UnifiedEvaluationRequest{Type: "direct"}, "default", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "0.7", response.Score)
+	assert.Equal(t, "Bearer s3cret", authorization)
+
+	evaluator.Spec.Auth = &arkv1alpha1.EvaluatorAuth{Basic: &arkv1alpha1.EvaluatorBasicAuth{
+		Username: arkv1alpha1.ValueSource{Value: "ark"},
+		Password: arkv1alpha1.ValueSource{Value: "pass"},
+	}}
+	headers, err := resolveEvaluatorHeaders(context.Background(), k8sClient, evaluator)
+	require.NoError(t, err)
+	assert.Equal(t, "Basic YXJrOnBhc3M=", headers["Authorization"])
+
+	evaluator.Spec.Auth.Basic.Password = arkv1alpha1.ValueSource{ValueFrom: &arkv1alpha1.ValueFromSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "password"},
+	}}
+	assert.ErrorContains(t, ValidateEvaluatorCredentials(context.Background(), k8sClient, evaluator), "failed to resolve evaluator auth basic password")
+}
@@ -0,0 +1,254 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	evaluatorv1 "mckinsey.com/ark/api/evaluator/v1"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+// evaluatorTLS is the resolved TLS configuration of a gRPC evaluator
+type evaluatorTLS struct {
+	caCert             string
+	clientCert         string
+	clientKey          string
+	serverName         string
+	insecureSkipVerify bool
+}
+
+// evaluatorConn is a connection to a gRPC evaluator and the key of the TLS configuration it was
+// opened with
+type evaluatorConn struct {
+	tlsKey string
+	conn   *grpc.ClientConn
+}
+
+// resolveEvaluatorTLS resolves the TLS configuration of the evaluator, nil for plaintext
+func resolveEvaluatorTLS(ctx context.Context, k8sClient client.Client, evaluator *arkv1alpha1.Evaluator) (*evaluatorTLS, error) {
+	spec := evaluator.Spec.TLS
+	if spec == nil {
+		return nil, nil
+	}
+
+	resolved := &evaluatorTLS{serverName: spec.ServerName, insecureSkipVerify: spec.InsecureSkipVerify}
+	resolver := common.NewValueSourceResolver(k8sClient)
+	sources := []struct {
+		field  string
+		source *arkv1alpha1.ValueSource
+		into   *string
+	}{
+		{"caCert", spec.CACert, &resolved.caCert},
+		{"clientCert", spec.ClientCert, &resolved.clientCert},
+		{"clientKey", spec.ClientKey, &resolved.clientKey},
+	}
+	for _, s := range sources {
+		if s.source == nil {
+			continue
+		}
+		value, err := resolver.ResolveValueSource(ctx, *s.source, evaluator.Namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve evaluator tls %s: %w", s.field, err)
+		}
+		*s.into = value
+	}
+	return resolved, nil
+}
+
+// credentials returns the transport credentials of the configuration, plaintext when it is nil
+func (t *evaluatorTLS) credentials() (credentials.TransportCredentials, error) {
+	if t == nil {
+		return insecure.NewCredentials(), nil
+	}
+	config, err := t.config()
+	if err != nil {
+		return nil, err
+	}
+	return credentials.NewTLS(config), nil
+}
+
+// config returns the TLS client configuration, with the client certificate when one is set
+func (t *evaluatorTLS) config() (*tls.Config, error) {
+	config := &tls.Config{
+		ServerName:         t.serverName,
+		InsecureSkipVerify: t.insecureSkipVerify, //nolint:gosec // opt-in for development evaluators
+		MinVersion:         tls.VersionTLS12,
+	}
+	if t.caCert != "" {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM([]byte(t.caCert)) {
+			return nil, fmt.Errorf("evaluator tls caCert contains no PEM certificates")
+		}
+		config.RootCAs = pool
+	}
+	if t.clientCert != "" || t.clientKey != "" {
+		certificate, err := tls.X509KeyPair([]byte(t.clientCert), []byte(t.clientKey))
+		if err != nil {
+			return nil, fmt.Errorf("invalid evaluator tls client certificate: %w", err)
+		}
+		config.Certificates = []tls.Certificate{certificate}
+	}
+	return config, nil
+}
+
+// key identifies the configuration, so connections are reopened when certificates are rotated
+func (t *evaluatorTLS) key() string {
+	if t == nil {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(strings.Join([]string{t.caCert, t.clientCert, t.clientKey, t.serverName, strconv.FormatBool(t.insecureSkipVerify)}, "\x00")))
+	return hex.EncodeToString(sum[:])
+}
+
+// grpcConn returns the shared connection to the gRPC evaluator at address. The connection is
+// replaced when the TLS configuration of the evaluator changes.
+func (c *EvaluatorClient) grpcConn(address string, tlsConfig *evaluatorTLS) (*grpc.ClientConn, error) {
+	key := tlsConfig.key()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.grpcConns == nil {
+		c.grpcConns = map[string]*evaluatorConn{}
+	}
+	if existing, ok := c.grpcConns[address]; ok {
+		if existing.tlsKey == key {
+			return existing.conn, nil
+		}
+		_ = existing.conn.Close()
+		delete(c.grpcConns, address)
+	}
+
+	transportCredentials, err := tlsConfig.credentials()
+	if err != nil {
+		return nil, err
+	}
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(transportCredentials))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create gRPC client for evaluator %s: %w", address, err)
+	}
+	c.grpcConns[address] = &evaluatorConn{tlsKey: key, conn: conn}
+	return conn, nil
+}
+
+func (c *EvaluatorClient) callUnifiedEvaluatorGRPC(ctx context.Context, address string, tlsConfig *evaluatorTLS, headers map[string]string, request UnifiedEvaluationRequest, timeout time.Duration) (*EvaluationResponse, error) {
+	protoRequest, err := newEvaluationRequestProto(request)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := c.grpcConn(address, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	evaluatorService := evaluatorv1.NewEvaluatorServiceClient(conn)
+
+	var protoResponse *evaluatorv1.UnifiedEvaluationResponse
+	err = c.call(ctx, address, func() error {
+		attemptCtx, cancel := context.WithTimeout(withEvaluatorMetadata(ctx, headers), timeout)
+		defer cancel()
+
+		response, callErr := evaluatorService.Evaluate(attemptCtx, protoRequest)
+		if callErr != nil {
+			return grpcEvaluatorError(callErr)
+		}
+		protoResponse = response
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if protoResponse.GetError() != "" {
+		return nil, fmt.Errorf("unified evaluator returned error: %s", protoResponse.GetError())
+	}
+
+	response := evaluationResponseFromProto(protoResponse)
+	logf.Log.Info("Unified evaluator response", "score", response.Score, "passed", response.Passed, "metadata", response.Metadata, "metadata_count", len(response.Metadata), "timeout_used", timeout)
+
+	return response, nil
+}
+
+// withEvaluatorMetadata adds the headers of the evaluator to the metadata of outgoing calls
+func withEvaluatorMetadata(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	pairs := make([]string, 0, 2*len(headers))
+	for name, value := range headers {
+		pairs = append(pairs, strings.ToLower(name), value)
+	}
+	return metadata.AppendToOutgoingContext(ctx, pairs...)
+}
+
+// grpcEvaluatorError marks the status codes of evaluators that are unavailable or failed
+// internally, like 5xx responses of HTTP evaluators, so they are retried and count towards
+// opening the circuit
+func grpcEvaluatorError(err error) error {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded, codes.ResourceExhausted, codes.Aborted, codes.Internal, codes.Unknown:
+		return &evaluatorUnavailableError{fmt.Errorf("failed to call evaluator: %w", err)}
+	default:
+		return fmt.Errorf("evaluator call failed: %w", err)
+	}
+}
+
+// newEvaluationRequestProto converts the request to its protobuf message. The config is converted
+// through JSON, so evaluators receive the same config as over HTTP.
+func newEvaluationRequestProto(request UnifiedEvaluationRequest) (*evaluatorv1.UnifiedEvaluationRequest, error) {
+	config := &structpb.Struct{}
+	if request.Config != nil {
+		data, err := json.Marshal(request.Config)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal evaluation config: %w", err)
+		}
+		if err := config.UnmarshalJSON(data); err != nil {
+			return nil, fmt.Errorf("failed to convert evaluation config: %w", err)
+		}
+	}
+
+	return &evaluatorv1.UnifiedEvaluationRequest{
+		Type:          request.Type,
+		Config:        config,
+		Parameters:    request.Parameters,
+		EvaluatorName: request.EvaluatorName,
+	}, nil
+}
+
+func evaluationResponseFromProto(response *evaluatorv1.UnifiedEvaluationResponse) *EvaluationResponse {
+	result := &EvaluationResponse{
+		Score:    response.GetScore(),
+		Passed:   response.GetPassed(),
+		Metadata: response.GetMetadata(),
+		Metrics:  response.GetMetrics(),
+	}
+	if usage := response.GetTokenUsage(); usage != nil {
+		result.TokenUsage = &arkv1alpha1.TokenUsage{
+			PromptTokens:     usage.GetPromptTokens(),
+			CompletionTokens: usage.GetCompletionTokens(),
+			TotalTokens:      usage.GetTotalTokens(),
+		}
+	}
+	return result
+}
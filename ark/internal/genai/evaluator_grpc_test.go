@@ -0,0 +1,124 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	evaluatorv1 "mckinsey.com/ark/api/evaluator/v1"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type testEvaluatorService struct {
+	evaluatorv1.UnimplementedEvaluatorServiceServer
+	calls     atomic.Int32
+	failFirst codes.Code
+	request   *evaluatorv1.UnifiedEvaluationRequest
+	metadata  metadata.MD
+}
+
+func (s *testEvaluatorService) Evaluate(ctx context.Context, request *evaluatorv1.UnifiedEvaluationRequest) (*evaluatorv1.UnifiedEvaluationResponse, error) {
+	if s.calls.Add(1) == 1 && s.failFirst != codes.OK {
+		return nil, status.Error(s.failFirst, "not yet")
+	}
+	s.request = request
+	s.metadata, _ = metadata.FromIncomingContext(ctx)
+	return &evaluatorv1.UnifiedEvaluationResponse{
+		Score:      "0.8",
+		Passed:     true,
+		Metadata:   map[string]string{"reasoning": "accurate"},
+		Metrics:    map[string]string{"relevance": "0.9"},
+		TokenUsage: &evaluatorv1.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil
+}
+
+func startTestEvaluator(t *testing.T, service *testEvaluatorService) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := grpc.NewServer()
+	evaluatorv1.RegisterEvaluatorServiceServer(server, service)
+	go func() { _ = server.Serve(listener) }()
+	t.Cleanup(server.Stop)
+	return listener.Addr().String()
+}
+
+func TestCallUnifiedEvaluatorGRPC(t *testing.T) {
+	service := &testEvaluatorService{failFirst: codes.Unavailable}
+	address := startTestEvaluator(t, service)
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	evaluator := &arkv1alpha1.Evaluator{
+		ObjectMeta: metav1.ObjectMeta{Name: "judge", Namespace: "default"},
+		Spec: arkv1alpha1.EvaluatorSpec{
+			Address:  arkv1alpha1.ValueSource{Value: address},
+			Protocol: arkv1alpha1.EvaluatorProtocolGRPC,
+			Headers:  []arkv1alpha1.Header{{Name: "X-Tenant", Value: arkv1alpha1.HeaderValue{Value: "ark"}}},
+		},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(evaluator).Build()
+
+	request := UnifiedEvaluationRequest{
+		Type:          "direct",
+		Config:        map[string]interface{}{"input": "2+2", "output": "4"},
+		Parameters:    map[string]string{"scope": "accuracy"},
+		EvaluatorName: "judge",
+	}
+	response, err := newTestEvaluatorClient(2, 5).CallUnifiedEvaluator(context.Background(), k8sClient, arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"}, request, "default", time.Second)
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), service.calls.Load())
+	assert.Equal(t, "0.8", response.Score)
+	assert.True(t, response.Passed)
+	assert.Equal(t, map[string]string{"reasoning": "accurate"}, response.Metadata)
+	assert.Equal(t, map[string]string{"relevance": "0.9"}, response.Metrics)
+	assert.Equal(t, &arkv1alpha1.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15}, response.TokenUsage)
+
+	assert.Equal(t, "direct", service.request.GetType())
+	assert.Equal(t, "4", service.request.GetConfig().GetFields()["output"].GetStringValue())
+	assert.Equal(t, map[string]string{"scope": "accuracy"}, service.request.GetParameters())
+	assert.Equal(t, []string{"ark"}, service.metadata.Get("x-tenant"))
+}
+
+func TestGRPCEvaluatorErrors(t *testing.T) {
+	service := &testEvaluatorService{failFirst: codes.InvalidArgument}
+	address := startTestEvaluator(t, service)
+
+	evaluatorClient := newTestEvaluatorClient(2, 1)
+	_, err := evaluatorClient.callUnifiedEvaluatorGRPC(context.Background(), address, nil, nil, UnifiedEvaluationRequest{Type: "direct"}, time.Second)
+	assert.ErrorContains(t, err, "InvalidArgument")
+	assert.Equal(t, int32(1), service.calls.Load())
+
+	// An invalid request means the evaluator is reachable, so the circuit stays closed
+	_, err = evaluatorClient.callUnifiedEvaluatorGRPC(context.Background(), address, nil, nil, UnifiedEvaluationRequest{Type: "direct"}, time.Second)
+	assert.NoError(t, err)
+}
+
+func TestEvaluatorTLSCredentials(t *testing.T) {
+	_, err := (&evaluatorTLS{caCert: "not a certificate"}).credentials()
+	assert.ErrorContains(t, err, "no PEM certificates")
+
+	_, err = (&evaluatorTLS{clientCert: "not a certificate"}).credentials()
+	assert.ErrorContains(t, err, "invalid evaluator tls client certificate")
+
+	creds, err := (&evaluatorTLS{serverName: "judge.internal"}).credentials()
+	require.NoError(t, err)
+	assert.Equal(t, "tls", creds.Info().SecurityProtocol)
+
+	assert.Empty(t, (*evaluatorTLS)(nil).key())
+	assert.NotEqual(t, (&evaluatorTLS{}).key(), (&evaluatorTLS{serverName: "judge.internal"}).key())
+}
@@ -0,0 +1,67 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+// Operations tracked by an OperationTracker. Their events use the operation followed by a phase as
+// the reason, e.g. ToolCallStart, ToolCallComplete or ToolCallError.
+const (
+	OperationQueryResolve      = "QueryResolve"
+	OperationModelCall         = "ModelCall"
+	OperationAgentExecution    = "AgentExecution"
+	OperationLLMCall           = "LLMCall"
+	OperationToolCall          = "ToolCall"
+	OperationTeamExecution     = "TeamExecution"
+	OperationTeamMember        = "TeamMember"
+	OperationA2ACall           = "A2ACall"
+	OperationExecutor          = "Executor"
+	OperationMemoryAddMessages = "MemoryAddMessages"
+	OperationMemoryGetMessages = "MemoryGetMessages"
+	OperationMemoryCompaction  = "MemoryCompaction"
+)
+
+// Phases appended to an operation to form the event reason
+const (
+	EventPhaseStart    = "Start"
+	EventPhaseComplete = "Complete"
+	EventPhaseError    = "Error"
+	EventPhaseMaxTurns = "MaxTurns"
+)
+
+// Reasons of events emitted outside of an operation
+const (
+	ReasonQueryResolveError              = "QueryResolveError"
+	ReasonTargetExecutionComplete        = "TargetExecutionComplete"
+	ReasonTargetExecutionError           = "TargetExecutionError"
+	ReasonTeamMaxTurnsReached            = "TeamMaxTurnsReached"
+	ReasonTeamMemberFailed               = "TeamMemberFailed"
+	ReasonTeamTerminated                 = "TeamTerminated"
+	ReasonParticipantSelected            = "ParticipantSelected"
+	ReasonSelectorAgentResponse          = "SelectorAgentResponse"
+	ReasonA2AExecutionSuccess            = "A2AExecutionSuccess"
+	ReasonA2AExecutionFailed             = "A2AExecutionFailed"
+	ReasonQueryParameterResolutionFailed = "QueryParameterResolutionFailed"
+	ReasonQueryParameterNotFound         = "QueryParameterNotFound"
+	ReasonGuardrailViolation             = "GuardrailViolation"
+	ReasonGuardrailBlocked               = "GuardrailBlocked"
+	ReasonToolApprovalRequested          = "ToolApprovalRequested"
+	ReasonToolApprovalApproved           = "ToolApprovalApproved"
+	ReasonToolApprovalRejected           = "ToolApprovalRejected"
+	ReasonToolJobStarted                 = "ToolJobStarted"
+	ReasonToolJobFailed                  = "ToolJobFailed"
+	ReasonRemoteQueryComplete            = "RemoteQueryComplete"
+	ReasonPostProcessorFailed            = "PostProcessorFailed"
+)
+
+// Metadata keys shared by events of different reasons
+const (
+	MetadataNamespace          = "namespace"
+	MetadataSessionID          = "sessionId"
+	MetadataQueryID            = "queryId"
+	MetadataAgent              = "agent"
+	MetadataTeam               = "team"
+	MetadataModel              = "model"
+	MetadataTurn               = "turn"
+	MetadataTerminationMessage = "terminationMessage"
+	// MetadataCount is the number of occurrences an aggregated event stands for
+	MetadataCount = "count"
+)
@@ -0,0 +1,109 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	telemetryconfig "mckinsey.com/ark/internal/telemetry/config"
+)
+
+// EventVerbosity controls which Kubernetes events the recorder emits.
+type EventVerbosity string
+
+const (
+	// EventVerbosityAll emits every event. An empty verbosity is treated as all.
+	EventVerbosityAll EventVerbosity = "all"
+	// EventVerbosityAggregated emits the first occurrence of an event and aggregates repeats.
+	EventVerbosityAggregated EventVerbosity = "aggregated"
+	// EventVerbosityWarnings emits only warning events, aggregating repeats.
+	EventVerbosityWarnings EventVerbosity = "warnings"
+	// EventVerbosityNone emits no events.
+	EventVerbosityNone EventVerbosity = "none"
+)
+
+const DefaultEventAggregationInterval = 10
+
+// EventSettings controls the volume of events emitted for a resource.
+type EventSettings struct {
+	Verbosity EventVerbosity
+	// AggregationInterval is the number of repeats of an event after which an aggregated event
+	// carrying the count is emitted
+	AggregationInterval int
+}
+
+// DefaultEventSettings emits every event, as event-based evaluations count individual events.
+func DefaultEventSettings() EventSettings {
+	return EventSettings{
+		Verbosity:           EventVerbosityAll,
+		AggregationInterval: DefaultEventAggregationInterval,
+	}
+}
+
+// ParseEventVerbosity validates an event verbosity.
+func ParseEventVerbosity(value string) (EventVerbosity, error) {
+	switch verbosity := EventVerbosity(value); verbosity {
+	case EventVerbosityAll, EventVerbosityAggregated, EventVerbosityWarnings, EventVerbosityNone:
+		return verbosity, nil
+	default:
+		return "", fmt.Errorf("unknown event verbosity %q, expected one of all, aggregated, warnings, none", value)
+	}
+}
+
+// Merge returns settings overridden by the non-empty eventVerbosity and eventAggregationInterval
+// values in overrides.
+func (s EventSettings) Merge(overrides map[string]string) (EventSettings, error) {
+	if value := overrides["eventVerbosity"]; value != "" {
+		verbosity, err := ParseEventVerbosity(value)
+		if err != nil {
+			return s, err
+		}
+		s.Verbosity = verbosity
+	}
+	if value := overrides["eventAggregationInterval"]; value != "" {
+		interval, err := strconv.Atoi(value)
+		if err != nil || interval <= 0 {
+			return s, fmt.Errorf("invalid eventAggregationInterval %q, expected a positive integer", value)
+		}
+		s.AggregationInterval = interval
+	}
+	return s, nil
+}
+
+// EventSettingsFromEnv reads the controller-wide event settings: ARK_EVENT_VERBOSITY and
+// ARK_EVENT_AGGREGATION_INTERVAL.
+func EventSettingsFromEnv() (EventSettings, error) {
+	return DefaultEventSettings().Merge(map[string]string{
+		"eventVerbosity":           os.Getenv("ARK_EVENT_VERBOSITY"),
+		"eventAggregationInterval": os.Getenv("ARK_EVENT_AGGREGATION_INTERVAL"),
+	})
+}
+
+// NamespaceEventSettings returns the controller settings overridden by the namespace
+// ark-config-telemetry ConfigMap. An invalid ConfigMap is logged and the controller settings are
+// used, so misconfigured events never fail a query.
+func NamespaceEventSettings(ctx context.Context, k8sClient client.Client, namespace string, settings EventSettings) EventSettings {
+	cm := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: telemetryconfig.TelemetryConfigMapName, Namespace: namespace}, cm)
+	if errors.IsNotFound(err) {
+		return settings
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to get namespace event settings, using controller defaults", "namespace", namespace)
+		return settings
+	}
+	merged, err := settings.Merge(cm.Data)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "invalid namespace event settings, using controller defaults", "namespace", namespace)
+		return settings
+	}
+	return merged
+}
@@ -44,6 +44,7 @@ type TokenUsage struct {
 	PromptTokens     int64 `json:"prompt_tokens,omitempty"`
 	CompletionTokens int64 `json:"completion_tokens,omitempty"`
 	TotalTokens      int64 `json:"total_tokens,omitempty"`
+	ReasoningTokens  int64 `json:"reasoning_tokens,omitempty"`
 }
 
 type OperationEvent struct {
@@ -62,11 +63,15 @@ func (e OperationEvent) ToMap() map[string]interface{} {
 		result["duration"] = e.Duration
 	}
 	if e.TokenUsage.TotalTokens > 0 {
-		result["token_usage"] = map[string]interface{}{
+		tokenUsage := map[string]interface{}{
 			"prompt_tokens":     e.TokenUsage.PromptTokens,
 			"completion_tokens": e.TokenUsage.CompletionTokens,
 			"total_tokens":      e.TokenUsage.TotalTokens,
 		}
+		if e.TokenUsage.ReasoningTokens > 0 {
+			tokenUsage["reasoning_tokens"] = e.TokenUsage.ReasoningTokens
+		}
+		result["token_usage"] = tokenUsage
 	}
 	return result
 }
@@ -6,8 +6,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -17,6 +19,8 @@ import (
 	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
 )
 
+const executionEngineProbeTimeout = 10 * time.Second
+
 // ExecutionEngineMessage represents a chat message in the format expected by execution engines
 type ExecutionEngineMessage struct {
 	Role    string `json:"role"`
@@ -38,13 +42,14 @@ type ExecutionEngineRequest struct {
 
 // AgentConfig contains agent configuration for the execution engine
 type AgentConfig struct {
-	Name         string                `json:"name"`
-	Namespace    string                `json:"namespace"`
-	Prompt       string                `json:"prompt"`
-	Description  string                `json:"description"`
-	Parameters   []Parameter           `json:"parameters,omitempty"`
-	Model        ExecutionEngineModel  `json:"model"`
-	OutputSchema *runtime.RawExtension `json:"outputSchema,omitempty"`
+	Name            string                       `json:"name"`
+	Namespace       string                       `json:"namespace"`
+	Prompt          string                       `json:"prompt"`
+	Description     string                       `json:"description"`
+	Parameters      []Parameter                  `json:"parameters,omitempty"`
+	Model           ExecutionEngineModel         `json:"model"`
+	OutputSchema    *runtime.RawExtension        `json:"outputSchema,omitempty"`
+	ModelParameters *arkv1alpha1.ModelParameters `json:"modelParameters,omitempty"`
 }
 
 // ExecutionEngineModel contains model configuration for the execution engine
@@ -156,16 +161,31 @@ func NewExecutionEngineClient(k8sClient client.Client) *ExecutionEngineClient {
 // Execute sends a request to the execution engine and returns the response messages
 func (c *ExecutionEngineClient) Execute(ctx context.Context, engineRef *arkv1alpha1.ExecutionEngineRef, agentConfig AgentConfig, userInput Message, history []Message, tools []ToolDefinition, recorder EventEmitter) ([]Message, error) {
 	// Track ExecutionEngine operation
-	engineTracker := NewOperationTracker(recorder, ctx, "Executor", engineRef.Name, map[string]string{
+	engineTracker := NewOperationTracker(recorder, ctx, OperationExecutor, engineRef.Name, map[string]string{
 		"agent":     agentConfig.Name,
 		"namespace": agentConfig.Namespace,
 	})
 	defer engineTracker.Complete("")
 
-	engineAddress, err := c.resolveExecutionEngineAddress(ctx, engineRef, agentConfig.Namespace)
+	engine, err := c.getExecutionEngine(ctx, engineRef, agentConfig.Namespace)
 	if err != nil {
 		engineTracker.Fail(err)
-		return nil, fmt.Errorf("failed to resolve execution engine address: %w", err)
+		return nil, fmt.Errorf("failed to resolve execution engine: %w", err)
+	}
+
+	if agentConfig.OutputSchema != nil && !engine.Spec.HasCapability(arkv1prealpha1.ExecutionEngineCapabilityStructuredOutput) {
+		err := fmt.Errorf("execution engine %s does not support structured output required by agent %s", engine.Name, agentConfig.Name)
+		engineTracker.Fail(err)
+		return nil, err
+	}
+	if !engine.Spec.HasCapability(arkv1prealpha1.ExecutionEngineCapabilityTools) {
+		tools = nil
+	}
+
+	headers, err := resolveExecutionEngineHeaders(ctx, c.client, engine.Spec.Headers, engine.Namespace)
+	if err != nil {
+		engineTracker.Fail(err)
+		return nil, err
 	}
 
 	// Convert messages to execution engine format
@@ -188,7 +208,7 @@ func (c *ExecutionEngineClient) Execute(ctx context.Context, engineRef *arkv1alp
 		return nil, fmt.Errorf("failed to marshal request: %w", err)
 	}
 
-	url := fmt.Sprintf("%s/execute", engineAddress)
+	url := fmt.Sprintf("%s/execute", strings.TrimSuffix(engine.Status.LastResolvedAddress, "/"))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(requestBody))
 	if err != nil {
@@ -196,6 +216,9 @@ func (c *ExecutionEngineClient) Execute(ctx context.Context, engineRef *arkv1alp
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := c.httpClient.Do(req)
@@ -241,8 +264,8 @@ func (c *ExecutionEngineClient) Execute(ctx context.Context, engineRef *arkv1alp
 	return convertedMessages, nil
 }
 
-// resolveExecutionEngineAddress resolves the address of the execution engine
-func (c *ExecutionEngineClient) resolveExecutionEngineAddress(ctx context.Context, engineRef *arkv1alpha1.ExecutionEngineRef, defaultNamespace string) (string, error) {
+// getExecutionEngine loads the execution engine and checks that it can accept requests
+func (c *ExecutionEngineClient) getExecutionEngine(ctx context.Context, engineRef *arkv1alpha1.ExecutionEngineRef, defaultNamespace string) (*arkv1prealpha1.ExecutionEngine, error) {
 	// Resolve execution engine name and namespace
 	engineName := engineRef.Name
 	namespace := engineRef.Namespace
@@ -254,15 +277,70 @@ func (c *ExecutionEngineClient) resolveExecutionEngineAddress(ctx context.Contex
 	var engineCRD arkv1prealpha1.ExecutionEngine
 	engineKey := types.NamespacedName{Name: engineName, Namespace: namespace}
 	if err := c.client.Get(ctx, engineKey, &engineCRD); err != nil {
-		return "", fmt.Errorf("execution engine %s not found in namespace %s: %w", engineName, namespace, err)
+		return nil, fmt.Errorf("execution engine %s not found in namespace %s: %w", engineName, namespace, err)
 	}
 
 	// Check if address is resolved in status
 	if engineCRD.Status.LastResolvedAddress == "" {
-		return "", fmt.Errorf("execution engine %s address not yet resolved", engineName)
+		return nil, fmt.Errorf("execution engine %s address not yet resolved", engineName)
 	}
 
-	return engineCRD.Status.LastResolvedAddress, nil
+	// Engines that failed their last health check are not sent requests
+	if meta.IsStatusConditionFalse(engineCRD.Status.Conditions, arkv1prealpha1.ExecutionEngineAvailable) {
+		condition := meta.FindStatusCondition(engineCRD.Status.Conditions, arkv1prealpha1.ExecutionEngineAvailable)
+		return nil, fmt.Errorf("execution engine %s is not available: %s", engineName, condition.Message)
+	}
+
+	return &engineCRD, nil
+}
+
+// resolveExecutionEngineHeaders resolves header values from ValueSources
+func resolveExecutionEngineHeaders(ctx context.Context, k8sClient client.Client, headers []arkv1prealpha1.Header, namespace string) (map[string]string, error) {
+	resolvedHeaders := make(map[string]string, len(headers))
+	for _, header := range headers {
+		headerValue, err := ResolveHeaderValueV1PreAlpha1(ctx, k8sClient, header, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve header %s: %w", header.Name, err)
+		}
+		resolvedHeaders[header.Name] = headerValue
+	}
+	return resolvedHeaders, nil
+}
+
+// ProbeExecutionEngine checks the health of an execution engine by calling GET {address}/health.
+// Engines that do not implement the health endpoint (404) are considered available if they respond.
+func ProbeExecutionEngine(ctx context.Context, k8sClient client.Client, address string, headers []arkv1prealpha1.Header, namespace string) error {
+	resolvedHeaders, err := resolveExecutionEngineHeaders(ctx, k8sClient, headers, namespace)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, executionEngineProbeTimeout)
+	defer cancel()
+
+	url := fmt.Sprintf("%s/health", strings.TrimSuffix(address, "/"))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create health request: %w", err)
+	}
+	for name, value := range resolvedHeaders {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("health check failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.Log.Error(closeErr, "failed to close response body")
+		}
+	}()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
+		return fmt.Errorf("health check returned status %d", resp.StatusCode)
+	}
+	return nil
 }
 
 // buildAgentConfig creates an AgentConfig from the agent and model data
@@ -0,0 +1,133 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+)
+
+func newExecutionEngineTestClient(t *testing.T, engine *arkv1prealpha1.ExecutionEngine) *ExecutionEngineClient {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1prealpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(engine).Build()
+	return NewExecutionEngineClient(k8sClient)
+}
+
+func newTestExecutionEngine(address string) *arkv1prealpha1.ExecutionEngine {
+	return &arkv1prealpha1.ExecutionEngine{
+		ObjectMeta: metav1.ObjectMeta{Name: "langchain", Namespace: "default"},
+		Spec: arkv1prealpha1.ExecutionEngineSpec{
+			Type:    "langchain",
+			Address: arkv1prealpha1.ValueSource{Value: address},
+			Headers: []arkv1prealpha1.Header{
+				{Name: "X-Engine-Token", Value: arkv1alpha1.HeaderValue{Value: "secret"}},
+			},
+		},
+		Status: arkv1prealpha1.ExecutionEngineStatus{LastResolvedAddress: address},
+	}
+}
+
+func TestExecutionEngineExecute(t *testing.T) {
+	var received ExecutionEngineRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/execute", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("X-Engine-Token"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(ExecutionEngineResponse{
+			Messages: []ExecutionEngineMessage{{Role: RoleAssistant, Content: "done"}},
+		})
+	}))
+	defer server.Close()
+
+	engine := newTestExecutionEngine(server.URL)
+	engine.Spec.Capabilities = []arkv1prealpha1.ExecutionEngineCapability{arkv1prealpha1.ExecutionEngineCapabilityStructuredOutput}
+	engineClient := newExecutionEngineTestClient(t, engine)
+
+	tools := []ToolDefinition{{Name: "search"}}
+	messages, err := engineClient.Execute(context.Background(), &arkv1alpha1.ExecutionEngineRef{Name: "langchain"},
+		AgentConfig{Name: "agent", Namespace: "default"}, NewUserMessage("hi"), nil, tools, &mockRecorder{})
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "done", messages[0].OfAssistant.Content.OfString.Value)
+	assert.Equal(t, "hi", received.UserInput.Content)
+	// Tools are not sent to engines that do not declare the tools capability
+	assert.Empty(t, received.Tools)
+}
+
+func TestExecutionEngineExecuteUnavailable(t *testing.T) {
+	engine := newTestExecutionEngine("http://engine.invalid")
+	engine.Status.Conditions = []metav1.Condition{{
+		Type:    arkv1prealpha1.ExecutionEngineAvailable,
+		Status:  metav1.ConditionFalse,
+		Reason:  "HealthCheckFailed",
+		Message: "connection refused",
+	}}
+	engineClient := newExecutionEngineTestClient(t, engine)
+
+	_, err := engineClient.Execute(context.Background(), &arkv1alpha1.ExecutionEngineRef{Name: "langchain"},
+		AgentConfig{Name: "agent", Namespace: "default"}, NewUserMessage("hi"), nil, nil, &mockRecorder{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "is not available: connection refused")
+}
+
+func TestExecutionEngineExecuteStructuredOutputUnsupported(t *testing.T) {
+	engine := newTestExecutionEngine("http://engine.invalid")
+	engine.Spec.Capabilities = []arkv1prealpha1.ExecutionEngineCapability{arkv1prealpha1.ExecutionEngineCapabilityTools}
+	engineClient := newExecutionEngineTestClient(t, engine)
+
+	agentConfig := AgentConfig{
+		Name:         "agent",
+		Namespace:    "default",
+		OutputSchema: &runtime.RawExtension{Raw: []byte(`{"type":"object"}`)},
+	}
+	_, err := engineClient.Execute(context.Background(), &arkv1alpha1.ExecutionEngineRef{Name: "langchain"},
+		agentConfig, NewUserMessage("hi"), nil, nil, &mockRecorder{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support structured output")
+}
+
+func TestProbeExecutionEngine(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "healthy", status: http.StatusOK},
+		{name: "health endpoint not implemented", status: http.StatusNotFound},
+		{name: "unhealthy", status: http.StatusServiceUnavailable, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, "/health", r.URL.Path)
+				assert.Equal(t, "secret", r.Header.Get("X-Engine-Token"))
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			headers := []arkv1prealpha1.Header{{Name: "X-Engine-Token", Value: arkv1alpha1.HeaderValue{Value: "secret"}}}
+			err := ProbeExecutionEngine(context.Background(), nil, server.URL, headers, "default")
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
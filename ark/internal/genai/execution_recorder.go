@@ -86,7 +86,7 @@ func (r *ExecutionRecorder) ParticipantSelected(ctx context.Context, teamName, s
 		},
 		Type: "team_selector",
 	}
-	r.emitter.EmitEvent(ctx, corev1.EventTypeNormal, "ParticipantSelected", event)
+	r.emitter.EmitEvent(ctx, corev1.EventTypeNormal, ReasonParticipantSelected, event)
 }
 
 func (r *ExecutionRecorder) SelectorAgentResponse(ctx context.Context, teamName, agentName, selectedName, availableParticipants string) {
@@ -101,5 +101,5 @@ func (r *ExecutionRecorder) SelectorAgentResponse(ctx context.Context, teamName,
 		},
 		Type: "team_selector_response",
 	}
-	r.emitter.EmitEvent(ctx, corev1.EventTypeNormal, "SelectorAgentResponse", event)
+	r.emitter.EmitEvent(ctx, corev1.EventTypeNormal, ReasonSelectorAgentResponse, event)
 }
@@ -0,0 +1,251 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	defaultGuardrailMessage = "The request was blocked by a content policy"
+	moderationPrompt        = "You check content against a content policy. Respond only with JSON of the form " +
+		`{"violation": true|false, "reason": "<short explanation>"}.` + "\n\nContent policy:\n"
+)
+
+// GuardrailViolation describes content that failed a guardrail check
+type GuardrailViolation struct {
+	Guardrail string
+	Stage     arkv1alpha1.GuardrailStage
+	Action    arkv1alpha1.GuardrailAction
+	Reason    string
+	Message   string
+}
+
+// GuardrailBlockedError is returned when content violates a guardrail with the block action
+type GuardrailBlockedError struct {
+	Violation GuardrailViolation
+}
+
+func (e *GuardrailBlockedError) Error() string {
+	return fmt.Sprintf("%s (guardrail %s, %s: %s)", e.Violation.Message, e.Violation.Guardrail, e.Violation.Stage, e.Violation.Reason)
+}
+
+// IsGuardrailBlocked returns true if err was caused by a blocking guardrail
+func IsGuardrailBlocked(err error) bool {
+	var blocked *GuardrailBlockedError
+	return errors.As(err, &blocked)
+}
+
+// Guardrail checks content against the rules of a Guardrail resource
+type Guardrail struct {
+	Name            string
+	Spec            arkv1alpha1.GuardrailSpec
+	patterns        []*regexp.Regexp
+	expressions     []cel.Program
+	moderationModel *Model
+}
+
+// CompileGuardrailExpression compiles a CEL guardrail expression over the content and stage variables
+func CompileGuardrailExpression(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("content", cel.StringType),
+		cel.Variable("stage", cel.StringType),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// NewGuardrail compiles the rules of a Guardrail resource
+func NewGuardrail(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Guardrail, modelRecorder telemetry.ModelRecorder) (*Guardrail, error) {
+	guardrail := &Guardrail{Name: crd.Name, Spec: crd.Spec}
+
+	for _, pattern := range crd.Spec.Patterns {
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %s has invalid pattern %q: %w", crd.Name, pattern, err)
+		}
+		guardrail.patterns = append(guardrail.patterns, compiled)
+	}
+
+	for _, expression := range crd.Spec.Expressions {
+		program, err := CompileGuardrailExpression(expression)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %s has invalid expression %q: %w", crd.Name, expression, err)
+		}
+		guardrail.expressions = append(guardrail.expressions, program)
+	}
+
+	if crd.Spec.ModerationModelRef != nil {
+		model, err := LoadModel(ctx, k8sClient, crd.Spec.ModerationModelRef, crd.Namespace, modelRecorder)
+		if err != nil {
+			return nil, fmt.Errorf("guardrail %s failed to load moderation model: %w", crd.Name, err)
+		}
+		guardrail.moderationModel = model
+	}
+
+	return guardrail, nil
+}
+
+// LoadGuardrails loads and compiles the referenced guardrails
+func LoadGuardrails(ctx context.Context, k8sClient client.Client, refs []arkv1alpha1.GuardrailRef, namespace string, modelRecorder telemetry.ModelRecorder) ([]*Guardrail, error) {
+	guardrails := make([]*Guardrail, 0, len(refs))
+	for _, ref := range refs {
+		var crd arkv1alpha1.Guardrail
+		if err := k8sClient.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &crd); err != nil {
+			return nil, fmt.Errorf("failed to get guardrail %s/%s: %w", namespace, ref.Name, err)
+		}
+		guardrail, err := NewGuardrail(ctx, k8sClient, &crd, modelRecorder)
+		if err != nil {
+			return nil, err
+		}
+		guardrails = append(guardrails, guardrail)
+	}
+	return guardrails, nil
+}
+
+// Check returns a violation if content breaks one of the guardrail rules at stage, or nil if it passes
+func (g *Guardrail) Check(ctx context.Context, stage arkv1alpha1.GuardrailStage, content string) (*GuardrailViolation, error) {
+	if !g.Spec.AppliesTo(stage) {
+		return nil, nil
+	}
+
+	reason, err := g.findViolation(ctx, stage, content)
+	if err != nil || reason == "" {
+		return nil, err
+	}
+
+	violation := &GuardrailViolation{
+		Guardrail: g.Name,
+		Stage:     stage,
+		Action:    g.Spec.Action,
+		Reason:    reason,
+		Message:   g.Spec.Message,
+	}
+	if violation.Action == "" {
+		violation.Action = arkv1alpha1.GuardrailActionBlock
+	}
+	if violation.Message == "" {
+		violation.Message = defaultGuardrailMessage
+	}
+	return violation, nil
+}
+
+func (g *Guardrail) findViolation(ctx context.Context, stage arkv1alpha1.GuardrailStage, content string) (string, error) {
+	for _, pattern := range g.patterns {
+		if pattern.MatchString(content) {
+			return fmt.Sprintf("matched pattern %q", pattern.String()), nil
+		}
+	}
+
+	lower := strings.ToLower(content)
+	for _, keyword := range g.Spec.Keywords {
+		if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+			return fmt.Sprintf("contains keyword %q", keyword), nil
+		}
+	}
+
+	for i, program := range g.expressions {
+		result, _, err := program.Eval(map[string]any{"content": content, "stage": string(stage)})
+		if err != nil {
+			return "", fmt.Errorf("guardrail %s expression %q failed: %w", g.Name, g.Spec.Expressions[i], err)
+		}
+		if matched, ok := result.Value().(bool); ok && matched {
+			return fmt.Sprintf("matched expression %q", g.Spec.Expressions[i]), nil
+		}
+	}
+
+	if g.moderationModel != nil {
+		return g.moderate(ctx, content)
+	}
+
+	return "", nil
+}
+
+func (g *Guardrail) moderate(ctx context.Context, content string) (string, error) {
+	response, err := g.moderationModel.ChatCompletion(ctx, []Message{
+		NewSystemMessage(moderationPrompt + g.Spec.Policy),
+		NewUserMessage(content),
+	}, nil, 1)
+	if err != nil {
+		return "", fmt.Errorf("guardrail %s moderation failed: %w", g.Name, err)
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return "", fmt.Errorf("guardrail %s moderation model returned no choices", g.Name)
+	}
+
+	var result struct {
+		Violation bool   `json:"violation"`
+		Reason    string `json:"reason"`
+	}
+	if err := unmarshalModelJSON(response.Choices[0].Message.Content, &result); err != nil {
+		return "", fmt.Errorf("guardrail %s moderation model returned invalid JSON: %w", g.Name, err)
+	}
+	if !result.Violation {
+		return "", nil
+	}
+	if result.Reason == "" {
+		result.Reason = "violates content policy"
+	}
+	return "moderation: " + result.Reason, nil
+}
+
+// RunGuardrails checks content against each guardrail and emits an event for every violation.
+// Returns a GuardrailBlockedError for the first violation with the block action.
+// Failing checks also block, so content is never let through unchecked.
+func RunGuardrails(ctx context.Context, guardrails []*Guardrail, stage arkv1alpha1.GuardrailStage, content string, recorder EventEmitter) error {
+	for _, guardrail := range guardrails {
+		violation, err := guardrail.Check(ctx, stage, content)
+		if err != nil {
+			violation = &GuardrailViolation{
+				Guardrail: guardrail.Name,
+				Stage:     stage,
+				Action:    arkv1alpha1.GuardrailActionBlock,
+				Reason:    err.Error(),
+				Message:   "The content policy check failed",
+			}
+		}
+		if violation == nil {
+			continue
+		}
+
+		reason := ReasonGuardrailViolation
+		if violation.Action == arkv1alpha1.GuardrailActionBlock {
+			reason = ReasonGuardrailBlocked
+		}
+		recorder.EmitEvent(ctx, corev1.EventTypeWarning, reason, BaseEvent{
+			Name: guardrail.Name,
+			Metadata: map[string]string{
+				"stage":   string(violation.Stage),
+				"action":  string(violation.Action),
+				"reason":  violation.Reason,
+				"queryId": getQueryID(ctx),
+			},
+		})
+
+		if violation.Action == arkv1alpha1.GuardrailActionBlock {
+			return &GuardrailBlockedError{Violation: *violation}
+		}
+	}
+	return nil
+}
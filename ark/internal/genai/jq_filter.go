@@ -59,7 +59,13 @@ func (f *FilteredToolExecutor) applyJQFilter(content, jqExpr string) (string, er
 		return content, nil
 	}
 
-	iter := query.Run(data)
+	return runJQQuery(context.Background(), query, data)
+}
+
+// runJQQuery applies a jq query to data and returns the result as JSON, or an array of the results
+// if the query produces more than one
+func runJQQuery(ctx context.Context, query *gojq.Query, data any) (string, error) {
+	iter := query.RunWithContext(ctx, data)
 	var results []interface{}
 	for {
 		v, ok := iter.Next()
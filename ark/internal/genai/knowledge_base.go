@@ -0,0 +1,369 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	// CollectionsEndpoint is the vector API of the memory service that stores knowledge bases
+	CollectionsEndpoint = "/collections/%s"
+
+	defaultChunkSize    = 1000
+	defaultChunkOverlap = 200
+	defaultTopK         = 4
+	// embeddingBatchSize is the number of chunks embedded and stored per request
+	embeddingBatchSize = 100
+)
+
+// chunkSeparators are the boundaries chunks end at, preferred in order
+var chunkSeparators = []string{"\n\n", "\n", ". ", " "}
+
+// KnowledgeBaseChunk is a chunk of a document with its embedding
+type KnowledgeBaseChunk struct {
+	ID        string    `json:"id"`
+	Document  string    `json:"document"`
+	Content   string    `json:"content"`
+	Embedding []float64 `json:"embedding"`
+}
+
+// KnowledgeBaseMatch is a chunk returned by a search, with the cosine similarity of its embedding
+// to the embedding of the search
+type KnowledgeBaseMatch struct {
+	ID       string  `json:"id"`
+	Document string  `json:"document"`
+	Content  string  `json:"content"`
+	Score    float64 `json:"score"`
+}
+
+// KnowledgeBaseStore stores the chunks of knowledge bases in the collections of a memory service.
+// Requests are authenticated with the controller service account token.
+type KnowledgeBaseStore struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// NewKnowledgeBaseStore returns the store of the memory referenced by a knowledge base
+func NewKnowledgeBaseStore(ctx context.Context, k8sClient client.Client, memoryRef arkv1alpha1.MemoryRef, namespace string) (*KnowledgeBaseStore, error) {
+	memory, err := getMemoryResource(ctx, k8sClient, memoryRef.Name, resolveNamespace(memoryRef.Namespace, namespace))
+	if err != nil {
+		return nil, err
+	}
+	if memory.Status.LastResolvedAddress == nil || *memory.Status.LastResolvedAddress == "" {
+		return nil, fmt.Errorf("memory %s has no lastResolvedAddress in status", memoryRef.Name)
+	}
+
+	httpClient := common.NewHTTPClientWithLogging(ctx)
+	httpClient.Timeout = getMemoryTimeout()
+	return &KnowledgeBaseStore{
+		httpClient: httpClient,
+		baseURL:    strings.TrimSuffix(*memory.Status.LastResolvedAddress, "/"),
+	}, nil
+}
+
+// KnowledgeBaseCollection returns the collection an indexing of a knowledge base started at a time
+// writes to. Each indexing writes to a new collection, so searches use the previous one until the
+// indexing completes
+func KnowledgeBaseCollection(kb *arkv1alpha1.KnowledgeBase, indexedAt time.Time) string {
+	return fmt.Sprintf("%s.%s.%d", kb.Namespace, kb.Name, indexedAt.Unix())
+}
+
+// AddChunks adds chunks to a collection, creating it if needed
+func (s *KnowledgeBaseStore) AddChunks(ctx context.Context, collection string, chunks []KnowledgeBaseChunk) error {
+	return s.post(ctx, collection, "/chunks", map[string]any{"chunks": chunks}, nil)
+}
+
+// Search returns the topK chunks of a collection most similar to an embedding
+func (s *KnowledgeBaseStore) Search(ctx context.Context, collection string, embedding []float64, topK int) ([]KnowledgeBaseMatch, error) {
+	var response struct {
+		Chunks []KnowledgeBaseMatch `json:"chunks"`
+	}
+	if err := s.post(ctx, collection, "/search", map[string]any{"embedding": embedding, "top_k": topK}, &response); err != nil {
+		return nil, err
+	}
+	return response.Chunks, nil
+}
+
+// DeleteCollection deletes a collection. Deleting a missing collection succeeds
+func (s *KnowledgeBaseStore) DeleteCollection(ctx context.Context, collection string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.collectionURL(collection), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	if err := setServiceAccountAuthorization(req); err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to delete collection %s: HTTP status %d", collection, resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *KnowledgeBaseStore) post(ctx context.Context, collection, path string, body, response any) error {
+	reqBody, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to serialize request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.collectionURL(collection)+path, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("User-Agent", UserAgent)
+	if err := setServiceAccountAuthorization(req); err != nil {
+		return err
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("collection %s: HTTP status %d: %s", collection, resp.StatusCode, strings.TrimSpace(string(message)))
+	}
+	if response == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (s *KnowledgeBaseStore) collectionURL(collection string) string {
+	return s.baseURL + fmt.Sprintf(CollectionsEndpoint, url.PathEscape(collection))
+}
+
+// KnowledgeBaseChunkSize returns the chunk size and overlap of a knowledge base
+func KnowledgeBaseChunkSize(kb *arkv1alpha1.KnowledgeBase) (int, int) {
+	size, overlap := defaultChunkSize, defaultChunkOverlap
+	if chunking := kb.Spec.Chunking; chunking != nil {
+		if chunking.Size > 0 {
+			size = chunking.Size
+		}
+		overlap = chunking.Overlap
+	}
+	if overlap >= size {
+		overlap = 0
+	}
+	return size, overlap
+}
+
+// ChunkText splits text into chunks of at most size characters, consecutive chunks sharing about
+// overlap characters. Chunks end at a paragraph, line, sentence or word boundary when one is in the
+// second half of the chunk
+func ChunkText(text string, size, overlap int) []string {
+	runes := []rune(strings.TrimSpace(text))
+	var chunks []string
+	for start := 0; start < len(runes); {
+		end := start + size
+		if end >= len(runes) {
+			chunks = appendChunk(chunks, runes[start:])
+			break
+		}
+		end = chunkBoundary(runes, start+size/2, end)
+		chunks = appendChunk(chunks, runes[start:end])
+
+		next := end - overlap
+		if next <= start {
+			next = end
+		}
+		start = next
+	}
+	return chunks
+}
+
+func appendChunk(chunks []string, runes []rune) []string {
+	if chunk := strings.TrimSpace(string(runes)); chunk != "" {
+		return append(chunks, chunk)
+	}
+	return chunks
+}
+
+// chunkBoundary returns the end of the last separator in runes[low:high], or high without one
+func chunkBoundary(runes []rune, low, high int) int {
+	for _, separator := range chunkSeparators {
+		sep := []rune(separator)
+		for i := high - len(sep); i >= low; i-- {
+			if string(runes[i:i+len(sep)]) == separator {
+				return i + len(sep)
+			}
+		}
+	}
+	return high
+}
+
+// KnowledgeBaseEmbedder embeds the chunks of knowledge bases, which models of type embedding do
+type KnowledgeBaseEmbedder interface {
+	Embed(ctx context.Context, inputs []string) (*Embeddings, error)
+}
+
+// IndexKnowledgeBaseDocuments chunks documents, embeds the chunks in batches and adds them to a
+// collection. It returns the number of chunks added
+func IndexKnowledgeBaseDocuments(ctx context.Context, embedder KnowledgeBaseEmbedder, store *KnowledgeBaseStore, collection string, documents []KnowledgeBaseDocument, size, overlap int) (int, error) {
+	var chunks []KnowledgeBaseChunk
+	for _, document := range documents {
+		for i, content := range ChunkText(document.Content, size, overlap) {
+			chunks = append(chunks, KnowledgeBaseChunk{ID: fmt.Sprintf("%s#%d", document.ID, i), Document: document.ID, Content: content})
+		}
+	}
+	if len(chunks) == 0 {
+		return 0, fmt.Errorf("the sources have no content")
+	}
+
+	for start := 0; start < len(chunks); start += embeddingBatchSize {
+		batch := chunks[start:min(start+embeddingBatchSize, len(chunks))]
+		inputs := make([]string, len(batch))
+		for i, chunk := range batch {
+			inputs[i] = chunk.Content
+		}
+		embeddings, err := embedder.Embed(ctx, inputs)
+		if err != nil {
+			return 0, fmt.Errorf("failed to embed chunks: %w", err)
+		}
+		for i := range batch {
+			batch[i].Embedding = embeddings.Vectors[i]
+		}
+		if err := store.AddChunks(ctx, collection, batch); err != nil {
+			return 0, err
+		}
+	}
+	return len(chunks), nil
+}
+
+// KnowledgeBaseToolName returns the name of the retrieval tool of a knowledge base
+func KnowledgeBaseToolName(name string) string {
+	return "search_" + strings.ReplaceAll(name, ".", "_")
+}
+
+func knowledgeBaseToolDefinition(kb *arkv1alpha1.KnowledgeBase) ToolDefinition {
+	description := kb.Spec.Description
+	if description == "" {
+		description = fmt.Sprintf("Search the knowledge base %s for the passages most relevant to a query", kb.Name)
+	}
+	return ToolDefinition{
+		Name:        KnowledgeBaseToolName(kb.Name),
+		Description: description,
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"query": map[string]any{
+					"type":        "string",
+					"description": "What to search for, phrased like the passages that answer it",
+				},
+			},
+			"required": []string{"query"},
+		},
+	}
+}
+
+// registerKnowledgeBases registers a retrieval tool for each knowledge base of an agent
+func (r *ToolRegistry) registerKnowledgeBases(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent, telemetryProvider telemetry.Provider) error {
+	for _, ref := range agent.Spec.KnowledgeBases {
+		var kb arkv1alpha1.KnowledgeBase
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: ref.Name, Namespace: agent.Namespace}, &kb); err != nil {
+			return fmt.Errorf("failed to get knowledge base %s/%s: %w", agent.Namespace, ref.Name, err)
+		}
+
+		definition := knowledgeBaseToolDefinition(&kb)
+		if _, exists := r.tools[definition.Name]; exists {
+			return fmt.Errorf("knowledge base %s: tool %s is already registered", kb.Name, definition.Name)
+		}
+		r.RegisterTool(definition, &KnowledgeBaseExecutor{
+			Client:        k8sClient,
+			Name:          kb.Name,
+			Namespace:     kb.Namespace,
+			ModelRecorder: telemetryProvider.ModelRecorder(),
+		})
+	}
+	return nil
+}
+
+// KnowledgeBaseExecutor searches a knowledge base with the embedding of the query of a tool call
+// and returns the most similar chunks
+type KnowledgeBaseExecutor struct {
+	Client        client.Client
+	Name          string
+	Namespace     string
+	ModelRecorder telemetry.ModelRecorder
+}
+
+func (e *KnowledgeBaseExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal([]byte(call.Function.Arguments), &arguments); err != nil || strings.TrimSpace(arguments.Query) == "" {
+		err := fmt.Errorf("a query is required to search the knowledge base %s", e.Name)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	matches, err := e.search(ctx, arguments.Query)
+	if err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	content, err := json.Marshal(matches)
+	if err != nil {
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: string(content)}, nil
+}
+
+func (e *KnowledgeBaseExecutor) search(ctx context.Context, query string) ([]KnowledgeBaseMatch, error) {
+	var kb arkv1alpha1.KnowledgeBase
+	if err := e.Client.Get(ctx, client.ObjectKey{Name: e.Name, Namespace: e.Namespace}, &kb); err != nil {
+		return nil, fmt.Errorf("failed to get knowledge base %s: %w", e.Name, err)
+	}
+	if kb.Status.Collection == "" {
+		return nil, fmt.Errorf("knowledge base %s has not been indexed yet", e.Name)
+	}
+
+	model, err := LoadModel(ctx, e.Client, &kb.Spec.EmbeddingModelRef, kb.Namespace, e.ModelRecorder)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load embedding model of knowledge base %s: %w", e.Name, err)
+	}
+	embeddings, err := model.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	store, err := NewKnowledgeBaseStore(ctx, e.Client, kb.Spec.Memory, kb.Namespace)
+	if err != nil {
+		return nil, err
+	}
+	topK := kb.Spec.TopK
+	if topK == 0 {
+		topK = defaultTopK
+	}
+	return store.Search(ctx, kb.Status.Collection, embeddings.Vectors[0], topK)
+}
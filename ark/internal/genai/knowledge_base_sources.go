@@ -0,0 +1,291 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	v4 "github.com/aws/aws-sdk-go-v2/aws/signer/v4"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/smithy-go/encoding/httpbinding"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	// maxKnowledgeBaseDocumentSize is the largest document a source can provide
+	maxKnowledgeBaseDocumentSize = 10 * 1024 * 1024
+	// maxKnowledgeBaseDocuments is the most documents the sources of a knowledge base can provide
+	maxKnowledgeBaseDocuments = 1000
+
+	knowledgeBaseFetchTimeout = 60 * time.Second
+)
+
+// emptyPayloadHash is the SHA-256 of an empty body, which S3 requests without a body are signed with
+var emptyPayloadHash = sha256Hex(nil)
+
+// KnowledgeBaseDocument is a document of a knowledge base source
+type KnowledgeBaseDocument struct {
+	ID      string
+	Content string
+}
+
+// LoadKnowledgeBaseDocuments reads the documents of all sources of a knowledge base
+func LoadKnowledgeBaseDocuments(ctx context.Context, k8sClient client.Client, kb *arkv1alpha1.KnowledgeBase) ([]KnowledgeBaseDocument, error) {
+	var documents []KnowledgeBaseDocument
+	for i, source := range kb.Spec.Sources {
+		var loaded []KnowledgeBaseDocument
+		var err error
+		switch {
+		case source.ConfigMap != nil:
+			loaded, err = loadConfigMapDocuments(ctx, k8sClient, source.ConfigMap, kb.Namespace)
+		case source.URL != "":
+			loaded, err = loadURLDocument(ctx, source.URL)
+		case source.S3 != nil:
+			loaded, err = loadS3Documents(ctx, k8sClient, source.S3, kb.Namespace)
+		default:
+			err = fmt.Errorf("no configMap, url or s3 set")
+		}
+		if err != nil {
+			return nil, fmt.Errorf("sources[%d]: %w", i, err)
+		}
+
+		documents = append(documents, loaded...)
+		if len(documents) > maxKnowledgeBaseDocuments {
+			return nil, fmt.Errorf("the sources have more than %d documents", maxKnowledgeBaseDocuments)
+		}
+	}
+	return documents, nil
+}
+
+func loadConfigMapDocuments(ctx context.Context, k8sClient client.Client, source *arkv1alpha1.KnowledgeBaseConfigMapSource, namespace string) ([]KnowledgeBaseDocument, error) {
+	var configMap corev1.ConfigMap
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: source.Name, Namespace: namespace}, &configMap); err != nil {
+		return nil, fmt.Errorf("failed to get configmap %s: %w", source.Name, err)
+	}
+
+	keys := source.Keys
+	if len(keys) == 0 {
+		for key := range configMap.Data {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+	}
+
+	documents := make([]KnowledgeBaseDocument, 0, len(keys))
+	for _, key := range keys {
+		content, ok := configMap.Data[key]
+		if !ok {
+			return nil, fmt.Errorf("key %s not found in configmap %s", key, source.Name)
+		}
+		documents = append(documents, KnowledgeBaseDocument{ID: fmt.Sprintf("configmap/%s/%s", source.Name, key), Content: content})
+	}
+	return documents, nil
+}
+
+func loadURLDocument(ctx context.Context, documentURL string) ([]KnowledgeBaseDocument, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, documentURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	content, err := fetchKnowledgeBaseDocument(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch %s: %w", documentURL, err)
+	}
+	return []KnowledgeBaseDocument{{ID: documentURL, Content: content}}, nil
+}
+
+func fetchKnowledgeBaseDocument(ctx context.Context, req *http.Request) (string, error) {
+	httpClient := common.NewHTTPClientWithLogging(ctx)
+	httpClient.Timeout = knowledgeBaseFetchTimeout
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxKnowledgeBaseDocumentSize+1))
+	if err != nil {
+		return "", err
+	}
+	if len(body) > maxKnowledgeBaseDocumentSize {
+		return "", fmt.Errorf("document is larger than %d bytes", maxKnowledgeBaseDocumentSize)
+	}
+	return string(body), nil
+}
+
+// s3Client lists and reads objects with signed REST requests
+type s3Client struct {
+	source      *arkv1alpha1.KnowledgeBaseS3Source
+	credentials aws.CredentialsProvider
+	signer      *v4.Signer
+}
+
+type s3ListBucketResult struct {
+	Contents []struct {
+		Key string `xml:"Key"`
+	} `xml:"Contents"`
+	IsTruncated           bool   `xml:"IsTruncated"`
+	NextContinuationToken string `xml:"NextContinuationToken"`
+}
+
+func loadS3Documents(ctx context.Context, k8sClient client.Client, source *arkv1alpha1.KnowledgeBaseS3Source, namespace string) ([]KnowledgeBaseDocument, error) {
+	s3, err := newS3Client(ctx, k8sClient, source, namespace)
+	if err != nil {
+		return nil, err
+	}
+	keys, err := s3.listKeys(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	documents := make([]KnowledgeBaseDocument, 0, len(keys))
+	for _, key := range keys {
+		content, err := s3.getObject(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, KnowledgeBaseDocument{ID: fmt.Sprintf("s3://%s/%s", source.Bucket, key), Content: content})
+	}
+	return documents, nil
+}
+
+// newS3Client loads the credentials of an S3 source. Without static keys, the default credential
+// chain provides the credentials, including IRSA web identity tokens and EKS Pod Identity.
+func newS3Client(ctx context.Context, k8sClient client.Client, source *arkv1alpha1.KnowledgeBaseS3Source, namespace string) (*s3Client, error) {
+	opts := []func(*config.LoadOptions) error{config.WithRegion(source.Region)}
+	if source.AccessKeyID != nil && source.SecretAccessKey != nil {
+		resolver := common.NewValueSourceResolver(k8sClient)
+		accessKeyID, err := resolver.ResolveValueSource(ctx, *source.AccessKeyID, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve accessKeyId: %w", err)
+		}
+		secretAccessKey, err := resolver.ResolveValueSource(ctx, *source.SecretAccessKey, namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve secretAccessKey: %w", err)
+		}
+		opts = append(opts, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKeyID, secretAccessKey, "")))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+	return &s3Client{
+		source:      source,
+		credentials: cfg.Credentials,
+		signer:      v4.NewSigner(func(options *v4.SignerOptions) { options.DisableURIPathEscaping = true }),
+	}, nil
+}
+
+// listKeys returns the keys of the objects under the prefix of the source, without folder markers
+func (s *s3Client) listKeys(ctx context.Context) ([]string, error) {
+	var keys []string
+	continuationToken := ""
+	for {
+		query := url.Values{"list-type": {"2"}}
+		if s.source.Prefix != "" {
+			query.Set("prefix", s.source.Prefix)
+		}
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		req, err := s.newRequest(ctx, "", query)
+		if err != nil {
+			return nil, err
+		}
+		body, err := fetchKnowledgeBaseDocument(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list bucket %s: %w", s.source.Bucket, err)
+		}
+
+		var result s3ListBucketResult
+		if err := xml.Unmarshal([]byte(body), &result); err != nil {
+			return nil, fmt.Errorf("failed to parse the objects of bucket %s: %w", s.source.Bucket, err)
+		}
+		for _, object := range result.Contents {
+			if !strings.HasSuffix(object.Key, "/") {
+				keys = append(keys, object.Key)
+			}
+		}
+		if len(keys) > maxKnowledgeBaseDocuments {
+			return nil, fmt.Errorf("bucket %s has more than %d objects under prefix %q", s.source.Bucket, maxKnowledgeBaseDocuments, s.source.Prefix)
+		}
+		if !result.IsTruncated || result.NextContinuationToken == "" {
+			return keys, nil
+		}
+		continuationToken = result.NextContinuationToken
+	}
+}
+
+func (s *s3Client) getObject(ctx context.Context, key string) (string, error) {
+	req, err := s.newRequest(ctx, key, nil)
+	if err != nil {
+		return "", err
+	}
+	content, err := fetchKnowledgeBaseDocument(ctx, req)
+	if err != nil {
+		return "", fmt.Errorf("failed to get object %s of bucket %s: %w", key, s.source.Bucket, err)
+	}
+	return content, nil
+}
+
+// newRequest returns a signed GET request of an object, or of the bucket without a key. Custom
+// endpoints are addressed with path style requests and AWS with virtual hosted style requests
+func (s *s3Client) newRequest(ctx context.Context, key string, query url.Values) (*http.Request, error) {
+	var requestURL *url.URL
+	var err error
+	path := "/" + key
+	if s.source.Endpoint != "" {
+		requestURL, err = url.Parse(strings.TrimSuffix(s.source.Endpoint, "/"))
+		path = strings.TrimSuffix("/"+s.source.Bucket+path, "/")
+	} else {
+		requestURL, err = url.Parse(fmt.Sprintf("https://%s.s3.%s.amazonaws.com", s.source.Bucket, s.source.Region))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid S3 endpoint: %w", err)
+	}
+	requestURL.Path = path
+	requestURL.RawPath = httpbinding.EscapePath(path, false)
+	requestURL.RawQuery = strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("X-Amz-Content-Sha256", emptyPayloadHash)
+
+	creds, err := s.credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve AWS credentials: %w", err)
+	}
+	if err := s.signer.SignHTTP(ctx, creds, req, emptyPayloadHash, "s3", s.source.Region, time.Now()); err != nil {
+		return nil, fmt.Errorf("failed to sign request: %w", err)
+	}
+	return req, nil
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,106 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestLoadKnowledgeBaseDocuments(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("Fetched guide"))
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "docs", Namespace: "default"},
+		Data:       map[string]string{"pricing.md": "Pricing", "faq.md": "FAQ"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	kb := &arkv1alpha1.KnowledgeBase{
+		ObjectMeta: metav1.ObjectMeta{Name: "product-docs", Namespace: "default"},
+		Spec: arkv1alpha1.KnowledgeBaseSpec{Sources: []arkv1alpha1.KnowledgeBaseSource{
+			{ConfigMap: &arkv1alpha1.KnowledgeBaseConfigMapSource{Name: "docs"}},
+			{URL: server.URL + "/guide.md"},
+		}},
+	}
+	documents, err := LoadKnowledgeBaseDocuments(context.Background(), k8sClient, kb)
+	require.NoError(t, err)
+	assert.Equal(t, []KnowledgeBaseDocument{
+		{ID: "configmap/docs/faq.md", Content: "FAQ"},
+		{ID: "configmap/docs/pricing.md", Content: "Pricing"},
+		{ID: server.URL + "/guide.md", Content: "Fetched guide"},
+	}, documents)
+
+	kb.Spec.Sources = []arkv1alpha1.KnowledgeBaseSource{{ConfigMap: &arkv1alpha1.KnowledgeBaseConfigMapSource{Name: "docs", Keys: []string{"terms.md"}}}}
+	_, err = LoadKnowledgeBaseDocuments(context.Background(), k8sClient, kb)
+	assert.ErrorContains(t, err, "sources[0]: key terms.md not found in configmap docs")
+
+	kb.Spec.Sources = []arkv1alpha1.KnowledgeBaseSource{{URL: server.URL + "/missing"}}
+	_, err = LoadKnowledgeBaseDocuments(context.Background(), k8sClient, kb)
+	assert.ErrorContains(t, err, "HTTP status 404")
+}
+
+func TestLoadS3Documents(t *testing.T) {
+	var authorizations, paths []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorizations = append(authorizations, r.Header.Get("Authorization"))
+		paths = append(paths, r.URL.EscapedPath())
+		switch {
+		case r.URL.Path == "/manuals" && r.URL.Query().Get("continuation-token") == "":
+			assert.Equal(t, "guides/", r.URL.Query().Get("prefix"))
+			_, _ = fmt.Fprint(w, `<ListBucketResult><Contents><Key>guides/</Key></Contents><Contents><Key>guides/setup guide.md</Key></Contents><IsTruncated>true</IsTruncated><NextContinuationToken>next</NextContinuationToken></ListBucketResult>`)
+		case r.URL.Path == "/manuals":
+			_, _ = fmt.Fprint(w, `<ListBucketResult><Contents><Key>guides/usage.md</Key></Contents><IsTruncated>false</IsTruncated></ListBucketResult>`)
+		case strings.HasPrefix(r.URL.Path, "/manuals/guides/"):
+			_, _ = fmt.Fprint(w, "Content of "+strings.TrimPrefix(r.URL.Path, "/manuals/"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	source := &arkv1alpha1.KnowledgeBaseS3Source{
+		Bucket:          "manuals",
+		Prefix:          "guides/",
+		Region:          "us-east-1",
+		Endpoint:        server.URL,
+		AccessKeyID:     &arkv1alpha1.ValueSource{Value: "AKIDEXAMPLE"},
+		SecretAccessKey: &arkv1alpha1.ValueSource{Value: "secret"},
+	}
+	documents, err := loadS3Documents(context.Background(), fake.NewClientBuilder().Build(), source, "default")
+	require.NoError(t, err)
+	assert.Equal(t, []KnowledgeBaseDocument{
+		{ID: "s3://manuals/guides/setup guide.md", Content: "Content of guides/setup guide.md"},
+		{ID: "s3://manuals/guides/usage.md", Content: "Content of guides/usage.md"},
+	}, documents)
+
+	assert.Contains(t, paths, "/manuals/guides/setup%20guide.md")
+	require.Len(t, authorizations, 4)
+	for _, authorization := range authorizations {
+		assert.True(t, strings.HasPrefix(authorization, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/"), authorization)
+		assert.Contains(t, authorization, "/us-east-1/s3/aws4_request")
+	}
+}
@@ -0,0 +1,183 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestChunkText(t *testing.T) {
+	assert.Empty(t, ChunkText("  \n ", 100, 10))
+	assert.Equal(t, []string{"short text"}, ChunkText(" short text ", 100, 10))
+
+	text := strings.Repeat("word ", 30) + "\n\n" + strings.Repeat("next ", 30)
+	chunks := ChunkText(text, 200, 20)
+	require.Len(t, chunks, 2)
+	assert.Equal(t, strings.TrimSpace(strings.Repeat("word ", 30)), chunks[0])
+	assert.True(t, strings.HasSuffix(chunks[1], "next"))
+
+	// Chunks without separators are cut at the size and share the overlap
+	chunks = ChunkText(strings.Repeat("a", 250), 100, 20)
+	require.Len(t, chunks, 3)
+	assert.Len(t, chunks[0], 100)
+	assert.Len(t, chunks[2], 90)
+}
+
+func TestKnowledgeBaseChunkSize(t *testing.T) {
+	size, overlap := KnowledgeBaseChunkSize(&arkv1alpha1.KnowledgeBase{})
+	assert.Equal(t, 1000, size)
+	assert.Equal(t, 200, overlap)
+
+	kb := &arkv1alpha1.KnowledgeBase{Spec: arkv1alpha1.KnowledgeBaseSpec{Chunking: &arkv1alpha1.KnowledgeBaseChunking{Size: 500, Overlap: 50}}}
+	size, overlap = KnowledgeBaseChunkSize(kb)
+	assert.Equal(t, 500, size)
+	assert.Equal(t, 50, overlap)
+}
+
+type fakeEmbedder struct {
+	calls int
+}
+
+func (f *fakeEmbedder) Embed(ctx context.Context, inputs []string) (*Embeddings, error) {
+	f.calls++
+	vectors := make([][]float64, len(inputs))
+	for i, input := range inputs {
+		vectors[i] = []float64{float64(len(input)), 1}
+	}
+	return &Embeddings{Vectors: vectors}, nil
+}
+
+// newTestKnowledgeBaseStore serves the collections API of the memory, recording the chunks added.
+// Requests without the service account token are rejected.
+func newTestKnowledgeBaseStore(t *testing.T, matches []KnowledgeBaseMatch) (*KnowledgeBaseStore, map[string][]KnowledgeBaseChunk, *httptest.Server) {
+	setTestServiceAccountToken(t, "controller-token")
+	collections := map[string][]KnowledgeBaseChunk{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer controller-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		parts := strings.Split(strings.TrimPrefix(r.URL.Path, "/collections/"), "/")
+		switch {
+		case r.Method == http.MethodPost && len(parts) == 2 && parts[1] == "chunks":
+			var body struct {
+				Chunks []KnowledgeBaseChunk `json:"chunks"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			collections[parts[0]] = append(collections[parts[0]], body.Chunks...)
+			_ = json.NewEncoder(w).Encode(map[string]int{"chunks": len(collections[parts[0]])})
+		case r.Method == http.MethodPost && len(parts) == 2 && parts[1] == "search":
+			var body struct {
+				TopK int `json:"top_k"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			_ = json.NewEncoder(w).Encode(map[string]any{"chunks": matches[:min(body.TopK, len(matches))]})
+		case r.Method == http.MethodDelete && len(parts) == 1:
+			if _, ok := collections[parts[0]]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			delete(collections, parts[0])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return &KnowledgeBaseStore{httpClient: server.Client(), baseURL: server.URL}, collections, server
+}
+
+func TestIndexKnowledgeBaseDocuments(t *testing.T) {
+	store, collections, _ := newTestKnowledgeBaseStore(t, nil)
+	embedder := &fakeEmbedder{}
+	documents := make([]KnowledgeBaseDocument, 0, 150)
+	for range 150 {
+		documents = append(documents, KnowledgeBaseDocument{ID: "configmap/docs/guide", Content: "a short document"})
+	}
+
+	chunks, err := IndexKnowledgeBaseDocuments(context.Background(), embedder, store, "default.docs.1", documents, 1000, 200)
+	require.NoError(t, err)
+	assert.Equal(t, 150, chunks)
+	assert.Equal(t, 2, embedder.calls)
+	require.Len(t, collections["default.docs.1"], 150)
+	assert.Equal(t, "configmap/docs/guide#0", collections["default.docs.1"][0].ID)
+	assert.Equal(t, []float64{16, 1}, collections["default.docs.1"][0].Embedding)
+
+	require.NoError(t, store.DeleteCollection(context.Background(), "default.docs.1"))
+	require.NoError(t, store.DeleteCollection(context.Background(), "default.docs.1"))
+
+	_, err = IndexKnowledgeBaseDocuments(context.Background(), embedder, store, "default.docs.2", []KnowledgeBaseDocument{{ID: "empty"}}, 1000, 200)
+	assert.ErrorContains(t, err, "no content")
+}
+
+func newKnowledgeBaseTestClient(t *testing.T, objects ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+}
+
+func TestRegisterKnowledgeBases(t *testing.T) {
+	kb := &arkv1alpha1.KnowledgeBase{
+		ObjectMeta: metav1.ObjectMeta{Name: "product-docs", Namespace: "default"},
+		Spec:       arkv1alpha1.KnowledgeBaseSpec{Description: "Search the product documentation"},
+	}
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: "support", Namespace: "default"},
+		Spec:       arkv1alpha1.AgentSpec{KnowledgeBases: []arkv1alpha1.KnowledgeBaseRef{{Name: "product-docs"}}},
+	}
+	k8sClient := newKnowledgeBaseTestClient(t, kb)
+
+	registry := NewToolRegistry(nil, noop.NewProvider().ToolRecorder())
+	require.NoError(t, registry.registerKnowledgeBases(context.Background(), k8sClient, agent, noop.NewProvider()))
+	definitions := registry.GetToolDefinitions()
+	require.Len(t, definitions, 1)
+	assert.Equal(t, "search_product-docs", definitions[0].Name)
+	assert.Equal(t, "Search the product documentation", definitions[0].Description)
+	assert.Equal(t, "knowledge-base", registry.GetToolType("search_product-docs"))
+
+	// A knowledge base that has not been indexed returns an error to the agent
+	result, err := registry.ExecuteTool(context.Background(), builtinToolCall("search_product-docs", map[string]string{"query": "pricing"}), &mockRecorder{})
+	assert.ErrorContains(t, err, "has not been indexed yet")
+	assert.Contains(t, result.Error, "has not been indexed yet")
+
+	assert.ErrorContains(t, registry.registerKnowledgeBases(context.Background(), k8sClient, agent, noop.NewProvider()), "already registered")
+
+	agent.Spec.KnowledgeBases = []arkv1alpha1.KnowledgeBaseRef{{Name: "missing"}}
+	assert.ErrorContains(t, NewToolRegistry(nil, noop.NewProvider().ToolRecorder()).registerKnowledgeBases(context.Background(), k8sClient, agent, noop.NewProvider()), "failed to get knowledge base default/missing")
+}
+
+func TestKnowledgeBaseStoreSearch(t *testing.T) {
+	matches := []KnowledgeBaseMatch{
+		{ID: "a#0", Document: "a", Content: "first", Score: 0.9},
+		{ID: "b#0", Document: "b", Content: "second", Score: 0.5},
+	}
+	store, _, server := newTestKnowledgeBaseStore(t, matches)
+
+	found, err := store.Search(context.Background(), "default.docs.1", []float64{1, 0}, 1)
+	require.NoError(t, err)
+	assert.Equal(t, matches[:1], found)
+
+	address := server.URL
+	memory := &arkv1alpha1.Memory{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+		Status:     arkv1alpha1.MemoryStatus{LastResolvedAddress: &address},
+	}
+	store, err = NewKnowledgeBaseStore(context.Background(), newKnowledgeBaseTestClient(t, memory), arkv1alpha1.MemoryRef{Name: "default"}, "default")
+	require.NoError(t, err)
+	assert.Equal(t, server.URL+"/collections/default.docs.1", store.collectionURL("default.docs.1"))
+}
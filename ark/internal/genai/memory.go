@@ -22,6 +22,8 @@ const (
 	MaxRetries            = 3
 	RetryDelay            = 100 * time.Millisecond
 	UserAgent             = "ark-memory-client/1.0"
+	// IdempotencyKeyHeader identifies an add messages request, so the memory ignores its retries
+	IdempotencyKeyHeader = "Idempotency-Key"
 )
 
 // getMemoryTimeout reads ARK_MEMORY_HTTP_TIMEOUT_SECONDS env var or returns default
@@ -53,6 +55,8 @@ type MessagesRequest struct {
 	SessionID string                                   `json:"session_id"`
 	QueryID   string                                   `json:"query_id"`
 	Messages  []openai.ChatCompletionMessageParamUnion `json:"messages"`
+	// Annotations of the last message, such as the provenance of a response
+	Annotations map[string]interface{} `json:"annotations,omitempty"`
 }
 
 type MessageRecord struct {
@@ -94,13 +98,22 @@ func NewMemoryForQuery(ctx context.Context, k8sClient client.Client, memoryRef *
 	var memoryName, memoryNamespace string
 
 	if memoryRef == nil {
-		// Try to load "default" memory from the same namespace
-		_, err := getMemoryResource(ctx, k8sClient, "default", namespace)
+		// Try to load the namespace default memory
+		defaultMemory := defaultMemoryName
+		if defaults, err := GetNamespaceDefaults(ctx, k8sClient, namespace); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to get namespace defaults, using the default memory", "namespace", namespace)
+		} else {
+			defaultMemory = defaults.Memory
+		}
+		_, err := getMemoryResource(ctx, k8sClient, defaultMemory, namespace)
 		if err != nil {
+			if defaultMemory != defaultMemoryName {
+				return nil, fmt.Errorf("default memory %s configured in %s: %w", defaultMemory, DefaultsConfigMapName, err)
+			}
 			// If default memory doesn't exist, use noop memory
 			return NewNoopMemory(), nil
 		}
-		memoryName, memoryNamespace = "default", namespace
+		memoryName, memoryNamespace = defaultMemory, namespace
 	} else {
 		memoryName = memoryRef.Name
 		memoryNamespace = resolveNamespace(memoryRef.Namespace, namespace)
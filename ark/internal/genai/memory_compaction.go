@@ -0,0 +1,166 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	CompactEndpoint = "/sessions/%s/compact"
+
+	memoryCompactionPrompt        = "Summarize the following conversation so the summary can replace it as context for later turns. Preserve facts, decisions, names, numbers and open questions. Respond with the summary only."
+	memoryCompactionSummaryPrefix = "Summary of the earlier conversation:\n"
+	// Rough number of characters per token, used to estimate the size of a session
+	charactersPerToken = 4
+)
+
+// MemoryCompactor is implemented by memories that can compact long sessions
+type MemoryCompactor interface {
+	// CompactMessages replaces the older messages of the session with a summary when the session
+	// exceeds the compaction limits of the memory, and returns the messages to use as history
+	CompactMessages(ctx context.Context, messages []Message, modelRecorder telemetry.ModelRecorder) ([]Message, error)
+}
+
+type compactRequest struct {
+	Count   int                                    `json:"count"`
+	Summary openai.ChatCompletionMessageParamUnion `json:"summary"`
+}
+
+// CompactMessages summarizes the messages before the most recent ones with the compaction model
+// and replaces them in the memory backend with the summary
+func (m *HTTPMemory) CompactMessages(ctx context.Context, messages []Message, modelRecorder telemetry.ModelRecorder) ([]Message, error) {
+	count := compactionCount(messages, m.compaction)
+	if count == 0 {
+		return messages, nil
+	}
+
+	tracker := NewOperationTracker(m.recorder, ctx, OperationMemoryCompaction, m.name, map[string]string{
+		"namespace": m.namespace,
+		"sessionId": m.sessionId,
+		"messages":  fmt.Sprintf("%d", count),
+	})
+
+	summary, err := m.summarizeMessages(ctx, messages[:count], modelRecorder)
+	if err != nil {
+		tracker.Fail(err)
+		return nil, err
+	}
+
+	if err := m.compactSession(ctx, count, summary); err != nil {
+		tracker.Fail(err)
+		return nil, err
+	}
+
+	tracker.Complete("compacted")
+	return append([]Message{summary}, messages[count:]...), nil
+}
+
+func (m *HTTPMemory) summarizeMessages(ctx context.Context, messages []Message, modelRecorder telemetry.ModelRecorder) (Message, error) {
+	modelRef := m.compaction.ModelRef
+	if modelRef == nil {
+		defaults, err := GetNamespaceDefaults(ctx, m.client, m.namespace)
+		if err != nil {
+			return Message{}, err
+		}
+		modelRef = &arkv1alpha1.AgentModelRef{Name: defaults.Model}
+	}
+	model, err := LoadModel(ctx, m.client, modelRef, m.namespace, modelRecorder)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to load compaction model: %w", err)
+	}
+
+	transcript, err := json.Marshal(toOpenAIMessages(messages))
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to serialize messages: %w", err)
+	}
+
+	response, err := model.ChatCompletion(ctx, []Message{
+		NewSystemMessage(memoryCompactionPrompt),
+		NewUserMessage(string(transcript)),
+	}, nil, 1)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to summarize messages: %w", err)
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return Message{}, fmt.Errorf("compaction model returned no choices")
+	}
+
+	return NewSystemMessage(memoryCompactionSummaryPrefix + response.Choices[0].Message.Content), nil
+}
+
+func (m *HTTPMemory) compactSession(ctx context.Context, count int, summary Message) error {
+	reqBody, err := json.Marshal(compactRequest{Count: count, Summary: openai.ChatCompletionMessageParamUnion(summary)})
+	if err != nil {
+		return fmt.Errorf("failed to serialize compaction request: %w", err)
+	}
+
+	requestURL := m.baseURL + fmt.Sprintf(CompactEndpoint, url.PathEscape(m.sessionId))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(reqBody))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	req.Header.Set("User-Agent", UserAgent)
+
+	resp, err := m.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("HTTP request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// compactionCount returns how many of the oldest messages to replace with a summary, or zero when
+// the session is within the compaction limits
+func compactionCount(messages []Message, compaction *arkv1alpha1.MemoryCompaction) int {
+	if compaction == nil {
+		return 0
+	}
+	exceedsMessages := compaction.MaxMessages > 0 && len(messages) > compaction.MaxMessages
+	exceedsTokens := compaction.MaxTokens > 0 && estimateTokens(messages) > compaction.MaxTokens
+	if !exceedsMessages && !exceedsTokens {
+		return 0
+	}
+
+	count := len(messages) - compaction.KeepRecent
+	// Tool results must follow the assistant message that called the tool, so they are kept together
+	for count > 0 && count < len(messages) && messages[count].OfTool != nil {
+		count--
+	}
+	// Replacing a single message with its summary would not shorten the session
+	if count < 2 {
+		return 0
+	}
+	return count
+}
+
+func estimateTokens(messages []Message) int {
+	data, err := json.Marshal(toOpenAIMessages(messages))
+	if err != nil {
+		return 0
+	}
+	return len(data) / charactersPerToken
+}
+
+func toOpenAIMessages(messages []Message) []openai.ChatCompletionMessageParamUnion {
+	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
+	for i, msg := range messages {
+		openaiMessages[i] = openai.ChatCompletionMessageParamUnion(msg)
+	}
+	return openaiMessages
+}
@@ -0,0 +1,57 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestCompactionCount(t *testing.T) {
+	messages := []Message{
+		NewUserMessage("one"),
+		NewAssistantMessage("two"),
+		NewUserMessage("three"),
+		NewAssistantMessage("four"),
+		Message(openai.ToolMessage("five", "call-1")),
+		NewAssistantMessage("six"),
+	}
+
+	assert.Equal(t, 0, compactionCount(messages, nil))
+	assert.Equal(t, 0, compactionCount(messages, &arkv1alpha1.MemoryCompaction{MaxMessages: 6, KeepRecent: 2}))
+	assert.Equal(t, 5, compactionCount(messages, &arkv1alpha1.MemoryCompaction{MaxMessages: 5, KeepRecent: 1}))
+	assert.Equal(t, 5, compactionCount(messages, &arkv1alpha1.MemoryCompaction{MaxTokens: 1, KeepRecent: 1}))
+
+	// The tool result stays with the assistant message that called the tool
+	assert.Equal(t, 3, compactionCount(messages, &arkv1alpha1.MemoryCompaction{MaxMessages: 5, KeepRecent: 2}))
+
+	// Nothing to gain from summarizing a single message
+	assert.Equal(t, 0, compactionCount(messages, &arkv1alpha1.MemoryCompaction{MaxMessages: 5, KeepRecent: 5}))
+}
+
+func TestCompactSession(t *testing.T) {
+	var received compactRequest
+	var path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	memory := &HTTPMemory{httpClient: server.Client(), baseURL: server.URL, sessionId: "team/session"}
+	require.NoError(t, memory.compactSession(context.Background(), 4, NewSystemMessage("summary")))
+
+	assert.Equal(t, "/sessions/team%2Fsession/compact", path)
+	assert.Equal(t, 4, received.Count)
+	require.NotNil(t, received.Summary.OfSystem)
+}
@@ -3,13 +3,17 @@ package genai
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
 	"strings"
+	"time"
 
 	"github.com/openai/openai-go"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -24,6 +28,9 @@ type HTTPMemory struct {
 	name       string
 	namespace  string
 	recorder   EventEmitter
+	compaction *arkv1alpha1.MemoryCompaction
+	maxRetries int
+	retryDelay time.Duration
 }
 
 // NewHTTPMemory creates a new HTTP-based memory implementation
@@ -61,6 +68,9 @@ func NewHTTPMemory(ctx context.Context, k8sClient client.Client, memoryName, nam
 		name:       memoryName,
 		namespace:  namespace,
 		recorder:   recorder,
+		compaction: memory.Spec.Compaction,
+		maxRetries: config.MaxRetries,
+		retryDelay: config.RetryDelay,
 	}, nil
 }
 
@@ -73,7 +83,7 @@ func (m *HTTPMemory) resolveAndUpdateAddress(ctx context.Context) error {
 
 	// Resolve the address using ValueSourceResolver
 	resolver := common.NewValueSourceResolver(m.client)
-	resolvedAddress, err := resolver.ResolveValueSource(ctx, memory.Spec.Address, m.namespace)
+	resolvedAddress, err := resolver.ResolveValueSource(ctx, memory.AddressSource(), m.namespace)
 	if err != nil {
 		return fmt.Errorf("failed to resolve memory address: %w", err)
 	}
@@ -99,7 +109,8 @@ func (m *HTTPMemory) resolveAndUpdateAddress(ctx context.Context) error {
 	return nil
 }
 
-// AddMessages stores messages to the memory backend
+// AddMessages stores messages to the memory backend. Requests that fail with a transport error,
+// 429 or 5xx are retried up to maxRetries times.
 func (m *HTTPMemory) AddMessages(ctx context.Context, queryID string, messages []Message) error {
 	if len(messages) == 0 {
 		return nil
@@ -110,7 +121,7 @@ func (m *HTTPMemory) AddMessages(ctx context.Context, queryID string, messages [
 		return err
 	}
 
-	tracker := NewOperationTracker(m.recorder, ctx, "MemoryAddMessages", m.name, map[string]string{
+	tracker := NewOperationTracker(m.recorder, ctx, OperationMemoryAddMessages, m.name, map[string]string{
 		"namespace": m.namespace,
 		"sessionId": m.sessionId,
 		"queryId":   queryID,
@@ -124,39 +135,78 @@ func (m *HTTPMemory) AddMessages(ctx context.Context, queryID string, messages [
 	}
 
 	reqBody, err := json.Marshal(MessagesRequest{
-		SessionID: m.sessionId,
-		QueryID:   queryID,
-		Messages:  openaiMessages,
+		SessionID:   m.sessionId,
+		QueryID:     queryID,
+		Messages:    openaiMessages,
+		Annotations: responseProvenanceAnnotations(openaiMessages),
 	})
 	if err != nil {
 		tracker.Fail(fmt.Errorf("failed to serialize messages: %w", err))
 		return fmt.Errorf("failed to serialize messages: %w", err)
 	}
 
+	// Retries send the same key, so messages stored by an attempt that timed out are not stored twice
+	idempotencyKey := rand.Text()
+	log := logf.FromContext(ctx)
+	for attempt := 0; ; attempt++ {
+		err = m.postMessages(ctx, reqBody, idempotencyKey)
+		var retryable *memoryRetryableError
+		if err == nil || !errors.As(err, &retryable) || attempt >= m.maxRetries || ctx.Err() != nil {
+			break
+		}
+
+		delay := m.retryDelay << attempt
+		log.Info("retrying memory request", "memory", m.name, "attempt", attempt+1, "delay", delay, "error", err)
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("memory request cancelled while retrying: %w", ctx.Err())
+			tracker.Fail(err)
+			return err
+		case <-time.After(delay):
+		}
+	}
+	if err != nil {
+		tracker.Fail(err)
+		return err
+	}
+
+	tracker.Complete("messages added")
+	return nil
+}
+
+// memoryRetryableError marks failures of a request that are retried: transport errors, timeouts,
+// 429 and 5xx responses
+type memoryRetryableError struct {
+	err error
+}
+
+func (e *memoryRetryableError) Error() string { return e.err.Error() }
+func (e *memoryRetryableError) Unwrap() error { return e.err }
+
+// postMessages sends one attempt of an add messages request
+func (m *HTTPMemory) postMessages(ctx context.Context, reqBody []byte, idempotencyKey string) error {
 	requestURL := fmt.Sprintf("%s%s", m.baseURL, MessagesEndpoint)
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost, requestURL, bytes.NewReader(reqBody))
 	if err != nil {
-		tracker.Fail(fmt.Errorf("failed to create request: %w", err))
 		return fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set("Content-Type", ContentTypeJSON)
 	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set(IdempotencyKeyHeader, idempotencyKey)
 
 	resp, err := m.httpClient.Do(req)
 	if err != nil {
-		tracker.Fail(fmt.Errorf("HTTP request failed: %w", err))
-		return fmt.Errorf("HTTP request failed: %w", err)
+		return &memoryRetryableError{fmt.Errorf("HTTP request failed: %w", err)}
 	}
 	defer func() { _ = resp.Body.Close() }()
 
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError {
+		return &memoryRetryableError{fmt.Errorf("HTTP status %d", resp.StatusCode)}
+	}
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		err := fmt.Errorf("HTTP status %d", resp.StatusCode)
-		tracker.Fail(err)
-		return err
+		return fmt.Errorf("HTTP status %d", resp.StatusCode)
 	}
-
-	tracker.Complete("messages added")
 	return nil
 }
 
@@ -167,7 +217,7 @@ func (m *HTTPMemory) GetMessages(ctx context.Context) ([]Message, error) {
 		return nil, err
 	}
 
-	tracker := NewOperationTracker(m.recorder, ctx, "MemoryGetMessages", m.name, map[string]string{
+	tracker := NewOperationTracker(m.recorder, ctx, OperationMemoryGetMessages, m.name, map[string]string{
 		"namespace": m.namespace,
 		"sessionId": m.sessionId,
 	})
@@ -1,10 +1,21 @@
 package genai
 
 import (
+	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
 func TestUnmarshalMessageRobust(t *testing.T) {
@@ -145,3 +156,55 @@ func TestUnmarshalMessageRobustFutureRoles(t *testing.T) {
 		})
 	}
 }
+
+func newTestHTTPMemory(t *testing.T, address string) MemoryInterface {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	memory := &arkv1alpha1.Memory{
+		ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+		Spec:       arkv1alpha1.MemorySpec{Address: arkv1alpha1.ValueSource{Value: address}},
+		Status:     arkv1alpha1.MemoryStatus{LastResolvedAddress: &address},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(memory).Build()
+	config := Config{MaxRetries: 2, RetryDelay: time.Millisecond, SessionId: "session"}
+	m, err := NewHTTPMemory(context.Background(), k8sClient, "default", "default", &mockRecorder{}, config)
+	require.NoError(t, err)
+	return m
+}
+
+func TestAddMessagesRetriesWithSameIdempotencyKey(t *testing.T) {
+	var keys []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get(IdempotencyKeyHeader))
+		if len(keys) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	m := newTestHTTPMemory(t, server.URL)
+	err := m.AddMessages(context.Background(), "query", []Message{NewUserMessage("hello")})
+
+	require.NoError(t, err)
+	require.Len(t, keys, 3)
+	assert.NotEmpty(t, keys[0])
+	assert.Equal(t, keys[0], keys[1])
+	assert.Equal(t, keys[0], keys[2])
+}
+
+func TestAddMessagesDoesNotRetryClientErrors(t *testing.T) {
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	m := newTestHTTPMemory(t, server.URL)
+	err := m.AddMessages(context.Background(), "query", []Message{NewUserMessage("hello")})
+
+	require.Error(t, err)
+	assert.Equal(t, 1, requests)
+}
@@ -2,7 +2,12 @@
 
 package genai
 
-import "github.com/openai/openai-go"
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/openai/openai-go"
+)
 
 // PrepareExecutionMessages separates the current message from context messages
 // and combines with memory history for agent/team execution.
@@ -32,6 +37,32 @@ func ExtractUserMessageContent(messages []Message) string {
 	return ""
 }
 
+// MessageText returns the text content of a message, joining the text parts of multi-part user
+// messages
+func MessageText(message Message) string {
+	switch {
+	case message.OfUser != nil:
+		if len(message.OfUser.Content.OfArrayOfContentParts) == 0 {
+			return message.OfUser.Content.OfString.Value
+		}
+		var parts []string
+		for _, part := range message.OfUser.Content.OfArrayOfContentParts {
+			if part.OfText != nil {
+				parts = append(parts, part.OfText.Text)
+			}
+		}
+		return strings.Join(parts, "\n")
+	case message.OfAssistant != nil:
+		return message.OfAssistant.Content.OfString.Value
+	case message.OfTool != nil:
+		return message.OfTool.Content.OfString.Value
+	case message.OfSystem != nil:
+		return message.OfSystem.Content.OfString.Value
+	default:
+		return ""
+	}
+}
+
 // PrepareModelMessages combines all messages for direct model execution.
 // This pattern is used when all messages (memory + input) should be sent
 // to the model as a continuous conversation history.
@@ -51,3 +82,10 @@ func PrepareNewMessagesForMemory(inputMessages, responseMessages []Message) []Me
 	newMessages = append(newMessages, responseMessages...)
 	return newMessages
 }
+
+// unmarshalModelJSON parses JSON answered by a model, which may wrap it in a markdown code fence
+func unmarshalModelJSON(content string, v any) error {
+	answer := strings.TrimSpace(content)
+	answer = strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(answer, "```json"), "```"), "```")
+	return json.Unmarshal([]byte(strings.TrimSpace(answer)), v)
+}
@@ -397,3 +397,29 @@ func BenchmarkPrepareNewMessagesForMemory(b *testing.B) {
 		_ = PrepareNewMessagesForMemory(inputMessages, responseMessages)
 	}
 }
+
+func TestUnmarshalModelJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+		wantErr bool
+	}{
+		{name: "plain", content: `["a", "b"]`, want: []string{"a", "b"}},
+		{name: "json fence", content: "```json\n[\"a\"]\n```", want: []string{"a"}},
+		{name: "bare fence", content: "  ```\n[\"a\"]\n```  ", want: []string{"a"}},
+		{name: "not json", content: "no values", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			err := unmarshalModelJSON(tt.content, &got)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unmarshalModelJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("unmarshalModelJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
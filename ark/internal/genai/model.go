@@ -52,6 +52,13 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		return nil, fmt.Errorf("failed to load model CRD %s in namespace %s: %w", modelName, namespace, err)
 	}
 
+	return MakeModel(ctx, k8sClient, modelCRD, modelRecorder)
+}
+
+// MakeModel resolves the configuration of a Model resource, which does not need to exist in the
+// cluster yet
+func MakeModel(ctx context.Context, k8sClient client.Client, modelCRD *arkv1alpha1.Model, modelRecorder telemetry.ModelRecorder) (*Model, error) {
+	namespace := modelCRD.Namespace
 	resolver := common.NewValueSourceResolver(k8sClient)
 	model, err := resolver.ResolveValueSource(ctx, modelCRD.Spec.Model, namespace)
 	if err != nil {
@@ -62,6 +69,10 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		Model:         model,
 		Type:          modelCRD.Spec.Type,
 		ModelRecorder: modelRecorder,
+		Namespace:     namespace,
+		Capabilities:  modelCRD.Spec.Capabilities,
+		ContextWindow: modelCRD.Spec.ContextWindow,
+		Pricing:       modelCRD.Spec.Pricing,
 	}
 
 	switch modelCRD.Spec.Type {
@@ -77,6 +88,14 @@ func LoadModel(ctx context.Context, k8sClient client.Client, modelSpec interface
 		if err := loadBedrockConfig(ctx, resolver, modelCRD.Spec.Config.Bedrock, namespace, model, modelInstance); err != nil {
 			return nil, err
 		}
+	case ModelTypeMock:
+		if err := loadMockConfig(ctx, resolver, modelCRD.Spec.Config.Mock, namespace, modelInstance); err != nil {
+			return nil, err
+		}
+	case ModelTypeEmbedding:
+		if err := loadEmbeddingConfig(ctx, resolver, modelCRD.Spec.Config, namespace, modelInstance); err != nil {
+			return nil, err
+		}
 	default:
 		return nil, fmt.Errorf("unsupported model type: %s", modelCRD.Spec.Type)
 	}
@@ -18,9 +18,19 @@ func loadAzureConfig(ctx context.Context, resolver *common.ValueSourceResolver,
 		return fmt.Errorf("failed to resolve Azure baseURL: %w", err)
 	}
 
-	apiKey, err := resolver.ResolveValueSource(ctx, config.APIKey, namespace)
-	if err != nil {
-		return fmt.Errorf("failed to resolve Azure apiKey: %w", err)
+	var apiKey string
+	var tokenSource *AzureTokenSource
+	switch {
+	case config.ManagedIdentity != nil:
+		identity := config.ManagedIdentity
+		tokenSource = NewAzureTokenSource(identity.ClientID, identity.TenantID, identity.Scope)
+	case config.APIKey != nil:
+		apiKey, err = resolver.ResolveValueSource(ctx, *config.APIKey, namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve Azure apiKey: %w", err)
+		}
+	default:
+		return fmt.Errorf("azure configuration requires apiKey or managedIdentity")
 	}
 
 	var apiVersion string
@@ -49,12 +59,13 @@ func loadAzureConfig(ctx context.Context, resolver *common.ValueSourceResolver,
 	}
 
 	azureProvider := &AzureProvider{
-		Model:      model.Model,
-		BaseURL:    baseURL,
-		APIKey:     apiKey,
-		APIVersion: apiVersion,
-		Headers:    headers,
-		Properties: properties,
+		Model:       model.Model,
+		BaseURL:     baseURL,
+		APIKey:      apiKey,
+		TokenSource: tokenSource,
+		APIVersion:  apiVersion,
+		Headers:     headers,
+		Properties:  properties,
 	}
 	model.Provider = azureProvider
 	model.Properties = properties
@@ -47,6 +47,9 @@ func loadBedrockConfig(ctx context.Context, resolver *common.ValueSourceResolver
 	}
 
 	bedrockModel := NewBedrockModel(modelName, region, baseURL, accessKeyID, secretAccessKey, sessionToken, modelArn, properties)
+	bedrockModel.InferenceProfileArn = resolveOptionalValue(ctx, resolver, config.InferenceProfileArn, namespace)
+	bedrockModel.FailoverRegions = config.FailoverRegions
+	bedrockModel.RoleArn = resolveOptionalValue(ctx, resolver, config.RoleArn, namespace)
 	model.Provider = bedrockModel
 	model.Properties = properties
 
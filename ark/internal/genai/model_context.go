@@ -0,0 +1,111 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const contextSummaryPrompt = "Summarize the following conversation so the summary can replace it as context for the rest of the conversation. Preserve facts, decisions, names, numbers and open questions. Respond with the summary only."
+
+// fitContextWindow shortens messages that do not fit the model context window. The leading system
+// messages and the last message are always kept; the oldest messages in between are removed, or
+// summarized with the summarize strategy, until the request fits.
+func (m *Model) fitContextWindow(ctx context.Context, messages []Message, tools ...[]openai.ChatCompletionToolParam) ([]Message, error) {
+	window := m.ContextWindow
+	if window == nil {
+		return messages, nil
+	}
+
+	budget := window.MaxTokens - window.ReserveOutputTokens - estimateToolTokens(tools...)
+	if estimateTokens(messages) <= budget {
+		return messages, nil
+	}
+
+	start := 0
+	for start < len(messages)-1 && messages[start].OfSystem != nil {
+		start++
+	}
+
+	count := contextTrimCount(messages, start, budget)
+	if count == 0 {
+		return nil, fmt.Errorf("messages exceed the context window of model %s (%d tokens) even without history", m.Model, window.MaxTokens)
+	}
+
+	trimmed := make([]Message, 0, len(messages)-count+1)
+	trimmed = append(trimmed, messages[:start]...)
+	if window.Strategy == ContextWindowStrategySummarize {
+		summary, err := m.summarizeContext(ctx, messages[start:start+count], budget)
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "failed to summarize context, truncating instead", "model", m.Model)
+		} else if estimateTokens(append(append(trimmed, summary), messages[start+count:]...)) <= budget {
+			trimmed = append(trimmed, summary)
+		}
+	}
+	trimmed = append(trimmed, messages[start+count:]...)
+
+	logf.FromContext(ctx).Info("shortened conversation to fit the model context window",
+		"model", m.Model, "maxTokens", window.MaxTokens, "removedMessages", count, "strategy", window.Strategy)
+	return trimmed, nil
+}
+
+// contextTrimCount returns how many messages after the first start messages have to be removed for
+// the rest to fit in budget, or zero if removing all but the last message is not enough
+func contextTrimCount(messages []Message, start, budget int) int {
+	fixed := estimateTokens(messages[:start])
+	for count := 1; start+count < len(messages); count++ {
+		// Tool results must follow the assistant message that called the tool, so they are removed together
+		if messages[start+count].OfTool != nil {
+			continue
+		}
+		if fixed+estimateTokens(messages[start+count:]) <= budget {
+			return count
+		}
+	}
+	return 0
+}
+
+// summarizeContext summarizes removed messages with the model itself. The transcript is cut to the
+// budget so the summary request fits the context window.
+func (m *Model) summarizeContext(ctx context.Context, messages []Message, budget int) (Message, error) {
+	data, err := json.Marshal(toOpenAIMessages(messages))
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to serialize messages: %w", err)
+	}
+	transcript := string(data)
+	if maxLength := (budget - estimateTokens([]Message{NewSystemMessage(contextSummaryPrompt)})) * charactersPerToken; len(transcript) > maxLength {
+		transcript = transcript[len(transcript)-max(maxLength, 0):]
+	}
+
+	summarizer := *m
+	summarizer.ContextWindow = nil
+	summarizer.OutputSchema = nil
+	summarizer.Parameters = nil
+	response, err := summarizer.ChatCompletion(ctx, []Message{
+		NewSystemMessage(contextSummaryPrompt),
+		NewUserMessage(transcript),
+	}, nil, 1)
+	if err != nil {
+		return Message{}, err
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return Message{}, fmt.Errorf("model returned no choices")
+	}
+	return NewSystemMessage(memoryCompactionSummaryPrefix + response.Choices[0].Message.Content), nil
+}
+
+func estimateToolTokens(tools ...[]openai.ChatCompletionToolParam) int {
+	if len(tools) == 0 || len(tools[0]) == 0 {
+		return 0
+	}
+	data, err := json.Marshal(tools[0])
+	if err != nil {
+		return 0
+	}
+	return len(data) / charactersPerToken
+}
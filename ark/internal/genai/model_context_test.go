@@ -0,0 +1,111 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+type contextTestProvider struct {
+	received [][]Message
+	err      error
+}
+
+func (p *contextTestProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	p.received = append(p.received, messages)
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "short"}}}}, nil
+}
+
+func (p *contextTestProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return p.ChatCompletion(ctx, messages, n, tools...)
+}
+
+func (p *contextTestProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func (p *contextTestProvider) SetModelParameters(parameters *arkv1alpha1.ModelParameters) {}
+
+func contextTestMessages() []Message {
+	long := strings.Repeat("x", 400)
+	return []Message{
+		NewSystemMessage("be brief"),
+		NewUserMessage(long),
+		NewAssistantMessage(long),
+		NewUserMessage(long),
+		Message(openai.AssistantMessage("")),
+		Message(openai.ToolMessage(long, "call-1")),
+		NewUserMessage("and now?"),
+	}
+}
+
+func TestFitContextWindow(t *testing.T) {
+	ctx := context.Background()
+	messages := contextTestMessages()
+
+	model := &Model{Model: "gpt-4o"}
+	fitted, err := model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, messages, fitted)
+
+	model.ContextWindow = &arkv1alpha1.ModelContextWindow{MaxTokens: 10000}
+	fitted, err = model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, messages, fitted)
+
+	// The system prompt and the last message are kept, the oldest messages are removed first
+	model.ContextWindow = &arkv1alpha1.ModelContextWindow{MaxTokens: 200}
+	fitted, err = model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{messages[0], messages[4], messages[5], messages[6]}, fitted)
+
+	// The tool result is removed with the assistant message that called the tool
+	model.ContextWindow = &arkv1alpha1.ModelContextWindow{MaxTokens: 100}
+	fitted, err = model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{messages[0], messages[6]}, fitted)
+
+	model.ContextWindow = &arkv1alpha1.ModelContextWindow{MaxTokens: 300, ReserveOutputTokens: 290}
+	_, err = model.fitContextWindow(ctx, messages)
+	assert.ErrorContains(t, err, "exceed the context window")
+}
+
+func TestFitContextWindowSummarize(t *testing.T) {
+	ctx := context.Background()
+	messages := contextTestMessages()
+
+	provider := &contextTestProvider{}
+	model := &Model{
+		Model:         "gpt-4o",
+		Provider:      provider,
+		ModelRecorder: noop.NewModelRecorder(),
+		ContextWindow: &arkv1alpha1.ModelContextWindow{MaxTokens: 200, Strategy: ContextWindowStrategySummarize},
+	}
+
+	fitted, err := model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	require.Len(t, fitted, 5)
+	assert.Equal(t, messages[0], fitted[0])
+	require.NotNil(t, fitted[1].OfSystem)
+	assert.Equal(t, memoryCompactionSummaryPrefix+"short", fitted[1].OfSystem.Content.OfString.Value)
+	assert.Equal(t, messages[4:], fitted[2:])
+	require.Len(t, provider.received, 1)
+
+	// Failed summaries fall back to truncation
+	provider.err = errors.New("rate limited")
+	fitted, err = model.fitContextWindow(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{messages[0], messages[4], messages[5], messages[6]}, fitted)
+}
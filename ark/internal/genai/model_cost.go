@@ -0,0 +1,77 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type costTrackerContextKey struct{}
+
+// CostTracker sums the cost of the model calls of a query. Calls to models without pricing add nothing.
+type CostTracker struct {
+	mu     sync.Mutex
+	total  float64
+	priced bool
+}
+
+func NewCostTracker() *CostTracker {
+	return &CostTracker{}
+}
+
+// WithCostTracker returns a context in which model calls add their cost to tracker
+func WithCostTracker(ctx context.Context, tracker *CostTracker) context.Context {
+	return context.WithValue(ctx, costTrackerContextKey{}, tracker)
+}
+
+// Total returns the formatted total cost, or an empty string when no priced model was called
+func (t *CostTracker) Total() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.priced {
+		return ""
+	}
+	return FormatCost(t.total)
+}
+
+func (t *CostTracker) add(cost float64) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.total += cost
+	t.priced = true
+}
+
+// FormatCost formats a cost with the precision used in query and session status
+func FormatCost(cost float64) string {
+	return strconv.FormatFloat(cost, 'f', 6, 64)
+}
+
+// TokenCost returns the cost of token usage at the prices of pricing
+func TokenCost(pricing *arkv1alpha1.ModelPricing, promptTokens, completionTokens int64) (float64, error) {
+	promptPrice, err := strconv.ParseFloat(pricing.PromptTokens, 64)
+	if err != nil {
+		return 0, err
+	}
+	completionPrice, err := strconv.ParseFloat(pricing.CompletionTokens, 64)
+	if err != nil {
+		return 0, err
+	}
+	return (float64(promptTokens)*promptPrice + float64(completionTokens)*completionPrice) / 1_000_000, nil
+}
+
+// recordCost adds the cost of a call to the cost tracker of the context
+func (m *Model) recordCost(ctx context.Context, promptTokens, completionTokens int64) {
+	tracker, ok := ctx.Value(costTrackerContextKey{}).(*CostTracker)
+	if !ok || tracker == nil || m.Pricing == nil {
+		return
+	}
+	cost, err := TokenCost(m.Pricing, promptTokens, completionTokens)
+	if err != nil {
+		return
+	}
+	tracker.add(cost)
+}
@@ -0,0 +1,63 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+type costTestProvider struct {
+	contextTestProvider
+}
+
+func (p *costTestProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	return &openai.ChatCompletion{
+		Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "ok"}}},
+		Usage:   openai.CompletionUsage{PromptTokens: 2000, CompletionTokens: 500, TotalTokens: 2500},
+	}, nil
+}
+
+func TestTokenCost(t *testing.T) {
+	cost, err := TokenCost(&arkv1alpha1.ModelPricing{PromptTokens: "2.5", CompletionTokens: "10"}, 1000, 200)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.0045, cost, 1e-12)
+
+	_, err = TokenCost(&arkv1alpha1.ModelPricing{PromptTokens: "free", CompletionTokens: "10"}, 1000, 200)
+	assert.Error(t, err)
+}
+
+func TestModelRecordsCost(t *testing.T) {
+	tracker := NewCostTracker()
+	ctx := WithCostTracker(context.Background(), tracker)
+	messages := []Message{NewUserMessage("hello")}
+
+	unpriced := &Model{Model: "gpt-4o", Provider: &costTestProvider{}, ModelRecorder: noop.NewModelRecorder()}
+	_, err := unpriced.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	assert.Empty(t, tracker.Total())
+
+	priced := &Model{
+		Model:         "gpt-4o",
+		Provider:      &costTestProvider{},
+		ModelRecorder: noop.NewModelRecorder(),
+		Pricing:       &arkv1alpha1.ModelPricing{PromptTokens: "2.5", CompletionTokens: "10"},
+	}
+	_, err = priced.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	_, err = priced.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0.020000", tracker.Total())
+
+	// Calls outside of a query are not tracked
+	_, err = priced.ChatCompletion(context.Background(), messages, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "0.020000", tracker.Total())
+}
@@ -0,0 +1,102 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/telemetry/metrics"
+)
+
+// EmbeddingProvider is a provider that turns text into embedding vectors
+type EmbeddingProvider interface {
+	Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error)
+}
+
+// Embeddings are the vectors of the inputs of an embedding request, in the order of the inputs
+type Embeddings struct {
+	Model   string      `json:"model"`
+	Vectors [][]float64 `json:"vectors"`
+	Usage   TokenUsage  `json:"usage"`
+}
+
+// loadEmbeddingConfig resolves the provider of an embedding model, which is the provider whose
+// configuration is set
+func loadEmbeddingConfig(ctx context.Context, resolver *common.ValueSourceResolver, config arkv1alpha1.ModelConfig, namespace string, model *Model) error {
+	switch {
+	case config.OpenAI != nil:
+		return loadOpenAIConfig(ctx, resolver, config.OpenAI, namespace, model)
+	case config.Azure != nil:
+		return loadAzureConfig(ctx, resolver, config.Azure, namespace, model)
+	default:
+		return fmt.Errorf("embedding models require an openai or azure configuration")
+	}
+}
+
+// Embed returns the embedding vectors of inputs. Only models of type embedding produce embeddings
+func (m *Model) Embed(ctx context.Context, inputs []string) (*Embeddings, error) {
+	provider, ok := m.Provider.(EmbeddingProvider)
+	if m.Type != ModelTypeEmbedding || !ok {
+		return nil, fmt.Errorf("model %s is not an embedding model", m.Model)
+	}
+	if len(inputs) == 0 {
+		return nil, fmt.Errorf("no inputs to embed")
+	}
+
+	ctx, span := m.ModelRecorder.StartModelExecution(ctx, m.Model, m.Type)
+	defer span.End()
+	m.ModelRecorder.RecordModelDetails(span, m.Model, m.Type)
+
+	response, err := provider.Embed(ctx, inputs)
+	if err != nil {
+		m.ModelRecorder.RecordError(span, err)
+		return nil, err
+	}
+	if len(response.Data) != len(inputs) {
+		err := fmt.Errorf("model %s returned %d embeddings for %d inputs", m.Model, len(response.Data), len(inputs))
+		m.ModelRecorder.RecordError(span, err)
+		return nil, err
+	}
+
+	data := response.Data
+	sort.Slice(data, func(i, j int) bool { return data[i].Index < data[j].Index })
+	vectors := make([][]float64, len(data))
+	for i, embedding := range data {
+		vectors[i] = embedding.Embedding
+	}
+
+	usage := TokenUsage{PromptTokens: response.Usage.PromptTokens, TotalTokens: response.Usage.TotalTokens}
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens
+	}
+	m.ModelRecorder.RecordTokenUsage(span, usage.PromptTokens, 0, usage.TotalTokens)
+	metrics.AddTokenUsage(m.Model, m.Namespace, usage.PromptTokens, 0)
+	m.recordCost(ctx, usage.PromptTokens, 0)
+	m.ModelRecorder.RecordSuccess(span)
+
+	return &Embeddings{Model: m.Model, Vectors: vectors, Usage: usage}, nil
+}
+
+func embeddingParams(model string, inputs []string) openai.EmbeddingNewParams {
+	return openai.EmbeddingNewParams{
+		Model:          model,
+		Input:          openai.EmbeddingNewParamsInputUnion{OfArrayOfStrings: inputs},
+		EncodingFormat: openai.EmbeddingNewParamsEncodingFormatFloat,
+	}
+}
+
+func (op *OpenAIProvider) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	client := op.createClient(ctx)
+	return client.Embeddings.New(ctx, embeddingParams(op.Model, inputs))
+}
+
+func (ap *AzureProvider) Embed(ctx context.Context, inputs []string) (*openai.CreateEmbeddingResponse, error) {
+	client := ap.createClient(ctx)
+	return client.Embeddings.New(ctx, embeddingParams(ap.Model, inputs))
+}
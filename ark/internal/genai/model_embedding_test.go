@@ -0,0 +1,81 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func newEmbeddingServer(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/embeddings", r.URL.Path)
+		var request map[string]any
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "text-embedding-3-small", request["model"])
+		assert.Equal(t, []any{"first", "second"}, request["input"])
+
+		// Providers do not guarantee the order of the embeddings
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"object": "list", "model": "text-embedding-3-small",
+			"data": [{"object": "embedding", "index": 1, "embedding": [0.3, 0.4]}, {"object": "embedding", "index": 0, "embedding": [0.1, 0.2]}],
+			"usage": {"prompt_tokens": 4, "total_tokens": 4}}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func embeddingModelCRD(baseURL string) *arkv1alpha1.Model {
+	return &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "embeddings", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:  ModelTypeEmbedding,
+			Model: arkv1alpha1.ValueSource{Value: "text-embedding-3-small"},
+			Config: arkv1alpha1.ModelConfig{OpenAI: &arkv1alpha1.OpenAIModelConfig{
+				BaseURL: arkv1alpha1.ValueSource{Value: baseURL},
+				APIKey:  arkv1alpha1.ValueSource{Value: "key"},
+			}},
+		},
+	}
+}
+
+func TestModelEmbed(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+	server := newEmbeddingServer(t)
+
+	model, err := MakeModel(context.Background(), k8sClient, embeddingModelCRD(server.URL), noop.NewModelRecorder())
+	require.NoError(t, err)
+
+	embeddings, err := model.Embed(context.Background(), []string{"first", "second"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float64{{0.1, 0.2}, {0.3, 0.4}}, embeddings.Vectors)
+	assert.Equal(t, TokenUsage{PromptTokens: 4, TotalTokens: 4}, embeddings.Usage)
+
+	_, err = model.ChatCompletion(context.Background(), []Message{NewUserMessage("Hello")}, nil, 1)
+	assert.ErrorContains(t, err, "does not support chat completions")
+
+	crd := embeddingModelCRD(server.URL)
+	crd.Spec.Config = arkv1alpha1.ModelConfig{}
+	_, err = MakeModel(context.Background(), k8sClient, crd, noop.NewModelRecorder())
+	assert.ErrorContains(t, err, "require an openai or azure configuration")
+}
+
+func TestModelEmbedChatModel(t *testing.T) {
+	model := &Model{Model: "gpt-4o", Type: ModelTypeOpenAI, Provider: &OpenAIProvider{Model: "gpt-4o"}, ModelRecorder: noop.NewModelRecorder()}
+	_, err := model.Embed(context.Background(), []string{"text"})
+	assert.ErrorContains(t, err, "not an embedding model")
+}
@@ -6,13 +6,18 @@ import (
 
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/telemetry"
+	"mckinsey.com/ark/internal/telemetry/metrics"
 )
 
 type ChatCompletionProvider interface {
 	ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error)
 	ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error)
 	SetOutputSchema(schema *runtime.RawExtension, schemaName string)
+	SetModelParameters(parameters *arkv1alpha1.ModelParameters)
 }
 
 type ConfigProvider interface {
@@ -26,13 +31,43 @@ type Model struct {
 	Provider      ChatCompletionProvider
 	OutputSchema  *runtime.RawExtension
 	SchemaName    string
+	Parameters    *arkv1alpha1.ModelParameters
 	ModelRecorder telemetry.ModelRecorder
+	Namespace     string
+	Capabilities  []arkv1alpha1.ModelCapability
+	ContextWindow *arkv1alpha1.ModelContextWindow
+	Pricing       *arkv1alpha1.ModelPricing
+}
+
+// checkCapabilities rejects messages with images or files the model does not support
+func (m *Model) checkCapabilities(messages []Message) error {
+	spec := arkv1alpha1.ModelSpec{Capabilities: m.Capabilities}
+	hasImages, hasFiles := messageMediaTypes(messages)
+	if hasImages && !spec.HasCapability(arkv1alpha1.ModelCapabilityVision) {
+		return fmt.Errorf("model %s does not support image input", m.Model)
+	}
+	if hasFiles && !spec.HasCapability(arkv1alpha1.ModelCapabilityFiles) {
+		return fmt.Errorf("model %s does not support file input", m.Model)
+	}
+	return nil
 }
 
 func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStream EventStreamInterface, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	if m.Provider == nil {
 		return nil, nil
 	}
+	if m.Type == ModelTypeEmbedding {
+		return nil, fmt.Errorf("model %s is an embedding model and does not support chat completions", m.Model)
+	}
+
+	if err := m.checkCapabilities(messages); err != nil {
+		return nil, err
+	}
+
+	messages, err := m.fitContextWindow(ctx, messages, tools...)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, span := m.ModelRecorder.StartModelExecution(ctx, m.Model, m.Type)
 	defer span.End()
@@ -48,11 +83,14 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 	if m.OutputSchema != nil {
 		m.Provider.SetOutputSchema(m.OutputSchema, m.SchemaName)
 	}
+	m.Provider.SetModelParameters(m.Parameters)
 
 	var response *openai.ChatCompletion
-	var err error
+	recording := modelCallRecordingFromContext(ctx)
 
-	if eventStream != nil {
+	if recording != nil && recording.Replaying() {
+		response, err = recording.replay(ctx, m.Model, messages, tools...)
+	} else if eventStream != nil {
 		response, err = m.Provider.ChatCompletionStream(ctx, messages, n, func(chunk *openai.ChatCompletionChunk) error {
 			chunkWithMeta := WrapChunkWithMetadata(ctx, chunk, m.Model)
 			return eventStream.StreamChunk(ctx, chunkWithMeta)
@@ -71,12 +109,29 @@ func (m *Model) ChatCompletion(ctx context.Context, messages []Message, eventStr
 		m.ModelRecorder.RecordError(span, err)
 		return nil, err
 	}
+	normalizeUsage(&response.Usage)
+
+	if recording != nil && !recording.Replaying() {
+		if err := recording.record(m.Model, messages, response, tools...); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to record model call", "model", m.Model)
+		}
+	}
 
 	if len(response.Choices) > 0 {
 		m.ModelRecorder.RecordOutput(span, response.Choices[0].Message)
 	}
 
 	m.ModelRecorder.RecordTokenUsage(span, response.Usage.PromptTokens, response.Usage.CompletionTokens, response.Usage.TotalTokens)
+	reasoningTokens := response.Usage.CompletionTokensDetails.ReasoningTokens
+	if reasoningTokens > 0 {
+		m.ModelRecorder.RecordReasoningTokens(span, reasoningTokens)
+	}
+	// Replayed calls did not use any tokens of the model
+	if recording == nil || !recording.Replaying() {
+		metrics.AddTokenUsage(m.Model, m.Namespace, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+		metrics.AddReasoningTokens(m.Model, m.Namespace, reasoningTokens)
+		m.recordCost(ctx, response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	}
 	m.ModelRecorder.RecordSuccess(span)
 
 	return response, nil
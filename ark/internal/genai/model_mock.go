@@ -0,0 +1,29 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+func loadMockConfig(ctx context.Context, resolver *common.ValueSourceResolver, config *arkv1alpha1.MockModelConfig, namespace string, model *Model) error {
+	if config == nil {
+		return fmt.Errorf("mock configuration is required for mock model type")
+	}
+
+	data, err := resolver.ResolveValueSource(ctx, config.Script, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve mock script: %w", err)
+	}
+	script, err := ParseMockScript(data)
+	if err != nil {
+		return err
+	}
+
+	model.Provider = &MockProvider{Model: model.Model, Script: script}
+	return nil
+}
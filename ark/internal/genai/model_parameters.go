@@ -0,0 +1,199 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/openai/openai-go"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// modelParameterLimits are the model parameters a provider accepts
+type modelParameterLimits struct {
+	maxTemperature  float64
+	penalties       bool
+	seed            bool
+	reasoningEffort bool
+}
+
+var (
+	openAIParameterLimits  = modelParameterLimits{maxTemperature: 2, penalties: true, seed: true, reasoningEffort: true}
+	bedrockParameterLimits = modelParameterLimits{maxTemperature: 1}
+)
+
+// MergeModelParameters returns the parameters of base with the ones set in override replacing them
+func MergeModelParameters(base, override *arkv1alpha1.ModelParameters) *arkv1alpha1.ModelParameters {
+	if override == nil {
+		return base
+	}
+	if base == nil {
+		return override
+	}
+
+	merged := base.DeepCopy()
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.MaxTokens != nil {
+		merged.MaxTokens = override.MaxTokens
+	}
+	if override.FrequencyPenalty != nil {
+		merged.FrequencyPenalty = override.FrequencyPenalty
+	}
+	if override.PresencePenalty != nil {
+		merged.PresencePenalty = override.PresencePenalty
+	}
+	if len(override.Stop) > 0 {
+		merged.Stop = override.Stop
+	}
+	if override.Seed != nil {
+		merged.Seed = override.Seed
+	}
+	if override.ReasoningEffort != "" {
+		merged.ReasoningEffort = override.ReasoningEffort
+	}
+	if override.ToolChoice != "" {
+		merged.ToolChoice = override.ToolChoice
+	}
+	return merged
+}
+
+// queryModelParameters returns the model parameters of the query being executed, which override
+// the ones of agents
+func queryModelParameters(ctx context.Context) *arkv1alpha1.ModelParameters {
+	if query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query); ok {
+		return query.Spec.ModelParameters
+	}
+	return nil
+}
+
+// ValidateModelParameters checks the parameters against the limits of a model type. An empty
+// model type checks the limits common to all providers
+func ValidateModelParameters(parameters *arkv1alpha1.ModelParameters, modelType string) error {
+	if parameters == nil {
+		return nil
+	}
+	limits := openAIParameterLimits
+	if modelType == ModelTypeBedrock {
+		limits = bedrockParameterLimits
+	}
+
+	if err := checkParameterRange("temperature", parameters.Temperature, 0, limits.maxTemperature); err != nil {
+		return err
+	}
+	if err := checkParameterRange("topP", parameters.TopP, 0, 1); err != nil {
+		return err
+	}
+	penalties := []struct {
+		name  string
+		value *string
+	}{{"frequencyPenalty", parameters.FrequencyPenalty}, {"presencePenalty", parameters.PresencePenalty}}
+	for _, penalty := range penalties {
+		if penalty.value != nil && !limits.penalties {
+			return fmt.Errorf("%s is not supported by %s models", penalty.name, modelType)
+		}
+		if err := checkParameterRange(penalty.name, penalty.value, -2, 2); err != nil {
+			return err
+		}
+	}
+	if parameters.Seed != nil && !limits.seed {
+		return fmt.Errorf("seed is not supported by %s models", modelType)
+	}
+	if parameters.ReasoningEffort != "" && !limits.reasoningEffort {
+		return fmt.Errorf("reasoningEffort is not supported by %s models", modelType)
+	}
+	if parameters.MaxTokens != nil && *parameters.MaxTokens < 1 {
+		return fmt.Errorf("maxTokens must be at least 1")
+	}
+	if len(parameters.Stop) > 4 {
+		return fmt.Errorf("at most 4 stop sequences are allowed, got %d", len(parameters.Stop))
+	}
+	for _, stop := range parameters.Stop {
+		if stop == "" {
+			return fmt.Errorf("stop sequences must not be empty")
+		}
+	}
+	return nil
+}
+
+func checkParameterRange(name string, value *string, minValue, maxValue float64) error {
+	if value == nil {
+		return nil
+	}
+	parsed, err := strconv.ParseFloat(*value, 64)
+	if err != nil {
+		return fmt.Errorf("invalid %s '%s': %w", name, *value, err)
+	}
+	if parsed < minValue || parsed > maxValue {
+		return fmt.Errorf("%s must be between %g and %g, got %s", name, minValue, maxValue, *value)
+	}
+	return nil
+}
+
+// applyModelParametersToParams sets the model parameters on an OpenAI request, replacing the
+// defaults and properties of the model
+func applyModelParametersToParams(parameters *arkv1alpha1.ModelParameters, params *openai.ChatCompletionNewParams) {
+	if parameters == nil {
+		return
+	}
+	if value, ok := parseModelParameter(parameters.Temperature); ok {
+		params.Temperature = openai.Float(value)
+	}
+	if value, ok := parseModelParameter(parameters.TopP); ok {
+		params.TopP = openai.Float(value)
+	}
+	if parameters.MaxTokens != nil {
+		params.MaxCompletionTokens = openai.Int(*parameters.MaxTokens)
+	}
+	if value, ok := parseModelParameter(parameters.FrequencyPenalty); ok {
+		params.FrequencyPenalty = openai.Float(value)
+	}
+	if value, ok := parseModelParameter(parameters.PresencePenalty); ok {
+		params.PresencePenalty = openai.Float(value)
+	}
+	if len(parameters.Stop) > 0 {
+		params.Stop = openai.ChatCompletionNewParamsStopUnion{OfStringArray: parameters.Stop}
+	}
+	if parameters.Seed != nil {
+		params.Seed = openai.Int(*parameters.Seed)
+	}
+}
+
+// applyToolChoiceToParams sets the tool choice on an OpenAI request with tools
+func applyToolChoiceToParams(parameters *arkv1alpha1.ModelParameters, params *openai.ChatCompletionNewParams) {
+	if parameters == nil || parameters.ToolChoice == "" || len(params.Tools) == 0 {
+		return
+	}
+	switch parameters.ToolChoice {
+	case arkv1alpha1.ToolChoiceAuto, arkv1alpha1.ToolChoiceNone, arkv1alpha1.ToolChoiceRequired:
+		params.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{OfAuto: openai.String(parameters.ToolChoice)}
+	default:
+		params.ToolChoice = openai.ChatCompletionToolChoiceOptionUnionParam{
+			OfChatCompletionNamedToolChoice: &openai.ChatCompletionNamedToolChoiceParam{
+				Function: openai.ChatCompletionNamedToolChoiceFunctionParam{Name: parameters.ToolChoice},
+			},
+		}
+	}
+}
+
+// IsForcedToolChoice reports whether a tool choice makes the model call a tool
+func IsForcedToolChoice(toolChoice string) bool {
+	return toolChoice != "" && toolChoice != arkv1alpha1.ToolChoiceAuto && toolChoice != arkv1alpha1.ToolChoiceNone
+}
+
+// parseModelParameter parses a fractional parameter. Invalid values are rejected by the webhooks
+// and ignored here
+func parseModelParameter(value *string) (float64, bool) {
+	if value == nil {
+		return 0, false
+	}
+	parsed, err := strconv.ParseFloat(*value, 64)
+	return parsed, err == nil
+}
@@ -0,0 +1,135 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func stringParameter(value string) *string {
+	return &value
+}
+
+func TestMergeModelParameters(t *testing.T) {
+	maxTokens := int64(256)
+	agent := &arkv1alpha1.ModelParameters{Temperature: stringParameter("0.2"), MaxTokens: &maxTokens, Stop: []string{"END"}}
+	query := &arkv1alpha1.ModelParameters{Temperature: stringParameter("0.9"), ToolChoice: arkv1alpha1.ToolChoiceRequired}
+
+	assert.Same(t, agent, MergeModelParameters(agent, nil))
+	assert.Same(t, query, MergeModelParameters(nil, query))
+
+	merged := MergeModelParameters(agent, query)
+	assert.Equal(t, "0.9", *merged.Temperature)
+	assert.Equal(t, int64(256), *merged.MaxTokens)
+	assert.Equal(t, []string{"END"}, merged.Stop)
+	assert.Equal(t, arkv1alpha1.ToolChoiceRequired, merged.ToolChoice)
+	assert.Equal(t, "0.2", *agent.Temperature)
+}
+
+func TestQueryModelParameters(t *testing.T) {
+	assert.Nil(t, queryModelParameters(context.Background()))
+
+	parameters := &arkv1alpha1.ModelParameters{Temperature: stringParameter("0")}
+	query := &arkv1alpha1.Query{Spec: arkv1alpha1.QuerySpec{ModelParameters: parameters}}
+	ctx := context.WithValue(context.Background(), QueryContextKey, query)
+	assert.Same(t, parameters, queryModelParameters(ctx))
+}
+
+func TestValidateModelParameters(t *testing.T) {
+	seed := int64(42)
+	valid := &arkv1alpha1.ModelParameters{
+		Temperature:      stringParameter("1.5"),
+		TopP:             stringParameter("0.9"),
+		FrequencyPenalty: stringParameter("-1.5"),
+		Seed:             &seed,
+		Stop:             []string{"\n\n"},
+	}
+	require.NoError(t, ValidateModelParameters(nil, ""))
+	require.NoError(t, ValidateModelParameters(valid, ""))
+	require.NoError(t, ValidateModelParameters(valid, ModelTypeAzure))
+
+	assert.ErrorContains(t, ValidateModelParameters(valid, ModelTypeBedrock), "temperature must be between 0 and 1")
+	assert.ErrorContains(t, ValidateModelParameters(&arkv1alpha1.ModelParameters{Seed: &seed}, ModelTypeBedrock), "seed is not supported by bedrock models")
+	assert.ErrorContains(t, ValidateModelParameters(&arkv1alpha1.ModelParameters{ReasoningEffort: "high"}, ModelTypeBedrock), "reasoningEffort is not supported by bedrock models")
+	assert.ErrorContains(t, ValidateModelParameters(&arkv1alpha1.ModelParameters{Temperature: stringParameter("2.5")}, ""), "temperature must be between 0 and 2")
+	assert.ErrorContains(t, ValidateModelParameters(&arkv1alpha1.ModelParameters{PresencePenalty: stringParameter("3")}, ""), "presencePenalty must be between -2 and 2")
+	assert.ErrorContains(t, ValidateModelParameters(&arkv1alpha1.ModelParameters{Stop: []string{"a", "b", "c", "d", "e"}}, ""), "at most 4 stop sequences")
+}
+
+func TestApplyModelParametersToParams(t *testing.T) {
+	maxTokens, seed := int64(100), int64(7)
+	params := openai.ChatCompletionNewParams{}
+	applyPropertiesToParams(nil, &params)
+	applyModelParametersToParams(&arkv1alpha1.ModelParameters{
+		Temperature:     stringParameter("0.3"),
+		TopP:            stringParameter("0.5"),
+		MaxTokens:       &maxTokens,
+		PresencePenalty: stringParameter("1"),
+		Stop:            []string{"END"},
+		Seed:            &seed,
+	}, &params)
+
+	assert.Equal(t, 0.3, params.Temperature.Value)
+	assert.Equal(t, 0.5, params.TopP.Value)
+	assert.Equal(t, int64(100), params.MaxCompletionTokens.Value)
+	assert.Equal(t, 1.0, params.PresencePenalty.Value)
+	assert.False(t, params.FrequencyPenalty.Valid())
+	assert.Equal(t, []string{"END"}, params.Stop.OfStringArray)
+	assert.Equal(t, int64(7), params.Seed.Value)
+	assert.Equal(t, int64(1), params.N.Value)
+}
+
+func TestBedrockRequestModelParameters(t *testing.T) {
+	model := NewBedrockModel("claude", "us-east-1", "", "", "", "", "", map[string]string{"temperature": "0.7", "max_tokens": "2048"})
+	request := model.buildRequest(nil, "", nil)
+	assert.Equal(t, 0.7, request.Temperature)
+	assert.Equal(t, 2048, request.MaxTokens)
+	assert.Nil(t, request.TopP)
+
+	maxTokens := int64(512)
+	model.SetModelParameters(&arkv1alpha1.ModelParameters{Temperature: stringParameter("0.1"), TopP: stringParameter("0.8"), MaxTokens: &maxTokens, Stop: []string{"Human:"}})
+	request = model.buildRequest(nil, "", nil)
+	assert.Equal(t, 0.1, request.Temperature)
+	assert.Equal(t, 0.8, *request.TopP)
+	assert.Equal(t, 512, request.MaxTokens)
+	assert.Equal(t, []string{"Human:"}, request.StopSequences)
+}
+
+func TestApplyToolChoiceToParams(t *testing.T) {
+	params := openai.ChatCompletionNewParams{}
+	applyToolChoiceToParams(&arkv1alpha1.ModelParameters{ToolChoice: arkv1alpha1.ToolChoiceRequired}, &params)
+	assert.False(t, params.ToolChoice.OfAuto.Valid(), "a tool choice without tools is not sent")
+
+	params.Tools = []openai.ChatCompletionToolParam{{Function: openai.FunctionDefinitionParam{Name: "search"}}}
+	applyToolChoiceToParams(&arkv1alpha1.ModelParameters{ToolChoice: arkv1alpha1.ToolChoiceRequired}, &params)
+	assert.Equal(t, arkv1alpha1.ToolChoiceRequired, params.ToolChoice.OfAuto.Value)
+
+	params = openai.ChatCompletionNewParams{Tools: params.Tools}
+	applyToolChoiceToParams(&arkv1alpha1.ModelParameters{ToolChoice: "search"}, &params)
+	require.NotNil(t, params.ToolChoice.OfChatCompletionNamedToolChoice)
+	assert.Equal(t, "search", params.ToolChoice.OfChatCompletionNamedToolChoice.Function.Name)
+
+	assert.True(t, IsForcedToolChoice("search"))
+	assert.True(t, IsForcedToolChoice(arkv1alpha1.ToolChoiceRequired))
+	assert.False(t, IsForcedToolChoice(arkv1alpha1.ToolChoiceAuto))
+	assert.False(t, IsForcedToolChoice(""))
+}
+
+func TestBedrockToolChoice(t *testing.T) {
+	assert.Nil(t, newBedrockToolChoice(""))
+	assert.Equal(t, &bedrockToolChoice{Type: "auto"}, newBedrockToolChoice(arkv1alpha1.ToolChoiceAuto))
+	assert.Equal(t, &bedrockToolChoice{Type: "any"}, newBedrockToolChoice(arkv1alpha1.ToolChoiceRequired))
+	assert.Equal(t, &bedrockToolChoice{Type: "tool", Name: "search"}, newBedrockToolChoice("search"))
+
+	model := NewBedrockModel("claude", "us-east-1", "", "", "", "", "", nil)
+	model.SetModelParameters(&arkv1alpha1.ModelParameters{ToolChoice: "search"})
+	assert.Nil(t, model.buildRequest(nil, "", nil).ToolChoice)
+	assert.Equal(t, &bedrockToolChoice{Type: "tool", Name: "search"}, model.buildRequest(nil, "", []bedrockTool{{Name: "search"}}).ToolChoice)
+}
@@ -22,16 +22,26 @@ type ProbeResult struct {
 
 // ProbeModel tests if a model is available
 func ProbeModel(ctx context.Context, model *Model) ProbeResult {
-	// Create probe context with 30s timeout, inheriting trace context from parent
-	timeout := 30 * time.Second
+	return ProbeModelWithTimeout(ctx, model, 30*time.Second)
+}
+
+// ProbeModelWithTimeout tests if a model answers a one token completion within timeout. Embedding
+// models are probed with the embedding of a single word instead
+func ProbeModelWithTimeout(ctx context.Context, model *Model, timeout time.Duration) ProbeResult {
+	// Create probe context inheriting trace context from parent
 	probeCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
-	// Simple test message
-	testMessages := []Message{NewUserMessage("Hello")}
+	var err error
+	if model.Type == ModelTypeEmbedding {
+		_, err = model.Embed(probeCtx, []string{"Hello"})
+	} else {
+		// Simple test message
+		testMessages := []Message{NewUserMessage("Hello")}
 
-	// Try to get a completion (streaming disabled for probe)
-	_, err := model.ChatCompletion(probeCtx, testMessages, nil, 1)
+		// Try to get a completion (streaming disabled for probe)
+		_, err = model.ChatCompletion(probeCtx, testMessages, nil, 1)
+	}
 	if err != nil {
 		return ProbeResult{
 			Available:     false,
@@ -0,0 +1,76 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/openai/openai-go"
+	"github.com/openai/openai-go/packages/param"
+	"github.com/openai/openai-go/shared"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// reasoningModelPattern matches the names of OpenAI reasoning models, e.g. o1, o3-mini or o4-mini
+var reasoningModelPattern = regexp.MustCompile(`^o[1-9](-|$)`)
+
+// IsReasoningModel reports whether a model is a reasoning model, which rejects sampling
+// parameters. Provider prefixes such as openai/ are ignored
+func IsReasoningModel(model string) bool {
+	name := strings.ToLower(model[strings.LastIndex(model, "/")+1:])
+	return reasoningModelPattern.MatchString(name)
+}
+
+// applyReasoningToParams adapts an OpenAI request to reasoning models. Models are treated as
+// reasoning models by their name, or when a reasoning effort is set, as Azure deployments can
+// have any name. Sampling parameters they reject are dropped and max_tokens is sent as
+// max_completion_tokens.
+func applyReasoningToParams(model string, parameters *arkv1alpha1.ModelParameters, params *openai.ChatCompletionNewParams) {
+	effort := ""
+	if parameters != nil {
+		effort = parameters.ReasoningEffort
+	}
+	if effort == "" && !IsReasoningModel(model) {
+		return
+	}
+
+	if effort != "" {
+		params.ReasoningEffort = shared.ReasoningEffort(effort)
+	}
+	params.Temperature = param.Opt[float64]{}
+	params.TopP = param.Opt[float64]{}
+	params.FrequencyPenalty = param.Opt[float64]{}
+	params.PresencePenalty = param.Opt[float64]{}
+	params.Logprobs = param.Opt[bool]{}
+	params.TopLogprobs = param.Opt[int64]{}
+	params.LogitBias = nil
+	params.Stop = openai.ChatCompletionNewParamsStopUnion{}
+	if params.MaxTokens.Valid() {
+		if !params.MaxCompletionTokens.Valid() {
+			params.MaxCompletionTokens = params.MaxTokens
+		}
+		params.MaxTokens = param.Opt[int64]{}
+	}
+}
+
+// normalizeUsage fills in the total of providers that omit it, and of streamed responses whose
+// usage is reported without a total
+func normalizeUsage(usage *openai.CompletionUsage) {
+	if usage.TotalTokens == 0 {
+		usage.TotalTokens = usage.PromptTokens + usage.CompletionTokens
+	}
+}
+
+// TokenUsageFromCompletion returns the token usage of a model call, including the tokens reasoning
+// models spent on reasoning
+func TokenUsageFromCompletion(usage openai.CompletionUsage) TokenUsage {
+	normalizeUsage(&usage)
+	return TokenUsage{
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+		TotalTokens:      usage.TotalTokens,
+		ReasoningTokens:  usage.CompletionTokensDetails.ReasoningTokens,
+	}
+}
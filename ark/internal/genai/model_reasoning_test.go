@@ -0,0 +1,83 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestIsReasoningModel(t *testing.T) {
+	for _, model := range []string{"o1", "o1-mini", "o3-mini-2025-01-31", "o4-mini", "openai/o3", "O3"} {
+		assert.True(t, IsReasoningModel(model), model)
+	}
+	for _, model := range []string{"gpt-4o", "omni-moderation-latest", "claude-3-5-sonnet", "llama3"} {
+		assert.False(t, IsReasoningModel(model), model)
+	}
+}
+
+func TestApplyReasoningToParams(t *testing.T) {
+	params := openai.ChatCompletionNewParams{Model: "o3-mini", MaxTokens: openai.Int(2048)}
+	applyPropertiesToParams(map[string]string{"top_p": "0.5"}, &params)
+	applyModelParametersToParams(&arkv1alpha1.ModelParameters{Temperature: stringParameter("0.2"), Stop: []string{"END"}, ReasoningEffort: "high"}, &params)
+	applyReasoningToParams("o3-mini", &arkv1alpha1.ModelParameters{ReasoningEffort: "high"}, &params)
+
+	body, err := json.Marshal(params)
+	require.NoError(t, err)
+	var request map[string]any
+	require.NoError(t, json.Unmarshal(body, &request))
+	for _, key := range []string{"temperature", "top_p", "stop", "max_tokens"} {
+		assert.NotContains(t, request, key)
+	}
+	assert.Equal(t, "high", request["reasoning_effort"])
+	assert.Equal(t, float64(2048), request["max_completion_tokens"])
+	assert.Equal(t, float64(1), request["n"])
+}
+
+func TestApplyReasoningToParamsByEffort(t *testing.T) {
+	params := openai.ChatCompletionNewParams{}
+	applyPropertiesToParams(nil, &params)
+	applyReasoningToParams("gpt-4o", nil, &params)
+	assert.Equal(t, 1.0, params.Temperature.Value)
+
+	// Azure deployments can have any name, the effort marks them as reasoning models
+	applyReasoningToParams("reasoning-deployment", &arkv1alpha1.ModelParameters{ReasoningEffort: "low"}, &params)
+	assert.False(t, params.Temperature.Valid())
+	assert.Equal(t, "low", string(params.ReasoningEffort))
+}
+
+func TestTokenUsageFromCompletion(t *testing.T) {
+	usage := openai.CompletionUsage{PromptTokens: 40, CompletionTokens: 300}
+	usage.CompletionTokensDetails.ReasoningTokens = 256
+
+	tokenUsage := TokenUsageFromCompletion(usage)
+	assert.Equal(t, TokenUsage{PromptTokens: 40, CompletionTokens: 300, TotalTokens: 340, ReasoningTokens: 256}, tokenUsage)
+}
+
+func TestAccumulateStreamUsage(t *testing.T) {
+	var chunks []openai.ChatCompletionChunk
+	for _, raw := range []string{
+		`{"id":"1","choices":[{"index":0,"delta":{"role":"assistant","content":"Hi"}}]}`,
+		`{"id":"1","choices":[],"usage":{"prompt_tokens":12,"completion_tokens":70,"total_tokens":82,"completion_tokens_details":{"reasoning_tokens":64}}}`,
+	} {
+		var chunk openai.ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(raw), &chunk))
+		chunks = append(chunks, chunk)
+	}
+
+	var response *openai.ChatCompletion
+	toolCalls := map[int64]*openai.ChatCompletionMessageToolCall{}
+	for i := range chunks {
+		accumulateStreamChunk(&chunks[i], &response, toolCalls)
+	}
+	require.NotNil(t, response)
+	assert.Equal(t, "Hi", response.Choices[0].Message.Content)
+	assert.Equal(t, int64(82), response.Usage.TotalTokens)
+	assert.Equal(t, int64(64), response.Usage.CompletionTokensDetails.ReasoningTokens)
+}
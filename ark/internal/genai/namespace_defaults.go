@@ -0,0 +1,192 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// DefaultsConfigMapName is the per-namespace ConfigMap holding operator-configured resource defaults
+const DefaultsConfigMapName = "ark-config-defaults"
+
+const defaultMemoryName = "default"
+
+// NamespaceDefaults are the operator-configured defaults of a namespace. Without an
+// ark-config-defaults ConfigMap, the model and memory named "default" are used and the other
+// fields are unset, which applies no default.
+type NamespaceDefaults struct {
+	Model  string
+	Memory string
+	// Evaluators evaluate every completed query in the namespace, unless they set their own selector
+	Evaluators []string
+
+	QueryTTL                *time.Duration
+	QueryTTLAfterCompletion *time.Duration
+	QueryTimeout            *time.Duration
+	QueryServiceAccount     string
+	QueryDataPolicy         arkv1alpha1.DataPolicy
+	QuerySLO                *arkv1alpha1.QuerySLO
+	QueryIdempotencyWindow  *time.Duration
+	AzureAPIVersion         string
+	// ModelTemperature is applied to models that do not set a temperature
+	ModelTemperature *float64
+	// ModelTemperatureMin and ModelTemperatureMax bound temperatures set directly on models
+	ModelTemperatureMin *float64
+	ModelTemperatureMax *float64
+}
+
+// GetNamespaceDefaults reads the namespace ark-config-defaults ConfigMap. defaultEvaluators is a
+// comma-separated list of names. Returns an error if the ConfigMap has invalid values.
+func GetNamespaceDefaults(ctx context.Context, k8sClient client.Client, namespace string) (*NamespaceDefaults, error) {
+	defaults := &NamespaceDefaults{
+		Model:  defaultModelName,
+		Memory: defaultMemoryName,
+	}
+
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: DefaultsConfigMapName, Namespace: namespace}, cm); err != nil {
+		if errors.IsNotFound(err) {
+			return defaults, nil
+		}
+		return nil, fmt.Errorf("failed to get defaults ConfigMap: %w", err)
+	}
+
+	if model := strings.TrimSpace(cm.Data["defaultModel"]); model != "" {
+		defaults.Model = model
+	}
+	if memory := strings.TrimSpace(cm.Data["defaultMemory"]); memory != "" {
+		defaults.Memory = memory
+	}
+	for _, evaluator := range strings.Split(cm.Data["defaultEvaluators"], ",") {
+		if evaluator = strings.TrimSpace(evaluator); evaluator != "" {
+			defaults.Evaluators = append(defaults.Evaluators, evaluator)
+		}
+	}
+
+	var err error
+	if defaults.QueryTTL, err = parseDefaultDuration(cm.Data, "queryTTL"); err != nil {
+		return nil, err
+	}
+	if defaults.QueryTTLAfterCompletion, err = parseDefaultDuration(cm.Data, "queryTTLAfterCompletion"); err != nil {
+		return nil, err
+	}
+	if defaults.QueryTimeout, err = parseDefaultDuration(cm.Data, "queryTimeout"); err != nil {
+		return nil, err
+	}
+	if defaults.QuerySLO, err = parseDefaultQuerySLO(cm.Data); err != nil {
+		return nil, err
+	}
+	if defaults.QueryIdempotencyWindow, err = parseDefaultDuration(cm.Data, "queryIdempotencyWindow"); err != nil {
+		return nil, err
+	}
+	if defaults.ModelTemperatureMin, err = parseDefaultFloat(cm.Data, "modelTemperatureMin"); err != nil {
+		return nil, err
+	}
+	if defaults.ModelTemperatureMax, err = parseDefaultFloat(cm.Data, "modelTemperatureMax"); err != nil {
+		return nil, err
+	}
+	if defaults.ModelTemperature, err = parseDefaultFloat(cm.Data, "modelTemperature"); err != nil {
+		return nil, err
+	}
+
+	defaults.QueryServiceAccount = strings.TrimSpace(cm.Data["queryServiceAccount"])
+	defaults.QueryDataPolicy = arkv1alpha1.DataPolicy(strings.TrimSpace(cm.Data["queryDataPolicy"]))
+	switch defaults.QueryDataPolicy {
+	case "", arkv1alpha1.DataPolicyNone, arkv1alpha1.DataPolicyRedactPII:
+	default:
+		return nil, fmt.Errorf("invalid queryDataPolicy '%s' in %s: must be %s or %s",
+			defaults.QueryDataPolicy, DefaultsConfigMapName, arkv1alpha1.DataPolicyNone, arkv1alpha1.DataPolicyRedactPII)
+	}
+	defaults.AzureAPIVersion = strings.TrimSpace(cm.Data["azureAPIVersion"])
+
+	return defaults, nil
+}
+
+// IsDefaultEvaluator reports whether the evaluator with the given name is a namespace default
+func (d *NamespaceDefaults) IsDefaultEvaluator(name string) bool {
+	return slices.Contains(d.Evaluators, name)
+}
+
+// CheckTemperature returns an error if temperature is outside the configured bounds
+func (d *NamespaceDefaults) CheckTemperature(temperature string) error {
+	value, err := strconv.ParseFloat(temperature, 64)
+	if err != nil {
+		return fmt.Errorf("invalid temperature '%s': must be a number", temperature)
+	}
+	if d.ModelTemperatureMin != nil && value < *d.ModelTemperatureMin {
+		return fmt.Errorf("temperature %s is below the minimum of %v set in %s", temperature, *d.ModelTemperatureMin, DefaultsConfigMapName)
+	}
+	if d.ModelTemperatureMax != nil && value > *d.ModelTemperatureMax {
+		return fmt.Errorf("temperature %s is above the maximum of %v set in %s", temperature, *d.ModelTemperatureMax, DefaultsConfigMapName)
+	}
+	return nil
+}
+
+func parseDefaultDuration(data map[string]string, key string) (*time.Duration, error) {
+	value, ok := data[key]
+	if !ok {
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(strings.TrimSpace(value))
+	if err != nil || duration <= 0 {
+		return nil, fmt.Errorf("defaults ConfigMap has invalid %s '%s': must be a positive duration", key, value)
+	}
+	return &duration, nil
+}
+
+// parseDefaultQuerySLO reads the querySLOMaxDuration, querySLOMaxCost and querySLOMaxTokens keys
+func parseDefaultQuerySLO(data map[string]string) (*arkv1alpha1.QuerySLO, error) {
+	slo := &arkv1alpha1.QuerySLO{}
+	maxDuration, err := parseDefaultDuration(data, "querySLOMaxDuration")
+	if err != nil {
+		return nil, err
+	}
+	if maxDuration != nil {
+		slo.MaxDuration = &metav1.Duration{Duration: *maxDuration}
+	}
+	maxCost, err := parseDefaultFloat(data, "querySLOMaxCost")
+	if err != nil {
+		return nil, err
+	}
+	if maxCost != nil {
+		if *maxCost < 0 {
+			return nil, fmt.Errorf("defaults ConfigMap has invalid querySLOMaxCost '%s': must not be negative", data["querySLOMaxCost"])
+		}
+		slo.MaxCost = strings.TrimSpace(data["querySLOMaxCost"])
+	}
+	if value, ok := data["querySLOMaxTokens"]; ok {
+		maxTokens, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil || maxTokens <= 0 {
+			return nil, fmt.Errorf("defaults ConfigMap has invalid querySLOMaxTokens '%s': must be a positive integer", value)
+		}
+		slo.MaxTokens = maxTokens
+	}
+	if slo.MaxDuration == nil && slo.MaxCost == "" && slo.MaxTokens == 0 {
+		return nil, nil
+	}
+	return slo, nil
+}
+
+func parseDefaultFloat(data map[string]string, key string) (*float64, error) {
+	value, ok := data[key]
+	if !ok {
+		return nil, nil
+	}
+	parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+	if err != nil {
+		return nil, fmt.Errorf("defaults ConfigMap has invalid %s '%s': must be a number", key, value)
+	}
+	return &parsed, nil
+}
@@ -0,0 +1,66 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestGetNamespaceDefaults(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultsConfigMapName, Namespace: "team-a"},
+		Data: map[string]string{
+			"defaultModel":      " gpt-4o ",
+			"defaultEvaluators": "quality, ,safety",
+			"queryTimeout":      "10m",
+			"querySLOMaxTokens": "5000",
+			"modelTemperature":  " 0.2 ",
+		},
+	}, &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultsConfigMapName, Namespace: "team-b"},
+		Data:       map[string]string{"modelTemperature": "warm"},
+	}).Build()
+
+	defaults, err := GetNamespaceDefaults(ctx, k8sClient, "default")
+	require.NoError(t, err)
+	assert.Equal(t, &NamespaceDefaults{Model: "default", Memory: "default"}, defaults)
+
+	defaults, err = GetNamespaceDefaults(ctx, k8sClient, "team-a")
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-4o", defaults.Model)
+	assert.Equal(t, "default", defaults.Memory)
+	assert.Equal(t, []string{"quality", "safety"}, defaults.Evaluators)
+	assert.True(t, defaults.IsDefaultEvaluator("safety"))
+	assert.False(t, defaults.IsDefaultEvaluator("latency"))
+	assert.Equal(t, 10*time.Minute, *defaults.QueryTimeout)
+	assert.Nil(t, defaults.QueryTTL)
+	assert.Equal(t, &arkv1alpha1.QuerySLO{MaxTokens: 5000}, defaults.QuerySLO)
+	assert.Equal(t, 0.2, *defaults.ModelTemperature)
+
+	_, err = GetNamespaceDefaults(ctx, k8sClient, "team-b")
+	assert.EqualError(t, err, "defaults ConfigMap has invalid modelTemperature 'warm': must be a number")
+}
+
+func TestCheckTemperature(t *testing.T) {
+	minimum, maximum := 0.0, 0.7
+	defaults := &NamespaceDefaults{ModelTemperatureMin: &minimum, ModelTemperatureMax: &maximum}
+
+	assert.NoError(t, defaults.CheckTemperature("0.5"))
+	assert.EqualError(t, defaults.CheckTemperature("0.9"), "temperature 0.9 is above the maximum of 0.7 set in ark-config-defaults")
+	assert.EqualError(t, defaults.CheckTemperature("-1"), "temperature -1 is below the minimum of 0 set in ark-config-defaults")
+	assert.EqualError(t, defaults.CheckTemperature("hot"), "invalid temperature 'hot': must be a number")
+}
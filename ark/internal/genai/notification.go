@@ -0,0 +1,240 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	notificationHTTPTimeout = 10 * time.Second
+	cloudEventsContentType  = "application/cloudevents+json"
+	cloudEventsTypePrefix   = "com.mckinsey.ark."
+)
+
+// Notification is a query lifecycle event, posted as JSON to webhook sinks
+type Notification struct {
+	Event     arkv1alpha1.NotificationEvent `json:"event"`
+	Kind      string                        `json:"kind"`
+	Name      string                        `json:"name"`
+	Namespace string                        `json:"namespace"`
+	Message   string                        `json:"message"`
+	Time      time.Time                     `json:"time"`
+	Details   map[string]string             `json:"details,omitempty"`
+}
+
+// cloudEvent is a CloudEvents 1.0 event in structured mode
+type cloudEvent struct {
+	SpecVersion     string       `json:"specversion"`
+	ID              string       `json:"id"`
+	Source          string       `json:"source"`
+	Type            string       `json:"type"`
+	Subject         string       `json:"subject"`
+	Time            time.Time    `json:"time"`
+	DataContentType string       `json:"datacontenttype"`
+	Data            Notification `json:"data"`
+}
+
+// Notifier posts notifications to the NotificationSinks of their namespace. Delivery failures are
+// logged and recorded as events on the sink, they never fail the operation that is notified.
+type Notifier struct {
+	Client     client.Client
+	Recorder   record.EventRecorder
+	HTTPClient *http.Client
+	resolver   *common.ValueSourceResolver
+}
+
+func NewNotifier(k8sClient client.Client, recorder record.EventRecorder) *Notifier {
+	return &Notifier{
+		Client:     k8sClient,
+		Recorder:   recorder,
+		HTTPClient: &http.Client{Timeout: notificationHTTPTimeout},
+		resolver:   common.NewValueSourceResolver(k8sClient),
+	}
+}
+
+// Notify sends a notification to the sinks of its namespace that subscribe to its event
+func (n *Notifier) Notify(ctx context.Context, notification Notification) {
+	if n == nil {
+		return
+	}
+	sinks, err := n.sinks(ctx, notification.Namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list notification sinks", "namespace", notification.Namespace)
+		return
+	}
+	for i := range sinks {
+		if sinks[i].Spec.Subscribes(notification.Event) {
+			n.Send(ctx, &sinks[i], notification)
+		}
+	}
+}
+
+// NotifyBudget sends BudgetExceeded to the sinks whose session budget the session exceeds with
+// current and did not exceed with previous
+func (n *Notifier) NotifyBudget(ctx context.Context, session *arkv1alpha1.Session, previous, current arkv1alpha1.SessionStatus) {
+	if n == nil {
+		return
+	}
+	sinks, err := n.sinks(ctx, session.Namespace)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "failed to list notification sinks", "namespace", session.Namespace)
+		return
+	}
+	for i := range sinks {
+		budget := sinks[i].Spec.SessionBudget
+		if budget == nil || !sinks[i].Spec.Subscribes(arkv1alpha1.NotificationBudgetExceeded) {
+			continue
+		}
+		if !ExceedsBudget(budget, current) || ExceedsBudget(budget, previous) {
+			continue
+		}
+		n.Send(ctx, &sinks[i], Notification{
+			Event:     arkv1alpha1.NotificationBudgetExceeded,
+			Kind:      "Session",
+			Name:      session.Name,
+			Namespace: session.Namespace,
+			Message:   fmt.Sprintf("Session %s exceeded its budget", session.Spec.SessionId),
+			Time:      time.Now().UTC(),
+			Details: map[string]string{
+				"sessionId":   session.Spec.SessionId,
+				"totalTokens": strconv.FormatInt(current.TokenUsage.TotalTokens, 10),
+				"cost":        current.Cost,
+				"maxTokens":   strconv.FormatInt(budget.MaxTokens, 10),
+				"maxCost":     budget.MaxCost,
+			},
+		})
+	}
+}
+
+// ExceedsBudget reports whether a session reached the token usage or cost of a budget
+func ExceedsBudget(budget *arkv1alpha1.SessionBudget, status arkv1alpha1.SessionStatus) bool {
+	if budget.MaxTokens > 0 && status.TokenUsage.TotalTokens >= budget.MaxTokens {
+		return true
+	}
+	if budget.MaxCost == "" || status.Cost == "" {
+		return false
+	}
+	maxCost, err := strconv.ParseFloat(budget.MaxCost, 64)
+	if err != nil {
+		return false
+	}
+	cost, err := strconv.ParseFloat(status.Cost, 64)
+	return err == nil && cost >= maxCost
+}
+
+func (n *Notifier) sinks(ctx context.Context, namespace string) ([]arkv1alpha1.NotificationSink, error) {
+	var sinks arkv1alpha1.NotificationSinkList
+	if err := n.Client.List(ctx, &sinks, client.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return sinks.Items, nil
+}
+
+// Send posts a notification to a sink
+func (n *Notifier) Send(ctx context.Context, sink *arkv1alpha1.NotificationSink, notification Notification) {
+	// Notifications are sent when operations end, including when their context was canceled
+	ctx = context.WithoutCancel(ctx)
+	if err := n.send(ctx, sink, notification); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to send notification", "sink", sink.Name, "namespace", sink.Namespace, "event", notification.Event)
+		if n.Recorder != nil {
+			n.Recorder.Event(sink, corev1.EventTypeWarning, "NotificationFailed",
+				fmt.Sprintf("Failed to send %s notification for %s %s: %v", notification.Event, notification.Kind, notification.Name, err))
+		}
+	}
+}
+
+func (n *Notifier) send(ctx context.Context, sink *arkv1alpha1.NotificationSink, notification Notification) error {
+	url, err := n.resolver.ResolveValueSource(ctx, sink.Spec.URL, sink.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to resolve url: %w", err)
+	}
+
+	body, contentType, err := notificationBody(sink.Spec.Type, notification)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	for _, header := range sink.Spec.Headers {
+		value, err := ResolveHeaderValue(ctx, n.Client, header, sink.Namespace)
+		if err != nil {
+			return fmt.Errorf("failed to resolve header %s: %w", header.Name, err)
+		}
+		req.Header.Set(header.Name, value)
+	}
+
+	resp, err := n.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close notification response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("sink returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// notificationBody formats a notification for a sink type
+func notificationBody(sinkType string, notification Notification) ([]byte, string, error) {
+	var payload any
+	contentType := ContentTypeJSON
+
+	switch sinkType {
+	case arkv1alpha1.NotificationSinkSlack, arkv1alpha1.NotificationSinkTeams:
+		payload = map[string]string{"text": notificationText(notification)}
+	case arkv1alpha1.NotificationSinkCloudEvents:
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, "", fmt.Errorf("failed to generate event id: %w", err)
+		}
+		payload = cloudEvent{
+			SpecVersion:     "1.0",
+			ID:              hex.EncodeToString(id),
+			Source:          fmt.Sprintf("/apis/ark.mckinsey.com/namespaces/%s", notification.Namespace),
+			Type:            cloudEventsTypePrefix + string(notification.Event),
+			Subject:         notification.Name,
+			Time:            notification.Time,
+			DataContentType: ContentTypeJSON,
+			Data:            notification,
+		}
+		contentType = cloudEventsContentType
+	default:
+		payload = notification
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	return body, contentType, nil
+}
+
+func notificationText(notification Notification) string {
+	return fmt.Sprintf("*%s* %s %s/%s: %s", notification.Event, notification.Kind, notification.Namespace, notification.Name, notification.Message)
+}
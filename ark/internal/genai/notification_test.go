@@ -0,0 +1,179 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type sinkRequest struct {
+	contentType string
+	header      string
+	body        []byte
+}
+
+func newSinkServer(t *testing.T) (*httptest.Server, func() []sinkRequest) {
+	var mu sync.Mutex
+	var requests []sinkRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mu.Lock()
+		requests = append(requests, sinkRequest{contentType: r.Header.Get("Content-Type"), header: r.Header.Get("X-Token"), body: body})
+		mu.Unlock()
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []sinkRequest {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]sinkRequest(nil), requests...)
+	}
+}
+
+func newTestNotifier(t *testing.T, objects ...client.Object) (*Notifier, *record.FakeRecorder) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(objects...).Build()
+	recorder := record.NewFakeRecorder(10)
+	return NewNotifier(k8sClient, recorder), recorder
+}
+
+func newSink(name, url string, spec arkv1alpha1.NotificationSinkSpec) *arkv1alpha1.NotificationSink {
+	spec.URL = arkv1alpha1.ValueSource{Value: url}
+	return &arkv1alpha1.NotificationSink{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       spec,
+	}
+}
+
+func TestNotificationBody(t *testing.T) {
+	notification := Notification{
+		Event:     arkv1alpha1.NotificationQueryFailed,
+		Kind:      "Query",
+		Name:      "q1",
+		Namespace: "default",
+		Message:   "model unavailable",
+		Time:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	body, contentType, err := notificationBody(arkv1alpha1.NotificationSinkWebhook, notification)
+	require.NoError(t, err)
+	assert.Equal(t, ContentTypeJSON, contentType)
+	var decoded Notification
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, notification, decoded)
+
+	body, _, err = notificationBody(arkv1alpha1.NotificationSinkSlack, notification)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"text": "*QueryFailed* Query default/q1: model unavailable"}`, string(body))
+
+	body, contentType, err = notificationBody(arkv1alpha1.NotificationSinkCloudEvents, notification)
+	require.NoError(t, err)
+	assert.Equal(t, cloudEventsContentType, contentType)
+	var event cloudEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "com.mckinsey.ark.QueryFailed", event.Type)
+	assert.Equal(t, "/apis/ark.mckinsey.com/namespaces/default", event.Source)
+	assert.Equal(t, "q1", event.Subject)
+	assert.NotEmpty(t, event.ID)
+	assert.Equal(t, notification, event.Data)
+}
+
+func TestExceedsBudget(t *testing.T) {
+	status := arkv1alpha1.SessionStatus{TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: 1000}, Cost: "0.500000"}
+
+	assert.True(t, ExceedsBudget(&arkv1alpha1.SessionBudget{MaxTokens: 1000}, status))
+	assert.False(t, ExceedsBudget(&arkv1alpha1.SessionBudget{MaxTokens: 1001}, status))
+	assert.True(t, ExceedsBudget(&arkv1alpha1.SessionBudget{MaxCost: "0.5"}, status))
+	assert.False(t, ExceedsBudget(&arkv1alpha1.SessionBudget{MaxCost: "1"}, status))
+	assert.False(t, ExceedsBudget(&arkv1alpha1.SessionBudget{MaxCost: "1"}, arkv1alpha1.SessionStatus{}))
+}
+
+func TestNotifyFiltersEvents(t *testing.T) {
+	server, requests := newSinkServer(t)
+	notifier, _ := newTestNotifier(t,
+		newSink("all", server.URL, arkv1alpha1.NotificationSinkSpec{
+			Headers: []arkv1alpha1.Header{{Name: "X-Token", Value: arkv1alpha1.HeaderValue{Value: "secret"}}},
+		}),
+		newSink("failures", server.URL, arkv1alpha1.NotificationSinkSpec{
+			Events: []arkv1alpha1.NotificationEvent{arkv1alpha1.NotificationQueryFailed},
+		}),
+	)
+
+	notifier.Notify(context.Background(), Notification{Event: arkv1alpha1.NotificationQueryCompleted, Kind: "Query", Name: "q1", Namespace: "default"})
+	received := requests()
+	require.Len(t, received, 1)
+	assert.Equal(t, ContentTypeJSON, received[0].contentType)
+	assert.Equal(t, "secret", received[0].header)
+
+	notifier.Notify(context.Background(), Notification{Event: arkv1alpha1.NotificationQueryFailed, Kind: "Query", Name: "q1", Namespace: "default"})
+	assert.Len(t, requests(), 3)
+
+	// Sinks of other namespaces are not notified
+	notifier.Notify(context.Background(), Notification{Event: arkv1alpha1.NotificationQueryFailed, Kind: "Query", Name: "q1", Namespace: "other"})
+	assert.Len(t, requests(), 3)
+}
+
+func TestNotifyRecordsFailures(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(server.Close)
+	notifier, recorder := newTestNotifier(t, newSink("broken", server.URL, arkv1alpha1.NotificationSinkSpec{}))
+
+	notifier.Notify(context.Background(), Notification{Event: arkv1alpha1.NotificationQueryCompleted, Kind: "Query", Name: "q1", Namespace: "default"})
+	require.Len(t, recorder.Events, 1)
+	assert.Contains(t, <-recorder.Events, "NotificationFailed")
+}
+
+func TestNotifyBudget(t *testing.T) {
+	server, requests := newSinkServer(t)
+	notifier, _ := newTestNotifier(t,
+		newSink("budget", server.URL, arkv1alpha1.NotificationSinkSpec{SessionBudget: &arkv1alpha1.SessionBudget{MaxTokens: 100}}),
+		newSink("no-budget", server.URL, arkv1alpha1.NotificationSinkSpec{}),
+	)
+	session := &arkv1alpha1.Session{
+		ObjectMeta: metav1.ObjectMeta{Name: "s1", Namespace: "default"},
+		Spec:       arkv1alpha1.SessionSpec{SessionId: "s1"},
+	}
+	usage := func(tokens int64) arkv1alpha1.SessionStatus {
+		return arkv1alpha1.SessionStatus{TokenUsage: arkv1alpha1.TokenUsage{TotalTokens: tokens}}
+	}
+
+	notifier.NotifyBudget(context.Background(), session, usage(50), usage(80))
+	assert.Empty(t, requests())
+
+	notifier.NotifyBudget(context.Background(), session, usage(80), usage(120))
+	received := requests()
+	require.Len(t, received, 1)
+	var notification Notification
+	require.NoError(t, json.Unmarshal(received[0].body, &notification))
+	assert.Equal(t, arkv1alpha1.NotificationBudgetExceeded, notification.Event)
+	assert.Equal(t, "120", notification.Details["totalTokens"])
+
+	// The budget is only reported when it is first exceeded
+	notifier.NotifyBudget(context.Background(), session, usage(120), usage(150))
+	assert.Len(t, requests(), 1)
+
+	var nilNotifier *Notifier
+	nilNotifier.NotifyBudget(context.Background(), session, usage(80), usage(120))
+}
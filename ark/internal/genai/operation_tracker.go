@@ -40,7 +40,7 @@ func NewOperationTracker(emitter EventEmitter, ctx context.Context, operation, n
 			Metadata: metadata,
 		},
 	}
-	emitter.EmitEvent(ctx, corev1.EventTypeNormal, operation+"Start", startEvent)
+	emitter.EmitEvent(ctx, corev1.EventTypeNormal, operation+EventPhaseStart, startEvent)
 
 	return tracker
 }
@@ -50,7 +50,7 @@ func (t *OperationTracker) Complete(result string) {
 	if log.V(3).Enabled() && result != "" {
 		log.V(3).Info("operation response", "operation", t.operation, "name", t.name, "response", result)
 	}
-	t.emitCompletion(corev1.EventTypeNormal, t.operation+"Complete", "", TokenUsage{})
+	t.emitCompletion(corev1.EventTypeNormal, t.operation+EventPhaseComplete, "", TokenUsage{})
 }
 
 func (t *OperationTracker) CompleteWithMetadata(result string, additionalMetadata map[string]string) {
@@ -58,11 +58,11 @@ func (t *OperationTracker) CompleteWithMetadata(result string, additionalMetadat
 	if log.V(3).Enabled() && result != "" {
 		log.V(3).Info("operation response with metadata", "operation", t.operation, "name", t.name, "response", result, "metadata", additionalMetadata)
 	}
-	t.emitCompletionWithMetadata(corev1.EventTypeNormal, t.operation+"Complete", "", TokenUsage{}, additionalMetadata)
+	t.emitCompletionWithMetadata(corev1.EventTypeNormal, t.operation+EventPhaseComplete, "", TokenUsage{}, additionalMetadata)
 }
 
 func (t *OperationTracker) CompleteWithTokens(tokenUsage TokenUsage) {
-	t.emitCompletion(corev1.EventTypeNormal, t.operation+"Complete", "", tokenUsage)
+	t.emitCompletion(corev1.EventTypeNormal, t.operation+EventPhaseComplete, "", tokenUsage)
 }
 
 func (t *OperationTracker) Fail(err error) {
@@ -70,7 +70,7 @@ func (t *OperationTracker) Fail(err error) {
 	if err != nil {
 		errorMsg = err.Error()
 	}
-	t.emitCompletion(corev1.EventTypeWarning, t.operation+"Error", errorMsg, TokenUsage{})
+	t.emitCompletion(corev1.EventTypeWarning, t.operation+EventPhaseError, errorMsg, TokenUsage{})
 }
 
 func (t *OperationTracker) CompleteWithTermination(terminationMessage string) {
@@ -81,7 +81,7 @@ func (t *OperationTracker) CompleteWithTermination(terminationMessage string) {
 
 	metadata := make(map[string]string)
 	maps.Copy(metadata, t.metadata)
-	metadata["terminationMessage"] = terminationMessage
+	metadata[MetadataTerminationMessage] = terminationMessage
 
 	event := OperationEvent{
 		BaseEvent: BaseEvent{
@@ -91,7 +91,7 @@ func (t *OperationTracker) CompleteWithTermination(terminationMessage string) {
 		Duration:   time.Since(t.startTime).String(),
 		TokenUsage: TokenUsage{},
 	}
-	t.emitter.EmitEvent(t.ctx, corev1.EventTypeNormal, t.operation+"Complete", event)
+	t.emitter.EmitEvent(t.ctx, corev1.EventTypeNormal, t.operation+EventPhaseComplete, event)
 }
 
 func (t *OperationTracker) emitCompletion(eventType, reason, errorMsg string, tokenUsage TokenUsage) {
@@ -0,0 +1,232 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	defaultPostProcessorTimeout = 30 * time.Second
+	// maxPostProcessorResponseSize limits the response read from post-processor webhooks
+	maxPostProcessorResponseSize = 10 << 20
+)
+
+// PostProcessorRequest is posted to post-processor webhooks
+type PostProcessorRequest struct {
+	Content   string `json:"content"`
+	Query     string `json:"query,omitempty"`
+	Namespace string `json:"namespace"`
+	// Agent is set for the post-processors of an agent
+	Agent string `json:"agent,omitempty"`
+}
+
+// PostProcessorResponse is returned by post-processor webhooks
+type PostProcessorResponse struct {
+	Content string `json:"content"`
+}
+
+// PostProcessors transforms the final response of a query target or agent
+type PostProcessors struct {
+	Specs     []arkv1alpha1.PostProcessor
+	Namespace string
+	// Agent is the agent whose response is transformed, empty for query post-processors
+	Agent     string
+	Client    client.Client
+	Telemetry telemetry.Provider
+	Recorder  EventEmitter
+}
+
+// Run applies the post-processors in order to the content of the last assistant message. A
+// post-processor that fails with the ignore failure policy leaves the content unchanged.
+func (p *PostProcessors) Run(ctx context.Context, messages []Message) ([]Message, error) {
+	if len(p.Specs) == 0 || len(messages) == 0 || messages[len(messages)-1].OfAssistant == nil {
+		return messages, nil
+	}
+
+	content := MessageText(messages[len(messages)-1])
+	for i := range p.Specs {
+		spec := &p.Specs[i]
+		processed, err := p.run(ctx, spec, content)
+		if err == nil {
+			content = processed
+			continue
+		}
+
+		if p.Recorder != nil {
+			p.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonPostProcessorFailed, BaseEvent{
+				Name: spec.DisplayName(),
+				Metadata: map[string]string{
+					"error":           err.Error(),
+					"failurePolicy":   string(spec.FailurePolicy),
+					MetadataAgent:     p.Agent,
+					MetadataQueryID:   getQueryID(ctx),
+					MetadataNamespace: p.Namespace,
+				},
+			})
+		}
+		if spec.FailurePolicy != arkv1alpha1.PostProcessorFailurePolicyIgnore {
+			return nil, fmt.Errorf("post-processor %s failed: %w", spec.DisplayName(), err)
+		}
+		logf.FromContext(ctx).Info("ignoring failed post-processor", "postProcessor", spec.DisplayName(), "error", err.Error())
+	}
+
+	processed := make([]Message, len(messages))
+	copy(processed, messages)
+	assistant := *processed[len(processed)-1].OfAssistant
+	assistant.Content = openai.ChatCompletionAssistantMessageParamContentUnion{OfString: openai.String(content)}
+	processed[len(processed)-1] = Message{OfAssistant: &assistant}
+	return processed, nil
+}
+
+func (p *PostProcessors) run(ctx context.Context, spec *arkv1alpha1.PostProcessor, content string) (string, error) {
+	kind := "webhook"
+	if spec.Tool != nil {
+		kind = "tool"
+	}
+	ctx, span := p.Telemetry.Tracer().Start(ctx, "postprocessor."+spec.DisplayName(),
+		telemetry.WithAttributes(
+			telemetry.String("postprocessor.name", spec.DisplayName()),
+			telemetry.String("postprocessor.type", kind),
+			telemetry.String(telemetry.AttrQueryRootInput, content),
+			telemetry.String(telemetry.AttrComponentName, "postprocessor"),
+		),
+	)
+	defer span.End()
+
+	timeout := defaultPostProcessorTimeout
+	if spec.Timeout != nil {
+		timeout = spec.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	var processed string
+	var err error
+	if spec.Tool != nil {
+		processed, err = p.callTool(ctx, spec.Tool.Name, content)
+	} else {
+		processed, err = p.callWebhook(ctx, spec.Webhook, content)
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(telemetry.StatusError, err.Error())
+		return "", err
+	}
+	span.SetAttributes(telemetry.String(telemetry.AttrQueryRootOutput, processed))
+	span.SetStatus(telemetry.StatusOk, "success")
+	return processed, nil
+}
+
+// callTool calls the tool like a query with a tool target: a JSON object response is passed as the
+// arguments, other content as the input argument
+func (p *PostProcessors) callTool(ctx context.Context, name, content string) (string, error) {
+	var tool arkv1alpha1.Tool
+	if err := p.Client.Get(ctx, client.ObjectKey{Name: name, Namespace: p.Namespace}, &tool); err != nil {
+		return "", fmt.Errorf("failed to get tool %s: %w", name, err)
+	}
+
+	var mcpSettings map[string]MCPSettings
+	if queryCrd, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query); ok {
+		if query, err := MakeQuery(queryCrd); err == nil {
+			mcpSettings = query.McpSettings
+		}
+	}
+	registry := NewToolRegistry(mcpSettings, p.Telemetry.ToolRecorder())
+	defer func() {
+		if err := registry.Close(); err != nil {
+			logf.FromContext(ctx).Error(err, "failed to close MCP client connections of post-processor", "tool", name)
+		}
+	}()
+
+	mcpPool, settings := registry.GetMCPPool()
+	executor, err := CreateToolExecutor(ctx, p.Client, &tool, p.Namespace, mcpPool, settings, p.Telemetry)
+	if err != nil {
+		return "", fmt.Errorf("failed to create executor for tool %s: %w", name, err)
+	}
+	registry.RegisterTool(CreateToolFromCRD(&tool), executor)
+
+	var arguments map[string]any
+	if err := json.Unmarshal([]byte(content), &arguments); err != nil {
+		arguments = map[string]any{"input": content}
+	}
+	encoded, err := json.Marshal(arguments)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal tool arguments: %w", err)
+	}
+
+	result, err := registry.ExecuteTool(ctx, ToolCall{
+		ID:       "postprocessor-" + name,
+		Type:     "function",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: name, Arguments: string(encoded)},
+	}, p.Recorder)
+	if err != nil {
+		return "", err
+	}
+	if result.Error != "" {
+		return "", fmt.Errorf("tool %s returned an error: %s", name, result.Error)
+	}
+	return result.Content, nil
+}
+
+func (p *PostProcessors) callWebhook(ctx context.Context, webhook *arkv1alpha1.PostProcessorWebhook, content string) (string, error) {
+	url, err := common.NewValueSourceResolver(p.Client).ResolveValueSource(ctx, webhook.URL, p.Namespace)
+	if err != nil {
+		return "", fmt.Errorf("failed to resolve url: %w", err)
+	}
+
+	body, err := json.Marshal(PostProcessorRequest{Content: content, Query: getQueryName(ctx), Namespace: p.Namespace, Agent: p.Agent})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", ContentTypeJSON)
+	for _, header := range webhook.Headers {
+		value, err := ResolveHeaderValue(ctx, p.Client, header, p.Namespace)
+		if err != nil {
+			return "", fmt.Errorf("failed to resolve header %s: %w", header.Name, err)
+		}
+		req.Header.Set(header.Name, value)
+	}
+
+	resp, err := common.NewHTTPClientWithLogging(ctx).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("request failed: %w", err)
+	}
+	defer func() {
+		if closeErr := resp.Body.Close(); closeErr != nil {
+			logf.FromContext(ctx).Error(closeErr, "failed to close post-processor response body")
+		}
+	}()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return "", fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPostProcessorResponseSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read response: %w", err)
+	}
+	var response PostProcessorResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return "", fmt.Errorf("webhook returned invalid JSON: %w", err)
+	}
+	return response.Content, nil
+}
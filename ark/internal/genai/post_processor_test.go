@@ -0,0 +1,90 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestPostProcessorsRun(t *testing.T) {
+	var received []PostProcessorRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request PostProcessorRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		received = append(received, request)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "secret", r.Header.Get("X-Token"))
+		_ = json.NewEncoder(w).Encode(PostProcessorResponse{Content: strings.ToUpper(request.Content)})
+	}))
+	defer server.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	webhook := func(path string) *arkv1alpha1.PostProcessorWebhook {
+		return &arkv1alpha1.PostProcessorWebhook{
+			URL:     arkv1alpha1.ValueSource{Value: server.URL + path},
+			Headers: []arkv1alpha1.Header{{Name: "X-Token", Value: arkv1alpha1.HeaderValue{Value: "secret"}}},
+		}
+	}
+	newPostProcessors := func(specs ...arkv1alpha1.PostProcessor) (*PostProcessors, *mockRecorder) {
+		recorder := &mockRecorder{}
+		return &PostProcessors{
+			Specs:     specs,
+			Namespace: "default",
+			Agent:     "writer",
+			Client:    fake.NewClientBuilder().WithScheme(scheme).Build(),
+			Telemetry: noop.NewProvider(),
+			Recorder:  recorder,
+		}, recorder
+	}
+	messages := []Message{NewUserMessage("hi"), NewAssistantMessage("hello")}
+	ctx := WithQueryContext(context.Background(), "query-uid", "", "greeting")
+
+	postProcessors, _ := newPostProcessors(arkv1alpha1.PostProcessor{Webhook: webhook("/upper")})
+	processed, err := postProcessors.Run(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", MessageText(processed[1]))
+	assert.Equal(t, "hello", MessageText(messages[1]), "the messages passed in are not modified")
+	assert.Equal(t, PostProcessorRequest{Content: "hello", Query: "greeting", Namespace: "default", Agent: "writer"}, received[0])
+
+	// Failures are ignored with the ignore policy and otherwise fail the execution
+	postProcessors, recorder := newPostProcessors(
+		arkv1alpha1.PostProcessor{Name: "optional", Webhook: webhook("/fail"), FailurePolicy: arkv1alpha1.PostProcessorFailurePolicyIgnore},
+		arkv1alpha1.PostProcessor{Webhook: webhook("/upper")},
+	)
+	processed, err = postProcessors.Run(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", MessageText(processed[1]))
+	require.Len(t, recorder.events, 1)
+
+	// Failures are still handled without a recorder
+	postProcessors.Recorder = nil
+	processed, err = postProcessors.Run(ctx, messages)
+	require.NoError(t, err)
+	assert.Equal(t, "HELLO", MessageText(processed[1]))
+
+	postProcessors, _ = newPostProcessors(arkv1alpha1.PostProcessor{Name: "required", Webhook: webhook("/fail")})
+	_, err = postProcessors.Run(ctx, messages)
+	assert.ErrorContains(t, err, "post-processor required failed: webhook returned status 502")
+
+	// Responses that do not end with an assistant message are left unchanged
+	processed, err = postProcessors.Run(ctx, messages[:1])
+	require.NoError(t, err)
+	assert.Equal(t, messages[:1], processed)
+}
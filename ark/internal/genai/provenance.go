@@ -0,0 +1,77 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"unicode/utf8"
+
+	"github.com/openai/openai-go"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	// ProvenanceAnnotation is the memory message annotation holding the provenance of a response
+	ProvenanceAnnotation = "provenance"
+	// provenanceSnippetLength is the maximum length of a tool result snippet in bytes
+	provenanceSnippetLength = 200
+)
+
+// ToolProvenance returns the tool calls of the messages that have a result, with a snippet of
+// the result. Arguments are hashed so provenance can be shown and audited without the arguments.
+func ToolProvenance(messages []Message) []arkv1alpha1.ResponseProvenance {
+	results := make(map[string]string)
+	for _, msg := range messages {
+		if msg.OfTool != nil {
+			results[msg.OfTool.ToolCallID] = msg.OfTool.Content.OfString.Value
+		}
+	}
+
+	var provenance []arkv1alpha1.ResponseProvenance
+	for _, msg := range messages {
+		if msg.OfAssistant == nil {
+			continue
+		}
+		for _, call := range msg.OfAssistant.ToolCalls {
+			result, ok := results[call.ID]
+			if !ok {
+				continue
+			}
+			provenance = append(provenance, arkv1alpha1.ResponseProvenance{
+				ToolCallID:    call.ID,
+				Tool:          call.Function.Name,
+				ArgumentsHash: HashAuditArguments(call.Function.Arguments),
+				Snippet:       provenanceSnippet(result),
+			})
+		}
+	}
+	return provenance
+}
+
+// responseProvenanceAnnotations returns the annotations of the last message of a batch when it is
+// an answer derived from tool results of the batch, nil otherwise
+func responseProvenanceAnnotations(messages []openai.ChatCompletionMessageParamUnion) map[string]interface{} {
+	if len(messages) == 0 || messages[len(messages)-1].OfAssistant == nil {
+		return nil
+	}
+	batch := make([]Message, len(messages))
+	for i, msg := range messages {
+		batch[i] = Message(msg)
+	}
+	provenance := ToolProvenance(batch)
+	if len(provenance) == 0 {
+		return nil
+	}
+	return map[string]interface{}{ProvenanceAnnotation: provenance}
+}
+
+// provenanceSnippet truncates a tool result to provenanceSnippetLength, respecting UTF-8 boundaries
+func provenanceSnippet(result string) string {
+	if len(result) <= provenanceSnippetLength {
+		return result
+	}
+	end := provenanceSnippetLength
+	for end > 0 && !utf8.RuneStart(result[end]) {
+		end--
+	}
+	return result[:end] + "..."
+}
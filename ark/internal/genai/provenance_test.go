@@ -0,0 +1,62 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func toolCallMessage(calls ...openai.ChatCompletionMessageToolCallParam) Message {
+	return Message(openai.ChatCompletionMessageParamUnion{
+		OfAssistant: &openai.ChatCompletionAssistantMessageParam{ToolCalls: calls},
+	})
+}
+
+func toolCall(id, name, arguments string) openai.ChatCompletionMessageToolCallParam {
+	return openai.ChatCompletionMessageToolCallParam{
+		ID:       id,
+		Function: openai.ChatCompletionMessageToolCallFunctionParam{Name: name, Arguments: arguments},
+	}
+}
+
+func TestToolProvenance(t *testing.T) {
+	longResult := strings.Repeat("é", provenanceSnippetLength)
+	messages := []Message{
+		NewUserMessage("What is the weather in Paris?"),
+		toolCallMessage(toolCall("call-1", "get-weather", `{"city":"Paris"}`), toolCall("call-2", "get-forecast", `{}`)),
+		ToolMessage("Sunny, 24C", "call-1"),
+		ToolMessage(longResult, "call-2"),
+		toolCallMessage(toolCall("call-3", "get-news", `{}`)),
+		NewAssistantMessage("It is sunny in Paris"),
+	}
+
+	provenance := ToolProvenance(messages)
+
+	require.Len(t, provenance, 2, "tool calls without a result are not provenance")
+	assert.Equal(t, "call-1", provenance[0].ToolCallID)
+	assert.Equal(t, "get-weather", provenance[0].Tool)
+	assert.Equal(t, HashAuditArguments(`{"city":"Paris"}`), provenance[0].ArgumentsHash)
+	assert.Equal(t, "Sunny, 24C", provenance[0].Snippet)
+	assert.LessOrEqual(t, len(provenance[1].Snippet), provenanceSnippetLength+len("..."))
+	assert.True(t, strings.HasSuffix(provenance[1].Snippet, "é..."), "snippets are cut at a rune boundary")
+}
+
+func TestResponseProvenanceAnnotations(t *testing.T) {
+	batch := []openai.ChatCompletionMessageParamUnion{
+		openai.ChatCompletionMessageParamUnion(toolCallMessage(toolCall("call-1", "get-weather", `{}`))),
+		openai.ChatCompletionMessageParamUnion(ToolMessage("Sunny", "call-1")),
+	}
+	assert.Nil(t, responseProvenanceAnnotations(batch), "only answers are annotated")
+
+	batch = append(batch, openai.ChatCompletionMessageParamUnion(NewAssistantMessage("It is sunny")))
+	annotations := responseProvenanceAnnotations(batch)
+	require.Contains(t, annotations, ProvenanceAnnotation)
+	assert.Len(t, annotations[ProvenanceAnnotation], 1)
+
+	assert.Nil(t, responseProvenanceAnnotations(batch[2:]), "answers without tool results have no provenance")
+}
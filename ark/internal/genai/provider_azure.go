@@ -3,22 +3,27 @@ package genai
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/openai/openai-go"
 	"github.com/openai/openai-go/option"
 	"k8s.io/apimachinery/pkg/runtime"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 )
 
 type AzureProvider struct {
-	Model        string
-	BaseURL      string
-	APIVersion   string
-	APIKey       string
+	Model      string
+	BaseURL    string
+	APIVersion string
+	APIKey     string
+	// TokenSource authenticates with Entra ID tokens instead of APIKey when set
+	TokenSource  *AzureTokenSource
 	Headers      map[string]string
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	parameters   *arkv1alpha1.ModelParameters
 }
 
 func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -26,6 +31,10 @@ func (ap *AzureProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNam
 	ap.schemaName = schemaName
 }
 
+func (ap *AzureProvider) SetModelParameters(parameters *arkv1alpha1.ModelParameters) {
+	ap.parameters = parameters
+}
+
 func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
@@ -39,10 +48,13 @@ func (ap *AzureProvider) ChatCompletion(ctx context.Context, messages []Message,
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyModelParametersToParams(ap.parameters, &params)
+	applyReasoningToParams(ap.Model, ap.parameters, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
 	}
+	applyToolChoiceToParams(ap.parameters, &params)
 
 	// Apply structured output schema if provided
 	applyStructuredOutputToParams(ap.outputSchema, ap.schemaName, &params)
@@ -65,10 +77,14 @@ func (ap *AzureProvider) prepareStreamParams(messages []Message, n int64, tools
 	}
 
 	applyPropertiesToParams(ap.Properties, &params)
+	applyModelParametersToParams(ap.parameters, &params)
+	applyReasoningToParams(ap.Model, ap.parameters, &params)
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
 	}
+	applyToolChoiceToParams(ap.parameters, &params)
 
 	// Apply structured output schema if provided
 	applyStructuredOutputToParams(ap.outputSchema, ap.schemaName, &params)
@@ -124,14 +140,8 @@ func (ap *AzureProvider) ChatCompletionStream(ctx context.Context, messages []Me
 		return nil, fmt.Errorf("streaming completed but no response was accumulated")
 	}
 
-	// Initialize usage if not present (streaming responses may not include usage)
-	if fullResponse.Usage.TotalTokens == 0 {
-		fullResponse.Usage = openai.CompletionUsage{
-			PromptTokens:     0,
-			CompletionTokens: 0,
-			TotalTokens:      0,
-		}
-	}
+	// Servers that ignore the stream options report no usage, and some report no total
+	normalizeUsage(&fullResponse.Usage)
 
 	return fullResponse, nil
 }
@@ -142,17 +152,32 @@ func (ap *AzureProvider) createClient(ctx context.Context) openai.Client {
 	deploymentURL := fmt.Sprintf("%s/openai/deployments/%s", ap.BaseURL, ap.Model)
 	options := []option.RequestOption{
 		option.WithBaseURL(deploymentURL),
-		option.WithHeader("api-key", ap.APIKey),
-		option.WithAPIKey(ap.APIKey),
 		option.WithHTTPClient(httpClient),
 		option.WithQueryAdd("api-version", ap.APIVersion),
 	}
+	if ap.TokenSource != nil {
+		options = append(options, option.WithMiddleware(ap.authorize))
+	} else {
+		options = append(options, option.WithHeader("api-key", ap.APIKey), option.WithAPIKey(ap.APIKey))
+	}
 
 	options = applyHeadersToOptions(ctx, ap.Headers, options, ap.Model)
 
 	return openai.NewClient(options...)
 }
 
+// authorize sets a current Entra ID token on each request, so long running queries keep working
+// after the token they started with expired
+func (ap *AzureProvider) authorize(req *http.Request, next option.MiddlewareNext) (*http.Response, error) {
+	token, err := ap.TokenSource.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get azure token: %w", err)
+	}
+	req.Header.Del("api-key")
+	req.Header.Set("Authorization", "Bearer "+token)
+	return next(req)
+}
+
 func (ap *AzureProvider) BuildConfig() map[string]any {
 	config := map[string]any{
 		"baseUrl": ap.BaseURL,
@@ -163,5 +188,12 @@ func (ap *AzureProvider) BuildConfig() map[string]any {
 	if ap.APIKey != "" {
 		config["apiKey"] = ap.APIKey
 	}
+	if ap.TokenSource != nil {
+		config["managedIdentity"] = map[string]any{
+			"clientId": ap.TokenSource.ClientID,
+			"tenantId": ap.TokenSource.TenantID,
+			"scope":    ap.TokenSource.Scope,
+		}
+	}
 	return config
 }
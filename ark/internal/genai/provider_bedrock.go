@@ -3,17 +3,47 @@ package genai
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/bedrockruntime"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
 	"github.com/openai/openai-go"
 	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// Headers of InvokeModel responses with the token counts Bedrock meters the call with
+const (
+	bedrockInputTokenCountHeader  = "X-Amzn-Bedrock-Input-Token-Count"
+	bedrockOutputTokenCountHeader = "X-Amzn-Bedrock-Output-Token-Count"
 )
 
+// bedrockFailoverErrorCodes are the error codes of a region that cannot serve the call right now,
+// so the call is retried in the next failover region
+var bedrockFailoverErrorCodes = []string{
+	"ThrottlingException",
+	"ServiceUnavailableException",
+	"ServiceQuotaExceededException",
+	"ModelNotReadyException",
+	"ModelTimeoutException",
+	"InternalServerException",
+}
+
 type BedrockModel struct {
 	Model           string
 	Region          string
@@ -22,10 +52,22 @@ type BedrockModel struct {
 	SecretAccessKey string
 	SessionToken    string
 	ModelArn        string
-	Properties      map[string]string
-	client          *bedrockruntime.Client
-	outputSchema    *runtime.RawExtension
-	schemaName      string
+	// InferenceProfileArn is the ARN or ID of the inference profile the model is invoked through
+	InferenceProfileArn string
+	// FailoverRegions are tried in order when the region is throttled or unavailable
+	FailoverRegions []string
+	// RoleArn is assumed with the credentials of the pod, or with the static keys when set
+	RoleArn      string
+	Properties   map[string]string
+	clients      []bedrockRegionClient
+	outputSchema *runtime.RawExtension
+	schemaName   string
+	parameters   *arkv1alpha1.ModelParameters
+}
+
+type bedrockRegionClient struct {
+	region string
+	client *bedrockruntime.Client
 }
 
 type bedrockMessage struct {
@@ -34,12 +76,21 @@ type bedrockMessage struct {
 }
 
 type bedrockRequest struct {
-	Messages         []bedrockMessage `json:"messages"`
-	MaxTokens        int              `json:"max_tokens"`
-	Temperature      float64          `json:"temperature"`
-	SystemPrompt     string           `json:"system,omitempty"`
-	AnthropicVersion string           `json:"anthropic_version,omitempty"`
-	Tools            []bedrockTool    `json:"tools,omitempty"`
+	Messages         []bedrockMessage   `json:"messages"`
+	MaxTokens        int                `json:"max_tokens"`
+	Temperature      float64            `json:"temperature"`
+	TopP             *float64           `json:"top_p,omitempty"`
+	StopSequences    []string           `json:"stop_sequences,omitempty"`
+	SystemPrompt     string             `json:"system,omitempty"`
+	AnthropicVersion string             `json:"anthropic_version,omitempty"`
+	Tools            []bedrockTool      `json:"tools,omitempty"`
+	ToolChoice       *bedrockToolChoice `json:"tool_choice,omitempty"`
+}
+
+// bedrockToolChoice is the tool choice of Anthropic models: auto, any, none, or a named tool
+type bedrockToolChoice struct {
+	Type string `json:"type"`
+	Name string `json:"name,omitempty"`
 }
 
 type bedrockTool struct {
@@ -54,9 +105,14 @@ type bedrockResponse struct {
 	Model      string           `json:"model"`
 	StopReason string           `json:"stop_reason"`
 	Usage      struct {
-		InputTokens  int `json:"input_tokens"`
-		OutputTokens int `json:"output_tokens"`
+		InputTokens              int `json:"input_tokens"`
+		OutputTokens             int `json:"output_tokens"`
+		CacheReadInputTokens     int `json:"cache_read_input_tokens"`
+		CacheCreationInputTokens int `json:"cache_creation_input_tokens"`
 	} `json:"usage"`
+	// Token counts of Meta Llama models
+	PromptTokenCount     int `json:"prompt_token_count"`
+	GenerationTokenCount int `json:"generation_token_count"`
 }
 
 type bedrockContent struct {
@@ -81,31 +137,99 @@ func NewBedrockModel(model, region, baseURL, accessKeyID, secretAccessKey, sessi
 }
 
 func (bm *BedrockModel) initClient(ctx context.Context) error {
-	if bm.client != nil {
+	if bm.clients != nil {
 		return nil
 	}
 
-	var cfg aws.Config
-	var err error
+	clients := make([]bedrockRegionClient, 0, 1+len(bm.FailoverRegions))
+	for _, region := range bm.regions() {
+		cfg, err := bm.loadConfig(ctx, region)
+		if err != nil {
+			return err
+		}
+		clients = append(clients, bedrockRegionClient{region: region, client: bedrockruntime.NewFromConfig(cfg)})
+	}
+	bm.clients = clients
+	return nil
+}
+
+// regions returns the region of the model followed by its failover regions. An empty region
+// uses the region of the environment.
+func (bm *BedrockModel) regions() []string {
+	regions := []string{bm.Region}
+	for _, region := range bm.FailoverRegions {
+		if region != "" && !slices.Contains(regions, region) {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
 
+// loadConfig loads the AWS config of a region. Without static keys, the default credential chain
+// provides the credentials, including IRSA web identity tokens and EKS Pod Identity.
+func (bm *BedrockModel) loadConfig(ctx context.Context, region string) (aws.Config, error) {
+	var opts []func(*config.LoadOptions) error
+	if region != "" {
+		opts = append(opts, config.WithRegion(region))
+	}
 	if bm.AccessKeyID != "" && bm.SecretAccessKey != "" {
 		creds := credentials.NewStaticCredentialsProvider(bm.AccessKeyID, bm.SecretAccessKey, bm.SessionToken)
-		cfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(bm.Region), config.WithCredentialsProvider(creds))
-	} else {
-		cfg, err = config.LoadDefaultConfig(ctx, config.WithRegion(bm.Region))
+		opts = append(opts, config.WithCredentialsProvider(creds))
 	}
 
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
 	if err != nil {
-		return fmt.Errorf("failed to load AWS config: %w", err)
+		return aws.Config{}, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	if bm.RoleArn != "" {
+		cfg.Credentials = aws.NewCredentialsCache(stscreds.NewAssumeRoleProvider(sts.NewFromConfig(cfg), bm.RoleArn))
 	}
 
 	// If BaseURL is provided, use it as custom endpoint
 	if bm.BaseURL != "" {
 		cfg.BaseEndpoint = aws.String(bm.BaseURL)
 	}
+	return cfg, nil
+}
 
-	bm.client = bedrockruntime.NewFromConfig(cfg)
-	return nil
+// modelID returns the ID the model is invoked with: the inference profile, the model ARN or the model
+func (bm *BedrockModel) modelID() string {
+	switch {
+	case bm.InferenceProfileArn != "":
+		return bm.InferenceProfileArn
+	case bm.ModelArn != "":
+		return bm.ModelArn
+	default:
+		return bm.Model
+	}
+}
+
+func (bm *BedrockModel) isAnthropic() bool {
+	return strings.Contains(strings.ToLower(bm.Model), "claude") || strings.Contains(strings.ToLower(bm.modelID()), "anthropic")
+}
+
+// invokeModel invokes the model in its region, and in the failover regions in order while the
+// regions before cannot serve the call
+func (bm *BedrockModel) invokeModel(ctx context.Context, input *bedrockruntime.InvokeModelInput) (*bedrockruntime.InvokeModelOutput, error) {
+	var err error
+	for i, regional := range bm.clients {
+		var result *bedrockruntime.InvokeModelOutput
+		result, err = regional.client.InvokeModel(ctx, input)
+		if err == nil {
+			return result, nil
+		}
+		if !isBedrockFailoverError(err) || i == len(bm.clients)-1 {
+			break
+		}
+		logf.FromContext(ctx).Info("Bedrock region unavailable, failing over", "model", bm.Model, "region", regional.region, "failoverRegion", bm.clients[i+1].region, "error", err.Error())
+	}
+	return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+}
+
+func isBedrockFailoverError(err error) bool {
+	var apiErr smithy.APIError
+	return errors.As(err, &apiErr) && slices.Contains(bedrockFailoverErrorCodes, apiErr.ErrorCode())
 }
 
 func (bm *BedrockModel) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -113,6 +237,10 @@ func (bm *BedrockModel) SetOutputSchema(schema *runtime.RawExtension, schemaName
 	bm.schemaName = schemaName
 }
 
+func (bm *BedrockModel) SetModelParameters(parameters *arkv1alpha1.ModelParameters) {
+	bm.parameters = parameters
+}
+
 func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	var toolsParam []openai.ChatCompletionToolParam
 	if len(tools) > 0 {
@@ -127,7 +255,7 @@ func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message,
 
 	request := bm.buildRequest(bedrockMessages, systemPrompt, bedrockTools)
 
-	if strings.Contains(strings.ToLower(bm.Model), "claude") {
+	if bm.isAnthropic() {
 		request.AnthropicVersion = "bedrock-2023-05-31"
 	}
 
@@ -136,21 +264,16 @@ func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message,
 		return nil, err
 	}
 
-	modelID := bm.Model
-	if bm.ModelArn != "" {
-		modelID = bm.ModelArn
-	}
-
 	input := &bedrockruntime.InvokeModelInput{
-		ModelId:     aws.String(modelID),
+		ModelId:     aws.String(bm.modelID()),
 		Body:        requestBody,
 		ContentType: aws.String("application/json"),
 		Accept:      aws.String("application/json"),
 	}
 
-	result, err := bm.client.InvokeModel(ctx, input)
+	result, err := bm.invokeModel(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to invoke Bedrock model: %w", err)
+		return nil, err
 	}
 
 	var response bedrockResponse
@@ -158,7 +281,30 @@ func (bm *BedrockModel) ChatCompletion(ctx context.Context, messages []Message,
 		return nil, err
 	}
 
-	return bm.convertResponse(response), nil
+	completion := bm.convertResponse(response)
+	if usage, ok := invocationTokenUsage(result.ResultMetadata); ok {
+		completion.Usage = usage
+	}
+	return completion, nil
+}
+
+// invocationTokenUsage returns the token counts Bedrock metered the call with, from the headers
+// of the response. They are set for every model, unlike the usage in the response body.
+func invocationTokenUsage(metadata middleware.Metadata) (openai.CompletionUsage, bool) {
+	response, ok := awsmiddleware.GetRawResponse(metadata).(*smithyhttp.Response)
+	if !ok || response == nil {
+		return openai.CompletionUsage{}, false
+	}
+	return headerTokenUsage(response.Header)
+}
+
+func headerTokenUsage(header http.Header) (openai.CompletionUsage, bool) {
+	input, inputErr := strconv.ParseInt(header.Get(bedrockInputTokenCountHeader), 10, 64)
+	output, outputErr := strconv.ParseInt(header.Get(bedrockOutputTokenCountHeader), 10, 64)
+	if inputErr != nil || outputErr != nil {
+		return openai.CompletionUsage{}, false
+	}
+	return openai.CompletionUsage{PromptTokens: input, CompletionTokens: output, TotalTokens: input + output}, true
 }
 
 func (bm *BedrockModel) ChatCompletionWithSchema(ctx context.Context, messages []Message, outputSchema *runtime.RawExtension, schemaName string, tools []openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
@@ -204,16 +350,46 @@ func (bm *BedrockModel) ChatCompletionStream(ctx context.Context, messages []Mes
 }
 
 func (bm *BedrockModel) buildRequest(messages []bedrockMessage, systemPrompt string, tools []bedrockTool) bedrockRequest {
-	temperature := getFloatProperty(bm.Properties, "temperature", 1.0)
-	maxTokens := getIntProperty(bm.Properties, "max_tokens", 4096)
-
-	return bedrockRequest{
+	request := bedrockRequest{
 		Messages:     messages,
-		MaxTokens:    maxTokens,
-		Temperature:  temperature,
+		MaxTokens:    getIntProperty(bm.Properties, "max_tokens", 4096),
+		Temperature:  getFloatProperty(bm.Properties, "temperature", 1.0),
 		SystemPrompt: systemPrompt,
 		Tools:        tools,
 	}
+
+	// Model parameters of the agent or query replace the properties of the model
+	if bm.parameters != nil {
+		if value, ok := parseModelParameter(bm.parameters.Temperature); ok {
+			request.Temperature = value
+		}
+		if value, ok := parseModelParameter(bm.parameters.TopP); ok {
+			request.TopP = &value
+		}
+		if bm.parameters.MaxTokens != nil {
+			request.MaxTokens = int(*bm.parameters.MaxTokens)
+		}
+		request.StopSequences = bm.parameters.Stop
+		if len(tools) > 0 {
+			request.ToolChoice = newBedrockToolChoice(bm.parameters.ToolChoice)
+		}
+	}
+	return request
+}
+
+// newBedrockToolChoice converts a tool choice to the one of Anthropic models, which call required
+// tools "any"
+func newBedrockToolChoice(toolChoice string) *bedrockToolChoice {
+	switch toolChoice {
+	case "":
+		return nil
+	case arkv1alpha1.ToolChoiceAuto, arkv1alpha1.ToolChoiceNone:
+		return &bedrockToolChoice{Type: toolChoice}
+	case arkv1alpha1.ToolChoiceRequired:
+		return &bedrockToolChoice{Type: "any"}
+	default:
+		return &bedrockToolChoice{Type: "tool", Name: toolChoice}
+	}
 }
 
 func (bm *BedrockModel) convertMessages(messages []Message) ([]bedrockMessage, string) {
@@ -228,7 +404,11 @@ func (bm *BedrockModel) convertMessages(messages []Message) ([]bedrockMessage, s
 
 		switch role {
 		case RoleSystem:
-			systemPrompt = content
+			// Bedrock takes a single system prompt, later system messages are appended to it
+			if systemPrompt != "" {
+				systemPrompt += "\n\n"
+			}
+			systemPrompt += content
 		case RoleUser, RoleAssistant, RoleTool:
 			msgRole := role
 			if role == RoleTool {
@@ -293,12 +473,19 @@ func (bm *BedrockModel) convertResponse(response bedrockResponse) *openai.ChatCo
 				FinishReason: finishReason,
 			},
 		},
-		Usage: openai.CompletionUsage{
-			PromptTokens:     int64(response.Usage.InputTokens),
-			CompletionTokens: int64(response.Usage.OutputTokens),
-			TotalTokens:      int64(response.Usage.InputTokens + response.Usage.OutputTokens),
-		},
+		Usage: response.tokenUsage(),
+	}
+}
+
+// tokenUsage returns the token usage in the response body. Prompt tokens include the tokens read
+// from and written to the prompt cache.
+func (r bedrockResponse) tokenUsage() openai.CompletionUsage {
+	prompt := int64(r.Usage.InputTokens + r.Usage.CacheReadInputTokens + r.Usage.CacheCreationInputTokens)
+	completion := int64(r.Usage.OutputTokens)
+	if prompt == 0 && completion == 0 {
+		prompt, completion = int64(r.PromptTokenCount), int64(r.GenerationTokenCount)
 	}
+	return openai.CompletionUsage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion}
 }
 
 func mustMarshalJSON(v interface{}) string {
@@ -387,6 +574,15 @@ func (bm *BedrockModel) BuildConfig() map[string]any {
 	if bm.ModelArn != "" {
 		cfg["modelArn"] = bm.ModelArn
 	}
+	if bm.InferenceProfileArn != "" {
+		cfg["inferenceProfileArn"] = bm.InferenceProfileArn
+	}
+	if len(bm.FailoverRegions) > 0 {
+		cfg["failoverRegions"] = bm.FailoverRegions
+	}
+	if bm.RoleArn != "" {
+		cfg["roleArn"] = bm.RoleArn
+	}
 
 	for key, value := range bm.Properties {
 		cfg[key] = value
@@ -0,0 +1,70 @@
+package genai
+
+import (
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/aws/smithy-go/middleware"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBedrockModelID(t *testing.T) {
+	model := NewBedrockModel("anthropic.claude-3-5-sonnet-20240620-v1:0", "us-east-1", "", "", "", "", "", nil)
+	assert.Equal(t, "anthropic.claude-3-5-sonnet-20240620-v1:0", model.modelID())
+
+	model.ModelArn = "arn:aws:bedrock:us-east-1::foundation-model/anthropic.claude-3-5-sonnet-20240620-v1:0"
+	assert.Equal(t, model.ModelArn, model.modelID())
+
+	model.InferenceProfileArn = "us.anthropic.claude-3-5-sonnet-20240620-v1:0"
+	assert.Equal(t, model.InferenceProfileArn, model.modelID())
+
+	profile := NewBedrockModel("sonnet", "", "", "", "", "", "", nil)
+	profile.InferenceProfileArn = "arn:aws:bedrock:us-east-1:123456789012:inference-profile/us.anthropic.claude-3-5-sonnet-20240620-v1:0"
+	assert.True(t, profile.isAnthropic())
+}
+
+func TestBedrockRegions(t *testing.T) {
+	model := NewBedrockModel("claude", "us-east-1", "", "", "", "", "", nil)
+	model.FailoverRegions = []string{"us-west-2", "us-east-1", "", "eu-central-1"}
+	assert.Equal(t, []string{"us-east-1", "us-west-2", "eu-central-1"}, model.regions())
+}
+
+func TestIsBedrockFailoverError(t *testing.T) {
+	throttled := &smithy.GenericAPIError{Code: "ThrottlingException", Message: "rate exceeded"}
+	assert.True(t, isBedrockFailoverError(fmt.Errorf("operation error: %w", throttled)))
+	assert.False(t, isBedrockFailoverError(&smithy.GenericAPIError{Code: "ValidationException"}))
+	assert.False(t, isBedrockFailoverError(fmt.Errorf("connection reset")))
+}
+
+func TestBedrockTokenUsage(t *testing.T) {
+	var response bedrockResponse
+	response.Usage.InputTokens = 100
+	response.Usage.CacheReadInputTokens = 400
+	response.Usage.OutputTokens = 50
+	usage := response.tokenUsage()
+	assert.Equal(t, int64(500), usage.PromptTokens)
+	assert.Equal(t, int64(50), usage.CompletionTokens)
+	assert.Equal(t, int64(550), usage.TotalTokens)
+
+	llama := bedrockResponse{PromptTokenCount: 20, GenerationTokenCount: 10}
+	assert.Equal(t, int64(30), llama.tokenUsage().TotalTokens)
+}
+
+func TestInvocationTokenUsage(t *testing.T) {
+	_, ok := invocationTokenUsage(middleware.Metadata{})
+	assert.False(t, ok)
+
+	header := http.Header{}
+	_, ok = headerTokenUsage(header)
+	assert.False(t, ok)
+
+	header.Set(bedrockInputTokenCountHeader, "120")
+	header.Set(bedrockOutputTokenCountHeader, "30")
+	usage, ok := headerTokenUsage(header)
+	assert.True(t, ok)
+	assert.Equal(t, int64(120), usage.PromptTokens)
+	assert.Equal(t, int64(30), usage.CompletionTokens)
+	assert.Equal(t, int64(150), usage.TotalTokens)
+}
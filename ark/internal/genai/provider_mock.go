@@ -0,0 +1,218 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"sync/atomic"
+	"time"
+
+	"github.com/openai/openai-go"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// MockScript lists the responses of a mock model. A request is answered by the first response
+// whose matcher matches the last message of the request.
+type MockScript struct {
+	// Latency is the default delay of a response
+	Latency   *metav1.Duration `json:"latency,omitempty"`
+	Responses []MockResponse   `json:"responses"`
+}
+
+// MockResponse is a scripted response of a mock model
+type MockResponse struct {
+	// Match is a regular expression the content of the last message must match. Matches any
+	// message when empty
+	Match string `json:"match,omitempty"`
+	// Role the last message must have, e.g. user or tool. Matches any role when empty
+	Role string `json:"role,omitempty"`
+
+	Content   string         `json:"content,omitempty"`
+	ToolCalls []MockToolCall `json:"toolCalls,omitempty"`
+	// Error fails the request with this message, to emulate provider errors
+	Error   string           `json:"error,omitempty"`
+	Latency *metav1.Duration `json:"latency,omitempty"`
+
+	match *regexp.Regexp
+}
+
+// MockToolCall is a tool call of a scripted response
+type MockToolCall struct {
+	Name string `json:"name"`
+	// Arguments of the call as a JSON object. Defaults to {}
+	Arguments string `json:"arguments,omitempty"`
+}
+
+// ParseMockScript parses and checks the YAML script of a mock model
+func ParseMockScript(data string) (*MockScript, error) {
+	var script MockScript
+	if err := yaml.UnmarshalStrict([]byte(data), &script); err != nil {
+		return nil, fmt.Errorf("invalid mock script: %w", err)
+	}
+	if len(script.Responses) == 0 {
+		return nil, fmt.Errorf("invalid mock script: at least one response is required")
+	}
+	for i := range script.Responses {
+		response := &script.Responses[i]
+		if response.Match != "" {
+			match, err := regexp.Compile(response.Match)
+			if err != nil {
+				return nil, fmt.Errorf("invalid mock script: response %d: %w", i, err)
+			}
+			response.match = match
+		}
+		for _, call := range response.ToolCalls {
+			if call.Name == "" {
+				return nil, fmt.Errorf("invalid mock script: response %d: tool calls require a name", i)
+			}
+		}
+	}
+	return &script, nil
+}
+
+// MockProvider answers requests from a script instead of calling a model. Token usage is
+// estimated from the size of the request and response.
+type MockProvider struct {
+	Model  string
+	Script *MockScript
+
+	calls atomic.Int64
+}
+
+func (mp *MockProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {}
+
+func (mp *MockProvider) SetModelParameters(parameters *arkv1alpha1.ModelParameters) {}
+
+func (mp *MockProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	response, err := mp.respond(messages)
+	if err != nil {
+		return nil, err
+	}
+
+	latency := response.Latency
+	if latency == nil {
+		latency = mp.Script.Latency
+	}
+	if latency != nil {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(latency.Duration):
+		}
+	}
+
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return mp.completion(response, messages, tools...), nil
+}
+
+func (mp *MockProvider) ChatCompletionStream(ctx context.Context, messages []Message, n int64, streamFunc func(*openai.ChatCompletionChunk) error, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	completion, err := mp.ChatCompletion(ctx, messages, n, tools...)
+	if err != nil {
+		return nil, err
+	}
+
+	// The response is streamed as a single chunk
+	message := completion.Choices[0].Message
+	chunk := &openai.ChatCompletionChunk{
+		ID:      completion.ID,
+		Object:  "chat.completion.chunk",
+		Created: completion.Created,
+		Model:   completion.Model,
+		Choices: []openai.ChatCompletionChunkChoice{{
+			Delta:        openai.ChatCompletionChunkChoiceDelta{Content: message.Content, Role: "assistant"},
+			FinishReason: completion.Choices[0].FinishReason,
+		}},
+	}
+	for i, call := range message.ToolCalls {
+		chunk.Choices[0].Delta.ToolCalls = append(chunk.Choices[0].Delta.ToolCalls, openai.ChatCompletionChunkChoiceDeltaToolCall{
+			Index:    int64(i),
+			ID:       call.ID,
+			Type:     "function",
+			Function: openai.ChatCompletionChunkChoiceDeltaToolCallFunction{Name: call.Function.Name, Arguments: call.Function.Arguments},
+		})
+	}
+	if err := streamFunc(chunk); err != nil {
+		return nil, err
+	}
+	return completion, nil
+}
+
+// respond returns the first scripted response matching the last message
+func (mp *MockProvider) respond(messages []Message) (*MockResponse, error) {
+	var content, role string
+	if len(messages) > 0 {
+		last := messages[len(messages)-1]
+		content, role = MessageText(last), mockMessageRole(last)
+	}
+
+	for i := range mp.Script.Responses {
+		response := &mp.Script.Responses[i]
+		if response.Role != "" && response.Role != role {
+			continue
+		}
+		if response.match != nil && !response.match.MatchString(content) {
+			continue
+		}
+		return response, nil
+	}
+	return nil, fmt.Errorf("mock model %s has no response matching the %s message", mp.Model, role)
+}
+
+func mockMessageRole(message Message) string {
+	switch {
+	case message.OfUser != nil:
+		return "user"
+	case message.OfAssistant != nil:
+		return "assistant"
+	case message.OfTool != nil:
+		return "tool"
+	case message.OfSystem != nil:
+		return "system"
+	case message.OfDeveloper != nil:
+		return "developer"
+	default:
+		return ""
+	}
+}
+
+func (mp *MockProvider) completion(response *MockResponse, messages []Message, tools ...[]openai.ChatCompletionToolParam) *openai.ChatCompletion {
+	call := mp.calls.Add(1)
+	message := openai.ChatCompletionMessage{Role: "assistant", Content: response.Content}
+	finishReason := "stop"
+	for i, toolCall := range response.ToolCalls {
+		arguments := toolCall.Arguments
+		if arguments == "" {
+			arguments = "{}"
+		}
+		message.ToolCalls = append(message.ToolCalls, openai.ChatCompletionMessageToolCall{
+			ID:       fmt.Sprintf("mock-call-%d-%d", call, i),
+			Type:     "function",
+			Function: openai.ChatCompletionMessageToolCallFunction{Name: toolCall.Name, Arguments: arguments},
+		})
+		finishReason = "tool_calls"
+	}
+
+	promptTokens := int64(estimateTokens(messages) + estimateToolTokens(tools...))
+	completionTokens := int64(len(response.Content)/charactersPerToken + 1)
+	return &openai.ChatCompletion{
+		ID:      fmt.Sprintf("mock-%d", call),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   mp.Model,
+		Choices: []openai.ChatCompletionChoice{{Message: message, FinishReason: finishReason}},
+		Usage: openai.CompletionUsage{
+			PromptTokens:     promptTokens,
+			CompletionTokens: completionTokens,
+			TotalTokens:      promptTokens + completionTokens,
+		},
+	}
+}
@@ -0,0 +1,96 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+const testMockScript = `
+latency: 1ms
+responses:
+- role: tool
+  content: It is sunny in Chicago
+- match: (?i)weather
+  toolCalls:
+  - name: get-weather
+    arguments: '{"city":"Chicago"}'
+- match: fail
+  error: rate limited
+- content: Hello
+`
+
+func TestMockModel(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "mock-script", Namespace: "default"},
+		Data:       map[string]string{"script.yaml": testMockScript},
+	}).Build()
+
+	model, err := MakeModel(ctx, k8sClient, &arkv1alpha1.Model{
+		ObjectMeta: metav1.ObjectMeta{Name: "mock", Namespace: "default"},
+		Spec: arkv1alpha1.ModelSpec{
+			Type:  ModelTypeMock,
+			Model: arkv1alpha1.ValueSource{Value: "mock-gpt"},
+			Config: arkv1alpha1.ModelConfig{Mock: &arkv1alpha1.MockModelConfig{
+				Script: arkv1alpha1.ValueSource{ValueFrom: &arkv1alpha1.ValueFromSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "mock-script"}, Key: "script.yaml"},
+				}},
+			}},
+		},
+	}, noop.NewModelRecorder())
+	require.NoError(t, err)
+
+	// The first matching response answers, and tool calls are emulated
+	messages := []Message{NewUserMessage("What is the weather?")}
+	response, err := model.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, response.Choices[0].Message.ToolCalls, 1)
+	call := response.Choices[0].Message.ToolCalls[0]
+	assert.Equal(t, "get-weather", call.Function.Name)
+	assert.Equal(t, `{"city":"Chicago"}`, call.Function.Arguments)
+	assert.Equal(t, "tool_calls", response.Choices[0].FinishReason)
+	assert.Positive(t, response.Usage.PromptTokens)
+
+	messages = append(messages, Message(openai.ToolMessage("72F", call.ID)))
+	response, err = model.ChatCompletion(ctx, messages, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "It is sunny in Chicago", response.Choices[0].Message.Content)
+
+	_, err = model.ChatCompletion(ctx, []Message{NewUserMessage("fail please")}, nil, 1)
+	assert.ErrorContains(t, err, "rate limited")
+
+	assert.True(t, ProbeModel(ctx, model).Available)
+}
+
+func TestParseMockScript(t *testing.T) {
+	_, err := ParseMockScript("responses: []")
+	assert.ErrorContains(t, err, "at least one response")
+
+	_, err = ParseMockScript("responses:\n- toolCalls:\n  - arguments: '{}'\n")
+	assert.ErrorContains(t, err, "tool calls require a name")
+
+	_, err = ParseMockScript("responses:\n- reply: hi\n")
+	assert.ErrorContains(t, err, "unknown field")
+
+	provider := &MockProvider{Model: "mock"}
+	provider.Script, err = ParseMockScript("responses:\n- match: ^hi$\n  content: hello\n")
+	require.NoError(t, err)
+	_, err = provider.ChatCompletion(context.Background(), []Message{NewUserMessage("bye")}, 1)
+	assert.ErrorContains(t, err, "no response matching the user message")
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/openai/openai-go/option"
 	"github.com/openai/openai-go/shared/constant"
 	"k8s.io/apimachinery/pkg/runtime"
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 )
@@ -20,6 +21,7 @@ type OpenAIProvider struct {
 	Properties   map[string]string
 	outputSchema *runtime.RawExtension
 	schemaName   string
+	parameters   *arkv1alpha1.ModelParameters
 }
 
 func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaName string) {
@@ -27,6 +29,10 @@ func (op *OpenAIProvider) SetOutputSchema(schema *runtime.RawExtension, schemaNa
 	op.schemaName = schemaName
 }
 
+func (op *OpenAIProvider) SetModelParameters(parameters *arkv1alpha1.ModelParameters) {
+	op.parameters = parameters
+}
+
 func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message, n int64, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
 	openaiMessages := make([]openai.ChatCompletionMessageParamUnion, len(messages))
 	for i, msg := range messages {
@@ -40,10 +46,13 @@ func (op *OpenAIProvider) ChatCompletion(ctx context.Context, messages []Message
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyModelParametersToParams(op.parameters, &params)
+	applyReasoningToParams(op.Model, op.parameters, &params)
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
 	}
+	applyToolChoiceToParams(op.parameters, &params)
 
 	// Apply structured output schema if provided
 	applyStructuredOutputToParams(op.outputSchema, op.schemaName, &params)
@@ -70,6 +79,11 @@ func accumulateStreamChunk(chunk *openai.ChatCompletionChunk, fullResponse **ope
 		}
 	}
 
+	// The usage is sent in a last chunk without choices
+	if chunk.JSON.Usage.Valid() {
+		(*fullResponse).Usage = chunk.Usage
+	}
+
 	if len(chunk.Choices) == 0 {
 		return
 	}
@@ -199,10 +213,14 @@ func (op *OpenAIProvider) prepareStreamParams(messages []Message, n int64, tools
 	}
 
 	applyPropertiesToParams(op.Properties, &params)
+	applyModelParametersToParams(op.parameters, &params)
+	applyReasoningToParams(op.Model, op.parameters, &params)
+	params.StreamOptions = openai.ChatCompletionStreamOptionsParam{IncludeUsage: openai.Bool(true)}
 
 	if len(tools) > 0 && len(tools[0]) > 0 {
 		params.Tools = tools[0]
 	}
+	applyToolChoiceToParams(op.parameters, &params)
 
 	// Apply structured output schema if provided
 	applyStructuredOutputToParams(op.outputSchema, op.schemaName, &params)
@@ -247,14 +265,8 @@ func (op *OpenAIProvider) ChatCompletionStream(ctx context.Context, messages []M
 		return nil, fmt.Errorf("streaming completed but no response was accumulated")
 	}
 
-	// Initialize usage if not present (streaming responses may not include usage)
-	if fullResponse.Usage.TotalTokens == 0 {
-		fullResponse.Usage = openai.CompletionUsage{
-			PromptTokens:     0,
-			CompletionTokens: 0,
-			TotalTokens:      0,
-		}
-	}
+	// Servers that ignore the stream options report no usage, and some report no total
+	normalizeUsage(&fullResponse.Usage)
 
 	return fullResponse, nil
 }
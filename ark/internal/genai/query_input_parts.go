@@ -0,0 +1,120 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// newUserMessageWithParts returns a user message with the resolved input as its first text part,
+// followed by the input parts of the query
+func newUserMessageWithParts(ctx context.Context, k8sClient client.Client, query arkv1alpha1.Query, input string) (Message, error) {
+	contentParts := []openai.ChatCompletionContentPartUnionParam{}
+	if input != "" {
+		contentParts = append(contentParts, openai.TextContentPart(input))
+	}
+
+	for i, part := range query.Spec.Parts {
+		contentPart, err := resolveInputPart(ctx, k8sClient, query, part)
+		if err != nil {
+			return Message{}, fmt.Errorf("failed to resolve parts[%d]: %w", i, err)
+		}
+		contentParts = append(contentParts, contentPart)
+	}
+
+	return Message(openai.UserMessage(contentParts)), nil
+}
+
+func resolveInputPart(ctx context.Context, k8sClient client.Client, query arkv1alpha1.Query, part arkv1alpha1.QueryInputPart) (openai.ChatCompletionContentPartUnionParam, error) {
+	switch part.Type {
+	case arkv1alpha1.QueryInputPartText:
+		text, err := ResolveQueryInput(ctx, k8sClient, &query, part.Text)
+		if err != nil {
+			return openai.ChatCompletionContentPartUnionParam{}, err
+		}
+		return openai.TextContentPart(text), nil
+	case arkv1alpha1.QueryInputPartImage:
+		imageURL := part.ImageURL
+		if imageURL == "" {
+			content, _, err := loadInputPartContent(ctx, k8sClient, query.Namespace, part.ValueFrom)
+			if err != nil {
+				return openai.ChatCompletionContentPartUnionParam{}, err
+			}
+			imageURL = dataURL(inputPartMimeType(part, content), content)
+		}
+		return openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: imageURL}), nil
+	case arkv1alpha1.QueryInputPartFile:
+		content, key, err := loadInputPartContent(ctx, k8sClient, query.Namespace, part.ValueFrom)
+		if err != nil {
+			return openai.ChatCompletionContentPartUnionParam{}, err
+		}
+		filename := part.Filename
+		if filename == "" {
+			filename = key
+		}
+		return openai.FileContentPart(openai.ChatCompletionContentPartFileFileParam{
+			FileData: openai.String(dataURL(inputPartMimeType(part, content), content)),
+			Filename: openai.String(filename),
+		}), nil
+	default:
+		return openai.ChatCompletionContentPartUnionParam{}, fmt.Errorf("unsupported part type: %s", part.Type)
+	}
+}
+
+// loadInputPartContent returns the content of an input part and the ConfigMap key it was read from
+func loadInputPartContent(ctx context.Context, k8sClient client.Client, namespace string, source *arkv1alpha1.QueryInputPartSource) ([]byte, string, error) {
+	if source == nil || source.ConfigMapKeyRef == nil {
+		return nil, "", fmt.Errorf("part has no content source")
+	}
+	ref := source.ConfigMapKeyRef
+
+	var configMap corev1.ConfigMap
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: namespace}, &configMap); err != nil {
+		return nil, "", fmt.Errorf("failed to get configMap %s: %w", ref.Name, err)
+	}
+	if content, ok := configMap.BinaryData[ref.Key]; ok {
+		return content, ref.Key, nil
+	}
+	if content, ok := configMap.Data[ref.Key]; ok {
+		return []byte(content), ref.Key, nil
+	}
+	return nil, "", fmt.Errorf("key %s not found in configMap %s", ref.Key, ref.Name)
+}
+
+func inputPartMimeType(part arkv1alpha1.QueryInputPart, content []byte) string {
+	if part.MimeType != "" {
+		return part.MimeType
+	}
+	// Drop parameters such as the charset from the detected type
+	mimeType, _, _ := strings.Cut(http.DetectContentType(content), ";")
+	return mimeType
+}
+
+func dataURL(mimeType string, content []byte) string {
+	return fmt.Sprintf("data:%s;base64,%s", mimeType, base64.StdEncoding.EncodeToString(content))
+}
+
+// messageMediaTypes reports whether the messages contain images and files
+func messageMediaTypes(messages []Message) (hasImages, hasFiles bool) {
+	for _, msg := range messages {
+		if msg.OfUser == nil {
+			continue
+		}
+		for _, part := range msg.OfUser.Content.OfArrayOfContentParts {
+			hasImages = hasImages || part.OfImageURL != nil
+			hasFiles = hasFiles || part.OfFile != nil
+		}
+	}
+	return hasImages, hasFiles
+}
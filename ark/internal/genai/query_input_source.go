@@ -0,0 +1,205 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const queryInputFetchTimeout = 30 * time.Second
+
+// QueryInputString returns the input of a user query before template resolution, read from
+// inputFrom when it is set
+func QueryInputString(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query) (string, error) {
+	if query.Spec.InputFrom == nil {
+		return query.Spec.GetInputString()
+	}
+	if query.Spec.Type != "" && query.Spec.Type != arkv1alpha1.QueryTypeUser {
+		return "", fmt.Errorf("cannot get string input for type=%s, expected type=%s or empty", query.Spec.Type, arkv1alpha1.QueryTypeUser)
+	}
+	return ReadQueryInputSource(ctx, k8sClient, query.Namespace, query.Spec.InputFrom)
+}
+
+// queryInputMessages returns the input of a messages query, read from inputFrom when it is set
+func queryInputMessages(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query) ([]openai.ChatCompletionMessageParamUnion, error) {
+	if query.Spec.InputFrom == nil {
+		return query.Spec.GetInputMessages()
+	}
+
+	content, err := ReadQueryInputSource(ctx, k8sClient, query.Namespace, query.Spec.InputFrom)
+	if err != nil {
+		return nil, err
+	}
+	var messages []openai.ChatCompletionMessageParamUnion
+	if err := json.Unmarshal([]byte(content), &messages); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal input as messages: %w", err)
+	}
+	return messages, nil
+}
+
+// ReadQueryInputSource reads the content of an inputFrom source, rejecting content larger than its
+// size limit
+func ReadQueryInputSource(ctx context.Context, k8sClient client.Client, namespace string, source *arkv1alpha1.QueryInputSource) (string, error) {
+	if err := ValidateQueryInputSource(source); err != nil {
+		return "", err
+	}
+	maxBytes := source.MaxBytes
+	if maxBytes <= 0 {
+		maxBytes = arkv1alpha1.DefaultQueryInputMaxBytes
+	}
+
+	var content string
+	var err error
+	switch {
+	case source.ConfigMapKeyRef != nil:
+		content, err = resolveQueryValueFrom(ctx, k8sClient, namespace, nil, &arkv1alpha1.ValueFromSource{ConfigMapKeyRef: source.ConfigMapKeyRef})
+	case source.SecretKeyRef != nil:
+		content, err = resolveQueryValueFrom(ctx, k8sClient, namespace, nil, &arkv1alpha1.ValueFromSource{SecretKeyRef: source.SecretKeyRef})
+	default:
+		content, err = fetchQueryInput(ctx, k8sClient, namespace, source.URL, maxBytes)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+
+	if int64(len(content)) > maxBytes {
+		return "", fmt.Errorf("input exceeds the limit of %d bytes", maxBytes)
+	}
+	return content, nil
+}
+
+// ValidateQueryInputSource returns an error unless exactly one source is set
+func ValidateQueryInputSource(source *arkv1alpha1.QueryInputSource) error {
+	sources := 0
+	for _, set := range []bool{source.ConfigMapKeyRef != nil, source.SecretKeyRef != nil, source.URL != ""} {
+		if set {
+			sources++
+		}
+	}
+	if sources != 1 {
+		return fmt.Errorf("inputFrom must specify exactly one of configMapKeyRef, secretKeyRef or url")
+	}
+	return nil
+}
+
+// queryInputAddressAllowed refuses connections to loopback, link-local, private and multicast
+// addresses, so inputs cannot be read from the cluster network or the cloud metadata service.
+// Replaced in tests.
+var queryInputAddressAllowed = func(ip net.IP) bool {
+	return !ip.IsLoopback() && !ip.IsLinkLocalUnicast() && !ip.IsLinkLocalMulticast() &&
+		!ip.IsPrivate() && !ip.IsUnspecified() && !ip.IsMulticast()
+}
+
+// queryInputAllowedHosts returns the hosts the namespace allows inputFrom URLs to be fetched from. The
+// namespace is read with the tool policy client, since the identity a query runs as may not be
+// allowed to read namespaces.
+func queryInputAllowedHosts(ctx context.Context, namespace string) ([]string, error) {
+	policyClient, err := toolPolicyClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	ns := &corev1.Namespace{}
+	if err := policyClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	return splitAnnotationList(ns.Annotations[annotations.QueryInputAllowedHosts]), nil
+}
+
+// newQueryInputHTTPClient returns the client inputFrom URLs are fetched with. Only allowed hosts can
+// be fetched, including through redirects, and only at public addresses. Proxies are not used, so the
+// address checked is the one connected to.
+func newQueryInputHTTPClient(allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: queryInputFetchTimeout,
+		Control: func(_, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip := net.ParseIP(host); ip == nil || !queryInputAddressAllowed(ip) {
+				return fmt.Errorf("address %s is not allowed for query input", host)
+			}
+			return nil
+		},
+	}
+	return &http.Client{
+		Timeout:   queryInputFetchTimeout,
+		Transport: &http.Transport{DialContext: dialer.DialContext, TLSHandshakeTimeout: 10 * time.Second},
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 10 {
+				return fmt.Errorf("stopped after 10 redirects")
+			}
+			return checkQueryInputURL(req.URL, allowedHosts)
+		},
+	}
+}
+
+func checkQueryInputURL(inputURL *url.URL, allowedHosts []string) error {
+	if inputURL.Scheme != "http" && inputURL.Scheme != "https" {
+		return fmt.Errorf("invalid URL %s: must be an http or https URL", inputURL)
+	}
+	if len(allowedHosts) == 0 {
+		return fmt.Errorf("URL inputs require the namespace annotation %s", annotations.QueryInputAllowedHosts)
+	}
+	if !IsHostAllowed(inputURL.Hostname(), allowedHosts) {
+		return fmt.Errorf("host '%s' is not in the allowed hosts of the namespace", inputURL.Hostname())
+	}
+	return nil
+}
+
+// fetchQueryInput reads one byte past the limit so oversized responses are rejected instead of
+// truncated
+func fetchQueryInput(ctx context.Context, k8sClient client.Client, namespace, rawURL string, maxBytes int64) (string, error) {
+	inputURL, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	allowedHosts, err := queryInputAllowedHosts(ctx, namespace)
+	if err != nil {
+		return "", err
+	}
+	if err := checkQueryInputURL(inputURL, allowedHosts); err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("invalid URL %s: %w", rawURL, err)
+	}
+	resp, err := newQueryInputHTTPClient(allowedHosts).Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch %s: %w", rawURL, err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode >= 400 {
+		return "", fmt.Errorf("HTTP error %d fetching %s", resp.StatusCode, rawURL)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxBytes+1))
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", rawURL, err)
+	}
+	return string(body), nil
+}
@@ -0,0 +1,154 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+func newInputSourceClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "prompts", Namespace: "default"},
+			Data: map[string]string{
+				"review":   "Review the {{.component}} release notes",
+				"messages": `[{"role": "user", "content": "Hello"}]`,
+			},
+		},
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "private-prompts", Namespace: "default"},
+			Data:       map[string][]byte{"prompt": []byte("Summarize the incident")},
+		},
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+			Name:        "default",
+			Annotations: map[string]string{annotations.QueryInputAllowedHosts: "127.0.0.1"},
+		}},
+	).Build()
+}
+
+// allowLoopbackInputs lets inputs be fetched from test servers for the rest of the test
+func allowLoopbackInputs(t *testing.T) {
+	original := queryInputAddressAllowed
+	queryInputAddressAllowed = func(ip net.IP) bool { return ip.IsLoopback() }
+	t.Cleanup(func() { queryInputAddressAllowed = original })
+}
+
+func configMapInput(key string) *arkv1alpha1.QueryInputSource {
+	return &arkv1alpha1.QueryInputSource{
+		ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prompts"}, Key: key},
+	}
+}
+
+func TestGetQueryInputMessagesFromConfigMap(t *testing.T) {
+	k8sClient := newInputSourceClient(t)
+	query := arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "default"},
+		Spec: arkv1alpha1.QuerySpec{
+			InputFrom:  configMapInput("review"),
+			Parameters: []arkv1alpha1.Parameter{{Name: "component", Value: "controller"}},
+		},
+	}
+
+	messages, err := GetQueryInputMessages(context.Background(), query, k8sClient)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Review the controller release notes", messages[0].OfUser.Content.OfString.Value)
+
+	query.Spec.Type = arkv1alpha1.QueryTypeMessages
+	query.Spec.InputFrom = configMapInput("messages")
+	messages, err = GetQueryInputMessages(context.Background(), query, k8sClient)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Hello", messages[0].OfUser.Content.OfString.Value)
+}
+
+func TestReadQueryInputSource(t *testing.T) {
+	allowLoopbackInputs(t)
+	k8sClient := newInputSourceClient(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("Prompt from URL"))
+	}))
+	t.Cleanup(server.Close)
+	ctx := WithToolPolicyClient(context.Background(), k8sClient)
+
+	content, err := ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{
+		SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "private-prompts"}, Key: "prompt"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Summarize the incident", content)
+
+	content, err = ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: server.URL + "/prompt.txt"})
+	require.NoError(t, err)
+	assert.Equal(t, "Prompt from URL", content)
+
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: server.URL + "/missing"})
+	assert.ErrorContains(t, err, "HTTP error 404")
+
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: server.URL, MaxBytes: 6})
+	assert.ErrorContains(t, err, "exceeds the limit of 6 bytes")
+
+	source := configMapInput("review")
+	source.MaxBytes = 6
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", source)
+	assert.ErrorContains(t, err, "exceeds the limit")
+
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", configMapInput("missing"))
+	assert.ErrorContains(t, err, "key missing not found in ConfigMap prompts")
+}
+
+func TestReadQueryInputSourceURLPolicy(t *testing.T) {
+	k8sClient := newInputSourceClient(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "http://metadata.google.internal/computeMetadata/v1/", http.StatusFound)
+			return
+		}
+		_, _ = w.Write([]byte("Prompt from URL"))
+	}))
+	t.Cleanup(server.Close)
+	ctx := WithToolPolicyClient(context.Background(), k8sClient)
+
+	_, err := ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: server.URL})
+	assert.ErrorContains(t, err, "address 127.0.0.1 is not allowed for query input")
+
+	allowLoopbackInputs(t)
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: server.URL + "/redirect"})
+	assert.ErrorContains(t, err, "host 'metadata.google.internal' is not in the allowed hosts of the namespace")
+
+	_, err = ReadQueryInputSource(ctx, k8sClient, "default", &arkv1alpha1.QueryInputSource{URL: "http://example.com/prompt.txt"})
+	assert.ErrorContains(t, err, "host 'example.com' is not in the allowed hosts of the namespace")
+
+	_, err = ReadQueryInputSource(ctx, k8sClient, "team-a", &arkv1alpha1.QueryInputSource{URL: server.URL})
+	assert.ErrorContains(t, err, "URL inputs require the namespace annotation "+annotations.QueryInputAllowedHosts)
+}
+
+func TestValidateQueryInputSource(t *testing.T) {
+	source := configMapInput("review")
+	assert.NoError(t, ValidateQueryInputSource(source))
+
+	source.URL = "https://example.com/prompt.txt"
+	assert.ErrorContains(t, ValidateQueryInputSource(source), "exactly one of configMapKeyRef, secretKeyRef or url")
+	assert.ErrorContains(t, ValidateQueryInputSource(&arkv1alpha1.QueryInputSource{}), "exactly one of")
+}
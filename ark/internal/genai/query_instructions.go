@@ -0,0 +1,95 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type queryInstructionsContextKey struct{}
+
+// QueryInstructions are the system prompt and context messages a query adds to the conversation
+// of its targets. The prompt of an agent comes first, followed by the query system prompt, so an
+// agent keeps its own instructions and the query refines them. Context messages follow the
+// conversation history, right before the input.
+type QueryInstructions struct {
+	SystemPrompt string
+	Context      []Message
+}
+
+// ResolveQueryInstructions returns the instructions of a query with its template parameters
+// resolved, or nil when the query adds none
+func ResolveQueryInstructions(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query) (*QueryInstructions, error) {
+	if query.Spec.SystemPrompt == "" && len(query.Spec.ContextMessages) == 0 {
+		return nil, nil
+	}
+
+	instructions := &QueryInstructions{}
+	if query.Spec.SystemPrompt != "" {
+		prompt, err := ResolveQueryInput(ctx, k8sClient, query, query.Spec.SystemPrompt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve system prompt: %w", err)
+		}
+		instructions.SystemPrompt = prompt
+	}
+	for i, message := range query.Spec.ContextMessages {
+		content, err := ResolveQueryInput(ctx, k8sClient, query, message.Content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to resolve context message %d: %w", i, err)
+		}
+		switch message.Role {
+		case RoleSystem:
+			instructions.Context = append(instructions.Context, NewSystemMessage(content))
+		case RoleAssistant:
+			instructions.Context = append(instructions.Context, NewAssistantMessage(content))
+		default:
+			instructions.Context = append(instructions.Context, NewUserMessage(content))
+		}
+	}
+	return instructions, nil
+}
+
+// WithQueryInstructions returns a context in which agents add the query instructions to their
+// conversation
+func WithQueryInstructions(ctx context.Context, instructions *QueryInstructions) context.Context {
+	return context.WithValue(ctx, queryInstructionsContextKey{}, instructions)
+}
+
+// QueryInstructionsFromContext returns the instructions set by WithQueryInstructions, or nil
+func QueryInstructionsFromContext(ctx context.Context) *QueryInstructions {
+	instructions, _ := ctx.Value(queryInstructionsContextKey{}).(*QueryInstructions)
+	return instructions
+}
+
+// Prompt returns the prompt of an agent followed by the query system prompt
+func (q *QueryInstructions) Prompt(agentPrompt string) string {
+	if q == nil || q.SystemPrompt == "" {
+		return agentPrompt
+	}
+	if agentPrompt == "" {
+		return q.SystemPrompt
+	}
+	return agentPrompt + "\n\n" + q.SystemPrompt
+}
+
+// SystemMessages returns the query system prompt as the leading message of targets without a
+// prompt of their own, such as models
+func (q *QueryInstructions) SystemMessages() []Message {
+	if q == nil || q.SystemPrompt == "" {
+		return nil
+	}
+	return []Message{NewSystemMessage(q.SystemPrompt)}
+}
+
+// History returns the history followed by the context messages of the query
+func (q *QueryInstructions) History(history []Message) []Message {
+	if q == nil || len(q.Context) == 0 {
+		return history
+	}
+	return append(append([]Message{}, history...), q.Context...)
+}
@@ -0,0 +1,94 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestResolveQueryInstructions(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	t.Run("no instructions", func(t *testing.T) {
+		query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "default"}}
+		instructions, err := ResolveQueryInstructions(context.Background(), k8sClient, query)
+		require.NoError(t, err)
+		assert.Nil(t, instructions)
+	})
+
+	t.Run("templates are resolved", func(t *testing.T) {
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "default"},
+			Spec: arkv1alpha1.QuerySpec{
+				Parameters:   []arkv1alpha1.Parameter{{Name: "language", Value: "German"}},
+				SystemPrompt: "Answer in {{.language}}",
+				ContextMessages: []arkv1alpha1.QueryContextMessage{
+					{Role: RoleUser, Content: "I live in Berlin"},
+					{Role: RoleAssistant, Content: "Noted, you speak {{.language}}"},
+				},
+			},
+		}
+		instructions, err := ResolveQueryInstructions(context.Background(), k8sClient, query)
+		require.NoError(t, err)
+		assert.Equal(t, "Answer in German", instructions.SystemPrompt)
+		require.Len(t, instructions.Context, 2)
+		assert.Equal(t, "I live in Berlin", instructions.Context[0].OfUser.Content.OfString.Value)
+		assert.Equal(t, "Noted, you speak German", instructions.Context[1].OfAssistant.Content.OfString.Value)
+	})
+
+	t.Run("invalid template", func(t *testing.T) {
+		query := &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "default"},
+			Spec: arkv1alpha1.QuerySpec{
+				Parameters:   []arkv1alpha1.Parameter{{Name: "language", Value: "German"}},
+				SystemPrompt: "Answer in {{.language",
+			},
+		}
+		_, err := ResolveQueryInstructions(context.Background(), k8sClient, query)
+		assert.ErrorContains(t, err, "failed to resolve system prompt")
+	})
+}
+
+func TestQueryInstructionsPrecedence(t *testing.T) {
+	var none *QueryInstructions
+	assert.Equal(t, "agent", none.Prompt("agent"))
+	assert.Nil(t, none.SystemMessages())
+
+	instructions := &QueryInstructions{SystemPrompt: "query", Context: []Message{NewUserMessage("context")}}
+	assert.Equal(t, "agent\n\nquery", instructions.Prompt("agent"))
+	assert.Equal(t, "query", instructions.Prompt(""))
+	require.Len(t, instructions.SystemMessages(), 1)
+
+	history := []Message{NewUserMessage("earlier")}
+	merged := instructions.History(history)
+	require.Len(t, merged, 2)
+	assert.Equal(t, "context", merged[1].OfUser.Content.OfString.Value)
+	assert.Len(t, history, 1)
+}
+
+func TestAgentMessagesWithQueryInstructions(t *testing.T) {
+	agent := &Agent{Name: "writer", Namespace: "default", Prompt: "You are a writer"}
+	ctx := WithQueryInstructions(context.Background(), &QueryInstructions{
+		SystemPrompt: "Keep it short",
+		Context:      []Message{NewAssistantMessage("Sure")},
+	})
+
+	messages, err := agent.prepareMessages(ctx, NewUserMessage("Write a poem"), []Message{NewUserMessage("Hello")})
+	require.NoError(t, err)
+	require.Len(t, messages, 4)
+	assert.Equal(t, "You are a writer\n\nKeep it short", messages[0].OfSystem.Content.OfString.Value)
+	assert.Equal(t, "Hello", messages[1].OfUser.Content.OfString.Value)
+	assert.Equal(t, "Sure", messages[2].OfAssistant.Content.OfString.Value)
+	assert.Equal(t, "Write a poem", messages[3].OfUser.Content.OfString.Value)
+}
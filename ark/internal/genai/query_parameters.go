@@ -3,8 +3,10 @@ package genai
 import (
 	"context"
 	"fmt"
+	"slices"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -12,24 +14,32 @@ import (
 	"mckinsey.com/ark/internal/common"
 )
 
-func ResolveQueryInput(ctx context.Context, k8sClient client.Client, namespace, input string, parameters []arkv1alpha1.Parameter) (string, error) {
-	if len(parameters) == 0 {
+func ResolveQueryInput(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query, input string) (string, error) {
+	if len(query.Spec.Parameters) == 0 && len(query.Spec.ParameterSchema) == 0 {
 		return input, nil
 	}
 
-	templateData, err := resolveQueryParameters(ctx, k8sClient, namespace, parameters)
+	params, err := resolveQueryParameters(ctx, k8sClient, query.Namespace, query, query.Spec.Parameters)
 	if err != nil {
 		return "", fmt.Errorf("failed to resolve parameters: %w", err)
 	}
 
-	resolved, err := common.ResolveTemplate(input, toAnyMap(templateData))
+	templateData := toAnyMap(params)
+	if len(query.Spec.ParameterSchema) > 0 {
+		if templateData, err = applyParameterSchema(query.Spec.ParameterSchema, params); err != nil {
+			return "", fmt.Errorf("invalid parameters: %w", err)
+		}
+	}
+
+	resolved, err := common.ResolveTemplate(input, templateData)
 	if err != nil {
 		return "", fmt.Errorf("template resolution failed: %w", err)
 	}
 	return resolved, nil
 }
 
-func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namespace string, parameters []arkv1alpha1.Parameter) (map[string]string, error) {
+// resolveQueryParameters resolves parameters declared by resource, which resourceFieldRef refers to
+func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namespace string, resource metav1.Object, parameters []arkv1alpha1.Parameter) (map[string]string, error) {
 	templateData := make(map[string]string)
 
 	for _, param := range parameters {
@@ -42,7 +52,7 @@ func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namesp
 			return nil, fmt.Errorf("parameter %s must specify either value or valueFrom", param.Name)
 		}
 
-		value, err := resolveQueryValueFrom(ctx, k8sClient, namespace, param.ValueFrom)
+		value, err := resolveQueryValueFrom(ctx, k8sClient, namespace, resource, param.ValueFrom)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve parameter %s: %w", param.Name, err)
 		}
@@ -52,7 +62,42 @@ func resolveQueryParameters(ctx context.Context, k8sClient client.Client, namesp
 	return templateData, nil
 }
 
-func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespace string, valueFrom *arkv1alpha1.ValueFromSource) (string, error) {
+// applyParameterSchema validates resolved parameter values against the schema, fills in defaults
+// and converts the values to their declared types for the template. Optional parameters without a
+// value or default resolve to an empty string.
+func applyParameterSchema(schema []arkv1alpha1.QueryTemplateParameter, values map[string]string) (map[string]any, error) {
+	for name := range values {
+		if !slices.ContainsFunc(schema, func(p arkv1alpha1.QueryTemplateParameter) bool { return p.Name == name }) {
+			return nil, fmt.Errorf("unknown parameter %s", name)
+		}
+	}
+
+	templateData := make(map[string]any, len(schema))
+	for _, declared := range schema {
+		value, ok := values[declared.Name]
+		if !ok {
+			if declared.Required {
+				return nil, fmt.Errorf("parameter %s is required", declared.Name)
+			}
+			if declared.Default == "" {
+				templateData[declared.Name] = ""
+				continue
+			}
+			value = declared.Default
+		}
+		if err := declared.ValidateValue(value); err != nil {
+			return nil, err
+		}
+		typed, err := declared.TypedValue(value)
+		if err != nil {
+			return nil, err
+		}
+		templateData[declared.Name] = typed
+	}
+	return templateData, nil
+}
+
+func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespace string, resource metav1.Object, valueFrom *arkv1alpha1.ValueFromSource) (string, error) {
 	if valueFrom.ConfigMapKeyRef != nil {
 		configMap := &corev1.ConfigMap{}
 		key := types.NamespacedName{Name: valueFrom.ConfigMapKeyRef.Name, Namespace: namespace}
@@ -81,11 +126,29 @@ func resolveQueryValueFrom(ctx context.Context, k8sClient client.Client, namespa
 		return string(value), nil
 	}
 
+	if valueFrom.QueryFieldRef != nil {
+		query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+		if !ok {
+			// Query parameters are resolved before the query context is set
+			if query, ok = resource.(*arkv1alpha1.Query); !ok {
+				return "", fmt.Errorf("queryFieldRef requires a query context")
+			}
+		}
+		return common.ResolveQueryFieldPath(query, valueFrom.QueryFieldRef.FieldPath)
+	}
+
+	if valueFrom.ResourceFieldRef != nil {
+		if resource == nil {
+			return "", fmt.Errorf("resourceFieldRef is not supported here")
+		}
+		return common.ResolveResourceFieldPath(resource, valueFrom.ResourceFieldRef.FieldPath)
+	}
+
 	return "", fmt.Errorf("no supported valueFrom source specified")
 }
 
-// ResolveBodyTemplate resolves body template with parameters and input data
-func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace, bodyTemplate string, parameters []arkv1alpha1.Parameter, inputData map[string]any) (string, error) {
+// ResolveBodyTemplate resolves body template with parameters of the tool and input data
+func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, tool *arkv1alpha1.Tool, bodyTemplate string, parameters []arkv1alpha1.Parameter, inputData map[string]any) (string, error) {
 	if bodyTemplate == "" {
 		return "", nil
 	}
@@ -97,7 +160,7 @@ func ResolveBodyTemplate(ctx context.Context, k8sClient client.Client, namespace
 	}
 
 	if len(parameters) > 0 {
-		paramData, err := resolveQueryParameters(ctx, k8sClient, namespace, parameters)
+		paramData, err := resolveQueryParameters(ctx, k8sClient, tool.Namespace, tool, parameters)
 		if err != nil {
 			return "", fmt.Errorf("failed to resolve body parameters: %w", err)
 		}
@@ -122,20 +185,27 @@ func GetQueryInputMessages(ctx context.Context, query arkv1alpha1.Query, k8sClie
 	}
 
 	if queryType == RoleUser {
-		// For 'user' type (default), get input string using helper method
-		inputString, err := query.Spec.GetInputString()
+		// For 'user' type (default), get input string from input or inputFrom
+		inputString, err := QueryInputString(ctx, k8sClient, &query)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get input string: %w", err)
 		}
 
 		// Resolve input with template parameters and create a single user message
-		resolvedInput, err := ResolveQueryInput(ctx, k8sClient, query.Namespace, inputString, query.Spec.Parameters)
+		resolvedInput, err := ResolveQueryInput(ctx, k8sClient, &query, inputString)
 		if err != nil {
 			return nil, fmt.Errorf("failed to resolve query input: %w", err)
 		}
+		if len(query.Spec.Parts) > 0 {
+			message, err := newUserMessageWithParts(ctx, k8sClient, query, resolvedInput)
+			if err != nil {
+				return nil, err
+			}
+			return []Message{message}, nil
+		}
 		return []Message{NewUserMessage(resolvedInput)}, nil
 	} else {
-		openaiMessages, err := query.Spec.GetInputMessages()
+		openaiMessages, err := queryInputMessages(ctx, k8sClient, &query)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get input messages: %w", err)
 		}
@@ -281,6 +281,170 @@ func TestGetQueryInputMessages(t *testing.T) {
 		require.NoError(t, err)
 		require.Len(t, messages, 0)
 	})
+
+	t.Run("user type with parts", func(t *testing.T) {
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "attachments",
+				Namespace: "test-ns",
+			},
+			BinaryData: map[string][]byte{
+				"chart.png": {0x89, 'P', 'N', 'G', '\r', '\n', 0x1a, '\n'},
+			},
+			Data: map[string]string{
+				"notes.txt": "quarterly notes",
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+		query := arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-query",
+				Namespace: "test-ns",
+			},
+			Spec: arkv1alpha1.QuerySpec{
+				Type:       "user",
+				Parameters: []arkv1alpha1.Parameter{{Name: "focus", Value: "revenue"}},
+				Parts: []arkv1alpha1.QueryInputPart{
+					{Type: arkv1alpha1.QueryInputPartImage, ImageURL: "https://example.com/cat.png"},
+					{Type: arkv1alpha1.QueryInputPartImage, ValueFrom: &arkv1alpha1.QueryInputPartSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "attachments"}, Key: "chart.png"},
+					}},
+					{Type: arkv1alpha1.QueryInputPartFile, ValueFrom: &arkv1alpha1.QueryInputPartSource{
+						ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "attachments"}, Key: "notes.txt"},
+					}},
+					{Type: arkv1alpha1.QueryInputPartText, Text: "Focus on {{.focus}}"},
+				},
+			},
+		}
+		require.NoError(t, query.Spec.SetInputString("Describe these attachments"))
+
+		messages, err := GetQueryInputMessages(ctx, query, k8sClient)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		require.NotNil(t, messages[0].OfUser)
+
+		parts := messages[0].OfUser.Content.OfArrayOfContentParts
+		require.Len(t, parts, 5)
+		assert.Equal(t, "Describe these attachments", parts[0].OfText.Text)
+		assert.Equal(t, "https://example.com/cat.png", parts[1].OfImageURL.ImageURL.URL)
+		assert.Equal(t, "data:image/png;base64,iVBORw0KGgo=", parts[2].OfImageURL.ImageURL.URL)
+		assert.Equal(t, "notes.txt", parts[3].OfFile.File.Filename.Value)
+		assert.Equal(t, "data:text/plain;base64,cXVhcnRlcmx5IG5vdGVz", parts[3].OfFile.File.FileData.Value)
+		assert.Equal(t, "Focus on revenue", parts[4].OfText.Text)
+	})
+
+	t.Run("user type with part from missing configmap", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		query := arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-query",
+				Namespace: "test-ns",
+			},
+			Spec: arkv1alpha1.QuerySpec{
+				Type: "user",
+				Parts: []arkv1alpha1.QueryInputPart{{Type: arkv1alpha1.QueryInputPartFile, ValueFrom: &arkv1alpha1.QueryInputPartSource{
+					ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "report.pdf"},
+				}}},
+			},
+		}
+		require.NoError(t, query.Spec.SetInputString("Summarize the report"))
+
+		_, err := GetQueryInputMessages(ctx, query, k8sClient)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed to resolve parts[0]")
+	})
+
+	t.Run("user type with query field parameters", func(t *testing.T) {
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+		query := arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        "test-query",
+				Namespace:   "test-ns",
+				Annotations: map[string]string{"ticket": "OPS-42"},
+			},
+			Spec: arkv1alpha1.QuerySpec{
+				Type:      "user",
+				SessionId: "chat-7",
+				Parameters: []arkv1alpha1.Parameter{
+					{Name: "ticket", ValueFrom: &arkv1alpha1.ValueFromSource{QueryFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.annotations['ticket']"}}},
+					{Name: "namespace", ValueFrom: &arkv1alpha1.ValueFromSource{ResourceFieldRef: &arkv1alpha1.FieldSelector{FieldPath: "metadata.namespace"}}},
+				},
+			},
+		}
+		require.NoError(t, query.Spec.SetInputString("Update {{.ticket}} from {{.namespace}}"))
+
+		messages, err := GetQueryInputMessages(ctx, query, k8sClient)
+		require.NoError(t, err)
+		require.Len(t, messages, 1)
+		assert.Equal(t, "Update OPS-42 from test-ns", messages[0].OfUser.Content.OfString.Value)
+	})
+}
+
+func TestResolveQueryInputParameterSchema(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "forecast", Namespace: "test-ns"},
+		Data:       map[string]string{"days": "seven"},
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(configMap).Build()
+
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-query", Namespace: "test-ns"},
+		Spec: arkv1alpha1.QuerySpec{
+			Parameters: []arkv1alpha1.Parameter{{Name: "city", Value: "Berlin"}},
+			ParameterSchema: []arkv1alpha1.QueryTemplateParameter{
+				{Name: "city", Required: true},
+				{Name: "days", Type: arkv1alpha1.ParameterTypeInteger, Default: "3"},
+				{Name: "detailed", Type: arkv1alpha1.ParameterTypeBoolean},
+			},
+		},
+	}
+
+	input := "{{.city}}{{if gt .days 2}} for {{.days}} days{{end}}{{if .detailed}} in detail{{end}}"
+	resolved, err := ResolveQueryInput(ctx, k8sClient, query, input)
+	require.NoError(t, err)
+	assert.Equal(t, "Berlin for 3 days", resolved)
+
+	query.Spec.Parameters = append(query.Spec.Parameters, arkv1alpha1.Parameter{
+		Name: "days",
+		ValueFrom: &arkv1alpha1.ValueFromSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "forecast"},
+			Key:                  "days",
+		}},
+	})
+	_, err = ResolveQueryInput(ctx, k8sClient, query, input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parameter days must be of type integer")
+
+	query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "days", Value: "5"}}
+	_, err = ResolveQueryInput(ctx, k8sClient, query, input)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "parameter city is required")
+}
+
+func TestModelCheckCapabilities(t *testing.T) {
+	image := Message(openai.UserMessage([]openai.ChatCompletionContentPartUnionParam{
+		openai.TextContentPart("What is this?"),
+		openai.ImageContentPart(openai.ChatCompletionContentPartImageImageURLParam{URL: "https://example.com/cat.png"}),
+	}))
+
+	undeclared := &Model{Model: "gpt-4o"}
+	assert.NoError(t, undeclared.checkCapabilities([]Message{image}))
+
+	vision := &Model{Model: "gpt-4o", Capabilities: []arkv1alpha1.ModelCapability{arkv1alpha1.ModelCapabilityVision}}
+	assert.NoError(t, vision.checkCapabilities([]Message{image}))
+
+	textOnly := &Model{Model: "gpt-3.5-turbo", Capabilities: []arkv1alpha1.ModelCapability{arkv1alpha1.ModelCapabilityFiles}}
+	err := textOnly.checkCapabilities([]Message{NewUserMessage("hi"), image})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "does not support image input")
+	assert.NoError(t, textOnly.checkCapabilities([]Message{NewUserMessage("hi")}))
 }
 
 func BenchmarkGetQueryInputMessages(b *testing.B) {
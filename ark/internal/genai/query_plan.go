@@ -0,0 +1,30 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+
+	"github.com/openai/openai-go"
+)
+
+// PlanModelCall returns the messages and tools of the first model call of the agent, without
+// calling the model
+func (a *Agent) PlanModelCall(ctx context.Context, userInput Message, history []Message) ([]Message, []openai.ChatCompletionToolParam, error) {
+	var tools []openai.ChatCompletionToolParam
+	if a.Tools != nil {
+		tools = a.Tools.ToOpenAITools()
+	}
+
+	messages, err := a.prepareMessages(ctx, userInput, history)
+	if err != nil {
+		return nil, nil, err
+	}
+	return messages, tools, nil
+}
+
+// EstimatePromptTokens estimates the prompt tokens of a model call from the size of its messages
+// and tool schemas
+func EstimatePromptTokens(messages []Message, tools []openai.ChatCompletionToolParam) int64 {
+	return int64(estimateTokens(messages) + estimateToolTokens(tools))
+}
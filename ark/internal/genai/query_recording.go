@@ -0,0 +1,142 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"github.com/openai/openai-go"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+type modelCallRecordingContextKey struct{}
+
+// RecordedModelCall is a model call of a recording
+type RecordedModelCall struct {
+	Model string `json:"model"`
+	// RequestHash is the hex encoded SHA-256 of the model, messages and tools of the request
+	RequestHash string                 `json:"requestHash"`
+	Response    *openai.ChatCompletion `json:"response"`
+}
+
+// ModelCallRecording records the model calls of a query, or answers them from a recording when
+// the query is replayed. Replayed calls are matched to the recorded call with the same request,
+// and otherwise to the next unused call of the same model, so agent logic that changes its
+// prompts can still be replayed.
+type ModelCallRecording struct {
+	Calls []RecordedModelCall `json:"calls"`
+
+	mu        sync.Mutex
+	replaying bool
+	used      []bool
+}
+
+// NewModelCallRecording creates an empty recording
+func NewModelCallRecording() *ModelCallRecording {
+	return &ModelCallRecording{}
+}
+
+// ParseModelCallRecording parses a stored recording for replay
+func ParseModelCallRecording(data []byte) (*ModelCallRecording, error) {
+	recording := &ModelCallRecording{}
+	if err := json.Unmarshal(data, recording); err != nil {
+		return nil, fmt.Errorf("failed to parse model call recording: %w", err)
+	}
+	recording.replaying = true
+	recording.used = make([]bool, len(recording.Calls))
+	return recording, nil
+}
+
+// LoadModelCallRecording reads the recording referenced by a query replay from store
+func LoadModelCallRecording(ctx context.Context, store ArtifactStore, namespace string, replay *arkv1alpha1.QueryReplay) (*ModelCallRecording, error) {
+	artifact, err := store.Get(ctx, namespace, replay.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read recording %s: %w", replay.Name, err)
+	}
+	return ParseModelCallRecording([]byte(artifact.Content))
+}
+
+// WithModelCallRecording records or replays the model calls made with ctx
+func WithModelCallRecording(ctx context.Context, recording *ModelCallRecording) context.Context {
+	return context.WithValue(ctx, modelCallRecordingContextKey{}, recording)
+}
+
+func modelCallRecordingFromContext(ctx context.Context) *ModelCallRecording {
+	recording, _ := ctx.Value(modelCallRecordingContextKey{}).(*ModelCallRecording)
+	return recording
+}
+
+// Replaying is true for a recording that answers model calls
+func (r *ModelCallRecording) Replaying() bool {
+	return r.replaying
+}
+
+// Marshal returns the recording as stored
+func (r *ModelCallRecording) Marshal() ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return json.Marshal(r)
+}
+
+func (r *ModelCallRecording) record(model string, messages []Message, response *openai.ChatCompletion, tools ...[]openai.ChatCompletionToolParam) error {
+	hash, err := modelRequestHash(model, messages, tools...)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.Calls = append(r.Calls, RecordedModelCall{Model: model, RequestHash: hash, Response: response})
+	return nil
+}
+
+func (r *ModelCallRecording) replay(ctx context.Context, model string, messages []Message, tools ...[]openai.ChatCompletionToolParam) (*openai.ChatCompletion, error) {
+	hash, err := modelRequestHash(model, messages, tools...)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next := -1
+	for i, call := range r.Calls {
+		if r.used[i] || call.Model != model {
+			continue
+		}
+		if call.RequestHash == hash {
+			next = i
+			break
+		}
+		if next < 0 {
+			next = i
+		}
+	}
+	if next < 0 {
+		return nil, fmt.Errorf("recording has no response left for a call of model %s", model)
+	}
+	if r.Calls[next].RequestHash != hash {
+		logf.FromContext(ctx).Info("replayed model call differs from the recorded request", "model", model, "call", next)
+	}
+	r.used[next] = true
+	return r.Calls[next].Response, nil
+}
+
+func modelRequestHash(model string, messages []Message, tools ...[]openai.ChatCompletionToolParam) (string, error) {
+	request := struct {
+		Model    string                             `json:"model"`
+		Messages []Message                          `json:"messages"`
+		Tools    [][]openai.ChatCompletionToolParam `json:"tools,omitempty"`
+	}{Model: model, Messages: messages, Tools: tools}
+	data, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal model request: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
@@ -0,0 +1,86 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestModelCallRecordingReplay(t *testing.T) {
+	provider := &contextTestProvider{}
+	model := &Model{Model: "gpt-4o", Provider: provider, ModelRecorder: noop.NewModelRecorder()}
+	first := []Message{NewUserMessage("first")}
+	second := []Message{NewUserMessage("second")}
+
+	recording := NewModelCallRecording()
+	ctx := WithModelCallRecording(context.Background(), recording)
+	_, err := model.ChatCompletion(ctx, first, nil, 1)
+	require.NoError(t, err)
+	_, err = model.ChatCompletion(ctx, second, nil, 1)
+	require.NoError(t, err)
+	require.Len(t, recording.Calls, 2)
+	recording.Calls[1].Response = &openai.ChatCompletion{Choices: []openai.ChatCompletionChoice{{Message: openai.ChatCompletionMessage{Content: "recorded"}}}}
+
+	data, err := recording.Marshal()
+	require.NoError(t, err)
+	replay, err := ParseModelCallRecording(data)
+	require.NoError(t, err)
+	require.True(t, replay.Replaying())
+
+	// Calls are matched by request, not by order, and the provider is not called
+	ctx = WithModelCallRecording(context.Background(), replay)
+	response, err := model.ChatCompletion(ctx, second, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "recorded", response.Choices[0].Message.Content)
+
+	// A changed request is answered by the next unused call of the model
+	response, err = model.ChatCompletion(ctx, []Message{NewUserMessage("changed")}, nil, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "short", response.Choices[0].Message.Content)
+	assert.Len(t, provider.received, 2)
+
+	_, err = model.ChatCompletion(ctx, first, nil, 1)
+	assert.ErrorContains(t, err, "no response left")
+}
+
+func TestLoadModelCallRecording(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "settings", Namespace: "default"},
+		Data:       map[string]string{"key": "value"},
+	}).Build()
+	store := &ConfigMapArtifactStore{Client: k8sClient, Scheme: scheme}
+
+	recording := NewModelCallRecording()
+	require.NoError(t, recording.record("gpt-4o", []Message{NewUserMessage("hi")}, &openai.ChatCompletion{ID: "chatcmpl-1"}))
+	data, err := recording.Marshal()
+	require.NoError(t, err)
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "report", Namespace: "default", UID: "query-uid"}}
+	_, err = store.Put(ctx, query, "report-recording", Artifact{Content: string(data)})
+	require.NoError(t, err)
+
+	loaded, err := LoadModelCallRecording(ctx, store, "default", &arkv1alpha1.QueryReplay{Store: arkv1alpha1.ArtifactStoreConfigMap, Name: "report-recording"})
+	require.NoError(t, err)
+	require.Len(t, loaded.Calls, 1)
+	assert.Equal(t, "chatcmpl-1", loaded.Calls[0].Response.ID)
+	assert.Equal(t, recording.Calls[0].RequestHash, loaded.Calls[0].RequestHash)
+
+	// ConfigMaps that are not artifacts cannot be replayed
+	_, err = LoadModelCallRecording(ctx, store, "default", &arkv1alpha1.QueryReplay{Store: arkv1alpha1.ArtifactStoreConfigMap, Name: "settings"})
+	assert.ErrorContains(t, err, "not an artifact")
+}
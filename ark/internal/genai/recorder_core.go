@@ -5,7 +5,10 @@ package genai
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"sync"
 
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -16,22 +19,42 @@ import (
 type Recorder[T runtime.Object] struct {
 	resource T
 	recorder record.EventRecorder
+	settings EventSettings
+
+	mu         sync.Mutex
+	aggregates map[string]*eventAggregate
+}
+
+// eventAggregate counts the occurrences of events with the same type, reason, name and error
+type eventAggregate struct {
+	eventType string
+	reason    string
+	last      map[string]interface{}
+	count     int
+	emitted   int
 }
 
-func NewQueryRecorder(query *arkv1alpha1.Query, recorder record.EventRecorder) *Recorder[*arkv1alpha1.Query] {
+func NewQueryRecorder(query *arkv1alpha1.Query, recorder record.EventRecorder, settings EventSettings) *Recorder[*arkv1alpha1.Query] {
 	return &Recorder[*arkv1alpha1.Query]{
-		resource: query,
-		recorder: recorder,
+		resource:   query,
+		recorder:   recorder,
+		settings:   settings,
+		aggregates: map[string]*eventAggregate{},
 	}
 }
 
-func NewModelRecorder(model *arkv1alpha1.Model, recorder record.EventRecorder) *Recorder[*arkv1alpha1.Model] {
+func NewModelRecorder(model *arkv1alpha1.Model, recorder record.EventRecorder, settings EventSettings) *Recorder[*arkv1alpha1.Model] {
 	return &Recorder[*arkv1alpha1.Model]{
-		resource: model,
-		recorder: recorder,
+		resource:   model,
+		recorder:   recorder,
+		settings:   settings,
+		aggregates: map[string]*eventAggregate{},
 	}
 }
 
+// EmitEvent records an event on the resource according to the event settings. With aggregated and
+// warnings verbosity, repeats of an event are counted and only every AggregationInterval-th repeat
+// is emitted, with the number of occurrences in the count field.
 func (r *Recorder[T]) EmitEvent(ctx context.Context, eventType, reason string, data EventData) {
 	log := logf.FromContext(ctx).WithValues("reason", reason)
 
@@ -45,7 +68,76 @@ func (r *Recorder[T]) EmitEvent(ctx context.Context, eventType, reason string, d
 		return
 	}
 
+	switch r.settings.Verbosity {
+	case EventVerbosityNone:
+		return
+	case EventVerbosityWarnings:
+		if eventType != corev1.EventTypeWarning {
+			return
+		}
+	}
+
 	eventMap := data.ToMap()
+	aggregated := r.settings.Verbosity == EventVerbosityAggregated || r.settings.Verbosity == EventVerbosityWarnings
+	if aggregated && !r.aggregate(eventType, reason, eventMap) {
+		log.V(2).Info("event aggregated", "data", eventMap)
+		return
+	}
+	r.emit(ctx, eventType, reason, eventMap)
+}
+
+// Flush emits the aggregated events whose latest occurrences have not been emitted yet
+func (r *Recorder[T]) Flush(ctx context.Context) {
+	if r.recorder == nil || r.isResourceNil() {
+		return
+	}
+
+	r.mu.Lock()
+	var pending []*eventAggregate
+	for _, aggregate := range r.aggregates {
+		if aggregate.count > aggregate.emitted {
+			aggregate.emitted = aggregate.count
+			aggregate.last[MetadataCount] = aggregate.count
+			pending = append(pending, aggregate)
+		}
+	}
+	r.mu.Unlock()
+
+	for _, aggregate := range pending {
+		r.emit(ctx, aggregate.eventType, aggregate.reason, aggregate.last)
+	}
+}
+
+// aggregate counts an occurrence of the event and reports whether it should be emitted
+func (r *Recorder[T]) aggregate(eventType, reason string, eventMap map[string]interface{}) bool {
+	interval := r.settings.AggregationInterval
+	if interval <= 0 {
+		interval = DefaultEventAggregationInterval
+	}
+	key := fmt.Sprintf("%s/%s/%v/%v", eventType, reason, eventMap["name"], eventMap["error"])
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	aggregate, exists := r.aggregates[key]
+	if !exists {
+		r.aggregates[key] = &eventAggregate{eventType: eventType, reason: reason, last: eventMap, count: 1, emitted: 1}
+		return true
+	}
+
+	aggregate.count++
+	aggregate.last = eventMap
+	if aggregate.count-aggregate.emitted < interval {
+		return false
+	}
+	aggregate.emitted = aggregate.count
+	eventMap[MetadataCount] = aggregate.count
+	return true
+}
+
+func (r *Recorder[T]) emit(ctx context.Context, eventType, reason string, eventMap map[string]interface{}) {
+	log := logf.FromContext(ctx).WithValues("reason", reason)
+
 	eventJSON, err := json.Marshal(eventMap)
 	if err != nil {
 		log.Error(err, "failed to marshal event data", "data", eventMap)
@@ -0,0 +1,140 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func drainEvents(recorder *record.FakeRecorder) []string {
+	var events []string
+	for {
+		select {
+		case event := <-recorder.Events:
+			events = append(events, event)
+		default:
+			return events
+		}
+	}
+}
+
+func TestRecorderAggregatesRepeatedEvents(t *testing.T) {
+	ctx := context.Background()
+	fakeRecorder := record.NewFakeRecorder(100)
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "default"}}
+	recorder := NewQueryRecorder(query, fakeRecorder, EventSettings{Verbosity: EventVerbosityAggregated, AggregationInterval: 3})
+
+	for range 5 {
+		tracker := NewOperationTracker(recorder, ctx, OperationToolCall, "search", nil)
+		tracker.Complete("")
+	}
+	tracker := NewOperationTracker(recorder, ctx, OperationToolCall, "search", nil)
+	tracker.Fail(errors.New("timeout"))
+
+	events := drainEvents(fakeRecorder)
+	require.Len(t, events, 5)
+	assert.Contains(t, events[0], "Normal ToolCallStart")
+	assert.NotContains(t, events[0], `"count"`)
+	assert.Contains(t, events[1], "Normal ToolCallComplete")
+	assert.Contains(t, events[2], "Normal ToolCallStart")
+	assert.Contains(t, events[2], `"count":4`)
+	assert.Contains(t, events[3], "Normal ToolCallComplete")
+	assert.Contains(t, events[3], `"count":4`)
+	assert.Contains(t, events[4], "Warning ToolCallError")
+
+	recorder.Flush(ctx)
+	events = drainEvents(fakeRecorder)
+	require.Len(t, events, 2)
+	assert.True(t, slices.ContainsFunc(events, func(event string) bool {
+		return strings.Contains(event, "ToolCallStart") && strings.Contains(event, `"count":6`)
+	}))
+	assert.True(t, slices.ContainsFunc(events, func(event string) bool {
+		return strings.Contains(event, "ToolCallComplete") && strings.Contains(event, `"count":5`)
+	}))
+
+	recorder.Flush(ctx)
+	assert.Empty(t, drainEvents(fakeRecorder))
+}
+
+func TestRecorderVerbosity(t *testing.T) {
+	ctx := context.Background()
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q", Namespace: "default"}}
+	emit := func(recorder *Recorder[*arkv1alpha1.Query]) {
+		for range 3 {
+			recorder.EmitEvent(ctx, corev1.EventTypeNormal, ReasonTargetExecutionComplete, BaseEvent{Name: "agent"})
+			recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonTargetExecutionError, BaseEvent{Name: "agent"})
+		}
+	}
+
+	tests := []struct {
+		verbosity EventVerbosity
+		expected  int
+	}{
+		{EventVerbosityAll, 6},
+		{EventVerbosityAggregated, 2},
+		{"", 6},
+		{EventVerbosityWarnings, 1},
+		{EventVerbosityNone, 0},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.verbosity), func(t *testing.T) {
+			fakeRecorder := record.NewFakeRecorder(100)
+			emit(NewQueryRecorder(query, fakeRecorder, EventSettings{Verbosity: tt.verbosity}))
+			assert.Len(t, drainEvents(fakeRecorder), tt.expected)
+		})
+	}
+}
+
+func TestNamespaceEventSettings(t *testing.T) {
+	ctx := context.Background()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ark-config-telemetry", Namespace: "quiet"},
+			Data:       map[string]string{"eventVerbosity": "warnings", "contentCapture": "off"},
+		},
+		&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "ark-config-telemetry", Namespace: "invalid"},
+			Data:       map[string]string{"eventAggregationInterval": "0"},
+		},
+	).Build()
+
+	defaults := DefaultEventSettings()
+	assert.Equal(t, defaults, NamespaceEventSettings(ctx, k8sClient, "default", defaults))
+	assert.Equal(t, EventSettings{Verbosity: EventVerbosityWarnings, AggregationInterval: DefaultEventAggregationInterval},
+		NamespaceEventSettings(ctx, k8sClient, "quiet", defaults))
+	assert.Equal(t, defaults, NamespaceEventSettings(ctx, k8sClient, "invalid", defaults))
+}
+
+func TestEventSettingsFromEnv(t *testing.T) {
+	t.Setenv("ARK_EVENT_VERBOSITY", "")
+	t.Setenv("ARK_EVENT_AGGREGATION_INTERVAL", "")
+	settings, err := EventSettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, DefaultEventSettings(), settings)
+
+	t.Setenv("ARK_EVENT_VERBOSITY", "aggregated")
+	t.Setenv("ARK_EVENT_AGGREGATION_INTERVAL", "50")
+	settings, err = EventSettingsFromEnv()
+	require.NoError(t, err)
+	assert.Equal(t, EventSettings{Verbosity: EventVerbosityAggregated, AggregationInterval: 50}, settings)
+
+	t.Setenv("ARK_EVENT_VERBOSITY", "debug")
+	_, err = EventSettingsFromEnv()
+	assert.ErrorContains(t, err, "unknown event verbosity")
+}
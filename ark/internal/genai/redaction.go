@@ -0,0 +1,250 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// RedactionConfigMapName is the per-namespace ConfigMap configuring PII redaction
+const RedactionConfigMapName = "ark-config-redaction"
+
+const piiClassifierPrompt = "Identify personal data in the user's text, such as names, addresses, contact details, account or identity numbers. " +
+	"Respond with a JSON array containing each personal data value exactly as it appears in the text, or [] if there is none."
+
+// RedactionPattern replaces matches of Pattern with [REDACTED:Name]
+type RedactionPattern struct {
+	Name    string `json:"name"`
+	Pattern string `json:"pattern"`
+}
+
+// DefaultRedactionPatterns are applied unless disabled in the redaction ConfigMap
+var DefaultRedactionPatterns = []RedactionPattern{
+	{Name: "email", Pattern: `[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`},
+	{Name: "credit-card", Pattern: `\b(?:\d[ -]?){13,16}\b`},
+	{Name: "ssn", Pattern: `\b\d{3}-\d{2}-\d{4}\b`},
+	{Name: "phone", Pattern: `\+?\d{1,3}[ .-]?\(?\d{3}\)?[ .-]?\d{3}[ .-]?\d{4}\b`},
+	{Name: "ipv4", Pattern: `\b(?:\d{1,3}\.){3}\d{1,3}\b`},
+}
+
+type compiledRedactionPattern struct {
+	name   string
+	regexp *regexp.Regexp
+}
+
+// PIIRedactor removes personal data from content using regular expressions and,
+// optionally, a classifier model
+type PIIRedactor struct {
+	patterns   []compiledRedactionPattern
+	classifier *Model
+	// cache holds classifier results so repeated content is only classified once per query
+	cache sync.Map
+}
+
+type piiRedactorContextKey struct{}
+
+// NewPIIRedactor creates a redactor from the namespace redaction ConfigMap, falling back to the default patterns
+func NewPIIRedactor(ctx context.Context, k8sClient client.Client, namespace string, modelRecorder telemetry.ModelRecorder) (*PIIRedactor, error) {
+	patterns := DefaultRedactionPatterns
+	classifierModel := ""
+
+	cm := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: RedactionConfigMapName, Namespace: namespace}, cm)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get redaction ConfigMap: %w", err)
+	default:
+		if strings.TrimSpace(cm.Data["defaultPatterns"]) == "false" {
+			patterns = nil
+		}
+		if value, ok := cm.Data["patterns"]; ok {
+			var custom []RedactionPattern
+			if err := yaml.Unmarshal([]byte(value), &custom); err != nil {
+				return nil, fmt.Errorf("redaction ConfigMap has invalid patterns: %w", err)
+			}
+			patterns = append(append([]RedactionPattern{}, patterns...), custom...)
+		}
+		classifierModel = strings.TrimSpace(cm.Data["classifierModel"])
+	}
+
+	redactor := &PIIRedactor{}
+	for _, pattern := range patterns {
+		compiled, err := regexp.Compile(pattern.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redaction pattern %s: %w", pattern.Name, err)
+		}
+		redactor.patterns = append(redactor.patterns, compiledRedactionPattern{name: pattern.Name, regexp: compiled})
+	}
+
+	if classifierModel != "" {
+		model, err := LoadModel(ctx, k8sClient, &arkv1alpha1.AgentModelRef{Name: classifierModel}, namespace, modelRecorder)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load PII classifier model %s: %w", classifierModel, err)
+		}
+		redactor.classifier = model
+	}
+
+	return redactor, nil
+}
+
+// Redact returns content with personal data replaced by [REDACTED:<type>] markers
+func (r *PIIRedactor) Redact(ctx context.Context, content string) string {
+	if content == "" {
+		return content
+	}
+
+	redacted := r.redactPatterns(content)
+	if r.classifier == nil {
+		return redacted
+	}
+
+	if cached, ok := r.cache.Load(redacted); ok {
+		return cached.(string)
+	}
+
+	values, err := r.classify(ctx, redacted)
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "PII classifier failed, using pattern redaction only")
+		return redacted
+	}
+	result := redacted
+	for _, value := range values {
+		if value != "" {
+			result = strings.ReplaceAll(result, value, "[REDACTED:pii]")
+		}
+	}
+	r.cache.Store(redacted, result)
+	return result
+}
+
+func (r *PIIRedactor) redactPatterns(content string) string {
+	for _, pattern := range r.patterns {
+		content = pattern.regexp.ReplaceAllString(content, "[REDACTED:"+pattern.name+"]")
+	}
+	return content
+}
+
+func (r *PIIRedactor) classify(ctx context.Context, content string) ([]string, error) {
+	// The classifier's own spans see the unclassified content, so only apply pattern redaction
+	// to them. This also prevents the classifier from recursively classifying its own input.
+	ctx = telemetry.WithRedactor(ctx, r.redactPatterns)
+
+	response, err := r.classifier.ChatCompletion(ctx, []Message{
+		NewSystemMessage(piiClassifierPrompt),
+		NewUserMessage(content),
+	}, nil, 1)
+	if err != nil {
+		return nil, err
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return nil, fmt.Errorf("PII classifier returned no choices")
+	}
+
+	var values []string
+	if err := unmarshalModelJSON(response.Choices[0].Message.Content, &values); err != nil {
+		return nil, fmt.Errorf("PII classifier returned invalid JSON: %w", err)
+	}
+	return values, nil
+}
+
+// RedactMessages returns copies of messages with personal data removed from their content
+func (r *PIIRedactor) RedactMessages(ctx context.Context, messages []Message) []Message {
+	redacted := make([]Message, len(messages))
+	for i, msg := range messages {
+		redacted[i] = r.redactMessage(ctx, msg)
+	}
+	return redacted
+}
+
+func (r *PIIRedactor) redactMessage(ctx context.Context, msg Message) Message {
+	raw, err := json.Marshal(openai.ChatCompletionMessageParamUnion(msg))
+	if err != nil {
+		return NewAssistantMessage("[REDACTED]")
+	}
+	var fields any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return NewAssistantMessage("[REDACTED]")
+	}
+	fields = r.redactContentFields(ctx, fields, "")
+
+	raw, err = json.Marshal(fields)
+	if err != nil {
+		return NewAssistantMessage("[REDACTED]")
+	}
+	redacted, err := unmarshalMessageRobust(raw)
+	if err != nil {
+		return NewAssistantMessage("[REDACTED]")
+	}
+	return Message(redacted)
+}
+
+// redactContentFields redacts string values under content, text and arguments keys
+func (r *PIIRedactor) redactContentFields(ctx context.Context, value any, key string) any {
+	switch v := value.(type) {
+	case map[string]any:
+		for k, field := range v {
+			v[k] = r.redactContentFields(ctx, field, k)
+		}
+		return v
+	case []any:
+		for i, item := range v {
+			v[i] = r.redactContentFields(ctx, item, key)
+		}
+		return v
+	case string:
+		if key == "content" || key == "text" || key == "arguments" {
+			return r.Redact(ctx, v)
+		}
+		return v
+	default:
+		return v
+	}
+}
+
+// WithPIIRedaction returns a context that redacts memory writes and telemetry content with redactor
+func WithPIIRedaction(ctx context.Context, redactor *PIIRedactor) context.Context {
+	ctx = context.WithValue(ctx, piiRedactorContextKey{}, redactor)
+	return telemetry.WithRedactor(ctx, func(content string) string {
+		return redactor.Redact(ctx, content)
+	})
+}
+
+// PIIRedactorFromContext returns the redactor set by WithPIIRedaction, or nil
+func PIIRedactorFromContext(ctx context.Context) *PIIRedactor {
+	redactor, _ := ctx.Value(piiRedactorContextKey{}).(*PIIRedactor)
+	return redactor
+}
+
+// RedactingMemory redacts messages before they are persisted to the wrapped memory
+type RedactingMemory struct {
+	MemoryInterface
+	redactor *PIIRedactor
+}
+
+// NewRedactingMemory wraps memory so messages are redacted before they are stored
+func NewRedactingMemory(memory MemoryInterface, redactor *PIIRedactor) MemoryInterface {
+	if memory == nil || redactor == nil {
+		return memory
+	}
+	return &RedactingMemory{MemoryInterface: memory, redactor: redactor}
+}
+
+func (m *RedactingMemory) AddMessages(ctx context.Context, queryID string, messages []Message) error {
+	return m.MemoryInterface.AddMessages(ctx, queryID, m.redactor.RedactMessages(ctx, messages))
+}
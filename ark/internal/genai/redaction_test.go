@@ -0,0 +1,131 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+type capturingMemory struct {
+	NoopMemory
+	messages []Message
+}
+
+func (m *capturingMemory) AddMessages(ctx context.Context, queryID string, messages []Message) error {
+	m.messages = append(m.messages, messages...)
+	return nil
+}
+
+func newTestPIIRedactor(t *testing.T, data map[string]string) (*PIIRedactor, error) {
+	t.Helper()
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	builder := fake.NewClientBuilder().WithScheme(scheme)
+	if data != nil {
+		builder = builder.WithObjects(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: RedactionConfigMapName, Namespace: "default"},
+			Data:       data,
+		})
+	}
+	return NewPIIRedactor(context.Background(), builder.Build(), "default", nil)
+}
+
+func TestPIIRedactorDefaultPatterns(t *testing.T) {
+	redactor, err := newTestPIIRedactor(t, nil)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{name: "email", input: "Contact jane.doe@example.com today", expected: "Contact [REDACTED:email] today"},
+		{name: "ssn", input: "SSN 123-45-6789", expected: "SSN [REDACTED:ssn]"},
+		{name: "credit card", input: "Card 4111 1111 1111 1111", expected: "Card [REDACTED:credit-card]"},
+		{name: "ipv4", input: "Host 10.0.0.12 is down", expected: "Host [REDACTED:ipv4] is down"},
+		{name: "no personal data", input: "The weather is sunny", expected: "The weather is sunny"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, redactor.Redact(context.Background(), tt.input))
+		})
+	}
+}
+
+func TestPIIRedactorConfigMapPatterns(t *testing.T) {
+	redactor, err := newTestPIIRedactor(t, map[string]string{
+		"defaultPatterns": "false",
+		"patterns":        "- name: employee-id\n  pattern: 'EMP-[0-9]{6}'\n",
+	})
+	require.NoError(t, err)
+
+	redacted := redactor.Redact(context.Background(), "EMP-123456 emailed jane@example.com")
+	assert.Equal(t, "[REDACTED:employee-id] emailed jane@example.com", redacted)
+}
+
+func TestPIIRedactorInvalidPattern(t *testing.T) {
+	_, err := newTestPIIRedactor(t, map[string]string{
+		"patterns": "- name: broken\n  pattern: '[a-'\n",
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid redaction pattern broken")
+}
+
+func TestRedactingMemory(t *testing.T) {
+	redactor, err := newTestPIIRedactor(t, nil)
+	require.NoError(t, err)
+
+	memory := &capturingMemory{}
+	redacting := NewRedactingMemory(memory, redactor)
+
+	toolCall := openai.ChatCompletionMessageToolCallParam{
+		ID:   "call-1",
+		Type: "function",
+		Function: openai.ChatCompletionMessageToolCallFunctionParam{
+			Name:      "lookup",
+			Arguments: `{"email":"jane@example.com"}`,
+		},
+	}
+	assistant := openai.AssistantMessage("")
+	assistant.OfAssistant.ToolCalls = []openai.ChatCompletionMessageToolCallParam{toolCall}
+
+	require.NoError(t, redacting.AddMessages(context.Background(), "query", []Message{
+		NewUserMessage("My email is jane@example.com"),
+		Message(assistant),
+		ToolMessage("Found user at 10.0.0.12", "call-1"),
+	}))
+
+	require.Len(t, memory.messages, 3)
+	assert.Equal(t, "My email is [REDACTED:email]", memory.messages[0].OfUser.Content.OfString.Value)
+	assert.Equal(t, `{"email":"[REDACTED:email]"}`, memory.messages[1].OfAssistant.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "lookup", memory.messages[1].OfAssistant.ToolCalls[0].Function.Name)
+	assert.Equal(t, "Found user at [REDACTED:ipv4]", memory.messages[2].OfTool.Content.OfString.Value)
+}
+
+func TestWithPIIRedaction(t *testing.T) {
+	redactor, err := newTestPIIRedactor(t, nil)
+	require.NoError(t, err)
+
+	ctx := WithPIIRedaction(context.Background(), redactor)
+	assert.Same(t, redactor, PIIRedactorFromContext(ctx))
+	assert.Nil(t, PIIRedactorFromContext(context.Background()))
+
+	attrs := telemetry.RedactAttributes(telemetry.RedactorFromContext(ctx), []telemetry.Attribute{
+		telemetry.String(telemetry.AttrQueryRootInput, "jane@example.com"),
+		telemetry.String("model.name", "gpt-4o"),
+	})
+	assert.Equal(t, "[REDACTED:email]", attrs[0].Value)
+	assert.Equal(t, "gpt-4o", attrs[1].Value)
+}
@@ -0,0 +1,72 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// ReferenceNotGrantedError is returned when a resource references a resource in another namespace
+// that no ReferenceGrant of that namespace allows
+type ReferenceNotGrantedError struct {
+	FromKind      string
+	FromNamespace string
+	ToKind        arkv1alpha1.TargetKind
+	ToName        string
+	ToNamespace   string
+}
+
+func (e *ReferenceNotGrantedError) Error() string {
+	return fmt.Sprintf("%s in namespace %s may not reference %s %s/%s: no ReferenceGrant in namespace %s allows it",
+		e.FromKind, e.FromNamespace, e.ToKind, e.ToNamespace, e.ToName, e.ToNamespace)
+}
+
+// IsReferenceNotGranted returns true if err was caused by a missing ReferenceGrant
+func IsReferenceNotGranted(err error) bool {
+	var notGranted *ReferenceNotGrantedError
+	return errors.As(err, &notGranted)
+}
+
+type referenceGrantReaderContextKey struct{}
+
+// WithReferenceGrantReader sets the client that reads ReferenceGrants. Grants are policy of the
+// referenced namespace, so the controller reads them with its own client rather than the possibly
+// impersonated client a query runs with.
+func WithReferenceGrantReader(ctx context.Context, reader client.Reader) context.Context {
+	return context.WithValue(ctx, referenceGrantReaderContextKey{}, reader)
+}
+
+// CheckReferenceGrant returns a ReferenceNotGrantedError unless a resource of fromKind in
+// fromNamespace may reference toKind toName in toNamespace. References within a namespace are
+// always allowed. Grants are read with the client of WithReferenceGrantReader, or k8sClient.
+func CheckReferenceGrant(ctx context.Context, k8sClient client.Reader, fromKind, fromNamespace string, toKind arkv1alpha1.TargetKind, toName, toNamespace string) error {
+	if toNamespace == "" || toNamespace == fromNamespace {
+		return nil
+	}
+	if reader, ok := ctx.Value(referenceGrantReaderContextKey{}).(client.Reader); ok {
+		k8sClient = reader
+	}
+
+	var grants arkv1alpha1.ReferenceGrantList
+	if err := k8sClient.List(ctx, &grants, client.InNamespace(toNamespace)); err != nil {
+		return fmt.Errorf("failed to list reference grants in namespace %s: %w", toNamespace, err)
+	}
+	for _, grant := range grants.Items {
+		if grant.Spec.Allows(fromKind, fromNamespace, toKind, toName) {
+			return nil
+		}
+	}
+	return &ReferenceNotGrantedError{
+		FromKind:      fromKind,
+		FromNamespace: fromNamespace,
+		ToKind:        toKind,
+		ToName:        toName,
+		ToNamespace:   toNamespace,
+	}
+}
@@ -0,0 +1,82 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestCheckReferenceGrant(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	fakeClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&arkv1alpha1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "platform"},
+			Spec: arkv1alpha1.ReferenceGrantSpec{
+				From: []arkv1alpha1.ReferenceGrantFrom{
+					{Kind: arkv1alpha1.ReferenceFromQuery, Namespace: "team-a"},
+					{Kind: arkv1alpha1.ReferenceFromAgent, Namespace: "team-b"},
+				},
+				To: []arkv1alpha1.ReferenceGrantTo{
+					{Kind: string(arkv1alpha1.TargetKindAgent)},
+					{Kind: string(arkv1alpha1.TargetKindModel), Name: "gpt-4o"},
+				},
+			},
+		},
+	).Build()
+
+	tests := []struct {
+		name          string
+		fromKind      string
+		fromNamespace string
+		toKind        arkv1alpha1.TargetKind
+		toName        string
+		toNamespace   string
+		granted       bool
+	}{
+		{name: "same namespace", fromKind: arkv1alpha1.ReferenceFromQuery, fromNamespace: "team-c", toKind: arkv1alpha1.TargetKindTool, toName: "search", toNamespace: "team-c", granted: true},
+		{name: "every resource of a kind", fromKind: arkv1alpha1.ReferenceFromQuery, fromNamespace: "team-a", toKind: arkv1alpha1.TargetKindAgent, toName: "planner", toNamespace: "platform", granted: true},
+		{name: "named resource", fromKind: arkv1alpha1.ReferenceFromAgent, fromNamespace: "team-b", toKind: arkv1alpha1.TargetKindModel, toName: "gpt-4o", toNamespace: "platform", granted: true},
+		{name: "other resource name", fromKind: arkv1alpha1.ReferenceFromAgent, fromNamespace: "team-b", toKind: arkv1alpha1.TargetKindModel, toName: "claude", toNamespace: "platform"},
+		{name: "kind not granted", fromKind: arkv1alpha1.ReferenceFromQuery, fromNamespace: "team-a", toKind: arkv1alpha1.TargetKindTool, toName: "search", toNamespace: "platform"},
+		{name: "referencing kind not granted", fromKind: arkv1alpha1.ReferenceFromAgent, fromNamespace: "team-a", toKind: arkv1alpha1.TargetKindAgent, toName: "planner", toNamespace: "platform"},
+		{name: "namespace without grants", fromKind: arkv1alpha1.ReferenceFromQuery, fromNamespace: "team-a", toKind: arkv1alpha1.TargetKindAgent, toName: "planner", toNamespace: "prod"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckReferenceGrant(context.Background(), fakeClient, tt.fromKind, tt.fromNamespace, tt.toKind, tt.toName, tt.toNamespace)
+			if tt.granted {
+				assert.NoError(t, err)
+			} else {
+				assert.True(t, IsReferenceNotGranted(err), "expected a missing grant, got %v", err)
+			}
+		})
+	}
+}
+
+func TestCheckReferenceGrantUsesContextReader(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	controllerClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&arkv1alpha1.ReferenceGrant{
+			ObjectMeta: metav1.ObjectMeta{Name: "shared", Namespace: "platform"},
+			Spec: arkv1alpha1.ReferenceGrantSpec{
+				From: []arkv1alpha1.ReferenceGrantFrom{{Kind: arkv1alpha1.ReferenceFromAgent, Namespace: "team-a"}},
+				To:   []arkv1alpha1.ReferenceGrantTo{{Kind: string(arkv1alpha1.TargetKindTool)}},
+			},
+		},
+	).Build()
+	queryClient := fake.NewClientBuilder().WithScheme(scheme).Build()
+
+	ctx := WithReferenceGrantReader(context.Background(), controllerClient)
+	assert.NoError(t, CheckReferenceGrant(ctx, queryClient, arkv1alpha1.ReferenceFromAgent, "team-a", arkv1alpha1.TargetKindTool, "search", "platform"))
+}
@@ -0,0 +1,361 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/openai/openai-go"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/common"
+)
+
+const (
+	defaultRemoteQueryPollInterval = 2 * time.Second
+	remoteQueryCancelTimeout       = 10 * time.Second
+	remoteClusterProbeTimeout      = 10 * time.Second
+
+	remoteQueryDone     = "done"
+	remoteQueryError    = "error"
+	remoteQueryCanceled = "canceled"
+)
+
+// RemoteClusterRESTConfig builds the client configuration of a remote cluster from its kubeconfig
+// or endpoint secrets
+func RemoteClusterRESTConfig(ctx context.Context, k8sClient client.Client, cluster *arkv1alpha1.RemoteCluster) (*rest.Config, error) {
+	resolver := common.NewValueSourceResolver(k8sClient)
+	secretValue := func(ref *corev1.SecretKeySelector) (string, error) {
+		return resolver.ResolveValueSource(ctx, arkv1alpha1.ValueSource{
+			ValueFrom: &arkv1alpha1.ValueFromSource{SecretKeyRef: ref},
+		}, cluster.Namespace)
+	}
+
+	switch {
+	case cluster.Spec.KubeconfigSecretRef != nil:
+		kubeconfig, err := secretValue(cluster.Spec.KubeconfigSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		kubeconfigAPI, err := clientcmd.Load([]byte(kubeconfig))
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig of remote cluster %s: %w", cluster.Name, err)
+		}
+		if err := validateRemoteKubeconfig(kubeconfigAPI); err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig of remote cluster %s: %w", cluster.Name, err)
+		}
+		config, err := clientcmd.NewDefaultClientConfig(*kubeconfigAPI, &clientcmd.ConfigOverrides{}).ClientConfig()
+		if err != nil {
+			return nil, fmt.Errorf("invalid kubeconfig of remote cluster %s: %w", cluster.Name, err)
+		}
+		return config, nil
+	case cluster.Spec.Endpoint != nil:
+		endpoint := cluster.Spec.Endpoint
+		token, err := secretValue(&endpoint.TokenSecretRef)
+		if err != nil {
+			return nil, err
+		}
+		config := &rest.Config{Host: endpoint.URL, BearerToken: token}
+		if endpoint.CASecretRef != nil {
+			ca, err := secretValue(endpoint.CASecretRef)
+			if err != nil {
+				return nil, err
+			}
+			config.CAData = []byte(ca)
+		}
+		return config, nil
+	default:
+		return nil, fmt.Errorf("remote cluster %s must specify kubeconfigSecretRef or endpoint", cluster.Name)
+	}
+}
+
+// validateRemoteKubeconfig rejects kubeconfigs that make the controller run commands, read its own
+// files or skip server verification. Kubeconfigs are written by tenants, so only inline server, CA
+// and credential data is accepted.
+func validateRemoteKubeconfig(config *clientcmdapi.Config) error {
+	for name, cluster := range config.Clusters {
+		switch {
+		case cluster.CertificateAuthority != "":
+			return fmt.Errorf("cluster %s: certificate-authority files are not supported, use certificate-authority-data", name)
+		case cluster.InsecureSkipTLSVerify:
+			return fmt.Errorf("cluster %s: insecure-skip-tls-verify is not supported", name)
+		}
+	}
+	for name, authInfo := range config.AuthInfos {
+		switch {
+		case authInfo.Exec != nil:
+			return fmt.Errorf("user %s: exec credential plugins are not supported", name)
+		case authInfo.AuthProvider != nil:
+			return fmt.Errorf("user %s: auth-provider is not supported", name)
+		case authInfo.TokenFile != "":
+			return fmt.Errorf("user %s: tokenFile is not supported, use token", name)
+		case authInfo.ClientCertificate != "" || authInfo.ClientKey != "":
+			return fmt.Errorf("user %s: client-certificate and client-key files are not supported, use client-certificate-data and client-key-data", name)
+		}
+	}
+	return nil
+}
+
+// RemoteTargetNamespace returns the namespace of the remote cluster a target runs in. Targets
+// without a namespace run in the namespace of the remote cluster, other namespaces must be allowed
+// by the remote cluster.
+func RemoteTargetNamespace(cluster *arkv1alpha1.RemoteCluster, target arkv1alpha1.QueryTarget) (string, error) {
+	defaultNamespace := cluster.Spec.Namespace
+	if defaultNamespace == "" {
+		defaultNamespace = cluster.Namespace
+	}
+	return remoteTargetNamespace(cluster.Name, defaultNamespace, cluster.Spec.AllowedNamespaces, target)
+}
+
+func remoteTargetNamespace(cluster, defaultNamespace string, allowedNamespaces []string, target arkv1alpha1.QueryTarget) (string, error) {
+	if target.Namespace == "" || target.Namespace == defaultNamespace {
+		return defaultNamespace, nil
+	}
+	if !slices.Contains(allowedNamespaces, target.Namespace) {
+		return "", fmt.Errorf("namespace %s is not allowed by remote cluster %s", target.Namespace, cluster)
+	}
+	return target.Namespace, nil
+}
+
+// NewRemoteClusterClient returns a client of the ARK resources of a remote cluster
+func NewRemoteClusterClient(ctx context.Context, k8sClient client.Client, cluster *arkv1alpha1.RemoteCluster) (client.Client, error) {
+	config, err := RemoteClusterRESTConfig(ctx, k8sClient, cluster)
+	if err != nil {
+		return nil, err
+	}
+	return newRemoteClient(config, cluster.Name)
+}
+
+func newRemoteClient(config *rest.Config, name string) (client.Client, error) {
+	scheme := runtime.NewScheme()
+	if err := arkv1alpha1.AddToScheme(scheme); err != nil {
+		return nil, err
+	}
+	remoteClient, err := client.New(config, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client of remote cluster %s: %w", name, err)
+	}
+	return remoteClient, nil
+}
+
+// ProbeRemoteCluster checks that the remote cluster is reachable and lets its credentials list
+// queries in the remote namespace. It returns the host of the remote API server.
+func ProbeRemoteCluster(ctx context.Context, k8sClient client.Client, cluster *arkv1alpha1.RemoteCluster) (string, error) {
+	config, err := RemoteClusterRESTConfig(ctx, k8sClient, cluster)
+	if err != nil {
+		return "", err
+	}
+	config = rest.CopyConfig(config)
+	config.Timeout = remoteClusterProbeTimeout
+	remoteClient, err := newRemoteClient(config, cluster.Name)
+	if err != nil {
+		return config.Host, err
+	}
+
+	namespace := cluster.Spec.Namespace
+	if namespace == "" {
+		namespace = cluster.Namespace
+	}
+	var queries arkv1alpha1.QueryList
+	if err := remoteClient.List(ctx, &queries, client.InNamespace(namespace), client.Limit(1)); err != nil {
+		return config.Host, fmt.Errorf("failed to list queries in namespace %s of remote cluster %s: %w", namespace, cluster.Name, err)
+	}
+	return config.Host, nil
+}
+
+// RemoteTargetExecutor runs a query target in a remote cluster. It creates a query with the
+// resolved input in the remote cluster, waits for it and returns its response. The token usage of
+// the remote query is emitted so it is merged into the usage of the local query.
+type RemoteTargetExecutor struct {
+	Client            client.Client
+	Cluster           string
+	Namespace         string
+	AllowedNamespaces []string
+	PollInterval      time.Duration
+}
+
+// NewRemoteTargetExecutor returns the executor of targets of the named RemoteCluster in the namespace
+func NewRemoteTargetExecutor(ctx context.Context, k8sClient client.Client, name, namespace string) (*RemoteTargetExecutor, error) {
+	var cluster arkv1alpha1.RemoteCluster
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, &cluster); err != nil {
+		return nil, fmt.Errorf("unable to get remote cluster %s: %w", name, err)
+	}
+	remoteClient, err := NewRemoteClusterClient(ctx, k8sClient, &cluster)
+	if err != nil {
+		return nil, err
+	}
+	remoteNamespace := cluster.Spec.Namespace
+	if remoteNamespace == "" {
+		remoteNamespace = namespace
+	}
+	return &RemoteTargetExecutor{
+		Client:            remoteClient,
+		Cluster:           name,
+		Namespace:         remoteNamespace,
+		AllowedNamespaces: cluster.Spec.AllowedNamespaces,
+	}, nil
+}
+
+func (e *RemoteTargetExecutor) Execute(ctx context.Context, query *arkv1alpha1.Query, target arkv1alpha1.QueryTarget, inputMessages []Message, recorder EventEmitter) ([]Message, error) {
+	remoteQuery, err := e.remoteQuery(query, target, inputMessages)
+	if err != nil {
+		return nil, err
+	}
+	if err := e.Client.Create(ctx, remoteQuery); err != nil {
+		return nil, fmt.Errorf("failed to create query in remote cluster %s: %w", e.Cluster, err)
+	}
+
+	if err := e.waitForCompletion(ctx, remoteQuery); err != nil {
+		e.cancel(ctx, remoteQuery)
+		return nil, fmt.Errorf("remote query %s in cluster %s did not complete: %w", remoteQuery.Name, e.Cluster, err)
+	}
+	e.emitCompletion(ctx, recorder, target, remoteQuery)
+
+	if remoteQuery.Status.Phase != remoteQueryDone {
+		return nil, fmt.Errorf("remote query %s in cluster %s ended with phase %s%s", remoteQuery.Name, e.Cluster, remoteQuery.Status.Phase, remoteResponseError(remoteQuery))
+	}
+	return remoteResponseMessages(remoteQuery)
+}
+
+// remoteQuery returns the query running the target in the remote cluster. Its input is the input
+// of the local query with templates and sources already resolved.
+func (e *RemoteTargetExecutor) remoteQuery(query *arkv1alpha1.Query, target arkv1alpha1.QueryTarget, inputMessages []Message) (*arkv1alpha1.Query, error) {
+	namespace, err := remoteTargetNamespace(e.Cluster, e.Namespace, e.AllowedNamespaces, target)
+	if err != nil {
+		return nil, err
+	}
+	remoteQuery := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: query.Name + "-",
+			Namespace:    namespace,
+			Annotations: map[string]string{
+				annotations.RemoteQuerySource: query.Namespace + "/" + query.Name,
+			},
+		},
+		Spec: arkv1alpha1.QuerySpec{
+			Targets:   []arkv1alpha1.QueryTarget{{Type: target.Type, Name: target.Name}},
+			SessionId: query.Spec.SessionId,
+			Timeout:   query.Spec.Timeout,
+		},
+	}
+
+	messages := make([]openai.ChatCompletionMessageParamUnion, len(inputMessages))
+	for i, message := range inputMessages {
+		messages[i] = openai.ChatCompletionMessageParamUnion(message)
+	}
+	if err := remoteQuery.Spec.SetInputMessages(messages); err != nil {
+		return nil, fmt.Errorf("failed to set input of remote query: %w", err)
+	}
+	return remoteQuery, nil
+}
+
+func (e *RemoteTargetExecutor) waitForCompletion(ctx context.Context, remoteQuery *arkv1alpha1.Query) error {
+	interval := e.PollInterval
+	if interval <= 0 {
+		interval = defaultRemoteQueryPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		switch remoteQuery.Status.Phase {
+		case remoteQueryDone, remoteQueryError, remoteQueryCanceled:
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := e.Client.Get(ctx, client.ObjectKeyFromObject(remoteQuery), remoteQuery); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+// cancel stops a remote query the local query no longer waits for. Failures are only logged, the
+// remote query still ends at its own timeout.
+func (e *RemoteTargetExecutor) cancel(ctx context.Context, remoteQuery *arkv1alpha1.Query) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), remoteQueryCancelTimeout)
+	defer cancel()
+
+	patch := client.MergeFrom(remoteQuery.DeepCopy())
+	remoteQuery.Spec.Cancel = true
+	if err := e.Client.Patch(ctx, remoteQuery, patch); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to cancel remote query", "cluster", e.Cluster, "query", remoteQuery.Name)
+	}
+}
+
+func (e *RemoteTargetExecutor) emitCompletion(ctx context.Context, recorder EventEmitter, target arkv1alpha1.QueryTarget, remoteQuery *arkv1alpha1.Query) {
+	if recorder == nil {
+		return
+	}
+	usage := remoteQuery.Status.TokenUsage
+	eventType := corev1.EventTypeNormal
+	if remoteQuery.Status.Phase != remoteQueryDone {
+		eventType = corev1.EventTypeWarning
+	}
+	recorder.EmitEvent(ctx, eventType, ReasonRemoteQueryComplete, OperationEvent{
+		BaseEvent: BaseEvent{
+			Name: target.Name,
+			Metadata: map[string]string{
+				"remoteCluster":        e.Cluster,
+				"remoteQuery":          remoteQuery.Name,
+				"remoteQueryNamespace": remoteQuery.Namespace,
+				"phase":                remoteQuery.Status.Phase,
+			},
+		},
+		TokenUsage: TokenUsage{
+			PromptTokens:     usage.PromptTokens,
+			CompletionTokens: usage.CompletionTokens,
+			TotalTokens:      usage.TotalTokens,
+			ReasoningTokens:  usage.ReasoningTokens,
+		},
+	})
+}
+
+// remoteResponseMessages returns the messages of the remote response, falling back to its content
+// when the raw messages were not kept in the status
+func remoteResponseMessages(remoteQuery *arkv1alpha1.Query) ([]Message, error) {
+	if len(remoteQuery.Status.Responses) == 0 {
+		return nil, fmt.Errorf("remote query %s has no response", remoteQuery.Name)
+	}
+	response := remoteQuery.Status.Responses[0]
+	if response.Raw != "" {
+		var raw []openai.ChatCompletionMessageParamUnion
+		if err := json.Unmarshal([]byte(response.Raw), &raw); err == nil && len(raw) > 0 {
+			messages := make([]Message, len(raw))
+			for i, message := range raw {
+				messages[i] = Message(message)
+			}
+			return messages, nil
+		}
+	}
+	return []Message{NewAssistantMessage(response.Content)}, nil
+}
+
+func remoteResponseError(remoteQuery *arkv1alpha1.Query) string {
+	for _, response := range remoteQuery.Status.Responses {
+		if response.Content != "" {
+			return ": " + response.Content
+		}
+	}
+	return ""
+}
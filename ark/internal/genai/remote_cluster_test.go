@@ -0,0 +1,253 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: east
+  cluster:
+    server: https://east.example.com:6443
+contexts:
+- name: east
+  context:
+    cluster: east
+    user: ark
+current-context: east
+users:
+- name: ark
+  user:
+    token: kubeconfig-token
+`
+
+type recordedEvents struct {
+	mu     sync.Mutex
+	events []OperationEvent
+}
+
+func (r *recordedEvents) EmitEvent(_ context.Context, _, _ string, data EventData) {
+	if event, ok := data.(OperationEvent); ok {
+		r.mu.Lock()
+		r.events = append(r.events, event)
+		r.mu.Unlock()
+	}
+}
+
+func newRemoteClusterClient(t *testing.T) client.Client {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	return fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+		&corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+			Data: map[string][]byte{
+				"kubeconfig": []byte(testKubeconfig),
+				"token":      []byte("endpoint-token"),
+				"ca.crt":     []byte("ca"),
+			},
+		},
+	).WithStatusSubresource(&arkv1alpha1.Query{}).Build()
+}
+
+func secretKey(key string) *corev1.SecretKeySelector {
+	return &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "east"}, Key: key}
+}
+
+func TestRemoteClusterRESTConfig(t *testing.T) {
+	k8sClient := newRemoteClusterClient(t)
+	ctx := context.Background()
+	cluster := &arkv1alpha1.RemoteCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+		Spec:       arkv1alpha1.RemoteClusterSpec{KubeconfigSecretRef: secretKey("kubeconfig")},
+	}
+
+	config, err := RemoteClusterRESTConfig(ctx, k8sClient, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "https://east.example.com:6443", config.Host)
+	assert.Equal(t, "kubeconfig-token", config.BearerToken)
+
+	cluster.Spec = arkv1alpha1.RemoteClusterSpec{Endpoint: &arkv1alpha1.RemoteClusterEndpoint{
+		URL:            "https://east.example.com",
+		TokenSecretRef: *secretKey("token"),
+		CASecretRef:    secretKey("ca.crt"),
+	}}
+	config, err = RemoteClusterRESTConfig(ctx, k8sClient, cluster)
+	require.NoError(t, err)
+	assert.Equal(t, "https://east.example.com", config.Host)
+	assert.Equal(t, "endpoint-token", config.BearerToken)
+	assert.Equal(t, []byte("ca"), config.CAData)
+
+	cluster.Spec.Endpoint.TokenSecretRef.Key = "missing"
+	_, err = RemoteClusterRESTConfig(ctx, k8sClient, cluster)
+	assert.ErrorContains(t, err, "key missing not found")
+}
+
+func TestRemoteClusterRESTConfigRejectsUnsafeKubeconfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		user    string
+		cluster string
+		want    string
+	}{
+		{name: "exec", user: "exec: {apiVersion: client.authentication.k8s.io/v1, command: /bin/sh}", want: "exec credential plugins"},
+		{name: "auth provider", user: "auth-provider: {name: gcp}", want: "auth-provider"},
+		{name: "token file", user: "tokenFile: /var/run/secrets/kubernetes.io/serviceaccount/token", want: "tokenFile"},
+		{name: "client key file", user: "client-key: /etc/ssl/key.pem", want: "client-key files"},
+		{name: "ca file", user: "token: t", cluster: "certificate-authority: /etc/ssl/ca.crt", want: "certificate-authority files"},
+		{name: "insecure", user: "token: t", cluster: "insecure-skip-tls-verify: true", want: "insecure-skip-tls-verify"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kubeconfig := "apiVersion: v1\nkind: Config\ncurrent-context: east\n" +
+				"clusters: [{name: east, cluster: {server: https://east.example.com, " + tt.cluster + "}}]\n" +
+				"contexts: [{name: east, context: {cluster: east, user: ark}}]\n" +
+				"users: [{name: ark, user: {" + tt.user + "}}]\n"
+			scheme := runtime.NewScheme()
+			require.NoError(t, corev1.AddToScheme(scheme))
+			k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Secret{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+				Data:       map[string][]byte{"kubeconfig": []byte(kubeconfig)},
+			}).Build()
+			cluster := &arkv1alpha1.RemoteCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+				Spec:       arkv1alpha1.RemoteClusterSpec{KubeconfigSecretRef: secretKey("kubeconfig")},
+			}
+
+			_, err := RemoteClusterRESTConfig(context.Background(), k8sClient, cluster)
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestRemoteTargetNamespace(t *testing.T) {
+	cluster := &arkv1alpha1.RemoteCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+		Spec:       arkv1alpha1.RemoteClusterSpec{Namespace: "agents", AllowedNamespaces: []string{"shared"}},
+	}
+
+	namespace, err := RemoteTargetNamespace(cluster, arkv1alpha1.QueryTarget{Name: "weather"})
+	require.NoError(t, err)
+	assert.Equal(t, "agents", namespace)
+
+	namespace, err = RemoteTargetNamespace(cluster, arkv1alpha1.QueryTarget{Name: "weather", Namespace: "shared"})
+	require.NoError(t, err)
+	assert.Equal(t, "shared", namespace)
+
+	_, err = RemoteTargetNamespace(cluster, arkv1alpha1.QueryTarget{Name: "weather", Namespace: "kube-system"})
+	assert.ErrorContains(t, err, "namespace kube-system is not allowed by remote cluster east")
+}
+
+// completeRemoteQuery sets the status of the remote query once the executor created it
+func completeRemoteQuery(t *testing.T, remoteClient client.Client, status arkv1alpha1.QueryStatus) {
+	go func() {
+		assert.Eventually(t, func() bool {
+			var queries arkv1alpha1.QueryList
+			if err := remoteClient.List(context.Background(), &queries); err != nil || len(queries.Items) == 0 {
+				return false
+			}
+			query := queries.Items[0]
+			query.Status = status
+			return remoteClient.Status().Update(context.Background(), &query) == nil
+		}, time.Second, 5*time.Millisecond)
+	}()
+}
+
+func newRemoteTargetExecutor(remoteClient client.Client) *RemoteTargetExecutor {
+	return &RemoteTargetExecutor{Client: remoteClient, Cluster: "east", Namespace: "agents", PollInterval: 10 * time.Millisecond}
+}
+
+func remoteTestQuery() *arkv1alpha1.Query {
+	return &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "default"},
+		Spec:       arkv1alpha1.QuerySpec{SessionId: "session-1"},
+	}
+}
+
+func TestRemoteTargetExecutorDone(t *testing.T) {
+	remoteClient := fake.NewClientBuilder().WithScheme(newRemoteClusterClient(t).Scheme()).WithStatusSubresource(&arkv1alpha1.Query{}).Build()
+	completeRemoteQuery(t, remoteClient, arkv1alpha1.QueryStatus{
+		Phase:      "done",
+		Responses:  []arkv1alpha1.Response{{Content: "Hello from east", Phase: "done"}},
+		TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	})
+	recorder := &recordedEvents{}
+	target := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather", Cluster: "east"}
+
+	messages, err := newRemoteTargetExecutor(remoteClient).Execute(context.Background(), remoteTestQuery(), target, []Message{NewUserMessage("Hello")}, recorder)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "Hello from east", messages[0].OfAssistant.Content.OfString.Value)
+
+	var queries arkv1alpha1.QueryList
+	require.NoError(t, remoteClient.List(context.Background(), &queries))
+	require.Len(t, queries.Items, 1)
+	remoteQuery := queries.Items[0]
+	assert.Equal(t, "agents", remoteQuery.Namespace)
+	assert.Equal(t, "default/q1", remoteQuery.Annotations[annotations.RemoteQuerySource])
+	assert.Equal(t, []arkv1alpha1.QueryTarget{{Type: "agent", Name: "weather"}}, remoteQuery.Spec.Targets)
+	assert.Equal(t, "session-1", remoteQuery.Spec.SessionId)
+	input, err := remoteQuery.Spec.GetInputMessages()
+	require.NoError(t, err)
+	assert.Equal(t, "Hello", input[0].OfUser.Content.OfString.Value)
+
+	require.Len(t, recorder.events, 1)
+	assert.Equal(t, int64(15), recorder.events[0].TokenUsage.TotalTokens)
+	assert.Equal(t, "east", recorder.events[0].Metadata["remoteCluster"])
+}
+
+func TestRemoteTargetExecutorError(t *testing.T) {
+	remoteClient := fake.NewClientBuilder().WithScheme(newRemoteClusterClient(t).Scheme()).WithStatusSubresource(&arkv1alpha1.Query{}).Build()
+	completeRemoteQuery(t, remoteClient, arkv1alpha1.QueryStatus{
+		Phase:     "error",
+		Responses: []arkv1alpha1.Response{{Content: "agent weather not found", Phase: "error"}},
+	})
+	target := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather", Cluster: "east"}
+
+	_, err := newRemoteTargetExecutor(remoteClient).Execute(context.Background(), remoteTestQuery(), target, []Message{NewUserMessage("Hello")}, nil)
+	assert.ErrorContains(t, err, "ended with phase error: agent weather not found")
+}
+
+func TestRemoteTargetExecutorCanceled(t *testing.T) {
+	remoteClient := fake.NewClientBuilder().WithScheme(newRemoteClusterClient(t).Scheme()).WithStatusSubresource(&arkv1alpha1.Query{}).Build()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	target := arkv1alpha1.QueryTarget{Type: "agent", Name: "weather", Cluster: "east"}
+
+	_, err := newRemoteTargetExecutor(remoteClient).Execute(ctx, remoteTestQuery(), target, []Message{NewUserMessage("Hello")}, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var queries arkv1alpha1.QueryList
+	require.NoError(t, remoteClient.List(context.Background(), &queries))
+	require.Len(t, queries.Items, 1)
+	assert.True(t, queries.Items[0].Spec.Cancel)
+}
+
+func TestRemoteResponseMessagesFromRaw(t *testing.T) {
+	remoteQuery := &arkv1alpha1.Query{Status: arkv1alpha1.QueryStatus{Responses: []arkv1alpha1.Response{{
+		Content: "truncated",
+		Raw:     `[{"role": "assistant", "content": "full answer"}]`,
+	}}}}
+
+	messages, err := remoteResponseMessages(remoteQuery)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	assert.Equal(t, "full answer", messages[0].OfAssistant.Content.OfString.Value)
+}
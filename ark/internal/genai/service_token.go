@@ -0,0 +1,30 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// serviceAccountTokenPath is the token of the controller service account. ARK services such as
+// ark-cluster-memory authenticate the controller by reviewing it with the TokenReview API.
+var serviceAccountTokenPath = "/var/run/secrets/kubernetes.io/serviceaccount/token"
+
+// setServiceAccountAuthorization authenticates a request to an ARK service with the service account
+// token. The token is read for every request, since projected tokens are rotated. Outside a cluster
+// there is no token and the request is sent without one.
+func setServiceAccountAuthorization(req *http.Request) error {
+	token, err := os.ReadFile(serviceAccountTokenPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to read service account token: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	return nil
+}
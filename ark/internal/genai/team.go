@@ -4,10 +4,13 @@ import (
 	"context"
 	"fmt"
 	"slices"
+	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/telemetry"
@@ -16,6 +19,7 @@ import (
 type Team struct {
 	Name              string
 	Members           []TeamMember
+	Membership        []arkv1alpha1.TeamMember
 	Strategy          string
 	Description       string
 	MaxTurns          *int
@@ -28,6 +32,8 @@ type Team struct {
 	Namespace         string
 	memory            MemoryInterface
 	eventStream       EventStreamInterface
+	streams           *memberStreams
+	termination       *teamTermination
 }
 
 // FullName returns the namespace/name format for the team
@@ -43,8 +49,9 @@ func (t *Team) Execute(ctx context.Context, userInput Message, history []Message
 	// Store memory and streaming parameters for member execution
 	t.memory = memory
 	t.eventStream = eventStream
+	t.streams = newMemberStreams(t.Client, t.Namespace, t.Recorder, userInput)
 
-	teamTracker := NewOperationTracker(t.Recorder, ctx, "TeamExecution", t.FullName(), map[string]string{
+	teamTracker := NewOperationTracker(t.Recorder, ctx, OperationTeamExecution, t.FullName(), map[string]string{
 		"strategy":    t.Strategy,
 		"queryId":     getQueryID(ctx),
 		"sessionId":   getSessionID(ctx),
@@ -85,6 +92,7 @@ func (t *Team) executeSequential(ctx context.Context, userInput Message, history
 		turnCtx, turnSpan := t.TeamRecorder.StartTurn(ctx, i, member.GetName(), member.GetType())
 		defer turnSpan.End()
 
+		turnStart := len(newMessages)
 		err := t.executeMemberAndAccumulate(turnCtx, member, userInput, &messages, &newMessages, i)
 
 		// Record turn output
@@ -101,6 +109,10 @@ func (t *Team) executeSequential(ctx context.Context, userInput Message, history
 		}
 
 		t.TeamRecorder.RecordSuccess(turnSpan)
+
+		if done, err := t.terminated(ctx, member, i, messages, newMessages[turnStart:]); err != nil || done {
+			return newMessages, err
+		}
 	}
 
 	return newMessages, nil
@@ -122,10 +134,10 @@ func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history
 		// Check maxTurns before executing
 		if t.MaxTurns != nil && messageCount >= *t.MaxTurns {
 			turnTracker := NewExecutionRecorder(t.Recorder)
-			turnTracker.TeamTurn(ctx, "MaxTurns", t.FullName(), t.Strategy, messageCount)
+			turnTracker.TeamTurn(ctx, EventPhaseMaxTurns, t.FullName(), t.Strategy, messageCount)
 
 			// Log maxTurns reached and return success with accumulated messages
-			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "TeamMaxTurnsReached", BaseEvent{
+			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonTeamMaxTurnsReached, BaseEvent{
 				Name: t.FullName(),
 				Metadata: map[string]string{
 					"strategy":     t.Strategy,
@@ -144,6 +156,7 @@ func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history
 		turnCtx, turnSpan := t.TeamRecorder.StartTurn(ctx, messageCount, member.GetName(), member.GetType())
 		defer turnSpan.End()
 
+		turnStart := len(newMessages)
 		err := t.executeMemberAndAccumulate(turnCtx, member, userInput, &messages, &newMessages, messageCount)
 
 		// Record turn output
@@ -158,7 +171,7 @@ func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history
 			t.TeamRecorder.RecordError(turnSpan, err)
 
 			// Fail immediately on any genuine error - emit event for visibility in events view
-			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "TeamMemberFailed", BaseEvent{
+			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonTeamMemberFailed, BaseEvent{
 				Name: member.GetName(),
 				Metadata: map[string]string{
 					"error":        err.Error(),
@@ -173,6 +186,10 @@ func (t *Team) executeRoundRobin(ctx context.Context, userInput Message, history
 
 		t.TeamRecorder.RecordSuccess(turnSpan)
 
+		if done, err := t.terminated(ctx, member, messageCount, messages, newMessages[turnStart:]); err != nil || done {
+			return newMessages, err
+		}
+
 		messageCount++                                   // Increment message count
 		memberIndex = (memberIndex + 1) % len(t.Members) // Move to next agent in round-robin
 	}
@@ -191,7 +208,17 @@ func (t *Team) GetDescription() string {
 }
 
 func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, recorder EventEmitter, telemetryProvider telemetry.Provider) (*Team, error) {
-	members, err := loadTeamMembers(ctx, k8sClient, crd, recorder, telemetryProvider)
+	membership, err := resolveTeamMembership(ctx, k8sClient, crd)
+	if err != nil {
+		return nil, err
+	}
+
+	members, err := loadTeamMembers(ctx, k8sClient, crd, membership, recorder, telemetryProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	termination, err := newTeamTermination(ctx, k8sClient, crd, telemetryProvider.ModelRecorder())
 	if err != nil {
 		return nil, err
 	}
@@ -199,6 +226,7 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 	return &Team{
 		Name:              crd.Name,
 		Members:           members,
+		Membership:        membership,
 		Strategy:          crd.Spec.Strategy,
 		Description:       crd.Spec.Description,
 		MaxTurns:          crd.Spec.MaxTurns,
@@ -209,13 +237,51 @@ func MakeTeam(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Tea
 		TelemetryProvider: telemetryProvider,
 		Client:            k8sClient,
 		Namespace:         crd.Namespace,
+		termination:       termination,
 	}, nil
 }
 
-func loadTeamMembers(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, recorder EventEmitter, telemetryProvider telemetry.Provider) ([]TeamMember, error) {
-	members := make([]TeamMember, 0, len(crd.Spec.Members))
+// resolveTeamMembership returns the members of the team followed by the agents matching its member
+// selectors, in the order of the selectors and then by name. Agents that are already members are
+// not added again
+func resolveTeamMembership(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team) ([]arkv1alpha1.TeamMember, error) {
+	membership := slices.Clone(crd.Spec.Members)
+	joined := make(map[string]bool, len(membership))
+	for _, member := range membership {
+		joined[member.Type+"/"+member.Name] = true
+	}
+
+	for i, memberSelector := range crd.Spec.MemberSelectors {
+		selector, err := metav1.LabelSelectorAsSelector(&memberSelector.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("invalid member selector %d for team %s: %w", i, crd.Name, err)
+		}
+
+		var agents arkv1alpha1.AgentList
+		if err := k8sClient.List(ctx, &agents, client.InNamespace(crd.Namespace), client.MatchingLabelsSelector{Selector: selector}); err != nil {
+			return nil, fmt.Errorf("failed to list agents for member selector %d of team %s: %w", i, crd.Name, err)
+		}
+		slices.SortFunc(agents.Items, func(a, b arkv1alpha1.Agent) int {
+			return strings.Compare(a.Name, b.Name)
+		})
+
+		for _, agent := range agents.Items {
+			member := arkv1alpha1.TeamMember{Name: agent.Name, Type: string(agentKey), Role: memberSelector.Role}
+			if joined[member.Type+"/"+member.Name] {
+				continue
+			}
+			joined[member.Type+"/"+member.Name] = true
+			membership = append(membership, member)
+		}
+	}
+
+	return membership, nil
+}
+
+func loadTeamMembers(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, membership []arkv1alpha1.TeamMember, recorder EventEmitter, telemetryProvider telemetry.Provider) ([]TeamMember, error) {
+	members := make([]TeamMember, 0, len(membership))
 
-	for _, memberSpec := range crd.Spec.Members {
+	for _, memberSpec := range membership {
 		member, err := loadTeamMember(ctx, k8sClient, memberSpec, crd.Namespace, crd.Name, recorder, telemetryProvider)
 		if err != nil {
 			return nil, err
@@ -256,6 +322,7 @@ func (t *Team) executeWithTracking(tracker *OperationTracker, execFunc func(cont
 			PromptTokens:     finalTokens.PromptTokens - initialTokens.PromptTokens,
 			CompletionTokens: finalTokens.CompletionTokens - initialTokens.CompletionTokens,
 			TotalTokens:      finalTokens.TotalTokens - initialTokens.TotalTokens,
+			ReasoningTokens:  finalTokens.ReasoningTokens - initialTokens.ReasoningTokens,
 		}
 	}
 
@@ -291,7 +358,7 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 		"agent": member.GetName(),
 	})
 
-	memberTracker := NewOperationTracker(t.Recorder, ctx, "TeamMember", member.GetName(), map[string]string{
+	memberTracker := NewOperationTracker(t.Recorder, ctx, OperationTeamMember, member.GetName(), map[string]string{
 		"team":       t.FullName(),
 		"memberType": member.GetType(),
 		"turn":       fmt.Sprintf("%d", turn),
@@ -300,7 +367,17 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 		"strategy":   t.Strategy,
 	})
 
-	memberNewMessages, err := member.Execute(ctx, userInput, *messages, t.memory, t.eventStream)
+	policy := memberMemoryPolicy(member)
+	history := *messages
+	if policy == arkv1alpha1.MemoryPolicyIsolated {
+		var err error
+		if history, err = t.streams.history(ctx, member.GetName()); err != nil {
+			memberTracker.Fail(err)
+			return err
+		}
+	}
+
+	memberNewMessages, err := member.Execute(ctx, userInput, history, t.memory, t.eventStream)
 	if err != nil {
 		if IsTerminateTeam(err) {
 			memberTracker.CompleteWithTermination(err.Error())
@@ -308,17 +385,42 @@ func (t *Team) executeMemberAndAccumulate(ctx context.Context, member TeamMember
 			memberTracker.Fail(err)
 		}
 		// Still accumulate messages even on error
-		*messages = append(*messages, memberNewMessages...)
-		*newMessages = append(*newMessages, memberNewMessages...)
+		if addErr := t.accumulateMemberMessages(ctx, member, policy, memberNewMessages, messages, newMessages); addErr != nil {
+			logf.FromContext(ctx).Error(addErr, "failed to save isolated member messages", "member", member.GetName())
+		}
 		return err
 	}
 
+	if err := t.accumulateMemberMessages(ctx, member, policy, memberNewMessages, messages, newMessages); err != nil {
+		memberTracker.Fail(err)
+		return err
+	}
 	memberTracker.Complete("")
-	*messages = append(*messages, memberNewMessages...)
-	*newMessages = append(*newMessages, memberNewMessages...)
 	return nil
 }
 
+// accumulateMemberMessages adds the messages of a member turn to the team conversation according to
+// the member's memory policy. Isolated members keep their messages in their own stream and only add
+// their final response, read-only members add nothing.
+func (t *Team) accumulateMemberMessages(ctx context.Context, member TeamMember, policy arkv1alpha1.MemoryPolicy, memberNewMessages []Message, messages, newMessages *[]Message) error {
+	switch policy {
+	case arkv1alpha1.MemoryPolicyReadOnly:
+		return nil
+	case arkv1alpha1.MemoryPolicyIsolated:
+		if len(memberNewMessages) == 0 {
+			return nil
+		}
+		response := memberNewMessages[len(memberNewMessages)-1]
+		*messages = append(*messages, response)
+		*newMessages = append(*newMessages, response)
+		return t.streams.add(ctx, member.GetName(), memberNewMessages)
+	default:
+		*messages = append(*messages, memberNewMessages...)
+		*newMessages = append(*newMessages, memberNewMessages...)
+		return nil
+	}
+}
+
 func loadTeamMember(ctx context.Context, k8sClient client.Client, memberSpec arkv1alpha1.TeamMember, namespace, teamName string, recorder EventEmitter, telemetryProvider telemetry.Provider) (TeamMember, error) {
 	key := types.NamespacedName{Name: memberSpec.Name, Namespace: namespace}
 
@@ -28,7 +28,7 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 	}
 
 	turnTracker := NewExecutionRecorder(t.Recorder)
-	turnTracker.TeamTurn(ctx, "Start", t.FullName(), t.Strategy, 0)
+	turnTracker.TeamTurn(ctx, EventPhaseStart, t.FullName(), t.Strategy, 0)
 
 	currentMemberName := t.Members[0].GetName()
 
@@ -45,6 +45,7 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		turnCtx, turnSpan := t.TeamRecorder.StartTurn(ctx, turns, member.GetName(), member.GetType())
 		defer turnSpan.End()
 
+		turnStart := len(newMessages)
 		err := t.executeMemberAndAccumulate(turnCtx, member, userInput, &messages, &newMessages, turns)
 
 		// Record turn output
@@ -62,6 +63,10 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 
 		t.TeamRecorder.RecordSuccess(turnSpan)
 
+		if done, err := t.terminated(ctx, member, turns, messages, newMessages[turnStart:]); err != nil || done {
+			return newMessages, err
+		}
+
 		nextMember := transitionMap[currentMemberName]
 		if nextMember == "" {
 			break
@@ -70,9 +75,9 @@ func (t *Team) executeGraph(ctx context.Context, userInput Message, history []Me
 		currentMemberName = nextMember
 
 		if t.MaxTurns != nil && turns+1 >= *t.MaxTurns {
-			turnTracker.TeamTurn(ctx, "MaxTurns", t.FullName(), t.Strategy, turns+1)
+			turnTracker.TeamTurn(ctx, EventPhaseMaxTurns, t.FullName(), t.Strategy, turns+1)
 			// Log the maxTurns limit for observability, but return success with accumulated messages
-			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "TeamMaxTurnsReached", BaseEvent{
+			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonTeamMaxTurnsReached, BaseEvent{
 				Name: t.FullName(),
 				Metadata: map[string]string{
 					"strategy": t.Strategy,
@@ -0,0 +1,112 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// Isolated team members keep their message stream in a memory session named after the query session
+// and the agent, so they continue their own conversation in later queries of the session
+const isolatedMemorySessionInfix = "-agent-"
+
+// memberMemoryPolicy returns the memory policy of a team member. Nested teams share the conversation.
+func memberMemoryPolicy(member TeamMember) arkv1alpha1.MemoryPolicy {
+	if agent, ok := member.(*Agent); ok && agent.MemoryPolicy != "" {
+		return agent.MemoryPolicy
+	}
+	return arkv1alpha1.MemoryPolicyShared
+}
+
+// memberStreams holds the message streams of the isolated members of a team execution
+type memberStreams struct {
+	client    client.Client
+	namespace string
+	recorder  EventEmitter
+	input     Message
+	streams   map[string][]Message
+	// Members that added to their stream in this execution
+	started map[string]bool
+}
+
+func newMemberStreams(k8sClient client.Client, namespace string, recorder EventEmitter, input Message) *memberStreams {
+	return &memberStreams{
+		client:    k8sClient,
+		namespace: namespace,
+		recorder:  recorder,
+		input:     input,
+		streams:   make(map[string][]Message),
+		started:   make(map[string]bool),
+	}
+}
+
+// history returns the messages of an isolated member, loading its stream from memory on first use
+func (s *memberStreams) history(ctx context.Context, name string) ([]Message, error) {
+	if stream, ok := s.streams[name]; ok {
+		return stream, nil
+	}
+
+	memory, err := s.memory(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	if memory == nil {
+		s.streams[name] = nil
+		return nil, nil
+	}
+	defer func() { _ = memory.Close() }()
+
+	stream, err := memory.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get messages of %s from memory: %w", name, err)
+	}
+	s.streams[name] = stream
+	return stream, nil
+}
+
+// add appends messages to the stream of an isolated member and saves them to its memory session.
+// The first messages of an execution are preceded by the team input they respond to.
+func (s *memberStreams) add(ctx context.Context, name string, messages []Message) error {
+	if !s.started[name] {
+		s.started[name] = true
+		messages = append([]Message{s.input}, messages...)
+	}
+	s.streams[name] = append(s.streams[name], messages...)
+
+	memory, err := s.memory(ctx, name)
+	if err != nil || memory == nil {
+		return err
+	}
+	defer func() { _ = memory.Close() }()
+
+	query := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if err := memory.AddMessages(ctx, query.Name, messages); err != nil {
+		return fmt.Errorf("failed to save messages of %s to memory: %w", name, err)
+	}
+	return nil
+}
+
+// memory opens the memory session of an isolated member. Returns nil outside of a query, where the
+// stream only lives for the team execution.
+func (s *memberStreams) memory(ctx context.Context, name string) (MemoryInterface, error) {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok {
+		return nil, nil
+	}
+
+	sessionId := query.Spec.SessionId
+	if sessionId == "" {
+		sessionId = string(query.UID)
+	}
+
+	memory, err := NewMemoryForQuery(ctx, s.client, query.Spec.Memory, s.namespace, s.recorder, sessionId+isolatedMemorySessionInfix+name, query.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory for %s: %w", name, err)
+	}
+	return NewRedactingMemory(memory, PIIRedactorFromContext(ctx)), nil
+}
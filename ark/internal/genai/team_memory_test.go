@@ -0,0 +1,50 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestTeamMemberMemoryPolicy(t *testing.T) {
+	ctx := context.Background()
+	input := NewUserMessage("plan a trip to Lisbon")
+	team := &Team{Name: "travel", streams: newMemberStreams(nil, "default", nil, input)}
+
+	turn := []Message{NewAssistantMessage("checking flights"), NewAssistantMessage("TAP at 9:00")}
+	var messages, newMessages []Message
+
+	shared := &Agent{Name: "planner"}
+	require.Equal(t, arkv1alpha1.MemoryPolicyShared, memberMemoryPolicy(shared))
+	require.NoError(t, team.accumulateMemberMessages(ctx, shared, memberMemoryPolicy(shared), turn, &messages, &newMessages))
+	assert.Len(t, messages, 2)
+	assert.Len(t, newMessages, 2)
+
+	readOnly := &Agent{Name: "reviewer", MemoryPolicy: arkv1alpha1.MemoryPolicyReadOnly}
+	require.NoError(t, team.accumulateMemberMessages(ctx, readOnly, memberMemoryPolicy(readOnly), turn, &messages, &newMessages))
+	assert.Len(t, messages, 2)
+
+	isolated := &Agent{Name: "flights", MemoryPolicy: arkv1alpha1.MemoryPolicyIsolated}
+	history, err := team.streams.history(ctx, isolated.Name)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+
+	require.NoError(t, team.accumulateMemberMessages(ctx, isolated, memberMemoryPolicy(isolated), turn, &messages, &newMessages))
+	// Only the final response reaches the team conversation
+	assert.Len(t, messages, 3)
+	assert.Equal(t, turn[1], messages[2])
+
+	history, err = team.streams.history(ctx, isolated.Name)
+	require.NoError(t, err)
+	assert.Equal(t, []Message{input, turn[0], turn[1]}, history)
+
+	require.NoError(t, team.accumulateMemberMessages(ctx, isolated, memberMemoryPolicy(isolated), turn[1:], &messages, &newMessages))
+	history, _ = team.streams.history(ctx, isolated.Name)
+	assert.Len(t, history, 4)
+}
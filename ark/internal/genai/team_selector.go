@@ -48,10 +48,15 @@ func buildParticipants(members []TeamMember) string {
 	return strings.Join(participants, ", ")
 }
 
-func buildRoles(members []TeamMember) string {
+// buildRoles describes each member by its role in the team, or by its description when it has no role
+func buildRoles(members []TeamMember, membership []arkv1alpha1.TeamMember) string {
 	var roles []string
-	for _, member := range members {
-		if desc := member.GetDescription(); desc != "" {
+	for i, member := range members {
+		desc := member.GetDescription()
+		if i < len(membership) && membership[i].Role != "" {
+			desc = membership[i].Role
+		}
+		if desc != "" {
 			roles = append(roles, member.GetName()+": "+desc)
 		} else {
 			roles = append(roles, member.GetName())
@@ -160,12 +165,12 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 	}
 
 	participantsList := buildParticipants(t.Members)
-	rolesList := buildRoles(t.Members)
+	rolesList := buildRoles(t.Members, t.Membership)
 	previousMember := ""
 
 	for turn := 0; ; turn++ {
 		turnTracker := NewExecutionRecorder(t.Recorder)
-		turnTracker.TeamTurn(ctx, "Start", t.FullName(), t.Strategy, turn)
+		turnTracker.TeamTurn(ctx, EventPhaseStart, t.FullName(), t.Strategy, turn)
 
 		nextMember, memberIndex, err := t.selectMember(ctx, messages, tmpl, participantsList, rolesList, previousMember)
 		if err != nil {
@@ -179,6 +184,7 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 		turnCtx, turnSpan := t.TeamRecorder.StartTurn(ctx, turn, nextMember.GetName(), nextMember.GetType())
 		defer turnSpan.End()
 
+		turnStart := len(newMessages)
 		err = t.executeMemberAndAccumulate(turnCtx, nextMember, userInput, &messages, &newMessages, memberIndex)
 
 		// Record turn output
@@ -198,10 +204,14 @@ func (t *Team) executeSelector(ctx context.Context, userInput Message, history [
 
 		previousMember = nextMember.GetName()
 
+		if done, err := t.terminated(ctx, nextMember, turn, messages, newMessages[turnStart:]); err != nil || done {
+			return newMessages, err
+		}
+
 		if t.MaxTurns != nil && turn+1 >= *t.MaxTurns {
-			turnTracker.TeamTurn(ctx, "MaxTurns", t.FullName(), t.Strategy, turn+1)
+			turnTracker.TeamTurn(ctx, EventPhaseMaxTurns, t.FullName(), t.Strategy, turn+1)
 			// Log the maxTurns limit for observability, but return success with accumulated messages
-			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, "TeamMaxTurnsReached", BaseEvent{
+			t.Recorder.EmitEvent(ctx, corev1.EventTypeWarning, ReasonTeamMaxTurnsReached, BaseEvent{
 				Name: t.FullName(),
 				Metadata: map[string]string{
 					"strategy": t.Strategy,
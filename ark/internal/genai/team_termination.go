@@ -0,0 +1,198 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/google/cel-go/cel"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+const (
+	defaultModeratorPrompt = "The team has fully answered the user request."
+	moderatorSystemPrompt  = "You moderate a conversation between the members of a team and decide whether the team has converged. " +
+		`Respond only with JSON of the form {"converged": true|false, "reason": "<short explanation>"}.` + "\n\nThe team has converged when:\n"
+)
+
+// teamTermination ends a team execution when a member message matches a termination condition, or
+// when the moderator model decides the team has converged
+type teamTermination struct {
+	keywords        []string
+	patterns        []*regexp.Regexp
+	expression      cel.Program
+	expressionText  string
+	moderator       *Model
+	moderatorPrompt string
+}
+
+// CompileTeamTerminationExpression compiles a CEL termination expression over the content, member and turn variables
+func CompileTeamTerminationExpression(expression string) (cel.Program, error) {
+	env, err := cel.NewEnv(
+		cel.Variable("content", cel.StringType),
+		cel.Variable("member", cel.StringType),
+		cel.Variable("turn", cel.IntType),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ast, issues := env.Compile(expression)
+	if issues != nil && issues.Err() != nil {
+		return nil, issues.Err()
+	}
+	if ast.OutputType() != cel.BoolType {
+		return nil, fmt.Errorf("expression must evaluate to a bool, got %s", ast.OutputType())
+	}
+	return env.Program(ast)
+}
+
+// newTeamTermination compiles the termination conditions and loads the moderator model of a team.
+// Returns nil for teams that only end by their strategy or turn limit.
+func newTeamTermination(ctx context.Context, k8sClient client.Client, crd *arkv1alpha1.Team, modelRecorder telemetry.ModelRecorder) (*teamTermination, error) {
+	if crd.Spec.Termination == nil && crd.Spec.Moderator == nil {
+		return nil, nil
+	}
+
+	termination := &teamTermination{}
+	if spec := crd.Spec.Termination; spec != nil {
+		termination.keywords = spec.Keywords
+		for _, pattern := range spec.Patterns {
+			compiled, err := regexp.Compile(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("team %s has invalid termination pattern %q: %w", crd.Name, pattern, err)
+			}
+			termination.patterns = append(termination.patterns, compiled)
+		}
+		if spec.Expression != "" {
+			program, err := CompileTeamTerminationExpression(spec.Expression)
+			if err != nil {
+				return nil, fmt.Errorf("team %s has invalid termination expression %q: %w", crd.Name, spec.Expression, err)
+			}
+			termination.expression = program
+			termination.expressionText = spec.Expression
+		}
+	}
+
+	if spec := crd.Spec.Moderator; spec != nil {
+		model, err := LoadModel(ctx, k8sClient, &spec.ModelRef, crd.Namespace, modelRecorder)
+		if err != nil {
+			return nil, fmt.Errorf("team %s failed to load moderator model: %w", crd.Name, err)
+		}
+		termination.moderator = model
+		termination.moderatorPrompt = spec.Prompt
+		if termination.moderatorPrompt == "" {
+			termination.moderatorPrompt = defaultModeratorPrompt
+		}
+	}
+
+	return termination, nil
+}
+
+// check returns why the team ends after a turn of member that added turnMessages to the
+// conversation, or an empty string when the team continues
+func (c *teamTermination) check(ctx context.Context, member string, turn int, conversation, turnMessages []Message) (string, error) {
+	for _, message := range turnMessages {
+		if message.OfAssistant == nil {
+			continue
+		}
+		reason, err := c.matchCondition(member, turn, MessageText(message))
+		if err != nil || reason != "" {
+			return reason, err
+		}
+	}
+
+	if c.moderator != nil {
+		return c.moderate(ctx, conversation)
+	}
+	return "", nil
+}
+
+func (c *teamTermination) matchCondition(member string, turn int, content string) (string, error) {
+	lower := strings.ToLower(content)
+	for _, keyword := range c.keywords {
+		if keyword != "" && strings.Contains(lower, strings.ToLower(keyword)) {
+			return fmt.Sprintf("contains keyword %q", keyword), nil
+		}
+	}
+
+	for _, pattern := range c.patterns {
+		if pattern.MatchString(content) {
+			return fmt.Sprintf("matched pattern %q", pattern.String()), nil
+		}
+	}
+
+	if c.expression != nil {
+		result, _, err := c.expression.Eval(map[string]any{"content": content, "member": member, "turn": turn})
+		if err != nil {
+			return "", fmt.Errorf("termination expression %q failed: %w", c.expressionText, err)
+		}
+		if matched, ok := result.Value().(bool); ok && matched {
+			return fmt.Sprintf("matched expression %q", c.expressionText), nil
+		}
+	}
+
+	return "", nil
+}
+
+func (c *teamTermination) moderate(ctx context.Context, conversation []Message) (string, error) {
+	response, err := c.moderator.ChatCompletion(ctx, []Message{
+		NewSystemMessage(moderatorSystemPrompt + c.moderatorPrompt),
+		NewUserMessage(buildHistory(conversation)),
+	}, nil, 1)
+	if err != nil {
+		return "", fmt.Errorf("moderator call failed: %w", err)
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return "", fmt.Errorf("moderator model returned no choices")
+	}
+
+	var result struct {
+		Converged bool   `json:"converged"`
+		Reason    string `json:"reason"`
+	}
+	if err := unmarshalModelJSON(response.Choices[0].Message.Content, &result); err != nil {
+		return "", fmt.Errorf("moderator model returned invalid JSON: %w", err)
+	}
+	if !result.Converged {
+		return "", nil
+	}
+	if result.Reason == "" {
+		result.Reason = "the team has converged"
+	}
+	return "moderator: " + result.Reason, nil
+}
+
+// terminated checks the termination conditions of the team after a turn and emits an event when
+// they end the team
+func (t *Team) terminated(ctx context.Context, member TeamMember, turn int, conversation, turnMessages []Message) (bool, error) {
+	if t.termination == nil {
+		return false, nil
+	}
+
+	reason, err := t.termination.check(ctx, member.GetName(), turn, conversation, turnMessages)
+	if err != nil {
+		return false, fmt.Errorf("termination check of team %s failed: %w", t.FullName(), err)
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	t.Recorder.EmitEvent(ctx, corev1.EventTypeNormal, ReasonTeamTerminated, BaseEvent{
+		Name: t.FullName(),
+		Metadata: map[string]string{
+			"strategy": t.Strategy,
+			"teamName": t.FullName(),
+			"member":   member.GetName(),
+			"turn":     fmt.Sprintf("%d", turn),
+			"reason":   reason,
+		},
+	})
+	return true, nil
+}
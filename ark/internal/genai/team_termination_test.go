@@ -0,0 +1,119 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+// scriptedMember answers each turn with the next of its replies
+type scriptedMember struct {
+	name    string
+	replies []string
+	turns   int
+}
+
+func (m *scriptedMember) Execute(ctx context.Context, userInput Message, history []Message, memory MemoryInterface, eventStream EventStreamInterface) ([]Message, error) {
+	reply := fmt.Sprintf("%s turn %d", m.name, m.turns)
+	if m.turns < len(m.replies) {
+		reply = m.replies[m.turns]
+	}
+	m.turns++
+	return []Message{Message(openai.ChatCompletionMessageParamUnion{
+		OfAssistant: &openai.ChatCompletionAssistantMessageParam{
+			Content: openai.ChatCompletionAssistantMessageParamContentUnion{OfString: openai.String(reply)},
+		},
+	})}, nil
+}
+
+func (m *scriptedMember) GetName() string        { return m.name }
+func (m *scriptedMember) GetType() string        { return "agent" }
+func (m *scriptedMember) GetDescription() string { return "" }
+
+func newTestTermination(t *testing.T, spec arkv1alpha1.TeamTerminationSpec) *teamTermination {
+	termination, err := newTeamTermination(context.Background(), nil, &arkv1alpha1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "debate", Namespace: "default"},
+		Spec:       arkv1alpha1.TeamSpec{Termination: &spec},
+	}, nil)
+	require.NoError(t, err)
+	return termination
+}
+
+func TestTeamTerminationConditions(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    arkv1alpha1.TeamTerminationSpec
+		member  string
+		turn    int
+		content string
+		reason  string
+	}{
+		{name: "keyword ignores case", spec: arkv1alpha1.TeamTerminationSpec{Keywords: []string{"APPROVED"}}, content: "Plan approved.", reason: `contains keyword "APPROVED"`},
+		{name: "pattern", spec: arkv1alpha1.TeamTerminationSpec{Patterns: []string{`^FINAL:`}}, content: "FINAL: ship it", reason: `matched pattern "^FINAL:"`},
+		{name: "pattern not matching", spec: arkv1alpha1.TeamTerminationSpec{Patterns: []string{`^FINAL:`}}, content: "not FINAL: yet"},
+		{name: "expression", spec: arkv1alpha1.TeamTerminationSpec{Expression: `member == "reviewer" && turn >= 2`}, member: "reviewer", turn: 2, reason: "matched expression"},
+		{name: "expression not matching", spec: arkv1alpha1.TeamTerminationSpec{Expression: `member == "reviewer" && turn >= 2`}, member: "writer", turn: 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reason, err := newTestTermination(t, tt.spec).matchCondition(tt.member, tt.turn, tt.content)
+			require.NoError(t, err)
+			if tt.reason == "" {
+				assert.Empty(t, reason)
+			} else {
+				assert.Contains(t, reason, tt.reason)
+			}
+		})
+	}
+}
+
+func TestTeamTerminationRejectsInvalidConditions(t *testing.T) {
+	team := &arkv1alpha1.Team{ObjectMeta: metav1.ObjectMeta{Name: "debate"}}
+
+	team.Spec.Termination = &arkv1alpha1.TeamTerminationSpec{Patterns: []string{"("}}
+	_, err := newTeamTermination(context.Background(), nil, team, nil)
+	assert.ErrorContains(t, err, "invalid termination pattern")
+
+	team.Spec.Termination = &arkv1alpha1.TeamTerminationSpec{Expression: `content + "x"`}
+	_, err = newTeamTermination(context.Background(), nil, team, nil)
+	assert.ErrorContains(t, err, "must evaluate to a bool")
+}
+
+func TestRoundRobinTeamEndsOnTermination(t *testing.T) {
+	maxTurns := 10
+	writer := &scriptedMember{name: "writer"}
+	reviewer := &scriptedMember{name: "reviewer", replies: []string{"needs work", "LGTM"}}
+	recorder := &mockRecorder{}
+	team := &Team{
+		Name:         "review",
+		Namespace:    "default",
+		Strategy:     "round-robin",
+		Members:      []TeamMember{writer, reviewer},
+		MaxTurns:     &maxTurns,
+		Recorder:     recorder,
+		TeamRecorder: noop.NewTeamRecorder(),
+		streams:      newMemberStreams(nil, "default", nil, NewUserMessage("write a haiku")),
+		termination:  newTestTermination(t, arkv1alpha1.TeamTerminationSpec{Keywords: []string{"lgtm"}}),
+	}
+
+	messages, err := team.executeRoundRobin(context.Background(), NewUserMessage("write a haiku"), nil)
+
+	require.NoError(t, err)
+	assert.Len(t, messages, 4, "the team ends after the reviewer's second turn")
+	assert.Equal(t, 2, writer.turns)
+	assert.Equal(t, 2, reviewer.turns)
+
+	last := recorder.events[len(recorder.events)-1].(BaseEvent)
+	assert.Equal(t, "reviewer", last.Metadata["member"])
+	assert.Equal(t, "3", last.Metadata["turn"])
+}
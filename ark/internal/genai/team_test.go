@@ -0,0 +1,80 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestResolveTeamMembership(t *testing.T) {
+	s := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(s))
+
+	agent := func(name, role, namespace string) *arkv1alpha1.Agent {
+		return &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: namespace,
+			Labels:    map[string]string{"team/role": role},
+		}}
+	}
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(
+		agent("reviewer", "critic", "default"),
+		agent("fact-checker", "critic", "default"),
+		agent("editor", "writer", "default"),
+		agent("scout", "researcher", "default"),
+		agent("other-critic", "critic", "other"),
+	).Build()
+
+	team := &arkv1alpha1.Team{
+		ObjectMeta: metav1.ObjectMeta{Name: "newsroom", Namespace: "default"},
+		Spec: arkv1alpha1.TeamSpec{
+			Members: []arkv1alpha1.TeamMember{
+				{Name: "scout", Type: "agent", Role: "researcher"},
+				{Name: "editor", Type: "agent"},
+			},
+			MemberSelectors: []arkv1alpha1.TeamMemberSelector{
+				{Role: "critic", Selector: metav1.LabelSelector{MatchLabels: map[string]string{"team/role": "critic"}}},
+				{Role: "writer", Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+					{Key: "team/role", Operator: metav1.LabelSelectorOpIn, Values: []string{"writer", "critic"}},
+				}}},
+			},
+		},
+	}
+
+	membership, err := resolveTeamMembership(context.Background(), k8sClient, team)
+	require.NoError(t, err)
+	// Selected agents follow the members by selector and name, and agents already in the team keep their place
+	assert.Equal(t, []arkv1alpha1.TeamMember{
+		{Name: "scout", Type: "agent", Role: "researcher"},
+		{Name: "editor", Type: "agent"},
+		{Name: "fact-checker", Type: "agent", Role: "critic"},
+		{Name: "reviewer", Type: "agent", Role: "critic"},
+	}, membership)
+
+	team.Spec.MemberSelectors = []arkv1alpha1.TeamMemberSelector{{Selector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+		{Key: "team/role", Operator: "Near"},
+	}}}}
+	_, err = resolveTeamMembership(context.Background(), k8sClient, team)
+	assert.ErrorContains(t, err, "invalid member selector 0 for team newsroom")
+}
+
+func TestBuildRoles(t *testing.T) {
+	members := []TeamMember{
+		&scriptedMember{name: "scout"},
+		&scriptedMember{name: "reviewer"},
+	}
+	membership := []arkv1alpha1.TeamMember{
+		{Name: "scout", Type: "agent"},
+		{Name: "reviewer", Type: "agent", Role: "critic"},
+	}
+	assert.Equal(t, "scout, reviewer: critic", buildRoles(members, membership))
+}
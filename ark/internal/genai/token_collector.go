@@ -4,32 +4,81 @@ package genai
 
 import (
 	"context"
+	"sort"
 	"sync"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
+// tokenUsageKey attributes token usage to the target, team, agent and model of a call
+type tokenUsageKey struct {
+	target string
+	team   string
+	agent  string
+	model  string
+}
+
 type TokenUsageCollector struct {
 	recorder    EventEmitter
 	mu          sync.RWMutex
 	tokenUsages []TokenUsage
+	details     map[tokenUsageKey]*arkv1alpha1.TokenUsageDetail
 }
 
 func NewTokenUsageCollector(recorder EventEmitter) *TokenUsageCollector {
 	return &TokenUsageCollector{
 		recorder:    recorder,
 		tokenUsages: make([]TokenUsage, 0),
+		details:     make(map[tokenUsageKey]*arkv1alpha1.TokenUsageDetail),
 	}
 }
 
 func (c *TokenUsageCollector) EmitEvent(ctx context.Context, eventType, reason string, data EventData) {
 	c.recorder.EmitEvent(ctx, eventType, reason, data)
 
+	// Team executions report the usage of their members again, which is already collected
+	if reason == OperationTeamExecution+EventPhaseComplete {
+		return
+	}
+
 	if opEvent, ok := data.(OperationEvent); ok && opEvent.TokenUsage.TotalTokens > 0 {
+		key := tokenUsageKeyFor(ctx, opEvent)
 		c.mu.Lock()
 		c.tokenUsages = append(c.tokenUsages, opEvent.TokenUsage)
+		detail, exists := c.details[key]
+		if !exists {
+			detail = &arkv1alpha1.TokenUsageDetail{Target: key.target, Team: key.team, Agent: key.agent, Model: key.model}
+			c.details[key] = detail
+		}
+		detail.Calls++
+		detail.TokenUsage.PromptTokens += opEvent.TokenUsage.PromptTokens
+		detail.TokenUsage.CompletionTokens += opEvent.TokenUsage.CompletionTokens
+		detail.TokenUsage.TotalTokens += opEvent.TokenUsage.TotalTokens
+		detail.TokenUsage.ReasoningTokens += opEvent.TokenUsage.ReasoningTokens
 		c.mu.Unlock()
 	}
 }
 
+// tokenUsageKeyFor attributes a call by the execution metadata of the context, falling back to
+// the agent and model recorded on the event
+func tokenUsageKeyFor(ctx context.Context, event OperationEvent) tokenUsageKey {
+	metadata := GetExecutionMetadata(ctx)
+	key := tokenUsageKey{model: event.Metadata["model"], agent: event.Metadata["agent"]}
+	if target, ok := metadata["target"].(string); ok {
+		key.target = target
+	}
+	if team, ok := metadata["team"].(string); ok {
+		key.team = team
+	}
+	if agent, ok := metadata["agent"].(string); ok && agent != "" {
+		key.agent = agent
+	}
+	if model, ok := metadata["model"].(string); ok && key.model == "" {
+		key.model = model
+	}
+	return key
+}
+
 func (c *TokenUsageCollector) GetTokenSummary() TokenUsage {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
@@ -39,13 +88,40 @@ func (c *TokenUsageCollector) GetTokenSummary() TokenUsage {
 		total.PromptTokens += usage.PromptTokens
 		total.CompletionTokens += usage.CompletionTokens
 		total.TotalTokens += usage.TotalTokens
+		total.ReasoningTokens += usage.ReasoningTokens
 	}
 
 	return total
 }
 
+// GetTokenUsageDetails returns the collected token usage by target, team, agent and model
+func (c *TokenUsageCollector) GetTokenUsageDetails() []arkv1alpha1.TokenUsageDetail {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	details := make([]arkv1alpha1.TokenUsageDetail, 0, len(c.details))
+	for _, detail := range c.details {
+		details = append(details, *detail)
+	}
+	sort.Slice(details, func(i, j int) bool {
+		a, b := details[i], details[j]
+		if a.Target != b.Target {
+			return a.Target < b.Target
+		}
+		if a.Team != b.Team {
+			return a.Team < b.Team
+		}
+		if a.Agent != b.Agent {
+			return a.Agent < b.Agent
+		}
+		return a.Model < b.Model
+	})
+	return details
+}
+
 func (c *TokenUsageCollector) Reset() {
 	c.mu.Lock()
 	c.tokenUsages = make([]TokenUsage, 0)
+	c.details = make(map[tokenUsageKey]*arkv1alpha1.TokenUsageDetail)
 	c.mu.Unlock()
 }
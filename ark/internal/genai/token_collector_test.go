@@ -8,6 +8,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
 type mockRecorder struct {
@@ -69,3 +71,48 @@ func TestTokenUsageCollector(t *testing.T) {
 	assert.Equal(t, int64(0), summary.CompletionTokens)
 	assert.Equal(t, int64(0), summary.TotalTokens)
 }
+
+func TestTokenUsageCollectorDetails(t *testing.T) {
+	collector := NewTokenUsageCollector(&mockRecorder{})
+
+	teamCtx := WithExecutionMetadata(context.Background(), map[string]interface{}{
+		"target": "team/research",
+		"team":   "research",
+	})
+	llmCall := func(agent, model string, prompt, completion int64) OperationEvent {
+		return OperationEvent{
+			BaseEvent:  BaseEvent{Name: model, Metadata: map[string]string{"agent": agent, "model": model}},
+			TokenUsage: TokenUsage{PromptTokens: prompt, CompletionTokens: completion, TotalTokens: prompt + completion},
+		}
+	}
+
+	collector.EmitEvent(teamCtx, corev1.EventTypeNormal, OperationLLMCall+EventPhaseComplete, llmCall("default/writer", "gpt-4o", 100, 50))
+	collector.EmitEvent(teamCtx, corev1.EventTypeNormal, OperationLLMCall+EventPhaseComplete, llmCall("default/planner", "gpt-4o-mini", 40, 10))
+	collector.EmitEvent(teamCtx, corev1.EventTypeNormal, OperationLLMCall+EventPhaseComplete, llmCall("default/writer", "gpt-4o", 60, 20))
+
+	// The team reports the usage of its members again when it completes
+	teamComplete := OperationEvent{
+		BaseEvent:  BaseEvent{Name: "default/research"},
+		TokenUsage: TokenUsage{PromptTokens: 200, CompletionTokens: 80, TotalTokens: 280},
+	}
+	collector.EmitEvent(teamCtx, corev1.EventTypeNormal, OperationTeamExecution+EventPhaseComplete, teamComplete)
+
+	agentCtx := WithExecutionMetadata(context.Background(), map[string]interface{}{"target": "agent/weather"})
+	collector.EmitEvent(agentCtx, corev1.EventTypeNormal, OperationLLMCall+EventPhaseComplete, llmCall("default/weather", "gpt-4o", 30, 5))
+
+	summary := collector.GetTokenSummary()
+	assert.Equal(t, int64(315), summary.TotalTokens)
+
+	details := collector.GetTokenUsageDetails()
+	assert.Equal(t, []arkv1alpha1.TokenUsageDetail{
+		{Target: "agent/weather", Agent: "default/weather", Model: "gpt-4o", Calls: 1,
+			TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 30, CompletionTokens: 5, TotalTokens: 35}},
+		{Target: "team/research", Team: "research", Agent: "default/planner", Model: "gpt-4o-mini", Calls: 1,
+			TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 40, CompletionTokens: 10, TotalTokens: 50}},
+		{Target: "team/research", Team: "research", Agent: "default/writer", Model: "gpt-4o", Calls: 2,
+			TokenUsage: arkv1alpha1.TokenUsage{PromptTokens: 160, CompletionTokens: 70, TotalTokens: 230}},
+	}, details)
+
+	collector.Reset()
+	assert.Empty(t, collector.GetTokenUsageDetails())
+}
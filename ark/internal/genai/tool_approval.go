@@ -0,0 +1,185 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const defaultToolApprovalPollInterval = 2 * time.Second
+
+// ApprovalToolExecutor pauses each call of a tool that requires approval. It creates a ToolApproval
+// with the arguments of the call and runs the call once a user approved it. Rejected calls return
+// the reason to the model as a tool error.
+type ApprovalToolExecutor struct {
+	BaseExecutor ToolExecutor
+	K8sClient    client.Client
+	ToolName     string
+	Namespace    string
+	PollInterval time.Duration
+}
+
+func (a *ApprovalToolExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok {
+		err := fmt.Errorf("tool %s requires approval, which is only available when running a query", a.ToolName)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	approval, err := a.requestApproval(ctx, query, call)
+	if err != nil {
+		err = fmt.Errorf("failed to request approval of tool %s: %w", a.ToolName, err)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	emitToolApprovalEvent(ctx, recorder, corev1.EventTypeNormal, ReasonToolApprovalRequested, approval)
+
+	phase, reason, err := a.waitForDecision(ctx, approval)
+	if err != nil {
+		a.setDecision(ctx, approval, arkv1alpha1.ToolApprovalExpired, "the query ended before a decision was made")
+		err = fmt.Errorf("tool %s was not approved: %w", a.ToolName, err)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	a.setDecision(ctx, approval, phase, reason)
+
+	if phase == arkv1alpha1.ToolApprovalRejected {
+		emitToolApprovalEvent(ctx, recorder, corev1.EventTypeWarning, ReasonToolApprovalRejected, approval)
+		message := fmt.Sprintf("Error: the call of tool %s was rejected", a.ToolName)
+		if reason != "" {
+			message += ": " + reason
+		}
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Content: message, Error: message}, nil
+	}
+
+	emitToolApprovalEvent(ctx, recorder, corev1.EventTypeNormal, ReasonToolApprovalApproved, approval)
+	return a.BaseExecutor.Execute(ctx, call, recorder)
+}
+
+// requestApproval creates the ToolApproval of a call, or returns it when the call is resumed
+func (a *ApprovalToolExecutor) requestApproval(ctx context.Context, query *arkv1alpha1.Query, call ToolCall) (*arkv1alpha1.ToolApproval, error) {
+	approval := &arkv1alpha1.ToolApproval{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ToolApprovalName(query.Name, call.ID),
+			Namespace: a.Namespace,
+			Labels: map[string]string{
+				annotations.Query: query.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: arkv1alpha1.GroupVersion.String(),
+				Kind:       "Query",
+				Name:       query.Name,
+				UID:        query.UID,
+				Controller: &[]bool{true}[0],
+			}},
+		},
+		Spec: arkv1alpha1.ToolApprovalSpec{
+			Query:      query.Name,
+			Tool:       a.ToolName,
+			ToolCallID: call.ID,
+			Arguments:  call.Function.Arguments,
+		},
+		Status: arkv1alpha1.ToolApprovalStatus{Phase: arkv1alpha1.ToolApprovalPending},
+	}
+
+	err := a.K8sClient.Create(ctx, approval)
+	if errors.IsAlreadyExists(err) {
+		err = a.K8sClient.Get(ctx, client.ObjectKeyFromObject(approval), approval)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return approval, nil
+}
+
+// waitForDecision polls the ToolApproval until it is approved or rejected, or ctx is done
+func (a *ApprovalToolExecutor) waitForDecision(ctx context.Context, approval *arkv1alpha1.ToolApproval) (string, string, error) {
+	interval := a.PollInterval
+	if interval <= 0 {
+		interval = defaultToolApprovalPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if phase, reason := ToolApprovalDecision(approval); phase != arkv1alpha1.ToolApprovalPending {
+			return phase, reason, nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", "", ctx.Err()
+		case <-ticker.C:
+		}
+		if err := a.K8sClient.Get(ctx, client.ObjectKeyFromObject(approval), approval); err != nil {
+			if ctx.Err() != nil {
+				return "", "", ctx.Err()
+			}
+			return "", "", err
+		}
+	}
+}
+
+// setDecision records the decision in the ToolApproval status. Failures are only logged, the
+// decision was already read from the annotations.
+func (a *ApprovalToolExecutor) setDecision(ctx context.Context, approval *arkv1alpha1.ToolApproval, phase, reason string) {
+	if approval.Status.Phase == phase {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+	approval.Status.Phase = phase
+	approval.Status.Reason = reason
+	now := metav1.Now()
+	approval.Status.DecisionTime = &now
+	if err := a.K8sClient.Status().Update(ctx, approval); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to update tool approval status", "toolApproval", approval.Name)
+	}
+}
+
+// ToolApprovalDecision returns the phase of a ToolApproval from its annotations or status, and the
+// rejection reason. Rejection takes precedence when both annotations are set.
+func ToolApprovalDecision(approval *arkv1alpha1.ToolApproval) (string, string) {
+	if reason, ok := approval.Annotations[annotations.Reject]; ok {
+		return arkv1alpha1.ToolApprovalRejected, reason
+	}
+	if _, ok := approval.Annotations[annotations.Approve]; ok {
+		return arkv1alpha1.ToolApprovalApproved, ""
+	}
+	if approval.Status.Phase == arkv1alpha1.ToolApprovalApproved || approval.Status.Phase == arkv1alpha1.ToolApprovalRejected {
+		return approval.Status.Phase, approval.Status.Reason
+	}
+	return arkv1alpha1.ToolApprovalPending, ""
+}
+
+// ToolApprovalName returns the name of the ToolApproval of a tool call of a query
+func ToolApprovalName(queryName, toolCallID string) string {
+	sum := sha256.Sum256([]byte(toolCallID))
+	name := queryName
+	if len(name) > 236 {
+		name = name[:236]
+	}
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+func emitToolApprovalEvent(ctx context.Context, recorder EventEmitter, eventType, reason string, approval *arkv1alpha1.ToolApproval) {
+	if recorder == nil {
+		return
+	}
+	recorder.EmitEvent(ctx, eventType, reason, BaseEvent{
+		Name: approval.Spec.Tool,
+		Metadata: map[string]string{
+			"toolApproval": approval.Name,
+			"arguments":    approval.Spec.Arguments,
+		},
+	})
+}
@@ -0,0 +1,129 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+func newApprovalExecutor(t *testing.T) (*ApprovalToolExecutor, client.Client, context.Context) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&arkv1alpha1.ToolApproval{}).Build()
+	query := &arkv1alpha1.Query{ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "default", UID: "q1-uid"}}
+	executor := &ApprovalToolExecutor{
+		BaseExecutor: &staticToolExecutor{content: "deleted"},
+		K8sClient:    k8sClient,
+		ToolName:     "delete-branch",
+		Namespace:    "default",
+		PollInterval: 10 * time.Millisecond,
+	}
+	return executor, k8sClient, context.WithValue(context.Background(), QueryContextKey, query)
+}
+
+func approvalCall() ToolCall {
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = "delete-branch"
+	call.Function.Arguments = `{"branch": "main"}`
+	return call
+}
+
+// decide annotates the ToolApproval of the call once the executor created it
+func decide(t *testing.T, k8sClient client.Client, annotation, value string) {
+	key := types.NamespacedName{Name: ToolApprovalName("q1", "call-1"), Namespace: "default"}
+	go func() {
+		approval := &arkv1alpha1.ToolApproval{}
+		assert.Eventually(t, func() bool {
+			if err := k8sClient.Get(context.Background(), key, approval); err != nil {
+				return false
+			}
+			approval.Annotations = map[string]string{annotation: value}
+			return k8sClient.Update(context.Background(), approval) == nil
+		}, time.Second, 5*time.Millisecond)
+	}()
+}
+
+func TestApprovalToolExecutorApproved(t *testing.T) {
+	executor, k8sClient, ctx := newApprovalExecutor(t)
+	decide(t, k8sClient, annotations.Approve, "")
+
+	result, err := executor.Execute(ctx, approvalCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "deleted", result.Content)
+
+	approval := &arkv1alpha1.ToolApproval{}
+	require.NoError(t, k8sClient.Get(ctx, types.NamespacedName{Name: ToolApprovalName("q1", "call-1"), Namespace: "default"}, approval))
+	assert.Equal(t, "q1", approval.Spec.Query)
+	assert.Equal(t, `{"branch": "main"}`, approval.Spec.Arguments)
+	assert.Equal(t, "q1", approval.Labels[annotations.Query])
+	assert.Equal(t, arkv1alpha1.ToolApprovalApproved, approval.Status.Phase)
+	assert.NotNil(t, approval.Status.DecisionTime)
+}
+
+func TestApprovalToolExecutorRejected(t *testing.T) {
+	executor, k8sClient, ctx := newApprovalExecutor(t)
+	decide(t, k8sClient, annotations.Reject, "protected branch")
+
+	result, err := executor.Execute(ctx, approvalCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "Error: the call of tool delete-branch was rejected: protected branch", result.Content)
+	assert.Equal(t, result.Content, result.Error)
+}
+
+func TestApprovalToolExecutorExpired(t *testing.T) {
+	executor, k8sClient, ctx := newApprovalExecutor(t)
+	ctx, cancel := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer cancel()
+
+	_, err := executor.Execute(ctx, approvalCall(), nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	approval := &arkv1alpha1.ToolApproval{}
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: ToolApprovalName("q1", "call-1"), Namespace: "default"}, approval))
+	assert.Equal(t, arkv1alpha1.ToolApprovalExpired, approval.Status.Phase)
+}
+
+func TestApprovalToolExecutorRequiresQuery(t *testing.T) {
+	executor, _, _ := newApprovalExecutor(t)
+
+	_, err := executor.Execute(context.Background(), approvalCall(), nil)
+	assert.ErrorContains(t, err, "only available when running a query")
+}
+
+func TestToolApprovalDecision(t *testing.T) {
+	approval := &arkv1alpha1.ToolApproval{}
+	phase, _ := ToolApprovalDecision(approval)
+	assert.Equal(t, arkv1alpha1.ToolApprovalPending, phase)
+
+	approval.Status.Phase = arkv1alpha1.ToolApprovalRejected
+	approval.Status.Reason = "no"
+	phase, reason := ToolApprovalDecision(approval)
+	assert.Equal(t, arkv1alpha1.ToolApprovalRejected, phase)
+	assert.Equal(t, "no", reason)
+
+	approval.Annotations = map[string]string{annotations.Approve: "", annotations.Reject: "too risky"}
+	phase, reason = ToolApprovalDecision(approval)
+	assert.Equal(t, arkv1alpha1.ToolApprovalRejected, phase)
+	assert.Equal(t, "too risky", reason)
+}
+
+func TestToolApprovalName(t *testing.T) {
+	name := ToolApprovalName("q1", "call-1")
+	assert.Equal(t, name, ToolApprovalName("q1", "call-1"))
+	assert.NotEqual(t, name, ToolApprovalName("q1", "call-2"))
+	assert.Len(t, ToolApprovalName(strings.Repeat("q", 300), "call-1"), 253)
+}
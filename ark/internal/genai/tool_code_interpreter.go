@@ -0,0 +1,302 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const (
+	defaultCodeInterpreterTimeout = 60 * time.Second
+	// Time the sandbox pod has beyond the code timeout to be scheduled and report the output
+	codeInterpreterStartupGrace = 2 * time.Minute
+	// CodeInterpreterNetworkPolicy is the NetworkPolicy denying all traffic of sandbox pods in a namespace
+	CodeInterpreterNetworkPolicy = "ark-code-interpreter-sandbox"
+	codeInterpreterSandbox       = "code-interpreter"
+	codeInterpreterUser          = int64(65534)
+)
+
+// Code interpreter languages
+const (
+	CodeInterpreterPython     = "python"
+	CodeInterpreterJavaScript = "javascript"
+)
+
+var codeInterpreterImages = map[string]string{
+	CodeInterpreterPython:     "python:3.12-slim",
+	CodeInterpreterJavaScript: "node:22-slim",
+}
+
+var codeInterpreterCommands = map[string][]string{
+	CodeInterpreterPython:     {"python", "-B", "-c", pythonSandboxRunner},
+	CodeInterpreterJavaScript: {"node", "-e", javaScriptSandboxRunner},
+}
+
+// The runners execute the code in /workspace and write the exit code, output and the files the
+// code wrote to the termination message, cut to its 4096 byte limit
+const pythonSandboxRunner = `
+import json, os, subprocess, sys
+LIMIT, WORKDIR = 4000, "/workspace"
+main = os.path.join(WORKDIR, "main.py")
+with open(os.environ["ARK_TOOL_ARGUMENTS_FILE"]) as f:
+    code = json.load(f).get("code", "")
+with open(main, "w") as f:
+    f.write(code)
+timeout = int(os.environ["ARK_CODE_TIMEOUT"])
+result = {"exitCode": 0, "stdout": "", "stderr": "", "files": []}
+try:
+    run = subprocess.run([sys.executable, "-B", main], cwd=WORKDIR, capture_output=True, timeout=timeout)
+    exit_code, out, err = run.returncode, run.stdout, run.stderr
+except subprocess.TimeoutExpired as e:
+    exit_code, out, err = -1, e.stdout or b"", (e.stderr or b"") + b"\ntimed out after %ds" % timeout
+result.update(exitCode=exit_code, stdout=out.decode(errors="replace"), stderr=err.decode(errors="replace"))
+for root, _, names in os.walk(WORKDIR):
+    for name in sorted(names):
+        path = os.path.join(root, name)
+        if path == main:
+            continue
+        entry = {"name": os.path.relpath(path, WORKDIR), "size": os.path.getsize(path)}
+        if entry["size"] <= 1024:
+            try:
+                with open(path, encoding="utf-8") as f:
+                    entry["content"] = f.read()
+            except (UnicodeDecodeError, OSError):
+                pass
+        result["files"].append(entry)
+size = lambda: len(json.dumps(result).encode())
+for entry in reversed(result["files"]):
+    if size() <= LIMIT:
+        break
+    entry.pop("content", None)
+for key in ("stderr", "stdout"):
+    while size() > LIMIT and result[key]:
+        result[key] = result[key][: len(result[key]) // 2]
+        result["truncated"] = True
+while size() > LIMIT and result["files"]:
+    result["files"].pop()
+with open("/dev/termination-log", "w") as f:
+    f.write(json.dumps(result))
+`
+
+const javaScriptSandboxRunner = `
+const fs = require("fs"), path = require("path"), { spawnSync } = require("child_process");
+const LIMIT = 4000, WORKDIR = "/workspace", main = path.join(WORKDIR, "main.js");
+const code = JSON.parse(fs.readFileSync(process.env.ARK_TOOL_ARGUMENTS_FILE, "utf8")).code || "";
+fs.writeFileSync(main, code);
+const timeout = Number(process.env.ARK_CODE_TIMEOUT);
+const run = spawnSync(process.execPath, [main], { cwd: WORKDIR, timeout: timeout * 1000, encoding: "utf8", maxBuffer: 64 * 1024 * 1024 });
+const result = { exitCode: run.status === null ? -1 : run.status, stdout: run.stdout || "", stderr: run.stderr || "", files: [] };
+if (run.error && run.error.code === "ETIMEDOUT") result.stderr += "\ntimed out after " + timeout + "s";
+const walk = (dir) => {
+  for (const name of fs.readdirSync(dir).sort()) {
+    const file = path.join(dir, name), stat = fs.statSync(file);
+    if (stat.isDirectory()) { walk(file); continue; }
+    if (file === main) continue;
+    const entry = { name: path.relative(WORKDIR, file), size: stat.size };
+    if (stat.size <= 1024) {
+      const text = fs.readFileSync(file, "utf8");
+      if (!text.includes("�")) entry.content = text;
+    }
+    result.files.push(entry);
+  }
+};
+walk(WORKDIR);
+const size = () => Buffer.byteLength(JSON.stringify(result));
+for (const entry of [...result.files].reverse()) { if (size() <= LIMIT) break; delete entry.content; }
+for (const key of ["stderr", "stdout"]) {
+  while (size() > LIMIT && result[key]) { result[key] = result[key].slice(0, Math.floor(result[key].length / 2)); result.truncated = true; }
+}
+while (size() > LIMIT && result.files.length) result.files.pop();
+fs.writeFileSync("/dev/termination-log", JSON.stringify(result));
+`
+
+// CodeInterpreterExecutor runs code written by the model as a job in a sandbox pod. The pod has no
+// network access or service account token, and runs as an unprivileged user with a read-only root
+// file system and a small writable workspace. The exit code, output and files the code wrote are
+// returned to the model as JSON.
+type CodeInterpreterExecutor struct {
+	K8sClient client.Client
+	Namespace string
+	Job       *JobToolExecutor
+}
+
+// NewCodeInterpreterExecutor creates the executor of a code interpreter tool
+func NewCodeInterpreterExecutor(k8sClient client.Client, toolName, namespace string, spec *arkv1alpha1.CodeInterpreterSpec) (*CodeInterpreterExecutor, error) {
+	if spec == nil {
+		spec = &arkv1alpha1.CodeInterpreterSpec{}
+	}
+	language := spec.Language
+	if language == "" {
+		language = CodeInterpreterPython
+	}
+	command, ok := codeInterpreterCommands[language]
+	if !ok {
+		return nil, fmt.Errorf("unsupported code interpreter language '%s': must be '%s' or '%s'", language, CodeInterpreterPython, CodeInterpreterJavaScript)
+	}
+	timeout, err := CodeInterpreterTimeout(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	image := spec.Image
+	if image == "" {
+		image = codeInterpreterImages[language]
+	}
+	resources := spec.Resources
+	if resources == nil {
+		resources = &corev1.ResourceRequirements{Limits: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("512Mi"),
+		}}
+	}
+
+	return &CodeInterpreterExecutor{
+		K8sClient: k8sClient,
+		Namespace: namespace,
+		Job: &JobToolExecutor{
+			K8sClient: k8sClient,
+			ToolName:  toolName,
+			Namespace: namespace,
+			Spec: arkv1alpha1.JobToolSpec{
+				Image:     image,
+				Command:   command,
+				Resources: resources,
+				Timeout:   strconv.Itoa(int((timeout + codeInterpreterStartupGrace).Seconds())),
+			},
+			configurePod: func(template *corev1.PodTemplateSpec) {
+				sandboxPod(template, timeout)
+			},
+		},
+	}, nil
+}
+
+func (c *CodeInterpreterExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	var arguments struct {
+		Code string `json:"code"`
+	}
+	if result, err := parseBuiltinToolArguments(call, &arguments); err != nil {
+		return result, err
+	}
+	if strings.TrimSpace(arguments.Code) == "" {
+		return builtinToolError(call, fmt.Errorf("code is required"))
+	}
+
+	if err := c.ensureNetworkPolicy(ctx); err != nil {
+		return builtinToolError(call, fmt.Errorf("failed to isolate the code interpreter sandbox: %w", err))
+	}
+	return c.Job.Execute(ctx, call, recorder)
+}
+
+// ensureNetworkPolicy creates the NetworkPolicy denying all ingress and egress of sandbox pods in
+// the namespace, and restores its spec if it was changed. Code does not run unless the policy is in
+// place. NetworkPolicies are additive, so other policies of the namespace that select sandbox pods
+// still allow their traffic, and the policy is only enforced by network plugins that support it.
+func (c *CodeInterpreterExecutor) ensureNetworkPolicy(ctx context.Context) error {
+	policy := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: CodeInterpreterNetworkPolicy, Namespace: c.Namespace},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{annotations.Sandbox: codeInterpreterSandbox}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+		},
+	}
+	err := c.K8sClient.Create(ctx, policy)
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	existing := &networkingv1.NetworkPolicy{}
+	if err := c.K8sClient.Get(ctx, client.ObjectKeyFromObject(policy), existing); err != nil {
+		return err
+	}
+	if equality.Semantic.DeepEqual(existing.Spec, policy.Spec) {
+		return nil
+	}
+	existing.Spec = policy.Spec
+	if err := c.K8sClient.Update(ctx, existing); err != nil {
+		return fmt.Errorf("network policy %s was changed and cannot be restored: %w", policy.Name, err)
+	}
+	return nil
+}
+
+// sandboxPod locks down the pod running the code
+func sandboxPod(template *corev1.PodTemplateSpec, timeout time.Duration) {
+	template.Labels[annotations.Sandbox] = codeInterpreterSandbox
+
+	spec := &template.Spec
+	spec.AutomountServiceAccountToken = &[]bool{false}[0]
+	spec.EnableServiceLinks = &[]bool{false}[0]
+	spec.SecurityContext = &corev1.PodSecurityContext{
+		RunAsNonRoot:   &[]bool{true}[0],
+		RunAsUser:      &[]int64{codeInterpreterUser}[0],
+		RunAsGroup:     &[]int64{codeInterpreterUser}[0],
+		FSGroup:        &[]int64{codeInterpreterUser}[0],
+		SeccompProfile: &corev1.SeccompProfile{Type: corev1.SeccompProfileTypeRuntimeDefault},
+	}
+	workspaceLimit := resource.MustParse("100Mi")
+	spec.Volumes = append(spec.Volumes,
+		corev1.Volume{Name: "workspace", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &workspaceLimit}}},
+		corev1.Volume{Name: "tmp", VolumeSource: corev1.VolumeSource{EmptyDir: &corev1.EmptyDirVolumeSource{SizeLimit: &workspaceLimit}}},
+	)
+
+	container := &spec.Containers[0]
+	container.WorkingDir = "/workspace"
+	container.Env = append(container.Env,
+		corev1.EnvVar{Name: "ARK_CODE_TIMEOUT", Value: strconv.Itoa(int(math.Ceil(timeout.Seconds())))},
+		corev1.EnvVar{Name: "HOME", Value: "/tmp"},
+	)
+	container.VolumeMounts = append(container.VolumeMounts,
+		corev1.VolumeMount{Name: "workspace", MountPath: "/workspace"},
+		corev1.VolumeMount{Name: "tmp", MountPath: "/tmp"},
+	)
+	container.SecurityContext = &corev1.SecurityContext{
+		AllowPrivilegeEscalation: &[]bool{false}[0],
+		ReadOnlyRootFilesystem:   &[]bool{true}[0],
+		Capabilities:             &corev1.Capabilities{Drop: []corev1.Capability{"ALL"}},
+	}
+}
+
+// CodeInterpreterTimeout returns the maximum run time of the code of a code interpreter tool
+func CodeInterpreterTimeout(spec *arkv1alpha1.CodeInterpreterSpec) (time.Duration, error) {
+	if spec == nil || spec.Timeout == "" {
+		return defaultCodeInterpreterTimeout, nil
+	}
+	timeout, err := parseToolTimeout(spec.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid code interpreter timeout: %w", err)
+	}
+	return timeout, nil
+}
+
+// codeInterpreterParameters returns the parameters of code interpreter tools without an input schema
+func codeInterpreterParameters(spec *arkv1alpha1.CodeInterpreterSpec) map[string]any {
+	language := CodeInterpreterPython
+	if spec != nil && spec.Language != "" {
+		language = spec.Language
+	}
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"code": map[string]any{
+				"type":        "string",
+				"description": fmt.Sprintf("The %s program to run. Print results to stdout, files written to the working directory are returned", language),
+			},
+		},
+		"required": []string{"code"},
+	}
+}
@@ -0,0 +1,141 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+func codeInterpreterCall(arguments string) ToolCall {
+	return ToolCall{
+		ID:       "call_1",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "run-code", Arguments: arguments},
+	}
+}
+
+func TestCodeInterpreterRunsCodeInSandbox(t *testing.T) {
+	output := `{"exitCode":0,"stdout":"42\n","stderr":"","files":[]}`
+	k8sClient, _, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, output))
+	executor, err := NewCodeInterpreterExecutor(k8sClient, "run-code", "default", &arkv1alpha1.CodeInterpreterSpec{Timeout: "30s"})
+	require.NoError(t, err)
+	executor.Job.PollInterval = time.Millisecond
+
+	result, err := executor.Execute(ctx, codeInterpreterCall(`{"code":"print(6 * 7)"}`), nil)
+	require.NoError(t, err)
+	assert.Equal(t, output, result.Content)
+
+	var job batchv1.Job
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: ToolJobName(toolJobQuery(), "run-code", "call_1"), Namespace: "default"}, &job))
+	assert.Equal(t, int64(150), *job.Spec.ActiveDeadlineSeconds)
+	pod := job.Spec.Template
+	assert.Equal(t, "code-interpreter", pod.Labels[annotations.Sandbox])
+	assert.False(t, *pod.Spec.AutomountServiceAccountToken)
+	assert.True(t, *pod.Spec.SecurityContext.RunAsNonRoot)
+
+	container := pod.Spec.Containers[0]
+	assert.Equal(t, "python:3.12-slim", container.Image)
+	assert.Equal(t, []string{"python", "-B", "-c", pythonSandboxRunner}, container.Command)
+	assert.Equal(t, "1", container.Resources.Limits.Cpu().String())
+	assert.True(t, *container.SecurityContext.ReadOnlyRootFilesystem)
+	assert.False(t, *container.SecurityContext.AllowPrivilegeEscalation)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "ARK_CODE_TIMEOUT", Value: "30"})
+	assert.Contains(t, container.VolumeMounts, corev1.VolumeMount{Name: "workspace", MountPath: "/workspace"})
+
+	var policy networkingv1.NetworkPolicy
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: CodeInterpreterNetworkPolicy, Namespace: "default"}, &policy))
+	assert.Equal(t, map[string]string{annotations.Sandbox: "code-interpreter"}, policy.Spec.PodSelector.MatchLabels)
+	assert.ElementsMatch(t, []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress}, policy.Spec.PolicyTypes)
+	assert.Empty(t, policy.Spec.Ingress)
+	assert.Empty(t, policy.Spec.Egress)
+}
+
+func TestCodeInterpreterJavaScript(t *testing.T) {
+	executor, err := NewCodeInterpreterExecutor(nil, "run-code", "default", &arkv1alpha1.CodeInterpreterSpec{Language: CodeInterpreterJavaScript})
+	require.NoError(t, err)
+	assert.Equal(t, "node:22-slim", executor.Job.Spec.Image)
+	assert.Equal(t, []string{"node", "-e", javaScriptSandboxRunner}, executor.Job.Spec.Command)
+	assert.Equal(t, "180", executor.Job.Spec.Timeout)
+}
+
+func TestCodeInterpreterRequiresCode(t *testing.T) {
+	k8sClient, _, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, ""))
+	executor, err := NewCodeInterpreterExecutor(k8sClient, "run-code", "default", nil)
+	require.NoError(t, err)
+
+	result, err := executor.Execute(ctx, codeInterpreterCall(`{"code":"  "}`), nil)
+	require.Error(t, err)
+	assert.Equal(t, "code is required", result.Error)
+
+	var jobs batchv1.JobList
+	require.NoError(t, k8sClient.List(context.Background(), &jobs))
+	assert.Empty(t, jobs.Items)
+}
+
+func TestCodeInterpreterInvalidSpec(t *testing.T) {
+	_, err := NewCodeInterpreterExecutor(nil, "run-code", "default", &arkv1alpha1.CodeInterpreterSpec{Language: "ruby"})
+	assert.ErrorContains(t, err, "unsupported code interpreter language 'ruby'")
+
+	_, err = NewCodeInterpreterExecutor(nil, "run-code", "default", &arkv1alpha1.CodeInterpreterSpec{Timeout: "soon"})
+	assert.ErrorContains(t, err, "invalid code interpreter timeout")
+}
+
+func TestCodeInterpreterRestoresChangedNetworkPolicy(t *testing.T) {
+	opened := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: CodeInterpreterNetworkPolicy, Namespace: "default"},
+		Spec: networkingv1.NetworkPolicySpec{
+			PodSelector: metav1.LabelSelector{MatchLabels: map[string]string{annotations.Sandbox: "code-interpreter"}},
+			PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress, networkingv1.PolicyTypeEgress},
+			Egress:      []networkingv1.NetworkPolicyEgressRule{{}},
+		},
+	}
+	output := `{"exitCode":0,"stdout":"","stderr":"","files":[]}`
+	k8sClient, _, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, output), opened)
+	executor, err := NewCodeInterpreterExecutor(k8sClient, "run-code", "default", nil)
+	require.NoError(t, err)
+	executor.Job.PollInterval = time.Millisecond
+
+	_, err = executor.Execute(ctx, codeInterpreterCall(`{"code":"pass"}`), nil)
+	require.NoError(t, err)
+
+	var policy networkingv1.NetworkPolicy
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(opened), &policy))
+	assert.Empty(t, policy.Spec.Egress)
+}
+
+func TestCodeInterpreterFailsClosedOnChangedNetworkPolicy(t *testing.T) {
+	opened := &networkingv1.NetworkPolicy{
+		ObjectMeta: metav1.ObjectMeta{Name: CodeInterpreterNetworkPolicy, Namespace: "default"},
+		Spec:       networkingv1.NetworkPolicySpec{PolicyTypes: []networkingv1.PolicyType{networkingv1.PolicyTypeIngress}},
+	}
+	k8sClient, _, ctx := newToolJobTest(t, interceptor.Funcs{
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			return apierrors.NewForbidden(networkingv1.Resource("networkpolicies"), obj.GetName(), nil)
+		},
+	}, opened)
+	executor, err := NewCodeInterpreterExecutor(k8sClient, "run-code", "default", nil)
+	require.NoError(t, err)
+
+	_, err = executor.Execute(ctx, codeInterpreterCall(`{"code":"pass"}`), nil)
+	assert.ErrorContains(t, err, "was changed and cannot be restored")
+
+	var jobs batchv1.JobList
+	require.NoError(t, k8sClient.List(context.Background(), &jobs))
+	assert.Empty(t, jobs.Items)
+}
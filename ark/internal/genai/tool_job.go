@@ -0,0 +1,338 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const (
+	defaultToolJobTimeout      = 10 * time.Minute
+	defaultToolJobPollInterval = 5 * time.Second
+	// Jobs of queries in other namespaces are not owned by the query and are removed after this time
+	toolJobTTL = time.Hour
+
+	// ToolJobArgumentsKey is the key of the call arguments in the ConfigMap of a tool job
+	ToolJobArgumentsKey = "arguments.json"
+	// ToolJobResultKey is the key of the ConfigMap of a tool job the container may write its result to
+	ToolJobResultKey = "result"
+
+	toolJobContainer = "tool"
+	toolJobInputDir  = "/ark/input"
+)
+
+// JobToolExecutor runs every call of a job tool as a Kubernetes Job. The arguments of the call are
+// mounted into the pod from a ConfigMap, and the result is read from the result key of that
+// ConfigMap, or else from the termination message of the container. Jobs are named after the query
+// and call, so a call resumed after a restart of the controller waits for the job already started.
+type JobToolExecutor struct {
+	K8sClient    client.Client
+	ToolName     string
+	Namespace    string
+	Spec         arkv1alpha1.JobToolSpec
+	PollInterval time.Duration
+	// configurePod adjusts the pod template of jobs, such as to run them in a sandbox
+	configurePod func(*corev1.PodTemplateSpec)
+}
+
+func (j *JobToolExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok {
+		err := fmt.Errorf("tool %s runs as a job, which is only available when running a query", j.ToolName)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	// The client then impersonates the identity of the query, so jobs are only created with its
+	// permissions and never with those of the controller
+	if query.Spec.ServiceAccount == "" && query.Spec.Impersonate == nil {
+		err := fmt.Errorf("tool %s runs as a job, which requires the query to set serviceAccount or impersonate", j.ToolName)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	job, err := j.startJob(ctx, query, call)
+	if err != nil {
+		err = fmt.Errorf("failed to start job of tool %s: %w", j.ToolName, err)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	emitToolJobEvent(ctx, recorder, corev1.EventTypeNormal, ReasonToolJobStarted, j.ToolName, job)
+
+	if err := j.waitForJob(ctx, job); err != nil {
+		emitToolJobEvent(ctx, recorder, corev1.EventTypeWarning, ReasonToolJobFailed, j.ToolName, job)
+		err = fmt.Errorf("job %s of tool %s failed: %w", job.Name, j.ToolName, err)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+
+	content, err := j.readResult(ctx, job)
+	if err != nil {
+		err = fmt.Errorf("failed to read result of job %s of tool %s: %w", job.Name, j.ToolName, err)
+		return ToolResult{ID: call.ID, Name: call.Function.Name, Error: err.Error()}, err
+	}
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: content}, nil
+}
+
+// startJob creates the job of a call and the ConfigMap holding its arguments, or returns the job
+// when the call is resumed
+func (j *JobToolExecutor) startJob(ctx context.Context, query *arkv1alpha1.Query, call ToolCall) (*batchv1.Job, error) {
+	timeout, err := ToolJobTimeout(j.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	job := j.buildJob(query, call, timeout)
+	err = j.K8sClient.Create(ctx, job)
+	if errors.IsAlreadyExists(err) {
+		if err := j.K8sClient.Get(ctx, client.ObjectKeyFromObject(job), job); err != nil {
+			return nil, err
+		}
+		if !j.isQueryJob(job, query) {
+			return nil, fmt.Errorf("job %s already exists and was not created for query %s", job.Name, query.Name)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	// A restart between creating the job and its ConfigMap leaves the job waiting for the ConfigMap,
+	// so resumed calls create it as well
+	if err := j.ensureInput(ctx, job, call); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// isQueryJob reports whether an existing job was created for the query and tool. Jobs in the query
+// namespace must also be owned by the query.
+func (j *JobToolExecutor) isQueryJob(job *batchv1.Job, query *arkv1alpha1.Query) bool {
+	if job.Labels[annotations.QueryUID] != string(query.UID) || job.Labels[annotations.Tool] != j.ToolName {
+		return false
+	}
+	if job.Namespace != query.Namespace {
+		return true
+	}
+	owner := metav1.GetControllerOf(job)
+	return owner != nil && owner.UID == query.UID
+}
+
+// ensureInput creates the ConfigMap holding the arguments of the call, owned by the job to be
+// removed with it. An existing ConfigMap is only used when it is owned by the job.
+func (j *JobToolExecutor) ensureInput(ctx context.Context, job *batchv1.Job, call ToolCall) error {
+	input := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      job.Name,
+			Namespace: job.Namespace,
+			Labels:    job.Labels,
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: batchv1.SchemeGroupVersion.String(),
+				Kind:       "Job",
+				Name:       job.Name,
+				UID:        job.UID,
+				Controller: &[]bool{true}[0],
+			}},
+		},
+		Data: map[string]string{ToolJobArgumentsKey: call.Function.Arguments},
+	}
+	err := j.K8sClient.Create(ctx, input)
+	if !errors.IsAlreadyExists(err) {
+		return err
+	}
+
+	if err := j.K8sClient.Get(ctx, client.ObjectKeyFromObject(input), input); err != nil {
+		return err
+	}
+	if owner := metav1.GetControllerOf(input); owner == nil || owner.UID != job.UID {
+		return fmt.Errorf("config map %s already exists and is not owned by job %s", input.Name, job.Name)
+	}
+	return nil
+}
+
+func (j *JobToolExecutor) buildJob(query *arkv1alpha1.Query, call ToolCall, timeout time.Duration) *batchv1.Job {
+	name := ToolJobName(query, j.ToolName, call.ID)
+	labels := map[string]string{
+		annotations.Query:    query.Name,
+		annotations.QueryUID: string(query.UID),
+		annotations.Tool:     j.ToolName,
+	}
+
+	container := corev1.Container{
+		Name:    toolJobContainer,
+		Image:   j.Spec.Image,
+		Command: j.Spec.Command,
+		Args:    j.Spec.Args,
+		Env: []corev1.EnvVar{
+			{Name: "ARK_TOOL_NAME", Value: j.ToolName},
+			{Name: "ARK_TOOL_CALL_ID", Value: call.ID},
+			{Name: "ARK_TOOL_ARGUMENTS_FILE", Value: toolJobInputDir + "/" + ToolJobArgumentsKey},
+			{Name: "ARK_TOOL_RESULT_CONFIGMAP", Value: name},
+			{Name: "ARK_NAMESPACE", Value: j.Namespace},
+		},
+		VolumeMounts:             []corev1.VolumeMount{{Name: "input", MountPath: toolJobInputDir, ReadOnly: true}},
+		TerminationMessagePolicy: corev1.TerminationMessageFallbackToLogsOnError,
+	}
+	if j.Spec.Resources != nil {
+		container.Resources = *j.Spec.Resources
+	}
+
+	backoffLimit := int32(0)
+	if j.Spec.BackoffLimit != nil {
+		backoffLimit = *j.Spec.BackoffLimit
+	}
+	deadline := int64(timeout.Seconds())
+	ttl := int32(toolJobTTL.Seconds())
+
+	job := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: j.Namespace, Labels: labels},
+		Spec: batchv1.JobSpec{
+			BackoffLimit:            &backoffLimit,
+			ActiveDeadlineSeconds:   &deadline,
+			TTLSecondsAfterFinished: &ttl,
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec: corev1.PodSpec{
+					RestartPolicy:      corev1.RestartPolicyNever,
+					ServiceAccountName: j.Spec.ServiceAccountName,
+					Containers:         []corev1.Container{container},
+					Volumes: []corev1.Volume{{
+						Name: "input",
+						VolumeSource: corev1.VolumeSource{
+							ConfigMap: &corev1.ConfigMapVolumeSource{LocalObjectReference: corev1.LocalObjectReference{Name: name}},
+						},
+					}},
+				},
+			},
+		},
+	}
+	if j.configurePod != nil {
+		j.configurePod(&job.Spec.Template)
+	}
+	// Owner references cannot cross namespaces, jobs of other namespaces are removed by their TTL
+	if query.Namespace == j.Namespace {
+		job.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: arkv1alpha1.GroupVersion.String(),
+			Kind:       "Query",
+			Name:       query.Name,
+			UID:        query.UID,
+			Controller: &[]bool{true}[0],
+		}}
+	}
+	return job
+}
+
+// waitForJob polls the job until it completes or fails, or ctx is done
+func (j *JobToolExecutor) waitForJob(ctx context.Context, job *batchv1.Job) error {
+	interval := j.PollInterval
+	if interval <= 0 {
+		interval = defaultToolJobPollInterval
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, condition := range job.Status.Conditions {
+			if condition.Status != corev1.ConditionTrue {
+				continue
+			}
+			switch condition.Type {
+			case batchv1.JobComplete:
+				return nil
+			case batchv1.JobFailed:
+				return fmt.Errorf("%s: %s", condition.Reason, condition.Message)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if err := j.K8sClient.Get(ctx, client.ObjectKeyFromObject(job), job); err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+	}
+}
+
+// readResult returns the result the job wrote to its ConfigMap, or else the termination message of
+// the container of its succeeded pod
+func (j *JobToolExecutor) readResult(ctx context.Context, job *batchv1.Job) (string, error) {
+	var output corev1.ConfigMap
+	if err := j.K8sClient.Get(ctx, client.ObjectKeyFromObject(job), &output); err != nil && !errors.IsNotFound(err) {
+		return "", err
+	}
+	if result, ok := output.Data[ToolJobResultKey]; ok {
+		return result, nil
+	}
+
+	var pods corev1.PodList
+	if err := j.K8sClient.List(ctx, &pods, client.InNamespace(job.Namespace), client.MatchingLabels{batchv1.JobNameLabel: job.Name}); err != nil {
+		return "", err
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodSucceeded {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			if status.Name == toolJobContainer && status.State.Terminated != nil {
+				return status.State.Terminated.Message, nil
+			}
+		}
+	}
+	logf.FromContext(ctx).Info("tool job completed without a result", "job", job.Name, "tool", j.ToolName)
+	return "", nil
+}
+
+// ToolJobTimeout returns the maximum time of a call of a job tool
+func ToolJobTimeout(spec arkv1alpha1.JobToolSpec) (time.Duration, error) {
+	if spec.Timeout == "" {
+		return defaultToolJobTimeout, nil
+	}
+	timeout, err := parseToolTimeout(spec.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid job timeout: %w", err)
+	}
+	return timeout, nil
+}
+
+// ToolJobName returns the name of the Job of a tool call of a query. The name hashes the query UID,
+// so the jobs of other queries and tools cannot be named in advance. Names are kept within the 63
+// characters of label values, since the job name labels its pods
+func ToolJobName(query *arkv1alpha1.Query, toolName, toolCallID string) string {
+	sum := sha256.Sum256([]byte(query.Namespace + "/" + string(query.UID) + "/" + toolName + "/" + toolCallID))
+	name := query.Name
+	if len(name) > 46 {
+		// A cut name may end with '.' or '-', which names cannot end with before the hash
+		name = strings.TrimRightFunc(name[:46], func(r rune) bool {
+			return (r < 'a' || r > 'z') && (r < '0' || r > '9')
+		})
+	}
+	if name == "" {
+		name = "tool-job"
+	}
+	return name + "-" + hex.EncodeToString(sum[:8])
+}
+
+func emitToolJobEvent(ctx context.Context, recorder EventEmitter, eventType, reason, toolName string, job *batchv1.Job) {
+	if recorder == nil {
+		return
+	}
+	recorder.EmitEvent(ctx, eventType, reason, BaseEvent{
+		Name: toolName,
+		Metadata: map[string]string{
+			"job":       job.Name,
+			"namespace": job.Namespace,
+		},
+	})
+}
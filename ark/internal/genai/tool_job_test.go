@@ -0,0 +1,253 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openai/openai-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+// finishToolJobs completes or fails jobs the first time they are read back, and writes result to
+// the ConfigMap of completed jobs
+func finishToolJobs(condition batchv1.JobConditionType, result string) interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if err := c.Get(ctx, key, obj, opts...); err != nil {
+				return err
+			}
+			job, ok := obj.(*batchv1.Job)
+			if !ok || len(job.Status.Conditions) > 0 {
+				return nil
+			}
+			// Jobs only run once their input exists
+			var input corev1.ConfigMap
+			if err := c.Get(ctx, key, &input); err != nil {
+				return client.IgnoreNotFound(err)
+			}
+			job.Status.Conditions = []batchv1.JobCondition{{Type: condition, Status: corev1.ConditionTrue, Reason: "DeadlineExceeded", Message: "Job was active longer than specified deadline"}}
+			if condition == batchv1.JobComplete && result != "" {
+				input.Data[ToolJobResultKey] = result
+				return c.Update(ctx, &input)
+			}
+			return nil
+		},
+	}
+}
+
+func newToolJobTest(t *testing.T, funcs interceptor.Funcs, objects ...client.Object) (client.Client, *JobToolExecutor, context.Context) {
+	s := runtime.NewScheme()
+	require.NoError(t, clientgoscheme.AddToScheme(s))
+	require.NoError(t, arkv1alpha1.AddToScheme(s))
+	k8sClient := fake.NewClientBuilder().WithScheme(s).WithObjects(objects...).WithInterceptorFuncs(funcs).Build()
+
+	executor := &JobToolExecutor{
+		K8sClient:    k8sClient,
+		ToolName:     "render-video",
+		Namespace:    "default",
+		Spec:         arkv1alpha1.JobToolSpec{Image: "render:1.0", Args: []string{"--fast"}, Timeout: "15m"},
+		PollInterval: time.Millisecond,
+	}
+	return k8sClient, executor, context.WithValue(context.Background(), QueryContextKey, toolJobQuery())
+}
+
+func toolJobQuery() *arkv1alpha1.Query {
+	return &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "trailer", Namespace: "default", UID: "query-uid"},
+		Spec:       arkv1alpha1.QuerySpec{ServiceAccount: "renderer"},
+	}
+}
+
+func toolJobCall() ToolCall {
+	return ToolCall{
+		ID:       "call_1",
+		Function: openai.ChatCompletionMessageToolCallFunction{Name: "render-video", Arguments: `{"scene":3}`},
+	}
+}
+
+func TestJobToolExecutorRunsCallAsJob(t *testing.T) {
+	k8sClient, executor, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, `{"url":"s3://renders/3.mp4"}`))
+
+	result, err := executor.Execute(ctx, toolJobCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, `{"url":"s3://renders/3.mp4"}`, result.Content)
+
+	name := ToolJobName(toolJobQuery(), "render-video", "call_1")
+	var job batchv1.Job
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &job))
+	assert.Equal(t, int64(900), *job.Spec.ActiveDeadlineSeconds)
+	assert.Equal(t, int32(0), *job.Spec.BackoffLimit)
+	assert.Equal(t, "trailer", job.OwnerReferences[0].Name)
+	container := job.Spec.Template.Spec.Containers[0]
+	assert.Equal(t, "render:1.0", container.Image)
+	assert.Equal(t, []string{"--fast"}, container.Args)
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "ARK_TOOL_ARGUMENTS_FILE", Value: "/ark/input/arguments.json"})
+	assert.Contains(t, container.Env, corev1.EnvVar{Name: "ARK_TOOL_RESULT_CONFIGMAP", Value: name})
+
+	var input corev1.ConfigMap
+	require.NoError(t, k8sClient.Get(context.Background(), types.NamespacedName{Name: name, Namespace: "default"}, &input))
+	assert.Equal(t, `{"scene":3}`, input.Data[ToolJobArgumentsKey])
+	assert.Equal(t, name, input.OwnerReferences[0].Name)
+}
+
+func TestJobToolExecutorReadsTerminationMessage(t *testing.T) {
+	name := ToolJobName(toolJobQuery(), "render-video", "call_1")
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name + "-x7k2p", Namespace: "default", Labels: map[string]string{batchv1.JobNameLabel: name}},
+		Status: corev1.PodStatus{
+			Phase: corev1.PodSucceeded,
+			ContainerStatuses: []corev1.ContainerStatus{{
+				Name:  "tool",
+				State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Message: "rendered 3 scenes"}},
+			}},
+		},
+	}
+	_, executor, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, ""), pod)
+
+	result, err := executor.Execute(ctx, toolJobCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "rendered 3 scenes", result.Content)
+}
+
+func TestJobToolExecutorFailedJob(t *testing.T) {
+	_, executor, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobFailed, ""))
+
+	result, err := executor.Execute(ctx, toolJobCall(), nil)
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "of tool render-video failed: DeadlineExceeded: Job was active longer than specified deadline")
+}
+
+func TestJobToolExecutorResumesStartedJob(t *testing.T) {
+	query := toolJobQuery()
+	started := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ToolJobName(query, "render-video", "call_1"),
+			Namespace:       "default",
+			UID:             "job-uid",
+			Labels:          map[string]string{annotations.QueryUID: "query-uid", annotations.Tool: "render-video"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Query", Name: "trailer", UID: "query-uid", Controller: &[]bool{true}[0]}},
+		},
+		Status: batchv1.JobStatus{Conditions: []batchv1.JobCondition{
+			{Type: batchv1.JobComplete, Status: corev1.ConditionTrue},
+		}},
+	}
+	input := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            started.Name,
+			Namespace:       "default",
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Job", Name: started.Name, UID: "job-uid", Controller: &[]bool{true}[0]}},
+		},
+		Data: map[string]string{ToolJobArgumentsKey: `{"scene":3}`, ToolJobResultKey: "done before restart"},
+	}
+	_, executor, ctx := newToolJobTest(t, interceptor.Funcs{}, started, input)
+
+	result, err := executor.Execute(ctx, toolJobCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "done before restart", result.Content)
+}
+
+func TestJobToolExecutorCreatesInputOfResumedJob(t *testing.T) {
+	started := &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            ToolJobName(toolJobQuery(), "render-video", "call_1"),
+			Namespace:       "default",
+			UID:             "job-uid",
+			Labels:          map[string]string{annotations.QueryUID: "query-uid", annotations.Tool: "render-video"},
+			OwnerReferences: []metav1.OwnerReference{{Kind: "Query", Name: "trailer", UID: "query-uid", Controller: &[]bool{true}[0]}},
+		},
+	}
+	k8sClient, executor, ctx := newToolJobTest(t, finishToolJobs(batchv1.JobComplete, "rendered"), started)
+
+	result, err := executor.Execute(ctx, toolJobCall(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, "rendered", result.Content)
+
+	var input corev1.ConfigMap
+	require.NoError(t, k8sClient.Get(context.Background(), client.ObjectKeyFromObject(started), &input))
+	assert.Equal(t, `{"scene":3}`, input.Data[ToolJobArgumentsKey])
+}
+
+func TestJobToolExecutorRejectsForeignObjects(t *testing.T) {
+	name := ToolJobName(toolJobQuery(), "render-video", "call_1")
+	foreignJob := &batchv1.Job{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}}
+	_, executor, ctx := newToolJobTest(t, interceptor.Funcs{}, foreignJob)
+
+	_, err := executor.Execute(ctx, toolJobCall(), nil)
+	assert.ErrorContains(t, err, "was not created for query trailer")
+
+	foreignInput := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Data:       map[string]string{ToolJobResultKey: "injected"},
+	}
+	_, executor, ctx = newToolJobTest(t, interceptor.Funcs{}, foreignInput)
+
+	_, err = executor.Execute(ctx, toolJobCall(), nil)
+	assert.ErrorContains(t, err, "is not owned by job")
+}
+
+func TestJobToolExecutorRequiresQueryIdentity(t *testing.T) {
+	_, executor, _ := newToolJobTest(t, interceptor.Funcs{})
+	query := toolJobQuery()
+	query.Spec.ServiceAccount = ""
+
+	_, err := executor.Execute(context.WithValue(context.Background(), QueryContextKey, query), toolJobCall(), nil)
+	assert.ErrorContains(t, err, "requires the query to set serviceAccount or impersonate")
+}
+
+func TestToolJobName(t *testing.T) {
+	query := toolJobQuery()
+	query.Name = "a-query-with-a-name-far-longer-than-any-labels-value-may-be"
+	name := ToolJobName(query, "render-video", "call_1")
+	assert.Len(t, name, 63)
+	assert.Equal(t, name, ToolJobName(query, "render-video", "call_1"))
+	assert.NotEqual(t, name, ToolJobName(query, "render-video", "call_2"))
+	assert.NotEqual(t, name, ToolJobName(query, "upload-video", "call_1"))
+
+	rerun := query.DeepCopy()
+	rerun.UID = "other-query-uid"
+	assert.NotEqual(t, name, ToolJobName(rerun, "render-video", "call_1"))
+}
+
+func TestToolJobNameCutPoint(t *testing.T) {
+	tests := []struct {
+		name      string
+		queryName string
+		prefix    string
+	}{
+		{name: "cut at a dash", queryName: "a-query-with-a-name-far-longer-than-the-limit-of-a-label", prefix: "a-query-with-a-name-far-longer-than-the-limit-"},
+		{name: "cut at a dot", queryName: "a.query.with.a.name.far.longer.than.the.limit.of.a.label", prefix: "a.query.with.a.name.far.longer.than.the.limit."},
+		{name: "cut after several separators", queryName: "a-query-with-a-name-far-longer-than-the-lim-.-of-a-label", prefix: "a-query-with-a-name-far-longer-than-the-lim-.-"},
+		{name: "only separators before the cut", queryName: strings.Repeat("-.", 23) + "a", prefix: strings.Repeat("-.", 23)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.prefix, tt.queryName[:46])
+			query := toolJobQuery()
+			query.Name = tt.queryName
+			name := ToolJobName(query, "render-video", "call_1")
+			assert.LessOrEqual(t, len(name), 63)
+			assert.Empty(t, validation.IsDNS1123Subdomain(name))
+			assert.Empty(t, validation.IsValidLabelValue(name))
+			assert.NotContains(t, name, "-.")
+			assert.NotContains(t, name, ".-")
+		})
+	}
+}
@@ -0,0 +1,124 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	toolOutputSummaryPrompt = "Summarize the following tool output. Preserve identifiers, numbers and any facts needed to answer the user's request. Respond with the summary only."
+	// Full tool outputs are kept in a separate session so they are not replayed as conversation history
+	toolOutputAuditSessionSuffix = "-tool-outputs"
+)
+
+// ToolOutputProcessor reduces tool results according to a tool output policy
+type ToolOutputProcessor struct {
+	Policy       *arkv1alpha1.ToolOutputPolicy
+	SummaryModel *Model
+	K8sClient    client.Client
+	Namespace    string
+}
+
+// Process returns the content to inject into the conversation, or the original content if it is within limits
+func (p *ToolOutputProcessor) Process(ctx context.Context, content string) string {
+	if p.Policy == nil || p.Policy.MaxBytes <= 0 || int64(len(content)) <= p.Policy.MaxBytes {
+		return content
+	}
+
+	if p.SummaryModel != nil {
+		summary, err := p.summarize(ctx, content)
+		if err == nil && int64(len(summary)) <= p.Policy.MaxBytes {
+			return summary
+		}
+		if err != nil {
+			logf.FromContext(ctx).Error(err, "failed to summarize tool output, falling back to truncation")
+		}
+	}
+
+	return truncateToolOutput(content, p.Policy.MaxBytes, p.Policy.Truncate)
+}
+
+func (p *ToolOutputProcessor) summarize(ctx context.Context, content string) (string, error) {
+	messages := []Message{
+		NewSystemMessage(toolOutputSummaryPrompt),
+		NewUserMessage(content),
+	}
+
+	response, err := p.SummaryModel.ChatCompletion(ctx, messages, nil, 1)
+	if err != nil {
+		return "", err
+	}
+	if response == nil || len(response.Choices) == 0 {
+		return "", fmt.Errorf("summary model returned no choices")
+	}
+
+	return response.Choices[0].Message.Content, nil
+}
+
+// StoreFullOutput stores the original tool output in the query's memory for audit
+func (p *ToolOutputProcessor) StoreFullOutput(ctx context.Context, call ToolCall, content string, recorder EventEmitter) error {
+	query, ok := ctx.Value(QueryContextKey).(*arkv1alpha1.Query)
+	if !ok {
+		return fmt.Errorf("missing query context for tool %s", call.Function.Name)
+	}
+
+	sessionId := query.Spec.SessionId
+	if sessionId == "" {
+		sessionId = string(query.UID)
+	}
+
+	memory, err := NewMemoryForQuery(ctx, p.K8sClient, query.Spec.Memory, p.Namespace, recorder, sessionId+toolOutputAuditSessionSuffix, query.Name)
+	if err != nil {
+		return fmt.Errorf("failed to create memory for tool output audit: %w", err)
+	}
+	defer func() { _ = memory.Close() }()
+	memory = NewRedactingMemory(memory, PIIRedactorFromContext(ctx))
+
+	return memory.AddMessages(ctx, query.Name, []Message{ToolMessage(content, call.ID)})
+}
+
+// truncateToolOutput keeps the head or tail of content within maxBytes, respecting UTF-8 boundaries.
+// The truncation marker counts towards maxBytes, and is left out when maxBytes cannot hold it.
+func truncateToolOutput(content string, maxBytes int64, strategy string) string {
+	// The marker and its line break are at most as long as for omitting all of content
+	keep := maxBytes - int64(len(truncationMarker(int64(len(content))))+1)
+	if keep < 0 {
+		return truncateContent(content, maxBytes, strategy)
+	}
+
+	kept := truncateContent(content, keep, strategy)
+	marker := truncationMarker(int64(len(content) - len(kept)))
+	if strategy == ToolOutputTruncateTail {
+		return marker + "\n" + kept
+	}
+	return kept + "\n" + marker
+}
+
+// truncateContent returns the head or tail of content of at most maxBytes, respecting UTF-8 boundaries
+func truncateContent(content string, maxBytes int64, strategy string) string {
+	if strategy == ToolOutputTruncateTail {
+		start := int64(len(content)) - maxBytes
+		for start < int64(len(content)) && !utf8.RuneStart(content[start]) {
+			start++
+		}
+		return content[start:]
+	}
+
+	end := maxBytes
+	for end > 0 && !utf8.RuneStart(content[end]) {
+		end--
+	}
+	return content[:end]
+}
+
+func truncationMarker(omitted int64) string {
+	return fmt.Sprintf("[truncated %d bytes]", omitted)
+}
@@ -0,0 +1,50 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/google/jsonschema-go/jsonschema"
+)
+
+// ToolOutputValidator checks tool responses against the output schema of the tool
+type ToolOutputValidator struct {
+	schema *jsonschema.Resolved
+}
+
+// NewToolOutputValidator parses and resolves a tool output schema
+func NewToolOutputValidator(raw []byte) (*ToolOutputValidator, error) {
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(raw, &schema); err != nil {
+		return nil, fmt.Errorf("failed to parse output schema: %w", err)
+	}
+
+	resolved, err := schema.Resolve(nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve output schema: %w", err)
+	}
+	return &ToolOutputValidator{schema: resolved}, nil
+}
+
+// Validate returns an error if content is not JSON matching the output schema
+func (v *ToolOutputValidator) Validate(content string) error {
+	var instance any
+	if err := json.Unmarshal([]byte(content), &instance); err != nil {
+		return fmt.Errorf("response is not valid JSON: %w", err)
+	}
+	return v.schema.Validate(instance)
+}
+
+// invalidToolOutputResult replaces a tool response that failed output validation with an error the
+// model can act on, so it does not reason over a malformed response
+func invalidToolOutputResult(call ToolCall, err error) ToolResult {
+	message := fmt.Sprintf("Error: tool %s returned a response that does not match its output schema: %v", call.Function.Name, err)
+	return ToolResult{
+		ID:      call.ID,
+		Name:    call.Function.Name,
+		Content: message,
+		Error:   message,
+	}
+}
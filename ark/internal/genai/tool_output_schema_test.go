@@ -0,0 +1,56 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+const forecastOutputSchema = `{
+	"type": "object",
+	"properties": {"temperature": {"type": "number"}},
+	"required": ["temperature"]
+}`
+
+func TestToolOutputValidatorValidate(t *testing.T) {
+	validator, err := NewToolOutputValidator([]byte(forecastOutputSchema))
+	require.NoError(t, err)
+
+	assert.NoError(t, validator.Validate(`{"temperature": 21.5}`))
+	assert.Error(t, validator.Validate(`{"temperature": "warm"}`))
+	assert.Error(t, validator.Validate(`{}`))
+	assert.ErrorContains(t, validator.Validate(`<html>Bad Gateway</html>`), "not valid JSON")
+
+	_, err = NewToolOutputValidator([]byte(`{"type": 42}`))
+	assert.Error(t, err)
+}
+
+func TestToolRegistryRejectsInvalidOutput(t *testing.T) {
+	validator, err := NewToolOutputValidator([]byte(forecastOutputSchema))
+	require.NoError(t, err)
+
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = "forecast"
+
+	registry := NewToolRegistry(nil, noop.NewToolRecorder())
+	registry.RegisterTool(ToolDefinition{Name: "forecast"}, &staticToolExecutor{content: `{"temperature": 21.5}`})
+	registry.SetOutputValidator("forecast", validator)
+
+	result, err := registry.ExecuteTool(context.Background(), call, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, `{"temperature": 21.5}`, result.Content)
+	assert.Empty(t, result.Error)
+
+	registry.RegisterTool(ToolDefinition{Name: "forecast"}, &staticToolExecutor{content: `{"error": "upstream timeout"}`})
+	result, err = registry.ExecuteTool(context.Background(), call, nil)
+	assert.NoError(t, err)
+	assert.Contains(t, result.Content, "does not match its output schema")
+	assert.Equal(t, result.Content, result.Error)
+	assert.Equal(t, "call-1", result.ID)
+}
@@ -0,0 +1,55 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/telemetry/noop"
+)
+
+func TestTruncateToolOutput(t *testing.T) {
+	content := strings.Repeat("0123456789", 5)
+
+	// The marker counts towards the limit
+	head := truncateToolOutput(content, 30, ToolOutputTruncateHead)
+	assert.Equal(t, "012345678\n[truncated 41 bytes]", head)
+	assert.Len(t, head, 30)
+
+	tail := truncateToolOutput(content, 30, ToolOutputTruncateTail)
+	assert.Equal(t, "[truncated 41 bytes]\n123456789", tail)
+
+	// Limits too small for the marker keep content only
+	assert.Equal(t, "0123", truncateToolOutput(content, 4, ToolOutputTruncateHead))
+
+	// Multi-byte runes are never split
+	assert.Equal(t, "é", truncateToolOutput("ééé", 3, ToolOutputTruncateHead))
+}
+
+func TestToolOutputProcessorProcess(t *testing.T) {
+	processor := &ToolOutputProcessor{
+		Policy: &arkv1alpha1.ToolOutputPolicy{MaxBytes: 25},
+	}
+
+	assert.Equal(t, "short", processor.Process(context.Background(), "short"))
+	assert.Equal(t, "yyyy\n[truncated 36 bytes]", processor.Process(context.Background(), strings.Repeat("y", 40)))
+}
+
+func TestToolRegistryAppliesOutputProcessor(t *testing.T) {
+	registry := NewToolRegistry(nil, noop.NewToolRecorder())
+	registry.RegisterTool(ToolDefinition{Name: "fetch"}, &staticToolExecutor{content: strings.Repeat("x", 100)})
+	registry.SetOutputProcessor("fetch", &ToolOutputProcessor{
+		Policy: &arkv1alpha1.ToolOutputPolicy{MaxBytes: 30, Truncate: ToolOutputTruncateTail},
+	})
+
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = "fetch"
+	result, err := registry.ExecuteTool(context.Background(), call, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, "[truncated 92 bytes]\n"+strings.Repeat("x", 8), result.Content)
+}
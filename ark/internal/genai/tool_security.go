@@ -0,0 +1,288 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+// NamespaceToolPolicy is the tool security policy declared on a namespace via annotations
+type NamespaceToolPolicy struct {
+	AllowedHosts     []string
+	AllowedMethods   []string
+	MaxResponseBytes int64
+}
+
+// NamespaceToolPolicyFromAnnotations builds the namespace tool policy. Returns nil if no policy is declared.
+func NamespaceToolPolicyFromAnnotations(nsAnnotations map[string]string) (*NamespaceToolPolicy, error) {
+	hosts := splitAnnotationList(nsAnnotations[annotations.ToolAllowedHosts])
+	methods := splitAnnotationList(nsAnnotations[annotations.ToolAllowedMethods])
+	maxBytesStr := strings.TrimSpace(nsAnnotations[annotations.ToolMaxResponseBytes])
+
+	if len(hosts) == 0 && len(methods) == 0 && maxBytesStr == "" {
+		return nil, nil
+	}
+
+	policy := &NamespaceToolPolicy{AllowedHosts: hosts}
+	for _, method := range methods {
+		policy.AllowedMethods = append(policy.AllowedMethods, strings.ToUpper(method))
+	}
+
+	if maxBytesStr != "" {
+		maxBytes, err := strconv.ParseInt(maxBytesStr, 10, 64)
+		if err != nil || maxBytes < 0 {
+			return nil, fmt.Errorf("invalid %s annotation value '%s'", annotations.ToolMaxResponseBytes, maxBytesStr)
+		}
+		policy.MaxResponseBytes = maxBytes
+	}
+
+	return policy, nil
+}
+
+type toolPolicyClientContextKey struct{}
+
+// WithToolPolicyClient returns a context in which namespace tool policies are read with k8sClient.
+// The identity a query runs as may not be allowed to read namespaces, so the controller passes its own client.
+func WithToolPolicyClient(ctx context.Context, k8sClient client.Client) context.Context {
+	return context.WithValue(ctx, toolPolicyClientContextKey{}, k8sClient)
+}
+
+// LoadNamespaceToolPolicy reads the tool policy of a namespace. Returns nil if the namespace declares none.
+func LoadNamespaceToolPolicy(ctx context.Context, k8sClient client.Client, namespace string) (*NamespaceToolPolicy, error) {
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, types.NamespacedName{Name: namespace}, ns); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get namespace %s: %w", namespace, err)
+	}
+	return NamespaceToolPolicyFromAnnotations(ns.Annotations)
+}
+
+// toolPolicyClient returns the client set with WithToolPolicyClient. Without it, namespace policies
+// would be read as the query identity, which sees no policy when it cannot read namespaces.
+func toolPolicyClient(ctx context.Context) (client.Client, error) {
+	policyClient, ok := ctx.Value(toolPolicyClientContextKey{}).(client.Client)
+	if !ok || policyClient == nil {
+		return nil, errors.New("namespace policies cannot be checked: no policy client is set")
+	}
+	return policyClient, nil
+}
+
+// CheckNamespaceToolPolicy returns an error if the tool's security policy conflicts with the policy of
+// the namespace. The admission webhook checks agents when they are created, this check repeats it when
+// the tool runs, since the tool or the namespace policy may have changed since. The policy is read
+// with the client set by WithToolPolicyClient.
+func CheckNamespaceToolPolicy(ctx context.Context, tool *arkv1alpha1.Tool, namespace string) error {
+	policyClient, err := toolPolicyClient(ctx)
+	if err != nil {
+		return err
+	}
+	policy, err := LoadNamespaceToolPolicy(ctx, policyClient, namespace)
+	if err != nil {
+		return err
+	}
+	return CheckToolSecurityConflict(tool.Name, tool.Spec.Security, policy)
+}
+
+// CheckToolSecurityConflict returns an error if the tool's security policy is less restrictive than the namespace policy
+func CheckToolSecurityConflict(toolName string, security *arkv1alpha1.ToolSecurity, policy *NamespaceToolPolicy) error {
+	if policy == nil {
+		return nil
+	}
+
+	if security == nil {
+		return fmt.Errorf("tool '%s' has no security policy but namespace requires one", toolName)
+	}
+
+	if len(policy.AllowedHosts) > 0 {
+		if len(security.AllowedHosts) == 0 {
+			return fmt.Errorf("tool '%s' allows all hosts but namespace restricts hosts to %v", toolName, policy.AllowedHosts)
+		}
+		for _, host := range security.AllowedHosts {
+			if !IsHostAllowed(host, policy.AllowedHosts) {
+				return fmt.Errorf("tool '%s' allows host '%s' which is not permitted by namespace policy", toolName, host)
+			}
+		}
+	}
+
+	if len(policy.AllowedMethods) > 0 {
+		if len(security.AllowedMethods) == 0 {
+			return fmt.Errorf("tool '%s' allows all HTTP methods but namespace restricts methods to %v", toolName, policy.AllowedMethods)
+		}
+		for _, method := range security.AllowedMethods {
+			if !slices.Contains(policy.AllowedMethods, strings.ToUpper(method)) {
+				return fmt.Errorf("tool '%s' allows HTTP method '%s' which is not permitted by namespace policy", toolName, method)
+			}
+		}
+	}
+
+	if policy.MaxResponseBytes > 0 {
+		if security.MaxResponseBytes == 0 || security.MaxResponseBytes > policy.MaxResponseBytes {
+			return fmt.Errorf("tool '%s' maxResponseBytes exceeds namespace limit of %d", toolName, policy.MaxResponseBytes)
+		}
+	}
+
+	return nil
+}
+
+// IsHostAllowed checks a host against an allowlist supporting "*.domain" wildcard entries
+func IsHostAllowed(host string, allowedHosts []string) bool {
+	if len(allowedHosts) == 0 {
+		return true
+	}
+
+	host = strings.ToLower(host)
+	for _, allowed := range allowedHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if allowed == "*" || allowed == host {
+			return true
+		}
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if host == suffix || strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func checkAllowedURL(security *arkv1alpha1.ToolSecurity, rawURL string) error {
+	if security == nil || len(security.AllowedHosts) == 0 {
+		return nil
+	}
+
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if !IsHostAllowed(parsedURL.Hostname(), security.AllowedHosts) {
+		return fmt.Errorf("host '%s' is not in the tool's allowed hosts", parsedURL.Hostname())
+	}
+	return nil
+}
+
+func checkAllowedMethod(security *arkv1alpha1.ToolSecurity, method string) error {
+	if security == nil || len(security.AllowedMethods) == 0 {
+		return nil
+	}
+
+	for _, allowed := range security.AllowedMethods {
+		if strings.EqualFold(allowed, method) {
+			return nil
+		}
+	}
+	return fmt.Errorf("HTTP method '%s' is not in the tool's allowed methods", method)
+}
+
+// toolRedirectPolicy checks every redirect of an HTTP tool against its security policy, so an
+// allowed host cannot redirect the request to a host or method that is not allowed
+func toolRedirectPolicy(security *arkv1alpha1.ToolSecurity) func(*http.Request, []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= 10 {
+			return fmt.Errorf("stopped after 10 redirects")
+		}
+		if err := checkAllowedMethod(security, req.Method); err != nil {
+			return err
+		}
+		return checkAllowedURL(security, req.URL.String())
+	}
+}
+
+// ValidateToolSecurity checks the static parts of a tool definition against its security policy
+func ValidateToolSecurity(tool *arkv1alpha1.Tool) error {
+	security := tool.Spec.Security
+	if security == nil {
+		return nil
+	}
+
+	if security.Timeout != "" {
+		if _, err := parseToolTimeout(security.Timeout); err != nil {
+			return fmt.Errorf("invalid security timeout for tool %s: %w", tool.Name, err)
+		}
+	}
+
+	if tool.Spec.Type == ToolTypeHTTP && tool.Spec.HTTP != nil {
+		method := tool.Spec.HTTP.Method
+		if method == "" {
+			method = "GET"
+		}
+		if err := checkAllowedMethod(security, method); err != nil {
+			return fmt.Errorf("tool %s violates security policy: %w", tool.Name, err)
+		}
+		// Templated hosts can only be checked once arguments are substituted
+		if !strings.Contains(tool.Spec.HTTP.URL, "{") {
+			if err := checkAllowedURL(security, tool.Spec.HTTP.URL); err != nil {
+				return fmt.Errorf("tool %s violates security policy: %w", tool.Name, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+func parseToolTimeout(timeout string) (time.Duration, error) {
+	if _, err := strconv.Atoi(timeout); err == nil {
+		timeout += "s"
+	}
+	return time.ParseDuration(timeout)
+}
+
+// SecureToolExecutor enforces the execution timeout and response size limits of a tool security policy
+type SecureToolExecutor struct {
+	BaseExecutor ToolExecutor
+	Security     *arkv1alpha1.ToolSecurity
+}
+
+func (s *SecureToolExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	if s.Security.Timeout != "" {
+		timeout, err := parseToolTimeout(s.Security.Timeout)
+		if err == nil {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+	}
+
+	result, err := s.BaseExecutor.Execute(ctx, call, recorder)
+	if err != nil {
+		return result, err
+	}
+
+	if s.Security.MaxResponseBytes > 0 && int64(len(result.Content)) > s.Security.MaxResponseBytes {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: fmt.Sprintf("tool response size %d exceeds limit of %d bytes", len(result.Content), s.Security.MaxResponseBytes),
+		}, fmt.Errorf("tool response size %d exceeds limit of %d bytes", len(result.Content), s.Security.MaxResponseBytes)
+	}
+
+	return result, nil
+}
+
+func splitAnnotationList(value string) []string {
+	var items []string
+	for item := range strings.SplitSeq(value, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,246 @@
+/* Copyright 2025. McKinsey & Company */
+
+package genai
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+type staticToolExecutor struct {
+	content string
+}
+
+func (s *staticToolExecutor) Execute(ctx context.Context, call ToolCall, recorder EventEmitter) (ToolResult, error) {
+	return ToolResult{ID: call.ID, Name: call.Function.Name, Content: s.content}, nil
+}
+
+func TestIsHostAllowed(t *testing.T) {
+	tests := []struct {
+		name    string
+		host    string
+		allowed []string
+		want    bool
+	}{
+		{"empty allowlist", "api.example.com", nil, true},
+		{"exact match", "api.example.com", []string{"api.example.com"}, true},
+		{"wildcard subdomain", "api.example.com", []string{"*.example.com"}, true},
+		{"wildcard apex", "example.com", []string{"*.example.com"}, true},
+		{"case insensitive", "API.Example.com", []string{"api.example.com"}, true},
+		{"not listed", "evil.com", []string{"*.example.com"}, false},
+		{"suffix without dot", "badexample.com", []string{"*.example.com"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsHostAllowed(tt.host, tt.allowed))
+		})
+	}
+}
+
+func TestNamespaceToolPolicyFromAnnotations(t *testing.T) {
+	policy, err := NamespaceToolPolicyFromAnnotations(map[string]string{})
+	require.NoError(t, err)
+	assert.Nil(t, policy)
+
+	policy, err = NamespaceToolPolicyFromAnnotations(map[string]string{
+		annotations.ToolAllowedHosts:     "*.example.com, api.internal",
+		annotations.ToolAllowedMethods:   "get,post",
+		annotations.ToolMaxResponseBytes: "1024",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"*.example.com", "api.internal"}, policy.AllowedHosts)
+	assert.Equal(t, []string{"GET", "POST"}, policy.AllowedMethods)
+	assert.Equal(t, int64(1024), policy.MaxResponseBytes)
+
+	_, err = NamespaceToolPolicyFromAnnotations(map[string]string{
+		annotations.ToolMaxResponseBytes: "lots",
+	})
+	assert.Error(t, err)
+}
+
+func TestCheckToolSecurityConflict(t *testing.T) {
+	policy := &NamespaceToolPolicy{
+		AllowedHosts:     []string{"*.example.com"},
+		AllowedMethods:   []string{"GET"},
+		MaxResponseBytes: 1024,
+	}
+
+	tests := []struct {
+		name     string
+		security *arkv1alpha1.ToolSecurity
+		wantErr  bool
+	}{
+		{"no security", nil, true},
+		{
+			name: "within policy",
+			security: &arkv1alpha1.ToolSecurity{
+				AllowedHosts:     []string{"api.example.com"},
+				AllowedMethods:   []string{"GET"},
+				MaxResponseBytes: 512,
+			},
+		},
+		{
+			name: "host outside policy",
+			security: &arkv1alpha1.ToolSecurity{
+				AllowedHosts:     []string{"api.other.com"},
+				AllowedMethods:   []string{"GET"},
+				MaxResponseBytes: 512,
+			},
+			wantErr: true,
+		},
+		{
+			name: "method outside policy",
+			security: &arkv1alpha1.ToolSecurity{
+				AllowedHosts:     []string{"api.example.com"},
+				AllowedMethods:   []string{"GET", "DELETE"},
+				MaxResponseBytes: 512,
+			},
+			wantErr: true,
+		},
+		{
+			name: "unbounded response size",
+			security: &arkv1alpha1.ToolSecurity{
+				AllowedHosts:   []string{"api.example.com"},
+				AllowedMethods: []string{"GET"},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckToolSecurityConflict("test-tool", tt.security, policy)
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+
+	assert.NoError(t, CheckToolSecurityConflict("test-tool", nil, nil))
+}
+
+func TestValidateToolSecurity(t *testing.T) {
+	tool := &arkv1alpha1.Tool{
+		Spec: arkv1alpha1.ToolSpec{
+			Type: ToolTypeHTTP,
+			HTTP: &arkv1alpha1.HTTPSpec{URL: "https://api.other.com/data", Method: "GET"},
+			Security: &arkv1alpha1.ToolSecurity{
+				AllowedHosts: []string{"*.example.com"},
+			},
+		},
+	}
+	assert.Error(t, ValidateToolSecurity(tool))
+
+	tool.Spec.HTTP.URL = "https://{host}/data"
+	assert.NoError(t, ValidateToolSecurity(tool))
+
+	tool.Spec.HTTP.URL = "https://api.example.com/data"
+	tool.Spec.Security.AllowedMethods = []string{"POST"}
+	assert.Error(t, ValidateToolSecurity(tool))
+}
+
+func TestSecureToolExecutorMaxResponseBytes(t *testing.T) {
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = "fetch"
+
+	executor := &SecureToolExecutor{
+		BaseExecutor: &staticToolExecutor{content: strings.Repeat("a", 10)},
+		Security:     &arkv1alpha1.ToolSecurity{MaxResponseBytes: 5},
+	}
+	result, err := executor.Execute(context.Background(), call, nil)
+	assert.Error(t, err)
+	assert.Contains(t, result.Error, "exceeds limit")
+
+	executor.Security.MaxResponseBytes = 10
+	result, err = executor.Execute(context.Background(), call, nil)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Repeat("a", 10), result.Content)
+}
+
+func TestHTTPExecutorChecksRedirects(t *testing.T) {
+	// Both servers listen on 127.0.0.1, the other one is reached by the host name localhost
+	other := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("internal"))
+	}))
+	defer other.Close()
+	otherURL, err := url.Parse(other.URL)
+	require.NoError(t, err)
+	allowed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/away":
+			http.Redirect(w, r, "http://localhost:"+otherURL.Port()+"/", http.StatusFound)
+		case "/here":
+			http.Redirect(w, r, "/data", http.StatusFound)
+		default:
+			_, _ = w.Write([]byte("data"))
+		}
+	}))
+	defer allowed.Close()
+
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	newExecutor := func(path string) *HTTPExecutor {
+		tool := &arkv1alpha1.Tool{
+			ObjectMeta: metav1.ObjectMeta{Name: "fetch", Namespace: "default"},
+			Spec: arkv1alpha1.ToolSpec{
+				Type:     "http",
+				HTTP:     &arkv1alpha1.HTTPSpec{URL: allowed.URL + path},
+				Security: &arkv1alpha1.ToolSecurity{AllowedHosts: []string{"127.0.0.1"}},
+			},
+		}
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(tool).Build()
+		return &HTTPExecutor{K8sClient: k8sClient, ToolName: "fetch", ToolNamespace: "default"}
+	}
+	call := ToolCall{ID: "call-1"}
+	call.Function.Name = "fetch"
+
+	result, err := newExecutor("/here").Execute(context.Background(), call, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "data", result.Content)
+
+	result, err = newExecutor("/away").Execute(context.Background(), call, nil)
+	require.Error(t, err)
+	assert.Contains(t, result.Error, "host 'localhost' is not in the tool's allowed hosts")
+	assert.NotContains(t, result.Content, "internal")
+}
+
+func TestCheckNamespaceToolPolicy(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	namespace := &corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "restricted",
+		Annotations: map[string]string{annotations.ToolAllowedHosts: "*.example.com"},
+	}}
+	policyClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(namespace).Build()
+
+	tool := &arkv1alpha1.Tool{
+		ObjectMeta: metav1.ObjectMeta{Name: "fetch", Namespace: "restricted"},
+		Spec:       arkv1alpha1.ToolSpec{Security: &arkv1alpha1.ToolSecurity{AllowedHosts: []string{"evil.com"}}},
+	}
+	// Without the policy client the policy cannot be read, which fails the check
+	assert.ErrorContains(t, CheckNamespaceToolPolicy(context.Background(), tool, "restricted"), "no policy client is set")
+
+	ctx := WithToolPolicyClient(context.Background(), policyClient)
+	assert.ErrorContains(t, CheckNamespaceToolPolicy(ctx, tool, "restricted"), "not permitted by namespace policy")
+
+	tool.Spec.Security.AllowedHosts = []string{"api.example.com"}
+	assert.NoError(t, CheckNamespaceToolPolicy(ctx, tool, "restricted"))
+	assert.NoError(t, CheckNamespaceToolPolicy(ctx, tool, "other"))
+}
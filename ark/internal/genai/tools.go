@@ -94,10 +94,26 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall, recorder Even
 		method = "GET"
 	}
 
+	// Enforce tool security policy on the resolved request
+	if err := checkAllowedMethod(tool.Spec.Security, method); err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: err.Error(),
+		}, err
+	}
+	if err := checkAllowedURL(tool.Spec.Security, parsedURL.String()); err != nil {
+		return ToolResult{
+			ID:    call.ID,
+			Name:  call.Function.Name,
+			Error: err.Error(),
+		}, err
+	}
+
 	// Handle request body for POST/PUT/PATCH requests
 	var requestBody io.Reader
 	if httpSpec.Body != "" && (method == "POST" || method == "PUT" || method == "PATCH") {
-		bodyContent, err := ResolveBodyTemplate(ctx, h.K8sClient, tool.Namespace, httpSpec.Body, httpSpec.BodyParameters, arguments)
+		bodyContent, err := ResolveBodyTemplate(ctx, h.K8sClient, tool, httpSpec.Body, httpSpec.BodyParameters, arguments)
 		if err != nil {
 			log.Error(err, "failed to resolve body template", "template", httpSpec.Body)
 			return ToolResult{
@@ -134,7 +150,7 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall, recorder Even
 
 	// Set timeout
 	timeout := h.getTimeout(httpSpec.Timeout)
-	httpClient := &http.Client{Timeout: timeout}
+	httpClient := &http.Client{Timeout: timeout, CheckRedirect: toolRedirectPolicy(tool.Spec.Security)}
 
 	// Make the request
 	log.Info("making HTTP request", "method", method, "url", parsedURL.String())
@@ -159,8 +175,12 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall, recorder Even
 		}, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
 	}
 
-	// Read response body
-	body, err := io.ReadAll(resp.Body)
+	// Read response body, bounded by the tool security policy if set
+	var bodyReader io.Reader = resp.Body
+	if tool.Spec.Security != nil && tool.Spec.Security.MaxResponseBytes > 0 {
+		bodyReader = io.LimitReader(resp.Body, tool.Spec.Security.MaxResponseBytes+1)
+	}
+	body, err := io.ReadAll(bodyReader)
 	if err != nil {
 		return ToolResult{
 			ID:    call.ID,
@@ -179,20 +199,24 @@ func (h *HTTPExecutor) Execute(ctx context.Context, call ToolCall, recorder Even
 }
 
 type ToolRegistry struct {
-	tools        map[string]ToolDefinition
-	executors    map[string]ToolExecutor
-	mcpPool      *MCPClientPool         // One MCP client pool per agent
-	mcpSettings  map[string]MCPSettings // MCP settings per MCP server (namespace/name)
-	toolRecorder telemetry.ToolRecorder
+	tools            map[string]ToolDefinition
+	executors        map[string]ToolExecutor
+	outputProcessors map[string]*ToolOutputProcessor
+	outputValidators map[string]*ToolOutputValidator
+	mcpPool          *MCPClientPool         // One MCP client pool per agent
+	mcpSettings      map[string]MCPSettings // MCP settings per MCP server (namespace/name)
+	toolRecorder     telemetry.ToolRecorder
 }
 
 func NewToolRegistry(mcpSettings map[string]MCPSettings, toolRecorder telemetry.ToolRecorder) *ToolRegistry {
 	return &ToolRegistry{
-		tools:        make(map[string]ToolDefinition),
-		executors:    make(map[string]ToolExecutor),
-		mcpPool:      NewMCPClientPool(),
-		mcpSettings:  mcpSettings,
-		toolRecorder: toolRecorder,
+		tools:            make(map[string]ToolDefinition),
+		executors:        make(map[string]ToolExecutor),
+		outputProcessors: make(map[string]*ToolOutputProcessor),
+		outputValidators: make(map[string]*ToolOutputValidator),
+		mcpPool:          NewMCPClientPool(),
+		mcpSettings:      mcpSettings,
+		toolRecorder:     toolRecorder,
 	}
 }
 
@@ -201,6 +225,16 @@ func (tr *ToolRegistry) RegisterTool(def ToolDefinition, executor ToolExecutor)
 	tr.executors[def.Name] = executor
 }
 
+// SetOutputProcessor sets the post-processing applied to results of the named tool
+func (tr *ToolRegistry) SetOutputProcessor(toolName string, processor *ToolOutputProcessor) {
+	tr.outputProcessors[toolName] = processor
+}
+
+// SetOutputValidator sets the output schema validation applied to results of the named tool
+func (tr *ToolRegistry) SetOutputValidator(toolName string, validator *ToolOutputValidator) {
+	tr.outputValidators[toolName] = validator
+}
+
 func (tr *ToolRegistry) GetToolDefinitions() []ToolDefinition {
 	definitions := make([]ToolDefinition, 0, len(tr.tools))
 	for _, def := range tr.tools {
@@ -215,15 +249,27 @@ func (tr *ToolRegistry) GetToolType(toolName string) string {
 		return "unknown"
 	}
 
-	switch executor.(type) {
-	case *NoopExecutor:
-		return "builtin"
-	case *TerminateExecutor:
+	return executorToolType(executor)
+}
+
+func executorToolType(executor ToolExecutor) string {
+	switch e := executor.(type) {
+	case *SecureToolExecutor:
+		return executorToolType(e.BaseExecutor)
+	case *ApprovalToolExecutor:
+		return executorToolType(e.BaseExecutor)
+	case *NoopExecutor, *TerminateExecutor, *FetchURLExecutor, *CalculatorExecutor, *CurrentTimeExecutor, *JSONTransformExecutor:
 		return "builtin"
 	case *HTTPExecutor:
 		return "custom"
+	case *JobToolExecutor:
+		return "job"
+	case *CodeInterpreterExecutor:
+		return "code-interpreter"
 	case *MCPExecutor:
 		return "mcp"
+	case *KnowledgeBaseExecutor:
+		return "knowledge-base"
 	case *FilteredToolExecutor:
 		return "filtered"
 	default:
@@ -251,12 +297,37 @@ func (tr *ToolRegistry) ExecuteTool(ctx context.Context, call ToolCall, recorder
 		return result, err
 	}
 
+	if validator, ok := tr.outputValidators[call.Function.Name]; ok {
+		if err := validator.Validate(result.Content); err != nil {
+			logf.FromContext(ctx).Info("tool response does not match output schema", "tool", call.Function.Name, "error", err.Error())
+			tr.toolRecorder.RecordError(span, err)
+			return invalidToolOutputResult(call, err), nil
+		}
+	}
+
+	if processor, ok := tr.outputProcessors[call.Function.Name]; ok {
+		result = tr.processToolOutput(ctx, processor, call, result, recorder)
+	}
+
 	tr.toolRecorder.RecordToolResult(span, result.Content)
 	tr.toolRecorder.RecordSuccess(span)
 
 	return result, nil
 }
 
+func (tr *ToolRegistry) processToolOutput(ctx context.Context, processor *ToolOutputProcessor, call ToolCall, result ToolResult, recorder EventEmitter) ToolResult {
+	fullContent := result.Content
+	result.Content = processor.Process(ctx, fullContent)
+	if result.Content == fullContent || !processor.Policy.StoreFullOutput {
+		return result
+	}
+
+	if err := processor.StoreFullOutput(ctx, call, fullContent, recorder); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to store full tool output", "tool", call.Function.Name)
+	}
+	return result
+}
+
 func (tr *ToolRegistry) ToOpenAITools() []openai.ChatCompletionToolParam {
 	tools := make([]openai.ChatCompletionToolParam, 0, len(tr.tools))
 
@@ -514,8 +585,16 @@ func getDefaultToolDescription(toolCRD *arkv1alpha1.Tool) string {
 			return fmt.Sprintf("HTTP request to %s", toolCRD.Spec.HTTP.URL)
 		}
 	case ToolTypeBuiltin:
-		// For builtin tools, use the description from the CRD itself
+		if builtin, ok := builtinTools[BuiltinToolName(toolCRD)]; ok {
+			return builtin.definition.Description
+		}
 		return fmt.Sprintf("Built-in tool: %s", toolCRD.Name)
+	case ToolTypeJob:
+		if toolCRD.Spec.Job != nil {
+			return fmt.Sprintf("Job running %s", toolCRD.Spec.Job.Image)
+		}
+	case ToolTypeCodeInterpreter:
+		return "Run code in a sandbox without network access. Returns the exit code, stdout, stderr and the files the code wrote to its working directory"
 	default:
 		return fmt.Sprintf("Custom tool: %s", toolCRD.Name)
 	}
@@ -532,6 +611,10 @@ func getToolParameters(toolCRD *arkv1alpha1.Tool) map[string]any {
 		if err := json.Unmarshal(toolCRD.Spec.InputSchema.Raw, &parameters); err != nil {
 			logf.Log.Error(err, "failed to unmarshal tool input schema")
 		}
+	} else if builtin, ok := builtinTools[BuiltinToolName(toolCRD)]; ok && toolCRD.Spec.Type == ToolTypeBuiltin {
+		return maps.Clone(builtin.definition.Parameters)
+	} else if toolCRD.Spec.Type == ToolTypeCodeInterpreter {
+		return codeInterpreterParameters(toolCRD.Spec.CodeInterpreter)
 	}
 
 	return parameters
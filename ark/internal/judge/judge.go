@@ -0,0 +1,379 @@
+/* Copyright 2025. McKinsey & Company */
+
+// Package judge implements an LLM-as-judge evaluator for the unified evaluation contract. Responses
+// are scored by an Ark Model against the criteria of a rubric.
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/kubeauth"
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// Evaluation parameters, named like those of the Python ark-evaluator
+const (
+	ParamModelName      = "model.name"
+	ParamModelNamespace = "model.namespace"
+	ParamScope          = "scope"
+	ParamRubric         = "rubric"
+	ParamThreshold      = "threshold"
+	ParamMinScore       = "min-score"
+	ParamEvaluatorRole  = "evaluator-role"
+	ParamContext        = "evaluation.context"
+)
+
+const (
+	// Responses pass when the average criterion score reaches the threshold
+	DefaultThreshold = 0.7
+	// Criteria are scored from 0 to maxCriterionScore and normalized to 0-1
+	maxCriterionScore = 10
+	scopeAll          = "all"
+	defaultModelName  = "default"
+	defaultRole       = "You are an impartial judge evaluating the response of an AI assistant."
+)
+
+// DefaultCriteria are scored when the scope parameter is empty or "all"
+var DefaultCriteria = []string{"relevance", "accuracy", "completeness", "clarity"}
+
+// Judge scores evaluation requests with Ark Models. With an Authorizer, the caller must be allowed
+// to get the query, the sources of its input and the model the judge reads for it.
+type Judge struct {
+	Client        client.Client
+	ModelRecorder telemetry.ModelRecorder
+	Authorizer    *kubeauth.Authorizer
+}
+
+// sample is the exchange being judged
+type sample struct {
+	input     string
+	output    string
+	namespace string
+}
+
+// settings are the judging parameters of a request
+type settings struct {
+	criteria       []string
+	rubric         string
+	threshold      float64
+	role           string
+	context        string
+	modelName      string
+	modelNamespace string
+}
+
+// verdict is the structured answer of the judging model
+type verdict struct {
+	Scores    map[string]float64 `json:"scores"`
+	Reasoning string             `json:"reasoning"`
+}
+
+// Evaluate judges a direct or query evaluation request
+func (j *Judge) Evaluate(ctx context.Context, request genai.UnifiedEvaluationRequest) (*genai.EvaluationResponse, error) {
+	sample, err := j.loadSample(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, err := parseSettings(request.Parameters, sample.namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := j.Authorizer.Authorize(ctx, authorizationv1.ResourceAttributes{
+		Verb: "get", Group: arkv1alpha1.GroupVersion.Group, Resource: "models", Namespace: settings.modelNamespace, Name: settings.modelName,
+	}); err != nil {
+		return nil, err
+	}
+	model, err := genai.LoadModel(ctx, j.Client, settings.modelName, settings.modelNamespace, j.ModelRecorder)
+	if err != nil {
+		return nil, err
+	}
+	schema, err := verdictSchema(settings.criteria)
+	if err != nil {
+		return nil, err
+	}
+	model.OutputSchema = schema
+	model.SchemaName = "evaluation-verdict"
+
+	logf.FromContext(ctx).Info("judging response", "type", request.Type, "evaluator", request.EvaluatorName,
+		"model", settings.modelName, "criteria", settings.criteria)
+
+	completion, err := model.ChatCompletion(ctx, []genai.Message{
+		genai.NewSystemMessage(systemPrompt(settings)),
+		genai.NewUserMessage(userPrompt(sample, settings)),
+	}, nil, 1)
+	if err != nil {
+		return nil, fmt.Errorf("judge model call failed: %w", err)
+	}
+	if completion == nil || len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("judge model returned no choices")
+	}
+
+	verdict, err := parseVerdict(completion.Choices[0].Message.Content, settings.criteria)
+	if err != nil {
+		return nil, err
+	}
+
+	score := 0.0
+	metadata := map[string]string{
+		"model":     settings.modelName,
+		"threshold": formatScore(settings.threshold),
+		"reasoning": verdict.Reasoning,
+	}
+	for _, criterion := range settings.criteria {
+		score += verdict.Scores[criterion]
+		metadata[criterion+"_score"] = formatScore(verdict.Scores[criterion])
+	}
+	score /= float64(len(settings.criteria))
+
+	return &genai.EvaluationResponse{
+		Score:    formatScore(score),
+		Passed:   score >= settings.threshold,
+		Metadata: metadata,
+		TokenUsage: &arkv1alpha1.TokenUsage{
+			PromptTokens:     completion.Usage.PromptTokens,
+			CompletionTokens: completion.Usage.CompletionTokens,
+			TotalTokens:      completion.Usage.TotalTokens,
+			ReasoningTokens:  completion.Usage.CompletionTokensDetails.ReasoningTokens,
+		},
+	}, nil
+}
+
+// loadSample returns the input and output of a direct evaluation, or of the query of a query evaluation
+func (j *Judge) loadSample(ctx context.Context, request genai.UnifiedEvaluationRequest) (sample, error) {
+	switch request.Type {
+	case "direct":
+		input, _ := request.Config["input"].(string)
+		output, _ := request.Config["output"].(string)
+		if input == "" || output == "" {
+			return sample{}, fmt.Errorf("direct evaluation requires config.input and config.output")
+		}
+		return sample{input: input, output: output, namespace: request.Parameters[ParamModelNamespace]}, nil
+	case "query":
+		return j.loadQuerySample(ctx, request.Config["queryRef"])
+	default:
+		return sample{}, fmt.Errorf("unsupported evaluation type '%s': the judge evaluator supports direct and query evaluations", request.Type)
+	}
+}
+
+func (j *Judge) loadQuerySample(ctx context.Context, rawRef any) (sample, error) {
+	data, err := json.Marshal(rawRef)
+	if err != nil {
+		return sample{}, fmt.Errorf("invalid config.queryRef: %w", err)
+	}
+	var ref arkv1alpha1.QueryRef
+	if err := json.Unmarshal(data, &ref); err != nil || ref.Name == "" || ref.Namespace == "" {
+		return sample{}, fmt.Errorf("query evaluation requires config.queryRef with name and namespace")
+	}
+
+	if err := j.Authorizer.Authorize(ctx, authorizationv1.ResourceAttributes{
+		Verb: "get", Group: arkv1alpha1.GroupVersion.Group, Resource: "queries", Namespace: ref.Namespace, Name: ref.Name,
+	}); err != nil {
+		return sample{}, err
+	}
+	var query arkv1alpha1.Query
+	if err := j.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: ref.Namespace}, &query); err != nil {
+		return sample{}, fmt.Errorf("failed to get query %s/%s: %w", ref.Namespace, ref.Name, err)
+	}
+	if err := j.Authorizer.Authorize(ctx, querySourceAccess(&query)...); err != nil {
+		return sample{}, err
+	}
+
+	input, err := queryInput(ctx, j.Client, &query)
+	if err != nil {
+		return sample{}, err
+	}
+
+	var outputs []string
+	for _, response := range query.Status.Responses {
+		if ref.ResponseTarget == "" || response.Target.Name == ref.ResponseTarget {
+			outputs = append(outputs, response.Content)
+		}
+	}
+	if len(outputs) == 0 {
+		return sample{}, fmt.Errorf("query %s/%s has no response to evaluate", ref.Namespace, ref.Name)
+	}
+
+	return sample{input: input, output: strings.Join(outputs, "\n\n"), namespace: ref.Namespace}, nil
+}
+
+// querySourceAccess returns the access to the secrets and config maps the input of the query is
+// resolved from, which the judge reads on behalf of the caller
+func querySourceAccess(query *arkv1alpha1.Query) []authorizationv1.ResourceAttributes {
+	var access []authorizationv1.ResourceAttributes
+	add := func(secret *corev1.SecretKeySelector, configMap *corev1.ConfigMapKeySelector) {
+		if secret != nil {
+			access = append(access, authorizationv1.ResourceAttributes{Verb: "get", Resource: "secrets", Namespace: query.Namespace, Name: secret.Name})
+		}
+		if configMap != nil {
+			access = append(access, authorizationv1.ResourceAttributes{Verb: "get", Resource: "configmaps", Namespace: query.Namespace, Name: configMap.Name})
+		}
+	}
+	if source := query.Spec.InputFrom; source != nil {
+		add(source.SecretKeyRef, source.ConfigMapKeyRef)
+	}
+	for _, parameter := range query.Spec.Parameters {
+		if parameter.ValueFrom != nil {
+			add(parameter.ValueFrom.SecretKeyRef, parameter.ValueFrom.ConfigMapKeyRef)
+		}
+	}
+	return access
+}
+
+// queryInput returns the resolved input of user queries, or the JSON messages of messages queries
+func queryInput(ctx context.Context, k8sClient client.Client, query *arkv1alpha1.Query) (string, error) {
+	// URL inputs check the hosts the namespace allows, which the caller may not be allowed to read
+	ctx = genai.WithToolPolicyClient(ctx, k8sClient)
+	if query.Spec.Type != "" && query.Spec.Type != arkv1alpha1.QueryTypeUser {
+		if query.Spec.InputFrom != nil {
+			return genai.ReadQueryInputSource(ctx, k8sClient, query.Namespace, query.Spec.InputFrom)
+		}
+		return string(query.Spec.Input.Raw), nil
+	}
+
+	input, err := genai.QueryInputString(ctx, k8sClient, query)
+	if err != nil {
+		return "", err
+	}
+	return genai.ResolveQueryInput(ctx, k8sClient, query, input)
+}
+
+func parseSettings(parameters map[string]string, namespace string) (settings, error) {
+	result := settings{
+		criteria:       DefaultCriteria,
+		rubric:         parameters[ParamRubric],
+		threshold:      DefaultThreshold,
+		role:           parameters[ParamEvaluatorRole],
+		context:        parameters[ParamContext],
+		modelName:      parameters[ParamModelName],
+		modelNamespace: parameters[ParamModelNamespace],
+	}
+	if result.modelName == "" {
+		result.modelName = defaultModelName
+	}
+	if result.modelNamespace == "" {
+		result.modelNamespace = namespace
+	}
+	if result.modelNamespace == "" {
+		return settings{}, fmt.Errorf("parameter %s is required", ParamModelNamespace)
+	}
+
+	if scope := strings.TrimSpace(parameters[ParamScope]); scope != "" && scope != scopeAll {
+		result.criteria = nil
+		for _, criterion := range strings.FieldsFunc(scope, func(r rune) bool { return r == ',' || r == ' ' }) {
+			result.criteria = append(result.criteria, strings.ToLower(criterion))
+		}
+	}
+
+	threshold := parameters[ParamThreshold]
+	if threshold == "" {
+		threshold = parameters[ParamMinScore]
+	}
+	if threshold != "" {
+		value, err := strconv.ParseFloat(threshold, 64)
+		if err != nil || value < 0 || value > 1 {
+			return settings{}, fmt.Errorf("parameter %s must be a number between 0 and 1, got '%s'", ParamThreshold, threshold)
+		}
+		result.threshold = value
+	}
+
+	return result, nil
+}
+
+func systemPrompt(settings settings) string {
+	role := settings.role
+	if role == "" {
+		role = defaultRole
+	}
+
+	var prompt strings.Builder
+	prompt.WriteString(role)
+	fmt.Fprintf(&prompt, "\n\nScore the response on each of these criteria from 0 (worst) to %d (best): %s.",
+		maxCriterionScore, strings.Join(settings.criteria, ", "))
+	if settings.rubric != "" {
+		prompt.WriteString("\n\nScore according to this rubric:\n")
+		prompt.WriteString(settings.rubric)
+	}
+	prompt.WriteString("\n\nAnswer with a JSON object with the score of each criterion in \"scores\" and a short justification in \"reasoning\".")
+	return prompt.String()
+}
+
+func userPrompt(sample sample, settings settings) string {
+	var prompt strings.Builder
+	if settings.context != "" {
+		fmt.Fprintf(&prompt, "Context:\n%s\n\n", settings.context)
+	}
+	fmt.Fprintf(&prompt, "User input:\n%s\n\nResponse to evaluate:\n%s", sample.input, sample.output)
+	return prompt.String()
+}
+
+func verdictSchema(criteria []string) (*runtime.RawExtension, error) {
+	scores := map[string]any{}
+	for _, criterion := range criteria {
+		scores[criterion] = map[string]any{"type": "number", "minimum": 0, "maximum": maxCriterionScore}
+	}
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"scores": map[string]any{
+				"type":                 "object",
+				"properties":           scores,
+				"required":             criteria,
+				"additionalProperties": false,
+			},
+			"reasoning": map[string]any{"type": "string"},
+		},
+		"required":             []string{"scores", "reasoning"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &runtime.RawExtension{Raw: schema}, nil
+}
+
+// parseVerdict reads the verdict of the judging model and normalizes its scores to 0-1. Models
+// without structured output may wrap the JSON in text, so the outermost object is used.
+func parseVerdict(content string, criteria []string) (verdict, error) {
+	start, end := strings.Index(content, "{"), strings.LastIndex(content, "}")
+	if start < 0 || end < start {
+		return verdict{}, fmt.Errorf("judge model did not return a JSON verdict: %s", content)
+	}
+
+	var result verdict
+	if err := json.Unmarshal([]byte(content[start:end+1]), &result); err != nil {
+		return verdict{}, fmt.Errorf("failed to parse judge verdict: %w", err)
+	}
+
+	var missing []string
+	for _, criterion := range criteria {
+		score, ok := result.Scores[criterion]
+		if !ok {
+			missing = append(missing, criterion)
+			continue
+		}
+		result.Scores[criterion] = min(max(score, 0), maxCriterionScore) / maxCriterionScore
+	}
+	if len(missing) > 0 {
+		return verdict{}, fmt.Errorf("judge verdict has no score for %s", strings.Join(missing, ", "))
+	}
+	return result, nil
+}
+
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', 2, 64)
+}
@@ -0,0 +1,160 @@
+/* Copyright 2025. McKinsey & Company */
+
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/kubeauth"
+)
+
+func TestParseSettings(t *testing.T) {
+	result, err := parseSettings(map[string]string{}, "default")
+	require.NoError(t, err)
+	assert.Equal(t, DefaultCriteria, result.criteria)
+	assert.Equal(t, DefaultThreshold, result.threshold)
+	assert.Equal(t, "default", result.modelName)
+	assert.Equal(t, "default", result.modelNamespace)
+
+	result, err = parseSettings(map[string]string{
+		ParamScope:          "Accuracy, clarity",
+		ParamMinScore:       "0.5",
+		ParamModelName:      "judge",
+		ParamModelNamespace: "models",
+	}, "default")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"accuracy", "clarity"}, result.criteria)
+	assert.Equal(t, 0.5, result.threshold)
+	assert.Equal(t, "judge", result.modelName)
+	assert.Equal(t, "models", result.modelNamespace)
+
+	_, err = parseSettings(map[string]string{ParamThreshold: "7"}, "default")
+	assert.ErrorContains(t, err, "between 0 and 1")
+
+	_, err = parseSettings(map[string]string{}, "")
+	assert.ErrorContains(t, err, ParamModelNamespace)
+}
+
+func TestParseVerdict(t *testing.T) {
+	criteria := []string{"accuracy", "clarity"}
+
+	result, err := parseVerdict(`Here is my verdict: {"scores": {"accuracy": 8, "clarity": 12}, "reasoning": "ok"}`, criteria)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.8, result.Scores["accuracy"], 1e-9)
+	assert.InDelta(t, 1.0, result.Scores["clarity"], 1e-9)
+	assert.Equal(t, "ok", result.Reasoning)
+
+	_, err = parseVerdict(`{"scores": {"accuracy": 8}, "reasoning": "ok"}`, criteria)
+	assert.ErrorContains(t, err, "no score for clarity")
+
+	_, err = parseVerdict("looks good to me", criteria)
+	assert.ErrorContains(t, err, "did not return a JSON verdict")
+}
+
+func TestServerEvaluate(t *testing.T) {
+	server := &Server{Judge: &Judge{}}
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader("{")))
+	assert.Equal(t, http.StatusBadRequest, recorder.Code)
+
+	// Evaluation errors are reported in the response so the evaluation controller records them
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(`{"type": "batch"}`)))
+	assert.Equal(t, http.StatusOK, recorder.Code)
+
+	var response genai.EvaluationResponse
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Contains(t, response.Error, "unsupported evaluation type 'batch'")
+
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(`{"type": "direct", "config": {"input": "hi"}}`)))
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &response))
+	assert.Contains(t, response.Error, "config.input and config.output")
+}
+
+func TestServerEvaluateAuthorization(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, arkv1alpha1.AddToScheme(scheme))
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				review.Status = authenticationv1.TokenReviewStatus{Authenticated: review.Spec.Token == "valid"}
+			case *authorizationv1.SubjectAccessReview:
+				review.Status.Allowed = review.Spec.ResourceAttributes.Namespace == "team-a"
+			}
+			return nil
+		},
+	}).Build()
+	server := &Server{Judge: &Judge{Client: k8sClient, Authorizer: &kubeauth.Authorizer{Client: k8sClient}}}
+	body := `{"type": "query", "config": {"queryRef": {"name": "q1", "namespace": "team-b"}}}`
+
+	recorder := httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(body)))
+	assert.Equal(t, http.StatusUnauthorized, recorder.Code)
+
+	request := httptest.NewRequest(http.MethodPost, "/evaluate", strings.NewReader(body))
+	request.Header.Set("Authorization", "Bearer valid")
+	recorder = httptest.NewRecorder()
+	server.Handler().ServeHTTP(recorder, request)
+	assert.Equal(t, http.StatusForbidden, recorder.Code)
+	assert.Contains(t, recorder.Body.String(), "cannot get queries in namespace team-b")
+}
+
+func TestQuerySourceAccess(t *testing.T) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "team-a"},
+		Spec: arkv1alpha1.QuerySpec{
+			InputFrom: &arkv1alpha1.QueryInputSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "prompt"}, Key: "input",
+			}},
+			Parameters: []arkv1alpha1.Parameter{{Name: "region", ValueFrom: &arkv1alpha1.ValueFromSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "settings"}, Key: "region"},
+			}}},
+		},
+	}
+
+	assert.Equal(t, []authorizationv1.ResourceAttributes{
+		{Verb: "get", Resource: "secrets", Namespace: "team-a", Name: "prompt"},
+		{Verb: "get", Resource: "configmaps", Namespace: "team-a", Name: "settings"},
+	}, querySourceAccess(query))
+}
+
+func TestQueryInputFromURL(t *testing.T) {
+	scheme := runtime.NewScheme()
+	require.NoError(t, corev1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{
+		Name:        "team-a",
+		Annotations: map[string]string{annotations.QueryInputAllowedHosts: "prompts.example.com"},
+	}}).Build()
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "q1", Namespace: "team-a"},
+		Spec:       arkv1alpha1.QuerySpec{InputFrom: &arkv1alpha1.QueryInputSource{URL: "http://example.com/prompt.txt"}},
+	}
+
+	// The allowed hosts of the namespace are read with the judge client
+	_, err := queryInput(context.Background(), k8sClient, query)
+	assert.ErrorContains(t, err, "host 'example.com' is not in the allowed hosts of the namespace")
+}
@@ -0,0 +1,104 @@
+/* Copyright 2025. McKinsey & Company */
+
+package judge
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+
+	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/kubeauth"
+)
+
+const (
+	// Judging calls a model, which can take a while for long responses
+	evaluateTimeout = 5 * time.Minute
+	shutdownTimeout = 10 * time.Second
+)
+
+// Server serves the judge on POST /evaluate, the path Evaluators point their address at. It runs as
+// a manager runnable on every replica. When the judge has an Authorizer, callers must send a
+// Kubernetes bearer token, e.g. with the bearerToken auth of the Evaluator.
+type Server struct {
+	Judge *Judge
+	Addr  string
+}
+
+// Handler returns the HTTP handler of the judge
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	var evaluate http.Handler = http.HandlerFunc(s.evaluate)
+	if s.Judge.Authorizer != nil {
+		evaluate = s.Judge.Authorizer.Middleware(evaluate)
+	}
+	mux.Handle("POST /evaluate", evaluate)
+	mux.HandleFunc("GET /health", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	return mux
+}
+
+// Start serves until ctx is cancelled
+func (s *Server) Start(ctx context.Context) error {
+	log := logf.FromContext(ctx).WithName("judge")
+	server := &http.Server{
+		Addr:              s.Addr,
+		Handler:           s.Handler(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(_ net.Listener) context.Context { return logf.IntoContext(ctx, log) },
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			log.Error(err, "failed to shut down judge evaluator")
+		}
+	}()
+
+	log.Info("serving judge evaluator", "address", s.Addr)
+	if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// NeedLeaderElection is false so every replica serves evaluations
+func (s *Server) NeedLeaderElection() bool {
+	return false
+}
+
+// evaluate answers evaluation failures with an error in the response body, which the evaluation
+// controller records on the Evaluation
+func (s *Server) evaluate(w http.ResponseWriter, r *http.Request) {
+	var request genai.UnifiedEvaluationRequest
+	if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+		http.Error(w, "invalid evaluation request: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), evaluateTimeout)
+	defer cancel()
+
+	response, err := s.Judge.Evaluate(ctx, request)
+	if authErr := (*kubeauth.Error)(nil); errors.As(err, &authErr) {
+		kubeauth.WriteError(w, err)
+		return
+	}
+	if err != nil {
+		logf.FromContext(ctx).Error(err, "evaluation failed", "type", request.Type, "evaluator", request.EvaluatorName)
+		response = &genai.EvaluationResponse{Error: err.Error()}
+	}
+
+	w.Header().Set("Content-Type", genai.ContentTypeJSON)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		logf.FromContext(ctx).Error(err, "failed to write evaluation response")
+	}
+}
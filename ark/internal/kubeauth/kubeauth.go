@@ -0,0 +1,126 @@
+/* Copyright 2025. McKinsey & Company */
+
+// Package kubeauth authenticates callers of the HTTP services the controller image serves with the
+// TokenReview API, and authorizes them with SubjectAccessReviews. The services act with the
+// controller's permissions, so they check that the caller holds the permissions they use for it.
+package kubeauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// +kubebuilder:rbac:groups=authentication.k8s.io,resources=tokenreviews,verbs=create
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
+
+// Error is a failure to authenticate or authorize a caller, with the HTTP status it maps to
+type Error struct {
+	Status  int
+	Message string
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Authorizer authenticates and authorizes the callers of a service
+type Authorizer struct {
+	Client client.Client
+	// Audiences the bearer tokens must be issued for, the API server audiences when empty
+	Audiences []string
+}
+
+type userKey struct{}
+
+// Middleware authenticates the bearer token of every request and attaches the caller to its
+// context. Requests without a valid token are rejected with 401.
+func (a *Authorizer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, err := a.Authenticate(r.Context(), r.Header.Get("Authorization"))
+		if err != nil {
+			WriteError(w, err)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userKey{}, user)))
+	})
+}
+
+// Authenticate reviews the bearer token of an Authorization header and returns its user
+func (a *Authorizer) Authenticate(ctx context.Context, authorization string) (*authenticationv1.UserInfo, error) {
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found || token == "" {
+		return nil, &Error{Status: http.StatusUnauthorized, Message: "bearer token is required"}
+	}
+
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.Audiences},
+	}
+	if err := a.Client.Create(ctx, review); err != nil {
+		return nil, fmt.Errorf("token review failed: %w", err)
+	}
+	if !review.Status.Authenticated {
+		logf.FromContext(ctx).Info("authentication failed", "error", review.Status.Error)
+		return nil, &Error{Status: http.StatusUnauthorized, Message: "invalid bearer token"}
+	}
+	return &review.Status.User, nil
+}
+
+// Authorize checks that the caller attached to ctx by Middleware is allowed the access of each
+// attributes. A nil Authorizer allows everything, for servers running without authentication.
+func (a *Authorizer) Authorize(ctx context.Context, attributes ...authorizationv1.ResourceAttributes) error {
+	if a == nil {
+		return nil
+	}
+	user, ok := ctx.Value(userKey{}).(*authenticationv1.UserInfo)
+	if !ok {
+		return &Error{Status: http.StatusUnauthorized, Message: "caller is not authenticated"}
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	for _, check := range attributes {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               user.Username,
+				UID:                user.UID,
+				Groups:             user.Groups,
+				Extra:              extra,
+				ResourceAttributes: &check,
+			},
+		}
+		if err := a.Client.Create(ctx, review); err != nil {
+			return fmt.Errorf("access review failed: %w", err)
+		}
+		if !review.Status.Allowed {
+			return &Error{
+				Status:  http.StatusForbidden,
+				Message: fmt.Sprintf("user %s cannot %s %s in namespace %s", user.Username, check.Verb, check.Resource, check.Namespace),
+			}
+		}
+	}
+	return nil
+}
+
+// WriteError answers a request with the status of an authentication or authorization error, or
+// with 500 for failures to review the caller
+func WriteError(w http.ResponseWriter, err error) {
+	var authErr *Error
+	if !errors.As(err, &authErr) {
+		http.Error(w, "failed to authorize caller: "+err.Error(), http.StatusInternalServerError)
+		return
+	}
+	if authErr.Status == http.StatusUnauthorized {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+	}
+	http.Error(w, authErr.Message, authErr.Status)
+}
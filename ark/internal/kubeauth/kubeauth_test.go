@@ -0,0 +1,88 @@
+/* Copyright 2025. McKinsey & Company */
+
+package kubeauth
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newTestAuthorizer accepts the token "valid" as user alice, who may only get queries in the
+// namespace "team-a"
+func newTestAuthorizer(t *testing.T) *Authorizer {
+	scheme := runtime.NewScheme()
+	require.NoError(t, authenticationv1.AddToScheme(scheme))
+	require.NoError(t, authorizationv1.AddToScheme(scheme))
+	k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithInterceptorFuncs(interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			switch review := obj.(type) {
+			case *authenticationv1.TokenReview:
+				if review.Spec.Token == "valid" {
+					review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "alice"}}
+				}
+			case *authorizationv1.SubjectAccessReview:
+				attributes := review.Spec.ResourceAttributes
+				review.Status.Allowed = review.Spec.User == "alice" && attributes.Verb == "get" &&
+					attributes.Resource == "queries" && attributes.Namespace == "team-a"
+			}
+			return nil
+		},
+	}).Build()
+	return &Authorizer{Client: k8sClient}
+}
+
+func TestMiddleware(t *testing.T) {
+	authorizer := newTestAuthorizer(t)
+	handler := authorizer.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := authorizer.Authorize(r.Context(), authorizationv1.ResourceAttributes{
+			Verb: "get", Resource: "queries", Namespace: r.URL.Query().Get("namespace"),
+		}); err != nil {
+			WriteError(w, err)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name          string
+		authorization string
+		namespace     string
+		want          int
+	}{
+		{name: "no token", namespace: "team-a", want: http.StatusUnauthorized},
+		{name: "basic auth", authorization: "Basic dXNlcjpwYXNz", namespace: "team-a", want: http.StatusUnauthorized},
+		{name: "invalid token", authorization: "Bearer invalid", namespace: "team-a", want: http.StatusUnauthorized},
+		{name: "allowed", authorization: "Bearer valid", namespace: "team-a", want: http.StatusOK},
+		{name: "denied", authorization: "Bearer valid", namespace: "team-b", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest(http.MethodGet, "/?namespace="+tt.namespace, nil)
+			if tt.authorization != "" {
+				request.Header.Set("Authorization", tt.authorization)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			assert.Equal(t, tt.want, recorder.Code)
+		})
+	}
+}
+
+func TestAuthorizeWithoutCaller(t *testing.T) {
+	var unset *Authorizer
+	assert.NoError(t, unset.Authorize(context.Background(), authorizationv1.ResourceAttributes{Verb: "get"}))
+
+	err := newTestAuthorizer(t).Authorize(context.Background(), authorizationv1.ResourceAttributes{Verb: "get"})
+	assert.ErrorContains(t, err, "caller is not authenticated")
+}
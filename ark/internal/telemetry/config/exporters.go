@@ -0,0 +1,109 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/trace"
+)
+
+const (
+	// LangfuseHostEnv is the URL of Langfuse, which traces are exported to with the keys of a project
+	LangfuseHostEnv      = "ARK_LANGFUSE_HOST"
+	LangfusePublicKeyEnv = "ARK_LANGFUSE_PUBLIC_KEY"
+	LangfuseSecretKeyEnv = "ARK_LANGFUSE_SECRET_KEY"
+
+	// PhoenixEndpointEnv is the URL of Arize Phoenix, which traces are exported to in the project
+	// named by PhoenixProjectEnv
+	PhoenixEndpointEnv = "ARK_PHOENIX_ENDPOINT"
+	PhoenixAPIKeyEnv   = "ARK_PHOENIX_API_KEY"
+	PhoenixProjectEnv  = "ARK_PHOENIX_PROJECT"
+
+	langfuseTracesPath = "/api/public/otel/v1/traces"
+	phoenixTracesPath  = "/v1/traces"
+
+	// phoenixProjectAttribute is the resource attribute Phoenix files traces under
+	phoenixProjectAttribute = "openinference.project.name"
+)
+
+// exporterConfig is an OTLP/HTTP trace exporter. Without an endpoint URL, the exporter is configured
+// with the OTEL_EXPORTER_OTLP_* environment variables.
+type exporterConfig struct {
+	name        string
+	endpointURL string
+	headers     map[string]string
+}
+
+// exporterConfigsFromEnv returns the exporters traces are sent to: the generic OTLP exporter when
+// OTEL_EXPORTER_OTLP_ENDPOINT is set, Langfuse when ARK_LANGFUSE_HOST is set and Phoenix when
+// ARK_PHOENIX_ENDPOINT is set. Each backend receives every span.
+func exporterConfigsFromEnv() ([]exporterConfig, error) {
+	var configs []exporterConfig
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" {
+		configs = append(configs, exporterConfig{name: "otlp"})
+	}
+
+	if host := os.Getenv(LangfuseHostEnv); host != "" {
+		publicKey := os.Getenv(LangfusePublicKeyEnv)
+		secretKey := os.Getenv(LangfuseSecretKeyEnv)
+		if publicKey == "" || secretKey == "" {
+			return nil, fmt.Errorf("%s requires %s and %s", LangfuseHostEnv, LangfusePublicKeyEnv, LangfuseSecretKeyEnv)
+		}
+		credentials := base64.StdEncoding.EncodeToString([]byte(publicKey + ":" + secretKey))
+		configs = append(configs, exporterConfig{
+			name:        "langfuse",
+			endpointURL: strings.TrimSuffix(host, "/") + langfuseTracesPath,
+			headers:     map[string]string{"Authorization": "Basic " + credentials},
+		})
+	}
+
+	if endpoint := os.Getenv(PhoenixEndpointEnv); endpoint != "" {
+		config := exporterConfig{
+			name:        "phoenix",
+			endpointURL: strings.TrimSuffix(endpoint, "/") + phoenixTracesPath,
+		}
+		if apiKey := os.Getenv(PhoenixAPIKeyEnv); apiKey != "" {
+			config.headers = map[string]string{"Authorization": "Bearer " + apiKey}
+		}
+		configs = append(configs, config)
+	}
+	return configs, nil
+}
+
+// newExporters creates the exporters of the configs
+func newExporters(ctx context.Context, configs []exporterConfig) ([]trace.SpanExporter, error) {
+	exporters := make([]trace.SpanExporter, 0, len(configs))
+	for _, config := range configs {
+		var opts []otlptracehttp.Option
+		if config.endpointURL != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(config.endpointURL))
+		}
+		if len(config.headers) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(config.headers))
+		}
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			for _, created := range exporters {
+				_ = created.Shutdown(ctx)
+			}
+			return nil, fmt.Errorf("failed to create %s exporter: %w", config.name, err)
+		}
+		exporters = append(exporters, exporter)
+	}
+	return exporters, nil
+}
+
+// exporterNames returns the names of the configs, for logging
+func exporterNames(configs []exporterConfig) []string {
+	names := make([]string, len(configs))
+	for i, config := range configs {
+		names[i] = config.name
+	}
+	return names
+}
@@ -5,9 +5,10 @@ package config
 import (
 	"context"
 	"os"
+	"sync"
 
 	otelapi "go.opentelemetry.io/otel"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/sdk/resource"
 	"go.opentelemetry.io/otel/sdk/trace"
 	semconv "go.opentelemetry.io/otel/semconv/v1.24.0"
@@ -29,15 +30,28 @@ type Provider struct {
 	toolRecorder  telemetry.ToolRecorder
 	teamRecorder  telemetry.TeamRecorder
 	shutdown      func() error
+
+	mu       sync.RWMutex
+	settings telemetry.TraceSettings
 }
 
 // NewProvider creates a telemetry provider based on configuration.
-// If OTEL endpoint is not configured, returns a no-op provider.
+// If no OTLP endpoint, Langfuse or Phoenix is configured, returns a no-op provider.
 func NewProvider() *Provider {
-	endpoint := os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT")
-	if endpoint == "" {
-		log.Info("OTEL_EXPORTER_OTLP_ENDPOINT not set, using no-op telemetry")
-		return newNoopProvider()
+	settings, err := TraceSettingsFromEnv()
+	if err != nil {
+		log.Error(err, "invalid trace settings, using defaults")
+		settings = telemetry.DefaultTraceSettings()
+	}
+
+	configs, err := exporterConfigsFromEnv()
+	if err != nil {
+		log.Error(err, "invalid exporter configuration, using no-op telemetry")
+		return newNoopProvider(settings)
+	}
+	if len(configs) == 0 {
+		log.Info("no OTEL_EXPORTER_OTLP_ENDPOINT, ARK_LANGFUSE_HOST or ARK_PHOENIX_ENDPOINT set, using no-op telemetry")
+		return newNoopProvider(settings)
 	}
 
 	serviceName := os.Getenv("OTEL_SERVICE_NAME")
@@ -45,26 +59,26 @@ func NewProvider() *Provider {
 		serviceName = "ark-controller"
 	}
 
-	headers := os.Getenv("OTEL_EXPORTER_OTLP_HEADERS")
-
-	log.Info("initializing OTEL telemetry", "endpoint", endpoint, "service", serviceName, "headers", headers)
+	log.Info("initializing OTEL telemetry", "exporters", exporterNames(configs), "service", serviceName, "environment", settings.Environment)
 
-	// Auto-configure OTLP exporter from environment variables:
+	// The generic OTLP exporter is configured from environment variables:
 	// OTEL_EXPORTER_OTLP_ENDPOINT, OTEL_EXPORTER_OTLP_HEADERS, OTEL_SERVICE_NAME
-	exporter, err := otlptracehttp.New(context.Background())
+	exporters, err := newExporters(context.Background(), configs)
 	if err != nil {
-		log.Error(err, "failed to create OTLP exporter, falling back to no-op telemetry")
-		return newNoopProvider()
+		log.Error(err, "failed to create exporters, falling back to no-op telemetry")
+		return newNoopProvider(settings)
 	}
 
-	// Create trace provider
-	tp := trace.NewTracerProvider(
-		trace.WithBatcher(exporter),
-		trace.WithResource(resource.NewWithAttributes(
-			semconv.SchemaURL,
-			semconv.ServiceName(serviceName),
-		)),
-	)
+	// Create trace provider. Root spans are sampled with the ratio from their namespace
+	// settings and child spans follow the decision of their parent.
+	opts := []trace.TracerProviderOption{
+		trace.WithSampler(trace.ParentBased(newSettingsSampler(settings.SamplingRatio))),
+		trace.WithResource(resource.NewWithAttributes(semconv.SchemaURL, resourceAttributes(serviceName)...)),
+	}
+	for _, exporter := range exporters {
+		opts = append(opts, trace.WithBatcher(exporter))
+	}
+	tp := trace.NewTracerProvider(opts...)
 
 	otelapi.SetTracerProvider(tp)
 
@@ -72,14 +86,14 @@ func NewProvider() *Provider {
 	sendStartupEvent(serviceName)
 
 	// Create OTEL-backed implementations
-	tracer := otelimpl.NewTracer("ark/controller")
+	tracer := otelimpl.NewTracer("ark/controller", settings)
 	queryRecorder := otelimpl.NewQueryRecorder(tracer)
 	agentRecorder := otelimpl.NewAgentRecorder(tracer)
 	modelRecorder := otelimpl.NewModelRecorder(tracer)
 	toolRecorder := otelimpl.NewToolRecorder(tracer)
 	teamRecorder := otelimpl.NewTeamRecorder(tracer)
 
-	log.Info("OTEL telemetry initialized successfully")
+	log.Info("OTEL telemetry initialized successfully", "contentCapture", settings.ContentCapture, "samplingRatio", settings.SamplingRatio)
 
 	return &Provider{
 		tracer:        tracer,
@@ -88,6 +102,7 @@ func NewProvider() *Provider {
 		modelRecorder: modelRecorder,
 		toolRecorder:  toolRecorder,
 		teamRecorder:  teamRecorder,
+		settings:      settings,
 		shutdown: func() error {
 			log.Info("shutting down telemetry")
 			return tp.Shutdown(context.Background())
@@ -96,7 +111,7 @@ func NewProvider() *Provider {
 }
 
 // newNoopProvider creates a no-op telemetry provider.
-func newNoopProvider() *Provider {
+func newNoopProvider(settings telemetry.TraceSettings) *Provider {
 	tracer := noop.NewTracer()
 	queryRecorder := noop.NewQueryRecorder()
 	agentRecorder := noop.NewAgentRecorder()
@@ -111,6 +126,7 @@ func newNoopProvider() *Provider {
 		modelRecorder: modelRecorder,
 		toolRecorder:  toolRecorder,
 		teamRecorder:  teamRecorder,
+		settings:      settings,
 		shutdown:      func() error { return nil },
 	}
 }
@@ -151,6 +167,16 @@ func (p *Provider) Shutdown() error {
 	return p.shutdown()
 }
 
+// resourceAttributes describe the controller to every backend. Phoenix files the traces under the
+// project of ARK_PHOENIX_PROJECT.
+func resourceAttributes(serviceName string) []attribute.KeyValue {
+	attributes := []attribute.KeyValue{semconv.ServiceName(serviceName)}
+	if project := os.Getenv(PhoenixProjectEnv); project != "" {
+		attributes = append(attributes, attribute.String(phoenixProjectAttribute, project))
+	}
+	return attributes
+}
+
 // sendStartupEvent sends a basic startup event to validate telemetry.
 func sendStartupEvent(serviceName string) {
 	tracer := otelapi.Tracer("ark/controller-startup")
@@ -0,0 +1,98 @@
+/* Copyright 2025. McKinsey & Company */
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go.opentelemetry.io/otel/sdk/trace"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"mckinsey.com/ark/internal/telemetry"
+)
+
+// TelemetryConfigMapName is the per-namespace ConfigMap overriding the controller trace settings
+const TelemetryConfigMapName = "ark-config-telemetry"
+
+// TraceSettingsFromEnv reads the controller-wide trace settings:
+// ARK_TELEMETRY_CONTENT_CAPTURE, ARK_TELEMETRY_CONTENT_MAX_LENGTH, ARK_TELEMETRY_SAMPLING_RATIO and
+// ARK_TELEMETRY_ENVIRONMENT.
+func TraceSettingsFromEnv() (telemetry.TraceSettings, error) {
+	return telemetry.DefaultTraceSettings().Merge(map[string]string{
+		"contentCapture":   os.Getenv("ARK_TELEMETRY_CONTENT_CAPTURE"),
+		"contentMaxLength": os.Getenv("ARK_TELEMETRY_CONTENT_MAX_LENGTH"),
+		"samplingRatio":    os.Getenv("ARK_TELEMETRY_SAMPLING_RATIO"),
+		"environment":      os.Getenv("ARK_TELEMETRY_ENVIRONMENT"),
+	})
+}
+
+// TraceSettings returns the controller trace settings.
+func (p *Provider) TraceSettings() telemetry.TraceSettings {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	return p.settings
+}
+
+// SetTraceSettings replaces the controller trace settings, for example when the controller config
+// is reloaded. They apply to queries started afterwards.
+func (p *Provider) SetTraceSettings(settings telemetry.TraceSettings) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.settings = settings
+}
+
+// WithNamespaceTraceSettings returns a context carrying the trace settings for namespace, i.e. the
+// controller settings overridden by the namespace ark-config-telemetry ConfigMap. It must be called
+// before the root span starts for the sampling ratio to apply. An invalid ConfigMap is logged and
+// the controller settings are used, so misconfigured telemetry never fails a query.
+func (p *Provider) WithNamespaceTraceSettings(ctx context.Context, k8sClient client.Client, namespace string) context.Context {
+	settings, err := p.namespaceTraceSettings(ctx, k8sClient, namespace)
+	if err != nil {
+		log.Error(err, "invalid namespace trace settings, using controller defaults", "namespace", namespace)
+		settings = p.TraceSettings()
+	}
+	return telemetry.WithTraceSettings(ctx, settings)
+}
+
+func (p *Provider) namespaceTraceSettings(ctx context.Context, k8sClient client.Client, namespace string) (telemetry.TraceSettings, error) {
+	controllerSettings := p.TraceSettings()
+	cm := &corev1.ConfigMap{}
+	err := k8sClient.Get(ctx, client.ObjectKey{Name: TelemetryConfigMapName, Namespace: namespace}, cm)
+	if errors.IsNotFound(err) {
+		return controllerSettings, nil
+	}
+	if err != nil {
+		return controllerSettings, fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, TelemetryConfigMapName, err)
+	}
+	settings, err := controllerSettings.Merge(cm.Data)
+	if err != nil {
+		return controllerSettings, fmt.Errorf("ConfigMap %s/%s: %w", namespace, TelemetryConfigMapName, err)
+	}
+	return settings, nil
+}
+
+// settingsSampler samples root spans with the ratio from the trace settings on the context,
+// falling back to the controller-wide ratio.
+type settingsSampler struct {
+	defaultRatio float64
+}
+
+func newSettingsSampler(defaultRatio float64) trace.Sampler {
+	return &settingsSampler{defaultRatio: defaultRatio}
+}
+
+func (s *settingsSampler) ShouldSample(params trace.SamplingParameters) trace.SamplingResult {
+	ratio := s.defaultRatio
+	if settings, ok := telemetry.TraceSettingsFromContext(params.ParentContext); ok {
+		ratio = settings.SamplingRatio
+	}
+	return trace.TraceIDRatioBased(ratio).ShouldSample(params)
+}
+
+func (s *settingsSampler) Description() string {
+	return fmt.Sprintf("ArkSettingsSampler{default=%g}", s.defaultRatio)
+}
@@ -0,0 +1,167 @@
+/* Copyright 2025. McKinsey & Company */
+
+// Package metrics exposes Prometheus metrics for the controller. All collectors are registered
+// with the controller-runtime registry and served by the manager metrics endpoint.
+package metrics
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+const (
+	StatusSuccess = "success"
+	StatusError   = "error"
+
+	TokenTypePrompt     = "prompt"
+	TokenTypeCompletion = "completion"
+
+	ResultPassed = "passed"
+	ResultFailed = "failed"
+)
+
+var (
+	// QueryDuration measures how long a query takes to execute against each target
+	QueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ark_query_duration_seconds",
+		Help:    "Duration of query execution per target, by target type.",
+		Buckets: []float64{0.1, 0.5, 1, 2.5, 5, 10, 30, 60, 120, 300, 600},
+	}, []string{"target_type", "namespace", "status"})
+
+	// TokenUsage counts tokens consumed by model calls
+	TokenUsage = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_token_usage_total",
+		Help: "Number of tokens consumed by model calls, by model and namespace.",
+	}, []string{"model", "namespace", "type"})
+
+	// ReasoningTokens counts the completion tokens reasoning models spent on reasoning, which are
+	// also counted as completion tokens in TokenUsage
+	ReasoningTokens = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_reasoning_tokens_total",
+		Help: "Number of completion tokens reasoning models spent on reasoning, by model and namespace.",
+	}, []string{"model", "namespace"})
+
+	// ToolCallDuration measures tool call latency
+	ToolCallDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "ark_tool_call_duration_seconds",
+		Help:    "Latency of tool calls.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"tool", "namespace", "status"})
+
+	// EvaluationResults counts completed evaluations; the pass rate is
+	// ark_evaluation_results_total{result="passed"} / ark_evaluation_results_total
+	EvaluationResults = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_evaluation_results_total",
+		Help: "Number of completed evaluations, by evaluator and result.",
+	}, []string{"evaluator", "namespace", "result"})
+
+	// AgentEvaluationScore is the score of the last completed evaluation of an agent by an
+	// evaluator, for alerting on quality regressions
+	AgentEvaluationScore = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ark_agent_evaluation_score",
+		Help: "Score of the last completed evaluation of an agent, by evaluator.",
+	}, []string{"agent", "evaluator", "namespace"})
+
+	// AgentEvaluationPassed is 1 when the last completed evaluation of an agent by an evaluator
+	// passed, and 0 otherwise
+	AgentEvaluationPassed = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "ark_agent_evaluation_passed",
+		Help: "Whether the last completed evaluation of an agent passed, by evaluator.",
+	}, []string{"agent", "evaluator", "namespace"})
+
+	// QuerySLOViolations counts completed queries that exceeded an objective of their SLO, by
+	// objective and the query template they were created from
+	QuerySLOViolations = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_query_slo_violations_total",
+		Help: "Number of completed queries that exceeded an objective of their SLO, by objective.",
+	}, []string{"objective", "namespace", "query_template"})
+
+	// A2ADiscoveryFailures counts failed agent discovery attempts against A2A servers
+	A2ADiscoveryFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "ark_a2a_discovery_failures_total",
+		Help: "Number of failed agent discovery attempts, by A2A server.",
+	}, []string{"a2aserver", "namespace"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		QueryDuration,
+		TokenUsage,
+		ReasoningTokens,
+		ToolCallDuration,
+		EvaluationResults,
+		AgentEvaluationScore,
+		AgentEvaluationPassed,
+		QuerySLOViolations,
+		A2ADiscoveryFailures,
+	)
+}
+
+func status(err error) string {
+	if err != nil {
+		return StatusError
+	}
+	return StatusSuccess
+}
+
+// ObserveQueryTarget records the duration of a query execution against a single target
+func ObserveQueryTarget(targetType, namespace string, duration time.Duration, err error) {
+	QueryDuration.WithLabelValues(targetType, namespace, status(err)).Observe(duration.Seconds())
+}
+
+// AddTokenUsage records the prompt and completion tokens consumed by a model call
+func AddTokenUsage(model, namespace string, promptTokens, completionTokens int64) {
+	if promptTokens > 0 {
+		TokenUsage.WithLabelValues(model, namespace, TokenTypePrompt).Add(float64(promptTokens))
+	}
+	if completionTokens > 0 {
+		TokenUsage.WithLabelValues(model, namespace, TokenTypeCompletion).Add(float64(completionTokens))
+	}
+}
+
+// AddReasoningTokens records the reasoning tokens of a model call
+func AddReasoningTokens(model, namespace string, reasoningTokens int64) {
+	if reasoningTokens > 0 {
+		ReasoningTokens.WithLabelValues(model, namespace).Add(float64(reasoningTokens))
+	}
+}
+
+// ObserveToolCall records the latency of a tool call
+func ObserveToolCall(tool, namespace string, duration time.Duration, err error) {
+	ToolCallDuration.WithLabelValues(tool, namespace, status(err)).Observe(duration.Seconds())
+}
+
+// RecordEvaluationResult counts a completed evaluation as passed or failed
+func RecordEvaluationResult(evaluator, namespace string, passed bool) {
+	result := ResultFailed
+	if passed {
+		result = ResultPassed
+	}
+	EvaluationResults.WithLabelValues(evaluator, namespace, result).Inc()
+}
+
+// SetAgentEvaluationPassed records whether the last completed evaluation of an agent passed
+func SetAgentEvaluationPassed(agent, evaluator, namespace string, passed bool) {
+	value := 0.0
+	if passed {
+		value = 1
+	}
+	AgentEvaluationPassed.WithLabelValues(agent, evaluator, namespace).Set(value)
+}
+
+// SetAgentEvaluationScore records the score of the last completed evaluation of an agent
+func SetAgentEvaluationScore(agent, evaluator, namespace string, score float64) {
+	AgentEvaluationScore.WithLabelValues(agent, evaluator, namespace).Set(score)
+}
+
+// IncQuerySLOViolation counts a completed query that exceeded an objective of its SLO
+func IncQuerySLOViolation(objective, namespace, queryTemplate string) {
+	QuerySLOViolations.WithLabelValues(objective, namespace, queryTemplate).Inc()
+}
+
+// IncA2ADiscoveryFailure counts a failed discovery attempt against an A2A server
+func IncA2ADiscoveryFailure(a2aServer, namespace string) {
+	A2ADiscoveryFailures.WithLabelValues(a2aServer, namespace).Inc()
+}
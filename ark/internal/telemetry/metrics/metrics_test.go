@@ -0,0 +1,94 @@
+/* Copyright 2025. McKinsey & Company */
+
+package metrics
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func TestAddTokenUsage(t *testing.T) {
+	AddTokenUsage("gpt-4o", "metrics-test", 120, 30)
+	AddTokenUsage("gpt-4o", "metrics-test", 80, 0)
+
+	assert.Equal(t, float64(200), testutil.ToFloat64(TokenUsage.WithLabelValues("gpt-4o", "metrics-test", TokenTypePrompt)))
+	assert.Equal(t, float64(30), testutil.ToFloat64(TokenUsage.WithLabelValues("gpt-4o", "metrics-test", TokenTypeCompletion)))
+}
+
+func TestAddReasoningTokens(t *testing.T) {
+	AddReasoningTokens("o3-mini", "metrics-test", 64)
+	AddReasoningTokens("o3-mini", "metrics-test", 0)
+
+	assert.Equal(t, float64(64), testutil.ToFloat64(ReasoningTokens.WithLabelValues("o3-mini", "metrics-test")))
+}
+
+func TestRecordEvaluationResult(t *testing.T) {
+	RecordEvaluationResult("judge", "metrics-test", true)
+	RecordEvaluationResult("judge", "metrics-test", true)
+	RecordEvaluationResult("judge", "metrics-test", false)
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(EvaluationResults.WithLabelValues("judge", "metrics-test", ResultPassed)))
+	assert.Equal(t, float64(1), testutil.ToFloat64(EvaluationResults.WithLabelValues("judge", "metrics-test", ResultFailed)))
+}
+
+func TestAgentEvaluation(t *testing.T) {
+	SetAgentEvaluationScore("weather", "judge", "metrics-test", 0.9)
+	SetAgentEvaluationPassed("weather", "judge", "metrics-test", true)
+	SetAgentEvaluationScore("weather", "judge", "metrics-test", 0.4)
+	SetAgentEvaluationPassed("weather", "judge", "metrics-test", false)
+
+	assert.Equal(t, 0.4, testutil.ToFloat64(AgentEvaluationScore.WithLabelValues("weather", "judge", "metrics-test")))
+	assert.Equal(t, float64(0), testutil.ToFloat64(AgentEvaluationPassed.WithLabelValues("weather", "judge", "metrics-test")))
+}
+
+func TestIncQuerySLOViolation(t *testing.T) {
+	IncQuerySLOViolation("tokens", "metrics-test", "summarize")
+	IncQuerySLOViolation("tokens", "metrics-test", "summarize")
+	IncQuerySLOViolation("duration", "metrics-test", "")
+
+	assert.Equal(t, float64(2), testutil.ToFloat64(QuerySLOViolations.WithLabelValues("tokens", "metrics-test", "summarize")))
+	assert.Equal(t, float64(1), testutil.ToFloat64(QuerySLOViolations.WithLabelValues("duration", "metrics-test", "")))
+}
+
+func TestObserveDurations(t *testing.T) {
+	ObserveQueryTarget("agent", "metrics-test", 2*time.Second, nil)
+	ObserveQueryTarget("agent", "metrics-test", time.Second, errors.New("boom"))
+	ObserveToolCall("search", "metrics-test", 100*time.Millisecond, nil)
+
+	assert.Equal(t, 2, testutil.CollectAndCount(QueryDuration, "ark_query_duration_seconds"))
+	assert.Equal(t, 1, testutil.CollectAndCount(ToolCallDuration, "ark_tool_call_duration_seconds"))
+}
+
+func TestRegisteredWithControllerRuntime(t *testing.T) {
+	IncA2ADiscoveryFailure("remote", "metrics-test")
+	IncQuerySLOViolation("cost", "metrics-test", "")
+	AddReasoningTokens("registered", "metrics-test", 1)
+	SetAgentEvaluationScore("registered", "judge", "metrics-test", 1)
+	SetAgentEvaluationPassed("registered", "judge", "metrics-test", true)
+
+	families, err := ctrlmetrics.Registry.Gather()
+	assert.NoError(t, err)
+
+	names := map[string]bool{}
+	for _, family := range families {
+		names[family.GetName()] = true
+	}
+	for _, name := range []string{
+		"ark_query_duration_seconds",
+		"ark_token_usage_total",
+		"ark_reasoning_tokens_total",
+		"ark_tool_call_duration_seconds",
+		"ark_evaluation_results_total",
+		"ark_agent_evaluation_score",
+		"ark_agent_evaluation_passed",
+		"ark_query_slo_violations_total",
+		"ark_a2a_discovery_failures_total",
+	} {
+		assert.True(t, names[name], "metric %s not registered", name)
+	}
+}
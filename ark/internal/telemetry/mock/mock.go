@@ -149,6 +149,10 @@ func (s *MockSpan) SpanID() string {
 	return "mock-span-id-456"
 }
 
+func (s *MockSpan) Sampled() bool {
+	return true
+}
+
 // GetAttribute returns the value of an attribute, or nil if not found.
 func (s *MockSpan) GetAttribute(key string) interface{} {
 	s.mu.Lock()
@@ -242,6 +246,18 @@ func (r *MockQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens,
 	)
 }
 
+func (r *MockQueryRecorder) RecordTokenUsageDetail(span telemetry.Span, target, team, agent, model string, promptTokens, completionTokens, totalTokens int64) {
+	span.AddEvent(telemetry.EventTokenUsageDetail,
+		telemetry.String(telemetry.AttrTargetName, target),
+		telemetry.String(telemetry.AttrTeamName, team),
+		telemetry.String(telemetry.AttrAgentName, agent),
+		telemetry.String(telemetry.AttrModelName, model),
+		telemetry.Int64(telemetry.AttrTokensPrompt, promptTokens),
+		telemetry.Int64(telemetry.AttrTokensCompletion, completionTokens),
+		telemetry.Int64(telemetry.AttrTokensTotal, totalTokens),
+	)
+}
+
 func (r *MockQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
@@ -24,13 +24,14 @@ func (t *noopTracer) Start(ctx context.Context, spanName string, opts ...telemet
 // All methods are intentionally empty for zero-overhead no-op behavior.
 type noopSpan struct{}
 
-func (s *noopSpan) End()                                                    {}            //nolint:revive
-func (s *noopSpan) SetAttributes(attributes ...telemetry.Attribute)         {}            //nolint:revive
-func (s *noopSpan) RecordError(err error)                                   {}            //nolint:revive
-func (s *noopSpan) SetStatus(status telemetry.Status, description string)   {}            //nolint:revive
-func (s *noopSpan) AddEvent(name string, attributes ...telemetry.Attribute) {}            //nolint:revive
-func (s *noopSpan) TraceID() string                                         { return "" } //nolint:revive
-func (s *noopSpan) SpanID() string                                          { return "" } //nolint:revive
+func (s *noopSpan) End()                                                    {}               //nolint:revive
+func (s *noopSpan) SetAttributes(attributes ...telemetry.Attribute)         {}               //nolint:revive
+func (s *noopSpan) RecordError(err error)                                   {}               //nolint:revive
+func (s *noopSpan) SetStatus(status telemetry.Status, description string)   {}               //nolint:revive
+func (s *noopSpan) AddEvent(name string, attributes ...telemetry.Attribute) {}               //nolint:revive
+func (s *noopSpan) TraceID() string                                         { return "" }    //nolint:revive
+func (s *noopSpan) SpanID() string                                          { return "" }    //nolint:revive
+func (s *noopSpan) Sampled() bool                                           { return false } //nolint:revive
 
 // noopQueryRecorder is a zero-overhead query recorder that does nothing.
 // All methods are intentionally empty for zero-overhead no-op behavior.
@@ -54,6 +55,8 @@ func (r *noopQueryRecorder) RecordRootOutput(span telemetry.Span, content string
 func (r *noopQueryRecorder) RecordInput(span telemetry.Span, content string)      {} //nolint:revive
 func (r *noopQueryRecorder) RecordOutput(span telemetry.Span, content string)     {} //nolint:revive
 func (r *noopQueryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
+} //nolint:revive
+func (r *noopQueryRecorder) RecordTokenUsageDetail(span telemetry.Span, target, team, agent, model string, promptTokens, completionTokens, totalTokens int64) {
 }                                                                                  //nolint:revive
 func (r *noopQueryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {} //nolint:revive
 func (r *noopQueryRecorder) RecordSuccess(span telemetry.Span)                     {} //nolint:revive
@@ -104,6 +107,8 @@ func (r *noopModelRecorder) RecordInput(span telemetry.Span, messages any) {} //
 func (r *noopModelRecorder) RecordOutput(span telemetry.Span, output any)  {} //nolint:revive
 func (r *noopModelRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, completionTokens, totalTokens int64) {
 } //nolint:revive
+func (r *noopModelRecorder) RecordReasoningTokens(span telemetry.Span, reasoningTokens int64) {
+} //nolint:revive
 func (r *noopModelRecorder) RecordModelDetails(span telemetry.Span, modelName, modelType string) {
 }                                                                       //nolint:revive
 func (r *noopModelRecorder) RecordSuccess(span telemetry.Span)          {} //nolint:revive
@@ -161,6 +161,10 @@ func (r *modelRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+func (r *modelRecorder) RecordReasoningTokens(span telemetry.Span, reasoningTokens int64) {
+	span.SetAttributes(telemetry.Int64(telemetry.AttrTokensReasoning, reasoningTokens))
+}
+
 func (r *modelRecorder) RecordModelDetails(span telemetry.Span, modelName, modelType string) {
 	span.SetAttributes(
 		telemetry.String(telemetry.AttrModelName, modelName),
@@ -80,6 +80,18 @@ func (r *queryRecorder) RecordTokenUsage(span telemetry.Span, promptTokens, comp
 	)
 }
 
+func (r *queryRecorder) RecordTokenUsageDetail(span telemetry.Span, target, team, agent, model string, promptTokens, completionTokens, totalTokens int64) {
+	span.AddEvent(telemetry.EventTokenUsageDetail,
+		telemetry.String(telemetry.AttrTargetName, target),
+		telemetry.String(telemetry.AttrTeamName, team),
+		telemetry.String(telemetry.AttrAgentName, agent),
+		telemetry.String(telemetry.AttrModelName, model),
+		telemetry.Int64(telemetry.AttrTokensPrompt, promptTokens),
+		telemetry.Int64(telemetry.AttrTokensCompletion, completionTokens),
+		telemetry.Int64(telemetry.AttrTokensTotal, totalTokens),
+	)
+}
+
 func (r *queryRecorder) RecordSessionID(span telemetry.Span, sessionID string) {
 	if sessionID != "" {
 		span.SetAttributes(telemetry.String(telemetry.AttrSessionID, sessionID))
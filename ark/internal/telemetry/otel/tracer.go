@@ -20,15 +20,18 @@ const (
 // tracer implements telemetry.Tracer using OpenTelemetry.
 type tracer struct {
 	otelTracer trace.Tracer
+	defaults   telemetry.TraceSettings
 }
 
-// NewTracer creates a new OTEL-backed tracer.
-func NewTracer(name string) telemetry.Tracer {
+// NewTracer creates a new OTEL-backed tracer. Spans capture content according to defaults
+// unless the context carries namespace-specific trace settings.
+func NewTracer(name string, defaults telemetry.TraceSettings) telemetry.Tracer {
 	if name == "" {
 		name = defaultTracerName
 	}
 	return &tracer{
 		otelTracer: otel.Tracer(name),
+		defaults:   defaults,
 	}
 }
 
@@ -60,10 +63,18 @@ func (t *tracer) Start(ctx context.Context, spanName string, opts ...telemetry.S
 		otelOpts = append(otelOpts, trace.WithTimestamp(cfg.Timestamp))
 	}
 
+	// Redact content attributes for queries with a data policy, then apply the capture mode
+	redactor := telemetry.RedactorFromContext(ctx)
+	settings, ok := telemetry.TraceSettingsFromContext(ctx)
+	if !ok {
+		settings = t.defaults
+	}
+	attributes := prepareAttributes(redactor, settings, append(cfg.Attributes, telemetry.EnvironmentAttributes(settings)...))
+
 	// Add attributes
-	if len(cfg.Attributes) > 0 {
-		otelAttrs := make([]attribute.KeyValue, len(cfg.Attributes))
-		for i, attr := range cfg.Attributes {
+	if len(attributes) > 0 {
+		otelAttrs := make([]attribute.KeyValue, len(attributes))
+		for i, attr := range attributes {
 			otelAttrs[i] = convertAttribute(attr)
 		}
 		otelOpts = append(otelOpts, trace.WithAttributes(otelAttrs...))
@@ -72,12 +83,14 @@ func (t *tracer) Start(ctx context.Context, spanName string, opts ...telemetry.S
 	// Start the span
 	ctx, otelSpan := t.otelTracer.Start(ctx, spanName, otelOpts...)
 
-	return ctx, &span{otelSpan: otelSpan}
+	return ctx, &span{otelSpan: otelSpan, redactor: redactor, settings: settings}
 }
 
 // span implements telemetry.Span using OpenTelemetry.
 type span struct {
 	otelSpan trace.Span
+	redactor telemetry.Redactor
+	settings telemetry.TraceSettings
 }
 
 func (s *span) End() {
@@ -88,6 +101,7 @@ func (s *span) SetAttributes(attributes ...telemetry.Attribute) {
 	if len(attributes) == 0 {
 		return
 	}
+	attributes = prepareAttributes(s.redactor, s.settings, attributes)
 
 	otelAttrs := make([]attribute.KeyValue, len(attributes))
 	for i, attr := range attributes {
@@ -111,6 +125,7 @@ func (s *span) AddEvent(name string, attributes ...telemetry.Attribute) {
 		s.otelSpan.AddEvent(name)
 		return
 	}
+	attributes = prepareAttributes(s.redactor, s.settings, attributes)
 
 	otelAttrs := make([]attribute.KeyValue, len(attributes))
 	for i, attr := range attributes {
@@ -127,6 +142,15 @@ func (s *span) SpanID() string {
 	return s.otelSpan.SpanContext().SpanID().String()
 }
 
+func (s *span) Sampled() bool {
+	return s.otelSpan.SpanContext().IsSampled()
+}
+
+// prepareAttributes redacts and then applies content capture to span attributes.
+func prepareAttributes(redactor telemetry.Redactor, settings telemetry.TraceSettings, attributes []telemetry.Attribute) []telemetry.Attribute {
+	return telemetry.CaptureAttributes(settings, telemetry.RedactAttributes(redactor, attributes))
+}
+
 // Conversion helpers
 
 func convertAttribute(attr telemetry.Attribute) attribute.KeyValue {
@@ -30,6 +30,10 @@ type QueryRecorder interface {
 	// RecordTokenUsage records LLM token consumption.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordTokenUsageDetail records the token consumption of one target, team, agent and model
+	// of the query as a span event.
+	RecordTokenUsageDetail(span Span, target, team, agent, model string, promptTokens, completionTokens, totalTokens int64)
+
 	// RecordSessionID associates a span with a session for multi-query tracking.
 	RecordSessionID(span Span, sessionID string)
 
@@ -84,6 +88,9 @@ type ModelRecorder interface {
 	// RecordTokenUsage records token consumption for the model call.
 	RecordTokenUsage(span Span, promptTokens, completionTokens, totalTokens int64)
 
+	// RecordReasoningTokens records the completion tokens a reasoning model spent on reasoning.
+	RecordReasoningTokens(span Span, reasoningTokens int64)
+
 	// RecordModelDetails records model configuration. Provider is extracted from modelType.
 	RecordModelDetails(span Span, modelName, modelType string)
 
@@ -148,6 +155,12 @@ const (
 	AttrTargetType = "target.type"
 	AttrTargetName = "target.name"
 
+	// Evaluation attributes
+	AttrEvaluationName      = "evaluation.name"
+	AttrEvaluationNamespace = "evaluation.namespace"
+	AttrEvaluationType      = "evaluation.type"
+	AttrEvaluatorName       = "evaluator.name"
+
 	// Agent attributes
 	AttrAgentName = "agent.name"
 
@@ -163,12 +176,17 @@ const (
 	AttrTokensPrompt     = "gen_ai.usage.input_tokens"
 	AttrTokensCompletion = "gen_ai.usage.output_tokens"
 	AttrTokensTotal      = "gen_ai.usage.total_tokens"
+	AttrTokensReasoning  = "gen_ai.usage.reasoning_tokens"
 
 	// Langfuse-specific attributes for compatibility
 	AttrLangfuseModel    = "model"
 	AttrLangfuseProvider = "provider"
 	AttrLangfuseType     = "type"
 
+	// Environment tags, read by Langfuse and by backends following the OTEL resource conventions
+	AttrDeploymentEnvironment = "deployment.environment"
+	AttrLangfuseEnvironment   = "langfuse.environment"
+
 	// Session tracking
 	AttrSessionID = "session.id"
 
@@ -191,6 +209,9 @@ const (
 
 	// Finish reason (aligned with OpenTelemetry GenAI conventions)
 	AttrFinishReason = "gen_ai.completion.finish_reason"
+
+	// Span event carrying the token usage of one target, team, agent and model of a query
+	EventTokenUsageDetail = "token_usage.detail"
 )
 
 // Provider is an interface for telemetry providers that can create recorders.
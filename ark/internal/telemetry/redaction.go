@@ -0,0 +1,64 @@
+/* Copyright 2025. McKinsey & Company */
+
+package telemetry
+
+import (
+	"context"
+	"strings"
+)
+
+// Redactor removes sensitive data from content before it is attached to spans.
+type Redactor func(content string) string
+
+type redactorContextKey struct{}
+
+// WithRedactor returns a context whose spans redact content attributes with redactor.
+func WithRedactor(ctx context.Context, redactor Redactor) context.Context {
+	return context.WithValue(ctx, redactorContextKey{}, redactor)
+}
+
+// RedactorFromContext returns the redactor set on the context, or nil if there is none.
+func RedactorFromContext(ctx context.Context) Redactor {
+	redactor, _ := ctx.Value(redactorContextKey{}).(Redactor)
+	return redactor
+}
+
+// contentAttributes carry user, model or tool content. Other attributes such as names and
+// identifiers are left unchanged so traces remain navigable.
+var contentAttributes = map[string]bool{
+	AttrQueryInput:      true,
+	AttrQueryOutput:     true,
+	AttrQueryRootInput:  true,
+	AttrQueryRootOutput: true,
+	AttrToolInput:       true,
+	AttrToolOutput:      true,
+	AttrMessagesInput:   true,
+	AttrMessagesOutput:  true,
+}
+
+// IsContentAttribute reports whether the attribute key holds content subject to redaction.
+func IsContentAttribute(key string) bool {
+	if contentAttributes[key] {
+		return true
+	}
+	// OpenInference message attributes, e.g. llm.input_messages.0.message.content
+	if strings.HasPrefix(key, "llm.input_messages.") || strings.HasPrefix(key, "llm.output_messages.") {
+		return strings.HasSuffix(key, ".content") || strings.HasSuffix(key, ".arguments")
+	}
+	return false
+}
+
+// RedactAttributes applies redactor to the string values of content attributes.
+func RedactAttributes(redactor Redactor, attributes []Attribute) []Attribute {
+	if redactor == nil {
+		return attributes
+	}
+	redacted := make([]Attribute, len(attributes))
+	for i, attr := range attributes {
+		redacted[i] = attr
+		if value, ok := attr.Value.(string); ok && IsContentAttribute(attr.Key) {
+			redacted[i].Value = redactor(value)
+		}
+	}
+	return redacted
+}
@@ -0,0 +1,174 @@
+/* Copyright 2025. McKinsey & Company */
+
+package telemetry
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ContentCaptureMode controls how content attributes are recorded on spans.
+type ContentCaptureMode string
+
+const (
+	// ContentCaptureFull records content unchanged.
+	ContentCaptureFull ContentCaptureMode = "full"
+	// ContentCaptureTruncated records content up to the configured maximum length.
+	ContentCaptureTruncated ContentCaptureMode = "truncated"
+	// ContentCaptureHashed records a SHA-256 digest of the content so equal values can be correlated.
+	ContentCaptureHashed ContentCaptureMode = "hashed"
+	// ContentCaptureOff omits content attributes.
+	ContentCaptureOff ContentCaptureMode = "off"
+)
+
+const (
+	DefaultContentMaxLength = 1024
+	DefaultSamplingRatio    = 1.0
+
+	truncatedSuffix = "...[truncated]"
+)
+
+// environmentPattern is the format of environments accepted by Langfuse, so the same names work
+// with every backend
+var environmentPattern = regexp.MustCompile(`^[a-z0-9_-]{1,40}$`)
+
+// TraceSettings controls content capture and head-based sampling of traces, and the environment
+// spans are tagged with.
+type TraceSettings struct {
+	ContentCapture   ContentCaptureMode
+	ContentMaxLength int
+	SamplingRatio    float64
+	Environment      string
+}
+
+// DefaultTraceSettings captures full content and samples every trace.
+func DefaultTraceSettings() TraceSettings {
+	return TraceSettings{
+		ContentCapture:   ContentCaptureFull,
+		ContentMaxLength: DefaultContentMaxLength,
+		SamplingRatio:    DefaultSamplingRatio,
+	}
+}
+
+// ParseContentCaptureMode validates a content capture mode.
+func ParseContentCaptureMode(value string) (ContentCaptureMode, error) {
+	switch mode := ContentCaptureMode(value); mode {
+	case ContentCaptureFull, ContentCaptureTruncated, ContentCaptureHashed, ContentCaptureOff:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("unknown content capture mode %q, expected one of full, truncated, hashed, off", value)
+	}
+}
+
+// Merge returns settings overridden by the non-empty values in overrides. Values are
+// validated, so overrides typically come from user configuration such as a ConfigMap.
+func (s TraceSettings) Merge(overrides map[string]string) (TraceSettings, error) {
+	if value := overrides["contentCapture"]; value != "" {
+		mode, err := ParseContentCaptureMode(value)
+		if err != nil {
+			return s, err
+		}
+		s.ContentCapture = mode
+	}
+	if value := overrides["contentMaxLength"]; value != "" {
+		length, err := strconv.Atoi(value)
+		if err != nil || length <= 0 {
+			return s, fmt.Errorf("invalid contentMaxLength %q, expected a positive integer", value)
+		}
+		s.ContentMaxLength = length
+	}
+	if value := overrides["samplingRatio"]; value != "" {
+		ratio, err := strconv.ParseFloat(value, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			return s, fmt.Errorf("invalid samplingRatio %q, expected a number between 0 and 1", value)
+		}
+		s.SamplingRatio = ratio
+	}
+	if value := overrides["environment"]; value != "" {
+		if !environmentPattern.MatchString(value) || strings.HasPrefix(value, "langfuse") {
+			return s, fmt.Errorf("invalid environment %q, expected up to 40 lowercase letters, digits, '-' or '_', not starting with langfuse", value)
+		}
+		s.Environment = value
+	}
+	return s, nil
+}
+
+// EnvironmentAttributes returns the attributes tagging a span with the environment of the settings.
+func EnvironmentAttributes(settings TraceSettings) []Attribute {
+	if settings.Environment == "" {
+		return nil
+	}
+	return []Attribute{
+		String(AttrDeploymentEnvironment, settings.Environment),
+		String(AttrLangfuseEnvironment, settings.Environment),
+	}
+}
+
+type traceSettingsContextKey struct{}
+
+// WithTraceSettings returns a context whose spans use settings instead of the controller defaults.
+func WithTraceSettings(ctx context.Context, settings TraceSettings) context.Context {
+	return context.WithValue(ctx, traceSettingsContextKey{}, settings)
+}
+
+// WithEnvironment returns a context whose spans are tagged with environment instead of the
+// environment of its trace settings, for example for a query annotated with its environment.
+func WithEnvironment(ctx context.Context, defaults TraceSettings, environment string) (context.Context, error) {
+	settings, ok := TraceSettingsFromContext(ctx)
+	if !ok {
+		settings = defaults
+	}
+	settings, err := settings.Merge(map[string]string{"environment": environment})
+	if err != nil {
+		return ctx, err
+	}
+	return WithTraceSettings(ctx, settings), nil
+}
+
+// TraceSettingsFromContext returns the settings set on the context, if any.
+func TraceSettingsFromContext(ctx context.Context) (TraceSettings, bool) {
+	settings, ok := ctx.Value(traceSettingsContextKey{}).(TraceSettings)
+	return settings, ok
+}
+
+// CaptureAttributes applies the content capture mode to the string values of content attributes.
+func CaptureAttributes(settings TraceSettings, attributes []Attribute) []Attribute {
+	if settings.ContentCapture == "" || settings.ContentCapture == ContentCaptureFull {
+		return attributes
+	}
+	captured := make([]Attribute, 0, len(attributes))
+	for _, attr := range attributes {
+		value, ok := attr.Value.(string)
+		if !ok || !IsContentAttribute(attr.Key) {
+			captured = append(captured, attr)
+			continue
+		}
+		switch settings.ContentCapture {
+		case ContentCaptureOff:
+			continue
+		case ContentCaptureHashed:
+			sum := sha256.Sum256([]byte(value))
+			attr.Value = "sha256:" + hex.EncodeToString(sum[:])
+		case ContentCaptureTruncated:
+			attr.Value = truncate(value, settings.ContentMaxLength)
+		}
+		captured = append(captured, attr)
+	}
+	return captured
+}
+
+func truncate(value string, maxLength int) string {
+	if maxLength <= 0 {
+		maxLength = DefaultContentMaxLength
+	}
+	runes := []rune(value)
+	if len(runes) <= maxLength {
+		return value
+	}
+	return string(runes[:maxLength]) + truncatedSuffix
+}
@@ -0,0 +1,97 @@
+/* Copyright 2025. McKinsey & Company */
+
+package telemetry
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCaptureAttributes(t *testing.T) {
+	attributes := []Attribute{
+		String(AttrQueryInput, strings.Repeat("a", 20)),
+		String(AttrQueryName, "weather"),
+	}
+
+	tests := []struct {
+		name  string
+		mode  ContentCaptureMode
+		check func(t *testing.T, captured []Attribute)
+	}{
+		{name: "full", mode: ContentCaptureFull, check: func(t *testing.T, captured []Attribute) {
+			assert.Equal(t, attributes, captured)
+		}},
+		{name: "truncated", mode: ContentCaptureTruncated, check: func(t *testing.T, captured []Attribute) {
+			assert.Equal(t, "aaaaa"+truncatedSuffix, captured[0].Value)
+			assert.Equal(t, "weather", captured[1].Value)
+		}},
+		{name: "hashed", mode: ContentCaptureHashed, check: func(t *testing.T, captured []Attribute) {
+			assert.True(t, strings.HasPrefix(captured[0].Value.(string), "sha256:"))
+			assert.Equal(t, "weather", captured[1].Value)
+		}},
+		{name: "off", mode: ContentCaptureOff, check: func(t *testing.T, captured []Attribute) {
+			require.Len(t, captured, 1)
+			assert.Equal(t, AttrQueryName, captured[0].Key)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := TraceSettings{ContentCapture: tt.mode, ContentMaxLength: 5}
+			tt.check(t, CaptureAttributes(settings, attributes))
+		})
+	}
+}
+
+func TestTraceSettingsMerge(t *testing.T) {
+	settings, err := DefaultTraceSettings().Merge(map[string]string{
+		"contentCapture": "truncated",
+		"samplingRatio":  "0.25",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, ContentCaptureTruncated, settings.ContentCapture)
+	assert.Equal(t, DefaultContentMaxLength, settings.ContentMaxLength)
+	assert.Equal(t, 0.25, settings.SamplingRatio)
+
+	_, err = DefaultTraceSettings().Merge(map[string]string{"contentCapture": "partial"})
+	assert.ErrorContains(t, err, "unknown content capture mode")
+
+	_, err = DefaultTraceSettings().Merge(map[string]string{"samplingRatio": "2"})
+	assert.ErrorContains(t, err, "invalid samplingRatio")
+
+	_, err = DefaultTraceSettings().Merge(map[string]string{"contentMaxLength": "0"})
+	assert.ErrorContains(t, err, "invalid contentMaxLength")
+
+	settings, err = DefaultTraceSettings().Merge(map[string]string{"environment": "staging"})
+	require.NoError(t, err)
+	assert.Equal(t, []Attribute{String(AttrDeploymentEnvironment, "staging"), String(AttrLangfuseEnvironment, "staging")}, EnvironmentAttributes(settings))
+	assert.Empty(t, EnvironmentAttributes(DefaultTraceSettings()))
+
+	_, err = DefaultTraceSettings().Merge(map[string]string{"environment": "Prod"})
+	assert.ErrorContains(t, err, "invalid environment")
+	_, err = DefaultTraceSettings().Merge(map[string]string{"environment": "langfuse-prod"})
+	assert.ErrorContains(t, err, "invalid environment")
+}
+
+func TestWithEnvironment(t *testing.T) {
+	namespaceSettings := TraceSettings{ContentCapture: ContentCaptureHashed, SamplingRatio: 0.5, Environment: "staging"}
+	ctx, err := WithEnvironment(WithTraceSettings(context.Background(), namespaceSettings), DefaultTraceSettings(), "prod")
+	require.NoError(t, err)
+	settings, ok := TraceSettingsFromContext(ctx)
+	require.True(t, ok)
+	assert.Equal(t, ContentCaptureHashed, settings.ContentCapture)
+	assert.Equal(t, "prod", settings.Environment)
+
+	ctx, err = WithEnvironment(context.Background(), DefaultTraceSettings(), "qa")
+	require.NoError(t, err)
+	settings, _ = TraceSettingsFromContext(ctx)
+	assert.Equal(t, DefaultSamplingRatio, settings.SamplingRatio)
+	assert.Equal(t, "qa", settings.Environment)
+
+	_, err = WithEnvironment(context.Background(), DefaultTraceSettings(), "Not Valid")
+	assert.ErrorContains(t, err, "invalid environment")
+}
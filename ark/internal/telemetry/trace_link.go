@@ -0,0 +1,28 @@
+/* Copyright 2025. McKinsey & Company */
+
+package telemetry
+
+import (
+	"os"
+	"strings"
+)
+
+// TraceURLTemplateEnv is the URL of a trace in the trace UI, with {traceID} in place of the trace
+// ID, for example https://langfuse.example.com/project/ark/traces/{traceID}
+const TraceURLTemplateEnv = "ARK_TRACE_URL_TEMPLATE"
+
+const traceIDPlaceholder = "{traceID}"
+
+// TraceLink returns the trace ID of a span and its URL in the trace UI, for resources to link to
+// the trace of their execution. Both are empty when the span is not exported, and the URL is empty
+// without a URL template.
+func TraceLink(span Span) (traceID, traceURL string) {
+	if !span.Sampled() {
+		return "", ""
+	}
+	traceID = span.TraceID()
+	if template := os.Getenv(TraceURLTemplateEnv); template != "" && traceID != "" {
+		traceURL = strings.ReplaceAll(template, traceIDPlaceholder, traceID)
+	}
+	return traceID, traceURL
+}
@@ -0,0 +1,37 @@
+/* Copyright 2025. McKinsey & Company */
+
+package telemetry
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type linkSpan struct {
+	Span
+	traceID string
+	sampled bool
+}
+
+func (s linkSpan) TraceID() string { return s.traceID }
+func (s linkSpan) Sampled() bool   { return s.sampled }
+
+func TestTraceLink(t *testing.T) {
+	span := linkSpan{traceID: "4bf92f3577b34da6a3ce929d0e0e4736", sampled: true}
+
+	traceID, traceURL := TraceLink(span)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Empty(t, traceURL)
+
+	t.Setenv(TraceURLTemplateEnv, "https://langfuse.example.com/project/ark/traces/{traceID}")
+	traceID, traceURL = TraceLink(span)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", traceID)
+	assert.Equal(t, "https://langfuse.example.com/project/ark/traces/4bf92f3577b34da6a3ce929d0e0e4736", traceURL)
+
+	// Traces that are not exported cannot be opened, so they are not linked
+	span.sampled = false
+	traceID, traceURL = TraceLink(span)
+	assert.Empty(t, traceID)
+	assert.Empty(t, traceURL)
+}
@@ -31,6 +31,9 @@ type Span interface {
 	// SpanID returns the span ID for correlating with external systems.
 	// Returns empty string if not available.
 	SpanID() string
+
+	// Sampled reports whether the span is exported, so its trace can be found in the backend.
+	Sampled() bool
 }
 
 // SpanOption configures span creation behavior.
@@ -0,0 +1,74 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// AgentPolicyConfigMapName is the per-namespace ConfigMap holding organisation policies for agent prompts
+const AgentPolicyConfigMapName = "ark-config-agent-policy"
+
+// AgentPolicy is the resolved agent policy for a namespace
+type AgentPolicy struct {
+	// MaxPromptLength is the maximum prompt length in characters, or 0 for no limit
+	MaxPromptLength int
+	// BannedInstructions are phrases that must not appear in prompts, matched case-insensitively
+	BannedInstructions []string
+}
+
+// GetAgentPolicy loads the agent policy from the namespace ConfigMap
+// Returns nil if no ConfigMap exists (not an error - no policy is enforced)
+// Returns error if ConfigMap exists but has invalid structure
+func GetAgentPolicy(ctx context.Context, k8sClient client.Client, namespace string) (*AgentPolicy, error) {
+	cm := &corev1.ConfigMap{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: AgentPolicyConfigMapName, Namespace: namespace}, cm); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to get agent policy ConfigMap: %w", err)
+	}
+
+	policy := &AgentPolicy{}
+
+	if value, ok := cm.Data["maxPromptLength"]; ok {
+		maxPromptLength, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || maxPromptLength < 0 {
+			return nil, fmt.Errorf("agent policy ConfigMap has invalid maxPromptLength '%s': must be a non-negative integer", value)
+		}
+		policy.MaxPromptLength = maxPromptLength
+	}
+
+	if value, ok := cm.Data["bannedInstructions"]; ok {
+		if err := yaml.Unmarshal([]byte(value), &policy.BannedInstructions); err != nil {
+			return nil, fmt.Errorf("agent policy ConfigMap has invalid bannedInstructions: %w", err)
+		}
+	}
+
+	return policy, nil
+}
+
+// CheckPrompt returns an error if the prompt violates the policy
+func (p *AgentPolicy) CheckPrompt(prompt string) error {
+	if length := utf8.RuneCountInString(prompt); p.MaxPromptLength > 0 && length > p.MaxPromptLength {
+		return fmt.Errorf("prompt is %d characters, exceeding the maximum of %d set in %s", length, p.MaxPromptLength, AgentPolicyConfigMapName)
+	}
+
+	lowerPrompt := strings.ToLower(prompt)
+	for _, banned := range p.BannedInstructions {
+		if banned != "" && strings.Contains(lowerPrompt, strings.ToLower(banned)) {
+			return fmt.Errorf("prompt contains banned instruction '%s' set in %s", banned, AgentPolicyConfigMapName)
+		}
+	}
+
+	return nil
+}
@@ -4,28 +4,38 @@ package v1
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
+	"github.com/google/jsonschema-go/jsonschema"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
-	"mckinsey.com/ark/internal/annotations"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/genai"
 )
 
 // SetupAgentWebhookWithManager registers the webhook for Agent in the manager.
 func SetupAgentWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.Agent{}).
-		WithDefaulter(&AgentCustomDefaulter{}).
+		WithDefaulter(&AgentCustomDefaulter{Client: mgr.GetClient()}).
 		WithValidator(&AgentCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/mutate-ark-mckinsey-com-v1alpha1-agent,mutating=true,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=agents,verbs=create;update,versions=v1alpha1,name=magent-v1.kb.io,admissionReviewVersions=v1
 
-type AgentCustomDefaulter struct{}
+// AgentCustomDefaulter sets the namespace default model from the ark-config-defaults ConfigMap on agents without a model
+type AgentCustomDefaulter struct {
+	Client client.Client
+}
 
 var _ webhook.CustomDefaulter = &AgentCustomDefaulter{}
 
@@ -35,21 +45,29 @@ func (d *AgentCustomDefaulter) Default(ctx context.Context, obj runtime.Object)
 		return fmt.Errorf("expected an Agent object but got %T", obj)
 	}
 
-	_, isA2A := agent.Annotations[annotations.A2AServerName]
+	isA2A, err := isDiscoveredA2AAgent(ctx, d.Client, agent)
+	if err != nil {
+		return err
+	}
 	hasModel := agent.Spec.ModelRef != nil
 
 	// Set default model for non-A2A agents
-	// A2A agents are identified by the presence of the a2a-server-name annotation
+	// A2A agents are identified by their controller, the A2AServer they were discovered from
 	// For upgrade details, see docs/content/reference/upgrading.mdx
 	if !hasModel && !isA2A {
+		defaults, err := genai.GetNamespaceDefaults(ctx, d.Client, agent.Namespace)
+		if err != nil {
+			return err
+		}
 		agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{
-			Name: "default",
+			Name: defaults.Model,
 		}
 	}
 
 	return nil
 }
 
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
 // +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-agent,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=agents,verbs=create;update,versions=v1alpha1,name=vagent-v1.kb.io,admissionReviewVersions=v1
 
 type AgentCustomValidator struct {
@@ -87,55 +105,291 @@ func (v *AgentCustomValidator) ValidateDelete(ctx context.Context, obj runtime.O
 func (v *AgentCustomValidator) validateAgent(ctx context.Context, agent *arkv1alpha1.Agent) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
-	if err := v.validateAgentModel(ctx, agent); err != nil {
+	warnings = append(warnings, v.validateAgentModel(ctx, agent)...)
+	warnings = append(warnings, v.validateExecutionEngine(ctx, agent)...)
+
+	if err := v.ValidateParameters(ctx, agent.Namespace, agent.Spec.Parameters); err != nil {
 		return warnings, err
 	}
 
-	if err := v.ValidateParameters(ctx, agent.Namespace, agent.Spec.Parameters); err != nil {
+	promptWarnings, err := v.validatePrompt(ctx, agent)
+	if err != nil {
 		return warnings, err
 	}
+	warnings = append(warnings, promptWarnings...)
+
+	if err := validateOutputSchema(agent.Spec.OutputSchema); err != nil {
+		return warnings, err
+	}
+
+	if err := v.validateAgentModelParameters(ctx, agent); err != nil {
+		return warnings, err
+	}
+
+	if agent.Spec.ModelRef != nil {
+		namespace := agent.Spec.ModelRef.Namespace
+		if namespace == "" {
+			namespace = agent.Namespace
+		}
+		if err := v.ValidateChatModel(ctx, agent.Spec.ModelRef.Name, namespace); err != nil {
+			return warnings, fmt.Errorf("modelRef: %w", err)
+		}
+	}
 
 	for i, tool := range agent.Spec.Tools {
-		toolWarnings, err := v.validateTool(i, tool)
+		toolWarnings, err := v.validateTool(ctx, i, tool, agent.Namespace)
 		if err != nil {
 			return warnings, err
 		}
 		warnings = append(warnings, toolWarnings...)
 	}
 
+	if err := v.validateToolSecurityPolicy(ctx, agent); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidateGuardrailRefs(ctx, agent.Spec.Guardrails, agent.Namespace); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidatePostProcessors(ctx, agent.Spec.PostProcessors, agent.Namespace); err != nil {
+		return warnings, err
+	}
+
+	knowledgeBaseWarnings, err := v.validateKnowledgeBases(ctx, agent)
+	if err != nil {
+		return warnings, err
+	}
+	warnings = append(warnings, knowledgeBaseWarnings...)
+
+	if agent.Spec.A2ARequirements != nil {
+		isA2A, err := isDiscoveredA2AAgent(ctx, v.Client, agent)
+		if err != nil {
+			return warnings, err
+		}
+		if !isA2A {
+			return warnings, fmt.Errorf("a2aRequirements can only be set on agents discovered from an A2AServer")
+		}
+	}
+
+	return warnings, nil
+}
+
+// isDiscoveredA2AAgent reports whether an agent is controlled by the A2AServer it was discovered
+// from. The A2AServer is looked up, so an agent cannot claim to be discovered by setting annotations
+// or an owner reference to a server it was not created for.
+func isDiscoveredA2AAgent(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent) (bool, error) {
+	owner := metav1.GetControllerOf(agent)
+	if owner == nil || owner.Kind != "A2AServer" || owner.APIVersion != arkv1prealpha1.GroupVersion.String() {
+		return false, nil
+	}
+
+	server := &arkv1prealpha1.A2AServer{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: owner.Name, Namespace: agent.Namespace}, server); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to get A2AServer %s: %w", owner.Name, err)
+	}
+	return server.UID == owner.UID, nil
+}
+
+// validatePrompt lints the prompt template and enforces the namespace agent policy, if any.
+// A2A agents are skipped as their prompt is never sent to a model.
+func (v *AgentCustomValidator) validatePrompt(ctx context.Context, agent *arkv1alpha1.Agent) (admission.Warnings, error) {
+	isA2A, err := isDiscoveredA2AAgent(ctx, v.Client, agent)
+	if err != nil || isA2A {
+		return nil, err
+	}
+
+	var warnings admission.Warnings
+	if len(agent.Spec.Parameters) > 0 {
+		templateWarnings, err := ValidateTemplate("prompt", agent.Spec.Prompt, agent.Spec.Parameters)
+		if err != nil {
+			return nil, err
+		}
+		warnings = append(warnings, templateWarnings...)
+	}
+
+	policy, err := GetAgentPolicy(ctx, v.Client, agent.Namespace)
+	if err != nil {
+		return warnings, err
+	}
+	if policy != nil {
+		if err := policy.CheckPrompt(agent.Spec.Prompt); err != nil {
+			return warnings, err
+		}
+	}
+
+	return warnings, nil
+}
+
+// validateOutputSchema rejects output schemas that are not valid JSON schema objects
+func validateOutputSchema(outputSchema *runtime.RawExtension) error {
+	if outputSchema == nil || len(outputSchema.Raw) == 0 {
+		return nil
+	}
+
+	var schema jsonschema.Schema
+	if err := json.Unmarshal(outputSchema.Raw, &schema); err != nil {
+		return fmt.Errorf("failed to parse outputSchema as JSON schema: %v", err)
+	}
+
+	// Structured output requires the top-level schema to describe an object
+	if schema.Type != "" && schema.Type != "object" {
+		return fmt.Errorf("invalid outputSchema type '%s': must be object", schema.Type)
+	}
+
+	return nil
+}
+
+// validateKnowledgeBases denies knowledge bases whose retrieval tool collides with another tool of
+// the agent, and warns when a knowledge base does not exist yet
+func (v *AgentCustomValidator) validateKnowledgeBases(ctx context.Context, agent *arkv1alpha1.Agent) (admission.Warnings, error) {
+	toolNames := map[string]bool{}
+	for _, tool := range agent.Spec.Tools {
+		toolNames[tool.Name] = true
+	}
+
+	var warnings admission.Warnings
+	for i, ref := range agent.Spec.KnowledgeBases {
+		toolName := genai.KnowledgeBaseToolName(ref.Name)
+		if toolNames[toolName] {
+			return warnings, fmt.Errorf("knowledgeBases[%d]: retrieval tool %s is already a tool of the agent", i, toolName)
+		}
+		toolNames[toolName] = true
+
+		if err := v.Client.Get(ctx, types.NamespacedName{Name: ref.Name, Namespace: agent.Namespace}, &arkv1alpha1.KnowledgeBase{}); err != nil {
+			warnings = append(warnings, fmt.Sprintf("knowledgeBases[%d]: knowledge base '%s' does not exist in namespace '%s'", i, ref.Name, agent.Namespace))
+		}
+	}
 	return warnings, nil
 }
 
-func (v *AgentCustomValidator) validateAgentModel(ctx context.Context, agent *arkv1alpha1.Agent) error {
-	// Model validation is now handled at runtime via status conditions
-	// Agents without valid models will show as Available: False
+// validateExecutionEngine warns when the referenced execution engine does not exist yet
+func (v *AgentCustomValidator) validateExecutionEngine(ctx context.Context, agent *arkv1alpha1.Agent) admission.Warnings {
+	engine := agent.Spec.ExecutionEngine
+	if engine == nil || engine.Name == "" || engine.Name == genai.ExecutionEngineA2A {
+		return nil
+	}
+
+	namespace := engine.Namespace
+	if namespace == "" {
+		namespace = agent.Namespace
+	}
+
+	if err := v.ValidateLoadExecutionEngine(ctx, engine.Name, namespace); err != nil {
+		return admission.Warnings{fmt.Sprintf("executionEngine references %v", err)}
+	}
+	return nil
+}
+
+// validateToolSecurityPolicy denies referencing tools whose security policy is less restrictive than the namespace policy
+func (v *AgentCustomValidator) validateToolSecurityPolicy(ctx context.Context, agent *arkv1alpha1.Agent) error {
+	if len(agent.Spec.Tools) == 0 {
+		return nil
+	}
+
+	policy, err := genai.LoadNamespaceToolPolicy(ctx, v.Client, agent.Namespace)
+	if err != nil {
+		return err
+	}
+	if policy == nil {
+		return nil
+	}
+
+	for i, agentTool := range agent.Spec.Tools {
+		if agentTool.Type != "custom" {
+			continue
+		}
+
+		tool := &arkv1alpha1.Tool{}
+		if err := v.Client.Get(ctx, types.NamespacedName{Name: agentTool.Name, Namespace: agentToolNamespace(agentTool, agent.Namespace)}, tool); err != nil {
+			// Missing tools are validated at runtime by the controller
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return fmt.Errorf("tool[%d]: failed to get tool %s: %w", i, agentTool.Name, err)
+		}
+
+		if err := genai.CheckToolSecurityConflict(tool.Name, tool.Spec.Security, policy); err != nil {
+			return fmt.Errorf("tool[%d]: %w", i, err)
+		}
+	}
+
+	return nil
+}
+
+func (v *AgentCustomValidator) validateAgentModel(ctx context.Context, agent *arkv1alpha1.Agent) admission.Warnings {
+	// Missing models only produce a warning; agents without valid models will show as Available: False
 	// This allows for eventual consistency when models are created after agents
+	if agent.Spec.ModelRef == nil {
+		return nil
+	}
+
+	namespace := agent.Spec.ModelRef.Namespace
+	if namespace == "" {
+		namespace = agent.Namespace
+	}
+
+	if err := v.ValidateLoadModel(ctx, agent.Spec.ModelRef.Name, namespace); err != nil {
+		return admission.Warnings{fmt.Sprintf("modelRef references %v", err)}
+	}
+	// Grants may be created after the agent, the agent is unavailable until then
+	if err := genai.CheckReferenceGrant(ctx, v.Client, arkv1alpha1.ReferenceFromAgent, agent.Namespace, arkv1alpha1.TargetKindModel, agent.Spec.ModelRef.Name, namespace); err != nil {
+		return admission.Warnings{fmt.Sprintf("modelRef: %v", err)}
+	}
 	return nil
 }
 
+// validateAgentModelParameters checks the model parameters against the limits of the agent model
+func (v *AgentCustomValidator) validateAgentModelParameters(ctx context.Context, agent *arkv1alpha1.Agent) error {
+	if params := agent.Spec.ModelParameters; params != nil && genai.IsForcedToolChoice(params.ToolChoice) &&
+		len(agent.Spec.Tools) == 0 && len(agent.Spec.KnowledgeBases) == 0 {
+		return fmt.Errorf("modelParameters: toolChoice '%s' requires the agent to have tools", params.ToolChoice)
+	}
+	if agent.Spec.ModelRef == nil {
+		return v.ValidateModelParameters(ctx, agent.Spec.ModelParameters, "", agent.Namespace)
+	}
+	namespace := agent.Spec.ModelRef.Namespace
+	if namespace == "" {
+		namespace = agent.Namespace
+	}
+	return v.ValidateModelParameters(ctx, agent.Spec.ModelParameters, agent.Spec.ModelRef.Name, namespace)
+}
+
 func (v *AgentCustomValidator) validateBuiltInTool(tool arkv1alpha1.AgentTool, hasName bool, index int) error {
 	if !hasName {
 		return fmt.Errorf("tool[%d]: built-in tools must specify a name", index)
 	}
+	if tool.Namespace != "" {
+		return fmt.Errorf("tool[%d]: built-in tools cannot specify a namespace", index)
+	}
 	if !isValidBuiltInTool(tool.Name) {
 		return fmt.Errorf("tool[%d]: unsupported built-in tool '%s': supported built-in tools are: noop, terminate", index, tool.Name)
 	}
 	return nil
 }
 
-func (v *AgentCustomValidator) validateCustomTool(tool arkv1alpha1.AgentTool, hasName bool, index int) (admission.Warnings, error) {
+func (v *AgentCustomValidator) validateCustomTool(ctx context.Context, tool arkv1alpha1.AgentTool, hasName bool, index int, namespace string) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
 	if !hasName {
 		return warnings, fmt.Errorf("tool[%d]: %s tools must specify a name", index, tool.Type)
 	}
 
-	// Custom tools are validated at runtime by the controller
-	// Allow creation to proceed without checking if tool exists
+	// Missing and not yet granted tools only produce a warning as they are resolved at runtime by the controller
+	toolNamespace := agentToolNamespace(tool, namespace)
+	if err := v.ValidateLoadTool(ctx, tool.Name, toolNamespace); err != nil {
+		warnings = append(warnings, fmt.Sprintf("tool[%d]: %v", index, err))
+	}
+	if err := genai.CheckReferenceGrant(ctx, v.Client, arkv1alpha1.ReferenceFromAgent, namespace, arkv1alpha1.TargetKindTool, tool.Name, toolNamespace); err != nil {
+		warnings = append(warnings, fmt.Sprintf("tool[%d]: %v", index, err))
+	}
 	return warnings, nil
 }
 
-func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentTool) (admission.Warnings, error) {
+func (v *AgentCustomValidator) validateTool(ctx context.Context, index int, tool arkv1alpha1.AgentTool, namespace string) (admission.Warnings, error) {
 	var warnings admission.Warnings
 	hasName := tool.Name != ""
 
@@ -145,7 +399,7 @@ func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentToo
 			return warnings, err
 		}
 	case "custom":
-		return v.validateCustomTool(tool, hasName, index)
+		return v.validateCustomTool(ctx, tool, hasName, index, namespace)
 	default:
 		return warnings, fmt.Errorf("tool[%d]: unsupported tool type '%s': supported types are: built-in, custom", index, tool.Type)
 	}
@@ -153,6 +407,14 @@ func (v *AgentCustomValidator) validateTool(index int, tool arkv1alpha1.AgentToo
 	return warnings, nil
 }
 
+// agentToolNamespace returns the namespace of a custom tool, which defaults to the agent namespace
+func agentToolNamespace(tool arkv1alpha1.AgentTool, agentNamespace string) string {
+	if tool.Namespace != "" {
+		return tool.Namespace
+	}
+	return agentNamespace
+}
+
 func isValidBuiltInTool(name string) bool {
 	validBuiltInTools := map[string]bool{
 		"noop":      true,
@@ -7,20 +7,24 @@ import (
 
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
 	"mckinsey.com/ark/internal/annotations"
 	"mckinsey.com/ark/internal/genai"
 )
 
 var _ = Describe("Agent Webhook", func() {
 	var (
-		ctx       context.Context
-		agent     *arkv1alpha1.Agent
-		validator *AgentCustomValidator
+		ctx        context.Context
+		agent      *arkv1alpha1.Agent
+		validator  *AgentCustomValidator
+		fakeClient client.Client
 	)
 
 	BeforeEach(func() {
@@ -29,9 +33,18 @@ var _ = Describe("Agent Webhook", func() {
 		// Setup scheme
 		s := runtime.NewScheme()
 		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(arkv1prealpha1.AddToScheme(s)).To(Succeed())
+		Expect(corev1.AddToScheme(s)).To(Succeed())
+
+		executionEngine := &arkv1prealpha1.ExecutionEngine{
+			ObjectMeta: metav1.ObjectMeta{Name: "langchain", Namespace: "default"},
+		}
+		model := &arkv1alpha1.Model{
+			ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: "default"},
+		}
 
 		// Create fake client
-		fakeClient := fake.NewClientBuilder().WithScheme(s).Build()
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(executionEngine, model).Build()
 
 		// Create validator
 		validator = &AgentCustomValidator{
@@ -97,11 +110,200 @@ var _ = Describe("Agent Webhook", func() {
 		})
 	})
 
+	Context("When validating agent references", func() {
+		It("Should warn when the model does not exist", func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "missing-model"}
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("model 'missing-model' does not exist")))
+		})
+
+		It("Should not warn when the model exists", func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "default"}
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn when a custom tool does not exist", func() {
+			agent.Spec.Tools = []arkv1alpha1.AgentTool{{Type: "custom", Name: "missing-tool"}}
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("tool 'missing-tool' does not exist")))
+		})
+
+		It("Should warn when the execution engine does not exist", func() {
+			agent.Spec.ExecutionEngine = &arkv1alpha1.ExecutionEngineRef{Name: "crewai"}
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("executionEngine 'crewai' does not exist")))
+		})
+	})
+
+	Context("When validating output schema", func() {
+		It("Should allow an object schema", func() {
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type":"object","properties":{"answer":{"type":"string"}}}`)}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a schema that is not valid JSON", func() {
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type":`)}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("failed to parse outputSchema")))
+		})
+
+		It("Should deny a non-object schema", func() {
+			agent.Spec.OutputSchema = &runtime.RawExtension{Raw: []byte(`{"type":"string"}`)}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("must be object")))
+		})
+	})
+
+	Context("When validating model parameters", func() {
+		BeforeEach(func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "claude", Namespace: "default"},
+				Spec:       arkv1alpha1.ModelSpec{Type: genai.ModelTypeBedrock},
+			})).To(Succeed())
+		})
+
+		It("Should allow parameters within the limits of the model", func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "default"}
+			temperature, seed := "1.5", int64(7)
+			agent.Spec.ModelParameters = &arkv1alpha1.ModelParameters{Temperature: &temperature, Seed: &seed}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a temperature above the limit of Bedrock models", func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "claude"}
+			temperature := "1.5"
+			agent.Spec.ModelParameters = &arkv1alpha1.ModelParameters{Temperature: &temperature}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("modelParameters: temperature must be between 0 and 1")))
+		})
+
+		It("Should deny parameters Bedrock models do not support", func() {
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "claude"}
+			penalty := "0.5"
+			agent.Spec.ModelParameters = &arkv1alpha1.ModelParameters{PresencePenalty: &penalty}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("presencePenalty is not supported by bedrock models")))
+		})
+
+		It("Should deny a forced tool choice without tools", func() {
+			agent.Spec.ModelParameters = &arkv1alpha1.ModelParameters{ToolChoice: arkv1alpha1.ToolChoiceRequired}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("modelParameters: toolChoice 'required' requires the agent to have tools")))
+
+			agent.Spec.ModelParameters.ToolChoice = arkv1alpha1.ToolChoiceNone
+			_, err = validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When referencing an embedding model", func() {
+		It("Should deny the model", func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "embeddings", Namespace: "default"},
+				Spec:       arkv1alpha1.ModelSpec{Type: genai.ModelTypeEmbedding},
+			})).To(Succeed())
+			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "embeddings"}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("modelRef: model 'embeddings' is an embedding model")))
+		})
+	})
+
+	Context("When referencing knowledge bases", func() {
+		It("Should warn when a knowledge base does not exist", func() {
+			agent.Spec.KnowledgeBases = []arkv1alpha1.KnowledgeBaseRef{{Name: "product-docs"}}
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("knowledge base 'product-docs' does not exist")))
+		})
+
+		It("Should deny a retrieval tool that collides with a tool of the agent", func() {
+			agent.Spec.KnowledgeBases = []arkv1alpha1.KnowledgeBaseRef{{Name: "product-docs"}, {Name: "product-docs"}}
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("knowledgeBases[1]: retrieval tool search_product-docs is already a tool of the agent")))
+		})
+	})
+
+	Context("When linting prompts", func() {
+		It("Should deny a prompt template that does not parse", func() {
+			agent.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "tone", Value: "formal"}}
+			agent.Spec.Prompt = "Answer in a {{.tone tone"
+			_, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).To(MatchError(ContainSubstring("invalid prompt template")))
+		})
+
+		It("Should warn when the prompt references undefined parameters", func() {
+			agent.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "tone", Value: "formal"}}
+			agent.Spec.Prompt = "Answer in a {{.style}} tone"
+			warnings, err := validator.ValidateCreate(ctx, agent)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("style")))
+		})
+
+		Context("with an agent policy ConfigMap", func() {
+			BeforeEach(func() {
+				Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+					ObjectMeta: metav1.ObjectMeta{Name: AgentPolicyConfigMapName, Namespace: "default"},
+					Data: map[string]string{
+						"maxPromptLength":    "40",
+						"bannedInstructions": "- ignore previous instructions",
+					},
+				})).To(Succeed())
+			})
+
+			It("Should allow a compliant prompt", func() {
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should deny a prompt exceeding the maximum length", func() {
+				agent.Spec.Prompt = "You are a test agent that answers every question in great detail"
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).To(MatchError(ContainSubstring("exceeding the maximum of 40")))
+			})
+
+			It("Should deny a prompt containing a banned instruction", func() {
+				agent.Spec.Prompt = "Ignore previous instructions"
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).To(MatchError(ContainSubstring("banned instruction")))
+			})
+
+			It("Should skip the policy for agents controlled by an A2AServer", func() {
+				discoverAgent(ctx, fakeClient, agent)
+				agent.Spec.Prompt = "Ignore previous instructions"
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("Should enforce the policy for agents that only claim an A2AServer", func() {
+				agent.Annotations = map[string]string{annotations.A2AServerName: "test-a2a-server"}
+				agent.OwnerReferences = []metav1.OwnerReference{{
+					APIVersion: arkv1prealpha1.GroupVersion.String(), Kind: "A2AServer", Name: "test-a2a-server", UID: "forged", Controller: &[]bool{true}[0],
+				}}
+				agent.Spec.Prompt = "Ignore previous instructions"
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).To(MatchError(ContainSubstring("banned instruction")))
+			})
+
+			It("Should count the prompt length in characters", func() {
+				agent.Spec.Prompt = "Vous êtes un agent très précis et sûr"
+				_, err := validator.ValidateCreate(ctx, agent)
+				Expect(err).NotTo(HaveOccurred())
+			})
+		})
+	})
+
 	Context("When defaulting agent model", func() {
 		var defaulter *AgentCustomDefaulter
 
 		BeforeEach(func() {
-			defaulter = &AgentCustomDefaulter{}
+			defaulter = &AgentCustomDefaulter{Client: fakeClient}
 		})
 
 		It("Should set default model for regular agents without modelRef", func() {
@@ -112,6 +314,17 @@ var _ = Describe("Agent Webhook", func() {
 			Expect(agent.Spec.ModelRef.Name).To(Equal("default"))
 		})
 
+		It("Should set the namespace default model from the defaults ConfigMap", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"defaultModel": "gpt-4o"},
+			})).To(Succeed())
+
+			agent.Spec.ModelRef = nil
+			Expect(defaulter.Default(ctx, agent)).To(Succeed())
+			Expect(agent.Spec.ModelRef.Name).To(Equal("gpt-4o"))
+		})
+
 		It("Should not override existing modelRef", func() {
 			agent.Spec.ModelRef = &arkv1alpha1.AgentModelRef{Name: "custom-model"}
 			err := defaulter.Default(ctx, agent)
@@ -121,12 +334,20 @@ var _ = Describe("Agent Webhook", func() {
 
 		It("Should not set default model for A2A agents", func() {
 			agent.Spec.ModelRef = nil
-			agent.Annotations = map[string]string{
-				annotations.A2AServerName: "test-a2a-server",
-			}
+			discoverAgent(ctx, fakeClient, agent)
 			err := defaulter.Default(ctx, agent)
 			Expect(err).NotTo(HaveOccurred())
 			Expect(agent.Spec.ModelRef).To(BeNil())
 		})
 	})
 })
+
+// discoverAgent makes agent controlled by an A2AServer, as agents discovered from it are
+func discoverAgent(ctx context.Context, k8sClient client.Client, agent *arkv1alpha1.Agent) {
+	server := &arkv1prealpha1.A2AServer{ObjectMeta: metav1.ObjectMeta{Name: "test-a2a-server", Namespace: agent.Namespace, UID: "a2a-server-uid"}}
+	Expect(k8sClient.Create(ctx, server)).To(Succeed())
+	agent.Annotations = map[string]string{annotations.A2AServerName: server.Name}
+	agent.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: arkv1prealpha1.GroupVersion.String(), Kind: "A2AServer", Name: server.Name, UID: server.UID, Controller: &[]bool{true}[0],
+	}}
+}
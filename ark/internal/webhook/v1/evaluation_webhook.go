@@ -5,7 +5,10 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"strings"
 
+	"github.com/google/cel-go/cel"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
@@ -53,37 +56,21 @@ func (v *EvaluationValidator) ValidateDelete(ctx context.Context, obj runtime.Ob
 func (v *EvaluationValidator) validateEvaluation(ctx context.Context, evaluation *arkv1alpha1.Evaluation) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
-	// Validate evaluator reference
-	if err := v.validateEvaluatorReference(ctx, evaluation); err != nil {
-		return warnings, err
-	}
-
-	// Validate type-specific requirements
-	switch evaluation.Spec.Type {
-	case "direct", "": // Default to direct type
-		if err := v.validateDirectMode(evaluation); err != nil {
+	// Batch evaluations run the evaluators of their items and template
+	if evaluation.Spec.Type != "batch" || evaluation.Spec.Evaluator.Name != "" {
+		evaluatorWarnings, err := v.validateEvaluatorReference(ctx, "evaluator", evaluation.Spec.Evaluator, evaluation.Namespace)
+		if err != nil {
 			return warnings, err
 		}
-	case "query":
-		if err := v.validateQueryMode(evaluation); err != nil {
-			return warnings, err
-		}
-	case "batch":
-		if err := v.validateBatchMode(evaluation); err != nil {
-			return warnings, err
-		}
-	case "baseline":
-		if err := v.validateBaselineMode(evaluation); err != nil {
-			return warnings, err
-		}
-	case "event":
-		if err := v.validateEventMode(evaluation); err != nil {
-			return warnings, err
-		}
-	default:
-		return warnings, fmt.Errorf("unsupported evaluation type '%s': supported types are: direct, query, batch, baseline, event", evaluation.Spec.Type)
+		warnings = append(warnings, evaluatorWarnings...)
 	}
 
+	configWarnings, err := v.validateConfig(ctx, evaluation.Spec.Type, &evaluation.Spec.Config, evaluation.Namespace)
+	if err != nil {
+		return warnings, err
+	}
+	warnings = append(warnings, configWarnings...)
+
 	// Validate evaluator parameters
 	if err := v.validateEvaluatorParameters(evaluation); err != nil {
 		return warnings, err
@@ -92,87 +79,187 @@ func (v *EvaluationValidator) validateEvaluation(ctx context.Context, evaluation
 	return warnings, nil
 }
 
-func (v *EvaluationValidator) validateEvaluatorReference(ctx context.Context, evaluation *arkv1alpha1.Evaluation) error {
-	evaluatorName := evaluation.Spec.Evaluator.Name
-	evaluatorNamespace := evaluation.Spec.Evaluator.Namespace
-	if evaluatorNamespace == "" {
-		evaluatorNamespace = evaluation.Namespace
+// validateConfig checks the config required by the evaluation type
+func (v *EvaluationValidator) validateConfig(ctx context.Context, evaluationType string, config *arkv1alpha1.EvaluationConfig, namespace string) (admission.Warnings, error) {
+	switch evaluationType {
+	case "direct", "": // Default to direct type
+		return nil, v.validateDirectMode(config)
+	case "query":
+		return nil, v.validateQueryMode(config)
+	case "batch":
+		return v.validateBatchMode(ctx, config, namespace)
+	case "baseline":
+		return nil, v.validateBaselineMode(config)
+	case "event":
+		return v.validateEventMode(config)
+	default:
+		return nil, fmt.Errorf("unsupported evaluation type '%s': supported types are: direct, query, batch, baseline, event", evaluationType)
 	}
+}
 
-	// Validate that the evaluator exists
-	if err := v.ValidateLoadEvaluator(ctx, evaluatorName, evaluatorNamespace); err != nil {
-		return fmt.Errorf("evaluator reference validation failed: %v", err)
+// validateEvaluatorReference requires an evaluator name and warns when the evaluator does not exist
+// yet, since it may be created after the evaluation
+func (v *EvaluationValidator) validateEvaluatorReference(ctx context.Context, field string, evaluator arkv1alpha1.EvaluationEvaluatorRef, namespace string) (admission.Warnings, error) {
+	if evaluator.Name == "" {
+		return nil, fmt.Errorf("%s.name is required", field)
 	}
-
-	return nil
+	if evaluator.Namespace != "" {
+		namespace = evaluator.Namespace
+	}
+	if err := v.ValidateLoadEvaluator(ctx, evaluator.Name, namespace); err != nil {
+		return admission.Warnings{fmt.Sprintf("%s references %v", field, err)}, nil
+	}
+	return nil, nil
 }
 
-func (v *EvaluationValidator) validateDirectMode(evaluation *arkv1alpha1.Evaluation) error {
+func (v *EvaluationValidator) validateDirectMode(config *arkv1alpha1.EvaluationConfig) error {
 	// Direct mode validation - both input and output are required in config
-	if evaluation.Spec.Config.Input == "" {
+	if config.DirectEvaluationConfig == nil || config.Input == "" {
 		return fmt.Errorf("direct mode evaluation requires non-empty input in config")
 	}
 
-	if evaluation.Spec.Config.Output == "" {
+	if config.DirectEvaluationConfig == nil || config.Output == "" {
 		return fmt.Errorf("direct mode evaluation requires non-empty output in config")
 	}
 
 	// Direct mode should not have query references
-	if evaluation.Spec.Config.QueryRef != nil {
+	if config.QueryBasedEvaluationConfig != nil && config.QueryRef != nil {
 		return fmt.Errorf("direct mode evaluation cannot specify queryRef in config")
 	}
 
 	return nil
 }
 
-func (v *EvaluationValidator) validateBatchMode(evaluation *arkv1alpha1.Evaluation) error {
-	// Batch mode requires evaluations list in config
-	if len(evaluation.Spec.Config.Evaluations) == 0 {
-		return fmt.Errorf("batch mode evaluation requires non-empty evaluations list in config")
+func (v *EvaluationValidator) validateBatchMode(ctx context.Context, config *arkv1alpha1.EvaluationConfig, namespace string) (admission.Warnings, error) {
+	// Batch mode requires evaluations to aggregate, items to create or a template for selected queries
+	if config.BatchEvaluationConfig == nil || (len(config.Evaluations) == 0 && len(config.Items) == 0 && config.Template == nil) {
+		return nil, fmt.Errorf("batch mode evaluation requires evaluations, items or template in config")
+	}
+
+	if config.Template != nil && config.QuerySelector == nil {
+		return nil, fmt.Errorf("batch mode evaluation with a template requires querySelector in config")
+	}
+	if config.QuerySelector != nil && config.Template == nil {
+		return nil, fmt.Errorf("batch mode evaluation with a querySelector requires template in config")
 	}
 
 	// Batch mode should not have direct input/output
-	if evaluation.Spec.Config.Input != "" {
-		return fmt.Errorf("batch mode evaluation cannot specify input in config")
+	if config.DirectEvaluationConfig != nil && config.Input != "" {
+		return nil, fmt.Errorf("batch mode evaluation cannot specify input in config")
 	}
 
-	if evaluation.Spec.Config.Output != "" {
-		return fmt.Errorf("batch mode evaluation cannot specify output in config")
+	if config.DirectEvaluationConfig != nil && config.Output != "" {
+		return nil, fmt.Errorf("batch mode evaluation cannot specify output in config")
 	}
 
-	return nil
+	var warnings admission.Warnings
+	for i, item := range config.Items {
+		evaluatorWarnings, err := v.validateEvaluatorReference(ctx, fmt.Sprintf("items[%d].evaluator", i), item.Evaluator, namespace)
+		if err != nil {
+			return warnings, err
+		}
+		warnings = append(warnings, evaluatorWarnings...)
+
+		itemConfig := item.Config
+		itemWarnings, err := v.validateConfig(ctx, item.Type, &itemConfig, namespace)
+		if err != nil {
+			return warnings, fmt.Errorf("items[%d]: %v", i, err)
+		}
+		warnings = append(warnings, prefixWarnings(fmt.Sprintf("items[%d]: ", i), itemWarnings)...)
+	}
+
+	if template := config.Template; template != nil {
+		evaluatorWarnings, err := v.validateEvaluatorReference(ctx, "template.evaluator", template.Evaluator, namespace)
+		if err != nil {
+			return warnings, err
+		}
+		warnings = append(warnings, evaluatorWarnings...)
+
+		// Template evaluations get their query from the selected queries
+		if template.Type == "event" {
+			templateWarnings, err := v.validateEventMode(&template.Config)
+			if err != nil {
+				return warnings, fmt.Errorf("template: %v", err)
+			}
+			warnings = append(warnings, prefixWarnings("template: ", templateWarnings)...)
+		}
+	}
+
+	return warnings, nil
 }
 
-func (v *EvaluationValidator) validateQueryMode(evaluation *arkv1alpha1.Evaluation) error {
+func (v *EvaluationValidator) validateQueryMode(config *arkv1alpha1.EvaluationConfig) error {
 	// Query mode requires a query reference in config
-	if evaluation.Spec.Config.QueryRef == nil {
+	if config.QueryBasedEvaluationConfig == nil || config.QueryRef == nil {
 		return fmt.Errorf("query mode evaluation requires queryRef in config")
 	}
 
 	// Query mode should not have direct input/output (they will be populated from query)
-	if evaluation.Spec.Config.Input != "" {
+	if config.DirectEvaluationConfig != nil && config.Input != "" {
 		return fmt.Errorf("query mode evaluation cannot specify input in config (will be populated from query)")
 	}
 
-	if evaluation.Spec.Config.Output != "" {
+	if config.DirectEvaluationConfig != nil && config.Output != "" {
 		return fmt.Errorf("query mode evaluation cannot specify output in config (will be populated from query)")
 	}
 
 	return nil
 }
 
-func (v *EvaluationValidator) validateBaselineMode(evaluation *arkv1alpha1.Evaluation) error {
+func (v *EvaluationValidator) validateBaselineMode(config *arkv1alpha1.EvaluationConfig) error {
 	// Baseline mode validation - currently no specific requirements
 	return nil
 }
 
-func (v *EvaluationValidator) validateEventMode(evaluation *arkv1alpha1.Evaluation) error {
+func (v *EvaluationValidator) validateEventMode(config *arkv1alpha1.EvaluationConfig) (admission.Warnings, error) {
 	// Event mode validation - should have rules in config
-	if len(evaluation.Spec.Config.Rules) == 0 {
-		return fmt.Errorf("event mode evaluation should specify rules in config")
+	if config.EventEvaluationConfig == nil || len(config.Rules) == 0 {
+		return nil, fmt.Errorf("event mode evaluation should specify rules in config")
 	}
 
-	return nil
+	return validateEventRules(config.Rules)
+}
+
+// semanticHelperPattern matches the helpers of the evaluator, e.g. tools.was_called('search')
+var semanticHelperPattern = regexp.MustCompile(`(?i)\b(tools?|agents?|teams?|llm|sequence|query)\.`)
+
+// validateEventRules rejects duplicate rule names and expressions that are not valid CEL. Expressions
+// using the semantic helpers of the evaluator also accept keyword arguments and `not`, so they only
+// produce a warning.
+func validateEventRules(rules []arkv1alpha1.ExpressionRule) (admission.Warnings, error) {
+	env, err := cel.NewEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	var warnings admission.Warnings
+	names := make(map[string]bool, len(rules))
+	for i, rule := range rules {
+		if names[rule.Name] {
+			return warnings, fmt.Errorf("rules[%d]: duplicate rule name '%s'", i, rule.Name)
+		}
+		names[rule.Name] = true
+
+		if strings.TrimSpace(rule.Expression) == "" {
+			return warnings, fmt.Errorf("rules[%d]: expression cannot be empty", i)
+		}
+		if _, issues := env.Parse(rule.Expression); issues != nil && issues.Err() != nil {
+			if semanticHelperPattern.MatchString(rule.Expression) {
+				warnings = append(warnings, fmt.Sprintf("rules[%d]: expression is not valid CEL: %v", i, issues.Err()))
+				continue
+			}
+			return warnings, fmt.Errorf("rules[%d]: invalid expression: %v", i, issues.Err())
+		}
+	}
+	return warnings, nil
+}
+
+func prefixWarnings(prefix string, warnings admission.Warnings) admission.Warnings {
+	prefixed := make(admission.Warnings, 0, len(warnings))
+	for _, warning := range warnings {
+		prefixed = append(prefixed, prefix+warning)
+	}
+	return prefixed
 }
 
 func (v *EvaluationValidator) validateEvaluatorParameters(evaluation *arkv1alpha1.Evaluation) error {
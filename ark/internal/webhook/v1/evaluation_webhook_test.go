@@ -0,0 +1,138 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Evaluation Webhook", func() {
+	var (
+		ctx        context.Context
+		evaluation *arkv1alpha1.Evaluation
+		validator  *EvaluationValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		evaluator := &arkv1alpha1.Evaluator{ObjectMeta: metav1.ObjectMeta{Name: "judge", Namespace: "default"}}
+		validator = &EvaluationValidator{
+			ResourceValidator: &ResourceValidator{Client: fake.NewClientBuilder().WithScheme(s).WithObjects(evaluator).Build()},
+		}
+
+		evaluation = &arkv1alpha1.Evaluation{
+			ObjectMeta: metav1.ObjectMeta{Name: "weather-check", Namespace: "default"},
+			Spec: arkv1alpha1.EvaluationSpec{
+				Type:      "query",
+				Evaluator: arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"},
+				Config: arkv1alpha1.EvaluationConfig{
+					QueryBasedEvaluationConfig: &arkv1alpha1.QueryBasedEvaluationConfig{QueryRef: &arkv1alpha1.QueryRef{Name: "weather"}},
+				},
+			},
+		}
+	})
+
+	It("Should admit a query evaluation with a queryRef", func() {
+		warnings, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("Should deny a query evaluation without a queryRef", func() {
+		evaluation.Spec.Config = arkv1alpha1.EvaluationConfig{}
+		_, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).To(MatchError(ContainSubstring("requires queryRef")))
+	})
+
+	It("Should warn when the evaluator does not exist yet", func() {
+		evaluation.Spec.Evaluator.Name = "missing"
+		warnings, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ConsistOf(ContainSubstring("missing")))
+	})
+
+	It("Should deny a batch evaluation without evaluations, items or template", func() {
+		evaluation.Spec.Type = "batch"
+		evaluation.Spec.Evaluator = arkv1alpha1.EvaluationEvaluatorRef{}
+		evaluation.Spec.Config = arkv1alpha1.EvaluationConfig{BatchEvaluationConfig: &arkv1alpha1.BatchEvaluationConfig{}}
+		_, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).To(MatchError(ContainSubstring("requires evaluations, items or template")))
+	})
+
+	It("Should validate the items of a batch evaluation", func() {
+		evaluation.Spec.Type = "batch"
+		evaluation.Spec.Evaluator = arkv1alpha1.EvaluationEvaluatorRef{}
+		evaluation.Spec.Config = arkv1alpha1.EvaluationConfig{BatchEvaluationConfig: &arkv1alpha1.BatchEvaluationConfig{
+			Items: []arkv1alpha1.BatchEvaluationItem{{Type: "direct", Evaluator: arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"}}},
+		}}
+		_, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).To(MatchError(ContainSubstring("items[0]: direct mode evaluation requires non-empty input")))
+	})
+
+	It("Should deny a batch template without a querySelector", func() {
+		evaluation.Spec.Type = "batch"
+		evaluation.Spec.Config = arkv1alpha1.EvaluationConfig{BatchEvaluationConfig: &arkv1alpha1.BatchEvaluationConfig{
+			Template: &arkv1alpha1.BatchEvaluationTemplate{Type: "query", Evaluator: arkv1alpha1.EvaluationEvaluatorRef{Name: "judge"}},
+		}}
+		_, err := validator.ValidateCreate(ctx, evaluation)
+		Expect(err).To(MatchError(ContainSubstring("requires querySelector")))
+	})
+
+	Context("When validating event rules", func() {
+		rules := func(expressions ...string) arkv1alpha1.EvaluationConfig {
+			config := arkv1alpha1.EvaluationConfig{EventEvaluationConfig: &arkv1alpha1.EventEvaluationConfig{}}
+			for i, expression := range expressions {
+				config.Rules = append(config.Rules, arkv1alpha1.ExpressionRule{Name: string(rune('a' + i)), Expression: expression})
+			}
+			return config
+		}
+
+		BeforeEach(func() {
+			evaluation.Spec.Type = "event"
+		})
+
+		It("Should deny an event evaluation without rules", func() {
+			evaluation.Spec.Config = rules()
+			_, err := validator.ValidateCreate(ctx, evaluation)
+			Expect(err).To(MatchError(ContainSubstring("should specify rules")))
+		})
+
+		It("Should admit valid CEL expressions", func() {
+			evaluation.Spec.Config = rules("events.exists(e, e.reason == 'ToolCallComplete')", "tools.was_called('search')")
+			warnings, err := validator.ValidateCreate(ctx, evaluation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny expressions that do not compile", func() {
+			evaluation.Spec.Config = rules("events.exists(e, e.reason ==")
+			_, err := validator.ValidateCreate(ctx, evaluation)
+			Expect(err).To(MatchError(ContainSubstring("rules[0]: invalid expression")))
+		})
+
+		It("Should only warn for semantic helper expressions that are not CEL", func() {
+			evaluation.Spec.Config = rules("not tools.had_error('search')")
+			warnings, err := validator.ValidateCreate(ctx, evaluation)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ConsistOf(ContainSubstring("rules[0]: expression is not valid CEL")))
+		})
+
+		It("Should deny duplicate rule names", func() {
+			evaluation.Spec.Config = rules("true", "false")
+			evaluation.Spec.Config.Rules[1].Name = evaluation.Spec.Config.Rules[0].Name
+			_, err := validator.ValidateCreate(ctx, evaluation)
+			Expect(err).To(MatchError(ContainSubstring("duplicate rule name")))
+		})
+	})
+})
@@ -5,6 +5,7 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -50,12 +51,26 @@ func (v *EvaluatorValidator) ValidateCreate(ctx context.Context, obj runtime.Obj
 	evaluatorLog.Info("Validating Evaluator", "name", evaluator.GetName(), "namespace", evaluator.GetNamespace())
 
 	// Validate that the address can be resolved
-	_, err := v.Resolver.ResolveValueSource(ctx, evaluator.Spec.Address, evaluator.GetNamespace())
+	address, err := v.Resolver.ResolveValueSource(ctx, evaluator.Spec.Address, evaluator.GetNamespace())
 	if err != nil {
 		evaluatorLog.Error(err, "Failed to resolve Address", "evaluator", evaluator.GetName())
 		return nil, fmt.Errorf("failed to resolve Address: %w", err)
 	}
 
+	if err := v.validateProtocol(ctx, evaluator, address); err != nil {
+		return nil, err
+	}
+
+	if err := validateEvaluatorAuth(evaluator); err != nil {
+		return nil, err
+	}
+
+	for i, header := range evaluator.Spec.Headers {
+		if err := v.validateHeaderValue(ctx, header.Value, evaluator.GetNamespace()); err != nil {
+			return nil, fmt.Errorf("failed to validate header %s (index %d): %w", header.Name, i, err)
+		}
+	}
+
 	// Validate model reference from parameters - only if explicitly specified
 	var modelName, modelNamespace string
 	modelNamespace = evaluator.GetNamespace()
@@ -87,6 +102,95 @@ func (v *EvaluatorValidator) ValidateCreate(ctx context.Context, obj runtime.Obj
 	return nil, nil
 }
 
+// validateProtocol checks the address of the evaluator against its protocol and that its TLS
+// sources resolve. HTTP evaluators only use TLS with an https address.
+func (v *EvaluatorValidator) validateProtocol(ctx context.Context, evaluator *arkv1alpha1.Evaluator, address string) error {
+	if evaluator.Spec.Protocol == arkv1alpha1.EvaluatorProtocolGRPC {
+		if strings.HasPrefix(address, "http://") || strings.HasPrefix(address, "https://") {
+			return fmt.Errorf("address of a gRPC evaluator must be host:port, got %s", address)
+		}
+	} else if evaluator.Spec.TLS != nil && !strings.HasPrefix(address, "https://") {
+		return fmt.Errorf("tls of an HTTP evaluator requires an https address, got %s", address)
+	}
+
+	tls := evaluator.Spec.TLS
+	if tls == nil {
+		return nil
+	}
+	if (tls.ClientCert == nil) != (tls.ClientKey == nil) {
+		return fmt.Errorf("tls clientCert and clientKey must be set together")
+	}
+	sources := []struct {
+		field  string
+		source *arkv1alpha1.ValueSource
+	}{{"caCert", tls.CACert}, {"clientCert", tls.ClientCert}, {"clientKey", tls.ClientKey}}
+	for _, s := range sources {
+		if s.source == nil {
+			continue
+		}
+		if _, err := v.Resolver.ResolveValueSource(ctx, *s.source, evaluator.GetNamespace()); err != nil {
+			return fmt.Errorf("failed to resolve tls %s: %w", s.field, err)
+		}
+	}
+	return nil
+}
+
+// validateEvaluatorAuth checks that auth does not conflict with the headers and that each of its
+// credentials has a source. The referenced Secrets are checked by the controller, so they can be
+// created after the evaluator.
+func validateEvaluatorAuth(evaluator *arkv1alpha1.Evaluator) error {
+	auth := evaluator.Spec.Auth
+	if auth == nil {
+		return nil
+	}
+
+	// The auth block owns the Authorization header
+	for _, header := range evaluator.Spec.Headers {
+		if strings.EqualFold(header.Name, "Authorization") {
+			return fmt.Errorf("header Authorization cannot be set when auth is configured")
+		}
+	}
+
+	type credential struct {
+		name   string
+		source *arkv1alpha1.ValueSource
+	}
+	credentials := []credential{{"bearerToken", auth.BearerToken}}
+	if auth.Basic != nil {
+		credentials = append(credentials, credential{"basic.username", &auth.Basic.Username}, credential{"basic.password", &auth.Basic.Password})
+	}
+	for _, credential := range credentials {
+		if credential.source == nil {
+			continue
+		}
+		if credential.source.Value == "" && credential.source.ValueFrom == nil {
+			return fmt.Errorf("auth.%s must specify either value or valueFrom", credential.name)
+		}
+		if credential.source.Value != "" && credential.source.ValueFrom != nil {
+			return fmt.Errorf("auth.%s cannot specify both value and valueFrom", credential.name)
+		}
+	}
+	return nil
+}
+
+// validateHeaderValue checks that the Secret or ConfigMap key of a header exists
+func (v *EvaluatorValidator) validateHeaderValue(ctx context.Context, headerValue arkv1alpha1.HeaderValue, namespace string) error {
+	switch {
+	case headerValue.Value != "":
+		return nil
+	case headerValue.ValueFrom == nil:
+		return fmt.Errorf("header value must have either value or valueFrom specified")
+	case headerValue.ValueFrom.SecretKeyRef != nil:
+		ref := headerValue.ValueFrom.SecretKeyRef
+		return v.ValidateLoadSecretKey(ctx, ref.Name, namespace, ref.Key)
+	case headerValue.ValueFrom.ConfigMapKeyRef != nil:
+		ref := headerValue.ValueFrom.ConfigMapKeyRef
+		return v.ValidateLoadConfigMapKey(ctx, ref.Name, namespace, ref.Key)
+	default:
+		return fmt.Errorf("no valid valueFrom source specified for header")
+	}
+}
+
 func (v *EvaluatorValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	return v.ValidateCreate(ctx, newObj)
 }
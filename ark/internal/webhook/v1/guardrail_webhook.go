@@ -0,0 +1,95 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"k8s.io/apimachinery/pkg/runtime"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/genai"
+)
+
+// SetupGuardrailWebhookWithManager registers the webhook for Guardrail in the manager.
+func SetupGuardrailWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.Guardrail{}).
+		WithValidator(&GuardrailCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}}).
+		Complete()
+}
+
+// +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-guardrail,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=guardrails,verbs=create;update,versions=v1alpha1,name=vguardrail-v1.kb.io,admissionReviewVersions=v1
+
+type GuardrailCustomValidator struct {
+	*ResourceValidator
+}
+
+var _ webhook.CustomValidator = &GuardrailCustomValidator{}
+
+func (v *GuardrailCustomValidator) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	guardrail, ok := obj.(*arkv1alpha1.Guardrail)
+	if !ok {
+		return nil, fmt.Errorf("expected a Guardrail object but got %T", obj)
+	}
+
+	return v.validateGuardrail(ctx, guardrail)
+}
+
+func (v *GuardrailCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	guardrail, ok := newObj.(*arkv1alpha1.Guardrail)
+	if !ok {
+		return nil, fmt.Errorf("expected a Guardrail object for the newObj but got %T", newObj)
+	}
+
+	return v.validateGuardrail(ctx, guardrail)
+}
+
+func (v *GuardrailCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	_, ok := obj.(*arkv1alpha1.Guardrail)
+	if !ok {
+		return nil, fmt.Errorf("expected a Guardrail object but got %T", obj)
+	}
+
+	return nil, nil
+}
+
+func (v *GuardrailCustomValidator) validateGuardrail(ctx context.Context, guardrail *arkv1alpha1.Guardrail) (admission.Warnings, error) {
+	var warnings admission.Warnings
+	spec := guardrail.Spec
+
+	if len(spec.Patterns) == 0 && len(spec.Keywords) == 0 && len(spec.Expressions) == 0 && spec.ModerationModelRef == nil {
+		return warnings, fmt.Errorf("at least one of patterns, keywords, expressions or moderationModelRef must be specified")
+	}
+
+	for i, pattern := range spec.Patterns {
+		if _, err := regexp.Compile(pattern); err != nil {
+			return warnings, fmt.Errorf("patterns[%d]: invalid regular expression: %v", i, err)
+		}
+	}
+
+	for i, expression := range spec.Expressions {
+		if _, err := genai.CompileGuardrailExpression(expression); err != nil {
+			return warnings, fmt.Errorf("expressions[%d]: invalid CEL expression: %v", i, err)
+		}
+	}
+
+	if spec.ModerationModelRef != nil {
+		if spec.Policy == "" {
+			return warnings, fmt.Errorf("policy is required when moderationModelRef is specified")
+		}
+		namespace := spec.ModerationModelRef.Namespace
+		if namespace == "" {
+			namespace = guardrail.Namespace
+		}
+		if err := v.ValidateLoadModel(ctx, spec.ModerationModelRef.Name, namespace); err != nil {
+			warnings = append(warnings, fmt.Sprintf("moderationModelRef: %v", err))
+		}
+	}
+
+	return warnings, nil
+}
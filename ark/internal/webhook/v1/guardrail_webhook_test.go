@@ -0,0 +1,75 @@
+/* Copyright 2025. McKinsey & Company */
+
+package v1
+
+import (
+	"context"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+var _ = Describe("Guardrail Webhook", func() {
+	var (
+		ctx       context.Context
+		guardrail *arkv1alpha1.Guardrail
+		validator *GuardrailCustomValidator
+	)
+
+	BeforeEach(func() {
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		fakeClient := fake.NewClientBuilder().WithScheme(s).Build()
+
+		validator = &GuardrailCustomValidator{ResourceValidator: &ResourceValidator{Client: fakeClient}}
+		guardrail = &arkv1alpha1.Guardrail{
+			ObjectMeta: metav1.ObjectMeta{Name: "no-credentials", Namespace: "default"},
+			Spec: arkv1alpha1.GuardrailSpec{
+				Patterns:    []string{`AKIA[0-9A-Z]{16}`},
+				Expressions: []string{`content.contains("password")`},
+			},
+		}
+	})
+
+	It("Should admit valid patterns and expressions", func() {
+		warnings, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(BeEmpty())
+	})
+
+	It("Should deny a guardrail without rules", func() {
+		guardrail.Spec = arkv1alpha1.GuardrailSpec{}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("at least one of")))
+	})
+
+	It("Should deny an invalid regular expression", func() {
+		guardrail.Spec.Patterns = []string{"[a-"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("patterns[0]")))
+	})
+
+	It("Should deny an expression that does not return a bool", func() {
+		guardrail.Spec.Expressions = []string{"content.size()"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("expressions[0]")))
+	})
+
+	It("Should require a policy for moderation models and warn when the model is missing", func() {
+		guardrail.Spec.ModerationModelRef = &arkv1alpha1.AgentModelRef{Name: "moderator"}
+		_, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).To(MatchError(ContainSubstring("policy is required")))
+
+		guardrail.Spec.Policy = "No medical advice"
+		warnings, err := validator.ValidateCreate(ctx, guardrail)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(warnings).To(ContainElement(ContainSubstring("model 'moderator' does not exist")))
+	})
+})
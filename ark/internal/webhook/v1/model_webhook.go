@@ -5,7 +5,10 @@ package v1
 import (
 	"context"
 	"fmt"
+	"strconv"
+	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -16,28 +19,105 @@ import (
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	"mckinsey.com/ark/internal/common"
 	"mckinsey.com/ark/internal/genai"
+	"mckinsey.com/ark/internal/telemetry/noop"
 )
 
 var modellog = logf.Log.WithName("model-resource")
 
-func SetupModelWebhookWithManager(mgr ctrl.Manager) error {
+// modelProbeTimeout keeps connectivity probes within the 10s admission webhook timeout
+const modelProbeTimeout = 8 * time.Second
+
+// SetupModelWebhookWithManager registers the Model webhooks. When probeConnectivity is set, models
+// are only admitted once a one token completion against their endpoint succeeds.
+func SetupModelWebhookWithManager(mgr ctrl.Manager, probeConnectivity bool) error {
 	k8sClient := mgr.GetClient()
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(&arkv1alpha1.Model{}).
+		WithDefaulter(&ModelDefaulter{Client: k8sClient}).
 		WithValidator(&ModelValidator{
-			Client:    k8sClient,
-			Resolver:  common.NewValueSourceResolver(k8sClient),
-			Validator: &ResourceValidator{Client: k8sClient},
+			Client:            k8sClient,
+			Resolver:          common.NewValueSourceResolver(k8sClient),
+			Validator:         &ResourceValidator{Client: k8sClient},
+			ProbeConnectivity: probeConnectivity,
 		}).
 		Complete()
 }
 
+// +kubebuilder:webhook:path=/mutate-ark-mckinsey-com-v1alpha1-model,mutating=true,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=models,verbs=create,versions=v1alpha1,name=mmodel-v1.kb.io,admissionReviewVersions=v1
+
+// ModelDefaulter applies the namespace provider defaults from the ark-config-defaults ConfigMap to new models
+type ModelDefaulter struct {
+	Client client.Client
+}
+
+var _ webhook.CustomDefaulter = &ModelDefaulter{}
+
+func (d *ModelDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	model, ok := obj.(*arkv1alpha1.Model)
+	if !ok {
+		return fmt.Errorf("expected a Model object but got %T", obj)
+	}
+
+	defaults, err := genai.GetNamespaceDefaults(ctx, d.Client, model.GetNamespace())
+	if err != nil {
+		return err
+	}
+
+	config := &model.Spec.Config
+	switch model.Spec.Type {
+	case genai.ModelTypeAzure:
+		if config.Azure == nil {
+			return nil
+		}
+		if config.Azure.APIVersion == nil && defaults.AzureAPIVersion != "" {
+			config.Azure.APIVersion = &arkv1alpha1.ValueSource{Value: defaults.AzureAPIVersion}
+		}
+		config.Azure.Properties = defaultTemperatureProperty(config.Azure.Properties, defaults.ModelTemperature)
+	case genai.ModelTypeOpenAI:
+		if config.OpenAI == nil {
+			return nil
+		}
+		config.OpenAI.Properties = defaultTemperatureProperty(config.OpenAI.Properties, defaults.ModelTemperature)
+	case genai.ModelTypeBedrock:
+		if config.Bedrock == nil {
+			return nil
+		}
+		if config.Bedrock.Temperature == nil && defaults.ModelTemperature != nil {
+			temperature := formatTemperature(*defaults.ModelTemperature)
+			config.Bedrock.Temperature = &temperature
+		}
+	}
+
+	return nil
+}
+
+func defaultTemperatureProperty(properties map[string]arkv1alpha1.ValueSource, temperature *float64) map[string]arkv1alpha1.ValueSource {
+	if temperature == nil {
+		return properties
+	}
+	if _, exists := properties["temperature"]; exists {
+		return properties
+	}
+	if properties == nil {
+		properties = make(map[string]arkv1alpha1.ValueSource)
+	}
+	properties["temperature"] = arkv1alpha1.ValueSource{Value: formatTemperature(*temperature)}
+	return properties
+}
+
+func formatTemperature(temperature float64) string {
+	return strconv.FormatFloat(temperature, 'f', -1, 64)
+}
+
 // +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-model,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=models,verbs=create;update,versions=v1alpha1,name=vmodel-v1.kb.io,admissionReviewVersions=v1
 
 type ModelValidator struct {
 	Client    client.Client
 	Resolver  *common.ValueSourceResolver
 	Validator *ResourceValidator
+	// ProbeConnectivity calls the model endpoint at admission, catching bad keys and base URLs
+	// before queries fail
+	ProbeConnectivity bool
 }
 
 var _ webhook.CustomValidator = &ModelValidator{}
@@ -84,11 +164,36 @@ func (v *ModelValidator) ValidateCreate(ctx context.Context, obj runtime.Object)
 		return nil, err
 	}
 
+	if err := v.validateTemperature(ctx, model); err != nil {
+		return nil, err
+	}
+
+	if v.ProbeConnectivity {
+		if err := v.probeConnectivity(ctx, model); err != nil {
+			return nil, err
+		}
+	}
+
 	modellog.Info("Model validation complete", "name", model.GetName())
 
 	return nil, nil
 }
 
+// probeConnectivity sends a one token completion with the resolved credentials of the model
+func (v *ModelValidator) probeConnectivity(ctx context.Context, model *arkv1alpha1.Model) error {
+	resolved, err := genai.MakeModel(ctx, v.Client, model, noop.NewModelRecorder())
+	if err != nil {
+		return fmt.Errorf("failed to load model configuration: %w", err)
+	}
+
+	result := genai.ProbeModelWithTimeout(ctx, resolved, modelProbeTimeout)
+	if !result.Available {
+		modellog.Error(result.DetailedError, "Model connectivity probe failed", "name", model.GetName())
+		return fmt.Errorf("model connectivity probe failed: %s", result.Message)
+	}
+	return nil
+}
+
 func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	switch model.Spec.Type {
 	case genai.ModelTypeAzure:
@@ -97,11 +202,41 @@ func (v *ModelValidator) validateProviderConfig(ctx context.Context, model *arkv
 		return v.validateOpenAIConfig(ctx, model)
 	case genai.ModelTypeBedrock:
 		return v.validateBedrockConfig(ctx, model)
+	case genai.ModelTypeMock:
+		return v.validateMockConfig(ctx, model)
+	case genai.ModelTypeEmbedding:
+		return v.validateEmbeddingConfig(ctx, model)
 	default:
 		return fmt.Errorf("unsupported model type: %s", model.Spec.Type)
 	}
 }
 
+// validateTemperature enforces the namespace temperature bounds on temperatures set as direct values
+func (v *ModelValidator) validateTemperature(ctx context.Context, model *arkv1alpha1.Model) error {
+	var temperature string
+	config := model.Spec.Config
+	switch {
+	case model.Spec.Type == genai.ModelTypeAzure && config.Azure != nil:
+		temperature = config.Azure.Properties["temperature"].Value
+	case model.Spec.Type == genai.ModelTypeOpenAI && config.OpenAI != nil:
+		temperature = config.OpenAI.Properties["temperature"].Value
+	case model.Spec.Type == genai.ModelTypeBedrock && config.Bedrock != nil && config.Bedrock.Temperature != nil:
+		temperature = *config.Bedrock.Temperature
+	}
+	if temperature == "" {
+		return nil
+	}
+
+	defaults, err := genai.GetNamespaceDefaults(ctx, v.Client, model.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if defaults.ModelTemperatureMin == nil && defaults.ModelTemperatureMax == nil {
+		return nil
+	}
+	return defaults.CheckTemperature(temperature)
+}
+
 func (v *ModelValidator) validateAzureConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	if model.Spec.Config.Azure == nil {
 		return fmt.Errorf("azure configuration is required for azure model type")
@@ -110,8 +245,15 @@ func (v *ModelValidator) validateAzureConfig(ctx context.Context, model *arkv1al
 	if err := v.validateValueSource(ctx, &model.Spec.Config.Azure.BaseURL, model.GetNamespace(), "spec.config.azure.baseUrl"); err != nil {
 		return err
 	}
-	if err := v.validateValueSource(ctx, &model.Spec.Config.Azure.APIKey, model.GetNamespace(), "spec.config.azure.apiKey"); err != nil {
-		return err
+	switch azure := model.Spec.Config.Azure; {
+	case azure.APIKey != nil && azure.ManagedIdentity != nil:
+		return fmt.Errorf("spec.config.azure: only one of apiKey and managedIdentity can be set")
+	case azure.APIKey != nil:
+		if err := v.validateValueSource(ctx, azure.APIKey, model.GetNamespace(), "spec.config.azure.apiKey"); err != nil {
+			return err
+		}
+	case azure.ManagedIdentity == nil:
+		return fmt.Errorf("spec.config.azure: one of apiKey and managedIdentity is required")
 	}
 	if model.Spec.Config.Azure.APIVersion != nil {
 		if err := v.validateValueSource(ctx, model.Spec.Config.Azure.APIVersion, model.GetNamespace(), "spec.config.azure.apiVersion"); err != nil {
@@ -149,6 +291,45 @@ func (v *ModelValidator) validateOpenAIConfig(ctx context.Context, model *arkv1a
 	return nil
 }
 
+// validateEmbeddingConfig checks the provider configuration of an embedding model, which is one
+// of openai and azure
+func (v *ModelValidator) validateEmbeddingConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	config := model.Spec.Config
+	if config.Bedrock != nil || config.Mock != nil {
+		return fmt.Errorf("embedding models support only the openai and azure configurations")
+	}
+	switch {
+	case config.OpenAI != nil && config.Azure != nil:
+		return fmt.Errorf("embedding models require exactly one of the openai and azure configurations")
+	case config.OpenAI != nil:
+		return v.validateOpenAIConfig(ctx, model)
+	case config.Azure != nil:
+		return v.validateAzureConfig(ctx, model)
+	default:
+		return fmt.Errorf("openai or azure configuration is required for embedding model type")
+	}
+}
+
+func (v *ModelValidator) validateMockConfig(ctx context.Context, model *arkv1alpha1.Model) error {
+	if model.Spec.Config.Mock == nil {
+		return fmt.Errorf("mock configuration is required for mock model type")
+	}
+
+	if err := v.validateValueSource(ctx, &model.Spec.Config.Mock.Script, model.GetNamespace(), "spec.config.mock.script"); err != nil {
+		return err
+	}
+
+	script, err := v.Resolver.ResolveValueSource(ctx, model.Spec.Config.Mock.Script, model.GetNamespace())
+	if err != nil {
+		return fmt.Errorf("failed to resolve mock script: %w", err)
+	}
+	if _, err := genai.ParseMockScript(script); err != nil {
+		return fmt.Errorf("spec.config.mock.script: %w", err)
+	}
+
+	return nil
+}
+
 func (v *ModelValidator) validateBedrockConfig(ctx context.Context, model *arkv1alpha1.Model) error {
 	if model.Spec.Config.Bedrock == nil {
 		return fmt.Errorf("bedrock configuration is required for bedrock model type")
@@ -179,12 +360,42 @@ func (v *ModelValidator) validateBedrockConfig(ctx context.Context, model *arkv1
 			return err
 		}
 	}
+	if model.Spec.Config.Bedrock.InferenceProfileArn != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Bedrock.InferenceProfileArn, model.GetNamespace(), "spec.config.bedrock.inferenceProfileArn"); err != nil {
+			return err
+		}
+	}
+	if model.Spec.Config.Bedrock.RoleArn != nil {
+		if err := v.validateValueSource(ctx, model.Spec.Config.Bedrock.RoleArn, model.GetNamespace(), "spec.config.bedrock.roleArn"); err != nil {
+			return err
+		}
+	}
+	if (model.Spec.Config.Bedrock.AccessKeyID == nil) != (model.Spec.Config.Bedrock.SecretAccessKey == nil) {
+		return fmt.Errorf("spec.config.bedrock.accessKeyId and spec.config.bedrock.secretAccessKey must be set together")
+	}
+	for i, region := range model.Spec.Config.Bedrock.FailoverRegions {
+		if region == "" {
+			return fmt.Errorf("spec.config.bedrock.failoverRegions[%d]: region cannot be empty", i)
+		}
+	}
 
 	return nil
 }
 
 func (v *ModelValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
-	return v.ValidateCreate(ctx, newObj)
+	oldModel, ok := oldObj.(*arkv1alpha1.Model)
+	if !ok {
+		return nil, fmt.Errorf("expected a Model object but got %T", oldObj)
+	}
+	newModel, ok := newObj.(*arkv1alpha1.Model)
+	if !ok {
+		return nil, fmt.Errorf("expected a Model object but got %T", newObj)
+	}
+
+	// Only probe again when the spec changed, not on label or annotation updates
+	validator := *v
+	validator.ProbeConnectivity = v.ProbeConnectivity && !equality.Semantic.DeepEqual(oldModel.Spec, newModel.Spec)
+	return validator.ValidateCreate(ctx, newModel)
 }
 
 func (v *ModelValidator) ValidateDelete(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
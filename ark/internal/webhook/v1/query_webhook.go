@@ -5,13 +5,28 @@ package v1
 import (
 	"context"
 	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
 
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
 )
 
 const (
@@ -24,10 +39,95 @@ const (
 // SetupQueryWebhookWithManager registers the webhook for Query in the manager.
 func SetupQueryWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.Query{}).
-		WithValidator(&QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}}).
+		WithDefaulter(&QueryCustomDefaulter{Client: mgr.GetClient()}).
+		WithValidator(&QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: mgr.GetClient()}, Reader: mgr.GetAPIReader()}).
 		Complete()
 }
 
+// +kubebuilder:webhook:path=/mutate-ark-mckinsey-com-v1alpha1-query,mutating=true,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=queries,verbs=create,versions=v1alpha1,name=mquery-v1.kb.io,admissionReviewVersions=v1
+
+// QueryCustomDefaulter applies the namespace defaults from the ark-config-defaults ConfigMap to new queries.
+// Defaults are only applied on create so that running queries are not changed when the ConfigMap changes.
+type QueryCustomDefaulter struct {
+	Client client.Client
+}
+
+var _ webhook.CustomDefaulter = &QueryCustomDefaulter{}
+
+func (d *QueryCustomDefaulter) Default(ctx context.Context, obj runtime.Object) error {
+	query, ok := obj.(*arkv1alpha1.Query)
+	if !ok {
+		return fmt.Errorf("expected a Query object but got %T", obj)
+	}
+
+	defaults, err := genai.GetNamespaceDefaults(ctx, d.Client, query.Namespace)
+	if err != nil {
+		return err
+	}
+
+	if query.Spec.TTL == nil {
+		ttl := arkv1alpha1.DefaultQueryTTL
+		if defaults.QueryTTL != nil {
+			ttl = *defaults.QueryTTL
+		}
+		query.Spec.TTL = &metav1.Duration{Duration: ttl}
+	}
+
+	if query.Spec.TTLAfterCompletion == nil && defaults.QueryTTLAfterCompletion != nil {
+		query.Spec.TTLAfterCompletion = &metav1.Duration{Duration: *defaults.QueryTTLAfterCompletion}
+	}
+
+	if query.Spec.Timeout == nil {
+		timeout := arkv1alpha1.DefaultQueryTimeout
+		if defaults.QueryTimeout != nil {
+			timeout = *defaults.QueryTimeout
+		}
+		query.Spec.Timeout = &metav1.Duration{Duration: timeout}
+	}
+
+	if query.Spec.SLO == nil && defaults.QuerySLO != nil {
+		query.Spec.SLO = defaults.QuerySLO.DeepCopy()
+	}
+
+	if query.Spec.ServiceAccount == "" && query.Spec.Impersonate == nil {
+		query.Spec.ServiceAccount = defaults.QueryServiceAccount
+	}
+
+	if query.Spec.DataPolicy == "" {
+		query.Spec.DataPolicy = defaults.QueryDataPolicy
+	}
+
+	// Label the query with its idempotency key, so earlier submissions are found by a label selector
+	if query.Spec.IdempotencyKey != "" {
+		if query.Labels == nil {
+			query.Labels = map[string]string{}
+		}
+		query.Labels[annotations.IdempotencyKey] = arkv1alpha1.IdempotencyKeyLabelValue(query.Spec.IdempotencyKey)
+	} else {
+		delete(query.Labels, annotations.IdempotencyKey)
+	}
+
+	// Fill in the defaults of the parameter schema. Parameters that do not match the schema are
+	// left for the validator to reject.
+	if len(query.Spec.ParameterSchema) > 0 {
+		if params, err := arkv1alpha1.ApplyParameterSchema(query.Spec.ParameterSchema, query.Spec.Parameters); err == nil {
+			query.Spec.Parameters = params
+		}
+	}
+
+	// Record the requesting user for the query audit log, replacing any client-supplied value
+	if req, err := admission.RequestFromContext(ctx); err == nil {
+		if query.Annotations == nil {
+			query.Annotations = map[string]string{}
+		}
+		query.Annotations[annotations.CreatedBy] = req.UserInfo.Username
+	}
+
+	return nil
+}
+
+// +kubebuilder:rbac:groups="",resources=serviceaccounts,verbs=get;list;watch
+// +kubebuilder:rbac:groups=authorization.k8s.io,resources=subjectaccessreviews,verbs=create
 // +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-query,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=queries,verbs=create;update,versions=v1alpha1,name=vquery-v1.kb.io,admissionReviewVersions=v1
 
 // QueryCustomValidator struct is responsible for validating the Query resource
@@ -37,6 +137,9 @@ func SetupQueryWebhookWithManager(mgr ctrl.Manager) error {
 // as this struct is used only for temporary operations and does not need to be deeply copied.
 type QueryCustomValidator struct {
 	*ResourceValidator
+	// Reader reads queries from the API server instead of the cache, so queries created just before
+	// are seen. Client is used when nil
+	Reader client.Reader
 }
 
 var _ webhook.CustomValidator = &QueryCustomValidator{}
@@ -48,15 +151,82 @@ func (v *QueryCustomValidator) ValidateCreate(ctx context.Context, obj runtime.O
 	}
 	log.V(3).Info("Validate create", "query", query.ObjectMeta)
 
+	if err := v.authorizeImpersonation(ctx, query.Spec.Impersonate); err != nil {
+		return nil, err
+	}
+
+	if err := v.checkIdempotencyKey(ctx, query); err != nil {
+		return nil, err
+	}
+
 	return v.validateQuery(ctx, query)
 }
 
+// checkIdempotencyKey rejects a query whose idempotency key was used by a query created within the
+// idempotency window. The error is a conflict naming that query in its details, so submitters can
+// return it instead of a duplicate.
+func (v *QueryCustomValidator) checkIdempotencyKey(ctx context.Context, query *arkv1alpha1.Query) error {
+	if query.Spec.IdempotencyKey == "" {
+		return nil
+	}
+
+	defaults, err := genai.GetNamespaceDefaults(ctx, v.Client, query.Namespace)
+	if err != nil {
+		return err
+	}
+	window := arkv1alpha1.DefaultQueryIdempotencyWindow
+	if defaults.QueryIdempotencyWindow != nil {
+		window = *defaults.QueryIdempotencyWindow
+	}
+
+	var reader client.Reader = v.Client
+	if v.Reader != nil {
+		reader = v.Reader
+	}
+	var queries arkv1alpha1.QueryList
+	if err := reader.List(ctx, &queries, client.InNamespace(query.Namespace),
+		client.MatchingLabels{annotations.IdempotencyKey: arkv1alpha1.IdempotencyKeyLabelValue(query.Spec.IdempotencyKey)}); err != nil {
+		return fmt.Errorf("failed to list queries with idempotencyKey: %w", err)
+	}
+	for _, existing := range queries.Items {
+		if existing.Name == query.Name || existing.Spec.IdempotencyKey != query.Spec.IdempotencyKey ||
+			!existing.DeletionTimestamp.IsZero() || time.Since(existing.CreationTimestamp.Time) > window {
+			continue
+		}
+		return &apierrors.StatusError{ErrStatus: metav1.Status{
+			Status:  metav1.StatusFailure,
+			Code:    http.StatusConflict,
+			Reason:  metav1.StatusReasonAlreadyExists,
+			Message: fmt.Sprintf("query %s was already created with idempotencyKey '%s'", existing.Name, query.Spec.IdempotencyKey),
+			Details: &metav1.StatusDetails{
+				Group:  arkv1alpha1.GroupVersion.Group,
+				Kind:   "queries",
+				Name:   existing.Name,
+				Causes: []metav1.StatusCause{{Type: metav1.CauseTypeFieldValueDuplicate, Field: "spec.idempotencyKey"}},
+			},
+		}}
+	}
+	return nil
+}
+
 func (v *QueryCustomValidator) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
 	query, ok := newObj.(*arkv1alpha1.Query)
 	if !ok {
 		return nil, fmt.Errorf("expected a Query object for the newObj but got %T", newObj)
 	}
 	log.V(3).Info("Validate update", "query", query.ObjectMeta)
+	oldQuery, ok := oldObj.(*arkv1alpha1.Query)
+	if !ok {
+		return nil, fmt.Errorf("expected a Query object for the oldObj but got %T", oldObj)
+	}
+	if oldQuery.Annotations[annotations.CreatedBy] != query.Annotations[annotations.CreatedBy] {
+		return nil, fmt.Errorf("annotation %s cannot be changed", annotations.CreatedBy)
+	}
+	if !equality.Semantic.DeepEqual(oldQuery.Spec.Impersonate, query.Spec.Impersonate) {
+		if err := v.authorizeImpersonation(ctx, query.Spec.Impersonate); err != nil {
+			return nil, err
+		}
+	}
 	if query.DeletionTimestamp.IsZero() {
 		return v.validateQuery(ctx, query)
 	}
@@ -80,40 +250,468 @@ func (v *QueryCustomValidator) validateQuery(ctx context.Context, query *arkv1al
 		return warnings, err
 	}
 
+	selectorWarnings, err := v.validateQuerySelector(ctx, query)
+	if err != nil {
+		return warnings, err
+	}
+	warnings = append(warnings, selectorWarnings...)
+
 	if err := v.ValidateParameters(ctx, query.Namespace, query.Spec.Parameters); err != nil {
 		return warnings, err
 	}
 
+	if err := validateParameterSchema(query); err != nil {
+		return warnings, err
+	}
+
+	inputWarnings, err := v.validateQueryInput(ctx, query)
+	if err != nil {
+		return warnings, err
+	}
+	warnings = append(warnings, inputWarnings...)
+
+	if err := v.validateQueryParts(ctx, query); err != nil {
+		return warnings, err
+	}
+
+	if err := v.validateQueryModelParameters(ctx, query); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidateLoadServiceAccount(ctx, query.Spec.ServiceAccount, query.Namespace); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidateGuardrailRefs(ctx, query.Spec.Guardrails, query.Namespace); err != nil {
+		return warnings, err
+	}
+
+	if err := v.ValidatePostProcessors(ctx, query.Spec.PostProcessors, query.Namespace); err != nil {
+		return warnings, err
+	}
+
 	return warnings, nil
 }
 
+// authorizeImpersonation checks that the caller may run a query as the identity in
+// spec.impersonate. Callers may run queries as themselves, otherwise they need permission to
+// impersonate the user, each group and the uid, as for kubectl --as.
+func (v *QueryCustomValidator) authorizeImpersonation(ctx context.Context, impersonate *arkv1alpha1.QueryImpersonation) error {
+	if impersonate == nil {
+		return nil
+	}
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot check permission for spec.impersonate: %w", err)
+	}
+	caller := req.UserInfo
+	if isCallerIdentity(caller, impersonate) {
+		return nil
+	}
+
+	checks := []authorizationv1.ResourceAttributes{{Verb: "impersonate", Resource: "users", Name: impersonate.User}}
+	for _, group := range impersonate.Groups {
+		checks = append(checks, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "groups", Name: group})
+	}
+	if impersonate.UID != "" {
+		checks = append(checks, authorizationv1.ResourceAttributes{Verb: "impersonate", Group: "authentication.k8s.io", Resource: "uids", Name: impersonate.UID})
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(caller.Extra))
+	for key, value := range caller.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	for _, check := range checks {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               caller.Username,
+				UID:                caller.UID,
+				Groups:             caller.Groups,
+				Extra:              extra,
+				ResourceAttributes: &check,
+			},
+		}
+		if err := v.Client.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to check permission for spec.impersonate: %w", err)
+		}
+		if !review.Status.Allowed {
+			return fmt.Errorf("user %s cannot impersonate %s %s required by spec.impersonate", caller.Username, check.Resource, check.Name)
+		}
+	}
+	return nil
+}
+
+// authorizeInputSource checks that the caller can get the ConfigMap or Secret an inputFrom reads, so
+// queries cannot read objects their creator cannot, and the webhook does not report whether objects
+// the caller cannot read exist
+func (v *QueryCustomValidator) authorizeInputSource(ctx context.Context, resource, name, namespace string) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot check permission for spec.inputFrom: %w", err)
+	}
+	caller := req.UserInfo
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(caller.Extra))
+	for key, value := range caller.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   caller.Username,
+			UID:    caller.UID,
+			Groups: caller.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Verb: "get", Resource: resource, Namespace: namespace, Name: name,
+			},
+		},
+	}
+	if err := v.Client.Create(ctx, review); err != nil {
+		return fmt.Errorf("failed to check permission for spec.inputFrom: %w", err)
+	}
+	if !review.Status.Allowed {
+		return fmt.Errorf("user %s cannot get %s %s in namespace %s required by spec.inputFrom", caller.Username, resource, name, namespace)
+	}
+	return nil
+}
+
+// isCallerIdentity reports whether impersonate names the caller, or the caller with fewer groups
+func isCallerIdentity(caller authenticationv1.UserInfo, impersonate *arkv1alpha1.QueryImpersonation) bool {
+	if impersonate.User != caller.Username || (impersonate.UID != "" && impersonate.UID != caller.UID) {
+		return false
+	}
+	for _, group := range impersonate.Groups {
+		if !slices.Contains(caller.Groups, group) {
+			return false
+		}
+	}
+	return true
+}
+
 func (v *QueryCustomValidator) validateQueryTargets(ctx context.Context, query *arkv1alpha1.Query) error {
 	if len(query.Spec.Targets) == 0 && query.Spec.Selector == nil {
 		return fmt.Errorf("at least one target or selector must be specified")
 	}
 
 	for i, target := range query.Spec.Targets {
+		if target.Cluster != "" {
+			if err := v.validateRemoteTarget(ctx, query, i, target); err != nil {
+				return err
+			}
+			continue
+		}
+		namespace := target.Namespace
+		if namespace == "" {
+			namespace = query.Namespace
+		}
 		switch target.Type {
 		case TargetTypeAgent:
-			if err := v.ValidateLoadAgent(ctx, target.Name, query.Namespace); err != nil {
+			if err := v.ValidateLoadAgent(ctx, target.Name, namespace); err != nil {
 				return fmt.Errorf("target[%d] references %v", i, err)
 			}
 		case TargetTypeTeam:
-			if err := v.ValidateLoadTeam(ctx, target.Name, query.Namespace); err != nil {
+			if err := v.ValidateLoadTeam(ctx, target.Name, namespace); err != nil {
 				return fmt.Errorf("target[%d] references %v", i, err)
 			}
 		case TargetTypeModel:
-			if err := v.ValidateLoadModel(ctx, target.Name, query.Namespace); err != nil {
+			if err := v.ValidateLoadModel(ctx, target.Name, namespace); err != nil {
 				return fmt.Errorf("target[%d] references %v", i, err)
 			}
+			if err := v.ValidateChatModel(ctx, target.Name, namespace); err != nil {
+				return fmt.Errorf("target[%d]: %v", i, err)
+			}
 		case TargetTypeTool:
-			if err := v.ValidateLoadTool(ctx, target.Name, query.Namespace); err != nil {
+			if err := v.ValidateLoadTool(ctx, target.Name, namespace); err != nil {
 				return fmt.Errorf("target[%d] references %v", i, err)
 			}
 		default:
 			return fmt.Errorf("target[%d]: unsupported type '%s': supported types are: %s, %s, %s, %s", i, target.Type, TargetTypeAgent, TargetTypeTeam, TargetTypeModel, TargetTypeTool)
 		}
+		if err := genai.CheckReferenceGrant(ctx, v.Client, arkv1alpha1.ReferenceFromQuery, query.Namespace, target.Kind(), target.Name, namespace); err != nil {
+			return fmt.Errorf("target[%d]: %v", i, err)
+		}
+	}
+
+	return nil
+}
+
+// validateQueryModelParameters checks the model parameters against the limits of the models the
+// query calls directly or through its agent targets
+func (v *QueryCustomValidator) validateQueryModelParameters(ctx context.Context, query *arkv1alpha1.Query) error {
+	parameters := query.Spec.ModelParameters
+	if err := v.ValidateModelParameters(ctx, parameters, "", query.Namespace); err != nil || parameters == nil {
+		return err
+	}
+
+	for i, target := range query.Spec.Targets {
+		if target.Cluster != "" {
+			continue
+		}
+		namespace := target.Namespace
+		if namespace == "" {
+			namespace = query.Namespace
+		}
+		modelName, modelNamespace := "", namespace
+		switch target.Type {
+		case TargetTypeModel:
+			modelName = target.Name
+		case TargetTypeAgent:
+			agent := &arkv1alpha1.Agent{}
+			if err := v.Client.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: namespace}, agent); err != nil || agent.Spec.ModelRef == nil {
+				continue
+			}
+			modelName = agent.Spec.ModelRef.Name
+			if agent.Spec.ModelRef.Namespace != "" {
+				modelNamespace = agent.Spec.ModelRef.Namespace
+			}
+		default:
+			continue
+		}
+		if err := v.ValidateModelParameters(ctx, parameters, modelName, modelNamespace); err != nil {
+			return fmt.Errorf("target[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// validateRemoteTarget checks that the remote cluster of a target is registered and allows the
+// target namespace. The target itself exists in the remote cluster and is only resolved when the
+// query runs.
+func (v *QueryCustomValidator) validateRemoteTarget(ctx context.Context, query *arkv1alpha1.Query, i int, target arkv1alpha1.QueryTarget) error {
+	switch target.Type {
+	case TargetTypeAgent, TargetTypeTeam, TargetTypeModel, TargetTypeTool:
+	default:
+		return fmt.Errorf("target[%d]: unsupported type '%s': supported types are: %s, %s, %s, %s", i, target.Type, TargetTypeAgent, TargetTypeTeam, TargetTypeModel, TargetTypeTool)
+	}
+	if err := v.ValidateLoadRemoteCluster(ctx, target.Cluster, query.Namespace); err != nil {
+		return fmt.Errorf("target[%d] references %v", i, err)
+	}
+	cluster := &arkv1alpha1.RemoteCluster{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: target.Cluster, Namespace: query.Namespace}, cluster); err != nil {
+		return fmt.Errorf("target[%d]: %v", i, err)
+	}
+	if _, err := genai.RemoteTargetNamespace(cluster, target); err != nil {
+		return fmt.Errorf("target[%d]: %v", i, err)
+	}
+	return nil
+}
+
+// validateQuerySelector rejects malformed selectors and warns when a selector matches nothing yet,
+// since matching resources may still be created before the query runs.
+func (v *QueryCustomValidator) validateQuerySelector(ctx context.Context, query *arkv1alpha1.Query) (admission.Warnings, error) {
+	if query.Spec.Selector == nil {
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&query.Spec.Selector.LabelSelector)
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector: %v", err)
+	}
+
+	lists := []struct {
+		kind    arkv1alpha1.TargetKind
+		newList func() client.ObjectList
+	}{
+		{arkv1alpha1.TargetKindAgent, func() client.ObjectList { return &arkv1alpha1.AgentList{} }},
+		{arkv1alpha1.TargetKindTeam, func() client.ObjectList { return &arkv1alpha1.TeamList{} }},
+		{arkv1alpha1.TargetKindModel, func() client.ObjectList { return &arkv1alpha1.ModelList{} }},
+		{arkv1alpha1.TargetKindTool, func() client.ObjectList { return &arkv1alpha1.ToolList{} }},
+	}
+	namespaces := query.Spec.Selector.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{query.Namespace}
+	}
+
+	for _, namespace := range namespaces {
+		for _, list := range lists {
+			if !query.Spec.Selector.SelectsKind(list.kind) {
+				continue
+			}
+			matched, err := v.selectorMatches(ctx, list.newList(), selector, namespace)
+			if err != nil {
+				return nil, err
+			}
+			if matched {
+				return nil, nil
+			}
+		}
+	}
+
+	return admission.Warnings{fmt.Sprintf("selector does not match any %s in namespace '%s'", selectedKindsDescription(query.Spec.Selector), strings.Join(namespaces, "', '"))}, nil
+}
+
+// selectedKindsDescription describes the kinds a selector matches, e.g. "agents or teams"
+func selectedKindsDescription(selector *arkv1alpha1.TargetSelector) string {
+	if len(selector.Kinds) == 0 {
+		return "agents, teams, models or tools"
+	}
+	kinds := make([]string, len(selector.Kinds))
+	for i, kind := range selector.Kinds {
+		kinds[i] = strings.ToLower(string(kind)) + "s"
+	}
+	return strings.Join(kinds, " or ")
+}
+
+func (v *QueryCustomValidator) selectorMatches(ctx context.Context, list client.ObjectList, selector labels.Selector, namespace string) (bool, error) {
+	if err := v.Client.List(ctx, list, client.InNamespace(namespace), client.MatchingLabelsSelector{Selector: selector}, client.Limit(1)); err != nil {
+		return false, fmt.Errorf("failed to evaluate selector: %v", err)
+	}
+	return meta.LenList(list) > 0, nil
+}
+
+// validateQueryInput rejects inputs that do not match the query type and lints templated inputs.
+// Inputs read from inputFrom are only checked when the query runs, after their source exists.
+func (v *QueryCustomValidator) validateQueryInput(ctx context.Context, query *arkv1alpha1.Query) (admission.Warnings, error) {
+	if source := query.Spec.InputFrom; source != nil {
+		if len(query.Spec.Input.Raw) > 0 && string(query.Spec.Input.Raw) != "null" {
+			return nil, fmt.Errorf("input and inputFrom cannot both be set")
+		}
+		if err := genai.ValidateQueryInputSource(source); err != nil {
+			return nil, err
+		}
+		// The caller must be allowed to read the source before its existence is reported
+		if source.ConfigMapKeyRef != nil {
+			if err := v.authorizeInputSource(ctx, "configmaps", source.ConfigMapKeyRef.Name, query.Namespace); err != nil {
+				return nil, err
+			}
+			return nil, v.ValidateLoadConfigMapKey(ctx, source.ConfigMapKeyRef.Name, query.Namespace, source.ConfigMapKeyRef.Key)
+		}
+		if source.SecretKeyRef != nil {
+			if err := v.authorizeInputSource(ctx, "secrets", source.SecretKeyRef.Name, query.Namespace); err != nil {
+				return nil, err
+			}
+			return nil, v.ValidateLoadSecretKey(ctx, source.SecretKeyRef.Name, query.Namespace, source.SecretKeyRef.Key)
+		}
+		return nil, nil
+	}
+
+	if query.Spec.Type == arkv1alpha1.QueryTypeMessages {
+		if _, err := query.Spec.GetInputMessages(); err != nil {
+			return nil, fmt.Errorf("invalid input: %v", err)
+		}
+		return nil, nil
+	}
+
+	input, err := query.Spec.GetInputString()
+	if err != nil {
+		return nil, fmt.Errorf("invalid input: %v", err)
+	}
+
+	parameters := query.Spec.Parameters
+	if len(query.Spec.ParameterSchema) > 0 {
+		// Every declared parameter resolves, to its value, its default or an empty string
+		parameters = make([]arkv1alpha1.Parameter, len(query.Spec.ParameterSchema))
+		for i, declared := range query.Spec.ParameterSchema {
+			parameters[i].Name = declared.Name
+		}
+	}
+	if len(parameters) == 0 {
+		return nil, nil
+	}
+
+	return ValidateTemplate("input", input, parameters)
+}
+
+// validateParameterSchema rejects defaults that do not match the type and enum of their parameter,
+// and parameters that do not match the schema
+func validateParameterSchema(query *arkv1alpha1.Query) error {
+	if len(query.Spec.ParameterSchema) == 0 {
+		return nil
+	}
+	for i, declared := range query.Spec.ParameterSchema {
+		if declared.Default == "" {
+			continue
+		}
+		if err := declared.ValidateValue(declared.Default); err != nil {
+			return fmt.Errorf("parameterSchema[%d]: invalid default: %v", i, err)
+		}
+	}
+	if _, err := arkv1alpha1.ApplyParameterSchema(query.Spec.ParameterSchema, query.Spec.Parameters); err != nil {
+		return fmt.Errorf("invalid parameters: %v", err)
+	}
+	return nil
+}
+
+// validateQueryParts checks the ConfigMaps of the input parts and rejects images or files for model
+// and agent targets whose model does not declare the capability for them
+func (v *QueryCustomValidator) validateQueryParts(ctx context.Context, query *arkv1alpha1.Query) error {
+	if len(query.Spec.Parts) == 0 {
+		return nil
+	}
+	if query.Spec.Type == arkv1alpha1.QueryTypeMessages {
+		return fmt.Errorf("parts can only be used with type %s", arkv1alpha1.QueryTypeUser)
+	}
+
+	var needed []arkv1alpha1.ModelCapability
+	for i, part := range query.Spec.Parts {
+		switch part.Type {
+		case arkv1alpha1.QueryInputPartImage:
+			needed = append(needed, arkv1alpha1.ModelCapabilityVision)
+		case arkv1alpha1.QueryInputPartFile:
+			needed = append(needed, arkv1alpha1.ModelCapabilityFiles)
+		}
+		if part.ValueFrom != nil && part.ValueFrom.ConfigMapKeyRef != nil {
+			if err := v.ValidateLoadConfigMap(ctx, part.ValueFrom.ConfigMapKeyRef.Name, query.Namespace); err != nil {
+				return fmt.Errorf("parts[%d] references %v", i, err)
+			}
+		}
+	}
+	if len(needed) == 0 {
+		return nil
 	}
 
+	for i, target := range query.Spec.Targets {
+		namespace := target.Namespace
+		if namespace == "" {
+			namespace = query.Namespace
+		}
+		modelName, modelNamespace, err := v.targetModel(ctx, target, namespace)
+		if err != nil {
+			return fmt.Errorf("target[%d] references %v", i, err)
+		}
+		if modelName == "" {
+			continue
+		}
+
+		model := &arkv1alpha1.Model{}
+		if err := v.Client.Get(ctx, types.NamespacedName{Name: modelName, Namespace: modelNamespace}, model); err != nil {
+			// Agents may reference models that are created later, the query fails when it runs
+			continue
+		}
+		for _, capability := range needed {
+			if !model.Spec.HasCapability(capability) {
+				return fmt.Errorf("target[%d]: model '%s' does not support %s input", i, modelName, capability)
+			}
+		}
+	}
 	return nil
 }
+
+// targetModel returns the model a model or agent target runs on. Teams and tools return no model,
+// and agents with an execution engine are left to the engine.
+func (v *QueryCustomValidator) targetModel(ctx context.Context, target arkv1alpha1.QueryTarget, namespace string) (string, string, error) {
+	switch target.Type {
+	case TargetTypeModel:
+		return target.Name, namespace, nil
+	case TargetTypeAgent:
+		agent := &arkv1alpha1.Agent{}
+		if err := v.Client.Get(ctx, types.NamespacedName{Name: target.Name, Namespace: namespace}, agent); err != nil {
+			return "", "", fmt.Errorf("agent '%s' does not exist in namespace '%s': %v", target.Name, namespace, err)
+		}
+		if agent.Spec.ExecutionEngine != nil {
+			return "", "", nil
+		}
+		if agent.Spec.ModelRef == nil {
+			defaults, err := genai.GetNamespaceDefaults(ctx, v.Client, namespace)
+			if err != nil {
+				return "", "", err
+			}
+			return defaults.Model, namespace, nil
+		}
+		if agent.Spec.ModelRef.Namespace != "" {
+			return agent.Spec.ModelRef.Name, agent.Spec.ModelRef.Namespace, nil
+		}
+		return agent.Spec.ModelRef.Name, namespace, nil
+	}
+	return "", "", nil
+}
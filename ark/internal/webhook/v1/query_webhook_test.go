@@ -3,54 +3,645 @@
 package v1
 
 import (
+	"context"
+	"errors"
+	"time"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	admissionv1 "k8s.io/api/admission/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
-	// TODO (user): Add any additional imports if needed
+	"mckinsey.com/ark/internal/annotations"
+	"mckinsey.com/ark/internal/genai"
 )
 
 var _ = Describe("Query Webhook", func() {
 	var (
-		obj       *arkv1alpha1.Query
-		oldObj    *arkv1alpha1.Query
-		validator QueryCustomValidator
+		ctx        context.Context
+		query      *arkv1alpha1.Query
+		validator  *QueryCustomValidator
+		fakeClient client.Client
 	)
 
 	BeforeEach(func() {
-		obj = &arkv1alpha1.Query{}
-		oldObj = &arkv1alpha1.Query{}
-		validator = QueryCustomValidator{}
-		Expect(validator).NotTo(BeNil(), "Expected validator to be initialized")
-		Expect(oldObj).NotTo(BeNil(), "Expected oldObj to be initialized")
-		Expect(obj).NotTo(BeNil(), "Expected obj to be initialized")
-		// TODO (user): Add any setup logic common to all tests
-	})
-
-	AfterEach(func() {
-		// TODO (user): Add any teardown logic common to all tests
-	})
-
-	Context("When creating or updating Query under Validating Webhook", func() {
-		// TODO (user): Add logic for validating webhooks
-		// Example:
-		// It("Should deny creation if a required field is missing", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = ""
-		//     Expect(validator.ValidateCreate(ctx, obj)).Error().To(HaveOccurred())
-		// })
-		//
-		// It("Should admit creation if all required fields are present", func() {
-		//     By("simulating an invalid creation scenario")
-		//     obj.SomeRequiredField = "valid_value"
-		//     Expect(validator.ValidateCreate(ctx, obj)).To(BeNil())
-		// })
-		//
-		// It("Should validate updates correctly", func() {
-		//     By("simulating a valid update scenario")
-		//     oldObj.SomeRequiredField = "updated_value"
-		//     obj.SomeRequiredField = "updated_value"
-		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
-		// })
+		ctx = context.Background()
+
+		s := runtime.NewScheme()
+		Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+		Expect(corev1.AddToScheme(s)).To(Succeed())
+
+		agent := &arkv1alpha1.Agent{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-agent",
+				Namespace: "default",
+				Labels:    map[string]string{"role": "assistant"},
+			},
+		}
+		serviceAccount := &corev1.ServiceAccount{
+			ObjectMeta: metav1.ObjectMeta{Name: "query-runner", Namespace: "default"},
+		}
+		fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(agent, serviceAccount).Build()
+
+		validator = &QueryCustomValidator{
+			ResourceValidator: &ResourceValidator{Client: fakeClient},
+		}
+
+		query = &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "test-query",
+				Namespace: "default",
+			},
+			Spec: arkv1alpha1.QuerySpec{
+				Targets: []arkv1alpha1.QueryTarget{{Type: TargetTypeAgent, Name: "test-agent"}},
+			},
+		}
+		Expect(query.Spec.SetInputString("Hello")).To(Succeed())
+	})
+
+	Context("When validating targets", func() {
+		It("Should admit a query targeting an existing agent", func() {
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny a query targeting a nonexistent agent", func() {
+			query.Spec.Targets[0].Name = "missing-agent"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("agent 'missing-agent' does not exist")))
+		})
+
+		It("Should admit an agent of a registered remote cluster", func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.RemoteCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+			})).To(Succeed())
+			query.Spec.Targets[0] = arkv1alpha1.QueryTarget{Type: TargetTypeAgent, Name: "remote-agent", Cluster: "east"}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a remote target in a namespace the remote cluster does not allow", func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.RemoteCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "east", Namespace: "default"},
+				Spec:       arkv1alpha1.RemoteClusterSpec{AllowedNamespaces: []string{"agents"}},
+			})).To(Succeed())
+			query.Spec.Targets[0] = arkv1alpha1.QueryTarget{Type: TargetTypeAgent, Name: "remote-agent", Namespace: "agents", Cluster: "east"}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+
+			query.Spec.Targets[0].Namespace = "kube-system"
+			_, err = validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("namespace kube-system is not allowed by remote cluster east")))
+		})
+
+		It("Should deny a target of an unregistered remote cluster", func() {
+			query.Spec.Targets[0].Cluster = "west"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("remote cluster 'west' does not exist")))
+		})
+
+		It("Should deny an unknown target type", func() {
+			query.Spec.Targets[0].Type = "workflow"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("unsupported type 'workflow'")))
+		})
+
+		It("Should deny a query without targets or selector", func() {
+			query.Spec.Targets = nil
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("at least one target or selector")))
+		})
+	})
+
+	Context("When validating selectors", func() {
+		BeforeEach(func() {
+			query.Spec.Targets = nil
+		})
+
+		It("Should admit a selector matching an agent", func() {
+			query.Spec.Selector = &arkv1alpha1.TargetSelector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "assistant"}}}
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should warn when the matching resources are of another kind", func() {
+			query.Spec.Selector = &arkv1alpha1.TargetSelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "assistant"}},
+				Kinds:         []arkv1alpha1.TargetKind{arkv1alpha1.TargetKindTeam},
+			}
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("selector does not match any teams")))
+		})
+
+		It("Should warn when the matching resources are in another namespace", func() {
+			query.Spec.Selector = &arkv1alpha1.TargetSelector{
+				LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "assistant"}},
+				Namespaces:    []string{"other"},
+			}
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("in namespace 'other'")))
+		})
+
+		It("Should warn when a selector matches nothing", func() {
+			query.Spec.Selector = &arkv1alpha1.TargetSelector{LabelSelector: metav1.LabelSelector{MatchLabels: map[string]string{"role": "reviewer"}}}
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("selector does not match")))
+		})
+
+		It("Should deny a malformed selector", func() {
+			query.Spec.Selector = &arkv1alpha1.TargetSelector{LabelSelector: metav1.LabelSelector{MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: "role", Operator: "Unknown"},
+			}}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("invalid selector")))
+		})
+	})
+
+	Context("When validating input templates", func() {
+		BeforeEach(func() {
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}}
+		})
+
+		It("Should admit a template using defined parameters", func() {
+			Expect(query.Spec.SetInputString("Tell me about {{.topic}}")).To(Succeed())
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny a template that does not parse", func() {
+			Expect(query.Spec.SetInputString("Tell me about {{.topic")).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("invalid input template")))
+		})
+
+		It("Should warn on undefined parameters", func() {
+			Expect(query.Spec.SetInputString("Tell me about {{.subject}}")).To(Succeed())
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(ContainElement(ContainSubstring("subject")))
+		})
+	})
+
+	Context("When validating the parameter schema", func() {
+		BeforeEach(func() {
+			query.Spec.ParameterSchema = []arkv1alpha1.QueryTemplateParameter{
+				{Name: "topic", Required: true},
+				{Name: "days", Type: arkv1alpha1.ParameterTypeInteger, Default: "3"},
+			}
+			Expect(query.Spec.SetInputString("Forecast {{.topic}} for {{.days}} days")).To(Succeed())
+		})
+
+		It("Should admit parameters matching the schema", func() {
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}, {Name: "days", Value: "5"}}
+			warnings, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(warnings).To(BeEmpty())
+		})
+
+		It("Should deny missing, undeclared and mistyped parameters", func() {
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("parameter topic is required")))
+
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}, {Name: "city", Value: "Berlin"}}
+			_, err = validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("unknown parameter city")))
+
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}, {Name: "days", Value: "five"}}
+			_, err = validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("parameter days must be of type integer")))
+		})
+
+		It("Should deny defaults that do not match their type", func() {
+			query.Spec.ParameterSchema[1].Default = "soon"
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("parameterSchema[1]: invalid default")))
+		})
+
+		It("Should apply the defaults of the schema", func() {
+			query.Spec.Parameters = []arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}}
+			defaulter := &QueryCustomDefaulter{Client: fakeClient}
+			Expect(defaulter.Default(ctx, query)).To(Succeed())
+			Expect(query.Spec.Parameters).To(Equal([]arkv1alpha1.Parameter{{Name: "topic", Value: "weather"}, {Name: "days", Value: "3"}}))
+		})
+	})
+
+	Context("When validating inputFrom", func() {
+		var reviews []authorizationv1.SubjectAccessReviewSpec
+
+		BeforeEach(func() {
+			query.Spec.Input.Raw = nil
+			reviews = nil
+			s := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+			Expect(corev1.AddToScheme(s)).To(Succeed())
+			Expect(authorizationv1.AddToScheme(s)).To(Succeed())
+			agent := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"}}
+			// Only alice may read ConfigMaps and Secrets
+			fakeClient = fake.NewClientBuilder().WithScheme(s).WithObjects(agent).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					review, ok := obj.(*authorizationv1.SubjectAccessReview)
+					if !ok {
+						return c.Create(ctx, obj, opts...)
+					}
+					reviews = append(reviews, review.Spec)
+					review.Status.Allowed = review.Spec.User == "alice"
+					return nil
+				},
+			}).Build()
+			validator = &QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: fakeClient}}
+			ctx = admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "alice"},
+			}})
+		})
+
+		It("Should admit an input read from an existing configMap key", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: "prompts", Namespace: "default"},
+				Data:       map[string]string{"review": "Review the release notes"},
+			})).To(Succeed())
+			query.Spec.InputFrom = &arkv1alpha1.QueryInputSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prompts"}, Key: "review"},
+			}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews).To(HaveLen(1))
+			Expect(*reviews[0].ResourceAttributes).To(Equal(authorizationv1.ResourceAttributes{
+				Verb: "get", Resource: "configmaps", Namespace: "default", Name: "prompts",
+			}))
+		})
+
+		It("Should deny an input read from a nonexistent secret", func() {
+			query.Spec.InputFrom = &arkv1alpha1.QueryInputSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "prompt"},
+			}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("secret 'missing' does not exist")))
+		})
+
+		It("Should not report whether a secret exists to callers that cannot read it", func() {
+			ctx = admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "mallory"},
+			}})
+			query.Spec.InputFrom = &arkv1alpha1.QueryInputSource{
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "prompt"},
+			}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("user mallory cannot get secrets missing in namespace default")))
+		})
+
+		It("Should deny setting more than one source", func() {
+			query.Spec.InputFrom = &arkv1alpha1.QueryInputSource{
+				URL:          "https://example.com/prompt.txt",
+				SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "prompts"}, Key: "prompt"},
+			}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("exactly one of configMapKeyRef, secretKeyRef or url")))
+		})
+
+		It("Should deny setting both input and inputFrom", func() {
+			Expect(query.Spec.SetInputString("Hello")).To(Succeed())
+			query.Spec.InputFrom = &arkv1alpha1.QueryInputSource{URL: "https://example.com/prompt.txt"}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("input and inputFrom cannot both be set")))
+		})
+	})
+
+	Context("When validating service accounts", func() {
+		It("Should admit an existing service account", func() {
+			query.Spec.ServiceAccount = "query-runner"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a nonexistent service account", func() {
+			query.Spec.ServiceAccount = "missing-sa"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("serviceAccount 'missing-sa' does not exist")))
+		})
+
+		It("Should return failures to read the service account as they are", func() {
+			lookupErr := errors.New("connection refused")
+			validator.Client = interceptor.NewClient(fakeClient.(client.WithWatch), interceptor.Funcs{
+				Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+					if _, ok := obj.(*corev1.ServiceAccount); ok {
+						return lookupErr
+					}
+					return c.Get(ctx, key, obj, opts...)
+				},
+			})
+			query.Spec.ServiceAccount = "query-runner"
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(lookupErr))
+			Expect(err.Error()).NotTo(ContainSubstring("does not exist"))
+		})
+	})
+
+	Context("When validating impersonation", func() {
+		var reviews []authorizationv1.SubjectAccessReviewSpec
+
+		BeforeEach(func() {
+			reviews = nil
+			s := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+			Expect(authorizationv1.AddToScheme(s)).To(Succeed())
+			agent := &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"}}
+			// Only the gateway may impersonate others
+			reviewClient := fake.NewClientBuilder().WithScheme(s).WithObjects(agent).WithInterceptorFuncs(interceptor.Funcs{
+				Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+					review := obj.(*authorizationv1.SubjectAccessReview)
+					reviews = append(reviews, review.Spec)
+					review.Status.Allowed = review.Spec.User == "gateway"
+					return nil
+				},
+			}).Build()
+			validator = &QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: reviewClient}}
+			query.Spec.Impersonate = &arkv1alpha1.QueryImpersonation{User: "alice@example.com", Groups: []string{"analysts"}}
+		})
+
+		requestBy := func(username string, groups ...string) context.Context {
+			return admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: username, Groups: groups},
+			}})
+		}
+
+		It("Should admit callers running queries as themselves", func() {
+			_, err := validator.ValidateCreate(requestBy("alice@example.com", "analysts", "staff"), query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews).To(BeEmpty())
+		})
+
+		It("Should check that other callers may impersonate the user and groups", func() {
+			_, err := validator.ValidateCreate(requestBy("gateway"), query)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reviews).To(HaveLen(2))
+			Expect(reviews[0].ResourceAttributes.Resource).To(Equal("users"))
+			Expect(reviews[1].ResourceAttributes.Name).To(Equal("analysts"))
+
+			_, err = validator.ValidateCreate(requestBy("bob@example.com"), query)
+			Expect(err).To(MatchError(ContainSubstring("user bob@example.com cannot impersonate users alice@example.com")))
+
+			_, err = validator.ValidateCreate(requestBy("alice@example.com"), query)
+			Expect(err).To(MatchError(ContainSubstring("cannot impersonate")))
+		})
+
+		It("Should only check updates that change the identity", func() {
+			oldQuery := query.DeepCopy()
+			query.Spec.Cancel = true
+			_, err := validator.ValidateUpdate(requestBy("controller"), oldQuery, query)
+			Expect(err).NotTo(HaveOccurred())
+
+			query.Spec.Impersonate.User = "bob@example.com"
+			_, err = validator.ValidateUpdate(requestBy("controller"), oldQuery, query)
+			Expect(err).To(MatchError(ContainSubstring("cannot impersonate")))
+		})
+
+		It("Should not default the service account", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"queryServiceAccount": "query-runner"},
+			})).To(Succeed())
+			Expect((&QueryCustomDefaulter{Client: fakeClient}).Default(ctx, query)).To(Succeed())
+			Expect(query.Spec.ServiceAccount).To(BeEmpty())
+		})
+	})
+
+	Context("When validating guardrails", func() {
+		It("Should deny a nonexistent guardrail", func() {
+			query.Spec.Guardrails = []arkv1alpha1.GuardrailRef{{Name: "missing-guardrail"}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("guardrail 'missing-guardrail' does not exist")))
+		})
+	})
+
+	Context("When validating parts", func() {
+		BeforeEach(func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "text-model", Namespace: "default"},
+				Spec:       arkv1alpha1.ModelSpec{Capabilities: []arkv1alpha1.ModelCapability{arkv1alpha1.ModelCapabilityFiles}},
+			})).To(Succeed())
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.Model{
+				ObjectMeta: metav1.ObjectMeta{Name: "vision-model", Namespace: "default"},
+				Spec:       arkv1alpha1.ModelSpec{Capabilities: []arkv1alpha1.ModelCapability{arkv1alpha1.ModelCapabilityVision}},
+			})).To(Succeed())
+			query.Spec.Parts = []arkv1alpha1.QueryInputPart{{Type: arkv1alpha1.QueryInputPartImage, ImageURL: "https://example.com/cat.png"}}
+		})
+
+		It("Should admit images for a model with vision", func() {
+			query.Spec.Targets = []arkv1alpha1.QueryTarget{{Type: TargetTypeModel, Name: "vision-model"}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny images for a model without vision", func() {
+			query.Spec.Targets = []arkv1alpha1.QueryTarget{{Type: TargetTypeModel, Name: "text-model"}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("model 'text-model' does not support vision input")))
+		})
+
+		It("Should deny images for an agent whose model lacks vision", func() {
+			Expect(fakeClient.Create(ctx, &arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "text-agent", Namespace: "default"},
+				Spec:       arkv1alpha1.AgentSpec{ModelRef: &arkv1alpha1.AgentModelRef{Name: "text-model"}},
+			})).To(Succeed())
+			query.Spec.Targets = []arkv1alpha1.QueryTarget{{Type: TargetTypeAgent, Name: "text-agent"}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("does not support vision input")))
+		})
+
+		It("Should deny parts on a messages query", func() {
+			Expect(query.Spec.SetInputMessages(nil)).To(Succeed())
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("parts can only be used with type user")))
+		})
+
+		It("Should deny a part read from a nonexistent configMap", func() {
+			query.Spec.Parts = []arkv1alpha1.QueryInputPart{{Type: arkv1alpha1.QueryInputPartFile, ValueFrom: &arkv1alpha1.QueryInputPartSource{
+				ConfigMapKeyRef: &corev1.ConfigMapKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}, Key: "report.pdf"},
+			}}}
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).To(MatchError(ContainSubstring("parts[0] references configMap 'missing' does not exist")))
+		})
+	})
+
+	Context("When updating", func() {
+		It("Should deny changing the creator annotation", func() {
+			query.Annotations = map[string]string{annotations.CreatedBy: "alice@example.com"}
+			updated := query.DeepCopy()
+			updated.Annotations[annotations.CreatedBy] = "mallory@example.com"
+			_, err := validator.ValidateUpdate(ctx, query, updated)
+			Expect(err).To(MatchError(ContainSubstring("cannot be changed")))
+		})
+	})
+
+	Context("When validating idempotency keys", func() {
+		createQuery := func(name string, created time.Time) {
+			existing := &arkv1alpha1.Query{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:              name,
+					Namespace:         "default",
+					CreationTimestamp: metav1.NewTime(created),
+				},
+				Spec: arkv1alpha1.QuerySpec{IdempotencyKey: "order-42"},
+			}
+			Expect((&QueryCustomDefaulter{Client: fakeClient}).Default(ctx, existing)).To(Succeed())
+			Expect(fakeClient.Create(ctx, existing)).To(Succeed())
+		}
+
+		BeforeEach(func() {
+			query.Spec.IdempotencyKey = "order-42"
+			Expect((&QueryCustomDefaulter{Client: fakeClient}).Default(ctx, query)).To(Succeed())
+		})
+
+		It("Should label the query with the key", func() {
+			Expect(query.Labels).To(HaveKeyWithValue(annotations.IdempotencyKey, arkv1alpha1.IdempotencyKeyLabelValue("order-42")))
+		})
+
+		It("Should admit the first query with a key", func() {
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("Should deny a resubmission within the window with the existing query", func() {
+			createQuery("first-query", time.Now())
+
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+			var statusErr *apierrors.StatusError
+			Expect(errors.As(err, &statusErr)).To(BeTrue())
+			Expect(statusErr.ErrStatus.Details.Name).To(Equal("first-query"))
+			Expect(statusErr.ErrStatus.Details.Causes[0].Field).To(Equal("spec.idempotencyKey"))
+		})
+
+		It("Should read earlier queries with the reader", func() {
+			createQuery("first-query", time.Now())
+			s := runtime.NewScheme()
+			Expect(arkv1alpha1.AddToScheme(s)).To(Succeed())
+			Expect(corev1.AddToScheme(s)).To(Succeed())
+			cached := fake.NewClientBuilder().WithScheme(s).WithObjects(&arkv1alpha1.Agent{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-agent", Namespace: "default"},
+			}).Build()
+			validator = &QueryCustomValidator{ResourceValidator: &ResourceValidator{Client: cached}, Reader: fakeClient}
+
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(apierrors.IsAlreadyExists(err)).To(BeTrue())
+		})
+
+		It("Should admit a resubmission after the window", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"queryIdempotencyWindow": "1h"},
+			})).To(Succeed())
+			createQuery("old-query", time.Now().Add(-2*time.Hour))
+
+			_, err := validator.ValidateCreate(ctx, query)
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
+
+	Context("When applying defaults", func() {
+		var defaulter *QueryCustomDefaulter
+
+		BeforeEach(func() {
+			defaulter = &QueryCustomDefaulter{Client: fakeClient}
+		})
+
+		It("Should apply built-in defaults without a defaults ConfigMap", func() {
+			Expect(defaulter.Default(ctx, query)).To(Succeed())
+			Expect(query.Spec.TTL.Duration).To(Equal(arkv1alpha1.DefaultQueryTTL))
+			Expect(query.Spec.Timeout.Duration).To(Equal(arkv1alpha1.DefaultQueryTimeout))
+			Expect(query.Spec.ServiceAccount).To(BeEmpty())
+		})
+
+		It("Should apply namespace defaults from the ConfigMap", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data: map[string]string{
+					"queryTTL":                "24h",
+					"queryTTLAfterCompletion": "1h",
+					"queryTimeout":            "10m",
+					"queryServiceAccount":     "query-runner",
+					"queryDataPolicy":         "redactPII",
+					"querySLOMaxDuration":     "2m",
+					"querySLOMaxTokens":       "50000",
+				},
+			})).To(Succeed())
+
+			Expect(defaulter.Default(ctx, query)).To(Succeed())
+			Expect(query.Spec.TTL.Duration).To(Equal(24 * time.Hour))
+			Expect(query.Spec.TTLAfterCompletion.Duration).To(Equal(time.Hour))
+			Expect(query.Spec.Timeout.Duration).To(Equal(10 * time.Minute))
+			Expect(query.Spec.ServiceAccount).To(Equal("query-runner"))
+			Expect(query.Spec.DataPolicy).To(Equal(arkv1alpha1.DataPolicyRedactPII))
+			Expect(query.Spec.SLO.MaxDuration.Duration).To(Equal(2 * time.Minute))
+			Expect(query.Spec.SLO.MaxTokens).To(Equal(int64(50000)))
+			Expect(query.Spec.SLO.MaxCost).To(BeEmpty())
+		})
+
+		It("Should not override values set on the query", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"querySLOMaxCost": "0.50"},
+			})).To(Succeed())
+			query.Spec.Timeout = &metav1.Duration{Duration: time.Minute}
+			query.Spec.ServiceAccount = "custom-sa"
+			query.Spec.SLO = &arkv1alpha1.QuerySLO{MaxTokens: 1000}
+			Expect(defaulter.Default(ctx, query)).To(Succeed())
+			Expect(query.Spec.Timeout.Duration).To(Equal(time.Minute))
+			Expect(query.Spec.ServiceAccount).To(Equal("custom-sa"))
+			Expect(query.Spec.SLO).To(Equal(&arkv1alpha1.QuerySLO{MaxTokens: 1000}))
+		})
+
+		It("Should record the requesting user as the creator", func() {
+			query.Annotations = map[string]string{annotations.CreatedBy: "spoofed"}
+			reqCtx := admission.NewContextWithRequest(ctx, admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+				UserInfo: authenticationv1.UserInfo{Username: "alice@example.com"},
+			}})
+			Expect(defaulter.Default(reqCtx, query)).To(Succeed())
+			Expect(query.Annotations).To(HaveKeyWithValue(annotations.CreatedBy, "alice@example.com"))
+		})
+
+		It("Should reject an invalid defaults ConfigMap", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"queryTTL": "forever"},
+			})).To(Succeed())
+
+			Expect(defaulter.Default(ctx, query)).To(MatchError(ContainSubstring("invalid queryTTL")))
+		})
+
+		It("Should reject an unknown data policy default", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"queryDataPolicy": "encrypt"},
+			})).To(Succeed())
+
+			Expect(defaulter.Default(ctx, query)).To(MatchError(ContainSubstring("invalid queryDataPolicy")))
+		})
+
+		It("Should reject an invalid SLO default", func() {
+			Expect(fakeClient.Create(ctx, &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: genai.DefaultsConfigMapName, Namespace: "default"},
+				Data:       map[string]string{"querySLOMaxTokens": "many"},
+			})).To(Succeed())
+
+			Expect(defaulter.Default(ctx, query)).To(MatchError(ContainSubstring("invalid querySLOMaxTokens")))
+		})
 	})
 })
@@ -5,7 +5,9 @@ package v1
 import (
 	"context"
 	"fmt"
+	"regexp"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -74,6 +76,12 @@ func (v *TeamCustomValidator) validateTeamMembers(ctx context.Context, team *ark
 		return warnings, err
 	}
 
+	terminationWarnings, err := v.validateTermination(ctx, team)
+	warnings = append(warnings, terminationWarnings...)
+	if err != nil {
+		return warnings, err
+	}
+
 	for i, member := range team.Spec.Members {
 		if member.Name == team.Name {
 			return warnings, fmt.Errorf("team member %d: team '%s' cannot reference itself", i, member.Name)
@@ -96,6 +104,10 @@ func (v *TeamCustomValidator) validateTeamMembers(ctx context.Context, team *ark
 		}
 	}
 
+	if err := validateMemberSelectors(team); err != nil {
+		return warnings, err
+	}
+
 	if err := v.validateNoMixedTeam(ctx, team); err != nil {
 		return warnings, err
 	}
@@ -103,6 +115,29 @@ func (v *TeamCustomValidator) validateTeamMembers(ctx context.Context, team *ark
 	return warnings, nil
 }
 
+// validateMemberSelectors checks that a team has members, and that its member selectors are valid
+// label selectors that do not select every agent in the namespace
+func validateMemberSelectors(team *arkv1alpha1.Team) error {
+	if len(team.Spec.Members) == 0 && len(team.Spec.MemberSelectors) == 0 {
+		return fmt.Errorf("team must have members or memberSelectors")
+	}
+
+	for i, memberSelector := range team.Spec.MemberSelectors {
+		if len(memberSelector.Selector.MatchLabels) == 0 && len(memberSelector.Selector.MatchExpressions) == 0 {
+			return fmt.Errorf("member selector %d must match labels or expressions", i)
+		}
+		if _, err := metav1.LabelSelectorAsSelector(&memberSelector.Selector); err != nil {
+			return fmt.Errorf("member selector %d is invalid: %v", i, err)
+		}
+	}
+
+	if len(team.Spec.MemberSelectors) > 0 && team.Spec.Strategy == "graph" {
+		return fmt.Errorf("graph strategy does not support memberSelectors: graph edges must reference members")
+	}
+
+	return nil
+}
+
 func (v *TeamCustomValidator) validateNoMixedTeam(ctx context.Context, team *arkv1alpha1.Team) error {
 	var hasInternalAgents, hasExternalAgents bool
 
@@ -142,6 +177,49 @@ func (v *TeamCustomValidator) validateStrategy(ctx context.Context, team *arkv1a
 	}
 }
 
+// validateTermination checks the termination conditions and moderator of a team, and warns about
+// teams that can take turns until the query times out
+func (v *TeamCustomValidator) validateTermination(ctx context.Context, team *arkv1alpha1.Team) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	if team.Spec.MaxTurns != nil && *team.Spec.MaxTurns < 1 {
+		return warnings, fmt.Errorf("maxTurns must be at least 1")
+	}
+
+	if termination := team.Spec.Termination; termination != nil {
+		if len(termination.Keywords) == 0 && len(termination.Patterns) == 0 && termination.Expression == "" {
+			return warnings, fmt.Errorf("termination requires at least one of keywords, patterns or expression")
+		}
+		for i, pattern := range termination.Patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return warnings, fmt.Errorf("termination.patterns[%d]: invalid regular expression: %v", i, err)
+			}
+		}
+		if termination.Expression != "" {
+			if _, err := genai.CompileTeamTerminationExpression(termination.Expression); err != nil {
+				return warnings, fmt.Errorf("termination.expression: invalid CEL expression: %v", err)
+			}
+		}
+	}
+
+	if moderator := team.Spec.Moderator; moderator != nil {
+		namespace := moderator.ModelRef.Namespace
+		if namespace == "" {
+			namespace = team.Namespace
+		}
+		if err := v.ValidateLoadModel(ctx, moderator.ModelRef.Name, namespace); err != nil {
+			warnings = append(warnings, fmt.Sprintf("moderator.modelRef: %v", err))
+		}
+	}
+
+	unbounded := team.Spec.Strategy == "round-robin" || team.Spec.Strategy == "selector"
+	if unbounded && team.Spec.MaxTurns == nil && team.Spec.Termination == nil && team.Spec.Moderator == nil {
+		warnings = append(warnings, fmt.Sprintf("%s team has no maxTurns, termination or moderator and runs until the query times out", team.Spec.Strategy))
+	}
+
+	return warnings, nil
+}
+
 func (v *TeamCustomValidator) validateSelectorAgent(ctx context.Context, team *arkv1alpha1.Team) error {
 	if team.Spec.Selector == nil || team.Spec.Selector.Agent == "" {
 		return fmt.Errorf("selector strategy requires selector.agent to be specified")
@@ -3,8 +3,11 @@
 package v1
 
 import (
+	"context"
+
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 	// TODO (user): Add any additional imports if needed
@@ -53,4 +56,33 @@ var _ = Describe("Team Webhook", func() {
 		//     Expect(validator.ValidateUpdate(ctx, oldObj, obj)).To(BeNil())
 		// })
 	})
+
+	Context("When validating member selectors", func() {
+		BeforeEach(func() {
+			obj = &arkv1alpha1.Team{
+				ObjectMeta: metav1.ObjectMeta{Name: "newsroom", Namespace: "default"},
+				Spec: arkv1alpha1.TeamSpec{
+					Strategy: "sequential",
+					MemberSelectors: []arkv1alpha1.TeamMemberSelector{{
+						Role:     "critic",
+						Selector: metav1.LabelSelector{MatchLabels: map[string]string{"team/role": "critic"}},
+					}},
+				},
+			}
+		})
+
+		It("should admit teams whose members are all selected by label", func() {
+			Expect(validator.ValidateCreate(context.Background(), obj)).Error().NotTo(HaveOccurred())
+		})
+
+		It("should deny teams without members or member selectors", func() {
+			obj.Spec.MemberSelectors = nil
+			Expect(validator.ValidateCreate(context.Background(), obj)).Error().To(MatchError("team must have members or memberSelectors"))
+		})
+
+		It("should deny member selectors that select every agent", func() {
+			obj.Spec.MemberSelectors[0].Selector = metav1.LabelSelector{}
+			Expect(validator.ValidateCreate(context.Background(), obj)).Error().To(MatchError("member selector 0 must match labels or expressions"))
+		})
+	})
 })
@@ -9,8 +9,10 @@ import (
 	"net/url"
 
 	"github.com/google/jsonschema-go/jsonschema"
+	authorizationv1 "k8s.io/api/authorization/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -25,13 +27,15 @@ var log = logf.Log
 // SetupToolWebhookWithManager registers the webhook for Tool in the manager.
 func SetupToolWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).For(&arkv1alpha1.Tool{}).
-		WithValidator(&ToolCustomValidator{}).
+		WithValidator(&ToolCustomValidator{Client: mgr.GetClient()}).
 		Complete()
 }
 
 // +kubebuilder:webhook:path=/validate-ark-mckinsey-com-v1alpha1-tool,mutating=false,failurePolicy=fail,sideEffects=None,groups=ark.mckinsey.com,resources=tools,verbs=create;update,versions=v1alpha1,name=vtool-v1.kb.io,admissionReviewVersions=v1
 
-type ToolCustomValidator struct{}
+type ToolCustomValidator struct {
+	Client client.Client
+}
 
 var _ webhook.CustomValidator = &ToolCustomValidator{}
 
@@ -62,7 +66,7 @@ func (v *ToolCustomValidator) ValidateDelete(ctx context.Context, obj runtime.Ob
 	return nil, nil
 }
 
-func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.Tool) (admission.Warnings, error) {
+func (v *ToolCustomValidator) validateTool(ctx context.Context, tool *arkv1alpha1.Tool) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
 	// Validate inputSchema if present
@@ -72,6 +76,16 @@ func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.
 		}
 	}
 
+	if tool.Spec.OutputSchema != nil {
+		if _, err := genai.NewToolOutputValidator(tool.Spec.OutputSchema.Raw); err != nil {
+			return warnings, fmt.Errorf("invalid outputSchema: %v", err)
+		}
+	}
+
+	if err := genai.ValidateToolSecurity(tool); err != nil {
+		return warnings, err
+	}
+
 	switch tool.Spec.Type {
 	case genai.ToolTypeHTTP:
 		return v.validateHTTP(tool.Spec.HTTP)
@@ -80,9 +94,13 @@ func (v *ToolCustomValidator) validateTool(_ context.Context, tool *arkv1alpha1.
 	case genai.ToolTypeAgent:
 		return v.validateAgentTool(tool.Spec.Agent.Name)
 	case genai.ToolTypeBuiltin:
-		return v.validateBuiltinTool(tool.Name)
+		return v.validateBuiltinTool(tool)
+	case genai.ToolTypeJob:
+		return v.validateJobTool(ctx, tool)
+	case genai.ToolTypeCodeInterpreter:
+		return v.validateCodeInterpreterTool(tool)
 	default:
-		return warnings, fmt.Errorf("unsupported tool type '%s': supported types are: http, mcp, agent, builtin", tool.Spec.Type)
+		return warnings, fmt.Errorf("unsupported tool type '%s': supported types are: http, mcp, agent, builtin, job, code-interpreter", tool.Spec.Type)
 	}
 }
 
@@ -145,17 +163,88 @@ func (v *ToolCustomValidator) validateAgentTool(agent string) (admission.Warning
 }
 
 // validateBuiltinTool validates Builtin-specific configuration
-func (v *ToolCustomValidator) validateBuiltinTool(toolName string) (admission.Warnings, error) {
+func (v *ToolCustomValidator) validateBuiltinTool(tool *arkv1alpha1.Tool) (admission.Warnings, error) {
 	var warnings admission.Warnings
 
-	supportedBuiltinTools := []string{genai.BuiltinToolNoop, genai.BuiltinToolTerminate}
-	for _, supportedTool := range supportedBuiltinTools {
-		if toolName == supportedTool {
-			return warnings, nil
+	if err := genai.ValidateBuiltinTool(tool); err != nil {
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// validateJobTool validates Job-specific configuration
+func (v *ToolCustomValidator) validateJobTool(ctx context.Context, tool *arkv1alpha1.Tool) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	if tool.Spec.Job == nil {
+		return warnings, fmt.Errorf("job spec is required for job type")
+	}
+	if tool.Spec.Job.Image == "" {
+		return warnings, fmt.Errorf("image is required for job tool")
+	}
+
+	if _, err := genai.ToolJobTimeout(*tool.Spec.Job); err != nil {
+		return warnings, err
+	}
+
+	if err := v.authorizeJobTool(ctx, tool); err != nil {
+		return warnings, err
+	}
+
+	return warnings, nil
+}
+
+// authorizeJobTool checks that the author of a job tool may create jobs in its namespace and use
+// its service account, since queries calling the tool run its image with that service account
+func (v *ToolCustomValidator) authorizeJobTool(ctx context.Context, tool *arkv1alpha1.Tool) error {
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot check permissions for job tool: %w", err)
+	}
+	caller := req.UserInfo
+
+	checks := []authorizationv1.ResourceAttributes{{Verb: "create", Group: "batch", Resource: "jobs", Namespace: tool.Namespace}}
+	if name := tool.Spec.Job.ServiceAccountName; name != "" {
+		checks = append(checks, authorizationv1.ResourceAttributes{Verb: "impersonate", Resource: "serviceaccounts", Namespace: tool.Namespace, Name: name})
+	}
+
+	extra := make(map[string]authorizationv1.ExtraValue, len(caller.Extra))
+	for key, value := range caller.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+	for _, check := range checks {
+		review := &authorizationv1.SubjectAccessReview{
+			Spec: authorizationv1.SubjectAccessReviewSpec{
+				User:               caller.Username,
+				UID:                caller.UID,
+				Groups:             caller.Groups,
+				Extra:              extra,
+				ResourceAttributes: &check,
+			},
+		}
+		if err := v.Client.Create(ctx, review); err != nil {
+			return fmt.Errorf("failed to check permissions for job tool: %w", err)
+		}
+		if !review.Status.Allowed {
+			if check.Name != "" {
+				return fmt.Errorf("user %s cannot %s %s %s in namespace %s required by the job tool", caller.Username, check.Verb, check.Resource, check.Name, check.Namespace)
+			}
+			return fmt.Errorf("user %s cannot %s %s in namespace %s required by the job tool", caller.Username, check.Verb, check.Resource, check.Namespace)
 		}
 	}
+	return nil
+}
 
-	return warnings, fmt.Errorf("unsupported builtin tool '%s': supported builtin tools are: %v", toolName, supportedBuiltinTools)
+// validateCodeInterpreterTool validates code interpreter configuration, which is optional
+func (v *ToolCustomValidator) validateCodeInterpreterTool(tool *arkv1alpha1.Tool) (admission.Warnings, error) {
+	var warnings admission.Warnings
+
+	if _, err := genai.CodeInterpreterTimeout(tool.Spec.CodeInterpreter); err != nil {
+		return warnings, err
+	}
+
+	return warnings, nil
 }
 
 // validateInputSchema validates the tool's inputSchema using jsonschema
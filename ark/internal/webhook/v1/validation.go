@@ -5,13 +5,20 @@ package v1
 import (
 	"context"
 	"fmt"
+	"io"
+	"text/template"
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1prealpha1 "mckinsey.com/ark/api/v1prealpha1"
+	"mckinsey.com/ark/internal/common"
+	"mckinsey.com/ark/internal/genai"
 )
 
 type ResourceValidator struct {
@@ -63,6 +70,23 @@ func (v *ResourceValidator) ValidateLoadModel(ctx context.Context, name, namespa
 	return nil
 }
 
+// ValidateChatModel rejects embedding models where a model answers chat completions. Models that
+// do not exist yet are accepted
+func (v *ResourceValidator) ValidateChatModel(ctx context.Context, name, namespace string) error {
+	if name == "" {
+		return nil
+	}
+
+	model := &arkv1alpha1.Model{}
+	if err := v.Client.Get(ctx, types.NamespacedName{Name: name, Namespace: namespace}, model); err != nil {
+		return nil
+	}
+	if model.Spec.Type == genai.ModelTypeEmbedding {
+		return fmt.Errorf("model '%s' is an embedding model and does not support chat completions", name)
+	}
+	return nil
+}
+
 func (v *ResourceValidator) ValidateLoadEvaluator(ctx context.Context, name, namespace string) error {
 	if name == "" {
 		return nil
@@ -96,6 +120,115 @@ func (v *ResourceValidator) ValidateLoadTool(ctx context.Context, name, namespac
 	return nil
 }
 
+func (v *ResourceValidator) ValidateLoadExecutionEngine(ctx context.Context, name, namespace string) error {
+	if name == "" {
+		return nil
+	}
+
+	executionEngine := &arkv1prealpha1.ExecutionEngine{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+
+	if err := v.Client.Get(ctx, key, executionEngine); err != nil {
+		return fmt.Errorf("executionEngine '%s' does not exist in namespace '%s': %v", name, namespace, err)
+	}
+
+	return nil
+}
+
+func (v *ResourceValidator) ValidateLoadGuardrail(ctx context.Context, name, namespace string) error {
+	if name == "" {
+		return nil
+	}
+
+	guardrail := &arkv1alpha1.Guardrail{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+
+	if err := v.Client.Get(ctx, key, guardrail); err != nil {
+		return fmt.Errorf("guardrail '%s' does not exist in namespace '%s': %v", name, namespace, err)
+	}
+
+	return nil
+}
+
+func (v *ResourceValidator) ValidateLoadRemoteCluster(ctx context.Context, name, namespace string) error {
+	if name == "" {
+		return nil
+	}
+
+	remoteCluster := &arkv1alpha1.RemoteCluster{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+
+	if err := v.Client.Get(ctx, key, remoteCluster); err != nil {
+		return fmt.Errorf("remote cluster '%s' does not exist in namespace '%s': %v", name, namespace, err)
+	}
+
+	return nil
+}
+
+// ValidateGuardrailRefs checks that each referenced guardrail exists
+func (v *ResourceValidator) ValidateGuardrailRefs(ctx context.Context, refs []arkv1alpha1.GuardrailRef, namespace string) error {
+	for i, ref := range refs {
+		if ref.Name == "" {
+			return fmt.Errorf("guardrails[%d]: name is required", i)
+		}
+		if err := v.ValidateLoadGuardrail(ctx, ref.Name, namespace); err != nil {
+			return fmt.Errorf("guardrails[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (v *ResourceValidator) ValidatePostProcessors(ctx context.Context, postProcessors []arkv1alpha1.PostProcessor, namespace string) error {
+	for i, postProcessor := range postProcessors {
+		if (postProcessor.Tool == nil) == (postProcessor.Webhook == nil) {
+			return fmt.Errorf("postProcessors[%d]: exactly one of tool or webhook is required", i)
+		}
+		if postProcessor.Tool == nil {
+			continue
+		}
+		if err := v.ValidateLoadTool(ctx, postProcessor.Tool.Name, namespace); err != nil {
+			return fmt.Errorf("postProcessors[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// ValidateModelParameters checks model parameters against the limits of the provider of a model.
+// Models that do not exist yet are checked against the limits common to all providers
+func (v *ResourceValidator) ValidateModelParameters(ctx context.Context, parameters *arkv1alpha1.ModelParameters, modelName, namespace string) error {
+	if parameters == nil {
+		return nil
+	}
+
+	modelType := ""
+	model := &arkv1alpha1.Model{}
+	if modelName != "" && v.Client.Get(ctx, types.NamespacedName{Name: modelName, Namespace: namespace}, model) == nil {
+		modelType = model.Spec.Type
+	}
+	if err := genai.ValidateModelParameters(parameters, modelType); err != nil {
+		return fmt.Errorf("modelParameters: %w", err)
+	}
+	return nil
+}
+
+func (v *ResourceValidator) ValidateLoadServiceAccount(ctx context.Context, name, namespace string) error {
+	if name == "" {
+		return nil
+	}
+
+	serviceAccount := &corev1.ServiceAccount{}
+	key := types.NamespacedName{Name: name, Namespace: namespace}
+
+	if err := v.Client.Get(ctx, key, serviceAccount); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("serviceAccount '%s' does not exist in namespace '%s'", name, namespace)
+		}
+		return err
+	}
+
+	return nil
+}
+
 func (v *ResourceValidator) ValidateLoadConfigMap(ctx context.Context, name, namespace string) error {
 	if name == "" {
 		return nil
@@ -202,6 +335,12 @@ func (v *ResourceValidator) validateValueFromSources(param arkv1alpha1.Parameter
 	if param.ValueFrom.QueryParameterRef != nil {
 		sources++
 	}
+	if param.ValueFrom.QueryFieldRef != nil {
+		sources++
+	}
+	if param.ValueFrom.ResourceFieldRef != nil {
+		sources++
+	}
 
 	if sources != 1 {
 		return fmt.Errorf("parameter[%d] '%s': valueFrom must specify exactly one source", index, param.Name)
@@ -238,6 +377,18 @@ func (v *ResourceValidator) validateParameterReferences(ctx context.Context, nam
 		}
 	}
 
+	if param.ValueFrom.QueryFieldRef != nil {
+		if err := common.ValidateQueryFieldPath(param.ValueFrom.QueryFieldRef.FieldPath); err != nil {
+			return fmt.Errorf("parameter[%d] '%s': queryFieldRef: %s", index, param.Name, err)
+		}
+	}
+
+	if param.ValueFrom.ResourceFieldRef != nil {
+		if err := common.ValidateResourceFieldPath(param.ValueFrom.ResourceFieldRef.FieldPath); err != nil {
+			return fmt.Errorf("parameter[%d] '%s': resourceFieldRef: %s", index, param.Name, err)
+		}
+	}
+
 	return nil
 }
 
@@ -266,6 +417,25 @@ func (v *ResourceValidator) ValidateParameters(ctx context.Context, namespace st
 	return nil
 }
 
+// ValidateTemplate rejects a field that does not parse as a Go template and warns when it references
+// names that are not declared as parameters, which would render as "<no value>" at runtime.
+func ValidateTemplate(field, tmpl string, parameters []arkv1alpha1.Parameter) (admission.Warnings, error) {
+	t, err := template.New(field).Option("missingkey=error").Parse(tmpl)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s template: %v", field, err)
+	}
+
+	data := make(map[string]any, len(parameters))
+	for _, param := range parameters {
+		data[param.Name] = ""
+	}
+	if err := t.Execute(io.Discard, data); err != nil {
+		return admission.Warnings{fmt.Sprintf("%s template may not resolve: %v", field, err)}, nil
+	}
+
+	return nil, nil
+}
+
 // ValidatePollInterval validates that poll interval is not negative
 func ValidatePollInterval(pollInterval time.Duration) error {
 	if pollInterval < 0 {
@@ -5,6 +5,7 @@ package v1prealpha1
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -74,7 +75,12 @@ func (v *A2AServerValidator) validateA2AServer(a2aServer *arkv1prealpha1.A2AServ
 	}
 
 	// Validate headers
-	if err := v.validateHeaders(a2aServer.Spec.Headers); err != nil {
+	if err := validateHeaders(a2aServer.Spec.Headers); err != nil {
+		allErrs = append(allErrs, err)
+	}
+
+	// Validate auth
+	if err := v.validateAuth(a2aServer.Spec.Auth, a2aServer.Spec.Headers); err != nil {
 		allErrs = append(allErrs, err)
 	}
 
@@ -104,7 +110,8 @@ func (v *A2AServerValidator) validateAddress(address arkv1prealpha1.ValueSource)
 	return nil
 }
 
-func (v *A2AServerValidator) validateHeaders(headers []arkv1prealpha1.Header) error {
+// validateHeaders checks headers for duplicate names and value sources that set both or neither of value and valueFrom
+func validateHeaders(headers []arkv1prealpha1.Header) error {
 	headerNames := make(map[string]bool)
 
 	for _, header := range headers {
@@ -126,3 +133,35 @@ func (v *A2AServerValidator) validateHeaders(headers []arkv1prealpha1.Header) er
 
 	return nil
 }
+
+func (v *A2AServerValidator) validateAuth(auth *arkv1prealpha1.A2AServerAuth, headers []arkv1prealpha1.Header) error {
+	if auth == nil || auth.OAuth2 == nil {
+		return nil
+	}
+
+	// The auth block owns the Authorization header
+	for _, header := range headers {
+		if strings.EqualFold(header.Name, "Authorization") {
+			return fmt.Errorf("header Authorization cannot be set when auth is configured")
+		}
+	}
+
+	credentials := []struct {
+		name   string
+		source arkv1prealpha1.ValueSource
+	}{
+		{"clientID", auth.OAuth2.ClientID},
+		{"clientSecret", auth.OAuth2.ClientSecret},
+	}
+	for _, credential := range credentials {
+		if credential.source.Value == "" && credential.source.ValueFrom == nil {
+			return fmt.Errorf("auth.oauth2.%s must specify either value or valueFrom", credential.name)
+		}
+
+		if credential.source.Value != "" && credential.source.ValueFrom != nil {
+			return fmt.Errorf("auth.oauth2.%s cannot specify both value and valueFrom", credential.name)
+		}
+	}
+
+	return nil
+}
@@ -60,6 +60,14 @@ func (v *ExecutionEngineValidator) ValidateCreate(ctx context.Context, obj runti
 		return nil, fmt.Errorf("failed to resolve Address: %w", err)
 	}
 
+	if err := validateHeaders(executionEngine.Spec.Headers); err != nil {
+		return nil, err
+	}
+
+	if executionEngine.Spec.PollInterval != nil && executionEngine.Spec.PollInterval.Duration <= 0 {
+		return nil, fmt.Errorf("pollInterval must be a positive duration")
+	}
+
 	executionengineLog.Info("ExecutionEngine validation complete", "name", executionEngine.GetName())
 
 	return nil, nil
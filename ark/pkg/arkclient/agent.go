@@ -0,0 +1,47 @@
+/* Copyright 2025. McKinsey & Company */
+
+package arkclient
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// CreateAgent creates an agent with the spec and returns it as stored
+func (c *Client) CreateAgent(ctx context.Context, name string, spec arkv1alpha1.AgentSpec) (*arkv1alpha1.Agent, error) {
+	agent := &arkv1alpha1.Agent{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.namespace},
+		Spec:       spec,
+	}
+	if err := c.Create(ctx, agent); err != nil {
+		return nil, err
+	}
+	return agent, nil
+}
+
+// GetAgent returns the named agent
+func (c *Client) GetAgent(ctx context.Context, name string) (*arkv1alpha1.Agent, error) {
+	var agent arkv1alpha1.Agent
+	if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: c.namespace}, &agent); err != nil {
+		return nil, err
+	}
+	return &agent, nil
+}
+
+// ListAgents returns the agents of the namespace
+func (c *Client) ListAgents(ctx context.Context, opts ...client.ListOption) ([]arkv1alpha1.Agent, error) {
+	var agents arkv1alpha1.AgentList
+	if err := c.List(ctx, &agents, append([]client.ListOption{client.InNamespace(c.namespace)}, opts...)...); err != nil {
+		return nil, err
+	}
+	return agents.Items, nil
+}
+
+// DeleteAgent deletes the named agent
+func (c *Client) DeleteAgent(ctx context.Context, name string) error {
+	return c.Delete(ctx, &arkv1alpha1.Agent{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: c.namespace}})
+}
@@ -0,0 +1,131 @@
+/* Copyright 2025. McKinsey & Company */
+
+package arkclient
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func newFakeClient(objs ...client.Object) *fake.ClientBuilder {
+	return fake.NewClientBuilder().WithScheme(Scheme).WithObjects(objs...).
+		WithIndex(&corev1.Event{}, "involvedObject.name", func(obj client.Object) []string {
+			return []string{obj.(*corev1.Event).InvolvedObject.Name}
+		})
+}
+
+func TestAgents(t *testing.T) {
+	ctx := context.Background()
+	c := NewForClient(newFakeClient().Build(), "")
+
+	created, err := c.CreateAgent(ctx, "weather", arkv1alpha1.AgentSpec{Prompt: "You report the weather"})
+	require.NoError(t, err)
+	assert.Equal(t, "default", created.Namespace)
+
+	agent, err := c.GetAgent(ctx, "weather")
+	require.NoError(t, err)
+	assert.Equal(t, "You report the weather", agent.Spec.Prompt)
+
+	agents, err := c.ListAgents(ctx)
+	require.NoError(t, err)
+	assert.Len(t, agents, 1)
+
+	require.NoError(t, c.DeleteAgent(ctx, "weather"))
+	_, err = c.GetAgent(ctx, "weather")
+	assert.Error(t, err)
+}
+
+func TestSubmitQueryAndWait(t *testing.T) {
+	ctx := context.Background()
+	// The fake client has no controller, queries are completed when they are created
+	completeWith := func(phase string) interceptor.Funcs {
+		return interceptor.Funcs{
+			Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+				if query, ok := obj.(*arkv1alpha1.Query); ok {
+					query.Status.Phase = phase
+					query.Status.Responses = []arkv1alpha1.Response{{Content: "Sunny"}}
+				}
+				return c.Create(ctx, obj, opts...)
+			},
+		}
+	}
+	spec := arkv1alpha1.QuerySpec{
+		Input:   runtime.RawExtension{Raw: []byte(`"What is the weather?"`)},
+		Targets: []arkv1alpha1.QueryTarget{{Type: "agent", Name: "weather"}},
+	}
+
+	c := NewForClient(newFakeClient().WithInterceptorFuncs(completeWith(QueryPhaseDone)).Build(), "default")
+	query, err := c.SubmitQueryAndWait(ctx, spec)
+	require.NoError(t, err)
+	assert.Contains(t, query.Name, "query-")
+	assert.Equal(t, "Sunny", query.Status.Responses[0].Content)
+
+	c = NewForClient(newFakeClient().WithInterceptorFuncs(completeWith(QueryPhaseError)).Build(), "default")
+	query, err = c.SubmitQueryAndWait(ctx, spec)
+	require.Error(t, err)
+	assert.Equal(t, QueryPhaseError, query.Status.Phase)
+}
+
+func TestStreamQuery(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{Name: "weather-query", Namespace: "default"},
+		Status:     arkv1alpha1.QueryStatus{Phase: QueryPhaseRunning},
+	}
+	queryEvent := func(name, reason string) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Name: name, Namespace: "default"},
+			InvolvedObject: corev1.ObjectReference{Kind: "Query", Name: query.Name},
+			Reason:         reason,
+		}
+	}
+	otherEvent := &corev1.Event{
+		ObjectMeta:     metav1.ObjectMeta{Name: "agent-event", Namespace: "default"},
+		InvolvedObject: corev1.ObjectReference{Kind: "Agent", Name: query.Name},
+	}
+	c := NewForClient(newFakeClient(query, queryEvent("resolve", "QueryResolveStart"), otherEvent).Build(), "default")
+
+	updates, err := c.StreamQuery(ctx, query.Name)
+	require.NoError(t, err)
+
+	update := <-updates
+	require.NotNil(t, update.Event)
+	assert.Equal(t, "QueryResolveStart", update.Event.Reason)
+	update = <-updates
+	require.NotNil(t, update.Query)
+	assert.Equal(t, QueryPhaseRunning, update.Query.Status.Phase)
+
+	require.NoError(t, c.Create(ctx, queryEvent("tool", "ToolCallStart")))
+	update = <-updates
+	require.NotNil(t, update.Event)
+	assert.Equal(t, "ToolCallStart", update.Event.Reason)
+
+	query.Status.Phase = QueryPhaseDone
+	require.NoError(t, c.Update(ctx, query))
+	update = <-updates
+	require.NotNil(t, update.Query)
+	assert.Equal(t, QueryPhaseDone, update.Query.Status.Phase)
+
+	_, open := <-updates
+	assert.False(t, open, "stream should end after the query finished")
+}
+
+func TestStreamMissingQuery(t *testing.T) {
+	c := NewForClient(newFakeClient().Build(), "default")
+	_, err := c.StreamQuery(context.Background(), "missing")
+	assert.Error(t, err)
+}
@@ -0,0 +1,223 @@
+/* Copyright 2025. McKinsey & Company */
+
+package arkclient
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/apimachinery/pkg/watch"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// Query phases
+const (
+	QueryPhasePending  = "pending"
+	QueryPhaseRunning  = "running"
+	QueryPhaseDone     = "done"
+	QueryPhaseError    = "error"
+	QueryPhaseCanceled = "canceled"
+)
+
+const (
+	// queryPollInterval is how often WaitForQuery checks the query
+	queryPollInterval = time.Second
+	// eventGracePeriod is how long StreamQuery waits for events recorded after the query finished
+	eventGracePeriod = 500 * time.Millisecond
+)
+
+// QueryFinished reports whether the query reached a final phase
+func QueryFinished(query *arkv1alpha1.Query) bool {
+	switch query.Status.Phase {
+	case QueryPhaseDone, QueryPhaseError, QueryPhaseCanceled:
+		return true
+	}
+	return false
+}
+
+// SubmitQuery creates a query with the spec and a generated name, and returns it as stored
+func (c *Client) SubmitQuery(ctx context.Context, spec arkv1alpha1.QuerySpec) (*arkv1alpha1.Query, error) {
+	query := &arkv1alpha1.Query{
+		ObjectMeta: metav1.ObjectMeta{GenerateName: "query-", Namespace: c.namespace},
+		Spec:       spec,
+	}
+	if err := c.Create(ctx, query); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// WaitForQuery waits until the named query finished and returns it. It stops with the error of
+// the context when the context is done first.
+func (c *Client) WaitForQuery(ctx context.Context, name string) (*arkv1alpha1.Query, error) {
+	var query arkv1alpha1.Query
+	err := wait.PollUntilContextCancel(ctx, queryPollInterval, true, func(ctx context.Context) (bool, error) {
+		if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: c.namespace}, &query); err != nil {
+			return false, err
+		}
+		return QueryFinished(&query), nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &query, nil
+}
+
+// SubmitQueryAndWait submits a query with the spec and waits until it finished. The finished query
+// is returned with an error when it did not complete successfully.
+func (c *Client) SubmitQueryAndWait(ctx context.Context, spec arkv1alpha1.QuerySpec) (*arkv1alpha1.Query, error) {
+	query, err := c.SubmitQuery(ctx, spec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit query: %w", err)
+	}
+	query, err = c.WaitForQuery(ctx, query.Name)
+	if err != nil {
+		return nil, err
+	}
+	if query.Status.Phase != QueryPhaseDone {
+		return query, fmt.Errorf("query %s finished with phase %s", query.Name, query.Status.Phase)
+	}
+	return query, nil
+}
+
+// QueryEvent is an update streamed by StreamQuery. Exactly one of its fields is set.
+type QueryEvent struct {
+	// Query is the query as it changed
+	Query *arkv1alpha1.Query
+	// Event is a Kubernetes event recorded for the query, such as a tool call or agent response
+	Event *corev1.Event
+	// Err ends the stream when watching the query failed
+	Err error
+}
+
+// StreamQuery streams the progress of the named query. The events recorded so far and the query
+// are sent first, then changes of the query and new events. The channel is closed shortly after
+// the query finished, or when the context is done.
+func (c *Client) StreamQuery(ctx context.Context, name string) (<-chan QueryEvent, error) {
+	var query arkv1alpha1.Query
+	if err := c.Get(ctx, client.ObjectKey{Name: name, Namespace: c.namespace}, &query); err != nil {
+		return nil, err
+	}
+
+	var events corev1.EventList
+	if err := c.List(ctx, &events, client.InNamespace(c.namespace), client.MatchingFields{"involvedObject.name": name}); err != nil {
+		return nil, fmt.Errorf("failed to list events of query %s: %w", name, err)
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return events.Items[i].CreationTimestamp.Before(&events.Items[j].CreationTimestamp)
+	})
+
+	queryWatch, err := c.Watch(ctx, &arkv1alpha1.QueryList{}, client.InNamespace(c.namespace),
+		client.MatchingFields{"metadata.name": name}, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: query.ResourceVersion}})
+	if err != nil {
+		return nil, fmt.Errorf("failed to watch query %s: %w", name, err)
+	}
+	eventWatch, err := c.Watch(ctx, &corev1.EventList{}, client.InNamespace(c.namespace),
+		client.MatchingFields{"involvedObject.name": name}, &client.ListOptions{Raw: &metav1.ListOptions{ResourceVersion: events.ResourceVersion}})
+	if err != nil {
+		queryWatch.Stop()
+		return nil, fmt.Errorf("failed to watch events of query %s: %w", name, err)
+	}
+
+	updates := make(chan QueryEvent, 10)
+	go func() {
+		defer close(updates)
+		defer queryWatch.Stop()
+		defer eventWatch.Stop()
+		streamQueryEvents(ctx, name, &query, events.Items, queryWatch, eventWatch, updates)
+	}()
+	return updates, nil
+}
+
+// streamQueryEvents sends the past events and the query, then the changes seen by the watches
+func streamQueryEvents(ctx context.Context, name string, query *arkv1alpha1.Query, pastEvents []corev1.Event, queryWatch, eventWatch watch.Interface, updates chan<- QueryEvent) {
+	send := func(update QueryEvent) bool {
+		select {
+		case updates <- update:
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for i := range pastEvents {
+		if isQueryEvent(&pastEvents[i], name) && !send(QueryEvent{Event: &pastEvents[i]}) {
+			return
+		}
+	}
+	if !send(QueryEvent{Query: query}) {
+		return
+	}
+
+	queryChanges := queryWatch.ResultChan()
+	eventChanges := eventWatch.ResultChan()
+	var finished <-chan time.Time
+	if QueryFinished(query) {
+		queryChanges = nil
+		finished = time.After(eventGracePeriod)
+	}
+
+	for {
+		select {
+		case change, ok := <-queryChanges:
+			if !ok {
+				send(QueryEvent{Err: fmt.Errorf("watch of query %s closed", name)})
+				return
+			}
+			switch change.Type {
+			case watch.Error:
+				send(QueryEvent{Err: apierrors.FromObject(change.Object)})
+				return
+			case watch.Deleted:
+				send(QueryEvent{Err: fmt.Errorf("query %s was deleted", name)})
+				return
+			}
+			changed, ok := change.Object.(*arkv1alpha1.Query)
+			if !ok || changed.Name != name {
+				continue
+			}
+			if !send(QueryEvent{Query: changed}) {
+				return
+			}
+			if QueryFinished(changed) {
+				// Events recorded when the query finished may arrive after the status change
+				queryChanges = nil
+				finished = time.After(eventGracePeriod)
+			}
+
+		case change, ok := <-eventChanges:
+			if !ok {
+				eventChanges = nil
+				continue
+			}
+			event, ok := change.Object.(*corev1.Event)
+			if !ok || change.Type != watch.Added || !isQueryEvent(event, name) {
+				continue
+			}
+			if !send(QueryEvent{Event: event}) {
+				return
+			}
+
+		case <-finished:
+			return
+
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// isQueryEvent reports whether the event was recorded for the named query. Field selectors only
+// match the name, so events of other kinds of resources with the same name are skipped here.
+func isQueryEvent(event *corev1.Event, name string) bool {
+	return event.InvolvedObject.Name == name &&
+		(event.InvolvedObject.Kind == "" || event.InvolvedObject.Kind == "Query")
+}
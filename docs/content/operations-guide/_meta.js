@@ -2,6 +2,9 @@ export default {
   'provisioning': 'Cloud Infrastructure Provisioning',
   'build-pipelines': 'Build Pipelines',
   'deploying-ark': 'Deploying ARK',
+  'controller-config': 'Controller Configuration',
+  'query-audit-log': 'Query Audit Log',
+  'metrics': 'Metrics',
   'penetration-testing-reports': 'Penetration Testing Reports',
   'code-analysis-reports': 'Code Analysis Reports',
   'artifact-analysis-reports': 'Artifact Analysis Reports',
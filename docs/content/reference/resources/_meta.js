@@ -1,10 +1,21 @@
 export default {
   a2aserver: 'A2AServers',
   agent: 'Agents',
+  executionengine: 'ExecutionEngines',
+  guardrail: 'Guardrails',
+  knowledgebase: 'KnowledgeBases',
   mcpserver: 'MCPServers',
   memory: 'Memories',
   models: 'Models',
+  notificationsink: 'NotificationSinks',
+  pipeline: 'Pipelines',
   query: 'Queries',
+  queryretentionpolicy: 'QueryRetentionPolicies',
+  querytemplate: 'QueryTemplates',
+  referencegrant: 'ReferenceGrants',
+  remotecluster: 'RemoteClusters',
+  session: 'Sessions',
   team: 'Teams',
+  toolapproval: 'ToolApprovals',
   tools: 'Tools'
 }
\ No newline at end of file
@@ -0,0 +1,68 @@
+import { Artifact } from './types.js';
+import { readFileSync, writeFileSync, existsSync, mkdirSync, rmSync } from 'fs';
+import { join } from 'path';
+
+// Namespaces and artifact names are Kubernetes object names, which also keeps them safe as file names
+const NAME_PATTERN = /^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$/;
+
+// Store of query response artifacts too large for the query status.
+// Artifacts are written as one JSON file each under ARTIFACTS_DIR when it is set, and kept in memory otherwise.
+export class ArtifactStore {
+  private artifacts: Map<string, Artifact> = new Map();
+  private readonly artifactsDir?: string;
+
+  constructor() {
+    this.artifactsDir = process.env.ARTIFACTS_DIR;
+  }
+
+  putArtifact(namespace: string, name: string, artifact: Artifact): void {
+    this.validateKey(namespace, name);
+    if (typeof artifact?.content !== 'string' || typeof artifact?.raw !== 'string') {
+      throw new Error('content and raw must be strings');
+    }
+
+    const stored = { content: artifact.content, raw: artifact.raw };
+    if (!this.artifactsDir) {
+      this.artifacts.set(this.key(namespace, name), stored);
+      return;
+    }
+    mkdirSync(join(this.artifactsDir, namespace), { recursive: true });
+    writeFileSync(this.filePath(namespace, name), JSON.stringify(stored), 'utf-8');
+  }
+
+  getArtifact(namespace: string, name: string): Artifact | undefined {
+    this.validateKey(namespace, name);
+    if (!this.artifactsDir) {
+      return this.artifacts.get(this.key(namespace, name));
+    }
+    const path = this.filePath(namespace, name);
+    return existsSync(path) ? JSON.parse(readFileSync(path, 'utf-8')) : undefined;
+  }
+
+  deleteArtifact(namespace: string, name: string): boolean {
+    this.validateKey(namespace, name);
+    if (!this.artifactsDir) {
+      return this.artifacts.delete(this.key(namespace, name));
+    }
+    const path = this.filePath(namespace, name);
+    if (!existsSync(path)) {
+      return false;
+    }
+    rmSync(path);
+    return true;
+  }
+
+  private key(namespace: string, name: string): string {
+    return `${namespace}/${name}`;
+  }
+
+  private filePath(namespace: string, name: string): string {
+    return join(this.artifactsDir!, namespace, `${name}.json`);
+  }
+
+  private validateKey(namespace: string, name: string): void {
+    if (!NAME_PATTERN.test(namespace) || !NAME_PATTERN.test(name)) {
+      throw new Error('namespace and name must be valid Kubernetes names');
+    }
+  }
+}
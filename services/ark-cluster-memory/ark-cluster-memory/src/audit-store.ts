@@ -0,0 +1,102 @@
+import { AuditFilter, AuditRecord } from './types.js';
+import { readFileSync, appendFileSync, writeFileSync, existsSync, mkdirSync } from 'fs';
+import { dirname } from 'path';
+
+const DEFAULT_MAX_RECORDS = 100000;
+
+// Append-only store of query execution audit records.
+// Records are persisted as JSON lines when AUDIT_FILE_PATH is set.
+// Only the latest maxRecords records are kept, older records are dropped from memory and the file.
+export class AuditStore {
+  private records: AuditRecord[] = [];
+  private readonly auditFilePath?: string;
+  private readonly maxRecords: number;
+  // Records written to the file since it was last compacted to the records held
+  private fileRecords = 0;
+
+  constructor(maxRecords?: number) {
+    // Use AUDIT_MAX_RECORDS env var or default to 100000 records
+    const envMaxRecords = process.env.AUDIT_MAX_RECORDS ? parseInt(process.env.AUDIT_MAX_RECORDS, 10) : DEFAULT_MAX_RECORDS;
+    this.maxRecords = maxRecords ?? (envMaxRecords > 0 ? envMaxRecords : DEFAULT_MAX_RECORDS);
+    this.auditFilePath = process.env.AUDIT_FILE_PATH;
+    this.loadFromFile();
+  }
+
+  addRecord(record: AuditRecord): void {
+    if (!record.query || !record.namespace) {
+      throw new Error('query and namespace are required');
+    }
+
+    this.records.push(record);
+    if (this.records.length > this.maxRecords) {
+      this.records.splice(0, this.records.length - this.maxRecords);
+    }
+    this.appendToFile(record);
+  }
+
+  getRecords(filter: AuditFilter = {}): AuditRecord[] {
+    const since = filter.since ? Date.parse(filter.since) : undefined;
+    if (since !== undefined && Number.isNaN(since)) {
+      throw new Error(`Invalid since timestamp: ${filter.since}`);
+    }
+
+    return this.records.filter(record =>
+      (!filter.namespace || record.namespace === filter.namespace) &&
+      (!filter.query || record.query === filter.query) &&
+      (!filter.createdBy || record.createdBy === filter.createdBy) &&
+      (!filter.status || record.status === filter.status) &&
+      (since === undefined || Date.parse(record.timestamp) >= since)
+    );
+  }
+
+  private loadFromFile(): void {
+    if (!this.auditFilePath || !existsSync(this.auditFilePath)) {
+      return;
+    }
+
+    try {
+      const lines = readFileSync(this.auditFilePath, 'utf-8').split('\n').filter(line => line.trim());
+      this.records = lines.slice(-this.maxRecords).map(line => JSON.parse(line));
+      this.fileRecords = lines.length;
+      console.log(`[AUDIT LOAD] Loaded ${this.records.length} audit records from ${this.auditFilePath}`);
+      if (lines.length > this.maxRecords) {
+        this.compactFile();
+      }
+    } catch (error) {
+      console.error(`[AUDIT LOAD] Failed to load audit records from file: ${error}`);
+    }
+  }
+
+  private appendToFile(record: AuditRecord): void {
+    if (!this.auditFilePath) return;
+
+    try {
+      const dir = dirname(this.auditFilePath);
+      if (!existsSync(dir)) {
+        mkdirSync(dir, { recursive: true });
+      }
+      appendFileSync(this.auditFilePath, JSON.stringify(record) + '\n', 'utf-8');
+      this.fileRecords++;
+      // Rewriting the file on every dropped record would be too costly, so it is compacted once
+      // it holds twice the records kept
+      if (this.fileRecords >= 2 * this.maxRecords) {
+        this.compactFile();
+      }
+    } catch (error) {
+      console.error(`[AUDIT SAVE] Failed to append audit record to file: ${error}`);
+    }
+  }
+
+  // Rewrites the file with the records held, dropping the older records
+  private compactFile(): void {
+    if (!this.auditFilePath) return;
+
+    try {
+      writeFileSync(this.auditFilePath, this.records.map(record => JSON.stringify(record) + '\n').join(''), 'utf-8');
+      this.fileRecords = this.records.length;
+      console.log(`[AUDIT SAVE] Compacted ${this.auditFilePath} to the latest ${this.records.length} audit records`);
+    } catch (error) {
+      console.error(`[AUDIT SAVE] Failed to compact audit records file: ${error}`);
+    }
+  }
+}
@@ -0,0 +1,224 @@
+import crypto from 'crypto';
+import fs from 'fs';
+import https from 'https';
+import type { NextFunction, Request, RequestHandler, Response } from 'express';
+
+export interface UserInfo {
+  username: string;
+  uid?: string;
+  groups?: string[];
+  extra?: Record<string, string[]>;
+}
+
+// ResourceAttributes is the access a request needs, checked like a kubectl request on the resource
+export interface ResourceAttributes {
+  namespace?: string;
+  verb: string;
+  group: string;
+  resource: string;
+  subresource?: string;
+  name?: string;
+}
+
+export interface Authorizer {
+  // authenticate returns the user of a bearer token, or undefined when the token is not valid
+  authenticate(token: string): Promise<UserInfo | undefined>;
+  authorize(user: UserInfo, attributes: ResourceAttributes): Promise<boolean>;
+}
+
+const SERVICE_ACCOUNT_DIR = '/var/run/secrets/kubernetes.io/serviceaccount';
+const CACHE_TTL_MS = 60_000;
+const REVIEW_TIMEOUT_MS = 10_000;
+
+interface CacheEntry<T> {
+  value: T;
+  expires: number;
+}
+
+/**
+ * KubernetesAuthorizer authenticates bearer tokens with the TokenReview API and checks access with
+ * the SubjectAccessReview API of the cluster the service runs in. Results are cached for a minute.
+ */
+export class KubernetesAuthorizer implements Authorizer {
+  private users = new Map<string, CacheEntry<UserInfo | undefined>>();
+  private decisions = new Map<string, CacheEntry<boolean>>();
+
+  constructor(
+    private host = process.env.KUBERNETES_SERVICE_HOST,
+    private port = process.env.KUBERNETES_SERVICE_PORT || '443',
+    private serviceAccountDir = SERVICE_ACCOUNT_DIR
+  ) {}
+
+  async authenticate(token: string): Promise<UserInfo | undefined> {
+    const key = crypto.createHash('sha256').update(token).digest('hex');
+    const cached = cachedValue(this.users, key);
+    if (cached) {
+      return cached.value;
+    }
+
+    const review = await this.post('/apis/authentication.k8s.io/v1/tokenreviews', {
+      apiVersion: 'authentication.k8s.io/v1',
+      kind: 'TokenReview',
+      spec: { token },
+    });
+    const user: UserInfo | undefined = review.status?.authenticated ? review.status.user : undefined;
+    this.users.set(key, { value: user, expires: Date.now() + CACHE_TTL_MS });
+    return user;
+  }
+
+  async authorize(user: UserInfo, attributes: ResourceAttributes): Promise<boolean> {
+    const key = JSON.stringify([user.username, user.groups, attributes]);
+    const cached = cachedValue(this.decisions, key);
+    if (cached) {
+      return cached.value;
+    }
+
+    const review = await this.post('/apis/authorization.k8s.io/v1/subjectaccessreviews', {
+      apiVersion: 'authorization.k8s.io/v1',
+      kind: 'SubjectAccessReview',
+      spec: {
+        user: user.username,
+        uid: user.uid,
+        groups: user.groups,
+        extra: user.extra,
+        resourceAttributes: attributes,
+      },
+    });
+    const allowed = review.status?.allowed === true;
+    this.decisions.set(key, { value: allowed, expires: Date.now() + CACHE_TTL_MS });
+    return allowed;
+  }
+
+  private post(path: string, body: unknown): Promise<any> {
+    if (!this.host) {
+      return Promise.reject(new Error('KUBERNETES_SERVICE_HOST is not set: run in a cluster or set AUTH_MODE=open'));
+    }
+    // The service account token is read for every review, since projected tokens are rotated
+    const token = fs.readFileSync(`${this.serviceAccountDir}/token`, 'utf-8').trim();
+    const ca = fs.readFileSync(`${this.serviceAccountDir}/ca.crt`);
+    const payload = JSON.stringify(body);
+
+    return new Promise((resolve, reject) => {
+      const req = https.request(
+        {
+          host: this.host,
+          port: this.port,
+          path,
+          method: 'POST',
+          ca,
+          timeout: REVIEW_TIMEOUT_MS,
+          headers: {
+            Authorization: `Bearer ${token}`,
+            'Content-Type': 'application/json',
+            'Content-Length': Buffer.byteLength(payload),
+          },
+        },
+        (res) => {
+          let data = '';
+          res.on('data', (chunk) => (data += chunk));
+          res.on('end', () => {
+            if (!res.statusCode || res.statusCode < 200 || res.statusCode >= 300) {
+              reject(new Error(`${path} returned status ${res.statusCode}: ${data}`));
+              return;
+            }
+            try {
+              resolve(JSON.parse(data));
+            } catch (error) {
+              reject(error);
+            }
+          });
+        }
+      );
+      req.on('timeout', () => req.destroy(new Error(`${path} timed out`)));
+      req.on('error', reject);
+      req.end(payload);
+    });
+  }
+}
+
+function cachedValue<T>(cache: Map<string, CacheEntry<T>>, key: string): CacheEntry<T> | undefined {
+  const entry = cache.get(key);
+  if (!entry) {
+    return undefined;
+  }
+  if (entry.expires <= Date.now()) {
+    cache.delete(key);
+    return undefined;
+  }
+  return entry;
+}
+
+// openAuthorizer lets every caller through, for running the service outside a cluster
+export const openAuthorizer: Authorizer = {
+  authenticate: async () => ({ username: 'anonymous' }),
+  authorize: async () => true,
+};
+
+// authorizerFromEnv returns the authorizer selected by AUTH_MODE: kubernetes (default) or open
+export function authorizerFromEnv(): Authorizer {
+  const mode = (process.env.AUTH_MODE || 'kubernetes').toLowerCase();
+  switch (mode) {
+    case 'kubernetes':
+      return new KubernetesAuthorizer();
+    case 'open':
+      console.warn('AUTH_MODE=open: audit, artifact and collection endpoints are not authenticated');
+      return openAuthorizer;
+    default:
+      throw new Error(`Invalid AUTH_MODE '${mode}': must be kubernetes or open`);
+  }
+}
+
+/**
+ * requireAccess returns middleware that authenticates the bearer token of a request and checks that
+ * its user has the access returned by attributes. Responds 401 without a valid token and 403 without access.
+ */
+export function requireAccess(authorizer: Authorizer, attributes: (req: Request) => ResourceAttributes): RequestHandler {
+  return async (req: Request, res: Response, next: NextFunction) => {
+    const token = bearerToken(req.headers.authorization);
+    try {
+      const user = token ? await authorizer.authenticate(token) : undefined;
+      if (!user) {
+        res.set('WWW-Authenticate', 'Bearer');
+        res.status(401).json({ error: 'a valid bearer token is required' });
+        return;
+      }
+      const required = attributes(req);
+      if (!(await authorizer.authorize(user, required))) {
+        const scope = required.namespace ? ` in namespace ${required.namespace}` : '';
+        const resource = required.subresource ? `${required.resource}/${required.subresource}` : required.resource;
+        res.status(403).json({ error: `${user.username} cannot ${required.verb} ${resource}${scope}` });
+        return;
+      }
+      next();
+    } catch (error) {
+      console.error('Failed to authorize request:', error);
+      res.status(500).json({ error: 'failed to authorize request' });
+    }
+  };
+}
+
+function bearerToken(authorization: string | undefined): string | undefined {
+  if (!authorization?.startsWith('Bearer ')) {
+    return undefined;
+  }
+  return authorization.slice('Bearer '.length).trim() || undefined;
+}
+
+// queryAttributes is the access to the queries of a namespace, or of all namespaces without one
+export function queryAttributes(verb: string, namespace?: string, name?: string, subresource?: string): ResourceAttributes {
+  return { namespace, verb, group: 'ark.mckinsey.com', resource: 'queries', subresource, name };
+}
+
+// collectionAttributes is the access to the knowledge base a collection belongs to. Collections are
+// named <namespace>.<knowledge base>.<indexing time>, other names need the access in all namespaces.
+export function collectionAttributes(verb: string, collection: string, subresource?: string): ResourceAttributes {
+  const match = /^([a-z0-9-]+)\.(.+)\.\d+$/.exec(collection);
+  return {
+    namespace: match?.[1],
+    verb,
+    group: 'ark.mckinsey.com',
+    resource: 'knowledgebases',
+    subresource,
+    name: match?.[2],
+  };
+}
@@ -1,23 +1,50 @@
-import { Message, StoredMessage } from './types.js';
-import { readFileSync, writeFileSync, existsSync } from 'fs';
+import { EncryptedMessage, Message, MessageAnnotations, PersistedMessage, StoredMessage } from './types.js';
+import { MessageEncryption } from './message-encryption.js';
+import { readFileSync, writeFileSync, appendFileSync, existsSync } from 'fs';
 import { dirname } from 'path';
 import { mkdirSync } from 'fs';
 import { EventEmitter } from 'events';
 
+export class SessionExistsError extends Error {
+  constructor(sessionID: string) {
+    super(`Session ${sessionID} already exists`);
+    this.name = 'SessionExistsError';
+  }
+}
+
+export class IdempotencyKeyReusedError extends Error {
+  constructor(key: string) {
+    super(`Idempotency key ${key} was used for a different request`);
+    this.name = 'IdempotencyKeyReusedError';
+  }
+}
+
 export class MemoryStore {
   // Flat list of all messages with metadata
   private messages: StoredMessage[] = [];
+  // Sequence numbers identify messages, so they are never reused while the store holds data
+  private lastSequence = 0;
   private readonly maxMessageSize: number;
   private readonly memoryFilePath?: string;
+  // Messages replaced by a compaction summary, persisted as JSON lines when ARCHIVE_FILE_PATH is set
+  private archivedMessages: StoredMessage[] = [];
+  private readonly archiveFilePath?: string;
+  // Encrypts message content in the files when MEMORY_ENCRYPTION_KEYS_DIR is set
+  private readonly encryption?: MessageEncryption;
+  // Encrypted content of messages, so messages are only encrypted again after a key rotation
+  private readonly sealed = new WeakMap<StoredMessage, EncryptedMessage>();
   public eventEmitter: EventEmitter = new EventEmitter();
 
-  constructor(maxMessageSize?: number) {
+  constructor(maxMessageSize?: number, encryption?: MessageEncryption) {
     // Use MAX_MESSAGE_SIZE_MB env var or default to 10MB
     const maxSizeMB = process.env.MAX_MESSAGE_SIZE_MB ? parseInt(process.env.MAX_MESSAGE_SIZE_MB, 10) : 10;
     this.maxMessageSize = maxMessageSize ?? (maxSizeMB * 1024 * 1024);
     this.memoryFilePath = process.env.MEMORY_FILE_PATH;
+    this.archiveFilePath = process.env.ARCHIVE_FILE_PATH;
+    this.encryption = encryption ?? MessageEncryption.fromEnv();
 
     this.loadFromFile();
+    this.loadArchive();
   }
 
   private validateSessionID(sessionID: string): void {
@@ -45,7 +72,7 @@ export class MemoryStore {
       session_id: sessionID,
       query_id: '', // Legacy method without query_id
       message,
-      sequence: this.messages.length + 1
+      sequence: ++this.lastSequence
     };
     
     this.messages.push(storedMessage);
@@ -74,8 +101,9 @@ export class MemoryStore {
       session_id: sessionID,
       query_id: '', // Legacy method without query_id
       message: msg,
-      sequence: this.messages.length + index + 1
+      sequence: this.lastSequence + index + 1
     }));
+    this.lastSequence += storedMessages.length;
     
     this.messages.push(...storedMessages);
     this.saveToFile();
@@ -89,7 +117,9 @@ export class MemoryStore {
     }
   }
 
-  addMessagesWithMetadata(sessionID: string, queryID: string, messages: Message[]): void {
+  // Stores messages of a query, with the annotations on the last message. A request retried with
+  // the same idempotency key is not stored again; returns false for such a retry.
+  addMessagesWithMetadata(sessionID: string, queryID: string, messages: Message[], idempotencyKey?: string, annotations?: MessageAnnotations): boolean {
     this.validateSessionID(sessionID);
     
     if (!queryID) {
@@ -100,6 +130,10 @@ export class MemoryStore {
       this.validateMessage(message);
     }
 
+    if (idempotencyKey && this.isStoredRequest(idempotencyKey, sessionID, queryID, messages)) {
+      return false;
+    }
+
     // Check if this is a new session for event emission
     const isNewSession = !this.messages.some(m => m.session_id === sessionID);
 
@@ -109,8 +143,11 @@ export class MemoryStore {
       session_id: sessionID,
       query_id: queryID,
       message: msg,
-      sequence: this.messages.length + index + 1
+      sequence: this.lastSequence + index + 1,
+      ...(idempotencyKey ? { idempotency_key: idempotencyKey } : {}),
+      ...(annotations && index === messages.length - 1 ? { annotations } : {})
     }));
+    this.lastSequence += storedMessages.length;
     
     this.messages.push(...storedMessages);
     this.saveToFile();
@@ -122,13 +159,31 @@ export class MemoryStore {
     for (const message of messages) {
       this.eventEmitter.emit(`message:${sessionID}`, message);
     }
+    return true;
+  }
+
+  // Reports whether messages were stored with the idempotency key. Reusing a key for other
+  // messages is an error.
+  private isStoredRequest(idempotencyKey: string, sessionID: string, queryID: string, messages: Message[]): boolean {
+    const stored = [...this.archivedMessages, ...this.messages].filter(m => m.idempotency_key === idempotencyKey);
+    if (stored.length === 0) {
+      return false;
+    }
+    const sameRequest = stored.length === messages.length && stored.every((m, index) =>
+      m.session_id === sessionID &&
+      m.query_id === queryID &&
+      JSON.stringify(m.message) === JSON.stringify(messages[index]));
+    if (!sameRequest) {
+      throw new IdempotencyKeyReusedError(idempotencyKey);
+    }
+    return true;
   }
 
   getMessages(sessionID: string): Message[] {
     this.validateSessionID(sessionID);
     // Return just the message content for backward compatibility
     return this.messages
-      .filter(m => m.session_id === sessionID)
+      .filter(m => m.session_id === sessionID && !m.deleted_at)
       .map(m => m.message);
   }
 
@@ -138,19 +193,141 @@ export class MemoryStore {
     }
     // Return messages filtered by query_id
     return this.messages
-      .filter(m => m.query_id === queryID)
+      .filter(m => m.query_id === queryID && !m.deleted_at)
       .map(m => m.message);
   }
 
   getMessagesWithMetadata(sessionID: string, queryID?: string): StoredMessage[] {
     this.validateSessionID(sessionID);
-    let filtered = this.messages.filter(m => m.session_id === sessionID);
+    let filtered = this.messages.filter(m => m.session_id === sessionID && !m.deleted_at);
     if (queryID) {
       filtered = filtered.filter(m => m.query_id === queryID);
     }
     return filtered;
   }
 
+  // Merges annotations into a message. Annotations set to null are removed.
+  annotateMessage(sequence: number, annotations: MessageAnnotations): StoredMessage | undefined {
+    const stored = this.findMessage(sequence);
+    if (!stored) {
+      return undefined;
+    }
+
+    const merged: MessageAnnotations = { ...stored.annotations };
+    for (const [key, value] of Object.entries(annotations)) {
+      if (value === null) {
+        delete merged[key];
+      } else {
+        merged[key] = value;
+      }
+    }
+    stored.annotations = merged;
+    this.saveToFile();
+    return stored;
+  }
+
+  // Soft deletes a message: it is kept for review but no longer returned to queries
+  deleteMessage(sequence: number): boolean {
+    const stored = this.findMessage(sequence);
+    if (!stored) {
+      return false;
+    }
+
+    stored.deleted_at = new Date().toISOString();
+    this.saveToFile();
+    return true;
+  }
+
+  private findMessage(sequence: number): StoredMessage | undefined {
+    return this.messages.find(m => m.sequence === sequence && !m.deleted_at);
+  }
+
+  // Returns every message of a session with its metadata, including soft deleted messages
+  exportSession(sessionID: string): StoredMessage[] {
+    this.validateSessionID(sessionID);
+    return this.messages.filter(m => m.session_id === sessionID);
+  }
+
+  // Restores exported messages into a session. Messages get new sequence numbers in this store
+  // and keep their timestamps, query IDs, annotations and deletion. Returns the number imported.
+  importSession(sessionID: string, records: StoredMessage[], replace: boolean): number {
+    this.validateSessionID(sessionID);
+
+    records.forEach((record, index) => {
+      if (!record || typeof record !== 'object' || !('message' in record)) {
+        throw new Error(`Record ${index + 1} has no message`);
+      }
+      this.validateMessage(record.message);
+    });
+
+    if (this.sessionExists(sessionID)) {
+      if (!replace) {
+        throw new SessionExistsError(sessionID);
+      }
+      this.messages = this.messages.filter(m => m.session_id !== sessionID);
+    }
+
+    const imported = records.map((record, index) => {
+      const stored: StoredMessage = {
+        timestamp: record.timestamp || new Date().toISOString(),
+        session_id: sessionID,
+        query_id: record.query_id || '',
+        message: record.message,
+        sequence: this.lastSequence + index + 1
+      };
+      if (record.annotations) {
+        stored.annotations = record.annotations;
+      }
+      if (record.deleted_at) {
+        stored.deleted_at = record.deleted_at;
+      }
+      return stored;
+    });
+    this.lastSequence += imported.length;
+
+    this.messages.push(...imported);
+    this.saveToFile();
+    return imported.length;
+  }
+
+  // Replaces the oldest messages of a session with a summary message. The replaced messages are
+  // moved to the archive. Returns the stored summary.
+  compactSession(sessionID: string, count: number, summary: Message): StoredMessage {
+    this.validateSessionID(sessionID);
+    this.validateMessage(summary);
+
+    const active = this.messages.filter(m => m.session_id === sessionID && !m.deleted_at);
+    if (!Number.isInteger(count) || count < 1 || count > active.length) {
+      throw new Error(`count must be between 1 and ${active.length}`);
+    }
+
+    const archived = active.slice(0, count);
+    const summaryMessage: StoredMessage = {
+      timestamp: new Date().toISOString(),
+      session_id: sessionID,
+      query_id: '',
+      message: summary,
+      sequence: ++this.lastSequence,
+      annotations: { summary_of: archived.map(m => m.sequence) }
+    };
+
+    // The summary takes the place of the first archived message, before the messages it precedes
+    const index = this.messages.indexOf(archived[0]);
+    const archivedSet = new Set(archived);
+    this.messages = this.messages.filter(m => !archivedSet.has(m));
+    this.messages.splice(index, 0, summaryMessage);
+
+    this.archivedMessages.push(...archived);
+    this.appendToArchive(archived);
+    this.saveToFile();
+    return summaryMessage;
+  }
+
+  getArchivedMessages(sessionID: string): StoredMessage[] {
+    this.validateSessionID(sessionID);
+    return this.archivedMessages.filter(m => m.session_id === sessionID);
+  }
+
   clearSession(sessionID: string): void {
     this.validateSessionID(sessionID);
     this.messages = this.messages.filter(m => m.session_id !== sessionID);
@@ -188,6 +365,7 @@ export class MemoryStore {
 
   purge(): void {
     this.messages = [];
+    this.lastSequence = 0;
     this.saveToFile();
     console.log('[MEMORY PURGE] Cleared all messages');
   }
@@ -198,15 +376,17 @@ export class MemoryStore {
       return;
     }
     
+    let records: PersistedMessage[] = [];
     try {
       if (existsSync(this.memoryFilePath)) {
         const data = readFileSync(this.memoryFilePath, 'utf-8');
         const parsed = JSON.parse(data);
         
         if (Array.isArray(parsed)) {
-          this.messages = parsed;
-          const sessions = new Set(this.messages.map(m => m.session_id)).size;
-          console.log(`[MEMORY LOAD] Loaded ${this.messages.length} messages from ${sessions} sessions from ${this.memoryFilePath}`);
+          records = parsed;
+          this.lastSequence = records.reduce((last, m) => Math.max(last, m.sequence ?? 0), 0);
+          const sessions = new Set(records.map(m => m.session_id)).size;
+          console.log(`[MEMORY LOAD] Loaded ${records.length} messages from ${sessions} sessions from ${this.memoryFilePath}`);
         } else {
           console.warn('Invalid data format in memory file, starting fresh');
         }
@@ -216,6 +396,12 @@ export class MemoryStore {
     } catch (error) {
       console.error(`[MEMORY LOAD] Failed to load memory from file: ${error}`);
     }
+
+    // Messages that cannot be decrypted stop the service instead of being dropped by the next save
+    this.messages = records.map(record => this.openRecord(record));
+    if (this.needsRewrite(records)) {
+      this.saveToFile();
+    }
   }
 
   private saveToFile(): void {
@@ -227,7 +413,7 @@ export class MemoryStore {
         mkdirSync(dir, { recursive: true });
       }
       
-      writeFileSync(this.memoryFilePath, JSON.stringify(this.messages, null, 2), 'utf-8');
+      writeFileSync(this.memoryFilePath, JSON.stringify(this.messages.map(m => this.sealRecord(m)), null, 2), 'utf-8');
       const sessions = new Set(this.messages.map(m => m.session_id)).size;
       console.log(`[MEMORY SAVE] Saved ${this.messages.length} messages from ${sessions} sessions to ${this.memoryFilePath}`);
     } catch (error) {
@@ -235,6 +421,86 @@ export class MemoryStore {
     }
   }
 
+  private loadArchive(): void {
+    if (!this.archiveFilePath || !existsSync(this.archiveFilePath)) {
+      return;
+    }
+
+    let records: PersistedMessage[] = [];
+    try {
+      const lines = readFileSync(this.archiveFilePath, 'utf-8').split('\n').filter(line => line.trim());
+      records = lines.map(line => JSON.parse(line));
+      console.log(`[MEMORY LOAD] Loaded ${records.length} archived messages from ${this.archiveFilePath}`);
+    } catch (error) {
+      console.error(`[MEMORY LOAD] Failed to load archived messages from file: ${error}`);
+    }
+
+    this.archivedMessages = records.map(record => this.openRecord(record));
+    if (this.needsRewrite(records)) {
+      this.rewriteArchive();
+    }
+  }
+
+  // Rewrites the archive with the active key, so keys of a rotation can be removed afterwards
+  private rewriteArchive(): void {
+    if (!this.archiveFilePath) return;
+
+    try {
+      writeFileSync(this.archiveFilePath, this.archivedMessages.map(m => JSON.stringify(this.sealRecord(m)) + '\n').join(''), 'utf-8');
+      console.log(`[MEMORY SAVE] Encrypted ${this.archivedMessages.length} archived messages with key ${this.encryption?.activeKey}`);
+    } catch (error) {
+      console.error(`[MEMORY SAVE] Failed to rewrite archived messages: ${error}`);
+    }
+  }
+
+  // Encrypts the message of a record for the files, reusing its encryption until the key rotates
+  private sealRecord(stored: StoredMessage): PersistedMessage {
+    if (!this.encryption) {
+      return stored;
+    }
+    let encrypted = this.sealed.get(stored);
+    if (!encrypted || encrypted.key_id !== this.encryption.activeKey) {
+      encrypted = this.encryption.encrypt(stored.message);
+      this.sealed.set(stored, encrypted);
+    }
+    const record: PersistedMessage = { ...stored, encrypted_message: encrypted };
+    delete record.message;
+    return record;
+  }
+
+  private openRecord(record: PersistedMessage): StoredMessage {
+    const { encrypted_message: encrypted, ...rest } = record;
+    if (!encrypted) {
+      return record as StoredMessage;
+    }
+    if (!this.encryption) {
+      throw new Error('Stored messages are encrypted, but MEMORY_ENCRYPTION_KEYS_DIR is not set');
+    }
+    const stored: StoredMessage = { ...rest, message: this.encryption.decrypt(encrypted) };
+    this.sealed.set(stored, encrypted);
+    return stored;
+  }
+
+  // Reports whether records are stored in plain text or with a key that is no longer active
+  private needsRewrite(records: PersistedMessage[]): boolean {
+    const encryption = this.encryption;
+    return encryption !== undefined && records.some(record => record.encrypted_message?.key_id !== encryption.activeKey);
+  }
+
+  private appendToArchive(messages: StoredMessage[]): void {
+    if (!this.archiveFilePath) return;
+
+    try {
+      const dir = dirname(this.archiveFilePath);
+      if (!existsSync(dir)) {
+        mkdirSync(dir, { recursive: true });
+      }
+      appendFileSync(this.archiveFilePath, messages.map(m => JSON.stringify(this.sealRecord(m)) + '\n').join(''), 'utf-8');
+    } catch (error) {
+      console.error(`[MEMORY SAVE] Failed to append archived messages to file: ${error}`);
+    }
+  }
+
   saveMemory(): void {
     if (!this.memoryFilePath) {
       console.log('[MEMORY SAVE] File persistence disabled - memory not saved');
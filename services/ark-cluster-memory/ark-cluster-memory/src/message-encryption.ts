@@ -0,0 +1,80 @@
+import { createCipheriv, createDecipheriv, randomBytes } from 'crypto';
+import { readdirSync, readFileSync } from 'fs';
+import { join } from 'path';
+import { EncryptedMessage, Message } from './types.js';
+
+const KEY_SIZE = 32;
+const IV_SIZE = 12;
+const TAG_SIZE = 16;
+
+// Envelope encryption of message content. Each message is encrypted with AES-256-GCM under its own
+// data key, and the data key is wrapped with AES-256-GCM under a key encryption key. New messages
+// use the active key, older keys stay available to decrypt messages stored before a rotation.
+export class MessageEncryption {
+  constructor(private readonly keys: Map<string, Buffer>, readonly activeKey: string) {
+    if (!keys.has(activeKey)) {
+      throw new Error(`Active encryption key ${activeKey} not found`);
+    }
+    for (const [id, key] of keys) {
+      if (key.length !== KEY_SIZE) {
+        throw new Error(`Encryption key ${id} is not a ${KEY_SIZE * 8}-bit key`);
+      }
+    }
+  }
+
+  // Reads the keys from MEMORY_ENCRYPTION_KEYS_DIR, one base64 encoded key per file named after
+  // its key ID. Returns undefined when encryption is not configured.
+  static fromEnv(): MessageEncryption | undefined {
+    const keysDir = process.env.MEMORY_ENCRYPTION_KEYS_DIR;
+    const activeKey = process.env.MEMORY_ENCRYPTION_ACTIVE_KEY;
+    if (!keysDir && !activeKey) {
+      return undefined;
+    }
+    if (!keysDir || !activeKey) {
+      throw new Error('MEMORY_ENCRYPTION_KEYS_DIR and MEMORY_ENCRYPTION_ACTIVE_KEY must be set together');
+    }
+
+    const keys = new Map<string, Buffer>();
+    // Mounted Secrets also hold hidden entries such as ..data, which are not keys
+    for (const id of readdirSync(keysDir).filter(name => !name.startsWith('.'))) {
+      keys.set(id, Buffer.from(readFileSync(join(keysDir, id), 'utf-8').trim(), 'base64'));
+    }
+    return new MessageEncryption(keys, activeKey);
+  }
+
+  encrypt(message: Message): EncryptedMessage {
+    const dataKey = randomBytes(KEY_SIZE);
+    const content = seal(dataKey, Buffer.from(JSON.stringify(message), 'utf-8'));
+    const wrappedKey = seal(this.keys.get(this.activeKey)!, dataKey, Buffer.from(this.activeKey, 'utf-8'));
+    return { key_id: this.activeKey, data_key: wrappedKey.toString('base64'), content: content.toString('base64') };
+  }
+
+  decrypt(encrypted: EncryptedMessage): Message {
+    const key = this.keys.get(encrypted.key_id);
+    if (!key) {
+      throw new Error(`Encryption key ${encrypted.key_id} not found`);
+    }
+    const dataKey = open(key, Buffer.from(encrypted.data_key, 'base64'), Buffer.from(encrypted.key_id, 'utf-8'));
+    return JSON.parse(open(dataKey, Buffer.from(encrypted.content, 'base64')).toString('utf-8'));
+  }
+}
+
+// seal returns the IV, the ciphertext and the authentication tag of plaintext
+function seal(key: Buffer, plaintext: Buffer, aad?: Buffer): Buffer {
+  const iv = randomBytes(IV_SIZE);
+  const cipher = createCipheriv('aes-256-gcm', key, iv);
+  if (aad) {
+    cipher.setAAD(aad);
+  }
+  const ciphertext = Buffer.concat([cipher.update(plaintext), cipher.final()]);
+  return Buffer.concat([iv, ciphertext, cipher.getAuthTag()]);
+}
+
+function open(key: Buffer, sealed: Buffer, aad?: Buffer): Buffer {
+  const decipher = createDecipheriv('aes-256-gcm', key, sealed.subarray(0, IV_SIZE));
+  if (aad) {
+    decipher.setAAD(aad);
+  }
+  decipher.setAuthTag(sealed.subarray(sealed.length - TAG_SIZE));
+  return Buffer.concat([decipher.update(sealed.subarray(IV_SIZE, sealed.length - TAG_SIZE)), decipher.final()]);
+}
@@ -0,0 +1,185 @@
+import express from 'express';
+
+// Minimal Prometheus metrics in the text exposition format, so the service has no metrics dependency
+
+type Labels = Record<string, string>;
+
+interface Metric {
+  render(): string[];
+}
+
+function labelKey(labels: Labels): string {
+  return JSON.stringify(Object.entries(labels).sort(([a], [b]) => a.localeCompare(b)));
+}
+
+function formatLabels(labels: Labels): string {
+  const entries = Object.entries(labels);
+  if (entries.length === 0) {
+    return '';
+  }
+  const formatted = entries.map(([name, value]) =>
+    `${name}="${value.replace(/\\/g, '\\\\').replace(/\n/g, '\\n').replace(/"/g, '\\"')}"`);
+  return `{${formatted.join(',')}}`;
+}
+
+export class Counter implements Metric {
+  private values = new Map<string, { labels: Labels; value: number }>();
+
+  constructor(private readonly name: string, private readonly help: string) {}
+
+  inc(labels: Labels = {}, value = 1): void {
+    const key = labelKey(labels);
+    const current = this.values.get(key) ?? { labels, value: 0 };
+    current.value += value;
+    this.values.set(key, current);
+  }
+
+  get(labels: Labels = {}): number {
+    return this.values.get(labelKey(labels))?.value ?? 0;
+  }
+
+  render(): string[] {
+    const lines = [`# HELP ${this.name} ${this.help}`, `# TYPE ${this.name} counter`];
+    for (const { labels, value } of this.values.values()) {
+      lines.push(`${this.name}${formatLabels(labels)} ${value}`);
+    }
+    return lines;
+  }
+}
+
+// Gauge reads its values when metrics are collected
+export class Gauge implements Metric {
+  constructor(
+    private readonly name: string,
+    private readonly help: string,
+    private readonly collect: () => number
+  ) {}
+
+  render(): string[] {
+    return [`# HELP ${this.name} ${this.help}`, `# TYPE ${this.name} gauge`, `${this.name} ${this.collect()}`];
+  }
+}
+
+export class Histogram implements Metric {
+  private values = new Map<string, { labels: Labels; counts: number[]; sum: number; count: number }>();
+
+  constructor(
+    private readonly name: string,
+    private readonly help: string,
+    private readonly buckets: number[]
+  ) {}
+
+  observe(labels: Labels, value: number): void {
+    const key = labelKey(labels);
+    const current = this.values.get(key) ?? { labels, counts: this.buckets.map(() => 0), sum: 0, count: 0 };
+    this.values.set(key, current);
+    this.buckets.forEach((bound, index) => {
+      if (value <= bound) {
+        current.counts[index]++;
+      }
+    });
+    current.sum += value;
+    current.count++;
+  }
+
+  render(): string[] {
+    const lines = [`# HELP ${this.name} ${this.help}`, `# TYPE ${this.name} histogram`];
+    for (const { labels, counts, sum, count } of this.values.values()) {
+      this.buckets.forEach((bound, index) => {
+        lines.push(`${this.name}_bucket${formatLabels({ ...labels, le: String(bound) })} ${counts[index]}`);
+      });
+      lines.push(`${this.name}_bucket${formatLabels({ ...labels, le: '+Inf' })} ${count}`);
+      lines.push(`${this.name}_sum${formatLabels(labels)} ${sum}`);
+      lines.push(`${this.name}_count${formatLabels(labels)} ${count}`);
+    }
+    return lines;
+  }
+}
+
+export class Registry {
+  private metrics: Metric[] = [];
+
+  register<T extends Metric>(metric: T): T {
+    this.metrics.push(metric);
+    return metric;
+  }
+
+  render(): string {
+    return this.metrics.flatMap(metric => metric.render()).join('\n') + '\n';
+  }
+}
+
+export const PROMETHEUS_CONTENT_TYPE = 'text/plain; version=0.0.4; charset=utf-8';
+
+export const registry = new Registry();
+
+export const requestDuration = registry.register(new Histogram(
+  'ark_memory_http_request_duration_seconds',
+  'Duration of HTTP requests by method, route and status',
+  [0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10]
+));
+
+export const requestPayloadBytes = registry.register(new Histogram(
+  'ark_memory_http_request_payload_bytes',
+  'Size of HTTP request bodies by method and route',
+  [1024, 4096, 16384, 65536, 262144, 1048576, 4194304, 16777216]
+));
+
+export const messagesStored = registry.register(new Counter(
+  'ark_memory_messages_stored_total',
+  'Messages stored, by namespace and, when METRICS_SESSION_LABELS is true, by session'
+));
+
+// Namespace of the service, reported on message counts
+const namespace = process.env.NAMESPACE || '';
+// Session labels create a series per session, so they are only added when asked for
+const sessionLabels = process.env.METRICS_SESSION_LABELS === 'true';
+
+export function recordMessagesStored(sessionID: string, count: number): void {
+  const labels: Labels = { namespace };
+  if (sessionLabels) {
+    labels.session = sessionID;
+  }
+  messagesStored.inc(labels, count);
+}
+
+// Route of a request as declared, such as /messages/:id, so requests of a route share a series
+function routeOf(req: express.Request): string {
+  if (!req.route) {
+    return 'unmatched';
+  }
+  return `${req.baseUrl}${req.route.path}`;
+}
+
+// Records the duration and payload size of requests, and logs requests slower than
+// slowRequestMs as JSON so they can be found in log queries
+export function metricsMiddleware(slowRequestMs: number): express.RequestHandler {
+  return (req, res, next) => {
+    const start = process.hrtime.bigint();
+    res.on('finish', () => {
+      const seconds = Number(process.hrtime.bigint() - start) / 1e9;
+      const route = routeOf(req);
+      requestDuration.observe({ method: req.method, route, status: String(res.statusCode) }, seconds);
+
+      const size = parseInt(req.get('content-length') || '', 10);
+      if (!isNaN(size)) {
+        requestPayloadBytes.observe({ method: req.method, route }, size);
+      }
+
+      if (slowRequestMs > 0 && seconds * 1000 >= slowRequestMs) {
+        console.warn(JSON.stringify({
+          level: 'warn',
+          msg: 'slow request',
+          method: req.method,
+          route,
+          path: req.path,
+          status: res.statusCode,
+          duration_ms: Math.round(seconds * 1000),
+          payload_bytes: isNaN(size) ? undefined : size,
+          session_id: req.body?.session_id ?? req.query.session_id
+        }));
+      }
+    });
+    next();
+  };
+}
@@ -0,0 +1,158 @@
+import { Router } from 'express';
+import { ArtifactStore } from '../artifact-store.js';
+import { Authorizer, queryAttributes, requireAccess } from '../kube-auth.js';
+
+// Artifacts hold query responses, so storing and deleting them needs the access the controller has to
+// update the status of the queries of their namespace. Reading them needs get access to those queries.
+export function createArtifactRouter(artifacts: ArtifactStore, authorizer: Authorizer): Router {
+  const router = Router();
+  const canWrite = requireAccess(authorizer, (req) => queryAttributes('update', req.params.namespace, undefined, 'status'));
+  const canRead = requireAccess(authorizer, (req) => queryAttributes('get', req.params.namespace));
+
+  /**
+   * @swagger
+   * /artifacts/{namespace}/{name}:
+   *   put:
+   *     summary: Store a query response artifact
+   *     description: Stores the full content and raw messages of a query response too large for the query status, replacing any artifact of the same name
+   *     tags:
+   *       - Artifacts
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: namespace
+   *         required: true
+   *         schema:
+   *           type: string
+   *       - in: path
+   *         name: name
+   *         required: true
+   *         schema:
+   *           type: string
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - content
+   *               - raw
+   *             properties:
+   *               content:
+   *                 type: string
+   *               raw:
+   *                 type: string
+   *     responses:
+   *       204:
+   *         description: Artifact stored
+   *       400:
+   *         description: Invalid artifact
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot update the status of the queries of the namespace
+   */
+  router.put('/:namespace/:name', canWrite, (req, res) => {
+    try {
+      artifacts.putArtifact(req.params.namespace, req.params.name, req.body);
+      res.status(204).send();
+    } catch (error) {
+      console.error('Failed to store artifact:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /artifacts/{namespace}/{name}:
+   *   get:
+   *     summary: Get a query response artifact
+   *     tags:
+   *       - Artifacts
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: namespace
+   *         required: true
+   *         schema:
+   *           type: string
+   *       - in: path
+   *         name: name
+   *         required: true
+   *         schema:
+   *           type: string
+   *     responses:
+   *       200:
+   *         description: The artifact
+   *       404:
+   *         description: Artifact not found
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot get the queries of the namespace
+   */
+  router.get('/:namespace/:name', canRead, (req, res) => {
+    try {
+      const artifact = artifacts.getArtifact(req.params.namespace, req.params.name);
+      if (!artifact) {
+        res.status(404).json({ error: 'Artifact not found' });
+        return;
+      }
+      res.json(artifact);
+    } catch (error) {
+      console.error('Failed to get artifact:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /artifacts/{namespace}/{name}:
+   *   delete:
+   *     summary: Delete a query response artifact
+   *     tags:
+   *       - Artifacts
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: namespace
+   *         required: true
+   *         schema:
+   *           type: string
+   *       - in: path
+   *         name: name
+   *         required: true
+   *         schema:
+   *           type: string
+   *     responses:
+   *       204:
+   *         description: Artifact deleted
+   *       404:
+   *         description: Artifact not found
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot update the status of the queries of the namespace
+   */
+  router.delete('/:namespace/:name', canWrite, (req, res) => {
+    try {
+      if (!artifacts.deleteArtifact(req.params.namespace, req.params.name)) {
+        res.status(404).json({ error: 'Artifact not found' });
+        return;
+      }
+      res.status(204).send();
+    } catch (error) {
+      console.error('Failed to delete artifact:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  return router;
+}
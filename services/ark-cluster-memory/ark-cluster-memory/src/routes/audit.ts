@@ -0,0 +1,112 @@
+import { Router } from 'express';
+import { AuditStore } from '../audit-store.js';
+import { Authorizer, queryAttributes, requireAccess } from '../kube-auth.js';
+
+// Writing the record of a query needs the access the controller has to update the query status.
+// Reading records needs list access to the queries of the namespace, or of all namespaces without one.
+export function createAuditRouter(audit: AuditStore, authorizer: Authorizer): Router {
+  const router = Router();
+
+  /**
+   * @swagger
+   * /audit:
+   *   post:
+   *     summary: Append a query audit record
+   *     description: Appends the audit record of a query execution. Records cannot be changed or deleted. The caller must be allowed to update the status of the query.
+   *     tags:
+   *       - Audit
+   *     security:
+   *       - bearerAuth: []
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - query
+   *               - namespace
+   *     responses:
+   *       201:
+   *         description: Audit record stored
+   *       400:
+   *         description: Invalid audit record
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot update the status of the query
+   */
+  const canWrite = requireAccess(authorizer, (req) => queryAttributes('update', req.body?.namespace, req.body?.query, 'status'));
+  router.post('/', canWrite, (req, res) => {
+    try {
+      audit.addRecord(req.body);
+      res.status(201).send();
+    } catch (error) {
+      console.error('Failed to add audit record:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /audit:
+   *   get:
+   *     summary: List query audit records
+   *     description: Returns audit records, optionally filtered. The caller must be allowed to list the queries of the namespace, or of all namespaces without the namespace filter.
+   *     tags:
+   *       - Audit
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: query
+   *         name: namespace
+   *         schema:
+   *           type: string
+   *       - in: query
+   *         name: query
+   *         schema:
+   *           type: string
+   *       - in: query
+   *         name: created_by
+   *         schema:
+   *           type: string
+   *       - in: query
+   *         name: status
+   *         schema:
+   *           type: string
+   *       - in: query
+   *         name: since
+   *         description: Only return records written at or after this RFC 3339 timestamp
+   *         schema:
+   *           type: string
+   *     responses:
+   *       200:
+   *         description: Matching audit records
+   *       400:
+   *         description: Invalid filter
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot list the queries of the namespace
+   */
+  const canRead = requireAccess(authorizer, (req) => queryAttributes('list', req.query.namespace as string | undefined));
+  router.get('/', canRead, (req, res) => {
+    try {
+      const records = audit.getRecords({
+        namespace: req.query.namespace as string | undefined,
+        query: req.query.query as string | undefined,
+        createdBy: req.query.created_by as string | undefined,
+        status: req.query.status as string | undefined,
+        since: req.query.since as string | undefined,
+      });
+      res.json({ records });
+    } catch (error) {
+      console.error('Failed to get audit records:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  return router;
+}
@@ -0,0 +1,171 @@
+import { Router } from 'express';
+import { VectorStore } from '../vector-store.js';
+import { Authorizer, collectionAttributes, requireAccess } from '../kube-auth.js';
+
+// Writing and deleting a collection needs the access the controller has to update the status of its
+// knowledge base. Searching needs get access to the knowledge base.
+export function createCollectionRouter(vectors: VectorStore, authorizer: Authorizer): Router {
+  const router = Router();
+  const canWrite = requireAccess(authorizer, (req) => collectionAttributes('update', req.params.collection, 'status'));
+  const canRead = requireAccess(authorizer, (req) => collectionAttributes('get', req.params.collection));
+
+  /**
+   * @swagger
+   * /collections/{collection}/chunks:
+   *   post:
+   *     summary: Add embedded chunks to a collection
+   *     description: Adds chunks of documents with their embeddings to a collection, creating it if needed
+   *     tags:
+   *       - Collections
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: collection
+   *         required: true
+   *         schema:
+   *           type: string
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - chunks
+   *             properties:
+   *               chunks:
+   *                 type: array
+   *                 items:
+   *                   type: object
+   *                   properties:
+   *                     id:
+   *                       type: string
+   *                     document:
+   *                       type: string
+   *                     content:
+   *                       type: string
+   *                     embedding:
+   *                       type: array
+   *                       items:
+   *                         type: number
+   *     responses:
+   *       200:
+   *         description: Chunks added, with the number of chunks of the collection
+   *       400:
+   *         description: Invalid chunks
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot update the status of the knowledge base
+   */
+  router.post('/:collection/chunks', canWrite, (req, res) => {
+    try {
+      const total = vectors.addChunks(req.params.collection, req.body?.chunks);
+      res.json({ chunks: total });
+    } catch (error) {
+      console.error('Failed to add chunks:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /collections/{collection}/search:
+   *   post:
+   *     summary: Search a collection
+   *     description: Returns the chunks most similar to an embedding, most similar first
+   *     tags:
+   *       - Collections
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: collection
+   *         required: true
+   *         schema:
+   *           type: string
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - embedding
+   *             properties:
+   *               embedding:
+   *                 type: array
+   *                 items:
+   *                   type: number
+   *               top_k:
+   *                 type: integer
+   *                 default: 4
+   *     responses:
+   *       200:
+   *         description: The matching chunks with their cosine similarity as score
+   *       404:
+   *         description: Collection not found
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot get the knowledge base
+   */
+  router.post('/:collection/search', canRead, (req, res) => {
+    try {
+      const topK = parseInt(req.body?.top_k, 10) || 4;
+      const chunks = vectors.search(req.params.collection, req.body?.embedding, topK);
+      if (!chunks) {
+        res.status(404).json({ error: 'Collection not found' });
+        return;
+      }
+      res.json({ chunks });
+    } catch (error) {
+      console.error('Failed to search collection:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /collections/{collection}:
+   *   delete:
+   *     summary: Delete a collection
+   *     tags:
+   *       - Collections
+   *     security:
+   *       - bearerAuth: []
+   *     parameters:
+   *       - in: path
+   *         name: collection
+   *         required: true
+   *         schema:
+   *           type: string
+   *     responses:
+   *       204:
+   *         description: Collection deleted
+   *       404:
+   *         description: Collection not found
+   *       401:
+   *         description: Missing or invalid bearer token
+   *       403:
+   *         description: Caller cannot update the status of the knowledge base
+   */
+  router.delete('/:collection', canWrite, (req, res) => {
+    try {
+      if (!vectors.deleteCollection(req.params.collection)) {
+        res.status(404).json({ error: 'Collection not found' });
+        return;
+      }
+      res.status(204).send();
+    } catch (error) {
+      console.error('Failed to delete collection:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  return router;
+}
@@ -1,5 +1,10 @@
-import { Router } from 'express';
-import { MemoryStore } from '../memory-store.js';
+import express, { Router } from 'express';
+import { IdempotencyKeyReusedError, MemoryStore, SessionExistsError } from '../memory-store.js';
+import { StoredMessage } from '../types.js';
+import { recordMessagesStored } from '../metrics.js';
+
+// Session exports are JSON Lines, one stored message per line
+const JSONL_CONTENT_TYPE = 'application/x-ndjson';
 
 export function createMemoryRouter(memory: MemoryStore): Router {
   const router = Router();
@@ -12,6 +17,12 @@ export function createMemoryRouter(memory: MemoryStore): Router {
    *     description: Stores chat messages for a specific session and query
    *     tags:
    *       - Memory
+   *     parameters:
+   *       - in: header
+   *         name: Idempotency-Key
+   *         schema:
+   *           type: string
+   *         description: Key of the request. A retry with the same key is not stored again
    *     requestBody:
    *       required: true
    *       content:
@@ -34,15 +45,20 @@ export function createMemoryRouter(memory: MemoryStore): Router {
    *                 description: Array of OpenAI-format messages
    *                 items:
    *                   type: object
+   *               annotations:
+   *                 type: object
+   *                 description: Annotations of the last message, such as the provenance of the tool results a response was derived from
    *     responses:
    *       200:
-   *         description: Messages stored successfully
+   *         description: Messages stored successfully, or already stored by a request with the same Idempotency-Key
    *       400:
    *         description: Invalid request parameters
+   *       422:
+   *         description: The Idempotency-Key was used for a different request
    */
   router.post('/messages', (req, res) => {
     try {
-      const { session_id, query_id, messages } = req.body;
+      const { session_id, query_id, messages, annotations } = req.body;
       
       console.log(`POST /messages - session_id: ${session_id}, query_id: ${query_id}, messages: ${messages?.length}`);
       
@@ -61,24 +77,39 @@ export function createMemoryRouter(memory: MemoryStore): Router {
         return;
       }
       
+      if (annotations !== undefined && (!annotations || typeof annotations !== 'object' || Array.isArray(annotations))) {
+        res.status(400).json({ error: 'annotations must be an object' });
+        return;
+      }
+      
       // Store messages with full metadata
-      memory.addMessagesWithMetadata(session_id, query_id, messages);
+      const stored = memory.addMessagesWithMetadata(session_id, query_id, messages, req.get('Idempotency-Key'), annotations);
+      if (stored) {
+        recordMessagesStored(session_id, messages.length);
+      } else {
+        res.set('Idempotent-Replayed', 'true');
+      }
       res.status(200).send();
     } catch (error) {
+      if (error instanceof IdempotencyKeyReusedError) {
+        res.status(422).json({ error: error.message });
+        return;
+      }
       console.error('Failed to add messages:', error);
       const err = error as Error;
       res.status(400).json({ error: err.message });
     }
   });
 
-  // GET /messages - returns messages
+  // GET /messages - returns messages, without soft deleted messages unless include_deleted=true
   router.get('/messages', (req, res) => {
     try {
       const session_id = req.query.session_id as string;
       const query_id = req.query.query_id as string;
+      const include_deleted = req.query.include_deleted === 'true';
       
       const allMessages = memory.getAllMessages();
-      let filteredMessages = allMessages;
+      let filteredMessages = include_deleted ? allMessages : allMessages.filter(m => !m.deleted_at);
       
       // Apply filters if provided
       if (session_id) {
@@ -98,6 +129,111 @@ export function createMemoryRouter(memory: MemoryStore): Router {
     }
   });
 
+  /**
+   * @swagger
+   * /messages/{id}:
+   *   patch:
+   *     summary: Annotate a message
+   *     description: Merges annotations such as feedback ratings, corrections or redaction flags into a message. Annotations set to null are removed.
+   *     tags:
+   *       - Memory
+   *     parameters:
+   *       - in: path
+   *         name: id
+   *         required: true
+   *         schema:
+   *           type: integer
+   *         description: Sequence number of the message
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - annotations
+   *             properties:
+   *               annotations:
+   *                 type: object
+   *     responses:
+   *       200:
+   *         description: The annotated message
+   *       400:
+   *         description: Invalid request parameters
+   *       404:
+   *         description: Message not found or deleted
+   */
+  router.patch('/messages/:id', (req, res) => {
+    try {
+      const id = Number(req.params.id);
+      const { annotations } = req.body;
+
+      if (!Number.isInteger(id)) {
+        res.status(400).json({ error: 'message id must be an integer' });
+        return;
+      }
+
+      if (!annotations || typeof annotations !== 'object' || Array.isArray(annotations)) {
+        res.status(400).json({ error: 'annotations object is required' });
+        return;
+      }
+
+      const message = memory.annotateMessage(id, annotations);
+      if (!message) {
+        res.status(404).json({ error: `message ${id} not found` });
+        return;
+      }
+      res.json(message);
+    } catch (error) {
+      console.error('Failed to annotate message:', error);
+      const err = error as Error;
+      res.status(500).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /messages/{id}:
+   *   delete:
+   *     summary: Soft delete a message
+   *     description: Marks a message as deleted. Deleted messages are kept but are only returned by GET /messages with include_deleted=true.
+   *     tags:
+   *       - Memory
+   *     parameters:
+   *       - in: path
+   *         name: id
+   *         required: true
+   *         schema:
+   *           type: integer
+   *         description: Sequence number of the message
+   *     responses:
+   *       204:
+   *         description: Message deleted
+   *       400:
+   *         description: Invalid message id
+   *       404:
+   *         description: Message not found or already deleted
+   */
+  router.delete('/messages/:id', (req, res) => {
+    try {
+      const id = Number(req.params.id);
+      if (!Number.isInteger(id)) {
+        res.status(400).json({ error: 'message id must be an integer' });
+        return;
+      }
+
+      if (!memory.deleteMessage(id)) {
+        res.status(404).json({ error: `message ${id} not found` });
+        return;
+      }
+      res.status(204).send();
+    } catch (error) {
+      console.error('Failed to delete message:', error);
+      const err = error as Error;
+      res.status(500).json({ error: err.message });
+    }
+  });
+
   // GET /memory-status - returns memory statistics summary
   router.get('/memory-status', (req, res) => {
     try {
@@ -149,6 +285,176 @@ export function createMemoryRouter(memory: MemoryStore): Router {
     }
   });
 
+  /**
+   * @swagger
+   * /sessions/{id}/export:
+   *   get:
+   *     summary: Export a session
+   *     description: Downloads every message of a session with its metadata as JSON Lines, including soft deleted messages
+   *     tags:
+   *       - Memory
+   *     parameters:
+   *       - in: path
+   *         name: id
+   *         required: true
+   *         schema:
+   *           type: string
+   *     responses:
+   *       200:
+   *         description: One stored message per line
+   *         content:
+   *           application/x-ndjson:
+   *             schema:
+   *               type: string
+   *       404:
+   *         description: Session not found
+   */
+  router.get('/sessions/:id/export', (req, res) => {
+    try {
+      const messages = memory.exportSession(req.params.id);
+      if (messages.length === 0) {
+        res.status(404).json({ error: `session ${req.params.id} not found` });
+        return;
+      }
+
+      res.setHeader('Content-Type', JSONL_CONTENT_TYPE);
+      res.setHeader('Content-Disposition', `attachment; filename="${encodeURIComponent(req.params.id)}.jsonl"`);
+      res.send(messages.map(m => JSON.stringify(m)).join('\n') + '\n');
+    } catch (error) {
+      console.error('Failed to export session:', error);
+      const err = error as Error;
+      res.status(500).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /sessions/{id}/import:
+   *   post:
+   *     summary: Import a session
+   *     description: Restores the messages of a session export into a session, which may have a different ID. Messages get new sequence numbers.
+   *     tags:
+   *       - Memory
+   *     parameters:
+   *       - in: path
+   *         name: id
+   *         required: true
+   *         schema:
+   *           type: string
+   *       - in: query
+   *         name: replace
+   *         schema:
+   *           type: boolean
+   *         description: Replace the messages of an existing session
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/x-ndjson:
+   *           schema:
+   *             type: string
+   *     responses:
+   *       201:
+   *         description: Session imported
+   *       400:
+   *         description: Invalid export
+   *       409:
+   *         description: Session already exists and replace is not set
+   */
+  router.post('/sessions/:id/import', express.text({ type: JSONL_CONTENT_TYPE, limit: '100mb' }), (req, res) => {
+    try {
+      if (typeof req.body !== 'string') {
+        res.status(400).json({ error: `request body must be ${JSONL_CONTENT_TYPE}` });
+        return;
+      }
+
+      const records: StoredMessage[] = [];
+      const lines = req.body.split('\n');
+      for (let i = 0; i < lines.length; i++) {
+        if (lines[i].trim() === '') continue;
+        try {
+          records.push(JSON.parse(lines[i]));
+        } catch {
+          res.status(400).json({ error: `line ${i + 1} is not valid JSON` });
+          return;
+        }
+      }
+
+      const imported = memory.importSession(req.params.id, records, req.query.replace === 'true');
+      res.status(201).json({ session_id: req.params.id, imported });
+    } catch (error) {
+      const err = error as Error;
+      if (error instanceof SessionExistsError) {
+        res.status(409).json({ error: err.message });
+        return;
+      }
+      console.error('Failed to import session:', error);
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  /**
+   * @swagger
+   * /sessions/{id}/compact:
+   *   post:
+   *     summary: Compact a session
+   *     description: Replaces the oldest messages of a session with a summary message. The replaced messages are archived.
+   *     tags:
+   *       - Memory
+   *     parameters:
+   *       - in: path
+   *         name: id
+   *         required: true
+   *         schema:
+   *           type: string
+   *     requestBody:
+   *       required: true
+   *       content:
+   *         application/json:
+   *           schema:
+   *             type: object
+   *             required:
+   *               - count
+   *               - summary
+   *             properties:
+   *               count:
+   *                 type: integer
+   *                 description: Number of the oldest messages to replace
+   *               summary:
+   *                 type: object
+   *                 description: OpenAI-format summary message
+   *     responses:
+   *       200:
+   *         description: The stored summary message
+   *       400:
+   *         description: Invalid request parameters
+   */
+  router.post('/sessions/:id/compact', (req, res) => {
+    try {
+      const { count, summary } = req.body;
+      if (!summary) {
+        res.status(400).json({ error: 'summary is required' });
+        return;
+      }
+
+      res.json(memory.compactSession(req.params.id, count, summary));
+    } catch (error) {
+      console.error('Failed to compact session:', error);
+      const err = error as Error;
+      res.status(400).json({ error: err.message });
+    }
+  });
+
+  // GET /sessions/:id/archive - returns the messages replaced by compaction summaries
+  router.get('/sessions/:id/archive', (req, res) => {
+    try {
+      res.json({ messages: memory.getArchivedMessages(req.params.id) });
+    } catch (error) {
+      console.error('Failed to get archived messages:', error);
+      const err = error as Error;
+      res.status(500).json({ error: err.message });
+    }
+  });
+
   /**
    * @swagger
    * /messages:
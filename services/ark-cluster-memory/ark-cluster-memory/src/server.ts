@@ -2,15 +2,31 @@ import express from 'express';
 import cors from 'cors';
 import { MemoryStore } from './memory-store.js';
 import { StreamStore } from './stream-store.js';
+import { AuditStore } from './audit-store.js';
+import { ArtifactStore } from './artifact-store.js';
+import { VectorStore } from './vector-store.js';
 import { createMemoryRouter } from './routes/memory.js';
 import { createStreamRouter } from './routes/stream.js';
+import { createAuditRouter } from './routes/audit.js';
+import { createArtifactRouter } from './routes/artifacts.js';
+import { createCollectionRouter } from './routes/collections.js';
+import { authorizerFromEnv } from './kube-auth.js';
+import { Gauge, metricsMiddleware, PROMETHEUS_CONTENT_TYPE, registry } from './metrics.js';
 
 const app = express();
 const memory = new MemoryStore();
 const stream = new StreamStore();
+const audit = new AuditStore();
+const artifacts = new ArtifactStore();
+const vectors = new VectorStore();
+const authorizer = authorizerFromEnv();
 
 // Middleware
 app.use(cors());
+// Embedded chunks are sent in batches, so collections get a larger body limit
+app.use('/collections', express.json({ limit: '50mb' }));
+// Artifacts hold responses too large for the query status, so they get a larger body limit
+app.use('/artifacts', express.json({ limit: '100mb' }));
 app.use(express.json({ limit: '10mb' }));
 
 // Request logging middleware
@@ -19,6 +35,20 @@ app.use((req, res, next) => {
   next();
 });
 
+// Request metrics, and logging of requests slower than SLOW_REQUEST_MS (default 1000, 0 disables)
+app.use(metricsMiddleware(parseInt(process.env.SLOW_REQUEST_MS || '1000', 10)));
+
+registry.register(new Gauge('ark_memory_sessions', 'Sessions held by the memory', () => memory.getStats().sessions));
+registry.register(new Gauge('ark_memory_messages', 'Messages held by the memory', () => memory.getStats().totalMessages));
+registry.register(new Gauge('ark_memory_collections', 'Knowledge base collections held by the memory', () => vectors.getStats().collections));
+registry.register(new Gauge('ark_memory_chunks', 'Knowledge base chunks held by the memory', () => vectors.getStats().chunks));
+registry.register(new Gauge('ark_memory_streams', 'Query streams held by the memory', () => Object.keys(stream.getAllStreams()).length));
+
+app.get('/metrics', (req, res) => {
+  res.set('Content-Type', PROMETHEUS_CONTENT_TYPE);
+  res.send(registry.render());
+});
+
 /**
  * @swagger
  * /health:
@@ -114,6 +144,9 @@ app.get('/stream-statistics', (req, res) => {
 // Mount route modules
 app.use('/', createMemoryRouter(memory));
 app.use('/stream', createStreamRouter(stream));
+app.use('/audit', createAuditRouter(audit, authorizer));
+app.use('/artifacts', createArtifactRouter(artifacts, authorizer));
+app.use('/collections', createCollectionRouter(vectors, authorizer));
 
 // Error handling
 app.use((err: Error, req: express.Request, res: express.Response, _next: express.NextFunction) => {
@@ -127,4 +160,4 @@ app.use((req, res) => {
 });
 
 export default app;
-export { memory, stream };
+export { memory, stream, audit, artifacts, vectors };
@@ -33,6 +33,15 @@ export function setupSwagger(app: Express, version: string): void {
         description: 'Real-time streaming operations for OpenAI-format chunks',
       },
     ],
+    components: {
+      securitySchemes: {
+        // Kubernetes bearer tokens, checked with the TokenReview and SubjectAccessReview APIs
+        bearerAuth: {
+          type: 'http',
+          scheme: 'bearer',
+        },
+      },
+    },
   },
   // In production, we run from dist; in dev, from src
   apis: process.env.NODE_ENV === 'production' 
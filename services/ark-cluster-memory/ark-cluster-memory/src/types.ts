@@ -1,13 +1,33 @@
 export type Message = unknown;
 
+export type MessageAnnotations = Record<string, unknown>;
+
 export interface StoredMessage {
   timestamp: string;
   session_id: string;
   query_id: string;
   message: Message;
   sequence: number;
+  annotations?: MessageAnnotations;
+  deleted_at?: string;
+  // Idempotency key of the request that stored the message
+  idempotency_key?: string;
+}
+
+// Message content encrypted with a data key, which is wrapped with the key key_id. data_key and
+// content are base64 encoded IVs, ciphertexts and authentication tags.
+export interface EncryptedMessage {
+  key_id: string;
+  data_key: string;
+  content: string;
 }
 
+// StoredMessage as it is written to files, with its message encrypted when encryption is enabled
+export type PersistedMessage = Omit<StoredMessage, 'message'> & {
+  message?: Message;
+  encrypted_message?: EncryptedMessage;
+};
+
 export interface AddMessageRequest {
   message: Message;
 }
@@ -41,4 +61,47 @@ export interface StreamResponse {
   model: string;
   choices?: StreamChoice[];
   error?: StreamError;
-}
\ No newline at end of file
+}
+export interface AuditRecord {
+  timestamp: string;
+  query: string;
+  namespace: string;
+  queryUid: string;
+  createdBy?: string;
+  serviceAccount?: string;
+  targets: string[];
+  toolCalls: unknown[];
+  modelCalls: unknown[];
+  tokenUsage: unknown;
+  status: string;
+  error?: string;
+  startTime: string;
+  duration: string;
+}
+
+export interface AuditFilter {
+  namespace?: string;
+  query?: string;
+  createdBy?: string;
+  status?: string;
+  since?: string;
+}
+
+export interface Artifact {
+  content: string;
+  raw: string;
+}
+
+export interface Chunk {
+  id: string;
+  document: string;
+  content: string;
+  embedding: number[];
+}
+
+export interface ChunkMatch {
+  id: string;
+  document: string;
+  content: string;
+  score: number;
+}
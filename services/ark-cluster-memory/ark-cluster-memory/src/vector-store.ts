@@ -0,0 +1,83 @@
+import { Chunk, ChunkMatch } from './types.js';
+
+// Collection names are a namespace, a knowledge base name and an indexing timestamp joined by dots
+const COLLECTION_PATTERN = /^[a-z0-9]([-a-z0-9.]*[a-z0-9])?$/;
+
+// Store of embedded document chunks searched by cosine similarity, used by knowledge bases.
+// Collections are kept in memory and indexed again by the controller after a restart.
+export class VectorStore {
+  private collections: Map<string, Chunk[]> = new Map();
+
+  addChunks(collection: string, chunks: Chunk[]): number {
+    this.validateCollection(collection);
+    if (!Array.isArray(chunks)) {
+      throw new Error('chunks must be an array');
+    }
+
+    const stored = this.collections.get(collection) || [];
+    for (const chunk of chunks) {
+      if (typeof chunk?.id !== 'string' || typeof chunk?.content !== 'string' || !Array.isArray(chunk?.embedding)) {
+        throw new Error('chunks need an id, content and embedding');
+      }
+      if (stored.length > 0 && stored[0].embedding.length !== chunk.embedding.length) {
+        throw new Error(`embedding has ${chunk.embedding.length} dimensions, the collection has ${stored[0].embedding.length}`);
+      }
+      stored.push({ id: chunk.id, document: chunk.document || '', content: chunk.content, embedding: chunk.embedding });
+    }
+    this.collections.set(collection, stored);
+    return stored.length;
+  }
+
+  search(collection: string, embedding: number[], topK: number): ChunkMatch[] | undefined {
+    this.validateCollection(collection);
+    const chunks = this.collections.get(collection);
+    if (!chunks) {
+      return undefined;
+    }
+    if (!Array.isArray(embedding) || embedding.length === 0) {
+      throw new Error('embedding must be a non-empty array');
+    }
+
+    return chunks
+      .map(chunk => ({ id: chunk.id, document: chunk.document, content: chunk.content, score: cosineSimilarity(embedding, chunk.embedding) }))
+      .sort((a, b) => b.score - a.score)
+      .slice(0, Math.max(1, topK));
+  }
+
+  deleteCollection(collection: string): boolean {
+    this.validateCollection(collection);
+    return this.collections.delete(collection);
+  }
+
+  getStats(): { collections: number; chunks: number } {
+    let chunks = 0;
+    for (const stored of this.collections.values()) {
+      chunks += stored.length;
+    }
+    return { collections: this.collections.size, chunks };
+  }
+
+  private validateCollection(collection: string): void {
+    if (!COLLECTION_PATTERN.test(collection)) {
+      throw new Error('collection must be lowercase alphanumeric characters, - or .');
+    }
+  }
+}
+
+function cosineSimilarity(a: number[], b: number[]): number {
+  if (a.length !== b.length) {
+    return 0;
+  }
+  let dot = 0;
+  let normA = 0;
+  let normB = 0;
+  for (let i = 0; i < a.length; i++) {
+    dot += a[i] * b[i];
+    normA += a[i] * a[i];
+    normB += b[i] * b[i];
+  }
+  if (normA === 0 || normB === 0) {
+    return 0;
+  }
+  return dot / (Math.sqrt(normA) * Math.sqrt(normB));
+}
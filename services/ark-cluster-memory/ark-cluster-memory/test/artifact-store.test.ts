@@ -0,0 +1,57 @@
+import { mkdtempSync, rmSync, existsSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { ArtifactStore } from '../src/artifact-store.js';
+
+describe('ArtifactStore', () => {
+  const originalDir = process.env.ARTIFACTS_DIR;
+
+  afterEach(() => {
+    if (originalDir === undefined) {
+      delete process.env.ARTIFACTS_DIR;
+    } else {
+      process.env.ARTIFACTS_DIR = originalDir;
+    }
+  });
+
+  test('should store, replace and delete artifacts in memory', () => {
+    delete process.env.ARTIFACTS_DIR;
+    const store = new ArtifactStore();
+
+    store.putArtifact('default', 'report-response-0', { content: 'first', raw: '[]' });
+    store.putArtifact('default', 'report-response-0', { content: 'second', raw: '[]' });
+    expect(store.getArtifact('default', 'report-response-0')).toEqual({ content: 'second', raw: '[]' });
+    expect(store.getArtifact('other', 'report-response-0')).toBeUndefined();
+
+    expect(store.deleteArtifact('default', 'report-response-0')).toBe(true);
+    expect(store.getArtifact('default', 'report-response-0')).toBeUndefined();
+    expect(store.deleteArtifact('default', 'report-response-0')).toBe(false);
+  });
+
+  test('should write artifacts to ARTIFACTS_DIR', () => {
+    const dir = mkdtempSync(join(tmpdir(), 'artifacts-'));
+    process.env.ARTIFACTS_DIR = dir;
+    try {
+      new ArtifactStore().putArtifact('default', 'report-response-0', { content: 'full', raw: '[]' });
+      expect(existsSync(join(dir, 'default', 'report-response-0.json'))).toBe(true);
+
+      // A new store reads the artifacts written before it started
+      const store = new ArtifactStore();
+      expect(store.getArtifact('default', 'report-response-0')).toEqual({ content: 'full', raw: '[]' });
+      expect(store.deleteArtifact('default', 'report-response-0')).toBe(true);
+      expect(existsSync(join(dir, 'default', 'report-response-0.json'))).toBe(false);
+    } finally {
+      rmSync(dir, { recursive: true, force: true });
+    }
+  });
+
+  test('should reject names that are not Kubernetes names', () => {
+    const store = new ArtifactStore();
+    expect(() => store.putArtifact('default', '../secrets', { content: '', raw: '' })).toThrow('valid Kubernetes names');
+  });
+
+  test('should reject artifacts without content or raw', () => {
+    const store = new ArtifactStore();
+    expect(() => store.putArtifact('default', 'report', { content: 'only' } as never)).toThrow('content and raw must be strings');
+  });
+});
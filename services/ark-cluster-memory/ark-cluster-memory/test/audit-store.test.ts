@@ -0,0 +1,85 @@
+import { mkdtempSync, readFileSync, rmSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { AuditStore } from '../src/audit-store.js';
+import { AuditRecord } from '../src/types.js';
+
+const makeRecord = (overrides: Partial<AuditRecord> = {}): AuditRecord => ({
+  timestamp: '2025-01-01T00:00:00Z',
+  query: 'query-1',
+  namespace: 'default',
+  queryUid: 'uid-1',
+  createdBy: 'alice@example.com',
+  targets: ['agent/researcher'],
+  toolCalls: [],
+  modelCalls: [],
+  tokenUsage: { total_tokens: 10 },
+  status: 'done',
+  startTime: '2025-01-01T00:00:00Z',
+  duration: '1s',
+  ...overrides
+});
+
+describe('AuditStore', () => {
+  let store: AuditStore;
+
+  beforeEach(() => {
+    store = new AuditStore();
+  });
+
+  test('should append and return records', () => {
+    store.addRecord(makeRecord());
+    store.addRecord(makeRecord({ query: 'query-2' }));
+
+    expect(store.getRecords()).toHaveLength(2);
+  });
+
+  test('should reject records without query or namespace', () => {
+    expect(() => store.addRecord(makeRecord({ query: '' }))).toThrow('query and namespace are required');
+  });
+
+  test('should filter records', () => {
+    store.addRecord(makeRecord());
+    store.addRecord(makeRecord({ query: 'query-2', namespace: 'team-a', createdBy: 'bob@example.com', status: 'error' }));
+    store.addRecord(makeRecord({ query: 'query-3', timestamp: '2025-02-01T00:00:00Z' }));
+
+    expect(store.getRecords({ namespace: 'team-a' }).map(r => r.query)).toEqual(['query-2']);
+    expect(store.getRecords({ createdBy: 'alice@example.com' })).toHaveLength(2);
+    expect(store.getRecords({ status: 'error' })).toHaveLength(1);
+    expect(store.getRecords({ since: '2025-01-15T00:00:00Z' }).map(r => r.query)).toEqual(['query-3']);
+  });
+
+  test('should reject an invalid since timestamp', () => {
+    expect(() => store.getRecords({ since: 'yesterday' })).toThrow('Invalid since timestamp');
+  });
+
+  test('should keep only the latest records', () => {
+    const capped = new AuditStore(2);
+    capped.addRecord(makeRecord({ query: 'query-1' }));
+    capped.addRecord(makeRecord({ query: 'query-2' }));
+    capped.addRecord(makeRecord({ query: 'query-3' }));
+
+    expect(capped.getRecords().map(r => r.query)).toEqual(['query-2', 'query-3']);
+  });
+
+  test('should drop older records from the file', () => {
+    const dir = mkdtempSync(join(tmpdir(), 'audit-'));
+    const filePath = join(dir, 'audit.jsonl');
+    process.env.AUDIT_FILE_PATH = filePath;
+    try {
+      const capped = new AuditStore(2);
+      for (const query of ['query-1', 'query-2', 'query-3', 'query-4']) {
+        capped.addRecord(makeRecord({ query }));
+      }
+      const lines = readFileSync(filePath, 'utf-8').trim().split('\n');
+      expect(lines.map(line => JSON.parse(line).query)).toEqual(['query-3', 'query-4']);
+
+      capped.addRecord(makeRecord({ query: 'query-5' }));
+      expect(new AuditStore(2).getRecords().map(r => r.query)).toEqual(['query-4', 'query-5']);
+      expect(readFileSync(filePath, 'utf-8').trim().split('\n')).toHaveLength(2);
+    } finally {
+      delete process.env.AUDIT_FILE_PATH;
+      rmSync(dir, { recursive: true, force: true });
+    }
+  });
+});
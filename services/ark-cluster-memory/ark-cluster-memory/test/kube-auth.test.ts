@@ -0,0 +1,175 @@
+import express from 'express';
+import request from 'supertest';
+import { ArtifactStore } from '../src/artifact-store.js';
+import { AuditStore } from '../src/audit-store.js';
+import { Authorizer, ResourceAttributes, UserInfo } from '../src/kube-auth.js';
+import { createArtifactRouter } from '../src/routes/artifacts.js';
+import { createAuditRouter } from '../src/routes/audit.js';
+import { createCollectionRouter } from '../src/routes/collections.js';
+import { VectorStore } from '../src/vector-store.js';
+
+// fakeAuthorizer accepts the token "controller", which may update query and knowledge base status,
+// and "reader", which may list queries and get knowledge bases in the default namespace
+class FakeAuthorizer implements Authorizer {
+  checked: ResourceAttributes[] = [];
+
+  async authenticate(token: string): Promise<UserInfo | undefined> {
+    return ['controller', 'reader'].includes(token) ? { username: token } : undefined;
+  }
+
+  async authorize(user: UserInfo, attributes: ResourceAttributes): Promise<boolean> {
+    this.checked.push(attributes);
+    if (user.username === 'controller') {
+      return attributes.verb === 'update' && attributes.subresource === 'status';
+    }
+    return ['list', 'get'].includes(attributes.verb) && !attributes.subresource && attributes.namespace === 'default';
+  }
+}
+
+const record = {
+  timestamp: '2025-01-01T00:00:00Z',
+  query: 'query-1',
+  namespace: 'default',
+  queryUid: 'uid-1',
+  targets: [],
+  toolCalls: [],
+  modelCalls: [],
+  tokenUsage: {},
+  status: 'done',
+  startTime: '2025-01-01T00:00:00Z',
+  duration: '1s',
+};
+
+describe('Audit endpoint authorization', () => {
+  let authorizer: FakeAuthorizer;
+  let app: express.Express;
+
+  beforeEach(() => {
+    authorizer = new FakeAuthorizer();
+    app = express();
+    app.use(express.json());
+    app.use('/audit', createAuditRouter(new AuditStore(), authorizer));
+  });
+
+  test('should require a valid bearer token', async () => {
+    const missing = await request(app).post('/audit').send(record);
+    expect(missing.status).toBe(401);
+    expect(missing.headers['www-authenticate']).toBe('Bearer');
+
+    const invalid = await request(app).get('/audit?namespace=default').set('Authorization', 'Bearer forged');
+    expect(invalid.status).toBe(401);
+  });
+
+  test('should only accept records from callers that can update the query status', async () => {
+    const denied = await request(app).post('/audit').set('Authorization', 'Bearer reader').send(record);
+    expect(denied.status).toBe(403);
+
+    const written = await request(app).post('/audit').set('Authorization', 'Bearer controller').send(record);
+    expect(written.status).toBe(201);
+    expect(authorizer.checked).toContainEqual({
+      namespace: 'default', verb: 'update', group: 'ark.mckinsey.com', resource: 'queries', subresource: 'status', name: 'query-1',
+    });
+  });
+
+  test('should only return records of namespaces the caller can list queries in', async () => {
+    await request(app).post('/audit').set('Authorization', 'Bearer controller').send(record);
+
+    const allowed = await request(app).get('/audit?namespace=default').set('Authorization', 'Bearer reader');
+    expect(allowed.status).toBe(200);
+    expect(allowed.body.records).toHaveLength(1);
+
+    const otherNamespace = await request(app).get('/audit?namespace=team-a').set('Authorization', 'Bearer reader');
+    expect(otherNamespace.status).toBe(403);
+
+    const allNamespaces = await request(app).get('/audit').set('Authorization', 'Bearer reader');
+    expect(allNamespaces.status).toBe(403);
+  });
+});
+
+describe('Collection endpoint authorization', () => {
+  let authorizer: FakeAuthorizer;
+  let app: express.Express;
+  const chunks = [{ id: 'a', document: 'guide.md', content: 'alpha', embedding: [1, 0] }];
+
+  beforeEach(() => {
+    authorizer = new FakeAuthorizer();
+    app = express();
+    app.use(express.json());
+    app.use('/collections', createCollectionRouter(new VectorStore(), authorizer));
+  });
+
+  test('should require a valid bearer token', async () => {
+    const missing = await request(app).post('/collections/default.docs.1/search').send({ embedding: [1, 0] });
+    expect(missing.status).toBe(401);
+  });
+
+  test('should only let callers that can update the knowledge base status write', async () => {
+    const denied = await request(app).post('/collections/default.docs.1/chunks').set('Authorization', 'Bearer reader').send({ chunks });
+    expect(denied.status).toBe(403);
+
+    const written = await request(app).post('/collections/default.docs.1/chunks').set('Authorization', 'Bearer controller').send({ chunks });
+    expect(written.status).toBe(200);
+    expect(authorizer.checked).toContainEqual({
+      namespace: 'default', verb: 'update', group: 'ark.mckinsey.com', resource: 'knowledgebases', subresource: 'status', name: 'docs',
+    });
+
+    const deleted = await request(app).delete('/collections/default.docs.1').set('Authorization', 'Bearer reader');
+    expect(deleted.status).toBe(403);
+  });
+
+  test('should only let callers that can get the knowledge base search', async () => {
+    await request(app).post('/collections/default.docs.1/chunks').set('Authorization', 'Bearer controller').send({ chunks });
+
+    const allowed = await request(app).post('/collections/default.docs.1/search').set('Authorization', 'Bearer reader').send({ embedding: [1, 0] });
+    expect(allowed.status).toBe(200);
+
+    const otherNamespace = await request(app).post('/collections/team-a.docs.1/search').set('Authorization', 'Bearer reader').send({ embedding: [1, 0] });
+    expect(otherNamespace.status).toBe(403);
+
+    const unscoped = await request(app).post('/collections/docs/search').set('Authorization', 'Bearer reader').send({ embedding: [1, 0] });
+    expect(unscoped.status).toBe(403);
+  });
+});
+
+describe('Artifact endpoint authorization', () => {
+  let authorizer: FakeAuthorizer;
+  let app: express.Express;
+  const artifact = { content: 'full response', raw: '[]' };
+
+  beforeEach(() => {
+    authorizer = new FakeAuthorizer();
+    app = express();
+    app.use(express.json());
+    app.use('/artifacts', createArtifactRouter(new ArtifactStore(), authorizer));
+  });
+
+  test('should require a valid bearer token', async () => {
+    const missing = await request(app).get('/artifacts/default/report-response-0');
+    expect(missing.status).toBe(401);
+  });
+
+  test('should only let callers that can update the query status store and delete artifacts', async () => {
+    const denied = await request(app).put('/artifacts/default/report-response-0').set('Authorization', 'Bearer reader').send(artifact);
+    expect(denied.status).toBe(403);
+
+    const stored = await request(app).put('/artifacts/default/report-response-0').set('Authorization', 'Bearer controller').send(artifact);
+    expect(stored.status).toBe(204);
+    expect(authorizer.checked).toContainEqual({
+      namespace: 'default', verb: 'update', group: 'ark.mckinsey.com', resource: 'queries', subresource: 'status',
+    });
+
+    const deleted = await request(app).delete('/artifacts/default/report-response-0').set('Authorization', 'Bearer reader');
+    expect(deleted.status).toBe(403);
+  });
+
+  test('should only return artifacts of namespaces the caller can get queries in', async () => {
+    await request(app).put('/artifacts/default/report-response-0').set('Authorization', 'Bearer controller').send(artifact);
+
+    const allowed = await request(app).get('/artifacts/default/report-response-0').set('Authorization', 'Bearer reader');
+    expect(allowed.status).toBe(200);
+    expect(allowed.body.content).toBe('full response');
+
+    const otherNamespace = await request(app).get('/artifacts/team-a/report-response-0').set('Authorization', 'Bearer reader');
+    expect(otherNamespace.status).toBe(403);
+  });
+});
@@ -1,4 +1,4 @@
-import { MemoryStore } from '../src/memory-store.js';
+import { IdempotencyKeyReusedError, MemoryStore } from '../src/memory-store.js';
 
 describe('MemoryStore', () => {
   let store: MemoryStore;
@@ -140,6 +140,94 @@ describe('MemoryStore', () => {
       expect(allMessages[1].sequence).toBe(2);
       expect(allMessages[2].sequence).toBe(3);
     });
+
+    test('should store a request retried with the same idempotency key once', () => {
+      const messages = [{ role: 'user', content: 'Hello' }];
+
+      expect(store.addMessagesWithMetadata('session1', 'q1', messages, 'key-1')).toBe(true);
+      expect(store.addMessagesWithMetadata('session1', 'q1', messages, 'key-1')).toBe(false);
+      expect(store.getMessages('session1')).toHaveLength(1);
+
+      expect(() => store.addMessagesWithMetadata('session2', 'q1', messages, 'key-1'))
+        .toThrow(IdempotencyKeyReusedError);
+    });
+  });
+
+  describe('Message Review', () => {
+    test('should not reuse sequence numbers after a session is cleared', () => {
+      store.addMessage('session1', 'message1');
+      store.addMessage('session2', 'message2');
+      store.clearSession('session2');
+      store.addMessage('session1', 'message3');
+
+      const allMessages = store.getAllMessages();
+      expect(allMessages.map(m => m.sequence)).toEqual([1, 3]);
+    });
+
+    test('should merge annotations into a message', () => {
+      store.addMessage('session1', 'message1');
+
+      store.annotateMessage(1, { rating: 1 });
+      const annotated = store.annotateMessage(1, { redacted: true });
+
+      expect(annotated?.annotations).toEqual({ rating: 1, redacted: true });
+      expect(store.annotateMessage(99, { rating: 1 })).toBeUndefined();
+    });
+
+    test('should hide soft deleted messages from session and query reads', () => {
+      store.addMessagesWithMetadata('session1', 'query1', ['message1', 'message2']);
+
+      expect(store.deleteMessage(1)).toBe(true);
+
+      expect(store.getMessages('session1')).toEqual(['message2']);
+      expect(store.getMessagesByQuery('query1')).toEqual(['message2']);
+      expect(store.getAllMessages()).toHaveLength(2);
+      expect(store.deleteMessage(1)).toBe(false);
+    });
+  });
+
+  describe('Session Export and Import', () => {
+    test('should export deleted messages and import them with new sequence numbers', () => {
+      store.addMessagesWithMetadata('session1', 'query1', ['message1', 'message2']);
+      store.deleteMessage(2);
+
+      const exported = store.exportSession('session1');
+      expect(exported).toHaveLength(2);
+
+      expect(store.importSession('session2', exported, false)).toBe(2);
+      const imported = store.exportSession('session2');
+      expect(imported.map(m => m.sequence)).toEqual([3, 4]);
+      expect(imported[1].deleted_at).toBeDefined();
+      expect(store.getMessages('session2')).toEqual(['message1']);
+    });
+
+    test('should refuse to import into an existing session without replace', () => {
+      store.addMessage('session1', 'message1');
+
+      expect(() => store.importSession('session1', [], false)).toThrow('Session session1 already exists');
+      store.importSession('session1', [], true);
+      expect(store.getMessages('session1')).toEqual([]);
+    });
+  });
+
+  describe('Compaction', () => {
+    test('should replace the oldest messages with a summary and archive them', () => {
+      store.addMessages('session1', ['message1', 'message2', 'message3']);
+      store.addMessage('session2', 'other');
+
+      const summary = store.compactSession('session1', 2, { role: 'system', content: 'summary' });
+
+      expect(summary.annotations).toEqual({ summary_of: [1, 2] });
+      expect(store.getMessages('session1')).toEqual([{ role: 'system', content: 'summary' }, 'message3']);
+      expect(store.getArchivedMessages('session1').map(m => m.message)).toEqual(['message1', 'message2']);
+      expect(store.getMessages('session2')).toEqual(['other']);
+    });
+
+    test('should reject a count larger than the session', () => {
+      store.addMessage('session1', 'message1');
+
+      expect(() => store.compactSession('session1', 2, 'summary')).toThrow('count must be between 1 and 1');
+    });
   });
 
   describe('Stats and Health', () => {
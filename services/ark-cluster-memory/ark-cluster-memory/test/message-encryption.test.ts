@@ -0,0 +1,122 @@
+import { randomBytes } from 'crypto';
+import { mkdtempSync, readFileSync, rmSync, writeFileSync } from 'fs';
+import { tmpdir } from 'os';
+import { join } from 'path';
+import { MessageEncryption } from '../src/message-encryption.js';
+import { MemoryStore } from '../src/memory-store.js';
+
+describe('MessageEncryption', () => {
+  const oldKey = randomBytes(32);
+  const newKey = randomBytes(32);
+
+  test('should decrypt what it encrypts without storing the content in clear', () => {
+    const encryption = new MessageEncryption(new Map([['k1', oldKey]]), 'k1');
+    const encrypted = encryption.encrypt({ role: 'user', content: 'the launch code is 1234' });
+
+    expect(encrypted.key_id).toBe('k1');
+    expect(JSON.stringify(encrypted)).not.toContain('launch code');
+    expect(encryption.decrypt(encrypted)).toEqual({ role: 'user', content: 'the launch code is 1234' });
+  });
+
+  test('should decrypt messages of older keys after a rotation', () => {
+    const encrypted = new MessageEncryption(new Map([['k1', oldKey]]), 'k1').encrypt('hello');
+    const rotated = new MessageEncryption(new Map([['k1', oldKey], ['k2', newKey]]), 'k2');
+
+    expect(rotated.decrypt(encrypted)).toBe('hello');
+    expect(rotated.encrypt('hello').key_id).toBe('k2');
+    expect(() => new MessageEncryption(new Map([['k2', newKey]]), 'k2').decrypt(encrypted)).toThrow('Encryption key k1 not found');
+  });
+
+  test('should reject tampered content and invalid keys', () => {
+    const encryption = new MessageEncryption(new Map([['k1', oldKey]]), 'k1');
+    const encrypted = encryption.encrypt('hello');
+    const content = Buffer.from(encrypted.content, 'base64');
+    content[content.length - 1] ^= 1;
+
+    expect(() => encryption.decrypt({ ...encrypted, content: content.toString('base64') })).toThrow();
+    expect(() => new MessageEncryption(new Map([['k1', oldKey]]), 'k2')).toThrow('Active encryption key k2 not found');
+    expect(() => new MessageEncryption(new Map([['k1', randomBytes(16)]]), 'k1')).toThrow('is not a 256-bit key');
+  });
+
+  test('should read keys from MEMORY_ENCRYPTION_KEYS_DIR', () => {
+    const dir = mkdtempSync(join(tmpdir(), 'keys-'));
+    writeFileSync(join(dir, 'k1'), oldKey.toString('base64') + '\n');
+    writeFileSync(join(dir, '..data'), 'not a key');
+    process.env.MEMORY_ENCRYPTION_KEYS_DIR = dir;
+    process.env.MEMORY_ENCRYPTION_ACTIVE_KEY = 'k1';
+    try {
+      expect(MessageEncryption.fromEnv()?.activeKey).toBe('k1');
+
+      delete process.env.MEMORY_ENCRYPTION_ACTIVE_KEY;
+      expect(() => MessageEncryption.fromEnv()).toThrow('must be set together');
+
+      delete process.env.MEMORY_ENCRYPTION_KEYS_DIR;
+      expect(MessageEncryption.fromEnv()).toBeUndefined();
+    } finally {
+      delete process.env.MEMORY_ENCRYPTION_KEYS_DIR;
+      delete process.env.MEMORY_ENCRYPTION_ACTIVE_KEY;
+      rmSync(dir, { recursive: true, force: true });
+    }
+  });
+
+  describe('MemoryStore', () => {
+    let dir: string;
+    let memoryFile: string;
+    let archiveFile: string;
+
+    beforeEach(() => {
+      dir = mkdtempSync(join(tmpdir(), 'memory-'));
+      memoryFile = join(dir, 'memory.json');
+      archiveFile = join(dir, 'archive.jsonl');
+      process.env.MEMORY_FILE_PATH = memoryFile;
+      process.env.ARCHIVE_FILE_PATH = archiveFile;
+    });
+
+    afterEach(() => {
+      delete process.env.MEMORY_FILE_PATH;
+      delete process.env.ARCHIVE_FILE_PATH;
+      rmSync(dir, { recursive: true, force: true });
+    });
+
+    test('should encrypt persisted messages and return them in clear', () => {
+      const store = new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey]]), 'k1'));
+      store.addMessagesWithMetadata('session1', 'query1', [{ role: 'user', content: 'secret plans' }, { role: 'assistant', content: 'noted' }]);
+      store.compactSession('session1', 1, { role: 'system', content: 'summary' });
+
+      expect(readFileSync(memoryFile, 'utf-8')).not.toContain('secret plans');
+      expect(readFileSync(archiveFile, 'utf-8')).not.toContain('secret plans');
+      expect(store.getMessages('session1')).toEqual([{ role: 'system', content: 'summary' }, { role: 'assistant', content: 'noted' }]);
+
+      const reloaded = new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey]]), 'k1'));
+      expect(reloaded.getMessages('session1')).toEqual(store.getMessages('session1'));
+      expect(reloaded.getArchivedMessages('session1').map(m => m.message)).toEqual([{ role: 'user', content: 'secret plans' }]);
+    });
+
+    test('should rewrite stored messages with the active key on a rotation', () => {
+      const store = new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey]]), 'k1'));
+      store.addMessagesWithMetadata('session1', 'query1', [{ role: 'user', content: 'first' }, { role: 'assistant', content: 'second' }]);
+      store.compactSession('session1', 1, { role: 'system', content: 'summary' });
+
+      new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey], ['k2', newKey]]), 'k2'));
+
+      // The old key can be removed once the files were rewritten
+      const rotated = new MemoryStore(undefined, new MessageEncryption(new Map([['k2', newKey]]), 'k2'));
+      expect(rotated.getMessages('session1')).toEqual([{ role: 'system', content: 'summary' }, { role: 'assistant', content: 'second' }]);
+      expect(rotated.getArchivedMessages('session1').map(m => m.message)).toEqual([{ role: 'user', content: 'first' }]);
+    });
+
+    test('should encrypt messages stored before encryption was enabled', () => {
+      new MemoryStore().addMessage('session1', 'stored in clear');
+
+      new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey]]), 'k1'));
+      expect(readFileSync(memoryFile, 'utf-8')).not.toContain('stored in clear');
+    });
+
+    test('should refuse to start without the keys of encrypted messages', () => {
+      new MemoryStore(undefined, new MessageEncryption(new Map([['k1', oldKey]]), 'k1')).addMessage('session1', 'hello');
+
+      expect(() => new MemoryStore()).toThrow('Stored messages are encrypted');
+      expect(() => new MemoryStore(undefined, new MessageEncryption(new Map([['k2', newKey]]), 'k2'))).toThrow('Encryption key k1 not found');
+    });
+  });
+});
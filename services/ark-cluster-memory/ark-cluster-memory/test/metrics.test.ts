@@ -0,0 +1,50 @@
+import request from 'supertest';
+import { Counter, Histogram } from '../src/metrics.js';
+import app from '../src/server.js';
+
+describe('Metrics', () => {
+  test('should render counters with escaped labels', () => {
+    const counter = new Counter('test_total', 'Test counter');
+    counter.inc({ session: 'a"b' }, 2);
+    counter.inc({ session: 'a"b' });
+
+    expect(counter.get({ session: 'a"b' })).toBe(3);
+    expect(counter.render()).toEqual([
+      '# HELP test_total Test counter',
+      '# TYPE test_total counter',
+      'test_total{session="a\\"b"} 3'
+    ]);
+  });
+
+  test('should render cumulative histogram buckets', () => {
+    const histogram = new Histogram('test_seconds', 'Test histogram', [0.1, 1]);
+    histogram.observe({ route: '/messages' }, 0.0625);
+    histogram.observe({ route: '/messages' }, 0.5);
+    histogram.observe({ route: '/messages' }, 4);
+
+    expect(histogram.render()).toEqual(expect.arrayContaining([
+      'test_seconds_bucket{route="/messages",le="0.1"} 1',
+      'test_seconds_bucket{route="/messages",le="1"} 2',
+      'test_seconds_bucket{route="/messages",le="+Inf"} 3',
+      'test_seconds_sum{route="/messages"} 4.5625',
+      'test_seconds_count{route="/messages"} 3'
+    ]));
+  });
+
+  test('GET /metrics should report requests and stored messages', async () => {
+    await request(app)
+      .post('/messages')
+      .send({ session_id: 'metrics-session', query_id: 'q1', messages: [{ role: 'user', content: 'Hello' }] });
+
+    const response = await request(app).get('/metrics');
+
+    expect(response.status).toBe(200);
+    expect(response.headers['content-type']).toContain('text/plain');
+    expect(response.text).toContain('ark_memory_http_request_duration_seconds_count{method="POST",route="/messages",status="200"} 1');
+    expect(response.text).toContain('ark_memory_http_request_payload_bytes_count{method="POST",route="/messages"} 1');
+    expect(response.text).toContain('ark_memory_messages_stored_total{namespace=""} 1');
+    expect(response.text).toContain('ark_memory_messages 1');
+
+    await request(app).delete('/messages');
+  });
+});
@@ -168,6 +168,248 @@ describe('ARK Cluster Memory API', () => {
     });
   });
 
+  describe('Idempotent Writes', () => {
+    const body = { session_id: 'retry-session', query_id: 'q1', messages: [{ role: 'user', content: 'Hello' }] };
+
+    test('should store a retried request once', async () => {
+      const first = await request(app).post('/messages').set('Idempotency-Key', 'key-1').send(body);
+      const retry = await request(app).post('/messages').set('Idempotency-Key', 'key-1').send(body);
+
+      expect(first.status).toBe(200);
+      expect(first.headers['idempotent-replayed']).toBeUndefined();
+      expect(retry.status).toBe(200);
+      expect(retry.headers['idempotent-replayed']).toBe('true');
+
+      const response = await request(app).get('/messages?session_id=retry-session');
+      expect(response.body.messages).toHaveLength(1);
+    });
+
+    test('should reject a key reused for other messages', async () => {
+      await request(app).post('/messages').set('Idempotency-Key', 'key-2').send(body);
+      const response = await request(app)
+        .post('/messages')
+        .set('Idempotency-Key', 'key-2')
+        .send({ ...body, messages: [{ role: 'user', content: 'Other' }] });
+
+      expect(response.status).toBe(422);
+    });
+  });
+
+  describe('Message Review', () => {
+    test('should annotate a message and remove annotations set to null', async () => {
+      await request(app)
+        .post('/messages')
+        .send({ session_id: 'review-session', query_id: 'q1', messages: [{ role: 'assistant', content: 'Paris' }] });
+
+      const annotated = await request(app)
+        .patch('/messages/1')
+        .send({ annotations: { rating: 5, correction: 'Paris, France' } });
+      expect(annotated.status).toBe(200);
+      expect(annotated.body.annotations).toEqual({ rating: 5, correction: 'Paris, France' });
+
+      await request(app).patch('/messages/1').send({ annotations: { correction: null } });
+
+      const response = await request(app).get('/messages?session_id=review-session');
+      expect(response.body.messages[0].annotations).toEqual({ rating: 5 });
+    });
+
+    test('should store request annotations on the last message', async () => {
+      const provenance = [{ toolCallId: 'call-1', tool: 'get-weather', argumentsHash: 'abc', snippet: 'Sunny' }];
+      const stored = await request(app)
+        .post('/messages')
+        .send({
+          session_id: 'provenance-session',
+          query_id: 'q1',
+          messages: [{ role: 'tool', content: 'Sunny', tool_call_id: 'call-1' }, { role: 'assistant', content: 'It is sunny' }],
+          annotations: { provenance }
+        });
+      expect(stored.status).toBe(200);
+
+      const response = await request(app).get('/messages?session_id=provenance-session');
+      expect(response.body.messages[0].annotations).toBeUndefined();
+      expect(response.body.messages[1].annotations).toEqual({ provenance });
+    });
+
+    test('should reject annotations that are not an object', async () => {
+      const response = await request(app).patch('/messages/1').send({ annotations: ['flag'] });
+
+      expect(response.status).toBe(400);
+      expect(response.body.error).toBe('annotations object is required');
+    });
+
+    test('should soft delete a message so GET only returns it when asked', async () => {
+      await request(app)
+        .post('/messages')
+        .send({ session_id: 'review-session', query_id: 'q1', messages: [{ content: 'keep' }, { content: 'remove' }] });
+
+      const deleted = await request(app).delete('/messages/2');
+      expect(deleted.status).toBe(204);
+
+      const response = await request(app).get('/messages?session_id=review-session');
+      expect(response.body.messages).toHaveLength(1);
+      expect(response.body.messages[0].message).toEqual({ content: 'keep' });
+
+      const withDeleted = await request(app).get('/messages?session_id=review-session&include_deleted=true');
+      expect(withDeleted.body.messages).toHaveLength(2);
+      expect(withDeleted.body.messages[1].deleted_at).toBeDefined();
+
+      const again = await request(app).delete('/messages/2');
+      expect(again.status).toBe(404);
+    });
+  });
+
+  describe('Session Export and Import', () => {
+    test('should export a session as JSONL and import it under another ID', async () => {
+      await request(app)
+        .post('/messages')
+        .send({ session_id: 'prod-session', query_id: 'q1', messages: [{ content: 'first' }, { content: 'second' }] });
+      await request(app).patch('/messages/1').send({ annotations: { rating: 1 } });
+
+      const exported = await request(app).get('/sessions/prod-session/export');
+      expect(exported.status).toBe(200);
+      expect(exported.headers['content-type']).toContain('application/x-ndjson');
+      const lines = exported.text.trim().split('\n');
+      expect(lines).toHaveLength(2);
+
+      const imported = await request(app)
+        .post('/sessions/staging-session/import')
+        .set('Content-Type', 'application/x-ndjson')
+        .send(exported.text);
+      expect(imported.status).toBe(201);
+      expect(imported.body.imported).toBe(2);
+
+      const response = await request(app).get('/messages?session_id=staging-session');
+      expect(response.body.messages).toHaveLength(2);
+      expect(response.body.messages[0].query_id).toBe('q1');
+      expect(response.body.messages[0].annotations).toEqual({ rating: 1 });
+      expect(response.body.messages[0].sequence).toBe(3);
+    });
+
+    test('should only import into an existing session with replace', async () => {
+      await request(app)
+        .post('/messages')
+        .send({ session_id: 'existing', query_id: 'q1', messages: [{ content: 'old' }] });
+      const body = JSON.stringify({ message: { content: 'new' } }) + '\n';
+
+      const conflict = await request(app)
+        .post('/sessions/existing/import')
+        .set('Content-Type', 'application/x-ndjson')
+        .send(body);
+      expect(conflict.status).toBe(409);
+
+      const replaced = await request(app)
+        .post('/sessions/existing/import?replace=true')
+        .set('Content-Type', 'application/x-ndjson')
+        .send(body);
+      expect(replaced.status).toBe(201);
+
+      const response = await request(app).get('/messages?session_id=existing');
+      expect(response.body.messages.map((m: { message: unknown }) => m.message)).toEqual([{ content: 'new' }]);
+    });
+
+    test('should reject lines that are not JSON', async () => {
+      const response = await request(app)
+        .post('/sessions/broken/import')
+        .set('Content-Type', 'application/x-ndjson')
+        .send('{"message": "ok"}\nnot json\n');
+
+      expect(response.status).toBe(400);
+      expect(response.body.error).toBe('line 2 is not valid JSON');
+    });
+
+    test('should return 404 when exporting an unknown session', async () => {
+      const response = await request(app).get('/sessions/unknown/export');
+
+      expect(response.status).toBe(404);
+    });
+  });
+
+  describe('Session Compaction', () => {
+    test('should replace the oldest messages with the summary', async () => {
+      await request(app)
+        .post('/messages')
+        .send({ session_id: 'long-session', query_id: 'q1', messages: [{ content: 'a' }, { content: 'b' }, { content: 'c' }] });
+
+      const compacted = await request(app)
+        .post('/sessions/long-session/compact')
+        .send({ count: 2, summary: { role: 'system', content: 'a and b' } });
+      expect(compacted.status).toBe(200);
+
+      const response = await request(app).get('/messages?session_id=long-session');
+      expect(response.body.messages.map((m: { message: unknown }) => m.message)).toEqual([
+        { role: 'system', content: 'a and b' },
+        { content: 'c' }
+      ]);
+
+      const archive = await request(app).get('/sessions/long-session/archive');
+      expect(archive.body.messages).toHaveLength(2);
+    });
+
+    test('should require a summary', async () => {
+      const response = await request(app).post('/sessions/long-session/compact').send({ count: 1 });
+
+      expect(response.status).toBe(400);
+      expect(response.body.error).toBe('summary is required');
+    });
+  });
+
+  describe('Response Artifacts', () => {
+    test('should store and return an artifact', async () => {
+      const artifact = { content: 'x'.repeat(11 * 1024 * 1024), raw: '[]' };
+
+      const put = await request(app).put('/artifacts/default/report-response-0').send(artifact);
+      expect(put.status).toBe(204);
+
+      const get = await request(app).get('/artifacts/default/report-response-0');
+      expect(get.status).toBe(200);
+      expect(get.body.content).toHaveLength(artifact.content.length);
+
+      const del = await request(app).delete('/artifacts/default/report-response-0');
+      expect(del.status).toBe(204);
+    });
+
+    test('should return 404 for a missing artifact', async () => {
+      const response = await request(app).get('/artifacts/default/missing');
+
+      expect(response.status).toBe(404);
+      expect(response.body.error).toBe('Artifact not found');
+    });
+
+    test('should reject an invalid artifact name', async () => {
+      const response = await request(app).put('/artifacts/default/Invalid_Name').send({ content: '', raw: '' });
+
+      expect(response.status).toBe(400);
+    });
+  });
+
+  describe('Knowledge Base Collections', () => {
+    test('should add, search and delete chunks', async () => {
+      const chunks = [
+        { id: 'a-0', document: 'a', content: 'cats', embedding: [1, 0] },
+        { id: 'b-0', document: 'b', content: 'dogs', embedding: [0, 1] },
+      ];
+
+      const add = await request(app).post('/collections/default.docs.1/chunks').send({ chunks });
+      expect(add.status).toBe(200);
+      expect(add.body.chunks).toBe(2);
+
+      const search = await request(app).post('/collections/default.docs.1/search').send({ embedding: [0, 1], top_k: 1 });
+      expect(search.status).toBe(200);
+      expect(search.body.chunks).toHaveLength(1);
+      expect(search.body.chunks[0].content).toBe('dogs');
+
+      const del = await request(app).delete('/collections/default.docs.1');
+      expect(del.status).toBe(204);
+    });
+
+    test('should return 404 when searching a missing collection', async () => {
+      const response = await request(app).post('/collections/default.missing.1/search').send({ embedding: [1] });
+
+      expect(response.status).toBe(404);
+      expect(response.body.error).toBe('Collection not found');
+    });
+  });
+
   describe('Error Handling', () => {
     test('should return 404 for unknown routes', async () => {
       const response = await request(app).get('/unknown');
@@ -0,0 +1,3 @@
+// The server tests exercise the stores without a cluster to review tokens with. Authorization is
+// tested with a fake authorizer in kube-auth.test.ts.
+process.env.AUTH_MODE = process.env.AUTH_MODE || 'open';
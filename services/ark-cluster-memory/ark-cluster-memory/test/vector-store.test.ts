@@ -0,0 +1,40 @@
+import { VectorStore } from '../src/vector-store.js';
+
+describe('VectorStore', () => {
+  const chunks = [
+    { id: 'a-0', document: 'a', content: 'cats', embedding: [1, 0, 0] },
+    { id: 'b-0', document: 'b', content: 'dogs', embedding: [0, 1, 0] },
+    { id: 'c-0', document: 'c', content: 'kittens', embedding: [0.9, 0.1, 0] },
+  ];
+
+  test('should return the most similar chunks first', () => {
+    const store = new VectorStore();
+    expect(store.addChunks('default.docs.1', chunks)).toBe(3);
+
+    const matches = store.search('default.docs.1', [1, 0, 0], 2)!;
+    expect(matches.map(match => match.id)).toEqual(['a-0', 'c-0']);
+    expect(matches[0].score).toBeCloseTo(1);
+    expect(matches[0]).not.toHaveProperty('embedding');
+  });
+
+  test('should delete collections', () => {
+    const store = new VectorStore();
+    store.addChunks('default.docs.1', chunks);
+    expect(store.getStats()).toEqual({ collections: 1, chunks: 3 });
+
+    expect(store.deleteCollection('default.docs.1')).toBe(true);
+    expect(store.search('default.docs.1', [1, 0, 0], 2)).toBeUndefined();
+    expect(store.deleteCollection('default.docs.1')).toBe(false);
+  });
+
+  test('should reject embeddings with other dimensions', () => {
+    const store = new VectorStore();
+    store.addChunks('default.docs.1', chunks);
+    expect(() => store.addChunks('default.docs.1', [{ id: 'd-0', document: 'd', content: 'birds', embedding: [1, 0] }])).toThrow('2 dimensions');
+  });
+
+  test('should reject invalid collection names', () => {
+    const store = new VectorStore();
+    expect(() => store.addChunks('../docs', chunks)).toThrow('collection must be');
+  });
+});
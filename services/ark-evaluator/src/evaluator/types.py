@@ -209,6 +209,7 @@ class EvaluationResponse(BaseModel):
     metadata: Optional[Dict[str, str]] = None
     error: Optional[str] = None
     tokenUsage: Optional[TokenUsage] = Field(default_factory=lambda: TokenUsage())
+    metrics: Optional[Dict[str, str]] = None
 
 class MetricEvaluationResponse(BaseModel):
     """Response from metric evaluation"""
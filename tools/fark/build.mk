@@ -22,7 +22,12 @@ CLEAN_TARGETS += $(FARK_SERVICE_DIR)/.cover
 CLEAN_TARGETS += $(FARK_SERVICE_DIR)/vendor
 
 # Define phony targets
-.PHONY: $(FARK_SERVICE_NAME)-build $(FARK_SERVICE_NAME)-install $(FARK_SERVICE_NAME)-dev $(FARK_SERVICE_NAME)-test $(FARK_SERVICE_NAME)-uninstall
+.PHONY: $(FARK_SERVICE_NAME)-build $(FARK_SERVICE_NAME)-install $(FARK_SERVICE_NAME)-install-plugin $(FARK_SERVICE_NAME)-dev $(FARK_SERVICE_NAME)-test $(FARK_SERVICE_NAME)-uninstall $(FARK_SERVICE_NAME)-proto
+
+# Regenerate the gRPC bindings, requires protoc, protoc-gen-go and protoc-gen-go-grpc
+$(FARK_SERVICE_NAME)-proto:
+	cd $(FARK_SERVICE_DIR)/api && protoc --go_out=. --go_opt=paths=source_relative \
+		--go-grpc_out=. --go-grpc_opt=paths=source_relative ark/v1/ark.proto
 
 # Test target
 $(FARK_SERVICE_NAME)-test: $(FARK_STAMP_TEST)
@@ -68,6 +73,14 @@ $(FARK_STAMP_INSTALL): $(FARK_BINARY)
 	fi
 	@touch $@
 
+# Install fark as the kubectl-ark plugin, next to the installed fark
+$(FARK_SERVICE_NAME)-install-plugin: $(FARK_STAMP_INSTALL)
+	@FARK_PATH=$$(command -v fark || echo "$(HOME)/.local/bin/fark"); \
+	PLUGIN_PATH=$$(dirname "$$FARK_PATH")/kubectl-ark; \
+	if ln -sf "$$FARK_PATH" "$$PLUGIN_PATH" 2>/dev/null || sudo ln -sf "$$FARK_PATH" "$$PLUGIN_PATH"; then \
+		echo "kubectl-ark installed to $$PLUGIN_PATH, run it with: kubectl ark"; \
+	fi
+
 # Dev target
 $(FARK_SERVICE_NAME)-dev: $(FARK_BINARY)
 	$(FARK_BINARY)
@@ -90,3 +103,6 @@ $(FARK_SERVICE_NAME)-uninstall:
 			echo "fark removed from /usr/local/bin (with sudo)"; \
 		fi; \
 	fi
+	@for plugin in "$(HOME)/.local/bin/kubectl-ark" /usr/local/bin/kubectl-ark; do \
+		if [ -L "$$plugin" ]; then rm -f "$$plugin" 2>/dev/null || sudo rm -f "$$plugin"; fi; \
+	done
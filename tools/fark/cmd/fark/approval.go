@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+func createApproveCommand(config *Config) *cobra.Command {
+	var namespace string
+
+	cmd := &cobra.Command{
+		Use:   "approve [tool-approval]",
+		Short: "Approve a tool call waiting for approval",
+		Long: `Approve a call of a tool marked requiresApproval. The query that made the call runs the tool
+once it is approved.
+
+Without a name, lists the tool calls waiting for approval with their arguments.`,
+		Example: `  fark approve
+  fark approve deploy-query-3f9a2c1b7e4d5a60 -n production`,
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			if len(args) == 0 {
+				return listPendingToolApprovals(config, ns)
+			}
+			return decideToolApproval(config, ns, args[0], annotations.Approve, "")
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return getResourceCompletions(config, string(ResourceToolApproval), namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	return cmd
+}
+
+func createRejectCommand(config *Config) *cobra.Command {
+	var namespace string
+	var reason string
+
+	cmd := &cobra.Command{
+		Use:   "reject <tool-approval>",
+		Short: "Reject a tool call waiting for approval",
+		Long: `Reject a call of a tool marked requiresApproval. The tool is not run and the reason is returned
+to the model as the tool error, so the query continues without it.`,
+		Example: `  fark reject deploy-query-3f9a2c1b7e4d5a60 --reason "deploys are frozen"`,
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			return decideToolApproval(config, ns, args[0], annotations.Reject, reason)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return getResourceCompletions(config, string(ResourceToolApproval), namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVar(&reason, "reason", "", "Reason returned to the model")
+	return cmd
+}
+
+// decideToolApproval annotates a pending ToolApproval with the decision
+func decideToolApproval(config *Config, namespace, name, annotation, value string) error {
+	ctx := context.Background()
+	client := config.DynamicClient.Resource(GetGVR(ResourceToolApproval)).Namespace(namespace)
+
+	resource, err := client.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get tool approval '%s': %v", name, err)
+	}
+	var approval arkv1alpha1.ToolApproval
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.Object, &approval); err != nil {
+		return fmt.Errorf("failed to parse tool approval: %v", err)
+	}
+	if approval.Status.Phase != "" && approval.Status.Phase != arkv1alpha1.ToolApprovalPending {
+		return fmt.Errorf("tool approval '%s' is already %s", name, approval.Status.Phase)
+	}
+
+	patch, err := json.Marshal(map[string]any{
+		"metadata": map[string]any{
+			"annotations": map[string]string{annotation: value},
+		},
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := client.Patch(ctx, name, types.MergePatchType, patch, metav1.PatchOptions{}); err != nil {
+		return fmt.Errorf("failed to update tool approval '%s': %v", name, err)
+	}
+
+	decision := "approved"
+	if annotation == annotations.Reject {
+		decision = "rejected"
+	}
+	fmt.Fprintf(os.Stderr, "call of tool '%s' by query '%s' %s\n", approval.Spec.Tool, approval.Spec.Query, decision)
+	return nil
+}
+
+func listPendingToolApprovals(config *Config, namespace string) error {
+	list, err := config.DynamicClient.Resource(GetGVR(ResourceToolApproval)).Namespace(namespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to list tool approvals: %v", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tTOOL\tQUERY\tARGUMENTS")
+	for _, item := range list.Items {
+		var approval arkv1alpha1.ToolApproval
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(item.Object, &approval); err != nil {
+			return fmt.Errorf("failed to parse tool approval: %v", err)
+		}
+		if approval.Status.Phase != "" && approval.Status.Phase != arkv1alpha1.ToolApprovalPending {
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", approval.Name, approval.Spec.Tool, approval.Spec.Query, approval.Spec.Arguments)
+	}
+	return w.Flush()
+}
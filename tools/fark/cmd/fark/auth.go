@@ -0,0 +1,274 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// AuthMode controls how fark server authenticates and authorizes callers
+type AuthMode string
+
+const (
+	// AuthModeNone serves every caller with the server's own credentials
+	AuthModeNone AuthMode = "none"
+	// AuthModeImpersonate validates bearer tokens and impersonates the caller in every cluster request
+	AuthModeImpersonate AuthMode = "impersonate"
+	// AuthModeAccessReview validates bearer tokens and checks each request with a SubjectAccessReview
+	AuthModeAccessReview AuthMode = "access-review"
+)
+
+func parseAuthMode(value string) (AuthMode, error) {
+	switch mode := AuthMode(value); mode {
+	case AuthModeNone, AuthModeImpersonate, AuthModeAccessReview:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("invalid auth mode '%s'. Valid modes: [none impersonate access-review]", value)
+	}
+}
+
+type requestConfigKey struct{}
+
+// requestConfig returns the config for the caller of r, or config when the server does not authenticate
+func requestConfig(r *http.Request, config *Config) *Config {
+	return contextConfig(r.Context(), config)
+}
+
+// contextConfig returns the config for the caller attached to ctx, or config when there is none
+func contextConfig(ctx context.Context, config *Config) *Config {
+	if scoped, ok := ctx.Value(requestConfigKey{}).(*Config); ok {
+		return scoped
+	}
+	return config
+}
+
+// Authenticator validates bearer tokens with the Kubernetes TokenReview API, so any token the
+// API server accepts works, including OIDC tokens when the cluster is configured for them
+type Authenticator struct {
+	config    *Config
+	clientset kubernetes.Interface
+	mode      AuthMode
+	audiences []string
+	// runAsCaller runs the queries submitted by a caller as the caller, with spec.impersonate
+	runAsCaller bool
+}
+
+func NewAuthenticator(config *Config, mode AuthMode, audiences []string, runAsCaller bool) (*Authenticator, error) {
+	if runAsCaller && mode == AuthModeNone {
+		return nil, fmt.Errorf("--run-as-caller requires --auth impersonate or access-review")
+	}
+	clientset, err := kubernetes.NewForConfig(config.RestConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kubernetes client: %v", err)
+	}
+	return &Authenticator{config: config, clientset: clientset, mode: mode, audiences: audiences, runAsCaller: runAsCaller}, nil
+}
+
+// Middleware authenticates the caller and attaches a config scoped to them to the request
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	if a.mode == AuthModeNone {
+		return next
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && r.URL.Path == openAPIPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		verb, resource, ok := requiredAccess(r)
+		if !ok {
+			writeError(w, http.StatusForbidden, fmt.Sprintf("%s %s is not an allowed route", r.Method, r.URL.Path), nil)
+			return
+		}
+		token := bearerToken(r.Header.Get("Authorization"))
+		scoped, err := a.callerConfig(r.Context(), token, verb, resource)
+		if err != nil {
+			var callerErr *callerError
+			if !errors.As(err, &callerErr) {
+				writeError(w, http.StatusInternalServerError, "failed to authenticate caller", err)
+				return
+			}
+			if callerErr.status == http.StatusUnauthorized {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+			}
+			writeError(w, callerErr.status, callerErr.message, nil)
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestConfigKey{}, scoped)))
+	})
+}
+
+// bearerToken returns the token of an Authorization value, or "" when it is not a bearer token
+func bearerToken(authorization string) string {
+	token, found := strings.CutPrefix(authorization, "Bearer ")
+	if !found {
+		return ""
+	}
+	return token
+}
+
+// callerError is a failure to authenticate or authorize a caller, with the HTTP status it maps to
+type callerError struct {
+	status  int
+	message string
+}
+
+func (e *callerError) Error() string {
+	return e.message
+}
+
+// callerConfig authenticates the bearer token and returns a config scoped to its user, after
+// checking that the user may perform verb on resource in access-review mode. Both the REST and
+// the gRPC server use it, so callers get the same access either way.
+func (a *Authenticator) callerConfig(ctx context.Context, token, verb string, resource ResourceType) (*Config, error) {
+	if token == "" {
+		return nil, &callerError{status: http.StatusUnauthorized, message: "bearer token is required"}
+	}
+
+	user, err := a.authenticate(ctx, token)
+	if err != nil {
+		a.config.Logger.Info("Authentication failed", zap.Error(err))
+		return nil, &callerError{status: http.StatusUnauthorized, message: "invalid bearer token"}
+	}
+
+	var scoped *Config
+	switch a.mode {
+	case AuthModeImpersonate:
+		scoped, err = a.impersonatingConfig(user)
+		if err != nil {
+			return nil, err
+		}
+	case AuthModeAccessReview:
+		if err := a.authorize(ctx, user, verb, resource); err != nil {
+			return nil, &callerError{status: http.StatusForbidden, message: err.Error()}
+		}
+		scopedConfig := *a.config
+		scopedConfig.User = user.Username
+		scoped = &scopedConfig
+	}
+	if a.runAsCaller {
+		scoped.Impersonate = &arkv1alpha1.QueryImpersonation{User: user.Username, UID: user.UID, Groups: user.Groups}
+	}
+	return scoped, nil
+}
+
+func (a *Authenticator) authenticate(ctx context.Context, token string) (*authenticationv1.UserInfo, error) {
+	review := &authenticationv1.TokenReview{
+		Spec: authenticationv1.TokenReviewSpec{Token: token, Audiences: a.audiences},
+	}
+	result, err := a.clientset.AuthenticationV1().TokenReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("token review failed: %v", err)
+	}
+	if !result.Status.Authenticated {
+		return nil, fmt.Errorf("token not authenticated: %s", result.Status.Error)
+	}
+	return &result.Status.User, nil
+}
+
+// impersonatingConfig returns a config whose cluster requests are made as the user, so the
+// API server applies the user's RBAC and records them as the creator of queries
+func (a *Authenticator) impersonatingConfig(user *authenticationv1.UserInfo) (*Config, error) {
+	restConfig := rest.CopyConfig(a.config.RestConfig)
+	restConfig.Impersonate = rest.ImpersonationConfig{
+		UserName: user.Username,
+		UID:      user.UID,
+		Groups:   user.Groups,
+		Extra:    make(map[string][]string, len(user.Extra)),
+	}
+	for key, value := range user.Extra {
+		restConfig.Impersonate.Extra[key] = value
+	}
+
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client for user: %v", err)
+	}
+
+	scoped := *a.config
+	scoped.RestConfig = restConfig
+	scoped.DynamicClient = dynamicClient
+	scoped.User = user.Username
+	return &scoped, nil
+}
+
+// authorize checks with a SubjectAccessReview that the user may perform verb on resource
+func (a *Authenticator) authorize(ctx context.Context, user *authenticationv1.UserInfo, verb string, resource ResourceType) error {
+	extra := make(map[string]authorizationv1.ExtraValue, len(user.Extra))
+	for key, value := range user.Extra {
+		extra[key] = authorizationv1.ExtraValue(value)
+	}
+
+	review := &authorizationv1.SubjectAccessReview{
+		Spec: authorizationv1.SubjectAccessReviewSpec{
+			User:   user.Username,
+			UID:    user.UID,
+			Groups: user.Groups,
+			Extra:  extra,
+			ResourceAttributes: &authorizationv1.ResourceAttributes{
+				Namespace: a.config.Namespace,
+				Verb:      verb,
+				Group:     GetGVR(resource).Group,
+				Resource:  GetGVR(resource).Resource,
+			},
+		},
+	}
+	result, err := a.clientset.AuthorizationV1().SubjectAccessReviews().Create(ctx, review, metav1.CreateOptions{})
+	if err != nil {
+		return fmt.Errorf("access review failed: %v", err)
+	}
+	if !result.Status.Allowed {
+		return fmt.Errorf("user %s cannot %s %s in namespace %s", user.Username, verb, resource, a.config.Namespace)
+	}
+	return nil
+}
+
+// routeAccess maps the routes of the server, other than the list routes, to the permission they
+// need. Anything that runs a query needs create on queries, and streaming needs watch on queries.
+var routeAccess = []struct {
+	method string
+	prefix string
+	suffix string
+	verb   string
+}{
+	{http.MethodGet, "query/", "/stream", "watch"},
+	{http.MethodPost, "agent/", "", "create"},
+	{http.MethodPost, "team/", "", "create"},
+	{http.MethodPost, "model/", "", "create"},
+	{http.MethodPost, "tool/", "", "create"},
+	{http.MethodPost, "query/", "", "create"},
+	{http.MethodPost, "template/", "", "create"},
+}
+
+// requiredAccess maps a server route to the permission it needs. Listing needs list on the
+// resource. Routes without a mapping are not allowed, so a route added to the server is denied
+// until its permission is defined here.
+func requiredAccess(r *http.Request) (string, ResourceType, bool) {
+	path := strings.Trim(r.URL.Path, "/")
+	if r.Method == http.MethodGet {
+		if resourceType, err := listableResourceType(path); err == nil {
+			return "list", resourceType, true
+		}
+	}
+	for _, route := range routeAccess {
+		name, found := strings.CutPrefix(path, route.prefix)
+		if r.Method == route.method && found && name != "" && strings.HasSuffix(name, route.suffix) {
+			return route.verb, ResourceQuery, true
+		}
+	}
+	return "", "", false
+}
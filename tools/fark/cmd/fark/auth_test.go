@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.uber.org/zap"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	authorizationv1 "k8s.io/api/authorization/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	arkv1 "mckinsey.com/ark/tools/fark/api/ark/v1"
+)
+
+// newTestAuthenticator accepts the token "valid" as user alice, who may only create queries. The
+// token "unreviewable" fails the token review itself.
+func newTestAuthenticator() *Authenticator {
+	clientset := fake.NewSimpleClientset()
+	clientset.PrependReactor("create", "tokenreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authenticationv1.TokenReview)
+		switch review.Spec.Token {
+		case "unreviewable":
+			return true, nil, errors.New("token review unavailable")
+		case "valid":
+			review.Status = authenticationv1.TokenReviewStatus{Authenticated: true, User: authenticationv1.UserInfo{Username: "alice"}}
+		}
+		return true, review, nil
+	})
+	clientset.PrependReactor("create", "subjectaccessreviews", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		review := action.(k8stesting.CreateAction).GetObject().(*authorizationv1.SubjectAccessReview)
+		attributes := review.Spec.ResourceAttributes
+		review.Status.Allowed = review.Spec.User == "alice" && attributes.Verb == "create" &&
+			attributes.Resource == "queries" && attributes.Namespace == "default"
+		return true, review, nil
+	})
+
+	config := &Config{Namespace: "default", Logger: zap.NewNop()}
+	return &Authenticator{config: config, clientset: clientset, mode: AuthModeAccessReview}
+}
+
+func TestAuthenticatorMiddleware(t *testing.T) {
+	authenticator := newTestAuthenticator()
+	var requestedBy string
+	handler := authenticator.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requestedBy = requestConfig(r, authenticator.config).User
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		token  string
+		want   int
+	}{
+		{name: "no token", method: http.MethodPost, path: "/agent/weather", want: http.StatusUnauthorized},
+		{name: "invalid token", method: http.MethodPost, path: "/agent/weather", token: "invalid", want: http.StatusUnauthorized},
+		{name: "failed token review", method: http.MethodPost, path: "/agent/weather", token: "unreviewable", want: http.StatusUnauthorized},
+		{name: "allowed", method: http.MethodPost, path: "/agent/weather", token: "valid", want: http.StatusOK},
+		{name: "denied by access review", method: http.MethodGet, path: "/agents", token: "valid", want: http.StatusForbidden},
+		{name: "unknown route", method: http.MethodDelete, path: "/agent/weather", token: "valid", want: http.StatusForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requestedBy = ""
+			request := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.token != "" {
+				request.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			recorder := httptest.NewRecorder()
+			handler.ServeHTTP(recorder, request)
+			if recorder.Code != tt.want {
+				t.Fatalf("got status %d, want %d: %s", recorder.Code, tt.want, recorder.Body.String())
+			}
+			if tt.want == http.StatusOK && requestedBy != "alice" {
+				t.Errorf("got caller %q, want alice", requestedBy)
+			}
+		})
+	}
+}
+
+func TestRequiredAccess(t *testing.T) {
+	tests := []struct {
+		method       string
+		path         string
+		wantVerb     string
+		wantResource ResourceType
+		wantAllowed  bool
+	}{
+		{http.MethodGet, "/agents", "list", ResourceAgent, true},
+		{http.MethodGet, "/queries", "list", ResourceQuery, true},
+		{http.MethodGet, "/query/weather/stream", "watch", ResourceQuery, true},
+		{http.MethodPost, "/agent/weather", "create", ResourceQuery, true},
+		{http.MethodPost, "/team/research", "create", ResourceQuery, true},
+		{http.MethodPost, "/query/weather", "create", ResourceQuery, true},
+		{http.MethodPost, "/template/summary", "create", ResourceQuery, true},
+		{http.MethodGet, "/query/weather", "", "", false},
+		{http.MethodPost, "/agents", "", "", false},
+		{http.MethodPost, "/agent/", "", "", false},
+		{http.MethodDelete, "/query/weather", "", "", false},
+		{http.MethodGet, "/secrets", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			verb, resource, allowed := requiredAccess(httptest.NewRequest(tt.method, tt.path, nil))
+			if verb != tt.wantVerb || resource != tt.wantResource || allowed != tt.wantAllowed {
+				t.Errorf("got (%q, %q, %v), want (%q, %q, %v)", verb, resource, allowed, tt.wantVerb, tt.wantResource, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestGRPCRequiredAccess(t *testing.T) {
+	tests := []struct {
+		name        string
+		method      string
+		req         any
+		wantVerb    string
+		wantAllowed bool
+	}{
+		{"list", arkv1.ArkService_ListResources_FullMethodName, &arkv1.ListResourcesRequest{Kind: "agents"}, "list", true},
+		{"list unknown kind", arkv1.ArkService_ListResources_FullMethodName, &arkv1.ListResourcesRequest{Kind: "secrets"}, "", false},
+		{"submit", arkv1.ArkService_SubmitQuery_FullMethodName, &arkv1.SubmitQueryRequest{}, "create", true},
+		{"stream", arkv1.ArkService_StreamQuery_FullMethodName, &arkv1.StreamQueryRequest{}, "watch", true},
+		{"unknown method", "/ark.v1.ArkService/DeleteQuery", nil, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verb, _, allowed := grpcRequiredAccess(tt.method, tt.req)
+			if verb != tt.wantVerb || allowed != tt.wantAllowed {
+				t.Errorf("got (%q, %v), want (%q, %v)", verb, allowed, tt.wantVerb, tt.wantAllowed)
+			}
+		})
+	}
+}
+
+func TestCallerConfigOverridesRequester(t *testing.T) {
+	scoped, err := newTestAuthenticator().callerConfig(context.Background(), "valid", "create", ResourceQuery)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scoped.User != "alice" {
+		t.Errorf("got user %q, want alice", scoped.User)
+	}
+}
@@ -0,0 +1,405 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/wait"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+const batchPollInterval = 2 * time.Second
+
+func createBatchCommand(config *Config) *cobra.Command {
+	var namespace string
+	var inputFile string
+	var target string
+	var inputTemplate string
+	var outputFile string
+	var evaluator string
+	var concurrency int
+	var timeout time.Duration
+	var keep bool
+
+	cmd := &cobra.Command{
+		Use:   "batch",
+		Short: "Run a query for every row of a CSV or JSONL file",
+		Long: `Run a query for every row of a CSV or JSONL file and write one JSON result per row.
+
+Each row becomes a query against the target. The row's "input" field is the query input and the
+other fields are template parameters. With --input, every field is a parameter of the given
+template instead. An "id" field is copied to the result to correlate rows.
+
+Results include responses, token usage and duration. With --evaluator, every completed query is
+evaluated and the score is added to its result. Queries are deleted once their result is written
+unless --keep is set.`,
+		Example: `  fark batch -f inputs.jsonl --target agent/my-agent --concurrency 5 --output results.jsonl
+  fark batch -f cities.csv --target team/planner --input "Plan a weekend in {{.city}}"
+  fark batch -f regression.jsonl --target agent/my-agent --evaluator quality`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			targetType, targetName, found := strings.Cut(target, "/")
+			if !found || targetName == "" {
+				return fmt.Errorf("--target must be in type/name format, e.g. agent/my-agent")
+			}
+			if err := validateTargetType(targetType); err != nil {
+				return err
+			}
+			if concurrency < 1 {
+				return fmt.Errorf("--concurrency must be at least 1")
+			}
+
+			rows, err := readBatchRows(inputFile)
+			if err != nil {
+				return err
+			}
+
+			out := os.Stdout
+			if outputFile != "" && outputFile != "-" {
+				file, err := os.Create(outputFile)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %v", err)
+				}
+				defer file.Close()
+				out = file
+			}
+
+			runner := &BatchRunner{
+				Config:        config,
+				Namespace:     getNamespaceOrDefault(namespace, config.Namespace),
+				Target:        arkv1alpha1.QueryTarget{Type: targetType, Name: targetName},
+				InputTemplate: inputTemplate,
+				Evaluator:     evaluator,
+				Concurrency:   concurrency,
+				Timeout:       timeout,
+				Keep:          keep,
+			}
+			return runner.Run(setupQueryContext(24*time.Hour, config.Logger), rows, out)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&inputFile, "file", "f", "", "CSV or JSONL file with one row per query")
+	cmd.Flags().StringVar(&target, "target", "", "Query target in type/name format, e.g. agent/my-agent")
+	cmd.Flags().StringVarP(&inputTemplate, "input", "i", "", "Input template for every row; row fields become parameters")
+	cmd.Flags().StringVarP(&outputFile, "output", "o", "-", "JSONL file for results, - for stdout")
+	cmd.Flags().StringVar(&evaluator, "evaluator", "", "Evaluator that scores each completed query")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 5, "Number of queries running at the same time")
+	cmd.Flags().DurationVar(&timeout, "timeout", arkv1alpha1.DefaultQueryTimeout, "Timeout for each query and evaluation")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep queries and evaluations after writing results")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	_ = cmd.MarkFlagRequired("file")
+	_ = cmd.MarkFlagRequired("target")
+	return cmd
+}
+
+// readBatchRows reads rows from a CSV file with a header line, or from a JSONL file
+func readBatchRows(path string) ([]map[string]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input file: %v", err)
+	}
+	defer file.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return readCSVRows(file)
+	}
+	return readJSONLRows(file)
+}
+
+func readCSVRows(r io.Reader) ([]map[string]string, error) {
+	records, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CSV: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("CSV file has no header line")
+	}
+
+	header := records[0]
+	rows := make([]map[string]string, 0, len(records)-1)
+	for _, record := range records[1:] {
+		row := make(map[string]string, len(header))
+		for i, column := range header {
+			if i < len(record) {
+				row[column] = record[i]
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func readJSONLRows(r io.Reader) ([]map[string]string, error) {
+	var rows []map[string]string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 3*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		var fields map[string]any
+		if err := json.Unmarshal([]byte(text), &fields); err != nil {
+			return nil, fmt.Errorf("line %d: invalid JSON: %v", line, err)
+		}
+
+		row := make(map[string]string, len(fields))
+		for key, value := range fields {
+			if s, ok := value.(string); ok {
+				row[key] = s
+				continue
+			}
+			encoded, _ := json.Marshal(value)
+			row[key] = string(encoded)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read input file: %v", err)
+	}
+	return rows, nil
+}
+
+// BatchResult is written as one JSON line per row
+type BatchResult struct {
+	Row        int                    `json:"row"`
+	ID         string                 `json:"id,omitempty"`
+	Query      string                 `json:"query"`
+	Phase      string                 `json:"phase"`
+	Input      string                 `json:"input"`
+	Parameters map[string]string      `json:"parameters,omitempty"`
+	Responses  []arkv1alpha1.Response `json:"responses,omitempty"`
+	TokenUsage arkv1alpha1.TokenUsage `json:"tokenUsage"`
+	Duration   string                 `json:"duration,omitempty"`
+	Evaluation *BatchEvaluationResult `json:"evaluation,omitempty"`
+	Error      string                 `json:"error,omitempty"`
+}
+
+// BatchEvaluationResult is the outcome of evaluating a batch query
+type BatchEvaluationResult struct {
+	Evaluator string `json:"evaluator"`
+	Phase     string `json:"phase"`
+	Score     string `json:"score,omitempty"`
+	Passed    bool   `json:"passed"`
+	Message   string `json:"message,omitempty"`
+}
+
+// BatchRunner submits a query per row with bounded concurrency
+type BatchRunner struct {
+	Config        *Config
+	Namespace     string
+	Target        arkv1alpha1.QueryTarget
+	InputTemplate string
+	Evaluator     string
+	Concurrency   int
+	Timeout       time.Duration
+	Keep          bool
+}
+
+// Run processes all rows and writes results in completion order
+func (b *BatchRunner) Run(ctx context.Context, rows []map[string]string, out io.Writer) error {
+	batchID := fmt.Sprintf("%d", time.Now().Unix())
+	encoder := json.NewEncoder(out)
+	var mu sync.Mutex
+	var failed int
+
+	semaphore := make(chan struct{}, b.Concurrency)
+	var wg sync.WaitGroup
+	for i, row := range rows {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(index int, row map[string]string) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+
+			result := b.runRow(ctx, batchID, index, row)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if result.Phase != "done" {
+				failed++
+			}
+			if err := encoder.Encode(result); err != nil {
+				b.Config.Logger.Error("Failed to write result", zap.Int("row", index), zap.Error(err))
+			}
+			fmt.Fprintf(os.Stderr, "row %d: %s\n", index, result.Phase)
+		}(i, row)
+	}
+	wg.Wait()
+
+	fmt.Fprintf(os.Stderr, "batch %s: %d rows, %d failed\n", batchID, len(rows), failed)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d queries did not complete", failed, len(rows))
+	}
+	return nil
+}
+
+func (b *BatchRunner) runRow(ctx context.Context, batchID string, index int, row map[string]string) BatchResult {
+	result := BatchResult{Row: index, ID: row["id"], Phase: "error"}
+
+	input, params := b.rowInput(row)
+	result.Input = input
+	if len(params) > 0 {
+		result.Parameters = make(map[string]string, len(params))
+		for _, param := range params {
+			result.Parameters[param.Name] = param.Value
+		}
+	}
+
+	// Query input is raw JSON, so the string is encoded to keep inputs like "42" as text
+	encoded, _ := json.Marshal(input)
+	query, err := createQuery(string(encoded), []arkv1alpha1.QueryTarget{b.Target}, b.Namespace, params, "")
+	if err != nil {
+		result.Error = fmt.Sprintf("failed to create query: %v", err)
+		return result
+	}
+	query.Name = fmt.Sprintf("batch-%s-%d", batchID, index)
+	query.Labels = map[string]string{annotations.Batch: batchID}
+	query.Spec.Timeout = &metav1.Duration{Duration: b.Timeout}
+	result.Query = query.Name
+
+	if err := submitQuery(b.Config, query); err != nil {
+		result.Error = fmt.Sprintf("failed to create query: %v", err)
+		return result
+	}
+	if !b.Keep {
+		defer cleanupQuery(b.Config, query.Name, b.Namespace, b.Config.Logger)
+	}
+
+	completed, err := b.waitForQuery(ctx, query.Name)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	result.Phase = completed.Status.Phase
+	result.Responses = completed.Status.Responses
+	result.TokenUsage = completed.Status.TokenUsage
+	if completed.Status.Duration != nil {
+		result.Duration = completed.Status.Duration.Duration.String()
+	}
+	if result.Phase == "error" {
+		result.Error = getQueryErrorFromEvents(b.Config.DynamicClient, query.Name, b.Namespace, b.Config.Logger)
+		return result
+	}
+
+	if b.Evaluator != "" {
+		result.Evaluation = b.evaluate(ctx, batchID, query.Name)
+	}
+	return result
+}
+
+// rowInput returns the query input and template parameters for a row
+func (b *BatchRunner) rowInput(row map[string]string) (string, []arkv1alpha1.Parameter) {
+	input := b.InputTemplate
+	var params []arkv1alpha1.Parameter
+	for key, value := range row {
+		if b.InputTemplate == "" && key == "input" {
+			input = value
+			continue
+		}
+		params = append(params, arkv1alpha1.Parameter{Name: key, Value: value})
+	}
+	return input, params
+}
+
+func (b *BatchRunner) waitForQuery(ctx context.Context, name string) (*arkv1alpha1.Query, error) {
+	var query *arkv1alpha1.Query
+	err := wait.PollUntilContextTimeout(ctx, batchPollInterval, b.Timeout+time.Minute, true, func(ctx context.Context) (bool, error) {
+		current, err := getExistingQuery(b.Config, name, b.Namespace)
+		if err != nil {
+			return false, err
+		}
+		query = current
+		switch current.Status.Phase {
+		case "done", "error", "canceled":
+			return true, nil
+		}
+		return false, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("query %s did not complete: %v", name, err)
+	}
+	return query, nil
+}
+
+// evaluate creates a query evaluation with the configured evaluator and waits for its result
+func (b *BatchRunner) evaluate(ctx context.Context, batchID, queryName string) *BatchEvaluationResult {
+	result := &BatchEvaluationResult{Evaluator: b.Evaluator, Phase: "error"}
+	evaluation := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "ark.mckinsey.com/v1alpha1",
+		"kind":       "Evaluation",
+		"metadata": map[string]any{
+			"name":      queryName + "-eval",
+			"namespace": b.Namespace,
+			"labels":    map[string]any{annotations.Batch: batchID},
+		},
+		"spec": map[string]any{
+			"type":      "query",
+			"evaluator": map[string]any{"name": b.Evaluator},
+			"config":    map[string]any{"queryRef": map[string]any{"name": queryName}},
+			"timeout":   b.Timeout.String(),
+		},
+	}}
+
+	client := b.Config.DynamicClient.Resource(GetGVR(ResourceEvaluation)).Namespace(b.Namespace)
+	if _, err := client.Create(ctx, evaluation, metav1.CreateOptions{}); err != nil {
+		result.Message = fmt.Sprintf("failed to create evaluation: %v", err)
+		return result
+	}
+	if !b.Keep {
+		defer func() {
+			if err := client.Delete(context.Background(), evaluation.GetName(), metav1.DeleteOptions{}); err != nil {
+				b.Config.Logger.Warn("Failed to delete evaluation", zap.Error(err))
+			}
+		}()
+	}
+
+	err := wait.PollUntilContextTimeout(ctx, batchPollInterval, b.Timeout+time.Minute, true, func(ctx context.Context) (bool, error) {
+		current, err := client.Get(ctx, evaluation.GetName(), metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		phase, _, _ := unstructured.NestedString(current.Object, "status", "phase")
+		if phase != "done" && phase != "error" {
+			return false, nil
+		}
+		result.Phase = phase
+		result.Score, _, _ = unstructured.NestedString(current.Object, "status", "score")
+		result.Passed, _, _ = unstructured.NestedBool(current.Object, "status", "passed")
+		result.Message, _, _ = unstructured.NestedString(current.Object, "status", "message")
+		return true, nil
+	})
+	if err != nil {
+		result.Message = fmt.Sprintf("evaluation did not complete: %v", err)
+	}
+	return result
+}
@@ -0,0 +1,410 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+// BenchmarkSuite is a standardized set of prompts run against every model
+type BenchmarkSuite struct {
+	Prompts     []string
+	Runs        int
+	Concurrency int
+}
+
+// benchmarkSuites are the built-in suites. latency sends short prompts one at a time, throughput
+// sends prompts with long answers concurrently.
+var benchmarkSuites = map[string]BenchmarkSuite{
+	"latency": {
+		Prompts: []string{
+			"Reply with the single word: ready",
+			"What is 17 + 25? Answer with the number only.",
+			"Name the capital of France in one word.",
+			"Translate 'good morning' to Spanish. Answer with the translation only.",
+			"Is 91 a prime number? Answer yes or no.",
+		},
+		Runs:        3,
+		Concurrency: 1,
+	},
+	"throughput": {
+		Prompts: []string{
+			"Write a summary of about 300 words of the history of the printing press.",
+			"Explain in about 300 words how TCP congestion control works.",
+			"Write a short story of about 300 words about a lighthouse keeper.",
+		},
+		Runs:        2,
+		Concurrency: 5,
+	},
+}
+
+func benchmarkSuiteNames() string {
+	names := make([]string, 0, len(benchmarkSuites))
+	for name := range benchmarkSuites {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+func createBenchmarkCommand(config *Config) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "benchmark",
+		Short: "Benchmark resources with standardized prompts",
+	}
+	cmd.AddCommand(createBenchmarkModelCommand(config))
+	return cmd
+}
+
+func createBenchmarkModelCommand(config *Config) *cobra.Command {
+	var namespace string
+	var suiteName string
+	var promptsFile string
+	var runs int
+	var concurrency int
+	var timeout time.Duration
+	var output string
+	var keep bool
+
+	cmd := &cobra.Command{
+		Use:   "model <model-name> [model-name...]",
+		Short: "Benchmark models with a standardized suite of prompts",
+		Long: `Benchmark models with a standardized suite of prompts and report latency percentiles,
+throughput, token usage, cost and failure rate per model.
+
+Every prompt of the suite is sent --runs times to each model as a query with a model target.
+Latency is the query duration reported by the controller. Throughput is measured over the
+wall-clock time of the model's benchmark. Cost is reported for models with pricing.
+
+Suites: ` + benchmarkSuiteNames() + `. With --prompts, the "input" field of every row of a CSV
+or JSONL file replaces the prompts of the suite.`,
+		Example: `  fark benchmark model gpt-4o --suite latency
+  fark benchmark model gpt-4o claude-sonnet --suite throughput --concurrency 10
+  fark benchmark model gpt-4o --prompts prompts.jsonl --runs 5 -o json`,
+		Args: cobra.MinimumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			suite, ok := benchmarkSuites[suiteName]
+			if !ok {
+				return fmt.Errorf("unknown suite '%s'. Valid suites: %s", suiteName, benchmarkSuiteNames())
+			}
+			if promptsFile != "" {
+				prompts, err := readBenchmarkPrompts(promptsFile)
+				if err != nil {
+					return err
+				}
+				suite.Prompts = prompts
+			}
+			if cmd.Flags().Changed("runs") {
+				suite.Runs = runs
+			}
+			if cmd.Flags().Changed("concurrency") {
+				suite.Concurrency = concurrency
+			}
+			if suite.Runs < 1 || suite.Concurrency < 1 {
+				return fmt.Errorf("--runs and --concurrency must be at least 1")
+			}
+			if output != OutputText && output != OutputJSON {
+				return fmt.Errorf("invalid output format '%s'. Valid formats: %s, %s", output, OutputText, OutputJSON)
+			}
+
+			runner := &BenchmarkRunner{
+				Config:    config,
+				Namespace: getNamespaceOrDefault(namespace, config.Namespace),
+				Suite:     suite,
+				Timeout:   timeout,
+				Keep:      keep,
+			}
+			report := runner.Run(setupQueryContext(24*time.Hour, config.Logger), suiteName, args)
+			if output == OutputJSON {
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(report)
+			}
+			return report.Print(os.Stdout)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVar(&suiteName, "suite", "latency", "Benchmark suite: "+benchmarkSuiteNames())
+	cmd.Flags().StringVar(&promptsFile, "prompts", "", "CSV or JSONL file whose input fields replace the prompts of the suite")
+	cmd.Flags().IntVarP(&runs, "runs", "r", 0, "Times every prompt is sent to each model (defaults to the suite's)")
+	cmd.Flags().IntVarP(&concurrency, "concurrency", "c", 0, "Number of queries running at the same time (defaults to the suite's)")
+	cmd.Flags().DurationVar(&timeout, "timeout", arkv1alpha1.DefaultQueryTimeout, "Timeout for each query")
+	cmd.Flags().StringVarP(&output, "output", "o", OutputText, "Output format: text or json")
+	cmd.Flags().BoolVar(&keep, "keep", false, "Keep the benchmark queries")
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	return cmd
+}
+
+func readBenchmarkPrompts(path string) ([]string, error) {
+	rows, err := readBatchRows(path)
+	if err != nil {
+		return nil, err
+	}
+	var prompts []string
+	for i, row := range rows {
+		input, ok := row["input"]
+		if !ok || input == "" {
+			return nil, fmt.Errorf("row %d has no input", i)
+		}
+		prompts = append(prompts, input)
+	}
+	if len(prompts) == 0 {
+		return nil, fmt.Errorf("prompts file %s has no rows", path)
+	}
+	return prompts, nil
+}
+
+// BenchmarkReport is the result of benchmarking models with a suite
+type BenchmarkReport struct {
+	Suite     string           `json:"suite"`
+	Namespace string           `json:"namespace"`
+	StartedAt time.Time        `json:"startedAt"`
+	Models    []ModelBenchmark `json:"models"`
+}
+
+// ModelBenchmark summarizes the queries sent to one model
+type ModelBenchmark struct {
+	Model       string                 `json:"model"`
+	Requests    int                    `json:"requests"`
+	Failures    int                    `json:"failures"`
+	FailureRate float64                `json:"failureRate"`
+	Latency     BenchmarkLatency       `json:"latency"`
+	Throughput  BenchmarkThroughput    `json:"throughput"`
+	TokenUsage  arkv1alpha1.TokenUsage `json:"tokenUsage"`
+	// Cost is the total cost of the queries, empty when the model has no pricing
+	Cost   string   `json:"cost,omitempty"`
+	Errors []string `json:"errors,omitempty"`
+}
+
+// BenchmarkLatency are latency percentiles of the completed queries
+type BenchmarkLatency struct {
+	P50  string `json:"p50,omitempty"`
+	P90  string `json:"p90,omitempty"`
+	P99  string `json:"p99,omitempty"`
+	Mean string `json:"mean,omitempty"`
+}
+
+// BenchmarkThroughput is measured over the wall-clock time of a model's benchmark
+type BenchmarkThroughput struct {
+	RequestsPerSecond         float64 `json:"requestsPerSecond"`
+	CompletionTokensPerSecond float64 `json:"completionTokensPerSecond"`
+}
+
+// benchmarkSample is the outcome of one benchmark query
+type benchmarkSample struct {
+	latency    time.Duration
+	tokenUsage arkv1alpha1.TokenUsage
+	cost       string
+	err        string
+}
+
+// BenchmarkRunner sends the prompts of a suite to models as queries with bounded concurrency
+type BenchmarkRunner struct {
+	Config    *Config
+	Namespace string
+	Suite     BenchmarkSuite
+	Timeout   time.Duration
+	Keep      bool
+}
+
+// Run benchmarks the models one after the other, so that they do not compete for resources
+func (b *BenchmarkRunner) Run(ctx context.Context, suiteName string, models []string) *BenchmarkReport {
+	report := &BenchmarkReport{Suite: suiteName, Namespace: b.Namespace, StartedAt: time.Now()}
+	benchmarkID := fmt.Sprintf("%d", report.StartedAt.Unix())
+	for _, model := range models {
+		if ctx.Err() != nil {
+			break
+		}
+		fmt.Fprintf(os.Stderr, "benchmarking model %s: %d queries\n", model, len(b.Suite.Prompts)*b.Suite.Runs)
+		start := time.Now()
+		samples := b.runModel(ctx, benchmarkID, model)
+		report.Models = append(report.Models, summarizeBenchmark(model, samples, time.Since(start)))
+	}
+	return report
+}
+
+func (b *BenchmarkRunner) runModel(ctx context.Context, benchmarkID, model string) []benchmarkSample {
+	total := len(b.Suite.Prompts) * b.Suite.Runs
+	samples := make([]benchmarkSample, total)
+
+	semaphore := make(chan struct{}, b.Suite.Concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < total; i++ {
+		select {
+		case semaphore <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			samples = samples[:i]
+			break
+		}
+
+		wg.Add(1)
+		go func(index int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			prompt := b.Suite.Prompts[index%len(b.Suite.Prompts)]
+			samples[index] = b.runQuery(ctx, fmt.Sprintf("benchmark-%s-%s-%d", benchmarkID, model, index), model, prompt)
+		}(i)
+	}
+	wg.Wait()
+	return samples
+}
+
+func (b *BenchmarkRunner) runQuery(ctx context.Context, name, model, prompt string) benchmarkSample {
+	// Query input is raw JSON, so the string is encoded to keep inputs like "42" as text
+	encoded, _ := json.Marshal(prompt)
+	query, err := createQuery(string(encoded), []arkv1alpha1.QueryTarget{{Type: "model", Name: model}}, b.Namespace, nil, "")
+	if err != nil {
+		return benchmarkSample{err: fmt.Sprintf("failed to create query: %v", err)}
+	}
+	query.Name = name
+	query.Labels = map[string]string{annotations.Benchmark: model}
+	query.Spec.Timeout = &metav1.Duration{Duration: b.Timeout}
+
+	start := time.Now()
+	if err := submitQuery(b.Config, query); err != nil {
+		return benchmarkSample{err: fmt.Sprintf("failed to create query: %v", err)}
+	}
+	if !b.Keep {
+		defer cleanupQuery(b.Config, query.Name, b.Namespace, b.Config.Logger)
+	}
+
+	waiter := &BatchRunner{Config: b.Config, Namespace: b.Namespace, Timeout: b.Timeout}
+	completed, err := waiter.waitForQuery(ctx, query.Name)
+	if err != nil {
+		return benchmarkSample{err: err.Error()}
+	}
+
+	sample := benchmarkSample{latency: time.Since(start), tokenUsage: completed.Status.TokenUsage, cost: completed.Status.Cost}
+	if completed.Status.Duration != nil {
+		sample.latency = completed.Status.Duration.Duration
+	}
+	if completed.Status.Phase != "done" {
+		sample.err = getQueryErrorFromEvents(b.Config.DynamicClient, query.Name, b.Namespace, b.Config.Logger)
+		if sample.err == "" {
+			sample.err = "query phase " + completed.Status.Phase
+		}
+	}
+	return sample
+}
+
+// maxBenchmarkErrors limits the distinct errors listed per model
+const maxBenchmarkErrors = 5
+
+func summarizeBenchmark(model string, samples []benchmarkSample, elapsed time.Duration) ModelBenchmark {
+	result := ModelBenchmark{Model: model, Requests: len(samples)}
+	var latencies []time.Duration
+	var cost float64
+	priced := false
+	for _, sample := range samples {
+		result.TokenUsage.PromptTokens += sample.tokenUsage.PromptTokens
+		result.TokenUsage.CompletionTokens += sample.tokenUsage.CompletionTokens
+		result.TokenUsage.TotalTokens += sample.tokenUsage.TotalTokens
+		if value, err := strconv.ParseFloat(sample.cost, 64); err == nil {
+			cost += value
+			priced = true
+		}
+
+		if sample.err != "" {
+			result.Failures++
+			if len(result.Errors) < maxBenchmarkErrors && !slices.Contains(result.Errors, sample.err) {
+				result.Errors = append(result.Errors, sample.err)
+			}
+			continue
+		}
+		latencies = append(latencies, sample.latency)
+	}
+
+	if result.Requests > 0 {
+		result.FailureRate = float64(result.Failures) / float64(result.Requests)
+	}
+	if priced {
+		result.Cost = strconv.FormatFloat(cost, 'f', 6, 64)
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		var sum time.Duration
+		for _, latency := range latencies {
+			sum += latency
+		}
+		result.Latency = BenchmarkLatency{
+			P50:  percentile(latencies, 50).String(),
+			P90:  percentile(latencies, 90).String(),
+			P99:  percentile(latencies, 99).String(),
+			Mean: (sum / time.Duration(len(latencies))).Round(time.Millisecond).String(),
+		}
+	}
+	if seconds := elapsed.Seconds(); seconds > 0 {
+		result.Throughput = BenchmarkThroughput{
+			RequestsPerSecond:         roundTo(float64(len(latencies))/seconds, 3),
+			CompletionTokensPerSecond: roundTo(float64(result.TokenUsage.CompletionTokens)/seconds, 1),
+		}
+	}
+	return result
+}
+
+// percentile returns the nearest-rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p int) time.Duration {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1].Round(time.Millisecond)
+}
+
+func roundTo(value float64, decimals int) float64 {
+	rounded, _ := strconv.ParseFloat(strconv.FormatFloat(value, 'f', decimals, 64), 64)
+	return rounded
+}
+
+// Print writes the report as a table with one row per model, followed by the errors
+func (r *BenchmarkReport) Print(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "MODEL\tREQUESTS\tFAILURES\tP50\tP90\tP99\tREQ/S\tTOKENS/S\tTOKENS\tCOST")
+	for _, model := range r.Models {
+		cost := model.Cost
+		if cost == "" {
+			cost = "<none>"
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d (%.0f%%)\t%s\t%s\t%s\t%.3f\t%.1f\t%d\t%s\n",
+			model.Model, model.Requests, model.Failures, model.FailureRate*100,
+			orNone(model.Latency.P50), orNone(model.Latency.P90), orNone(model.Latency.P99),
+			model.Throughput.RequestsPerSecond, model.Throughput.CompletionTokensPerSecond,
+			model.TokenUsage.TotalTokens, cost)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	for _, model := range r.Models {
+		for _, err := range model.Errors {
+			fmt.Fprintf(w, "%s: %s\n", model.Model, err)
+		}
+	}
+	return nil
+}
+
+func orNone(value string) string {
+	if value == "" {
+		return "<none>"
+	}
+	return value
+}
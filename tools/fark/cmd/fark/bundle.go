@@ -0,0 +1,374 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/yaml"
+)
+
+// bundleKinds are the kinds of resources in a bundle, in the order they are imported so the
+// resources a resource references exist before it
+var bundleKinds = []struct {
+	Kind string
+	Type ResourceType
+}{
+	{"Model", ResourceModel},
+	{"Tool", ResourceTool},
+	{"Agent", ResourceAgent},
+	{"Team", ResourceTeam},
+	{"Evaluator", ResourceEvaluator},
+	{"QueryTemplate", ResourceQueryTemplate},
+}
+
+// bundleSecretFields are the fields holding credentials. Their inline values are not exported,
+// references to secrets are.
+var bundleSecretFields = map[string]bool{
+	"apiKey":          true,
+	"accessKeyId":     true,
+	"secretAccessKey": true,
+	"sessionToken":    true,
+}
+
+// bundleSetDefaultPaths are the fields --set sets when only a kind is given
+var bundleSetDefaultPaths = map[string]string{
+	"model": "spec.model.value",
+}
+
+func createExportCommand(config *Config) *cobra.Command {
+	var namespace, output string
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export the agentic resources of a namespace as a bundle",
+		Long: `Export the Models, Tools, Agents, Teams, Evaluators and QueryTemplates of a namespace as a
+bundle, a multi-document YAML file that fark import creates in another namespace or cluster.
+
+Resources are written in dependency order and without their namespace, status and server managed
+metadata. Inline values of credentials such as model API keys are not exported; references to
+secrets are, so the secrets must exist where the bundle is imported.`,
+		Example: `  fark export -n team-a -o bundle.yaml
+  fark export -n team-a > bundle.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			resources, removed, err := exportBundle(cmd.Context(), config, ns)
+			if err != nil {
+				return err
+			}
+			for _, field := range removed {
+				fmt.Fprintf(os.Stderr, "warning: inline credential %s not exported\n", field)
+			}
+
+			if output == "" || output == "-" {
+				return writeBundle(os.Stdout, resources)
+			}
+			file, err := os.Create(output)
+			if err != nil {
+				return fmt.Errorf("failed to create bundle file: %v", err)
+			}
+			defer func() { _ = file.Close() }()
+			if err := writeBundle(file, resources); err != nil {
+				return err
+			}
+			fmt.Fprintf(os.Stderr, "exported %d resources from namespace '%s' to %s\n", len(resources), ns, output)
+			return nil
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVarP(&output, "output", "o", "", "Bundle file to write (defaults to stdout)")
+	return cmd
+}
+
+func createImportCommand(config *Config) *cobra.Command {
+	var namespace string
+	var sets []string
+	var dryRun bool
+
+	cmd := &cobra.Command{
+		Use:   "import <bundle>",
+		Short: "Import a bundle of agentic resources",
+		Long: `Create or update the resources of a bundle written by fark export, in dependency order.
+
+Values of the bundle are overridden with --set kind[/name][.path]=value. The path is a dotted
+field path; without a name the value is set on every resource of the kind. A kind alone sets
+its most common field, for model the model name (spec.model.value).`,
+		Example: `  fark import bundle.yaml -n team-b
+  fark import bundle.yaml --set model=gpt-4o
+  fark import bundle.yaml --set model/default.spec.config.openai.baseUrl.value=https://llm.example.com/v1
+  fark import bundle.yaml --set agent/weather.spec.prompt="You report the weather"
+  fark import bundle.yaml --dry-run`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			file, err := os.Open(args[0])
+			if err != nil {
+				return fmt.Errorf("failed to open bundle: %v", err)
+			}
+			defer func() { _ = file.Close() }()
+
+			resources, err := readBundle(file)
+			if err != nil {
+				return err
+			}
+			if err := applyBundleOverrides(resources, sets); err != nil {
+				return err
+			}
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			for _, resource := range resources {
+				resource.SetNamespace(ns)
+			}
+
+			if dryRun {
+				return writeBundle(os.Stdout, resources)
+			}
+			return importBundle(cmd.Context(), config, resources)
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringArrayVar(&sets, "set", nil, "Override a value with kind[/name][.path]=value (can be used multiple times)")
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "Print the resources that would be imported without importing them")
+	return cmd
+}
+
+// exportBundle returns the bundle resources of the namespace in import order, and the inline
+// credentials that were removed from them
+func exportBundle(ctx context.Context, config *Config, namespace string) ([]*unstructured.Unstructured, []string, error) {
+	var resources []*unstructured.Unstructured
+	var removed []string
+	for _, kind := range bundleKinds {
+		list, err := config.DynamicClient.Resource(GetGVR(kind.Type)).Namespace(namespace).List(ctx, metav1.ListOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list %s: %v", kind.Type, err)
+		}
+		sort.Slice(list.Items, func(i, j int) bool { return list.Items[i].GetName() < list.Items[j].GetName() })
+
+		items := make([]*unstructured.Unstructured, 0, len(list.Items))
+		for i := range list.Items {
+			resource := bundleResource(&list.Items[i])
+			for _, field := range removeInlineCredentials(resource.Object, "") {
+				removed = append(removed, fmt.Sprintf("%s/%s %s", strings.ToLower(kind.Kind), resource.GetName(), field))
+			}
+			items = append(items, resource)
+		}
+		if kind.Type == ResourceTeam {
+			items = orderTeams(items)
+		}
+		resources = append(resources, items...)
+	}
+	return resources, removed, nil
+}
+
+// bundleResource returns the resource without its namespace, status and server managed metadata
+func bundleResource(resource *unstructured.Unstructured) *unstructured.Unstructured {
+	result := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": resource.GetAPIVersion(),
+		"kind":       resource.GetKind(),
+	}}
+	result.SetName(resource.GetName())
+	result.SetLabels(resource.GetLabels())
+	annotations := resource.GetAnnotations()
+	delete(annotations, "kubectl.kubernetes.io/last-applied-configuration")
+	if len(annotations) > 0 {
+		result.SetAnnotations(annotations)
+	}
+	if spec, found := resource.Object["spec"]; found {
+		result.Object["spec"] = spec
+	}
+	return result
+}
+
+// removeInlineCredentials removes the inline values of credential fields and returns their paths
+func removeInlineCredentials(obj map[string]any, path string) []string {
+	var removed []string
+	keys := make([]string, 0, len(obj))
+	for key := range obj {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		field, ok := obj[key].(map[string]any)
+		if !ok {
+			continue
+		}
+		fieldPath := strings.TrimPrefix(path+"."+key, ".")
+		if _, inline := field["value"]; inline && bundleSecretFields[key] {
+			delete(field, "value")
+			removed = append(removed, fieldPath)
+			continue
+		}
+		removed = append(removed, removeInlineCredentials(field, fieldPath)...)
+	}
+	return removed
+}
+
+// orderTeams orders teams so that teams are after the teams they have as members. Teams in a
+// membership cycle keep their order at the end.
+func orderTeams(teams []*unstructured.Unstructured) []*unstructured.Unstructured {
+	byName := make(map[string]*unstructured.Unstructured, len(teams))
+	for _, team := range teams {
+		byName[team.GetName()] = team
+	}
+
+	ordered := make([]*unstructured.Unstructured, 0, len(teams))
+	state := map[string]int{} // 1 while visiting, 2 once ordered
+	var visit func(team *unstructured.Unstructured)
+	visit = func(team *unstructured.Unstructured) {
+		if state[team.GetName()] != 0 {
+			return
+		}
+		state[team.GetName()] = 1
+		members, _, _ := unstructured.NestedSlice(team.Object, "spec", "members")
+		for _, m := range members {
+			member, ok := m.(map[string]any)
+			if !ok || member["type"] != "team" {
+				continue
+			}
+			if name, _ := member["name"].(string); byName[name] != nil {
+				visit(byName[name])
+			}
+		}
+		state[team.GetName()] = 2
+		ordered = append(ordered, team)
+	}
+	for _, team := range teams {
+		visit(team)
+	}
+	return ordered
+}
+
+// writeBundle writes the resources as a multi-document YAML bundle
+func writeBundle(w io.Writer, resources []*unstructured.Unstructured) error {
+	for _, resource := range resources {
+		data, err := yaml.Marshal(resource.Object)
+		if err != nil {
+			return fmt.Errorf("failed to marshal %s/%s: %v", resource.GetKind(), resource.GetName(), err)
+		}
+		if _, err := fmt.Fprintf(w, "---\n%s", data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readBundle reads the resources of a bundle in import order. Resources of the same kind keep
+// the order of the bundle, except teams that are moved after the teams they have as members.
+func readBundle(r io.Reader) ([]*unstructured.Unstructured, error) {
+	decoder := utilyaml.NewYAMLOrJSONDecoder(r, 4096)
+	byKind := make([][]*unstructured.Unstructured, len(bundleKinds))
+	for {
+		var obj map[string]any
+		if err := decoder.Decode(&obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return nil, fmt.Errorf("failed to parse bundle: %v", err)
+		}
+		if len(obj) == 0 {
+			continue
+		}
+		resource := &unstructured.Unstructured{Object: obj}
+		order := bundleKindOrder(resource.GetKind())
+		if order < 0 {
+			return nil, fmt.Errorf("unsupported kind %q in bundle, supported kinds are Model, Tool, Agent, Team, Evaluator and QueryTemplate", resource.GetKind())
+		}
+		if resource.GetName() == "" {
+			return nil, fmt.Errorf("%s in bundle has no name", resource.GetKind())
+		}
+		byKind[order] = append(byKind[order], resource)
+	}
+
+	var resources []*unstructured.Unstructured
+	for i, kind := range bundleKinds {
+		if kind.Type == ResourceTeam {
+			byKind[i] = orderTeams(byKind[i])
+		}
+		resources = append(resources, byKind[i]...)
+	}
+	return resources, nil
+}
+
+func bundleKindOrder(kind string) int {
+	for i, k := range bundleKinds {
+		if k.Kind == kind {
+			return i
+		}
+	}
+	return -1
+}
+
+// applyBundleOverrides applies --set values of the form kind[/name][.path]=value
+func applyBundleOverrides(resources []*unstructured.Unstructured, sets []string) error {
+	for _, set := range sets {
+		key, value, ok := strings.Cut(set, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid --set %q, expected kind[/name][.path]=value", set)
+		}
+		selector, path, _ := strings.Cut(key, ".")
+		kind, name, _ := strings.Cut(selector, "/")
+		if path == "" {
+			path = bundleSetDefaultPaths[kind]
+			if path == "" {
+				return fmt.Errorf("invalid --set %q, a field path is required for %s, e.g. %s/<name>.spec.description=value", set, kind, kind)
+			}
+		}
+
+		matched := false
+		for _, resource := range resources {
+			if strings.ToLower(resource.GetKind()) != kind || (name != "" && resource.GetName() != name) {
+				continue
+			}
+			if err := unstructured.SetNestedField(resource.Object, value, strings.Split(path, ".")...); err != nil {
+				return fmt.Errorf("failed to set %s on %s/%s: %v", path, kind, resource.GetName(), err)
+			}
+			matched = true
+		}
+		if !matched {
+			return fmt.Errorf("--set %q matches no resource in the bundle", set)
+		}
+	}
+	return nil
+}
+
+// importBundle creates the resources, or updates them when they exist
+func importBundle(ctx context.Context, config *Config, resources []*unstructured.Unstructured) error {
+	for _, resource := range resources {
+		kind := bundleKinds[bundleKindOrder(resource.GetKind())]
+		client := config.DynamicClient.Resource(GetGVR(kind.Type)).Namespace(resource.GetNamespace())
+		id := fmt.Sprintf("%s/%s", strings.ToLower(kind.Kind), resource.GetName())
+
+		existing, err := client.Get(ctx, resource.GetName(), metav1.GetOptions{})
+		switch {
+		case apierrors.IsNotFound(err):
+			if _, err := client.Create(ctx, resource, metav1.CreateOptions{}); err != nil {
+				return fmt.Errorf("failed to create %s: %v", id, err)
+			}
+			fmt.Printf("%s created\n", id)
+		case err != nil:
+			return fmt.Errorf("failed to get %s: %v", id, err)
+		default:
+			resource.SetResourceVersion(existing.GetResourceVersion())
+			if _, err := client.Update(ctx, resource, metav1.UpdateOptions{}); err != nil {
+				return fmt.Errorf("failed to update %s: %v", id, err)
+			}
+			fmt.Printf("%s configured\n", id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const chatHelp = `Commands:
+  /reset                              Start a new session, forgetting the conversation
+  /switch-target <agent|team> <name>  Continue the session with another target
+  /save <file>                        Save the transcript as markdown
+  /help                               Show this help
+  /exit                               Leave the chat`
+
+func createChatCommand(config *Config) *cobra.Command {
+	var namespace string
+	var sessionId string
+	var timeout time.Duration
+	var parameters []string
+	var verbose bool
+
+	cmd := &cobra.Command{
+		Use:   "chat <agent|team> <name>",
+		Short: "Chat interactively with an agent or team",
+		Long: `Open an interactive chat with an agent or team.
+
+Every message is sent as a query in the same session, so memory accumulates across turns.
+Responses are shown as they arrive. Type /help in the chat for the available commands.`,
+		Example: `  fark chat agent weather
+  fark chat team team-seq -n my-namespace
+  fark chat agent weather --session-id trip-planning`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			params, err := parseParameters(parameters)
+			if err != nil {
+				return fmt.Errorf("failed to parse parameters: %v", err)
+			}
+
+			session := &ChatSession{
+				Config:     config,
+				Namespace:  getNamespaceOrDefault(namespace, config.Namespace),
+				SessionId:  sessionId,
+				Timeout:    timeout,
+				Parameters: params,
+				Verbose:    verbose,
+			}
+			if err := session.setTarget(args[0], args[1]); err != nil {
+				return err
+			}
+			if session.SessionId == "" {
+				session.SessionId = newChatSessionId()
+			}
+			return session.Run(os.Stdin)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return []string{"agent", "team"}, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return getResourceCompletions(config, args[0]+"s", namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVar(&sessionId, "session-id", "", "Session ID to continue (defaults to a new session)")
+	cmd.Flags().DurationVar(&timeout, "timeout", arkv1alpha1.DefaultQueryTimeout, "Timeout for each message")
+	cmd.Flags().StringArrayVarP(&parameters, "param", "p", nil, "Template parameters in key=value format (can be used multiple times)")
+	cmd.Flags().BoolVarP(&verbose, "verbose", "v", false, "Show events while waiting for responses")
+	return cmd
+}
+
+// chatTurn is one message of a chat transcript
+type chatTurn struct {
+	Speaker string
+	Content string
+}
+
+// ChatSession sends each chat message as a query within one session
+type ChatSession struct {
+	Config     *Config
+	Namespace  string
+	TargetType string
+	TargetName string
+	SessionId  string
+	Timeout    time.Duration
+	Parameters []arkv1alpha1.Parameter
+	Verbose    bool
+	transcript []chatTurn
+}
+
+func newChatSessionId() string {
+	return fmt.Sprintf("fark-chat-%d", time.Now().Unix())
+}
+
+// Run reads messages from input until it is closed or the user exits
+func (s *ChatSession) Run(input io.Reader) error {
+	fmt.Fprintf(os.Stderr, "Chatting with %s/%s in session %s. Type /help for commands.\n", s.TargetType, s.TargetName, s.SessionId)
+
+	scanner := bufio.NewScanner(input)
+	scanner.Buffer(make([]byte, 0, 64*1024), 3*1024*1024)
+	for {
+		fmt.Fprint(os.Stderr, colorize("you> ", "36"))
+		if !scanner.Scan() {
+			fmt.Fprintln(os.Stderr)
+			return scanner.Err()
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			exit, err := s.handleCommand(line)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%s\n", colorize(err.Error(), "31"))
+			}
+			if exit {
+				return nil
+			}
+			continue
+		}
+
+		if err := s.send(line); err != nil {
+			fmt.Fprintf(os.Stderr, "%s\n", colorize(err.Error(), "31"))
+		}
+	}
+}
+
+func (s *ChatSession) handleCommand(line string) (bool, error) {
+	fields := strings.Fields(line)
+	switch fields[0] {
+	case "/exit", "/quit":
+		return true, nil
+	case "/help":
+		fmt.Fprintln(os.Stderr, chatHelp)
+	case "/reset":
+		s.SessionId = newChatSessionId()
+		s.transcript = nil
+		fmt.Fprintf(os.Stderr, "Started new session %s\n", s.SessionId)
+	case "/switch-target":
+		if len(fields) != 3 {
+			return false, fmt.Errorf("usage: /switch-target <agent|team> <name>")
+		}
+		if err := s.setTarget(fields[1], fields[2]); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(os.Stderr, "Now chatting with %s/%s\n", s.TargetType, s.TargetName)
+	case "/save":
+		if len(fields) != 2 {
+			return false, fmt.Errorf("usage: /save <file>")
+		}
+		if err := s.saveTranscript(fields[1]); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(os.Stderr, "Transcript saved to %s\n", fields[1])
+	default:
+		return false, fmt.Errorf("unknown command %s, type /help for commands", fields[0])
+	}
+	return false, nil
+}
+
+// setTarget checks that the agent or team exists before chatting with it
+func (s *ChatSession) setTarget(targetType, targetName string) error {
+	var resourceType ResourceType
+	switch targetType {
+	case "agent":
+		resourceType = ResourceAgent
+	case "team":
+		resourceType = ResourceTeam
+	default:
+		return fmt.Errorf("invalid target type '%s'. Valid types: [agent team]", targetType)
+	}
+
+	_, err := s.Config.DynamicClient.Resource(GetGVR(resourceType)).Namespace(s.Namespace).Get(context.TODO(), targetName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get %s '%s': %v", targetType, targetName, err)
+	}
+
+	s.TargetType = targetType
+	s.TargetName = targetName
+	return nil
+}
+
+// send runs one chat turn as a query and records the responses in the transcript
+func (s *ChatSession) send(message string) error {
+	targets := []arkv1alpha1.QueryTarget{{Type: s.TargetType, Name: s.TargetName}}
+	query, err := createQuery(message, targets, s.Namespace, s.Parameters, s.SessionId)
+	if err != nil {
+		return fmt.Errorf("failed to create query: %v", err)
+	}
+	// Turns can be less than a second apart, so the default name is not unique enough
+	query.Name = fmt.Sprintf("chat-%d", time.Now().UnixNano())
+
+	if err := submitQuery(s.Config, query); err != nil {
+		return fmt.Errorf("failed to create query: %v", err)
+	}
+	s.transcript = append(s.transcript, chatTurn{Speaker: "you", Content: message})
+
+	// Ctrl-C cancels the current turn and returns to the prompt
+	ctx, cancel := context.WithTimeout(context.Background(), s.Timeout)
+	defer cancel()
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+	defer stop()
+
+	id := &ResourceIdentifier{
+		Config:    s.Config,
+		Type:      ResourceQuery,
+		Name:      query.Name,
+		Namespace: s.Namespace,
+	}
+	opts := &OutputOptions{
+		OutputMode: "text",
+		Verbose:    s.Verbose,
+		Quiet:      !s.Verbose,
+		Watch:      true,
+	}
+	waitErr := waitForQueryCompletion(ctx, id, opts)
+
+	if completed, err := getExistingQuery(s.Config, query.Name, s.Namespace); err == nil {
+		for _, response := range completed.Status.Responses {
+			if response.Content != "" {
+				s.transcript = append(s.transcript, chatTurn{
+					Speaker: fmt.Sprintf("%s/%s", response.Target.Type, response.Target.Name),
+					Content: response.Content,
+				})
+			}
+		}
+	}
+	cleanupQuery(s.Config, query.Name, s.Namespace, s.Config.Logger)
+
+	return waitErr
+}
+
+func (s *ChatSession) saveTranscript(path string) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Chat with %s/%s\n\nSession: %s\n", s.TargetType, s.TargetName, s.SessionId)
+	for _, turn := range s.transcript {
+		fmt.Fprintf(&b, "\n**%s**: %s\n", turn.Speaker, turn.Content)
+	}
+	if err := os.WriteFile(path, []byte(b.String()), 0o644); err != nil {
+		return fmt.Errorf("failed to save transcript: %v", err)
+	}
+	return nil
+}
@@ -37,9 +37,11 @@ func handleSpinnerCommands(spinner *Spinner, command string) {
 	}
 }
 
-// handleResultError processes query result errors with cleanup
-func handleResultError(result *QueryResult, id *ResourceIdentifier) error {
-	cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+// handleResultError processes query result errors with cleanup. Watched queries are kept.
+func handleResultError(result *QueryResult, id *ResourceIdentifier, opts *OutputOptions) error {
+	if !opts.Watch {
+		cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+	}
 	return result.Error
 }
 
@@ -49,27 +51,84 @@ func handleEvent(result *QueryResult, logger *zap.Logger, opts *OutputOptions) {
 }
 
 // handleQueryCompletion processes completed queries
-func handleQueryCompletion(result *QueryResult, id *ResourceIdentifier, opts *OutputOptions) error {
+func handleQueryCompletion(result *QueryResult, id *ResourceIdentifier, opts *OutputOptions, responses *responseTracker) error {
 	if result.Phase == "done" {
-		printQueryResults(result.Query, opts.OutputMode)
-		cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+		if opts.Watch && opts.OutputMode == OutputText {
+			// Responses seen while the query was running are already printed
+			responses.printNew(result.Query)
+		} else {
+			printQueryResults(result.Query, opts.OutputMode)
+		}
+		if !opts.Watch {
+			cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+		}
 		return nil
 	}
 
 	if result.Phase == "error" {
 		errorMessage := getQueryErrorFromEvents(id.Config.DynamicClient, id.Name, id.Namespace, id.Config.Logger)
-		cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+		if !opts.Watch {
+			cleanupQuery(id.Config, id.Name, id.Namespace, id.Config.Logger)
+		}
 		return fmt.Errorf("query failed: %s", errorMessage)
 	}
 
 	return nil
 }
 
+// responseTracker prints query responses once, as they appear on a watched query
+type responseTracker struct {
+	printed map[string]bool
+}
+
+func newResponseTracker() *responseTracker {
+	return &responseTracker{printed: make(map[string]bool)}
+}
+
+func responseKey(response arkv1alpha1.Response) string {
+	return response.Target.Type + "/" + response.Target.Name + "\x00" + response.Content
+}
+
+func (t *responseTracker) hasNew(query *arkv1alpha1.Query) bool {
+	for _, response := range query.Status.Responses {
+		if response.Content != "" && !t.printed[responseKey(response)] {
+			return true
+		}
+	}
+	return false
+}
+
+// takeNew returns the responses with content that have not been seen yet and marks them as seen
+func (t *responseTracker) takeNew(query *arkv1alpha1.Query) []arkv1alpha1.Response {
+	var responses []arkv1alpha1.Response
+	for _, response := range query.Status.Responses {
+		key := responseKey(response)
+		if response.Content == "" || t.printed[key] {
+			continue
+		}
+		t.printed[key] = true
+		responses = append(responses, response)
+	}
+	return responses
+}
+
+func (t *responseTracker) printNew(query *arkv1alpha1.Query) {
+	for _, response := range t.takeNew(query) {
+		target := fmt.Sprintf("%s/%s", response.Target.Type, response.Target.Name)
+		if len(query.Status.Responses) > 1 {
+			fmt.Fprintf(os.Stderr, "%s %s\n", time.Now().Format("15:04:05.000"), colorize("Response "+target, "36"))
+		}
+		fmt.Println(response.Content)
+	}
+}
+
 func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *OutputOptions) error {
 	spinner := NewSpinner()
 	defer spinner.Stop()
 
 	watcher := NewQueryWatcher(id.Config, id.Name, id.Namespace, id.Config.Logger)
+	// Watched queries may already be running, so show the events recorded so far
+	watcher.ReplayEvents = opts.Watch
 	resultChan, err := watcher.Watch(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to start watching query: %v", err)
@@ -77,6 +136,7 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 
 	spinner.Start()
 	var queryCompletionResult *QueryResult
+	responses := newResponseTracker()
 
 	for {
 		select {
@@ -84,7 +144,7 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 			if !ok {
 				// Channel closed - grace period expired, we can exit now
 				if queryCompletionResult != nil {
-					return handleQueryCompletion(queryCompletionResult, id, opts)
+					return handleQueryCompletion(queryCompletionResult, id, opts, responses)
 				}
 				return fmt.Errorf("result channel closed unexpectedly")
 			}
@@ -92,7 +152,7 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 			handleSpinnerCommands(spinner, result.SpinnerCommand)
 
 			if result.Error != nil {
-				return handleResultError(&result, id)
+				return handleResultError(&result, id, opts)
 			}
 
 			if result.IsEvent {
@@ -100,6 +160,13 @@ func waitForQueryCompletion(ctx context.Context, id *ResourceIdentifier, opts *O
 				continue
 			}
 
+			// Render partial responses, e.g. from team members, before the query finishes
+			if opts.Watch && opts.OutputMode == OutputText && result.Query != nil && !result.Done && responses.hasNew(result.Query) {
+				spinner.Stop()
+				responses.printNew(result.Query)
+				spinner.Start()
+			}
+
 			isQueryCompleted := result.Query != nil && result.Done
 			if isQueryCompleted && queryCompletionResult == nil {
 				// Store the completion result but continue processing events
@@ -156,6 +223,18 @@ func printQueryResults(query *arkv1alpha1.Query, outputMode string) {
 		return
 	}
 
+	// Other structured formats render the completed query, including token usage and status
+	if format, err := ParseOutputFormat(outputMode); err == nil && format.Name != OutputText {
+		queryObject, err := toMap(query)
+		if err == nil {
+			err = format.PrintObject(os.Stdout, ResourceQuery, queryObject)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "failed to render query: %v\n", err)
+		}
+		return
+	}
+
 	// Text output
 	if len(query.Status.Responses) == 0 {
 		fmt.Println("No responses received")
@@ -45,6 +45,10 @@ func (cf *CommandFactory) CreateTargetCommand(targetType ResourceType, use, shor
 	}
 
 	f.addTo(cmd)
+	f.registerParameterCompletion(cmd, cf.config, targetType)
+	if targetType == ResourceAgent || targetType == ResourceTeam {
+		cmd.Flags().BoolVar(&f.attach, "attach", false, "Stream tool calls and responses as they happen and keep the query afterwards")
+	}
 	return cmd
 }
 
@@ -115,10 +119,13 @@ func (cf *CommandFactory) createTargetCommand(targetType ResourceType, targetNam
 		Timeout:    f.timeout,
 		Parameters: f.parameters,
 		SessionId:  f.sessionId,
+		Attach:     f.attach,
+		DryRun:     f.dryRun,
 		ExecutionContext: ExecutionContext{
 			Config:     cf.config,
 			Namespace:  ns,
 			JSONOutput: f.outputMode == "json",
+			Output:     f.outputMode,
 			Silent:     f.quiet,
 			Verbose:    f.verbose,
 		},
@@ -132,7 +139,7 @@ func (cf *CommandFactory) handleTargetCommand(targetType ResourceType, f *flags,
 
 	if len(args) == 0 {
 		ns := getNamespaceOrDefault(f.namespace, cf.config.Namespace)
-		return runListResourcesCommand(cf.config, targetType, ns, f.outputMode == "json")
+		return runListResourcesCommand(cf.config, targetType, ns, f.format)
 	}
 
 	targetName := args[0]
@@ -19,10 +19,22 @@ type ExecutionContext struct {
 	Config     *Config
 	Namespace  string
 	JSONOutput bool
+	Output     string // -o value, overrides JSONOutput when set
 	Silent     bool
 	Verbose    bool
 }
 
+// outputMode returns the output format for query results
+func (c *ExecutionContext) outputMode() string {
+	if c.Output != "" {
+		return c.Output
+	}
+	if c.JSONOutput {
+		return OutputJSON
+	}
+	return OutputText
+}
+
 func (c *ExecutionContext) getLogger() *zap.Logger {
 	return getLogger(c.Config, c.Verbose, c.Silent, c.JSONOutput)
 }
@@ -34,6 +46,8 @@ type TargetCommand struct {
 	Timeout    time.Duration
 	Parameters []string
 	SessionId  string
+	Attach     bool
+	DryRun     bool
 	ExecutionContext
 }
 
@@ -49,34 +63,68 @@ func (c *TargetCommand) Run() error {
 		return fmt.Errorf("failed to parse parameters: %v", err)
 	}
 
-	targets := []arkv1alpha1.QueryTarget{{Type: c.TargetType, Name: c.TargetName}}
+	targets := []arkv1alpha1.QueryTarget{newQueryTarget(c.TargetType, c.TargetName)}
 	query, err := createQuery(c.Input, targets, c.Namespace, params, c.SessionId)
 	if err != nil {
 		return fmt.Errorf("failed to create query: %v", err)
 	}
 
+	if c.DryRun {
+		return dryRunQuery(c.Config, query, c.outputMode(), c.Silent)
+	}
+
 	if err := submitQuery(c.Config, query); err != nil {
 		return fmt.Errorf("failed to create query: %v", err)
 	}
 
 	ctx := setupQueryContext(c.Timeout, logger)
 
+	if c.Attach && !c.Silent {
+		fmt.Fprintf(os.Stderr, "query '%s' created, re-attach with: fark query %s --watch -n %s\n", query.Name, query.Name, c.Namespace)
+	}
+
 	id := &ResourceIdentifier{
 		Config:    c.Config,
 		Type:      ResourceQuery,
 		Name:      query.Name,
 		Namespace: c.Namespace,
 	}
-	var outputMode string
-	if c.JSONOutput {
-		outputMode = "json"
-	} else {
-		outputMode = "text"
+	outputOpts := &OutputOptions{
+		OutputMode: c.outputMode(),
+		Verbose:    c.Verbose,
+		Quiet:      c.Silent,
+		Watch:      c.Attach,
+	}
+	return waitForQueryCompletion(ctx, id, outputOpts)
+}
+
+// WatchCommand attaches to an existing query and streams its progress
+type WatchCommand struct {
+	QueryName string
+	Timeout   time.Duration
+	ExecutionContext
+}
+
+func (c *WatchCommand) Run() error {
+	logger := c.getLogger()
+
+	if _, err := getExistingQuery(c.Config, c.QueryName, c.Namespace); err != nil {
+		return fmt.Errorf("failed to fetch existing query '%s': %v", c.QueryName, err)
+	}
+
+	ctx := setupQueryContext(c.Timeout, logger)
+
+	id := &ResourceIdentifier{
+		Config:    c.Config,
+		Type:      ResourceQuery,
+		Name:      c.QueryName,
+		Namespace: c.Namespace,
 	}
 	outputOpts := &OutputOptions{
-		OutputMode: outputMode,
+		OutputMode: c.outputMode(),
 		Verbose:    c.Verbose,
 		Quiet:      c.Silent,
+		Watch:      true,
 	}
 	return waitForQueryCompletion(ctx, id, outputOpts)
 }
@@ -88,6 +136,7 @@ type TriggerCommand struct {
 	Timeout       time.Duration
 	Parameters    []string
 	SessionId     string
+	DryRun        bool
 	ExecutionContext
 }
 
@@ -128,6 +177,10 @@ func (c *TriggerCommand) Run() error {
 		return fmt.Errorf("failed to create triggered query: %v", err)
 	}
 
+	if c.DryRun {
+		return dryRunQuery(c.Config, newQuery, c.outputMode(), c.Silent)
+	}
+
 	if err := submitQuery(c.Config, newQuery); err != nil {
 		return fmt.Errorf("failed to create triggered query: %v", err)
 	}
@@ -142,14 +195,8 @@ func (c *TriggerCommand) Run() error {
 		Name:      newQuery.Name,
 		Namespace: c.Namespace,
 	}
-	var outputMode string
-	if c.JSONOutput {
-		outputMode = "json"
-	} else {
-		outputMode = "text"
-	}
 	outputOpts := &OutputOptions{
-		OutputMode: outputMode,
+		OutputMode: c.outputMode(),
 		Verbose:    c.Verbose,
 		Quiet:      c.Silent,
 	}
@@ -209,7 +256,7 @@ type ResourceIdentifier struct {
 }
 
 // Get retrieves a resource by name
-func (r *ResourceIdentifier) Get(jsonOutput bool) error {
+func (r *ResourceIdentifier) Get(format OutputFormat) error {
 	gvr := GetGVR(r.Type)
 	ctx := context.Background()
 	resource, err := r.Config.DynamicClient.Resource(gvr).Namespace(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
@@ -217,11 +264,11 @@ func (r *ResourceIdentifier) Get(jsonOutput bool) error {
 		return fmt.Errorf("failed to get %s '%s': %v", r.Type, r.Name, err)
 	}
 
-	if jsonOutput {
-		return printResourceJSON(resource)
+	// Without an explicit format, show the resource without technical metadata
+	if format.Name == OutputText {
+		return printResourceYAML(resource)
 	}
-
-	return printResourceYAML(resource)
+	return format.PrintObject(os.Stdout, r.Type, resource.Object)
 }
 
 // Delete deletes a resource
@@ -401,3 +448,51 @@ func (r *ResourceIdentifier) updateAgentFromFlags(spec *AgentSpec) error {
 	fmt.Fprintf(os.Stderr, "agent '%s' updated successfully\n", r.Name)
 	return nil
 }
+
+// RollbackAgent restores the agent spec recorded in the given AgentRevision
+func (r *ResourceIdentifier) RollbackAgent(toRevision int64) error {
+	ctx := context.Background()
+	revisionName := fmt.Sprintf("%s-%d", r.Name, toRevision)
+
+	revisionResource, err := r.Config.DynamicClient.Resource(GetGVR(ResourceAgentRevision)).Namespace(r.Namespace).Get(ctx, revisionName, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get revision %d of agent '%s': %v", toRevision, r.Name, err)
+	}
+
+	var revision arkv1alpha1.AgentRevision
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(revisionResource.Object, &revision); err != nil {
+		return fmt.Errorf("failed to parse agent revision: %v", err)
+	}
+	if revision.Spec.AgentName != r.Name {
+		return fmt.Errorf("revision '%s' belongs to agent '%s'", revisionName, revision.Spec.AgentName)
+	}
+
+	gvr := GetGVR(ResourceAgent)
+	resource, err := r.Config.DynamicClient.Resource(gvr).Namespace(r.Namespace).Get(ctx, r.Name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get agent '%s': %v", r.Name, err)
+	}
+	if owner := metav1.GetControllerOf(&revision); owner == nil || owner.UID != resource.GetUID() {
+		return fmt.Errorf("revision '%s' is not owned by agent '%s'", revisionName, r.Name)
+	}
+
+	// The revision history limit is not recorded in revisions and is kept as it is
+	template := revision.Spec.Template
+	if limit, found, _ := unstructured.NestedInt64(resource.Object, "spec", "revisionHistoryLimit"); found {
+		revisionHistoryLimit := int32(limit)
+		template.RevisionHistoryLimit = &revisionHistoryLimit
+	}
+	spec, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&template)
+	if err != nil {
+		return fmt.Errorf("failed to convert agent spec to unstructured: %v", err)
+	}
+
+	resource.Object["spec"] = spec
+	_, err = r.Config.DynamicClient.Resource(gvr).Namespace(r.Namespace).Update(ctx, resource, metav1.UpdateOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to update agent: %v", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "agent '%s' rolled back to revision %d\n", r.Name, toRevision)
+	return nil
+}
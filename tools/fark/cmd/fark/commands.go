@@ -11,24 +11,68 @@ import (
 )
 
 func createServerCommand(config *Config) *cobra.Command {
+	var authMode string
+	var authAudiences []string
+	var runAsCaller bool
+	var grpcPort string
+
 	serverCmd := &cobra.Command{
 		Use:   "server",
 		Short: "Start the HTTP server",
 		Long: `Start the Ark HTTP server to accept REST API requests for query submission and streaming.
 
-Provides endpoints for submitting queries to agents and teams in the Kubernetes cluster.`,
+Provides endpoints for submitting queries to agents and teams in the Kubernetes cluster.
+
+With --auth impersonate or --auth access-review, callers must send a bearer token that the
+Kubernetes API server accepts, such as a service account or OIDC token. With impersonate, every
+cluster request is made as the caller. With access-review, the server checks that the caller may
+perform each request and records them on the queries it creates. With --run-as-caller, the
+queries the server creates also run as the caller, so the caller's RBAC applies to the agents,
+tools and memory the query uses. Queries that set a serviceAccount are then refused.
+
+With --grpc-port, the server also serves the gRPC API defined in api/ark/v1/ark.proto on that
+port. It submits queries, streams their progress and lists resources like the REST endpoints,
+with the same authentication: gRPC callers send their bearer token in the authorization metadata.
+
+WebSocket streams opened from a browser are refused unless their origin is allowed with
+--allowed-origin.`,
 		Example: `  ark server
-  ark server --port 9090`,
-		Run: func(cmd *cobra.Command, args []string) {
+  ark server --port 9090
+  ark server --auth impersonate
+  ark server --grpc-port 50051
+  ark server --allowed-origin https://ui.example.com`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			mode, err := parseAuthMode(authMode)
+			if err != nil {
+				return err
+			}
+			authenticator, err := NewAuthenticator(config, mode, authAudiences, runAsCaller)
+			if err != nil {
+				return err
+			}
+
+			if grpcPort != "" {
+				go func() {
+					log.Printf("Starting gRPC server on port %s", grpcPort)
+					log.Fatal(serveGRPC(config, authenticator, grpcPort))
+				}()
+			}
+
 			setupRoutes(config)
-			log.Printf("Starting server on port %s", config.Port)
-			log.Fatal(http.ListenAndServe(":"+config.Port, nil))
+			log.Printf("Starting server on port %s with auth mode %s", config.Port, mode)
+			log.Fatal(http.ListenAndServe(":"+config.Port, authenticator.Middleware(http.DefaultServeMux)))
+			return nil
 		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
 
 	serverCmd.Flags().StringVarP(&config.Port, "port", "p", config.Port, "Server port")
+	serverCmd.Flags().StringVar(&authMode, "auth", string(AuthModeNone), "Caller authentication: none, impersonate or access-review")
+	serverCmd.Flags().StringSliceVar(&authAudiences, "auth-audience", nil, "Audiences accepted in bearer tokens (defaults to the API server audience)")
+	serverCmd.Flags().BoolVar(&runAsCaller, "run-as-caller", false, "Run the queries of authenticated callers as the caller instead of a service account")
+	serverCmd.Flags().StringVar(&grpcPort, "grpc-port", "", "Port of the gRPC API, disabled when empty")
+	serverCmd.Flags().StringSliceVar(&config.AllowedOrigins, "allowed-origin", nil, "Browser origins allowed to open WebSocket streams, such as https://ui.example.com")
 
 	return serverCmd
 }
@@ -44,12 +88,16 @@ func createQueryCommand(config *Config) *cobra.Command {
 When triggering a query:
 - Query text can be provided directly as arguments after the query name, or loaded from a file using --file.
 - Results are streamed in real-time and automatically cleaned up after completion.
-- Use -p key=value to override template parameters.`,
+- Use -p key=value to override template parameters.
+
+Use --watch to attach to an existing query instead of triggering it. Past and new events,
+tool calls and responses are shown as they happen, and the query is kept after it finishes.`,
 		Example: `  fark query
   fark query my-query
   fark query my-query "New input text"
   fark query my-query -f input.txt -n my-namespace
-  fark query my-query -p name=John -p condition=sunny`,
+  fark query my-query -p name=John -p condition=sunny
+  fark query my-query --watch`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if err := f.validate(); err != nil {
 				return err
@@ -57,11 +105,31 @@ When triggering a query:
 
 			if len(args) == 0 {
 				ns := getNamespaceOrDefault(f.namespace, config.Namespace)
-				return runListResourcesCommand(config, ResourceQuery, ns, f.outputMode == "json")
+				return runListResourcesCommand(config, ResourceQuery, ns, f.format)
 			}
 
 			queryName := args[0]
 			ns := getNamespaceOrDefault(f.namespace, config.Namespace)
+
+			if f.watch {
+				if len(args) > 1 {
+					return fmt.Errorf("cannot use --watch with query input")
+				}
+				opts := WatchCommand{
+					QueryName: queryName,
+					Timeout:   f.timeout,
+					ExecutionContext: ExecutionContext{
+						Config:     config,
+						Namespace:  ns,
+						JSONOutput: f.outputMode == "json",
+						Output:     f.outputMode,
+						Silent:     f.quiet,
+						Verbose:    f.verbose,
+					},
+				}
+				return handleQueryError(cmd, opts.Run())
+			}
+
 			resolver := &InputResolver{
 				Input:     f.input,
 				InputFile: f.inputFile,
@@ -80,10 +148,12 @@ When triggering a query:
 				Timeout:       f.timeout,
 				Parameters:    f.parameters,
 				SessionId:     f.sessionId,
+				DryRun:        f.dryRun,
 				ExecutionContext: ExecutionContext{
 					Config:     config,
 					Namespace:  ns,
 					JSONOutput: f.outputMode == "json",
+					Output:     f.outputMode,
 					Silent:     f.quiet,
 					Verbose:    f.verbose,
 				},
@@ -101,6 +171,8 @@ When triggering a query:
 	}
 
 	f.addTo(queryCmd)
+	f.registerParameterCompletion(queryCmd, config, ResourceQuery)
+	queryCmd.Flags().BoolVarP(&f.watch, "watch", "w", false, "Attach to an existing query and stream its progress without triggering it")
 	return queryCmd
 }
 
@@ -126,34 +198,66 @@ func setupRoutes(config *Config) {
 	http.HandleFunc("/model/", handleQueryResourceWithPath(config, ResourceModel))
 	http.HandleFunc("/tool/", handleQueryResourceWithPath(config, ResourceTool))
 	http.HandleFunc("/query/", handleTriggerQueryByName(config))
+	http.HandleFunc("POST /template/{name}", handleRunTemplate(config))
+
+	// Streaming endpoint for existing queries (SSE or WebSocket)
+	http.HandleFunc("GET /query/{name}/stream", handleQueryStream(config))
+
+	// OpenAPI document of the endpoints above
+	http.HandleFunc("GET "+openAPIPath, handleOpenAPI())
 }
 
 func createGetCommand(config *Config) *cobra.Command {
 	var namespace string
 	var jsonOutput bool
+	var output string
+	var responses bool
+	var responsesOpts QueryResponsesOptions
 
 	cmd := &cobra.Command{
 		Use:   "get <resource> [name]",
 		Short: "Get resource(s)",
 		Long: `Get detailed information about a specific resource, or list all resources of a type.
 
-Supported resources: agent, team, model, tool, query`,
+Supported resources: agent, team, model, tool, query
+
+With --responses, a query is shown as its responses with their full content. Responses the
+controller stored as artifacts to keep the query status small are read back from their artifact.
+--offset and --limit page through the responses of queries with many targets.`,
 		Example: `  fark get agent                    # List all agents
   fark get agent weather-agent      # Get specific agent
   fark get team weather-team -n production
-  fark get tool get-forecast --json`,
+  fark get tool get-forecast -o json
+  fark get query -o jsonpath='{.items[*].metadata.name}'
+  fark get query fanout-query --responses --offset 20 --limit 10
+  fark get agent -o custom-columns=NAME:.metadata.name,PROMPT:.spec.prompt`,
 		Args: cobra.RangeArgs(1, 2),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			resourceType := args[0]
 			ns := getNamespaceOrDefault(namespace, config.Namespace)
 
+			if jsonOutput {
+				output = OutputJSON
+			}
+			format, err := ParseOutputFormat(output)
+			if err != nil {
+				return err
+			}
+
+			if responses && (len(args) != 2 || resourceType != "query") {
+				return fmt.Errorf("--responses requires a query name")
+			}
+			if responsesOpts.Offset < 0 || responsesOpts.Limit < 0 {
+				return fmt.Errorf("--offset and --limit must not be negative")
+			}
+
 			if len(args) == 1 {
 				// List resources
 				resourceTypeEnum := getResourceTypeFromString(resourceType)
 				if resourceTypeEnum == "" {
 					return fmt.Errorf("unsupported resource type: %s", resourceType)
 				}
-				return runListResourcesCommand(config, resourceTypeEnum, ns, jsonOutput)
+				return runListResourcesCommand(config, resourceTypeEnum, ns, format)
 			} else {
 				// Get specific resource
 				resourceName := args[1]
@@ -163,7 +267,10 @@ Supported resources: agent, team, model, tool, query`,
 					Name:      resourceName,
 					Namespace: ns,
 				}
-				return id.Get(jsonOutput)
+				if responses {
+					return id.GetResponses(format, responsesOpts)
+				}
+				return id.Get(format)
 			}
 		},
 		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
@@ -181,7 +288,12 @@ Supported resources: agent, team, model, tool, query`,
 	}
 
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVarP(&output, "output", "o", OutputText, "Output format: "+outputFormatsHelp)
 	cmd.Flags().BoolVarP(&jsonOutput, "json", "j", false, "Output results in JSON format only")
+	_ = cmd.Flags().MarkDeprecated("json", "use -o json instead")
+	cmd.Flags().BoolVar(&responses, "responses", false, "Show the full responses of a query, including the ones stored as artifacts")
+	cmd.Flags().IntVar(&responsesOpts.Offset, "offset", 0, "Skip this many responses (with --responses)")
+	cmd.Flags().IntVar(&responsesOpts.Limit, "limit", 0, "Show at most this many responses, 0 shows all (with --responses)")
 	return cmd
 }
 
@@ -322,7 +434,11 @@ func createDeleteCommand(config *Config) *cobra.Command {
 		Short: "Delete a resource",
 		Long: `Delete a resource by name.
 
-Supported resources: agent, team, model, tool, query`,
+Supported resources: agent, team, model, tool, query
+
+With --responses, a query is shown as its responses with their full content. Responses the
+controller stored as artifacts to keep the query status small are read back from their artifact.
+--offset and --limit page through the responses of queries with many targets.`,
 		Example: `  fark delete agent my-agent
   fark delete team support-team -n production
   fark delete query old-query`,
@@ -362,3 +478,58 @@ Supported resources: agent, team, model, tool, query`,
 	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
 	return cmd
 }
+
+func createRollbackCommand(config *Config) *cobra.Command {
+	var namespace string
+	var toRevision int64
+
+	cmd := &cobra.Command{
+		Use:   "rollback <resource> <name>",
+		Short: "Roll back a resource to a previous revision",
+		Long: `Roll back a resource to the spec recorded in a previous revision.
+
+Rolling back records a new revision with the restored spec.
+
+Supported resources: agent`,
+		Example: `  fark rollback agent my-agent --to-revision 2
+  fark rollback agent weather-agent --to-revision 1 -n production`,
+		Args: cobra.RangeArgs(0, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return cmd.Help()
+			}
+			if len(args) == 1 {
+				return fmt.Errorf("resource name is required")
+			}
+			if args[0] != "agent" {
+				return fmt.Errorf("rollback is not supported for %s", args[0])
+			}
+			if toRevision < 1 {
+				return fmt.Errorf("--to-revision is required")
+			}
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			id := &ResourceIdentifier{
+				Config:    config,
+				Type:      ResourceAgent,
+				Name:      args[1],
+				Namespace: ns,
+			}
+			return id.RollbackAgent(toRevision)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) == 0 {
+				return []string{"agent"}, cobra.ShellCompDirectiveNoFileComp
+			}
+			if len(args) == 1 {
+				return getResourceCompletions(config, string(ResourceAgent), namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().Int64Var(&toRevision, "to-revision", 0, "Revision number to roll back to")
+	return cmd
+}
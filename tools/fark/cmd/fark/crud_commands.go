@@ -9,7 +9,7 @@ import (
 )
 
 // runGetResourceCommand gets a specific resource by name
-func runGetResourceCommand(config *Config, resourceType, resourceName, namespace string, jsonOutput bool) error {
+func runGetResourceCommand(config *Config, resourceType, resourceName, namespace string, format OutputFormat) error {
 	id := &ResourceIdentifier{
 		Config:    config,
 		Type:      getResourceTypeFromString(resourceType),
@@ -17,7 +17,7 @@ func runGetResourceCommand(config *Config, resourceType, resourceName, namespace
 		Namespace: namespace,
 	}
 
-	return id.Get(jsonOutput)
+	return id.Get(format)
 }
 
 // runDeleteResourceCommand deletes a resource
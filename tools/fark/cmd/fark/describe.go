@@ -0,0 +1,444 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const memoryRequestTimeout = 5 * time.Second
+
+func createDescribeCommand(config *Config) *cobra.Command {
+	var namespace string
+	var jsonOutput bool
+	var memoryMessages int
+
+	cmd := &cobra.Command{
+		Use:   "describe query <name>",
+		Short: "Show diagnostics for a query",
+		Long: `Show the status, events, evaluations, token usage, per-target durations and recent
+memory messages of a query in one view.
+
+Memory messages are read from the memory service address recorded on the Memory resource,
+so they are only shown when that address is reachable from where fark runs.`,
+		Example: `  fark describe query weather-query
+  fark describe query weather-query -n production --json
+  fark describe query weather-query --memory-messages 20`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] != "query" {
+				return fmt.Errorf("unsupported resource type: %s, only query can be described", args[0])
+			}
+
+			ns := getNamespaceOrDefault(namespace, config.Namespace)
+			diagnostics, err := collectQueryDiagnostics(context.Background(), config, args[1], ns, memoryMessages)
+			if err != nil {
+				return err
+			}
+
+			if jsonOutput {
+				jsonData, err := json.MarshalIndent(diagnostics, "", "  ")
+				if err != nil {
+					return fmt.Errorf("failed to marshal diagnostics: %v", err)
+				}
+				fmt.Println(string(jsonData))
+				return nil
+			}
+			printQueryDiagnostics(diagnostics)
+			return nil
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return []string{"query"}, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return getResourceCompletions(config, string(ResourceQuery), namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().BoolVar(&jsonOutput, "json", false, "Output diagnostics as JSON")
+	cmd.Flags().IntVar(&memoryMessages, "memory-messages", 10, "Number of recent memory messages to show, 0 to skip memory")
+	return cmd
+}
+
+// QueryDiagnostics combines everything known about a query
+type QueryDiagnostics struct {
+	Name        string                    `json:"name"`
+	Namespace   string                    `json:"namespace"`
+	Phase       string                    `json:"phase"`
+	Created     time.Time                 `json:"created"`
+	Duration    string                    `json:"duration,omitempty"`
+	SessionId   string                    `json:"sessionId"`
+	TraceID     string                    `json:"traceID,omitempty"`
+	TraceURL    string                    `json:"traceURL,omitempty"`
+	Input       string                    `json:"input"`
+	Targets     []arkv1alpha1.QueryTarget `json:"targets"`
+	Conditions  []metav1.Condition        `json:"conditions,omitempty"`
+	TokenUsage  arkv1alpha1.TokenUsage    `json:"tokenUsage"`
+	Timings     []TargetTiming            `json:"timings,omitempty"`
+	Responses   []arkv1alpha1.Response    `json:"responses,omitempty"`
+	Evaluations []EvaluationSummary       `json:"evaluations,omitempty"`
+	Events      []EventSummary            `json:"events,omitempty"`
+	Memory      *MemorySummary            `json:"memory,omitempty"`
+}
+
+// TargetTiming is the execution time of an agent or team, taken from its completion event
+type TargetTiming struct {
+	Type     string `json:"type"`
+	Name     string `json:"name"`
+	Duration string `json:"duration"`
+	Error    string `json:"error,omitempty"`
+}
+
+// EvaluationSummary is an evaluation that references the query
+type EvaluationSummary struct {
+	Name      string `json:"name"`
+	Evaluator string `json:"evaluator"`
+	Phase     string `json:"phase"`
+	Score     string `json:"score,omitempty"`
+	Passed    bool   `json:"passed"`
+	Message   string `json:"message,omitempty"`
+}
+
+// EventSummary is a Kubernetes event recorded for the query
+type EventSummary struct {
+	Time    time.Time `json:"time"`
+	Type    string    `json:"type"`
+	Reason  string    `json:"reason"`
+	Message string    `json:"message"`
+}
+
+// MemorySummary holds the most recent messages of the query session
+type MemorySummary struct {
+	Name     string          `json:"name"`
+	Address  string          `json:"address,omitempty"`
+	Messages []MemoryMessage `json:"messages,omitempty"`
+	Error    string          `json:"error,omitempty"`
+}
+
+// MemoryMessage is a message stored in memory
+type MemoryMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// timingReasons maps completion event reasons to the kind of target they time
+var timingReasons = map[string]string{
+	"AgentExecutionComplete": "agent",
+	"AgentExecutionError":    "agent",
+	"TeamExecutionComplete":  "team",
+	"TeamExecutionError":     "team",
+}
+
+func collectQueryDiagnostics(ctx context.Context, config *Config, name, namespace string, memoryMessages int) (*QueryDiagnostics, error) {
+	query, err := getExistingQuery(config, name, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get query '%s': %v", name, err)
+	}
+
+	diagnostics := &QueryDiagnostics{
+		Name:       query.Name,
+		Namespace:  query.Namespace,
+		Phase:      query.Status.Phase,
+		Created:    query.CreationTimestamp.Time,
+		SessionId:  query.Spec.SessionId,
+		TraceID:    query.Status.TraceID,
+		TraceURL:   query.Status.TraceURL,
+		Input:      describeInput(query),
+		Targets:    query.Spec.Targets,
+		Conditions: query.Status.Conditions,
+		TokenUsage: query.Status.TokenUsage,
+		Responses:  query.Status.Responses,
+	}
+	if diagnostics.SessionId == "" {
+		diagnostics.SessionId = string(query.UID)
+	}
+	if query.Status.Duration != nil {
+		diagnostics.Duration = query.Status.Duration.Duration.String()
+	}
+
+	events, err := listQueryEvents(ctx, config, name, namespace)
+	if err != nil {
+		config.Logger.Warn("Failed to list events", zap.Error(err))
+	} else {
+		diagnostics.Events, diagnostics.Timings = summarizeEvents(events.Items)
+	}
+
+	diagnostics.Evaluations, err = listQueryEvaluations(ctx, config, name, namespace)
+	if err != nil {
+		config.Logger.Warn("Failed to list evaluations", zap.Error(err))
+	}
+
+	if memoryMessages > 0 {
+		diagnostics.Memory = getMemorySummary(ctx, config, query, diagnostics.SessionId, memoryMessages)
+	}
+	return diagnostics, nil
+}
+
+func describeInput(query *arkv1alpha1.Query) string {
+	if source := query.Spec.InputFrom; source != nil {
+		switch {
+		case source.ConfigMapKeyRef != nil:
+			return fmt.Sprintf("(from configmap %s, key %s)", source.ConfigMapKeyRef.Name, source.ConfigMapKeyRef.Key)
+		case source.SecretKeyRef != nil:
+			return fmt.Sprintf("(from secret %s, key %s)", source.SecretKeyRef.Name, source.SecretKeyRef.Key)
+		default:
+			return fmt.Sprintf("(from %s)", source.URL)
+		}
+	}
+	if input, err := query.Spec.GetInputString(); err == nil {
+		return input
+	}
+	return string(query.Spec.Input.Raw)
+}
+
+func summarizeEvents(items []unstructured.Unstructured) ([]EventSummary, []TargetTiming) {
+	var events []EventSummary
+	var timings []TargetTiming
+	for _, item := range items {
+		eventType, _, _ := unstructured.NestedString(item.Object, "type")
+		reason, _, _ := unstructured.NestedString(item.Object, "reason")
+		message, _, _ := unstructured.NestedString(item.Object, "message")
+		events = append(events, EventSummary{
+			Time:    item.GetCreationTimestamp().Time,
+			Type:    eventType,
+			Reason:  reason,
+			Message: message,
+		})
+
+		targetType, ok := timingReasons[reason]
+		if !ok {
+			continue
+		}
+		var data struct {
+			Name     string `json:"name"`
+			Duration string `json:"duration"`
+			Error    string `json:"error"`
+		}
+		if err := json.Unmarshal([]byte(message), &data); err != nil {
+			continue
+		}
+		// Event names are namespace/name
+		_, targetName, found := strings.Cut(data.Name, "/")
+		if !found {
+			targetName = data.Name
+		}
+		timings = append(timings, TargetTiming{
+			Type:     targetType,
+			Name:     targetName,
+			Duration: data.Duration,
+			Error:    data.Error,
+		})
+	}
+	return events, timings
+}
+
+func listQueryEvaluations(ctx context.Context, config *Config, queryName, namespace string) ([]EvaluationSummary, error) {
+	list, err := config.DynamicClient.Resource(GetGVR(ResourceEvaluation)).Namespace(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	var evaluations []EvaluationSummary
+	for _, item := range list.Items {
+		ref, _, _ := unstructured.NestedString(item.Object, "spec", "config", "queryRef", "name")
+		if ref != queryName {
+			continue
+		}
+		summary := EvaluationSummary{Name: item.GetName()}
+		summary.Evaluator, _, _ = unstructured.NestedString(item.Object, "spec", "evaluator", "name")
+		summary.Phase, _, _ = unstructured.NestedString(item.Object, "status", "phase")
+		summary.Score, _, _ = unstructured.NestedString(item.Object, "status", "score")
+		summary.Passed, _, _ = unstructured.NestedBool(item.Object, "status", "passed")
+		summary.Message, _, _ = unstructured.NestedString(item.Object, "status", "message")
+		evaluations = append(evaluations, summary)
+	}
+	return evaluations, nil
+}
+
+// getMemorySummary reads the latest session messages from the memory service. Failures are
+// reported in the summary because memory is often not reachable from outside the cluster.
+func getMemorySummary(ctx context.Context, config *Config, query *arkv1alpha1.Query, sessionId string, limit int) *MemorySummary {
+	memoryName, memoryNamespace := "default", query.Namespace
+	if query.Spec.Memory != nil {
+		memoryName = query.Spec.Memory.Name
+		if query.Spec.Memory.Namespace != "" {
+			memoryNamespace = query.Spec.Memory.Namespace
+		}
+	}
+	summary := &MemorySummary{Name: memoryName}
+
+	memory, err := config.DynamicClient.Resource(GetGVR(ResourceMemory)).Namespace(memoryNamespace).Get(ctx, memoryName, metav1.GetOptions{})
+	if err != nil {
+		summary.Error = fmt.Sprintf("failed to get memory: %v", err)
+		return summary
+	}
+	summary.Address, _, _ = unstructured.NestedString(memory.Object, "status", "lastResolvedAddress")
+	if summary.Address == "" {
+		summary.Error = "memory address has not been resolved"
+		return summary
+	}
+
+	messages, err := fetchMemoryMessages(ctx, summary.Address, sessionId)
+	if err != nil {
+		summary.Error = err.Error()
+		return summary
+	}
+	if len(messages) > limit {
+		messages = messages[len(messages)-limit:]
+	}
+	summary.Messages = messages
+	return summary
+}
+
+func fetchMemoryMessages(ctx context.Context, address, sessionId string) ([]MemoryMessage, error) {
+	ctx, cancel := context.WithTimeout(ctx, memoryRequestTimeout)
+	defer cancel()
+
+	requestURL := fmt.Sprintf("%s/messages?session_id=%s", strings.TrimSuffix(address, "/"), url.QueryEscape(sessionId))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create memory request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("memory not reachable: %v", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, fmt.Errorf("memory returned HTTP status %d", resp.StatusCode)
+	}
+
+	var response struct {
+		Messages []struct {
+			Message json.RawMessage `json:"message"`
+		} `json:"messages"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return nil, fmt.Errorf("failed to decode memory response: %v", err)
+	}
+
+	messages := make([]MemoryMessage, 0, len(response.Messages))
+	for _, record := range response.Messages {
+		var message struct {
+			Role    string          `json:"role"`
+			Content json.RawMessage `json:"content"`
+		}
+		if err := json.Unmarshal(record.Message, &message); err != nil {
+			continue
+		}
+		content := string(message.Content)
+		var text string
+		if err := json.Unmarshal(message.Content, &text); err == nil {
+			content = text
+		}
+		messages = append(messages, MemoryMessage{Role: message.Role, Content: content})
+	}
+	return messages, nil
+}
+
+func printQueryDiagnostics(d *QueryDiagnostics) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Name:\t%s\n", d.Name)
+	fmt.Fprintf(w, "Namespace:\t%s\n", d.Namespace)
+	fmt.Fprintf(w, "Phase:\t%s\n", d.Phase)
+	fmt.Fprintf(w, "Created:\t%s\n", d.Created.Format(time.RFC3339))
+	if d.Duration != "" {
+		fmt.Fprintf(w, "Duration:\t%s\n", d.Duration)
+	}
+	fmt.Fprintf(w, "Session:\t%s\n", d.SessionId)
+	if d.TraceURL != "" {
+		fmt.Fprintf(w, "Trace:\t%s\n", d.TraceURL)
+	} else if d.TraceID != "" {
+		fmt.Fprintf(w, "Trace:\t%s\n", d.TraceID)
+	}
+	fmt.Fprintf(w, "Tokens:\t%d prompt, %d completion, %d total\n",
+		d.TokenUsage.PromptTokens, d.TokenUsage.CompletionTokens, d.TokenUsage.TotalTokens)
+	_ = w.Flush()
+
+	fmt.Printf("\nInput:\n  %s\n", d.Input)
+
+	fmt.Println("\nTargets:")
+	for _, target := range d.Targets {
+		fmt.Printf("  %s/%s\n", target.Type, target.Name)
+	}
+
+	if len(d.Timings) > 0 {
+		fmt.Println("\nTimings:")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		for _, timing := range d.Timings {
+			status := "ok"
+			if timing.Error != "" {
+				status = colorize(timing.Error, "31")
+			}
+			fmt.Fprintf(w, "  %s/%s\t%s\t%s\n", timing.Type, timing.Name, timing.Duration, status)
+		}
+		_ = w.Flush()
+	}
+
+	if len(d.Conditions) > 0 {
+		fmt.Println("\nConditions:")
+		for _, condition := range d.Conditions {
+			fmt.Printf("  %s=%s %s: %s\n", condition.Type, condition.Status, condition.Reason, condition.Message)
+		}
+	}
+
+	if len(d.Responses) > 0 {
+		fmt.Println("\nResponses:")
+		for _, response := range d.Responses {
+			fmt.Printf("  %s:\n", colorize(fmt.Sprintf("%s/%s", response.Target.Type, response.Target.Name), "36"))
+			fmt.Printf("    %s\n", strings.ReplaceAll(response.Content, "\n", "\n    "))
+		}
+	}
+
+	if len(d.Evaluations) > 0 {
+		fmt.Println("\nEvaluations:")
+		w = tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "  NAME\tEVALUATOR\tPHASE\tSCORE\tPASSED")
+		for _, evaluation := range d.Evaluations {
+			fmt.Fprintf(w, "  %s\t%s\t%s\t%s\t%t\n", evaluation.Name, evaluation.Evaluator, evaluation.Phase, evaluation.Score, evaluation.Passed)
+		}
+		_ = w.Flush()
+	}
+
+	if len(d.Events) > 0 {
+		fmt.Println("\nEvents:")
+		for _, event := range d.Events {
+			timestamp := colorize(event.Time.Format("15:04:05"), "90")
+			reason := colorize(event.Reason, getEventColorCode(event.Type))
+			fmt.Printf("  %s %s%s\n", timestamp, reason, parseEventDetails(event.Message))
+		}
+	}
+
+	if d.Memory != nil {
+		fmt.Printf("\nMemory (%s):\n", d.Memory.Name)
+		switch {
+		case d.Memory.Error != "":
+			fmt.Printf("  %s\n", colorize(d.Memory.Error, "33"))
+		case len(d.Memory.Messages) == 0:
+			fmt.Println("  No messages")
+		default:
+			for _, message := range d.Memory.Messages {
+				fmt.Printf("  %s: %s\n", colorize(message.Role, "36"), message.Content)
+			}
+		}
+	}
+}
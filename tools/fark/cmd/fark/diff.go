@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// diffContextLines is the number of unchanged lines shown around changed lines of a text diff
+const diffContextLines = 2
+
+func createDiffCommand(config *Config) *cobra.Command {
+	var namespace, file string
+	var fromRevision, toRevision int64
+
+	cmd := &cobra.Command{
+		Use:   "diff <resource> <name>",
+		Short: "Show the semantic diff of a resource",
+		Long: `Show what changes between two versions of a resource, field by field.
+
+The live resource is compared with the resource in a file, or revisions of an agent are compared
+with each other or with the live agent. Multi-line text such as prompts is diffed line by line,
+and lists of named entries such as agent tools or team members are matched by name, so tools
+that were added, removed or changed are listed as such.
+
+Supported resources: model, tool, agent, team, evaluator, querytemplate. Revisions are only
+recorded for agents.`,
+		Example: `  fark diff agent my-agent -f new-agent.yaml
+  fark diff agent my-agent --from-revision 2
+  fark diff agent my-agent --from-revision 2 --to-revision 4
+  fark diff team planners -f bundle.yaml -n production`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kind, resourceType, err := diffResourceKind(args[0])
+			if err != nil {
+				return err
+			}
+			if file == "" && fromRevision == 0 && toRevision == 0 {
+				return fmt.Errorf("-f or --from-revision is required")
+			}
+			if file != "" && toRevision != 0 {
+				return fmt.Errorf("cannot use both -f and --to-revision")
+			}
+			if (fromRevision != 0 || toRevision != 0) && resourceType != ResourceAgent {
+				return fmt.Errorf("revisions are only recorded for agents")
+			}
+
+			opts := DiffCommand{
+				Kind:         kind,
+				Type:         resourceType,
+				Name:         args[1],
+				Namespace:    getNamespaceOrDefault(namespace, config.Namespace),
+				File:         file,
+				FromRevision: fromRevision,
+				ToRevision:   toRevision,
+				Config:       config,
+			}
+			return opts.Run(cmd.Context(), os.Stdout)
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				var kinds []string
+				for _, kind := range bundleKinds {
+					kinds = append(kinds, strings.ToLower(kind.Kind))
+				}
+				return kinds, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				if _, resourceType, err := diffResourceKind(args[0]); err == nil {
+					return getResourceCompletions(config, string(resourceType), namespace), cobra.ShellCompDirectiveNoFileComp
+				}
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	cmd.Flags().StringVarP(&namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
+	cmd.Flags().StringVarP(&file, "file", "f", "", "File with the new version of the resource, a manifest or bundle")
+	cmd.Flags().Int64Var(&fromRevision, "from-revision", 0, "Agent revision to compare from (defaults to the live agent)")
+	cmd.Flags().Int64Var(&toRevision, "to-revision", 0, "Agent revision to compare to (defaults to the live agent)")
+	return cmd
+}
+
+// diffResourceKind returns the kind and resource type of a resource name given on the command line
+func diffResourceKind(resource string) (string, ResourceType, error) {
+	for _, kind := range bundleKinds {
+		if strings.EqualFold(resource, kind.Kind) || resource == string(kind.Type) {
+			return kind.Kind, kind.Type, nil
+		}
+	}
+	return "", "", fmt.Errorf("diff is not supported for %s, supported resources are model, tool, agent, team, evaluator and querytemplate", resource)
+}
+
+// DiffCommand compares two versions of the spec of a resource
+type DiffCommand struct {
+	Kind         string
+	Type         ResourceType
+	Name         string
+	Namespace    string
+	File         string
+	FromRevision int64
+	ToRevision   int64
+	Config       *Config
+}
+
+func (c *DiffCommand) Run(ctx context.Context, w io.Writer) error {
+	from, fromLabel, err := c.version(ctx, c.FromRevision, "")
+	if err != nil {
+		return err
+	}
+	to, toLabel, err := c.version(ctx, c.ToRevision, c.File)
+	if err != nil {
+		return err
+	}
+	if fromLabel == toLabel {
+		return fmt.Errorf("both sides of the diff are the %s", fromLabel)
+	}
+
+	changes := diffValues("spec", from, to)
+	fmt.Fprintf(w, "%s/%s: %s -> %s\n", strings.ToLower(c.Kind), c.Name, fromLabel, toLabel)
+	if len(changes) == 0 {
+		fmt.Fprintln(w, "no differences")
+		return nil
+	}
+	for _, change := range changes {
+		change.print(w)
+	}
+	return nil
+}
+
+// version returns the spec of one side of the diff: a revision, the resource in a file, or else
+// the live resource
+func (c *DiffCommand) version(ctx context.Context, revision int64, file string) (any, string, error) {
+	switch {
+	case revision > 0:
+		spec, err := c.revisionSpec(ctx, revision)
+		return spec, fmt.Sprintf("revision %d", revision), err
+	case file != "":
+		spec, err := c.fileSpec(file)
+		return spec, file, err
+	}
+
+	live, err := c.Config.DynamicClient.Resource(GetGVR(c.Type)).Namespace(c.Namespace).Get(ctx, c.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to get %s '%s': %v", strings.ToLower(c.Kind), c.Name, err)
+	}
+	return live.Object["spec"], "live", nil
+}
+
+func (c *DiffCommand) revisionSpec(ctx context.Context, revision int64) (any, error) {
+	name := fmt.Sprintf("%s-%d", c.Name, revision)
+	resource, err := c.Config.DynamicClient.Resource(GetGVR(ResourceAgentRevision)).Namespace(c.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get revision %d of agent '%s': %v", revision, c.Name, err)
+	}
+	if agentName, _, _ := unstructured.NestedString(resource.Object, "spec", "agentName"); agentName != c.Name {
+		return nil, fmt.Errorf("revision '%s' belongs to agent '%s'", name, agentName)
+	}
+	template, _, _ := unstructured.NestedFieldNoCopy(resource.Object, "spec", "template")
+	return template, nil
+}
+
+// fileSpec returns the spec of the resource in a manifest or bundle file. The resource of the
+// kind and name is used, or the only resource of the kind when none has the name
+func (c *DiffCommand) fileSpec(path string) (any, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %v", path, err)
+	}
+	defer func() { _ = file.Close() }()
+
+	resources, err := readBundle(file)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []*unstructured.Unstructured
+	for _, resource := range resources {
+		if resource.GetKind() != c.Kind {
+			continue
+		}
+		if resource.GetName() == c.Name {
+			return resource.Object["spec"], nil
+		}
+		candidates = append(candidates, resource)
+	}
+	if len(candidates) == 1 {
+		return candidates[0].Object["spec"], nil
+	}
+	return nil, fmt.Errorf("%s has no %s named '%s'", path, c.Kind, c.Name)
+}
+
+// Kinds of changes between two values
+const (
+	diffAdded   = "+"
+	diffRemoved = "-"
+	diffChanged = "~"
+)
+
+// diffChange is a field or named list entry that differs between two versions
+type diffChange struct {
+	Kind string
+	Path string
+	From any
+	To   any
+	// Lines is the line diff of changed multi-line text
+	Lines []string
+}
+
+func (d diffChange) print(w io.Writer) {
+	switch {
+	case d.Lines != nil:
+		fmt.Fprintf(w, "%s %s:\n", d.Kind, d.Path)
+		for _, line := range d.Lines {
+			fmt.Fprintf(w, "    %s\n", line)
+		}
+	case d.Kind == diffAdded:
+		fmt.Fprintf(w, "%s %s: %s\n", d.Kind, d.Path, formatDiffValue(d.To))
+	case d.Kind == diffRemoved:
+		fmt.Fprintf(w, "%s %s: %s\n", d.Kind, d.Path, formatDiffValue(d.From))
+	default:
+		fmt.Fprintf(w, "%s %s: %s -> %s\n", d.Kind, d.Path, formatDiffValue(d.From), formatDiffValue(d.To))
+	}
+}
+
+func formatDiffValue(value any) string {
+	if text, ok := value.(string); ok {
+		return fmt.Sprintf("%q", text)
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+	return string(data)
+}
+
+// diffValues returns the changes from one value to another. Objects are compared field by field,
+// lists of objects with a name are matched by name, and multi-line strings are diffed by line.
+func diffValues(path string, from, to any) []diffChange {
+	if reflect.DeepEqual(from, to) {
+		return nil
+	}
+	if from == nil {
+		return []diffChange{{Kind: diffAdded, Path: path, To: to}}
+	}
+	if to == nil {
+		return []diffChange{{Kind: diffRemoved, Path: path, From: from}}
+	}
+
+	switch fromValue := from.(type) {
+	case map[string]any:
+		if toValue, ok := to.(map[string]any); ok {
+			return diffObjects(path, fromValue, toValue)
+		}
+	case []any:
+		if toValue, ok := to.([]any); ok {
+			if fromNames, ok := entryNames(fromValue); ok {
+				if toNames, ok := entryNames(toValue); ok {
+					return diffNamedEntries(path, fromValue, fromNames, toValue, toNames)
+				}
+			}
+		}
+	case string:
+		if toValue, ok := to.(string); ok && (strings.Contains(fromValue, "\n") || strings.Contains(toValue, "\n")) {
+			return []diffChange{{Kind: diffChanged, Path: path, From: from, To: to, Lines: diffLines(fromValue, toValue)}}
+		}
+	}
+	return []diffChange{{Kind: diffChanged, Path: path, From: from, To: to}}
+}
+
+func diffObjects(path string, from, to map[string]any) []diffChange {
+	keys := make([]string, 0, len(from)+len(to))
+	for key := range from {
+		keys = append(keys, key)
+	}
+	for key := range to {
+		if _, found := from[key]; !found {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+
+	var changes []diffChange
+	for _, key := range keys {
+		changes = append(changes, diffValues(path+"."+key, from[key], to[key])...)
+	}
+	return changes
+}
+
+// entryNames returns the names of the entries of a list when every entry is an object with a
+// unique name
+func entryNames(entries []any) ([]string, bool) {
+	names := make([]string, 0, len(entries))
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		object, ok := entry.(map[string]any)
+		if !ok {
+			return nil, false
+		}
+		name, ok := object["name"].(string)
+		if !ok || name == "" || seen[name] {
+			return nil, false
+		}
+		seen[name] = true
+		names = append(names, name)
+	}
+	return names, true
+}
+
+// diffNamedEntries compares the entries of two lists by name, listing removed entries first, then
+// changed and added entries in the order of the new list
+func diffNamedEntries(path string, from []any, fromNames []string, to []any, toNames []string) []diffChange {
+	toIndex := map[string]int{}
+	for i, name := range toNames {
+		toIndex[name] = i
+	}
+	fromIndex := map[string]int{}
+	var changes []diffChange
+	for i, name := range fromNames {
+		fromIndex[name] = i
+		if _, found := toIndex[name]; !found {
+			changes = append(changes, diffChange{Kind: diffRemoved, Path: fmt.Sprintf("%s[%s]", path, name), From: from[i]})
+		}
+	}
+	for i, name := range toNames {
+		entryPath := fmt.Sprintf("%s[%s]", path, name)
+		j, found := fromIndex[name]
+		if !found {
+			changes = append(changes, diffChange{Kind: diffAdded, Path: entryPath, To: to[i]})
+			continue
+		}
+		changes = append(changes, diffValues(entryPath, from[j], to[i])...)
+	}
+	if len(changes) == 0 {
+		// Same entries in another order, which matters for lists such as sequential team members
+		changes = append(changes, diffChange{Kind: diffChanged, Path: path + " order", From: fromNames, To: toNames})
+	}
+	return changes
+}
+
+// diffLines returns the line diff of two texts, with unchanged lines prefixed by two spaces,
+// removed lines by "- " and added lines by "+ ". Unchanged lines away from changes are elided.
+func diffLines(from, to string) []string {
+	a := strings.Split(from, "\n")
+	b := strings.Split(to, "\n")
+
+	// Longest common subsequence of lines, lcs[i][j] is for a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, "  "+a[i])
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, "- "+a[i])
+			i++
+		default:
+			lines = append(lines, "+ "+b[j])
+			j++
+		}
+	}
+	return elideUnchangedLines(lines)
+}
+
+// elideUnchangedLines keeps unchanged lines within diffContextLines of a change
+func elideUnchangedLines(lines []string) []string {
+	keep := make([]bool, len(lines))
+	for i, line := range lines {
+		if strings.HasPrefix(line, "  ") {
+			continue
+		}
+		for k := max(0, i-diffContextLines); k <= min(len(lines)-1, i+diffContextLines); k++ {
+			keep[k] = true
+		}
+	}
+
+	result := []string{}
+	elided := false
+	for i, line := range lines {
+		if keep[i] {
+			result = append(result, line)
+			elided = false
+		} else if !elided {
+			result = append(result, "  ...")
+			elided = true
+		}
+	}
+	return result
+}
@@ -62,12 +62,16 @@ func (ep *EventProcessor) writeQueryEvent(w http.ResponseWriter, flusher http.Fl
 }
 
 func (ep *EventProcessor) writeKubernetesEvent(w http.ResponseWriter, flusher http.Flusher, eventObj *unstructured.Unstructured) {
+	ep.writeStreamEvent(w, flusher, kubernetesEventData(eventObj))
+}
+
+func kubernetesEventData(eventObj *unstructured.Unstructured) map[string]any {
 	eventType, _, _ := unstructured.NestedString(eventObj.Object, "type")
 	reason, _, _ := unstructured.NestedString(eventObj.Object, "reason")
 	message, _, _ := unstructured.NestedString(eventObj.Object, "message")
 	source, _, _ := unstructured.NestedString(eventObj.Object, "source", "component")
 
-	eventData := map[string]any{
+	return map[string]any{
 		"type":      "kubernetes_event",
 		"eventType": eventType,
 		"reason":    reason,
@@ -75,8 +79,6 @@ func (ep *EventProcessor) writeKubernetesEvent(w http.ResponseWriter, flusher ht
 		"source":    source,
 		"object":    eventObj.Object,
 	}
-
-	ep.writeStreamEvent(w, flusher, eventData)
 }
 
 func (ep *EventProcessor) writeStreamEvent(w http.ResponseWriter, flusher http.Flusher, data map[string]any) {
@@ -2,6 +2,7 @@ package main
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -11,24 +12,40 @@ type flags struct {
 	input      string
 	inputFile  string
 	timeout    time.Duration
-	outputMode string // "text" or "json"
-	verbose    bool   // Show detailed events and logs
-	quiet      bool   // Suppress events and progress indicators
+	outputMode string // -o value, see ParseOutputFormat
+	format     OutputFormat
+	verbose    bool // Show detailed events and logs
+	quiet      bool // Suppress events and progress indicators
 	namespace  string
 	parameters []string
 	sessionId  string
+	watch      bool // Attach to an existing query instead of triggering it
+	attach     bool // Stream progress and keep the created query
+	dryRun     bool // Print the query that would be created without submitting it
 }
 
 func (f *flags) addTo(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&f.input, "input", "i", "", "Override query input text")
 	cmd.Flags().StringVarP(&f.inputFile, "file", "f", "", "File containing query input (max 3MB)")
 	cmd.Flags().DurationVar(&f.timeout, "timeout", f.timeout, "Query timeout duration")
-	cmd.Flags().StringVarP(&f.outputMode, "output", "o", "text", "Output format: text or json")
+	cmd.Flags().StringVarP(&f.outputMode, "output", "o", OutputText, "Output format: "+outputFormatsHelp)
 	cmd.Flags().BoolVarP(&f.verbose, "verbose", "v", false, "Show detailed events and logs")
 	cmd.Flags().BoolVarP(&f.quiet, "quiet", "q", false, "Suppress event logs (spinner still shown)")
 	cmd.Flags().StringVarP(&f.namespace, "namespace", "n", "", "Namespace (defaults to configured namespace)")
 	cmd.Flags().StringArrayVarP(&f.parameters, "param", "p", nil, "Template parameters in key=value format (can be used multiple times)")
 	cmd.Flags().StringVar(&f.sessionId, "session-id", "", "Session ID to associate with the query")
+	cmd.Flags().BoolVar(&f.dryRun, "dry-run", false, "Validate and print the query that would be created without submitting it")
+}
+
+// registerParameterCompletion completes -p with the parameter keys of the target named by the first argument
+func (f *flags) registerParameterCompletion(cmd *cobra.Command, config *Config, targetType ResourceType) {
+	_ = cmd.RegisterFlagCompletionFunc("param", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) == 0 || strings.Contains(toComplete, "=") {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		keys := getParameterCompletions(config, targetType, args[0], f.namespace)
+		return keys, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	})
 }
 
 // validate validates the flag combination and sets defaults
@@ -46,8 +63,18 @@ func (f *flags) validate() error {
 		f.verbose = false // Ensure quiet overrides verbose
 	}
 
-	if f.outputMode != "text" && f.outputMode != "json" {
-		return fmt.Errorf("invalid output mode: %s. Must be 'text' or 'json'", f.outputMode)
+	if f.watch && (f.input != "" || f.inputFile != "" || len(f.parameters) > 0) {
+		return fmt.Errorf("cannot use --watch with --input, --file or --param")
+	}
+
+	if f.dryRun && (f.watch || f.attach) {
+		return fmt.Errorf("cannot use --dry-run with --watch or --attach")
+	}
+
+	format, err := ParseOutputFormat(f.outputMode)
+	if err != nil {
+		return err
 	}
+	f.format = format
 	return nil
 }
@@ -0,0 +1,358 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	arkv1 "mckinsey.com/ark/tools/fark/api/ark/v1"
+)
+
+// listableResources are the resources ListResources serves, matching the REST list endpoints
+var listableResources = []ResourceType{ResourceAgent, ResourceTeam, ResourceModel, ResourceTool, ResourceQuery}
+
+// arkServiceServer serves the gRPC API. It runs queries the same way as the REST endpoints, with
+// the config of the caller attached to the context by the auth interceptors.
+type arkServiceServer struct {
+	arkv1.UnimplementedArkServiceServer
+	config *Config
+}
+
+// newGRPCServer returns a gRPC server of the ARK service that authenticates callers like the REST server
+func newGRPCServer(config *Config, authenticator *Authenticator) *grpc.Server {
+	server := grpc.NewServer(
+		grpc.UnaryInterceptor(authenticator.unaryInterceptor),
+		grpc.StreamInterceptor(authenticator.streamInterceptor),
+	)
+	arkv1.RegisterArkServiceServer(server, &arkServiceServer{config: config})
+	return server
+}
+
+func serveGRPC(config *Config, authenticator *Authenticator, port string) error {
+	listener, err := net.Listen("tcp", ":"+port)
+	if err != nil {
+		return fmt.Errorf("failed to listen on gRPC port %s: %v", port, err)
+	}
+	return newGRPCServer(config, authenticator).Serve(listener)
+}
+
+func (s *arkServiceServer) ListResources(ctx context.Context, req *arkv1.ListResourcesRequest) (*arkv1.ListResourcesResponse, error) {
+	config := contextConfig(ctx, s.config)
+	resourceType, err := listableResourceType(req.GetKind())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	items, err := NewResourceManager(config).ListResources(resourceType, config.Namespace)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to list %s: %v", resourceType, err)
+	}
+
+	response := &arkv1.ListResourcesResponse{}
+	for _, item := range items {
+		data, err := json.Marshal(item)
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to encode %s: %v", resourceType, err)
+		}
+		resource := unstructured.Unstructured{Object: item}
+		response.Resources = append(response.Resources, &arkv1.Resource{
+			Name:      resource.GetName(),
+			Namespace: resource.GetNamespace(),
+			Json:      string(data),
+		})
+	}
+	return response, nil
+}
+
+func listableResourceType(kind string) (ResourceType, error) {
+	for _, resourceType := range listableResources {
+		if ResourceType(kind) == resourceType {
+			return resourceType, nil
+		}
+	}
+	return "", fmt.Errorf("invalid kind '%s'. Valid kinds: %v", kind, listableResources)
+}
+
+func (s *arkServiceServer) SubmitQuery(req *arkv1.SubmitQueryRequest, stream grpc.ServerStreamingServer[arkv1.QueryEvent]) error {
+	config := contextConfig(stream.Context(), s.config)
+
+	var query *arkv1alpha1.Query
+	var err error
+	if req.GetQueryName() != "" {
+		query, err = triggerQueryRequest(config, req)
+	} else {
+		query, err = targetQueryRequest(config, req)
+	}
+	if err != nil {
+		return err
+	}
+	if keys := metadata.ValueFromIncomingContext(stream.Context(), "idempotency-key"); len(keys) > 0 {
+		query.Spec.IdempotencyKey = keys[0]
+	}
+
+	if err := submitQuery(config, query); err != nil {
+		return status.Errorf(codes.Internal, "failed to create query: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(stream.Context(), arkv1alpha1.DefaultQueryTimeout)
+	defer cancel()
+	return streamQueryEvents(ctx, config, query.Name, stream)
+}
+
+// targetQueryRequest returns a new query of the request targets
+func targetQueryRequest(config *Config, req *arkv1.SubmitQueryRequest) (*arkv1alpha1.Query, error) {
+	if req.GetInput() == "" {
+		return nil, status.Error(codes.InvalidArgument, "input is required")
+	}
+	if len(req.GetTargets()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "targets or query_name is required")
+	}
+
+	targets := make([]arkv1alpha1.QueryTarget, 0, len(req.GetTargets()))
+	for _, target := range req.GetTargets() {
+		if err := validateTargetType(target.GetType()); err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if target.GetName() == "" {
+			return nil, status.Error(codes.InvalidArgument, "target name is required")
+		}
+		targets = append(targets, arkv1alpha1.QueryTarget{
+			Type:      target.GetType(),
+			Name:      target.GetName(),
+			Namespace: target.GetNamespace(),
+			Cluster:   target.GetCluster(),
+		})
+	}
+
+	query, err := createQuery(req.GetInput(), targets, config.Namespace, queryParameters(req.GetParameters()), req.GetSessionId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create query: %v", err)
+	}
+	return query, nil
+}
+
+// triggerQueryRequest returns a query that runs the named query again, with the overrides of the request
+func triggerQueryRequest(config *Config, req *arkv1.SubmitQueryRequest) (*arkv1alpha1.Query, error) {
+	existingQuery, err := getExistingQuery(config, req.GetQueryName(), config.Namespace)
+	if err != nil {
+		return nil, status.Errorf(codes.NotFound, "failed to get query: %v", err)
+	}
+
+	input := existingQuery.Spec.Input
+	if req.GetInput() != "" {
+		input = runtime.RawExtension{Raw: []byte(req.GetInput())}
+	}
+	params := existingQuery.Spec.Parameters
+	if len(req.GetParameters()) > 0 {
+		params = queryParameters(req.GetParameters())
+	}
+
+	query, err := createTriggerQuery(existingQuery, input, params, req.GetSessionId())
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to create trigger query: %v", err)
+	}
+	return query, nil
+}
+
+func queryParameters(parameters []*arkv1.Parameter) []arkv1alpha1.Parameter {
+	params := make([]arkv1alpha1.Parameter, 0, len(parameters))
+	for _, parameter := range parameters {
+		params = append(params, arkv1alpha1.Parameter{Name: parameter.GetName(), Value: parameter.GetValue()})
+	}
+	return params
+}
+
+func (s *arkServiceServer) StreamQuery(req *arkv1.StreamQueryRequest, stream grpc.ServerStreamingServer[arkv1.QueryEvent]) error {
+	config := contextConfig(stream.Context(), s.config)
+	query, err := getExistingQuery(config, req.GetName(), config.Namespace)
+	if err != nil {
+		return status.Errorf(codes.NotFound, "failed to get query: %v", err)
+	}
+
+	timeout := arkv1alpha1.DefaultQueryTimeout
+	if query.Spec.Timeout != nil {
+		timeout = query.Spec.Timeout.Duration
+	}
+	ctx, cancel := context.WithTimeout(stream.Context(), timeout)
+	defer cancel()
+	return streamQueryEvents(ctx, config, query.Name, stream)
+}
+
+// streamQueryEvents sends the events recorded so far, then phase changes, events and responses as
+// they happen, and finally a completed event. It is the gRPC counterpart of streamQueryUpdates.
+func streamQueryEvents(ctx context.Context, config *Config, queryName string, stream grpc.ServerStreamingServer[arkv1.QueryEvent]) error {
+	watcher := NewQueryWatcher(config, queryName, config.Namespace, config.Logger)
+	watcher.ReplayEvents = true
+	resultChan, err := watcher.Watch(ctx)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to watch query: %v", err)
+	}
+
+	responses := newResponseTracker()
+	lastPhase := ""
+	for result := range resultChan {
+		if result.Error != nil {
+			return status.Error(codes.Internal, result.Error.Error())
+		}
+		for _, event := range queryResultEvents(result, responses, &lastPhase) {
+			event.QueryName = queryName
+			if err := stream.Send(event); err != nil {
+				config.Logger.Debug("Stream client disconnected", zap.String("query", queryName), zap.Error(err))
+				return err
+			}
+		}
+		if result.Done {
+			return nil
+		}
+	}
+
+	// The watch ends early when the timeout expires or the client goes away
+	return status.FromContextError(ctx.Err()).Err()
+}
+
+func queryResultEvents(result QueryResult, responses *responseTracker, lastPhase *string) []*arkv1.QueryEvent {
+	if result.IsEvent {
+		eventType, _, _ := unstructured.NestedString(result.Event.Object, "type")
+		reason, _, _ := unstructured.NestedString(result.Event.Object, "reason")
+		message, _, _ := unstructured.NestedString(result.Event.Object, "message")
+		return []*arkv1.QueryEvent{{Event: &arkv1.QueryEvent_KubernetesEvent{
+			KubernetesEvent: &arkv1.KubernetesEvent{Type: eventType, Reason: reason, Message: message},
+		}}}
+	}
+
+	query := result.Query
+	if query == nil {
+		return nil
+	}
+
+	var events []*arkv1.QueryEvent
+	if query.Status.Phase != *lastPhase {
+		*lastPhase = query.Status.Phase
+		events = append(events, &arkv1.QueryEvent{Event: &arkv1.QueryEvent_Phase{
+			Phase: &arkv1.PhaseEvent{Phase: query.Status.Phase},
+		}})
+	}
+
+	for _, response := range responses.takeNew(query) {
+		events = append(events, &arkv1.QueryEvent{Event: &arkv1.QueryEvent_Response{Response: queryResponse(response)}})
+	}
+
+	if result.Done {
+		completed := &arkv1.Completed{
+			Phase: query.Status.Phase,
+			TokenUsage: &arkv1.TokenUsage{
+				PromptTokens:     query.Status.TokenUsage.PromptTokens,
+				CompletionTokens: query.Status.TokenUsage.CompletionTokens,
+				TotalTokens:      query.Status.TokenUsage.TotalTokens,
+			},
+		}
+		for _, response := range query.Status.Responses {
+			completed.Responses = append(completed.Responses, queryResponse(response))
+		}
+		events = append(events, &arkv1.QueryEvent{Event: &arkv1.QueryEvent_Completed{Completed: completed}})
+	}
+	return events
+}
+
+func queryResponse(response arkv1alpha1.Response) *arkv1.Response {
+	return &arkv1.Response{
+		Target: &arkv1.Target{
+			Type:      response.Target.Type,
+			Name:      response.Target.Name,
+			Namespace: response.Target.Namespace,
+			Cluster:   response.Target.Cluster,
+		},
+		Content: response.Content,
+		Phase:   response.Phase,
+	}
+}
+
+// grpcRequiredAccess maps a gRPC call to the permission it needs, like requiredAccess for REST
+// routes. Calls without a mapping are not allowed.
+func grpcRequiredAccess(fullMethod string, req any) (string, ResourceType, bool) {
+	switch fullMethod {
+	case arkv1.ArkService_ListResources_FullMethodName:
+		if list, ok := req.(*arkv1.ListResourcesRequest); ok {
+			if resourceType, err := listableResourceType(list.GetKind()); err == nil {
+				return "list", resourceType, true
+			}
+		}
+	case arkv1.ArkService_SubmitQuery_FullMethodName:
+		return "create", ResourceQuery, true
+	case arkv1.ArkService_StreamQuery_FullMethodName:
+		return "watch", ResourceQuery, true
+	}
+	return "", "", false
+}
+
+// grpcCallerContext authenticates the caller from the authorization metadata and attaches a
+// config scoped to them to ctx
+func (a *Authenticator) grpcCallerContext(ctx context.Context, fullMethod string, req any) (context.Context, error) {
+	if a.mode == AuthModeNone {
+		return ctx, nil
+	}
+
+	token := ""
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			token = bearerToken(values[0])
+		}
+	}
+
+	verb, resource, ok := grpcRequiredAccess(fullMethod, req)
+	if !ok {
+		return nil, status.Errorf(codes.PermissionDenied, "%s is not an allowed call", fullMethod)
+	}
+	scoped, err := a.callerConfig(ctx, token, verb, resource)
+	if err != nil {
+		var callerErr *callerError
+		if !errors.As(err, &callerErr) {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+		if callerErr.status == http.StatusUnauthorized {
+			return nil, status.Error(codes.Unauthenticated, callerErr.message)
+		}
+		return nil, status.Error(codes.PermissionDenied, callerErr.message)
+	}
+	return context.WithValue(ctx, requestConfigKey{}, scoped), nil
+}
+
+func (a *Authenticator) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.grpcCallerContext(ctx, info.FullMethod, req)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// streamInterceptor authenticates server-streaming calls. The access they need does not depend
+// on the request, so the caller is checked before the request is received.
+func (a *Authenticator) streamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.grpcCallerContext(stream.Context(), info.FullMethod, nil)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &callerServerStream{ServerStream: stream, ctx: ctx})
+}
+
+// callerServerStream is a server stream whose context carries the config of the caller
+type callerServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *callerServerStream) Context() context.Context {
+	return s.ctx
+}
@@ -13,17 +13,38 @@ import (
 )
 
 type TargetQueryRequest struct {
-	Name       string                  `json:"name"`
-	Input      string                  `json:"input"`
-	Parameters []arkv1alpha1.Parameter `json:"parameters,omitempty"`
-	SessionId  string                  `json:"sessionId,omitempty"`
+	Name           string                  `json:"name"`
+	Input          string                  `json:"input"`
+	Parameters     []arkv1alpha1.Parameter `json:"parameters,omitempty"`
+	SessionId      string                  `json:"sessionId,omitempty"`
+	IdempotencyKey string                  `json:"idempotencyKey,omitempty"`
 }
 
 type TriggerQueryRequest struct {
-	QueryName     string                  `json:"queryName"`
-	InputOverride string                  `json:"inputOverride,omitempty"`
-	Parameters    []arkv1alpha1.Parameter `json:"parameters,omitempty"`
-	SessionId     string                  `json:"sessionId,omitempty"`
+	QueryName      string                  `json:"queryName"`
+	InputOverride  string                  `json:"inputOverride,omitempty"`
+	Parameters     []arkv1alpha1.Parameter `json:"parameters,omitempty"`
+	SessionId      string                  `json:"sessionId,omitempty"`
+	IdempotencyKey string                  `json:"idempotencyKey,omitempty"`
+}
+
+type TemplateQueryRequest struct {
+	Parameters     []arkv1alpha1.Parameter   `json:"parameters,omitempty"`
+	Targets        []arkv1alpha1.QueryTarget `json:"targets,omitempty"`
+	SessionId      string                    `json:"sessionId,omitempty"`
+	IdempotencyKey string                    `json:"idempotencyKey,omitempty"`
+}
+
+// idempotencyKeyHeader carries the idempotency key of a query request when the body has none.
+// Resubmissions with the same key return the existing query instead of creating another one
+const idempotencyKeyHeader = "Idempotency-Key"
+
+// requestIdempotencyKey returns the idempotency key of the body, or else of the request header
+func requestIdempotencyKey(r *http.Request, bodyKey string) string {
+	if bodyKey != "" {
+		return bodyKey
+	}
+	return r.Header.Get(idempotencyKeyHeader)
 }
 
 func parseTargetQueryRequest(r *http.Request) (*TargetQueryRequest, error) {
@@ -46,7 +67,7 @@ func parseTriggerQueryRequest(r *http.Request) (*TriggerQueryRequest, error) {
 func handleListAgents(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 		handleListResource(config, ResourceAgent, w, r)
@@ -56,7 +77,7 @@ func handleListAgents(config *Config) http.HandlerFunc {
 func handleListTeams(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 		handleListResource(config, ResourceTeam, w, r)
@@ -66,7 +87,7 @@ func handleListTeams(config *Config) http.HandlerFunc {
 func handleListModels(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 		handleListResource(config, ResourceModel, w, r)
@@ -76,7 +97,7 @@ func handleListModels(config *Config) http.HandlerFunc {
 func handleListTools(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 		handleListResource(config, ResourceTool, w, r)
@@ -86,7 +107,7 @@ func handleListTools(config *Config) http.HandlerFunc {
 func handleListQueries(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodGet {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 		handleListResource(config, ResourceQuery, w, r)
@@ -96,14 +117,14 @@ func handleListQueries(config *Config) http.HandlerFunc {
 func handleTriggerQueryByName(config *Config) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 
 		// Extract query name from path
 		queryName := extractNameFromPath(r.URL.Path, "/query/")
 		if queryName == "" {
-			http.Error(w, "query name is required in path", http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, "query name is required in path", nil)
 			return
 		}
 
@@ -111,11 +132,55 @@ func handleTriggerQueryByName(config *Config) http.HandlerFunc {
 	}
 }
 
-func handleListResource(config *Config, resourceType ResourceType, w http.ResponseWriter, _ *http.Request) {
+// handleRunTemplate creates a query from the query template named in the path and streams its progress
+func handleRunTemplate(config *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		config := requestConfig(r, config)
+
+		var req TemplateQueryRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request: invalid JSON", err)
+			return
+		}
+
+		template, err := getQueryTemplate(config, r.PathValue("name"), config.Namespace)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "query template not found", err)
+			return
+		}
+
+		query, err := createTemplateQuery(template, req.Parameters, req.Targets, req.SessionId)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "invalid template parameters", err)
+			return
+		}
+		query.Spec.IdempotencyKey = requestIdempotencyKey(r, req.IdempotencyKey)
+
+		if err := submitQuery(config, query); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to create query", err)
+			return
+		}
+
+		flusher, err := setupStreamingResponse(w)
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, "streaming unsupported", nil)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), query.Spec.GetTimeout())
+		defer cancel()
+
+		processor := NewEventProcessor(config)
+		processor.StreamQueryEvents(ctx, w, flusher, query.Name)
+	}
+}
+
+func handleListResource(config *Config, resourceType ResourceType, w http.ResponseWriter, r *http.Request) {
+	config = requestConfig(r, config)
 	rm := NewResourceManager(config)
 	resources, err := rm.ListResources(resourceType, config.Namespace)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to list %s: %v", resourceType, err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, fmt.Sprintf("failed to list %s", resourceType), err)
 		return
 	}
 	writeJSONResponse(w, resources)
@@ -135,7 +200,7 @@ func extractNameFromPath(path, prefix string) string {
 func handleQueryResourceWithPath(config *Config, resourceType ResourceType) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
-			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			writeMethodNotAllowed(w)
 			return
 		}
 
@@ -143,7 +208,7 @@ func handleQueryResourceWithPath(config *Config, resourceType ResourceType) http
 		pathPrefix := fmt.Sprintf("/%s/", strings.TrimSuffix(string(resourceType), "s"))
 		name := extractNameFromPath(r.URL.Path, pathPrefix)
 		if name == "" {
-			http.Error(w, fmt.Sprintf("%s name is required in path", strings.TrimSuffix(string(resourceType), "s")), http.StatusBadRequest)
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("%s name is required in path", strings.TrimSuffix(string(resourceType), "s")), nil)
 			return
 		}
 
@@ -153,10 +218,12 @@ func handleQueryResourceWithPath(config *Config, resourceType ResourceType) http
 
 // handleQueryResourceWithName handles querying with the name already extracted
 func handleQueryResourceWithName(config *Config, resourceType ResourceType, w http.ResponseWriter, r *http.Request, name string) {
+	config = requestConfig(r, config)
+
 	// Parse request body to get input and optional parameters
 	req, err := parseTargetQueryRequest(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid request", err)
 		return
 	}
 
@@ -164,7 +231,7 @@ func handleQueryResourceWithName(config *Config, resourceType ResourceType, w ht
 	req.Name = name
 
 	if req.Input == "" {
-		http.Error(w, "input is required", http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "input is required", nil)
 		return
 	}
 
@@ -172,18 +239,19 @@ func handleQueryResourceWithName(config *Config, resourceType ResourceType, w ht
 	targets := []arkv1alpha1.QueryTarget{{Type: string(resourceType)[:len(resourceType)-1], Name: req.Name}}
 	query, err := createQuery(req.Input, targets, config.Namespace, req.Parameters, req.SessionId)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to create query: %v", err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "failed to create query", err)
 		return
 	}
+	query.Spec.IdempotencyKey = requestIdempotencyKey(r, req.IdempotencyKey)
 
 	if err := submitQuery(config, query); err != nil {
-		http.Error(w, fmt.Sprintf("failed to create query: %v", err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "failed to create query", err)
 		return
 	}
 
 	flusher, err := setupStreamingResponse(w)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "streaming unsupported", nil)
 		return
 	}
 
@@ -196,10 +264,12 @@ func handleQueryResourceWithName(config *Config, resourceType ResourceType, w ht
 
 // handleTriggerQueryWithName handles triggering query with name from path
 func handleTriggerQueryWithName(config *Config, w http.ResponseWriter, r *http.Request, queryName string) {
+	config = requestConfig(r, config)
+
 	// Parse request body to get optional overrides
 	req, err := parseTriggerQueryRequest(r)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("invalid request: %v", err), http.StatusBadRequest)
+		writeError(w, http.StatusBadRequest, "invalid request", err)
 		return
 	}
 
@@ -209,7 +279,7 @@ func handleTriggerQueryWithName(config *Config, w http.ResponseWriter, r *http.R
 	// Get existing query
 	existingQuery, err := getExistingQuery(config, req.QueryName, config.Namespace)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to get query: %v", err), http.StatusNotFound)
+		writeError(w, http.StatusNotFound, "query not found", err)
 		return
 	}
 
@@ -228,18 +298,19 @@ func handleTriggerQueryWithName(config *Config, w http.ResponseWriter, r *http.R
 	// Create triggered query
 	newQuery, err := createTriggerQuery(existingQuery, input, params, req.SessionId)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to create trigger query: %v", err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "failed to create trigger query", err)
 		return
 	}
+	newQuery.Spec.IdempotencyKey = requestIdempotencyKey(r, req.IdempotencyKey)
 
 	if err := submitQuery(config, newQuery); err != nil {
-		http.Error(w, fmt.Sprintf("failed to create triggered query: %v", err), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "failed to create triggered query", err)
 		return
 	}
 
 	flusher, err := setupStreamingResponse(w)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, http.StatusInternalServerError, "streaming unsupported", nil)
 		return
 	}
 
@@ -22,3 +22,56 @@ func writeJSONResponse(w http.ResponseWriter, data interface{}) error {
 	w.Header().Set("Content-Type", "application/json")
 	return json.NewEncoder(w).Encode(data)
 }
+
+// Error codes of ErrorResponse
+const (
+	ErrorCodeInvalidRequest   = "InvalidRequest"
+	ErrorCodeUnauthorized     = "Unauthorized"
+	ErrorCodeForbidden        = "Forbidden"
+	ErrorCodeNotFound         = "NotFound"
+	ErrorCodeMethodNotAllowed = "MethodNotAllowed"
+	ErrorCodeInternal         = "InternalError"
+)
+
+// ErrorResponse is the JSON body of every error the HTTP server returns before it starts streaming
+type ErrorResponse struct {
+	// Code identifies the kind of error, derived from the HTTP status
+	Code string `json:"code"`
+	// Message describes the error
+	Message string `json:"message"`
+	// Details carries the underlying error, when there is one
+	Details string `json:"details,omitempty"`
+}
+
+func errorCode(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return ErrorCodeInvalidRequest
+	case http.StatusUnauthorized:
+		return ErrorCodeUnauthorized
+	case http.StatusForbidden:
+		return ErrorCodeForbidden
+	case http.StatusNotFound:
+		return ErrorCodeNotFound
+	case http.StatusMethodNotAllowed:
+		return ErrorCodeMethodNotAllowed
+	default:
+		return ErrorCodeInternal
+	}
+}
+
+// writeError writes an ErrorResponse with status. The details are taken from err when it is set.
+func writeError(w http.ResponseWriter, status int, message string, err error) {
+	body := ErrorResponse{Code: errorCode(status), Message: message}
+	if err != nil {
+		body.Details = err.Error()
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+func writeMethodNotAllowed(w http.ResponseWriter) {
+	writeError(w, http.StatusMethodNotAllowed, "method not allowed", nil)
+}
@@ -2,12 +2,12 @@ package main
 
 import (
 	"fmt"
-	"log"
 	"os"
+	"path/filepath"
+	"strings"
 
 	"github.com/spf13/cobra"
 	"k8s.io/client-go/dynamic"
-	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
 
 	// Import all Kubernetes client auth plugins (e.g. Azure, GCP, OIDC, etc.)
@@ -15,6 +15,9 @@ import (
 	_ "k8s.io/client-go/plugin/pkg/client/auth"
 )
 
+// kubectlPluginPrefix is the prefix kubectl finds plugins by on the PATH
+const kubectlPluginPrefix = "kubectl-"
+
 func main() {
 	config := initializeConfig()
 	rootCmd := createRootCommand(config)
@@ -26,30 +29,9 @@ func main() {
 }
 
 func initializeConfig() *Config {
-	kubeConfig, contextNamespace, err := getKubeConfigAndNamespace()
-	if err != nil {
-		log.Fatalf("Failed to get kubeconfig: %v", err)
-	}
-
-	dynamicClient, err := dynamic.NewForConfig(kubeConfig)
-	if err != nil {
-		log.Fatalf("Failed to create dynamic client: %v", err)
-	}
-
-	// Priority: context namespace > "default"
-	namespace := contextNamespace
-	if namespace == "" {
-		namespace = "default"
-	}
-	port := "8080"
-
-	logger := initLogger()
-
 	return &Config{
-		DynamicClient: dynamicClient,
-		Namespace:     namespace,
-		Port:          port,
-		Logger:        logger,
+		Port:   "8080",
+		Logger: initLogger(),
 	}
 }
 
@@ -70,9 +52,16 @@ agentic workloads across Kubernetes namespaces.`,
 			}
 			return fmt.Errorf("unknown command %q for %q", args[0], cmd.CommandPath())
 		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if !requiresCluster(cmd) {
+				return nil
+			}
+			return config.connect()
+		},
 		SilenceUsage:  true,
 		SilenceErrors: true,
 	}
+	config.kubeConfig = bindKubeConfigFlags(rootCmd)
 
 	cf := NewCommandFactory(config)
 	rootCmd.AddCommand(createServerCommand(config))
@@ -81,51 +70,102 @@ agentic workloads across Kubernetes namespaces.`,
 	rootCmd.AddCommand(cf.CreateTargetCommand(ResourceModel, "model [model-name] [query...]", "Query models"))
 	rootCmd.AddCommand(cf.CreateTargetCommand(ResourceTool, "tool [tool-name] [request...]", "Query tools"))
 	rootCmd.AddCommand(createQueryCommand(config))
+	rootCmd.AddCommand(createChatCommand(config))
+	rootCmd.AddCommand(createBatchCommand(config))
+	rootCmd.AddCommand(createBenchmarkCommand(config))
+	rootCmd.AddCommand(createDescribeCommand(config))
+	rootCmd.AddCommand(createReplayCommand(config))
+	rootCmd.AddCommand(createRunCommand(config))
+	rootCmd.AddCommand(createExportCommand(config))
+	rootCmd.AddCommand(createImportCommand(config))
 
 	// Add CRUD commands
 	rootCmd.AddCommand(createGetCommand(config))
 	rootCmd.AddCommand(createCreateCommand(config))
 	rootCmd.AddCommand(createUpdateCommand(config))
 	rootCmd.AddCommand(createDeleteCommand(config))
+	rootCmd.AddCommand(createRollbackCommand(config))
+	rootCmd.AddCommand(createDiffCommand(config))
+	rootCmd.AddCommand(createApproveCommand(config))
+	rootCmd.AddCommand(createRejectCommand(config))
+
+	if isKubectlPlugin() {
+		useKubectlPluginNames(rootCmd)
+	}
 
 	return rootCmd
 }
 
-func getKubeConfigAndNamespace() (*rest.Config, string, error) {
-	// Try in-cluster config first
-	config, err := rest.InClusterConfig()
-	if err == nil {
-		// In-cluster - no context namespace available
-		return config, "", nil
-	}
+// bindKubeConfigFlags adds the kubectl flags selecting the kubeconfig, context, cluster, user and
+// namespace to a command. They are resolved like kubectl resolves them: the KUBECONFIG path list or
+// ~/.kube/config, the current context unless --context is set, and the in-cluster config without a
+// kubeconfig.
+func bindKubeConfigFlags(cmd *cobra.Command) clientcmd.ClientConfig {
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	overrides := &clientcmd.ConfigOverrides{}
 
-	// Use kubeconfig file
-	kubeconfig := os.Getenv("KUBECONFIG")
-	if kubeconfig == "" {
-		kubeconfig = os.Getenv("HOME") + "/.kube/config"
-	}
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&loadingRules.ExplicitPath, clientcmd.RecommendedConfigPathFlag, "", "Path to the kubeconfig file to use for CLI requests")
+	clientcmd.BindOverrideFlags(overrides, flags, clientcmd.RecommendedConfigOverrideFlags(""))
+
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides)
+}
 
-	// Load the kubeconfig to get context namespace
-	configLoader := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		&clientcmd.ClientConfigLoadingRules{ExplicitPath: kubeconfig},
-		&clientcmd.ConfigOverrides{},
-	)
+// connect creates the cluster client from the kubeconfig flags on first use. The namespace is the
+// one of --namespace, then of the selected context, then "default".
+func (c *Config) connect() error {
+	if c.DynamicClient != nil {
+		return nil
+	}
 
-	rawConfig, err := configLoader.RawConfig()
+	restConfig, err := c.kubeConfig.ClientConfig()
+	if err != nil {
+		return fmt.Errorf("failed to get kubeconfig: %v", err)
+	}
+	namespace, _, err := c.kubeConfig.Namespace()
+	if err != nil {
+		return fmt.Errorf("failed to get namespace from kubeconfig: %v", err)
+	}
+	dynamicClient, err := dynamic.NewForConfig(restConfig)
 	if err != nil {
-		// Fallback to basic config loading
-		config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
-		return config, "", err
+		return fmt.Errorf("failed to create dynamic client: %v", err)
 	}
 
-	// Get current context namespace
-	currentContext := rawConfig.CurrentContext
-	contextNamespace := ""
-	if context, exists := rawConfig.Contexts[currentContext]; exists && context.Namespace != "" {
-		contextNamespace = context.Namespace
+	c.RestConfig = restConfig
+	c.DynamicClient = dynamicClient
+	c.Namespace = namespace
+	return nil
+}
+
+// requiresCluster returns false for the help and completion commands, which work without a cluster.
+// Completion functions connect on demand, once the flags of the completed command are parsed.
+func requiresCluster(cmd *cobra.Command) bool {
+	for c := cmd; c != nil; c = c.Parent() {
+		switch c.Name() {
+		case "help", "completion", cobra.ShellCompRequestCmd, cobra.ShellCompNoDescRequestCmd:
+			return false
+		}
 	}
+	return true
+}
 
-	// Build the rest config
-	config, err = configLoader.ClientConfig()
-	return config, contextNamespace, err
+// isKubectlPlugin returns whether fark runs as the kubectl-ark plugin, which kubectl runs for
+// `kubectl ark`
+func isKubectlPlugin() bool {
+	return strings.HasPrefix(filepath.Base(os.Args[0]), kubectlPluginPrefix)
+}
+
+// useKubectlPluginNames makes help and examples show `kubectl ark` rather than `fark`
+func useKubectlPluginNames(rootCmd *cobra.Command) {
+	rootCmd.Use = "ark"
+	rootCmd.Annotations = map[string]string{cobra.CommandDisplayNameAnnotation: "kubectl ark"}
+
+	var rename func(cmd *cobra.Command)
+	rename = func(cmd *cobra.Command) {
+		cmd.Example = strings.ReplaceAll(cmd.Example, "fark ", "kubectl ark ")
+		for _, sub := range cmd.Commands() {
+			rename(sub)
+		}
+	}
+	rename(rootCmd)
 }
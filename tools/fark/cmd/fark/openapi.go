@@ -0,0 +1,276 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// openAPIPath serves the OpenAPI document. It is served without authentication.
+	openAPIPath = "/openapi.json"
+	// openAPIVersion is the version of the HTTP API described by the OpenAPI document
+	openAPIVersion = "v1alpha1"
+)
+
+// handleOpenAPI serves the OpenAPI 3 document of the HTTP server
+func handleOpenAPI() http.HandlerFunc {
+	document := openAPIDocument()
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := writeJSONResponse(w, document); err != nil {
+			writeError(w, http.StatusInternalServerError, "failed to write OpenAPI document", err)
+		}
+	}
+}
+
+// openAPIDocument describes the routes registered by setupRoutes
+func openAPIDocument() map[string]any {
+	paths := map[string]any{}
+
+	for _, resourceType := range []ResourceType{ResourceAgent, ResourceTeam, ResourceModel, ResourceTool, ResourceQuery} {
+		paths["/"+string(resourceType)] = map[string]any{
+			"get": map[string]any{
+				"operationId": "list" + titleCase(string(resourceType)),
+				"summary":     fmt.Sprintf("List the %s of the namespace", resourceType),
+				"tags":        []string{string(resourceType)},
+				"responses": map[string]any{
+					"200": jsonResponse("The resources", map[string]any{
+						"type":  "array",
+						"items": map[string]any{"type": "object", "additionalProperties": true},
+					}),
+					"401": errorResponse("The bearer token is missing or invalid"),
+					"403": errorResponse("The caller may not list the resources"),
+					"405": errorResponse("The method is not allowed"),
+					"500": errorResponse("The resources could not be listed"),
+				},
+			},
+		}
+	}
+
+	for _, resourceType := range []ResourceType{ResourceAgent, ResourceTeam, ResourceModel, ResourceTool} {
+		kind := strings.TrimSuffix(string(resourceType), "s")
+		paths["/"+kind+"/{name}"] = map[string]any{
+			"post": map[string]any{
+				"operationId": "query" + titleCase(kind),
+				"summary":     fmt.Sprintf("Run a query against the %s and stream its progress", kind),
+				"tags":        []string{string(resourceType)},
+				"parameters":  []any{nameParameter("Name of the " + kind)},
+				"requestBody": jsonRequestBody("TargetQueryRequest"),
+				"responses":   queryStreamResponses(),
+			},
+		}
+	}
+
+	paths["/query/{name}"] = map[string]any{
+		"post": map[string]any{
+			"operationId": "triggerQuery",
+			"summary":     "Run a saved query again and stream its progress",
+			"tags":        []string{string(ResourceQuery)},
+			"parameters":  []any{nameParameter("Name of the saved query")},
+			"requestBody": jsonRequestBody("TriggerQueryRequest"),
+			"responses":   queryStreamResponses(),
+		},
+	}
+	paths["/template/{name}"] = map[string]any{
+		"post": map[string]any{
+			"operationId": "runTemplate",
+			"summary":     "Create a query from a query template and stream its progress",
+			"tags":        []string{"templates"},
+			"parameters":  []any{nameParameter("Name of the query template")},
+			"requestBody": jsonRequestBody("TemplateQueryRequest"),
+			"responses":   queryStreamResponses(),
+		},
+	}
+	paths["/query/{name}/stream"] = map[string]any{
+		"get": map[string]any{
+			"operationId": "streamQuery",
+			"summary":     "Stream the progress of an existing query without triggering it",
+			"description": "Uses server-sent events, or WebSocket when the request carries Upgrade: websocket. Each message is a StreamMessage.",
+			"tags":        []string{string(ResourceQuery)},
+			"parameters":  []any{nameParameter("Name of the query")},
+			"responses": map[string]any{
+				"200": map[string]any{
+					"description": "Server-sent events, one StreamMessage per update",
+					"content": map[string]any{
+						"text/event-stream": map[string]any{"schema": schemaRef("StreamMessage")},
+					},
+				},
+				"401": errorResponse("The bearer token is missing or invalid"),
+				"403": errorResponse("The caller may not watch queries"),
+				"404": errorResponse("The query does not exist"),
+				"500": errorResponse("Streaming is not supported"),
+			},
+		},
+	}
+	paths[openAPIPath] = map[string]any{
+		"get": map[string]any{
+			"operationId": "getOpenAPI",
+			"summary":     "Get this OpenAPI document",
+			"responses": map[string]any{
+				"200": jsonResponse("The OpenAPI document", map[string]any{"type": "object"}),
+			},
+		},
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":       "fark HTTP API",
+			"description": "Lists ARK resources and runs queries against them.",
+			"version":     openAPIVersion,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": openAPISchemas(),
+			"securitySchemes": map[string]any{
+				"bearerAuth": map[string]any{
+					"type":        "http",
+					"scheme":      "bearer",
+					"description": "Kubernetes bearer token, required when the server runs with --auth",
+				},
+			},
+		},
+		"security": []any{map[string]any{}, map[string]any{"bearerAuth": []string{}}},
+	}
+}
+
+func openAPISchemas() map[string]any {
+	parameters := map[string]any{"type": "array", "items": schemaRef("Parameter")}
+	sessionID := map[string]any{"type": "string", "description": "Session of the query, for memory"}
+	idempotencyKey := map[string]any{"type": "string", "maxLength": 256, "description": "Resubmissions with the same key return the existing query instead of creating another one. Also read from the Idempotency-Key header"}
+
+	return map[string]any{
+		"TargetQueryRequest": map[string]any{
+			"type":     "object",
+			"required": []string{"input"},
+			"properties": map[string]any{
+				"input":          map[string]any{"type": "string", "description": "Input of the query"},
+				"parameters":     parameters,
+				"sessionId":      sessionID,
+				"idempotencyKey": idempotencyKey,
+			},
+		},
+		"TriggerQueryRequest": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"inputOverride":  map[string]any{"type": "string", "description": "Input replacing the input of the saved query"},
+				"parameters":     parameters,
+				"sessionId":      sessionID,
+				"idempotencyKey": idempotencyKey,
+			},
+		},
+		"TemplateQueryRequest": map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"parameters":     parameters,
+				"targets":        map[string]any{"type": "array", "items": schemaRef("QueryTarget"), "description": "Targets replacing the targets of the template"},
+				"sessionId":      sessionID,
+				"idempotencyKey": idempotencyKey,
+			},
+		},
+		"Parameter": map[string]any{
+			"type":     "object",
+			"required": []string{"name"},
+			"properties": map[string]any{
+				"name":      map[string]any{"type": "string"},
+				"value":     map[string]any{"type": "string"},
+				"valueFrom": map[string]any{"type": "object", "additionalProperties": true, "description": "Reference to a ConfigMap, Secret or query field"},
+			},
+		},
+		"QueryTarget": map[string]any{
+			"type":     "object",
+			"required": []string{"type", "name"},
+			"properties": map[string]any{
+				"type":      map[string]any{"type": "string", "enum": []string{"agent", "team", "model", "tool"}},
+				"name":      map[string]any{"type": "string"},
+				"namespace": map[string]any{"type": "string"},
+				"cluster":   map[string]any{"type": "string"},
+			},
+		},
+		"StreamMessage": map[string]any{
+			"type":     "object",
+			"required": []string{"type"},
+			"properties": map[string]any{
+				"type":     map[string]any{"type": "string", "enum": []string{"kubernetes_event", "phase", "response", "completed", "error"}},
+				"phase":    map[string]any{"type": "string", "description": "Phase of the query, for phase and completed messages"},
+				"response": map[string]any{"type": "object", "additionalProperties": true, "description": "Response of a target, for response messages"},
+				"query":    map[string]any{"type": "object", "additionalProperties": true, "description": "The full query, for completed messages"},
+				"message":  map[string]any{"type": "string", "description": "Reason the stream stopped, for error messages"},
+			},
+			"additionalProperties": true,
+		},
+		"ErrorResponse": map[string]any{
+			"type":     "object",
+			"required": []string{"code", "message"},
+			"properties": map[string]any{
+				"code": map[string]any{"type": "string", "enum": []string{
+					ErrorCodeInvalidRequest, ErrorCodeUnauthorized, ErrorCodeForbidden,
+					ErrorCodeNotFound, ErrorCodeMethodNotAllowed, ErrorCodeInternal,
+				}},
+				"message": map[string]any{"type": "string"},
+				"details": map[string]any{"type": "string", "description": "The underlying error, when there is one"},
+			},
+		},
+	}
+}
+
+// queryStreamResponses are the responses of the endpoints that create a query and stream its progress
+func queryStreamResponses() map[string]any {
+	return map[string]any{
+		"200": map[string]any{
+			"description": "Server-sent events with the progress and the final query",
+			"content": map[string]any{
+				"text/plain": map[string]any{"schema": map[string]any{"type": "string"}},
+			},
+		},
+		"400": errorResponse("The request is invalid"),
+		"401": errorResponse("The bearer token is missing or invalid"),
+		"403": errorResponse("The caller may not create queries"),
+		"404": errorResponse("The resource does not exist"),
+		"405": errorResponse("The method is not allowed"),
+		"500": errorResponse("The query could not be created"),
+	}
+}
+
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+func nameParameter(description string) map[string]any {
+	return map[string]any{
+		"name":        "name",
+		"in":          "path",
+		"required":    true,
+		"description": description,
+		"schema":      map[string]any{"type": "string"},
+	}
+}
+
+func jsonRequestBody(schema string) map[string]any {
+	return map[string]any{
+		"required": true,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schemaRef(schema)},
+		},
+	}
+}
+
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{"schema": schema},
+		},
+	}
+}
+
+func errorResponse(description string) map[string]any {
+	return jsonResponse(description, schemaRef("ErrorResponse"))
+}
+
+func titleCase(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,263 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/util/jsonpath"
+	"sigs.k8s.io/yaml"
+)
+
+// Output formats accepted by -o
+const (
+	OutputText          = "text"
+	OutputTable         = "table"
+	OutputJSON          = "json"
+	OutputYAML          = "yaml"
+	OutputJSONPath      = "jsonpath"
+	OutputGoTemplate    = "go-template"
+	OutputCustomColumns = "custom-columns"
+)
+
+const outputFormatsHelp = "text, table, json, yaml, jsonpath=<expr>, go-template=<template> or custom-columns=<HEADER:jsonpath,...>"
+
+// OutputFormat is a parsed -o value. Argument holds the expression of jsonpath,
+// go-template and custom-columns formats.
+type OutputFormat struct {
+	Name     string
+	Argument string
+}
+
+// ParseOutputFormat parses values such as "yaml" or "jsonpath={.metadata.name}"
+func ParseOutputFormat(value string) (OutputFormat, error) {
+	name, argument, hasArgument := strings.Cut(value, "=")
+	switch name {
+	case OutputText, OutputTable, OutputJSON, OutputYAML:
+		if hasArgument {
+			return OutputFormat{}, fmt.Errorf("output format %s does not take an argument", name)
+		}
+	case OutputJSONPath, OutputGoTemplate, OutputCustomColumns:
+		if argument == "" {
+			return OutputFormat{}, fmt.Errorf("output format %s requires an argument, e.g. %s=...", name, name)
+		}
+	default:
+		return OutputFormat{}, fmt.Errorf("invalid output format: %s. Must be one of %s", value, outputFormatsHelp)
+	}
+	return OutputFormat{Name: name, Argument: argument}, nil
+}
+
+// column is a table column whose value is read with a JSONPath expression
+type column struct {
+	Header   string
+	JSONPath string
+}
+
+// resourceColumns mirrors the printer columns of the CRDs, so tables match kubectl get
+var resourceColumns = map[ResourceType][]column{
+	ResourceAgent: {
+		{"NAME", "{.metadata.name}"},
+		{"MODEL", "{.spec.modelRef.name}"},
+		{"AVAILABLE", `{.status.conditions[?(@.type=="Available")].status}`},
+		{"AGE", "{.metadata.creationTimestamp}"},
+	},
+	ResourceTeam: {
+		{"NAME", "{.metadata.name}"},
+		{"STRATEGY", "{.spec.strategy}"},
+		{"AGE", "{.metadata.creationTimestamp}"},
+	},
+	ResourceModel: {
+		{"NAME", "{.metadata.name}"},
+		{"TYPE", "{.spec.type}"},
+		{"MODEL", "{.spec.model.value}"},
+		{"AVAILABLE", `{.status.conditions[?(@.type=="ModelAvailable")].status}`},
+		{"AGE", "{.metadata.creationTimestamp}"},
+	},
+	ResourceTool: {
+		{"NAME", "{.metadata.name}"},
+		{"TYPE", "{.spec.type}"},
+		{"DESCRIPTION", "{.spec.description}"},
+		{"AGE", "{.metadata.creationTimestamp}"},
+	},
+	ResourceQuery: {
+		{"NAME", "{.metadata.name}"},
+		{"TYPE", "{.spec.type}"},
+		{"PHASE", "{.status.phase}"},
+		{"DURATION", "{.status.duration}"},
+		{"AGE", "{.metadata.creationTimestamp}"},
+	},
+}
+
+var defaultColumns = []column{
+	{"NAME", "{.metadata.name}"},
+	{"AGE", "{.metadata.creationTimestamp}"},
+}
+
+// PrintList renders resources. Structured formats receive {"items": [...]} like kubectl,
+// except json, which stays a plain array for existing scripts.
+func (o OutputFormat) PrintList(w io.Writer, resourceType ResourceType, items []map[string]any) error {
+	switch o.Name {
+	case OutputJSON:
+		return printJSON(w, items)
+	case OutputYAML:
+		return printYAML(w, items)
+	case OutputJSONPath, OutputGoTemplate:
+		list := make([]any, len(items))
+		for i, item := range items {
+			list[i] = item
+		}
+		return o.printTemplate(w, map[string]any{"items": list})
+	default:
+		return o.printTable(w, resourceType, items)
+	}
+}
+
+// PrintObject renders a single resource
+func (o OutputFormat) PrintObject(w io.Writer, resourceType ResourceType, obj map[string]any) error {
+	switch o.Name {
+	case OutputJSON:
+		return printJSON(w, obj)
+	case OutputYAML:
+		return printYAML(w, obj)
+	case OutputJSONPath, OutputGoTemplate:
+		return o.printTemplate(w, obj)
+	default:
+		return o.printTable(w, resourceType, []map[string]any{obj})
+	}
+}
+
+func printJSON(w io.Writer, data any) error {
+	jsonData, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(jsonData))
+	return err
+}
+
+func printYAML(w io.Writer, data any) error {
+	yamlData, err := yaml.Marshal(data)
+	if err != nil {
+		return fmt.Errorf("failed to marshal YAML: %v", err)
+	}
+	_, err = w.Write(yamlData)
+	return err
+}
+
+func (o OutputFormat) printTemplate(w io.Writer, data any) error {
+	if o.Name == OutputGoTemplate {
+		tmpl, err := template.New("output").Parse(o.Argument)
+		if err != nil {
+			return fmt.Errorf("invalid go-template: %v", err)
+		}
+		return tmpl.Execute(w, data)
+	}
+
+	parser, err := parseJSONPath(o.Argument)
+	if err != nil {
+		return err
+	}
+	if err := parser.Execute(w, data); err != nil {
+		return fmt.Errorf("failed to execute jsonpath: %v", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// parseJSONPath accepts expressions with or without the surrounding braces, as kubectl does
+func parseJSONPath(expression string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expression, "{") {
+		expression = "{" + expression + "}"
+	}
+	parser := jsonpath.New("output").AllowMissingKeys(true)
+	if err := parser.Parse(expression); err != nil {
+		return nil, fmt.Errorf("invalid jsonpath %s: %v", expression, err)
+	}
+	return parser, nil
+}
+
+func (o OutputFormat) columns(resourceType ResourceType) ([]column, error) {
+	if o.Name != OutputCustomColumns {
+		if columns, ok := resourceColumns[resourceType]; ok {
+			return columns, nil
+		}
+		return defaultColumns, nil
+	}
+
+	var columns []column
+	for _, spec := range strings.Split(o.Argument, ",") {
+		header, path, found := strings.Cut(spec, ":")
+		if !found || header == "" || path == "" {
+			return nil, fmt.Errorf("invalid custom column %q, expected HEADER:jsonpath", spec)
+		}
+		columns = append(columns, column{Header: header, JSONPath: path})
+	}
+	return columns, nil
+}
+
+func (o OutputFormat) printTable(w io.Writer, resourceType ResourceType, items []map[string]any) error {
+	columns, err := o.columns(resourceType)
+	if err != nil {
+		return err
+	}
+
+	parsers := make([]*jsonpath.JSONPath, len(columns))
+	headers := make([]string, len(columns))
+	for i, col := range columns {
+		if parsers[i], err = parseJSONPath(col.JSONPath); err != nil {
+			return err
+		}
+		headers[i] = col.Header
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(headers, "\t"))
+	for _, item := range items {
+		values := make([]string, len(columns))
+		for i, col := range columns {
+			var value strings.Builder
+			if err := parsers[i].Execute(&value, item); err != nil {
+				return fmt.Errorf("failed to read column %s: %v", col.Header, err)
+			}
+			values[i] = value.String()
+			if col.JSONPath == "{.metadata.creationTimestamp}" {
+				values[i] = formatAge(values[i])
+			}
+			if values[i] == "" {
+				values[i] = "<none>"
+			}
+		}
+		fmt.Fprintln(tw, strings.Join(values, "\t"))
+	}
+	return tw.Flush()
+}
+
+func formatAge(timestamp string) string {
+	created, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return timestamp
+	}
+	return duration.HumanDuration(time.Since(created))
+}
+
+// toMap converts a typed object to the map form the renderers work on
+func toMap(obj any) (map[string]any, error) {
+	if u, ok := obj.(*unstructured.Unstructured); ok {
+		return u.Object, nil
+	}
+	data, err := json.Marshal(obj)
+	if err != nil {
+		return nil, err
+	}
+	var result map[string]any
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
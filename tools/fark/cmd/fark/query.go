@@ -1,11 +1,16 @@
 package main
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
+	"text/template"
 	"time"
 
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -40,17 +45,164 @@ func createQuery(input string, targets []arkv1alpha1.QueryTarget, namespace stri
 }
 
 func submitQuery(config *Config, query *arkv1alpha1.Query) error {
+	// The requester is only ever the authenticated caller, never copied from another query
+	delete(query.Annotations, annotations.RequestedBy)
+	if config.User != "" {
+		if query.Annotations == nil {
+			query.Annotations = map[string]string{}
+		}
+		query.Annotations[annotations.RequestedBy] = config.User
+	}
+	if config.Impersonate != nil {
+		// A query cannot run as both, and running it as its service account would give the caller
+		// the permissions of the service account
+		if query.Spec.ServiceAccount != "" {
+			return fmt.Errorf("query %s sets serviceAccount %s, which cannot be used when queries run as the caller", query.Name, query.Spec.ServiceAccount)
+		}
+		query.Spec.Impersonate = config.Impersonate
+	}
+
+	if query.Spec.IdempotencyKey == "" {
+		return createQueryResource(config, query)
+	}
+
+	// Submissions with the same key share a name, so the API server rejects a duplicate the
+	// webhook has not seen yet
+	query.GenerateName = ""
+	query.Name = arkv1alpha1.IdempotentQueryName(query.Namespace, query.Spec.IdempotencyKey)
+	err := createQueryResource(config, query)
+	if _, ok := idempotentQueryName(err); !ok && apierrors.IsAlreadyExists(err) {
+		// Submitted again, the webhook names the query of the key, unless it was created before
+		// the idempotency window and the key may be used for a new query
+		err = createQueryResource(config, query)
+		if _, ok := idempotentQueryName(err); !ok && apierrors.IsAlreadyExists(err) {
+			query.GenerateName = query.Name + "-"
+			query.Name = ""
+			return createQueryResource(config, query)
+		}
+	}
+	if name, ok := idempotentQueryName(err); ok {
+		return useSubmittedQuery(config, query, name)
+	}
+	return err
+}
+
+func createQueryResource(config *Config, query *arkv1alpha1.Query) error {
 	unstructuredQuery, err := convertToUnstructured(query)
 	if err != nil {
 		return fmt.Errorf("failed to convert query: %v", err)
 	}
 
-	_, err = config.DynamicClient.Resource(GetGVR(ResourceQuery)).Namespace(query.Namespace).Create(
+	created, err := config.DynamicClient.Resource(GetGVR(ResourceQuery)).Namespace(query.Namespace).Create(
 		context.TODO(),
 		unstructuredQuery,
 		metav1.CreateOptions{},
 	)
-	return err
+	if err != nil {
+		return err
+	}
+	query.Name = created.GetName()
+	return nil
+}
+
+// useSubmittedQuery points query at the query already submitted with its idempotency key, which
+// the caller only gets when it was requested by the same user
+func useSubmittedQuery(config *Config, query *arkv1alpha1.Query, name string) error {
+	existing, err := config.DynamicClient.Resource(GetGVR(ResourceQuery)).Namespace(query.Namespace).Get(
+		context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return fmt.Errorf("failed to get query %s submitted with idempotencyKey '%s': %v", name, query.Spec.IdempotencyKey, err)
+	}
+	if existing.GetAnnotations()[annotations.RequestedBy] != query.Annotations[annotations.RequestedBy] {
+		return fmt.Errorf("idempotencyKey '%s' was already used by another user", query.Spec.IdempotencyKey)
+	}
+	query.Name = name
+	return nil
+}
+
+// idempotentQueryName returns the name of the existing query when the query webhook rejected a
+// create because a query was already created with the same idempotency key
+func idempotentQueryName(err error) (string, bool) {
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) || status.Status().Details == nil {
+		return "", false
+	}
+	details := status.Status().Details
+	for _, cause := range details.Causes {
+		if cause.Type == metav1.CauseTypeFieldValueDuplicate && cause.Field == "spec.idempotencyKey" {
+			return details.Name, details.Name != ""
+		}
+	}
+	return "", false
+}
+
+// dryRunQuery validates a query with a server-side dry run, so the CRD schema and admission
+// webhooks are applied, and prints the manifest that would be created
+func dryRunQuery(config *Config, query *arkv1alpha1.Query, outputMode string, quiet bool) error {
+	format, err := ParseOutputFormat(outputMode)
+	if err != nil {
+		return err
+	}
+
+	resolved, err := resolveInputTemplate(query)
+	if err != nil {
+		return fmt.Errorf("invalid input template: %v", err)
+	}
+
+	unstructuredQuery, err := convertToUnstructured(query)
+	if err != nil {
+		return fmt.Errorf("failed to convert query: %v", err)
+	}
+
+	result, err := config.DynamicClient.Resource(GetGVR(ResourceQuery)).Namespace(query.Namespace).Create(
+		context.TODO(),
+		unstructuredQuery,
+		metav1.CreateOptions{DryRun: []string{metav1.DryRunAll}},
+	)
+	if err != nil {
+		return fmt.Errorf("query is invalid: %v", err)
+	}
+
+	if resolved != "" && !quiet {
+		fmt.Fprintf(os.Stderr, "Resolved input:\n%s\n\n", resolved)
+	}
+	if format.Name == OutputText {
+		return printResourceYAML(result)
+	}
+	return format.PrintObject(os.Stdout, ResourceQuery, result.Object)
+}
+
+// resolveInputTemplate resolves the input template with the literal parameter values, the way the
+// controller will. Parameters with valueFrom are only resolved in the cluster, so they are shown
+// as placeholders. It returns an empty string when there is nothing to resolve.
+func resolveInputTemplate(query *arkv1alpha1.Query) (string, error) {
+	if len(query.Spec.Parameters) == 0 || (query.Spec.Type != "" && query.Spec.Type != arkv1alpha1.QueryTypeUser) {
+		return "", nil
+	}
+
+	input := string(query.Spec.Input.Raw)
+	var inputString string
+	if err := json.Unmarshal(query.Spec.Input.Raw, &inputString); err == nil {
+		input = inputString
+	}
+
+	data := make(map[string]any, len(query.Spec.Parameters))
+	for _, param := range query.Spec.Parameters {
+		if param.ValueFrom != nil && param.Value == "" {
+			data[param.Name] = fmt.Sprintf("<%s resolved at runtime>", param.Name)
+			continue
+		}
+		data[param.Name] = param.Value
+	}
+	tmpl, err := template.New("input").Parse(input)
+	if err != nil {
+		return "", err
+	}
+	var resolved bytes.Buffer
+	if err := tmpl.Execute(&resolved, data); err != nil {
+		return "", err
+	}
+	return resolved.String(), nil
 }
 
 func convertToUnstructured(query *arkv1alpha1.Query) (*unstructured.Unstructured, error) {
@@ -80,37 +232,13 @@ func convertToUnstructured(query *arkv1alpha1.Query) (*unstructured.Unstructured
 	return unstructuredObj, nil
 }
 
-func runListResourcesCommand(config *Config, resourceType ResourceType, namespace string, jsonOutput bool) error {
+func runListResourcesCommand(config *Config, resourceType ResourceType, namespace string, format OutputFormat) error {
 	rm := NewResourceManager(config)
 	resources, err := rm.ListResources(resourceType, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to list %s: %v", resourceType, err)
 	}
-
-	if jsonOutput {
-		jsonData, err := json.MarshalIndent(resources, "", "  ")
-		if err != nil {
-			return fmt.Errorf("failed to marshal JSON: %v", err)
-		}
-		fmt.Println(string(jsonData))
-		return nil
-	}
-
-	for _, resource := range resources {
-		if name, ok := getResourceName(resource); ok {
-			fmt.Println(name)
-		}
-	}
-	return nil
-}
-
-func getResourceName(resource map[string]any) (string, bool) {
-	if metadata, ok := resource["metadata"].(map[string]any); ok {
-		if name, ok := metadata["name"].(string); ok {
-			return name, true
-		}
-	}
-	return "", false
+	return format.PrintList(os.Stdout, resourceType, resources)
 }
 
 func deleteQuery(config *Config, queryName, namespace string) error {
@@ -165,6 +293,10 @@ func createTriggerQuery(existingQuery *arkv1alpha1.Query, input runtime.RawExten
 		ServiceAccount: existingQuery.Spec.ServiceAccount,
 		SessionId:      getSessionId(sessionId, existingQuery.Spec.SessionId),
 	}
+	// Keep reading the input from its source unless it was overridden
+	if input.Raw == nil {
+		spec.InputFrom = existingQuery.Spec.InputFrom
+	}
 
 	queryObjectMeta := &metav1.ObjectMeta{
 		Name:        queryName,
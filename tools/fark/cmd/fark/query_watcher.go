@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"sort"
 	"time"
 
 	"go.uber.org/zap"
@@ -30,6 +31,8 @@ type QueryWatcher struct {
 	queryName string
 	namespace string
 	logger    *zap.Logger
+	// ReplayEvents sends the events recorded before the watch started, for attaching to running queries
+	ReplayEvents bool
 }
 
 func NewQueryWatcher(config *Config, queryName, namespace string, logger *zap.Logger) *QueryWatcher {
@@ -50,12 +53,24 @@ func (qw *QueryWatcher) Watch(ctx context.Context) (<-chan QueryResult, error) {
 		return nil, fmt.Errorf("failed to create query watcher: %v", err)
 	}
 
-	eventWatch, err := qw.createEventWatcher(ctx)
+	var pastEvents []unstructured.Unstructured
+	resourceVersion := ""
+	if qw.ReplayEvents {
+		events, err := qw.listEvents(ctx)
+		if err != nil {
+			qw.logger.Warn("Failed to list past events", zap.Error(err))
+		} else {
+			pastEvents = events.Items
+			resourceVersion = events.GetResourceVersion()
+		}
+	}
+
+	eventWatch, err := qw.createEventWatcher(ctx, resourceVersion)
 	if err != nil {
 		qw.logger.Warn("Failed to create event watcher", zap.Error(err))
 	}
 
-	go qw.processEvents(ctx, queryWatch, eventWatch, resultChan)
+	go qw.processEvents(ctx, queryWatch, eventWatch, pastEvents, resultChan)
 
 	return resultChan, nil
 }
@@ -69,22 +84,54 @@ func (qw *QueryWatcher) createQueryWatcher(ctx context.Context) (watch.Interface
 	)
 }
 
-func (qw *QueryWatcher) createEventWatcher(ctx context.Context) (watch.Interface, error) {
+func (qw *QueryWatcher) createEventWatcher(ctx context.Context, resourceVersion string) (watch.Interface, error) {
 	return qw.config.DynamicClient.Resource(GetGVR(ResourceEvent)).Namespace(qw.namespace).Watch(
 		ctx,
 		metav1.ListOptions{
-			FieldSelector: fields.OneTermEqualSelector("involvedObject.name", qw.queryName).String(),
+			FieldSelector:   fields.OneTermEqualSelector("involvedObject.name", qw.queryName).String(),
+			ResourceVersion: resourceVersion,
 		},
 	)
 }
 
-func (qw *QueryWatcher) processEvents(ctx context.Context, queryWatch, eventWatch watch.Interface, resultChan chan<- QueryResult) {
+// listEvents returns the events recorded for the query so far, oldest first
+func (qw *QueryWatcher) listEvents(ctx context.Context) (*unstructured.UnstructuredList, error) {
+	return listQueryEvents(ctx, qw.config, qw.queryName, qw.namespace)
+}
+
+// listQueryEvents returns the events of a query, oldest first
+func listQueryEvents(ctx context.Context, config *Config, queryName, namespace string) (*unstructured.UnstructuredList, error) {
+	events, err := config.DynamicClient.Resource(GetGVR(ResourceEvent)).Namespace(namespace).List(
+		ctx,
+		metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("involvedObject.name", queryName).String(),
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return events.Items[i].GetCreationTimestamp().Time.Before(events.Items[j].GetCreationTimestamp().Time)
+	})
+	return events, nil
+}
+
+func (qw *QueryWatcher) processEvents(ctx context.Context, queryWatch, eventWatch watch.Interface, pastEvents []unstructured.Unstructured, resultChan chan<- QueryResult) {
 	defer close(resultChan)
 	defer queryWatch.Stop()
 	if eventWatch != nil {
 		defer eventWatch.Stop()
 	}
 
+	// Past events are sent blocking so none are dropped when there are more than the channel holds
+	for i := range pastEvents {
+		select {
+		case resultChan <- QueryResult{Event: &pastEvents[i], IsEvent: true}:
+		case <-ctx.Done():
+			return
+		}
+	}
+
 	var gracePeriodTimer *time.Timer
 	var queryCompleted bool
 
@@ -0,0 +1,280 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+// failureRecordQueryKey is the key of the failed query manifest in a failure record ConfigMap
+const failureRecordQueryKey = "query.yaml"
+
+func createReplayCommand(config *Config) *cobra.Command {
+	f := &flags{timeout: arkv1alpha1.DefaultQueryTimeout}
+	var targets []string
+
+	cmd := &cobra.Command{
+		Use:   "replay query <name>",
+		Short: "Resubmit a failed query",
+		Long: `Resubmit a failed query from the failure record the controller captured when it failed.
+
+The replay runs the query exactly as it was submitted, unless the input, parameters, session or
+targets are overridden. Parameters given with -p replace parameters of the same name and keep the
+others. Targets given with --target replace the targets and selector of the failed query.
+
+The replayed query is kept after it finishes, so it records its own failure if it fails again.`,
+		Example: `  fark replay query weather-query
+  fark replay query weather-query -p location=Paris
+  fark replay query weather-query --target agent/weather-agent-v2 -n production
+  fark replay query weather-query --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] != "query" {
+				return fmt.Errorf("unsupported resource type: %s, only query can be replayed", args[0])
+			}
+			if err := f.validate(); err != nil {
+				return err
+			}
+
+			overrideTargets, err := parseTargets(targets)
+			if err != nil {
+				return err
+			}
+
+			opts := ReplayCommand{
+				QueryName:  args[1],
+				Input:      f.input,
+				InputFile:  f.inputFile,
+				Timeout:    f.timeout,
+				Parameters: f.parameters,
+				SessionId:  f.sessionId,
+				Targets:    overrideTargets,
+				DryRun:     f.dryRun,
+				ExecutionContext: ExecutionContext{
+					Config:    config,
+					Namespace: getNamespaceOrDefault(f.namespace, config.Namespace),
+					Output:    f.outputMode,
+					Silent:    f.quiet,
+					Verbose:   f.verbose,
+				},
+			}
+			return handleQueryError(cmd, opts.Run())
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return []string{"query"}, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return getResourceCompletions(config, string(ResourceQuery), f.namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	f.addTo(cmd)
+	cmd.Flags().StringArrayVar(&targets, "target", nil, "Replace the targets with type/name or type/name@cluster, e.g. agent/my-agent (can be used multiple times)")
+	return cmd
+}
+
+// parseTargets parses targets in type/name or type/name@cluster format
+func parseTargets(values []string) ([]arkv1alpha1.QueryTarget, error) {
+	var targets []arkv1alpha1.QueryTarget
+	for _, value := range values {
+		targetType, targetName, found := strings.Cut(value, "/")
+		if !found || targetName == "" {
+			return nil, fmt.Errorf("--target must be in type/name format, e.g. agent/my-agent")
+		}
+		if err := validateTargetType(targetType); err != nil {
+			return nil, err
+		}
+		targets = append(targets, newQueryTarget(targetType, targetName))
+	}
+	return targets, nil
+}
+
+// newQueryTarget returns the target of a name, which is name@cluster for targets of a remote cluster
+func newQueryTarget(targetType, name string) arkv1alpha1.QueryTarget {
+	name, cluster, _ := strings.Cut(name, "@")
+	return arkv1alpha1.QueryTarget{Type: targetType, Name: name, Cluster: cluster}
+}
+
+// ReplayCommand resubmits a failed query from its failure record
+type ReplayCommand struct {
+	QueryName  string
+	Input      string
+	InputFile  string
+	Timeout    time.Duration
+	Parameters []string
+	SessionId  string
+	Targets    []arkv1alpha1.QueryTarget
+	DryRun     bool
+	ExecutionContext
+}
+
+func (c *ReplayCommand) Run() error {
+	logger := c.getLogger()
+
+	if c.Input != "" && c.InputFile != "" {
+		return fmt.Errorf("cannot use both --input and --file flags")
+	}
+
+	failed, err := getFailedQuery(c.Config, c.QueryName, c.Namespace)
+	if err != nil {
+		return err
+	}
+
+	replay, err := c.createReplayQuery(failed)
+	if err != nil {
+		return err
+	}
+
+	if c.DryRun {
+		return dryRunQuery(c.Config, replay, c.outputMode(), c.Silent)
+	}
+
+	if err := submitQuery(c.Config, replay); err != nil {
+		return fmt.Errorf("failed to create replayed query: %v", err)
+	}
+
+	logger.Info("Replayed query submitted", zap.String("original", c.QueryName), zap.String("new", replay.Name))
+	if !c.Silent {
+		fmt.Fprintf(os.Stderr, "query '%s' created from the failure record of '%s'\n", replay.Name, c.QueryName)
+	}
+
+	ctx := setupQueryContext(c.Timeout, logger)
+	id := &ResourceIdentifier{
+		Config:    c.Config,
+		Type:      ResourceQuery,
+		Name:      replay.Name,
+		Namespace: c.Namespace,
+	}
+	outputOpts := &OutputOptions{
+		OutputMode: c.outputMode(),
+		Verbose:    c.Verbose,
+		Quiet:      c.Silent,
+		Watch:      true,
+	}
+	return waitForQueryCompletion(ctx, id, outputOpts)
+}
+
+// getFailedQuery returns the query recorded in the failure record of a failed query
+func getFailedQuery(config *Config, queryName, namespace string) (*arkv1alpha1.Query, error) {
+	query, err := getExistingQuery(config, queryName, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch query '%s': %v", queryName, err)
+	}
+
+	recordName := query.Annotations[annotations.FailureRecord]
+	if recordName == "" {
+		return nil, fmt.Errorf("query '%s' has no failure record, only failed queries can be replayed", queryName)
+	}
+
+	record, err := config.DynamicClient.Resource(GetGVR(ResourceConfigMap)).Namespace(namespace).Get(
+		context.TODO(), recordName, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch failure record '%s': %v", recordName, err)
+	}
+
+	manifest, found, _ := unstructured.NestedString(record.Object, "data", failureRecordQueryKey)
+	if !found {
+		return nil, fmt.Errorf("failure record '%s' has no %s", recordName, failureRecordQueryKey)
+	}
+
+	var failed arkv1alpha1.Query
+	if err := yaml.Unmarshal([]byte(manifest), &failed); err != nil {
+		return nil, fmt.Errorf("failed to parse failure record '%s': %v", recordName, err)
+	}
+	return &failed, nil
+}
+
+func (c *ReplayCommand) createReplayQuery(failed *arkv1alpha1.Query) (*arkv1alpha1.Query, error) {
+	spec := failed.Spec.DeepCopy()
+
+	input := c.Input
+	if c.InputFile != "" {
+		content, err := readInputFile(c.InputFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read input file: %v", err)
+		}
+		input = content
+	}
+	if input != "" {
+		spec.Type = arkv1alpha1.QueryTypeUser
+		if err := spec.SetInputString(input); err != nil {
+			return nil, err
+		}
+	}
+
+	overrides, err := parseParameters(c.Parameters)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse parameters: %v", err)
+	}
+	spec.Parameters = mergeParameters(spec.Parameters, overrides)
+
+	if len(c.Targets) > 0 {
+		spec.Targets = c.Targets
+		spec.Selector = nil
+	}
+	spec.SessionId = getSessionId(c.SessionId, spec.SessionId)
+	// A replay is a new submission, it would be rejected with the idempotency key of the failed query
+	spec.IdempotencyKey = ""
+
+	labels := map[string]string{}
+	for key, value := range failed.Labels {
+		labels[key] = value
+	}
+	labels[annotations.ReplayOf] = failed.Name
+
+	// Audit annotations name the user of the failed query, the replay is attributed on creation
+	queryAnnotations := map[string]string{}
+	for key, value := range failed.Annotations {
+		if key != annotations.CreatedBy && key != annotations.RequestedBy {
+			queryAnnotations[key] = value
+		}
+	}
+
+	return &arkv1alpha1.Query{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "ark.mckinsey.com/v1alpha1",
+			Kind:       "Query",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        fmt.Sprintf("%s-replay-%d", failed.Name, time.Now().Unix()),
+			Namespace:   c.Namespace,
+			Labels:      labels,
+			Annotations: queryAnnotations,
+		},
+		Spec: *spec,
+	}, nil
+}
+
+// mergeParameters replaces parameters with the overrides of the same name and appends the others
+func mergeParameters(params, overrides []arkv1alpha1.Parameter) []arkv1alpha1.Parameter {
+	merged := append([]arkv1alpha1.Parameter{}, params...)
+	for _, override := range overrides {
+		replaced := false
+		for i := range merged {
+			if merged[i].Name == override.Name {
+				merged[i] = override
+				replaced = true
+			}
+		}
+		if !replaced {
+			merged = append(merged, override)
+		}
+	}
+	return merged
+}
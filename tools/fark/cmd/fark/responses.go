@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/rest"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+const (
+	// artifactConfigMapKey is the key the controller writes gzipped artifacts to in artifact ConfigMaps
+	artifactConfigMapKey = "artifact.json.gz"
+
+	artifactRequestTimeout = 30 * time.Second
+)
+
+// responseArtifact is the full content of a response the controller stored outside the query status
+type responseArtifact struct {
+	Content string `json:"content"`
+	Raw     string `json:"raw"`
+}
+
+// QueryResponsesOptions selects a page of the responses of a query
+type QueryResponsesOptions struct {
+	Offset int
+	Limit  int
+}
+
+// GetResponses prints the responses of a query with their full content. Responses the controller
+// stored as artifacts to keep the query status small are read back from their artifact.
+func (r *ResourceIdentifier) GetResponses(format OutputFormat, opts QueryResponsesOptions) error {
+	query, err := getExistingQuery(r.Config, r.Name, r.Namespace)
+	if err != nil {
+		return fmt.Errorf("failed to get query '%s': %v", r.Name, err)
+	}
+
+	responses := query.Status.Responses
+	if opts.Offset > len(responses) {
+		opts.Offset = len(responses)
+	}
+	responses = responses[opts.Offset:]
+	if opts.Limit > 0 && opts.Limit < len(responses) {
+		responses = responses[:opts.Limit]
+	}
+
+	ctx := context.Background()
+	full := make([]arkv1alpha1.Response, len(responses))
+	for i, response := range responses {
+		if response.Artifact != nil {
+			artifact, err := r.loadResponseArtifact(ctx, response.Artifact)
+			if err != nil {
+				return fmt.Errorf("failed to read the response of %s/%s: %v", response.Target.Type, response.Target.Name, err)
+			}
+			response.Content = artifact.Content
+			response.Raw = artifact.Raw
+		}
+		full[i] = response
+	}
+
+	// Structured formats get the responses as a list, the default formats print their content
+	if format.Name != OutputText && format.Name != OutputTable {
+		items := make([]map[string]any, 0, len(full))
+		for _, response := range full {
+			item, err := toMap(response)
+			if err != nil {
+				return err
+			}
+			items = append(items, item)
+		}
+		return format.PrintList(os.Stdout, ResourceQuery, items)
+	}
+
+	for i, response := range full {
+		target := fmt.Sprintf("%s/%s", response.Target.Type, response.Target.Name)
+		fmt.Fprintf(os.Stderr, "%s\n", colorize(fmt.Sprintf("Response %d of %d: %s (%s)", opts.Offset+i+1, len(query.Status.Responses), target, response.Phase), "36"))
+		fmt.Println(response.Content)
+	}
+	return nil
+}
+
+// loadResponseArtifact reads an artifact from its ConfigMap, or from the URL of the http store
+func (r *ResourceIdentifier) loadResponseArtifact(ctx context.Context, reference *arkv1alpha1.ResponseArtifact) (responseArtifact, error) {
+	var artifact responseArtifact
+	switch reference.Store {
+	case arkv1alpha1.ArtifactStoreConfigMap:
+		configMap, err := r.Config.DynamicClient.Resource(GetGVR(ResourceConfigMap)).Namespace(r.Namespace).Get(ctx, reference.Name, metav1.GetOptions{})
+		if err != nil {
+			return artifact, fmt.Errorf("failed to get artifact configMap %s: %v", reference.Name, err)
+		}
+		encoded, _, _ := unstructured.NestedString(configMap.Object, "binaryData", artifactConfigMapKey)
+		compressed, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return artifact, fmt.Errorf("failed to decode artifact %s: %v", reference.Name, err)
+		}
+		reader, err := gzip.NewReader(bytes.NewReader(compressed))
+		if err != nil {
+			return artifact, fmt.Errorf("failed to decompress artifact %s: %v", reference.Name, err)
+		}
+		if err := json.NewDecoder(reader).Decode(&artifact); err != nil {
+			return artifact, fmt.Errorf("failed to decode artifact %s: %v", reference.Name, err)
+		}
+		return artifact, nil
+	case arkv1alpha1.ArtifactStoreHTTP:
+		ctx, cancel := context.WithTimeout(ctx, artifactRequestTimeout)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, reference.URL, nil)
+		if err != nil {
+			return artifact, fmt.Errorf("failed to create artifact request: %v", err)
+		}
+		// The store authorizes the caller with the credentials of the kubeconfig
+		httpClient, err := rest.HTTPClientFor(r.Config.RestConfig)
+		if err != nil {
+			return artifact, fmt.Errorf("failed to create artifact client: %v", err)
+		}
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			return artifact, fmt.Errorf("artifact store not reachable: %v", err)
+		}
+		defer func() { _ = resp.Body.Close() }()
+		if resp.StatusCode != http.StatusOK {
+			return artifact, fmt.Errorf("artifact store returned HTTP status %d", resp.StatusCode)
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&artifact); err != nil {
+			return artifact, fmt.Errorf("failed to decode artifact %s: %v", reference.Name, err)
+		}
+		return artifact, nil
+	default:
+		return artifact, fmt.Errorf("unsupported artifact store '%s'", reference.Store)
+	}
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/websocket"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+// streamGracePeriod is the least time a stream is kept open, even for queries past their timeout
+const streamGracePeriod = 30 * time.Second
+
+// streamSender delivers one update to a streaming client. An error means the client is gone.
+type streamSender func(data map[string]any) error
+
+// handleQueryStream streams the progress of an existing query over SSE, or over WebSocket
+// when the client asks for an upgrade. The query is not triggered or deleted.
+func handleQueryStream(config *Config) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		config := requestConfig(r, config)
+		queryName := r.PathValue("name")
+		query, err := getExistingQuery(config, queryName, config.Namespace)
+		if err != nil {
+			writeError(w, http.StatusNotFound, "query not found", err)
+			return
+		}
+
+		timeout := queryStreamTimeout(query, time.Now())
+
+		if isWebSocketUpgrade(r) {
+			websocket.Server{
+				Handshake: checkStreamOrigin(config.AllowedOrigins),
+				Handler: func(ws *websocket.Conn) {
+					ctx, cancel := context.WithTimeout(ws.Request().Context(), timeout)
+					defer cancel()
+					streamQueryUpdates(ctx, config, queryName, func(data map[string]any) error {
+						return websocket.JSON.Send(ws, data)
+					})
+				},
+			}.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			writeError(w, http.StatusInternalServerError, "streaming unsupported", nil)
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		streamQueryUpdates(ctx, config, queryName, func(data map[string]any) error {
+			jsonData, err := json.Marshal(data)
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", data["type"], jsonData); err != nil {
+				return err
+			}
+			flusher.Flush()
+			return nil
+		})
+	}
+}
+
+func isWebSocketUpgrade(r *http.Request) bool {
+	return strings.EqualFold(r.Header.Get("Upgrade"), "websocket")
+}
+
+// queryStreamTimeout returns how long the query may still run, counted from its creation. The
+// controller starts the timeout once it picks the query up, so streams get at least
+// streamGracePeriod to deliver the end of queries that started late or already completed.
+func queryStreamTimeout(query *arkv1alpha1.Query, now time.Time) time.Duration {
+	timeout := arkv1alpha1.DefaultQueryTimeout
+	if query.Spec.Timeout != nil {
+		timeout = query.Spec.Timeout.Duration
+	}
+	return max(query.CreationTimestamp.Add(timeout).Sub(now), streamGracePeriod)
+}
+
+// checkStreamOrigin rejects WebSocket handshakes from browser origins that are not allowed with
+// --allowed-origin, so other sites cannot open streams with the credentials of their visitors.
+// Clients that send no Origin header are not browsers and are accepted.
+func checkStreamOrigin(allowedOrigins []string) func(*websocket.Config, *http.Request) error {
+	return func(wsConfig *websocket.Config, r *http.Request) error {
+		origin, err := websocket.Origin(wsConfig, r)
+		if err != nil || origin == nil {
+			return err
+		}
+		wsConfig.Origin = origin
+		for _, allowed := range allowedOrigins {
+			if strings.EqualFold(strings.TrimSuffix(allowed, "/"), origin.Scheme+"://"+origin.Host) {
+				return nil
+			}
+		}
+		return fmt.Errorf("origin %s is not allowed", origin)
+	}
+}
+
+// streamQueryUpdates sends the events recorded so far, then phase changes, events and
+// responses as they happen, and finally a completed update with the full query.
+func streamQueryUpdates(ctx context.Context, config *Config, queryName string, send streamSender) {
+	watcher := NewQueryWatcher(config, queryName, config.Namespace, config.Logger)
+	watcher.ReplayEvents = true
+	resultChan, err := watcher.Watch(ctx)
+	if err != nil {
+		_ = send(map[string]any{"type": "error", "message": err.Error()})
+		return
+	}
+
+	responses := newResponseTracker()
+	lastPhase := ""
+	for result := range resultChan {
+		if err := sendQueryResult(result, send, responses, &lastPhase); err != nil {
+			config.Logger.Debug("Stream client disconnected", zap.String("query", queryName), zap.Error(err))
+			return
+		}
+		if result.Error != nil || result.Done {
+			return
+		}
+	}
+}
+
+func sendQueryResult(result QueryResult, send streamSender, responses *responseTracker, lastPhase *string) error {
+	if result.Error != nil {
+		return send(map[string]any{"type": "error", "message": result.Error.Error()})
+	}
+
+	if result.IsEvent {
+		return send(kubernetesEventData(result.Event))
+	}
+
+	query := result.Query
+	if query == nil {
+		return nil
+	}
+
+	if query.Status.Phase != *lastPhase {
+		*lastPhase = query.Status.Phase
+		if err := send(map[string]any{"type": "phase", "phase": query.Status.Phase}); err != nil {
+			return err
+		}
+	}
+
+	for _, response := range responses.takeNew(query) {
+		if err := send(map[string]any{"type": "response", "response": response}); err != nil {
+			return err
+		}
+	}
+
+	if result.Done {
+		return send(map[string]any{"type": "completed", "phase": query.Status.Phase, "query": query})
+	}
+	return nil
+}
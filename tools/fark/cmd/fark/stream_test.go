@@ -0,0 +1,71 @@
+package main
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/websocket"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+)
+
+func TestCheckStreamOrigin(t *testing.T) {
+	handshake := checkStreamOrigin([]string{"https://ui.example.com/", "http://localhost:3000"})
+
+	tests := []struct {
+		name    string
+		origin  string
+		allowed bool
+	}{
+		{name: "no origin", allowed: true},
+		{name: "allowed origin", origin: "https://ui.example.com", allowed: true},
+		{name: "allowed origin in another case", origin: "https://UI.example.com", allowed: true},
+		{name: "allowed origin with port", origin: "http://localhost:3000", allowed: true},
+		{name: "other site", origin: "https://evil.example.com", allowed: false},
+		{name: "other scheme", origin: "http://ui.example.com", allowed: false},
+		{name: "other port", origin: "http://localhost:8080", allowed: false},
+		{name: "opaque origin", origin: "null", allowed: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			request := httptest.NewRequest("GET", "/query/weather/stream", nil)
+			if tt.origin != "" {
+				request.Header.Set("Origin", tt.origin)
+			}
+			err := handshake(&websocket.Config{Version: websocket.ProtocolVersionHybi13}, request)
+			if allowed := err == nil; allowed != tt.allowed {
+				t.Errorf("got allowed %v, want %v: %v", allowed, tt.allowed, err)
+			}
+		})
+	}
+}
+
+func TestQueryStreamTimeout(t *testing.T) {
+	now := time.Now()
+	query := func(age time.Duration, timeout *metav1.Duration) *arkv1alpha1.Query {
+		return &arkv1alpha1.Query{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: metav1.NewTime(now.Add(-age))},
+			Spec:       arkv1alpha1.QuerySpec{Timeout: timeout},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		query *arkv1alpha1.Query
+		want  time.Duration
+	}{
+		{name: "new query", query: query(0, &metav1.Duration{Duration: 10 * time.Minute}), want: 10 * time.Minute},
+		{name: "running query", query: query(4*time.Minute, &metav1.Duration{Duration: 10 * time.Minute}), want: 6 * time.Minute},
+		{name: "default timeout", query: query(time.Minute, nil), want: arkv1alpha1.DefaultQueryTimeout - time.Minute},
+		{name: "past its timeout", query: query(time.Hour, &metav1.Duration{Duration: 10 * time.Minute}), want: streamGracePeriod},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := queryStreamTimeout(tt.query, now); got != tt.want {
+				t.Errorf("got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
+	"mckinsey.com/ark/internal/annotations"
+)
+
+func createRunCommand(config *Config) *cobra.Command {
+	f := &flags{timeout: arkv1alpha1.DefaultQueryTimeout}
+	var targets []string
+
+	cmd := &cobra.Command{
+		Use:   "run template <name>",
+		Short: "Run a query from a query template",
+		Long: `Create a query from a QueryTemplate and stream its progress.
+
+The query gets the input, targets and defaults of the template. Parameters given with -p fill in
+the template parameters; required parameters must be given and the others fall back to their
+defaults. Targets given with --target replace the targets and selector of the template.
+
+The query is kept after it finishes, so the evaluators of the template can evaluate it.`,
+		Example: `  fark run template ticket-summary -p ticket=ARK-123
+  fark run template ticket-summary -p ticket=ARK-123 -p audience=executives
+  fark run template ticket-summary -p ticket=ARK-123 --target agent/summarizer-v2
+  fark run template ticket-summary -p ticket=ARK-123 --dry-run`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if args[0] != "template" {
+				return fmt.Errorf("unsupported resource type: %s, only template can be run", args[0])
+			}
+			if err := f.validate(); err != nil {
+				return err
+			}
+			if f.input != "" || f.inputFile != "" {
+				return fmt.Errorf("cannot use --input or --file, the input is defined by the template")
+			}
+
+			overrideTargets, err := parseTargets(targets)
+			if err != nil {
+				return err
+			}
+
+			opts := RunTemplateCommand{
+				TemplateName: args[1],
+				Parameters:   f.parameters,
+				SessionId:    f.sessionId,
+				Targets:      overrideTargets,
+				DryRun:       f.dryRun,
+				ExecutionContext: ExecutionContext{
+					Config:    config,
+					Namespace: getNamespaceOrDefault(f.namespace, config.Namespace),
+					Output:    f.outputMode,
+					Silent:    f.quiet,
+					Verbose:   f.verbose,
+				},
+			}
+			if cmd.Flags().Changed("timeout") {
+				opts.Timeout = f.timeout
+			}
+			return handleQueryError(cmd, opts.Run())
+		},
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			switch len(args) {
+			case 0:
+				return []string{"template"}, cobra.ShellCompDirectiveNoFileComp
+			case 1:
+				return getResourceCompletions(config, string(ResourceQueryTemplate), f.namespace), cobra.ShellCompDirectiveNoFileComp
+			}
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		},
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+
+	f.addTo(cmd)
+	_ = cmd.RegisterFlagCompletionFunc("param", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		if len(args) < 2 {
+			return nil, cobra.ShellCompDirectiveNoFileComp
+		}
+		keys := getParameterCompletions(config, ResourceQueryTemplate, args[1], f.namespace)
+		return keys, cobra.ShellCompDirectiveNoSpace | cobra.ShellCompDirectiveNoFileComp
+	})
+	cmd.Flags().StringArrayVar(&targets, "target", nil, "Replace the targets with type/name or type/name@cluster, e.g. agent/my-agent (can be used multiple times)")
+	return cmd
+}
+
+// RunTemplateCommand creates a query from a query template and waits for it
+type RunTemplateCommand struct {
+	TemplateName string
+	Parameters   []string
+	SessionId    string
+	Targets      []arkv1alpha1.QueryTarget
+	// Timeout overrides the timeout of the template when set
+	Timeout time.Duration
+	DryRun  bool
+	ExecutionContext
+}
+
+func (c *RunTemplateCommand) Run() error {
+	logger := c.getLogger()
+
+	template, err := getQueryTemplate(c.Config, c.TemplateName, c.Namespace)
+	if err != nil {
+		return err
+	}
+
+	params, err := parseParameters(c.Parameters)
+	if err != nil {
+		return fmt.Errorf("failed to parse parameters: %v", err)
+	}
+	query, err := createTemplateQuery(template, params, c.Targets, c.SessionId)
+	if err != nil {
+		return err
+	}
+	if c.Timeout > 0 {
+		query.Spec.Timeout = &metav1.Duration{Duration: c.Timeout}
+	}
+
+	if c.DryRun {
+		return dryRunQuery(c.Config, query, c.outputMode(), c.Silent)
+	}
+
+	if err := submitQuery(c.Config, query); err != nil {
+		return fmt.Errorf("failed to create query: %v", err)
+	}
+
+	logger.Info("Template query submitted", zap.String("template", c.TemplateName), zap.String("query", query.Name))
+	if !c.Silent {
+		fmt.Fprintf(os.Stderr, "query '%s' created from template '%s'\n", query.Name, c.TemplateName)
+	}
+
+	ctx := setupQueryContext(query.Spec.GetTimeout(), logger)
+	id := &ResourceIdentifier{
+		Config:    c.Config,
+		Type:      ResourceQuery,
+		Name:      query.Name,
+		Namespace: c.Namespace,
+	}
+	outputOpts := &OutputOptions{
+		OutputMode: c.outputMode(),
+		Verbose:    c.Verbose,
+		Quiet:      c.Silent,
+		Watch:      true,
+	}
+	return waitForQueryCompletion(ctx, id, outputOpts)
+}
+
+// getQueryTemplate returns the named query template
+func getQueryTemplate(config *Config, name, namespace string) (*arkv1alpha1.QueryTemplate, error) {
+	resource, err := config.DynamicClient.Resource(GetGVR(ResourceQueryTemplate)).Namespace(namespace).Get(
+		context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch query template '%s': %v", name, err)
+	}
+
+	var template arkv1alpha1.QueryTemplate
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(resource.UnstructuredContent(), &template); err != nil {
+		return nil, fmt.Errorf("failed to convert to QueryTemplate object: %v", err)
+	}
+	return &template, nil
+}
+
+// createTemplateQuery returns a query created from the template with the parameter values. Targets
+// replace the targets and selector of the template when given.
+func createTemplateQuery(template *arkv1alpha1.QueryTemplate, params []arkv1alpha1.Parameter, targets []arkv1alpha1.QueryTarget, sessionId string) (*arkv1alpha1.Query, error) {
+	spec, err := template.Spec.NewQuerySpec(params)
+	if err != nil {
+		return nil, fmt.Errorf("invalid parameters for query template '%s': %v", template.Name, err)
+	}
+	if len(targets) > 0 {
+		spec.Targets = targets
+		spec.Selector = nil
+	}
+	spec.SessionId = sessionId
+
+	return &arkv1alpha1.Query{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: "ark.mckinsey.com/v1alpha1",
+			Kind:       "Query",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%d", template.Name, time.Now().Unix()),
+			Namespace: template.Namespace,
+			Labels:    map[string]string{annotations.QueryTemplate: template.Name},
+		},
+		Spec: spec,
+	}, nil
+}
@@ -7,13 +7,27 @@ import (
 	"go.uber.org/zap"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+
+	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
 
 type Config struct {
+	RestConfig    *rest.Config
 	DynamicClient dynamic.Interface
 	Namespace     string
 	Port          string
 	Logger        *zap.Logger
+	// User is the authenticated caller of a fark server request, empty for the CLI
+	User string
+	// Impersonate is the identity queries submitted for the caller run as, set with --run-as-caller
+	Impersonate *arkv1alpha1.QueryImpersonation
+	// AllowedOrigins are the browser origins that may open WebSocket streams, set with --allowed-origin
+	AllowedOrigins []string
+
+	// kubeConfig resolves the cluster and namespace from the kubeconfig flags
+	kubeConfig clientcmd.ClientConfig
 }
 
 type ResourceType string
@@ -25,6 +39,16 @@ const (
 	ResourceModel ResourceType = "models"
 	ResourceTool  ResourceType = "tools"
 	ResourceEvent ResourceType = "events"
+
+	ResourceConfigMap ResourceType = "configmaps"
+
+	ResourceEvaluation ResourceType = "evaluations"
+	ResourceMemory     ResourceType = "memories"
+
+	ResourceAgentRevision ResourceType = "agentrevisions"
+	ResourceToolApproval  ResourceType = "toolapprovals"
+	ResourceQueryTemplate ResourceType = "querytemplates"
+	ResourceEvaluator     ResourceType = "evaluators"
 )
 
 var resourceGVRMap = map[ResourceType]schema.GroupVersionResource{
@@ -34,6 +58,16 @@ var resourceGVRMap = map[ResourceType]schema.GroupVersionResource{
 	ResourceModel: {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "models"},
 	ResourceTool:  {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "tools"},
 	ResourceEvent: {Group: "", Version: "v1", Resource: "events"},
+
+	ResourceConfigMap: {Group: "", Version: "v1", Resource: "configmaps"},
+
+	ResourceEvaluation: {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "evaluations"},
+	ResourceMemory:     {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "memories"},
+
+	ResourceAgentRevision: {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "agentrevisions"},
+	ResourceToolApproval:  {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "toolapprovals"},
+	ResourceQueryTemplate: {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "querytemplates"},
+	ResourceEvaluator:     {Group: "ark.mckinsey.com", Version: "v1alpha1", Resource: "evaluators"},
 }
 
 func GetGVR(resourceType ResourceType) schema.GroupVersionResource {
@@ -45,6 +79,7 @@ type OutputOptions struct {
 	OutputMode string // "text" or "json"
 	Verbose    bool   // Show detailed events and logs
 	Quiet      bool   // Suppress events and progress indicators
+	Watch      bool   // Render responses as they arrive and keep the query afterwards
 }
 
 // AgentSpec groups agent creation and update parameters
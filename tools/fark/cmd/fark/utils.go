@@ -1,12 +1,17 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"slices"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 
 	arkv1alpha1 "mckinsey.com/ark/api/v1alpha1"
 )
@@ -61,6 +66,9 @@ func parseParameters(parameters []string) ([]arkv1alpha1.Parameter, error) {
 }
 
 func getResourceCompletions(config *Config, resourceType, namespace string) []string {
+	if err := config.connect(); err != nil {
+		return nil
+	}
 	ns := getNamespaceOrDefault(namespace, config.Namespace)
 	rm := NewResourceManager(config)
 
@@ -72,6 +80,54 @@ func getResourceCompletions(config *Config, resourceType, namespace string) []st
 	return names
 }
 
+// getParameterCompletions returns "key=" suggestions for the parameters a target accepts: the input
+// schema properties of a tool, the query parameters referenced by an agent, or the parameters of a
+// query or query template
+func getParameterCompletions(config *Config, targetType ResourceType, name, namespace string) []string {
+	if err := config.connect(); err != nil {
+		return nil
+	}
+	ns := getNamespaceOrDefault(namespace, config.Namespace)
+	resource, err := config.DynamicClient.Resource(GetGVR(targetType)).Namespace(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+
+	var keys []string
+	switch targetType {
+	case ResourceTool:
+		properties, _, _ := unstructured.NestedMap(resource.Object, "spec", "inputSchema", "properties")
+		for key := range properties {
+			keys = append(keys, key)
+		}
+	case ResourceAgent:
+		parameters, _, _ := unstructured.NestedSlice(resource.Object, "spec", "parameters")
+		for _, parameter := range parameters {
+			if parameterMap, ok := parameter.(map[string]any); ok {
+				if key, found, _ := unstructured.NestedString(parameterMap, "valueFrom", "queryParameterRef", "name"); found {
+					keys = append(keys, key)
+				}
+			}
+		}
+	case ResourceQuery, ResourceQueryTemplate:
+		parameters, _, _ := unstructured.NestedSlice(resource.Object, "spec", "parameters")
+		for _, parameter := range parameters {
+			if parameterMap, ok := parameter.(map[string]any); ok {
+				if key, found, _ := unstructured.NestedString(parameterMap, "name"); found {
+					keys = append(keys, key)
+				}
+			}
+		}
+	}
+
+	sort.Strings(keys)
+	completions := make([]string, 0, len(keys))
+	for _, key := range slices.Compact(keys) {
+		completions = append(completions, key+"=")
+	}
+	return completions
+}
+
 func handleQueryError(cmd *cobra.Command, err error) error {
 	if err != nil {
 		cmd.SilenceUsage = true